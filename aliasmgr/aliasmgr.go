@@ -189,22 +189,24 @@ func (m *Manager) AddLocalAlias(alias, baseScid lnwire.ShortChannelID,
 	m.Lock()
 	defer m.Unlock()
 
+	// baseConfirmed tracks whether the base SCID is marked in the
+	// baseConfBucket, in which case no gossiper mapping is created for
+	// the alias, just like when the maps are populated on startup.
+	var baseConfirmed bool
+
 	err := kvdb.Update(m.backend, func(tx kvdb.RwTx) error {
+		var baseGossipBytes [8]byte
+		byteOrder.PutUint64(baseGossipBytes[:], baseScid.ToUint64())
+
+		confBucket, err := tx.CreateTopLevelBucket(confirmedBucket)
+		if err != nil {
+			return err
+		}
+		baseConfirmed = confBucket.Get(baseGossipBytes[:]) != nil
+
 		// If the caller does not want to allow the alias to be used
 		// for a channel update, we'll mark it in the baseConfBucket.
 		if !gossip {
-			var baseGossipBytes [8]byte
-			byteOrder.PutUint64(
-				baseGossipBytes[:], baseScid.ToUint64(),
-			)
-
-			confBucket, err := tx.CreateTopLevelBucket(
-				confirmedBucket,
-			)
-			if err != nil {
-				return err
-			}
-
 			err = confBucket.Put(baseGossipBytes[:], []byte{})
 			if err != nil {
 				return err
@@ -224,7 +226,9 @@ func (m *Manager) AddLocalAlias(alias, baseScid lnwire.ShortChannelID,
 		byteOrder.PutUint64(aliasBytes[:], alias.ToUint64())
 		byteOrder.PutUint64(baseBytes[:], baseScid.ToUint64())
 		return aliasToBaseBucket.Put(aliasBytes[:], baseBytes[:])
-	}, func() {})
+	}, func() {
+		baseConfirmed = false
+	})
 	if err != nil {
 		return err
 	}
@@ -232,8 +236,9 @@ func (m *Manager) AddLocalAlias(alias, baseScid lnwire.ShortChannelID,
 	// Update the aliasToBase and baseToSet maps.
 	m.baseToSet[baseScid] = append(m.baseToSet[baseScid], alias)
 
-	// Only store the gossiper map if gossip is true.
-	if gossip {
+	// Only store the gossiper map if gossip is true and the base SCID
+	// isn't confirmed yet.
+	if gossip && !baseConfirmed {
 		m.aliasToBase[alias] = baseScid
 	}
 
@@ -68,6 +68,66 @@ func TestAliasStoreRequest(t *testing.T) {
 	require.Equal(t, nextAlias, alias2)
 }
 
+// TestAliasStoreConfirmedBase tests that an alias added to a channel whose
+// base SCID is already confirmed isn't used by the gossiper, both before and
+// after a restart.
+func TestAliasStoreConfirmedBase(t *testing.T) {
+	t.Parallel()
+
+	// Create the backend database and use this to create the aliasStore.
+	dbPath := filepath.Join(t.TempDir(), "testdb")
+	db, err := kvdb.Create(
+		kvdb.BoltBackendName, dbPath, true, kvdb.DefaultDBTimeout,
+	)
+	require.NoError(t, err)
+	defer db.Close()
+
+	aliasStore, err := NewManager(db)
+	require.NoError(t, err)
+
+	baseScid := lnwire.ShortChannelID{BlockHeight: 100}
+	alias1 := startingAlias
+	alias2 := getNextScid(alias1)
+
+	// Before the base SCID is confirmed, the gossiper can find it using
+	// the alias.
+	err = aliasStore.AddLocalAlias(alias1, baseScid, true)
+	require.NoError(t, err)
+
+	base, err := aliasStore.FindBaseSCID(alias1)
+	require.NoError(t, err)
+	require.Equal(t, baseScid, base)
+
+	// Once it's confirmed, neither the existing nor a new alias is used
+	// by the gossiper anymore.
+	require.NoError(t, aliasStore.DeleteSixConfs(baseScid))
+
+	err = aliasStore.AddLocalAlias(alias2, baseScid, true)
+	require.NoError(t, err)
+
+	assertAliases := func(m *Manager) {
+		t.Helper()
+
+		require.ElementsMatch(
+			t, []lnwire.ShortChannelID{alias1, alias2},
+			m.GetAliases(baseScid),
+		)
+
+		_, err := m.FindBaseSCID(alias1)
+		require.ErrorIs(t, err, errNoBase)
+
+		_, err = m.FindBaseSCID(alias2)
+		require.ErrorIs(t, err, errNoBase)
+	}
+	assertAliases(aliasStore)
+
+	// The same holds after a restart.
+	aliasStore, err = NewManager(db)
+	require.NoError(t, err)
+
+	assertAliases(aliasStore)
+}
+
 // TestGetNextScid tests that given a current lnwire.ShortChannelID,
 // getNextScid returns the expected alias to use next.
 func TestGetNextScid(t *testing.T) {
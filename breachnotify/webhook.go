@@ -0,0 +1,272 @@
+package breachnotify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/contractcourt"
+	"github.com/lightningnetwork/lnd/subscribe"
+)
+
+const (
+	// DefaultTimeout is the default time a single delivery of a breach
+	// notification may take.
+	DefaultTimeout = 10 * time.Second
+
+	// DefaultAttempts is the default number of times the delivery of a
+	// breach notification is attempted.
+	DefaultAttempts = 5
+
+	// initialRetryDelay is the delay before the first retry of a failed
+	// delivery. It doubles with every further retry.
+	initialRetryDelay = time.Second
+)
+
+// Config houses the dependencies of the Webhook.
+type Config struct {
+	// URL is the HTTP(S) endpoint the breach notifications are posted to.
+	URL string
+
+	// Timeout is the time a single delivery may take.
+	Timeout time.Duration
+
+	// Attempts is the number of times the delivery of a notification is
+	// attempted before it is given up.
+	Attempts int
+
+	// SubscribeBreaches subscribes to the breaches detected by the breach
+	// arbiter.
+	SubscribeBreaches func() (*subscribe.Client, error)
+
+	// Client is the HTTP client used to deliver the notifications.
+	Client *http.Client
+}
+
+// Output describes an output of the revoked commitment we're entitled to.
+type Output struct {
+	// OutPoint is the outpoint of the output.
+	OutPoint string `json:"outpoint"`
+
+	// AmountSat is the value of the output in satoshis.
+	AmountSat int64 `json:"amount_sat"`
+
+	// WitnessType describes the kind of output.
+	WitnessType string `json:"witness_type"`
+}
+
+// Notification is the JSON body of the requests the Webhook sends for every
+// detected breach.
+type Notification struct {
+	// ChanPoint is the funding outpoint of the breached channel.
+	ChanPoint string `json:"chan_point"`
+
+	// ChainHash is the hash of the genesis block of the chain.
+	ChainHash string `json:"chain_hash"`
+
+	// BreachTxid is the txid of the revoked commitment.
+	BreachTxid string `json:"breach_txid"`
+
+	// BreachHeight is the height the breach was detected at.
+	BreachHeight uint32 `json:"breach_height"`
+
+	// RevokedStateNum is the number of the revoked commitment state.
+	RevokedStateNum uint64 `json:"revoked_state_num"`
+
+	// Outputs are the outputs of the revoked commitment we're entitled
+	// to.
+	Outputs []Output `json:"outputs"`
+
+	// TotalAmountSat is the total value of the outputs in satoshis,
+	// before the fees of the justice transaction.
+	TotalAmountSat int64 `json:"total_amount_sat"`
+
+	// DetectedAt is the time the breach was detected.
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// newNotification converts a breach event into the body of a notification.
+func newNotification(event *contractcourt.BreachDetected) *Notification {
+	n := &Notification{
+		ChanPoint:       event.ChanPoint.String(),
+		ChainHash:       event.ChainHash.String(),
+		BreachTxid:      event.BreachTxHash.String(),
+		BreachHeight:    event.BreachHeight,
+		RevokedStateNum: event.RevokedStateNum,
+		Outputs:         make([]Output, 0, len(event.Outputs)),
+		TotalAmountSat:  int64(event.TotalAmount),
+		DetectedAt:      event.DetectedAt,
+	}
+
+	for _, output := range event.Outputs {
+		n.Outputs = append(n.Outputs, Output{
+			OutPoint:    output.OutPoint.String(),
+			AmountSat:   int64(output.Amount),
+			WitnessType: output.WitnessType,
+		})
+	}
+
+	return n
+}
+
+// Webhook posts a JSON encoded Notification to an external endpoint as soon
+// as a breach of one of our channels is detected, so that operators can page
+// someone or start an external sweep before the justice transaction confirms.
+type Webhook struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	sub *subscribe.Client
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a new breach notification webhook.
+func New(cfg *Config) *Webhook {
+	return &Webhook{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}
+}
+
+// Start subscribes to the detected breaches and starts delivering them.
+func (w *Webhook) Start() error {
+	var err error
+	w.started.Do(func() {
+		log.Infof("Breach notification webhook starting, posting "+
+			"to %v", w.cfg.URL)
+
+		w.sub, err = w.cfg.SubscribeBreaches()
+		if err != nil {
+			return
+		}
+
+		w.wg.Add(1)
+		go w.notifyLoop()
+	})
+
+	return err
+}
+
+// Stop cancels the subscription and waits for pending deliveries to be given
+// up.
+func (w *Webhook) Stop() error {
+	w.stopped.Do(func() {
+		log.Info("Breach notification webhook shutting down")
+
+		if w.sub != nil {
+			w.sub.Cancel()
+		}
+		close(w.quit)
+		w.wg.Wait()
+	})
+
+	return nil
+}
+
+// notifyLoop delivers every detected breach.
+//
+// NOTE: This MUST be run as a goroutine.
+func (w *Webhook) notifyLoop() {
+	defer w.wg.Done()
+
+	for {
+		select {
+		case update, ok := <-w.sub.Updates():
+			if !ok {
+				return
+			}
+
+			event, ok := update.(*contractcourt.BreachDetected)
+			if !ok {
+				continue
+			}
+
+			// Each notification is delivered independently, so
+			// that an endpoint that is slow to accept one doesn't
+			// delay the next.
+			w.wg.Add(1)
+			go w.deliver(newNotification(event))
+
+		case <-w.quit:
+			return
+		}
+	}
+}
+
+// deliver posts the notification to the endpoint, retrying with an
+// exponential backoff until it is accepted or all attempts failed.
+//
+// NOTE: This MUST be run as a goroutine.
+func (w *Webhook) deliver(n *Notification) {
+	defer w.wg.Done()
+
+	body, err := json.Marshal(n)
+	if err != nil {
+		log.Errorf("Unable to encode breach notification of "+
+			"ChannelPoint(%v): %v", n.ChanPoint, err)
+		return
+	}
+
+	delay := initialRetryDelay
+	for attempt := 1; ; attempt++ {
+		err := w.post(body)
+		if err == nil {
+			log.Infof("Delivered breach notification of "+
+				"ChannelPoint(%v)", n.ChanPoint)
+			return
+		}
+
+		if attempt >= w.cfg.Attempts {
+			log.Errorf("Giving up delivering breach notification "+
+				"of ChannelPoint(%v) after %d attempts: %v",
+				n.ChanPoint, attempt, err)
+			return
+		}
+
+		log.Warnf("Unable to deliver breach notification of "+
+			"ChannelPoint(%v), retrying in %v: %v", n.ChanPoint,
+			delay, err)
+
+		select {
+		case <-time.After(delay):
+			delay *= 2
+
+		case <-w.quit:
+			return
+		}
+	}
+}
+
+// post sends a single request with the encoded notification.
+func (w *Webhook) post(body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), w.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, w.cfg.URL, bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %v", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package breachnotify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/contractcourt"
+	"github.com/lightningnetwork/lnd/subscribe"
+	"github.com/stretchr/testify/require"
+)
+
+var testTime = time.Date(2022, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+// TestWebhook asserts that a detected breach is posted to the endpoint, and
+// that a failed delivery is retried.
+func TestWebhook(t *testing.T) {
+	t.Parallel()
+
+	var (
+		requests      uint32
+		notifications = make(chan *Notification, 1)
+	)
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(
+				t, "application/json",
+				r.Header.Get("Content-Type"),
+			)
+
+			// Reject the first attempt to check that the
+			// notification is delivered again.
+			if atomic.AddUint32(&requests, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			var n Notification
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&n))
+			notifications <- &n
+		},
+	))
+	t.Cleanup(server.Close)
+
+	breaches := subscribe.NewServer()
+	require.NoError(t, breaches.Start())
+	t.Cleanup(func() {
+		require.NoError(t, breaches.Stop())
+	})
+
+	webhook := New(&Config{
+		URL:               server.URL,
+		Timeout:           time.Second,
+		Attempts:          2,
+		SubscribeBreaches: breaches.Subscribe,
+		Client:            server.Client(),
+	})
+	require.NoError(t, webhook.Start())
+	t.Cleanup(func() {
+		require.NoError(t, webhook.Stop())
+	})
+
+	chanPoint := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 1}
+	output := wire.OutPoint{Hash: chainhash.Hash{2}, Index: 0}
+	require.NoError(t, breaches.SendUpdate(&contractcourt.BreachDetected{
+		ChanPoint:       chanPoint,
+		BreachTxHash:    chainhash.Hash{2},
+		BreachHeight:    100,
+		RevokedStateNum: 3,
+		Outputs: []contractcourt.BreachedOutputDetail{{
+			OutPoint:    output,
+			Amount:      5000,
+			WitnessType: "CommitmentRevoke",
+		}},
+		TotalAmount: 5000,
+		DetectedAt:  testTime,
+	}))
+
+	select {
+	case n := <-notifications:
+		require.Equal(t, &Notification{
+			ChanPoint:       chanPoint.String(),
+			ChainHash:       chainhash.Hash{}.String(),
+			BreachTxid:      chainhash.Hash{2}.String(),
+			BreachHeight:    100,
+			RevokedStateNum: 3,
+			Outputs: []Output{{
+				OutPoint:    output.String(),
+				AmountSat:   5000,
+				WitnessType: "CommitmentRevoke",
+			}},
+			TotalAmountSat: 5000,
+			DetectedAt:     testTime,
+		}, n)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("breach notification not delivered")
+	}
+
+	require.EqualValues(t, 2, atomic.LoadUint32(&requests))
+}
@@ -2,11 +2,14 @@ package brontide
 
 import (
 	"bytes"
+	"io"
 	"math"
 	"math/rand"
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/stretchr/testify/require"
 )
 
@@ -64,3 +67,134 @@ func BenchmarkReadHeaderAndBody(t *testing.B) {
 	}
 	require.NoError(t, benchErr)
 }
+
+// benchMessageSize is the size of the messages used by the benchmarks below.
+// It roughly matches the size of a commitment signature message carrying a
+// few htlc signatures.
+const benchMessageSize = 500
+
+// newBenchMachines creates an initiator and responder brontide machine and
+// runs the handshake between them.
+func newBenchMachines(t testing.TB) (*Machine, *Machine) {
+	initiatorPriv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	responderPriv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	initiator := NewBrontideMachine(
+		true, &keychain.PrivKeyECDH{PrivKey: initiatorPriv},
+		responderPriv.PubKey(),
+	)
+	responder := NewBrontideMachine(
+		false, &keychain.PrivKeyECDH{PrivKey: responderPriv}, nil,
+	)
+	require.NoError(t, runHandshake(initiator, responder))
+
+	return initiator, responder
+}
+
+// runHandshake runs all three acts of the handshake between the initiator and
+// responder.
+func runHandshake(initiator, responder *Machine) error {
+	actOne, err := initiator.GenActOne()
+	if err != nil {
+		return err
+	}
+	if err := responder.RecvActOne(actOne); err != nil {
+		return err
+	}
+
+	actTwo, err := responder.GenActTwo()
+	if err != nil {
+		return err
+	}
+	if err := initiator.RecvActTwo(actTwo); err != nil {
+		return err
+	}
+
+	actThree, err := initiator.GenActThree()
+	if err != nil {
+		return err
+	}
+
+	return responder.RecvActThree(actThree)
+}
+
+// BenchmarkHandshake benchmarks a full three act handshake, including the
+// creation of both state machines.
+func BenchmarkHandshake(b *testing.B) {
+	initiatorPriv, err := btcec.NewPrivateKey()
+	require.NoError(b, err)
+	responderPriv, err := btcec.NewPrivateKey()
+	require.NoError(b, err)
+
+	initiatorKey := &keychain.PrivKeyECDH{PrivKey: initiatorPriv}
+	responderKey := &keychain.PrivKeyECDH{PrivKey: responderPriv}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		initiator := NewBrontideMachine(
+			true, initiatorKey, responderPriv.PubKey(),
+		)
+		responder := NewBrontideMachine(false, responderKey, nil)
+
+		if err := runHandshake(initiator, responder); err != nil {
+			b.Fatalf("handshake failed: %v", err)
+		}
+	}
+}
+
+// BenchmarkWriteMessage benchmarks encrypting a message and flushing it to
+// the wire.
+func BenchmarkWriteMessage(b *testing.B) {
+	initiator, _ := newBenchMachines(b)
+	msg := bytes.Repeat([]byte("a"), benchMessageSize)
+
+	b.SetBytes(benchMessageSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := initiator.WriteMessage(msg); err != nil {
+			b.Fatalf("unable to write message: %v", err)
+		}
+		if _, err := initiator.Flush(io.Discard); err != nil {
+			b.Fatalf("unable to flush message: %v", err)
+		}
+	}
+}
+
+// BenchmarkReadMessage benchmarks reading and decrypting a message from the
+// wire.
+func BenchmarkReadMessage(b *testing.B) {
+	initiator, responder := newBenchMachines(b)
+	msg := bytes.Repeat([]byte("a"), benchMessageSize)
+
+	var wire bytes.Buffer
+	require.NoError(b, initiator.WriteMessage(msg))
+	_, err := initiator.Flush(&wire)
+	require.NoError(b, err)
+
+	var (
+		packet = wire.Bytes()
+		r      = bytes.NewReader(packet)
+		nonce  = responder.recvCipher.nonce
+	)
+
+	b.SetBytes(benchMessageSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		r.Reset(packet)
+		if _, err := responder.ReadMessage(r); err != nil {
+			b.Fatalf("unable to read message: %v", err)
+		}
+
+		// We reset the nonce each time as otherwise the same packet
+		// would fail to decrypt.
+		responder.recvCipher.nonce = nonce
+	}
+}
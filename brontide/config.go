@@ -0,0 +1,108 @@
+package brontide
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultHandshakeTimeout is the default timeout for each act of the
+	// handshake that is read from the remote party. If the remote party
+	// fails to deliver the act within this time frame, then we'll fail the
+	// connection.
+	DefaultHandshakeTimeout = 5 * time.Second
+
+	// DefaultReadTimeout is the default timeout for reading the body of a
+	// message once its header has been read.
+	DefaultReadTimeout = 5 * time.Second
+
+	// DefaultWriteTimeout is the default timeout for writing a buffered
+	// message to the wire.
+	DefaultWriteTimeout = 5 * time.Second
+)
+
+// Config houses the timeouts and socket options that are enforced on a
+// brontide connection and the hooks that are called for it.
+type Config struct {
+	// HandshakeTimeout is the timeout for each act of the handshake that
+	// is read from the remote party.
+	HandshakeTimeout time.Duration
+
+	// ReadTimeout is the timeout for reading the body of a message with
+	// ReadNextBody once its header has been read. No timeout is enforced
+	// on waiting for the header, as peers may idle between messages. If
+	// zero, the caller is responsible for setting the read deadline.
+	ReadTimeout time.Duration
+
+	// WriteTimeout is the timeout for writing a buffered message to the
+	// wire with Flush. If zero, the caller is responsible for setting the
+	// write deadline.
+	WriteTimeout time.Duration
+
+	// OnKeyRotation, if set, is called for every rotation of the sending
+	// or receiving key of the connection. It is called from the goroutine
+	// that writes or reads the message which caused the rotation, so it
+	// must not block.
+	OnKeyRotation func(KeyRotation)
+
+	// Socket holds the options of the TCP sockets of dialed connections
+	// and listeners.
+	Socket SocketConfig
+
+	// NoNoiseIK, if set, makes listeners reject connections that are
+	// established with the Noise_IK handshake, so only the Noise_XK
+	// handshake of BOLT 8 is accepted.
+	NoNoiseIK bool
+}
+
+// DefaultConfig returns a Config that uses the default timeouts.
+func DefaultConfig() *Config {
+	return &Config{
+		HandshakeTimeout: DefaultHandshakeTimeout,
+		ReadTimeout:      DefaultReadTimeout,
+		WriteTimeout:     DefaultWriteTimeout,
+	}
+}
+
+// sanitizeConfig returns the default config if the passed config is nil and
+// validates it otherwise.
+func sanitizeConfig(cfg *Config) (*Config, error) {
+	if cfg == nil {
+		return DefaultConfig(), nil
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Validate checks that the timeouts of the Config are sane and that the socket
+// options are supported.
+func (c *Config) Validate() error {
+	if c.HandshakeTimeout <= 0 {
+		return errors.New("handshake timeout must be positive")
+	}
+
+	if c.ReadTimeout < 0 {
+		return errors.New("read timeout must not be negative")
+	}
+
+	if c.WriteTimeout < 0 {
+		return errors.New("write timeout must not be negative")
+	}
+
+	return c.Socket.Validate()
+}
+
+// machineOptions returns the options of the brontide Machine that follow from
+// the config.
+func (c *Config) machineOptions() []func(*Machine) {
+	var options []func(*Machine)
+	if c.OnKeyRotation != nil {
+		options = append(options, RotationHook(c.OnKeyRotation))
+	}
+
+	return options
+}
@@ -24,6 +24,8 @@ type Conn struct {
 
 	noise *Machine
 
+	cfg *Config
+
 	readBuf bytes.Buffer
 }
 
@@ -33,38 +35,86 @@ var _ net.Conn = (*Conn)(nil)
 // Dial attempts to establish an encrypted+authenticated connection with the
 // remote peer located at address which has remotePub as its long-term static
 // public key. In the case of a handshake failure, the connection is closed and
-// a non-nil error is returned.
+// a non-nil error is returned. The timeouts of the connection are taken from
+// the passed config, if it is nil the default timeouts are used. The options
+// are passed to the brontide Machine, which allows selecting the handshake
+// pattern.
 func Dial(local keychain.SingleKeyECDH, netAddr *lnwire.NetAddress,
-	timeout time.Duration, dialer tor.DialFunc) (*Conn, error) {
+	timeout time.Duration, dialer tor.DialFunc, cfg *Config,
+	options ...func(*Machine)) (*Conn, error) {
+
+	cfg, err := sanitizeConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	ipAddr := netAddr.Address.String()
-	var conn net.Conn
-	var err error
-	conn, err = dialer("tcp", ipAddr, timeout)
+	conn, err := dialer("tcp", ipAddr, timeout)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := cfg.Socket.Apply(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return NewClientConn(local, netAddr.IdentityKey, conn, cfg, options...)
+}
+
+// NewClientConn carries out the brontide handshake as the initiator over an
+// already established connection to the remote peer which has remotePub as
+// its long-term static public key. This allows brontide to be used on top of
+// transports other than plain TCP. In the case of a handshake failure, the
+// connection is closed and a non-nil error is returned. The timeouts of the
+// connection are taken from the passed config, if it is nil the default
+// timeouts are used. The options are passed to the brontide Machine, which
+// allows selecting the handshake pattern and key rotation policy.
+func NewClientConn(local keychain.SingleKeyECDH, remotePub *btcec.PublicKey,
+	conn net.Conn, cfg *Config, options ...func(*Machine)) (*Conn, error) {
+
+	cfg, err := sanitizeConfig(cfg)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	options = append(cfg.machineOptions(), options...)
 	b := &Conn{
 		conn:  conn,
-		noise: NewBrontideMachine(true, local, netAddr.IdentityKey),
+		noise: NewBrontideMachine(true, local, remotePub, options...),
+		cfg:   cfg,
 	}
 
 	// Initiate the handshake by sending the first act to the receiver.
-	actOne, err := b.noise.GenActOne()
-	if err != nil {
-		b.conn.Close()
-		return nil, err
+	// With the Noise_IK pattern, it already contains our static key.
+	var actOne []byte
+	switch b.noise.HandshakePattern() {
+	case PatternIK:
+		act, err := b.noise.GenActOneIK()
+		if err != nil {
+			b.conn.Close()
+			return nil, err
+		}
+		actOne = act[:]
+
+	default:
+		act, err := b.noise.GenActOne()
+		if err != nil {
+			b.conn.Close()
+			return nil, err
+		}
+		actOne = act[:]
 	}
-	if _, err := conn.Write(actOne[:]); err != nil {
+	if _, err := conn.Write(actOne); err != nil {
 		b.conn.Close()
 		return nil, err
 	}
 
 	// We'll ensure that we get ActTwo from the remote peer in a timely
-	// manner. If they don't respond within handshakeReadTimeout, then
+	// manner. If they don't respond within the handshake timeout, then
 	// we'll kill the connection.
-	err = conn.SetReadDeadline(time.Now().Add(handshakeReadTimeout))
+	err = conn.SetReadDeadline(time.Now().Add(cfg.HandshakeTimeout))
 	if err != nil {
 		b.conn.Close()
 		return nil, err
@@ -84,14 +134,29 @@ func Dial(local keychain.SingleKeyECDH, netAddr *lnwire.NetAddress,
 		return nil, err
 	}
 
-	// Finally, complete the handshake by sending over our encrypted static
-	// key and execute the final ECDH operation.
-	actThree, err := b.noise.GenActThree()
-	if err != nil {
-		b.conn.Close()
-		return nil, err
+	// Finally, complete the Noise_XK handshake by sending over our
+	// encrypted static key and execute the final ECDH operation. In the
+	// Noise_IK handshake, we instead confirm that we take part in this
+	// handshake, as our first act could have been replayed.
+	var actThree []byte
+	switch b.noise.HandshakePattern() {
+	case PatternIK:
+		act, err := b.noise.GenActThreeIK()
+		if err != nil {
+			b.conn.Close()
+			return nil, err
+		}
+		actThree = act[:]
+
+	default:
+		act, err := b.noise.GenActThree()
+		if err != nil {
+			b.conn.Close()
+			return nil, err
+		}
+		actThree = act[:]
 	}
-	if _, err := conn.Write(actThree[:]); err != nil {
+	if _, err := conn.Write(actThree); err != nil {
 		b.conn.Close()
 		return nil, err
 	}
@@ -130,8 +195,17 @@ func (c *Conn) ReadNextHeader() (uint32, error) {
 // ReadNextBody uses the connection to read the next message body from the
 // brontide stream. This function will block until the read of the body succeeds
 // and return the decrypted payload. The provided buffer MUST be the packet
-// length returned by the preceding call to ReadNextHeader.
+// length returned by the preceding call to ReadNextHeader. If the connection
+// has a read timeout configured, the read deadline is set accordingly before
+// reading the body.
 func (c *Conn) ReadNextBody(buf []byte) ([]byte, error) {
+	if c.cfg.ReadTimeout != 0 {
+		deadline := time.Now().Add(c.cfg.ReadTimeout)
+		if err := c.conn.SetReadDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+
 	return c.noise.ReadBody(c.conn, buf)
 }
 
@@ -224,8 +298,18 @@ func (c *Conn) WriteMessage(b []byte) error {
 // bytes returned reflects the number of plaintext bytes in the payload, and
 // does not account for the overhead of the header or MACs.
 //
+// If the connection has a write timeout configured, the write deadline is set
+// accordingly before writing.
+//
 // NOTE: It is safe to call this method again iff a timeout error is returned.
 func (c *Conn) Flush() (int, error) {
+	if c.cfg.WriteTimeout != 0 {
+		deadline := time.Now().Add(c.cfg.WriteTimeout)
+		if err := c.conn.SetWriteDeadline(deadline); err != nil {
+			return 0, err
+		}
+	}
+
 	return c.noise.Flush(c.conn)
 }
 
@@ -287,3 +371,27 @@ func (c *Conn) RemotePub() *btcec.PublicKey {
 func (c *Conn) LocalPub() *btcec.PublicKey {
 	return c.noise.localStatic.PubKey()
 }
+
+// HandshakePattern returns the Noise handshake pattern that was used to
+// establish the connection.
+func (c *Conn) HandshakePattern() HandshakePattern {
+	return c.noise.HandshakePattern()
+}
+
+// RotateKeys requests the rotation of our sending key before the next message
+// is written to the connection.
+//
+// NOTE: The remote peer must support key rotations ahead of the BOLT 8
+// rotation interval.
+func (c *Conn) RotateKeys() {
+	c.noise.RotateKeys()
+}
+
+// SetKeyRotationPolicy sets the policy that determines when our sending key
+// is rotated in addition to the BOLT 8 rotation interval.
+//
+// NOTE: The remote peer must support key rotations ahead of the BOLT 8
+// rotation interval.
+func (c *Conn) SetKeyRotationPolicy(policy KeyRotationPolicy) {
+	c.noise.SetKeyRotationPolicy(policy)
+}
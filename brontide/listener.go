@@ -14,6 +14,10 @@ import (
 // parallel.
 const defaultHandshakes = 1000
 
+// errListenerClosed is returned when a handshake is aborted because the
+// listener is shutting down.
+var errListenerClosed = errors.New("brontide listener closed")
+
 // Listener is an implementation of a net.Conn which executes an authenticated
 // key exchange and message encryption protocol dubbed "Machine" after
 // initial connection acceptance. See the Machine struct for additional
@@ -22,7 +26,9 @@ const defaultHandshakes = 1000
 type Listener struct {
 	localStatic keychain.SingleKeyECDH
 
-	tcp *net.TCPListener
+	cfg *Config
+
+	tcp net.Listener
 
 	handshakeSema chan struct{}
 	conns         chan maybeConn
@@ -33,22 +39,25 @@ type Listener struct {
 var _ net.Listener = (*Listener)(nil)
 
 // NewListener returns a new net.Listener which enforces the Brontide scheme
-// during both initial connection establishment and data transfer.
-func NewListener(localStatic keychain.SingleKeyECDH,
-	listenAddr string) (*Listener, error) {
+// during both initial connection establishment and data transfer. The
+// timeouts of the accepted connections are taken from the passed config, if it
+// is nil the default timeouts are used.
+func NewListener(localStatic keychain.SingleKeyECDH, listenAddr string,
+	cfg *Config) (*Listener, error) {
 
-	addr, err := net.ResolveTCPAddr("tcp", listenAddr)
+	cfg, err := sanitizeConfig(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	l, err := net.ListenTCP("tcp", addr)
+	l, err := cfg.Socket.Listen(listenAddr)
 	if err != nil {
 		return nil, err
 	}
 
 	brontideListener := &Listener{
 		localStatic:   localStatic,
+		cfg:           cfg,
 		tcp:           l,
 		handshakeSema: make(chan struct{}, defaultHandshakes),
 		conns:         make(chan maybeConn),
@@ -109,34 +118,116 @@ func (l *Listener) doHandshake(conn net.Conn) {
 
 	remoteAddr := conn.RemoteAddr().String()
 
-	brontideConn := &Conn{
-		conn:  conn,
-		noise: NewBrontideMachine(false, l.localStatic, nil),
+	brontideConn, err := acceptHandshake(
+		l.localStatic, conn, l.cfg, l.quit,
+	)
+	switch {
+	// The listener is shutting down, so there's no one left to report the
+	// failed handshake to.
+	case errors.Is(err, errListenerClosed):
+		return
+
+	case err != nil:
+		l.rejectConn(rejectedConnErr(err, remoteAddr))
+		return
 	}
 
+	l.acceptConn(brontideConn)
+}
+
+// NewServerConn carries out the brontide handshake as the responder over an
+// already established connection. This allows brontide to be used on top of
+// transports other than plain TCP. In the case of a handshake failure, the
+// connection is closed and a non-nil error is returned. The timeouts of the
+// connection are taken from the passed config, if it is nil the default
+// timeouts are used.
+func NewServerConn(localStatic keychain.SingleKeyECDH, conn net.Conn,
+	cfg *Config) (*Conn, error) {
+
+	cfg, err := sanitizeConfig(cfg)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return acceptHandshake(localStatic, conn, cfg, nil)
+}
+
+// acceptHandshake carries out the brontide handshake as the responder over the
+// given connection. The handshake pattern is chosen by the initiator, both the
+// Noise_XK and the Noise_IK pattern are accepted unless Noise_IK is disabled
+// in the config. If the quit channel is
+// closed while the handshake is in progress, errListenerClosed is returned. In
+// case of an error, the connection is closed.
+func acceptHandshake(localStatic keychain.SingleKeyECDH, conn net.Conn,
+	cfg *Config, quit <-chan struct{}) (*Conn, error) {
+
 	// We'll ensure that we get ActOne from the remote peer in a timely
-	// manner. If they don't respond within handshakeReadTimeout, then
+	// manner. If they don't respond within the handshake timeout, then
 	// we'll kill the connection.
-	err := conn.SetReadDeadline(time.Now().Add(handshakeReadTimeout))
+	err := conn.SetReadDeadline(time.Now().Add(cfg.HandshakeTimeout))
 	if err != nil {
-		brontideConn.conn.Close()
-		l.rejectConn(rejectedConnErr(err, remoteAddr))
-		return
+		conn.Close()
+		return nil, err
+	}
+
+	// The handshake version that prefixes the first act tells us which
+	// handshake pattern the initiator chose, so we read it first to know
+	// how large the rest of the act is.
+	var version [1]byte
+	if _, err := io.ReadFull(conn, version[:]); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	pattern, err := patternFromVersion(version[0])
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("act one: %w", err)
+	}
+	if pattern == PatternIK && cfg.NoNoiseIK {
+		conn.Close()
+		return nil, errors.New("act one: Noise_IK handshake is " +
+			"disabled")
+	}
+
+	brontideConn := &Conn{
+		conn: conn,
+		noise: NewBrontideMachine(
+			false, localStatic, nil, append(
+				cfg.machineOptions(), NoisePattern(pattern),
+			)...,
+		),
+		cfg: cfg,
 	}
 
 	// Attempt to carry out the first act of the handshake protocol. If the
 	// connecting node doesn't know our long-term static public key, then
 	// this portion will fail with a non-nil error.
-	var actOne [ActOneSize]byte
-	if _, err := io.ReadFull(conn, actOne[:]); err != nil {
-		brontideConn.conn.Close()
-		l.rejectConn(rejectedConnErr(err, remoteAddr))
-		return
-	}
-	if err := brontideConn.noise.RecvActOne(actOne); err != nil {
-		brontideConn.conn.Close()
-		l.rejectConn(rejectedConnErr(err, remoteAddr))
-		return
+	switch pattern {
+	case PatternIK:
+		var actOne [ActOneIKSize]byte
+		actOne[0] = version[0]
+		if _, err := io.ReadFull(conn, actOne[1:]); err != nil {
+			brontideConn.conn.Close()
+			return nil, err
+		}
+		err := brontideConn.noise.RecvActOneIK(actOne)
+		if err != nil {
+			brontideConn.conn.Close()
+			return nil, err
+		}
+
+	default:
+		var actOne [ActOneSize]byte
+		actOne[0] = version[0]
+		if _, err := io.ReadFull(conn, actOne[1:]); err != nil {
+			brontideConn.conn.Close()
+			return nil, err
+		}
+		if err := brontideConn.noise.RecvActOne(actOne); err != nil {
+			brontideConn.conn.Close()
+			return nil, err
+		}
 	}
 
 	// Next, progress the handshake processes by sending over our ephemeral
@@ -144,44 +235,59 @@ func (l *Listener) doHandshake(conn net.Conn) {
 	actTwo, err := brontideConn.noise.GenActTwo()
 	if err != nil {
 		brontideConn.conn.Close()
-		l.rejectConn(rejectedConnErr(err, remoteAddr))
-		return
+		return nil, err
 	}
 	if _, err := conn.Write(actTwo[:]); err != nil {
 		brontideConn.conn.Close()
-		l.rejectConn(rejectedConnErr(err, remoteAddr))
-		return
+		return nil, err
 	}
 
 	select {
-	case <-l.quit:
-		return
+	case <-quit:
+		brontideConn.conn.Close()
+		return nil, errListenerClosed
 	default:
 	}
 
 	// We'll ensure that we get ActTwo from the remote peer in a timely
-	// manner. If they don't respond within handshakeReadTimeout, then
+	// manner. If they don't respond within the handshake timeout, then
 	// we'll kill the connection.
-	err = conn.SetReadDeadline(time.Now().Add(handshakeReadTimeout))
+	err = conn.SetReadDeadline(time.Now().Add(cfg.HandshakeTimeout))
 	if err != nil {
 		brontideConn.conn.Close()
-		l.rejectConn(rejectedConnErr(err, remoteAddr))
-		return
+		return nil, err
 	}
 
-	// Finally, finish the handshake processes by reading and decrypting
-	// the connection peer's static public key. If this succeeds then both
-	// sides have mutually authenticated each other.
-	var actThree [ActThreeSize]byte
-	if _, err := io.ReadFull(conn, actThree[:]); err != nil {
-		brontideConn.conn.Close()
-		l.rejectConn(rejectedConnErr(err, remoteAddr))
-		return
-	}
-	if err := brontideConn.noise.RecvActThree(actThree); err != nil {
-		brontideConn.conn.Close()
-		l.rejectConn(rejectedConnErr(err, remoteAddr))
-		return
+	// Finally, finish the handshake processes by reading the third act.
+	// In the Noise_IK handshake, it confirms that the initiator takes part
+	// in this handshake, as the first act that carries its static key
+	// could have been replayed. The initiator is only authenticated once
+	// it is processed.
+	if pattern == PatternIK {
+		var actThree [ActThreeIKSize]byte
+		if _, err := io.ReadFull(conn, actThree[:]); err != nil {
+			brontideConn.conn.Close()
+			return nil, err
+		}
+		err := brontideConn.noise.RecvActThreeIK(actThree)
+		if err != nil {
+			brontideConn.conn.Close()
+			return nil, err
+		}
+	} else {
+		// For the Noise_XK handshake, we read and decrypt the
+		// connection peer's static public key. If this succeeds then
+		// both sides have mutually authenticated each other.
+		var actThree [ActThreeSize]byte
+		if _, err := io.ReadFull(conn, actThree[:]); err != nil {
+			brontideConn.conn.Close()
+			return nil, err
+		}
+		err := brontideConn.noise.RecvActThree(actThree)
+		if err != nil {
+			brontideConn.conn.Close()
+			return nil, err
+		}
 	}
 
 	// We'll reset the deadline as it's no longer critical beyond the
@@ -189,11 +295,10 @@ func (l *Listener) doHandshake(conn net.Conn) {
 	err = conn.SetReadDeadline(time.Time{})
 	if err != nil {
 		brontideConn.conn.Close()
-		l.rejectConn(rejectedConnErr(err, remoteAddr))
-		return
+		return nil, err
 	}
 
-	l.acceptConn(brontideConn)
+	return brontideConn, nil
 }
 
 // maybeConn holds either a brontide connection or an error returned from the
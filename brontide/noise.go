@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"sync"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
@@ -24,6 +25,10 @@ const (
 	// network, then the initial handshake will fail.
 	protocolName = "Noise_XK_secp256k1_ChaChaPoly_SHA256"
 
+	// protocolNameIK is the instantiation of the Noise protocol for the
+	// Noise_IK variant of the handshake.
+	protocolNameIK = "Noise_IK_secp256k1_ChaChaPoly_SHA256"
+
 	// macSize is the length in bytes of the tags generated by poly1305.
 	macSize = 16
 
@@ -39,11 +44,10 @@ const (
 	// cipher stream before the keys are rotated forwards.
 	keyRotationInterval = 1000
 
-	// handshakeReadTimeout is a read timeout that will be enforced when
-	// waiting for data payloads during the various acts of Brontide. If
-	// the remote party fails to deliver the proper payload within this
-	// time frame, then we'll fail the connection.
-	handshakeReadTimeout = time.Second * 5
+	// maxBodySendBufSize is the largest send buffer that a Machine keeps
+	// around to encrypt the next message into. Larger messages are rare,
+	// so we avoid pinning a 64 KB buffer for every connection.
+	maxBodySendBufSize = 4096
 )
 
 var (
@@ -100,41 +104,56 @@ type cipherState struct {
 	// cipher is an instance of the ChaCha20-Poly1305 AEAD construction
 	// created using the secretKey above.
 	cipher cipher.AEAD
+
+	// nonceBuf is a static buffer that holds the serialized nonce. Passing
+	// a stack allocated array to the AEAD would cause it to escape to the
+	// heap, resulting in an allocation for every message.
+	nonceBuf [12]byte
+
+	// onRotate, if set, is called after the key has been rotated because
+	// the rotation interval has been reached.
+	onRotate func()
 }
 
 // Encrypt returns a ciphertext which is the encryption of the plainText
 // observing the passed associatedData within the AEAD construction.
 func (c *cipherState) Encrypt(associatedData, cipherText, plainText []byte) []byte {
-	defer func() {
-		c.nonce++
+	binary.LittleEndian.PutUint64(c.nonceBuf[4:], c.nonce)
 
-		if c.nonce == keyRotationInterval {
-			c.rotateKey()
-		}
-	}()
-
-	var nonce [12]byte
-	binary.LittleEndian.PutUint64(nonce[4:], c.nonce)
+	cipherText = c.cipher.Seal(
+		cipherText, c.nonceBuf[:], plainText, associatedData,
+	)
+	c.incrementNonce()
 
-	return c.cipher.Seal(cipherText, nonce[:], plainText, associatedData)
+	return cipherText
 }
 
 // Decrypt attempts to decrypt the passed ciphertext observing the specified
 // associatedData within the AEAD construction. In the case that the final MAC
 // check fails, then a non-nil error will be returned.
 func (c *cipherState) Decrypt(associatedData, plainText, cipherText []byte) ([]byte, error) {
-	defer func() {
-		c.nonce++
+	binary.LittleEndian.PutUint64(c.nonceBuf[4:], c.nonce)
 
-		if c.nonce == keyRotationInterval {
-			c.rotateKey()
-		}
-	}()
+	plainText, err := c.cipher.Open(
+		plainText, c.nonceBuf[:], cipherText, associatedData,
+	)
+	c.incrementNonce()
+
+	return plainText, err
+}
+
+// incrementNonce increments the nonce after each encryption/decryption, and
+// rotates the key once the rotation interval has been reached.
+func (c *cipherState) incrementNonce() {
+	c.nonce++
 
-	var nonce [12]byte
-	binary.LittleEndian.PutUint64(nonce[4:], c.nonce)
+	if c.nonce == keyRotationInterval {
+		c.rotateKey()
 
-	return c.cipher.Open(plainText, nonce[:], cipherText, associatedData)
+		if c.onRotate != nil {
+			c.onRotate()
+		}
+	}
 }
 
 // InitializeKey initializes the secret key and AEAD cipher scheme based off of
@@ -274,6 +293,78 @@ func (s *symmetricState) InitializeSymmetric(protocolName []byte) {
 	s.InitializeKey(empty)
 }
 
+// HandshakePattern identifies the Noise handshake pattern that is used to
+// establish a brontide session.
+type HandshakePattern uint8
+
+const (
+	// PatternXK is the three act Noise_XK handshake specified in BOLT 8.
+	// The initiator only reveals its static key in the third act, under
+	// strong forward secrecy.
+	PatternXK HandshakePattern = iota
+
+	// PatternIK is the Noise_IK handshake. The initiator already sends
+	// its static key within the first act, so the responder learns who is
+	// connecting without waiting for the third act. The third act only
+	// confirms that the initiator takes part in the handshake, as the
+	// first act could otherwise be replayed. In exchange, the initiator's static key is only protected
+	// by the responder's static key, so it lacks forward secrecy. As the
+	// responder must support the pattern, it should only be used to
+	// re-connect to peers that signaled support for it.
+	PatternIK
+)
+
+// String returns a human-readable name of the handshake pattern.
+func (p HandshakePattern) String() string {
+	switch p {
+	case PatternXK:
+		return "Noise_XK"
+
+	case PatternIK:
+		return "Noise_IK"
+
+	default:
+		return fmt.Sprintf("<unknown handshake pattern: %d>", uint8(p))
+	}
+}
+
+// protocolName returns the protocol name that is mixed into the handshake
+// digest for the pattern.
+func (p HandshakePattern) protocolName() string {
+	if p == PatternIK {
+		return protocolNameIK
+	}
+
+	return protocolName
+}
+
+// version returns the handshake version that prefixes every act of the
+// pattern, which allows the responder to tell the patterns apart.
+func (p HandshakePattern) version() byte {
+	if p == PatternIK {
+		return HandshakeVersionIK
+	}
+
+	return HandshakeVersion
+}
+
+// patternFromVersion returns the handshake pattern that is signaled by the
+// given handshake version.
+func patternFromVersion(version byte) (HandshakePattern, error) {
+	switch version {
+	case HandshakeVersion:
+		return PatternXK, nil
+
+	case HandshakeVersionIK:
+		return PatternIK, nil
+
+	default:
+		return 0, fmt.Errorf("invalid handshake version: %v, only %v "+
+			"and %v are valid", version, HandshakeVersion,
+			HandshakeVersionIK)
+	}
+}
+
 // handshakeState encapsulates the symmetricState and keeps track of all the
 // public keys (static and ephemeral) for both sides during the handshake
 // transcript. If the handshake completes successfully, then two instances of a
@@ -292,10 +383,10 @@ type handshakeState struct {
 }
 
 // newHandshakeState returns a new instance of the handshake state initialized
-// with the prologue and protocol name. If this is the responder's handshake
-// state, then the remotePub can be nil.
-func newHandshakeState(initiator bool, prologue []byte,
-	localKey keychain.SingleKeyECDH,
+// with the prologue and the protocol name of the given pattern. If this is the
+// responder's handshake state, then the remotePub can be nil.
+func newHandshakeState(initiator bool, pattern HandshakePattern,
+	prologue []byte, localKey keychain.SingleKeyECDH,
 	remotePub *btcec.PublicKey) handshakeState {
 
 	h := handshakeState{
@@ -308,13 +399,13 @@ func newHandshakeState(initiator bool, prologue []byte,
 	// protocol name, and additionally mix in the prologue. If either sides
 	// disagree about the prologue or protocol name, then the handshake
 	// will fail.
-	h.InitializeSymmetric([]byte(protocolName))
+	h.InitializeSymmetric([]byte(pattern.protocolName()))
 	h.mixHash(prologue)
 
-	// In Noise_XK, the initiator should know the responder's static
-	// public key, therefore we include the responder's static key in the
-	// handshake digest. If the initiator gets this value wrong, then the
-	// handshake will fail.
+	// In both Noise_XK and Noise_IK, the initiator should know the
+	// responder's static public key, therefore we include the responder's
+	// static key in the handshake digest. If the initiator gets this value
+	// wrong, then the handshake will fail.
 	if initiator {
 		h.mixHash(remotePub.SerializeCompressed())
 	} else {
@@ -324,6 +415,16 @@ func newHandshakeState(initiator bool, prologue []byte,
 	return h
 }
 
+// NoisePattern is a functional option that selects the Noise handshake
+// pattern of the brontide Machine. By default, the Noise_XK pattern of BOLT 8
+// is used. The function closure returned by this function can be passed into
+// NewBrontideMachine as a function option parameter.
+func NoisePattern(pattern HandshakePattern) func(*Machine) {
+	return func(m *Machine) {
+		m.pattern = pattern
+	}
+}
+
 // EphemeralGenerator is a functional option that allows callers to substitute
 // a custom function for use when generating ephemeral keys for ActOne or
 // ActTwo. The function closure returned by this function can be passed into
@@ -361,12 +462,33 @@ func EphemeralGenerator(gen func() (*btcec.PrivateKey, error)) func(*Machine) {
 //	-> e, es
 //	<- e, ee
 //	-> s, se
+//
+// If the Noise_IK pattern is selected with the NoisePattern option, the
+// handshake uses different first and third acts instead:
+//
+//	GenActOneIK()   ->
+//	                    RecvActOneIK()
+//	                <-  GenActTwo()
+//	RecvActTwo()
+//	GenActThreeIK() ->
+//	                    RecvActThreeIK()
+//
+// This exchange corresponds to the following Noise handshake, followed by an
+// empty confirmation payload from the initiator:
+//
+//	<- s
+//	...
+//	-> e, es, s, ss
+//	<- e, ee, se
 type Machine struct {
 	sendCipher cipherState
 	recvCipher cipherState
 
 	ephemeralGen func() (*btcec.PrivateKey, error)
 
+	// pattern is the Noise handshake pattern used by the Machine.
+	pattern HandshakePattern
+
 	handshakeState
 
 	// nextCipherHeader is a static buffer that we'll use to read in the
@@ -374,6 +496,18 @@ type Machine struct {
 	// (of the next ciphertext), followed by a 16 byte MAC.
 	nextCipherHeader [encHeaderSize]byte
 
+	// pktLenSend is a static buffer that we'll use to serialize the length
+	// of the next message we send.
+	pktLenSend [lengthHeaderSize]byte
+
+	// nextCipherHeaderSend is a static buffer that holds the encrypted
+	// header of the next message we send.
+	nextCipherHeaderSend [encHeaderSize]byte
+
+	// bodySendBuf is a buffer that is re-used to hold the ciphertext of the
+	// messages we send, as long as it doesn't exceed maxBodySendBufSize.
+	bodySendBuf []byte
+
 	// nextHeaderSend holds a reference to the remaining header bytes to
 	// write out for a pending message. This allows us to tolerate timeout
 	// errors that cause partial writes.
@@ -383,6 +517,36 @@ type Machine struct {
 	// out for a pending message. This allows us to tolerate timeout errors
 	// that cause partial writes.
 	nextBodySend []byte
+
+	// pktLenRecv is a static buffer that holds the decrypted length of the
+	// next message we receive. The length isn't decrypted in place, as we
+	// may need to retry the decryption of the header with the next key of
+	// the remote party.
+	pktLenRecv [lengthHeaderSize]byte
+
+	// rotationHook, if set, is called for every rotation of the sending
+	// or receiving key.
+	rotationHook func(KeyRotation)
+
+	// sendMsgs is the number of messages written since the sending key
+	// was last rotated.
+	sendMsgs uint64
+
+	// lastSendRotation is the time the sending key was last rotated, or
+	// the time the handshake completed if it hasn't been rotated yet.
+	lastSendRotation time.Time
+
+	// rotationMtx guards the fields below, as they may be modified while
+	// messages are being written.
+	rotationMtx sync.Mutex
+
+	// rotationPolicy determines when the sending key is rotated ahead of
+	// the rotation interval.
+	rotationPolicy KeyRotationPolicy
+
+	// rotationRequested is set by RotateKeys if the sending key should be
+	// rotated before the next message is written.
+	rotationRequested bool
 }
 
 // NewBrontideMachine creates a new instance of the brontide state-machine. If
@@ -394,13 +558,9 @@ type Machine struct {
 func NewBrontideMachine(initiator bool, localKey keychain.SingleKeyECDH,
 	remotePub *btcec.PublicKey, options ...func(*Machine)) *Machine {
 
-	handshake := newHandshakeState(
-		initiator, lightningPrologue, localKey, remotePub,
-	)
-
 	m := &Machine{
-		handshakeState: handshake,
-		ephemeralGen:   ephemeralGen,
+		ephemeralGen: ephemeralGen,
+		pattern:      PatternXK,
 	}
 
 	// With the default options established, we'll now process all the
@@ -409,15 +569,44 @@ func NewBrontideMachine(initiator bool, localKey keychain.SingleKeyECDH,
 		option(m)
 	}
 
+	// The handshake state can only be initialized once we know which
+	// pattern is used, as its protocol name is part of the handshake
+	// digest.
+	m.handshakeState = newHandshakeState(
+		initiator, m.pattern, lightningPrologue, localKey, remotePub,
+	)
+
 	return m
 }
 
+// HandshakePattern returns the Noise handshake pattern used by the Machine.
+func (b *Machine) HandshakePattern() HandshakePattern {
+	return b.pattern
+}
+
+// checkPattern returns an error if the Machine doesn't use the given pattern,
+// which means that the given act isn't part of its handshake.
+func (b *Machine) checkPattern(act string, pattern HandshakePattern) error {
+	if b.pattern != pattern {
+		return fmt.Errorf("%v is not part of the %v handshake", act,
+			b.pattern)
+	}
+
+	return nil
+}
+
 const (
 	// HandshakeVersion is the expected version of the brontide handshake.
 	// Any messages that carry a different version will cause the handshake
 	// to abort immediately.
 	HandshakeVersion = byte(0)
 
+	// HandshakeVersionIK is the version of the brontide handshake that
+	// uses the Noise_IK pattern. It prefixes each act of the handshake,
+	// which allows the responder to tell it apart from the Noise_XK
+	// handshake as soon as it reads the first byte.
+	HandshakeVersionIK = byte(1)
+
 	// ActOneSize is the size of the packet sent from initiator to
 	// responder in ActOne. The packet consists of a handshake version, an
 	// ephemeral key in compressed format, and a 16-byte poly1305 tag.
@@ -439,6 +628,22 @@ const (
 	//
 	// 1 + 33 + 16 + 16
 	ActThreeSize = 66
+
+	// ActOneIKSize is the size of the packet sent from initiator to
+	// responder in the first act of the Noise_IK handshake. The packet
+	// consists of a handshake version, an ephemeral key in compressed
+	// format, the initiator's encrypted static key along with its 16-byte
+	// poly1305 tag, and a 16-byte poly1305 tag.
+	//
+	// 1 + 33 + 33 + 16 + 16
+	ActOneIKSize = 99
+
+	// ActThreeIKSize is the size of the packet sent from initiator to
+	// responder in the final act of the Noise_IK handshake. The packet
+	// consists of a handshake version and a 16-byte poly1305 tag.
+	//
+	// 1 + 16
+	ActThreeIKSize = 17
 )
 
 // GenActOne generates the initial packet (act one) to be sent from initiator
@@ -451,6 +656,10 @@ const (
 func (b *Machine) GenActOne() ([ActOneSize]byte, error) {
 	var actOne [ActOneSize]byte
 
+	if err := b.checkPattern("act one", PatternXK); err != nil {
+		return actOne, err
+	}
+
 	// e
 	localEphemeral, err := b.ephemeralGen()
 	if err != nil {
@@ -490,6 +699,10 @@ func (b *Machine) RecvActOne(actOne [ActOneSize]byte) error {
 		p   [16]byte
 	)
 
+	if err := b.checkPattern("act one", PatternXK); err != nil {
+		return err
+	}
+
 	// If the handshake version is unknown, then the handshake fails
 	// immediately.
 	if actOne[0] != HandshakeVersion {
@@ -521,12 +734,137 @@ func (b *Machine) RecvActOne(actOne [ActOneSize]byte) error {
 	return err
 }
 
+// GenActOneIK generates the first packet of the Noise_IK handshake to be sent
+// from initiator to responder. In addition to the fresh ephemeral key of
+// GenActOne, the initiator transmits its static key encrypted with a key
+// derived from the ECDH between its ephemeral key and the responder's static
+// key. It then performs an ECDH between both static keys, which binds the
+// handshake to the initiator's static key.
+//
+//	-> e, es, s, ss
+func (b *Machine) GenActOneIK() ([ActOneIKSize]byte, error) {
+	var actOne [ActOneIKSize]byte
+
+	if err := b.checkPattern("IK act one", PatternIK); err != nil {
+		return actOne, err
+	}
+
+	// e
+	localEphemeral, err := b.ephemeralGen()
+	if err != nil {
+		return actOne, err
+	}
+	b.localEphemeral = &keychain.PrivKeyECDH{
+		PrivKey: localEphemeral,
+	}
+
+	ephemeral := localEphemeral.PubKey().SerializeCompressed()
+	b.mixHash(ephemeral)
+
+	// es
+	es, err := ecdh(b.remoteStatic, b.localEphemeral)
+	if err != nil {
+		return actOne, err
+	}
+	b.mixKey(es)
+
+	// s
+	ourPubkey := b.localStatic.PubKey().SerializeCompressed()
+	ciphertext := b.EncryptAndHash(ourPubkey)
+
+	// ss
+	ss, err := ecdh(b.remoteStatic, b.localStatic)
+	if err != nil {
+		return actOne, err
+	}
+	b.mixKey(ss)
+
+	authPayload := b.EncryptAndHash([]byte{})
+
+	actOne[0] = HandshakeVersionIK
+	copy(actOne[1:34], ephemeral)
+	copy(actOne[34:83], ciphertext)
+	copy(actOne[83:], authPayload)
+
+	return actOne, nil
+}
+
+// RecvActOneIK processes the first packet of the Noise_IK handshake sent by
+// the initiator. The responder executes the mirrored actions to that of the
+// initiator, learning the initiator's static key in the process. As the first
+// act can be replayed, the initiator is only authenticated once the third act
+// is processed with RecvActThreeIK.
+func (b *Machine) RecvActOneIK(actOne [ActOneIKSize]byte) error {
+	var (
+		err error
+		e   [33]byte
+		s   [33 + 16]byte
+		p   [16]byte
+	)
+
+	if err := b.checkPattern("IK act one", PatternIK); err != nil {
+		return err
+	}
+
+	// If the handshake version is unknown, then the handshake fails
+	// immediately.
+	if actOne[0] != HandshakeVersionIK {
+		return fmt.Errorf("IK act one: invalid handshake version: %v, "+
+			"only %v is valid, msg=%x", actOne[0],
+			HandshakeVersionIK, actOne[:])
+	}
+
+	copy(e[:], actOne[1:34])
+	copy(s[:], actOne[34:83])
+	copy(p[:], actOne[83:])
+
+	// e
+	b.remoteEphemeral, err = btcec.ParsePubKey(e[:])
+	if err != nil {
+		return err
+	}
+	b.mixHash(b.remoteEphemeral.SerializeCompressed())
+
+	// es
+	es, err := ecdh(b.remoteEphemeral, b.localStatic)
+	if err != nil {
+		return err
+	}
+	b.mixKey(es)
+
+	// s
+	//
+	// If the initiator doesn't know our static key, then this operation
+	// will fail.
+	remotePub, err := b.DecryptAndHash(s[:])
+	if err != nil {
+		return err
+	}
+	b.remoteStatic, err = btcec.ParsePubKey(remotePub)
+	if err != nil {
+		return err
+	}
+
+	// ss
+	ss, err := ecdh(b.remoteStatic, b.localStatic)
+	if err != nil {
+		return err
+	}
+	b.mixKey(ss)
+
+	_, err = b.DecryptAndHash(p[:])
+	return err
+}
+
 // GenActTwo generates the second packet (act two) to be sent from the
 // responder to the initiator. The packet for act two is identical to that of
 // act one, but then results in a different ECDH operation between the
-// initiator's and responder's ephemeral keys.
+// initiator's and responder's ephemeral keys. In the Noise_IK handshake, the
+// responder already knows the initiator's static key, so it additionally
+// performs an ECDH between it and its ephemeral key.
 //
-//	<- e, ee
+//	<- e, ee       (Noise_XK)
+//	<- e, ee, se   (Noise_IK)
 func (b *Machine) GenActTwo() ([ActTwoSize]byte, error) {
 	var actTwo [ActTwoSize]byte
 
@@ -549,9 +887,18 @@ func (b *Machine) GenActTwo() ([ActTwoSize]byte, error) {
 	}
 	b.mixKey(s)
 
+	// se
+	if b.pattern == PatternIK {
+		se, err := ecdh(b.remoteStatic, b.localEphemeral)
+		if err != nil {
+			return actTwo, err
+		}
+		b.mixKey(se)
+	}
+
 	authPayload := b.EncryptAndHash([]byte{})
 
-	actTwo[0] = HandshakeVersion
+	actTwo[0] = b.pattern.version()
 	copy(actTwo[1:34], ephemeral)
 	copy(actTwo[34:], authPayload)
 
@@ -560,7 +907,7 @@ func (b *Machine) GenActTwo() ([ActTwoSize]byte, error) {
 
 // RecvActTwo processes the second packet (act two) sent from the responder to
 // the initiator. A successful processing of this packet authenticates the
-// initiator to the responder.
+// responder to the initiator.
 func (b *Machine) RecvActTwo(actTwo [ActTwoSize]byte) error {
 	var (
 		err error
@@ -570,9 +917,10 @@ func (b *Machine) RecvActTwo(actTwo [ActTwoSize]byte) error {
 
 	// If the handshake version is unknown, then the handshake fails
 	// immediately.
-	if actTwo[0] != HandshakeVersion {
+	version := b.pattern.version()
+	if actTwo[0] != version {
 		return fmt.Errorf("act two: invalid handshake version: %v, "+
-			"only %v is valid, msg=%x", actTwo[0], HandshakeVersion,
+			"only %v is valid, msg=%x", actTwo[0], version,
 			actTwo[:])
 	}
 
@@ -593,6 +941,15 @@ func (b *Machine) RecvActTwo(actTwo [ActTwoSize]byte) error {
 	}
 	b.mixKey(s)
 
+	// se
+	if b.pattern == PatternIK {
+		se, err := ecdh(b.remoteEphemeral, b.localStatic)
+		if err != nil {
+			return err
+		}
+		b.mixKey(se)
+	}
+
 	_, err = b.DecryptAndHash(p[:])
 	return err
 }
@@ -607,6 +964,10 @@ func (b *Machine) RecvActTwo(actTwo [ActTwoSize]byte) error {
 func (b *Machine) GenActThree() ([ActThreeSize]byte, error) {
 	var actThree [ActThreeSize]byte
 
+	if err := b.checkPattern("act three", PatternXK); err != nil {
+		return actThree, err
+	}
+
 	ourPubkey := b.localStatic.PubKey().SerializeCompressed()
 	ciphertext := b.EncryptAndHash(ourPubkey)
 
@@ -640,6 +1001,10 @@ func (b *Machine) RecvActThree(actThree [ActThreeSize]byte) error {
 		p   [16]byte
 	)
 
+	if err := b.checkPattern("act three", PatternXK); err != nil {
+		return err
+	}
+
 	// If the handshake version is unknown, then the handshake fails
 	// immediately.
 	if actThree[0] != HandshakeVersion {
@@ -679,8 +1044,61 @@ func (b *Machine) RecvActThree(actThree [ActThreeSize]byte) error {
 	return nil
 }
 
+// GenActThreeIK creates the final (act three) packet of the Noise_IK
+// handshake, which is sent from the initiator to the responder. The first act
+// of the Noise_IK handshake doesn't depend on any input of the responder, so
+// it can be replayed by anyone who observed it. Act three proves to the
+// responder that the initiator holds the private key of the ephemeral key it
+// sent in act one, and thus that it is taking part in this handshake. The
+// session sending and receiving keys are derived once it's created.
+func (b *Machine) GenActThreeIK() ([ActThreeIKSize]byte, error) {
+	var actThree [ActThreeIKSize]byte
+
+	if err := b.checkPattern("IK act three", PatternIK); err != nil {
+		return actThree, err
+	}
+
+	authPayload := b.EncryptAndHash([]byte{})
+
+	actThree[0] = HandshakeVersionIK
+	copy(actThree[1:], authPayload)
+
+	b.split()
+
+	return actThree, nil
+}
+
+// RecvActThreeIK processes the final act (act three) of the Noise_IK
+// handshake sent from the initiator to the responder. The tag of the act can
+// only be created with the key derived from the ee and se ECDH operations,
+// which requires the private key of the initiator's ephemeral key. Only after
+// processing this act is the initiator authenticated to the responder.
+func (b *Machine) RecvActThreeIK(actThree [ActThreeIKSize]byte) error {
+	if err := b.checkPattern("IK act three", PatternIK); err != nil {
+		return err
+	}
+
+	// If the handshake version is unknown, then the handshake fails
+	// immediately.
+	if actThree[0] != HandshakeVersionIK {
+		return fmt.Errorf("IK act three: invalid handshake version: "+
+			"%v, only %v is valid, msg=%x", actThree[0],
+			HandshakeVersionIK, actThree[:])
+	}
+
+	if _, err := b.DecryptAndHash(actThree[1:]); err != nil {
+		return err
+	}
+
+	// With the initiator authenticated, derive the session sending and
+	// receiving keys.
+	b.split()
+
+	return nil
+}
+
 // split is the final wrap-up act to be executed at the end of a successful
-// three act handshake. This function creates two internal cipherState
+// handshake. This function creates two internal cipherState
 // instances: one which is used to encrypt messages from the initiator to the
 // responder, and another which is used to encrypt message for the opposite
 // direction.
@@ -713,6 +1131,14 @@ func (b *Machine) split() {
 		b.sendCipher = cipherState{}
 		b.sendCipher.InitializeKeyWithSalt(b.chainingKey, sendKey)
 	}
+
+	b.sendCipher.onRotate = func() {
+		b.sendKeyRotated(RotationNonceLimit)
+	}
+	b.recvCipher.onRotate = func() {
+		b.notifyRotation(false, RotationNonceLimit)
+	}
+	b.lastSendRotation = time.Now()
 }
 
 // WriteMessage encrypts and buffers the next message p. The ciphertext of the
@@ -736,18 +1162,39 @@ func (b *Machine) WriteMessage(p []byte) error {
 		return ErrMessageNotFlushed
 	}
 
+	// Rotate the sending key ahead of the rotation interval if our policy
+	// or an explicit request asks for it. This must happen before the
+	// header is encrypted, so the remote party can detect the rotation
+	// when decrypting it.
+	if reason, ok := b.sendRotationDue(); ok {
+		b.sendCipher.rotateKey()
+		b.sendKeyRotated(reason)
+	}
+	b.sendMsgs++
+
 	// The full length of the packet is only the packet length, and does
 	// NOT include the MAC.
 	fullLength := uint16(len(p))
 
-	var pktLen [2]byte
-	binary.BigEndian.PutUint16(pktLen[:], fullLength)
+	binary.BigEndian.PutUint16(b.pktLenSend[:], fullLength)
 
 	// First, generate the encrypted+MAC'd length prefix for the packet.
-	b.nextHeaderSend = b.sendCipher.Encrypt(nil, nil, pktLen[:])
+	b.nextHeaderSend = b.sendCipher.Encrypt(
+		nil, b.nextCipherHeaderSend[:0], b.pktLenSend[:],
+	)
 
-	// Finally, generate the encrypted packet itself.
-	b.nextBodySend = b.sendCipher.Encrypt(nil, nil, p)
+	// Finally, generate the encrypted packet itself. The ciphertext is
+	// written into our send buffer, which is only grown if the message
+	// doesn't fit. As the previous message has been fully flushed, the
+	// buffer is safe to re-use.
+	b.nextBodySend = b.sendCipher.Encrypt(nil, b.bodySendBuf[:0], p)
+
+	// Retain the (possibly grown) buffer for the next message, unless it
+	// has become too large to keep around for the lifetime of the
+	// connection.
+	if cap(b.nextBodySend) <= maxBodySendBufSize {
+		b.bodySendBuf = b.nextBodySend
+	}
 
 	return nil
 }
@@ -855,14 +1302,30 @@ func (b *Machine) ReadHeader(r io.Reader) (uint32, error) {
 	}
 
 	// Attempt to decrypt+auth the packet length present in the stream.
+	// We keep a copy of the cipher state around, in case the remote party
+	// rotated its key ahead of the rotation interval.
 	//
-	// By passing in `nextCipherHeader` as the destination, we avoid making
-	// the library allocate a new buffer to decode the plaintext.
+	// By passing in `pktLenRecv` as the destination, we avoid making the
+	// library allocate a new buffer to decode the plaintext.
+	prevCipher := b.recvCipher
 	pktLenBytes, err := b.recvCipher.Decrypt(
-		nil, b.nextCipherHeader[:0], b.nextCipherHeader[:],
+		nil, b.pktLenRecv[:0], b.nextCipherHeader[:],
 	)
 	if err != nil {
-		return 0, err
+		// If the header can't be authenticated with the current key,
+		// the remote party may have rotated its sending key. We only
+		// look a single rotation ahead, as the remote party rotates
+		// at most once per message.
+		prevCipher.rotateKey()
+		pktLenBytes, err = prevCipher.Decrypt(
+			nil, b.pktLenRecv[:0], b.nextCipherHeader[:],
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		b.recvCipher = prevCipher
+		b.notifyRotation(false, RotationRemote)
 	}
 
 	// Compute the packet length that we will need to read off the wire.
@@ -9,6 +9,7 @@ import (
 	"net"
 	"testing"
 	"testing/iotest"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightningnetwork/lnd/keychain"
@@ -22,7 +23,7 @@ type maybeNetConn struct {
 	err  error
 }
 
-func makeListener() (*Listener, *lnwire.NetAddress, error) {
+func makeListener(cfg *Config) (*Listener, *lnwire.NetAddress, error) {
 	// First, generate the long-term private keys for the brontide listener.
 	localPriv, err := btcec.NewPrivateKey()
 	if err != nil {
@@ -35,7 +36,7 @@ func makeListener() (*Listener, *lnwire.NetAddress, error) {
 	addr := "localhost:0"
 
 	// Our listener will be local, and the connection remote.
-	listener, err := NewListener(localKeyECDH, addr)
+	listener, err := NewListener(localKeyECDH, addr, cfg)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -48,8 +49,10 @@ func makeListener() (*Listener, *lnwire.NetAddress, error) {
 	return listener, netAddr, nil
 }
 
-func establishTestConnection() (net.Conn, net.Conn, func(), error) {
-	listener, netAddr, err := makeListener()
+func establishTestConnection(options ...func(*Machine)) (net.Conn, net.Conn,
+	func(), error) {
+
+	listener, netAddr, err := makeListener(nil)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -70,7 +73,8 @@ func establishTestConnection() (net.Conn, net.Conn, func(), error) {
 	go func() {
 		remoteConn, err := Dial(
 			remoteKeyECDH, netAddr,
-			tor.DefaultConnTimeout, net.DialTimeout,
+			tor.DefaultConnTimeout, net.DialTimeout, nil,
+			options...,
 		)
 		remoteConnChan <- maybeNetConn{remoteConn, err}
 	}()
@@ -147,13 +151,316 @@ func TestConnectionCorrectness(t *testing.T) {
 	}
 }
 
+// TestNoiseIKConnection asserts that the listener accepts connections that
+// are established with the Noise_IK handshake, and that messages are
+// delivered in both directions.
+func TestNoiseIKConnection(t *testing.T) {
+	localConn, remoteConn, cleanUp, err := establishTestConnection(
+		NoisePattern(PatternIK),
+	)
+	require.NoError(t, err, "unable to establish test connection")
+	defer cleanUp()
+
+	local := localConn.(*Conn)
+	remote := remoteConn.(*Conn)
+	require.Equal(t, PatternIK, local.HandshakePattern())
+	require.Equal(t, PatternIK, remote.HandshakePattern())
+
+	// Both sides should have learned each other's static key.
+	require.True(t, local.RemotePub().IsEqual(remote.LocalPub()))
+	require.True(t, remote.RemotePub().IsEqual(local.LocalPub()))
+
+	for i := 0; i < 10; i++ {
+		msg := []byte(fmt.Sprintf("hello%d", i))
+
+		_, err := localConn.Write(msg)
+		require.NoError(t, err)
+
+		readBuf := make([]byte, len(msg))
+		_, err = io.ReadFull(remoteConn, readBuf)
+		require.NoError(t, err)
+		require.Equal(t, msg, readBuf)
+
+		_, err = remoteConn.Write(msg)
+		require.NoError(t, err)
+
+		_, err = io.ReadFull(localConn, readBuf)
+		require.NoError(t, err)
+		require.Equal(t, msg, readBuf)
+	}
+}
+
+// TestNoiseIKDisabled asserts that a listener rejects connections that are
+// established with the Noise_IK handshake if it is disabled in its config.
+func TestNoiseIKDisabled(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.NoNoiseIK = true
+
+	listener, netAddr, err := makeListener(cfg)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	remotePriv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	remoteKeyECDH := &keychain.PrivKeyECDH{PrivKey: remotePriv}
+
+	_, err = Dial(
+		remoteKeyECDH, netAddr, tor.DefaultConnTimeout, net.DialTimeout,
+		nil, NoisePattern(PatternIK),
+	)
+	require.Error(t, err)
+
+	// The Noise_XK handshake is still accepted.
+	conn, err := Dial(
+		remoteKeyECDH, netAddr, tor.DefaultConnTimeout, net.DialTimeout,
+		nil,
+	)
+	require.NoError(t, err)
+	conn.Close()
+}
+
+// TestNoiseIKHandshake asserts that the acts of the Noise_IK handshake derive
+// matching session keys, that the handshake fails if the initiator doesn't
+// know the responder's static key or if the first act is replayed, and that
+// the acts of the two patterns can't be mixed.
+func TestNoiseIKHandshake(t *testing.T) {
+	newKey := func() *keychain.PrivKeyECDH {
+		priv, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		return &keychain.PrivKeyECDH{PrivKey: priv}
+	}
+	initiatorKey, responderKey := newKey(), newKey()
+
+	initiator := NewBrontideMachine(
+		true, initiatorKey, responderKey.PubKey(),
+		NoisePattern(PatternIK),
+	)
+	responder := NewBrontideMachine(
+		false, responderKey, nil, NoisePattern(PatternIK),
+	)
+
+	// The acts of the Noise_XK handshake can't be used with a Noise_IK
+	// machine.
+	_, err := initiator.GenActOne()
+	require.Error(t, err)
+	_, err = initiator.GenActThree()
+	require.Error(t, err)
+
+	actOne, err := initiator.GenActOneIK()
+	require.NoError(t, err)
+	require.Equal(t, HandshakeVersionIK, actOne[0])
+	require.NoError(t, responder.RecvActOneIK(actOne))
+
+	// The responder learns the initiator's static key in the first act.
+	require.True(t, responder.remoteStatic.IsEqual(initiatorKey.PubKey()))
+
+	actTwo, err := responder.GenActTwo()
+	require.NoError(t, err)
+	require.Equal(t, HandshakeVersionIK, actTwo[0])
+	require.NoError(t, initiator.RecvActTwo(actTwo))
+
+	actThree, err := initiator.GenActThreeIK()
+	require.NoError(t, err)
+	require.Equal(t, HandshakeVersionIK, actThree[0])
+	require.NoError(t, responder.RecvActThreeIK(actThree))
+
+	// Both sides should now be able to decrypt each other's messages.
+	var buf bytes.Buffer
+	msg := []byte("hello")
+	require.NoError(t, initiator.WriteMessage(msg))
+	_, err = initiator.Flush(&buf)
+	require.NoError(t, err)
+
+	received, err := responder.ReadMessage(&buf)
+	require.NoError(t, err)
+	require.Equal(t, msg, received)
+
+	require.NoError(t, responder.WriteMessage(msg))
+	_, err = responder.Flush(&buf)
+	require.NoError(t, err)
+
+	received, err = initiator.ReadMessage(&buf)
+	require.NoError(t, err)
+	require.Equal(t, msg, received)
+
+	// If the first act is replayed to a new responder, the replaying
+	// party can't create the third act, as it lacks the initiator's
+	// ephemeral key. We mimic it with a machine that uses the right static
+	// keys but has a different ephemeral key.
+	responder = NewBrontideMachine(
+		false, responderKey, nil, NoisePattern(PatternIK),
+	)
+	require.NoError(t, responder.RecvActOneIK(actOne))
+	actTwo, err = responder.GenActTwo()
+	require.NoError(t, err)
+
+	replayer := NewBrontideMachine(
+		true, initiatorKey, responderKey.PubKey(),
+		NoisePattern(PatternIK),
+	)
+	_, err = replayer.GenActOneIK()
+	require.NoError(t, err)
+	require.Error(t, replayer.RecvActTwo(actTwo))
+
+	var forged [ActThreeIKSize]byte
+	forged[0] = HandshakeVersionIK
+	require.Error(t, responder.RecvActThreeIK(forged))
+
+	// If the initiator uses the wrong static key for the responder, the
+	// responder should fail to process the first act.
+	initiator = NewBrontideMachine(
+		true, initiatorKey, newKey().PubKey(), NoisePattern(PatternIK),
+	)
+	responder = NewBrontideMachine(
+		false, responderKey, nil, NoisePattern(PatternIK),
+	)
+
+	actOne, err = initiator.GenActOneIK()
+	require.NoError(t, err)
+	require.Error(t, responder.RecvActOneIK(actOne))
+
+	// A Noise_XK responder should reject the first act of the Noise_IK
+	// handshake.
+	responder = NewBrontideMachine(false, responderKey, nil)
+	_, err = responder.GenActOneIK()
+	require.Error(t, err)
+
+	var xkActOne [ActOneSize]byte
+	copy(xkActOne[:], actOne[:])
+	require.Error(t, responder.RecvActOne(xkActOne))
+}
+
+// TestKeyRotation asserts that the sending key is rotated according to the key
+// rotation policy and on request, that the remote party follows these
+// rotations and that the rotation hooks are called.
+func TestKeyRotation(t *testing.T) {
+	var sendRotations, recvRotations []KeyRotation
+	localConn, remoteConn, cleanUp, err := establishTestConnection(
+		RotationHook(func(r KeyRotation) {
+			sendRotations = append(sendRotations, r)
+		}),
+	)
+	require.NoError(t, err, "unable to establish test connection")
+	defer cleanUp()
+
+	local := localConn.(*Conn)
+	remote := remoteConn.(*Conn)
+	local.noise.rotationHook = func(r KeyRotation) {
+		recvRotations = append(recvRotations, r)
+	}
+
+	// sendMsgs writes the given number of messages from the remote to the
+	// local side and asserts they are received.
+	sendMsgs := func(n int) {
+		for i := 0; i < n; i++ {
+			msg := []byte(fmt.Sprintf("hello%d", i))
+
+			_, err := remoteConn.Write(msg)
+			require.NoError(t, err)
+
+			readBuf := make([]byte, len(msg))
+			_, err = io.ReadFull(localConn, readBuf)
+			require.NoError(t, err)
+			require.Equal(t, msg, readBuf)
+		}
+	}
+
+	// assertRotations asserts that the remote side rotated its sending key
+	// and the local side its receiving key for the expected reasons.
+	assertRotations := func(reasons ...KeyRotationReason) {
+		t.Helper()
+
+		require.Len(t, sendRotations, len(reasons))
+		require.Len(t, recvRotations, len(reasons))
+		for i, reason := range reasons {
+			require.True(t, sendRotations[i].Send)
+			require.Equal(t, reason, sendRotations[i].Reason)
+			require.True(
+				t, sendRotations[i].RemotePub.IsEqual(
+					local.LocalPub(),
+				),
+			)
+
+			expReason := RotationRemote
+			if reason == RotationNonceLimit {
+				expReason = RotationNonceLimit
+			}
+			require.False(t, recvRotations[i].Send)
+			require.Equal(t, expReason, recvRotations[i].Reason)
+		}
+
+		sendRotations = nil
+		recvRotations = nil
+	}
+
+	// Without a policy, the keys aren't rotated ahead of the rotation
+	// interval.
+	sendMsgs(10)
+	assertRotations()
+
+	// With a message limit, the key is rotated with the message following
+	// the limit. As we already sent more messages than that, the first
+	// message rotates the key right away.
+	remote.SetKeyRotationPolicy(KeyRotationPolicy{Messages: 3})
+	sendMsgs(7)
+	assertRotations(
+		RotationMessageLimit, RotationMessageLimit,
+		RotationMessageLimit,
+	)
+
+	// An explicit request rotates the key with the next message.
+	remote.SetKeyRotationPolicy(KeyRotationPolicy{})
+	remote.RotateKeys()
+	sendMsgs(1)
+	assertRotations(RotationExplicit)
+
+	// With a time limit, the key is rotated with the first message after
+	// the interval has passed.
+	remote.SetKeyRotationPolicy(KeyRotationPolicy{Interval: time.Hour})
+	sendMsgs(1)
+	assertRotations()
+
+	remote.noise.lastSendRotation = time.Now().Add(-2 * time.Hour)
+	sendMsgs(2)
+	assertRotations(RotationTimeLimit)
+
+	// The rotation every 1000 encryptions happens on both sides as usual.
+	// As both the header and body are encrypted, that's after 500
+	// messages.
+	remote.SetKeyRotationPolicy(KeyRotationPolicy{})
+	sendMsgs(keyRotationInterval / 2)
+	assertRotations(RotationNonceLimit)
+
+	// Messages in the other direction aren't affected by the rotations.
+	msg := []byte("hello")
+	_, err = localConn.Write(msg)
+	require.NoError(t, err)
+
+	readBuf := make([]byte, len(msg))
+	_, err = io.ReadFull(remoteConn, readBuf)
+	require.NoError(t, err)
+	require.Equal(t, msg, readBuf)
+
+	// The local side only looks a single rotation ahead, so it can't
+	// decrypt a message if the key was rotated twice in between.
+	remote.noise.sendCipher.rotateKey()
+	remote.RotateKeys()
+
+	_, err = remoteConn.Write(msg)
+	require.NoError(t, err)
+
+	_, err = io.ReadFull(localConn, readBuf)
+	require.Error(t, err)
+}
+
 // TestConecurrentHandshakes verifies the listener's ability to not be blocked
 // by other pending handshakes. This is tested by opening multiple tcp
 // connections with the listener, without completing any of the brontide acts.
 // The test passes if real brontide dialer connects while the others are
 // stalled.
 func TestConcurrentHandshakes(t *testing.T) {
-	listener, netAddr, err := makeListener()
+	listener, netAddr, err := makeListener(nil)
 	require.NoError(t, err, "unable to create listener connection")
 	defer listener.Close()
 
@@ -197,7 +504,7 @@ func TestConcurrentHandshakes(t *testing.T) {
 	go func() {
 		remoteConn, err := Dial(
 			remoteKeyECDH, netAddr,
-			tor.DefaultConnTimeout, net.DialTimeout,
+			tor.DefaultConnTimeout, net.DialTimeout, nil,
 		)
 		connChan <- maybeNetConn{remoteConn, err}
 	}()
@@ -215,6 +522,131 @@ func TestConcurrentHandshakes(t *testing.T) {
 	result.conn.Close()
 }
 
+// TestHandshakeTimeout asserts that the listener fails connections that don't
+// deliver the first act of the handshake within the configured handshake
+// timeout.
+func TestHandshakeTimeout(t *testing.T) {
+	const handshakeTimeout = 100 * time.Millisecond
+
+	cfg := DefaultConfig()
+	cfg.HandshakeTimeout = handshakeTimeout
+
+	listener, _, err := makeListener(cfg)
+	require.NoError(t, err, "unable to create listener connection")
+	defer listener.Close()
+
+	// Open a tcp connection that never completes any steps of the
+	// brontide handshake.
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err, "unable to tcp dial listener")
+	defer conn.Close()
+
+	// The stalled connection should be rejected with a timeout error once
+	// the handshake timeout expires.
+	start := time.Now()
+	_, err = listener.Accept()
+	require.Error(t, err)
+	require.GreaterOrEqual(t, time.Since(start), handshakeTimeout/2)
+	require.Less(t, time.Since(start), DefaultHandshakeTimeout)
+	require.Contains(t, err.Error(), "i/o timeout")
+}
+
+// TestConfigValidate asserts that invalid timeouts are rejected by both the
+// dialer and the listener.
+func TestConfigValidate(t *testing.T) {
+	require.NoError(t, DefaultConfig().Validate())
+
+	// A missing handshake timeout would allow peers to stall handshakes
+	// indefinitely.
+	cfg := DefaultConfig()
+	cfg.HandshakeTimeout = 0
+	require.Error(t, cfg.Validate())
+
+	_, _, err := makeListener(cfg)
+	require.Error(t, err)
+
+	// Read and write timeouts may be disabled, but not negative.
+	cfg = DefaultConfig()
+	cfg.ReadTimeout = 0
+	cfg.WriteTimeout = 0
+	require.NoError(t, cfg.Validate())
+
+	cfg.ReadTimeout = -time.Second
+	require.Error(t, cfg.Validate())
+
+	cfg = DefaultConfig()
+	cfg.WriteTimeout = -time.Second
+	require.Error(t, cfg.Validate())
+}
+
+// TestSocketConfig asserts that connections can be established with custom
+// socket options and that SO_REUSEPORT allows multiple listeners on the same
+// address.
+func TestSocketConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Socket = SocketConfig{
+		Nagle:     true,
+		KeepAlive: time.Minute,
+		ReusePort: reusePortSupported,
+	}
+
+	listener, netAddr, err := makeListener(cfg)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	remotePriv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	remoteKeyECDH := &keychain.PrivKeyECDH{PrivKey: remotePriv}
+
+	connChan := make(chan maybeNetConn, 1)
+	go func() {
+		conn, err := listener.Accept()
+		connChan <- maybeNetConn{conn, err}
+	}()
+
+	remoteConn, err := Dial(
+		remoteKeyECDH, netAddr, tor.DefaultConnTimeout,
+		net.DialTimeout, cfg,
+	)
+	require.NoError(t, err)
+	defer remoteConn.Close()
+
+	local := <-connChan
+	require.NoError(t, local.err)
+	defer local.conn.Close()
+
+	msg := []byte("hello")
+	_, err = remoteConn.Write(msg)
+	require.NoError(t, err)
+
+	readBuf := make([]byte, len(msg))
+	_, err = io.ReadFull(local.conn, readBuf)
+	require.NoError(t, err)
+	require.Equal(t, msg, readBuf)
+
+	// Connections that aren't backed by a TCP socket are left untouched.
+	pipeConn, _ := net.Pipe()
+	require.NoError(t, cfg.Socket.Apply(pipeConn))
+
+	if !reusePortSupported {
+		cfg.Socket.ReusePort = true
+		require.ErrorIs(t, cfg.Validate(), errReusePortUnsupported)
+
+		return
+	}
+
+	// With SO_REUSEPORT, another listener can be bound to the same
+	// address. Without it, that fails.
+	addr := listener.Addr().String()
+	l, err := cfg.Socket.Listen(addr)
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+
+	noReuse := SocketConfig{}
+	_, err = noReuse.Listen(addr)
+	require.Error(t, err)
+}
+
 func TestMaxPayloadLength(t *testing.T) {
 	t.Parallel()
 
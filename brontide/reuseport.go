@@ -0,0 +1,18 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+// +build linux darwin dragonfly freebsd netbsd openbsd
+
+package brontide
+
+import "golang.org/x/sys/unix"
+
+// reusePortSupported indicates whether SO_REUSEPORT can be set on this
+// platform.
+const reusePortSupported = true
+
+// setReusePort sets SO_REUSEPORT on the socket with the given file
+// descriptor.
+func setReusePort(fd uintptr) error {
+	return unix.SetsockoptInt(
+		int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1,
+	)
+}
@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!dragonfly,!freebsd,!netbsd,!openbsd
+
+package brontide
+
+// reusePortSupported indicates whether SO_REUSEPORT can be set on this
+// platform.
+const reusePortSupported = false
+
+// setReusePort always fails, as SO_REUSEPORT isn't supported on this
+// platform.
+func setReusePort(uintptr) error {
+	return errReusePortUnsupported
+}
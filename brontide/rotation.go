@@ -0,0 +1,186 @@
+package brontide
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// KeyRotationReason describes why the key of a cipher stream was rotated.
+type KeyRotationReason uint8
+
+const (
+	// RotationNonceLimit indicates that the key was rotated because the
+	// rotation interval of BOLT 8 was reached.
+	RotationNonceLimit KeyRotationReason = iota
+
+	// RotationMessageLimit indicates that the sending key was rotated
+	// because the message limit of the key rotation policy was reached.
+	RotationMessageLimit
+
+	// RotationTimeLimit indicates that the sending key was rotated because
+	// the time limit of the key rotation policy was reached.
+	RotationTimeLimit
+
+	// RotationExplicit indicates that the sending key was rotated because
+	// it was requested with RotateKeys.
+	RotationExplicit
+
+	// RotationRemote indicates that the receiving key was rotated because
+	// the remote party rotated its sending key ahead of the rotation
+	// interval.
+	RotationRemote
+)
+
+// String returns a human-readable description of the rotation reason.
+func (r KeyRotationReason) String() string {
+	switch r {
+	case RotationNonceLimit:
+		return "nonce limit"
+
+	case RotationMessageLimit:
+		return "message limit"
+
+	case RotationTimeLimit:
+		return "time limit"
+
+	case RotationExplicit:
+		return "explicit"
+
+	case RotationRemote:
+		return "remote"
+
+	default:
+		return fmt.Sprintf("<unknown rotation reason: %d>", uint8(r))
+	}
+}
+
+// KeyRotation describes a single rotation of the sending or receiving key of
+// a brontide Machine.
+type KeyRotation struct {
+	// RemotePub is the static public key of the remote party.
+	RemotePub *btcec.PublicKey
+
+	// Send is true if our sending key was rotated, and false if the
+	// receiving key was rotated.
+	Send bool
+
+	// Reason is the reason the key was rotated.
+	Reason KeyRotationReason
+}
+
+// KeyRotationPolicy determines when the sending key of a brontide Machine is
+// rotated in addition to the rotation every 1000 encryptions mandated by BOLT
+// 8. Rotating the key more often bounds the amount of traffic that can be
+// decrypted if a key is compromised on long-lived connections.
+//
+// NOTE: The remote party must be able to follow rotations that happen ahead
+// of the BOLT 8 rotation interval, so a policy should only be set for peers
+// that signal support for it.
+type KeyRotationPolicy struct {
+	// Messages is the number of messages after which the sending key is
+	// rotated. If zero, the key isn't rotated based on the number of
+	// messages.
+	Messages uint64
+
+	// Interval is the time after which the sending key is rotated. The
+	// key is rotated with the first message written after the interval
+	// has passed. If zero, the key isn't rotated based on time.
+	Interval time.Duration
+}
+
+// RotationPolicy is a functional option that sets the key rotation policy of
+// the brontide Machine. The function closure returned by this function can be
+// passed into NewBrontideMachine as a function option parameter.
+func RotationPolicy(policy KeyRotationPolicy) func(*Machine) {
+	return func(m *Machine) {
+		m.rotationPolicy = policy
+	}
+}
+
+// RotationHook is a functional option that sets a hook that is called for
+// every rotation of the sending or receiving key of the brontide Machine. The
+// hook is called from the goroutine that writes or reads the message which
+// caused the rotation, so it must not block. The function closure returned by
+// this function can be passed into NewBrontideMachine as a function option
+// parameter.
+func RotationHook(hook func(KeyRotation)) func(*Machine) {
+	return func(m *Machine) {
+		m.rotationHook = hook
+	}
+}
+
+// RotateKeys requests the rotation of our sending key. The key is rotated
+// before the next message is written, the remote party rotates its receiving
+// key once it receives that message.
+//
+// NOTE: The remote party must support key rotations ahead of the BOLT 8
+// rotation interval. This method is safe for concurrent use.
+func (b *Machine) RotateKeys() {
+	b.rotationMtx.Lock()
+	defer b.rotationMtx.Unlock()
+
+	b.rotationRequested = true
+}
+
+// SetKeyRotationPolicy replaces the key rotation policy of the Machine. This
+// allows a policy to be set once the remote party signaled support for it
+// after the handshake.
+//
+// NOTE: This method is safe for concurrent use.
+func (b *Machine) SetKeyRotationPolicy(policy KeyRotationPolicy) {
+	b.rotationMtx.Lock()
+	defer b.rotationMtx.Unlock()
+
+	b.rotationPolicy = policy
+}
+
+// sendRotationDue returns whether our sending key should be rotated before
+// the next message is written, and the reason for the rotation.
+func (b *Machine) sendRotationDue() (KeyRotationReason, bool) {
+	b.rotationMtx.Lock()
+	defer b.rotationMtx.Unlock()
+
+	policy := b.rotationPolicy
+
+	switch {
+	case b.rotationRequested:
+		b.rotationRequested = false
+		return RotationExplicit, true
+
+	case policy.Messages != 0 && b.sendMsgs >= policy.Messages:
+		return RotationMessageLimit, true
+
+	case policy.Interval != 0 &&
+		time.Since(b.lastSendRotation) >= policy.Interval:
+
+		return RotationTimeLimit, true
+
+	default:
+		return 0, false
+	}
+}
+
+// sendKeyRotated resets the message count and time of our sending key after
+// it has been rotated and notifies the rotation hook.
+func (b *Machine) sendKeyRotated(reason KeyRotationReason) {
+	b.sendMsgs = 0
+	b.lastSendRotation = time.Now()
+
+	b.notifyRotation(true, reason)
+}
+
+// notifyRotation calls the rotation hook, if set, for a rotation of the
+// sending or receiving key.
+func (b *Machine) notifyRotation(send bool, reason KeyRotationReason) {
+	if b.rotationHook == nil {
+		return
+	}
+
+	b.rotationHook(KeyRotation{
+		RemotePub: b.remoteStatic,
+		Send:      send,
+		Reason:    reason,
+	})
+}
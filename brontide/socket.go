@@ -0,0 +1,135 @@
+package brontide
+
+import (
+	"context"
+	"errors"
+	"net"
+	"syscall"
+	"time"
+)
+
+// errReusePortUnsupported is returned if SO_REUSEPORT is requested on a
+// platform that doesn't support it.
+var errReusePortUnsupported = errors.New("SO_REUSEPORT is not supported on " +
+	"this platform")
+
+// SocketConfig houses the options of the TCP sockets underneath brontide
+// connections. The zero value keeps the defaults of the Go runtime: Nagle's
+// algorithm is disabled, TCP keep-alive probes are sent every 15 seconds and
+// SO_REUSEPORT isn't set.
+type SocketConfig struct {
+	// Nagle enables Nagle's algorithm by not setting TCP_NODELAY on the
+	// sockets. This reduces the number of small packets at the cost of
+	// latency.
+	Nagle bool
+
+	// KeepAlive is the period between TCP keep-alive probes. If zero, the
+	// default of the Go runtime is used. If negative, keep-alive probes
+	// are disabled.
+	KeepAlive time.Duration
+
+	// ReusePort sets SO_REUSEPORT on listening sockets, which allows
+	// multiple processes to listen on the same address. The kernel then
+	// balances incoming connections between them.
+	ReusePort bool
+}
+
+// Validate checks that the socket options are supported on this platform.
+func (s *SocketConfig) Validate() error {
+	if s.ReusePort && !reusePortSupported {
+		return errReusePortUnsupported
+	}
+
+	return nil
+}
+
+// Apply sets the socket options on the given connection. Connections that
+// aren't backed by a TCP socket are left untouched. This allows embedders
+// that establish connections themselves, for example with a custom dialer, to
+// apply the same options.
+func (s *SocketConfig) Apply(conn net.Conn) error {
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		return nil
+	}
+
+	if err := tcpConn.SetNoDelay(!s.Nagle); err != nil {
+		return err
+	}
+
+	switch {
+	case s.KeepAlive < 0:
+		return tcpConn.SetKeepAlive(false)
+
+	case s.KeepAlive > 0:
+		if err := tcpConn.SetKeepAlive(true); err != nil {
+			return err
+		}
+
+		return tcpConn.SetKeepAlivePeriod(s.KeepAlive)
+	}
+
+	return nil
+}
+
+// Listen announces on the given TCP address with the socket options applied to
+// the listening socket and all accepted connections. A wildcard address like
+// [::]:9735 or :9735 is served dual-stack, accepting both IPv4 and IPv6
+// connections where the operating system allows it.
+func (s *SocketConfig) Listen(address string) (net.Listener, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	lc := net.ListenConfig{
+		KeepAlive: s.KeepAlive,
+		Control: func(_, _ string, c syscall.RawConn) error {
+			if !s.ReusePort {
+				return nil
+			}
+
+			var sockErr error
+			err := c.Control(func(fd uintptr) {
+				sockErr = setReusePort(fd)
+			})
+			if err != nil {
+				return err
+			}
+
+			return sockErr
+		},
+	}
+
+	l, err := lc.Listen(context.Background(), "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &socketListener{Listener: l, cfg: s}, nil
+}
+
+// socketListener is a net.Listener that applies the socket options to every
+// connection it accepts.
+type socketListener struct {
+	net.Listener
+
+	cfg *SocketConfig
+}
+
+// Accept waits for and returns the next connection with the socket options
+// applied.
+//
+// Part of the net.Listener interface.
+func (l *socketListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.cfg.Apply(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,68 @@
+package ws
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+const (
+	// schemeWS is the URL scheme of plain WebSocket connections.
+	schemeWS = "ws"
+
+	// schemeWSS is the URL scheme of WebSocket connections over TLS.
+	schemeWSS = "wss"
+)
+
+// Addr is the address of a peer that accepts connections over WebSocket. It is
+// the ws:// or wss:// URL of the peer's WebSocket listener.
+type Addr struct {
+	url *url.URL
+}
+
+// A compile-time assertion to ensure that Addr meets the net.Addr interface.
+var _ net.Addr = (*Addr)(nil)
+
+// IsAddr returns whether the given address is a ws:// or wss:// URL rather
+// than a plain host and port.
+func IsAddr(address string) bool {
+	return strings.HasPrefix(address, schemeWS+"://") ||
+		strings.HasPrefix(address, schemeWSS+"://")
+}
+
+// ParseAddr parses the given ws:// or wss:// URL into an Addr. As there is no
+// default port for WebSocket peer connections, the URL must specify the port.
+func ParseAddr(address string) (*Addr, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != schemeWS && u.Scheme != schemeWSS {
+		return nil, fmt.Errorf("invalid websocket address %v, the "+
+			"scheme must be %v or %v", address, schemeWS,
+			schemeWSS)
+	}
+
+	if _, _, err := net.SplitHostPort(u.Host); err != nil {
+		return nil, fmt.Errorf("invalid websocket address %v, the "+
+			"port must be specified: %v", address, err)
+	}
+
+	return &Addr{url: u}, nil
+}
+
+// Network returns the scheme of the address, which is either ws or wss.
+//
+// Part of the net.Addr interface.
+func (a *Addr) Network() string {
+	return a.url.Scheme
+}
+
+// String returns the URL of the address.
+//
+// Part of the net.Addr interface.
+func (a *Addr) String() string {
+	return a.url.String()
+}
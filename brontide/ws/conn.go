@@ -0,0 +1,145 @@
+package ws
+
+import (
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ErrNonBinaryMessage is returned when the remote party sends a WebSocket
+// message that isn't a binary message. The brontide stream is only ever
+// carried in binary messages.
+var ErrNonBinaryMessage = errors.New("websocket message is not binary")
+
+// conn is an implementation of net.Conn which carries a byte stream over a
+// WebSocket connection. Every Write is sent as a single binary message, and
+// Read reads the payloads of the received binary messages as one continuous
+// stream, regardless of how the remote party chose to frame them.
+//
+// NOTE: Unlike a TCP connection, a WebSocket connection can't be used anymore
+// once a read or write timed out. As the brontide stream can't recover from a
+// timeout in the middle of a message either, this doesn't make a difference
+// to its users.
+type conn struct {
+	ws *websocket.Conn
+
+	// reader is the reader of the message that is currently being read,
+	// if any. It is only accessed by Read, which must not be called
+	// concurrently.
+	reader io.Reader
+}
+
+// A compile-time assertion to ensure that conn meets the net.Conn interface.
+var _ net.Conn = (*conn)(nil)
+
+// newConn wraps the given WebSocket connection into a net.Conn.
+func newConn(ws *websocket.Conn) *conn {
+	return &conn{
+		ws: ws,
+	}
+}
+
+// Read reads data from the payloads of the binary messages received on the
+// connection. A normal closure of the WebSocket connection by the remote party
+// is reported as io.EOF.
+//
+// Part of the net.Conn interface.
+func (c *conn) Read(b []byte) (int, error) {
+	for {
+		if c.reader == nil {
+			msgType, reader, err := c.ws.NextReader()
+			switch {
+			case websocket.IsCloseError(
+				err, websocket.CloseNormalClosure,
+				websocket.CloseGoingAway,
+			):
+				return 0, io.EOF
+
+			case err != nil:
+				return 0, err
+			}
+
+			if msgType != websocket.BinaryMessage {
+				return 0, ErrNonBinaryMessage
+			}
+
+			c.reader = reader
+		}
+
+		n, err := c.reader.Read(b)
+		if err == io.EOF {
+			// The current message has been read entirely, so we
+			// continue with the next one unless we already read
+			// some data.
+			c.reader = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+
+		return n, err
+	}
+}
+
+// Write sends the given data as a single binary message.
+//
+// Part of the net.Conn interface.
+func (c *conn) Write(b []byte) (int, error) {
+	err := c.ws.WriteMessage(websocket.BinaryMessage, b)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// Close closes the underlying connection without sending a close message, as
+// the brontide stream already authenticates its end.
+//
+// Part of the net.Conn interface.
+func (c *conn) Close() error {
+	return c.ws.Close()
+}
+
+// LocalAddr returns the local address of the underlying connection.
+//
+// Part of the net.Conn interface.
+func (c *conn) LocalAddr() net.Addr {
+	return c.ws.LocalAddr()
+}
+
+// RemoteAddr returns the remote address of the underlying connection.
+//
+// Part of the net.Conn interface.
+func (c *conn) RemoteAddr() net.Addr {
+	return c.ws.RemoteAddr()
+}
+
+// SetDeadline sets both the read and write deadline of the connection.
+//
+// Part of the net.Conn interface.
+func (c *conn) SetDeadline(t time.Time) error {
+	if err := c.ws.SetReadDeadline(t); err != nil {
+		return err
+	}
+
+	return c.ws.SetWriteDeadline(t)
+}
+
+// SetReadDeadline sets the deadline for future Read calls.
+//
+// Part of the net.Conn interface.
+func (c *conn) SetReadDeadline(t time.Time) error {
+	return c.ws.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets the deadline for future Write calls.
+//
+// Part of the net.Conn interface.
+func (c *conn) SetWriteDeadline(t time.Time) error {
+	return c.ws.SetWriteDeadline(t)
+}
@@ -0,0 +1,52 @@
+package ws
+
+import (
+	"crypto/tls"
+	"net"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/gorilla/websocket"
+	"github.com/lightningnetwork/lnd/brontide"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/tor"
+)
+
+// Dial attempts to establish an encrypted+authenticated connection over a
+// WebSocket connection to the given ws:// or wss:// URL, with the remote peer
+// which has remotePub as its long-term static public key. The timeout applies
+// to both establishing the TCP connection and the WebSocket upgrade. In the
+// case of a handshake failure, the connection is closed and a non-nil error is
+// returned. The timeouts of the brontide connection are taken from the passed
+// config, if it is nil the default timeouts are used. The options are passed
+// to the brontide Machine, which allows selecting the handshake pattern.
+//
+// NOTE: The TLS certificate of a wss:// listener isn't verified, as peers
+// commonly use self-signed certificates and the brontide handshake already
+// authenticates the remote peer by its static public key. TLS only serves to
+// pass through proxies and firewalls that don't allow plain connections.
+func Dial(local keychain.SingleKeyECDH, remotePub *btcec.PublicKey,
+	url string, timeout time.Duration, dialer tor.DialFunc,
+	cfg *brontide.Config,
+	options ...func(*brontide.Machine)) (*brontide.Conn, error) {
+
+	wsDialer := &websocket.Dialer{
+		NetDial: func(network, addr string) (net.Conn, error) {
+			return dialer(network, addr, timeout)
+		},
+		HandshakeTimeout: timeout,
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true, // nolint:gosec
+			MinVersion:         tls.VersionTLS12,
+		},
+	}
+
+	wsConn, _, err := wsDialer.Dial(url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return brontide.NewClientConn(
+		local, remotePub, newConn(wsConn), cfg, options...,
+	)
+}
@@ -0,0 +1,225 @@
+package ws
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/lightningnetwork/lnd/brontide"
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+// defaultHandshakes is the maximum number of handshakes that can be done in
+// parallel.
+const defaultHandshakes = 1000
+
+// Listener is an implementation of a net.Listener which accepts WebSocket
+// connections and executes the brontide handshake over them. Just like the
+// brontide TCP listener, it returns *brontide.Conn connections that are
+// authenticated and encrypted.
+type Listener struct {
+	localStatic keychain.SingleKeyECDH
+
+	cfg *brontide.Config
+
+	tcp net.Listener
+
+	srv *http.Server
+
+	upgrader websocket.Upgrader
+
+	handshakeSema chan struct{}
+	conns         chan maybeConn
+	quit          chan struct{}
+	closeOnce     sync.Once
+}
+
+// A compile-time assertion to ensure that Listener meets the net.Listener
+// interface.
+var _ net.Listener = (*Listener)(nil)
+
+// NewListener returns a new net.Listener which accepts WebSocket connections
+// on the given address and enforces the brontide scheme on top of them. The
+// WebSocket upgrade is accepted on any path. The timeouts of the accepted
+// connections are taken from the passed config, if it is nil the default
+// timeouts are used. If a TLS config is passed, the listener accepts wss://
+// connections over TLS instead of plain ws:// connections.
+func NewListener(localStatic keychain.SingleKeyECDH, listenAddr string,
+	cfg *brontide.Config, tlsCfg *tls.Config) (*Listener, error) {
+
+	if cfg == nil {
+		cfg = brontide.DefaultConfig()
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	l, err := cfg.Socket.Listen(listenAddr)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		l = tls.NewListener(l, tlsCfg)
+	}
+
+	wsListener := &Listener{
+		localStatic: localStatic,
+		cfg:         cfg,
+		tcp:         l,
+		upgrader: websocket.Upgrader{
+			HandshakeTimeout: cfg.HandshakeTimeout,
+
+			// Peers authenticate each other with the brontide
+			// handshake and no ambient credentials like cookies
+			// are involved, so browser-based peers may connect
+			// from any origin.
+			CheckOrigin: func(*http.Request) bool {
+				return true
+			},
+		},
+		handshakeSema: make(chan struct{}, defaultHandshakes),
+		conns:         make(chan maybeConn),
+		quit:          make(chan struct{}),
+	}
+
+	for i := 0; i < defaultHandshakes; i++ {
+		wsListener.handshakeSema <- struct{}{}
+	}
+
+	wsListener.srv = &http.Server{
+		Handler:           wsListener,
+		ReadHeaderTimeout: cfg.HandshakeTimeout,
+	}
+
+	go func() {
+		// Serve always returns a non-nil error, which is expected once
+		// the listener is closed.
+		err := wsListener.srv.Serve(l)
+		if !errors.Is(err, http.ErrServerClosed) {
+			wsListener.rejectConn(err)
+		}
+	}()
+
+	return wsListener, nil
+}
+
+// ServeHTTP upgrades an incoming HTTP request to a WebSocket connection and
+// then performs the brontide handshake over it. Each request is served in its
+// own goroutine, so slow handshakes don't block other connection attempts. A
+// maximum of defaultHandshakes will be active at any given time.
+//
+// Part of the http.Handler interface.
+func (l *Listener) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	select {
+	case <-l.handshakeSema:
+	case <-l.quit:
+		http.Error(w, "listener closed", http.StatusServiceUnavailable)
+		return
+	default:
+		http.Error(
+			w, "too many handshakes", http.StatusServiceUnavailable,
+		)
+		return
+	}
+	defer func() { l.handshakeSema <- struct{}{} }()
+
+	// If the upgrade fails, the upgrader already replied to the request
+	// with an HTTP error.
+	wsConn, err := l.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		l.rejectConn(rejectedConnErr(err, r.RemoteAddr))
+		return
+	}
+
+	brontideConn, err := brontide.NewServerConn(
+		l.localStatic, newConn(wsConn), l.cfg,
+	)
+	if err != nil {
+		l.rejectConn(rejectedConnErr(err, r.RemoteAddr))
+		return
+	}
+
+	l.acceptConn(brontideConn)
+}
+
+// rejectedConnErr is a helper function that prepends the remote address of the
+// failed connection attempt to the original error message.
+func rejectedConnErr(err error, remoteAddr string) error {
+	return fmt.Errorf("unable to accept websocket connection from %v: %v",
+		remoteAddr, err)
+}
+
+// maybeConn holds either a brontide connection or an error returned from the
+// handshake.
+type maybeConn struct {
+	conn *brontide.Conn
+	err  error
+}
+
+// acceptConn returns a connection that successfully performed a handshake.
+func (l *Listener) acceptConn(conn *brontide.Conn) {
+	select {
+	case l.conns <- maybeConn{conn: conn}:
+	case <-l.quit:
+		conn.Close()
+	}
+}
+
+// rejectConn returns any errors encountered during connection or handshake.
+func (l *Listener) rejectConn(err error) {
+	select {
+	case l.conns <- maybeConn{err: err}:
+	case <-l.quit:
+	}
+}
+
+// Accept waits for and returns the next connection to the listener. All
+// incoming connections are authenticated via the three act brontide
+// key-exchange scheme. This function will fail with a non-nil error in the
+// case that either the WebSocket upgrade or the handshake breaks down, or the
+// remote peer doesn't know our static public key.
+//
+// Part of the net.Listener interface.
+func (l *Listener) Accept() (net.Conn, error) {
+	select {
+	case result := <-l.conns:
+		// Avoid returning a typed nil connection along with an error.
+		if result.err != nil {
+			return nil, result.err
+		}
+
+		return result.conn, nil
+
+	case <-l.quit:
+		return nil, errors.New("websocket listener closed")
+	}
+}
+
+// Close closes the listener. Any blocked Accept operations will be unblocked
+// and return errors.
+//
+// Part of the net.Listener interface.
+func (l *Listener) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		close(l.quit)
+
+		// Closing the server also closes the underlying TCP listener.
+		// Connections that were already upgraded are hijacked and
+		// therefore not affected.
+		err = l.srv.Close()
+	})
+
+	return err
+}
+
+// Addr returns the listener's network address.
+//
+// Part of the net.Listener interface.
+func (l *Listener) Addr() net.Addr {
+	return l.tcp.Addr()
+}
@@ -0,0 +1,282 @@
+package ws
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/gorilla/websocket"
+	"github.com/lightningnetwork/lnd/brontide"
+	"github.com/lightningnetwork/lnd/cert"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/tor"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestListener creates a websocket listener on a random local port and
+// returns it along with the public key of its static key and its URL. If a TLS
+// config is passed, the listener accepts wss:// connections.
+func newTestListener(t *testing.T,
+	tlsCfg *tls.Config) (*Listener, *btcec.PublicKey, string) {
+
+	t.Helper()
+
+	localPriv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	listener, err := NewListener(
+		&keychain.PrivKeyECDH{PrivKey: localPriv}, "localhost:0", nil,
+		tlsCfg,
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, listener.Close())
+	})
+
+	scheme := schemeWS
+	if tlsCfg != nil {
+		scheme = schemeWSS
+	}
+	url := fmt.Sprintf("%v://%v/", scheme, listener.Addr())
+
+	return listener, localPriv.PubKey(), url
+}
+
+// newTestTLSConfig returns a TLS config with a freshly generated self-signed
+// certificate.
+func newTestTLSConfig(t *testing.T) *tls.Config {
+	t.Helper()
+
+	certPath := filepath.Join(t.TempDir(), "tls.cert")
+	keyPath := filepath.Join(t.TempDir(), "tls.key")
+	err := cert.GenCertPair(
+		"test", certPath, keyPath, nil, nil, false, time.Hour,
+	)
+	require.NoError(t, err)
+
+	certData, _, err := cert.LoadCert(certPath, keyPath)
+	require.NoError(t, err)
+
+	return cert.TLSConfFromCert(certData)
+}
+
+// newTestKey returns a new static key for the dialing side.
+func newTestKey(t *testing.T) *keychain.PrivKeyECDH {
+	t.Helper()
+
+	remotePriv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	return &keychain.PrivKeyECDH{PrivKey: remotePriv}
+}
+
+// dialResult holds the outcome of a dial attempt.
+type dialResult struct {
+	conn *brontide.Conn
+	err  error
+}
+
+// TestWebSocketConnection asserts that a brontide connection can be
+// established over a plain and a TLS websocket connection and that messages of
+// varying sizes are delivered in both directions.
+func TestWebSocketConnection(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ws", func(t *testing.T) {
+		t.Parallel()
+		testWebSocketConnection(t, nil)
+	})
+
+	t.Run("wss", func(t *testing.T) {
+		t.Parallel()
+		testWebSocketConnection(t, newTestTLSConfig(t))
+	})
+}
+
+// testWebSocketConnection establishes a brontide connection over a websocket
+// connection to a listener with the given TLS config, and exchanges messages
+// over it.
+func testWebSocketConnection(t *testing.T, tlsCfg *tls.Config) {
+	listener, listenerPub, url := newTestListener(t, tlsCfg)
+	remoteKey := newTestKey(t)
+
+	dialChan := make(chan dialResult, 1)
+	go func() {
+		conn, err := Dial(
+			remoteKey, listenerPub, url, tor.DefaultConnTimeout,
+			net.DialTimeout, nil,
+		)
+		dialChan <- dialResult{conn, err}
+	}()
+
+	localConn, err := listener.Accept()
+	require.NoError(t, err)
+	defer localConn.Close()
+
+	dialed := <-dialChan
+	require.NoError(t, dialed.err)
+	remoteConn := dialed.conn
+	defer remoteConn.Close()
+
+	// Both ends should have authenticated each other.
+	local := localConn.(*brontide.Conn)
+	require.True(t, local.RemotePub().IsEqual(remoteKey.PubKey()))
+	require.True(t, remoteConn.RemotePub().IsEqual(listenerPub))
+
+	// Send messages of varying sizes in both directions, including one of
+	// the maximum size.
+	for _, size := range []int{1, 100, 1000, math.MaxUint16} {
+		msg := bytes.Repeat([]byte{byte(size)}, size)
+
+		require.NoError(t, remoteConn.WriteMessage(msg))
+		_, err := remoteConn.Flush()
+		require.NoError(t, err)
+
+		received, err := local.ReadNextMessage()
+		require.NoError(t, err)
+		require.Equal(t, msg, received)
+
+		require.NoError(t, local.WriteMessage(msg))
+		_, err = local.Flush()
+		require.NoError(t, err)
+
+		received, err = remoteConn.ReadNextMessage()
+		require.NoError(t, err)
+		require.Equal(t, msg, received)
+	}
+}
+
+// TestWebSocketWrongKey asserts that the handshake fails if the dialing side
+// doesn't know the static key of the listener.
+func TestWebSocketWrongKey(t *testing.T) {
+	t.Parallel()
+
+	listener, _, url := newTestListener(t, nil)
+	remoteKey := newTestKey(t)
+	wrongPub := newTestKey(t).PubKey()
+
+	dialChan := make(chan dialResult, 1)
+	go func() {
+		conn, err := Dial(
+			remoteKey, wrongPub, url, tor.DefaultConnTimeout,
+			net.DialTimeout, nil,
+		)
+		dialChan <- dialResult{conn, err}
+	}()
+
+	_, err := listener.Accept()
+	require.Error(t, err)
+
+	dialed := <-dialChan
+	require.Error(t, dialed.err)
+}
+
+// TestWebSocketNonBinaryMessage asserts that the handshake fails if the
+// remote party sends text instead of binary messages.
+func TestWebSocketNonBinaryMessage(t *testing.T) {
+	t.Parallel()
+
+	listener, _, url := newTestListener(t, nil)
+
+	wsConn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer wsConn.Close()
+
+	err = wsConn.WriteMessage(
+		websocket.TextMessage, make([]byte, brontide.ActOneSize),
+	)
+	require.NoError(t, err)
+
+	_, err = listener.Accept()
+	require.ErrorContains(t, err, ErrNonBinaryMessage.Error())
+}
+
+// TestConnReadAcrossMessages asserts that the websocket conn reads the
+// payloads of consecutive binary messages as one continuous stream.
+func TestConnReadAcrossMessages(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	upgraded := make(chan *websocket.Conn, 1)
+	go func() {
+		_ = http.Serve(listener, http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				var upgrader websocket.Upgrader
+				wsConn, err := upgrader.Upgrade(w, r, nil)
+				if err != nil {
+					return
+				}
+				upgraded <- wsConn
+			},
+		))
+	}()
+
+	url := fmt.Sprintf("ws://%v/", listener.Addr())
+	wsConn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	client := newConn(wsConn)
+	defer client.Close()
+
+	server := newConn(<-upgraded)
+	defer server.Close()
+
+	// Write a stream in chunks that don't line up with the reads below.
+	for _, chunk := range []string{"he", "llo wo", "rld"} {
+		n, err := client.Write([]byte(chunk))
+		require.NoError(t, err)
+		require.Equal(t, len(chunk), n)
+	}
+
+	buf := make([]byte, len("hello world"))
+	_, err = io.ReadFull(server, buf[:4])
+	require.NoError(t, err)
+	_, err = io.ReadFull(server, buf[4:])
+	require.NoError(t, err)
+	require.Equal(t, "hello world", string(buf))
+
+	// A normal closure by the remote party is reported as io.EOF.
+	err = wsConn.WriteMessage(
+		websocket.CloseMessage, websocket.FormatCloseMessage(
+			websocket.CloseNormalClosure, "",
+		),
+	)
+	require.NoError(t, err)
+
+	_, err = server.Read(buf)
+	require.ErrorIs(t, err, io.EOF)
+}
+
+// TestParseAddr asserts that only ws:// and wss:// URLs with a port are parsed
+// as websocket addresses.
+func TestParseAddr(t *testing.T) {
+	t.Parallel()
+
+	addr, err := ParseAddr("wss://example.com:9737/peer")
+	require.NoError(t, err)
+	require.Equal(t, "wss", addr.Network())
+	require.Equal(t, "wss://example.com:9737/peer", addr.String())
+	require.True(t, IsAddr(addr.String()))
+
+	addr, err = ParseAddr("ws://127.0.0.1:9737")
+	require.NoError(t, err)
+	require.Equal(t, "ws", addr.Network())
+
+	require.False(t, IsAddr("127.0.0.1:9737"))
+
+	_, err = ParseAddr("ws://127.0.0.1")
+	require.Error(t, err)
+
+	_, err = ParseAddr("http://127.0.0.1:9737")
+	require.Error(t, err)
+}
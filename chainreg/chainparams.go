@@ -144,7 +144,8 @@ func ApplyLitecoinParams(params *BitcoinNetParams,
 // parameter configuration.
 func IsTestnet(params *BitcoinNetParams) bool {
 	switch params.Params.Net {
-	case bitcoinWire.TestNet3, bitcoinWire.BitcoinNet(litecoinWire.TestNet4):
+	case bitcoinWire.TestNet3, bitcoinTestNet4,
+		bitcoinWire.BitcoinNet(litecoinWire.TestNet4):
 		return true
 	default:
 		return false
@@ -17,8 +17,10 @@ import (
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/chain"
 	"github.com/lightninglabs/neutrino"
+	"github.com/lightninglabs/neutrino/banman"
 	"github.com/lightningnetwork/lnd/blockcache"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/chainntnfs/bitcoindnotify"
@@ -89,9 +91,21 @@ type Config struct {
 	// if using neutrino.
 	NeutrinoCS *neutrino.ChainService
 
+	// NeutrinoBanStore is the store that holds the bans of the peers of
+	// the neutrino ChainService. Must be non-nil if using neutrino.
+	NeutrinoBanStore banman.Store
+
+	// HSM is the hardware security module that keeps the node identity
+	// key and the channel funding keys. It is nil if the keys aren't kept
+	// in an HSM.
+	HSM keychain.HSM
+
 	// ActiveNetParams details the current chain we are on.
 	ActiveNetParams BitcoinNetParams
 
+	// ActiveNetwork is the network of the current chain we are on.
+	ActiveNetwork *Network
+
 	// FeeURL defines the URL for fee estimation we will use. This field is
 	// optional.
 	FeeURL string
@@ -199,6 +213,12 @@ type PartialChainControl struct {
 	// ChannelConstraints is the set of default constraints that will be
 	// used for any incoming or outgoing channel reservation requests.
 	ChannelConstraints channeldb.ChannelConstraints
+
+	// LookupMempoolSpend returns the unconfirmed transaction that spends
+	// the given outpoint in the mempool of the chain backend, or nil if
+	// there is none. It is nil if the backend doesn't support such
+	// lookups.
+	LookupMempoolSpend func(op wire.OutPoint) (*wire.MsgTx, error)
 }
 
 // ChainControl couples the three primary interfaces lnd utilizes for a
@@ -376,20 +396,10 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 			rpcPort -= 2
 			bitcoindHost = fmt.Sprintf("%v:%d",
 				bitcoindMode.RPCHost, rpcPort)
-			if (cfg.Bitcoin.Active &&
-				(cfg.Bitcoin.RegTest || cfg.Bitcoin.SigNet)) ||
-				(cfg.Litecoin.Active && cfg.Litecoin.RegTest) {
-
+			if cfg.ActiveNetwork.FallbackRPCPort != 0 {
 				conn, err := net.Dial("tcp", bitcoindHost)
 				if err != nil || conn == nil {
-					switch {
-					case cfg.Bitcoin.Active && cfg.Bitcoin.RegTest:
-						rpcPort = 18443
-					case cfg.Litecoin.Active && cfg.Litecoin.RegTest:
-						rpcPort = 19443
-					case cfg.Bitcoin.Active && cfg.Bitcoin.SigNet:
-						rpcPort = 38332
-					}
+					rpcPort = cfg.ActiveNetwork.FallbackRPCPort
 					bitcoindHost = fmt.Sprintf("%v:%d",
 						bitcoindMode.RPCHost,
 						rpcPort)
@@ -453,7 +463,7 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 			DisableTLS:           true,
 			HTTPPostMode:         true,
 		}
-		if cfg.Bitcoin.Active && !cfg.Bitcoin.RegTest {
+		if cfg.Bitcoin.Active && !cfg.ActiveNetwork.StaticFees {
 			log.Infof("Initializing bitcoind backed fee estimator "+
 				"in %s mode", bitcoindMode.EstimateMode)
 
@@ -469,7 +479,7 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 			if err != nil {
 				return nil, nil, err
 			}
-		} else if cfg.Litecoin.Active && !cfg.Litecoin.RegTest {
+		} else if cfg.Litecoin.Active && !cfg.ActiveNetwork.StaticFees {
 			log.Infof("Initializing litecoind backed fee "+
 				"estimator in %s mode",
 				bitcoindMode.EstimateMode)
@@ -597,6 +607,16 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 			return err
 		}
 
+		// The gettxspendingprevout call was added in version 24.0, so
+		// we can only look up mempool spends from there on.
+		if ver >= 240000 {
+			cc.LookupMempoolSpend = func(op wire.OutPoint) (
+				*wire.MsgTx, error) {
+
+				return lookupBitcoindMempoolSpend(chainConn, op)
+			}
+		}
+
 	case "btcd", "ltcd":
 		// Otherwise, we'll be speaking directly via RPC to a node.
 		//
@@ -708,8 +728,7 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 
 		// If we're not in simnet or regtest mode, then we'll attempt
 		// to use a proper fee estimator for testnet.
-		if !cfg.Bitcoin.SimNet && !cfg.Litecoin.SimNet &&
-			!cfg.Bitcoin.RegTest && !cfg.Litecoin.RegTest {
+		if !cfg.ActiveNetwork.StaticFees {
 
 			log.Info("Initializing btcd backed fee estimator")
 
@@ -750,7 +769,8 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 	// If the fee URL isn't set, and the user is running mainnet, then
 	// we'll return an error to instruct them to set a proper fee
 	// estimator.
-	case cfg.FeeURL == "" && cfg.Bitcoin.MainNet &&
+	case cfg.FeeURL == "" && cfg.Bitcoin.Active &&
+		cfg.ActiveNetwork.Name == "mainnet" &&
 		homeChainConfig.Node == "neutrino":
 
 		return nil, nil, fmt.Errorf("--feeurl parameter required " +
@@ -758,9 +778,10 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 
 	// Override default fee estimator if an external service is specified.
 	case cfg.FeeURL != "":
-		// Do not cache fees on regtest to make it easier to execute
-		// manual or automated test cases.
-		cacheFees := !cfg.Bitcoin.RegTest
+		// Do not cache fees on networks with static fees such as
+		// regtest to make it easier to execute manual or automated
+		// test cases.
+		cacheFees := !cfg.ActiveNetwork.StaticFees
 
 		log.Infof("Using external fee estimator %v: cached=%v",
 			cfg.FeeURL, cacheFees)
@@ -872,6 +893,56 @@ func getBitcoindHealthCheckCmd(client *rpcclient.Client) (string, int64, error)
 	return "getblockchaininfo", info.Version, nil
 }
 
+// lookupBitcoindMempoolSpend returns the transaction in bitcoind's mempool that
+// spends the given outpoint, or nil if there is none.
+func lookupBitcoindMempoolSpend(client *rpcclient.Client,
+	op wire.OutPoint) (*wire.MsgTx, error) {
+
+	prevOuts := []struct {
+		Txid string `json:"txid"`
+		Vout uint32 `json:"vout"`
+	}{{
+		Txid: op.Hash.String(),
+		Vout: op.Index,
+	}}
+	param, err := json.Marshal(prevOuts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.RawRequest(
+		"gettxspendingprevout", []json.RawMessage{param},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var spends []struct {
+		SpendingTxid string `json:"spendingtxid"`
+	}
+	if err := json.Unmarshal(resp, &spends); err != nil {
+		return nil, err
+	}
+
+	// The spending txid is omitted if the outpoint isn't spent in the
+	// mempool.
+	if len(spends) == 0 || spends[0].SpendingTxid == "" {
+		return nil, nil
+	}
+
+	txid, err := chainhash.NewHashFromStr(spends[0].SpendingTxid)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := client.GetRawTransaction(txid)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.MsgTx(), nil
+}
+
 var (
 	// BitcoinTestnetGenesis is the genesis hash of Bitcoin's testnet
 	// chain.
@@ -0,0 +1,294 @@
+package chainreg
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Network describes a network lnd can be run on, together with the
+// properties that determine how lnd interacts with it. Support for an
+// additional network, such as a new testnet or a signet with a custom
+// challenge, is added by registering its parameters with RegisterNetwork. A
+// signet with a custom challenge can also be selected from the configuration
+// alone.
+type Network struct {
+	// Name is the name the network is selected by in the configuration,
+	// for example "testnet" or "signet". It must match the name of the
+	// network's data directory, as lncli uses it to locate the macaroons.
+	Name string
+
+	// Chain is the chain the network belongs to.
+	Chain ChainCode
+
+	// BitcoinParams are the parameters of a bitcoin network. Exactly one
+	// of BitcoinParams and LitecoinParams must be set.
+	BitcoinParams *BitcoinNetParams
+
+	// LitecoinParams are the parameters of a litecoin network, which are
+	// applied to the bitcoin typed parameters used throughout the daemon
+	// with ApplyLitecoinParams.
+	LitecoinParams *LitecoinNetParams
+
+	// DNSSeeds are the DNS seeds used to bootstrap peers on the network,
+	// unless the user configured seeds of their own. See ChainDNSSeeds for
+	// the format.
+	DNSSeeds [][2]string
+
+	// FallbackRPCPort is the RPC port that is tried if a bitcoind or
+	// litecoind node can't be reached on the port derived from the
+	// network's RPC port. A value of zero disables the fallback.
+	FallbackRPCPort int
+
+	// DevNetwork indicates that the network is used for development, so we
+	// won't bootstrap connections to peers on it.
+	DevNetwork bool
+
+	// StaticFees indicates that the fee estimates of the chain backend
+	// are meaningless on the network, so static fees are used instead.
+	StaticFees bool
+
+	// BtcdOnly indicates that the network is only supported by the btcd
+	// and ltcd backends.
+	BtcdOnly bool
+
+	// BitcoindOnly indicates that the network is only supported by the
+	// bitcoind backend, as btcd and neutrino don't implement its consensus
+	// rules and would reject valid blocks.
+	BitcoindOnly bool
+}
+
+// ValidateNetworkName checks that the given name can be used as the name of a
+// network, which is also the name of the network's data directory.
+func ValidateNetworkName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("network name must be set")
+
+	// Lncli lower cases the network name it locates the macaroons with.
+	case name != strings.ToLower(name):
+		return fmt.Errorf("network name %q must be lower case", name)
+
+	case name == "." || name == ".." || strings.ContainsAny(name, `/\`):
+		return fmt.Errorf("network name %q isn't a valid directory "+
+			"name", name)
+	}
+
+	return nil
+}
+
+// validate checks that the network is well formed.
+func (n *Network) validate() error {
+	if err := ValidateNetworkName(n.Name); err != nil {
+		return err
+	}
+
+	switch {
+	case (n.BitcoinParams == nil) == (n.LitecoinParams == nil):
+		return fmt.Errorf("network %v must have either bitcoin or "+
+			"litecoin parameters", n.Name)
+
+	case n.BitcoinParams != nil && n.Chain != BitcoinChain:
+		return fmt.Errorf("network %v has bitcoin parameters for the "+
+			"%v chain", n.Name, n.Chain)
+
+	case n.LitecoinParams != nil && n.Chain != LitecoinChain:
+		return fmt.Errorf("network %v has litecoin parameters for the "+
+			"%v chain", n.Name, n.Chain)
+	}
+
+	return nil
+}
+
+// networkRegistry indexes networks by chain and name.
+type networkRegistry struct {
+	mtx sync.RWMutex
+
+	networks map[ChainCode]map[string]*Network
+}
+
+// newNetworkRegistry creates an empty network registry.
+func newNetworkRegistry() *networkRegistry {
+	return &networkRegistry{
+		networks: make(map[ChainCode]map[string]*Network),
+	}
+}
+
+// register adds a network to the registry. An error is returned if a network
+// with the same name was already registered for the chain.
+func (r *networkRegistry) register(network *Network) error {
+	if err := network.validate(); err != nil {
+		return err
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	chainNetworks, ok := r.networks[network.Chain]
+	if !ok {
+		chainNetworks = make(map[string]*Network)
+		r.networks[network.Chain] = chainNetworks
+	}
+	if _, ok := chainNetworks[network.Name]; ok {
+		return fmt.Errorf("network %v already registered for the %v "+
+			"chain", network.Name, network.Chain)
+	}
+	chainNetworks[network.Name] = network
+
+	return nil
+}
+
+// lookup returns the network with the given name of the given chain.
+func (r *networkRegistry) lookup(chain ChainCode, name string) (*Network,
+	error) {
+
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	network, ok := r.networks[chain][name]
+	if !ok {
+		return nil, fmt.Errorf("unknown %v network %q, must be one of "+
+			"%v", chain, name, r.namesLocked(chain))
+	}
+
+	return network, nil
+}
+
+// names returns the sorted names of the networks registered for the given
+// chain.
+func (r *networkRegistry) names(chain ChainCode) []string {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	return r.namesLocked(chain)
+}
+
+// namesLocked returns the sorted names of the networks registered for the
+// given chain.
+//
+// NOTE: The caller must hold the mutex.
+func (r *networkRegistry) namesLocked(chain ChainCode) []string {
+	names := make([]string, 0, len(r.networks[chain]))
+	for name := range r.networks[chain] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// networks is the registry of the networks that can be selected in the
+// configuration.
+var networks = newNetworkRegistry()
+
+// RegisterNetwork makes a network available for selection in the
+// configuration. An error is returned if a network with the same name was
+// already registered for the chain.
+func RegisterNetwork(network *Network) error {
+	return networks.register(network)
+}
+
+// LookupNetwork returns the network with the given name of the given chain.
+func LookupNetwork(chain ChainCode, name string) (*Network, error) {
+	return networks.lookup(chain, name)
+}
+
+// NetworkNames returns the sorted names of the networks registered for the
+// given chain.
+func NetworkNames(chain ChainCode) []string {
+	return networks.names(chain)
+}
+
+// NetParams applies the parameters of the network to the given bitcoin typed
+// parameters and returns the result.
+func (n *Network) NetParams(params BitcoinNetParams) BitcoinNetParams {
+	if n.LitecoinParams != nil {
+		ApplyLitecoinParams(&params, n.LitecoinParams)
+		return params
+	}
+
+	return *n.BitcoinParams
+}
+
+// builtinNetworks are the networks lnd supports out of the box.
+var builtinNetworks = []*Network{
+	{
+		Name:          "mainnet",
+		Chain:         BitcoinChain,
+		BitcoinParams: &BitcoinMainNetParams,
+		DNSSeeds:      ChainDNSSeeds[BitcoinMainnetGenesis],
+	},
+	{
+		Name:          "testnet",
+		Chain:         BitcoinChain,
+		BitcoinParams: &BitcoinTestNetParams,
+		DNSSeeds:      ChainDNSSeeds[BitcoinTestnetGenesis],
+	},
+	{
+		Name:          "testnet4",
+		Chain:         BitcoinChain,
+		BitcoinParams: &BitcoinTestNet4Params,
+		BitcoindOnly:  true,
+	},
+	{
+		Name:            "regtest",
+		Chain:           BitcoinChain,
+		BitcoinParams:   &BitcoinRegTestNetParams,
+		FallbackRPCPort: 18443,
+		DevNetwork:      true,
+		StaticFees:      true,
+	},
+	{
+		Name:          "simnet",
+		Chain:         BitcoinChain,
+		BitcoinParams: &BitcoinSimNetParams,
+		DevNetwork:    true,
+		StaticFees:    true,
+		BtcdOnly:      true,
+	},
+	{
+		Name:            "signet",
+		Chain:           BitcoinChain,
+		BitcoinParams:   &BitcoinSigNetParams,
+		DNSSeeds:        ChainDNSSeeds[BitcoinSignetGenesis],
+		FallbackRPCPort: 38332,
+		DevNetwork:      true,
+	},
+	{
+		Name:           "mainnet",
+		Chain:          LitecoinChain,
+		LitecoinParams: &LitecoinMainNetParams,
+		DNSSeeds:       ChainDNSSeeds[LitecoinMainnetGenesis],
+	},
+	{
+		Name:           "testnet",
+		Chain:          LitecoinChain,
+		LitecoinParams: &LitecoinTestNetParams,
+	},
+	{
+		Name:            "regtest",
+		Chain:           LitecoinChain,
+		LitecoinParams:  &LitecoinRegTestNetParams,
+		FallbackRPCPort: 19443,
+		DevNetwork:      true,
+		StaticFees:      true,
+	},
+	{
+		Name:           "simnet",
+		Chain:          LitecoinChain,
+		LitecoinParams: &LitecoinSimNetParams,
+		DevNetwork:     true,
+		StaticFees:     true,
+		BtcdOnly:       true,
+	},
+}
+
+func init() {
+	for _, network := range builtinNetworks {
+		if err := RegisterNetwork(network); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package chainreg
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuiltinNetworks asserts that the built-in networks are registered for
+// both chains together with their DNS seeds.
+func TestBuiltinNetworks(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{
+		"mainnet", "regtest", "signet", "simnet", "testnet", "testnet4",
+	}, NetworkNames(BitcoinChain))
+
+	network, err := LookupNetwork(LitecoinChain, "testnet")
+	require.NoError(t, err)
+	require.Equal(t, &LitecoinTestNetParams, network.LitecoinParams)
+
+	_, err = LookupNetwork(LitecoinChain, "signet")
+	require.Error(t, err)
+
+	network, err = LookupNetwork(BitcoinChain, "mainnet")
+	require.NoError(t, err)
+	require.Equal(
+		t, ChainDNSSeeds[BitcoinMainnetGenesis], network.DNSSeeds,
+	)
+
+	// Testnet4 is a network of its own, which is treated as a testnet.
+	network, err = LookupNetwork(BitcoinChain, "testnet4")
+	require.NoError(t, err)
+	require.True(t, IsTestnet(network.BitcoinParams))
+	require.NotEqual(
+		t, BitcoinTestNetParams.Net, network.BitcoinParams.Net,
+	)
+	require.True(t, network.BitcoindOnly)
+}
+
+// TestTestNet4Genesis asserts that the hash of the testnet4 genesis block we
+// construct matches the known genesis hash of the network.
+func TestTestNet4Genesis(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(
+		t, testNet4GenesisMerkleRoot,
+		testNet4GenesisCoinbaseTx.TxHash(),
+	)
+	require.Equal(
+		t, "00000000da84f2bafbbc53dee25a72ae507ff4914b867c565be350b0da"+
+			"8bf043", testNet4GenesisBlock.BlockHash().String(),
+	)
+	require.Equal(
+		t, testNet4GenesisHash, testNet4GenesisBlock.BlockHash(),
+	)
+}
+
+// TestRegisterNetwork asserts that networks can be registered and looked up
+// by name, and that invalid or duplicate networks are rejected.
+func TestRegisterNetwork(t *testing.T) {
+	t.Parallel()
+
+	registry := newNetworkRegistry()
+
+	// A signet with a custom challenge can be added as a network of its
+	// own.
+	challenge := []byte{0x51}
+	params := chaincfg.CustomSignetParams(challenge, nil)
+	customSignet := &Network{
+		Name:  "customsignet",
+		Chain: BitcoinChain,
+		BitcoinParams: &BitcoinNetParams{
+			Params:   &params,
+			RPCPort:  "38332",
+			CoinType: keychain.CoinTypeTestnet,
+		},
+		DevNetwork: true,
+	}
+	require.NoError(t, registry.register(customSignet))
+	require.Equal(
+		t, []string{"customsignet"}, registry.names(BitcoinChain),
+	)
+
+	network, err := registry.lookup(BitcoinChain, "customsignet")
+	require.NoError(t, err)
+	require.Equal(t, customSignet, network)
+	require.Equal(
+		t, &params, network.NetParams(BitcoinTestNetParams).Params,
+	)
+
+	// The network is only known to the registry it was added to.
+	_, err = LookupNetwork(BitcoinChain, "customsignet")
+	require.Error(t, err)
+
+	// Registering the same network twice fails.
+	require.Error(t, registry.register(customSignet))
+
+	// A network must have parameters matching its chain.
+	require.Error(t, registry.register(&Network{
+		Name:          "testnet5",
+		Chain:         LitecoinChain,
+		BitcoinParams: &BitcoinTestNetParams,
+	}))
+	require.Error(t, registry.register(&Network{
+		Name:  "testnet5",
+		Chain: BitcoinChain,
+	}))
+
+	// The name of a network must be usable as a directory name.
+	for _, name := range []string{"", "..", "a/b", "TestNet5"} {
+		require.Error(t, registry.register(&Network{
+			Name:          name,
+			Chain:         BitcoinChain,
+			BitcoinParams: &BitcoinTestNetParams,
+		}))
+	}
+}
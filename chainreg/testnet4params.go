@@ -0,0 +1,126 @@
+package chainreg
+
+import (
+	"time"
+
+	bitcoinCfg "github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	bitcoinWire "github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+// bitcoinTestNet4 is the message start of the 4th version of the bitcoin
+// test network, which isn't known to btcd yet.
+const bitcoinTestNet4 bitcoinWire.BitcoinNet = 0x283f161c
+
+// testNet4GenesisCoinbaseTx is the coinbase transaction of the genesis block
+// of the 4th version of the bitcoin test network.
+var testNet4GenesisCoinbaseTx = bitcoinWire.MsgTx{
+	Version: 1,
+	TxIn: []*bitcoinWire.TxIn{
+		{
+			PreviousOutPoint: bitcoinWire.OutPoint{
+				Hash:  chainhash.Hash{},
+				Index: 0xffffffff,
+			},
+			SignatureScript: append(
+				[]byte{
+					0x04, 0xff, 0xff, 0x00, 0x1d, 0x01,
+					0x04, 0x4c, 0x4c,
+				},
+				"03/May/2024 00000000000000000000"+
+					"1ebd58c244970b3aa9d783bb001011fb"+
+					"e8ea8e98e00e"...,
+			),
+			Sequence: 0xffffffff,
+		},
+	},
+	TxOut: []*bitcoinWire.TxOut{
+		{
+			Value: 0x12a05f200,
+			PkScript: append(
+				append([]byte{0x21}, make([]byte, 33)...),
+				0xac,
+			),
+		},
+	},
+	LockTime: 0,
+}
+
+// testNet4GenesisHash is the hash of the genesis block of the 4th version of
+// the bitcoin test network.
+var testNet4GenesisHash = chainhash.Hash{
+	0x43, 0xf0, 0x8b, 0xda, 0xb0, 0x50, 0xe3, 0x5b,
+	0x56, 0x7c, 0x86, 0x4b, 0x91, 0xf4, 0x7f, 0x50,
+	0xae, 0x72, 0x5a, 0xe2, 0xde, 0x53, 0xbc, 0xfb,
+	0xba, 0xf2, 0x84, 0xda, 0x00, 0x00, 0x00, 0x00,
+}
+
+// testNet4GenesisMerkleRoot is the merkle root of the genesis block of the
+// 4th version of the bitcoin test network.
+var testNet4GenesisMerkleRoot = chainhash.Hash{
+	0x4e, 0x7b, 0x2b, 0x91, 0x28, 0xfe, 0x02, 0x91,
+	0xdb, 0x06, 0x93, 0xaf, 0x2a, 0xe4, 0x18, 0xb7,
+	0x67, 0xe6, 0x57, 0xcd, 0x40, 0x7e, 0x80, 0xcb,
+	0x14, 0x34, 0x22, 0x1e, 0xae, 0xa7, 0xa0, 0x7a,
+}
+
+// testNet4GenesisBlock is the genesis block of the 4th version of the bitcoin
+// test network.
+var testNet4GenesisBlock = bitcoinWire.MsgBlock{
+	Header: bitcoinWire.BlockHeader{
+		Version:    1,
+		PrevBlock:  chainhash.Hash{},
+		MerkleRoot: testNet4GenesisMerkleRoot,
+		Timestamp:  time.Unix(1714777860, 0),
+		Bits:       0x1d00ffff,
+		Nonce:      393743547,
+	},
+	Transactions: []*bitcoinWire.MsgTx{&testNet4GenesisCoinbaseTx},
+}
+
+// testNet4Params returns the chain parameters of the 4th version of the
+// bitcoin test network. They are based on the ones of testnet3, which is only
+// correct for the parameters lnd itself uses. BIP94 changes the difficulty
+// retarget of testnet4 to start at the first block of the period and adds the
+// timewarp rule, which btcd doesn't implement. As btcd and neutrino would
+// therefore reject valid testnet4 headers, the network is only supported with
+// the bitcoind backend, which validates the chain for us.
+func testNet4Params() bitcoinCfg.Params {
+	params := bitcoinCfg.TestNet3Params
+	params.Name = "testnet4"
+	params.Net = bitcoinTestNet4
+	params.DefaultPort = "48333"
+	params.DNSSeeds = []bitcoinCfg.DNSSeed{
+		{
+			Host:         "seed.testnet4.bitcoin.sprovoost.nl",
+			HasFiltering: true,
+		},
+		{
+			Host:         "seed.testnet4.wiz.biz",
+			HasFiltering: true,
+		},
+	}
+	params.GenesisBlock = &testNet4GenesisBlock
+	params.GenesisHash = &testNet4GenesisHash
+	params.Checkpoints = nil
+
+	// All soft forks up to segwit are active from the first block on.
+	params.BIP0034Height = 1
+	params.BIP0065Height = 1
+	params.BIP0066Height = 1
+
+	return params
+}
+
+// bitcoinTestNet4Params holds the chain parameters of the 4th version of the
+// bitcoin test network.
+var bitcoinTestNet4Params = testNet4Params()
+
+// BitcoinTestNet4Params contains parameters specific to the 4th version of
+// the bitcoin test network.
+var BitcoinTestNet4Params = BitcoinNetParams{
+	Params:   &bitcoinTestNet4Params,
+	RPCPort:  "48334",
+	CoinType: keychain.CoinTypeTestnet,
+}
@@ -1,6 +1,7 @@
 package chanacceptor
 
 import (
+	"sort"
 	"sync"
 	"sync/atomic"
 )
@@ -54,26 +55,90 @@ func (c *ChainedAcceptor) numAcceptors() int {
 	return len(c.acceptors)
 }
 
-// Accept evaluates the results of all ChannelAcceptors in the acceptors map
-// and returns the conjunction of all these predicates.
+// Accept evaluates the results of the ChannelAcceptors in the acceptors map
+// and returns the conjunction of these predicates. Only the acceptors with the
+// lowest priority value decide, unless none of them is able to, in which case
+// the acceptors of the next priority decide. Acceptors that don't implement
+// FailoverAcceptor have the highest priority.
 //
 // NOTE: Part of the ChannelAcceptor interface.
 func (c *ChainedAcceptor) Accept(req *ChannelAcceptRequest) *ChannelAcceptResponse {
 	c.acceptorsMtx.RLock()
 	defer c.acceptorsMtx.RUnlock()
 
-	var finalResp ChannelAcceptResponse
-
+	// Group our acceptors by their priority.
+	groups := make(map[uint32][]ChannelAcceptor)
+	var priorities []uint32
 	for _, acceptor := range c.acceptors {
+		var priority uint32
+		if failover, ok := acceptor.(FailoverAcceptor); ok {
+			priority = failover.Priority()
+		}
+
+		if _, ok := groups[priority]; !ok {
+			priorities = append(priorities, priority)
+		}
+		groups[priority] = append(groups[priority], acceptor)
+	}
+	sort.Slice(priorities, func(i, j int) bool {
+		return priorities[i] < priorities[j]
+	})
+
+	// Without any acceptors, we accept the channel.
+	if len(priorities) == 0 {
+		return &ChannelAcceptResponse{}
+	}
+
+	for _, priority := range priorities {
+		resp, ok := acceptGroup(req, groups[priority])
+		if ok {
+			return resp
+		}
+
+		log.Warnf("No acceptor of priority %v available for: %x, "+
+			"failing over", priority, req.OpenChanMsg.PendingChannelID)
+	}
+
+	// None of our acceptors was able to decide, so we reject the channel.
+	return NewChannelAcceptResponse(
+		false, errChannelRejected, nil, 0, 0, 0, 0, 0, 0, false,
+	)
+}
+
+// acceptGroup returns the conjunction of the results of the acceptors that
+// are able to decide on the channel, and false if none of them is.
+func acceptGroup(req *ChannelAcceptRequest,
+	acceptors []ChannelAcceptor) (*ChannelAcceptResponse, bool) {
+
+	var (
+		finalResp ChannelAcceptResponse
+		decided   bool
+	)
+
+	for _, acceptor := range acceptors {
 		// Call our acceptor to determine whether we want to accept this
-		// channel.
-		acceptorResponse := acceptor.Accept(req)
+		// channel. An acceptor that is unable to decide is skipped.
+		var acceptorResponse *ChannelAcceptResponse
+		if failover, ok := acceptor.(FailoverAcceptor); ok {
+			var err error
+			acceptorResponse, err = failover.TryAccept(req)
+			if err != nil {
+				log.Debugf("Acceptor unable to decide on: "+
+					"%x: %v", req.OpenChanMsg.PendingChannelID,
+					err)
+
+				continue
+			}
+		} else {
+			acceptorResponse = acceptor.Accept(req)
+		}
+		decided = true
 
 		// If we should reject the channel, we can just exit early. This
 		// has the effect of returning the error belonging to our first
 		// failed acceptor.
 		if acceptorResponse.RejectChannel() {
-			return acceptorResponse
+			return acceptorResponse, true
 		}
 
 		// If we have accepted the channel, we need to set the other
@@ -93,13 +158,13 @@ func (c *ChainedAcceptor) Accept(req *ChannelAcceptRequest) *ChannelAcceptRespon
 			return NewChannelAcceptResponse(
 				false, errChannelRejected, nil, 0, 0,
 				0, 0, 0, 0, false,
-			)
+			), true
 		}
 	}
 
 	// If we have gone through all of our acceptors with no objections, we
 	// can return an acceptor with a nil error.
-	return &finalResp
+	return &finalResp, decided
 }
 
 // A compile-time constraint to ensure ChainedAcceptor implements the
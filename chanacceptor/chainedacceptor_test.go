@@ -0,0 +1,101 @@
+package chanacceptor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// mockFailoverAcceptor is a FailoverAcceptor that either returns a fixed
+// response or is unable to decide.
+type mockFailoverAcceptor struct {
+	priority    uint32
+	reject      bool
+	unavailable bool
+	calls       int
+}
+
+func (m *mockFailoverAcceptor) Accept(
+	req *ChannelAcceptRequest) *ChannelAcceptResponse {
+
+	resp, err := m.TryAccept(req)
+	if err != nil {
+		return NewChannelAcceptResponse(
+			false, errChannelRejected, nil, 0, 0, 0, 0, 0, 0, false,
+		)
+	}
+
+	return resp
+}
+
+func (m *mockFailoverAcceptor) TryAccept(
+	req *ChannelAcceptRequest) (*ChannelAcceptResponse, error) {
+
+	m.calls++
+
+	if m.unavailable {
+		return nil, errors.New("unavailable")
+	}
+
+	if m.reject {
+		return NewChannelAcceptResponse(
+			false, errChannelRejected, nil, 0, 0, 0, 0, 0, 0, false,
+		), nil
+	}
+
+	return &ChannelAcceptResponse{}, nil
+}
+
+func (m *mockFailoverAcceptor) Priority() uint32 {
+	return m.priority
+}
+
+// TestChainedAcceptorFailover tests that the ChainedAcceptor only asks the
+// acceptors of the next priority if none of the acceptors with a lower
+// priority value is able to decide.
+func TestChainedAcceptorFailover(t *testing.T) {
+	t.Parallel()
+
+	req := &ChannelAcceptRequest{
+		OpenChanMsg: &lnwire.OpenChannel{},
+	}
+
+	chained := NewChainedAcceptor()
+
+	// Without any acceptors, the channel is accepted.
+	require.False(t, chained.Accept(req).RejectChannel())
+
+	primary := &mockFailoverAcceptor{priority: 0}
+	standby := &mockFailoverAcceptor{priority: 1, reject: true}
+	chained.AddAcceptor(primary)
+	chained.AddAcceptor(standby)
+
+	// The primary decides, the standby isn't asked.
+	require.False(t, chained.Accept(req).RejectChannel())
+	require.Equal(t, 1, primary.calls)
+	require.Equal(t, 0, standby.calls)
+
+	// Once the primary is unavailable, the standby decides.
+	primary.unavailable = true
+	require.True(t, chained.Accept(req).RejectChannel())
+	require.Equal(t, 2, primary.calls)
+	require.Equal(t, 1, standby.calls)
+
+	// A second primary that is available decides on its own, and the
+	// unavailable primary doesn't veto it.
+	secondPrimary := &mockFailoverAcceptor{priority: 0}
+	secondID := chained.AddAcceptor(secondPrimary)
+	require.False(t, chained.Accept(req).RejectChannel())
+	require.Equal(t, 1, standby.calls)
+
+	// If no acceptor is able to decide, the channel is rejected.
+	chained.RemoveAcceptor(secondID)
+	standby.unavailable = true
+	require.True(t, chained.Accept(req).RejectChannel())
+
+	// An acceptor without a priority always decides first.
+	chained.AddAcceptor(&dummyAcceptor{})
+	require.False(t, chained.Accept(req).RejectChannel())
+}
@@ -41,6 +41,11 @@ type ChannelAcceptResponse struct {
 	// shutdown address.
 	UpfrontShutdown lnwire.DeliveryAddress
 
+	// RequireUpfrontShutdown indicates that the channel must be rejected
+	// if an upfront shutdown script can't be set for it, because the
+	// remote peer doesn't support the option.
+	RequireUpfrontShutdown bool
+
 	// CSVDelay is the csv delay we require for the remote peer.
 	CSVDelay uint16
 
@@ -119,6 +124,21 @@ type ChannelAcceptor interface {
 	Accept(req *ChannelAcceptRequest) *ChannelAcceptResponse
 }
 
+// FailoverAcceptor is a ChannelAcceptor with a priority, which may be unable to
+// decide on a channel. Acceptors of a lower priority value are asked first, and
+// the acceptors of the next priority only decide if none of them is able to.
+type FailoverAcceptor interface {
+	// Embed the ChannelAcceptor.
+	ChannelAcceptor
+
+	// Priority returns the priority of the acceptor.
+	Priority() uint32
+
+	// TryAccept is like Accept, but returns an error instead of a
+	// rejection if the acceptor is unable to decide on the channel.
+	TryAccept(req *ChannelAcceptRequest) (*ChannelAcceptResponse, error)
+}
+
 // MultiplexAcceptor is an interface that abstracts the ability of a
 // ChannelAcceptor to contain sub-ChannelAcceptors.
 type MultiplexAcceptor interface {
@@ -166,5 +166,9 @@ func mergeResponse(current,
 		return current, err
 	}
 
+	current.RequireUpfrontShutdown = mergeBool(
+		current.RequireUpfrontShutdown, newValue.RequireUpfrontShutdown,
+	)
+
 	return current, nil
 }
@@ -179,6 +179,22 @@ func TestMergeResponse(t *testing.T) {
 			},
 			err: errZeroConf,
 		},
+		{
+			// Test that an upfront shutdown script is required if
+			// any of the responses requires it.
+			name: "require upfront shutdown",
+			current: ChannelAcceptResponse{
+				UpfrontShutdown: addr1,
+			},
+			new: ChannelAcceptResponse{
+				RequireUpfrontShutdown: true,
+			},
+			merged: ChannelAcceptResponse{
+				UpfrontShutdown:        addr1,
+				RequireUpfrontShutdown: true,
+			},
+			err: nil,
+		},
 	}
 
 	for _, test := range tests {
@@ -18,6 +18,10 @@ import (
 var (
 	errShuttingDown = errors.New("server shutting down")
 
+	// errAcceptorUnavailable is returned when the rpc acceptor can't decide
+	// on a channel because its client disconnected or is unhealthy.
+	errAcceptorUnavailable = errors.New("channel acceptor unavailable")
+
 	// errCustomLength is returned when our custom error's length exceeds
 	// our maximum.
 	errCustomLength = fmt.Errorf("custom error message exceeds length "+
@@ -78,9 +82,26 @@ type RPCAcceptor struct {
 	// params are our current chain params.
 	params *chaincfg.Params
 
+	// priority is the priority of the acceptor, as registered by the rpc
+	// client.
+	priority uint32
+
+	// healthTimeout is the amount of time the rpc client has to answer a
+	// request before it is considered unhealthy, as registered by the rpc
+	// client. Zero disables the health check.
+	healthTimeout time.Duration
+
+	// registrationMtx guards priority and healthTimeout.
+	registrationMtx sync.Mutex
+
 	// done is closed when the rpc client terminates.
 	done chan struct{}
 
+	// unhealthy is closed when the rpc client didn't answer a request
+	// within its health timeout, to terminate its stream.
+	unhealthy     chan struct{}
+	unhealthyOnce sync.Once
+
 	// quit is closed when lnd is shutting down.
 	quit chan struct{}
 
@@ -94,6 +115,25 @@ type RPCAcceptor struct {
 //
 // NOTE: Part of the ChannelAcceptor interface.
 func (r *RPCAcceptor) Accept(req *ChannelAcceptRequest) *ChannelAcceptResponse {
+	resp, err := r.TryAccept(req)
+	if err != nil {
+		return NewChannelAcceptResponse(
+			false, errChannelRejected, nil, 0, 0, 0, 0, 0, 0, false,
+		)
+	}
+
+	return resp
+}
+
+// TryAccept is like Accept, but returns errAcceptorUnavailable instead of
+// rejecting the channel if the RPC client disconnects or doesn't answer within
+// its health timeout. An RPC client that exceeds its health timeout is
+// considered unhealthy and its stream is terminated.
+//
+// NOTE: Part of the FailoverAcceptor interface.
+func (r *RPCAcceptor) TryAccept(req *ChannelAcceptRequest) (
+	*ChannelAcceptResponse, error) {
+
 	respChan := make(chan *ChannelAcceptResponse, 1)
 
 	newRequest := &chanAcceptInfo{
@@ -104,6 +144,17 @@ func (r *RPCAcceptor) Accept(req *ChannelAcceptRequest) *ChannelAcceptResponse {
 	// timeout is the time after which ChannelAcceptRequests expire.
 	timeout := time.After(r.timeout)
 
+	// healthTimeout is the time after which we consider the client
+	// unhealthy, if it registered one.
+	r.registrationMtx.Lock()
+	healthTimeoutDuration := r.healthTimeout
+	r.registrationMtx.Unlock()
+
+	var healthTimeout <-chan time.Time
+	if healthTimeoutDuration != 0 {
+		healthTimeout = time.After(healthTimeoutDuration)
+	}
+
 	// Create a rejection response which we can use for the cases where we
 	// reject the channel.
 	rejectChannel := NewChannelAcceptResponse(
@@ -117,47 +168,77 @@ func (r *RPCAcceptor) Accept(req *ChannelAcceptRequest) *ChannelAcceptResponse {
 	case <-timeout:
 		log.Errorf("RPCAcceptor returned false - reached timeout of %v",
 			r.timeout)
-		return rejectChannel
+		return rejectChannel, nil
+
+	case <-healthTimeout:
+		return nil, r.markUnhealthy(healthTimeoutDuration)
 
 	case <-r.done:
-		return rejectChannel
+		return nil, errAcceptorUnavailable
 
 	case <-r.quit:
-		return rejectChannel
+		return nil, errShuttingDown
 	}
 
 	// Receive the response and return it. If no response has been received
 	// in AcceptorTimeout, then return false.
 	select {
 	case resp := <-respChan:
-		return resp
+		return resp, nil
 
 	case <-timeout:
 		log.Errorf("RPCAcceptor returned false - reached timeout of %v",
 			r.timeout)
-		return rejectChannel
+		return rejectChannel, nil
+
+	case <-healthTimeout:
+		return nil, r.markUnhealthy(healthTimeoutDuration)
 
 	case <-r.done:
-		return rejectChannel
+		return nil, errAcceptorUnavailable
 
 	case <-r.quit:
-		return rejectChannel
+		return nil, errShuttingDown
 	}
 }
 
+// Priority returns the priority the RPC client registered.
+//
+// NOTE: Part of the FailoverAcceptor interface.
+func (r *RPCAcceptor) Priority() uint32 {
+	r.registrationMtx.Lock()
+	defer r.registrationMtx.Unlock()
+
+	return r.priority
+}
+
+// markUnhealthy terminates the stream of the RPC client because it didn't
+// answer within its health timeout.
+func (r *RPCAcceptor) markUnhealthy(healthTimeout time.Duration) error {
+	r.unhealthyOnce.Do(func() {
+		log.Warnf("RPCAcceptor didn't answer within health timeout "+
+			"of %v, disconnecting", healthTimeout)
+
+		close(r.unhealthy)
+	})
+
+	return errAcceptorUnavailable
+}
+
 // NewRPCAcceptor creates and returns an instance of the RPCAcceptor.
 func NewRPCAcceptor(receive func() (*lnrpc.ChannelAcceptResponse, error),
 	send func(*lnrpc.ChannelAcceptRequest) error, timeout time.Duration,
 	params *chaincfg.Params, quit chan struct{}) *RPCAcceptor {
 
 	return &RPCAcceptor{
-		receive:  receive,
-		send:     send,
-		requests: make(chan *chanAcceptInfo),
-		timeout:  timeout,
-		params:   params,
-		done:     make(chan struct{}),
-		quit:     quit,
+		receive:   receive,
+		send:      send,
+		requests:  make(chan *chanAcceptInfo),
+		timeout:   timeout,
+		params:    params,
+		done:      make(chan struct{}),
+		unhealthy: make(chan struct{}),
+		quit:      quit,
 	}
 }
 
@@ -202,6 +283,19 @@ func (r *RPCAcceptor) receiveResponses(errChan chan error,
 			return
 		}
 
+		// A registration updates the priority and health timeout of
+		// the acceptor rather than answering a request.
+		if resp.Registration != nil {
+			r.registrationMtx.Lock()
+			r.priority = resp.Registration.Priority
+			r.healthTimeout = time.Duration(
+				resp.Registration.HealthTimeoutMs,
+			) * time.Millisecond
+			r.registrationMtx.Unlock()
+
+			continue
+		}
+
 		var pendingID [32]byte
 		copy(pendingID[:], resp.PendingChanId)
 
@@ -217,6 +311,8 @@ func (r *RPCAcceptor) receiveResponses(errChan chan error,
 			MinHtlcIn:       resp.MinHtlcIn,
 			MinAcceptDepth:  resp.MinAcceptDepth,
 			ZeroConf:        resp.ZeroConf,
+
+			RequireUpfrontShutdown: resp.RequireUpfrontShutdown,
 		}
 
 		// We have received a decision for one of our channel
@@ -410,7 +506,7 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 				log.Errorf("Invalid acceptor response: %v", err)
 			}
 
-			requestInfo.response <- NewChannelAcceptResponse(
+			acceptResp := NewChannelAcceptResponse(
 				accept, acceptErr, shutdown,
 				uint16(resp.CsvDelay),
 				uint16(resp.MaxHtlcCount),
@@ -420,6 +516,10 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 				lnwire.MilliSatoshi(resp.MinHtlcIn),
 				resp.ZeroConf,
 			)
+			acceptResp.RequireUpfrontShutdown =
+				resp.RequireUpfrontShutdown
+
+			requestInfo.response <- acceptResp
 
 			// Delete the channel from the acceptRequests map.
 			delete(acceptRequests, pendingID)
@@ -429,6 +529,11 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 			log.Errorf("Received an error: %v, shutting down", err)
 			return err
 
+		// Exit if our acceptor didn't answer in time, so that its
+		// client reconnects.
+		case <-r.unhealthy:
+			return errAcceptorUnavailable
+
 		// Exit if we are shutting down.
 		case <-r.quit:
 			return errShuttingDown
@@ -508,6 +613,6 @@ func (r *RPCAcceptor) validateAcceptorResponse(dustLimit btcutil.Amount,
 	}
 }
 
-// A compile-time constraint to ensure RPCAcceptor implements the ChannelAcceptor
-// interface.
-var _ ChannelAcceptor = (*RPCAcceptor)(nil)
+// A compile-time constraint to ensure RPCAcceptor implements the
+// FailoverAcceptor interface.
+var _ FailoverAcceptor = (*RPCAcceptor)(nil)
@@ -0,0 +1,164 @@
+package chanbackup
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+)
+
+// ErrInsecureAuthToken is returned if a bearer token would be sent to the
+// companion device over plain HTTP, which would disclose it to anyone on the
+// path.
+var ErrInsecureAuthToken = errors.New("bearer token requires an https URL")
+
+const (
+	// BackupVersionHeader is the HTTP header that carries the version of
+	// the backup in the body of a request or response.
+	BackupVersionHeader = "Backup-Version"
+
+	// BackupPrevVersionHeader is the HTTP header of a PUT request that
+	// carries the version of the backup that is replaced.
+	BackupPrevVersionHeader = "Backup-Prev-Version"
+)
+
+// HTTPSyncTarget is a SyncTarget that synchronizes the channel backup to a
+// companion device over HTTP. The device is expected to implement the
+// following protocol on a single URL:
+//
+//   - GET returns the backup it holds in the body, along with its version in
+//     the Backup-Version header, or 404 Not Found if it holds none.
+//   - PUT stores the backup in the body with the version in the
+//     Backup-Version header if the version of the backup it holds matches
+//     the Backup-Prev-Version header, a version of zero denoting that it
+//     holds none. Otherwise, the backup is rejected with 409 Conflict.
+type HTTPSyncTarget struct {
+	url       string
+	authToken string
+	client    *http.Client
+}
+
+// A compile-time check to ensure HTTPSyncTarget implements the SyncTarget
+// interface.
+var _ SyncTarget = (*HTTPSyncTarget)(nil)
+
+// NewHTTPSyncTarget creates a new HTTPSyncTarget for the companion device at
+// the given URL. If authToken is set, it is sent as bearer token with every
+// request, which is only allowed for https URLs.
+func NewHTTPSyncTarget(url, authToken string,
+	client *http.Client) *HTTPSyncTarget {
+
+	return &HTTPSyncTarget{
+		url:       url,
+		authToken: authToken,
+		client:    client,
+	}
+}
+
+// FetchBackup returns the backup the companion device currently holds, or
+// nil if it holds none.
+//
+// NOTE: This is part of the SyncTarget interface.
+func (h *HTTPSyncTarget) FetchBackup() (*VersionedBackup, error) {
+	req, err := h.newRequest(http.MethodGet, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected status fetching backup: %v",
+			resp.Status)
+	}
+
+	version, err := strconv.ParseUint(
+		resp.Header.Get(BackupVersionHeader), 10, 64,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup version: %v", err)
+	}
+
+	backup, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VersionedBackup{
+		Version: version,
+		Backup:  backup,
+	}, nil
+}
+
+// PushBackup replaces the backup with the given previous version on the
+// companion device with the new backup.
+//
+// NOTE: This is part of the SyncTarget interface.
+func (h *HTTPSyncTarget) PushBackup(prevVersion uint64,
+	backup *VersionedBackup) error {
+
+	req, err := h.newRequest(http.MethodPut, backup.Backup)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(
+		BackupVersionHeader, strconv.FormatUint(backup.Version, 10),
+	)
+	req.Header.Set(
+		BackupPrevVersionHeader, strconv.FormatUint(prevVersion, 10),
+	)
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return nil
+
+	case http.StatusConflict:
+		return ErrSyncConflict
+
+	default:
+		return fmt.Errorf("unexpected status pushing backup: %v",
+			resp.Status)
+	}
+}
+
+// newRequest creates a new request to the companion device.
+func (h *HTTPSyncTarget) newRequest(method string,
+	body []byte) (*http.Request, error) {
+
+	req, err := http.NewRequest(method, h.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+
+	if h.authToken != "" {
+		if req.URL.Scheme != "https" {
+			return nil, ErrInsecureAuthToken
+		}
+
+		req.Header.Set("Authorization", "Bearer "+h.authToken)
+	}
+
+	return req, nil
+}
@@ -0,0 +1,269 @@
+package chanbackup
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/ticker"
+)
+
+var (
+	// ErrSyncConflict is returned by a SyncTarget if the version of the
+	// backup it holds doesn't match the version the new backup replaces.
+	ErrSyncConflict = errors.New("backup version conflict")
+
+	// ErrForeignBackup is returned if the sync target holds a backup that
+	// can't be decrypted with our keys. Such a backup belongs to another
+	// node and is never overwritten.
+	ErrForeignBackup = errors.New("sync target holds a backup of " +
+		"another node")
+)
+
+// VersionedBackup is a packed multi backup along with its version. The
+// version is incremented every time a new backup is pushed to a sync target.
+type VersionedBackup struct {
+	// Version is the version of the backup.
+	Version uint64
+
+	// Backup is the packed multi backup.
+	Backup PackedMulti
+}
+
+// SyncTarget is a companion device that holds a copy of the channel backup,
+// for example a phone.
+type SyncTarget interface {
+	// FetchBackup returns the backup the target currently holds, or nil
+	// if it holds none.
+	FetchBackup() (*VersionedBackup, error)
+
+	// PushBackup replaces the backup with the given previous version with
+	// the new backup. If the target holds a backup with a different
+	// version, ErrSyncConflict is returned. A previous version of zero
+	// denotes that the target is expected to hold no backup.
+	PushBackup(prevVersion uint64, backup *VersionedBackup) error
+}
+
+// SyncSwapper is a Swapper that synchronizes every new backup to a sync
+// target after updating it with the wrapped Swapper. The synchronization runs
+// in the background, so a slow target doesn't hold up the updates. Failed
+// synchronizations are retried until the target holds the latest backup.
+//
+// As the backup is created from the live channel state, it is always treated
+// as the freshest one. If the target holds a backup with an unexpected
+// version, for example because it was written by an earlier run of the node,
+// the backup is replaced as long as it can be decrypted with our keys.
+type SyncSwapper struct {
+	started sync.Once
+	stopped sync.Once
+
+	Swapper
+
+	target SyncTarget
+
+	// keyRing is used to check that a conflicting backup on the target
+	// is one of ours before replacing it.
+	keyRing keychain.KeyRing
+
+	// retryTicker triggers another synchronization attempt after a
+	// failed one.
+	retryTicker ticker.Ticker
+
+	// syncSignal is signaled whenever a new backup is pending.
+	syncSignal chan struct{}
+
+	// version is the version of the backup the target is known to hold.
+	// It is only accessed by the syncer goroutine.
+	version uint64
+
+	// mtx guards the fields below. It is never held while communicating
+	// with the sync target.
+	mtx sync.Mutex
+
+	// pending is the latest backup that wasn't synchronized yet. It is
+	// nil if the target holds the latest backup.
+	pending PackedMulti
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// A compile-time check to ensure SyncSwapper implements the Swapper
+// interface.
+var _ Swapper = (*SyncSwapper)(nil)
+
+// NewSyncSwapper creates a new SyncSwapper that synchronizes the backups of
+// the given Swapper to the sync target.
+func NewSyncSwapper(swapper Swapper, target SyncTarget,
+	keyRing keychain.KeyRing, retryTicker ticker.Ticker) *SyncSwapper {
+
+	return &SyncSwapper{
+		Swapper:     swapper,
+		target:      target,
+		keyRing:     keyRing,
+		retryTicker: retryTicker,
+		syncSignal:  make(chan struct{}, 1),
+		quit:        make(chan struct{}),
+	}
+}
+
+// Start starts retrying failed synchronizations in the background.
+func (s *SyncSwapper) Start() error {
+	s.started.Do(func() {
+		log.Infof("chanbackup.SyncSwapper starting")
+
+		s.retryTicker.Resume()
+
+		s.wg.Add(1)
+		go s.syncer()
+	})
+
+	return nil
+}
+
+// Stop stops the SyncSwapper.
+func (s *SyncSwapper) Stop() error {
+	s.stopped.Do(func() {
+		log.Infof("Stopping chanbackup.SyncSwapper")
+
+		close(s.quit)
+		s.wg.Wait()
+
+		s.retryTicker.Stop()
+	})
+
+	return nil
+}
+
+// UpdateAndSwap updates the backup with the wrapped Swapper and then queues
+// it for the synchronization to the sync target. A failed synchronization
+// doesn't fail the update, it is retried later on instead.
+//
+// NOTE: This is part of the Swapper interface.
+func (s *SyncSwapper) UpdateAndSwap(newBackup PackedMulti) error {
+	if err := s.Swapper.UpdateAndSwap(newBackup); err != nil {
+		return err
+	}
+
+	s.mtx.Lock()
+	s.pending = newBackup
+	s.mtx.Unlock()
+
+	// A signal that is still pending already covers the new backup.
+	select {
+	case s.syncSignal <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// syncer synchronizes the pending backup whenever a new one is queued, and
+// retries failed synchronizations every time the retry ticker fires.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *SyncSwapper) syncer() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-s.syncSignal:
+		case <-s.retryTicker.Ticks():
+		case <-s.quit:
+			return
+		}
+
+		if err := s.syncPending(); err != nil {
+			log.Errorf("Unable to synchronize channel backup, "+
+				"will retry: %v", err)
+		}
+	}
+}
+
+// syncPending pushes the pending backup to the sync target, resolving a
+// version conflict if necessary.
+//
+// NOTE: This is only called by the syncer goroutine.
+func (s *SyncSwapper) syncPending() error {
+	s.mtx.Lock()
+	pending := s.pending
+	s.mtx.Unlock()
+
+	if pending == nil {
+		return nil
+	}
+
+	// Resolving a conflict tells us the version the target holds, so a
+	// second attempt only fails if the target was modified concurrently.
+	const maxAttempts = 2
+	for i := 0; i < maxAttempts; i++ {
+		backup := &VersionedBackup{
+			Version: s.version + 1,
+			Backup:  pending,
+		}
+		err := s.target.PushBackup(s.version, backup)
+		switch {
+		case err == nil:
+			log.Infof("Synchronized channel backup version %d",
+				backup.Version)
+
+			s.version = backup.Version
+
+			// A newer backup that was queued in the meantime is
+			// synchronized next.
+			s.mtx.Lock()
+			if bytes.Equal(s.pending, pending) {
+				s.pending = nil
+			}
+			s.mtx.Unlock()
+
+			return nil
+
+		case errors.Is(err, ErrSyncConflict):
+			if err := s.resolveConflict(); err != nil {
+				return err
+			}
+
+		default:
+			return err
+		}
+	}
+
+	return fmt.Errorf("%w: sync target modified concurrently",
+		ErrSyncConflict)
+}
+
+// resolveConflict fetches the backup the sync target holds and adopts its
+// version, so the next push replaces it.
+//
+// NOTE: This is only called by the syncer goroutine.
+func (s *SyncSwapper) resolveConflict() error {
+	remote, err := s.target.FetchBackup()
+	if err != nil {
+		return fmt.Errorf("unable to fetch backup from sync "+
+			"target: %v", err)
+	}
+
+	if remote == nil {
+		s.version = 0
+		return nil
+	}
+
+	// We only replace backups that we can decrypt, a backup created with
+	// another seed belongs to another node and must not be lost.
+	var multi Multi
+	err = multi.UnpackFromReader(bytes.NewReader(remote.Backup), s.keyRing)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrForeignBackup, err)
+	}
+
+	log.Warnf("Sync target holds channel backup version %d with %d "+
+		"channels, replacing it with the current channel state",
+		remote.Version, len(multi.StaticBackups))
+
+	s.version = remote.Version
+
+	return nil
+}
@@ -0,0 +1,236 @@
+package chanbackup
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/stretchr/testify/require"
+)
+
+// mockCompanion is an in-memory companion device that implements the protocol
+// expected by the HTTPSyncTarget.
+type mockCompanion struct {
+	sync.Mutex
+
+	backup  []byte
+	version uint64
+
+	// unavailable makes the companion fail all requests.
+	unavailable bool
+}
+
+func (m *mockCompanion) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.unavailable {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if m.backup == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set(
+			BackupVersionHeader, strconv.FormatUint(m.version, 10),
+		)
+		_, _ = w.Write(m.backup)
+
+	case http.MethodPut:
+		prevVersion, _ := strconv.ParseUint(
+			r.Header.Get(BackupPrevVersionHeader), 10, 64,
+		)
+		if prevVersion != m.version {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		version, _ := strconv.ParseUint(
+			r.Header.Get(BackupVersionHeader), 10, 64,
+		)
+		backup, _ := ioutil.ReadAll(r.Body)
+
+		m.version = version
+		m.backup = backup
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// state returns the backup and version the companion holds.
+func (m *mockCompanion) state() ([]byte, uint64) {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.backup, m.version
+}
+
+// setUnavailable sets whether the companion fails all requests.
+func (m *mockCompanion) setUnavailable(unavailable bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.unavailable = unavailable
+}
+
+// memSwapper is a Swapper that keeps the latest backup in memory.
+type memSwapper struct {
+	backup PackedMulti
+}
+
+func (m *memSwapper) UpdateAndSwap(newBackup PackedMulti) error {
+	m.backup = newBackup
+	return nil
+}
+
+func (m *memSwapper) ExtractMulti(keyRing keychain.KeyRing) (*Multi, error) {
+	return m.backup.Unpack(keyRing)
+}
+
+// newTestPackedMulti packs a multi backup of a random channel.
+func newTestPackedMulti(t *testing.T) PackedMulti {
+	testChannel, err := genRandomOpenChannelShell()
+	require.NoError(t, err)
+
+	multi := Multi{
+		StaticBackups: []Single{NewSingle(testChannel, nil)},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, multi.PackToWriter(&b, &mockKeyRing{}))
+
+	return PackedMulti(b.Bytes())
+}
+
+// TestSyncSwapper tests that the SyncSwapper keeps the backup of a companion
+// device up to date and resolves version conflicts.
+func TestSyncSwapper(t *testing.T) {
+	t.Parallel()
+
+	companion := &mockCompanion{}
+	server := httptest.NewServer(companion)
+	defer server.Close()
+
+	keyRing := &mockKeyRing{}
+	newSyncSwapper := func() (*SyncSwapper, *ticker.Force) {
+		retryTicker := ticker.NewForce(time.Hour)
+		syncSwapper := NewSyncSwapper(
+			&memSwapper{},
+			NewHTTPSyncTarget(server.URL, "", server.Client()),
+			keyRing, retryTicker,
+		)
+		require.NoError(t, syncSwapper.Start())
+		t.Cleanup(func() {
+			require.NoError(t, syncSwapper.Stop())
+		})
+
+		return syncSwapper, retryTicker
+	}
+
+	// assertSynced asserts that the companion eventually holds the given
+	// backup with the given version.
+	assertSynced := func(backup PackedMulti, version uint64) {
+		t.Helper()
+
+		err := wait.NoError(func() error {
+			remoteBackup, remoteVersion := companion.state()
+			if remoteVersion != version ||
+				!bytes.Equal(remoteBackup, backup) {
+
+				return fmt.Errorf("backup not synchronized")
+			}
+
+			return nil
+		}, time.Second*5)
+		require.NoError(t, err)
+	}
+
+	syncSwapper, retryTicker := newSyncSwapper()
+
+	// Every new backup should be pushed to the companion with an
+	// incremented version.
+	for version := uint64(1); version <= 2; version++ {
+		backup := newTestPackedMulti(t)
+		require.NoError(t, syncSwapper.UpdateAndSwap(backup))
+		assertSynced(backup, version)
+	}
+
+	// If the companion is unavailable, the update still succeeds and the
+	// backup is synchronized once the companion is available again.
+	companion.setUnavailable(true)
+	backup := newTestPackedMulti(t)
+	require.NoError(t, syncSwapper.UpdateAndSwap(backup))
+
+	_, remoteVersion := companion.state()
+	require.EqualValues(t, 2, remoteVersion)
+
+	companion.setUnavailable(false)
+	retryTicker.Force <- time.Now()
+	assertSynced(backup, 3)
+
+	// A new instance doesn't know the version the companion holds. The
+	// conflict is resolved by replacing the companion's backup since we
+	// can decrypt it.
+	syncSwapper, _ = newSyncSwapper()
+	backup = newTestPackedMulti(t)
+	require.NoError(t, syncSwapper.UpdateAndSwap(backup))
+	assertSynced(backup, 4)
+
+	// A backup we can't decrypt belongs to another node and must not be
+	// replaced.
+	foreignBackup := []byte("foreign backup")
+	companion.Lock()
+	companion.backup = foreignBackup
+	companion.version = 10
+	companion.Unlock()
+
+	syncSwapper.mtx.Lock()
+	syncSwapper.pending = newTestPackedMulti(t)
+	syncSwapper.mtx.Unlock()
+
+	err := syncSwapper.syncPending()
+	require.ErrorIs(t, err, ErrForeignBackup)
+
+	remoteBackup, remoteVersion := companion.state()
+	require.EqualValues(t, 10, remoteVersion)
+	require.Equal(t, foreignBackup, remoteBackup)
+}
+
+// TestHTTPSyncTargetInsecureAuthToken tests that a bearer token is never sent
+// to the companion device over plain http.
+func TestHTTPSyncTargetInsecureAuthToken(t *testing.T) {
+	t.Parallel()
+
+	companion := &mockCompanion{}
+	server := httptest.NewServer(companion)
+	defer server.Close()
+
+	target := NewHTTPSyncTarget(server.URL, "token", server.Client())
+
+	_, err := target.FetchBackup()
+	require.ErrorIs(t, err, ErrInsecureAuthToken)
+
+	err = target.PushBackup(0, &VersionedBackup{
+		Version: 1,
+		Backup:  newTestPackedMulti(t),
+	})
+	require.ErrorIs(t, err, ErrInsecureAuthToken)
+
+	_, version := companion.state()
+	require.Zero(t, version)
+}
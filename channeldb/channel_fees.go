@@ -0,0 +1,257 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// channelFeesBucket is a top level bucket which holds the on-chain
+	// fees that were paid by us over the lifetime of a channel. It nests
+	// channels by their channel point, which in turn hold the fees that
+	// were paid, keyed by the txid of the transaction and the index of the
+	// input the fee is attributed to. As the fees are keyed this way,
+	// recording the same fee more than once has no effect. The fees
+	// outlive the channel, so that they are still available once the
+	// channel is closed.
+	// [channelFeesBucket]
+	//	[channelBucket]
+	//		txid || inputIndex -> feeType || fee
+	channelFeesBucket = []byte("channel-fees")
+)
+
+// ChannelFeeType indicates which kind of on-chain transaction of a channel a
+// fee was paid for.
+type ChannelFeeType uint8
+
+const (
+	// ChannelFeeFunding is the fee paid for the funding transaction of a
+	// channel we opened.
+	ChannelFeeFunding ChannelFeeType = 0
+
+	// ChannelFeeCommitment is the fee of a commitment transaction that
+	// confirmed on-chain, which is paid by the initiator of the channel.
+	ChannelFeeCommitment ChannelFeeType = 1
+
+	// ChannelFeeCooperativeClose is the fee of a cooperative close
+	// transaction, which is paid by the initiator of the channel.
+	ChannelFeeCooperativeClose ChannelFeeType = 2
+
+	// ChannelFeeSweep is our share of the fee of a sweep transaction that
+	// claimed one of the outputs of the channel, or that bumped the fee of
+	// its commitment through an anchor.
+	ChannelFeeSweep ChannelFeeType = 3
+)
+
+// String returns a human readable version of the fee type.
+func (t ChannelFeeType) String() string {
+	switch t {
+	case ChannelFeeFunding:
+		return "Funding"
+
+	case ChannelFeeCommitment:
+		return "Commitment"
+
+	case ChannelFeeCooperativeClose:
+		return "CooperativeClose"
+
+	case ChannelFeeSweep:
+		return "Sweep"
+
+	default:
+		return fmt.Sprintf("ChannelFeeType(%d)", t)
+	}
+}
+
+// ChannelFees is an account of the on-chain fees that were paid by us over the
+// lifetime of a channel.
+type ChannelFees struct {
+	// Funding is the fee paid for the funding transaction. This is only
+	// known for channels we funded from our wallet.
+	Funding btcutil.Amount
+
+	// Commitment is the fee of the commitment transaction that closed the
+	// channel, if we're the initiator of the channel.
+	Commitment btcutil.Amount
+
+	// CooperativeClose is the fee of the cooperative close transaction,
+	// if we're the initiator of the channel.
+	CooperativeClose btcutil.Amount
+
+	// Sweep is the sum of our shares of the fees of the sweep transactions
+	// that claimed outputs of the channel or bumped its commitment.
+	Sweep btcutil.Amount
+}
+
+// Total returns the sum of all fees paid for the channel.
+func (f *ChannelFees) Total() btcutil.Amount {
+	return f.Funding + f.Commitment + f.CooperativeClose + f.Sweep
+}
+
+// channelFeeSize is the size of a serialized channel fee: the fee type
+// followed by the fee amount.
+const channelFeeSize = 1 + 8
+
+// PutChannelFee records a fee that was paid by us on behalf of the channel
+// identified by chanPoint. The fee is attributed to the input with the given
+// index of the transaction with the given txid, which allows the shares of a
+// sweep transaction that claims several outputs of the channel to be recorded
+// separately. For fees of whole transactions, like the funding or closing
+// transaction, the input index should be zero. Recording a fee for the same
+// transaction input again overwrites the previous record.
+func (c *ChannelStateDB) PutChannelFee(chanPoint wire.OutPoint,
+	txid chainhash.Hash, inputIndex uint32, feeType ChannelFeeType,
+	fee btcutil.Amount) error {
+
+	var chanPointBuf bytes.Buffer
+	if err := writeOutpoint(&chanPointBuf, &chanPoint); err != nil {
+		return err
+	}
+
+	var key [chainhash.HashSize + 4]byte
+	copy(key[:], txid[:])
+	binary.BigEndian.PutUint32(key[chainhash.HashSize:], inputIndex)
+
+	var value [channelFeeSize]byte
+	value[0] = byte(feeType)
+	binary.BigEndian.PutUint64(value[1:], uint64(fee))
+
+	return kvdb.Update(c.backend, func(tx kvdb.RwTx) error {
+		feesBucket, err := tx.CreateTopLevelBucket(channelFeesBucket)
+		if err != nil {
+			return err
+		}
+
+		chanBucket, err := feesBucket.CreateBucketIfNotExists(
+			chanPointBuf.Bytes(),
+		)
+		if err != nil {
+			return err
+		}
+
+		return chanBucket.Put(key[:], value[:])
+	}, func() {})
+}
+
+// FetchChannelFees returns the on-chain fees that were recorded for the
+// channel identified by chanPoint. If no fees were recorded, all fees are
+// zero.
+func (c *ChannelStateDB) FetchChannelFees(
+	chanPoint wire.OutPoint) (*ChannelFees, error) {
+
+	var chanPointBuf bytes.Buffer
+	if err := writeOutpoint(&chanPointBuf, &chanPoint); err != nil {
+		return nil, err
+	}
+
+	fees := &ChannelFees{}
+	err := kvdb.View(c.backend, func(tx kvdb.RTx) error {
+		feesBucket := tx.ReadBucket(channelFeesBucket)
+		if feesBucket == nil {
+			return nil
+		}
+
+		chanBucket := feesBucket.NestedReadBucket(
+			chanPointBuf.Bytes(),
+		)
+		if chanBucket == nil {
+			return nil
+		}
+
+		var err error
+		fees, err = readChannelFees(chanBucket)
+		return err
+	}, func() {
+		fees = &ChannelFees{}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fees, nil
+}
+
+// FetchAllChannelFees returns the on-chain fees that were recorded for all
+// channels within a single database transaction, keyed by their channel point.
+// Channels without any recorded fees are omitted.
+func (c *ChannelStateDB) FetchAllChannelFees() (
+	map[wire.OutPoint]*ChannelFees, error) {
+
+	var allFees map[wire.OutPoint]*ChannelFees
+	err := kvdb.View(c.backend, func(tx kvdb.RTx) error {
+		feesBucket := tx.ReadBucket(channelFeesBucket)
+		if feesBucket == nil {
+			return nil
+		}
+
+		return feesBucket.ForEach(func(k, _ []byte) error {
+			chanBucket := feesBucket.NestedReadBucket(k)
+			if chanBucket == nil {
+				return nil
+			}
+
+			var chanPoint wire.OutPoint
+			err := readOutpoint(bytes.NewReader(k), &chanPoint)
+			if err != nil {
+				return err
+			}
+
+			fees, err := readChannelFees(chanBucket)
+			if err != nil {
+				return err
+			}
+			allFees[chanPoint] = fees
+
+			return nil
+		})
+	}, func() {
+		allFees = make(map[wire.OutPoint]*ChannelFees)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return allFees, nil
+}
+
+// readChannelFees sums up the fees recorded in the given channel bucket.
+func readChannelFees(chanBucket kvdb.RBucket) (*ChannelFees, error) {
+	var fees ChannelFees
+	err := chanBucket.ForEach(func(k, v []byte) error {
+		if len(v) != channelFeeSize {
+			return fmt.Errorf("invalid channel fee for key %x", k)
+		}
+
+		fee := btcutil.Amount(binary.BigEndian.Uint64(v[1:]))
+		switch ChannelFeeType(v[0]) {
+		case ChannelFeeFunding:
+			fees.Funding += fee
+
+		case ChannelFeeCommitment:
+			fees.Commitment += fee
+
+		case ChannelFeeCooperativeClose:
+			fees.CooperativeClose += fee
+
+		case ChannelFeeSweep:
+			fees.Sweep += fee
+
+		default:
+			return fmt.Errorf("unknown channel fee type %v",
+				ChannelFeeType(v[0]))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &fees, nil
+}
@@ -0,0 +1,76 @@
+package channeldb
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChannelFees tests that the on-chain fees of a channel are accumulated
+// per type, and that recording the fee of the same transaction input twice
+// doesn't count it twice.
+func TestChannelFees(t *testing.T) {
+	t.Parallel()
+
+	fullDB, cleanUp, err := MakeTestDB()
+	require.NoError(t, err, "unable to make test database")
+	defer cleanUp()
+
+	cdb := fullDB.ChannelStateDB()
+
+	chanPoint := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 1}
+	otherChanPoint := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 2}
+
+	// Channels without any recorded fees have no fees.
+	fees, err := cdb.FetchChannelFees(chanPoint)
+	require.NoError(t, err)
+	require.Equal(t, &ChannelFees{}, fees)
+
+	allFees, err := cdb.FetchAllChannelFees()
+	require.NoError(t, err)
+	require.Empty(t, allFees)
+
+	fundingTx := chainhash.Hash{2}
+	sweepTx := chainhash.Hash{3}
+
+	put := func(op wire.OutPoint, txid chainhash.Hash, idx uint32,
+		feeType ChannelFeeType, fee btcutil.Amount) {
+
+		err := cdb.PutChannelFee(op, txid, idx, feeType, fee)
+		require.NoError(t, err)
+	}
+
+	put(chanPoint, fundingTx, 0, ChannelFeeFunding, 500)
+	put(chanPoint, sweepTx, 0, ChannelFeeSweep, 100)
+	put(chanPoint, sweepTx, 1, ChannelFeeSweep, 200)
+	put(otherChanPoint, sweepTx, 2, ChannelFeeSweep, 300)
+
+	// Recording the same fee again must not change the totals.
+	put(chanPoint, sweepTx, 1, ChannelFeeSweep, 200)
+
+	fees, err = cdb.FetchChannelFees(chanPoint)
+	require.NoError(t, err)
+	require.Equal(t, &ChannelFees{
+		Funding: 500,
+		Sweep:   300,
+	}, fees)
+	require.Equal(t, btcutil.Amount(800), fees.Total())
+
+	fees, err = cdb.FetchChannelFees(otherChanPoint)
+	require.NoError(t, err)
+	require.Equal(t, &ChannelFees{Sweep: 300}, fees)
+
+	// Fetching the fees of all channels at once should yield the same.
+	allFees, err = cdb.FetchAllChannelFees()
+	require.NoError(t, err)
+	require.Equal(t, map[wire.OutPoint]*ChannelFees{
+		chanPoint: {
+			Funding: 500,
+			Sweep:   300,
+		},
+		otherChanPoint: {Sweep: 300},
+	}, allFees)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/batch"
 	mig "github.com/lightningnetwork/lnd/channeldb/migration"
 	"github.com/lightningnetwork/lnd/channeldb/migration12"
 	"github.com/lightningnetwork/lnd/channeldb/migration13"
@@ -292,6 +293,11 @@ type DB struct {
 	clock                     clock.Clock
 	dryRun                    bool
 	keepFailedPaymentAttempts bool
+
+	// groupCommit is the scheduler that non-critical updates are lazily
+	// added to if group commit mode is enabled. It is shared with the
+	// channel graph and nil if group commit mode is disabled.
+	groupCommit batch.Scheduler
 }
 
 // Open opens or creates channeldb. Any necessary schemas migrations due
@@ -356,12 +362,27 @@ func CreateWithBackend(backend kvdb.Backend, modifiers ...OptionModifier) (*DB,
 	chanDB.graph, err = NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
 		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		opts.UseGraphCache, opts.NoMigration,
+		opts.GraphCacheMaxSize, opts.UseGraphCache, opts.NoMigration,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	// In group commit mode, the graph's batch schedulers are replaced by a
+	// single scheduler that is shared with the other non-critical updates,
+	// such as the forwarding log. As gossip from remote peers is added
+	// lazily, concurrent gossip updates and forwarding events are written
+	// to disk in a single transaction per interval. The scheduler holds
+	// the graph's cache lock while committing, just like the graph's own
+	// channel scheduler.
+	if opts.GroupCommitInterval > 0 {
+		chanDB.groupCommit = batch.NewTimeScheduler(
+			backend, &chanDB.graph.cacheMu, opts.GroupCommitInterval,
+		)
+		chanDB.graph.chanScheduler = chanDB.groupCommit
+		chanDB.graph.nodeScheduler = chanDB.groupCommit
+	}
+
 	// Synchronize the version of database and apply migrations if needed.
 	if !opts.NoMigration {
 		if err := chanDB.syncVersions(dbVersions); err != nil {
@@ -401,6 +422,8 @@ var dbTopLevelBuckets = [][]byte{
 	outpointBucket,
 	chanIDBucket,
 	historicalChannelBucket,
+	channelFeesBucket,
+	outputLabelBucket,
 }
 
 // Wipe completely deletes all saved state within all used buckets within the
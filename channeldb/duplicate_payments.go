@@ -243,3 +243,284 @@ func fetchDuplicatePayments(paymentHashBucket kvdb.RBucket) ([]*MPPayment,
 
 	return payments, nil
 }
+
+// DuplicatePaymentsReport summarizes the legacy payment structures found by
+// CleanupDuplicatePayments, and the ones it removed if it wasn't a dry run.
+type DuplicatePaymentsReport struct {
+	// NumPaymentsWithDuplicates is the number of payment hashes that have
+	// a legacy duplicate payments bucket.
+	NumPaymentsWithDuplicates uint64
+
+	// NumDuplicates is the total number of legacy duplicate payments.
+	NumDuplicates uint64
+
+	// NumDuplicatesDeleted is the number of failed or incomplete duplicate
+	// payments that were (or would be, in a dry run) deleted.
+	NumDuplicatesDeleted uint64
+
+	// NumDuplicatesKept is the number of succeeded duplicate payments that
+	// were kept, as they hold the preimage proving the payment.
+	NumDuplicatesKept uint64
+
+	// NumDanglingIndexes is the number of entries of the payments index
+	// that point to a payment that no longer exists, and were (or would
+	// be) deleted.
+	NumDanglingIndexes uint64
+
+	// ReclaimedBytes is the approximate number of key and value bytes
+	// that were (or would be) freed by the cleanup.
+	ReclaimedBytes uint64
+}
+
+// duplicateCleanup holds the set of deletions collected by a duplicate
+// payments cleanup.
+type duplicateCleanup struct {
+	// duplicates maps a payment hash to the keys of the duplicate payment
+	// buckets that should be deleted.
+	duplicates map[lntypes.Hash][][]byte
+
+	// emptyBuckets is the set of payment hashes whose duplicate payments
+	// bucket is empty after deleting the duplicates.
+	emptyBuckets []lntypes.Hash
+
+	// indexes is the set of payments index keys that should be deleted.
+	indexes [][]byte
+}
+
+// bucketSize returns the number of key and value bytes stored in the passed
+// bucket, including all of its nested buckets.
+func bucketSize(bucket kvdb.RBucket) (uint64, error) {
+	var size uint64
+	err := bucket.ForEach(func(k, v []byte) error {
+		size += uint64(len(k) + len(v))
+
+		// A nil value marks a nested bucket.
+		if v != nil {
+			return nil
+		}
+
+		nested := bucket.NestedReadBucket(k)
+		if nested == nil {
+			return nil
+		}
+
+		nestedSize, err := bucketSize(nested)
+		if err != nil {
+			return err
+		}
+		size += nestedSize
+
+		return nil
+	})
+
+	return size, err
+}
+
+// collectDuplicateCleanup finds the legacy duplicate payments and dangling
+// payments index entries that should be removed, and fills in the report
+// accordingly.
+func collectDuplicateCleanup(tx kvdb.RTx,
+	report *DuplicatePaymentsReport) (*duplicateCleanup, error) {
+
+	cleanup := &duplicateCleanup{
+		duplicates: make(map[lntypes.Hash][][]byte),
+	}
+
+	payments := tx.ReadBucket(paymentsRootBucket)
+	if payments == nil {
+		return cleanup, nil
+	}
+
+	err := payments.ForEach(func(k, _ []byte) error {
+		bucket := payments.NestedReadBucket(k)
+		if bucket == nil {
+			return fmt.Errorf("non bucket element in payments " +
+				"bucket")
+		}
+
+		dup := bucket.NestedReadBucket(duplicatePaymentsBucket)
+		if dup == nil {
+			return nil
+		}
+
+		hash, err := lntypes.MakeHash(k)
+		if err != nil {
+			return err
+		}
+
+		report.NumPaymentsWithDuplicates++
+
+		var numKept int
+		err = dup.ForEach(func(seqNr, _ []byte) error {
+			subBucket := dup.NestedReadBucket(seqNr)
+			if subBucket == nil {
+				return ErrNoDuplicateNestedBucket
+			}
+
+			report.NumDuplicates++
+
+			// Succeeded duplicates are kept, since their preimage
+			// is the proof of the payment. Failed duplicates, and
+			// the ones that never completed, can't be retried or
+			// resumed anymore, so we delete them.
+			status := fetchDuplicatePaymentStatus(subBucket)
+			if status == StatusSucceeded {
+				report.NumDuplicatesKept++
+				numKept++
+
+				return nil
+			}
+
+			size, err := bucketSize(subBucket)
+			if err != nil {
+				return err
+			}
+
+			// The sequence number of the duplicate is also the
+			// key of its payments index entry, which we'll delete
+			// along with it.
+			report.NumDuplicatesDeleted++
+			report.ReclaimedBytes += size + uint64(len(seqNr))
+			cleanup.duplicates[hash] = append(
+				cleanup.duplicates[hash], seqNr,
+			)
+			cleanup.indexes = append(cleanup.indexes, seqNr)
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if numKept == 0 {
+			cleanup.emptyBuckets = append(
+				cleanup.emptyBuckets, hash,
+			)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := tx.ReadBucket(paymentsIndexBucket)
+	if indexes == nil {
+		return cleanup, nil
+	}
+
+	// Finally, look for index entries that point to payments that don't
+	// exist anymore. These could be left behind by older versions of lnd
+	// that deleted payments without their index entries.
+	err = indexes.ForEach(func(seqNr, v []byte) error {
+		r := bytes.NewReader(v)
+		hash, err := deserializePaymentIndex(r)
+		if err != nil {
+			return err
+		}
+
+		bucket := payments.NestedReadBucket(hash[:])
+		if bucket != nil && hasSequenceNumber(bucket, seqNr) {
+			return nil
+		}
+
+		report.NumDanglingIndexes++
+		report.ReclaimedBytes += uint64(len(seqNr) + len(v))
+		cleanup.indexes = append(cleanup.indexes, seqNr)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cleanup, nil
+}
+
+// hasSequenceNumber returns true if the payment stored in the passed bucket,
+// or one of its duplicates, has the given sequence number.
+func hasSequenceNumber(paymentBucket kvdb.RBucket, seqNr []byte) bool {
+	if bytes.Equal(paymentBucket.Get(paymentSequenceKey), seqNr) {
+		return true
+	}
+
+	dup := paymentBucket.NestedReadBucket(duplicatePaymentsBucket)
+	if dup == nil {
+		return false
+	}
+
+	return dup.NestedReadBucket(seqNr) != nil
+}
+
+// CleanupDuplicatePayments removes the legacy structures left behind by older
+// versions of lnd from the payments database: failed and incomplete duplicate
+// payments, the duplicate payments buckets that become empty as a result, and
+// entries of the payments index that point to payments that no longer exist.
+// Succeeded duplicate payments are kept. If dryRun is set, nothing is deleted
+// and only the report of what would be removed is returned.
+func (d *DB) CleanupDuplicatePayments(dryRun bool) (*DuplicatePaymentsReport,
+	error) {
+
+	var report *DuplicatePaymentsReport
+
+	if dryRun {
+		err := kvdb.View(d, func(tx kvdb.RTx) error {
+			_, err := collectDuplicateCleanup(tx, report)
+			return err
+		}, func() {
+			report = &DuplicatePaymentsReport{}
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return report, nil
+	}
+
+	err := kvdb.Update(d, func(tx kvdb.RwTx) error {
+		cleanup, err := collectDuplicateCleanup(tx, report)
+		if err != nil {
+			return err
+		}
+
+		payments := tx.ReadWriteBucket(paymentsRootBucket)
+		for hash, seqNrs := range cleanup.duplicates {
+			dup := payments.NestedReadWriteBucket(
+				hash[:],
+			).NestedReadWriteBucket(duplicatePaymentsBucket)
+
+			for _, seqNr := range seqNrs {
+				err := dup.DeleteNestedBucket(seqNr)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, hash := range cleanup.emptyBuckets {
+			bucket := payments.NestedReadWriteBucket(hash[:])
+			err := bucket.DeleteNestedBucket(
+				duplicatePaymentsBucket,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		indexes := tx.ReadWriteBucket(paymentsIndexBucket)
+		for _, seqNr := range cleanup.indexes {
+			if err := indexes.Delete(seqNr); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {
+		report = &DuplicatePaymentsReport{}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
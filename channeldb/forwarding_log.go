@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/batch"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwire"
 )
@@ -101,7 +102,10 @@ func decodeForwardingEvent(r io.Reader, f *ForwardingEvent) error {
 
 // AddForwardingEvents adds a series of forwarding events to the database.
 // Before inserting, the set of events will be sorted according to their
-// timestamp. This ensures that all writes to disk are sequential.
+// timestamp. This ensures that all writes to disk are sequential. If group
+// commit mode is enabled, the events are committed together with other
+// non-critical updates, which may delay this call by up to the group commit
+// interval.
 func (f *ForwardingLog) AddForwardingEvents(events []ForwardingEvent) error {
 	// Before we create the database transaction, we'll ensure that the set
 	// of forwarding events are properly sorted according to their
@@ -111,7 +115,7 @@ func (f *ForwardingLog) AddForwardingEvents(events []ForwardingEvent) error {
 
 	var timestamp [8]byte
 
-	return kvdb.Batch(f.db.Backend, func(tx kvdb.RwTx) error {
+	update := func(tx kvdb.RwTx) error {
 		// First, we'll fetch the bucket that stores our time series
 		// log.
 		logBucket, err := tx.CreateTopLevelBucket(
@@ -131,7 +135,20 @@ func (f *ForwardingLog) AddForwardingEvents(events []ForwardingEvent) error {
 		}
 
 		return nil
-	})
+	}
+
+	// The forwarding log isn't critical to the safety of our channels, so
+	// it can be committed lazily if group commit mode is enabled.
+	if f.db.groupCommit != nil {
+		req := &batch.Request{
+			Update: update,
+		}
+		batch.LazyAdd()(req)
+
+		return f.db.groupCommit.Execute(req)
+	}
+
+	return kvdb.Batch(f.db.Backend, update)
 }
 
 // storeEvent tries to store a forwarding event into the given bucket by trying
@@ -3,10 +3,14 @@ package channeldb
 import (
 	"math/rand"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcwallet/walletdb"
 	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/batch"
+	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -364,3 +368,89 @@ func TestForwardingLogStoreEvent(t *testing.T) {
 		}
 	}
 }
+
+// countingBackend is a kvdb.Backend that counts the read-write transactions
+// that are committed through it.
+type countingBackend struct {
+	kvdb.Backend
+
+	updates uint32
+}
+
+// Update counts the transaction and passes it on to the wrapped backend.
+func (c *countingBackend) Update(f func(tx walletdb.ReadWriteTx) error,
+	reset func()) error {
+
+	atomic.AddUint32(&c.updates, 1)
+
+	return c.Backend.Update(f, reset)
+}
+
+// TestForwardingLogGroupCommit asserts that forwarding events and gossip
+// updates that are added concurrently are committed in a single transaction
+// if group commit mode is enabled.
+func TestForwardingLogGroupCommit(t *testing.T) {
+	t.Parallel()
+
+	backend, backendCleanup, err := kvdb.GetTestBackend(t.TempDir(), "cdb")
+	require.NoError(t, err)
+	t.Cleanup(backendCleanup)
+
+	counter := &countingBackend{Backend: backend}
+
+	const interval = 100 * time.Millisecond
+	db, err := CreateWithBackend(
+		counter, OptionSetGroupCommitInterval(interval),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	log := db.ForwardingLog()
+	graph := db.ChannelGraph()
+
+	node, err := createTestVertex(db)
+	require.NoError(t, err)
+
+	// Add two sets of forwarding events and a node announcement received
+	// from a remote peer concurrently.
+	atomic.StoreUint32(&counter.updates, 0)
+
+	timestamp := time.Unix(1234, 0)
+	errChan := make(chan error, 3)
+	for i := 0; i < 2; i++ {
+		events := []ForwardingEvent{{
+			Timestamp:      timestamp.Add(time.Duration(i)),
+			IncomingChanID: lnwire.NewShortChanIDFromInt(1),
+			OutgoingChanID: lnwire.NewShortChanIDFromInt(2),
+			AmtIn:          2000,
+			AmtOut:         1000,
+		}}
+
+		go func() {
+			errChan <- log.AddForwardingEvents(events)
+		}()
+	}
+	go func() {
+		errChan <- graph.AddLightningNode(node, batch.LazyAdd())
+	}()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, <-errChan)
+	}
+
+	// All of the updates should have been written in one transaction.
+	require.EqualValues(t, 1, atomic.LoadUint32(&counter.updates))
+
+	timeSlice, err := log.Query(ForwardingEventQuery{
+		StartTime:    timestamp,
+		EndTime:      timestamp.Add(time.Second),
+		NumMaxEvents: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, timeSlice.ForwardingEvents, 2)
+
+	_, err = graph.FetchLightningNode(node.PubKeyBytes)
+	require.NoError(t, err)
+}
@@ -190,7 +190,8 @@ type ChannelGraph struct {
 // returned instance has its own unique reject cache and channel cache.
 func NewChannelGraph(db kvdb.Backend, rejectCacheSize, chanCacheSize int,
 	batchCommitInterval time.Duration, preAllocCacheNumNodes int,
-	useGraphCache, noMigrations bool) (*ChannelGraph, error) {
+	graphCacheMaxSize uint64, useGraphCache,
+	noMigrations bool) (*ChannelGraph, error) {
 
 	if !noMigrations {
 		if err := initChannelGraph(db); err != nil {
@@ -210,10 +211,12 @@ func NewChannelGraph(db kvdb.Backend, rejectCacheSize, chanCacheSize int,
 		db, nil, batchCommitInterval,
 	)
 
-	// The graph cache can be turned off (e.g. for mobile users) for a
-	// speed/memory usage tradeoff.
+	// The graph cache can be turned off or bounded (e.g. for mobile users)
+	// for a speed/memory usage tradeoff.
 	if useGraphCache {
-		g.graphCache = NewGraphCache(preAllocCacheNumNodes)
+		g.graphCache = NewBoundedGraphCache(
+			preAllocCacheNumNodes, graphCacheMaxSize,
+		)
 		startTime := time.Now()
 		log.Debugf("Populating in-memory channel graph, this might " +
 			"take a while...")
@@ -469,7 +472,24 @@ func (c *ChannelGraph) ForEachNodeChannel(tx kvdb.RTx, node route.Vertex,
 	cb func(channel *DirectedChannel) error) error {
 
 	if c.graphCache != nil {
-		return c.graphCache.ForEachChannel(node, cb)
+		err := c.graphCache.ForEachChannel(node, cb)
+		if err != errGraphCacheMiss {
+			return err
+		}
+
+		// The node was evicted from the cache, so we load it from the
+		// database and try again.
+		if err := c.loadCacheNode(tx, node); err != nil {
+			return err
+		}
+
+		err = c.graphCache.ForEachChannel(node, cb)
+		if err != errGraphCacheMiss {
+			return err
+		}
+
+		// The node was modified while being loaded or is unknown, so
+		// it wasn't cached and we use the database for this call.
 	}
 
 	// Fallback that uses the database.
@@ -509,13 +529,89 @@ func (c *ChannelGraph) ForEachNodeChannel(tx kvdb.RTx, node route.Vertex,
 	return nodeTraversal(tx, node[:], c.db, dbCallback)
 }
 
+// loadCacheNode reads the features and channels of a node that was evicted
+// from the graph cache from the database and adds it back to the cache. If no
+// transaction is provided, a new one is created.
+func (c *ChannelGraph) loadCacheNode(tx kvdb.RTx, node route.Vertex) error {
+	if !c.graphCache.startLoad(node) {
+		return nil
+	}
+
+	var (
+		features *lnwire.FeatureVector
+		channels []*DirectedChannel
+	)
+	load := func(tx kvdb.RTx) error {
+		// Reset the result in case the transaction is retried.
+		features, channels = nil, nil
+
+		nodes := tx.ReadBucket(nodeBucket)
+		if nodes == nil {
+			return ErrGraphNotFound
+		}
+
+		lightningNode, err := fetchLightningNode(nodes, node[:])
+		switch {
+		case err == nil:
+			features = lightningNode.Features
+
+		case err != ErrGraphNodeNotFound:
+			return err
+		}
+
+		return nodeTraversal(tx, node[:], c.db, func(_ kvdb.RTx,
+			info *ChannelEdgeInfo, outPolicy,
+			inPolicy *ChannelEdgePolicy) error {
+
+			// Just like the cache itself, we skip channels that
+			// are disabled in both directions.
+			if outPolicy != nil && outPolicy.IsDisabled() &&
+				inPolicy != nil && inPolicy.IsDisabled() {
+
+				return nil
+			}
+
+			channel := &DirectedChannel{
+				ChannelID:    info.ChannelID,
+				IsNode1:      node == info.NodeKey1Bytes,
+				OtherNode:    info.NodeKey2Bytes,
+				Capacity:     info.Capacity,
+				OutPolicySet: outPolicy != nil,
+			}
+			if node == info.NodeKey2Bytes {
+				channel.OtherNode = info.NodeKey1Bytes
+			}
+			if inPolicy != nil {
+				channel.InPolicy = NewCachedPolicy(inPolicy)
+			}
+			channels = append(channels, channel)
+
+			return nil
+		})
+	}
+
+	var err error
+	if tx == nil {
+		err = kvdb.View(c.db, load, func() {})
+	} else {
+		err = load(tx)
+	}
+
+	c.graphCache.finishLoad(node, features, channels, err == nil)
+
+	return err
+}
+
 // FetchNodeFeatures returns the features of a given node. If no features are
 // known for the node, an empty feature vector is returned.
 func (c *ChannelGraph) FetchNodeFeatures(
 	node route.Vertex) (*lnwire.FeatureVector, error) {
 
 	if c.graphCache != nil {
-		return c.graphCache.GetFeatures(node), nil
+		features, ok := c.graphCache.getFeatures(node)
+		if ok {
+			return features, nil
+		}
 	}
 
 	// Fallback that uses the database.
@@ -545,7 +641,13 @@ func (c *ChannelGraph) ForEachNodeCached(cb func(node route.Vertex,
 	chans map[uint64]*DirectedChannel) error) error {
 
 	if c.graphCache != nil {
-		return c.graphCache.ForEachNode(cb)
+		err := c.graphCache.ForEachNode(cb)
+		if err != errGraphCacheMiss {
+			return err
+		}
+
+		// The cache is partial, so we need to iterate over the full
+		// graph in the database.
 	}
 
 	// Otherwise call back to a version that uses the database directly.
@@ -562,12 +664,10 @@ func (c *ChannelGraph) ForEachNodeCached(cb func(node route.Vertex,
 			toNodeCallback := func() route.Vertex {
 				return node.PubKeyBytes
 			}
-			toNodeFeatures, err := c.FetchNodeFeatures(
-				node.PubKeyBytes,
-			)
-			if err != nil {
-				return err
-			}
+			// We use the features of the node directly, as a lookup
+			// through a partial cache would load every node of the
+			// graph into it.
+			toNodeFeatures := node.Features
 
 			var cachedInPolicy *CachedEdgePolicy
 			if p2 != nil {
@@ -968,7 +1068,14 @@ func (c *ChannelGraph) deleteLightningNode(nodes kvdb.RwBucket,
 func (c *ChannelGraph) AddChannelEdge(edge *ChannelEdgeInfo,
 	op ...batch.SchedulerOption) error {
 
-	var alreadyExists bool
+	var (
+		alreadyExists bool
+		cacheSeq      uint64
+	)
+	if c.graphCache != nil {
+		cacheSeq = c.graphCache.writeSeq()
+	}
+
 	r := &batch.Request{
 		Reset: func() {
 			alreadyExists = false
@@ -986,6 +1093,13 @@ func (c *ChannelGraph) AddChannelEdge(edge *ChannelEdgeInfo,
 			return err
 		},
 		OnCommit: func(err error) error {
+			if c.graphCache != nil {
+				c.graphCache.commitWrite(
+					cacheSeq, edge.NodeKey1Bytes,
+					edge.NodeKey2Bytes,
+				)
+			}
+
 			switch {
 			case err != nil:
 				return err
@@ -2472,16 +2586,22 @@ func (c *ChannelGraph) UpdateEdgePolicy(edge *ChannelEdgePolicy,
 	var (
 		isUpdate1    bool
 		edgeNotFound bool
+		edgeNodes    []route.Vertex
+		cacheSeq     uint64
 	)
+	if c.graphCache != nil {
+		cacheSeq = c.graphCache.writeSeq()
+	}
 
 	r := &batch.Request{
 		Reset: func() {
 			isUpdate1 = false
 			edgeNotFound = false
+			edgeNodes = nil
 		},
 		Update: func(tx kvdb.RwTx) error {
 			var err error
-			isUpdate1, err = updateEdgePolicy(
+			isUpdate1, edgeNodes, err = updateEdgePolicy(
 				tx, edge, c.graphCache,
 			)
 
@@ -2495,6 +2615,10 @@ func (c *ChannelGraph) UpdateEdgePolicy(edge *ChannelEdgePolicy,
 			return err
 		},
 		OnCommit: func(err error) error {
+			if c.graphCache != nil {
+				c.graphCache.commitWrite(cacheSeq, edgeNodes...)
+			}
+
 			switch {
 			case err != nil:
 				return err
@@ -2545,21 +2669,21 @@ func (c *ChannelGraph) updateEdgeCache(e *ChannelEdgePolicy, isUpdate1 bool) {
 // updateEdgePolicy attempts to update an edge's policy within the relevant
 // buckets using an existing database transaction. The returned boolean will be
 // true if the updated policy belongs to node1, and false if the policy belonged
-// to node2.
+// to node2. The nodes of the channel are returned as well.
 func updateEdgePolicy(tx kvdb.RwTx, edge *ChannelEdgePolicy,
-	graphCache *GraphCache) (bool, error) {
+	graphCache *GraphCache) (bool, []route.Vertex, error) {
 
 	edges := tx.ReadWriteBucket(edgeBucket)
 	if edges == nil {
-		return false, ErrEdgeNotFound
+		return false, nil, ErrEdgeNotFound
 	}
 	edgeIndex := edges.NestedReadWriteBucket(edgeIndexBucket)
 	if edgeIndex == nil {
-		return false, ErrEdgeNotFound
+		return false, nil, ErrEdgeNotFound
 	}
 	nodes, err := tx.CreateTopLevelBucket(nodeBucket)
 	if err != nil {
-		return false, err
+		return false, nil, err
 	}
 
 	// Create the channelID key be converting the channel ID
@@ -2571,7 +2695,7 @@ func updateEdgePolicy(tx kvdb.RwTx, edge *ChannelEdgePolicy,
 	// nodes which connect this channel edge.
 	nodeInfo := edgeIndex.Get(chanID[:])
 	if nodeInfo == nil {
-		return false, ErrEdgeNotFound
+		return false, nil, ErrEdgeNotFound
 	}
 
 	// Depending on the flags value passed above, either the first
@@ -2592,7 +2716,7 @@ func updateEdgePolicy(tx kvdb.RwTx, edge *ChannelEdgePolicy,
 	// identified, we update the on-disk edge representation.
 	err = putChanEdgePolicy(edges, nodes, edge, fromNode, toNode)
 	if err != nil {
-		return false, err
+		return false, nil, err
 	}
 
 	var (
@@ -2608,7 +2732,7 @@ func updateEdgePolicy(tx kvdb.RwTx, edge *ChannelEdgePolicy,
 		)
 	}
 
-	return isUpdate1, nil
+	return isUpdate1, []route.Vertex{fromNodePubKey, toNodePubKey}, nil
 }
 
 // LightningNode represents an individual vertex/node within the channel graph.
@@ -1,6 +1,8 @@
 package channeldb
 
 import (
+	"container/list"
+	"errors"
 	"fmt"
 	"sync"
 
@@ -164,18 +166,108 @@ func (c *DirectedChannel) DeepCopy() *DirectedChannel {
 	return &channelCopy
 }
 
+const (
+	// graphCacheNodeSize is the estimated number of bytes the graph cache
+	// uses to hold a node, excluding its channels and features. This
+	// covers the map entries of the node and its LRU list element.
+	graphCacheNodeSize = 256
+
+	// graphCacheChannelSize is the estimated number of bytes the graph
+	// cache uses to hold one side of a channel, including its incoming
+	// policy and the map entry.
+	graphCacheChannelSize = 224
+
+	// graphCacheFeaturesSize is the estimated number of bytes the graph
+	// cache uses to hold the feature vector of a node.
+	graphCacheFeaturesSize = 128
+)
+
+// errGraphCacheMiss is returned by the graph cache if the requested
+// information isn't in the cache because it was evicted to stay within the
+// memory budget. The caller needs to fall back to the database.
+var errGraphCacheMiss = errors.New("graph cache miss")
+
 // GraphCache is a type that holds a minimal set of information of the public
 // channel graph that can be used for pathfinding.
+//
+// The cache can optionally be bounded by a memory budget. Once the estimated
+// size of the cache exceeds the budget, the least recently used nodes are
+// evicted together with their channels and features. From that point on, the
+// cache is partial and a node that isn't in the cache needs to be loaded from
+// the database by the caller.
 type GraphCache struct {
 	nodeChannels map[route.Vertex]map[uint64]*DirectedChannel
 	nodeFeatures map[route.Vertex]*lnwire.FeatureVector
 
+	// maxSize is the memory budget of the cache in bytes. A value of zero
+	// means the cache is unbounded.
+	maxSize uint64
+
+	// size is the estimated number of bytes the cache currently uses.
+	size uint64
+
+	// partial is set once the first node was evicted. From then on, a
+	// node that isn't in the cache might still exist in the graph.
+	partial bool
+
+	// numEvictions is the number of nodes evicted from the cache.
+	numEvictions uint64
+
+	// lru is the list of cached nodes, ordered from most to least
+	// recently used, and lruElems maps each cached node to its list
+	// element. Both are only maintained if the cache is bounded.
+	lru      *list.List
+	lruElems map[route.Vertex]*list.Element
+
+	// lruMtx guards the order of the LRU list, which is also updated by
+	// readers that only hold the read lock of mtx. It must only be
+	// acquired after mtx.
+	lruMtx sync.Mutex
+
+	// loading tracks the nodes that are currently being loaded from the
+	// database. A node is marked as dirty if it is modified while being
+	// loaded, in which case the possibly stale result isn't cached.
+	loading map[route.Vertex]*graphCacheLoad
+
+	// loadSeq is the sequence number of the most recent load, and
+	// loadedSeq maps each cached node that was loaded from the database to
+	// the sequence number of its load. They allow forgetting nodes that
+	// were loaded while a write transaction modifying them was pending.
+	loadSeq   uint64
+	loadedSeq map[route.Vertex]uint64
+
 	mtx sync.RWMutex
 }
 
+// graphCacheLoad tracks a node that is being loaded from the database.
+type graphCacheLoad struct {
+	// seq is the sequence number of the load.
+	seq uint64
+
+	// dirty is set if the node was modified while being loaded.
+	dirty bool
+}
+
 // NewGraphCache creates a new graphCache.
 func NewGraphCache(preAllocNumNodes int) *GraphCache {
-	return &GraphCache{
+	return NewBoundedGraphCache(preAllocNumNodes, 0)
+}
+
+// NewBoundedGraphCache creates a new graphCache that evicts the least recently
+// used nodes once its estimated size exceeds maxSize bytes. A maxSize of zero
+// means the cache is unbounded.
+func NewBoundedGraphCache(preAllocNumNodes int, maxSize uint64) *GraphCache {
+	// There is no point in pre-allocating more nodes than the budget can
+	// hold.
+	maxNumNodes := maxSize / graphCacheNodeSize
+	if maxSize != 0 && uint64(preAllocNumNodes) > maxNumNodes {
+		preAllocNumNodes = int(maxNumNodes)
+	}
+
+	c := &GraphCache{
+		maxSize:   maxSize,
+		loading:   make(map[route.Vertex]*graphCacheLoad),
+		loadedSeq: make(map[route.Vertex]uint64),
 		nodeChannels: make(
 			map[route.Vertex]map[uint64]*DirectedChannel,
 			// A channel connects two nodes, so we can look it up
@@ -188,6 +280,15 @@ func NewGraphCache(preAllocNumNodes int) *GraphCache {
 			preAllocNumNodes,
 		),
 	}
+
+	if maxSize != 0 {
+		c.lru = list.New()
+		c.lruElems = make(
+			map[route.Vertex]*list.Element, preAllocNumNodes,
+		)
+	}
+
+	return c
 }
 
 // Stats returns statistics about the current cache size.
@@ -199,9 +300,129 @@ func (c *GraphCache) Stats() string {
 	for node := range c.nodeChannels {
 		numChannels += len(c.nodeChannels[node])
 	}
-	return fmt.Sprintf("num_node_features=%d, num_nodes=%d, "+
+	stats := fmt.Sprintf("num_node_features=%d, num_nodes=%d, "+
 		"num_channels=%d", len(c.nodeFeatures), len(c.nodeChannels),
 		numChannels)
+
+	if c.maxSize != 0 {
+		stats += fmt.Sprintf(", size=%d, max_size=%d, "+
+			"num_evictions=%d", c.size, c.maxSize, c.numEvictions)
+	}
+
+	return stats
+}
+
+// cached returns true if the node is held by the cache. If the cache isn't
+// partial, every node is treated as cached, as the cache then holds the full
+// graph.
+//
+// NOTE: The caller must hold at least the read lock.
+func (c *GraphCache) cached(node route.Vertex) bool {
+	if !c.partial {
+		return true
+	}
+
+	_, ok := c.lruElems[node]
+	return ok
+}
+
+// touch adds the node to the LRU list if it isn't in it yet and otherwise
+// marks it as the most recently used node.
+//
+// NOTE: The caller must hold the write lock.
+func (c *GraphCache) touch(node route.Vertex) {
+	if c.maxSize == 0 {
+		return
+	}
+
+	c.lruMtx.Lock()
+	defer c.lruMtx.Unlock()
+
+	if elem, ok := c.lruElems[node]; ok {
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	c.lruElems[node] = c.lru.PushFront(node)
+	c.size += graphCacheNodeSize
+}
+
+// markUsed marks a cached node as the most recently used node.
+//
+// NOTE: The caller must hold at least the read lock.
+func (c *GraphCache) markUsed(node route.Vertex) {
+	if c.maxSize == 0 {
+		return
+	}
+
+	c.lruMtx.Lock()
+	defer c.lruMtx.Unlock()
+
+	if elem, ok := c.lruElems[node]; ok {
+		c.lru.MoveToFront(elem)
+	}
+}
+
+// markDirty marks the node as modified if it is currently being loaded from
+// the database.
+//
+// NOTE: The caller must hold the write lock.
+func (c *GraphCache) markDirty(node route.Vertex) {
+	if load, ok := c.loading[node]; ok {
+		load.dirty = true
+	}
+}
+
+// nodeSize returns the estimated number of bytes the cache uses to hold the
+// node.
+//
+// NOTE: The caller must hold at least the read lock.
+func (c *GraphCache) nodeSize(node route.Vertex) uint64 {
+	size := uint64(graphCacheNodeSize)
+	size += uint64(len(c.nodeChannels[node])) * graphCacheChannelSize
+	if _, ok := c.nodeFeatures[node]; ok {
+		size += graphCacheFeaturesSize
+	}
+
+	return size
+}
+
+// forget removes the node from the cache, without removing its channels from
+// the other nodes.
+//
+// NOTE: The caller must hold the write lock.
+func (c *GraphCache) forget(node route.Vertex) {
+	if c.maxSize != 0 {
+		c.lruMtx.Lock()
+		if elem, ok := c.lruElems[node]; ok {
+			c.lru.Remove(elem)
+			delete(c.lruElems, node)
+			c.size -= c.nodeSize(node)
+		}
+		c.lruMtx.Unlock()
+	}
+
+	delete(c.nodeChannels, node)
+	delete(c.nodeFeatures, node)
+	delete(c.loadedSeq, node)
+}
+
+// evict removes the least recently used nodes until the cache is within its
+// memory budget again. The most recently used node is never evicted.
+//
+// NOTE: The caller must hold the write lock.
+func (c *GraphCache) evict() {
+	if c.maxSize == 0 {
+		return
+	}
+
+	for c.size > c.maxSize && c.lru.Len() > 1 {
+		node := c.lru.Back().Value.(route.Vertex)
+
+		c.forget(node)
+		c.partial = true
+		c.numEvictions++
+	}
 }
 
 // AddNodeFeatures adds a graph node and its features to the cache.
@@ -213,8 +434,23 @@ func (c *GraphCache) AddNodeFeatures(node GraphCacheNode) {
 	// between the calls. And the AddChannel() method will acquire its own
 	// lock anyway.
 	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.markDirty(nodePubKey)
+
+	// If the node was evicted, we don't add it back. It is loaded from
+	// the database once it is needed again.
+	if !c.cached(nodePubKey) {
+		return
+	}
+
+	c.touch(nodePubKey)
+	if _, ok := c.nodeFeatures[nodePubKey]; !ok && c.maxSize != 0 {
+		c.size += graphCacheFeaturesSize
+	}
 	c.nodeFeatures[nodePubKey] = node.Features()
-	c.mtx.Unlock()
+
+	c.evict()
 }
 
 // AddNode adds a graph node, including all the (directed) channels of that
@@ -265,6 +501,7 @@ func (c *GraphCache) AddChannel(info *ChannelEdgeInfo,
 		OtherNode: info.NodeKey1Bytes,
 		Capacity:  info.Capacity,
 	})
+	c.evict()
 	c.mtx.Unlock()
 
 	// The policy's node is always the to_node. So if policy 1 has to_node
@@ -290,10 +527,24 @@ func (c *GraphCache) AddChannel(info *ChannelEdgeInfo,
 // updateOrAddEdge makes sure the edge information for a node is either updated
 // if it already exists or is added to that node's list of channels.
 func (c *GraphCache) updateOrAddEdge(node route.Vertex, edge *DirectedChannel) {
+	c.markDirty(node)
+
+	// If the node was evicted, we don't add the channel, as we'd end up
+	// with an incomplete list of the node's channels. The node is loaded
+	// from the database once it is needed again.
+	if !c.cached(node) {
+		return
+	}
+
+	c.touch(node)
 	if len(c.nodeChannels[node]) == 0 {
 		c.nodeChannels[node] = make(map[uint64]*DirectedChannel)
 	}
 
+	_, ok := c.nodeChannels[node][edge.ChannelID]
+	if !ok && c.maxSize != 0 {
+		c.size += graphCacheChannelSize
+	}
 	c.nodeChannels[node][edge.ChannelID] = edge
 }
 
@@ -308,6 +559,8 @@ func (c *GraphCache) UpdatePolicy(policy *ChannelEdgePolicy, fromNode,
 	defer c.mtx.Unlock()
 
 	updatePolicy := func(nodeKey route.Vertex) {
+		c.markDirty(nodeKey)
+
 		if len(c.nodeChannels[nodeKey]) == 0 {
 			return
 		}
@@ -347,7 +600,7 @@ func (c *GraphCache) RemoveNode(node route.Vertex) {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
 
-	delete(c.nodeFeatures, node)
+	c.markDirty(node)
 
 	// First remove all channels from the other nodes' lists.
 	for _, channel := range c.nodeChannels[node] {
@@ -355,7 +608,7 @@ func (c *GraphCache) RemoveNode(node route.Vertex) {
 	}
 
 	// Then remove our whole node completely.
-	delete(c.nodeChannels, node)
+	c.forget(node)
 }
 
 // RemoveChannel removes a single channel between two nodes.
@@ -370,11 +623,20 @@ func (c *GraphCache) RemoveChannel(node1, node2 route.Vertex, chanID uint64) {
 
 // removeChannelIfFound removes a single channel from one side.
 func (c *GraphCache) removeChannelIfFound(node route.Vertex, chanID uint64) {
+	c.markDirty(node)
+
 	if len(c.nodeChannels[node]) == 0 {
 		return
 	}
 
+	if _, ok := c.nodeChannels[node][chanID]; !ok {
+		return
+	}
+
 	delete(c.nodeChannels[node], chanID)
+	if c.maxSize != 0 {
+		c.size -= graphCacheChannelSize
+	}
 }
 
 // UpdateChannel updates the channel edge information for a specific edge. We
@@ -384,12 +646,11 @@ func (c *GraphCache) UpdateChannel(info *ChannelEdgeInfo) {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
 
-	if len(c.nodeChannels[info.NodeKey1Bytes]) == 0 ||
-		len(c.nodeChannels[info.NodeKey2Bytes]) == 0 {
-
-		return
-	}
+	c.markDirty(info.NodeKey1Bytes)
+	c.markDirty(info.NodeKey2Bytes)
 
+	// Either of the nodes might have been evicted, so we update each side
+	// that is still cached.
 	channel, ok := c.nodeChannels[info.NodeKey1Bytes][info.ChannelID]
 	if ok {
 		// We only expect to be called when the channel is already
@@ -406,14 +667,22 @@ func (c *GraphCache) UpdateChannel(info *ChannelEdgeInfo) {
 }
 
 // getChannels returns a copy of the passed node's channels or nil if there
-// isn't any.
-func (c *GraphCache) getChannels(node route.Vertex) []*DirectedChannel {
+// isn't any. errGraphCacheMiss is returned if the node was evicted from the
+// cache.
+func (c *GraphCache) getChannels(node route.Vertex) ([]*DirectedChannel,
+	error) {
+
 	c.mtx.RLock()
 	defer c.mtx.RUnlock()
 
+	if !c.cached(node) {
+		return nil, errGraphCacheMiss
+	}
+	c.markUsed(node)
+
 	channels, ok := c.nodeChannels[node]
 	if !ok {
-		return nil
+		return nil, nil
 	}
 
 	features, ok := c.nodeFeatures[node]
@@ -448,10 +717,12 @@ func (c *GraphCache) getChannels(node route.Vertex) []*DirectedChannel {
 		i++
 	}
 
-	return channelsCopy
+	return channelsCopy, nil
 }
 
 // ForEachChannel invokes the given callback for each channel of the given node.
+// If the node was evicted from the cache, errGraphCacheMiss is returned
+// without invoking the callback.
 func (c *GraphCache) ForEachChannel(node route.Vertex,
 	cb func(channel *DirectedChannel) error) error {
 
@@ -462,7 +733,11 @@ func (c *GraphCache) ForEachChannel(node route.Vertex,
 	// the real world graph and our representation may always become
 	// slightly out of sync for a short time and the actual channel state
 	// is stored separately.
-	channels := c.getChannels(node)
+	channels, err := c.getChannels(node)
+	if err != nil {
+		return err
+	}
+
 	for _, channel := range channels {
 		if err := cb(channel); err != nil {
 			return err
@@ -477,13 +752,18 @@ func (c *GraphCache) ForEachChannel(node route.Vertex,
 // node.
 //
 // NOTE: This method should be considered _read only_, the channels or nodes
-// passed in MUST NOT be modified.
+// passed in MUST NOT be modified. If the cache is partial, errGraphCacheMiss
+// is returned without invoking the callback.
 func (c *GraphCache) ForEachNode(cb func(node route.Vertex,
 	channels map[uint64]*DirectedChannel) error) error {
 
 	c.mtx.RLock()
 	defer c.mtx.RUnlock()
 
+	if c.partial {
+		return errGraphCacheMiss
+	}
+
 	for node, channels := range c.nodeChannels {
 		// We don't make a copy here since this is a read-only RPC
 		// call. We also don't need the node features either for this
@@ -499,15 +779,122 @@ func (c *GraphCache) ForEachNode(cb func(node route.Vertex,
 // GetFeatures returns the features of the node with the given ID. If no
 // features are known for the node, an empty feature vector is returned.
 func (c *GraphCache) GetFeatures(node route.Vertex) *lnwire.FeatureVector {
+	features, _ := c.getFeatures(node)
+	return features
+}
+
+// getFeatures returns the features of the node with the given ID. If no
+// features are known for the node, an empty feature vector is returned. The
+// boolean is false if the node was evicted from the cache.
+func (c *GraphCache) getFeatures(node route.Vertex) (*lnwire.FeatureVector,
+	bool) {
+
 	c.mtx.RLock()
 	defer c.mtx.RUnlock()
 
+	if !c.cached(node) {
+		return lnwire.EmptyFeatureVector(), false
+	}
+	c.markUsed(node)
+
 	features, ok := c.nodeFeatures[node]
 	if !ok || features == nil {
 		// The router expects the features to never be nil, so we return
 		// an empty feature set instead.
-		return lnwire.EmptyFeatureVector()
+		return lnwire.EmptyFeatureVector(), true
 	}
 
-	return features
+	return features, true
+}
+
+// startLoad registers that the node is about to be loaded from the database
+// after a cache miss. It returns false if the node is already being loaded,
+// in which case the caller should read from the database without caching the
+// result.
+func (c *GraphCache) startLoad(node route.Vertex) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if _, ok := c.loading[node]; ok {
+		return false
+	}
+
+	c.loadSeq++
+	c.loading[node] = &graphCacheLoad{
+		seq: c.loadSeq,
+	}
+
+	return true
+}
+
+// finishLoad adds a node that was loaded from the database to the cache,
+// evicting other nodes if needed. The node isn't added if it was modified
+// while being loaded, as the loaded information might be stale. If loaded is
+// false, reading from the database failed and the load is only ended. Nodes
+// that are unknown to the database aren't added either, so that lookups of
+// nonexistent nodes don't evict the nodes we know.
+func (c *GraphCache) finishLoad(node route.Vertex,
+	features *lnwire.FeatureVector, channels []*DirectedChannel,
+	loaded bool) {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	load := c.loading[node]
+	delete(c.loading, node)
+
+	switch {
+	case !loaded || load.dirty || c.cached(node):
+		return
+
+	case features == nil && len(channels) == 0:
+		return
+	}
+
+	c.touch(node)
+	c.loadedSeq[node] = load.seq
+	if features != nil {
+		c.nodeFeatures[node] = features
+		c.size += graphCacheFeaturesSize
+	}
+
+	if len(channels) > 0 {
+		nodeChannels := make(map[uint64]*DirectedChannel, len(channels))
+		for _, channel := range channels {
+			nodeChannels[channel.ChannelID] = channel
+		}
+		c.nodeChannels[node] = nodeChannels
+		c.size += uint64(len(nodeChannels)) * graphCacheChannelSize
+	}
+
+	c.evict()
+}
+
+// writeSeq returns the sequence number that needs to be passed to commitWrite
+// once a write transaction that modifies the cache is committed. It must be
+// obtained before the transaction is started.
+func (c *GraphCache) writeSeq() uint64 {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.loadSeq
+}
+
+// commitWrite is called once a write transaction that modified the given
+// nodes in the cache was committed or failed. The transaction updates the
+// cache before it is committed, so a load of one of the nodes that started in
+// the meantime may have read the previous state from the database without
+// being marked as dirty. Such nodes are forgotten, so that they are loaded
+// again on their next use.
+func (c *GraphCache) commitWrite(seq uint64, nodes ...route.Vertex) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for _, node := range nodes {
+		c.markDirty(node)
+
+		if loadSeq, ok := c.loadedSeq[node]; ok && loadSeq > seq {
+			c.forget(node)
+		}
+	}
 }
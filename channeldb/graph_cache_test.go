@@ -144,6 +144,101 @@ func TestGraphCacheAddNode(t *testing.T) {
 	runTest(pubKey2, pubKey1)
 }
 
+// TestGraphCacheEviction tests that a graph cache with a memory budget evicts
+// the least recently used nodes and reports evicted nodes as cache misses.
+func TestGraphCacheEviction(t *testing.T) {
+	t.Parallel()
+
+	newNode := func(b byte) *node {
+		var pubKey route.Vertex
+		pubKey[0] = b
+
+		return &node{
+			pubKey:   pubKey,
+			features: lnwire.EmptyFeatureVector(),
+		}
+	}
+	nodeA, nodeB, nodeC := newNode(1), newNode(2), newNode(3)
+
+	// The budget fits exactly two nodes with their features.
+	cache := NewBoundedGraphCache(
+		10, 2*(graphCacheNodeSize+graphCacheFeaturesSize),
+	)
+	require.NoError(t, cache.AddNode(nil, nodeA))
+	require.NoError(t, cache.AddNode(nil, nodeB))
+	require.False(t, cache.partial)
+
+	// Using node A makes node B the least recently used node, so it is
+	// evicted once node C is added.
+	_, ok := cache.getFeatures(nodeA.pubKey)
+	require.True(t, ok)
+	require.NoError(t, cache.AddNode(nil, nodeC))
+
+	require.True(t, cache.partial)
+	require.EqualValues(t, 1, cache.numEvictions)
+	require.LessOrEqual(t, cache.size, cache.maxSize)
+
+	_, ok = cache.getFeatures(nodeB.pubKey)
+	require.False(t, ok)
+	err := cache.ForEachChannel(nodeB.pubKey, func(*DirectedChannel) error {
+		return nil
+	})
+	require.ErrorIs(t, err, errGraphCacheMiss)
+	require.ErrorIs(t, cache.ForEachNode(nil), errGraphCacheMiss)
+
+	// Updates of an evicted node don't add it back to the cache.
+	cache.AddNodeFeatures(nodeB)
+	_, ok = cache.getFeatures(nodeB.pubKey)
+	require.False(t, ok)
+
+	// A node that is modified while being loaded isn't cached, as the
+	// loaded information might be stale.
+	require.True(t, cache.startLoad(nodeB.pubKey))
+	require.False(t, cache.startLoad(nodeB.pubKey))
+	cache.AddNodeFeatures(nodeB)
+	cache.finishLoad(nodeB.pubKey, nodeB.features, nil, true)
+	_, ok = cache.getFeatures(nodeB.pubKey)
+	require.False(t, ok)
+
+	// Otherwise the loaded node is cached, evicting the least recently
+	// used node A.
+	require.True(t, cache.startLoad(nodeB.pubKey))
+	cache.finishLoad(nodeB.pubKey, nodeB.features, nil, true)
+	_, ok = cache.getFeatures(nodeB.pubKey)
+	require.True(t, ok)
+	_, ok = cache.getFeatures(nodeA.pubKey)
+	require.False(t, ok)
+	require.EqualValues(t, 2, cache.numEvictions)
+
+	// A node that is loaded while a write transaction modifying it is
+	// pending might have read the previous state from the database, so it
+	// is forgotten once the transaction is committed.
+	seq := cache.writeSeq()
+	require.True(t, cache.startLoad(nodeA.pubKey))
+	cache.finishLoad(nodeA.pubKey, nodeA.features, nil, true)
+	_, ok = cache.getFeatures(nodeA.pubKey)
+	require.True(t, ok)
+
+	cache.commitWrite(seq, nodeA.pubKey)
+	_, ok = cache.getFeatures(nodeA.pubKey)
+	require.False(t, ok)
+
+	// Nodes that were loaded before the transaction was started are kept.
+	cache.commitWrite(cache.writeSeq(), nodeB.pubKey)
+	_, ok = cache.getFeatures(nodeB.pubKey)
+	require.True(t, ok)
+
+	// Lookups of nodes that are unknown to the database aren't cached, so
+	// they don't evict the nodes we know.
+	unknownNode := newNode(4)
+	require.True(t, cache.startLoad(unknownNode.pubKey))
+	cache.finishLoad(unknownNode.pubKey, nil, nil, true)
+	_, ok = cache.getFeatures(unknownNode.pubKey)
+	require.False(t, ok)
+	_, ok = cache.getFeatures(nodeB.pubKey)
+	require.True(t, ok)
+}
+
 func assertCachedPolicyEqual(t *testing.T, original *ChannelEdgePolicy,
 	cached *CachedEdgePolicy) {
 
@@ -68,7 +68,7 @@ func MakeTestGraph(t testing.TB, modifiers ...OptionModifier) (*ChannelGraph, er
 	graph, err := NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
 		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		true, false,
+		opts.GraphCacheMaxSize, true, false,
 	)
 	if err != nil {
 		backendCleanup()
@@ -3525,7 +3525,7 @@ func TestGraphLoading(t *testing.T) {
 	graph, err := NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
 		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		true, false,
+		opts.GraphCacheMaxSize, true, false,
 	)
 	require.NoError(t, err)
 
@@ -3539,7 +3539,7 @@ func TestGraphLoading(t *testing.T) {
 	graphReloaded, err := NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
 		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		true, false,
+		opts.GraphCacheMaxSize, true, false,
 	)
 	require.NoError(t, err)
 
@@ -3554,3 +3554,110 @@ func TestGraphLoading(t *testing.T) {
 		graphReloaded.graphCache.nodeFeatures,
 	)
 }
+
+// TestGraphCacheMemoryBudget asserts that a graph cache with a memory budget
+// stays within the budget and transparently falls back to the database for
+// nodes that were evicted.
+func TestGraphCacheMemoryBudget(t *testing.T) {
+	t.Parallel()
+
+	tempDirName := t.TempDir()
+	backend, backendCleanup, err := kvdb.GetTestBackend(tempDirName, "cgr")
+	require.NoError(t, err)
+	defer backend.Close()
+	defer backendCleanup()
+
+	opts := DefaultOptions()
+	graph, err := NewChannelGraph(
+		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
+		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
+		opts.GraphCacheMaxSize, true, false,
+	)
+	require.NoError(t, err)
+
+	const numNodes = 100
+	const numChannels = 4
+	_, nodes := fillTestGraph(t, graph, numNodes, numChannels)
+
+	// Reload the graph with a budget that only fits a fraction of the
+	// nodes.
+	const maxSize = 20 * (graphCacheNodeSize + graphCacheFeaturesSize +
+		2*numChannels*graphCacheChannelSize)
+	bounded, err := NewChannelGraph(
+		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
+		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
+		maxSize, true, false,
+	)
+	require.NoError(t, err)
+	require.True(t, bounded.graphCache.partial)
+	require.LessOrEqual(t, bounded.graphCache.size, uint64(maxSize))
+
+	type channelSummary struct {
+		ChannelID    uint64
+		IsNode1      bool
+		OtherNode    route.Vertex
+		Capacity     btcutil.Amount
+		OutPolicySet bool
+		InPolicy     CachedEdgePolicy
+	}
+	summarize := func(g *ChannelGraph,
+		node route.Vertex) map[uint64]channelSummary {
+
+		channels := make(map[uint64]channelSummary)
+		err := g.ForEachNodeChannel(nil, node,
+			func(c *DirectedChannel) error {
+				s := channelSummary{
+					ChannelID:    c.ChannelID,
+					IsNode1:      c.IsNode1,
+					OtherNode:    c.OtherNode,
+					Capacity:     c.Capacity,
+					OutPolicySet: c.OutPolicySet,
+				}
+				if c.InPolicy != nil {
+					s.InPolicy = *c.InPolicy
+					s.InPolicy.ToNodePubKey = nil
+					s.InPolicy.ToNodeFeatures = nil
+				}
+				channels[c.ChannelID] = s
+
+				return nil
+			})
+		require.NoError(t, err)
+
+		return channels
+	}
+
+	// Every node must be served the same way by both caches, even though
+	// most of them have to be loaded from the database by the bounded one.
+	for _, node := range nodes {
+		require.Equal(
+			t, summarize(graph, node.PubKeyBytes),
+			summarize(bounded, node.PubKeyBytes),
+		)
+
+		features, err := graph.FetchNodeFeatures(node.PubKeyBytes)
+		require.NoError(t, err)
+		boundedFeatures, err := bounded.FetchNodeFeatures(
+			node.PubKeyBytes,
+		)
+		require.NoError(t, err)
+		require.Equal(t, features, boundedFeatures)
+
+		require.LessOrEqual(
+			t, bounded.graphCache.size, uint64(maxSize),
+		)
+	}
+	require.NotZero(t, bounded.graphCache.numEvictions)
+
+	// Iterating over all nodes must fall back to the database, as the
+	// cache only holds a part of the graph.
+	var numIterated int
+	err = bounded.ForEachNodeCached(func(route.Vertex,
+		map[uint64]*DirectedChannel) error {
+
+		numIterated++
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, numNodes, numIterated)
+}
@@ -316,6 +316,56 @@ func TestAddDuplicatePayAddr(t *testing.T) {
 	require.Error(t, err, ErrDuplicatePayAddr)
 }
 
+// TestReissueInvoice asserts that reissuing an invoice cancels the original
+// invoice and links both invoices, and that only open invoices can be
+// reissued.
+func TestReissueInvoice(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := MakeTestDB()
+	defer cleanUp()
+	require.NoError(t, err)
+
+	original, err := randInvoice(1000)
+	require.NoError(t, err)
+	originalHash := original.Terms.PaymentPreimage.Hash()
+	_, err = db.AddInvoice(original, originalHash)
+	require.NoError(t, err)
+
+	// Reissuing an unknown invoice fails.
+	reissued, err := randInvoice(1000)
+	require.NoError(t, err)
+	reissuedHash := reissued.Terms.PaymentPreimage.Hash()
+	reissued.ReissuedFrom = &reissuedHash
+	_, err = db.AddInvoice(reissued, reissuedHash)
+	require.ErrorIs(t, err, ErrInvoiceNotFound)
+
+	// Reissuing the original invoice cancels it and links it to the new
+	// invoice.
+	reissued.ReissuedFrom = &originalHash
+	_, err = db.AddInvoice(reissued, reissuedHash)
+	require.NoError(t, err)
+
+	dbOriginal, err := db.LookupInvoice(InvoiceRefByHash(originalHash))
+	require.NoError(t, err)
+	require.Equal(t, ContractCanceled, dbOriginal.State)
+	require.Nil(t, dbOriginal.ReissuedFrom)
+	require.Equal(t, &reissuedHash, dbOriginal.ReissuedTo)
+
+	dbReissued, err := db.LookupInvoice(InvoiceRefByHash(reissuedHash))
+	require.NoError(t, err)
+	require.Equal(t, ContractOpen, dbReissued.State)
+	require.Equal(t, &originalHash, dbReissued.ReissuedFrom)
+	require.Nil(t, dbReissued.ReissuedTo)
+
+	// The canceled original invoice can't be reissued again.
+	another, err := randInvoice(1000)
+	require.NoError(t, err)
+	another.ReissuedFrom = &originalHash
+	_, err = db.AddInvoice(another, another.Terms.PaymentPreimage.Hash())
+	require.ErrorIs(t, err, ErrInvoiceNotReissuable)
+}
+
 // TestAddDuplicateKeysendPayAddr asserts that we permit duplicate payment
 // addresses to be inserted if they are blank to support JIT legacy keysend
 // invoices.
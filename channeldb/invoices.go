@@ -150,6 +150,12 @@ var (
 	ErrHTLCPreimageAlreadyExists = errors.New(
 		"htlc-level preimage already exists",
 	)
+
+	// ErrInvoiceNotReissuable is returned when attempting to reissue an
+	// invoice that isn't open, still has HTLCs or is an AMP invoice.
+	ErrInvoiceNotReissuable = errors.New(
+		"only open non-AMP invoices without htlcs can be reissued",
+	)
 )
 
 // ErrDuplicateSetID is an error returned when attempting to adding an AMP HTLC
@@ -216,6 +222,8 @@ const (
 	amtPaidType         tlv.Type = 13
 	hodlInvoiceType     tlv.Type = 14
 	invoiceAmpStateType tlv.Type = 15
+	reissuedFromType    tlv.Type = 16
+	reissuedToType      tlv.Type = 17
 
 	// A set of tlv type definitions used to serialize the invoice AMP
 	// state along-side the main invoice body.
@@ -620,6 +628,14 @@ type Invoice struct {
 	// HodlInvoice indicates whether the invoice should be held in the
 	// Accepted state or be settled right away.
 	HodlInvoice bool
+
+	// ReissuedFrom is the payment hash of the invoice this invoice
+	// replaces, if it was created by reissuing an existing invoice.
+	ReissuedFrom *lntypes.Hash
+
+	// ReissuedTo is the payment hash of the invoice that replaced this
+	// invoice, if it was reissued. A reissued invoice is always canceled.
+	ReissuedTo *lntypes.Hash
 }
 
 // HTLCSet returns the set of HTLCs belonging to setID and in the provided
@@ -930,6 +946,10 @@ func (i *Invoice) IsPending() bool {
 // insertion will be aborted and rejected due to the strict policy banning any
 // duplicate payment hashes. A side effect of this function is that it sets
 // AddIndex on newInvoice.
+//
+// If ReissuedFrom is set on newInvoice, the invoice it refers to is canceled
+// and linked to the new invoice within the same transaction. Only open non-AMP
+// invoices without any HTLCs can be reissued.
 func (d *DB) AddInvoice(newInvoice *Invoice, paymentHash lntypes.Hash) (
 	uint64, error) {
 
@@ -963,6 +983,18 @@ func (d *DB) AddInvoice(newInvoice *Invoice, paymentHash lntypes.Hash) (
 			return ErrDuplicateInvoice
 		}
 
+		// If this invoice replaces an existing one, cancel the
+		// original and link it to the new invoice.
+		if newInvoice.ReissuedFrom != nil {
+			err := cancelReissuedInvoice(
+				invoices, invoiceIndex,
+				*newInvoice.ReissuedFrom, paymentHash,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Check that we aren't inserting an invoice with a duplicate
 		// payment address. The all-zeros payment address is
 		// special-cased to support legacy keysend invoices which don't
@@ -1010,6 +1042,43 @@ func (d *DB) AddInvoice(newInvoice *Invoice, paymentHash lntypes.Hash) (
 	return invoiceAddIndex, err
 }
 
+// cancelReissuedInvoice cancels the open invoice with the given payment hash
+// and records the payment hash of the invoice that replaces it.
+func cancelReissuedInvoice(invoices, invoiceIndex kvdb.RwBucket,
+	payHash, reissuedTo lntypes.Hash) error {
+
+	invoiceNum := invoiceIndex.Get(payHash[:])
+	if invoiceNum == nil {
+		return ErrInvoiceNotFound
+	}
+
+	invoice, err := fetchInvoice(invoiceNum, invoices)
+	if err != nil {
+		return err
+	}
+
+	isAMP := invoice.Terms.Features.HasFeature(lnwire.AMPOptional)
+	if invoice.State != ContractOpen || isAMP {
+		return ErrInvoiceNotReissuable
+	}
+
+	for _, htlc := range invoice.Htlcs {
+		if htlc.State != HtlcStateCanceled {
+			return ErrInvoiceNotReissuable
+		}
+	}
+
+	invoice.State = ContractCanceled
+	invoice.ReissuedTo = &reissuedTo
+
+	var buf bytes.Buffer
+	if err := serializeInvoice(&buf, &invoice); err != nil {
+		return err
+	}
+
+	return invoices.Put(invoiceNum, buf.Bytes())
+}
+
 // InvoicesAddedSince can be used by callers to seek into the event time series
 // of all the invoices added in the database. The specified sinceAddIndex
 // should be the highest add index that the caller knows of. This method will
@@ -1635,7 +1704,7 @@ func serializeInvoice(w io.Writer, i *Invoice) error {
 		hodlInvoice = 1
 	}
 
-	tlvStream, err := tlv.NewStream(
+	records := []tlv.Record{
 		// Memo and payreq.
 		tlv.MakePrimitiveRecord(memoType, &i.Memo),
 		tlv.MakePrimitiveRecord(payReqType, &i.PaymentRequest),
@@ -1666,7 +1735,24 @@ func serializeInvoice(w io.Writer, i *Invoice) error {
 			i.AMPState.recordSize,
 			ampStateEncoder, ampStateDecoder,
 		),
-	)
+	}
+
+	// The reissue links are only present for invoices that were reissued,
+	// so we only encode them if they're set.
+	if i.ReissuedFrom != nil {
+		reissuedFrom := [32]byte(*i.ReissuedFrom)
+		records = append(records, tlv.MakePrimitiveRecord(
+			reissuedFromType, &reissuedFrom,
+		))
+	}
+	if i.ReissuedTo != nil {
+		reissuedTo := [32]byte(*i.ReissuedTo)
+		records = append(records, tlv.MakePrimitiveRecord(
+			reissuedToType, &reissuedTo,
+		))
+	}
+
+	tlvStream, err := tlv.NewStream(records...)
 	if err != nil {
 		return err
 	}
@@ -2008,6 +2094,9 @@ func deserializeInvoice(r io.Reader) (Invoice, error) {
 		creationDateBytes []byte
 		settleDateBytes   []byte
 		featureBytes      []byte
+
+		reissuedFrom [32]byte
+		reissuedTo   [32]byte
 	)
 
 	var i Invoice
@@ -2042,6 +2131,10 @@ func deserializeInvoice(r io.Reader) (Invoice, error) {
 			invoiceAmpStateType, &i.AMPState, nil,
 			ampStateEncoder, ampStateDecoder,
 		),
+
+		// Reissue links.
+		tlv.MakePrimitiveRecord(reissuedFromType, &reissuedFrom),
+		tlv.MakePrimitiveRecord(reissuedToType, &reissuedTo),
 	)
 	if err != nil {
 		return i, err
@@ -2054,10 +2147,20 @@ func deserializeInvoice(r io.Reader) (Invoice, error) {
 	}
 
 	lr := io.LimitReader(r, bodyLen)
-	if err = tlvStream.Decode(lr); err != nil {
+	typeMap, err := tlvStream.DecodeWithParsedTypes(lr)
+	if err != nil {
 		return i, err
 	}
 
+	if _, ok := typeMap[reissuedFromType]; ok {
+		hash := lntypes.Hash(reissuedFrom)
+		i.ReissuedFrom = &hash
+	}
+	if _, ok := typeMap[reissuedToType]; ok {
+		hash := lntypes.Hash(reissuedTo)
+		i.ReissuedTo = &hash
+	}
+
 	preimage := lntypes.Preimage(preimageBytes)
 	if preimage != unknownPreimage {
 		i.Terms.PaymentPreimage = &preimage
@@ -2468,6 +2571,16 @@ func copyInvoice(src *Invoice) (*Invoice, error) {
 		dest.Terms.PaymentPreimage = &preimage
 	}
 
+	if src.ReissuedFrom != nil {
+		reissuedFrom := *src.ReissuedFrom
+		dest.ReissuedFrom = &reissuedFrom
+	}
+
+	if src.ReissuedTo != nil {
+		reissuedTo := *src.ReissuedTo
+		dest.ReissuedTo = &reissuedTo
+	}
+
 	for k, v := range src.Htlcs {
 		dest.Htlcs[k] = v.Copy()
 	}
@@ -50,10 +50,22 @@ type Options struct {
 	// wait before attempting to commit a pending set of updates.
 	BatchCommitInterval time.Duration
 
+	// GroupCommitInterval, if non-zero, enables group commit mode. In this
+	// mode non-critical updates, such as remote gossip and the forwarding
+	// log, are batched and committed to disk at most once per interval.
+	// Updates to channel state are always committed synchronously.
+	GroupCommitInterval time.Duration
+
 	// PreAllocCacheNumNodes is the number of nodes we expect to be in the
 	// graph cache, so we can pre-allocate the map accordingly.
 	PreAllocCacheNumNodes int
 
+	// GraphCacheMaxSize is the memory budget of the graph cache in bytes.
+	// Once the budget is exceeded, the least recently used nodes are
+	// evicted from the cache and loaded from the database on demand. A
+	// value of zero means the cache is unbounded.
+	GraphCacheMaxSize uint64
+
 	// UseGraphCache denotes whether the in-memory graph cache should be
 	// used or a fallback version that uses the underlying database for
 	// path finding.
@@ -119,6 +131,13 @@ func OptionSetPreAllocCacheNumNodes(n int) OptionModifier {
 	}
 }
 
+// OptionSetGraphCacheMaxSize sets the GraphCacheMaxSize to n bytes.
+func OptionSetGraphCacheMaxSize(n uint64) OptionModifier {
+	return func(o *Options) {
+		o.GraphCacheMaxSize = n
+	}
+}
+
 // OptionSetUseGraphCache sets the UseGraphCache option to the given value.
 func OptionSetUseGraphCache(use bool) OptionModifier {
 	return func(o *Options) {
@@ -156,6 +175,14 @@ func OptionSetBatchCommitInterval(interval time.Duration) OptionModifier {
 	}
 }
 
+// OptionSetGroupCommitInterval enables group commit mode with the given
+// interval. A zero interval disables group commit.
+func OptionSetGroupCommitInterval(interval time.Duration) OptionModifier {
+	return func(o *Options) {
+		o.GroupCommitInterval = interval
+	}
+}
+
 // OptionNoMigration allows the database to be opened in read only mode by
 // disabling migrations.
 func OptionNoMigration(b bool) OptionModifier {
@@ -0,0 +1,114 @@
+package channeldb
+
+import (
+	"encoding/binary"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// outputLabelBucket is a top level bucket which holds the labels of
+	// the outputs of our on-chain transactions. It nests transactions by
+	// the hash of the transaction without its input scripts, which in turn
+	// hold the labels of the outputs keyed by their index. Leaving out the
+	// input scripts makes the labels of a funded PSBT apply to the final
+	// transaction, whose txid differs if it spends nested segwit inputs.
+	// [outputLabelBucket]
+	//	[txBucket]
+	//		outputIndex -> label
+	outputLabelBucket = []byte("output-labels")
+)
+
+// outputLabelKey returns the key the output labels of the given transaction are
+// stored under, which is the hash of the transaction without its input
+// scripts.
+func outputLabelKey(tx *wire.MsgTx) chainhash.Hash {
+	stripped := tx.Copy()
+	for _, txIn := range stripped.TxIn {
+		txIn.SignatureScript = nil
+		txIn.Witness = nil
+	}
+
+	return stripped.TxHash()
+}
+
+// PutOutputLabels stores the given labels of the outputs of a transaction,
+// keyed by the index of the output. The transaction may be unsigned. Labels
+// that were stored for an output before are overwritten.
+func (c *ChannelStateDB) PutOutputLabels(tx *wire.MsgTx,
+	labels map[uint32]string) error {
+
+	txKey := outputLabelKey(tx)
+
+	return kvdb.Update(c.backend, func(dbTx kvdb.RwTx) error {
+		labelBucket, err := dbTx.CreateTopLevelBucket(outputLabelBucket)
+		if err != nil {
+			return err
+		}
+
+		txBucket, err := labelBucket.CreateBucketIfNotExists(txKey[:])
+		if err != nil {
+			return err
+		}
+
+		for index, label := range labels {
+			var key [4]byte
+			binary.BigEndian.PutUint32(key[:], index)
+
+			err = txBucket.Put(key[:], []byte(label))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {})
+}
+
+// FetchOutputLabels returns the labels of the outputs of the given
+// transactions within a single database transaction, keyed by the outpoint of
+// the output. Outputs without a label are omitted.
+func (c *ChannelStateDB) FetchOutputLabels(
+	txs ...*wire.MsgTx) (map[wire.OutPoint]string, error) {
+
+	var labels map[wire.OutPoint]string
+	err := kvdb.View(c.backend, func(dbTx kvdb.RTx) error {
+		labelBucket := dbTx.ReadBucket(outputLabelBucket)
+		if labelBucket == nil {
+			return nil
+		}
+
+		for _, tx := range txs {
+			txKey := outputLabelKey(tx)
+			txBucket := labelBucket.NestedReadBucket(txKey[:])
+			if txBucket == nil {
+				continue
+			}
+
+			txid := tx.TxHash()
+			err := txBucket.ForEach(func(k, v []byte) error {
+				op := wire.OutPoint{
+					Hash:  txid,
+					Index: binary.BigEndian.Uint32(k),
+				}
+				labels[op] = string(v)
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {
+		labels = make(map[wire.OutPoint]string)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
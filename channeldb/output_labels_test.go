@@ -0,0 +1,60 @@
+package channeldb
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOutputLabels tests that the labels of the outputs of an unsigned
+// transaction are returned for the signed transaction.
+func TestOutputLabels(t *testing.T) {
+	t.Parallel()
+
+	fullDB, cleanUp, err := MakeTestDB()
+	require.NoError(t, err, "unable to make test database")
+	defer cleanUp()
+
+	cdb := fullDB.ChannelStateDB()
+
+	unsignedTx := wire.NewMsgTx(2)
+	unsignedTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Hash: chainhash.Hash{1}},
+	})
+	unsignedTx.AddTxOut(&wire.TxOut{Value: 1000, PkScript: []byte{0}})
+	unsignedTx.AddTxOut(&wire.TxOut{Value: 2000, PkScript: []byte{1}})
+	unsignedTx.AddTxOut(&wire.TxOut{Value: 3000, PkScript: []byte{2}})
+
+	// Without any stored labels, nothing is returned.
+	labels, err := cdb.FetchOutputLabels(unsignedTx)
+	require.NoError(t, err)
+	require.Empty(t, labels)
+
+	err = cdb.PutOutputLabels(unsignedTx, map[uint32]string{
+		0: "first",
+		2: "third",
+	})
+	require.NoError(t, err)
+
+	// Signing a nested segwit input changes the txid, but the labels
+	// must still be found for the signed transaction.
+	signedTx := unsignedTx.Copy()
+	signedTx.TxIn[0].SignatureScript = []byte{0x16, 0x00, 0x14}
+	signedTx.TxIn[0].Witness = wire.TxWitness{{1}, {2}}
+	require.NotEqual(t, unsignedTx.TxHash(), signedTx.TxHash())
+
+	otherTx := wire.NewMsgTx(2)
+	otherTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Hash: chainhash.Hash{2}},
+	})
+	otherTx.AddTxOut(&wire.TxOut{Value: 1000, PkScript: []byte{0}})
+
+	labels, err = cdb.FetchOutputLabels(signedTx, otherTx)
+	require.NoError(t, err)
+	require.Equal(t, map[wire.OutPoint]string{
+		{Hash: signedTx.TxHash(), Index: 0}: "first",
+		{Hash: signedTx.TxHash(), Index: 2}: "third",
+	}, labels)
+}
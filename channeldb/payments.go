@@ -9,6 +9,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lntypes"
@@ -1183,6 +1184,25 @@ func serializeHop(w io.Writer, h *route.Hop) error {
 		records = append(records, record.NewMetadataRecord(&h.Metadata))
 	}
 
+	if h.EncryptedData != nil {
+		records = append(
+			records, record.NewEncryptedDataRecord(&h.EncryptedData),
+		)
+	}
+
+	if h.BlindingPoint != nil {
+		records = append(
+			records, record.NewBlindingPointRecord(&h.BlindingPoint),
+		)
+	}
+
+	if h.TotalAmtMsat != 0 {
+		totalAmt := uint64(h.TotalAmtMsat)
+		records = append(
+			records, record.NewTotalAmtMsatBlindedRecord(&totalAmt),
+		)
+	}
+
 	// Final sanity check to absolutely rule out custom records that are not
 	// custom and write into the standard range.
 	if err := h.CustomRecords.Validate(); err != nil {
@@ -1304,6 +1324,41 @@ func deserializeHop(r io.Reader) (*route.Hop, error) {
 		h.Metadata = metadata
 	}
 
+	encryptedDataType := uint64(record.EncryptedDataOnionType)
+	if encryptedData, ok := tlvMap[encryptedDataType]; ok {
+		delete(tlvMap, encryptedDataType)
+
+		h.EncryptedData = encryptedData
+	}
+
+	blindingPointType := uint64(record.BlindingPointOnionType)
+	if blindingPoint, ok := tlvMap[blindingPointType]; ok {
+		delete(tlvMap, blindingPointType)
+
+		h.BlindingPoint, err = btcec.ParsePubKey(blindingPoint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	totalAmtType := uint64(record.TotalAmtMsatBlindedType)
+	if totalAmtBytes, ok := tlvMap[totalAmtType]; ok {
+		delete(tlvMap, totalAmtType)
+
+		var (
+			totalAmt    uint64
+			totalAmtRec = record.NewTotalAmtMsatBlindedRecord(
+				&totalAmt,
+			)
+			r = bytes.NewReader(totalAmtBytes)
+		)
+		err := totalAmtRec.Decode(r, uint64(len(totalAmtBytes)))
+		if err != nil {
+			return nil, err
+		}
+		h.TotalAmtMsat = lnwire.MilliSatoshi(totalAmt)
+	}
+
 	h.CustomRecords = tlvMap
 
 	return h, nil
@@ -163,6 +163,55 @@ func TestRouteSerialization(t *testing.T) {
 	}
 }
 
+// TestBlindedRouteSerialization tests that the blinded route fields of the
+// hops of a route survive a serialization round trip.
+func TestBlindedRouteSerialization(t *testing.T) {
+	t.Parallel()
+
+	blindedRoute := route.Route{
+		TotalTimeLock: 123,
+		TotalAmount:   1234567,
+		SourcePubKey:  route.NewVertex(pub),
+		Hops: []*route.Hop{
+			{
+				PubKeyBytes:      route.NewVertex(pub),
+				ChannelID:        12345,
+				OutgoingTimeLock: 111,
+				AmtToForward:     555,
+				EncryptedData:    []byte{1, 2, 3},
+				BlindingPoint:    pub,
+			},
+			{
+				PubKeyBytes:      route.NewVertex(pub),
+				ChannelID:        1,
+				OutgoingTimeLock: 111,
+				AmtToForward:     555,
+				EncryptedData:    []byte{4, 5, 6},
+				TotalAmtMsat:     1000,
+			},
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, SerializeRoute(&b, blindedRoute))
+
+	route2, err := DeserializeRoute(&b)
+	require.NoError(t, err)
+	require.Len(t, route2.Hops, 2)
+
+	intro := route2.Hops[0]
+	require.Equal(t, []byte{1, 2, 3}, intro.EncryptedData)
+	require.True(t, pub.IsEqual(intro.BlindingPoint))
+	require.Zero(t, intro.TotalAmtMsat)
+	require.Empty(t, intro.CustomRecords)
+
+	final := route2.Hops[1]
+	require.Equal(t, []byte{4, 5, 6}, final.EncryptedData)
+	require.Nil(t, final.BlindingPoint)
+	require.EqualValues(t, 1000, final.TotalAmtMsat)
+	require.Empty(t, final.CustomRecords)
+}
+
 // deletePayment removes a payment with paymentHash from the payments database.
 func deletePayment(t *testing.T, db *DB, paymentHash lntypes.Hash, seqNr uint64) {
 	t.Helper()
@@ -718,3 +767,156 @@ func putDuplicatePayment(t *testing.T, duplicateBucket kvdb.RwBucket,
 	err = paymentBucket.Put(duplicatePaymentSettleInfoKey, preImg[:])
 	require.NoError(t, err)
 }
+
+// failDuplicatePayment turns the duplicate payment with the given sequence
+// number into a failed one.
+func failDuplicatePayment(t *testing.T, db *DB, paymentHash lntypes.Hash,
+	seqNr uint64) {
+
+	err := kvdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		bucket, err := fetchPaymentBucketUpdate(tx, paymentHash)
+		if err != nil {
+			return err
+		}
+
+		var sequenceKey [8]byte
+		byteOrder.PutUint64(sequenceKey[:], seqNr)
+
+		dup := bucket.NestedReadWriteBucket(
+			duplicatePaymentsBucket,
+		).NestedReadWriteBucket(sequenceKey[:])
+
+		err = dup.Delete(duplicatePaymentSettleInfoKey)
+		if err != nil {
+			return err
+		}
+
+		return dup.Put(
+			duplicatePaymentFailInfoKey,
+			[]byte{byte(FailureReasonNoRoute)},
+		)
+	}, func() {})
+	require.NoError(t, err)
+}
+
+// TestCleanupDuplicatePayments tests that the cleanup of legacy duplicate
+// payments removes failed duplicates and dangling index entries, keeps
+// succeeded duplicates, and doesn't touch the database in a dry run.
+func TestCleanupDuplicatePayments(t *testing.T) {
+	t.Parallel()
+
+	db, cleanup, err := MakeTestDB()
+	require.NoError(t, err)
+	defer cleanup()
+
+	pControl := NewPaymentControl(db)
+
+	// Create two payments, the first with a succeeded and a failed
+	// duplicate, the second with a failed duplicate only.
+	var (
+		infos   []*PaymentCreationInfo
+		preimgs []lntypes.Preimage
+	)
+	for i := 0; i < 2; i++ {
+		info, _, preimg, err := genInfo()
+		require.NoError(t, err)
+
+		err = pControl.InitPayment(info.PaymentIdentifier, info)
+		require.NoError(t, err)
+
+		infos = append(infos, info)
+		preimgs = append(preimgs, preimg)
+	}
+
+	hash0, hash1 := infos[0].PaymentIdentifier, infos[1].PaymentIdentifier
+
+	appendDuplicatePayment(t, db, hash0, 100, preimgs[0])
+	appendDuplicatePayment(t, db, hash0, 101, preimgs[0])
+	failDuplicatePayment(t, db, hash0, 101)
+
+	appendDuplicatePayment(t, db, hash1, 102, preimgs[1])
+	failDuplicatePayment(t, db, hash1, 102)
+
+	// Add an index entry for a payment that doesn't exist.
+	err = kvdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		var sequenceKey [8]byte
+		byteOrder.PutUint64(sequenceKey[:], 200)
+
+		return createPaymentIndexEntry(
+			tx, sequenceKey[:], lntypes.Hash{1},
+		)
+	}, func() {})
+	require.NoError(t, err)
+
+	payments, err := db.FetchPayments()
+	require.NoError(t, err)
+	require.Len(t, payments, 5)
+
+	expectedReport := &DuplicatePaymentsReport{
+		NumPaymentsWithDuplicates: 2,
+		NumDuplicates:             3,
+		NumDuplicatesDeleted:      2,
+		NumDuplicatesKept:         1,
+		NumDanglingIndexes:        1,
+	}
+
+	// A dry run reports what would be deleted, but doesn't delete
+	// anything.
+	report, err := db.CleanupDuplicatePayments(true)
+	require.NoError(t, err)
+	require.NotZero(t, report.ReclaimedBytes)
+
+	reclaimedBytes := report.ReclaimedBytes
+	report.ReclaimedBytes = 0
+	require.Equal(t, expectedReport, report)
+
+	payments, err = db.FetchPayments()
+	require.NoError(t, err)
+	require.Len(t, payments, 5)
+
+	// Running the cleanup for real deletes the failed duplicates and
+	// reports the same numbers.
+	report, err = db.CleanupDuplicatePayments(false)
+	require.NoError(t, err)
+	expectedReport.ReclaimedBytes = reclaimedBytes
+	require.Equal(t, expectedReport, report)
+
+	payments, err = db.FetchPayments()
+	require.NoError(t, err)
+	require.Len(t, payments, 3)
+
+	seqNrs := make([]uint64, 0, len(payments))
+	for _, p := range payments {
+		seqNrs = append(seqNrs, p.SequenceNum)
+	}
+	require.Contains(t, seqNrs, uint64(100))
+	require.NotContains(t, seqNrs, uint64(101))
+	require.NotContains(t, seqNrs, uint64(102))
+
+	// The second payment has no duplicates left, so its duplicates bucket
+	// is gone, and all remaining index entries point to payments.
+	err = kvdb.View(db, func(tx walletdb.ReadTx) error {
+		bucket, err := fetchPaymentBucket(tx, hash1)
+		require.NoError(t, err)
+		require.Nil(t, bucket.NestedReadBucket(duplicatePaymentsBucket))
+
+		indexes := tx.ReadBucket(paymentsIndexBucket)
+		return indexes.ForEach(func(k, _ []byte) error {
+			require.NotEqual(t, uint64(101), byteOrder.Uint64(k))
+			require.NotEqual(t, uint64(102), byteOrder.Uint64(k))
+			require.NotEqual(t, uint64(200), byteOrder.Uint64(k))
+
+			return nil
+		})
+	}, func() {})
+	require.NoError(t, err)
+
+	// A second run has nothing left to delete.
+	report, err = db.CleanupDuplicatePayments(false)
+	require.NoError(t, err)
+	require.Equal(t, &DuplicatePaymentsReport{
+		NumPaymentsWithDuplicates: 1,
+		NumDuplicates:             1,
+		NumDuplicatesKept:         1,
+	}, report)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+var drainSwitchCommand = cli.Command{
+	Name:     "drainswitch",
+	Category: "Payments",
+	Usage:    "Stop forwarding new HTLCs to prepare for a restart.",
+	Description: `
+	Put the switch into drain mode. While draining, all new forwards are
+	failed back with a temporary channel failure, while the HTLCs already
+	in flight are resolved as usual. The number of HTLCs still in flight is
+	reported, so that the node can be restarted once it dropped to zero.
+
+	With the --wait flag, the command blocks until no HTLCs are in flight
+	anymore. Drain mode is left with the --stop flag or by restarting the
+	node.`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "stop",
+			Usage: "leave drain mode and accept new forwards " +
+				"again",
+		},
+		cli.BoolFlag{
+			Name: "wait",
+			Usage: "block until no HTLCs are in flight through the " +
+				"switch anymore",
+		},
+	},
+	Action: actionDecorator(drainSwitch),
+}
+
+func drainSwitch(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	req := &routerrpc.DrainSwitchRequest{
+		Stop: ctx.Bool("stop"),
+		Wait: ctx.Bool("wait"),
+	}
+
+	client := routerrpc.NewRouterClient(conn)
+	resp, err := client.DrainSwitch(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
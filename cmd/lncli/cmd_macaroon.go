@@ -8,11 +8,14 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/golang/protobuf/proto"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/urfave/cli"
 	"gopkg.in/macaroon-bakery.v2/bakery"
@@ -29,6 +32,17 @@ var (
 		Name:  "ip_address",
 		Usage: "the IP address the macaroon will be bound to",
 	}
+	macSpendLimitFlag = cli.Uint64Flag{
+		Name: "spend_limit_sat",
+		Usage: "the maximum amount in satoshis, including fees, " +
+			"the macaroon can spend in Lightning payments " +
+			"within the spend limit period",
+	}
+	macSpendLimitPeriodFlag = cli.DurationFlag{
+		Name:  "spend_limit_period",
+		Usage: "the period the spend limit applies to",
+		Value: 24 * time.Hour,
+	}
 	macCustomCaveatNameFlag = cli.StringFlag{
 		Name:  "custom_caveat_name",
 		Usage: "the name of the custom caveat to add",
@@ -46,12 +60,13 @@ var bakeMacaroonCommand = cli.Command{
 	Usage: "Bakes a new macaroon with the provided list of permissions " +
 		"and restrictions.",
 	ArgsUsage: "[--save_to=] [--timeout=] [--ip_address=] " +
+		"[--spend_limit_sat= [--spend_limit_period=]] " +
 		"[--custom_caveat_name= [--custom_caveat_condition=]] " +
 		"[--root_key_id=] [--allow_external_permissions] " +
 		"permissions...",
 	Description: `
 	Bake a new macaroon that grants the provided permissions and
-	optionally adds restrictions (timeout, IP address) to it.
+	optionally adds restrictions (timeout, IP address, spend limit) to it.
 
 	The new macaroon can either be shown on command line in hex serialized
 	format or it can be saved directly to a file using the --save_to
@@ -83,6 +98,8 @@ var bakeMacaroonCommand = cli.Command{
 		},
 		macTimeoutFlag,
 		macIPAddressFlag,
+		macSpendLimitFlag,
+		macSpendLimitPeriodFlag,
 		macCustomCaveatNameFlag,
 		macCustomCaveatConditionFlag,
 		cli.Uint64Flag{
@@ -415,7 +432,8 @@ var constrainMacaroonCommand = cli.Command{
 	Name:     "constrainmacaroon",
 	Category: "Macaroons",
 	Usage:    "Adds one or more restriction(s) to an existing macaroon",
-	ArgsUsage: "[--timeout=] [--ip_address=] [--custom_caveat_name= " +
+	ArgsUsage: "[--timeout=] [--ip_address=] [--spend_limit_sat= " +
+		"[--spend_limit_period=]] [--custom_caveat_name= " +
 		"[--custom_caveat_condition=]] input-macaroon-file " +
 		"constrained-macaroon-file",
 	Description: `
@@ -425,6 +443,8 @@ var constrainMacaroonCommand = cli.Command{
 	Flags: []cli.Flag{
 		macTimeoutFlag,
 		macIPAddressFlag,
+		macSpendLimitFlag,
+		macSpendLimitPeriodFlag,
 		macCustomCaveatNameFlag,
 		macCustomCaveatConditionFlag,
 	},
@@ -512,6 +532,22 @@ func applyMacaroonConstraints(ctx *cli.Context,
 		)
 	}
 
+	if ctx.IsSet(macSpendLimitFlag.Name) {
+		maxAmt := lnwire.NewMSatFromSatoshis(
+			btcutil.Amount(ctx.Uint64(macSpendLimitFlag.Name)),
+		)
+		period := ctx.Duration(macSpendLimitPeriodFlag.Name)
+		if period < time.Second {
+			return nil, fmt.Errorf("spend_limit_period must be " +
+				"at least one second")
+		}
+
+		macConstraints = append(
+			macConstraints,
+			macaroons.SpendLimitConstraint(maxAmt, period),
+		)
+	}
+
 	if ctx.IsSet(macCustomCaveatNameFlag.Name) {
 		customCaveatName := ctx.String(macCustomCaveatNameFlag.Name)
 		if containsWhiteSpace(customCaveatName) {
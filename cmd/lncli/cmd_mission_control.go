@@ -2,11 +2,14 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 	"strconv"
 
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/urfave/cli"
 )
@@ -45,34 +48,79 @@ var setCfgCommand = cli.Command{
 	Category: "Mission Control",
 	Usage:    "Set mission control's config.",
 	Description: `
-	Update the config values being used by mission control to calculate 
-	the probability that payment routes will succeed.
-	`,
+	Update the config values being used by mission control to calculate the
+	probability that payment routes will succeed. The estimator type must be
+	provided to set estimator-related parameters.`,
 	Flags: []cli.Flag{
+		// General settings.
+		cli.UintFlag{
+			Name: "pmtnr",
+			Usage: "the number of payments mission control " +
+				"should store",
+		},
+		cli.DurationFlag{
+			Name: "failrelax",
+			Usage: "the amount of time to wait after a failure " +
+				"before raising failure amount",
+		},
+		// Probability estimator.
+		cli.StringFlag{
+			Name: "estimator",
+			Usage: "the probability estimator to use, choose " +
+				"between 'apriori' or 'bimodal'",
+		},
+		// Apriori config.
 		cli.DurationFlag{
-			Name: "halflife",
+			Name: "apriorihalflife",
 			Usage: "the amount of time taken to restore a node " +
 				"or channel to 50% probability of success.",
 		},
 		cli.Float64Flag{
-			Name: "hopprob",
+			Name: "apriorihopprob",
 			Usage: "the probability of success assigned " +
 				"to hops that we have no information about",
 		},
 		cli.Float64Flag{
-			Name: "weight",
+			Name: "aprioriweight",
 			Usage: "the degree to which mission control should " +
 				"rely on historical results, expressed as " +
-				"value in [0;1]",
-		}, cli.UintFlag{
-			Name: "pmtnr",
-			Usage: "the number of payments mission control " +
-				"should store",
+				"value in [0, 1]",
 		},
+		// Bimodal config.
 		cli.DurationFlag{
-			Name: "failrelax",
-			Usage: "the amount of time to wait after a failure " +
-				"before raising failure amount",
+			Name: "bimodaldecaytime",
+			Usage: "the time span after which we phase out " +
+				"learnings from previous payment attempts",
+		},
+		cli.Uint64Flag{
+			Name: "bimodalscale",
+			Usage: "controls the assumed channel liquidity " +
+				"imbalance in the network, measured in msat. " +
+				"a low value (compared to typical channel " +
+				"capacity) anticipates unbalanced channels.",
+		},
+		cli.Float64Flag{
+			Name: "bimodalweight",
+			Usage: "controls the degree to which the probability " +
+				"estimator takes into account other channels " +
+				"of a router",
+		},
+		// Deprecated apriori flags, kept for backwards
+		// compatibility.
+		cli.DurationFlag{
+			Name:   "halflife",
+			Usage:  "deprecated, use apriorihalflife",
+			Hidden: true,
+		},
+		cli.Float64Flag{
+			Name:   "hopprob",
+			Usage:  "deprecated, use apriorihopprob",
+			Hidden: true,
+		},
+		cli.Float64Flag{
+			Name:   "weight",
+			Usage:  "deprecated, use aprioriweight",
+			Hidden: true,
 		},
 	},
 	Action: actionDecorator(setCfg),
@@ -85,42 +133,107 @@ func setCfg(ctx *cli.Context) error {
 
 	client := routerrpc.NewRouterClient(conn)
 
-	resp, err := client.GetMissionControlConfig(
+	// Fetch current mission control config which we update to create our
+	// response.
+	mcCfg, err := client.GetMissionControlConfig(
 		ctxc, &routerrpc.GetMissionControlConfigRequest{},
 	)
 	if err != nil {
 		return err
 	}
 
-	var haveValue bool
+	// haveValue is a helper variable to determine if a flag has been set or
+	// the help should be displayed.
+	haveValue := false
 
-	if ctx.IsSet("halflife") {
+	// Handle general mission control settings.
+	if ctx.IsSet("pmtnr") {
 		haveValue = true
-		resp.Config.HalfLifeSeconds = uint64(ctx.Duration(
-			"halflife",
-		).Seconds())
+		mcCfg.Config.MaximumPaymentResults = uint32(ctx.Int("pmtnr"))
 	}
-
-	if ctx.IsSet("hopprob") {
+	if ctx.IsSet("failrelax") {
 		haveValue = true
-		resp.Config.HopProbability = float32(ctx.Float64("hopprob"))
+		mcCfg.Config.MinimumFailureRelaxInterval = uint64(ctx.Duration(
+			"failrelax",
+		).Seconds())
 	}
 
-	if ctx.IsSet("weight") {
+	// We switch between estimators and set corresponding configs. If
+	// estimator is not set, we ignore the values.
+	if ctx.IsSet("estimator") {
 		haveValue = true
-		resp.Config.Weight = float32(ctx.Float64("weight"))
-	}
 
-	if ctx.IsSet("pmtnr") {
-		haveValue = true
-		resp.Config.MaximumPaymentResults = uint32(ctx.Int("pmtnr"))
+		err := switchEstimator(mcCfg.Config, ctx.String("estimator"))
+		if err != nil {
+			return err
+		}
 	}
 
-	if ctx.IsSet("failrelax") {
-		haveValue = true
-		resp.Config.MinimumFailureRelaxInterval = uint64(ctx.Duration(
-			"failrelax",
-		).Seconds())
+	switch mcCfg.Config.Model {
+	// Apriori config.
+	case routerrpc.MissionControlConfig_APRIORI:
+		// The node may not populate the apriori parameters yet, in
+		// which case we start out from the deprecated fields.
+		if mcCfg.Config.GetApriori() == nil {
+			mcCfg.Config.EstimatorConfig = aprioriParams(
+				mcCfg.Config.HalfLifeSeconds,
+				float64(mcCfg.Config.HopProbability),
+				float64(mcCfg.Config.Weight),
+			)
+		}
+		aCfg := mcCfg.Config.GetApriori()
+
+		for _, name := range []string{"apriorihalflife", "halflife"} {
+			if ctx.IsSet(name) {
+				haveValue = true
+				aCfg.HalfLifeSeconds = uint64(ctx.Duration(
+					name,
+				).Seconds())
+			}
+		}
+
+		for _, name := range []string{"apriorihopprob", "hopprob"} {
+			if ctx.IsSet(name) {
+				haveValue = true
+				aCfg.HopProbability = ctx.Float64(name)
+			}
+		}
+
+		for _, name := range []string{"aprioriweight", "weight"} {
+			if ctx.IsSet(name) {
+				haveValue = true
+				aCfg.Weight = ctx.Float64(name)
+			}
+		}
+
+		// Keep the deprecated fields in sync for older nodes.
+		mcCfg.Config.HalfLifeSeconds = aCfg.HalfLifeSeconds
+		mcCfg.Config.HopProbability = float32(aCfg.HopProbability)
+		mcCfg.Config.Weight = float32(aCfg.Weight)
+
+	// Bimodal config.
+	case routerrpc.MissionControlConfig_BIMODAL:
+		bCfg := mcCfg.Config.GetBimodal()
+		if bCfg == nil {
+			return fmt.Errorf("bimodal estimator config missing")
+		}
+
+		if ctx.IsSet("bimodalweight") {
+			haveValue = true
+			bCfg.NodeWeight = ctx.Float64("bimodalweight")
+		}
+
+		if ctx.IsSet("bimodalscale") {
+			haveValue = true
+			bCfg.ScaleMsat = ctx.Uint64("bimodalscale")
+		}
+
+		if ctx.IsSet("bimodaldecaytime") {
+			haveValue = true
+			bCfg.DecayTime = uint64(ctx.Duration(
+				"bimodaldecaytime",
+			).Seconds())
+		}
 	}
 
 	if !haveValue {
@@ -129,12 +242,67 @@ func setCfg(ctx *cli.Context) error {
 
 	_, err = client.SetMissionControlConfig(
 		ctxc, &routerrpc.SetMissionControlConfigRequest{
-			Config: resp.Config,
+			Config: mcCfg.Config,
 		},
 	)
 	return err
 }
 
+// switchEstimator selects the estimator with the given name in the mission
+// control config. If the estimator isn't the current one, its parameters are
+// initialized with the default values.
+func switchEstimator(cfg *routerrpc.MissionControlConfig, name string) error {
+	switch name {
+	case routing.AprioriEstimatorName:
+		if cfg.Model == routerrpc.MissionControlConfig_APRIORI {
+			return nil
+		}
+
+		dCfg := routing.DefaultAprioriConfig()
+		cfg.Model = routerrpc.MissionControlConfig_APRIORI
+		cfg.EstimatorConfig = aprioriParams(
+			uint64(dCfg.PenaltyHalfLife.Seconds()),
+			dCfg.AprioriHopProbability, dCfg.AprioriWeight,
+		)
+
+	case routing.BimodalEstimatorName:
+		if cfg.Model == routerrpc.MissionControlConfig_BIMODAL {
+			return nil
+		}
+
+		dCfg := routing.DefaultBimodalConfig()
+		cfg.Model = routerrpc.MissionControlConfig_BIMODAL
+		cfg.EstimatorConfig = &routerrpc.MissionControlConfig_Bimodal{
+			Bimodal: &routerrpc.BimodalParameters{
+				ScaleMsat:  uint64(dCfg.BimodalScaleMsat),
+				NodeWeight: dCfg.BimodalNodeWeight,
+				DecayTime: uint64(
+					dCfg.BimodalDecayTime.Seconds(),
+				),
+			},
+		}
+
+	default:
+		return fmt.Errorf("unknown estimator %v", name)
+	}
+
+	return nil
+}
+
+// aprioriParams returns the apriori estimator config with the given
+// parameters.
+func aprioriParams(halfLifeSeconds uint64, hopProbability,
+	weight float64) *routerrpc.MissionControlConfig_Apriori {
+
+	return &routerrpc.MissionControlConfig_Apriori{
+		Apriori: &routerrpc.AprioriParameters{
+			HalfLifeSeconds: halfLifeSeconds,
+			HopProbability:  hopProbability,
+			Weight:          weight,
+		},
+	}
+}
+
 var queryMissionControlCommand = cli.Command{
 	Name:     "querymc",
 	Category: "Mission Control",
@@ -160,6 +328,116 @@ func queryMissionControl(ctx *cli.Context) error {
 	return nil
 }
 
+var exportMissionControlCommand = cli.Command{
+	Name:     "exportmc",
+	Category: "Mission Control",
+	Usage: "Export the internal mission control state to a file, so " +
+		"that it can be imported by other nodes.",
+	ArgsUsage: "file",
+	Description: `
+	Export the mission control state to the given file in a versioned
+	binary format that can be imported by other nodes with importmcfile.
+
+	If --anonymize is set, the pairs involving our own node are left out
+	and the timestamps are truncated to the hour, so that the export can be
+	shared without revealing our node.
+	`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "anonymize",
+			Usage: "leave out the pairs involving our own node and " +
+				"truncate the timestamps to the hour",
+		},
+	},
+	Action: actionDecorator(exportMissionControl),
+}
+
+func exportMissionControl(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "exportmc")
+	}
+
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+
+	req := &routerrpc.ExportMissionControlRequest{
+		Anonymize: ctx.Bool("anonymize"),
+	}
+	resp, err := client.ExportMissionControl(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	path := lncfg.CleanAndExpandPath(ctx.Args().First())
+	if err := ioutil.WriteFile(path, resp.Data, 0644); err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %d pairs (format version %d)\n", resp.NumPairs,
+		resp.Version)
+
+	return nil
+}
+
+var importMissionControlFileCommand = cli.Command{
+	Name:     "importmcfile",
+	Category: "Mission Control",
+	Usage: "Import mission control state from a file that was exported " +
+		"with exportmc.",
+	ArgsUsage: "file",
+	Description: `
+	Import the mission control state from the given file, that was
+	exported by this or another node with exportmc. Only results that are
+	fresher than our existing state are used, unless --force is set.
+
+	The import is only applied in-memory, and will not be persisted across
+	restarts.
+	`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "force",
+			Usage: "whether to force the import of older results",
+		},
+	},
+	Action: actionDecorator(importMissionControlFile),
+}
+
+func importMissionControlFile(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "importmcfile")
+	}
+
+	path := lncfg.CleanAndExpandPath(ctx.Args().First())
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+
+	req := &routerrpc.ImportMissionControlRequest{
+		Data:  data,
+		Force: ctx.Bool("force"),
+	}
+	resp, err := client.ImportMissionControl(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var queryProbCommand = cli.Command{
 	Name:      "queryprob",
 	Category:  "Mission Control",
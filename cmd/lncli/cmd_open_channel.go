@@ -220,6 +220,35 @@ var openChannelCommand = cli.Command{
 			Usage: "(optional) whether a scid-alias channel type" +
 				" should be negotiated.",
 		},
+		cli.Uint64Flag{
+			Name: "remote_chan_reserve_sat",
+			Usage: "(optional) the channel reserve in satoshis we " +
+				"require the remote peer to keep. If this is " +
+				"not set, 1% of the channel capacity is required",
+		},
+		cli.BoolFlag{
+			Name: "zero_reserve",
+			Usage: "(optional) whether the remote peer should not " +
+				"be required to keep a channel reserve. Only " +
+				"allowed for peers configured with " +
+				"zeroreserve.peer. WARNING: the remote peer " +
+				"can then broadcast a revoked state without " +
+				"risking any of its funds",
+		},
+		cli.Uint64Flag{
+			Name: "request_funds_sat",
+			Usage: "(optional) the amount in satoshis the remote " +
+				"peer is asked to contribute to the channel " +
+				"for a lease fee. The channel is only opened " +
+				"if the quoted lease fee doesn't exceed " +
+				"max_lease_fee_sat",
+		},
+		cli.Uint64Flag{
+			Name: "max_lease_fee_sat",
+			Usage: "(optional) the largest lease fee in satoshis " +
+				"we accept for the funds requested through " +
+				"request_funds_sat",
+		},
 	},
 	Action: actionDecorator(openChannel),
 }
@@ -261,6 +290,10 @@ func openChannel(ctx *cli.Context) error {
 		MaxLocalCsv:                uint32(ctx.Uint64("max_local_csv")),
 		ZeroConf:                   ctx.Bool("zero_conf"),
 		ScidAlias:                  ctx.Bool("scid_alias"),
+		RemoteChanReserveSat:       ctx.Uint64("remote_chan_reserve_sat"),
+		ZeroReserve:                ctx.Bool("zero_reserve"),
+		RequestFundsSat:            ctx.Uint64("request_funds_sat"),
+		MaxLeaseFeeSat:             ctx.Uint64("max_lease_fee_sat"),
 	}
 
 	switch {
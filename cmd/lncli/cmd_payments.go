@@ -69,6 +69,12 @@ var (
 		Value: routerrpc.DefaultMaxParts,
 	}
 
+	maxParallelismFlag = cli.UintFlag{
+		Name: "max_parallelism",
+		Usage: "the maximum number of partial payments that may be " +
+			"in flight at the same time, defaults to max_parts",
+	}
+
 	jsonFlag = cli.BoolFlag{
 		Name: "json",
 		Usage: "if set, payment updates are printed as json " +
@@ -90,6 +96,21 @@ var (
 			"specified in milli-satoshis",
 	}
 
+	minShardSizeMsatFlag = cli.UintFlag{
+		Name: "min_shard_size_msat",
+		Usage: "the smallest payment split that should be attempted " +
+			"if payment splitting is required to attempt a " +
+			"payment, specified in milli-satoshis",
+	}
+
+	splitStrategyFlag = cli.StringFlag{
+		Name: "split_strategy",
+		Usage: "how to split the payment if no route is found for " +
+			"the full amount; valid values are 'adaptive', " +
+			"'equal' and 'largest_channel'",
+		Value: "adaptive",
+	}
+
 	ampFlag = cli.BoolFlag{
 		Name: "amp",
 		Usage: "if set to true, then AMP will be used to complete the " +
@@ -100,6 +121,34 @@ var (
 		Name:  "time_pref",
 		Usage: "(optional) expresses time preference (range -1 to 1)",
 	}
+
+	ignoreNodeFlag = cli.StringSliceFlag{
+		Name: "ignore_node",
+		Usage: "(optional) pubkey of a node that must not be used " +
+			"as an intermediate hop, can be specified multiple " +
+			"times",
+	}
+
+	allowNodeFlag = cli.StringSliceFlag{
+		Name: "allow_node",
+		Usage: "(optional) pubkey of a node that may be used as an " +
+			"intermediate hop, can be specified multiple times. " +
+			"If set, no other nodes are used as intermediate hops",
+	}
+
+	ignoreChanIDFlag = cli.StringSliceFlag{
+		Name: "ignore_chan_id",
+		Usage: "(optional) short channel id of a channel that must " +
+			"not be used, can be specified multiple times",
+	}
+
+	allowChanIDFlag = cli.StringSliceFlag{
+		Name: "allow_chan_id",
+		Usage: "(optional) short channel id of a channel that may " +
+			"be used, can be specified multiple times. If set, " +
+			"no other channels are used, including our own and " +
+			"those of route hints",
+	}
 )
 
 // paymentFlags returns common flags for sendpayment and payinvoice.
@@ -143,9 +192,18 @@ func paymentFlags() []cli.Flag {
 			Name:  "allow_self_payment",
 			Usage: "allow sending a circular payment to self",
 		},
+		cli.StringFlag{
+			Name: "self_payment_policy",
+			Usage: "how to handle a payment that routes back to " +
+				"this node; valid values are 'default', " +
+				"'reject' and 'circular'",
+			Value: "default",
+		},
 		dataFlag, inflightUpdatesFlag, maxPartsFlag, jsonFlag,
 		maxShardSizeSatFlag, maxShardSizeMsatFlag, ampFlag,
-		timePrefFlag,
+		timePrefFlag, maxParallelismFlag, ignoreNodeFlag, allowNodeFlag,
+		ignoreChanIDFlag, allowChanIDFlag, minShardSizeMsatFlag,
+		splitStrategyFlag,
 	}
 }
 
@@ -407,6 +465,36 @@ func sendPayment(ctx *cli.Context) error {
 	return sendPaymentRequest(ctx, req)
 }
 
+// parseNodeList parses a list of hex encoded node pubkeys.
+func parseNodeList(pubKeys []string) ([][]byte, error) {
+	nodes := make([][]byte, 0, len(pubKeys))
+	for _, pubKey := range pubKeys {
+		node, err := route.NewVertexFromStr(pubKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node pubkey %v: %v",
+				pubKey, err)
+		}
+		nodes = append(nodes, node[:])
+	}
+
+	return nodes, nil
+}
+
+// parseChanIDList parses a list of short channel ids.
+func parseChanIDList(chanIDs []string) ([]uint64, error) {
+	ids := make([]uint64, 0, len(chanIDs))
+	for _, chanID := range chanIDs {
+		id, err := strconv.ParseUint(chanID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid short channel id "+
+				"%v: %v", chanID, err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
 func sendPaymentRequest(ctx *cli.Context,
 	req *routerrpc.SendPaymentRequest) error {
 
@@ -432,6 +520,35 @@ func sendPaymentRequest(ctx *cli.Context,
 		req.LastHopPubkey = lastHop[:]
 	}
 
+	var err error
+	req.IgnoredNodes, err = parseNodeList(
+		ctx.StringSlice(ignoreNodeFlag.Name),
+	)
+	if err != nil {
+		return err
+	}
+
+	req.AllowedNodes, err = parseNodeList(
+		ctx.StringSlice(allowNodeFlag.Name),
+	)
+	if err != nil {
+		return err
+	}
+
+	req.IgnoredChanIds, err = parseChanIDList(
+		ctx.StringSlice(ignoreChanIDFlag.Name),
+	)
+	if err != nil {
+		return err
+	}
+
+	req.AllowedChanIds, err = parseChanIDList(
+		ctx.StringSlice(allowChanIDFlag.Name),
+	)
+	if err != nil {
+		return err
+	}
+
 	req.CltvLimit = int32(ctx.Int(cltvLimitFlag.Name))
 
 	pmtTimeout := ctx.Duration("timeout")
@@ -442,7 +559,26 @@ func sendPaymentRequest(ctx *cli.Context,
 
 	req.AllowSelfPayment = ctx.Bool("allow_self_payment")
 
+	switch ctx.String("self_payment_policy") {
+	case "default":
+		req.SelfPaymentPolicy = routerrpc.
+			SelfPaymentPolicy_SELF_PAYMENT_DEFAULT
+
+	case "reject":
+		req.SelfPaymentPolicy = routerrpc.
+			SelfPaymentPolicy_SELF_PAYMENT_REJECT
+
+	case "circular":
+		req.SelfPaymentPolicy = routerrpc.
+			SelfPaymentPolicy_SELF_PAYMENT_CIRCULAR
+
+	default:
+		return fmt.Errorf("unknown self payment policy: %v",
+			ctx.String("self_payment_policy"))
+	}
+
 	req.MaxParts = uint32(ctx.Uint(maxPartsFlag.Name))
+	req.MaxParallelism = uint32(ctx.Uint(maxParallelismFlag.Name))
 
 	switch {
 	// If the max shard size is specified, then it should either be in sat
@@ -461,6 +597,24 @@ func sendPaymentRequest(ctx *cli.Context,
 		))
 	}
 
+	req.MinShardSizeMsat = ctx.Uint64(minShardSizeMsatFlag.Name)
+
+	switch ctx.String(splitStrategyFlag.Name) {
+	case "adaptive":
+		req.SplitStrategy = routerrpc.SplitStrategy_SPLIT_ADAPTIVE
+
+	case "equal":
+		req.SplitStrategy = routerrpc.SplitStrategy_SPLIT_EQUAL
+
+	case "largest_channel":
+		req.SplitStrategy = routerrpc.
+			SplitStrategy_SPLIT_LARGEST_CHANNEL
+
+	default:
+		return fmt.Errorf("unknown split strategy: %v",
+			ctx.String(splitStrategyFlag.Name))
+	}
+
 	// Parse custom data records.
 	data := ctx.String(dataFlag.Name)
 	if data != "" {
@@ -880,6 +1034,18 @@ var sendToRouteCommand = cli.Command{
 				"it to true so the payment won't be failed " +
 				"unless a terminal error has occurred.",
 		},
+		cli.StringFlag{
+			Name: "session_key",
+			Usage: "(expert) the hex encoded sphinx session key " +
+				"to construct the onion with, instead of a " +
+				"random one",
+		},
+		cli.StringFlag{
+			Name: "onion_blob",
+			Usage: "(expert) a hex encoded, fully constructed " +
+				"onion packet to send instead of the one lnd " +
+				"would construct, requires --session_key",
+		},
 	},
 	Action: sendToRoute,
 }
@@ -976,6 +1142,24 @@ func sendToRoute(ctx *cli.Context) error {
 		SkipTempErr: ctx.Bool("skip_temp_err"),
 	}
 
+	if ctx.IsSet("session_key") {
+		req.SessionKey, err = hex.DecodeString(
+			ctx.String("session_key"),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to decode session key: %v",
+				err)
+		}
+	}
+
+	if ctx.IsSet("onion_blob") {
+		req.OnionBlob, err = hex.DecodeString(ctx.String("onion_blob"))
+		if err != nil {
+			return fmt.Errorf("unable to decode onion blob: %v",
+				err)
+		}
+	}
+
 	return sendToRouteRequest(ctx, req)
 }
 
@@ -1608,6 +1792,59 @@ func deletePayments(ctx *cli.Context) error {
 	return nil
 }
 
+var cleanupDuplicatePaymentsCommand = cli.Command{
+	Name:     "cleanupduplicatepayments",
+	Category: "Payments",
+	Usage: "Delete the legacy duplicate payments left behind by old " +
+		"versions of lnd.",
+	ArgsUsage: "[--dry_run]",
+	Description: `
+	Old versions of lnd allowed paying the same payment hash more than once
+	and stored the additional payments in a legacy format. This command
+	deletes the failed and incomplete ones of those duplicate payments, as
+	well as any payment index entries that point to payments that no longer
+	exist. Succeeded duplicate payments are kept, since they hold the
+	preimage that proves the payment.
+
+	If --dry_run is specified, nothing is deleted and only a report of what
+	would be deleted is shown.
+
+	NOTE: As with deletepayments, the freed disk space is only reclaimed
+	after compacting the database.
+	`,
+	Action: actionDecorator(cleanupDuplicatePayments),
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "dry_run",
+			Usage: "only report what would be deleted, without " +
+				"deleting anything",
+		},
+	},
+}
+
+func cleanupDuplicatePayments(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if ctx.NArg() > 0 {
+		return cli.ShowCommandHelp(ctx, "cleanupduplicatepayments")
+	}
+
+	resp, err := client.CleanupDuplicatePayments(
+		ctxc, &lnrpc.CleanupDuplicatePaymentsRequest{
+			DryRun: ctx.Bool("dry_run"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 // ESC is the ASCII code for escape character.
 const ESC = 27
 
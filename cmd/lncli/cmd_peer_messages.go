@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/hex"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var subscribePeerMessagesCommand = cli.Command{
+	Name:     "subscribepeermessages",
+	Category: "Peers",
+	Usage: "Stream summaries of the wire messages exchanged with " +
+		"peers.",
+	Description: `
+	Stream a summary of every wire message that is sent to or received
+	from the selected peers. This is a debugging aid that allows protocol
+	issues to be inspected without restarting lnd with trace logging.
+
+	The stream can be restricted to a set of peers and message types. To
+	reduce the volume of the stream, only one out of every --sample_rate
+	matching messages can be shown.
+	`,
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name: "peer",
+			Usage: "the hex-encoded identity pubkey of a peer to " +
+				"show messages for, can be specified multiple " +
+				"times; if not set messages of all peers are " +
+				"shown",
+		},
+		cli.Int64SliceFlag{
+			Name: "type",
+			Usage: "a wire message type to show, can be " +
+				"specified multiple times; if not set " +
+				"messages of all types are shown",
+		},
+		cli.Uint64Flag{
+			Name: "sample_rate",
+			Usage: "only show one out of every sample_rate " +
+				"matching messages",
+		},
+	},
+	Action: actionDecorator(subscribePeerMessages),
+}
+
+func subscribePeerMessages(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.SubscribePeerMessagesRequest{
+		SampleRate: uint32(ctx.Uint64("sample_rate")),
+	}
+	for _, peerStr := range ctx.StringSlice("peer") {
+		peer, err := hex.DecodeString(peerStr)
+		if err != nil {
+			return err
+		}
+		req.Peers = append(req.Peers, peer)
+	}
+	for _, msgType := range ctx.Int64Slice("type") {
+		req.MsgTypes = append(req.MsgTypes, uint32(msgType))
+	}
+
+	stream, err := client.SubscribePeerMessages(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(msg)
+	}
+}
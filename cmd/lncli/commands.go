@@ -262,7 +262,8 @@ var sendCoinsCommand = cli.Command{
 			Usage: "if set, then the amount field will be ignored, " +
 				"and the wallet will attempt to sweep all " +
 				"outputs within the wallet to the target " +
-				"address",
+				"address, except for the value reserved for " +
+				"fee bumping anchor channels",
 		},
 		cli.Int64Flag{
 			Name:  "amt",
@@ -703,6 +704,8 @@ var closeChannelCommand = cli.Command{
 	A unilateral channel closure means that the latest commitment
 	transaction will be broadcast to the network. As a result, any settled
 	funds will be time locked for a few blocks before they can be spent.
+	Adding --dry_run shows the commitment transaction, its fee and the
+	time locks of its outputs without broadcasting anything.
 
 	In the case of a cooperative closure, one can manually set the fee to
 	be used for the closing transaction via either the --conf_target or
@@ -738,6 +741,11 @@ var closeChannelCommand = cli.Command{
 			Name:  "force",
 			Usage: "attempt an uncooperative closure",
 		},
+		cli.BoolFlag{
+			Name: "dry_run",
+			Usage: "only show what an uncooperative closure " +
+				"would broadcast, requires --force",
+		},
 		cli.BoolFlag{
 			Name:  "block",
 			Usage: "block until the channel is closed",
@@ -797,6 +805,26 @@ func closeChannel(ctx *cli.Context) error {
 		return err
 	}
 
+	if ctx.Bool("dry_run") {
+		if !ctx.Bool("force") {
+			return fmt.Errorf("--dry_run is only supported for " +
+				"uncooperative closures, use --force")
+		}
+
+		resp, err := client.DryRunForceClose(
+			ctxc, &lnrpc.DryRunForceCloseRequest{
+				ChannelPoint: channelPoint,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(resp)
+
+		return nil
+	}
+
 	// TODO(roasbeef): implement time deadline within server
 	req := &lnrpc.CloseChannelRequest{
 		ChannelPoint:    channelPoint,
@@ -1255,6 +1283,12 @@ var listPeersCommand = cli.Command{
 			Name:  "list_errors",
 			Usage: "list a full set of most recent errors for the peer",
 		},
+		cli.BoolFlag{
+			Name: "include_events",
+			Usage: "include the most recent significant events " +
+				"of the peer, such as connects, channel " +
+				"reestablishes, errors and flow control stalls",
+		},
 	},
 	Action: actionDecorator(listPeers),
 }
@@ -1267,7 +1301,8 @@ func listPeers(ctx *cli.Context) error {
 	// By default, we display a single error on the cli. If the user
 	// specifically requests a full error set, then we will provide it.
 	req := &lnrpc.ListPeersRequest{
-		LatestError: !ctx.IsSet("list_errors"),
+		LatestError:   !ctx.IsSet("list_errors"),
+		IncludeEvents: ctx.Bool("include_events"),
 	}
 	resp, err := client.ListPeers(ctxc, req)
 	if err != nil {
@@ -1481,6 +1516,117 @@ func listChannels(ctx *cli.Context) error {
 	return nil
 }
 
+var channelLiquidityCommand = cli.Command{
+	Name:      "channelliquidity",
+	Category:  "Channels",
+	Usage:     "Show a real-time liquidity breakdown of active channels.",
+	ArgsUsage: "[chan_point]",
+	Description: `
+	Show how much can currently be sent and received over the active
+	channels, along with the pending HTLCs in both directions, the
+	commitment fee buffer and the channel reserves that these amounts are
+	derived from.
+
+	If a channel point is given, only the liquidity of that channel is
+	shown.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "chan_point",
+			Usage: "(optional) the channel to show the liquidity " +
+				"of, in the format txid:index",
+		},
+	},
+	Action: actionDecorator(channelLiquidity),
+}
+
+func channelLiquidity(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var chanPointStr string
+	switch {
+	case ctx.IsSet("chan_point"):
+		chanPointStr = ctx.String("chan_point")
+
+	case ctx.Args().Present():
+		chanPointStr = ctx.Args().First()
+	}
+
+	req := &lnrpc.ChannelLiquidityRequest{}
+	if chanPointStr != "" {
+		chanPoint, err := parseChanPoint(chanPointStr)
+		if err != nil {
+			return fmt.Errorf("unable to parse chan_point: %v", err)
+		}
+		req.ChanPoint = chanPoint
+	}
+
+	resp, err := client.ChannelLiquidity(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var channelDustCommand = cli.Command{
+	Name:      "channeldust",
+	Category:  "Channels",
+	Usage:     "Show the dust limits and trimmed value of open channels.",
+	ArgsUsage: "[chan_point]",
+	Description: `
+	Show the dust limits of both commitments of the open channels, the
+	amounts below which incoming and outgoing HTLCs are trimmed from each
+	commitment at its current fee rate, and the value that isn't protected
+	on-chain because it's trimmed to dust.
+
+	If a channel point is given, only the dust of that channel is shown.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "chan_point",
+			Usage: "(optional) the channel to show the dust " +
+				"of, in the format txid:index",
+		},
+	},
+	Action: actionDecorator(channelDust),
+}
+
+func channelDust(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var chanPointStr string
+	switch {
+	case ctx.IsSet("chan_point"):
+		chanPointStr = ctx.String("chan_point")
+
+	case ctx.Args().Present():
+		chanPointStr = ctx.Args().First()
+	}
+
+	req := &lnrpc.ChannelDustRequest{}
+	if chanPointStr != "" {
+		chanPoint, err := parseChanPoint(chanPointStr)
+		if err != nil {
+			return fmt.Errorf("unable to parse chan_point: %v", err)
+		}
+		req.ChanPoint = chanPoint
+	}
+
+	resp, err := client.ChannelDust(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var closedChannelsCommand = cli.Command{
 	Name:     "closedchannels",
 	Category: "Channels",
@@ -1578,6 +1724,65 @@ func describeGraph(ctx *cli.Context) error {
 	return nil
 }
 
+var describeGraphSliceCommand = cli.Command{
+	Name:     "describegraphslice",
+	Category: "Graph",
+	Usage: "Describe the part of the network graph around a set of " +
+		"nodes.",
+	ArgsUsage: "pub_key [pub_key...]",
+	Description: `
+	Prints the nodes within a number of hops of the given nodes, and the
+	channels between them that have at least the given capacity. This is
+	the part of the graph a light client needs to find routes to the given
+	nodes.
+	`,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "max_hops",
+			Usage: "the maximum number of hops between a node in " +
+				"the slice and the closest of the given nodes, " +
+				"if not set a default of 3 hops is used",
+		},
+		cli.Int64Flag{
+			Name: "min_capacity",
+			Usage: "the minimum capacity in satoshis of the " +
+				"channels in the slice",
+		},
+		cli.BoolFlag{
+			Name: "include_unannounced",
+			Usage: "If set, unannounced channels will be included " +
+				"in the slice.",
+		},
+	},
+	Action: actionDecorator(describeGraphSlice),
+}
+
+func describeGraphSlice(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() == 0 {
+		return cli.ShowCommandHelp(ctx, "describegraphslice")
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.GraphSliceRequest{
+		PubKeys:            ctx.Args(),
+		MaxHops:            uint32(ctx.Uint64("max_hops")),
+		MinCapacitySat:     ctx.Int64("min_capacity"),
+		IncludeUnannounced: ctx.Bool("include_unannounced"),
+	}
+
+	graph, err := client.DescribeGraphSlice(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(graph)
+	return nil
+}
+
 var getNodeMetricsCommand = cli.Command{
 	Name:        "getnodemetrics",
 	Category:    "Graph",
@@ -1733,6 +1938,35 @@ func getNetworkInfo(ctx *cli.Context) error {
 	return nil
 }
 
+var getRebroadcastScheduleCommand = cli.Command{
+	Name:     "getrebroadcastschedule",
+	Category: "Channels",
+	Usage: "Get the times at which the channel updates of our public " +
+		"channels are next rebroadcast.",
+	Description: `
+	Returns the time at which the channel update of each of our public
+	channels is next refreshed and rebroadcast to the network. Channel
+	updates are rebroadcast periodically, even if nothing changed, to
+	prevent the network from pruning our channels as stale.`,
+	Action: actionDecorator(getRebroadcastSchedule),
+}
+
+func getRebroadcastSchedule(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.RebroadcastScheduleRequest{}
+
+	schedule, err := client.GetRebroadcastSchedule(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(schedule)
+	return nil
+}
+
 var debugLevelCommand = cli.Command{
 	Name:  "debuglevel",
 	Usage: "Set the debug level.",
@@ -1771,6 +2005,69 @@ func debugLevel(ctx *cli.Context) error {
 	return nil
 }
 
+var reloadConfigCommand = cli.Command{
+	Name:  "reloadconfig",
+	Usage: "Reload the options of lnd.conf that can be changed at runtime.",
+	Description: `
+	Re-read lnd.conf and apply the options that can safely be changed while
+	lnd is running: the debug level, the default routing policy of new
+	channels, the throttling of incoming channel updates and the intervals
+	of the health checks. All other options only take effect after a
+	restart.
+
+	If tlsautorotate is set, changes of tlsextraip and tlsextradomain are
+	applied by rotating the TLS certificate.
+
+	The options that were changed are printed. Sending SIGHUP to lnd
+	triggers the same reload.`,
+	Action: actionDecorator(reloadConfig),
+}
+
+func reloadConfig(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ReloadConfig(ctxc, &lnrpc.ReloadConfigRequest{})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var rotateTLSCertCommand = cli.Command{
+	Name:  "rotatetlscert",
+	Usage: "Regenerate the TLS certificate of the RPC server.",
+	Description: `
+	Regenerate the TLS certificate and key of the RPC server with the
+	currently configured extra IPs and domains. The new certificate is
+	served for new connections right away, existing connections are not
+	affected.
+
+	The new certificate is written to the configured tlscertpath, so
+	clients need to reload it before they connect again. If lncli reads
+	the certificate from a different location, it must be copied there.`,
+	Action: actionDecorator(rotateTLSCert),
+}
+
+func rotateTLSCert(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.RotateTLSCert(
+		ctxc, &lnrpc.RotateTLSCertRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var listChainTxnsCommand = cli.Command{
 	Name:     "listchaintxns",
 	Category: "On-chain",
@@ -26,6 +26,17 @@ func devCommands() []cli.Command {
 			ArgsUsage:   "graph-json-file",
 			Action:      actionDecorator(importGraph),
 		},
+		{
+			Name:     "simulateforwards",
+			Category: "Development",
+			Description: "Checks the forwards of a SimulateForwardsRequest " +
+				"JSON against the current liquidity and " +
+				"forwarding policies of our channels, without " +
+				"adding any HTLCs",
+			Usage:     "Simulate a sequence of forwards.",
+			ArgsUsage: "forwards-json-file",
+			Action:    actionDecorator(simulateForwards),
+		},
 	}
 }
 
@@ -62,3 +73,29 @@ func importGraph(ctx *cli.Context) error {
 	printRespJSON(res)
 	return nil
 }
+
+func simulateForwards(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getDevClient(ctx)
+	defer cleanUp()
+
+	jsonFile := lncfg.CleanAndExpandPath(ctx.Args().First())
+	jsonBytes, err := ioutil.ReadFile(jsonFile)
+	if err != nil {
+		return fmt.Errorf("error reading JSON from file %v: %v",
+			jsonFile, err)
+	}
+
+	req := &devrpc.SimulateForwardsRequest{}
+	err = jsonpb.Unmarshal(bytes.NewReader(jsonBytes), req)
+	if err != nil {
+		return fmt.Errorf("error parsing JSON: %v", err)
+	}
+	res, err := client.SimulateForwards(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(res)
+	return nil
+}
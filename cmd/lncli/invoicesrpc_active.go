@@ -18,6 +18,9 @@ func invoicesCommands() []cli.Command {
 		cancelInvoiceCommand,
 		addHoldInvoiceCommand,
 		settleInvoiceCommand,
+		reissueInvoiceCommand,
+		gcExpiredInvoicesCommand,
+		lookupInvoiceV2Command,
 	}
 }
 
@@ -252,3 +255,261 @@ func addHoldInvoice(ctx *cli.Context) error {
 
 	return nil
 }
+
+var reissueInvoiceCommand = cli.Command{
+	Name:     "reissueinvoice",
+	Category: "Invoices",
+	Usage: "Replace an open invoice with a new one that has a fresh " +
+		"payment address and expiry.",
+	Description: `
+	Reissue an open, unexpired invoice that hasn't received any payments.
+	The new invoice copies the amount, memo and route hints of the
+	original invoice, which is canceled.
+
+	As invoices are identified by their payment hash, the new invoice
+	can't reuse the preimage of the original invoice. By default a random
+	preimage is generated. A hold invoice can only be reissued as a hold
+	invoice with a new hash.`,
+	ArgsUsage: "paymenthash",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "paymenthash",
+			Usage: "the hex-encoded payment hash (32 byte) of " +
+				"the invoice to reissue",
+		},
+		cli.Int64Flag{
+			Name: "expiry",
+			Usage: "the new invoice's expiry time in seconds. If " +
+				"not specified, the expiry time of the " +
+				"original invoice is used.",
+		},
+		cli.StringFlag{
+			Name: "preimage",
+			Usage: "the hex-encoded preimage (32 byte) of the " +
+				"new invoice",
+		},
+		cli.StringFlag{
+			Name: "hash",
+			Usage: "the hex-encoded payment hash (32 byte) of " +
+				"the new invoice, making it a hold invoice",
+		},
+	},
+	Action: actionDecorator(reissueInvoice),
+}
+
+func reissueInvoice(ctx *cli.Context) error {
+	var (
+		paymentHash []byte
+		err         error
+	)
+
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+
+	switch {
+	case ctx.IsSet("paymenthash"):
+		paymentHash, err = hex.DecodeString(ctx.String("paymenthash"))
+	case args.Present():
+		paymentHash, err = hex.DecodeString(args.First())
+	default:
+		return fmt.Errorf("payment hash argument missing")
+	}
+	if err != nil {
+		return fmt.Errorf("unable to parse payment hash: %v", err)
+	}
+
+	preimage, err := hex.DecodeString(ctx.String("preimage"))
+	if err != nil {
+		return fmt.Errorf("unable to parse preimage: %v", err)
+	}
+
+	hash, err := hex.DecodeString(ctx.String("hash"))
+	if err != nil {
+		return fmt.Errorf("unable to parse hash: %v", err)
+	}
+
+	req := &invoicesrpc.ReissueInvoiceRequest{
+		PaymentHash: paymentHash,
+		Expiry:      ctx.Int64("expiry"),
+		Preimage:    preimage,
+		Hash:        hash,
+	}
+
+	resp, err := client.ReissueInvoice(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var gcExpiredInvoicesCommand = cli.Command{
+	Name:     "gcexpiredinvoices",
+	Category: "Invoices",
+	Usage:    "Delete unpaid invoices that expired long ago.",
+	Description: `
+	Delete all unpaid invoices that expired more than the given age ago.
+	If no age is given, the age configured with invoices.gc-expired-after
+	is used. If invoices.gc-archive-file is configured, the invoices are
+	appended to it before they are deleted.
+
+	The stats of the garbage collection are returned. With --stats_only,
+	only the stats of previous garbage collections are returned.`,
+	Flags: []cli.Flag{
+		cli.DurationFlag{
+			Name: "expired_after",
+			Usage: "the time since their expiry after which " +
+				"unpaid invoices are deleted, for example 720h",
+		},
+		cli.BoolFlag{
+			Name: "stats_only",
+			Usage: "only return the stats of previous garbage " +
+				"collections",
+		},
+	},
+	Action: actionDecorator(gcExpiredInvoices),
+}
+
+func gcExpiredInvoices(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	expiredAfter := ctx.Duration("expired_after")
+	if expiredAfter < 0 {
+		return fmt.Errorf("expired_after must not be negative")
+	}
+
+	req := &invoicesrpc.GcExpiredInvoicesRequest{
+		StatsOnly:       ctx.Bool("stats_only"),
+		ExpiredAfterSec: uint64(expiredAfter.Seconds()),
+	}
+
+	resp, err := client.GcExpiredInvoices(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var lookupInvoiceV2Command = cli.Command{
+	Name:     "lookupinvoicev2",
+	Category: "Invoices",
+	Usage: "Lookup an invoice by its payment hash, payment address or " +
+		"AMP set ID.",
+	Description: `
+	Lookup an invoice by exactly one of its payment hash, payment address
+	or set ID. The payment hash isn't sufficient to reference the payments
+	of an AMP invoice, as every payment of it uses its own set of payment
+	hashes.
+
+	With --htlc_set_only, only the HTLCs of the given set ID are returned.
+	With --htlc_set_blank, no HTLCs are returned for the given payment
+	address, which allows to fetch the set IDs of an AMP invoice first
+	and look up the HTLCs of each of them afterwards.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "rhash",
+			Usage: "the hex-encoded payment hash (32 byte)",
+		},
+		cli.StringFlag{
+			Name:  "payment_addr",
+			Usage: "the hex-encoded payment address (32 byte)",
+		},
+		cli.StringFlag{
+			Name:  "set_id",
+			Usage: "the hex-encoded set ID (32 byte) of an AMP payment",
+		},
+		cli.BoolFlag{
+			Name: "htlc_set_only",
+			Usage: "only return the HTLCs of the given set ID, " +
+				"requires --set_id",
+		},
+		cli.BoolFlag{
+			Name: "htlc_set_blank",
+			Usage: "don't return any HTLCs, requires " +
+				"--payment_addr",
+		},
+	},
+	Action: actionDecorator(lookupInvoiceV2),
+}
+
+func lookupInvoiceV2(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	var numRefs int
+	for _, flag := range []string{"rhash", "payment_addr", "set_id"} {
+		if ctx.IsSet(flag) {
+			numRefs++
+		}
+	}
+	if numRefs != 1 {
+		return fmt.Errorf("exactly one of rhash, payment_addr and " +
+			"set_id must be set")
+	}
+
+	req := &invoicesrpc.LookupInvoiceMsg{}
+	switch {
+	case ctx.IsSet("rhash"):
+		rHash, err := hex.DecodeString(ctx.String("rhash"))
+		if err != nil {
+			return fmt.Errorf("unable to decode rhash: %v", err)
+		}
+
+		req.InvoiceRef = &invoicesrpc.LookupInvoiceMsg_PaymentHash{
+			PaymentHash: rHash,
+		}
+
+	case ctx.IsSet("payment_addr"):
+		payAddr, err := hex.DecodeString(ctx.String("payment_addr"))
+		if err != nil {
+			return fmt.Errorf("unable to decode payment_addr: %v",
+				err)
+		}
+
+		req.InvoiceRef = &invoicesrpc.LookupInvoiceMsg_PaymentAddr{
+			PaymentAddr: payAddr,
+		}
+
+	case ctx.IsSet("set_id"):
+		setID, err := hex.DecodeString(ctx.String("set_id"))
+		if err != nil {
+			return fmt.Errorf("unable to decode set_id: %v", err)
+		}
+
+		req.InvoiceRef = &invoicesrpc.LookupInvoiceMsg_SetId{
+			SetId: setID,
+		}
+	}
+
+	switch {
+	case ctx.Bool("htlc_set_only") && ctx.Bool("htlc_set_blank"):
+		return fmt.Errorf("htlc_set_only and htlc_set_blank can't " +
+			"be combined")
+
+	case ctx.Bool("htlc_set_only"):
+		req.LookupModifier = invoicesrpc.LookupModifier_HTLC_SET_ONLY
+
+	case ctx.Bool("htlc_set_blank"):
+		req.LookupModifier = invoicesrpc.LookupModifier_HTLC_SET_BLANK
+	}
+
+	invoice, err := client.LookupInvoiceV2(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(invoice)
+
+	return nil
+}
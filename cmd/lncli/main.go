@@ -16,6 +16,7 @@ import (
 
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/lightningnetwork/lnd/build"
+	"github.com/lightningnetwork/lnd/chainreg"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/macaroons"
@@ -276,11 +277,12 @@ func extractPathArgs(ctx *cli.Context) (string, string, error) {
 		return "", "", fmt.Errorf("unknown chain: %v", chain)
 	}
 
+	// Besides the registered networks, lnd can run on a custom signet
+	// with a name of its own, so any name that can be the name of the
+	// network's data directory is accepted.
 	network := strings.ToLower(ctx.GlobalString("network"))
-	switch network {
-	case "mainnet", "testnet", "regtest", "simnet", "signet":
-	default:
-		return "", "", fmt.Errorf("unknown network: %v", network)
+	if err := chainreg.ValidateNetworkName(network); err != nil {
+		return "", "", err
 	}
 
 	// We'll now fetch the lnddir so we can make a decision  on how to
@@ -453,15 +455,21 @@ func main() {
 		lookupInvoiceCommand,
 		listInvoicesCommand,
 		listChannelsCommand,
+		channelLiquidityCommand,
+		channelDustCommand,
 		closedChannelsCommand,
 		listPaymentsCommand,
 		describeGraphCommand,
+		describeGraphSliceCommand,
 		getNodeMetricsCommand,
 		getChanInfoCommand,
 		getNodeInfoCommand,
 		queryRoutesCommand,
 		getNetworkInfoCommand,
+		getRebroadcastScheduleCommand,
 		debugLevelCommand,
+		reloadConfigCommand,
+		rotateTLSCertCommand,
 		decodePayReqCommand,
 		listChainTxnsCommand,
 		stopCommand,
@@ -484,8 +492,10 @@ func main() {
 		profileSubCommand,
 		getStateCommand,
 		deletePaymentsCommand,
+		cleanupDuplicatePaymentsCommand,
 		sendCustomCommand,
 		subscribeCustomCommand,
+		subscribePeerMessagesCommand,
 		fishCompletionCommand,
 		listAliasesCommand,
 	}
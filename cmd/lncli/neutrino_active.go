@@ -4,6 +4,8 @@
 package main
 
 import (
+	"encoding/hex"
+	"fmt"
 	"strconv"
 
 	"github.com/lightningnetwork/lnd/lnrpc/neutrinorpc"
@@ -304,6 +306,165 @@ func getBlockHash(ctx *cli.Context) error {
 	return nil
 }
 
+var getCFiltersCommand = cli.Command{
+	Name:     "getcfilters",
+	Usage:    "Get the compact filters of a range of blocks.",
+	Category: "Neutrino",
+	Description: "Returns the compact filters, filter headers and block " +
+		"headers of the blocks from start_height up to and including " +
+		"end_height.",
+	ArgsUsage: "start_height end_height",
+	Action:    actionDecorator(getCFilters),
+}
+
+func getCFilters(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if we do not have the expected
+	// number of arguments/flags.
+	if ctx.NArg() != 2 {
+		return cli.ShowCommandHelp(ctx, "getcfilters")
+	}
+
+	startHeight, endHeight, err := parseHeightRange(ctx.Args())
+	if err != nil {
+		return err
+	}
+
+	client, cleanUp := getNeutrinoKitClient(ctx)
+	defer cleanUp()
+
+	req := &neutrinorpc.GetCFiltersRequest{
+		StartHeight: startHeight,
+		EndHeight:   endHeight,
+	}
+
+	resp, err := client.GetCFilters(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var matchCFiltersCommand = cli.Command{
+	Name:     "matchcfilters",
+	Usage:    "Match the compact filters of a range of blocks.",
+	Category: "Neutrino",
+	Description: "Returns the blocks from start_height up to and " +
+		"including end_height whose compact filters match any of the " +
+		"given output scripts. As compact filters are probabilistic, " +
+		"a match doesn't guarantee that a block contains a script.",
+	ArgsUsage: "start_height end_height",
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name: "script",
+			Usage: "a hex encoded output script to match the " +
+				"filters against, can be specified multiple " +
+				"times",
+		},
+	},
+	Action: actionDecorator(matchCFilters),
+}
+
+func matchCFilters(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if we do not have the expected
+	// number of arguments/flags.
+	if ctx.NArg() != 2 || !ctx.IsSet("script") {
+		return cli.ShowCommandHelp(ctx, "matchcfilters")
+	}
+
+	startHeight, endHeight, err := parseHeightRange(ctx.Args())
+	if err != nil {
+		return err
+	}
+
+	var scripts [][]byte
+	for _, scriptHex := range ctx.StringSlice("script") {
+		script, err := hex.DecodeString(scriptHex)
+		if err != nil {
+			return fmt.Errorf("invalid script %v: %v", scriptHex,
+				err)
+		}
+
+		scripts = append(scripts, script)
+	}
+
+	client, cleanUp := getNeutrinoKitClient(ctx)
+	defer cleanUp()
+
+	req := &neutrinorpc.MatchCFiltersRequest{
+		StartHeight: startHeight,
+		EndHeight:   endHeight,
+		Scripts:     scripts,
+	}
+
+	resp, err := client.MatchCFilters(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var getBanStatusCommand = cli.Command{
+	Name:     "getbanstatus",
+	Usage:    "Get the ban status of a peer.",
+	Category: "Neutrino",
+	Description: "Returns whether a peer is banned, and if so, why and " +
+		"until when.",
+	ArgsUsage: "address",
+	Action:    actionDecorator(getBanStatus),
+}
+
+func getBanStatus(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if we do not have the expected
+	// number of arguments/flags.
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "getbanstatus")
+	}
+
+	client, cleanUp := getNeutrinoKitClient(ctx)
+	defer cleanUp()
+
+	req := &neutrinorpc.GetBanStatusRequest{
+		PeerAddrs: ctx.Args().First(),
+	}
+
+	resp, err := client.GetBanStatus(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+// parseHeightRange parses the start and end height of a range of blocks from
+// the first two arguments.
+func parseHeightRange(args cli.Args) (int32, int32, error) {
+	startHeight, err := strconv.ParseInt(args.Get(0), 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid start height: %v", err)
+	}
+
+	endHeight, err := strconv.ParseInt(args.Get(1), 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid end height: %v", err)
+	}
+
+	return int32(startHeight), int32(endHeight), nil
+}
+
 // neutrinoCommands will return the set of commands to enable for neutrinorpc
 // builds.
 func neutrinoCommands() []cli.Command {
@@ -322,6 +483,9 @@ func neutrinoCommands() []cli.Command {
 				getBlockHeaderCommand,
 				getCFilterCommand,
 				getBlockHashCommand,
+				getCFiltersCommand,
+				matchCFiltersCommand,
+				getBanStatusCommand,
 			},
 		},
 	}
@@ -22,6 +22,9 @@ func peersCommands() []cli.Command {
 				"network",
 			Subcommands: []cli.Command{
 				updateNodeAnnouncementCommand,
+				updateFeaturesCommand,
+				updateGossipFilterCommand,
+				listGossipFiltersCommand,
 			},
 		},
 	}
@@ -160,3 +163,187 @@ func updateNodeAnnouncement(ctx *cli.Context) error {
 
 	return nil
 }
+
+var updateFeaturesCommand = cli.Command{
+	Name:     "updatefeatures",
+	Category: "Peers",
+	Usage:    "enable or disable optional feature bits at runtime",
+	Description: `
+	Enable or disable optional feature bits, such as
+	option_shutdown_anysegwit (27), without restarting the node. Only
+	feature bits that can be toggled at runtime are accepted.
+
+	The new feature set is advertised in a fresh node announcement and is
+	sent to each peer in our Init message the next time it connects. Set
+	--reconnect to disconnect all persistent peers so that the new feature
+	bits are negotiated with them right away.`,
+	ArgsUsage: "[--feature_bit_add=] [--feature_bit_remove=] [--reconnect]",
+	Flags: []cli.Flag{
+		cli.Int64SliceFlag{
+			Name: "feature_bit_add",
+			Usage: "an optional feature bit that should be " +
+				"enabled. Can be set multiple times in the " +
+				"same command",
+		},
+		cli.Int64SliceFlag{
+			Name: "feature_bit_remove",
+			Usage: "an optional feature bit that should be " +
+				"disabled. Can be set multiple times in the " +
+				"same command",
+		},
+		cli.BoolFlag{
+			Name: "reconnect",
+			Usage: "disconnect all persistent peers so they " +
+				"reconnect and re-negotiate features",
+		},
+	},
+	Action: actionDecorator(updateFeatures),
+}
+
+func updateFeatures(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getPeersClient(ctx)
+	defer cleanUp()
+
+	req := &peersrpc.UpdateFeaturesRequest{
+		ReconnectPeers: ctx.Bool("reconnect"),
+	}
+
+	for _, feature := range ctx.Int64Slice("feature_bit_add") {
+		action := &peersrpc.UpdateFeatureAction{
+			Action:     peersrpc.UpdateAction_ADD,
+			FeatureBit: lnrpc.FeatureBit(feature),
+		}
+		req.FeatureUpdates = append(req.FeatureUpdates, action)
+	}
+
+	for _, feature := range ctx.Int64Slice("feature_bit_remove") {
+		action := &peersrpc.UpdateFeatureAction{
+			Action:     peersrpc.UpdateAction_REMOVE,
+			FeatureBit: lnrpc.FeatureBit(feature),
+		}
+		req.FeatureUpdates = append(req.FeatureUpdates, action)
+	}
+
+	if len(req.FeatureUpdates) == 0 {
+		return fmt.Errorf("no feature bit changes specified")
+	}
+
+	resp, err := client.UpdateFeatures(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var updateGossipFilterCommand = cli.Command{
+	Name:     "updategossipfilter",
+	Category: "Peers",
+	Usage:    "override the gossip filter we send to a peer",
+	Description: `
+	Override the gossip timestamp filter we send to a peer, regardless of
+	whether it was chosen as an active or passive gossip syncer. This can be
+	used to reduce the bandwidth spent on gossip, for example on metered
+	connections.
+
+	The following modes are supported:
+	  default: remove the override and let the gossip sync manager choose
+	           the filter again
+	  mute:    ask the peer not to send us any gossip
+	  all:     ask the peer to send us all new gossip
+	  custom:  ask the peer to send us the gossip with a timestamp within
+	           the range given by --first_timestamp and --timestamp_range
+
+	The filter is sent right away if the peer is connected, and every time
+	it reconnects until the override is removed. Overrides are not persisted
+	across restarts.`,
+	ArgsUsage: "pubkey mode [--first_timestamp=] [--timestamp_range=]",
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "first_timestamp",
+			Usage: "the unix timestamp in seconds of the " +
+				"start of the range of gossip to receive in " +
+				"the custom mode",
+		},
+		cli.Uint64Flag{
+			Name: "timestamp_range",
+			Usage: "the number of seconds the range of gossip to " +
+				"receive spans in the custom mode",
+		},
+	},
+	Action: actionDecorator(updateGossipFilter),
+}
+
+func updateGossipFilter(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getPeersClient(ctx)
+	defer cleanUp()
+
+	if ctx.NArg() != 2 {
+		return cli.ShowCommandHelp(ctx, "updategossipfilter")
+	}
+
+	req := &peersrpc.UpdateGossipFilterRequest{
+		PubKey:         ctx.Args().Get(0),
+		FirstTimestamp: uint32(ctx.Uint64("first_timestamp")),
+		TimestampRange: uint32(ctx.Uint64("timestamp_range")),
+	}
+
+	switch ctx.Args().Get(1) {
+	case "default":
+		req.Mode = peersrpc.GossipFilterMode_DEFAULT_GOSSIP
+
+	case "mute":
+		req.Mode = peersrpc.GossipFilterMode_MUTE_GOSSIP
+
+	case "all":
+		req.Mode = peersrpc.GossipFilterMode_ALL_GOSSIP
+
+	case "custom":
+		req.Mode = peersrpc.GossipFilterMode_CUSTOM_GOSSIP
+
+	default:
+		return fmt.Errorf("unknown gossip filter mode %q",
+			ctx.Args().Get(1))
+	}
+
+	resp, err := client.UpdateGossipFilter(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var listGossipFiltersCommand = cli.Command{
+	Name:     "listgossipfilters",
+	Category: "Peers",
+	Usage:    "list the gossip filters that override the default ones",
+	Description: `
+	List the gossip filters that were set with the updategossipfilter
+	command and currently override the ones chosen for our peers by the
+	gossip sync manager.`,
+	Action: actionDecorator(listGossipFilters),
+}
+
+func listGossipFilters(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getPeersClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListGossipFilters(
+		ctxc, &peersrpc.ListGossipFiltersRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
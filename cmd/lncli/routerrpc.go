@@ -7,11 +7,14 @@ func routerCommands() []cli.Command {
 	return []cli.Command{
 		queryMissionControlCommand,
 		importMissionControlCommand,
+		exportMissionControlCommand,
+		importMissionControlFileCommand,
 		queryProbCommand,
 		resetMissionControlCommand,
 		buildRouteCommand,
 		getCfgCommand,
 		setCfgCommand,
 		updateChanStatusCommand,
+		drainSwitchCommand,
 	}
 }
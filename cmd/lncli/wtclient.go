@@ -24,6 +24,8 @@ func wtclientCommands() []cli.Command {
 				getTowerCommand,
 				statsCommand,
 				policyCommand,
+				auditBackupsCommand,
+				cleanupSessionsCommand,
 			},
 		},
 	}
@@ -307,3 +309,89 @@ func policy(ctx *cli.Context) error {
 	printRespJSON(resp)
 	return nil
 }
+
+var auditBackupsCommand = cli.Command{
+	Name:      "audit",
+	Usage:     "Verify that all revoked states of a channel are backed up.",
+	ArgsUsage: "chan_point",
+	Description: `
+	List which revoked states of the channel with the given channel point
+	were backed up to which watchtower session, and report the ranges of
+	revoked commit heights that weren't backed up to any watchtower.
+	`,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "start_height",
+			Usage: "the first commit height to verify the " +
+				"backup coverage of, e.g. to skip states " +
+				"revoked before the watchtower client was " +
+				"enabled",
+		},
+	},
+	Action: actionDecorator(auditBackups),
+}
+
+func auditBackups(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if the number of arguments/flags
+	// is not what we expect.
+	if ctx.NArg() != 1 || ctx.NumFlags() > 1 {
+		return cli.ShowCommandHelp(ctx, "audit")
+	}
+
+	chanPoint, err := parseChanPoint(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("unable to parse chan_point: %v", err)
+	}
+
+	client, cleanUp := getWtclient(ctx)
+	defer cleanUp()
+
+	req := &wtclientrpc.AuditChannelBackupsRequest{
+		ChanPoint:   chanPoint,
+		StartHeight: ctx.Uint64("start_height"),
+	}
+	resp, err := client.AuditChannelBackups(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var cleanupSessionsCommand = cli.Command{
+	Name:  "cleanup",
+	Usage: "Delete the sessions that are no longer needed.",
+	Description: `
+	Delete all watchtower sessions whose channels were all cooperatively
+	closed, both at their watchtowers and from the local database. This is
+	done automatically whenever a channel is cooperatively closed, but can
+	be forced with this command, for example to retry sessions whose
+	watchtower was unreachable.
+	`,
+	Action: actionDecorator(cleanupSessions),
+}
+
+func cleanupSessions(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if the number of arguments/flags
+	// is not what we expect.
+	if ctx.NArg() > 0 || ctx.NumFlags() > 0 {
+		return cli.ShowCommandHelp(ctx, "cleanup")
+	}
+
+	client, cleanUp := getWtclient(ctx)
+	defer cleanUp()
+
+	req := &wtclientrpc.CleanupSessionsRequest{}
+	resp, err := client.CleanupSessions(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
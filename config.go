@@ -24,6 +24,8 @@ import (
 	flags "github.com/jessevdk/go-flags"
 	"github.com/lightninglabs/neutrino"
 	"github.com/lightningnetwork/lnd/autopilot"
+	"github.com/lightningnetwork/lnd/breachnotify"
+	"github.com/lightningnetwork/lnd/brontide"
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/chainreg"
 	"github.com/lightningnetwork/lnd/chanbackup"
@@ -42,6 +44,8 @@ import (
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/signal"
 	"github.com/lightningnetwork/lnd/tor"
+	"github.com/lightningnetwork/lnd/trafficstats"
+	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
 )
 
 const (
@@ -92,6 +96,10 @@ const (
 	// (14 months * 30 days * 24 hours).
 	defaultTLSCertDuration = 14 * 30 * 24 * time.Hour
 
+	// defaultTLSRotateBefore is how long before its expiry the TLS
+	// certificate is regenerated if automatic rotation is enabled.
+	defaultTLSRotateBefore = 30 * 24 * time.Hour
+
 	// minTimeLockDelta is the minimum timelock we require for incoming
 	// HTLCs on our channels.
 	minTimeLockDelta = routing.MinCLTVDelta
@@ -163,6 +171,14 @@ const (
 	defaultRSBackoff  = time.Second * 30
 	defaultRSAttempts = 1
 
+	// Set defaults for a health check which ensures that the hardware
+	// security module can be reached. This check is only active when the
+	// keys are kept in an HSM.
+	defaultHSMInterval = time.Minute
+	defaultHSMTimeout  = time.Second * 5
+	defaultHSMBackoff  = time.Second * 30
+	defaultHSMAttempts = 1
+
 	// defaultRemoteMaxHtlcs specifies the default limit for maximum
 	// concurrent HTLCs the remote party may add to commitment transactions.
 	// This value can be overridden with --default-remote-max-htlcs.
@@ -267,6 +283,8 @@ type Config struct {
 	TLSAutoRefresh     bool          `long:"tlsautorefresh" description:"Re-generate TLS certificate and key if the IPs or domains are changed"`
 	TLSDisableAutofill bool          `long:"tlsdisableautofill" description:"Do not include the interface IPs or the system hostname in TLS certificate, use first --tlsextradomain as Common Name instead, if set"`
 	TLSCertDuration    time.Duration `long:"tlscertduration" description:"The duration for which the auto-generated TLS certificate will be valid for"`
+	TLSAutoRotate      bool          `long:"tlsautorotate" description:"Re-generate the TLS certificate and key before the certificate expires, and serve the new certificate without a restart"`
+	TLSRotateBefore    time.Duration `long:"tlsrotatebefore" description:"How long before its expiry the TLS certificate is re-generated if tlsautorotate is set"`
 
 	NoMacaroons     bool          `long:"no-macaroons" description:"Disable macaroon authentication, can only be used if server is not listening on a public interface."`
 	AdminMacPath    string        `long:"adminmacaroonpath" description:"Path to write the admin macaroon for lnd's RPC and REST services if it doesn't exist"`
@@ -288,12 +306,16 @@ type Config struct {
 	RawRPCListeners   []string `long:"rpclisten" description:"Add an interface/port/socket to listen for RPC connections"`
 	RawRESTListeners  []string `long:"restlisten" description:"Add an interface/port/socket to listen for REST connections"`
 	RawListeners      []string `long:"listen" description:"Add an interface/port to listen for peer connections"`
+	RawWSListeners    []string `long:"wslisten" description:"Add an interface/port to listen for peer connections over WebSocket (RFC 6455), for example from browser-based peers or peers behind restrictive firewalls. The port must be specified."`
+	RawWSSListeners   []string `long:"wsslisten" description:"Add an interface/port to listen for peer connections over WebSocket secured by TLS, using the certificate of the RPC server. The certificate isn't verified by the dialing peers, as the brontide handshake already authenticates the node. The port must be specified."`
 	RawExternalIPs    []string `long:"externalip" description:"Add an ip:port to the list of local addresses we claim to listen on to peers. If a port is not specified, the default (9735) will be used regardless of other parameters"`
 	ExternalHosts     []string `long:"externalhosts" description:"Add a hostname:port that should be periodically resolved to announce IPs for. If a port is not specified, the default (9735) will be used."`
 	RPCListeners      []net.Addr
 	RESTListeners     []net.Addr
 	RestCORS          []string `long:"restcors" description:"Add an ip:port/hostname to allow cross origin access from. To allow all origins, set as \"*\"."`
 	Listeners         []net.Addr
+	WSListeners       []net.Addr
+	WSSListeners      []net.Addr
 	ExternalIPs       []net.Addr
 	DisableListen     bool          `long:"nolisten" description:"Disable listening for incoming peer connections"`
 	DisableRest       bool          `long:"norest" description:"Disable REST API"`
@@ -306,6 +328,10 @@ type Config struct {
 	MaxBackoff        time.Duration `long:"maxbackoff" description:"Longest backoff when reconnecting to persistent peers. Valid time units are {s, m, h}."`
 	ConnectionTimeout time.Duration `long:"connectiontimeout" description:"The timeout value for network connections. Valid time units are {ms, s, m, h}."`
 
+	WSHeartbeatInterval time.Duration `long:"ws-heartbeat-interval" description:"If set, a heartbeat message is sent on REST based WebSocket streams that haven't sent a message for this long, which allows browser clients to detect stalled connections. Set to 0 to disable heartbeats"`
+	WSMaxFrameSize      int           `long:"ws-max-frame-size" description:"The maximum size in bytes of a single message sent or received on REST based WebSocket connections"`
+	WSResumeWindow      time.Duration `long:"ws-resume-window" description:"If set, invoice and payment event streams on REST based WebSocket connections can be resumed by reconnecting with the resume token of the last received message within this window after a disconnect. Set to 0 to disable resumable streams"`
+
 	DebugLevel string `short:"d" long:"debuglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <global-level>,<subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
 
 	CPUProfile string `long:"cpuprofile" description:"Write CPU profile to the specified file"`
@@ -357,7 +383,7 @@ type Config struct {
 	Alias                         string        `long:"alias" description:"The node alias. Used as a moniker by peers and intelligence services"`
 	Color                         string        `long:"color" description:"The color of the node in hex format (i.e. '#3399FF'). Used to customize node appearance in intelligence services"`
 	MinChanSize                   int64         `long:"minchansize" description:"The smallest channel size (in satoshis) that we should accept. Incoming channels smaller than this will be rejected"`
-	MaxChanSize                   int64         `long:"maxchansize" description:"The largest channel size (in satoshis) that we should accept. Incoming channels larger than this will be rejected"`
+	MaxChanSize                   int64         `long:"maxchansize" description:"The largest channel size (in satoshis) that we should open or accept. Channels larger than this will be rejected. It can be overridden for single peers with chanpolicy.peer"`
 	CoopCloseTargetConfs          uint32        `long:"coop-close-target-confs" description:"The target number of blocks that a cooperative channel close transaction should confirm in. This is used to estimate the fee to use as the lower bound during fee negotiation for the channel closure."`
 
 	ChannelCommitInterval time.Duration `long:"channel-commit-interval" description:"The maximum time that is allowed to pass between receiving a channel state update and signing the next commitment. Setting this to a longer duration allows for more efficient channel operations at the cost of latency."`
@@ -397,6 +423,10 @@ type Config struct {
 
 	EnableUpfrontShutdown bool `long:"enable-upfront-shutdown" description:"If true, option upfront shutdown script will be enabled. If peers that we open channels with support this feature, we will automatically set the script to which cooperative closes should be paid out to on channel open. This offers the partial protection of a channel peer disconnecting from us if cooperative close is attempted with a different script."`
 
+	UpfrontShutdownAddr string `long:"upfront-shutdown-addr" description:"The address that cooperative closes of inbound channels should be paid out to, set as upfront shutdown script if the peer supports the option. A channel acceptor may override it per channel."`
+
+	RequireUpfrontShutdown bool `long:"require-upfront-shutdown" description:"If true, inbound channels from peers that don't support option upfront shutdown script are rejected, and a new wallet address is used as upfront shutdown script if none is configured."`
+
 	AcceptKeySend bool `long:"accept-keysend" description:"If true, spontaneous payments through keysend will be accepted. [experimental]"`
 
 	AcceptAMP bool `long:"accept-amp" description:"If true, spontaneous payments via AMP will be accepted."`
@@ -409,6 +439,10 @@ type Config struct {
 
 	DustThreshold uint64 `long:"dust-threshold" description:"Sets the dust sum threshold in satoshis for a channel after which dust HTLC's will be failed."`
 
+	DustLimitLegacy uint64 `long:"dust-limit-legacy" description:"The dust limit in satoshis we require for our commitment transaction in new channels without anchor outputs. If not set, the dust limit of the largest witness program is used."`
+
+	DustLimitAnchors uint64 `long:"dust-limit-anchors" description:"The dust limit in satoshis we require for our commitment transaction in new channels with anchor outputs. If not set, the dust limit of the largest witness program is used."`
+
 	Invoices *lncfg.Invoices `group:"invoices" namespace:"invoices"`
 
 	Routing *lncfg.Routing `group:"routing" namespace:"routing"`
@@ -439,6 +473,24 @@ type Config struct {
 
 	RemoteSigner *lncfg.RemoteSigner `group:"remotesigner" namespace:"remotesigner"`
 
+	HSM *lncfg.HSM `group:"hsm" namespace:"hsm"`
+
+	LiquidityAds *lncfg.LiquidityAds `group:"liquidityads" namespace:"liquidityads"`
+
+	ZeroReserve *lncfg.ZeroReserve `group:"zeroreserve" namespace:"zeroreserve"`
+
+	ChanPolicy *lncfg.ChanPolicy `group:"chanpolicy" namespace:"chanpolicy"`
+
+	TrafficStats *lncfg.TrafficStats `group:"trafficstats" namespace:"trafficstats"`
+
+	BackupSync *lncfg.BackupSync `group:"backupsync" namespace:"backupsync"`
+
+	Brontide *lncfg.Brontide `group:"brontide" namespace:"brontide"`
+
+	Wire *lncfg.Wire `group:"wire" namespace:"wire"`
+
+	BreachNotify *lncfg.BreachNotify `group:"breachnotify" namespace:"breachnotify"`
+
 	// LogWriter is the root logger that all of the daemon's subloggers are
 	// hooked up to.
 	LogWriter *build.RotatingLogWriter
@@ -454,6 +506,9 @@ type Config struct {
 
 	// ActiveNetParams contains parameters of the target chain.
 	ActiveNetParams chainreg.BitcoinNetParams
+
+	// ActiveNetwork is the network of the target chain we're running on.
+	ActiveNetwork *chainreg.Network
 }
 
 // DefaultConfig returns all default values for the Config struct.
@@ -466,6 +521,7 @@ func DefaultConfig() Config {
 		TLSCertPath:       defaultTLSCertPath,
 		TLSKeyPath:        defaultTLSKeyPath,
 		TLSCertDuration:   defaultTLSCertDuration,
+		TLSRotateBefore:   defaultTLSRotateBefore,
 		LetsEncryptDir:    defaultLetsEncryptDir,
 		LetsEncryptListen: defaultLetsEncryptListen,
 		LogDir:            defaultLogDir,
@@ -474,6 +530,7 @@ func DefaultConfig() Config {
 		AcceptorTimeout:   defaultAcceptorTimeout,
 		WSPingInterval:    lnrpc.DefaultPingInterval,
 		WSPongWait:        lnrpc.DefaultPongWait,
+		WSMaxFrameSize:    lnrpc.DefaultMaxFrameSize,
 		Bitcoin: &lncfg.Chain{
 			MinHTLCIn:     chainreg.DefaultBitcoinMinHTLCInMSat,
 			MinHTLCOut:    chainreg.DefaultBitcoinMinHTLCOutMSat,
@@ -575,6 +632,9 @@ func DefaultConfig() Config {
 		Watchtower: &lncfg.Watchtower{
 			TowerDir: defaultTowerDir,
 		},
+		WtClient: &lncfg.WtClient{
+			MaxUpdates: wtpolicy.DefaultMaxUpdates,
+		},
 		HealthChecks: &lncfg.HealthCheckConfig{
 			ChainCheck: &lncfg.CheckConfig{
 				Interval: defaultChainInterval,
@@ -609,22 +669,51 @@ func DefaultConfig() Config {
 				Attempts: defaultRSAttempts,
 				Backoff:  defaultRSBackoff,
 			},
+			HSM: &lncfg.CheckConfig{
+				Interval: defaultHSMInterval,
+				Timeout:  defaultHSMTimeout,
+				Attempts: defaultHSMAttempts,
+				Backoff:  defaultHSMBackoff,
+			},
 		},
 		Gossip: &lncfg.Gossip{
 			MaxChannelUpdateBurst: discovery.DefaultMaxChannelUpdateBurst,
 			ChannelUpdateInterval: discovery.DefaultChannelUpdateInterval,
+			RebroadcastInterval:   discovery.DefaultRebroadcastInterval,
+			RebroadcastJitter:     discovery.DefaultRebroadcastJitter,
 		},
 		Invoices: &lncfg.Invoices{
 			HoldExpiryDelta: lncfg.DefaultHoldInvoiceExpiryDelta,
+			GcInterval:      lncfg.DefaultInvoiceGcInterval,
+		},
+		MaxOutgoingCltvExpiry:   htlcswitch.DefaultMaxOutgoingCltvExpiry,
+		MaxChannelFeeAllocation: htlcswitch.DefaultMaxLinkFeeAllocation,
+		MaxCommitFeeRateAnchors: lnwallet.DefaultAnchorsCommitMaxFeeRateSatPerVByte,
+		DustThreshold:           uint64(htlcswitch.DefaultDustThreshold.ToSatoshis()),
+		LogWriter:               build.NewRotatingLogWriter(),
+		DB:                      lncfg.DefaultDB(),
+		Cluster:                 lncfg.DefaultCluster(),
+		RPCMiddleware:           lncfg.DefaultRPCMiddleware(),
+		LiquidityAds:            lncfg.DefaultLiquidityAds(),
+		ZeroReserve:             &lncfg.ZeroReserve{},
+		ChanPolicy:              &lncfg.ChanPolicy{},
+		TrafficStats: &lncfg.TrafficStats{
+			Interval: trafficstats.DefaultInterval,
+		},
+		BackupSync: &lncfg.BackupSync{
+			Timeout:       lncfg.DefaultBackupSyncTimeout,
+			RetryInterval: lncfg.DefaultBackupSyncRetryInterval,
+		},
+		Brontide: &lncfg.Brontide{
+			HandshakeTimeout: brontide.DefaultHandshakeTimeout,
+			ReadTimeout:      brontide.DefaultReadTimeout,
+			WriteTimeout:     brontide.DefaultWriteTimeout,
+		},
+		Wire: lncfg.DefaultWire(),
+		BreachNotify: &lncfg.BreachNotify{
+			Timeout:  breachnotify.DefaultTimeout,
+			Attempts: breachnotify.DefaultAttempts,
 		},
-		MaxOutgoingCltvExpiry:     htlcswitch.DefaultMaxOutgoingCltvExpiry,
-		MaxChannelFeeAllocation:   htlcswitch.DefaultMaxLinkFeeAllocation,
-		MaxCommitFeeRateAnchors:   lnwallet.DefaultAnchorsCommitMaxFeeRateSatPerVByte,
-		DustThreshold:             uint64(htlcswitch.DefaultDustThreshold.ToSatoshis()),
-		LogWriter:                 build.NewRotatingLogWriter(),
-		DB:                        lncfg.DefaultDB(),
-		Cluster:                   lncfg.DefaultCluster(),
-		RPCMiddleware:             lncfg.DefaultRPCMiddleware(),
 		registeredChains:          chainreg.NewChainRegistry(),
 		ActiveNetParams:           chainreg.BitcoinTestNetParams,
 		ChannelCommitInterval:     defaultChannelCommitInterval,
@@ -635,6 +724,9 @@ func DefaultConfig() Config {
 		RemoteSigner: &lncfg.RemoteSigner{
 			Timeout: lncfg.DefaultRemoteSignerRPCTimeout,
 		},
+		HSM: &lncfg.HSM{
+			KeyLabelPrefix: lncfg.DefaultHSMKeyLabelPrefix,
+		},
 	}
 }
 
@@ -664,36 +756,16 @@ func LoadConfig(interceptor signal.Interceptor) (*Config, error) {
 		os.Exit(0)
 	}
 
-	// If the config file path has not been modified by the user, then we'll
-	// use the default config file path. However, if the user has modified
-	// their lnddir, then we should assume they intend to use the config
-	// file within it.
-	configFileDir := CleanAndExpandPath(preCfg.LndDir)
-	configFilePath := CleanAndExpandPath(preCfg.ConfigFile)
-	switch {
-	// User specified --lnddir but no --configfile. Update the config file
-	// path to the lnd config directory, but don't require it to exist.
-	case configFileDir != DefaultLndDir &&
-		configFilePath == DefaultConfigFile:
-
-		configFilePath = filepath.Join(
-			configFileDir, lncfg.DefaultConfigFilename,
-		)
-
-	// User did specify an explicit --configfile, so we check that it does
-	// exist under that path to avoid surprises.
-	case configFilePath != DefaultConfigFile:
-		if !fileExists(configFilePath) {
-			return nil, fmt.Errorf("specified config file does "+
-				"not exist in %s", configFilePath)
-		}
+	configFilePath, err := resolveConfigFile(&preCfg)
+	if err != nil {
+		return nil, err
 	}
 
 	// Next, load any additional configuration options from the file.
 	var configFileError error
 	cfg := preCfg
 	fileParser := flags.NewParser(&cfg, flags.Default)
-	err := flags.NewIniParser(fileParser).ParseFile(configFilePath)
+	err = flags.NewIniParser(fileParser).ParseFile(configFilePath)
 	if err != nil {
 		// If it's a parsing related error, then we'll return
 		// immediately, otherwise we can proceed as possibly the config
@@ -748,6 +820,37 @@ func LoadConfig(interceptor signal.Interceptor) (*Config, error) {
 	return cleanCfg, nil
 }
 
+// resolveConfigFile returns the path of the config file to load, based on the
+// lnd directory and config file options of the pre-parsed command line.
+func resolveConfigFile(preCfg *Config) (string, error) {
+	// If the config file path has not been modified by the user, then we'll
+	// use the default config file path. However, if the user has modified
+	// their lnddir, then we should assume they intend to use the config
+	// file within it.
+	configFileDir := CleanAndExpandPath(preCfg.LndDir)
+	configFilePath := CleanAndExpandPath(preCfg.ConfigFile)
+	switch {
+	// User specified --lnddir but no --configfile. Update the config file
+	// path to the lnd config directory, but don't require it to exist.
+	case configFileDir != DefaultLndDir &&
+		configFilePath == DefaultConfigFile:
+
+		configFilePath = filepath.Join(
+			configFileDir, lncfg.DefaultConfigFilename,
+		)
+
+	// User did specify an explicit --configfile, so we check that it does
+	// exist under that path to avoid surprises.
+	case configFilePath != DefaultConfigFile:
+		if !fileExists(configFilePath) {
+			return "", fmt.Errorf("specified config file does "+
+				"not exist in %s", configFilePath)
+		}
+	}
+
+	return configFilePath, nil
+}
+
 // usageError is an error type that signals a problem with the supplied flags.
 type usageError struct {
 	err error
@@ -881,9 +984,14 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 	cfg.Tor.WatchtowerKeyPath = CleanAndExpandPath(cfg.Tor.WatchtowerKeyPath)
 	cfg.Watchtower.TowerDir = CleanAndExpandPath(cfg.Watchtower.TowerDir)
 	cfg.BackupFilePath = CleanAndExpandPath(cfg.BackupFilePath)
+	cfg.Invoices.GcArchiveFile = CleanAndExpandPath(
+		cfg.Invoices.GcArchiveFile,
+	)
 	cfg.WalletUnlockPasswordFile = CleanAndExpandPath(
 		cfg.WalletUnlockPasswordFile,
 	)
+	cfg.HSM.Module = CleanAndExpandPath(cfg.HSM.Module)
+	cfg.HSM.PINFile = CleanAndExpandPath(cfg.HSM.PINFile)
 
 	// Ensure that the user didn't attempt to specify negative values for
 	// any of the autopilot params.
@@ -929,13 +1037,12 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 	// For non-Wumbo channels this limit remains 16777215 satoshis by default
 	// as specified in BOLT-02. For wumbo channels this limit is 1,000,000,000.
 	// satoshis (10 BTC). Always enforce --maxchansize explicitly set by user.
-	// If unset (marked by 0 value), then enforce proper default.
-	if cfg.MaxChanSize == 0 {
-		if cfg.ProtocolOptions.Wumbo() {
-			cfg.MaxChanSize = int64(funding.MaxBtcFundingAmountWumbo)
-		} else {
-			cfg.MaxChanSize = int64(funding.MaxBtcFundingAmount)
-		}
+	// If unset (marked by 0 value), then default to the wumbo limit. The
+	// funding manager caps it at the non-wumbo limit for as long as wumbo
+	// channels are disabled, which may change while lnd is running.
+	maxChanSizeSet := cfg.MaxChanSize != 0
+	if !maxChanSizeSet {
+		cfg.MaxChanSize = int64(funding.MaxBtcFundingAmountWumbo)
 	}
 
 	// Ensure that the user specified values for the min and max channel
@@ -949,7 +1056,7 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 
 	// Don't allow superfluous --maxchansize greater than
 	// BOLT 02 soft-limit for non-wumbo channel
-	if !cfg.ProtocolOptions.Wumbo() &&
+	if maxChanSizeSet && !cfg.ProtocolOptions.Wumbo() &&
 		cfg.MaxChanSize > int64(MaxFundingAmount) {
 
 		return nil, mkErr("invalid channel size parameters: "+
@@ -959,6 +1066,16 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		)
 	}
 
+	// The TLS certificate must be rotated within the validity of a newly
+	// generated certificate, otherwise it would be rotated right away.
+	if cfg.TLSAutoRotate && (cfg.TLSRotateBefore <= 0 ||
+		cfg.TLSRotateBefore >= cfg.TLSCertDuration) {
+
+		return nil, mkErr("invalid tlsrotatebefore: %v, must be "+
+			"positive and less than tlscertduration %v",
+			cfg.TLSRotateBefore, cfg.TLSCertDuration)
+	}
+
 	// Ensure a valid max channel fee allocation was set.
 	if cfg.MaxChannelFeeAllocation <= 0 || cfg.MaxChannelFeeAllocation > 1 {
 		return nil, mkErr("invalid max channel fee allocation: %v, "+
@@ -971,6 +1088,12 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 			cfg.MaxCommitFeeRateAnchors)
 	}
 
+	// Ensure our peers accept the configured dust limits.
+	dustLimits := cfg.dustLimitPolicy()
+	if err := dustLimits.Validate(); err != nil {
+		return nil, mkErr("invalid dust limit: %v", err)
+	}
+
 	// Validate the Tor config parameters.
 	socks, err := lncfg.ParseAddressString(
 		cfg.Tor.SOCKS, strconv.Itoa(defaultTorSOCKSPort),
@@ -1092,70 +1215,40 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 			return nil, mkErr("error validating litecoin: %v", err)
 		}
 
-		// Multiple networks can't be selected simultaneously.  Count
-		// number of network flags passed; assign active network params
-		// while we're at it.
-		numNets := 0
-		var ltcParams chainreg.LitecoinNetParams
-		if cfg.Litecoin.MainNet {
-			numNets++
-			ltcParams = chainreg.LitecoinMainNetParams
-		}
-		if cfg.Litecoin.TestNet3 {
-			numNets++
-			ltcParams = chainreg.LitecoinTestNetParams
-		}
-		if cfg.Litecoin.RegTest {
-			numNets++
-			ltcParams = chainreg.LitecoinRegTestNetParams
-		}
-		if cfg.Litecoin.SimNet {
-			numNets++
-			ltcParams = chainreg.LitecoinSimNetParams
-		}
-		if cfg.Litecoin.SigNet {
-			return nil, mkErr("litecoin.signet is not supported")
-		}
-
-		if numNets > 1 {
-			str := "The mainnet, testnet, and simnet params " +
-				"can't be used together -- choose one of the " +
-				"three"
-			return nil, mkErr(str)
-		}
-
-		// The target network must be provided, otherwise, we won't
-		// know how to initialize the daemon.
-		if numNets == 0 {
-			str := "either --litecoin.mainnet, or " +
-				"litecoin.testnet must be specified"
-			return nil, mkErr(str)
+		network, err := selectNetwork(
+			chainreg.LitecoinChain, cfg.Litecoin,
+		)
+		if err != nil {
+			return nil, mkErr("%v", err)
 		}
+		cfg.ActiveNetwork = network
 
 		// The litecoin chain is the current active chain. However
 		// throughout the codebase we required chaincfg.Params. So as a
 		// temporary hack, we'll mutate the default net params for
 		// bitcoin with the litecoin specific information.
-		chainreg.ApplyLitecoinParams(&cfg.ActiveNetParams, &ltcParams)
+		cfg.ActiveNetParams = network.NetParams(cfg.ActiveNetParams)
 
 		switch cfg.Litecoin.Node {
 		case "ltcd":
 			err := parseRPCParams(
 				cfg.Litecoin, cfg.LtcdMode,
-				chainreg.LitecoinChain, cfg.ActiveNetParams,
+				chainreg.LitecoinChain, cfg.ActiveNetwork,
+				cfg.ActiveNetParams,
 			)
 			if err != nil {
 				return nil, mkErr("unable to load RPC "+
 					"credentials for ltcd: %v", err)
 			}
 		case "litecoind":
-			if cfg.Litecoin.SimNet {
-				return nil, mkErr("litecoind does not " +
-					"support simnet")
+			if cfg.ActiveNetwork.BtcdOnly {
+				return nil, mkErr("litecoind does not "+
+					"support %v", cfg.ActiveNetwork.Name)
 			}
 			err := parseRPCParams(
 				cfg.Litecoin, cfg.LitecoindMode,
-				chainreg.LitecoinChain, cfg.ActiveNetParams,
+				chainreg.LitecoinChain, cfg.ActiveNetwork,
+				cfg.ActiveNetParams,
 			)
 			if err != nil {
 				return nil, mkErr("unable to load RPC "+
@@ -1178,82 +1271,16 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		MaxFundingAmount = funding.MaxLtcFundingAmount
 
 	case cfg.Bitcoin.Active:
-		// Multiple networks can't be selected simultaneously.  Count
-		// number of network flags passed; assign active network params
-		// while we're at it.
-		numNets := 0
-		if cfg.Bitcoin.MainNet {
-			numNets++
-			cfg.ActiveNetParams = chainreg.BitcoinMainNetParams
-		}
-		if cfg.Bitcoin.TestNet3 {
-			numNets++
-			cfg.ActiveNetParams = chainreg.BitcoinTestNetParams
-		}
-		if cfg.Bitcoin.RegTest {
-			numNets++
-			cfg.ActiveNetParams = chainreg.BitcoinRegTestNetParams
-		}
-		if cfg.Bitcoin.SimNet {
-			numNets++
-			cfg.ActiveNetParams = chainreg.BitcoinSimNetParams
-		}
-		if cfg.Bitcoin.SigNet {
-			numNets++
-			cfg.ActiveNetParams = chainreg.BitcoinSigNetParams
-
-			// Let the user overwrite the default signet parameters.
-			// The challenge defines the actual signet network to
-			// join and the seed nodes are needed for network
-			// discovery.
-			sigNetChallenge := chaincfg.DefaultSignetChallenge
-			sigNetSeeds := chaincfg.DefaultSignetDNSSeeds
-			if cfg.Bitcoin.SigNetChallenge != "" {
-				challenge, err := hex.DecodeString(
-					cfg.Bitcoin.SigNetChallenge,
-				)
-				if err != nil {
-					return nil, mkErr("Invalid "+
-						"signet challenge, hex decode "+
-						"failed: %v", err)
-				}
-				sigNetChallenge = challenge
-			}
-
-			if len(cfg.Bitcoin.SigNetSeedNode) > 0 {
-				sigNetSeeds = make([]chaincfg.DNSSeed, len(
-					cfg.Bitcoin.SigNetSeedNode,
-				))
-				for idx, seed := range cfg.Bitcoin.SigNetSeedNode {
-					sigNetSeeds[idx] = chaincfg.DNSSeed{
-						Host:         seed,
-						HasFiltering: false,
-					}
-				}
-			}
-
-			chainParams := chaincfg.CustomSignetParams(
-				sigNetChallenge, sigNetSeeds,
-			)
-			cfg.ActiveNetParams.Params = &chainParams
-		}
-		if numNets > 1 {
-			str := "The mainnet, testnet, regtest, and simnet " +
-				"params can't be used together -- choose one " +
-				"of the four"
-			return nil, mkErr(str)
-		}
-
-		// The target network must be provided, otherwise, we won't
-		// know how to initialize the daemon.
-		if numNets == 0 {
-			str := "either --bitcoin.mainnet, or bitcoin.testnet," +
-				"bitcoin.simnet, or bitcoin.regtest " +
-				"must be specified"
-			return nil, mkErr(str)
+		network, err := selectNetwork(
+			chainreg.BitcoinChain, cfg.Bitcoin,
+		)
+		if err != nil {
+			return nil, mkErr("%v", err)
 		}
+		cfg.ActiveNetwork = network
+		cfg.ActiveNetParams = network.NetParams(cfg.ActiveNetParams)
 
-		err := cfg.Bitcoin.Validate(
+		err = cfg.Bitcoin.Validate(
 			minTimeLockDelta, funding.MinBtcRemoteDelay,
 		)
 		if err != nil {
@@ -1263,29 +1290,41 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 
 		switch cfg.Bitcoin.Node {
 		case "btcd":
+			if cfg.ActiveNetwork.BitcoindOnly {
+				return nil, mkErr("btcd does not support %v",
+					cfg.ActiveNetwork.Name)
+			}
+
 			err := parseRPCParams(
 				cfg.Bitcoin, cfg.BtcdMode,
-				chainreg.BitcoinChain, cfg.ActiveNetParams,
+				chainreg.BitcoinChain, cfg.ActiveNetwork,
+				cfg.ActiveNetParams,
 			)
 			if err != nil {
 				return nil, mkErr("unable to load RPC "+
 					"credentials for btcd: %v", err)
 			}
 		case "bitcoind":
-			if cfg.Bitcoin.SimNet {
-				return nil, mkErr("bitcoind does not " +
-					"support simnet")
+			if cfg.ActiveNetwork.BtcdOnly {
+				return nil, mkErr("bitcoind does not "+
+					"support %v", cfg.ActiveNetwork.Name)
 			}
 
 			err := parseRPCParams(
 				cfg.Bitcoin, cfg.BitcoindMode,
-				chainreg.BitcoinChain, cfg.ActiveNetParams,
+				chainreg.BitcoinChain, cfg.ActiveNetwork,
+				cfg.ActiveNetParams,
 			)
 			if err != nil {
 				return nil, mkErr("unable to load RPC "+
 					"credentials for bitcoind: %v", err)
 			}
 		case "neutrino":
+			if cfg.ActiveNetwork.BitcoindOnly {
+				return nil, mkErr("neutrino does not "+
+					"support %v", cfg.ActiveNetwork.Name)
+			}
+
 			// No need to get RPC parameters.
 
 		case "nochainbackend":
@@ -1366,7 +1405,7 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 	cfg.networkDir = filepath.Join(
 		cfg.DataDir, defaultChainSubDirname,
 		cfg.registeredChains.PrimaryChain().String(),
-		lncfg.NormalizeNetwork(cfg.ActiveNetParams.Name),
+		cfg.ActiveNetwork.Name,
 	)
 
 	// If a custom macaroon directory wasn't specified and the data
@@ -1418,7 +1457,7 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 	// per network in the same fashion as the data directory.
 	cfg.LogDir = filepath.Join(
 		cfg.LogDir, cfg.registeredChains.PrimaryChain().String(),
-		lncfg.NormalizeNetwork(cfg.ActiveNetParams.Name),
+		cfg.ActiveNetwork.Name,
 	)
 
 	// A log writer must be passed in, otherwise we can't function and would
@@ -1504,6 +1543,11 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		return nil, mkErr("cannot set noseedbackup and " +
 			"wallet-unlock-password-file at the same time")
 
+	// The keys can't be kept in an HSM if a remote signer holds them.
+	case cfg.HSM.Active && cfg.RemoteSigner.Enable:
+		return nil, mkErr("cannot use an HSM together with a remote " +
+			"signer")
+
 	// The "allow-create" flag cannot be set without the auto unlock file.
 	case cfg.WalletUnlockAllowCreate && cfg.WalletUnlockPasswordFile == "":
 		return nil, mkErr("cannot set wallet-unlock-allow-create " +
@@ -1546,6 +1590,8 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 	if cfg.DisableListen {
 		ltndLog.Infof("Listening on the p2p interface is disabled!")
 		cfg.Listeners = nil
+		cfg.WSListeners = nil
+		cfg.WSSListeners = nil
 		cfg.ExternalIPs = nil
 	} else {
 
@@ -1570,10 +1616,53 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 			return nil, err
 		}
 
+		// There is no default port for WebSocket peer connections, so
+		// we require it to be set explicitly.
+		normalizeWSListeners := func(option string,
+			rawAddrs []string) ([]net.Addr, error) {
+
+			for _, addr := range rawAddrs {
+				_, _, err := net.SplitHostPort(addr)
+				if err != nil {
+					return nil, mkErr("invalid %v address "+
+						"%v, the port must be "+
+						"specified: %v", option, addr,
+						err)
+				}
+			}
+
+			addrs, err := lncfg.NormalizeAddresses(
+				rawAddrs, strconv.Itoa(defaultPeerPort),
+				cfg.net.ResolveTCPAddr,
+			)
+			if err != nil {
+				return nil, mkErr("error normalizing %v "+
+					"addrs: %v", option, err)
+			}
+
+			return addrs, nil
+		}
+		cfg.WSListeners, err = normalizeWSListeners(
+			"wslisten", cfg.RawWSListeners,
+		)
+		if err != nil {
+			return nil, err
+		}
+		cfg.WSSListeners, err = normalizeWSListeners(
+			"wsslisten", cfg.RawWSSListeners,
+		)
+		if err != nil {
+			return nil, err
+		}
+
 		// For the p2p port it makes no sense to listen to an Unix socket.
 		// Also, we would need to refactor the brontide listener to support
 		// that.
-		for _, p2pListener := range cfg.Listeners {
+		var p2pListeners []net.Addr
+		p2pListeners = append(p2pListeners, cfg.Listeners...)
+		p2pListeners = append(p2pListeners, cfg.WSListeners...)
+		p2pListeners = append(p2pListeners, cfg.WSSListeners...)
+		for _, p2pListener := range p2pListeners {
 			if lncfg.IsUnix(p2pListener) {
 				return nil, mkErr("unix socket addresses "+
 					"cannot be used for the p2p "+
@@ -1651,11 +1740,35 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		cfg.HealthChecks,
 		cfg.RPCMiddleware,
 		cfg.RemoteSigner,
+		cfg.HSM,
+		cfg.LiquidityAds,
+		cfg.ZeroReserve,
+		cfg.ChanPolicy,
+		cfg.Routing,
+		cfg.TrafficStats,
+		cfg.BackupSync,
+		cfg.Brontide,
+		cfg.Wire,
+		cfg.Gossip,
+		cfg.Invoices,
+		cfg.BreachNotify,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	// Make sure that justice transactions can pay into the sweep address
+	// of the watchtower client, so backups don't fail later on.
+	if cfg.WtClient.SweepAddress != "" {
+		_, err := sweepAddrPkScript(
+			cfg.WtClient.SweepAddress, cfg.ActiveNetParams.Params,
+		)
+		if err != nil {
+			return nil, mkErr("invalid wtclient.sweep-address: %v",
+				err)
+		}
+	}
+
 	// Finally, ensure that the user's color is correctly formatted,
 	// otherwise the server will not be able to start after the unlocking
 	// the wallet.
@@ -1673,10 +1786,19 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 func (c *Config) graphDatabaseDir() string {
 	return filepath.Join(
 		c.DataDir, defaultGraphSubDirname,
-		lncfg.NormalizeNetwork(c.ActiveNetParams.Name),
+		c.ActiveNetwork.Name,
 	)
 }
 
+// dustLimitPolicy returns the dust limits we require for our commitment
+// transaction per commitment type.
+func (c *Config) dustLimitPolicy() lnwallet.DustLimitPolicy {
+	return lnwallet.DustLimitPolicy{
+		Legacy:  btcutil.Amount(c.DustLimitLegacy),
+		Anchors: btcutil.Amount(c.DustLimitAnchors),
+	}
+}
+
 // ImplementationConfig returns the configuration of what actual implementations
 // should be used when creating the main lnd instance.
 func (c *Config) ImplementationConfig(
@@ -1697,19 +1819,27 @@ func (c *Config) ImplementationConfig(
 			DatabaseBuilder: NewDefaultDatabaseBuilder(
 				c, ltndLog,
 			),
-			WalletConfigBuilder: rpcImpl,
-			ChainControlBuilder: rpcImpl,
+			WalletConfigBuilder:   rpcImpl,
+			ChainControlBuilder:   rpcImpl,
+			OnionProcessorBuilder: rpcImpl,
+			HtlcHoldHookBuilder:   rpcImpl,
+			EdgeScorerBuilder:     rpcImpl,
+			HSMBuilder:            rpcImpl,
 		}
 	}
 
 	defaultImpl := NewDefaultWalletImpl(c, ltndLog, interceptor, false)
 	return &ImplementationCfg{
-		GrpcRegistrar:       defaultImpl,
-		RestRegistrar:       defaultImpl,
-		ExternalValidator:   defaultImpl,
-		DatabaseBuilder:     NewDefaultDatabaseBuilder(c, ltndLog),
-		WalletConfigBuilder: defaultImpl,
-		ChainControlBuilder: defaultImpl,
+		GrpcRegistrar:         defaultImpl,
+		RestRegistrar:         defaultImpl,
+		ExternalValidator:     defaultImpl,
+		DatabaseBuilder:       NewDefaultDatabaseBuilder(c, ltndLog),
+		WalletConfigBuilder:   defaultImpl,
+		ChainControlBuilder:   defaultImpl,
+		OnionProcessorBuilder: defaultImpl,
+		HtlcHoldHookBuilder:   defaultImpl,
+		EdgeScorerBuilder:     defaultImpl,
+		HSMBuilder:            defaultImpl,
 	}
 }
 
@@ -1739,8 +1869,108 @@ func CleanAndExpandPath(path string) string {
 	return filepath.Clean(os.ExpandEnv(path))
 }
 
+// selectNetwork returns the network that was selected in the configuration of
+// the given chain. Exactly one network must be selected.
+func selectNetwork(chain chainreg.ChainCode,
+	chainCfg *lncfg.Chain) (*chainreg.Network, error) {
+
+	// Multiple networks can't be selected simultaneously.
+	names := chainCfg.Networks()
+	switch {
+	case len(names) > 1:
+		return nil, fmt.Errorf("the %v networks can't be used "+
+			"together -- choose one", strings.Join(names, ", "))
+
+	// The target network must be provided, otherwise, we won't know how
+	// to initialize the daemon.
+	case len(names) == 0:
+		return nil, fmt.Errorf("either --%v.network or the flag of a "+
+			"network such as --%v.mainnet or --%v.testnet must be "+
+			"specified", chain, chain, chain)
+	}
+
+	network, err := chainreg.LookupNetwork(chain, names[0])
+	switch {
+	// A signet with a custom challenge can be given a name of its own,
+	// which keeps its data apart from the data of other signets.
+	case err != nil && chain == chainreg.BitcoinChain &&
+		chainCfg.SigNetChallenge != "":
+
+		return customSignet(names[0], chainCfg)
+
+	case err != nil:
+		return nil, err
+
+	// Let the user overwrite the default signet parameters.
+	case network.Name == "signet" && (chainCfg.SigNetChallenge != "" ||
+		len(chainCfg.SigNetSeedNode) > 0):
+
+		return customSignet(network.Name, chainCfg)
+	}
+
+	return network, nil
+}
+
+// customSignet returns a signet network with the given name, whose challenge
+// and seed nodes are taken from the configuration of the chain. The challenge
+// defines the actual signet network to join and the seed nodes are needed for
+// network discovery. The default seed nodes are only used together with the
+// default challenge, as they belong to the global default signet.
+func customSignet(name string, chainCfg *lncfg.Chain) (*chainreg.Network,
+	error) {
+
+	if err := chainreg.ValidateNetworkName(name); err != nil {
+		return nil, err
+	}
+
+	sigNetChallenge := chaincfg.DefaultSignetChallenge
+	sigNetSeeds := chaincfg.DefaultSignetDNSSeeds
+	if chainCfg.SigNetChallenge != "" {
+		challenge, err := hex.DecodeString(chainCfg.SigNetChallenge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signet challenge, hex "+
+				"decode failed: %v", err)
+		}
+		sigNetChallenge = challenge
+		sigNetSeeds = nil
+	}
+
+	if len(chainCfg.SigNetSeedNode) > 0 {
+		sigNetSeeds = make([]chaincfg.DNSSeed, len(
+			chainCfg.SigNetSeedNode,
+		))
+		for idx, seed := range chainCfg.SigNetSeedNode {
+			sigNetSeeds[idx] = chaincfg.DNSSeed{
+				Host:         seed,
+				HasFiltering: false,
+			}
+		}
+	}
+
+	// The parameters keep the signet name, as that is what the rest of
+	// the daemon, like the invoice encoding, identifies a signet by.
+	chainParams := chaincfg.CustomSignetParams(
+		sigNetChallenge, sigNetSeeds,
+	)
+
+	// A custom signet is a development network, which has no lightning
+	// DNS seeds of its own.
+	return &chainreg.Network{
+		Name:  name,
+		Chain: chainreg.BitcoinChain,
+		BitcoinParams: &chainreg.BitcoinNetParams{
+			Params:   &chainParams,
+			RPCPort:  chainreg.BitcoinSigNetParams.RPCPort,
+			CoinType: chainreg.BitcoinSigNetParams.CoinType,
+		},
+		FallbackRPCPort: 38332,
+		DevNetwork:      true,
+	}, nil
+}
+
 func parseRPCParams(cConfig *lncfg.Chain, nodeConfig interface{},
-	net chainreg.ChainCode, netParams chainreg.BitcoinNetParams) error {
+	net chainreg.ChainCode, network *chainreg.Network,
+	netParams chainreg.BitcoinNetParams) error {
 
 	// First, we'll check our node config to make sure the RPC parameters
 	// were set correctly. We'll also determine the path to the conf file
@@ -1865,9 +2095,9 @@ func parseRPCParams(cConfig *lncfg.Chain, nodeConfig interface{},
 	// If we're in simnet mode, then the running btcd instance won't read
 	// the RPC credentials from the configuration. So if lnd wasn't
 	// specified the parameters, then we won't be able to start.
-	if cConfig.SimNet {
-		return fmt.Errorf("rpcuser and rpcpass must be set to your " +
-			"btcd node's RPC parameters for simnet mode")
+	if network.BtcdOnly {
+		return fmt.Errorf("rpcuser and rpcpass must be set to your "+
+			"btcd node's RPC parameters for %v mode", network.Name)
 	}
 
 	fmt.Println("Attempting automatic RPC configuration to " + daemonName)
@@ -2018,7 +2248,7 @@ func extractBitcoindRPCParams(networkName, bitcoindDataDir, bitcoindConfigPath,
 	switch networkName {
 	case "mainnet":
 		chainDir = ""
-	case "regtest", "testnet3", "signet":
+	case "regtest", "testnet3", "testnet4", "signet":
 		chainDir = networkName
 	default:
 		return "", "", "", "", fmt.Errorf("unexpected networkname %v", networkName)
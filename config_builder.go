@@ -3,6 +3,7 @@ package lnd
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -21,10 +22,18 @@ import (
 	"github.com/btcsuite/btcwallet/walletdb"
 	proxy "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/lightninglabs/neutrino"
+	"github.com/lightninglabs/neutrino/banman"
 	"github.com/lightninglabs/neutrino/headerfs"
+	sphinx "github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/blockcache"
 	"github.com/lightningnetwork/lnd/chainreg"
+	"github.com/lightningnetwork/lnd/chanacceptor"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channelnotifier"
+	"github.com/lightningnetwork/lnd/funding"
+	"github.com/lightningnetwork/lnd/htlcswitch/hop"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/invoices"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lncfg"
@@ -33,6 +42,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/rpcwallet"
 	"github.com/lightningnetwork/lnd/macaroons"
+	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/rpcperms"
 	"github.com/lightningnetwork/lnd/signal"
 	"github.com/lightningnetwork/lnd/walletunlocker"
@@ -108,6 +118,62 @@ type ChainControlBuilder interface {
 		*btcwallet.Config) (*chainreg.ChainControl, func(), error)
 }
 
+// OnionProcessorBuilder is an interface that must be satisfied by a custom
+// onion processor implementation.
+type OnionProcessorBuilder interface {
+	// BuildOnionProcessor is responsible for creating the onion processor
+	// that is used to decode the onion packets of incoming HTLCs. The
+	// passed sphinx router is backed by the node's identity key and the
+	// on-disk replay log.
+	BuildOnionProcessor(*sphinx.Router) (hop.Processor, error)
+}
+
+// HSMBuilder is an interface that must be satisfied by a custom hardware
+// security module implementation.
+type HSMBuilder interface {
+	// BuildHSM is responsible for opening a logged in session with the
+	// hardware security module described by the passed config. The
+	// returned function closes the session.
+	BuildHSM(*lncfg.HSM) (keychain.HSM, func(), error)
+}
+
+// HtlcHoldHookDeps contains the subsystems that an invoice htlc hold hook can
+// use to open a just-in-time channel before it releases a held htlc.
+type HtlcHoldHookDeps struct {
+	// ChannelAcceptor is the acceptor that is consulted for every inbound
+	// channel. It can be used to accept zero-conf channels from a trusted
+	// liquidity service provider.
+	ChannelAcceptor chanacceptor.MultiplexAcceptor
+
+	// OpenChannel initiates a new channel funding flow with a connected
+	// peer, in the same way the OpenChannel RPC does.
+	OpenChannel func(*funding.InitFundingMsg) (
+		chan *lnrpc.OpenStatusUpdate, chan error)
+
+	// ChannelNotifier can be used to wait for a new channel to become
+	// active.
+	ChannelNotifier *channelnotifier.ChannelNotifier
+}
+
+// HtlcHoldHookBuilder is an interface that must be satisfied by a custom
+// invoice htlc hold hook implementation.
+type HtlcHoldHookBuilder interface {
+	// BuildHtlcHoldHook is responsible for creating the hook that is
+	// offered every htlc paying to one of our invoices before it is
+	// processed. A nil hook disables holding htlcs.
+	BuildHtlcHoldHook(*HtlcHoldHookDeps) (invoices.HtlcHoldHook, error)
+}
+
+// EdgeScorerBuilder is an interface that must be satisfied by a custom path
+// finding edge scorer implementation.
+type EdgeScorerBuilder interface {
+	// BuildEdgeScorers is responsible for creating the scorers that are
+	// consulted by path finding in addition to its default scoring of
+	// the edges, for example to avoid or prefer certain nodes. An empty
+	// list keeps the default scoring.
+	BuildEdgeScorers(*channeldb.ChannelGraph) ([]routing.EdgeScorer, error)
+}
+
 // ImplementationCfg is a struct that holds all configuration items for
 // components that can be implemented outside lnd itself.
 type ImplementationCfg struct {
@@ -134,6 +200,23 @@ type ImplementationCfg struct {
 	// ChainControlBuilder is a type that can provide a custom wallet
 	// implementation.
 	ChainControlBuilder
+
+	// OnionProcessorBuilder is a type that can provide a custom onion
+	// processor implementation.
+	OnionProcessorBuilder
+
+	// HtlcHoldHookBuilder is a type that can provide a custom invoice
+	// htlc hold hook, for example to implement receive flows through a
+	// liquidity service provider.
+	HtlcHoldHookBuilder
+
+	// EdgeScorerBuilder is a type that can provide custom edge scorers
+	// that bias the routes selected by path finding.
+	EdgeScorerBuilder
+
+	// HSMBuilder is a type that can provide a session with the hardware
+	// security module that keeps the node identity and funding keys.
+	HSMBuilder
 }
 
 // DefaultWalletImpl is the default implementation of our normal, btcwallet
@@ -253,11 +336,15 @@ func (d *DefaultWalletImpl) BuildWalletConfig(ctx context.Context,
 	if d.cfg.registeredChains.PrimaryChain() == chainreg.LitecoinChain {
 		mainChain = d.cfg.Litecoin
 	}
-	var neutrinoCS *neutrino.ChainService
+	var (
+		neutrinoCS       *neutrino.ChainService
+		neutrinoBanStore banman.Store
+	)
 	if mainChain.Node == "neutrino" {
-		neutrinoBackend, neutrinoCleanUp, err := initNeutrinoBackend(
-			d.cfg, mainChain.ChainDir, blockCache,
-		)
+		neutrinoBackend, banStore, neutrinoCleanUp, err :=
+			initNeutrinoBackend(
+				d.cfg, mainChain.ChainDir, blockCache,
+			)
 		if err != nil {
 			err := fmt.Errorf("unable to initialize neutrino "+
 				"backend: %v", err)
@@ -266,6 +353,7 @@ func (d *DefaultWalletImpl) BuildWalletConfig(ctx context.Context,
 		}
 		cleanUpTasks = append(cleanUpTasks, neutrinoCleanUp)
 		neutrinoCS = neutrinoBackend
+		neutrinoBanStore = banStore
 	}
 
 	var (
@@ -400,7 +488,7 @@ func (d *DefaultWalletImpl) BuildWalletConfig(ctx context.Context,
 		}
 		macaroonService, err = macaroons.NewService(
 			rootKeyStore, "lnd", walletInitParams.StatelessInit,
-			macaroons.IPLockChecker,
+			macaroons.IPLockChecker, macaroons.SpendLimitChecker,
 			macaroons.CustomChecker(interceptorChain),
 		)
 		if err != nil {
@@ -549,7 +637,9 @@ func (d *DefaultWalletImpl) BuildWalletConfig(ctx context.Context,
 		HeightHintDB:                dbs.HeightHintDB,
 		ChanStateDB:                 dbs.ChanStateDB.ChannelStateDB(),
 		NeutrinoCS:                  neutrinoCS,
+		NeutrinoBanStore:            neutrinoBanStore,
 		ActiveNetParams:             d.cfg.ActiveNetParams,
+		ActiveNetwork:               d.cfg.ActiveNetwork,
 		FeeURL:                      d.cfg.FeeURL,
 		Dialer: func(addr string) (net.Conn, error) {
 			return d.cfg.net.Dial(
@@ -622,21 +712,37 @@ func (d *DefaultWalletImpl) BuildChainControl(
 		return nil, nil, err
 	}
 
-	keyRing := keychain.NewBtcWalletKeyRing(
-		walletController.InternalWallet(), walletConfig.CoinType,
+	var (
+		keyRing keychain.SecretKeyRing = keychain.NewBtcWalletKeyRing(
+			walletController.InternalWallet(),
+			walletConfig.CoinType,
+		)
+		signer input.Signer = walletController
 	)
 
+	// If the node identity and funding keys are kept in a hardware
+	// security module, all other keys are still derived from the wallet.
+	if hsm := partialChainControl.Cfg.HSM; hsm != nil {
+		hsmKeyRing := keychain.NewHSMKeyRing(
+			hsm, keyRing, d.cfg.HSM.KeyLabelPrefix,
+			keychain.DefaultHSMKeyFamilies...,
+		)
+		keyRing = hsmKeyRing
+		signer = input.NewHSMSigner(walletController, hsmKeyRing)
+	}
+
 	// Create, and start the lnwallet, which handles the core payment
 	// channel logic, and exposes control via proxy state machines.
 	lnWalletConfig := lnwallet.Config{
 		Database:           partialChainControl.Cfg.ChanStateDB,
 		Notifier:           partialChainControl.ChainNotifier,
 		WalletController:   walletController,
-		Signer:             walletController,
+		Signer:             signer,
 		FeeEstimator:       partialChainControl.FeeEstimator,
 		SecretKeyRing:      keyRing,
 		ChainIO:            walletController,
 		DefaultConstraints: partialChainControl.ChannelConstraints,
+		DustLimits:         d.cfg.dustLimitPolicy(),
 		NetParams:          *walletConfig.NetParams,
 	}
 
@@ -654,6 +760,60 @@ func (d *DefaultWalletImpl) BuildChainControl(
 	return activeChainControl, cleanUp, nil
 }
 
+// BuildOnionProcessor is responsible for creating the onion processor that is
+// used to decode the onion packets of incoming HTLCs.
+//
+// NOTE: This is part of the OnionProcessorBuilder interface.
+func (d *DefaultWalletImpl) BuildOnionProcessor(
+	router *sphinx.Router) (hop.Processor, error) {
+
+	return hop.NewOnionProcessor(router), nil
+}
+
+// BuildHSM is responsible for opening a session with the hardware security
+// module. The default implementation logs into the configured PKCS#11 token
+// with the PIN read from the PIN file, which requires lnd to be built with the
+// hsm build tag.
+//
+// NOTE: This is part of the HSMBuilder interface.
+func (d *DefaultWalletImpl) BuildHSM(cfg *lncfg.HSM) (keychain.HSM,
+	func(), error) {
+
+	pinBytes, err := ioutil.ReadFile(cfg.PINFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading HSM PIN from file "+
+			"%s: %v", cfg.PINFile, err)
+	}
+
+	// Remove any newlines at the end of the file, like we do for the
+	// wallet unlock password file.
+	pin := string(bytes.TrimRight(pinBytes, "\r\n"))
+
+	return keychain.OpenPKCS11HSM(cfg.Module, cfg.TokenLabel, pin)
+}
+
+// BuildHtlcHoldHook is responsible for creating the hook that is offered
+// every htlc paying to one of our invoices. The default implementation
+// doesn't hold any htlcs.
+//
+// NOTE: This is part of the HtlcHoldHookBuilder interface.
+func (d *DefaultWalletImpl) BuildHtlcHoldHook(
+	_ *HtlcHoldHookDeps) (invoices.HtlcHoldHook, error) {
+
+	return nil, nil
+}
+
+// BuildEdgeScorers is responsible for creating the scorers that path finding
+// consults in addition to its default scoring. The default implementation
+// doesn't add any scorers.
+//
+// NOTE: This is part of the EdgeScorerBuilder interface.
+func (d *DefaultWalletImpl) BuildEdgeScorers(
+	_ *channeldb.ChannelGraph) ([]routing.EdgeScorer, error) {
+
+	return nil, nil
+}
+
 // RPCSignerWalletImpl is a wallet implementation that uses a remote signer over
 // an RPC interface.
 type RPCSignerWalletImpl struct {
@@ -725,6 +885,7 @@ func (d *RPCSignerWalletImpl) BuildChainControl(
 		SecretKeyRing:      rpcKeyRing,
 		ChainIO:            walletController,
 		DefaultConstraints: partialChainControl.ChannelConstraints,
+		DustLimits:         d.cfg.dustLimitPolicy(),
 		NetParams:          *walletConfig.NetParams,
 	}
 
@@ -821,12 +982,14 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 
 	startOpenTime := time.Now()
 
+	towerServerDBPath := filepath.Join(
+		cfg.Watchtower.TowerDir,
+		cfg.registeredChains.PrimaryChain().String(),
+		cfg.ActiveNetwork.Name,
+	)
 	databaseBackends, err := cfg.DB.GetBackends(
-		ctx, cfg.graphDatabaseDir(), cfg.networkDir, filepath.Join(
-			cfg.Watchtower.TowerDir,
-			cfg.registeredChains.PrimaryChain().String(),
-			lncfg.NormalizeNetwork(cfg.ActiveNetParams.Name),
-		), cfg.WtClient.Active, cfg.Watchtower.Active,
+		ctx, cfg.graphDatabaseDir(), cfg.networkDir, towerServerDBPath,
+		cfg.WtClient.Active, cfg.Watchtower.Active,
 	)
 	if err != nil {
 		return nil, nil, fmt.Errorf("unable to obtain database "+
@@ -865,8 +1028,10 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 		channeldb.OptionSetRejectCacheSize(cfg.Caches.RejectCacheSize),
 		channeldb.OptionSetChannelCacheSize(cfg.Caches.ChannelCacheSize),
 		channeldb.OptionSetBatchCommitInterval(cfg.DB.BatchCommitInterval),
+		channeldb.OptionSetGroupCommitInterval(cfg.DB.GroupCommitInterval),
 		channeldb.OptionDryRunMigration(cfg.DryRunMigration),
 		channeldb.OptionSetUseGraphCache(!cfg.DB.NoGraphCache),
+		channeldb.OptionSetGraphCacheMaxSize(cfg.DB.GraphCacheMaxSize),
 		channeldb.OptionKeepFailedPaymentAttempts(cfg.KeepFailedPaymentAttempts),
 		channeldb.OptionPruneRevocationLog(cfg.DB.PruneRevocation),
 	}
@@ -915,6 +1080,67 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 	// using the same struct (and DB backend) instance.
 	dbs.ChanStateDB = dbs.GraphDB
 
+	// Remove the legacy duplicate payments if requested. In dry run mode
+	// we only report what would be removed.
+	if cfg.DB.CleanupDuplicatePayments {
+		report, err := dbs.ChanStateDB.CleanupDuplicatePayments(
+			cfg.DryRunMigration,
+		)
+		if err != nil {
+			cleanUp()
+
+			err := fmt.Errorf("unable to clean up duplicate "+
+				"payments: %v", err)
+			d.logger.Error(err)
+			return nil, nil, err
+		}
+
+		d.logger.Infof("Duplicate payments cleanup (dry_run=%v): "+
+			"found %d duplicates of %d payments, deleted %d, "+
+			"kept %d succeeded, deleted %d dangling index "+
+			"entries, reclaimed ~%d bytes", cfg.DryRunMigration,
+			report.NumDuplicates, report.NumPaymentsWithDuplicates,
+			report.NumDuplicatesDeleted, report.NumDuplicatesKept,
+			report.NumDanglingIndexes, report.ReclaimedBytes)
+	}
+
+	// If the watchtower databases are stored in postgres, any bolt
+	// databases left from before are migrated into postgres first.
+	if cfg.DB.TowerBackend == lncfg.PostgresBackend {
+		towerDBs := []struct {
+			active   bool
+			backend  kvdb.Backend
+			boltPath string
+			fileName string
+		}{{
+			active:   cfg.WtClient.Active,
+			backend:  databaseBackends.TowerClientDB,
+			boltPath: cfg.graphDatabaseDir(),
+			fileName: lncfg.TowerClientDBName,
+		}, {
+			active:   cfg.Watchtower.Active,
+			backend:  databaseBackends.TowerServerDB,
+			boltPath: towerServerDBPath,
+			fileName: lncfg.TowerServerDBName,
+		}}
+		for _, towerDB := range towerDBs {
+			if !towerDB.active {
+				continue
+			}
+
+			err := migrateBoltTowerDB(
+				towerDB.backend, towerDB.boltPath,
+				towerDB.fileName, cfg.DB.Bolt.DBTimeout,
+			)
+			if err != nil {
+				cleanUp()
+
+				d.logger.Error(err)
+				return nil, nil, err
+			}
+		}
+	}
+
 	// Wrap the watchtower client DB and make sure we clean up.
 	if cfg.WtClient.Active {
 		dbs.TowerClientDB, err = wtdb.OpenClientDB(
@@ -951,6 +1177,50 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 	return dbs, cleanUp, nil
 }
 
+// migrateBoltTowerDB migrates the bolt watchtower database at the given path
+// into the postgres backend, if the bolt database still exists. The bolt file
+// is renamed once it's migrated, so it isn't picked up again if the tower
+// backend is switched back to bolt.
+func migrateBoltTowerDB(backend kvdb.Backend, boltPath, boltFileName string,
+	timeout time.Duration) error {
+
+	boltFile := filepath.Join(boltPath, boltFileName)
+	if _, err := os.Stat(boltFile); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	boltBackend, err := kvdb.GetBoltBackend(&kvdb.BoltBackendConfig{
+		DBPath:     boltPath,
+		DBFileName: boltFileName,
+		DBTimeout:  timeout,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = wtdb.MigrateBackend(boltBackend, backend)
+	_ = boltBackend.Close()
+
+	switch {
+	// The postgres database is already in use, so we leave the bolt file
+	// alone rather than risk overwriting newer state.
+	case errors.Is(err, wtdb.ErrBackendInitialized):
+		return nil
+
+	// The bolt database was never initialized, there's nothing to migrate.
+	case errors.Is(err, wtdb.ErrUninitializedDB):
+
+	case err != nil:
+		return fmt.Errorf("unable to migrate %v: %v", boltFile, err)
+	}
+
+	return os.Rename(boltFile, boltFile+".migrated")
+}
+
 // waitForWalletPassword blocks until a password is provided by the user to
 // this RPC server.
 func waitForWalletPassword(cfg *Config,
@@ -1165,29 +1435,27 @@ func importWatchOnlyAccounts(wallet *wallet.Wallet,
 // backend given a target chain directory to store the chain state.
 func initNeutrinoBackend(cfg *Config, chainDir string,
 	blockCache *blockcache.BlockCache) (*neutrino.ChainService,
-	func(), error) {
+	banman.Store, func(), error) {
 
 	// Both channel validation flags are false by default but their meaning
 	// is the inverse of each other. Therefore both cannot be true. For
 	// every other case, the neutrino.validatechannels overwrites the
 	// routing.assumechanvalid value.
 	if cfg.NeutrinoMode.ValidateChannels && cfg.Routing.AssumeChannelValid {
-		return nil, nil, fmt.Errorf("can't set both " +
+		return nil, nil, nil, fmt.Errorf("can't set both " +
 			"neutrino.validatechannels and routing." +
 			"assumechanvalid to true at the same time")
 	}
 	cfg.Routing.AssumeChannelValid = !cfg.NeutrinoMode.ValidateChannels
 
 	// First we'll open the database file for neutrino, creating the
-	// database if needed. We append the normalized network name here to
-	// match the behavior of btcwallet.
-	dbPath := filepath.Join(
-		chainDir, lncfg.NormalizeNetwork(cfg.ActiveNetParams.Name),
-	)
+	// database if needed. We append the network name here to match the
+	// other data directories of the network.
+	dbPath := filepath.Join(chainDir, cfg.ActiveNetwork.Name)
 
 	// Ensure that the neutrino db path exists.
 	if err := os.MkdirAll(dbPath, 0700); err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	dbName := filepath.Join(dbPath, "neutrino.db")
@@ -1195,7 +1463,7 @@ func initNeutrinoBackend(cfg *Config, chainDir string,
 		"bdb", dbName, !cfg.SyncFreelist, cfg.DB.Bolt.DBTimeout,
 	)
 	if err != nil {
-		return nil, nil, fmt.Errorf("unable to create neutrino "+
+		return nil, nil, nil, fmt.Errorf("unable to create neutrino "+
 			"database: %v", err)
 	}
 
@@ -1204,7 +1472,7 @@ func initNeutrinoBackend(cfg *Config, chainDir string,
 	)
 	if err != nil {
 		db.Close()
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	// With the database open, we can now create an instance of the
@@ -1254,13 +1522,23 @@ func initNeutrinoBackend(cfg *Config, chainDir string,
 	neutrinoCS, err := neutrino.NewChainService(config)
 	if err != nil {
 		db.Close()
-		return nil, nil, fmt.Errorf("unable to create neutrino light "+
+		return nil, nil, nil, fmt.Errorf("unable to create neutrino light "+
 			"client: %v", err)
 	}
 
+	// The ban store of the chain service isn't exposed, so we create our
+	// own instance on top of the same database to be able to look up the
+	// details of the bans of peers.
+	banStore, err := banman.NewStore(db)
+	if err != nil {
+		db.Close()
+		return nil, nil, nil, fmt.Errorf("unable to create neutrino "+
+			"ban store: %v", err)
+	}
+
 	if err := neutrinoCS.Start(); err != nil {
 		db.Close()
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	cleanUp := func() {
@@ -1271,7 +1549,7 @@ func initNeutrinoBackend(cfg *Config, chainDir string,
 		db.Close()
 	}
 
-	return neutrinoCS, cleanUp, nil
+	return neutrinoCS, banStore, cleanUp, nil
 }
 
 // parseHeaderStateAssertion parses the user-specified neutrino header state
@@ -0,0 +1,453 @@
+package lnd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/lightningnetwork/lnd/build"
+	"github.com/lightningnetwork/lnd/chainreg"
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// reloadableConfig is the subset of the config that can safely be reloaded
+// while lnd is running.
+type reloadableConfig struct {
+	// DebugLevel is the log level of all or individual subsystems.
+	DebugLevel string
+
+	// BaseFee, FeeRate and TimeLockDelta make up the default routing
+	// policy that is announced for new channels.
+	BaseFee       lnwire.MilliSatoshi
+	FeeRate       lnwire.MilliSatoshi
+	TimeLockDelta uint32
+
+	// MaxChannelUpdateBurst and ChannelUpdateInterval throttle the channel
+	// updates we accept from the network.
+	MaxChannelUpdateBurst int
+	ChannelUpdateInterval time.Duration
+
+	// The intervals at which the health checks are run.
+	ChainCheckInterval    time.Duration
+	DiskCheckInterval     time.Duration
+	TLSCheckInterval      time.Duration
+	TorConnectionInterval time.Duration
+	RemoteSignerInterval  time.Duration
+	HSMInterval           time.Duration
+
+	// TLSExtraIPs and TLSExtraDomains are added to the TLS certificate of
+	// the RPC server. They are only reloaded if the certificate is rotated
+	// automatically, otherwise they are left empty.
+	TLSExtraIPs     []string
+	TLSExtraDomains []string
+}
+
+// newReloadableConfig extracts the options that can be reloaded from the
+// given config.
+func newReloadableConfig(cfg *Config) *reloadableConfig {
+	chainCfg := cfg.Bitcoin
+	if cfg.registeredChains.PrimaryChain() == chainreg.LitecoinChain {
+		chainCfg = cfg.Litecoin
+	}
+
+	reloadCfg := &reloadableConfig{
+		DebugLevel:            cfg.DebugLevel,
+		BaseFee:               chainCfg.BaseFee,
+		FeeRate:               chainCfg.FeeRate,
+		TimeLockDelta:         chainCfg.TimeLockDelta,
+		MaxChannelUpdateBurst: cfg.Gossip.MaxChannelUpdateBurst,
+		ChannelUpdateInterval: cfg.Gossip.ChannelUpdateInterval,
+		ChainCheckInterval:    cfg.HealthChecks.ChainCheck.Interval,
+		DiskCheckInterval:     cfg.HealthChecks.DiskCheck.Interval,
+		TLSCheckInterval:      cfg.HealthChecks.TLSCheck.Interval,
+		TorConnectionInterval: cfg.HealthChecks.TorConnection.Interval,
+		RemoteSignerInterval:  cfg.HealthChecks.RemoteSigner.Interval,
+		HSMInterval:           cfg.HealthChecks.HSM.Interval,
+	}
+
+	if cfg.TLSAutoRotate {
+		reloadCfg.TLSExtraIPs = cfg.TLSExtraIPs
+		reloadCfg.TLSExtraDomains = cfg.TLSExtraDomains
+	}
+
+	return reloadCfg
+}
+
+// reloadableOption is a single option of the reloadable config, identified by
+// its name in lnd.conf.
+type reloadableOption struct {
+	name  string
+	value interface{}
+}
+
+// options returns the options of the reloadable config in a stable order. The
+// routing policy options are prefixed with the name of the given chain.
+func (r *reloadableConfig) options(chain string) []reloadableOption {
+	return []reloadableOption{
+		{"debuglevel", r.DebugLevel},
+		{chain + ".basefee", r.BaseFee},
+		{chain + ".feerate", r.FeeRate},
+		{chain + ".timelockdelta", r.TimeLockDelta},
+		{"gossip.max-channel-update-burst", r.MaxChannelUpdateBurst},
+		{"gossip.channel-update-interval", r.ChannelUpdateInterval},
+		{"healthcheck.chainbackend.interval", r.ChainCheckInterval},
+		{"healthcheck.diskspace.interval", r.DiskCheckInterval},
+		{"healthcheck.tls.interval", r.TLSCheckInterval},
+		{"healthcheck.torconnection.interval", r.TorConnectionInterval},
+		{"healthcheck.remotesigner.interval", r.RemoteSignerInterval},
+		{"healthcheck.hsm.interval", r.HSMInterval},
+		{"tlsextraip", r.TLSExtraIPs},
+		{"tlsextradomain", r.TLSExtraDomains},
+	}
+}
+
+// configChange describes an option whose value was changed by a config
+// reload.
+type configChange struct {
+	option   string
+	oldValue string
+	newValue string
+}
+
+// diff returns the options whose values differ between the reloadable config
+// and the new one.
+func (r *reloadableConfig) diff(newCfg *reloadableConfig,
+	chain string) []configChange {
+
+	oldOptions := r.options(chain)
+	newOptions := newCfg.options(chain)
+
+	var changes []configChange
+	for i, oldOption := range oldOptions {
+		oldValue := fmt.Sprintf("%v", oldOption.value)
+		newValue := fmt.Sprintf("%v", newOptions[i].value)
+		if oldValue == newValue {
+			continue
+		}
+
+		changes = append(changes, configChange{
+			option:   oldOption.name,
+			oldValue: oldValue,
+			newValue: newValue,
+		})
+	}
+
+	return changes
+}
+
+// healthChecksChanged returns true if any of the health check intervals
+// differ between the reloadable config and the new one.
+func (r *reloadableConfig) healthChecksChanged(
+	newCfg *reloadableConfig) bool {
+
+	return r.ChainCheckInterval != newCfg.ChainCheckInterval ||
+		r.DiskCheckInterval != newCfg.DiskCheckInterval ||
+		r.TLSCheckInterval != newCfg.TLSCheckInterval ||
+		r.TorConnectionInterval != newCfg.TorConnectionInterval ||
+		r.RemoteSignerInterval != newCfg.RemoteSignerInterval ||
+		r.HSMInterval != newCfg.HSMInterval
+}
+
+// tlsSANsChanged returns true if the extra IPs or domains of the TLS
+// certificate differ between the reloadable config and the new one.
+func (r *reloadableConfig) tlsSANsChanged(newCfg *reloadableConfig) bool {
+	return fmt.Sprintf("%v", r.TLSExtraIPs) !=
+		fmt.Sprintf("%v", newCfg.TLSExtraIPs) ||
+		fmt.Sprintf("%v", r.TLSExtraDomains) !=
+			fmt.Sprintf("%v", newCfg.TLSExtraDomains)
+}
+
+// validateLogger is a build.LeveledSubLogger that only validates debug levels
+// without applying them.
+type validateLogger struct {
+	build.LeveledSubLogger
+}
+
+// SetLogLevel is a no-op, as the log level is only validated.
+func (v *validateLogger) SetLogLevel(string, string) {}
+
+// SetLogLevels is a no-op, as the log level is only validated.
+func (v *validateLogger) SetLogLevels(string) {}
+
+// loadReloadableConfig parses the config file and the command line options
+// the same way LoadConfig does and returns the validated subset of options
+// that can be reloaded. Command line options keep taking precedence over the
+// config file.
+func loadReloadableConfig(cfg *Config) (*reloadableConfig, error) {
+	preCfg := DefaultConfig()
+	if _, err := flags.Parse(&preCfg); err != nil {
+		return nil, err
+	}
+
+	configFilePath, err := resolveConfigFile(&preCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	newCfg := preCfg
+	fileParser := flags.NewParser(&newCfg, flags.Default)
+	err = flags.NewIniParser(fileParser).ParseFile(configFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse config file: %w", err)
+	}
+
+	flagParser := flags.NewParser(&newCfg, flags.Default)
+	if _, err := flagParser.Parse(); err != nil {
+		return nil, err
+	}
+
+	// The chain can't change while lnd is running, so we take it from the
+	// current config. The same goes for the automatic rotation of the TLS
+	// certificate, which decides whether its extra IPs and domains are
+	// reloaded.
+	newCfg.registeredChains = cfg.registeredChains
+	newCfg.TLSAutoRotate = cfg.TLSAutoRotate
+	reloadCfg := newReloadableConfig(&newCfg)
+
+	// Make sure the new values are sane before any of them is applied.
+	err = build.ParseAndSetDebugLevels(
+		reloadCfg.DebugLevel, &validateLogger{cfg.LogWriter},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if reloadCfg.TimeLockDelta < minTimeLockDelta {
+		return nil, fmt.Errorf("timelockdelta must be at least %v",
+			minTimeLockDelta)
+	}
+
+	if reloadCfg.MaxChannelUpdateBurst <= 0 {
+		return nil, errors.New("gossip.max-channel-update-burst must " +
+			"be positive")
+	}
+
+	if reloadCfg.ChannelUpdateInterval <= 0 {
+		return nil, errors.New("gossip.channel-update-interval must " +
+			"be positive")
+	}
+
+	if err := newCfg.HealthChecks.Validate(); err != nil {
+		return nil, err
+	}
+
+	return reloadCfg, nil
+}
+
+// reloadConfig reloads the options of lnd.conf that can safely be changed
+// while lnd is running and applies the ones that changed. Those are the debug
+// level, the default routing policy of new channels, the throttling of
+// channel updates, the intervals of the health checks and, if the TLS
+// certificate is rotated automatically, its extra IPs and domains. All other
+// options are ignored. The applied changes are returned.
+func (s *server) reloadConfig() ([]configChange, error) {
+	s.reloadMtx.Lock()
+	defer s.reloadMtx.Unlock()
+
+	if !s.Started() || s.Stopped() {
+		return nil, errors.New("server is not active")
+	}
+
+	newCfg, err := loadReloadableConfig(s.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load config: %w", err)
+	}
+
+	chain := s.cfg.registeredChains.PrimaryChain().String()
+	changes := s.reloadedCfg.diff(newCfg, chain)
+	if len(changes) == 0 {
+		srvrLog.Infof("Reloaded config, no changes")
+		return nil, nil
+	}
+
+	err = applyConfigChanges(s.reloadedCfg, newCfg, s.configAppliers())
+	if err != nil {
+		return nil, err
+	}
+
+	s.reloadedCfg = newCfg
+
+	for _, change := range changes {
+		srvrLog.Infof("Reloaded config option %v: %v -> %v",
+			change.option, change.oldValue, change.newValue)
+	}
+
+	return changes, nil
+}
+
+// configAppliers apply the options of a config reload to the subsystems of the
+// running server.
+type configAppliers struct {
+	// setDebugLevel applies the given debug level.
+	setDebugLevel func(debugLevel string) error
+
+	// setRoutingPolicy applies the default routing policy of new
+	// channels.
+	setRoutingPolicy func(baseFee, feeRate lnwire.MilliSatoshi,
+		timeLockDelta uint32)
+
+	// setChannelUpdateRateLimit applies the throttling of the channel
+	// updates we accept from the network.
+	setChannelUpdateRateLimit func(maxBurst int, interval time.Duration)
+
+	// restartHealthChecks restarts the health checks with the intervals
+	// of the given config.
+	restartHealthChecks func(newCfg *reloadableConfig) error
+
+	// setTLSExtraSANs rotates the TLS certificate to cover the given
+	// extra IPs and domains.
+	setTLSExtraSANs func(extraIPs, extraDomains []string) error
+}
+
+// configAppliers returns the configAppliers that apply a config reload to the
+// server's subsystems.
+//
+// NOTE: This method must be called while holding the reloadMtx.
+func (s *server) configAppliers() *configAppliers {
+	return &configAppliers{
+		setDebugLevel: func(debugLevel string) error {
+			return build.ParseAndSetDebugLevels(
+				debugLevel, s.cfg.LogWriter,
+			)
+		},
+		setRoutingPolicy: func(baseFee, feeRate lnwire.MilliSatoshi,
+			timeLockDelta uint32) {
+
+			// The routing policy of the chain control is handed to
+			// newly connected peers while holding the server
+			// mutex.
+			s.mu.Lock()
+			policy := s.cc.RoutingPolicy
+			policy.BaseFee = baseFee
+			policy.FeeRate = feeRate
+			policy.TimeLockDelta = timeLockDelta
+			s.cc.RoutingPolicy = policy
+			s.mu.Unlock()
+
+			s.fundingMgr.SetDefaultRoutingPolicy(policy)
+		},
+		setChannelUpdateRateLimit: func(maxBurst int,
+			interval time.Duration) {
+
+			s.authGossiper.SetChannelUpdateRateLimit(
+				maxBurst, interval,
+			)
+		},
+		restartHealthChecks: s.restartLivenessMonitor,
+		setTLSExtraSANs: func(extraIPs, extraDomains []string) error {
+			_, err := s.tlsManager.SetExtraSANs(
+				extraIPs, extraDomains,
+			)
+
+			return err
+		},
+	}
+}
+
+// applyConfigChanges applies the options that differ between the old and the
+// new reloadable config with the given appliers. Options that didn't change
+// are left untouched.
+func applyConfigChanges(oldCfg, newCfg *reloadableConfig,
+	appliers *configAppliers) error {
+
+	if newCfg.DebugLevel != oldCfg.DebugLevel {
+		err := appliers.setDebugLevel(newCfg.DebugLevel)
+		if err != nil {
+			return err
+		}
+	}
+
+	if newCfg.BaseFee != oldCfg.BaseFee ||
+		newCfg.FeeRate != oldCfg.FeeRate ||
+		newCfg.TimeLockDelta != oldCfg.TimeLockDelta {
+
+		appliers.setRoutingPolicy(
+			newCfg.BaseFee, newCfg.FeeRate, newCfg.TimeLockDelta,
+		)
+	}
+
+	if newCfg.MaxChannelUpdateBurst != oldCfg.MaxChannelUpdateBurst ||
+		newCfg.ChannelUpdateInterval != oldCfg.ChannelUpdateInterval {
+
+		appliers.setChannelUpdateRateLimit(
+			newCfg.MaxChannelUpdateBurst,
+			newCfg.ChannelUpdateInterval,
+		)
+	}
+
+	// The intervals of the health checks are fixed once they are started,
+	// so the liveliness monitor is replaced by one that uses the new
+	// intervals.
+	if oldCfg.healthChecksChanged(newCfg) {
+		if err := appliers.restartHealthChecks(newCfg); err != nil {
+			return err
+		}
+	}
+
+	// A change of the extra IPs or domains is applied by rotating the TLS
+	// certificate.
+	if oldCfg.tlsSANsChanged(newCfg) {
+		err := appliers.setTLSExtraSANs(
+			newCfg.TLSExtraIPs, newCfg.TLSExtraDomains,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to rotate TLS "+
+				"certificate: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// restartLivenessMonitor stops the liveliness monitor and starts a new one
+// that runs the health checks at the intervals of the given config.
+//
+// NOTE: This method must be called while holding the reloadMtx.
+func (s *server) restartLivenessMonitor(newCfg *reloadableConfig) error {
+	if s.livelinessMonitor != nil {
+		if err := s.livelinessMonitor.Stop(); err != nil {
+			return err
+		}
+	}
+
+	// We only change the intervals of the health checks, all other
+	// settings are taken over from the current config.
+	withInterval := func(c *lncfg.CheckConfig,
+		interval time.Duration) *lncfg.CheckConfig {
+
+		check := *c
+		check.Interval = interval
+
+		return &check
+	}
+
+	healthChecks := *s.cfg.HealthChecks
+	healthChecks.ChainCheck = withInterval(
+		healthChecks.ChainCheck, newCfg.ChainCheckInterval,
+	)
+	healthChecks.DiskCheck = &lncfg.DiskCheckConfig{
+		RequiredRemaining: healthChecks.DiskCheck.RequiredRemaining,
+		CheckConfig: withInterval(
+			healthChecks.DiskCheck.CheckConfig,
+			newCfg.DiskCheckInterval,
+		),
+	}
+	healthChecks.TLSCheck = withInterval(
+		healthChecks.TLSCheck, newCfg.TLSCheckInterval,
+	)
+	healthChecks.TorConnection = withInterval(
+		healthChecks.TorConnection, newCfg.TorConnectionInterval,
+	)
+	healthChecks.RemoteSigner = withInterval(
+		healthChecks.RemoteSigner, newCfg.RemoteSignerInterval,
+	)
+	healthChecks.HSM = withInterval(healthChecks.HSM, newCfg.HSMInterval)
+
+	cfg := *s.cfg
+	cfg.HealthChecks = &healthChecks
+	s.createLivenessMonitor(&cfg, s.cc)
+
+	return s.livelinessMonitor.Start()
+}
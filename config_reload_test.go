@@ -0,0 +1,176 @@
+package lnd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReloadableConfigDiff asserts that only the options whose values changed
+// are reported by a config reload, named as in lnd.conf.
+func TestReloadableConfigDiff(t *testing.T) {
+	t.Parallel()
+
+	oldCfg := &reloadableConfig{
+		DebugLevel:            "info",
+		BaseFee:               1000,
+		FeeRate:               1,
+		TimeLockDelta:         80,
+		MaxChannelUpdateBurst: 10,
+		ChannelUpdateInterval: time.Minute,
+		ChainCheckInterval:    time.Minute,
+	}
+
+	// Without any changes, nothing is reported.
+	newCfg := *oldCfg
+	require.Empty(t, oldCfg.diff(&newCfg, "bitcoin"))
+	require.False(t, oldCfg.healthChecksChanged(&newCfg))
+
+	newCfg.DebugLevel = "debug,PEER=trace"
+	newCfg.FeeRate = 100
+	newCfg.ChainCheckInterval = 10 * time.Minute
+
+	require.Equal(t, []configChange{
+		{
+			option:   "debuglevel",
+			oldValue: "info",
+			newValue: "debug,PEER=trace",
+		},
+		{
+			option:   "bitcoin.feerate",
+			oldValue: "1 mSAT",
+			newValue: "100 mSAT",
+		},
+		{
+			option:   "healthcheck.chainbackend.interval",
+			oldValue: "1m0s",
+			newValue: "10m0s",
+		},
+	}, oldCfg.diff(&newCfg, "bitcoin"))
+	require.True(t, oldCfg.healthChecksChanged(&newCfg))
+	require.False(t, oldCfg.tlsSANsChanged(&newCfg))
+
+	// A changed extra domain of the TLS certificate is reported too.
+	newCfg = *oldCfg
+	newCfg.TLSExtraDomains = []string{"node.example.com"}
+
+	require.Equal(t, []configChange{{
+		option:   "tlsextradomain",
+		oldValue: "[]",
+		newValue: "[node.example.com]",
+	}}, oldCfg.diff(&newCfg, "bitcoin"))
+	require.True(t, oldCfg.tlsSANsChanged(&newCfg))
+}
+
+// TestApplyConfigChanges asserts that a config reload only applies the
+// options that changed, and that it stops at the first option that can't be
+// applied.
+func TestApplyConfigChanges(t *testing.T) {
+	t.Parallel()
+
+	oldCfg := &reloadableConfig{
+		DebugLevel:            "info",
+		BaseFee:               1000,
+		FeeRate:               1,
+		TimeLockDelta:         80,
+		MaxChannelUpdateBurst: 10,
+		ChannelUpdateInterval: time.Minute,
+		ChainCheckInterval:    time.Minute,
+	}
+
+	// The appliers record which options they applied.
+	var applied []string
+	appliers := &configAppliers{
+		setDebugLevel: func(debugLevel string) error {
+			applied = append(applied, "debuglevel="+debugLevel)
+			return nil
+		},
+		setRoutingPolicy: func(baseFee, feeRate lnwire.MilliSatoshi,
+			timeLockDelta uint32) {
+
+			applied = append(applied, fmt.Sprintf("policy=%v/%v/%v",
+				baseFee, feeRate, timeLockDelta))
+		},
+		setChannelUpdateRateLimit: func(maxBurst int,
+			interval time.Duration) {
+
+			applied = append(applied, fmt.Sprintf("ratelimit=%v/%v",
+				maxBurst, interval))
+		},
+		restartHealthChecks: func(newCfg *reloadableConfig) error {
+			applied = append(applied, fmt.Sprintf("healthchecks=%v",
+				newCfg.ChainCheckInterval))
+			return nil
+		},
+		setTLSExtraSANs: func(extraIPs, extraDomains []string) error {
+			applied = append(applied, fmt.Sprintf("tls=%v/%v",
+				extraIPs, extraDomains))
+			return nil
+		},
+	}
+
+	// Without any changes, nothing is applied.
+	newCfg := *oldCfg
+	require.NoError(t, applyConfigChanges(oldCfg, &newCfg, appliers))
+	require.Empty(t, applied)
+
+	// Only the changed options are applied, the routing policy as a
+	// whole.
+	newCfg.FeeRate = 100
+	newCfg.ChainCheckInterval = 10 * time.Minute
+	require.NoError(t, applyConfigChanges(oldCfg, &newCfg, appliers))
+	require.Equal(t, []string{
+		"policy=1000 mSAT/100 mSAT/80",
+		"healthchecks=10m0s",
+	}, applied)
+
+	// All options are applied if all of them changed.
+	applied = nil
+	newCfg = reloadableConfig{
+		DebugLevel:            "debug",
+		BaseFee:               1000,
+		FeeRate:               1,
+		TimeLockDelta:         80,
+		MaxChannelUpdateBurst: 20,
+		ChannelUpdateInterval: time.Second,
+		ChainCheckInterval:    time.Hour,
+		TLSExtraDomains:       []string{"node.example.com"},
+	}
+	require.NoError(t, applyConfigChanges(oldCfg, &newCfg, appliers))
+	require.Equal(t, []string{
+		"debuglevel=debug",
+		"ratelimit=20/1s",
+		"healthchecks=1h0m0s",
+		"tls=[]/[node.example.com]",
+	}, applied)
+
+	// If an option can't be applied, the reload stops there.
+	applied = nil
+	errRestart := errors.New("unable to restart health checks")
+	appliers.restartHealthChecks = func(*reloadableConfig) error {
+		return errRestart
+	}
+	err := applyConfigChanges(oldCfg, &newCfg, appliers)
+	require.ErrorIs(t, err, errRestart)
+	require.Equal(t, []string{
+		"debuglevel=debug",
+		"ratelimit=20/1s",
+	}, applied)
+
+	// A failed rotation of the TLS certificate is wrapped.
+	applied = nil
+	errRotate := errors.New("unable to rotate")
+	appliers.restartHealthChecks = func(*reloadableConfig) error {
+		return nil
+	}
+	appliers.setTLSExtraSANs = func(_, _ []string) error {
+		return errRotate
+	}
+	err = applyConfigChanges(oldCfg, &newCfg, appliers)
+	require.ErrorIs(t, err, errRotate)
+	require.Contains(t, err.Error(), "unable to rotate TLS certificate")
+}
@@ -128,6 +128,7 @@ func (c *anchorResolver) Resolve() (ContractResolver, error) {
 		// Anchor was swept successfully.
 		case nil:
 			sweepTxID := sweepRes.Tx.TxHash()
+			c.recordSweepFee(c.anchor, sweepRes)
 
 			spendTx = &sweepTxID
 			outcome = channeldb.ResolverOutcomeClaimed
@@ -0,0 +1,85 @@
+package contractcourt
+
+import (
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// BreachedOutputDetail describes an output of a revoked commitment that we're
+// entitled to sweep.
+type BreachedOutputDetail struct {
+	// OutPoint is the outpoint of the output on the revoked commitment.
+	OutPoint wire.OutPoint
+
+	// Amount is the value of the output.
+	Amount btcutil.Amount
+
+	// WitnessType describes the kind of output, for example the
+	// counterparty's commitment output or an HTLC.
+	WitnessType string
+}
+
+// BreachDetected is sent to the breach subscribers of the BreachArbiter as
+// soon as a revoked commitment of one of our channels is detected on chain,
+// before the justice transaction is published. It allows operators to be
+// alerted while the counterparty's funds are still locked by the CSV delay.
+type BreachDetected struct {
+	// ChanPoint is the funding outpoint of the breached channel.
+	ChanPoint wire.OutPoint
+
+	// ChainHash is the hash of the genesis block of the chain the channel
+	// lives on.
+	ChainHash chainhash.Hash
+
+	// BreachTxHash is the hash of the revoked commitment transaction.
+	BreachTxHash chainhash.Hash
+
+	// BreachHeight is the height the revoked commitment was detected at.
+	BreachHeight uint32
+
+	// RevokedStateNum is the number of the revoked commitment state that
+	// was broadcast.
+	RevokedStateNum uint64
+
+	// Outputs are the outputs of the revoked commitment we're entitled to.
+	Outputs []BreachedOutputDetail
+
+	// TotalAmount is the total value of Outputs, before the fees of the
+	// justice transaction.
+	TotalAmount btcutil.Amount
+
+	// DetectedAt is the time the breach was handed to the BreachArbiter.
+	DetectedAt time.Time
+}
+
+// newBreachDetected assembles the breach notification of the given
+// retribution.
+func newBreachDetected(retInfo *retributionInfo, revokedStateNum uint64,
+	now time.Time) *BreachDetected {
+
+	event := &BreachDetected{
+		ChanPoint:       retInfo.chanPoint,
+		ChainHash:       retInfo.chainHash,
+		BreachTxHash:    retInfo.commitHash,
+		BreachHeight:    retInfo.breachHeight,
+		RevokedStateNum: revokedStateNum,
+		Outputs: make(
+			[]BreachedOutputDetail, 0, len(retInfo.breachedOutputs),
+		),
+		DetectedAt: now,
+	}
+
+	for _, output := range retInfo.breachedOutputs {
+		event.Outputs = append(event.Outputs, BreachedOutputDetail{
+			OutPoint:    output.outpoint,
+			Amount:      output.amt,
+			WitnessType: output.witnessType.String(),
+		})
+		event.TotalAmount += output.amt
+	}
+
+	return event
+}
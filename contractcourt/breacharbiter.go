@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"time"
 
 	"github.com/btcsuite/btcd/blockchain"
 	"github.com/btcsuite/btcd/btcutil"
@@ -21,6 +22,7 @@ import (
 	"github.com/lightningnetwork/lnd/labels"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/subscribe"
 )
 
 const (
@@ -85,6 +87,11 @@ const (
 	// CloseBreach indicates that a channel breach has been detected, and
 	// the link should immediately be marked as unavailable.
 	CloseBreach
+
+	// CloseRejectedCommit indicates that our commitment transaction was
+	// rejected by the mempool policy after the channel was force closed,
+	// and a cooperative channel closure should be attempted instead.
+	CloseRejectedCommit
 )
 
 // RetributionStorer provides an interface for managing a persistent map from
@@ -181,6 +188,10 @@ type BreachArbiter struct {
 
 	subscriptions map[wire.OutPoint]chan struct{}
 
+	// breachNotifier notifies the subscribers of SubscribeBreaches of new
+	// breaches.
+	breachNotifier *subscribe.Server
+
 	quit chan struct{}
 	wg   sync.WaitGroup
 	sync.Mutex
@@ -190,9 +201,10 @@ type BreachArbiter struct {
 // its dependent objects.
 func NewBreachArbiter(cfg *BreachConfig) *BreachArbiter {
 	return &BreachArbiter{
-		cfg:           cfg,
-		subscriptions: make(map[wire.OutPoint]chan struct{}),
-		quit:          make(chan struct{}),
+		cfg:            cfg,
+		subscriptions:  make(map[wire.OutPoint]chan struct{}),
+		breachNotifier: subscribe.NewServer(),
+		quit:           make(chan struct{}),
 	}
 }
 
@@ -208,6 +220,10 @@ func (b *BreachArbiter) Start() error {
 }
 
 func (b *BreachArbiter) start() error {
+	if err := b.breachNotifier.Start(); err != nil {
+		return err
+	}
+
 	// Load all retributions currently persisted in the retribution store.
 	var breachRetInfos map[wire.OutPoint]retributionInfo
 	if err := b.cfg.Store.ForAll(func(ret *retributionInfo) error {
@@ -308,6 +324,11 @@ func (b *BreachArbiter) Stop() error {
 
 		close(b.quit)
 		b.wg.Wait()
+
+		if err := b.breachNotifier.Stop(); err != nil {
+			brarLog.Errorf("Unable to stop breach notifier: %v",
+				err)
+		}
 	})
 	return nil
 }
@@ -346,6 +367,13 @@ func (b *BreachArbiter) SubscribeBreachComplete(chanPoint *wire.OutPoint,
 	return false, nil
 }
 
+// SubscribeBreaches returns a client that receives a BreachDetected event as
+// soon as a new breach is handed to the BreachArbiter, before the justice
+// transaction is published.
+func (b *BreachArbiter) SubscribeBreaches() (*subscribe.Client, error) {
+	return b.breachNotifier.Subscribe()
+}
+
 // notifyBreachComplete is used by the BreachArbiter to notify outside
 // subsystems that the breach resolution process is complete.
 func (b *BreachArbiter) notifyBreachComplete(chanPoint *wire.OutPoint) {
@@ -987,6 +1015,16 @@ func (b *BreachArbiter) handleBreachHandoff(breachEvent *ContractBreachEvent) {
 		return
 	}
 
+	// Let our subscribers know about the breach right away, so that they
+	// don't need to wait for the breach transaction to confirm.
+	err = b.breachNotifier.SendUpdate(newBreachDetected(
+		retInfo, breachInfo.RevokedStateNum, time.Now(),
+	))
+	if err != nil {
+		brarLog.Errorf("Unable to notify breach of "+
+			"ChannelPoint(%v): %v", chanPoint, err)
+	}
+
 	// Now that a new channel contract has been added to the retribution
 	// store, we first register for a notification to be dispatched once
 	// the breach transaction (the revoked commitment transaction) has been
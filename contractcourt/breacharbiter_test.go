@@ -1019,6 +1019,12 @@ func TestBreachHandoffSuccess(t *testing.T) {
 
 	chanPoint := alice.ChanPoint
 
+	// Subscribe to breaches, the subscribers should be notified as soon as
+	// the breach is handed off.
+	breachSub, err := brar.SubscribeBreaches()
+	require.NoError(t, err)
+	defer breachSub.Cancel()
+
 	// Signal a spend of the funding transaction and wait for the close
 	// observer to exit.
 	processACK := make(chan error)
@@ -1053,6 +1059,25 @@ func TestBreachHandoffSuccess(t *testing.T) {
 	// force closed.
 	assertArbiterBreach(t, brar, chanPoint)
 
+	// The subscriber should have been notified of the breach.
+	select {
+	case update := <-breachSub.Updates():
+		event, ok := update.(*BreachDetected)
+		require.True(t, ok)
+		require.Equal(t, *chanPoint, event.ChanPoint)
+		require.Equal(t, bobClose.CloseTx.TxHash(), event.BreachTxHash)
+		require.NotEmpty(t, event.Outputs)
+
+		var total btcutil.Amount
+		for _, output := range event.Outputs {
+			total += output.Amount
+		}
+		require.Equal(t, total, event.TotalAmount)
+
+	case <-time.After(time.Second * 15):
+		t.Fatalf("breach subscriber wasn't notified")
+	}
+
 	// Send another breach event. Since the handoff for this channel was
 	// already ACKed, the breach arbiter should immediately ACK and ignore
 	// this event.
@@ -1085,6 +1110,14 @@ func TestBreachHandoffSuccess(t *testing.T) {
 
 	// State should not have changed.
 	assertArbiterBreach(t, brar, chanPoint)
+
+	// As the breach was already known, the subscriber isn't notified
+	// again.
+	select {
+	case update := <-breachSub.Updates():
+		t.Fatalf("unexpected breach notification: %v", update)
+	case <-time.After(100 * time.Millisecond):
+	}
 }
 
 // TestBreachHandoffFail tests that a channel's close observer properly
@@ -20,6 +20,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/subscribe"
 )
 
 // ErrChainArbExiting signals that the chain arbitrator is shutting down.
@@ -84,6 +85,14 @@ type ChainArbitratorConfig struct {
 	// continually be rebroadcast if needed.
 	PublishTx func(*wire.MsgTx, string) error
 
+	// RequestCoopClose asks the peer of the channel with the given channel
+	// point to close it cooperatively. This is used as a fallback if our
+	// commitment transaction can't enter the mempool because its fee rate
+	// is too low. It doesn't wait for the peer to handle the request, the
+	// result is delivered over the returned channel instead. If nil, no
+	// cooperative close is attempted.
+	RequestCoopClose func(wire.OutPoint) (<-chan error, error)
+
 	// DeliverResolutionMsg is a function that will append an outgoing
 	// message to the "out box" for a ChannelLink. This is used to cancel
 	// backwards any HTLC's that are either dust, we're timing out, or
@@ -220,6 +229,10 @@ type ChainArbitrator struct {
 	// active channels that it must still watch over.
 	chanSource *channeldb.DB
 
+	// stateNtfnServer dispatches the state transitions of all our channel
+	// arbitrators to any active subscribers.
+	stateNtfnServer *subscribe.Server
+
 	quit chan struct{}
 
 	wg sync.WaitGroup
@@ -231,11 +244,12 @@ func NewChainArbitrator(cfg ChainArbitratorConfig,
 	db *channeldb.DB) *ChainArbitrator {
 
 	return &ChainArbitrator{
-		cfg:            cfg,
-		activeChannels: make(map[wire.OutPoint]*ChannelArbitrator),
-		activeWatchers: make(map[wire.OutPoint]*chainWatcher),
-		chanSource:     db,
-		quit:           make(chan struct{}),
+		cfg:             cfg,
+		activeChannels:  make(map[wire.OutPoint]*ChannelArbitrator),
+		activeWatchers:  make(map[wire.OutPoint]*chainWatcher),
+		chanSource:      db,
+		stateNtfnServer: subscribe.NewServer(),
+		quit:            make(chan struct{}),
 	}
 }
 
@@ -325,6 +339,20 @@ func (a *arbChannel) ForceCloseChan() (*lnwallet.LocalForceCloseSummary, error)
 	return chanMachine.ForceClose()
 }
 
+// channelFeeRecorder returns a closure that records on-chain fees paid on
+// behalf of the channel with the given channel point.
+func (c *ChainArbitrator) channelFeeRecorder(chanPoint wire.OutPoint) func(
+	chainhash.Hash, uint32, channeldb.ChannelFeeType, btcutil.Amount) error {
+
+	return func(txid chainhash.Hash, inputIndex uint32,
+		feeType channeldb.ChannelFeeType, fee btcutil.Amount) error {
+
+		return c.chanSource.ChannelStateDB().PutChannelFee(
+			chanPoint, txid, inputIndex, feeType, fee,
+		)
+	}
+}
+
 // newActiveChannelArbitrator creates a new instance of an active channel
 // arbitrator given the state of the target channel.
 func newActiveChannelArbitrator(channel *channeldb.OpenChannel,
@@ -371,6 +399,8 @@ func newActiveChannelArbitrator(channel *channeldb.OpenChannel,
 			chanStateDB := c.chanSource.ChannelStateDB()
 			return chanStateDB.FetchHistoricalChannel(&chanPoint)
 		},
+		PutChannelFee:         c.channelFeeRecorder(chanPoint),
+		NotifyStateTransition: c.notifyStateTransition,
 	}
 
 	// The final component needed is an arbitrator log that the arbitrator
@@ -489,6 +519,12 @@ func (c *ChainArbitrator) Start() error {
 
 	log.Info("ChainArbitrator starting")
 
+	// Start the notification server before any of the arbitrators, so
+	// that their initial state transitions can be dispatched.
+	if err := c.stateNtfnServer.Start(); err != nil {
+		return err
+	}
+
 	// First, we'll fetch all the channels that are still open, in order to
 	// collect them within our set of active contracts.
 	openChannels, err := c.chanSource.ChannelStateDB().FetchAllChannels()
@@ -521,6 +557,7 @@ func (c *ChainArbitrator) Start() error {
 				isOurAddr:           c.cfg.IsOurAddress,
 				contractBreach:      breachClosure,
 				extractStateNumHint: lnwallet.GetStateNumHint,
+				putChannelFee:       c.channelFeeRecorder(chanPoint),
 			},
 		)
 		if err != nil {
@@ -586,6 +623,8 @@ func (c *ChainArbitrator) Start() error {
 				chanStateDB := c.chanSource.ChannelStateDB()
 				return chanStateDB.FetchHistoricalChannel(&chanPoint)
 			},
+			PutChannelFee:         c.channelFeeRecorder(chanPoint),
+			NotifyStateTransition: c.notifyStateTransition,
 		}
 		chanLog, err := newBoltArbitratorLog(
 			c.chanSource.Backend, arbCfg, c.cfg.ChainHash, chanPoint,
@@ -898,6 +937,16 @@ func (c *ChainArbitrator) rebroadcast(channel *channeldb.OpenChannel,
 			kind, closeTx.TxHash(), err)
 	}
 
+	// If our commitment was rejected by the mempool policy, the channel
+	// arbitrator, which isn't started yet, keeps rebroadcasting it.
+	if errors.Is(err, lnwallet.ErrMempoolRejected) &&
+		state == channeldb.ChanStatusCommitBroadcasted {
+
+		if arb, ok := c.activeChannels[chanPoint]; ok {
+			arb.rejectedCommit = closeTx
+		}
+	}
+
 	return nil
 }
 
@@ -951,7 +1000,25 @@ func (c *ChainArbitrator) Stop() error {
 
 	c.wg.Wait()
 
-	return nil
+	return c.stateNtfnServer.Stop()
+}
+
+// SubscribeArbitratorUpdates returns a subscribe.Client that will receive an
+// ArbitratorStateUpdate each time one of our channel arbitrators transitions
+// to a new state. Only transitions from the point of subscription onwards are
+// delivered.
+func (c *ChainArbitrator) SubscribeArbitratorUpdates() (*subscribe.Client,
+	error) {
+
+	return c.stateNtfnServer.Subscribe()
+}
+
+// notifyStateTransition dispatches a channel arbitrator state transition to
+// all active subscribers.
+func (c *ChainArbitrator) notifyStateTransition(update *ArbitratorStateUpdate) {
+	if err := c.stateNtfnServer.SendUpdate(update); err != nil {
+		log.Warnf("Unable to send arbitrator state update: %v", err)
+	}
 }
 
 // ContractUpdate is a message packages the latest set of active HTLCs on a
@@ -1139,6 +1206,7 @@ func (c *ChainArbitrator) WatchNewChannel(newChan *channeldb.OpenChannel) error
 				)
 			},
 			extractStateNumHint: lnwallet.GetStateNumHint,
+			putChannelFee:       c.channelFeeRecorder(chanPoint),
 		},
 	)
 	if err != nil {
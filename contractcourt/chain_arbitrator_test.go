@@ -129,6 +129,62 @@ func TestChainArbitratorRepublishCloses(t *testing.T) {
 	}
 }
 
+// TestChainArbitratorRestoresRejectedCommit tests that a commitment which is
+// rejected by the mempool policy when it is republished at startup is handed
+// to the channel arbitrator, so it keeps being rebroadcast after a restart.
+func TestChainArbitratorRestoresRejectedCommit(t *testing.T) {
+	t.Parallel()
+
+	db, err := channeldb.Open(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	lChannel, _, cleanup, err := lnwallet.CreateTestChannels(
+		channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	channel := lChannel.State()
+	channel.Db = db.ChannelStateDB()
+
+	addr := &net.TCPAddr{
+		IP:   net.ParseIP("127.0.0.1"),
+		Port: 18556,
+	}
+	require.NoError(t, channel.SyncPending(addr, 101))
+
+	closeTx := channel.FundingTxn.Copy()
+	closeTx.TxIn[0].PreviousOutPoint = channel.FundingOutpoint
+	require.NoError(t, channel.MarkCommitmentBroadcasted(closeTx, true))
+
+	chainArbCfg := ChainArbitratorConfig{
+		ChainIO: &mock.ChainIO{},
+		Notifier: &mock.ChainNotifier{
+			SpendChan: make(chan *chainntnfs.SpendDetail),
+			EpochChan: make(chan *chainntnfs.BlockEpoch),
+			ConfChan:  make(chan *chainntnfs.TxConfirmation),
+		},
+		PublishTx: func(*wire.MsgTx, string) error {
+			return lnwallet.ErrMempoolMinFee
+		},
+		Clock: clock.NewDefaultClock(),
+	}
+	chainArb := NewChainArbitrator(chainArbCfg, db)
+	require.NoError(t, chainArb.Start())
+
+	// Stop the arbitrators before inspecting their state, so we don't race
+	// with their goroutines.
+	require.NoError(t, chainArb.Stop())
+
+	arb, ok := chainArb.activeChannels[channel.FundingOutpoint]
+	require.True(t, ok)
+	require.NotNil(t, arb.rejectedCommit)
+	require.Equal(t, closeTx.TxHash(), arb.rejectedCommit.TxHash())
+}
+
 // TestResolveContract tests that if we have an active channel being watched by
 // the chain arb, then a call to ResolveContract will mark the channel as fully
 // closed in the database, and also clean up all arbitrator state.
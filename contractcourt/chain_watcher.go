@@ -187,6 +187,11 @@ type chainWatcherConfig struct {
 	// obfuscater. This is used by the chain watcher to identify which
 	// state was broadcast and confirmed on-chain.
 	extractStateNumHint func(*wire.MsgTx, [lnwallet.StateHintSize]byte) uint64
+
+	// putChannelFee records an on-chain fee that was paid by us on behalf
+	// of the channel. If nil, no fees are recorded.
+	putChannelFee func(chainhash.Hash, uint32, channeldb.ChannelFeeType,
+		btcutil.Amount) error
 }
 
 // chainWatcher is a system that's assigned to every active channel. The duty
@@ -556,6 +561,40 @@ func newChainSet(chanState *channeldb.OpenChannel) (*chainSet, error) {
 	}, nil
 }
 
+// recordCloseFee records the fee of the transaction that spent the funding
+// output if we're the initiator of the channel, as the initiator pays the fee
+// of both the commitment and the cooperative close transactions. As the fee is
+// recorded by txid, recording it again after a restart has no effect.
+func (c *chainWatcher) recordCloseFee(closeTx *wire.MsgTx) {
+	chanState := c.cfg.chanState
+	if !chanState.IsInitiator || c.cfg.putChannelFee == nil {
+		return
+	}
+
+	var totalOut btcutil.Amount
+	for _, txOut := range closeTx.TxOut {
+		totalOut += btcutil.Amount(txOut.Value)
+	}
+	if totalOut > chanState.Capacity {
+		return
+	}
+
+	// Only a cooperative close transaction has a finalized input
+	// sequence, just like we check below to detect it.
+	feeType := channeldb.ChannelFeeCommitment
+	if closeTx.TxIn[0].Sequence == wire.MaxTxInSequenceNum {
+		feeType = channeldb.ChannelFeeCooperativeClose
+	}
+
+	err := c.cfg.putChannelFee(
+		closeTx.TxHash(), 0, feeType, chanState.Capacity-totalOut,
+	)
+	if err != nil {
+		log.Errorf("Unable to record close fee for ChannelPoint(%v): "+
+			"%v", chanState.FundingOutpoint, err)
+	}
+}
+
 // closeObserver is a dedicated goroutine that will watch for any closes of the
 // channel that it's watching on chain. In the event of an on-chain event, the
 // close observer will assembled the proper materials required to claim the
@@ -586,6 +625,10 @@ func (c *chainWatcher) closeObserver(spendNtfn *chainntnfs.SpendEvent) {
 		// revoked state...!!!
 		commitTxBroadcast := commitSpend.SpendingTx
 
+		// Whatever kind of transaction spent the funding output, we'll
+		// account its fee to the channel if we paid it.
+		c.recordCloseFee(commitTxBroadcast)
+
 		// First, we'll construct the chainset which includes all the
 		// data we need to dispatch an event to our subscribers about
 		// this possible channel close event.
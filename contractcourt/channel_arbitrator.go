@@ -161,14 +161,57 @@ type ChannelArbitratorConfig struct {
 	PutResolverReport func(tx kvdb.RwTx,
 		report *channeldb.ResolverReport) error
 
+	// PutChannelFee records an on-chain fee that was paid by us on behalf
+	// of the channel. The fee is attributed to the input with the given
+	// index of the transaction with the given txid.
+	PutChannelFee func(txid chainhash.Hash, inputIndex uint32,
+		feeType channeldb.ChannelFeeType, fee btcutil.Amount) error
+
 	// FetchHistoricalChannel retrieves the historical state of a channel.
 	// This is mostly used to supplement the ContractResolvers with
 	// additional information required for proper contract resolution.
 	FetchHistoricalChannel func() (*channeldb.OpenChannel, error)
 
+	// NotifyStateTransition is called each time the arbitrator commits a
+	// new state to its log. If nil, no notifications are sent.
+	NotifyStateTransition func(*ArbitratorStateUpdate)
+
 	ChainArbitratorConfig
 }
 
+// ArbitratorStateUpdate describes a state transition of a channel arbitrator.
+// These updates allow callers to follow the progress of a force close without
+// having to inspect the logs.
+type ArbitratorStateUpdate struct {
+	// ChanPoint is the channel point of the channel the arbitrator is
+	// watching over.
+	ChanPoint wire.OutPoint
+
+	// PrevState is the state the arbitrator transitioned from.
+	PrevState ArbitratorState
+
+	// NewState is the state the arbitrator transitioned to.
+	NewState ArbitratorState
+
+	// TriggerHeight is the block height at which the transition was
+	// triggered.
+	TriggerHeight uint32
+
+	// Reason is a human readable description of what caused the state
+	// machine to advance.
+	Reason string
+
+	// CloseTxid is the txid of our commitment transaction if it was
+	// broadcast as part of this transition.
+	CloseTxid *chainhash.Hash
+
+	// BroadcastErr is set if the broadcast of our commitment transaction
+	// was rejected by the mempool policy of the backend. Such updates
+	// don't advance the state, they alert the operator that the
+	// commitment doesn't propagate while it is being rebroadcast.
+	BroadcastErr error
+}
+
 // ReportOutputType describes the type of output that is being reported
 // on.
 type ReportOutputType uint8
@@ -370,6 +413,22 @@ type ChannelArbitrator struct {
 	// upon start up to decide which actions to take.
 	state ArbitratorState
 
+	// rejectedCommit is our commitment transaction if its last broadcast
+	// was rejected by the mempool policy of the backend. It is
+	// rebroadcast on every new block until it is accepted. Its anchor is
+	// offered to the sweeper on every block as well, but without package
+	// relay the sweeper can only CPFP it once it has been accepted.
+	rejectedCommit *wire.MsgTx
+
+	// coopCloseRequested is true once our peer has been asked to close the
+	// channel cooperatively because our commitment transaction pays less
+	// than the mempool minimum fee.
+	coopCloseRequested bool
+
+	// coopCloseResult delivers the result of our pending cooperative
+	// close request. It is nil if there is no pending request.
+	coopCloseResult <-chan error
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -797,6 +856,33 @@ func (t transitionTrigger) String() string {
 	}
 }
 
+// description returns a human readable description of the event that caused a
+// state transition with the given trigger.
+func (t transitionTrigger) description() string {
+	switch t {
+	case chainTrigger:
+		return "new block or change to the set of active HTLCs"
+
+	case userTrigger:
+		return "force close requested by user"
+
+	case remoteCloseTrigger:
+		return "remote commitment confirmed"
+
+	case localCloseTrigger:
+		return "local commitment confirmed"
+
+	case coopCloseTrigger:
+		return "cooperative close confirmed"
+
+	case breachCloseTrigger:
+		return "remote breach confirmed"
+
+	default:
+		return "unknown trigger"
+	}
+}
+
 // stateStep is a help method that examines our internal state, and attempts
 // the appropriate state transition if necessary. The next state we transition
 // to is returned, Additionally, if the next transition results in a commitment
@@ -992,7 +1078,21 @@ func (c *ChannelArbitrator) stateStep(
 		if err := c.cfg.PublishTx(closeTx, label); err != nil {
 			log.Errorf("ChannelArbitrator(%v): unable to broadcast "+
 				"close tx: %v", c.cfg.ChanPoint, err)
-			if err != lnwallet.ErrDoubleSpend {
+
+			switch {
+			case err == lnwallet.ErrDoubleSpend:
+
+			// If the commitment was rejected by the mempool
+			// policy, retrying it right away won't help. We move
+			// on and rebroadcast it on every block instead, and
+			// fall back to a cooperative close if its fee rate is
+			// too low.
+			case errors.Is(err, lnwallet.ErrMempoolRejected):
+				c.rejectedCommit = closeTx
+				c.notifyCommitRejected(triggerHeight, err)
+				c.maybeRequestCoopClose(err)
+
+			default:
 				return StateError, closeTx, err
 			}
 		}
@@ -1009,6 +1109,13 @@ func (c *ChannelArbitrator) stateStep(
 
 		// We are waiting for a commitment to be confirmed.
 		case chainTrigger, userTrigger:
+			// If the broadcast of our commitment was rejected, we
+			// try again on every new block, as the mempool might
+			// have changed.
+			if trigger == chainTrigger {
+				c.rebroadcastRejectedCommit(triggerHeight)
+			}
+
 			// The commitment transaction has been broadcast, but it
 			// doesn't necessarily need to be the commitment
 			// transaction version that is going to be confirmed. To
@@ -1166,6 +1273,125 @@ func (c *ChannelArbitrator) stateStep(
 	return nextState, closeTx, nil
 }
 
+// rebroadcastRejectedCommit rebroadcasts our commitment transaction if its
+// last broadcast was rejected by the mempool policy of the backend.
+func (c *ChannelArbitrator) rebroadcastRejectedCommit(height uint32) {
+	if c.rejectedCommit == nil {
+		return
+	}
+
+	label := labels.MakeLabel(
+		labels.LabelTypeChannelClose, &c.cfg.ShortChanID,
+	)
+	err := c.cfg.PublishTx(c.rejectedCommit, label)
+	switch {
+	case err == nil:
+		log.Infof("ChannelArbitrator(%v): rebroadcast of rejected "+
+			"commitment %v succeeded", c.cfg.ChanPoint,
+			c.rejectedCommit.TxHash())
+
+		c.rejectedCommit = nil
+
+	// A conflicting commitment was accepted, which will be handled once
+	// it confirms.
+	case err == lnwallet.ErrDoubleSpend:
+		c.rejectedCommit = nil
+
+	case errors.Is(err, lnwallet.ErrMempoolRejected):
+		c.notifyCommitRejected(height, err)
+		c.maybeRequestCoopClose(err)
+
+	default:
+		log.Errorf("ChannelArbitrator(%v): unable to rebroadcast "+
+			"commitment %v: %v", c.cfg.ChanPoint,
+			c.rejectedCommit.TxHash(), err)
+	}
+}
+
+// maybeRequestCoopClose asks our peer to close the channel cooperatively if
+// our commitment transaction was rejected because its fee rate is below the
+// mempool minimum. The closing transaction is negotiated at the current fee
+// rate, so it can enter the mempool where our commitment can't. CPFP through
+// the anchor doesn't help here, as the backend only accepts the child once
+// the parent is in its mempool. The request is retried on every block until
+// the peer accepts it, and our commitment is kept being rebroadcast meanwhile.
+// We don't wait for the peer to handle the request, its result is processed
+// by the channelAttendant once it arrives.
+func (c *ChannelArbitrator) maybeRequestCoopClose(err error) {
+	if c.coopCloseRequested || c.cfg.RequestCoopClose == nil ||
+		!errors.Is(err, lnwallet.ErrMempoolMinFee) {
+
+		return
+	}
+
+	// A cooperative close can only be negotiated for a channel without
+	// any HTLCs, the others need to be resolved on-chain.
+	c.updateActiveHTLCs()
+	for _, htlcs := range c.activeHTLCs {
+		if len(htlcs.incomingHTLCs) != 0 ||
+			len(htlcs.outgoingHTLCs) != 0 {
+
+			return
+		}
+	}
+
+	result, err := c.cfg.RequestCoopClose(c.cfg.ChanPoint)
+	if err != nil {
+		log.Warnf("ChannelArbitrator(%v): unable to request "+
+			"cooperative close, will retry: %v", c.cfg.ChanPoint,
+			err)
+		return
+	}
+
+	log.Infof("ChannelArbitrator(%v): requested cooperative close as "+
+		"fallback for commitment rejected by mempool policy",
+		c.cfg.ChanPoint)
+
+	c.coopCloseRequested = true
+	c.coopCloseResult = result
+}
+
+// handleCoopCloseResult processes the result of our cooperative close
+// request. If it failed, the request is made again on the next block.
+func (c *ChannelArbitrator) handleCoopCloseResult(err error) {
+	c.coopCloseResult = nil
+
+	if err == nil {
+		log.Infof("ChannelArbitrator(%v): cooperative close request "+
+			"accepted by peer", c.cfg.ChanPoint)
+		return
+	}
+
+	log.Warnf("ChannelArbitrator(%v): cooperative close request failed, "+
+		"will retry: %v", c.cfg.ChanPoint, err)
+
+	c.coopCloseRequested = false
+}
+
+// notifyCommitRejected alerts the operator that the broadcast of our
+// commitment transaction was rejected by the mempool policy of the backend.
+func (c *ChannelArbitrator) notifyCommitRejected(height uint32, err error) {
+	txid := c.rejectedCommit.TxHash()
+
+	log.Errorf("ChannelArbitrator(%v): commitment %v rejected by mempool "+
+		"policy, will rebroadcast every block: %v", c.cfg.ChanPoint,
+		txid, err)
+
+	if c.cfg.NotifyStateTransition == nil {
+		return
+	}
+
+	c.cfg.NotifyStateTransition(&ArbitratorStateUpdate{
+		ChanPoint:     c.cfg.ChanPoint,
+		PrevState:     c.state,
+		NewState:      c.state,
+		TriggerHeight: height,
+		Reason:        "commitment rejected by mempool policy",
+		CloseTxid:     &txid,
+		BroadcastErr:  err,
+	})
+}
+
 // sweepAnchors offers all given anchor resolutions to the sweeper. It requests
 // sweeping at the minimum fee rate. This fee rate can be upped manually by the
 // user via the BumpFee rpc.
@@ -1422,6 +1648,22 @@ func (c *ChannelArbitrator) advanceState(
 			return priorState, nil, err
 		}
 		c.state = nextState
+
+		if c.cfg.NotifyStateTransition != nil {
+			update := &ArbitratorStateUpdate{
+				ChanPoint:     c.cfg.ChanPoint,
+				PrevState:     priorState,
+				NewState:      nextState,
+				TriggerHeight: triggerHeight,
+				Reason:        trigger.description(),
+			}
+			if closeTx != nil {
+				txid := closeTx.TxHash()
+				update.CloseTxid = &txid
+			}
+
+			c.cfg.NotifyStateTransition(update)
+		}
 	}
 }
 
@@ -2486,8 +2728,9 @@ func (c *ChannelArbitrator) channelAttendant(bestHeight int32) {
 
 			// If we're not in the default state, then we can
 			// ignore this signal as we're waiting for contract
-			// resolution.
-			if c.state != StateDefault {
+			// resolution, unless the broadcast of our commitment
+			// was rejected and needs to be retried.
+			if c.state != StateDefault && c.rejectedCommit == nil {
 				continue
 			}
 
@@ -2506,6 +2749,11 @@ func (c *ChannelArbitrator) channelAttendant(bestHeight int32) {
 				return
 			}
 
+		// Our peer has handled our request to close the channel
+		// cooperatively.
+		case err := <-c.coopCloseResult:
+			c.handleCoopCloseResult(err)
+
 		// A new signal update was just sent. This indicates that the
 		// channel under watch is now live, and may modify its internal
 		// state, so we'll get the most up to date signals to we can
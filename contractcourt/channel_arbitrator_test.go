@@ -2728,3 +2728,311 @@ func (m *mockChannel) ForceCloseChan() (*lnwallet.LocalForceCloseSummary, error)
 	}
 	return summary, nil
 }
+
+// TestChannelArbitratorStateNotifications asserts that the channel arbitrator
+// notifies its subscribers about each state transition it commits, including
+// why it transitioned and the txid of the commitment it broadcast.
+func TestChannelArbitratorStateNotifications(t *testing.T) {
+	t.Parallel()
+
+	log := &mockArbitratorLog{
+		state:     StateDefault,
+		newStates: make(chan ArbitratorState, 5),
+	}
+
+	updates := make(chan *ArbitratorStateUpdate, 5)
+	chanArbCtx, err := createTestChannelArbitrator(
+		t, log, func(cfg *ChannelArbitratorConfig) {
+			cfg.NotifyStateTransition = func(
+				u *ArbitratorStateUpdate) {
+
+				updates <- u
+			}
+		},
+	)
+	require.NoError(t, err, "unable to create ChannelArbitrator")
+	chanArb := chanArbCtx.chanArb
+
+	require.NoError(t, chanArb.Start(nil))
+	defer chanArb.Stop()
+
+	errChan := make(chan error, 1)
+	respChan := make(chan *wire.MsgTx, 1)
+	chanArb.forceCloseReqs <- &forceCloseReq{
+		errResp: errChan,
+		closeTx: respChan,
+	}
+
+	chanArbCtx.AssertStateTransitions(
+		StateBroadcastCommit, StateCommitmentBroadcasted,
+	)
+
+	var closeTx *wire.MsgTx
+	select {
+	case closeTx = <-respChan:
+	case <-time.After(defaultTimeout):
+		t.Fatalf("no response received")
+	}
+
+	assertUpdate := func(prev, next ArbitratorState) *ArbitratorStateUpdate {
+		t.Helper()
+
+		select {
+		case u := <-updates:
+			require.Equal(t, chanArb.cfg.ChanPoint, u.ChanPoint)
+			require.Equal(t, prev, u.PrevState)
+			require.Equal(t, next, u.NewState)
+			require.Equal(t, userTrigger.description(), u.Reason)
+
+			return u
+
+		case <-time.After(defaultTimeout):
+			t.Fatalf("no state update received")
+			return nil
+		}
+	}
+
+	// The first transition only decides to go on-chain, so no closing
+	// transaction should be reported yet.
+	update := assertUpdate(StateDefault, StateBroadcastCommit)
+	require.Nil(t, update.CloseTxid)
+
+	// The second one broadcasts our commitment.
+	update = assertUpdate(StateBroadcastCommit, StateCommitmentBroadcasted)
+	require.NotNil(t, update.CloseTxid)
+	require.Equal(t, closeTx.TxHash(), *update.CloseTxid)
+}
+
+// TestChannelArbitratorCommitRejected tests that a commitment that is rejected
+// by the mempool policy doesn't fail the force close, triggers an alert and is
+// rebroadcast on the next block.
+func TestChannelArbitratorCommitRejected(t *testing.T) {
+	t.Parallel()
+
+	log := &mockArbitratorLog{
+		state:     StateDefault,
+		newStates: make(chan ArbitratorState, 5),
+	}
+
+	updates := make(chan *ArbitratorStateUpdate, 5)
+	chanArbCtx, err := createTestChannelArbitrator(
+		t, log, func(cfg *ChannelArbitratorConfig) {
+			cfg.NotifyStateTransition = func(
+				u *ArbitratorStateUpdate) {
+
+				updates <- u
+			}
+		},
+	)
+	require.NoError(t, err, "unable to create ChannelArbitrator")
+	chanArb := chanArbCtx.chanArb
+
+	// The first broadcast is rejected, the rebroadcast succeeds.
+	errRejected := fmt.Errorf("%w: min relay fee not met",
+		lnwallet.ErrMempoolRejected)
+	publishErrs := make(chan error, 2)
+	publishErrs <- errRejected
+	publishErrs <- nil
+
+	published := make(chan *wire.MsgTx, 2)
+	chanArb.cfg.PublishTx = func(tx *wire.MsgTx, _ string) error {
+		published <- tx
+		return <-publishErrs
+	}
+
+	require.NoError(t, chanArb.Start(nil))
+	defer chanArb.Stop()
+
+	errChan := make(chan error, 1)
+	respChan := make(chan *wire.MsgTx, 1)
+	chanArb.forceCloseReqs <- &forceCloseReq{
+		errResp: errChan,
+		closeTx: respChan,
+	}
+
+	// The force close should succeed despite the rejection.
+	chanArbCtx.AssertStateTransitions(
+		StateBroadcastCommit, StateCommitmentBroadcasted,
+	)
+	select {
+	case err := <-errChan:
+		require.NoError(t, err)
+	case <-time.After(defaultTimeout):
+		t.Fatalf("no response received")
+	}
+
+	var closeTx *wire.MsgTx
+	select {
+	case closeTx = <-published:
+	case <-time.After(defaultTimeout):
+		t.Fatalf("commitment not published")
+	}
+
+	// An alert carrying the broadcast error should be sent besides the
+	// regular state updates.
+	var alert *ArbitratorStateUpdate
+	for i := 0; i < 3 && alert == nil; i++ {
+		select {
+		case u := <-updates:
+			if u.BroadcastErr != nil {
+				alert = u
+			}
+
+		case <-time.After(defaultTimeout):
+			t.Fatalf("no state update received")
+		}
+	}
+	require.NotNil(t, alert, "no alert received")
+	require.ErrorIs(t, alert.BroadcastErr, lnwallet.ErrMempoolRejected)
+	require.Equal(t, alert.PrevState, alert.NewState)
+	require.Equal(t, closeTx.TxHash(), *alert.CloseTxid)
+
+	// On the next block, the rejected commitment should be rebroadcast.
+	chanArb.blocks <- 5
+
+	select {
+	case tx := <-published:
+		require.Equal(t, closeTx.TxHash(), tx.TxHash())
+	case <-time.After(defaultTimeout):
+		t.Fatalf("commitment not rebroadcast")
+	}
+
+	// As the rebroadcast succeeded, it isn't attempted again.
+	chanArb.blocks <- 6
+
+	select {
+	case <-published:
+		t.Fatalf("commitment rebroadcast after success")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestChannelArbitratorCommitRejectedCoopClose tests that a cooperative close
+// is requested if our commitment is rejected for paying less than the mempool
+// minimum fee, and that the request is retried until the peer accepts it.
+func TestChannelArbitratorCommitRejectedCoopClose(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		publishErr  error
+		htlcs       []channeldb.HTLC
+		expRequests int
+	}{{
+		name:        "min fee",
+		publishErr:  lnwallet.ErrMempoolMinFee,
+		expRequests: 2,
+	}, {
+		name:       "min fee with htlcs",
+		publishErr: lnwallet.ErrMempoolMinFee,
+		htlcs: []channeldb.HTLC{{
+			Amt:         10000,
+			HtlcIndex:   99,
+			OutputIndex: 0,
+		}},
+	}, {
+		name:       "chain too long",
+		publishErr: lnwallet.ErrMempoolChainTooLong,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			testCommitRejectedCoopClose(
+				t, tc.publishErr, tc.htlcs, tc.expRequests,
+			)
+		})
+	}
+}
+
+func testCommitRejectedCoopClose(t *testing.T, publishErr error,
+	htlcs []channeldb.HTLC, expRequests int) {
+
+	log := &mockArbitratorLog{
+		state:     StateDefault,
+		newStates: make(chan ArbitratorState, 5),
+	}
+
+	chanArbCtx, err := createTestChannelArbitrator(t, log)
+	require.NoError(t, err, "unable to create ChannelArbitrator")
+	chanArb := chanArbCtx.chanArb
+
+	// Every broadcast of the commitment is rejected.
+	published := make(chan struct{}, 10)
+	chanArb.cfg.PublishTx = func(*wire.MsgTx, string) error {
+		published <- struct{}{}
+		return fmt.Errorf("%w: -26: min relay fee not met", publishErr)
+	}
+
+	// The first request fails as if the peer went offline before handling
+	// it, all following ones succeed.
+	requests := make(chan wire.OutPoint, 10)
+	chanArb.cfg.RequestCoopClose = func(op wire.OutPoint) (<-chan error,
+		error) {
+
+		requests <- op
+
+		result := make(chan error, 1)
+		if len(requests) == 1 {
+			result <- errors.New("peer offline")
+		} else {
+			result <- nil
+		}
+
+		return result, nil
+	}
+
+	require.NoError(t, chanArb.Start(nil))
+	defer chanArb.Stop()
+
+	if len(htlcs) != 0 {
+		chanArb.notifyContractUpdate(&ContractUpdate{
+			HtlcKey: LocalHtlcSet,
+			Htlcs:   htlcs,
+		})
+	}
+
+	errChan := make(chan error, 1)
+	respChan := make(chan *wire.MsgTx, 1)
+	chanArb.forceCloseReqs <- &forceCloseReq{
+		errResp: errChan,
+		closeTx: respChan,
+	}
+
+	chanArbCtx.AssertStateTransitions(
+		StateBroadcastCommit, StateCommitmentBroadcasted,
+	)
+	select {
+	case err := <-errChan:
+		require.NoError(t, err)
+	case <-time.After(defaultTimeout):
+		t.Fatalf("no response received")
+	}
+
+	select {
+	case <-published:
+	case <-time.After(defaultTimeout):
+		t.Fatalf("commitment not published")
+	}
+
+	// Mine a few blocks, each of which leads to a rebroadcast of the
+	// commitment. As the request is made after the rebroadcast, the last
+	// rebroadcast ensures the requests of all prior blocks were made.
+	for height := int32(5); height <= 8; height++ {
+		chanArb.blocks <- height
+
+		select {
+		case <-published:
+		case <-time.After(defaultTimeout):
+			t.Fatalf("commitment not rebroadcast")
+		}
+	}
+
+	require.Len(t, requests, expRequests)
+	for i := 0; i < expRequests; i++ {
+		require.Equal(t, chanArb.cfg.ChanPoint, <-requests)
+	}
+}
@@ -346,6 +346,10 @@ func (c *commitSweepResolver) Resolve() (ContractResolver, error) {
 			// No errors, therefore continue processing.
 			c.log.Infof("local commitment output fully resolved by "+
 				"sweep tx: %v", sweepResult.Tx.TxHash())
+
+			c.recordSweepFee(
+				c.commitResolution.SelfOutPoint, sweepResult,
+			)
 		default:
 			// Unknown errors.
 			c.log.Errorf("unable to sweep input: %v",
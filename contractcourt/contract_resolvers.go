@@ -10,6 +10,7 @@ import (
 	"github.com/btcsuite/btclog"
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/sweep"
 )
 
 var (
@@ -125,6 +126,54 @@ func (r *contractResolverKit) initLogger(resolver ContractResolver) {
 	r.log = build.NewPrefixLog(logPrefix, log)
 }
 
+// recordSweepFee records our share of the fee of the sweep transaction that
+// claimed the given outpoint, so that it counts towards the on-chain fees paid
+// for the channel. Failures are only logged, as they don't affect the
+// resolution of the contract.
+func (r *contractResolverKit) recordSweepFee(op wire.OutPoint,
+	result sweep.Result) {
+
+	if r.PutChannelFee == nil || result.Tx == nil || result.Fee <= 0 {
+		return
+	}
+
+	for i, txIn := range result.Tx.TxIn {
+		if txIn.PreviousOutPoint != op {
+			continue
+		}
+
+		err := r.PutChannelFee(
+			result.Tx.TxHash(), uint32(i), channeldb.ChannelFeeSweep,
+			result.Fee,
+		)
+		if err != nil {
+			log.Errorf("ChannelPoint(%v): unable to record sweep "+
+				"fee: %v", r.ChanPoint, err)
+		}
+
+		return
+	}
+}
+
+// watchSweepFee records our share of the fee of the sweep transaction that
+// claims the given outpoint once the sweeper reports the result. It's used by
+// resolvers that wait for the spend of the outpoint rather than for the result
+// of the sweeper.
+func (r *contractResolverKit) watchSweepFee(op wire.OutPoint,
+	resultChan chan sweep.Result) {
+
+	go func() {
+		select {
+		case result := <-resultChan:
+			if result.Err == nil {
+				r.recordSweepFee(op, result)
+			}
+
+		case <-r.quit:
+		}
+	}()
+}
+
 var (
 	// errResolverShuttingDown is returned when the resolver stops
 	// progressing because it received the quit signal.
@@ -242,7 +242,7 @@ func (h *htlcSuccessResolver) broadcastReSignedSuccessTx() (
 			h.htlcResolution.SignDetails, h.htlcResolution.Preimage,
 			h.broadcastHeight,
 		)
-		_, err := h.Sweeper.SweepInput(
+		resultChan, err := h.Sweeper.SweepInput(
 			&secondLevelInput,
 			sweep.Params{
 				Fee: sweep.FeePreference{
@@ -253,6 +253,8 @@ func (h *htlcSuccessResolver) broadcastReSignedSuccessTx() (
 		if err != nil {
 			return nil, err
 		}
+		successTx := h.htlcResolution.SignedSuccessTx
+		h.watchSweepFee(successTx.TxIn[0].PreviousOutPoint, resultChan)
 
 		log.Infof("%T(%x): waiting for second-level HTLC success "+
 			"transaction to confirm", h, h.htlc.RHash[:])
@@ -347,7 +349,7 @@ func (h *htlcSuccessResolver) broadcastReSignedSuccessTx() (
 		h.htlcResolution.CsvDelay, h.broadcastHeight,
 		h.htlc.RHash,
 	)
-	_, err = h.Sweeper.SweepInput(
+	resultChan, err := h.Sweeper.SweepInput(
 		inp,
 		sweep.Params{
 			Fee: sweep.FeePreference{
@@ -358,6 +360,7 @@ func (h *htlcSuccessResolver) broadcastReSignedSuccessTx() (
 	if err != nil {
 		return nil, err
 	}
+	h.watchSweepFee(*op, resultChan)
 
 	// Will return this outpoint, when this is spent the resolver is fully
 	// resolved.
@@ -452,6 +455,14 @@ func (h *htlcSuccessResolver) resolveRemoteCommitOutput() (
 		return nil, errResolverShuttingDown
 	}
 
+	// The sweep transaction only spends the HTLC output, so its whole
+	// fee is ours.
+	htlcValue := h.htlcResolution.SweepSignDesc.Output.Value
+	h.recordSweepFee(h.htlcResolution.ClaimOutpoint, sweep.Result{
+		Tx:  h.sweepTx,
+		Fee: btcutil.Amount(htlcValue - h.sweepTx.TxOut[0].Value),
+	})
+
 	// Once the transaction has received a sufficient number of
 	// confirmations, we'll mark ourselves as fully resolved and exit.
 	h.resolved = true
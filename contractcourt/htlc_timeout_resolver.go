@@ -331,7 +331,7 @@ func (h *htlcTimeoutResolver) spendHtlcOutput() (*chainntnfs.SpendDetail, error)
 			h.htlcResolution.SignDetails,
 			h.broadcastHeight,
 		)
-		_, err := h.Sweeper.SweepInput(
+		resultChan, err := h.Sweeper.SweepInput(
 			&inp,
 			sweep.Params{
 				Fee: sweep.FeePreference{
@@ -342,6 +342,8 @@ func (h *htlcTimeoutResolver) spendHtlcOutput() (*chainntnfs.SpendDetail, error)
 		if err != nil {
 			return nil, err
 		}
+		timeoutTx := h.htlcResolution.SignedTimeoutTx
+		h.watchSweepFee(timeoutTx.TxIn[0].PreviousOutPoint, resultChan)
 
 	// If we have no SignDetails, and we haven't already sent the output to
 	// the utxo nursery, then we'll do so now.
@@ -474,7 +476,7 @@ func (h *htlcTimeoutResolver) handleCommitSpend(
 			h.htlcResolution.CsvDelay, h.broadcastHeight,
 			h.htlc.RHash,
 		)
-		_, err = h.Sweeper.SweepInput(
+		resultChan, err := h.Sweeper.SweepInput(
 			inp,
 			sweep.Params{
 				Fee: sweep.FeePreference{
@@ -485,6 +487,7 @@ func (h *htlcTimeoutResolver) handleCommitSpend(
 		if err != nil {
 			return nil, err
 		}
+		h.watchSweepFee(*op, resultChan)
 
 		// Update the claim outpoint to point to the second-level
 		// transaction created by the sweeper.
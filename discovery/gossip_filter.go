@@ -0,0 +1,100 @@
+package discovery
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// GossipFilterMode determines which of its gossip messages we ask a peer to
+// send us through the gossip timestamp filter we send to it.
+type GossipFilterMode uint8
+
+const (
+	// GossipFilterMute asks the peer not to send us any gossip messages,
+	// regardless of its sync type.
+	GossipFilterMute GossipFilterMode = iota
+
+	// GossipFilterAll asks the peer to send us all new gossip messages,
+	// just like we do for active syncers.
+	GossipFilterAll
+
+	// GossipFilterCustom asks the peer to send us the gossip messages with
+	// a timestamp within a custom range.
+	GossipFilterCustom
+)
+
+// String returns a human readable string describing the gossip filter mode.
+func (m GossipFilterMode) String() string {
+	switch m {
+	case GossipFilterMute:
+		return "GossipFilterMute"
+
+	case GossipFilterAll:
+		return "GossipFilterAll"
+
+	case GossipFilterCustom:
+		return "GossipFilterCustom"
+
+	default:
+		return fmt.Sprintf("unknown gossip filter mode: %d", m)
+	}
+}
+
+var (
+	// ErrInvalidGossipFilter is returned when a gossip filter with a
+	// custom timestamp range doesn't cover any time at all.
+	ErrInvalidGossipFilter = errors.New("custom gossip filter must have " +
+		"a non-zero timestamp range")
+)
+
+// GossipFilter is a gossip timestamp filter we send to a peer in place of the
+// one that is determined by the sync type of its gossip syncer. This allows
+// the gossip received from selected peers to be limited, for example to save
+// bandwidth on metered connections.
+type GossipFilter struct {
+	// Mode determines which gossip messages the peer should send us.
+	Mode GossipFilterMode
+
+	// FirstTimestamp is the start of the custom timestamp range. This is
+	// only used with the GossipFilterCustom mode.
+	FirstTimestamp time.Time
+
+	// TimestampRange is the number of seconds the custom timestamp range
+	// spans. This is only used with the GossipFilterCustom mode.
+	TimestampRange uint32
+}
+
+// Validate returns an error if the gossip filter can't be sent to a peer.
+func (f *GossipFilter) Validate() error {
+	switch f.Mode {
+	case GossipFilterMute, GossipFilterAll:
+		return nil
+
+	case GossipFilterCustom:
+		if f.TimestampRange == 0 {
+			return ErrInvalidGossipFilter
+		}
+
+		return nil
+
+	default:
+		return fmt.Errorf("unknown gossip filter mode %v", f.Mode)
+	}
+}
+
+// timestampRange returns the first timestamp and the timestamp range of the
+// gossip timestamp filter that should be sent to the peer.
+func (f *GossipFilter) timestampRange() (time.Time, uint32) {
+	switch f.Mode {
+	case GossipFilterAll:
+		return time.Now(), math.MaxUint32
+
+	case GossipFilterCustom:
+		return f.FirstTimestamp, f.TimestampRange
+
+	default:
+		return zeroTimestamp, 0
+	}
+}
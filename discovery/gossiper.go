@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -42,6 +43,27 @@ const (
 	// channel and direction.
 	DefaultChannelUpdateInterval = time.Minute
 
+	// DefaultRebroadcastInterval is the default interval after which we'll
+	// rebroadcast the channel updates of our public channels and our node
+	// announcement, even if nothing has changed.
+	DefaultRebroadcastInterval = 24 * time.Hour
+
+	// DefaultRebroadcastJitter is the default maximum duration by which
+	// the rebroadcast of a channel update is randomly brought forward.
+	DefaultRebroadcastJitter = time.Hour
+
+	// ChannelUpdateStaleness is the age after which a channel update is
+	// commonly considered stale by the network. Nodes prune channels for
+	// which neither direction has been updated within this period, so our
+	// rebroadcast interval must stay well below it.
+	ChannelUpdateStaleness = 14 * 24 * time.Hour
+
+	// remoteKeepAliveInterval is the minimum duration between two
+	// keep-alive channel updates of a remote node that we propagate. It
+	// doesn't depend on our own rebroadcast interval, as remote nodes send
+	// their keep-alive updates on their own schedule.
+	remoteKeepAliveInterval = 24 * time.Hour
+
 	// maxPrematureUpdates tracks the max amount of premature channel
 	// updates that we'll hold onto.
 	maxPrematureUpdates = 100
@@ -220,6 +242,13 @@ type Config struct {
 	// having zombie channels.
 	RebroadcastInterval time.Duration
 
+	// RebroadcastJitter is the maximum duration by which the rebroadcast
+	// of the channel update of each of our channels is randomly brought
+	// forward. This spreads the rebroadcasts of our channels over time
+	// instead of sending them all out at once. It must be smaller than
+	// RebroadcastInterval.
+	RebroadcastJitter time.Duration
+
 	// WaitingProofStore is a persistent storage of partial channel proof
 	// announcement messages. We use it to buffer half of the material
 	// needed to reconstruct a full authenticated channel announcement.
@@ -461,9 +490,45 @@ type AuthenticatedGossiper struct {
 	// AuthenticatedGossiper lock.
 	chanUpdateRateLimiter map[uint64][2]*rate.Limiter
 
+	// rebroadcastSchedule tracks the jitter drawn for the latest channel
+	// update of each of our public channels, keyed by channel ID. It is
+	// used to determine when the update is due to be rebroadcast.
+	rebroadcastSchedule map[uint64]rebroadcastEntry
+	rebroadcastMtx      sync.Mutex
+
 	sync.Mutex
 }
 
+// rebroadcastEntry holds the random jitter drawn for a specific channel
+// update of one of our channels.
+type rebroadcastEntry struct {
+	// lastUpdate is the timestamp of the channel update the jitter was
+	// drawn for.
+	lastUpdate time.Time
+
+	// jitter is the duration by which the rebroadcast of the channel
+	// update is brought forward.
+	jitter time.Duration
+}
+
+// ScheduledRebroadcast describes when the channel update of one of our public
+// channels is due to be rebroadcast to the network.
+type ScheduledRebroadcast struct {
+	// ChannelID is the short channel ID of the channel.
+	ChannelID lnwire.ShortChannelID
+
+	// ChannelPoint is the funding outpoint of the channel.
+	ChannelPoint wire.OutPoint
+
+	// LastUpdate is the timestamp of our latest channel update for the
+	// channel.
+	LastUpdate time.Time
+
+	// NextRebroadcast is the time after which the channel update will be
+	// refreshed and rebroadcast.
+	NextRebroadcast time.Time
+}
+
 // New creates a new AuthenticatedGossiper instance, initialized with the
 // passed configuration parameters.
 func New(cfg Config, selfKeyDesc *keychain.KeyDescriptor) *AuthenticatedGossiper {
@@ -479,6 +544,7 @@ func New(cfg Config, selfKeyDesc *keychain.KeyDescriptor) *AuthenticatedGossiper
 		channelMtx:              multimutex.NewMutex(),
 		recentRejects:           lru.NewCache(maxRejectedUpdates),
 		chanUpdateRateLimiter:   make(map[uint64][2]*rate.Limiter),
+		rebroadcastSchedule:     make(map[uint64]rebroadcastEntry),
 	}
 
 	gossiper.syncMgr = newSyncManager(&SyncManagerCfg{
@@ -1396,11 +1462,100 @@ func (d *AuthenticatedGossiper) isRecentlyRejectedMsg(msg lnwire.Message,
 	return err != cache.ErrElementNotFound
 }
 
+// nextRebroadcast returns the time at which the channel update of the given
+// channel with the given timestamp is due to be rebroadcast. This is
+// RebroadcastInterval after the update was created, brought forward by a
+// random jitter of up to RebroadcastJitter. A new jitter is drawn whenever the
+// channel update changes.
+//
+// NOTE: This method must be called with the rebroadcastMtx held.
+func (d *AuthenticatedGossiper) nextRebroadcast(chanID uint64,
+	lastUpdate time.Time) time.Time {
+
+	entry, ok := d.rebroadcastSchedule[chanID]
+	if !ok || !entry.lastUpdate.Equal(lastUpdate) {
+		entry = rebroadcastEntry{
+			lastUpdate: lastUpdate,
+		}
+		if d.cfg.RebroadcastJitter > 0 {
+			entry.jitter = time.Duration(
+				rand.Int63n(int64(d.cfg.RebroadcastJitter)),
+			)
+		}
+
+		d.rebroadcastSchedule[chanID] = entry
+	}
+
+	return lastUpdate.Add(d.cfg.RebroadcastInterval - entry.jitter)
+}
+
+// SetChannelUpdateRateLimit replaces the rate limit that is applied to the
+// channel updates we receive for a specific channel and direction. The limit
+// is applied to the rate limiters of channels we've already seen updates for
+// as well.
+func (d *AuthenticatedGossiper) SetChannelUpdateRateLimit(maxBurst int,
+	interval time.Duration) {
+
+	d.Lock()
+	defer d.Unlock()
+
+	d.cfg.MaxChannelUpdateBurst = maxBurst
+	d.cfg.ChannelUpdateInterval = interval
+
+	limit := rate.Every(interval)
+	for _, rls := range d.chanUpdateRateLimiter {
+		for _, rl := range rls {
+			rl.SetLimit(limit)
+			rl.SetBurst(maxBurst)
+		}
+	}
+}
+
+// RebroadcastSchedule returns the time at which the channel update of each of
+// our public channels is next due to be rebroadcast. Private channels are
+// never rebroadcast and are therefore not included.
+func (d *AuthenticatedGossiper) RebroadcastSchedule() ([]ScheduledRebroadcast,
+	error) {
+
+	d.rebroadcastMtx.Lock()
+	defer d.rebroadcastMtx.Unlock()
+
+	var schedule []ScheduledRebroadcast
+	err := d.cfg.Router.ForAllOutgoingChannels(func(
+		_ kvdb.RTx,
+		info *channeldb.ChannelEdgeInfo,
+		edge *channeldb.ChannelEdgePolicy) error {
+
+		if info.AuthProof == nil {
+			return nil
+		}
+
+		schedule = append(schedule, ScheduledRebroadcast{
+			ChannelID: lnwire.NewShortChanIDFromInt(
+				info.ChannelID,
+			),
+			ChannelPoint: info.ChannelPoint,
+			LastUpdate:   edge.LastUpdate,
+			NextRebroadcast: d.nextRebroadcast(
+				info.ChannelID, edge.LastUpdate,
+			),
+		})
+
+		return nil
+	})
+	if err != nil && err != channeldb.ErrGraphNoEdgesFound {
+		return nil, fmt.Errorf("unable to retrieve outgoing channels: "+
+			"%v", err)
+	}
+
+	return schedule, nil
+}
+
 // retransmitStaleAnns examines all outgoing channels that the source node is
 // known to maintain to check to see if any of them are "stale". A channel is
-// stale iff, the last timestamp of its rebroadcast is older than the
-// RebroadcastInterval. We also check if a refreshed node announcement should
-// be resent.
+// stale iff its rebroadcast is due according to nextRebroadcast, which keeps
+// the age of our channel updates below RebroadcastInterval. We also check if
+// a refreshed node announcement should be resent.
 func (d *AuthenticatedGossiper) retransmitStaleAnns(now time.Time) error {
 	// Iterate over all of our channels and check if any of them fall
 	// within the prune interval or re-broadcast interval.
@@ -1413,6 +1568,11 @@ func (d *AuthenticatedGossiper) retransmitStaleAnns(now time.Time) error {
 		havePublicChannels bool
 		edgesToUpdate      []updateTuple
 	)
+
+	// We'll keep track of the public channels we come across, so that we
+	// can drop the schedule entries of channels that were closed.
+	d.rebroadcastMtx.Lock()
+	publicChans := make(map[uint64]struct{})
 	err := d.cfg.Router.ForAllOutgoingChannels(func(
 		_ kvdb.RTx,
 		info *channeldb.ChannelEdgeInfo,
@@ -1433,6 +1593,7 @@ func (d *AuthenticatedGossiper) retransmitStaleAnns(now time.Time) error {
 		// use this to determine whether we should send a node
 		// announcement below.
 		havePublicChannels = true
+		publicChans[info.ChannelID] = struct{}{}
 
 		// If this edge has a ChannelUpdate that was created before the
 		// introduction of the MaxHTLC field, then we'll update this
@@ -1450,12 +1611,12 @@ func (d *AuthenticatedGossiper) retransmitStaleAnns(now time.Time) error {
 			return nil
 		}
 
-		timeElapsed := now.Sub(edge.LastUpdate)
-
-		// If it's been longer than RebroadcastInterval since we've
-		// re-broadcasted the channel, add the channel to the set of
-		// edges we need to update.
-		if timeElapsed >= d.cfg.RebroadcastInterval {
+		// If the rebroadcast of the channel is due, add the channel to
+		// the set of edges we need to update.
+		nextRebroadcast := d.nextRebroadcast(
+			info.ChannelID, edge.LastUpdate,
+		)
+		if !now.Before(nextRebroadcast) {
 			edgesToUpdate = append(edgesToUpdate, updateTuple{
 				info: info,
 				edge: edge,
@@ -1465,9 +1626,16 @@ func (d *AuthenticatedGossiper) retransmitStaleAnns(now time.Time) error {
 		return nil
 	})
 	if err != nil && err != channeldb.ErrGraphNoEdgesFound {
+		d.rebroadcastMtx.Unlock()
 		return fmt.Errorf("unable to retrieve outgoing channels: %v",
 			err)
 	}
+	for chanID := range d.rebroadcastSchedule {
+		if _, ok := publicChans[chanID]; !ok {
+			delete(d.rebroadcastSchedule, chanID)
+		}
+	}
+	d.rebroadcastMtx.Unlock()
 
 	var signedUpdates []lnwire.Message
 	for _, chanToUpdate := range edgesToUpdate {
@@ -2666,15 +2834,13 @@ func (d *AuthenticatedGossiper) handleChanUpdate(nMsg *networkMsg,
 	// to rate limit its updates to prevent spam throughout the network.
 	if nMsg.isRemote && edgeToUpdate != nil {
 		// If it's a keep-alive update, we'll only propagate one if
-		// it's been a day since the previous. This follows our own
-		// heuristic of sending keep-alive updates after the same
-		// duration (see retransmitStaleAnns).
+		// it's been a day since the previous.
 		timeSinceLastUpdate := timestamp.Sub(edgeToUpdate.LastUpdate)
 		if IsKeepAliveUpdate(upd, edgeToUpdate) {
-			if timeSinceLastUpdate < d.cfg.RebroadcastInterval {
+			if timeSinceLastUpdate < remoteKeepAliveInterval {
 				log.Debugf("Ignoring keep alive update not "+
 					"within %v period for channel %v",
-					d.cfg.RebroadcastInterval, shortChanID)
+					remoteKeepAliveInterval, shortChanID)
 				nMsg.err <- nil
 				return nil, false
 			}
@@ -3787,7 +3787,7 @@ func TestRateLimitChannelUpdates(t *testing.T) {
 	// We'll start with the keep alive case.
 	//
 	// We rate limit any keep alive updates that have not at least spanned
-	// our rebroadcast interval.
+	// a day, regardless of our own rebroadcast interval.
 	rateLimitKeepAliveUpdate := *batch.chanUpdAnn1
 	rateLimitKeepAliveUpdate.Timestamp++
 	require.NoError(t, signUpdate(remoteKeyPriv1, &rateLimitKeepAliveUpdate))
@@ -3796,7 +3796,7 @@ func TestRateLimitChannelUpdates(t *testing.T) {
 	keepAliveUpdate := *batch.chanUpdAnn1
 	keepAliveUpdate.Timestamp = uint32(
 		time.Unix(int64(batch.chanUpdAnn1.Timestamp), 0).
-			Add(ctx.gossiper.cfg.RebroadcastInterval).Unix(),
+			Add(remoteKeepAliveInterval).Unix(),
 	)
 	require.NoError(t, signUpdate(remoteKeyPriv1, &keepAliveUpdate))
 	assertRateLimit(&keepAliveUpdate, nodePeer1, false)
@@ -4029,3 +4029,82 @@ func TestRejectCacheChannelAnn(t *testing.T) {
 		t.Fatal("did not process remote announcement")
 	}
 }
+
+// TestRebroadcastSchedule asserts that the channel updates of our public
+// channels are scheduled to be rebroadcast within the jitter before the
+// rebroadcast interval elapses, and that the jitter is only redrawn once the
+// channel update changes.
+func TestRebroadcastSchedule(t *testing.T) {
+	t.Parallel()
+
+	const (
+		interval = 24 * time.Hour
+		jitter   = time.Hour
+	)
+
+	router := newMockRouter(0)
+	gossiper := New(Config{
+		Router:              router,
+		RebroadcastInterval: interval,
+		RebroadcastJitter:   jitter,
+	}, selfKeyDesc)
+
+	// Add a public and a private channel to the graph.
+	lastUpdate := time.Unix(int64(testTimestamp), 0)
+	addChannel := func(chanID uint64, public bool) {
+		info := channeldb.ChannelEdgeInfo{
+			ChannelID: chanID,
+		}
+		if public {
+			info.AuthProof = &channeldb.ChannelAuthProof{}
+		}
+		router.infos[chanID] = info
+		router.edges[chanID] = []channeldb.ChannelEdgePolicy{{
+			ChannelID:  chanID,
+			LastUpdate: lastUpdate,
+		}}
+	}
+	addChannel(1, true)
+	addChannel(2, false)
+
+	// Only the public channel should be scheduled, within the jitter
+	// before the rebroadcast interval elapses.
+	schedule, err := gossiper.RebroadcastSchedule()
+	require.NoError(t, err)
+	require.Len(t, schedule, 1)
+	require.Equal(t, uint64(1), schedule[0].ChannelID.ToUint64())
+	require.Equal(t, lastUpdate, schedule[0].LastUpdate)
+
+	next := schedule[0].NextRebroadcast
+	require.False(t, next.After(lastUpdate.Add(interval)))
+	require.True(t, next.After(lastUpdate.Add(interval-jitter)))
+
+	// As long as the channel update doesn't change, the schedule should
+	// stay the same.
+	schedule, err = gossiper.RebroadcastSchedule()
+	require.NoError(t, err)
+	require.Len(t, schedule, 1)
+	require.Equal(t, next, schedule[0].NextRebroadcast)
+
+	// Once the channel update is refreshed, it should be scheduled
+	// relative to its new timestamp.
+	newUpdate := next
+	router.edges[1][0].LastUpdate = newUpdate
+
+	schedule, err = gossiper.RebroadcastSchedule()
+	require.NoError(t, err)
+	require.Len(t, schedule, 1)
+	require.Equal(t, newUpdate, schedule[0].LastUpdate)
+	require.False(t, schedule[0].NextRebroadcast.After(
+		newUpdate.Add(interval),
+	))
+	require.True(t, schedule[0].NextRebroadcast.After(
+		newUpdate.Add(interval-jitter),
+	))
+
+	// Once the channel is closed, its schedule entry should be dropped.
+	delete(router.infos, 1)
+	delete(router.edges, 1)
+	require.NoError(t, gossiper.retransmitStaleAnns(newUpdate))
+	require.Empty(t, gossiper.rebroadcastSchedule)
+}
@@ -0,0 +1,173 @@
+package discovery
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+const (
+	// DefaultGraphSliceHops is the default number of hops a graph slice
+	// extends from its root nodes.
+	DefaultGraphSliceHops = 3
+
+	// MaxGraphSliceHops is the maximum number of hops a graph slice can
+	// extend from its root nodes. This bounds the work a single query can
+	// cause, as the number of nodes reached grows quickly with every hop.
+	MaxGraphSliceHops = 6
+)
+
+// GraphSliceQuery describes the part of the channel graph that should be
+// returned to a light client.
+type GraphSliceQuery struct {
+	// Roots are the nodes the slice is centered around, typically the
+	// light client's own channel peers and the destinations it pays.
+	Roots []route.Vertex
+
+	// MaxHops is the maximum distance, in channels, of a node in the slice
+	// to the closest root node.
+	MaxHops uint32
+
+	// MinCapacity is the minimum capacity of the channels included in the
+	// slice. Channels that are too small to carry the payments of the
+	// light client aren't worth syncing.
+	MinCapacity btcutil.Amount
+
+	// IncludeUnannounced indicates whether unannounced channels should be
+	// included in the slice.
+	IncludeUnannounced bool
+}
+
+// GraphSliceChannel is a channel in a graph slice along with the routing
+// policies of both of its directions. Unknown policies are nil.
+type GraphSliceChannel struct {
+	Info    *channeldb.ChannelEdgeInfo
+	Policy1 *channeldb.ChannelEdgePolicy
+	Policy2 *channeldb.ChannelEdgePolicy
+}
+
+// GraphSlice is the part of the channel graph that matched a GraphSliceQuery.
+type GraphSlice struct {
+	// Nodes are the nodes in the slice that we have a node announcement
+	// for.
+	Nodes []*channeldb.LightningNode
+
+	// Channels are the channels between the nodes of the slice.
+	Channels []*GraphSliceChannel
+}
+
+// FetchGraphSlice returns the nodes within the given number of hops of the
+// query's root nodes, and the channels between them that have at least the
+// requested capacity. Only channels above the capacity threshold are
+// traversed, so nodes that can only be reached through smaller channels
+// aren't part of the slice.
+func FetchGraphSlice(graph *channeldb.ChannelGraph,
+	query *GraphSliceQuery) (*GraphSlice, error) {
+
+	if query.MaxHops > MaxGraphSliceHops {
+		return nil, fmt.Errorf("max hops of %d exceeds the maximum of "+
+			"%d", query.MaxHops, MaxGraphSliceHops)
+	}
+
+	var (
+		slice    = &GraphSlice{}
+		visited  = make(map[route.Vertex]struct{})
+		channels = make(map[uint64]struct{})
+		frontier []*channeldb.LightningNode
+	)
+
+	for _, root := range query.Roots {
+		if _, ok := visited[root]; ok {
+			continue
+		}
+
+		node, err := graph.FetchLightningNode(root)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch node %v: %w",
+				root, err)
+		}
+
+		visited[root] = struct{}{}
+		slice.Nodes = append(slice.Nodes, node)
+		frontier = append(frontier, node)
+	}
+
+	// We'll now expand the slice breadth first, one hop at a time. The
+	// channels of the nodes at the outer edge of the slice are only added
+	// if they connect two nodes that are both part of the slice.
+	for hop := uint32(0); hop <= query.MaxHops; hop++ {
+		var next []route.Vertex
+
+		nodes := frontier
+		frontier = nil
+		for _, node := range nodes {
+			err := node.ForEachChannel(nil, func(_ kvdb.RTx,
+				info *channeldb.ChannelEdgeInfo,
+				p1, p2 *channeldb.ChannelEdgePolicy) error {
+
+				if info.Capacity < query.MinCapacity {
+					return nil
+				}
+				if !query.IncludeUnannounced &&
+					info.AuthProof == nil {
+
+					return nil
+				}
+				if _, ok := channels[info.ChannelID]; ok {
+					return nil
+				}
+
+				peer := route.Vertex(info.NodeKey1Bytes)
+				if peer == node.PubKeyBytes {
+					peer = info.NodeKey2Bytes
+				}
+
+				_, known := visited[peer]
+				if !known && hop == query.MaxHops {
+					return nil
+				}
+				if !known {
+					visited[peer] = struct{}{}
+					next = append(next, peer)
+				}
+
+				channels[info.ChannelID] = struct{}{}
+				slice.Channels = append(
+					slice.Channels, &GraphSliceChannel{
+						Info:    info,
+						Policy1: p1,
+						Policy2: p2,
+					},
+				)
+
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Fetch the nodes we've reached with this hop. A channel can be
+		// known without a node announcement for one of its ends, in
+		// which case we can't expand the slice any further from it.
+		for _, pub := range next {
+			node, err := graph.FetchLightningNode(pub)
+			switch {
+			case errors.Is(err, channeldb.ErrGraphNodeNotFound):
+				continue
+
+			case err != nil:
+				return nil, err
+			}
+
+			slice.Nodes = append(slice.Nodes, node)
+			frontier = append(frontier, node)
+		}
+	}
+
+	return slice, nil
+}
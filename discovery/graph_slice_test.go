@@ -0,0 +1,129 @@
+package discovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFetchGraphSlice asserts that a graph slice only contains the nodes
+// within the requested number of hops of its roots, reached through channels
+// above the requested capacity.
+func TestFetchGraphSlice(t *testing.T) {
+	t.Parallel()
+
+	db, err := makeTestDB(t)
+	require.NoError(t, err)
+	graph := db.ChannelGraph()
+
+	// We'll create the graph A - B - C - D, with a small channel B - E
+	// and an unannounced channel A - F.
+	nodes := make(map[string]route.Vertex)
+	for _, name := range []string{"A", "B", "C", "D", "E", "F"} {
+		priv, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		node := &channeldb.LightningNode{
+			HaveNodeAnnouncement: true,
+			LastUpdate:           time.Unix(1, 0),
+			Alias:                name,
+			Features:             lnwire.EmptyFeatureVector(),
+		}
+		copy(node.PubKeyBytes[:], priv.PubKey().SerializeCompressed())
+		require.NoError(t, graph.AddLightningNode(node))
+
+		nodes[name] = node.PubKeyBytes
+	}
+
+	var chanID uint64
+	addChannel := func(node1, node2 string, capacity btcutil.Amount,
+		announced bool) uint64 {
+
+		chanID++
+		edge := &channeldb.ChannelEdgeInfo{
+			ChannelID:        chanID,
+			NodeKey1Bytes:    nodes[node1],
+			NodeKey2Bytes:    nodes[node2],
+			BitcoinKey1Bytes: nodes[node1],
+			BitcoinKey2Bytes: nodes[node2],
+			Capacity:         capacity,
+		}
+		if announced {
+			edge.AuthProof = &channeldb.ChannelAuthProof{
+				NodeSig1Bytes:    []byte{1},
+				NodeSig2Bytes:    []byte{1},
+				BitcoinSig1Bytes: []byte{1},
+				BitcoinSig2Bytes: []byte{1},
+			}
+		}
+		require.NoError(t, graph.AddChannelEdge(edge))
+
+		return chanID
+	}
+
+	chanAB := addChannel("A", "B", 1_000_000, true)
+	chanBC := addChannel("B", "C", 1_000_000, true)
+	chanCD := addChannel("C", "D", 1_000_000, true)
+	chanBE := addChannel("B", "E", 10_000, true)
+	chanAF := addChannel("A", "F", 1_000_000, false)
+
+	assertSlice := func(query *GraphSliceQuery, expNodes []string,
+		expChans []uint64) {
+
+		t.Helper()
+
+		slice, err := FetchGraphSlice(graph, query)
+		require.NoError(t, err)
+
+		var sliceNodes []string
+		for _, node := range slice.Nodes {
+			sliceNodes = append(sliceNodes, node.Alias)
+		}
+		require.ElementsMatch(t, expNodes, sliceNodes)
+
+		var sliceChans []uint64
+		for _, channel := range slice.Channels {
+			sliceChans = append(sliceChans, channel.Info.ChannelID)
+		}
+		require.ElementsMatch(t, expChans, sliceChans)
+	}
+
+	query := &GraphSliceQuery{
+		Roots:       []route.Vertex{nodes["A"]},
+		MaxHops:     2,
+		MinCapacity: 100_000,
+	}
+	assertSlice(query, []string{"A", "B", "C"}, []uint64{chanAB, chanBC})
+
+	// Lowering the capacity threshold adds the small channel, and
+	// including unannounced channels adds the private one.
+	query.MinCapacity = 0
+	query.IncludeUnannounced = true
+	assertSlice(
+		query, []string{"A", "B", "C", "E", "F"},
+		[]uint64{chanAB, chanBC, chanBE, chanAF},
+	)
+
+	// With a second root, the slice extends from both of them. Channels
+	// between nodes at the edge of the slice are included as well.
+	query.Roots = append(query.Roots, nodes["D"])
+	query.MaxHops = 0
+	assertSlice(query, []string{"A", "D"}, nil)
+
+	query.MaxHops = 1
+	assertSlice(
+		query, []string{"A", "B", "C", "D", "F"},
+		[]uint64{chanAB, chanAF, chanCD, chanBC},
+	)
+
+	// Queries that exceed the maximum number of hops are rejected.
+	query.MaxHops = MaxGraphSliceHops + 1
+	_, err = FetchGraphSlice(graph, query)
+	require.Error(t, err)
+}
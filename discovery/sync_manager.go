@@ -2,6 +2,7 @@ package discovery
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -29,6 +30,10 @@ var (
 	// start/stop a gossip syncer for a connected/disconnected peer, but the
 	// SyncManager has already been stopped.
 	ErrSyncManagerExiting = errors.New("sync manager exiting")
+
+	// errMutedSyncer is returned when a muted peer's gossip syncer is
+	// considered for an active sync slot.
+	errMutedSyncer = errors.New("peer is muted")
 )
 
 // newSyncer in an internal message we'll use within the SyncManager to signal
@@ -151,6 +156,12 @@ type SyncManager struct {
 	// duration of the connection.
 	pinnedActiveSyncers map[route.Vertex]*GossipSyncer
 
+	// gossipFilters is the set of gossip filters that override the ones
+	// determined by the sync type of a peer's gossip syncer. They're
+	// applied to the gossip syncer of a peer every time it connects. This
+	// map is guarded by the syncersMu.
+	gossipFilters map[route.Vertex]*GossipFilter
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -168,7 +179,8 @@ func newSyncManager(cfg *SyncManagerCfg) *SyncManager {
 		pinnedActiveSyncers: make(
 			map[route.Vertex]*GossipSyncer, len(cfg.PinnedSyncers),
 		),
-		quit: make(chan struct{}),
+		gossipFilters: make(map[route.Vertex]*GossipFilter),
+		quit:          make(chan struct{}),
 	}
 }
 
@@ -262,6 +274,11 @@ func (m *SyncManager) syncerHandler() {
 			attemptHistoricalSync := false
 
 			m.syncersMu.Lock()
+
+			// If the gossip filter of the peer was overridden,
+			// the syncer will send it once it has started.
+			s.localGossipFilter = m.gossipFilters[s.cfg.peerPub]
+
 			switch {
 			// For pinned syncers, we will immediately transition
 			// the peer into an active (pinned) sync state.
@@ -283,6 +300,11 @@ func (m *SyncManager) syncerHandler() {
 					m.cfg.NumActiveSyncers > 0
 				fallthrough
 
+			// A muted peer doesn't send us any new gossip, so we'll
+			// initialize its GossipSyncer as passive.
+			case m.isMutedSyncer(s):
+				fallthrough
+
 			// If we've exceeded our total number of active syncers,
 			// we'll initialize this GossipSyncer as passive.
 			case len(m.activeSyncers) >= m.cfg.NumActiveSyncers:
@@ -557,6 +579,15 @@ func (m *SyncManager) rotateActiveSyncerCandidate() {
 	m.syncersMu.Lock()
 	defer m.syncersMu.Unlock()
 
+	// A muted active syncer doesn't send us any new gossip, so it's
+	// rotated out first, even if there's no candidate to replace it.
+	for _, s := range m.activeSyncers {
+		if m.isMutedSyncer(s) && s.syncState() == chansSynced {
+			m.replaceActiveSyncer(s)
+			return
+		}
+	}
+
 	// If we couldn't find an eligible active syncer to rotate, we can
 	// return early.
 	activeSyncer := chooseRandomSyncer(m.activeSyncers, nil)
@@ -566,8 +597,16 @@ func (m *SyncManager) rotateActiveSyncerCandidate() {
 	}
 
 	// Similarly, if we don't have a candidate to rotate with, we can return
-	// early as well.
-	candidate := chooseRandomSyncer(m.inactiveSyncers, nil)
+	// early as well. Muted syncers aren't eligible candidates.
+	candidate := chooseRandomSyncer(
+		m.inactiveSyncers, func(s *GossipSyncer) error {
+			if m.isMutedSyncer(s) {
+				return errMutedSyncer
+			}
+
+			return nil
+		},
+	)
 	if candidate == nil {
 		log.Debug("No eligible candidate to rotate active syncer")
 		return
@@ -591,6 +630,29 @@ func (m *SyncManager) rotateActiveSyncerCandidate() {
 	}
 }
 
+// replaceActiveSyncer transitions the given active syncer to a passive one and
+// transitions an eligible passive syncer, if any, to an active one in its
+// place.
+//
+// NOTE: This must be called with the syncersMu lock held.
+func (m *SyncManager) replaceActiveSyncer(s *GossipSyncer) {
+	if err := m.transitionActiveSyncer(s); err != nil {
+		log.Errorf("Unable to transition active GossipSyncer(%x): %v",
+			s.cfg.peerPub, err)
+		return
+	}
+
+	newActiveSyncer := chooseRandomSyncer(
+		m.inactiveSyncers, m.transitionPassiveSyncer,
+	)
+	if newActiveSyncer == nil {
+		return
+	}
+
+	log.Debugf("Replaced active GossipSyncer(%x) with GossipSyncer(%x)",
+		s.cfg.peerPub, newActiveSyncer.cfg.peerPub)
+}
+
 // transitionActiveSyncer transitions an active syncer to a passive one.
 //
 // NOTE: This must be called with the syncersMu lock held.
@@ -612,6 +674,12 @@ func (m *SyncManager) transitionActiveSyncer(s *GossipSyncer) error {
 //
 // NOTE: This must be called with the syncersMu lock held.
 func (m *SyncManager) transitionPassiveSyncer(s *GossipSyncer) error {
+	// A muted peer doesn't send us any new gossip, so it shouldn't take
+	// an active sync slot.
+	if m.isMutedSyncer(s) {
+		return errMutedSyncer
+	}
+
 	log.Debugf("Transitioning passive GossipSyncer(%x) to active",
 		s.cfg.peerPub)
 
@@ -768,6 +836,89 @@ func (m *SyncManager) gossipSyncers() map[route.Vertex]*GossipSyncer {
 	return syncers
 }
 
+// SetGossipFilter overrides the gossip filter we send to the given peer,
+// regardless of the sync type of its gossip syncer. A nil filter removes the
+// override. The filter is sent right away if we're connected to the peer, and
+// every time the peer reconnects until it is removed.
+func (m *SyncManager) SetGossipFilter(peer route.Vertex,
+	filter *GossipFilter) error {
+
+	if filter != nil {
+		if err := filter.Validate(); err != nil {
+			return err
+		}
+	}
+
+	m.syncersMu.Lock()
+	defer m.syncersMu.Unlock()
+
+	// If we're connected to the peer, its gossip syncer should send the
+	// new filter right away.
+	s, ok := m.gossipSyncer(peer)
+	if ok {
+		if err := s.setLocalGossipFilter(filter); err != nil {
+			return fmt.Errorf("unable to set gossip filter of "+
+				"peer %v: %w", peer, err)
+		}
+	}
+
+	if filter == nil {
+		delete(m.gossipFilters, peer)
+	} else {
+		m.gossipFilters[peer] = filter
+	}
+
+	if !ok {
+		return nil
+	}
+
+	_, isActive := m.activeSyncers[peer]
+	_, isInactive := m.inactiveSyncers[peer]
+
+	switch {
+	// A muted peer doesn't send us any new gossip, so if it occupies an
+	// active sync slot, we'll rotate it out.
+	case isActive && m.isMutedSyncer(s):
+		m.replaceActiveSyncer(s)
+
+	// If a peer is no longer muted, it may take a free active sync slot.
+	case isInactive && m.IsGraphSynced() &&
+		len(m.activeSyncers) < m.cfg.NumActiveSyncers:
+
+		err := m.transitionPassiveSyncer(s)
+		if err != nil && err != errMutedSyncer {
+			log.Errorf("Unable to transition passive "+
+				"GossipSyncer(%x): %v", s.cfg.peerPub, err)
+		}
+	}
+
+	return nil
+}
+
+// isMutedSyncer returns true if the gossip filter of the given syncer's peer
+// was overridden to mute it.
+//
+// NOTE: This must be called with the syncersMu lock held.
+func (m *SyncManager) isMutedSyncer(s *GossipSyncer) bool {
+	filter, ok := m.gossipFilters[s.cfg.peerPub]
+	return ok && filter.Mode == GossipFilterMute
+}
+
+// GossipFilters returns the gossip filters that override the ones determined
+// by the sync type of a peer's gossip syncer.
+func (m *SyncManager) GossipFilters() map[route.Vertex]*GossipFilter {
+	m.syncersMu.Lock()
+	defer m.syncersMu.Unlock()
+
+	filters := make(map[route.Vertex]*GossipFilter, len(m.gossipFilters))
+	for peer, filter := range m.gossipFilters {
+		filterCopy := *filter
+		filters[peer] = &filterCopy
+	}
+
+	return filters
+}
+
 // markGraphSynced allows us to report that the initial historical sync has
 // completed.
 func (m *SyncManager) markGraphSynced() {
@@ -678,3 +678,121 @@ func assertActiveSyncerTransition(t *testing.T, s *GossipSyncer, peer *mockPeer)
 	})
 	assertSyncerStatus(t, s, chansSynced, PassiveSync)
 }
+
+// TestSyncManagerGossipFilter tests that the gossip filter of a peer can be
+// overridden, and that the override is applied again when the peer
+// reconnects.
+func TestSyncManagerGossipFilter(t *testing.T) {
+	t.Parallel()
+
+	syncMgr := newTestSyncManager(1)
+	syncMgr.Start()
+	defer syncMgr.Stop()
+
+	// A custom gossip filter must cover some time.
+	peer := randPeer(t, syncMgr.quit)
+	err := syncMgr.SetGossipFilter(peer.PubKey(), &GossipFilter{
+		Mode: GossipFilterCustom,
+	})
+	require.ErrorIs(t, err, ErrInvalidGossipFilter)
+
+	// Our first peer becomes an active syncer after the initial historical
+	// sync.
+	syncMgr.InitSyncState(peer)
+	s := assertSyncerExistence(t, syncMgr, peer)
+	assertTransitionToChansSynced(t, s, peer)
+	assertActiveGossipTimestampRange(t, peer)
+	assertSyncerStatus(t, s, chansSynced, ActiveSync)
+
+	setGossipFilter := func(filter *GossipFilter) chan error {
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- syncMgr.SetGossipFilter(
+				peer.PubKey(), filter,
+			)
+		}()
+
+		return errChan
+	}
+	mutedHorizon := &lnwire.GossipTimestampRange{
+		FirstTimestamp: uint32(zeroTimestamp.Unix()),
+		TimestampRange: 0,
+	}
+
+	// Muting the peer should send a filter that doesn't match any
+	// updates. As the peer no longer sends us any new gossip, its syncer
+	// should give up its active sync slot.
+	errChan := setGossipFilter(&GossipFilter{Mode: GossipFilterMute})
+	assertMsgSent(t, peer, mutedHorizon)
+	require.NoError(t, <-errChan)
+	assertSyncerStatus(t, s, chansSynced, PassiveSync)
+
+	filters := syncMgr.GossipFilters()
+	require.Len(t, filters, 1)
+	require.Equal(t, GossipFilterMute, filters[peer.PubKey()].Mode)
+
+	// When the peer reconnects, the override should be sent again right
+	// away, and not be replaced once the historical sync completed. The
+	// muted peer shouldn't become an active syncer either.
+	syncMgr.PruneSyncState(peer.PubKey())
+	syncMgr.InitSyncState(peer)
+	s = assertSyncerExistence(t, syncMgr, peer)
+	assertMsgSent(t, peer, mutedHorizon)
+	assertTransitionToChansSynced(t, s, peer)
+	assertNoMsgSent(t, peer)
+	assertSyncerStatus(t, s, chansSynced, PassiveSync)
+
+	// Removing the override should restore the update horizon of its sync
+	// type, after which the peer takes the free active sync slot.
+	errChan = setGossipFilter(nil)
+	assertMsgSent(t, peer, mutedHorizon)
+	assertActiveGossipTimestampRange(t, peer)
+	require.NoError(t, <-errChan)
+	require.Empty(t, syncMgr.GossipFilters())
+	assertSyncerStatus(t, s, chansSynced, ActiveSync)
+}
+
+// TestSyncManagerRotateMutedSyncer tests that a muted active syncer is
+// replaced by a passive one, and that a muted syncer doesn't become active
+// through a rotation.
+func TestSyncManagerRotateMutedSyncer(t *testing.T) {
+	t.Parallel()
+
+	syncMgr := newTestSyncManager(1)
+	syncMgr.Start()
+	defer syncMgr.Stop()
+
+	// The first syncer registered performs the historical sync and
+	// becomes active, the second one is passive.
+	activeSyncPeer := randPeer(t, syncMgr.quit)
+	syncMgr.InitSyncState(activeSyncPeer)
+	activeSyncer := assertSyncerExistence(t, syncMgr, activeSyncPeer)
+	assertTransitionToChansSynced(t, activeSyncer, activeSyncPeer)
+	assertActiveGossipTimestampRange(t, activeSyncPeer)
+	assertSyncerStatus(t, activeSyncer, chansSynced, ActiveSync)
+
+	passiveSyncPeer := randPeer(t, syncMgr.quit)
+	syncMgr.InitSyncState(passiveSyncPeer)
+	passiveSyncer := assertSyncerExistence(t, syncMgr, passiveSyncPeer)
+	assertSyncerStatus(t, passiveSyncer, chansSynced, PassiveSync)
+
+	// Muting the active peer should send it the muted horizon and rotate
+	// in the passive syncer in its place.
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- syncMgr.SetGossipFilter(
+			activeSyncPeer.PubKey(),
+			&GossipFilter{Mode: GossipFilterMute},
+		)
+	}()
+	assertActiveSyncerTransition(t, activeSyncer, activeSyncPeer)
+	assertPassiveSyncerTransition(t, passiveSyncer, passiveSyncPeer)
+	require.NoError(t, <-errChan)
+
+	// A rotation shouldn't make the muted peer active again.
+	syncMgr.cfg.RotateTicker.(*ticker.Force).Force <- time.Time{}
+	assertNoMsgSent(t, activeSyncPeer)
+	assertNoMsgSent(t, passiveSyncPeer)
+	assertSyncerStatus(t, activeSyncer, chansSynced, PassiveSync)
+	assertSyncerStatus(t, passiveSyncer, chansSynced, ActiveSync)
+}
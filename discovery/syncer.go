@@ -208,6 +208,13 @@ type syncTransitionReq struct {
 	errChan     chan error
 }
 
+// localGossipFilterReq encapsulates a request for a gossip syncer to override
+// the gossip timestamp filter it sends to the remote peer.
+type localGossipFilterReq struct {
+	filter  *GossipFilter
+	errChan chan error
+}
+
 // historicalSyncReq encapsulates a request for a gossip syncer to perform a
 // historical sync.
 type historicalSyncReq struct {
@@ -319,6 +326,19 @@ type GossipSyncer struct {
 	// determine if we've already sent out our update.
 	localUpdateHorizon *lnwire.GossipTimestampRange
 
+	// localGossipFilter, if set, is the gossip filter we send to the
+	// remote peer in place of the one determined by our sync type.
+	localGossipFilter *GossipFilter
+
+	// localGossipFilterSent is true once the localGossipFilter has been
+	// sent to the remote peer.
+	localGossipFilterSent bool
+
+	// localGossipFilterReqs is a channel through which requests to
+	// override our gossip filter are sent. Like sync transitions, these
+	// are only handled when the gossip syncer is in a chansSynced state.
+	localGossipFilterReqs chan *localGossipFilterReq
+
 	// syncTransitions is a channel through which new sync type transition
 	// requests will be sent through. These requests should only be handled
 	// when the gossip syncer is in a chansSynced state to ensure its state
@@ -423,6 +443,8 @@ func newGossipSyncer(cfg gossipSyncerCfg) *GossipSyncer {
 		gossipMsgs:         make(chan lnwire.Message, 100),
 		queryMsgs:          make(chan lnwire.Message, 100),
 		quit:               make(chan struct{}),
+
+		localGossipFilterReqs: make(chan *localGossipFilterReq),
 	}
 }
 
@@ -595,11 +617,29 @@ func (g *GossipSyncer) channelGraphSyncer() {
 			}
 			g.Unlock()
 
+			switch {
+			// If our gossip filter was overridden, we'll send the
+			// local gossip filter instead of the update horizon of
+			// our sync type, unless we already did.
+			case g.localGossipFilter != nil:
+				if g.localGossipFilterSent {
+					break
+				}
+
+				err := g.sendLocalGossipFilter(
+					g.localGossipFilter,
+				)
+				if err != nil {
+					log.Errorf("Unable to send local "+
+						"gossip filter to %x: %v",
+						g.cfg.peerPub, err)
+				}
+
 			// If we haven't yet sent out our update horizon, and
 			// we want to receive real-time channel updates, we'll
 			// do so now.
-			if g.localUpdateHorizon == nil &&
-				syncType.IsActiveSync() {
+			case g.localUpdateHorizon == nil &&
+				syncType.IsActiveSync():
 
 				err := g.sendGossipTimestampRange(
 					time.Now(), math.MaxUint32,
@@ -627,6 +667,9 @@ func (g *GossipSyncer) channelGraphSyncer() {
 			case req := <-g.historicalSyncReqs:
 				g.handleHistoricalSync(req)
 
+			case req := <-g.localGossipFilterReqs:
+				req.errChan <- g.handleLocalGossipFilter(req)
+
 			case <-g.quit:
 				return
 			}
@@ -1465,6 +1508,13 @@ func (g *GossipSyncer) handleSyncTransition(req *syncTransitionReq) error {
 	log.Debugf("GossipSyncer(%x): transitioning from %v to %v",
 		g.cfg.peerPub, syncType, req.newSyncType)
 
+	// If our gossip filter was overridden, the transition doesn't change
+	// which updates we'd like to receive from the remote peer.
+	if g.localGossipFilter != nil {
+		g.setSyncType(req.newSyncType)
+		return nil
+	}
+
 	var (
 		firstTimestamp time.Time
 		timestampRange uint32
@@ -1500,6 +1550,81 @@ func (g *GossipSyncer) handleSyncTransition(req *syncTransitionReq) error {
 	return nil
 }
 
+// setLocalGossipFilter sends a request to the gossip syncer to send the given
+// gossip filter to the remote peer in place of the one determined by its sync
+// type. A nil filter restores the gossip filter of the current sync type.
+//
+// NOTE: This can only be done once the gossip syncer has reached its final
+// chansSynced state.
+func (g *GossipSyncer) setLocalGossipFilter(filter *GossipFilter) error {
+	errChan := make(chan error, 1)
+	select {
+	case g.localGossipFilterReqs <- &localGossipFilterReq{
+		filter:  filter,
+		errChan: errChan,
+	}:
+	case <-time.After(syncTransitionTimeout):
+		return ErrSyncTransitionTimeout
+	case <-g.quit:
+		return ErrGossipSyncerExiting
+	}
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-g.quit:
+		return ErrGossipSyncerExiting
+	}
+}
+
+// handleLocalGossipFilter handles a request to override our gossip filter.
+func (g *GossipSyncer) handleLocalGossipFilter(
+	req *localGossipFilterReq) error {
+
+	g.localGossipFilter = req.filter
+	g.localGossipFilterSent = false
+
+	if req.filter != nil {
+		return g.sendLocalGossipFilter(req.filter)
+	}
+
+	// The override was removed, so we'll go back to the update horizon
+	// of our current sync type.
+	log.Debugf("GossipSyncer(%x): removing local gossip filter",
+		g.cfg.peerPub)
+
+	firstTimestamp, timestampRange := zeroTimestamp, uint32(0)
+	if g.SyncType().IsActiveSync() {
+		firstTimestamp = time.Now()
+		timestampRange = math.MaxUint32
+	}
+
+	err := g.sendGossipTimestampRange(firstTimestamp, timestampRange)
+	if err != nil {
+		return fmt.Errorf("unable to send local update horizon: %v",
+			err)
+	}
+
+	return nil
+}
+
+// sendLocalGossipFilter sends the given local gossip filter to the remote
+// peer.
+func (g *GossipSyncer) sendLocalGossipFilter(filter *GossipFilter) error {
+	log.Debugf("GossipSyncer(%x): sending local gossip filter %v",
+		g.cfg.peerPub, filter.Mode)
+
+	firstTimestamp, timestampRange := filter.timestampRange()
+	err := g.sendGossipTimestampRange(firstTimestamp, timestampRange)
+	if err != nil {
+		return fmt.Errorf("unable to send local gossip filter: %v", err)
+	}
+
+	g.localGossipFilterSent = true
+
+	return nil
+}
+
 // setSyncType sets the gossip syncer's sync type to the given type.
 func (g *GossipSyncer) setSyncType(syncType SyncerType) {
 	atomic.StoreUint32(&g.syncType, uint32(syncType))
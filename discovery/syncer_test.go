@@ -2223,6 +2223,76 @@ func TestGossipSyncerSyncTransitions(t *testing.T) {
 	}
 }
 
+// TestGossipSyncerLocalGossipFilter tests that a gossip syncer sends the local
+// gossip filter it is given in place of the update horizon of its sync type.
+func TestGossipSyncerLocalGossipFilter(t *testing.T) {
+	t.Parallel()
+
+	msgChan, syncer, _ := newTestSyncer(
+		lnwire.ShortChannelID{BlockHeight: latestKnownHeight},
+		defaultEncoding, defaultChunkSize,
+	)
+	syncer.setSyncType(ActiveSync)
+	syncer.setSyncState(chansSynced)
+
+	syncer.Start()
+	defer syncer.Stop()
+
+	nextHorizon := func() *lnwire.GossipTimestampRange {
+		t.Helper()
+
+		select {
+		case msgs := <-msgChan:
+			require.Len(t, msgs, 1)
+			horizon, ok := msgs[0].(*lnwire.GossipTimestampRange)
+			require.True(t, ok)
+
+			return horizon
+
+		case <-time.After(time.Second):
+			t.Fatalf("expected to send a " +
+				"lnwire.GossipTimestampRange message")
+		}
+
+		return nil
+	}
+
+	// As an active syncer, we'll first ask for all new updates.
+	horizon := nextHorizon()
+	require.EqualValues(t, math.MaxUint32, horizon.TimestampRange)
+
+	// Overriding the gossip filter should send the custom range.
+	err := syncer.setLocalGossipFilter(&GossipFilter{
+		Mode:           GossipFilterCustom,
+		FirstTimestamp: time.Unix(1000, 0),
+		TimestampRange: 500,
+	})
+	require.NoError(t, err)
+
+	horizon = nextHorizon()
+	require.EqualValues(t, 1000, horizon.FirstTimestamp)
+	require.EqualValues(t, 500, horizon.TimestampRange)
+
+	// A sync transition shouldn't change the filter while it's
+	// overridden.
+	require.NoError(t, syncer.ProcessSyncTransition(PassiveSync))
+	require.Equal(t, PassiveSync, syncer.SyncType())
+
+	select {
+	case msgs := <-msgChan:
+		t.Fatalf("unexpected messages sent: %v", spew.Sdump(msgs))
+	default:
+	}
+
+	// Once the override is removed, we should go back to the update
+	// horizon of our new sync type.
+	require.NoError(t, syncer.setLocalGossipFilter(nil))
+
+	horizon = nextHorizon()
+	require.Equal(t, uint32(zeroTimestamp.Unix()), horizon.FirstTimestamp)
+	require.Zero(t, horizon.TimestampRange)
+}
+
 // TestGossipSyncerHistoricalSync tests that a gossip syncer can perform a
 // historical sync with the remote peer.
 func TestGossipSyncerHistoricalSync(t *testing.T) {
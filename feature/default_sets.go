@@ -83,4 +83,10 @@ var defaultSetDesc = setDesc{
 		SetInit:    {}, // I
 		SetNodeAnn: {}, // N
 	},
+	lnwire.NoiseIKOptional: {
+		SetInit: {}, // I
+	},
+	lnwire.KeyRotationOptional: {
+		SetInit: {}, // I
+	},
 }
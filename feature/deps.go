@@ -147,3 +147,11 @@ func mapToOptional(bit lnwire.FeatureBit) lnwire.FeatureBit {
 	}
 	return bit
 }
+
+// mapToRequired returns the required variant of a given feature bit pair.
+func mapToRequired(bit lnwire.FeatureBit) lnwire.FeatureBit {
+	if !bit.IsRequired() {
+		bit ^= 0x01
+	}
+	return bit
+}
@@ -1,7 +1,9 @@
 package feature
 
 import (
+	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/lightningnetwork/lnd/lnwire"
 )
@@ -44,14 +46,45 @@ type Config struct {
 	// NoAnySegwit unsets any bits that signal support for using other
 	// segwit witness versions for co-op closes.
 	NoAnySegwit bool
+
+	// NoNoiseIK unsets any bits that signal support for the Noise_IK
+	// brontide handshake.
+	NoNoiseIK bool
+}
+
+// ErrFeatureNotDynamic is returned when a caller attempts to toggle a feature
+// bit at runtime that can only be changed through the daemon's configuration.
+var ErrFeatureNotDynamic = errors.New("feature bit cannot be toggled at " +
+	"runtime")
+
+// dynamicFeatures is the set of optional feature bits that may be enabled or
+// disabled while the daemon is running. The behavior tied to each of these
+// features is either negotiated solely through the feature vectors exchanged
+// with a peer, or derived from our own feature vectors whenever it's needed,
+// so changing them only requires the peer to re-receive our Init message. The
+// funding manager for example looks up the wumbo bit each time it checks the
+// size of an incoming channel. Features such as zero-conf channels and scid
+// aliases aren't dynamic, as parts of their behavior are derived from the
+// configuration at startup.
+var dynamicFeatures = featureSet{
+	lnwire.ScriptEnforcedLeaseOptional: {},
+	lnwire.ShutdownAnySegwitOptional:   {},
+	lnwire.WumboChannelsOptional:       {},
 }
 
 // Manager is responsible for generating feature vectors for different requested
 // feature sets.
 type Manager struct {
-	// fsets is a static map of feature set to raw feature vectors. Requests
-	// are fulfilled by cloning these internal feature vectors.
+	// desc is the set descriptor the manager was created with. It is used
+	// to determine which sets a feature bit is added to when it is enabled
+	// at runtime.
+	desc setDesc
+
+	// fsets is a map of feature set to raw feature vectors. Requests are
+	// fulfilled by cloning these internal feature vectors.
 	fsets map[Set]*lnwire.RawFeatureVector
+
+	mu sync.RWMutex
 }
 
 // NewManager creates a new feature Manager, applying any custom modifications
@@ -150,6 +183,10 @@ func newManager(cfg Config, desc setDesc) (*Manager, error) {
 			raw.Unset(lnwire.ShutdownAnySegwitOptional)
 			raw.Unset(lnwire.ShutdownAnySegwitRequired)
 		}
+		if cfg.NoNoiseIK {
+			raw.Unset(lnwire.NoiseIKOptional)
+			raw.Unset(lnwire.NoiseIKRequired)
+		}
 
 		// Ensure that all of our feature sets properly set any
 		// dependent features.
@@ -162,6 +199,7 @@ func newManager(cfg Config, desc setDesc) (*Manager, error) {
 	}
 
 	return &Manager{
+		desc:  desc,
 		fsets: fsets,
 	}, nil
 }
@@ -169,6 +207,9 @@ func newManager(cfg Config, desc setDesc) (*Manager, error) {
 // GetRaw returns a raw feature vector for the passed set. If no set is known,
 // an empty raw feature vector is returned.
 func (m *Manager) GetRaw(set Set) *lnwire.RawFeatureVector {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	if fv, ok := m.fsets[set]; ok {
 		return fv.Clone()
 	}
@@ -178,9 +219,37 @@ func (m *Manager) GetRaw(set Set) *lnwire.RawFeatureVector {
 
 // SetRaw sets a new raw feature vector for the given set.
 func (m *Manager) SetRaw(set Set, raw *lnwire.RawFeatureVector) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.fsets[set] = raw
 }
 
+// Snapshot returns a copy of all feature sets, which can be handed to Restore
+// to revert any updates made in the meantime.
+func (m *Manager) Snapshot() map[Set]*lnwire.RawFeatureVector {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	snapshot := make(map[Set]*lnwire.RawFeatureVector, len(m.fsets))
+	for set, raw := range m.fsets {
+		snapshot[set] = raw.Clone()
+	}
+
+	return snapshot
+}
+
+// Restore replaces all feature sets with the ones of the given snapshot.
+func (m *Manager) Restore(snapshot map[Set]*lnwire.RawFeatureVector) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.fsets = make(map[Set]*lnwire.RawFeatureVector, len(snapshot))
+	for set, raw := range snapshot {
+		m.fsets[set] = raw.Clone()
+	}
+}
+
 // Get returns a feature vector for the passed set. If no set is known, an empty
 // feature vector is returned.
 func (m *Manager) Get(set Set) *lnwire.FeatureVector {
@@ -190,6 +259,9 @@ func (m *Manager) Get(set Set) *lnwire.FeatureVector {
 
 // ListSets returns a list of the feature sets that our node supports.
 func (m *Manager) ListSets() []Set {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	var sets []Set
 
 	for set := range m.fsets {
@@ -198,3 +270,66 @@ func (m *Manager) ListSets() []Set {
 
 	return sets
 }
+
+// IsDynamic returns true if the given optional feature bit can be toggled at
+// runtime using UpdateOptional.
+func IsDynamic(bit lnwire.FeatureBit) bool {
+	_, ok := dynamicFeatures[bit]
+	return ok
+}
+
+// UpdateOptional enables or disables the given optional feature bits in every
+// feature set they are advertised in by default. The updates are applied
+// atomically: if any of the bits can't be toggled at runtime, or the resulting
+// feature sets would be missing a dependency, none of the sets are modified.
+func (m *Manager) UpdateOptional(updates map[lnwire.FeatureBit]bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Work on copies of the current sets so that we can bail out without
+	// leaving the manager in a partially updated state.
+	newSets := make(map[Set]*lnwire.RawFeatureVector, len(m.fsets))
+	for set, raw := range m.fsets {
+		newSets[set] = raw.Clone()
+	}
+
+	for bit, enable := range updates {
+		if !IsDynamic(bit) {
+			return fmt.Errorf("%w: %v", ErrFeatureNotDynamic,
+				lnwire.Features[bit])
+		}
+
+		for set := range m.desc[bit] {
+			raw, ok := newSets[set]
+			if !ok {
+				raw = lnwire.NewRawFeatureVector()
+				newSets[set] = raw
+			}
+
+			if !enable {
+				raw.Unset(bit)
+				continue
+			}
+
+			// Enabling the optional bit while the required one is
+			// set would produce an invalid vector, so we'll treat
+			// the feature as already enabled in that case.
+			if raw.IsSet(mapToRequired(bit)) {
+				continue
+			}
+			raw.Set(bit)
+		}
+	}
+
+	for set, raw := range newSets {
+		fv := lnwire.NewFeatureVector(raw, lnwire.Features)
+		if err := ValidateDeps(fv); err != nil {
+			return fmt.Errorf("invalid feature set %v: %v", set,
+				err)
+		}
+	}
+
+	m.fsets = newSets
+
+	return nil
+}
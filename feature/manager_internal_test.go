@@ -135,3 +135,107 @@ func testManager(t *testing.T, test managerTest) {
 		assertSet(lnwire.StaticRemoteKeyOptional)
 	}
 }
+
+// TestManagerUpdateOptional asserts that dynamic feature bits can be toggled
+// at runtime, and that invalid updates leave the feature sets untouched.
+func TestManagerUpdateOptional(t *testing.T) {
+	t.Parallel()
+
+	m, err := newManager(Config{}, defaultSetDesc)
+	require.NoError(t, err)
+
+	// Disabling anysegwit should remove the bit from both the init and
+	// node announcement sets.
+	err = m.UpdateOptional(map[lnwire.FeatureBit]bool{
+		lnwire.ShutdownAnySegwitOptional: false,
+	})
+	require.NoError(t, err)
+	for _, set := range []Set{SetInit, SetNodeAnn} {
+		require.False(t, m.GetRaw(set).IsSet(
+			lnwire.ShutdownAnySegwitOptional,
+		))
+	}
+
+	// Re-enabling it should only add it to the sets it is advertised in
+	// by default.
+	err = m.UpdateOptional(map[lnwire.FeatureBit]bool{
+		lnwire.ShutdownAnySegwitOptional: true,
+	})
+	require.NoError(t, err)
+	require.True(t, m.GetRaw(SetInit).IsSet(
+		lnwire.ShutdownAnySegwitOptional,
+	))
+	require.True(t, m.GetRaw(SetNodeAnn).IsSet(
+		lnwire.ShutdownAnySegwitOptional,
+	))
+	require.False(t, m.GetRaw(SetInvoice).IsSet(
+		lnwire.ShutdownAnySegwitOptional,
+	))
+
+	// Wumbo channels can be enabled at runtime even if they were disabled
+	// at startup.
+	m, err = newManager(Config{NoWumbo: true}, defaultSetDesc)
+	require.NoError(t, err)
+
+	err = m.UpdateOptional(map[lnwire.FeatureBit]bool{
+		lnwire.WumboChannelsOptional: true,
+	})
+	require.NoError(t, err)
+	for _, set := range []Set{SetInit, SetNodeAnn} {
+		require.True(t, m.GetRaw(set).IsSet(
+			lnwire.WumboChannelsOptional,
+		))
+	}
+
+	// Features that aren't dynamic can't be toggled. This includes
+	// features whose behavior is partly derived from the startup config.
+	for _, bit := range []lnwire.FeatureBit{
+		lnwire.StaticRemoteKeyOptional,
+		lnwire.ScidAliasOptional,
+		lnwire.ZeroConfOptional,
+	} {
+		err = m.UpdateOptional(map[lnwire.FeatureBit]bool{
+			bit: false,
+		})
+		require.ErrorIs(t, err, ErrFeatureNotDynamic)
+	}
+
+	// Enabling script enforced leases without anchors breaks a
+	// dependency, so no set should be modified.
+	m, err = newManager(Config{NoAnchors: true}, defaultSetDesc)
+	require.NoError(t, err)
+
+	err = m.UpdateOptional(map[lnwire.FeatureBit]bool{
+		lnwire.ShutdownAnySegwitOptional:   false,
+		lnwire.ScriptEnforcedLeaseOptional: true,
+	})
+	require.Error(t, err)
+	require.False(t, m.GetRaw(SetInit).IsSet(
+		lnwire.ScriptEnforcedLeaseOptional,
+	))
+	require.True(t, m.GetRaw(SetInit).IsSet(
+		lnwire.ShutdownAnySegwitOptional,
+	))
+}
+
+// TestManagerSnapshotRestore asserts that restoring a snapshot reverts the
+// feature sets to the state they were in when the snapshot was taken.
+func TestManagerSnapshotRestore(t *testing.T) {
+	t.Parallel()
+
+	m, err := newManager(Config{}, defaultSetDesc)
+	require.NoError(t, err)
+
+	snapshot := m.Snapshot()
+	initSet := m.GetRaw(SetInit)
+
+	err = m.UpdateOptional(map[lnwire.FeatureBit]bool{
+		lnwire.WumboChannelsOptional: false,
+	})
+	require.NoError(t, err)
+	require.False(t, m.GetRaw(SetInit).IsSet(lnwire.WumboChannelsOptional))
+
+	m.Restore(snapshot)
+	require.Equal(t, initSet, m.GetRaw(SetInit))
+	require.Equal(t, snapshot, m.Snapshot())
+}
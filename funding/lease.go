@@ -0,0 +1,63 @@
+package funding
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// LeasePolicy is the policy under which we answer requests of channel
+// initiators to contribute funds to their channel for a lease fee, as
+// proposed by liquidity ads (option_will_fund). Only the lease rates are
+// negotiated, as contributing funds requires dual funded channel opens.
+type LeasePolicy struct {
+	// Rates are the lease rates we charge.
+	Rates lnwire.LeaseRates
+
+	// MinLeaseAmt is the smallest amount we're willing to lease.
+	MinLeaseAmt btcutil.Amount
+
+	// MaxLeaseAmt is the largest amount we're willing to lease.
+	MaxLeaseAmt btcutil.Amount
+}
+
+// QuoteLease returns the rates and the lease fee under which we'd contribute
+// the requested funds, or an error if the request doesn't match our policy.
+func (p *LeasePolicy) QuoteLease(req *lnwire.RequestFunds) (*lnwire.WillFund,
+	btcutil.Amount, error) {
+
+	switch {
+	case req.RequestedAmt < p.MinLeaseAmt:
+		return nil, 0, fmt.Errorf("requested lease amount %v below "+
+			"minimum of %v", req.RequestedAmt, p.MinLeaseAmt)
+
+	case req.RequestedAmt > p.MaxLeaseAmt:
+		return nil, 0, fmt.Errorf("requested lease amount %v above "+
+			"maximum of %v", req.RequestedAmt, p.MaxLeaseAmt)
+	}
+
+	willFund := &lnwire.WillFund{LeaseRates: p.Rates}
+
+	return willFund, p.Rates.LeaseFee(req.RequestedAmt), nil
+}
+
+// checkLeaseQuote checks the answer of the remote peer to our funding request,
+// and returns an error if it declined the request or quoted a lease fee above
+// the given maximum.
+func checkLeaseQuote(req *lnwire.RequestFunds, willFund *lnwire.WillFund,
+	maxLeaseFee btcutil.Amount) error {
+
+	if willFund == nil {
+		return fmt.Errorf("remote peer declined to lease %v",
+			req.RequestedAmt)
+	}
+
+	fee := willFund.LeaseFee(req.RequestedAmt)
+	if fee > maxLeaseFee {
+		return fmt.Errorf("quoted lease fee of %v for %v exceeds "+
+			"maximum of %v", fee, req.RequestedAmt, maxLeaseFee)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package funding
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLeasePolicyQuote asserts that funding requests are quoted according to
+// the lease policy.
+func TestLeasePolicyQuote(t *testing.T) {
+	t.Parallel()
+
+	policy := &LeasePolicy{
+		Rates: lnwire.LeaseRates{
+			FundingFeeBase:    1_000,
+			FundingFeeRate:    100,
+			ChannelFeeMaxBase: 1_000,
+			ChannelFeeMaxRate: 500,
+		},
+		MinLeaseAmt: 100_000,
+		MaxLeaseAmt: 1_000_000,
+	}
+
+	// A request within the policy's limits is charged the base fee plus
+	// 1% of the requested amount.
+	willFund, fee, err := policy.QuoteLease(&lnwire.RequestFunds{
+		RequestedAmt: 500_000,
+	})
+	require.NoError(t, err)
+	require.Equal(t, policy.Rates, willFund.LeaseRates)
+	require.Equal(t, btcutil.Amount(6_000), fee)
+
+	// Requests outside of the limits are rejected.
+	_, _, err = policy.QuoteLease(&lnwire.RequestFunds{
+		RequestedAmt: 99_999,
+	})
+	require.Error(t, err)
+
+	_, _, err = policy.QuoteLease(&lnwire.RequestFunds{
+		RequestedAmt: 1_000_001,
+	})
+	require.Error(t, err)
+}
+
+// TestCheckLeaseQuote asserts that the quote of a funding request is only
+// accepted if the lease fee doesn't exceed our maximum.
+func TestCheckLeaseQuote(t *testing.T) {
+	t.Parallel()
+
+	req := &lnwire.RequestFunds{RequestedAmt: 500_000}
+	willFund := &lnwire.WillFund{LeaseRates: lnwire.LeaseRates{
+		FundingFeeBase: 1_000,
+		FundingFeeRate: 100,
+	}}
+
+	testCases := []struct {
+		name        string
+		willFund    *lnwire.WillFund
+		maxLeaseFee btcutil.Amount
+		expErr      bool
+	}{
+		{
+			name:        "declined",
+			willFund:    nil,
+			maxLeaseFee: 10_000,
+			expErr:      true,
+		},
+		{
+			name:        "fee within maximum",
+			willFund:    willFund,
+			maxLeaseFee: 6_000,
+		},
+		{
+			name:        "fee above maximum",
+			willFund:    willFund,
+			maxLeaseFee: 5_999,
+			expErr:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkLeaseQuote(req, tc.willFund, tc.maxLeaseFee)
+			if tc.expErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
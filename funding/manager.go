@@ -144,6 +144,10 @@ type reservationWithCtx struct {
 	remoteMaxValue lnwire.MilliSatoshi
 	remoteMaxHtlcs uint16
 
+	// remoteChanReserve is the channel reserve we require the remote to
+	// keep.
+	remoteChanReserve btcutil.Amount
+
 	// maxLocalCsv is the maximum csv we will accept from the remote.
 	maxLocalCsv uint16
 
@@ -151,6 +155,14 @@ type reservationWithCtx struct {
 	// the channel.
 	channelType *lnwire.ChannelType
 
+	// requestFunds is our request for the remote to contribute funds to
+	// the channel, if we made one.
+	requestFunds *lnwire.RequestFunds
+
+	// maxLeaseFee is the largest lease fee we accept to be quoted for the
+	// requested funds.
+	maxLeaseFee btcutil.Amount
+
 	updateMtx   sync.RWMutex
 	lastUpdated time.Time
 
@@ -233,6 +245,16 @@ type InitFundingMsg struct {
 	// peer.
 	MaxLocalCsv uint16
 
+	// RemoteChanReserve is the channel reserve we require the remote peer
+	// to keep. If zero, the default reserve for the channel's capacity is
+	// used.
+	RemoteChanReserve btcutil.Amount
+
+	// ZeroReserve indicates that the remote peer shouldn't be required to
+	// keep a channel reserve at all. This is only allowed for zero reserve
+	// peers.
+	ZeroReserve bool
+
 	// ChanFunder is an optional channel funder that allows the caller to
 	// control exactly how the channel funding is carried out. If not
 	// specified, then the default chanfunding.WalletAssembler will be
@@ -249,6 +271,15 @@ type InitFundingMsg struct {
 	// support explicit channel type negotiation.
 	ChannelType *lnwire.ChannelType
 
+	// RequestFunds optionally asks the remote peer to quote the lease rates
+	// under which it would contribute funds to the channel.
+	RequestFunds *lnwire.RequestFunds
+
+	// MaxLeaseFee is the largest lease fee we accept to be quoted for the
+	// requested funds. The funding flow is canceled if the remote peer
+	// declines the request or quotes a higher fee.
+	MaxLeaseFee btcutil.Amount
+
 	// Updates is a channel which updates to the opening status of the channel
 	// are sent on.
 	Updates chan *lnrpc.OpenStatusUpdate
@@ -287,9 +318,11 @@ func newSerializedKey(pubKey *btcec.PublicKey) serializedPubKey {
 // within the configuration MUST be non-nil for the FundingManager to carry out
 // its duties.
 type Config struct {
-	// NoWumboChans indicates if we're to reject all incoming wumbo channel
-	// requests, and also reject all outgoing wumbo channel requests.
-	NoWumboChans bool
+	// NoWumboChans returns true if we're to reject all incoming wumbo
+	// channel requests, and also reject all outgoing wumbo channel
+	// requests. It's queried each time a channel is opened, as wumbo
+	// channels can be enabled or disabled while the daemon is running.
+	NoWumboChans func() bool
 
 	// IDKey is the PublicKey that is used to identify this node within the
 	// Lightning Network.
@@ -388,6 +421,16 @@ type Config struct {
 	// to at all times.
 	RequiredRemoteChanReserve func(capacity, dustLimit btcutil.Amount) btcutil.Amount
 
+	// IsZeroReservePeer returns true if channels without a channel
+	// reserve may be negotiated with the given peer. Without a reserve, a
+	// party can broadcast a revoked commitment without risking any of its
+	// funds, so this should only be allowed for trusted peers.
+	IsZeroReservePeer func(peer *btcec.PublicKey) bool
+
+	// PeerPolicy returns the limits of the channels with the given peer.
+	// If nil, only the global limits apply.
+	PeerPolicy func(peer *btcec.PublicKey) PeerPolicy
+
 	// RequiredRemoteMaxValue is a function closure that, given the channel
 	// capacity, returns the amount of MilliSatoshis that our remote peer
 	// can have in total outstanding HTLCs with us.
@@ -424,9 +467,11 @@ type Config struct {
 	// due to fees.
 	MinChanSize btcutil.Amount
 
-	// MaxChanSize is the largest channel size that we'll accept as an
-	// inbound channel. We have such a parameter, so that you may decide how
-	// WUMBO you would like your channel.
+	// MaxChanSize is the largest channel size that we'll open or accept.
+	// We have such a parameter, so that you may decide how WUMBO you would
+	// like your channel. It may be overridden for single peers by their
+	// PeerPolicy. While wumbo channels are disabled, the limit is capped at
+	// MaxBtcFundingAmount.
 	MaxChanSize btcutil.Amount
 
 	// MaxPendingChannels is the maximum number of pending channels we
@@ -458,6 +503,15 @@ type Config struct {
 	// is enabled.
 	EnableUpfrontShutdown bool
 
+	// UpfrontShutdownScript is the upfront shutdown script we set for
+	// inbound channels with peers that support the option, unless the
+	// channel acceptor provided one.
+	UpfrontShutdownScript lnwire.DeliveryAddress
+
+	// RequireUpfrontShutdown specifies whether inbound channels from peers
+	// that don't support upfront shutdown scripts are rejected.
+	RequireUpfrontShutdown bool
+
 	// RegisteredChains keeps track of all chains that have been registered
 	// with the daemon.
 	RegisteredChains *chainreg.ChainRegistry
@@ -474,6 +528,10 @@ type Config struct {
 	// AliasManager is an implementation of the aliasHandler interface that
 	// abstracts away the handling of many alias functions.
 	AliasManager aliasHandler
+
+	// LeasePolicy is the policy under which we answer funding requests of
+	// channel initiators. If nil, funding requests are ignored.
+	LeasePolicy *LeasePolicy
 }
 
 // Manager acts as an orchestrator/bridge between the wallet's
@@ -502,6 +560,13 @@ type Manager struct {
 	nonceMtx    sync.RWMutex
 	chanIDNonce uint64
 
+	// routingPolicy is the default routing policy used when initially
+	// announcing channels. It starts out as the DefaultRoutingPolicy of
+	// the config, but can be replaced while the funding manager is
+	// running.
+	policyMtx     sync.RWMutex
+	routingPolicy htlcswitch.ForwardingPolicy
+
 	// activeReservations is a map which houses the state of all pending
 	// funding workflows.
 	activeReservations map[serializedPubKey]pendingChannels
@@ -584,6 +649,7 @@ func NewFundingManager(cfg Config) (*Manager, error) {
 	return &Manager{
 		cfg:                         &cfg,
 		chanIDKey:                   cfg.TempChanIDSeed,
+		routingPolicy:               cfg.DefaultRoutingPolicy,
 		activeReservations:          make(map[serializedPubKey]pendingChannels),
 		signedReservations:          make(map[lnwire.ChannelID][32]byte),
 		newChanBarriers:             make(map[lnwire.ChannelID]chan struct{}),
@@ -595,6 +661,27 @@ func NewFundingManager(cfg Config) (*Manager, error) {
 	}, nil
 }
 
+// SetDefaultRoutingPolicy replaces the default routing policy that is used
+// when initially announcing channels. It only affects channels whose
+// announcement is created afterwards.
+//
+// NOTE: This method is safe for concurrent use.
+func (f *Manager) SetDefaultRoutingPolicy(policy htlcswitch.ForwardingPolicy) {
+	f.policyMtx.Lock()
+	defer f.policyMtx.Unlock()
+
+	f.routingPolicy = policy
+}
+
+// defaultRoutingPolicy returns the default routing policy that is used when
+// initially announcing channels.
+func (f *Manager) defaultRoutingPolicy() htlcswitch.ForwardingPolicy {
+	f.policyMtx.RLock()
+	defer f.policyMtx.RUnlock()
+
+	return f.routingPolicy
+}
+
 // Start launches all helper goroutines required for handling requests sent
 // to the funding manager.
 func (f *Manager) Start() error {
@@ -1205,6 +1292,23 @@ func (f *Manager) ProcessFundingMsg(msg lnwire.Message, peer lnpeer.Peer) {
 	}
 }
 
+// maxChanSize returns the largest channel size we'll currently open with, or
+// accept from, the given peer. The policy of the peer may override the
+// configured limit. As wumbo channels may be toggled at runtime, the limit is
+// capped at the non-wumbo soft-limit whenever they're disabled.
+func (f *Manager) maxChanSize(peer *btcec.PublicKey) btcutil.Amount {
+	maxChanSize := f.cfg.MaxChanSize
+	if peerMax := f.peerPolicy(peer).MaxChanSize; peerMax != 0 {
+		maxChanSize = peerMax
+	}
+
+	if f.cfg.NoWumboChans() && maxChanSize > MaxBtcFundingAmount {
+		return MaxBtcFundingAmount
+	}
+
+	return maxChanSize
+}
+
 // handleFundingOpen creates an initial 'ChannelReservation' within the wallet,
 // then responds to the source peer with an accept channel message progressing
 // the funding workflow.
@@ -1285,12 +1389,12 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		return
 	}
 
-	// Ensure that the remote party respects our maximum channel size.
-	if amt > f.cfg.MaxChanSize {
-		f.failFundingFlow(
-			peer, msg.PendingChannelID,
-			lnwallet.ErrChanTooLarge(amt, f.cfg.MaxChanSize),
-		)
+	// The channel must respect our maximum channel size and the exposure
+	// limit we've configured for this peer.
+	if err := f.checkPeerPolicy(peerPubKey, amt); err != nil {
+		log.Warnf("Rejecting channel from peer %x: %v",
+			peerPubKey.SerializeCompressed(), err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
 		return
 	}
 
@@ -1332,6 +1436,24 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		return
 	}
 
+	// If our shutdown policy requires an upfront shutdown script for this
+	// channel, or our channel acceptor provided one, we reject the channel
+	// right away if the peer doesn't support the option.
+	requireShutdown := f.cfg.RequireUpfrontShutdown ||
+		acceptorResp.RequireUpfrontShutdown
+	remoteUpfrontShutdown := peer.RemoteFeatures().HasFeature(
+		lnwire.UpfrontShutdownScriptOptional,
+	)
+	if !remoteUpfrontShutdown &&
+		(requireShutdown || len(acceptorResp.UpfrontShutdown) != 0) {
+
+		f.failFundingFlow(
+			peer, msg.PendingChannelID,
+			lnwallet.ErrUpfrontShutdownRequired(),
+		)
+		return
+	}
+
 	log.Infof("Recv'd fundingRequest(amt=%v, push=%v, delay=%v, "+
 		"pendingId=%x) from peer(%x)", amt, msg.PushAmount,
 		msg.CsvDelay, msg.PendingChannelID,
@@ -1445,6 +1567,7 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		ZeroConf:         zeroConf,
 		OptionScidAlias:  scid,
 		ScidAliasFeature: scidFeatureVal,
+		AllowZeroReserve: f.zeroReserveAllowed(peer.IdentityKey()),
 	}
 
 	reservation, err := f.cfg.Wallet.InitChannelReservation(req)
@@ -1484,6 +1607,9 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		numConfsReq = 0
 	}
 
+	// Wumbo channels may require a deeper confirmation with this peer.
+	numConfsReq = f.requiredConfs(peerPubKey, amt, numConfsReq, zeroConf)
+
 	reservation.SetNumConfsRequired(numConfsReq)
 
 	// We'll also validate and apply all the constraints the initiating
@@ -1506,12 +1632,17 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 	}
 
 	// Check whether the peer supports upfront shutdown, and get a new wallet
-	// address if our node is configured to set shutdown addresses by default.
-	// We use the upfront shutdown script provided by our channel acceptor
-	// (if any) in lieu of user input.
+	// address if our node is configured to set shutdown addresses by default
+	// or requires one for this channel. We use the upfront shutdown script
+	// provided by our channel acceptor (if any) in lieu of user input, and
+	// fall back to our statically configured script otherwise.
+	upfrontShutdown := acceptorResp.UpfrontShutdown
+	if len(upfrontShutdown) == 0 && remoteUpfrontShutdown {
+		upfrontShutdown = f.cfg.UpfrontShutdownScript
+	}
 	shutdown, err := getUpfrontShutdownScript(
-		f.cfg.EnableUpfrontShutdown, peer, acceptorResp.UpfrontShutdown,
-		f.selectShutdownScript,
+		f.cfg.EnableUpfrontShutdown || requireShutdown, peer,
+		upfrontShutdown, f.selectShutdownScript,
 	)
 	if err != nil {
 		f.failFundingFlow(
@@ -1573,6 +1704,17 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		chanReserve = acceptorResp.Reserve
 	}
 
+	// If a zero reserve peer doesn't require us to keep a reserve, we
+	// won't require one from them either.
+	if req.AllowZeroReserve && msg.ChannelReserve == 0 {
+		log.Warnf("Negotiating channel without reserve with zero "+
+			"reserve peer %x for pending_id(%x)",
+			peer.IdentityKey().SerializeCompressed(),
+			msg.PendingChannelID)
+
+		chanReserve = 0
+	}
+
 	remoteMaxValue := f.cfg.RequiredRemoteMaxValue(amt)
 	if acceptorResp.InFlightTotal != 0 {
 		remoteMaxValue = acceptorResp.InFlightTotal
@@ -1598,16 +1740,17 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		f.activeReservations[peerIDKey] = make(pendingChannels)
 	}
 	resCtx := &reservationWithCtx{
-		reservation:    reservation,
-		chanAmt:        amt,
-		remoteCsvDelay: remoteCsvDelay,
-		remoteMinHtlc:  minHtlc,
-		remoteMaxValue: remoteMaxValue,
-		remoteMaxHtlcs: maxHtlcs,
-		maxLocalCsv:    f.cfg.MaxLocalCSVDelay,
-		channelType:    msg.ChannelType,
-		err:            make(chan error, 1),
-		peer:           peer,
+		reservation:       reservation,
+		chanAmt:           amt,
+		remoteCsvDelay:    remoteCsvDelay,
+		remoteMinHtlc:     minHtlc,
+		remoteMaxValue:    remoteMaxValue,
+		remoteMaxHtlcs:    maxHtlcs,
+		remoteChanReserve: chanReserve,
+		maxLocalCsv:       f.cfg.MaxLocalCSVDelay,
+		channelType:       msg.ChannelType,
+		err:               make(chan error, 1),
+		peer:              peer,
 	}
 	f.activeReservations[peerIDKey][msg.PendingChannelID] = resCtx
 	f.resMtx.Unlock()
@@ -1659,6 +1802,13 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 	log.Debugf("Remote party accepted commitment constraints: %v",
 		spew.Sdump(remoteContribution.ChannelConfig.ChannelConstraints))
 
+	// If the initiator asked us to contribute funds to the channel, we'll
+	// answer with the rates of our lease policy.
+	var willFund *lnwire.WillFund
+	if msg.RequestFunds != nil {
+		willFund = f.quoteLease(peerPubKey, msg)
+	}
+
 	// With the initiator's contribution recorded, respond with our
 	// contribution in the next message of the workflow.
 	ourContribution := reservation.OurContribution()
@@ -1680,6 +1830,7 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		UpfrontShutdownScript: ourContribution.UpfrontShutdown,
 		ChannelType:           chanTypeFeatureBits,
 		LeaseExpiry:           msg.LeaseExpiry,
+		WillFund:              willFund,
 	}
 
 	if err := peer.SendMessage(true, &fundingAccept); err != nil {
@@ -1689,6 +1840,39 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 	}
 }
 
+// quoteLease evaluates a funding request of a channel initiator against our
+// lease policy, and returns the rates to answer it with. Nil is returned if
+// we don't want to lease the requested funds.
+//
+// NOTE: As only single funded channels are supported, the requested funds are
+// never added to the channel being opened. The rates are only a quote the
+// initiator can use to decide whether to lease liquidity from us.
+func (f *Manager) quoteLease(peerKey *btcec.PublicKey,
+	msg *lnwire.OpenChannel) *lnwire.WillFund {
+
+	if f.cfg.LeasePolicy == nil {
+		log.Debugf("Ignoring funding request for pending_id(%x): no "+
+			"lease policy", msg.PendingChannelID)
+
+		return nil
+	}
+
+	willFund, fee, err := f.cfg.LeasePolicy.QuoteLease(msg.RequestFunds)
+	if err != nil {
+		log.Infof("Rejecting funding request of peer %x for "+
+			"pending_id(%x): %v", peerKey.SerializeCompressed(),
+			msg.PendingChannelID, err)
+
+		return nil
+	}
+
+	log.Infof("Quoting lease fee of %v for %v requested by peer %x for "+
+		"pending_id(%x)", fee, msg.RequestFunds.RequestedAmt,
+		peerKey.SerializeCompressed(), msg.PendingChannelID)
+
+	return willFund
+}
+
 // handleFundingAccept processes a response to the workflow initiation sent by
 // the remote peer. This message then queues a message with the funding
 // outpoint, and a commitment signature to the remote peer.
@@ -1711,6 +1895,23 @@ func (f *Manager) handleFundingAccept(peer lnpeer.Peer,
 	log.Infof("Recv'd fundingResponse for pending_id(%x)",
 		pendingChanID[:])
 
+	// If we asked the peer to contribute funds, make sure it quoted a
+	// lease fee we're willing to pay.
+	if resCtx.requestFunds != nil {
+		err := checkLeaseQuote(
+			resCtx.requestFunds, msg.WillFund, resCtx.maxLeaseFee,
+		)
+		if err != nil {
+			f.failFundingFlow(peer, msg.PendingChannelID, err)
+			return
+		}
+
+		log.Infof("Peer %x quoted lease fee of %v for pending_id(%x): "+
+			"%+v", peerKey.SerializeCompressed(),
+			msg.WillFund.LeaseFee(resCtx.requestFunds.RequestedAmt),
+			pendingChanID[:], msg.WillFund.LeaseRates)
+	}
+
 	// Perform some basic validation of any custom TLV records included.
 	//
 	// TODO: Return errors as funding.Error to give context to remote peer?
@@ -1809,7 +2010,14 @@ func (f *Manager) handleFundingAccept(peer lnpeer.Peer,
 	// We'll also specify the responder's preference for the number of
 	// required confirmations, and also the set of channel constraints
 	// they've specified for commitment states we can create.
-	resCtx.reservation.SetNumConfsRequired(uint16(msg.MinAcceptDepth))
+	//
+	// As the initiator, we won't consider a wumbo channel open before the
+	// depth our policy for this peer requires either.
+	numConfs := f.requiredConfs(
+		peerKey, resCtx.chanAmt, uint16(msg.MinAcceptDepth),
+		resCtx.reservation.IsZeroConf(),
+	)
+	resCtx.reservation.SetNumConfsRequired(numConfs)
 	channelConstraints := &channeldb.ChannelConstraints{
 		DustLimit:        msg.DustLimit,
 		ChanReserve:      msg.ChannelReserve,
@@ -1827,13 +2035,10 @@ func (f *Manager) handleFundingAccept(peer lnpeer.Peer,
 		return
 	}
 
-	// As they've accepted our channel constraints, we'll regenerate them
-	// here so we can properly commit their accepted constraints to the
-	// reservation. Also make sure that we re-generate the ChannelReserve
-	// with our dust limit or we can get stuck channels.
-	chanReserve := f.cfg.RequiredRemoteChanReserve(
-		resCtx.chanAmt, resCtx.reservation.OurContribution().DustLimit,
-	)
+	// As they've accepted our channel constraints, we'll commit the
+	// ChannelReserve we required, which was generated with our dust limit
+	// to avoid stuck channels, to the reservation.
+	chanReserve := resCtx.remoteChanReserve
 
 	// The remote node has responded with their portion of the channel
 	// contribution. At this point, we can process their contribution which
@@ -2319,6 +2524,23 @@ func (f *Manager) handleFundingSigned(peer lnpeer.Peer,
 		}
 	}
 
+	// If the channel shares its funding transaction with other channels
+	// that were verified together, the transaction is only published once
+	// the last of them was signed by its peer. Publishing it any earlier
+	// could lock up the funds of the other channels in their funding
+	// outputs without a commitment transaction to recover them.
+	if batchTx, ok := resCtx.reservation.PsbtBatchSigned(); ok {
+		log.Infof("Broadcasting batch funding tx %v, all channels "+
+			"funded by it are signed", batchTx.TxHash())
+
+		label := labels.MakeLabel(labels.LabelTypeChannelOpen, nil)
+		err = f.cfg.PublishTransaction(batchTx, label)
+		if err != nil {
+			log.Errorf("Unable to broadcast batch funding tx %v: "+
+				"%v", batchTx.TxHash(), err)
+		}
+	}
+
 	// Now that we have a finalized reservation for this funding flow,
 	// we'll send the to be active channel to the ChainArbitrator so it can
 	// watch for any on-chain actions before the channel has fully
@@ -2939,8 +3161,9 @@ func (f *Manager) extractAnnounceParams(c *channeldb.OpenChannel) (
 
 	// We don't necessarily want to go as low as the remote party allows.
 	// Check it against our default forwarding policy.
-	if fwdMinHTLC < f.cfg.DefaultRoutingPolicy.MinHTLCOut {
-		fwdMinHTLC = f.cfg.DefaultRoutingPolicy.MinHTLCOut
+	defaultPolicy := f.defaultRoutingPolicy()
+	if fwdMinHTLC < defaultPolicy.MinHTLCOut {
+		fwdMinHTLC = defaultPolicy.MinHTLCOut
 	}
 
 	// We'll obtain the max HTLC value we can forward in our direction, as
@@ -3544,13 +3767,14 @@ func (f *Manager) newChanAnnouncement(localPubKey,
 
 	// We announce the channel with the default values. Some of
 	// these values can later be changed by crafting a new ChannelUpdate.
+	defaultPolicy := f.defaultRoutingPolicy()
 	chanUpdateAnn := &lnwire.ChannelUpdate{
 		ShortChannelID: shortChanID,
 		ChainHash:      chainHash,
 		Timestamp:      uint32(time.Now().Unix()),
 		MessageFlags:   msgFlags,
 		ChannelFlags:   chanFlags,
-		TimeLockDelta:  uint16(f.cfg.DefaultRoutingPolicy.TimeLockDelta),
+		TimeLockDelta:  uint16(defaultPolicy.TimeLockDelta),
 
 		// We use the HtlcMinimumMsat that the remote party required us
 		// to use, as our ChannelUpdate will be used to carry HTLCs
@@ -3558,8 +3782,8 @@ func (f *Manager) newChanAnnouncement(localPubKey,
 		HtlcMinimumMsat: fwdMinHTLC,
 		HtlcMaximumMsat: fwdMaxHTLC,
 
-		BaseFee: uint32(f.cfg.DefaultRoutingPolicy.BaseFee),
-		FeeRate: uint32(f.cfg.DefaultRoutingPolicy.FeeRate),
+		BaseFee: uint32(defaultPolicy.BaseFee),
+		FeeRate: uint32(defaultPolicy.FeeRate),
 	}
 
 	if ourPolicy != nil {
@@ -3783,6 +4007,30 @@ func getUpfrontShutdownScript(enableUpfrontShutdown bool, peer lnpeer.Peer,
 	return getScript(taprootOK)
 }
 
+// validateRemoteChanReserve checks that a custom channel reserve we require
+// the remote party to keep is at least the smallest dust limit and at most a
+// fifth of the channel capacity, which doesn't exceed our funding amount. Any
+// other reserve would be rejected once the channel constraints are committed.
+func validateRemoteChanReserve(reserve, localAmt btcutil.Amount) error {
+	minReserve := lnwallet.DustLimitForSize(input.UnknownWitnessSize)
+	if reserve < minReserve {
+		return lnwallet.ErrChanReserveTooSmall(reserve, minReserve)
+	}
+
+	maxReserve := localAmt / 5
+	if reserve > maxReserve {
+		return lnwallet.ErrChanReserveTooLarge(reserve, maxReserve)
+	}
+
+	return nil
+}
+
+// zeroReserveAllowed returns true if channels without a channel reserve may be
+// negotiated with the given peer.
+func (f *Manager) zeroReserveAllowed(peer *btcec.PublicKey) bool {
+	return f.cfg.IsZeroReservePeer != nil && f.cfg.IsZeroReservePeer(peer)
+}
+
 // handleInitFundingMsg creates a channel reservation within the daemon's
 // wallet, then sends a funding request to the remote peer kicking off the
 // funding workflow.
@@ -3903,6 +4151,30 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		commitFeePerKw = f.cfg.MaxAnchorsCommitFeeRate
 	}
 
+	// The commitment transaction must be able to enter the mempool on its
+	// own, so we never go below the current relay fee of our backend,
+	// which may have been raised above the anchor cap by a mempool spike.
+	relayFeePerKw := f.cfg.FeeEstimator.RelayFeePerKW()
+	if commitFeePerKw < relayFeePerKw {
+		log.Infof("Raising initial commit fee rate of %x from %v to "+
+			"relay fee rate of %v", chanID[:], commitFeePerKw,
+			relayFeePerKw)
+
+		commitFeePerKw = relayFeePerKw
+	}
+
+	// Unless the funding transaction is assembled externally, we won't
+	// construct one that can't be relayed.
+	if msg.ChanFunder == nil && msg.FundingFeePerKw != 0 &&
+		msg.FundingFeePerKw < relayFeePerKw {
+
+		err := fmt.Errorf("funding fee rate of %v is below the relay "+
+			"fee rate of %v", msg.FundingFeePerKw, relayFeePerKw)
+		log.Error(err)
+		msg.Err <- err
+		return
+	}
+
 	var scidFeatureVal bool
 	if hasFeatures(
 		msg.Peer.LocalFeatures(), msg.Peer.RemoteFeatures(),
@@ -3930,6 +4202,34 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		ZeroConf:         zeroConf,
 		OptionScidAlias:  scid,
 		ScidAliasFeature: scidFeatureVal,
+		AllowZeroReserve: f.zeroReserveAllowed(peerKey),
+	}
+
+	// The channel must respect our maximum channel size and the exposure
+	// limit we've configured for this peer.
+	if err := f.checkPeerPolicy(peerKey, localAmt); err != nil {
+		msg.Err <- err
+		return
+	}
+
+	// A channel without reserve can only be opened with a zero reserve
+	// peer.
+	if msg.ZeroReserve && !req.AllowZeroReserve {
+		msg.Err <- fmt.Errorf("peer %x is not a zero reserve peer",
+			peerKey.SerializeCompressed())
+		return
+	}
+
+	// A custom reserve is validated before any funds are reserved for the
+	// channel.
+	if msg.RemoteChanReserve != 0 {
+		err := validateRemoteChanReserve(
+			msg.RemoteChanReserve, localAmt,
+		)
+		if err != nil {
+			msg.Err <- err
+			return
+		}
 	}
 
 	reservation, err := f.cfg.Wallet.InitChannelReservation(req)
@@ -3983,6 +4283,32 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		maxHtlcs = f.cfg.RequiredRemoteMaxHTLCs(capacity)
 	}
 
+	// Once the reservation has been created, we'll determine the
+	// constraints we require for the remote party.
+	ourContribution := reservation.OurContribution()
+
+	// Fetch our dust limit which is part of the default channel
+	// constraints, and log it.
+	ourDustLimit := ourContribution.DustLimit
+
+	log.Infof("Dust limit for pendingID(%x): %v", chanID, ourDustLimit)
+
+	// Finally, we'll use the current value of the channels and our default
+	// policy to determine of required commitment constraints for the
+	// remote party.
+	chanReserve := f.cfg.RequiredRemoteChanReserve(capacity, ourDustLimit)
+	switch {
+	case msg.ZeroReserve:
+		log.Warnf("Opening channel without reserve with zero reserve "+
+			"peer %x for pending_id(%x)",
+			peerKey.SerializeCompressed(), chanID)
+
+		chanReserve = 0
+
+	case msg.RemoteChanReserve != 0:
+		chanReserve = msg.RemoteChanReserve
+	}
+
 	// If a pending channel map for this peer isn't already created, then
 	// we create one, ultimately allowing us to track this pending
 	// reservation within the target peer.
@@ -3993,17 +4319,20 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 	}
 
 	resCtx := &reservationWithCtx{
-		chanAmt:        capacity,
-		remoteCsvDelay: remoteCsvDelay,
-		remoteMinHtlc:  minHtlcIn,
-		remoteMaxValue: maxValue,
-		remoteMaxHtlcs: maxHtlcs,
-		maxLocalCsv:    maxCSV,
-		channelType:    msg.ChannelType,
-		reservation:    reservation,
-		peer:           msg.Peer,
-		updates:        msg.Updates,
-		err:            msg.Err,
+		chanAmt:           capacity,
+		remoteCsvDelay:    remoteCsvDelay,
+		remoteMinHtlc:     minHtlcIn,
+		remoteMaxValue:    maxValue,
+		remoteMaxHtlcs:    maxHtlcs,
+		remoteChanReserve: chanReserve,
+		maxLocalCsv:       maxCSV,
+		channelType:       msg.ChannelType,
+		requestFunds:      msg.RequestFunds,
+		maxLeaseFee:       msg.MaxLeaseFee,
+		reservation:       reservation,
+		peer:              msg.Peer,
+		updates:           msg.Updates,
+		err:               msg.Err,
 	}
 	f.activeReservations[peerIDKey][chanID] = resCtx
 	f.resMtx.Unlock()
@@ -4011,21 +4340,6 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 	// Update the timestamp once the InitFundingMsg has been handled.
 	defer resCtx.updateTimestamp()
 
-	// Once the reservation has been created, and indexed, queue a funding
-	// request to the remote peer, kicking off the funding workflow.
-	ourContribution := reservation.OurContribution()
-
-	// Fetch our dust limit which is part of the default channel
-	// constraints, and log it.
-	ourDustLimit := ourContribution.DustLimit
-
-	log.Infof("Dust limit for pendingID(%x): %v", chanID, ourDustLimit)
-
-	// Finally, we'll use the current value of the channels and our default
-	// policy to determine of required commitment constraints for the
-	// remote party.
-	chanReserve := f.cfg.RequiredRemoteChanReserve(capacity, ourDustLimit)
-
 	// When opening a script enforced channel lease, include the required
 	// expiry TLV record in our proposal.
 	var leaseExpiry *lnwire.LeaseExpiry
@@ -4059,6 +4373,7 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		UpfrontShutdownScript: shutdown,
 		ChannelType:           chanType,
 		LeaseExpiry:           leaseExpiry,
+		RequestFunds:          msg.RequestFunds,
 	}
 	if err := msg.Peer.SendMessage(true, &fundingOpen); err != nil {
 		e := fmt.Errorf("unable to send funding request message: %v",
@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -504,6 +505,7 @@ func createTestFundingManager(t *testing.T, privKey *btcec.PrivateKey,
 		},
 		ZombieSweeperInterval:         1 * time.Hour,
 		ReservationTimeout:            1 * time.Nanosecond,
+		NoWumboChans:                  func() bool { return false },
 		MaxChanSize:                   MaxBtcFundingAmount,
 		MaxLocalCSVDelay:              defaultMaxLocalCSVDelay,
 		MaxPendingChannels:            lncfg.DefaultMaxPendingChannels,
@@ -620,6 +622,7 @@ func recreateAliceFundingManager(t *testing.T, alice *testNode) {
 		},
 		ZombieSweeperInterval: oldCfg.ZombieSweeperInterval,
 		ReservationTimeout:    oldCfg.ReservationTimeout,
+		NoWumboChans:          oldCfg.NoWumboChans,
 		OpenChannelPredicate:  chainedAcceptor,
 		DeleteAliasEdge:       oldCfg.DeleteAliasEdge,
 		AliasManager:          oldCfg.AliasManager,
@@ -639,6 +642,21 @@ func recreateAliceFundingManager(t *testing.T, alice *testNode) {
 
 type cfgOption func(*Config)
 
+// allowWumbo lets the node open and accept channels up to the wumbo limit.
+func allowWumbo(cfg *Config) {
+	cfg.MaxChanSize = MaxBtcFundingAmountWumbo
+}
+
+// bobOnly applies the given option only to the config of Bob, so that Alice
+// may initiate channels that Bob rejects.
+func bobOnly(option cfgOption) cfgOption {
+	return func(cfg *Config) {
+		if cfg.IDKey.IsEqual(bobPubKey) {
+			option(cfg)
+		}
+	}
+}
+
 func setupFundingManagers(t *testing.T,
 	options ...cfgOption) (*testNode, *testNode) {
 
@@ -3174,6 +3192,147 @@ func TestFundingManagerRejectPush(t *testing.T) {
 	}
 }
 
+// TestFundingManagerZeroReserve checks that channels without a channel
+// reserve can only be negotiated with peers that are allowed to have one.
+func TestFundingManagerZeroReserve(t *testing.T) {
+	t.Parallel()
+
+	// Only allow zero reserve channels between Alice and Bob.
+	alice, bob := setupFundingManagers(
+		t, func(cfg *Config) {
+			cfg.IsZeroReservePeer = func(peer *btcec.PublicKey) bool {
+				return peer.IsEqual(alicePubKey) ||
+					peer.IsEqual(bobPubKey)
+			}
+		},
+	)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	// Create a funding request without a reserve for Bob and start the
+	// workflow.
+	updateChan := make(chan *lnrpc.OpenStatusUpdate)
+	errChan := make(chan error, 1)
+	initReq := &InitFundingMsg{
+		Peer:            bob,
+		TargetPubkey:    bob.privKey.PubKey(),
+		ChainHash:       *fundingNetParams.GenesisHash,
+		LocalFundingAmt: 500000,
+		ZeroReserve:     true,
+		Updates:         updateChan,
+		Err:             errChan,
+	}
+
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+
+	// Alice should have sent the OpenChannel message to Bob, without
+	// requiring him to keep a reserve.
+	var aliceMsg lnwire.Message
+	select {
+	case aliceMsg = <-alice.msgChan:
+	case err := <-initReq.Err:
+		t.Fatalf("error init funding workflow: %v", err)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not send OpenChannel message")
+	}
+
+	openChannelReq, ok := aliceMsg.(*lnwire.OpenChannel)
+	require.True(t, ok, "expected OpenChannel, got %T", aliceMsg)
+	require.Zero(t, openChannelReq.ChannelReserve)
+
+	// Bob should mirror the zero reserve in his AcceptChannel message.
+	bob.fundingMgr.ProcessFundingMsg(openChannelReq, alice)
+	acceptChannelResponse := assertFundingMsgSent(
+		t, bob.msgChan, "AcceptChannel",
+	).(*lnwire.AcceptChannel)
+	require.Zero(t, acceptChannelResponse.ChannelReserve)
+
+	// Alice should accept it and continue with the funding flow.
+	alice.fundingMgr.ProcessFundingMsg(acceptChannelResponse, bob)
+	assertFundingMsgSent(t, alice.msgChan, "FundingCreated")
+
+	chanID := openChannelReq.PendingChannelID
+	for _, node := range []struct {
+		mgr  *Manager
+		peer *btcec.PublicKey
+	}{
+		{alice.fundingMgr, bobPubKey},
+		{bob.fundingMgr, alicePubKey},
+	} {
+		resCtx, err := node.mgr.getReservationCtx(node.peer, chanID)
+		require.NoError(t, err)
+
+		reservation := resCtx.reservation
+		require.Zero(t, reservation.OurContribution().ChanReserve)
+		require.Zero(t, reservation.TheirContribution().ChanReserve)
+	}
+
+	// Without being allowed to, Alice can't open a channel without a
+	// reserve.
+	alice, bob = setupFundingManagers(t)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	initReq.Peer = bob
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+
+	select {
+	case err := <-initReq.Err:
+		require.ErrorContains(t, err, "not a zero reserve peer")
+	case <-time.After(time.Second * 5):
+		t.Fatalf("expected zero reserve channel to be rejected")
+	}
+}
+
+// TestFundingManagerInvalidRemoteChanReserve checks that a custom channel
+// reserve that can't be committed to is rejected before the funds of the
+// channel are reserved.
+func TestFundingManagerInvalidRemoteChanReserve(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupFundingManagers(t)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	const localAmt = 500000
+
+	testCases := []struct {
+		reserve btcutil.Amount
+		err     string
+	}{{
+		reserve: 1,
+		err:     "too small",
+	}, {
+		reserve: localAmt/5 + 1,
+		err:     "too large",
+	}}
+
+	for _, testCase := range testCases {
+		errChan := make(chan error, 1)
+		initReq := &InitFundingMsg{
+			Peer:              bob,
+			TargetPubkey:      bob.privKey.PubKey(),
+			ChainHash:         *fundingNetParams.GenesisHash,
+			LocalFundingAmt:   localAmt,
+			RemoteChanReserve: testCase.reserve,
+			Updates:           make(chan *lnrpc.OpenStatusUpdate),
+			Err:               errChan,
+		}
+
+		alice.fundingMgr.InitFundingWorkflow(initReq)
+
+		select {
+		case err := <-errChan:
+			require.ErrorContains(t, err, testCase.err)
+		case <-time.After(time.Second * 5):
+			t.Fatalf("expected reserve of %v to be rejected",
+				testCase.reserve)
+		}
+
+		// No reservation must have been made for the channel.
+		alice.fundingMgr.resMtx.RLock()
+		require.Empty(t, alice.fundingMgr.activeReservations)
+		alice.fundingMgr.resMtx.RUnlock()
+	}
+}
+
 // TestFundingManagerMaxConfs ensures that we don't accept a funding proposal
 // that proposes a MinAcceptDepth greater than the maximum number of
 // confirmations we're willing to accept.
@@ -3459,10 +3618,12 @@ func TestMaxChannelSizeConfig(t *testing.T) {
 	// channels but set --maxchansize explicitly lower than soft-limit.
 	// Verify that wumbo rejecting funding managers will respect --maxchansize
 	// below 16777215 satoshi (MaxBtcFundingAmount) limit.
-	alice, bob := setupFundingManagers(t, func(cfg *Config) {
-		cfg.NoWumboChans = true
-		cfg.MaxChanSize = MaxBtcFundingAmount - 1
-	})
+	alice, bob := setupFundingManagers(
+		t, allowWumbo, bobOnly(func(cfg *Config) {
+			cfg.NoWumboChans = func() bool { return true }
+			cfg.MaxChanSize = MaxBtcFundingAmount - 1
+		}),
+	)
 
 	// Attempt to create a channel above the limit
 	// imposed by --maxchansize, which should be rejected.
@@ -3490,10 +3651,12 @@ func TestMaxChannelSizeConfig(t *testing.T) {
 	// channels but set --maxchansize explicitly higher than soft-limit
 	// A --maxchansize greater than this limit should have no effect.
 	tearDownFundingManagers(t, alice, bob)
-	alice, bob = setupFundingManagers(t, func(cfg *Config) {
-		cfg.NoWumboChans = true
-		cfg.MaxChanSize = MaxBtcFundingAmount + 1
-	})
+	alice, bob = setupFundingManagers(
+		t, allowWumbo, bobOnly(func(cfg *Config) {
+			cfg.NoWumboChans = func() bool { return true }
+			cfg.MaxChanSize = MaxBtcFundingAmount + 1
+		}),
+	)
 
 	// Reset the Peer to the newly created one.
 	initReq.Peer = bob
@@ -3508,10 +3671,12 @@ func TestMaxChannelSizeConfig(t *testing.T) {
 	// Create the funding managers, this time allowing
 	// wumbo channels but setting --maxchansize explicitly.
 	tearDownFundingManagers(t, alice, bob)
-	alice, bob = setupFundingManagers(t, func(cfg *Config) {
-		cfg.NoWumboChans = false
-		cfg.MaxChanSize = btcutil.Amount(100000000)
-	})
+	alice, bob = setupFundingManagers(
+		t, allowWumbo, bobOnly(func(cfg *Config) {
+			cfg.NoWumboChans = func() bool { return false }
+			cfg.MaxChanSize = btcutil.Amount(100000000)
+		}),
+	)
 
 	// Reset the Peer to the newly created one.
 	initReq.Peer = bob
@@ -3526,6 +3691,24 @@ func TestMaxChannelSizeConfig(t *testing.T) {
 	openChanMsg = expectOpenChannelMsg(t, alice.msgChan)
 	bob.fundingMgr.ProcessFundingMsg(openChanMsg, alice)
 	assertErrorSent(t, bob.msgChan)
+
+	// The limit also applies to the channels we initiate, so Alice won't
+	// open a channel above her own --maxchansize.
+	tearDownFundingManagers(t, alice, bob)
+	alice, bob = setupFundingManagers(t, func(cfg *Config) {
+		cfg.MaxChanSize = btcutil.Amount(100000000)
+	})
+	defer tearDownFundingManagers(t, alice, bob)
+
+	initReq.Peer = bob
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+
+	select {
+	case err := <-initReq.Err:
+		require.ErrorContains(t, err, "exceeds maximum chan size")
+	case <-time.After(time.Second * 5):
+		t.Fatalf("expected channel above the size limit to be rejected")
+	}
 }
 
 // TestWumboChannelConfig tests that the funding manager will respect the wumbo
@@ -3533,11 +3716,13 @@ func TestMaxChannelSizeConfig(t *testing.T) {
 func TestWumboChannelConfig(t *testing.T) {
 	t.Parallel()
 
-	// First we'll create a set of funding managers that will reject wumbo
+	// First we'll create a set of funding managers where Bob rejects wumbo
 	// channels.
-	alice, bob := setupFundingManagers(t, func(cfg *Config) {
-		cfg.NoWumboChans = true
-	})
+	alice, bob := setupFundingManagers(
+		t, allowWumbo, bobOnly(func(cfg *Config) {
+			cfg.NoWumboChans = func() bool { return true }
+		}),
+	)
 
 	// If we attempt to initiate a new funding open request to Alice,
 	// that's below the wumbo channel mark, we should be able to start the
@@ -3575,10 +3760,12 @@ func TestWumboChannelConfig(t *testing.T) {
 	// Next, we'll re-create the funding managers, but this time allowing
 	// wumbo channels explicitly.
 	tearDownFundingManagers(t, alice, bob)
-	alice, bob = setupFundingManagers(t, func(cfg *Config) {
-		cfg.NoWumboChans = false
-		cfg.MaxChanSize = MaxBtcFundingAmountWumbo
-	})
+	alice, bob = setupFundingManagers(
+		t, allowWumbo, bobOnly(func(cfg *Config) {
+			cfg.NoWumboChans = func() bool { return false }
+			cfg.MaxChanSize = MaxBtcFundingAmountWumbo
+		}),
+	)
 
 	// Reset the Peer to the newly created one.
 	initReq.Peer = bob
@@ -3589,6 +3776,140 @@ func TestWumboChannelConfig(t *testing.T) {
 	openChanMsg = expectOpenChannelMsg(t, alice.msgChan)
 	bob.fundingMgr.ProcessFundingMsg(openChanMsg, alice)
 	assertFundingMsgSent(t, bob.msgChan, "AcceptChannel")
+
+	// Finally, we'll re-create the funding managers with the default wumbo
+	// limit, but disable wumbo channels through the feature bit that may
+	// be toggled at runtime.
+	tearDownFundingManagers(t, alice, bob)
+	noWumbo := uint32(1)
+	alice, bob = setupFundingManagers(
+		t, allowWumbo, bobOnly(func(cfg *Config) {
+			cfg.NoWumboChans = func() bool {
+				return atomic.LoadUint32(&noWumbo) == 1
+			}
+			cfg.MaxChanSize = MaxBtcFundingAmountWumbo
+		}),
+	)
+	initReq.Peer = bob
+
+	// As long as wumbo channels are disabled, the channel should be
+	// rejected.
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+	openChanMsg = expectOpenChannelMsg(t, alice.msgChan)
+	bob.fundingMgr.ProcessFundingMsg(openChanMsg, alice)
+	assertErrorSent(t, bob.msgChan)
+
+	// Once they're enabled, the same channel should be accepted without
+	// restarting the funding manager.
+	atomic.StoreUint32(&noWumbo, 0)
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+	openChanMsg = expectOpenChannelMsg(t, alice.msgChan)
+	bob.fundingMgr.ProcessFundingMsg(openChanMsg, alice)
+	assertFundingMsgSent(t, bob.msgChan, "AcceptChannel")
+}
+
+// TestFundingManagerPeerPolicy tests that the channel size and exposure limits
+// of a peer are enforced for both the channels we initiate and those we
+// accept, and that wumbo channels require the depth of the policy.
+func TestFundingManagerPeerPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := PeerPolicy{
+		MaxChanSize: 2 * MaxBtcFundingAmount,
+		MaxExposure: 3 * MaxBtcFundingAmount / 2,
+		WumboConfs:  10,
+	}
+
+	// Only Bob limits the channels with Alice.
+	alice, bob := setupFundingManagers(t, func(cfg *Config) {
+		cfg.MaxChanSize = MaxBtcFundingAmountWumbo
+		cfg.PeerPolicy = func(peer *btcec.PublicKey) PeerPolicy {
+			if peer.IsEqual(alicePubKey) {
+				return policy
+			}
+
+			return PeerPolicy{}
+		}
+	})
+	defer tearDownFundingManagers(t, alice, bob)
+
+	updateChan := make(chan *lnrpc.OpenStatusUpdate)
+	errChan := make(chan error, 1)
+	initReq := &InitFundingMsg{
+		Peer:            bob,
+		TargetPubkey:    bob.privKey.PubKey(),
+		ChainHash:       *fundingNetParams.GenesisHash,
+		LocalFundingAmt: policy.MaxChanSize + 1,
+		Updates:         updateChan,
+		Err:             errChan,
+	}
+
+	// A channel above the maximum size for Alice is rejected by Bob.
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+	openChanMsg := expectOpenChannelMsg(t, alice.msgChan)
+	bob.fundingMgr.ProcessFundingMsg(openChanMsg, alice)
+	assertErrorSent(t, bob.msgChan)
+
+	// A wumbo channel within the limits is accepted, but Bob requires the
+	// depth of his policy instead of the default one.
+	initReq.LocalFundingAmt = MaxBtcFundingAmount + 1
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+	openChanMsg = expectOpenChannelMsg(t, alice.msgChan)
+	bob.fundingMgr.ProcessFundingMsg(openChanMsg, alice)
+	acceptChannelResponse := assertFundingMsgSent(
+		t, bob.msgChan, "AcceptChannel",
+	).(*lnwire.AcceptChannel)
+	require.EqualValues(
+		t, policy.WumboConfs, acceptChannelResponse.MinAcceptDepth,
+	)
+
+	// Another wumbo channel would raise the exposure of Bob to Alice
+	// above the limit, as the pending one counts towards it.
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+	openChanMsg = expectOpenChannelMsg(t, alice.msgChan)
+	bob.fundingMgr.ProcessFundingMsg(openChanMsg, alice)
+	assertErrorSent(t, bob.msgChan)
+
+	// The limits also apply to the channels we initiate.
+	alice, bob = setupFundingManagers(t, func(cfg *Config) {
+		cfg.MaxChanSize = MaxBtcFundingAmountWumbo
+		cfg.PeerPolicy = func(*btcec.PublicKey) PeerPolicy {
+			return policy
+		}
+	})
+	defer tearDownFundingManagers(t, alice, bob)
+
+	initReq.Peer = bob
+	initReq.LocalFundingAmt = policy.MaxChanSize + 1
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+
+	select {
+	case err := <-initReq.Err:
+		require.ErrorContains(t, err, "exceeds maximum chan size")
+	case <-time.After(time.Second * 5):
+		t.Fatalf("expected channel above the size limit to be rejected")
+	}
+
+	// The maximum channel size of a peer overrides the global one, even
+	// if it is larger.
+	alice, bob = setupFundingManagers(
+		t, allowWumbo, bobOnly(func(cfg *Config) {
+			cfg.MaxChanSize = MaxBtcFundingAmount
+			cfg.PeerPolicy = func(*btcec.PublicKey) PeerPolicy {
+				return PeerPolicy{
+					MaxChanSize: MaxBtcFundingAmountWumbo,
+				}
+			}
+		}),
+	)
+	defer tearDownFundingManagers(t, alice, bob)
+
+	initReq.Peer = bob
+	initReq.LocalFundingAmt = MaxBtcFundingAmount + 1
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+	openChanMsg = expectOpenChannelMsg(t, alice.msgChan)
+	bob.fundingMgr.ProcessFundingMsg(openChanMsg, alice)
+	assertFundingMsgSent(t, bob.msgChan, "AcceptChannel")
 }
 
 // TestFundingManagerUpfrontShutdown asserts that we'll properly fail out if
@@ -3745,6 +4066,78 @@ func testUpfrontFailure(t *testing.T, pkscript []byte, expectErr bool) {
 	}
 }
 
+// TestFundingManagerRequireUpfrontShutdown checks that inbound channels are
+// rejected if an upfront shutdown script is required but not supported by the
+// peer, and that the configured script is used otherwise.
+func TestFundingManagerRequireUpfrontShutdown(t *testing.T) {
+	t.Parallel()
+
+	script := lnwire.DeliveryAddress("\x00\x14\x4b\xfe\x98\x3f\x16\xaa" +
+		"\xde\x1b\x1c\xb1\x54\x5a\xa5\xa4\x88\xd5\xe3\x68\xb5\xdc")
+
+	openChannel := func(supported bool) lnwire.Message {
+		alice, bob := setupFundingManagers(
+			t, func(cfg *Config) {
+				cfg.UpfrontShutdownScript = script
+				cfg.RequireUpfrontShutdown = true
+			},
+		)
+		defer tearDownFundingManagers(t, alice, bob)
+
+		if supported {
+			alice.remoteFeatures = []lnwire.FeatureBit{
+				lnwire.UpfrontShutdownScriptOptional,
+			}
+		}
+
+		initReq := &InitFundingMsg{
+			Peer:            bob,
+			TargetPubkey:    bob.privKey.PubKey(),
+			ChainHash:       *fundingNetParams.GenesisHash,
+			LocalFundingAmt: 500000,
+			Updates:         make(chan *lnrpc.OpenStatusUpdate),
+			Err:             make(chan error, 1),
+		}
+		alice.fundingMgr.InitFundingWorkflow(initReq)
+
+		var aliceMsg lnwire.Message
+		select {
+		case aliceMsg = <-alice.msgChan:
+		case err := <-initReq.Err:
+			t.Fatalf("error init funding workflow: %v", err)
+		case <-time.After(time.Second * 5):
+			t.Fatalf("alice did not send OpenChannel message")
+		}
+
+		openChannelReq, ok := aliceMsg.(*lnwire.OpenChannel)
+		require.True(t, ok, "expected OpenChannel, got %T", aliceMsg)
+
+		// Let Bob handle the OpenChannel message and return his
+		// response.
+		bob.fundingMgr.ProcessFundingMsg(openChannelReq, alice)
+
+		select {
+		case msg := <-bob.msgChan:
+			return msg
+		case <-time.After(time.Second * 5):
+			t.Fatalf("bob did not respond to OpenChannel")
+			return nil
+		}
+	}
+
+	// If Alice doesn't support upfront shutdown scripts, Bob rejects the
+	// channel.
+	errMsg, ok := openChannel(false).(*lnwire.Error)
+	require.True(t, ok, "expected Error")
+	require.Contains(t, errMsg.Error(), "upfront shutdown script required")
+
+	// Otherwise, Bob accepts the channel and sets his configured upfront
+	// shutdown script.
+	acceptMsg, ok := openChannel(true).(*lnwire.AcceptChannel)
+	require.True(t, ok, "expected AcceptChannel")
+	require.Equal(t, script, acceptMsg.UpfrontShutdownScript)
+}
+
 // TestFundingManagerZeroConf tests that the fundingmanager properly handles
 // the whole flow for zero-conf channels.
 func TestFundingManagerZeroConf(t *testing.T) {
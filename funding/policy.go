@@ -0,0 +1,119 @@
+package funding
+
+import (
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnwallet"
+)
+
+// PeerPolicy limits the channels we open with, or accept from, a specific
+// peer.
+type PeerPolicy struct {
+	// MaxChanSize is the largest channel that may be opened with the
+	// peer, in either direction. It overrides the global MaxChanSize if
+	// non-zero.
+	MaxChanSize btcutil.Amount
+
+	// MaxExposure is the largest total amount we may have in channels
+	// with the peer, including pending ones. The exposure of a channel is
+	// its capacity minus the reserve the peer has to keep, as the peer
+	// forfeits its reserve if it broadcasts a revoked state. Zero disables
+	// the limit.
+	MaxExposure btcutil.Amount
+
+	// WumboConfs is the minimum number of confirmations required before a
+	// wumbo channel with the peer is considered open, regardless of the
+	// depth the remote party asks for. Zero disables the requirement.
+	WumboConfs uint16
+}
+
+// peerPolicy returns the policy of the channels with the given peer.
+func (f *Manager) peerPolicy(peer *btcec.PublicKey) PeerPolicy {
+	if f.cfg.PeerPolicy == nil {
+		return PeerPolicy{}
+	}
+
+	return f.cfg.PeerPolicy(peer)
+}
+
+// peerExposure returns the total amount we have in open and pending channels
+// with the given peer, as well as in active reservations, excluding the
+// reserves the peer has to keep.
+func (f *Manager) peerExposure(peer *btcec.PublicKey) (btcutil.Amount, error) {
+	channels, err := f.cfg.Wallet.Cfg.Database.FetchOpenChannels(peer)
+	if err != nil {
+		return 0, err
+	}
+
+	var exposure btcutil.Amount
+	for _, c := range channels {
+		exposure += c.Capacity - c.RemoteChanCfg.ChanReserve
+	}
+
+	f.resMtx.RLock()
+	for _, resCtx := range f.activeReservations[newSerializedKey(peer)] {
+		exposure += resCtx.chanAmt - resCtx.remoteChanReserve
+	}
+	f.resMtx.RUnlock()
+
+	return exposure, nil
+}
+
+// checkPeerPolicy returns an error if a new channel of the given size with the
+// peer exceeds our maximum channel size or violates the policy of the peer.
+// It's checked both for the channels we initiate and those we accept.
+func (f *Manager) checkPeerPolicy(peer *btcec.PublicKey,
+	amt btcutil.Amount) error {
+
+	if maxChanSize := f.maxChanSize(peer); amt > maxChanSize {
+		return lnwallet.ErrChanTooLarge(amt, maxChanSize)
+	}
+
+	policy := f.peerPolicy(peer)
+
+	if policy.MaxExposure == 0 {
+		return nil
+	}
+
+	exposure, err := f.peerExposure(peer)
+	if err != nil {
+		return err
+	}
+
+	// The reserve of the new channel isn't negotiated yet, so we assume
+	// the one we require by default. A zero reserve peer may end up
+	// without any.
+	var reserve btcutil.Amount
+	if !f.zeroReserveAllowed(peer) {
+		reserve = f.cfg.RequiredRemoteChanReserve(
+			amt, f.cfg.Wallet.Cfg.DefaultConstraints.DustLimit,
+		)
+	}
+
+	exposure += amt - reserve
+	if exposure > policy.MaxExposure {
+		return lnwallet.ErrPeerExposureTooLarge(
+			exposure, policy.MaxExposure,
+		)
+	}
+
+	return nil
+}
+
+// requiredConfs raises the given number of confirmations to the minimum the
+// policy of the peer requires for wumbo channels, if the channel is one.
+// Zero-conf channels are left untouched, as they're explicitly negotiated.
+func (f *Manager) requiredConfs(peer *btcec.PublicKey, amt btcutil.Amount,
+	numConfs uint16, zeroConf bool) uint16 {
+
+	if zeroConf || amt <= MaxBtcFundingAmount {
+		return numConfs
+	}
+
+	wumboConfs := f.peerPolicy(peer).WumboConfs
+	if wumboConfs > numConfs {
+		return wumboConfs
+	}
+
+	return numConfs
+}
@@ -53,6 +53,8 @@ func NewBoltBackendCreator(dbPath,
 			NoFreelistSync:    boltCfg.NoFreelistSync,
 			AutoCompact:       boltCfg.AutoCompact,
 			AutoCompactMinAge: boltCfg.AutoCompactMinAge,
+			CheckIntegrity:    boltCfg.CheckIntegrity,
+			SalvageCorrupt:    boltCfg.SalvageCorrupt,
 			DBTimeout:         boltCfg.DBTimeout,
 		}
 
@@ -54,6 +54,10 @@ const (
 	// OutgoingFailureForwardsDisabled is returned when the switch is
 	// configured to disallow forwards.
 	OutgoingFailureForwardsDisabled
+
+	// OutgoingFailureSwitchDraining is returned when the switch is in
+	// drain mode and doesn't accept any new forwards.
+	OutgoingFailureSwitchDraining
 )
 
 // FailureString returns the string representation of a failure detail.
@@ -91,6 +95,9 @@ func (fd OutgoingFailure) FailureString() string {
 	case OutgoingFailureForwardsDisabled:
 		return "node configured to disallow forwards"
 
+	case OutgoingFailureSwitchDraining:
+		return "switch is draining"
+
 	default:
 		return "unknown failure detail"
 	}
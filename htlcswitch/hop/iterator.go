@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"runtime"
 	"sync"
 
 	"github.com/btcsuite/btcd/btcec/v2"
@@ -180,6 +181,9 @@ func (p *OnionProcessor) DecodeHopIterator(r io.Reader, rHash []byte,
 			return nil, lnwire.CodeInvalidOnionHmac
 		case sphinx.ErrInvalidOnionKey:
 			return nil, lnwire.CodeInvalidOnionKey
+		case sphinx.ErrReplayedPacket:
+			log.Errorf("unable to process onion packet: %v", err)
+			return nil, lnwire.CodeTemporaryChannelFailure
 		default:
 			log.Errorf("unable to process onion packet: %v", err)
 			return nil, lnwire.CodeInvalidOnionKey
@@ -375,6 +379,57 @@ func (p *OnionProcessor) DecodeHopIterators(id []byte,
 	return resps, nil
 }
 
+// ProcessOnionPackets decodes and validates a batch of independent onion
+// packets concurrently, which improves the throughput compared to decoding
+// them one by one using DecodeHopIterator. In contrast to DecodeHopIterators,
+// each packet is checked against and added to the replay log on its own, so
+// the outcome is not tied to a batch id.
+//
+// NOTE: Part of the Processor interface.
+func (p *OnionProcessor) ProcessOnionPackets(
+	reqs []DecodeHopIteratorRequest) []DecodeHopIteratorResponse {
+
+	resps := make([]DecodeHopIteratorResponse, len(reqs))
+
+	// Execute cpu-heavy onion decoding in parallel. As the decoding is
+	// bound by the cpu, we don't use more goroutines than there are cpus,
+	// regardless of the size of the batch.
+	numWorkers := runtime.NumCPU()
+	if len(reqs) < numWorkers {
+		numWorkers = len(reqs)
+	}
+
+	indexes := make(chan int, len(reqs))
+	for i := range reqs {
+		indexes <- i
+	}
+	close(indexes)
+
+	var wg sync.WaitGroup
+	wg.Add(numWorkers)
+	for w := 0; w < numWorkers; w++ {
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				req := reqs[i]
+				iterator, failCode := p.DecodeHopIterator(
+					req.OnionReader, req.RHash,
+					req.IncomingCltv,
+				)
+
+				resps[i] = DecodeHopIteratorResponse{
+					HopIterator: iterator,
+					FailCode:    failCode,
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return resps
+}
+
 // ExtractErrorEncrypter takes an io.Reader which should contain the onion
 // packet as original received by a forwarding node and creates an
 // ErrorEncrypter instance using the derived shared secret. In the case that en
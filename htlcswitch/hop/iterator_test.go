@@ -3,10 +3,15 @@ package hop
 import (
 	"bytes"
 	"encoding/binary"
+	"runtime"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btclog"
 	"github.com/davecgh/go-spew/spew"
 	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/record"
 	"github.com/lightningnetwork/lnd/tlv"
@@ -98,3 +103,116 @@ func TestSphinxHopIteratorForwardingInstructions(t *testing.T) {
 		}
 	}
 }
+
+// TestOnionProcessorProcessOnionPackets asserts that a batch of onion packets
+// is processed concurrently, and that each packet is subject to replay
+// protection.
+func TestOnionProcessorProcessOnionPackets(t *testing.T) {
+	// The onion processor logs its progress, so make sure a logger is
+	// set.
+	UseLogger(btclog.Disabled)
+
+	nodeKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	router := sphinx.NewRouter(
+		&keychain.PrivKeyECDH{PrivKey: nodeKey},
+		&chaincfg.SimNetParams, sphinx.NewMemoryReplayLog(),
+	)
+	processor := NewOnionProcessor(router)
+	require.NoError(t, processor.Start())
+	t.Cleanup(func() {
+		require.NoError(t, processor.Stop())
+	})
+
+	// Create an onion packet that has our node as its final hop.
+	hopPayload, err := sphinx.NewHopPayload(&sphinx.HopData{
+		ForwardAmount: 1000,
+		OutgoingCltv:  100,
+	}, nil)
+	require.NoError(t, err)
+
+	var path sphinx.PaymentPath
+	path[0] = sphinx.OnionHop{
+		NodePub:    *nodeKey.PubKey(),
+		HopPayload: hopPayload,
+	}
+
+	rHash := bytes.Repeat([]byte{1}, 32)
+	newOnion := func() []byte {
+		sessionKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		onion, err := sphinx.NewOnionPacket(
+			&path, sessionKey, rHash,
+			sphinx.DeterministicPacketFiller,
+		)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, onion.Encode(&b))
+
+		return b.Bytes()
+	}
+
+	// Each request gets a reader of its own, as a reader is consumed when
+	// the onion is decoded.
+	newReqs := func(onions ...[]byte) []DecodeHopIteratorRequest {
+		reqs := make([]DecodeHopIteratorRequest, 0, len(onions))
+		for _, onion := range onions {
+			reqs = append(reqs, DecodeHopIteratorRequest{
+				OnionReader:  bytes.NewReader(onion),
+				RHash:        rHash,
+				IncomingCltv: 100,
+			})
+		}
+
+		return reqs
+	}
+
+	// The batch contains more distinct valid onions than there are cpus,
+	// so that the workers process several onions each, and a packet that
+	// can't be decoded.
+	numOnions := runtime.NumCPU() + 1
+	onions := make([][]byte, 0, numOnions+1)
+	for i := 0; i < numOnions; i++ {
+		onions = append(onions, newOnion())
+	}
+	invalid := make([]byte, len(onions[0]))
+	invalid[0] = 0xff
+	onions = append(onions, invalid)
+
+	resps := processor.ProcessOnionPackets(newReqs(onions...))
+	require.Len(t, resps, len(onions))
+
+	for i := 0; i < numOnions; i++ {
+		iterator, failCode := resps[i].Result()
+		require.Equal(t, lnwire.CodeNone, failCode)
+
+		payload, err := iterator.HopPayload()
+		require.NoError(t, err)
+		require.Equal(t, Exit, payload.FwdInfo.NextHop)
+	}
+
+	_, failCode := resps[numOnions].Result()
+	require.Equal(t, lnwire.CodeInvalidOnionVersion, failCode)
+
+	// Processing the first onion again must be detected as a replay.
+	resps = processor.ProcessOnionPackets(newReqs(onions[0]))
+	_, failCode = resps[0].Result()
+	require.Equal(t, lnwire.CodeTemporaryChannelFailure, failCode)
+
+	// A replay within the same batch is detected as well, so only one of
+	// the two copies of a new onion is accepted.
+	onion := newOnion()
+	resps = processor.ProcessOnionPackets(newReqs(onion, onion))
+
+	failCodes := make([]lnwire.FailCode, 0, len(resps))
+	for _, resp := range resps {
+		_, failCode := resp.Result()
+		failCodes = append(failCodes, failCode)
+	}
+	require.ElementsMatch(t, []lnwire.FailCode{
+		lnwire.CodeNone, lnwire.CodeTemporaryChannelFailure,
+	}, failCodes)
+}
@@ -0,0 +1,56 @@
+package hop
+
+import (
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Processor is an interface that abstracts away the processing of onion
+// packets. The default implementation is the OnionProcessor, which processes
+// the packets locally using a sphinx router. Alternative implementations can
+// for example offload the processing to dedicated hardware, or to a remote
+// signer in the case of a watch-only node.
+type Processor interface {
+	// Start spins up the onion processor.
+	Start() error
+
+	// Stop shuts down the onion processor.
+	Stop() error
+
+	// DecodeHopIterator attempts to decode a valid onion packet from the
+	// passed io.Reader instance using the rHash as the associated data
+	// when checking the relevant MACs during the decoding process.
+	DecodeHopIterator(r io.Reader, rHash []byte,
+		incomingCltv uint32) (Iterator, lnwire.FailCode)
+
+	// ReconstructHopIterator attempts to decode a valid onion packet from
+	// the passed io.Reader instance that was successfully processed
+	// before. No replay protection is applied.
+	ReconstructHopIterator(r io.Reader, rHash []byte) (Iterator, error)
+
+	// DecodeHopIterators performs batched decoding and validation of
+	// incoming onion packets. For the same `id`, this method must return
+	// the same iterators and failcodes upon subsequent invocations.
+	DecodeHopIterators(id []byte,
+		reqs []DecodeHopIteratorRequest) ([]DecodeHopIteratorResponse,
+		error)
+
+	// ProcessOnionPackets decodes and validates a batch of independent
+	// onion packets concurrently. The i-th response corresponds to the
+	// i-th request.
+	ProcessOnionPackets(
+		reqs []DecodeHopIteratorRequest) []DecodeHopIteratorResponse
+
+	// ExtractErrorEncrypter creates an ErrorEncrypter instance for the
+	// onion packet with the given ephemeral key. In the case that an
+	// error occurs, a lnwire failure code detailing the parsing failure
+	// will be returned.
+	ExtractErrorEncrypter(ephemeralKey *btcec.PublicKey) (ErrorEncrypter,
+		lnwire.FailCode)
+}
+
+// A compile time check to ensure OnionProcessor implements the Processor
+// interface.
+var _ Processor = (*OnionProcessor)(nil)
@@ -0,0 +1,250 @@
+package htlcswitch
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/htlcswitch/htlcmetrics"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// DefaultMaxHtlcTraces is the default number of HTLCs that are traced at the
+// same time.
+const DefaultMaxHtlcTraces = 10000
+
+// htlcTraceExpiry is the time after which the trace of an HTLC that wasn't
+// resolved upstream is dropped once the limit of traced HTLCs is reached.
+// Traces of HTLCs that are never resolved by the link, for example because
+// they are resolved on chain, would otherwise stop the tracing of new HTLCs.
+const htlcTraceExpiry = time.Hour
+
+// HtlcTracer records the times at which forwarded HTLCs pass the stages of the
+// forwarding pipeline, and hands the trace of each HTLC to a recorder once its
+// settle or fail was sent upstream. HTLCs are identified by their incoming
+// circuit key until they are added downstream, after which the outgoing
+// circuit key leads to the same trace.
+//
+// The tracer only keeps track of in-memory state, so HTLCs that are replayed
+// after a restart aren't traced, and the traces of a link's HTLCs are dropped
+// when the link is stopped. All methods are safe to call on a nil tracer, in
+// which case nothing is traced.
+type HtlcTracer struct {
+	recorder  htlcmetrics.Recorder
+	maxTraces int
+	now       func() time.Time
+
+	mu sync.Mutex
+
+	// traces holds the traces of the HTLCs in flight, keyed by their
+	// incoming circuit key.
+	traces map[CircuitKey]*htlcmetrics.Trace
+
+	// outgoing maps the outgoing circuit key of the HTLCs that were added
+	// downstream to their incoming circuit key.
+	outgoing map[CircuitKey]CircuitKey
+}
+
+// NewHtlcTracer creates a tracer that hands the traces of forwarded HTLCs to
+// the given recorder. At most maxTraces HTLCs are traced at the same time,
+// HTLCs that are received while the limit is reached aren't traced.
+func NewHtlcTracer(recorder htlcmetrics.Recorder, maxTraces int,
+	now func() time.Time) *HtlcTracer {
+
+	return &HtlcTracer{
+		recorder:  recorder,
+		maxTraces: maxTraces,
+		now:       now,
+		traces:    make(map[CircuitKey]*htlcmetrics.Trace),
+		outgoing:  make(map[CircuitKey]CircuitKey),
+	}
+}
+
+// AddReceived starts the trace of an HTLC that was received from the upstream
+// peer.
+func (t *HtlcTracer) AddReceived(inKey CircuitKey) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.traces[inKey]; ok {
+		return
+	}
+
+	now := t.now()
+	if len(t.traces) >= t.maxTraces {
+		t.dropExpired(now)
+
+		if len(t.traces) >= t.maxTraces {
+			return
+		}
+	}
+
+	trace := &htlcmetrics.Trace{
+		IncomingChanID: inKey.ChanID,
+		IncomingHtlcID: inKey.HtlcID,
+	}
+	trace.Timestamps[htlcmetrics.StageAddReceived] = now
+
+	t.traces[inKey] = trace
+}
+
+// ForwardDecision records that the locked in HTLCs of the incoming link were
+// processed. The traces of the HTLCs that are forwarded are continued, while
+// the ones of HTLCs that were settled or failed by the incoming link are
+// dropped.
+func (t *HtlcTracer) ForwardDecision(inKeys []CircuitKey,
+	forwarded []*htlcPacket) {
+
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	isForwarded := make(map[CircuitKey]struct{}, len(forwarded))
+	for _, pkt := range forwarded {
+		inKey := pkt.inKey()
+		isForwarded[inKey] = struct{}{}
+
+		trace, ok := t.traces[inKey]
+		if !ok {
+			continue
+		}
+		trace.Timestamps[htlcmetrics.StageForwardDecision] = now
+	}
+
+	for _, inKey := range inKeys {
+		if _, ok := isForwarded[inKey]; !ok {
+			delete(t.traces, inKey)
+		}
+	}
+}
+
+// DownstreamAdd records that an HTLC was added to the outgoing channel and
+// sent to the downstream peer.
+func (t *HtlcTracer) DownstreamAdd(inKey, outKey CircuitKey) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	trace, ok := t.traces[inKey]
+	if !ok {
+		return
+	}
+
+	trace.OutgoingChanID = outKey.ChanID
+	trace.OutgoingHtlcID = outKey.HtlcID
+	trace.Timestamps[htlcmetrics.StageDownstreamAdd] = t.now()
+
+	t.outgoing[outKey] = inKey
+}
+
+// SettleReceived records that the settle or fail of an HTLC was received from
+// the downstream peer.
+func (t *HtlcTracer) SettleReceived(outKey CircuitKey) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	inKey, ok := t.outgoing[outKey]
+	if !ok {
+		return
+	}
+	delete(t.outgoing, outKey)
+
+	if trace, ok := t.traces[inKey]; ok {
+		trace.Timestamps[htlcmetrics.StageSettleReceived] = t.now()
+	}
+}
+
+// SettleSent records that the settle or fail of an HTLC was sent to the
+// upstream peer, which completes its trace. The trace is handed to the
+// recorder.
+func (t *HtlcTracer) SettleSent(inKey CircuitKey, settled bool) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	trace, ok := t.traces[inKey]
+	if ok {
+		t.drop(inKey)
+
+		trace.Settled = settled
+		trace.Timestamps[htlcmetrics.StageSettleSent] = t.now()
+	}
+	t.mu.Unlock()
+
+	if ok {
+		t.recorder.HtlcResolved(trace)
+	}
+}
+
+// LinkStopped drops the traces of the HTLCs that were received by the link of
+// the given channel, as their resolution isn't observed anymore and their
+// HTLC indexes may be reused once the link is restarted. The outgoing circuit
+// keys of the channel are forgotten as well.
+func (t *HtlcTracer) LinkStopped(chanID lnwire.ShortChannelID) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for inKey := range t.traces {
+		if inKey.ChanID == chanID {
+			t.drop(inKey)
+		}
+	}
+
+	for outKey := range t.outgoing {
+		if outKey.ChanID == chanID {
+			delete(t.outgoing, outKey)
+		}
+	}
+}
+
+// dropExpired drops the traces of the HTLCs that were received more than
+// htlcTraceExpiry ago.
+//
+// NOTE: The caller must hold the mutex.
+func (t *HtlcTracer) dropExpired(now time.Time) {
+	for inKey, trace := range t.traces {
+		received := trace.Timestamps[htlcmetrics.StageAddReceived]
+		if now.Sub(received) >= htlcTraceExpiry {
+			t.drop(inKey)
+		}
+	}
+}
+
+// drop removes the trace of the HTLC with the given incoming circuit key,
+// along with its outgoing circuit key.
+//
+// NOTE: The caller must hold the mutex.
+func (t *HtlcTracer) drop(inKey CircuitKey) {
+	trace, ok := t.traces[inKey]
+	if !ok {
+		return
+	}
+	delete(t.traces, inKey)
+
+	outKey := CircuitKey{
+		ChanID: trace.OutgoingChanID,
+		HtlcID: trace.OutgoingHtlcID,
+	}
+	if key, ok := t.outgoing[outKey]; ok && key == inKey {
+		delete(t.outgoing, outKey)
+	}
+}
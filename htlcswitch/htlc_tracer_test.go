@@ -0,0 +1,153 @@
+package htlcswitch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/htlcswitch/htlcmetrics"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// mockHtlcRecorder is a htlcmetrics.Recorder that collects the traces of
+// resolved HTLCs.
+type mockHtlcRecorder struct {
+	traces []*htlcmetrics.Trace
+}
+
+// HtlcResolved records the trace of a resolved HTLC.
+func (m *mockHtlcRecorder) HtlcResolved(trace *htlcmetrics.Trace) {
+	m.traces = append(m.traces, trace)
+}
+
+// TestHtlcTracer asserts that the tracer stamps the stages of forwarded HTLCs
+// and hands their traces to the recorder once they are resolved upstream.
+func TestHtlcTracer(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1000, 0)
+	now := start
+	tick := func() {
+		now = now.Add(time.Second)
+	}
+
+	recorder := &mockHtlcRecorder{}
+	tracer := NewHtlcTracer(recorder, 2, func() time.Time {
+		return now
+	})
+
+	inChan := lnwire.NewShortChanIDFromInt(1)
+	outChan := lnwire.NewShortChanIDFromInt(2)
+
+	forwarded := CircuitKey{ChanID: inChan, HtlcID: 0}
+	local := CircuitKey{ChanID: inChan, HtlcID: 1}
+	untraced := CircuitKey{ChanID: inChan, HtlcID: 2}
+	outKey := CircuitKey{ChanID: outChan, HtlcID: 5}
+
+	// Only two HTLCs are traced at the same time, so the third one is
+	// ignored.
+	tracer.AddReceived(forwarded)
+	tracer.AddReceived(local)
+	tracer.AddReceived(untraced)
+	tick()
+
+	// The HTLC that isn't forwarded is no longer traced once the locked
+	// in adds have been processed.
+	tracer.ForwardDecision(
+		[]CircuitKey{forwarded, local, untraced},
+		[]*htlcPacket{{
+			incomingChanID: inChan,
+			incomingHTLCID: forwarded.HtlcID,
+		}},
+	)
+	tick()
+
+	tracer.DownstreamAdd(forwarded, outKey)
+	tick()
+	tracer.SettleReceived(outKey)
+	tick()
+
+	tracer.SettleSent(local, false)
+	tracer.SettleSent(untraced, false)
+	require.Empty(t, recorder.traces)
+
+	tracer.SettleSent(forwarded, true)
+	require.Len(t, recorder.traces, 1)
+
+	trace := recorder.traces[0]
+	require.Equal(t, inChan, trace.IncomingChanID)
+	require.Equal(t, forwarded.HtlcID, trace.IncomingHtlcID)
+	require.Equal(t, outChan, trace.OutgoingChanID)
+	require.Equal(t, outKey.HtlcID, trace.OutgoingHtlcID)
+	require.True(t, trace.Settled)
+
+	first := htlcmetrics.StageAddReceived
+	for stage := first; stage < htlcmetrics.NumStages; stage++ {
+		interval, ok := trace.Interval(first, stage)
+		require.True(t, ok, stage.String())
+		require.Equal(t, time.Duration(stage)*time.Second, interval)
+	}
+
+	// All state of the resolved HTLC is cleaned up.
+	require.Empty(t, tracer.traces)
+	require.Empty(t, tracer.outgoing)
+
+	// A nil tracer ignores all calls.
+	var nilTracer *HtlcTracer
+	nilTracer.AddReceived(forwarded)
+	nilTracer.ForwardDecision([]CircuitKey{forwarded}, nil)
+	nilTracer.DownstreamAdd(forwarded, outKey)
+	nilTracer.SettleReceived(outKey)
+	nilTracer.SettleSent(forwarded, true)
+}
+
+// TestHtlcTracerStaleTraces asserts that the traces of HTLCs that are never
+// resolved upstream don't stop the tracing of new HTLCs.
+func TestHtlcTracerStaleTraces(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1000, 0)
+	recorder := &mockHtlcRecorder{}
+	tracer := NewHtlcTracer(recorder, 2, func() time.Time {
+		return now
+	})
+
+	inChan := lnwire.NewShortChanIDFromInt(1)
+	otherChan := lnwire.NewShortChanIDFromInt(2)
+	outChan := lnwire.NewShortChanIDFromInt(3)
+
+	stale := CircuitKey{ChanID: inChan, HtlcID: 0}
+	other := CircuitKey{ChanID: otherChan, HtlcID: 0}
+	outKey := CircuitKey{ChanID: outChan, HtlcID: 0}
+
+	tracer.AddReceived(stale)
+	tracer.DownstreamAdd(stale, outKey)
+	tracer.AddReceived(other)
+
+	// Stopping the incoming link drops the traces of its HTLCs, so a
+	// reused HTLC index starts a new trace.
+	tracer.LinkStopped(inChan)
+	require.NotContains(t, tracer.traces, stale)
+	require.Contains(t, tracer.traces, other)
+	require.Empty(t, tracer.outgoing)
+
+	tracer.AddReceived(stale)
+	tracer.SettleReceived(outKey)
+	tracer.SettleSent(stale, true)
+	require.Len(t, recorder.traces, 1)
+
+	settleReceived := htlcmetrics.StageSettleReceived
+	require.True(t, recorder.traces[0].Timestamps[settleReceived].IsZero())
+
+	// Once the limit is reached, new HTLCs are only traced after the
+	// oldest traces expired.
+	fresh := CircuitKey{ChanID: inChan, HtlcID: 1}
+	tracer.AddReceived(stale)
+	tracer.AddReceived(fresh)
+	require.NotContains(t, tracer.traces, fresh)
+
+	now = now.Add(htlcTraceExpiry)
+	tracer.AddReceived(fresh)
+	require.Len(t, tracer.traces, 1)
+	require.Contains(t, tracer.traces, fresh)
+}
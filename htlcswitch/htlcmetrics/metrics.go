@@ -0,0 +1,120 @@
+package htlcmetrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Stage is a stage of the forwarding pipeline that an HTLC passes through.
+type Stage uint8
+
+const (
+	// StageAddReceived is reached when the incoming link receives the
+	// update_add_htlc from the upstream peer.
+	StageAddReceived Stage = iota
+
+	// StageForwardDecision is reached when the incoming link has locked
+	// in the HTLC and hands it to the switch to be forwarded.
+	StageForwardDecision
+
+	// StageDownstreamAdd is reached when the outgoing link adds the HTLC
+	// to its channel and sends update_add_htlc to the downstream peer.
+	StageDownstreamAdd
+
+	// StageSettleReceived is reached when the outgoing link receives the
+	// settle or fail of the HTLC from the downstream peer.
+	StageSettleReceived
+
+	// StageSettleSent is reached when the incoming link sends the settle
+	// or fail of the HTLC to the upstream peer.
+	StageSettleSent
+
+	// NumStages is the number of stages of the forwarding pipeline.
+	NumStages
+)
+
+// String returns a human readable name of the stage.
+func (s Stage) String() string {
+	switch s {
+	case StageAddReceived:
+		return "add_received"
+
+	case StageForwardDecision:
+		return "forward_decision"
+
+	case StageDownstreamAdd:
+		return "downstream_add"
+
+	case StageSettleReceived:
+		return "settle_received"
+
+	case StageSettleSent:
+		return "settle_sent"
+
+	default:
+		return fmt.Sprintf("unknown<%d>", uint8(s))
+	}
+}
+
+// Trace holds the times at which a forwarded HTLC reached the stages of the
+// forwarding pipeline. Stages that weren't reached, for example because the
+// HTLC was failed by the switch before it was added downstream, have the zero
+// time.
+type Trace struct {
+	// IncomingChanID and IncomingHtlcID identify the HTLC on the incoming
+	// channel.
+	IncomingChanID lnwire.ShortChannelID
+	IncomingHtlcID uint64
+
+	// OutgoingChanID and OutgoingHtlcID identify the HTLC on the outgoing
+	// channel. They are only set if the HTLC was added downstream.
+	OutgoingChanID lnwire.ShortChannelID
+	OutgoingHtlcID uint64
+
+	// Settled is true if the HTLC was settled, and false if it was
+	// failed.
+	Settled bool
+
+	// Timestamps holds the time each stage was reached, indexed by stage.
+	Timestamps [NumStages]time.Time
+}
+
+// ID returns an identifier of the traced HTLC that is unique among the open
+// HTLCs of the node.
+func (t *Trace) ID() string {
+	return fmt.Sprintf("%v:%d", t.IncomingChanID, t.IncomingHtlcID)
+}
+
+// Interval returns the time it took the HTLC to get from one stage to
+// another. False is returned if the HTLC didn't reach either of the stages.
+func (t *Trace) Interval(from, to Stage) (time.Duration, bool) {
+	start, end := t.Timestamps[from], t.Timestamps[to]
+	if start.IsZero() || end.IsZero() {
+		return 0, false
+	}
+
+	return end.Sub(start), true
+}
+
+// Recorder records the traces of forwarded HTLCs, so that node operators can
+// locate latency bottlenecks in the forwarding pipeline.
+type Recorder interface {
+	// HtlcResolved records the trace of an HTLC whose settle or fail was
+	// sent to the upstream peer.
+	HtlcResolved(trace *Trace)
+}
+
+// NoOpRecorder is a Recorder that discards all traces. It is used if the
+// latency of HTLCs isn't exported.
+type NoOpRecorder struct{}
+
+// A compile-time assertion to ensure NoOpRecorder implements the Recorder
+// interface.
+var _ Recorder = (*NoOpRecorder)(nil)
+
+// HtlcResolved records the trace of a resolved HTLC.
+//
+// NOTE: This is part of the Recorder interface.
+func (NoOpRecorder) HtlcResolved(*Trace) {}
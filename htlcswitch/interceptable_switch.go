@@ -4,6 +4,8 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-errors/errors"
 	"github.com/lightningnetwork/lnd/channeldb"
@@ -22,6 +24,18 @@ var (
 	ErrUnsupportedFailureCode = errors.New("unsupported failure code")
 )
 
+const (
+	// interceptorHealthInterval is the interval at which the switch checks
+	// whether the active interceptor resolves its forwards within its
+	// health timeout.
+	interceptorHealthInterval = 100 * time.Millisecond
+
+	// legacyInterceptorID is the id of the interceptor registered through
+	// SetInterceptor. Interceptors registered through AddInterceptor are
+	// assigned ids starting at 1.
+	legacyInterceptorID = 0
+)
+
 // InterceptableSwitch is an implementation of ForwardingSwitch interface.
 // This implementation is used like a proxy that wraps the switch and
 // intercepts forward requests. A reference to the Switch is held in order
@@ -45,18 +59,35 @@ type InterceptableSwitch struct {
 
 	// interceptorRegistration is a channel that we use to synchronize
 	// client connect and disconnect.
-	interceptorRegistration chan ForwardInterceptor
+	interceptorRegistration chan *interceptorRegistration
 
 	// requireInterceptor indicates whether processing should block if no
 	// interceptor is connected.
 	requireInterceptor bool
 
-	// interceptor is the handler for intercepted packets.
-	interceptor ForwardInterceptor
+	// interceptorID is the id of the last interceptor registered through
+	// AddInterceptor. To be used atomically.
+	interceptorID uint64
+
+	// interceptorSeq is incremented for every registered interceptor, to
+	// order interceptors of the same priority by registration.
+	interceptorSeq uint64
+
+	// interceptors are all currently registered interceptors by id.
+	interceptors map[uint64]*registeredInterceptor
+
+	// interceptor is the active interceptor, which is the registered
+	// interceptor with the lowest priority value. Intercepted packets are
+	// only sent to it.
+	interceptor *registeredInterceptor
 
 	// holdForwards keeps track of outstanding intercepted forwards.
 	holdForwards map[channeldb.CircuitKey]InterceptedForward
 
+	// offeredForwards tracks when each held forward was offered to the
+	// active interceptor, to detect an unhealthy interceptor.
+	offeredForwards map[channeldb.CircuitKey]time.Time
+
 	// cltvRejectDelta defines the number of blocks before the expiry of the
 	// htlc where we no longer intercept it and instead cancel it back.
 	cltvRejectDelta uint32
@@ -65,6 +96,56 @@ type InterceptableSwitch struct {
 	quit chan struct{}
 }
 
+// InterceptorOptions define how a registered interceptor is used.
+type InterceptorOptions struct {
+	// Priority is the priority of the interceptor. Forwards are only sent
+	// to the interceptor with the lowest priority value, or the one that
+	// was registered first if there are several.
+	Priority uint32
+
+	// HealthTimeout is the time the interceptor has to resolve a forward
+	// offered to it. An interceptor that exceeds it is unregistered, and
+	// its forwards are offered to the next interceptor. Zero disables the
+	// health check.
+	HealthTimeout time.Duration
+
+	// OnUnhealthy is called when the interceptor is unregistered because
+	// it exceeded its health timeout. It must not block.
+	OnUnhealthy func()
+}
+
+// registeredInterceptor is an interceptor registered with the switch.
+type registeredInterceptor struct {
+	id          uint64
+	seq         uint64
+	interceptor ForwardInterceptor
+	opts        InterceptorOptions
+}
+
+// before returns whether the interceptor takes precedence over the other
+// interceptor.
+func (r *registeredInterceptor) before(other *registeredInterceptor) bool {
+	if r.opts.Priority != other.opts.Priority {
+		return r.opts.Priority < other.opts.Priority
+	}
+
+	return r.seq < other.seq
+}
+
+// interceptorRegistration registers, updates or removes an interceptor.
+type interceptorRegistration struct {
+	id uint64
+
+	// interceptor is the interceptor to register. If nil, only the
+	// options of an already registered interceptor are updated.
+	interceptor ForwardInterceptor
+
+	opts InterceptorOptions
+
+	// remove indicates that the interceptor is to be unregistered.
+	remove bool
+}
+
 type interceptedPackets struct {
 	packets  []*htlcPacket
 	linkQuit chan struct{}
@@ -119,8 +200,10 @@ func NewInterceptableSwitch(s *Switch, cltvRejectDelta uint32,
 		htlcSwitch:              s,
 		intercepted:             make(chan *interceptedPackets),
 		onchainIntercepted:      make(chan InterceptedForward),
-		interceptorRegistration: make(chan ForwardInterceptor),
+		interceptorRegistration: make(chan *interceptorRegistration),
+		interceptors:            make(map[uint64]*registeredInterceptor),
 		holdForwards:            make(map[channeldb.CircuitKey]InterceptedForward),
+		offeredForwards:         make(map[channeldb.CircuitKey]time.Time),
 		resolutionChan:          make(chan *fwdResolution),
 		requireInterceptor:      requireInterceptor,
 		cltvRejectDelta:         cltvRejectDelta,
@@ -129,15 +212,58 @@ func NewInterceptableSwitch(s *Switch, cltvRejectDelta uint32,
 	}
 }
 
-// SetInterceptor sets the ForwardInterceptor to be used. A nil argument
-// unregisters the current interceptor.
+// SetInterceptor sets the ForwardInterceptor to be used with the default
+// options, replacing the one previously set. A nil argument unregisters the
+// interceptor previously set.
 func (s *InterceptableSwitch) SetInterceptor(
 	interceptor ForwardInterceptor) {
 
+	s.register(&interceptorRegistration{
+		id:          legacyInterceptorID,
+		interceptor: interceptor,
+		remove:      interceptor == nil,
+	})
+}
+
+// AddInterceptor registers an additional ForwardInterceptor with the given
+// options, and returns the id to update or unregister it with.
+func (s *InterceptableSwitch) AddInterceptor(interceptor ForwardInterceptor,
+	opts InterceptorOptions) uint64 {
+
+	id := atomic.AddUint64(&s.interceptorID, 1)
+	s.register(&interceptorRegistration{
+		id:          id,
+		interceptor: interceptor,
+		opts:        opts,
+	})
+
+	return id
+}
+
+// UpdateInterceptor updates the options of a registered interceptor.
+func (s *InterceptableSwitch) UpdateInterceptor(id uint64,
+	opts InterceptorOptions) {
+
+	s.register(&interceptorRegistration{
+		id:   id,
+		opts: opts,
+	})
+}
+
+// RemoveInterceptor unregisters an interceptor.
+func (s *InterceptableSwitch) RemoveInterceptor(id uint64) {
+	s.register(&interceptorRegistration{
+		id:     id,
+		remove: true,
+	})
+}
+
+// register passes the registration to the main loop.
+func (s *InterceptableSwitch) register(reg *interceptorRegistration) {
 	// Synchronize setting the handler with the main loop to prevent race
 	// conditions.
 	select {
-	case s.interceptorRegistration <- interceptor:
+	case s.interceptorRegistration <- reg:
 
 	case <-s.quit:
 	}
@@ -162,11 +288,17 @@ func (s *InterceptableSwitch) Stop() error {
 }
 
 func (s *InterceptableSwitch) run() {
+	healthTicker := time.NewTicker(interceptorHealthInterval)
+	defer healthTicker.Stop()
+
 	for {
 		select {
 		// An interceptor registration or de-registration came in.
-		case interceptor := <-s.interceptorRegistration:
-			s.setInterceptor(interceptor)
+		case reg := <-s.interceptorRegistration:
+			s.handleRegistration(reg)
+
+		case <-healthTicker.C:
+			s.checkInterceptorHealth()
 
 		case packets := <-s.intercepted:
 			var notIntercepted []*htlcPacket
@@ -203,7 +335,10 @@ func (s *InterceptableSwitch) run() {
 	}
 }
 func (s *InterceptableSwitch) sendForward(fwd InterceptedForward) {
-	err := s.interceptor(fwd.Packet())
+	packet := fwd.Packet()
+	s.offeredForwards[packet.IncomingCircuit] = time.Now()
+
+	err := s.interceptor.interceptor(packet)
 	if err != nil {
 		// Only log the error. If we couldn't send the packet, we assume
 		// that the interceptor will reconnect so that we can retry.
@@ -211,14 +346,90 @@ func (s *InterceptableSwitch) sendForward(fwd InterceptedForward) {
 	}
 }
 
-func (s *InterceptableSwitch) setInterceptor(interceptor ForwardInterceptor) {
+// handleRegistration applies an interceptor registration, and activates the
+// interceptor that takes precedence afterwards.
+func (s *InterceptableSwitch) handleRegistration(reg *interceptorRegistration) {
+	switch {
+	case reg.remove:
+		delete(s.interceptors, reg.id)
+
+	case reg.interceptor != nil:
+		s.interceptorSeq++
+		s.interceptors[reg.id] = &registeredInterceptor{
+			id:          reg.id,
+			seq:         s.interceptorSeq,
+			interceptor: reg.interceptor,
+			opts:        reg.opts,
+		}
+
+	default:
+		registered, ok := s.interceptors[reg.id]
+		if !ok {
+			return
+		}
+		registered.opts = reg.opts
+	}
+
+	s.activateInterceptor()
+}
+
+// activateInterceptor makes the registered interceptor with the lowest
+// priority value the active one. If it changes, which includes the
+// re-registration of the active interceptor, the held forwards are offered to
+// the new interceptor.
+func (s *InterceptableSwitch) activateInterceptor() {
+	var next *registeredInterceptor
+	for _, registered := range s.interceptors {
+		if next == nil || registered.before(next) {
+			next = registered
+		}
+	}
+
+	if next == s.interceptor {
+		return
+	}
+
+	s.setInterceptor(next)
+}
+
+// checkInterceptorHealth unregisters the active interceptor if it didn't
+// resolve one of its forwards within its health timeout, and offers the held
+// forwards to the next interceptor.
+func (s *InterceptableSwitch) checkInterceptorHealth() {
+	if s.interceptor == nil || s.interceptor.opts.HealthTimeout == 0 {
+		return
+	}
+
+	timeout := s.interceptor.opts.HealthTimeout
+	for key, offered := range s.offeredForwards {
+		if time.Since(offered) < timeout {
+			continue
+		}
+
+		log.Warnf("Interceptor %v didn't resolve forward %v within "+
+			"%v, failing over", s.interceptor.id, key, timeout)
+
+		delete(s.interceptors, s.interceptor.id)
+		if s.interceptor.opts.OnUnhealthy != nil {
+			s.interceptor.opts.OnUnhealthy()
+		}
+		s.activateInterceptor()
+
+		return
+	}
+}
+
+func (s *InterceptableSwitch) setInterceptor(
+	interceptor *registeredInterceptor) {
+
 	s.interceptor = interceptor
+	s.offeredForwards = make(map[channeldb.CircuitKey]time.Time)
 
 	// Replay all currently held htlcs. When an interceptor is not required,
 	// there may be none because they've been cleared after the previous
 	// disconnect.
 	if interceptor != nil {
-		log.Debugf("Interceptor connected")
+		log.Debugf("Interceptor %v connected", interceptor.id)
 
 		for _, fwd := range s.holdForwards {
 			s.sendForward(fwd)
@@ -252,6 +463,7 @@ func (s *InterceptableSwitch) resolve(res *FwdResolution) error {
 		return fmt.Errorf("fwd %v not found", res.Key)
 	}
 	delete(s.holdForwards, res.Key)
+	delete(s.offeredForwards, res.Key)
 
 	switch res.Action {
 	case FwdActionResume:
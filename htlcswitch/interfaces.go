@@ -120,6 +120,11 @@ type ChannelUpdateHandler interface {
 	// HTLC's which have been set to the over flow queue.
 	Bandwidth() lnwire.MilliSatoshi
 
+	// LiquiditySnapshot returns a breakdown of the liquidity of the
+	// channel at this instant, including the pending HTLCs, the commitment
+	// fee buffer and the reserves of both parties.
+	LiquiditySnapshot() *lnwallet.LiquiditySnapshot
+
 	// EligibleToForward returns a bool indicating if the channel is able
 	// to actively accept requests to forward HTLC's. A channel may be
 	// active, but not able to forward HTLC's if it hasn't yet finalized
@@ -193,6 +198,9 @@ type ChannelLink interface {
 	// policy to govern if it an incoming HTLC should be forwarded or not.
 	UpdateForwardingPolicy(ForwardingPolicy)
 
+	// ForwardingPolicy returns the current forwarding policy of the link.
+	ForwardingPolicy() ForwardingPolicy
+
 	// CheckHtlcForward should return a nil error if the passed HTLC details
 	// satisfy the current forwarding policy fo the target link. Otherwise,
 	// a LinkError with a valid protocol failure message should be returned
@@ -215,6 +223,10 @@ type ChannelLink interface {
 	// total sent/received milli-satoshis.
 	Stats() (uint64, lnwire.MilliSatoshi, lnwire.MilliSatoshi)
 
+	// NumPendingHtlcs returns the number of HTLCs that are on either of
+	// the commitments of the channel.
+	NumPendingHtlcs() int
+
 	// Peer returns the representation of remote peer with which we have
 	// the channel link opened.
 	Peer() lnpeer.Peer
@@ -267,6 +279,17 @@ type InterceptableHtlcForwarder interface {
 	// SetInterceptor sets a ForwardInterceptor.
 	SetInterceptor(interceptor ForwardInterceptor)
 
+	// AddInterceptor registers an additional ForwardInterceptor with the
+	// given options, and returns the id to update or unregister it with.
+	AddInterceptor(interceptor ForwardInterceptor,
+		opts InterceptorOptions) uint64
+
+	// UpdateInterceptor updates the options of a registered interceptor.
+	UpdateInterceptor(id uint64, opts InterceptorOptions)
+
+	// RemoveInterceptor unregisters an interceptor.
+	RemoveInterceptor(id uint64)
+
 	// Resolve resolves an intercepted packet.
 	Resolve(res *FwdResolution) error
 }
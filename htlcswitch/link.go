@@ -303,6 +303,10 @@ type ChannelLinkConfig struct {
 	// GetAliases is used by the link and switch to fetch the set of
 	// aliases for a given link.
 	GetAliases func(base lnwire.ShortChannelID) []lnwire.ShortChannelID
+
+	// HtlcTracer is an optional tracer that records the latency of
+	// forwarded HTLCs. If nil, HTLCs aren't traced.
+	HtlcTracer *HtlcTracer
 }
 
 // shutdownReq contains an error channel that will be used by the channelLink
@@ -540,6 +544,10 @@ func (l *channelLink) Stop() {
 	close(l.quit)
 	l.wg.Wait()
 
+	// The resolution of the link's HTLCs isn't observed anymore, so we
+	// stop tracing them.
+	l.cfg.HtlcTracer.LinkStopped(l.ShortChanID())
+
 	// Now that the htlcManager has completely exited, reset the packet
 	// courier. This allows the mailbox to revaluate any lingering Adds that
 	// were delivered but didn't make it on a commitment to be failed back
@@ -1470,6 +1478,7 @@ func (l *channelLink) handleDownstreamUpdateAdd(pkt *htlcPacket) error {
 	l.keystoneBatch = append(l.keystoneBatch, pkt.keystone())
 
 	_ = l.cfg.Peer.SendMessage(false, htlc)
+	l.cfg.HtlcTracer.DownstreamAdd(pkt.inKey(), pkt.outKey())
 
 	// Send a forward event notification to htlcNotifier.
 	l.cfg.HtlcNotifier.NotifyForwardingEvent(
@@ -1557,6 +1566,7 @@ func (l *channelLink) handleDownstreamPkt(pkt *htlcPacket) {
 		// Then we send the HTLC settle message to the connected peer
 		// so we can continue the propagation of the settle message.
 		l.cfg.Peer.SendMessage(false, htlc)
+		l.cfg.HtlcTracer.SettleSent(inKey, true)
 
 		// Send a settle event notification to htlcNotifier.
 		l.cfg.HtlcNotifier.NotifySettleEvent(
@@ -1624,6 +1634,7 @@ func (l *channelLink) handleDownstreamPkt(pkt *htlcPacket) {
 		// We send the HTLC message to the peer which initially created
 		// the HTLC.
 		l.cfg.Peer.SendMessage(false, htlc)
+		l.cfg.HtlcTracer.SettleSent(inKey, false)
 
 		// If the packet does not have a link failure set, it failed
 		// further down the route so we notify a forwarding failure.
@@ -1742,6 +1753,11 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		l.log.Tracef("receive upstream htlc with payment hash(%x), "+
 			"assigning index: %v", msg.PaymentHash[:], index)
 
+		l.cfg.HtlcTracer.AddReceived(CircuitKey{
+			ChanID: l.ShortChanID(),
+			HtlcID: index,
+		})
+
 	case *lnwire.UpdateFulfillHTLC:
 		pre := msg.PaymentPreimage
 		idx := msg.ID
@@ -1792,6 +1808,11 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		// from the remote peer.
 		l.uncommittedPreimages = append(l.uncommittedPreimages, pre)
 
+		l.cfg.HtlcTracer.SettleReceived(CircuitKey{
+			ChanID: l.ShortChanID(),
+			HtlcID: idx,
+		})
+
 		// Pipeline this settle, send it to the switch.
 		go l.forwardBatch(false, settlePacket)
 
@@ -1849,6 +1870,11 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 			return
 		}
 
+		l.cfg.HtlcTracer.SettleReceived(CircuitKey{
+			ChanID: l.ShortChanID(),
+			HtlcID: msg.ID,
+		})
+
 	case *lnwire.UpdateFailHTLC:
 		idx := msg.ID
 		err := l.channel.ReceiveFailHTLC(idx, msg.Reason[:])
@@ -1858,6 +1884,11 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 			return
 		}
 
+		l.cfg.HtlcTracer.SettleReceived(CircuitKey{
+			ChanID: l.ShortChanID(),
+			HtlcID: idx,
+		})
+
 	case *lnwire.CommitSig:
 		// Since we may have learned new preimages for the first time,
 		// we'll add them to our preimage cache. By doing this, we
@@ -2481,6 +2512,16 @@ func (l *channelLink) UpdateForwardingPolicy(newPolicy ForwardingPolicy) {
 	l.cfg.FwrdingPolicy = newPolicy
 }
 
+// ForwardingPolicy returns the current forwarding policy of the link.
+//
+// NOTE: Part of the ChannelLink interface.
+func (l *channelLink) ForwardingPolicy() ForwardingPolicy {
+	l.RLock()
+	defer l.RUnlock()
+
+	return l.cfg.FwrdingPolicy
+}
+
 // CheckHtlcForward should return a nil error if the passed HTLC details
 // satisfy the current forwarding policy fo the target link. Otherwise,
 // a LinkError with a valid protocol failure message should be returned
@@ -2665,6 +2706,22 @@ func (l *channelLink) Stats() (uint64, lnwire.MilliSatoshi, lnwire.MilliSatoshi)
 		snapshot.TotalMSatReceived
 }
 
+// NumPendingHtlcs returns the number of HTLCs that are on either of the
+// commitments of the channel.
+//
+// NOTE: Part of the ChannelLink interface.
+func (l *channelLink) NumPendingHtlcs() int {
+	return l.channel.NumPendingHtlcs()
+}
+
+// LiquiditySnapshot returns a breakdown of the liquidity of the channel at
+// this instant.
+//
+// NOTE: Part of the ChannelLink interface.
+func (l *channelLink) LiquiditySnapshot() *lnwallet.LiquiditySnapshot {
+	return l.channel.LiquiditySnapshot()
+}
+
 // String returns the string representation of channel link.
 //
 // NOTE: Part of the ChannelLink interface.
@@ -3145,6 +3202,8 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 		}
 	}
 
+	l.traceForwardDecision(lockedInHtlcs, switchPackets)
+
 	if len(switchPackets) == 0 {
 		return
 	}
@@ -3162,6 +3221,31 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 	l.forwardBatch(replay, switchPackets...)
 }
 
+// traceForwardDecision records the forwarding decision of the locked in adds
+// with the HTLC tracer, if any.
+func (l *channelLink) traceForwardDecision(
+	lockedInHtlcs []*lnwallet.PaymentDescriptor,
+	switchPackets []*htlcPacket) {
+
+	if l.cfg.HtlcTracer == nil {
+		return
+	}
+
+	inKeys := make([]CircuitKey, 0, len(lockedInHtlcs))
+	for _, pd := range lockedInHtlcs {
+		if pd.EntryType != lnwallet.Add {
+			continue
+		}
+
+		inKeys = append(inKeys, CircuitKey{
+			ChanID: l.ShortChanID(),
+			HtlcID: pd.HtlcIndex,
+		})
+	}
+
+	l.cfg.HtlcTracer.ForwardDecision(inKeys, switchPackets)
+}
+
 // processExitHop handles an htlc for which this link is the exit hop. It
 // returns a boolean indicating whether the commitment tx needs an update.
 func (l *channelLink) processExitHop(pd *lnwallet.PaymentDescriptor,
@@ -106,6 +106,12 @@ func (e LinkFailureError) Error() string {
 	}
 }
 
+// RemoteUnresponsive returns true if the link failed because the remote peer
+// didn't respond to our channel updates in time.
+func (e LinkFailureError) RemoteUnresponsive() bool {
+	return e.code == ErrRemoteUnresponsive
+}
+
 // ShouldSendToPeer indicates whether we should send an error to the peer if
 // the link fails with this LinkFailureError.
 func (e LinkFailureError) ShouldSendToPeer() bool {
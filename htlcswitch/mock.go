@@ -29,6 +29,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnpeer"
 	"github.com/lightningnetwork/lnd/lntest/mock"
 	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/ticker"
@@ -702,6 +703,14 @@ type mockChannelLink struct {
 
 	checkHtlcForwardResult *LinkError
 
+	bandwidth lnwire.MilliSatoshi
+
+	liquidity *lnwallet.LiquiditySnapshot
+
+	numPendingHtlcs int32
+
+	policy ForwardingPolicy
+
 	failAliasUpdate func(sid lnwire.ShortChannelID,
 		incoming bool) *lnwire.ChannelUpdate
 
@@ -772,6 +781,7 @@ func newMockChannelLink(htlcSwitch *Switch, chanID lnwire.ChannelID,
 		optionFeature: optionFeature,
 		aliases:       aliases,
 		confirmedZC:   realConfirmed,
+		bandwidth:     99999999,
 	}
 }
 
@@ -805,6 +815,9 @@ func (f *mockChannelLink) HandleChannelUpdate(lnwire.Message) {
 
 func (f *mockChannelLink) UpdateForwardingPolicy(_ ForwardingPolicy) {
 }
+func (f *mockChannelLink) ForwardingPolicy() ForwardingPolicy {
+	return f.policy
+}
 func (f *mockChannelLink) CheckHtlcForward([32]byte, lnwire.MilliSatoshi,
 	lnwire.MilliSatoshi, uint32, uint32, uint32,
 	lnwire.ShortChannelID) *LinkError {
@@ -823,6 +836,18 @@ func (f *mockChannelLink) Stats() (uint64, lnwire.MilliSatoshi, lnwire.MilliSato
 	return 0, 0, 0
 }
 
+func (f *mockChannelLink) NumPendingHtlcs() int {
+	return int(atomic.LoadInt32(&f.numPendingHtlcs))
+}
+
+func (f *mockChannelLink) LiquiditySnapshot() *lnwallet.LiquiditySnapshot {
+	if f.liquidity != nil {
+		return f.liquidity
+	}
+
+	return &lnwallet.LiquiditySnapshot{}
+}
+
 func (f *mockChannelLink) AttachMailBox(mailBox MailBox) {
 	f.mailBox = mailBox
 	f.packets = mailBox.PacketOutBox()
@@ -863,7 +888,7 @@ func (f *mockChannelLink) Start() error {
 
 func (f *mockChannelLink) ChanID() lnwire.ChannelID                     { return f.chanID }
 func (f *mockChannelLink) ShortChanID() lnwire.ShortChannelID           { return f.shortChanID }
-func (f *mockChannelLink) Bandwidth() lnwire.MilliSatoshi               { return 99999999 }
+func (f *mockChannelLink) Bandwidth() lnwire.MilliSatoshi               { return f.bandwidth }
 func (f *mockChannelLink) Peer() lnpeer.Peer                            { return f.peer }
 func (f *mockChannelLink) ChannelPoint() *wire.OutPoint                 { return &wire.OutPoint{} }
 func (f *mockChannelLink) Stop()                                        {}
@@ -0,0 +1,210 @@
+package htlcswitch
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// DefaultSimulatedExpiryDelta is the number of blocks after the current
+// height at which the outgoing HTLC of a simulated forward expires if no
+// expiry is given.
+const DefaultSimulatedExpiryDelta = 40
+
+// SimulatedForward describes an HTLC forward that is checked against the
+// current liquidity and forwarding policies of our links, without adding an
+// HTLC to any channel.
+type SimulatedForward struct {
+	// IncomingChanID is the short channel ID of the channel the HTLC would
+	// arrive on.
+	IncomingChanID lnwire.ShortChannelID
+
+	// OutgoingChanID is the short channel ID of the channel the HTLC
+	// would be forwarded on.
+	OutgoingChanID lnwire.ShortChannelID
+
+	// AmtIn is the amount of the incoming HTLC. If zero, the amount to
+	// forward plus the fee required by the policy of the outgoing link is
+	// used.
+	AmtIn lnwire.MilliSatoshi
+
+	// AmtOut is the amount of the outgoing HTLC.
+	AmtOut lnwire.MilliSatoshi
+
+	// IncomingExpiry is the absolute expiry height of the incoming HTLC.
+	// If zero, the outgoing expiry plus the time lock delta of the policy
+	// of the outgoing link is used.
+	IncomingExpiry uint32
+
+	// OutgoingExpiry is the absolute expiry height of the outgoing HTLC.
+	// If zero, it is set DefaultSimulatedExpiryDelta blocks after the
+	// current height.
+	OutgoingExpiry uint32
+}
+
+// SimulatedForwardResult is the outcome of a simulated forward.
+type SimulatedForwardResult struct {
+	// Forward is the simulated forward, with its defaults filled in.
+	Forward SimulatedForward
+
+	// Err is the reason the forward would fail, nil if it would succeed.
+	Err error
+
+	// OutgoingBandwidth is the bandwidth of the outgoing link that is left
+	// after this forward and all previous successful forwards over the
+	// same link.
+	OutgoingBandwidth lnwire.MilliSatoshi
+}
+
+// SimulateForwards checks whether the given forwards would succeed against
+// the current liquidity and forwarding policies of our links. The forwards
+// are simulated in order, and every forward that would succeed reserves its
+// amounts on the incoming and outgoing link for the forwards that follow it,
+// so that a stream of forwards can be used for capacity planning. No HTLCs
+// are added to any channel.
+//
+// NOTE: Unlike real forwards, simulated forwards are only checked against the
+// requested outgoing link, rather than all links to the same peer. The dust
+// exposure of the links is evaluated for every forward on its own.
+func (s *Switch) SimulateForwards(
+	forwards []SimulatedForward) []*SimulatedForwardResult {
+
+	var (
+		height  = s.BestHeight()
+		results = make([]*SimulatedForwardResult, 0, len(forwards))
+		usedIn  = make(map[lnwire.ShortChannelID]lnwire.MilliSatoshi)
+		usedOut = make(map[lnwire.ShortChannelID]lnwire.MilliSatoshi)
+	)
+
+	for _, fwd := range forwards {
+		fwd := fwd
+		result := &SimulatedForwardResult{}
+		err := s.simulateForward(&fwd, result, height, usedIn, usedOut)
+		if err != nil {
+			log.Debugf("Simulated forward from %v to %v of %v would "+
+				"fail: %v", fwd.IncomingChanID,
+				fwd.OutgoingChanID, fwd.AmtOut, err)
+
+			result.Err = err
+		}
+
+		result.Forward = fwd
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// simulateForward checks a single simulated forward, filling in its defaults,
+// and reserves its amounts in usedIn and usedOut if it would succeed.
+func (s *Switch) simulateForward(fwd *SimulatedForward,
+	result *SimulatedForwardResult, height uint32,
+	usedIn, usedOut map[lnwire.ShortChannelID]lnwire.MilliSatoshi) error {
+
+	// The payment hash is only used for logging by the links.
+	var paymentHash lntypes.Hash
+
+	outgoingLink, err := s.GetLinkByShortID(fwd.OutgoingChanID)
+	if err != nil {
+		return NewLinkError(&lnwire.FailUnknownNextPeer{})
+	}
+
+	policy := outgoingLink.ForwardingPolicy()
+	if fwd.OutgoingExpiry == 0 {
+		fwd.OutgoingExpiry = height + DefaultSimulatedExpiryDelta
+	}
+	if fwd.IncomingExpiry == 0 {
+		fwd.IncomingExpiry = fwd.OutgoingExpiry + policy.TimeLockDelta
+	}
+	if fwd.AmtIn == 0 {
+		fwd.AmtIn = fwd.AmtOut + ExpectedFee(policy, fwd.AmtOut)
+	}
+
+	// Links are keyed by their short channel ID in the reservations, so
+	// that forwards using an alias of a channel share its liquidity.
+	outgoingID := outgoingLink.ShortChanID()
+	bandwidth := outgoingLink.Bandwidth()
+	if bandwidth > usedOut[outgoingID] {
+		result.OutgoingBandwidth = bandwidth - usedOut[outgoingID]
+	}
+
+	if s.cfg.RejectHTLC {
+		return NewDetailedLinkError(
+			&lnwire.FailChannelDisabled{},
+			OutgoingFailureForwardsDisabled,
+		)
+	}
+
+	if s.IsDraining() {
+		return NewDetailedLinkError(
+			&lnwire.FailTemporaryChannelFailure{},
+			OutgoingFailureSwitchDraining,
+		)
+	}
+
+	linkErr := s.checkCircularForward(
+		fwd.IncomingChanID, fwd.OutgoingChanID,
+		s.cfg.AllowCircularRoute, paymentHash,
+	)
+	if linkErr != nil {
+		return linkErr
+	}
+
+	incomingLink, err := s.GetLinkByShortID(fwd.IncomingChanID)
+	if err != nil {
+		return fmt.Errorf("incoming channel %v: %w", fwd.IncomingChanID,
+			err)
+	}
+
+	// The remote party of the incoming channel must be able to add the
+	// incoming HTLC on top of the ones reserved by previous forwards.
+	incomingID := incomingLink.ShortChanID()
+	receivable := incomingLink.LiquiditySnapshot().Receivable
+	if fwd.AmtIn+usedIn[incomingID] > receivable {
+		return fmt.Errorf("incoming channel %v can receive at most "+
+			"%v, previous forwards reserved %v", fwd.IncomingChanID,
+			receivable, usedIn[incomingID])
+	}
+
+	if !outgoingLink.EligibleToForward() {
+		return NewDetailedLinkError(
+			&lnwire.FailUnknownNextPeer{},
+			OutgoingFailureLinkNotEligible,
+		)
+	}
+
+	linkErr = outgoingLink.CheckHtlcForward(
+		paymentHash, fwd.AmtIn, fwd.AmtOut, fwd.IncomingExpiry,
+		fwd.OutgoingExpiry, height, fwd.OutgoingChanID,
+	)
+	if linkErr != nil {
+		return linkErr
+	}
+
+	if fwd.AmtOut > result.OutgoingBandwidth {
+		return NewDetailedLinkError(
+			&lnwire.FailTemporaryChannelFailure{},
+			OutgoingFailureInsufficientBalance,
+		)
+	}
+
+	if err := outgoingLink.MayAddOutgoingHtlc(fwd.AmtOut); err != nil {
+		return NewDetailedLinkError(
+			&lnwire.FailTemporaryChannelFailure{},
+			OutgoingFailureInsufficientBalance,
+		)
+	}
+
+	if s.evaluateDustThreshold(incomingLink, fwd.AmtIn, true) ||
+		s.evaluateDustThreshold(outgoingLink, fwd.AmtOut, false) {
+
+		return NewLinkError(&lnwire.FailTemporaryChannelFailure{})
+	}
+
+	usedIn[incomingID] += fwd.AmtIn
+	usedOut[outgoingID] += fwd.AmtOut
+	result.OutgoingBandwidth -= fwd.AmtOut
+
+	return nil
+}
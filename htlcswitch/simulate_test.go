@@ -0,0 +1,130 @@
+package htlcswitch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSwitchSimulateForwards tests that simulated forwards are checked against
+// the liquidity and policies of the links, and that successful forwards
+// reserve liquidity for the ones that follow them.
+func TestSwitchSimulateForwards(t *testing.T) {
+	t.Parallel()
+
+	alicePeer, err := newMockServer(
+		t, "alice", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err)
+	bobPeer, err := newMockServer(
+		t, "bob", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err)
+
+	s, err := initSwitchWithDB(testStartingHeight, nil)
+	require.NoError(t, err)
+	require.NoError(t, s.Start())
+	defer func() {
+		require.NoError(t, s.Stop())
+	}()
+
+	chanID1, chanID2, aliceChanID, bobChanID := genIDs()
+
+	// Alice's channel is the incoming one, and Bob's the outgoing one.
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, emptyScid, alicePeer, true, false,
+		false, false,
+	)
+	aliceChannelLink.liquidity = &lnwallet.LiquiditySnapshot{
+		Receivable: 10000,
+	}
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, emptyScid, bobPeer, true, false, false,
+		false,
+	)
+	bobChannelLink.bandwidth = 8000
+	bobChannelLink.policy = ForwardingPolicy{
+		BaseFee:       100,
+		TimeLockDelta: 40,
+	}
+	require.NoError(t, s.AddLink(aliceChannelLink))
+	require.NoError(t, s.AddLink(bobChannelLink))
+
+	unknownChanID := lnwire.NewShortChanIDFromInt(99)
+	forwards := []SimulatedForward{{
+		// The defaults are filled in from Bob's policy.
+		IncomingChanID: aliceChanID,
+		OutgoingChanID: bobChanID,
+		AmtOut:         5000,
+	}, {
+		// Only 3000 msat are left in Bob's channel.
+		IncomingChanID: aliceChanID,
+		OutgoingChanID: bobChanID,
+		AmtOut:         4000,
+	}, {
+		// The failed forward didn't reserve any liquidity.
+		IncomingChanID: aliceChanID,
+		OutgoingChanID: bobChanID,
+		AmtOut:         3000,
+	}, {
+		// Alice's channel can only receive another 1800 msat.
+		IncomingChanID: aliceChanID,
+		OutgoingChanID: bobChanID,
+		AmtIn:          2000,
+	}, {
+		IncomingChanID: aliceChanID,
+		OutgoingChanID: unknownChanID,
+		AmtOut:         1000,
+	}, {
+		IncomingChanID: bobChanID,
+		OutgoingChanID: bobChanID,
+		AmtOut:         1000,
+	}}
+
+	results := s.SimulateForwards(forwards)
+	require.Len(t, results, len(forwards))
+
+	// The first forward succeeds with the defaults filled in.
+	outgoingExpiry := uint32(testStartingHeight) +
+		DefaultSimulatedExpiryDelta
+	require.NoError(t, results[0].Err)
+	require.Equal(t, SimulatedForward{
+		IncomingChanID: aliceChanID,
+		OutgoingChanID: bobChanID,
+		AmtIn:          5100,
+		AmtOut:         5000,
+		IncomingExpiry: outgoingExpiry + 40,
+		OutgoingExpiry: outgoingExpiry,
+	}, results[0].Forward)
+	require.Equal(t, lnwire.MilliSatoshi(3000), results[0].OutgoingBandwidth)
+
+	requireDetail := func(result *SimulatedForwardResult,
+		detail FailureDetail) {
+
+		var linkErr *LinkError
+		require.True(t, errors.As(result.Err, &linkErr))
+		require.Equal(t, detail, linkErr.FailureDetail)
+	}
+
+	requireDetail(results[1], OutgoingFailureInsufficientBalance)
+	require.Equal(t, lnwire.MilliSatoshi(3000), results[1].OutgoingBandwidth)
+
+	require.NoError(t, results[2].Err)
+	require.Equal(t, lnwire.MilliSatoshi(0), results[2].OutgoingBandwidth)
+
+	require.ErrorContains(t, results[3].Err, "can receive at most")
+
+	var linkErr *LinkError
+	require.True(t, errors.As(results[4].Err, &linkErr))
+	require.IsType(t, &lnwire.FailUnknownNextPeer{}, linkErr.WireMessage())
+
+	requireDetail(results[5], OutgoingFailureCircularRoute)
+
+	// The simulation didn't touch the liquidity of the links, so the same
+	// forward succeeds again.
+	results = s.SimulateForwards(forwards[:1])
+	require.NoError(t, results[0].Err)
+}
@@ -231,6 +231,10 @@ type Switch struct {
 	started  int32 // To be used atomically.
 	shutdown int32 // To be used atomically.
 
+	// draining is set to 1 while the switch is in drain mode, in which it
+	// doesn't accept any new forwards. To be used atomically.
+	draining int32
+
 	// bestHeight is the best known height of the main chain. The links will
 	// be used this information to govern decisions based on HTLC timeouts.
 	// This will be retrieved by the registered links atomically.
@@ -1105,6 +1109,19 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 			return s.failAddPacket(packet, failure)
 		}
 
+		// If the switch is being drained, we fail all new forwards
+		// with a temporary failure, so that the sender can retry the
+		// payment along another route while the HTLCs in flight
+		// resolve.
+		if s.IsDraining() {
+			failure := NewDetailedLinkError(
+				&lnwire.FailTemporaryChannelFailure{},
+				OutgoingFailureSwitchDraining,
+			)
+
+			return s.failAddPacket(packet, failure)
+		}
+
 		// Before we attempt to find a non-strict forwarding path for
 		// this htlc, check whether the htlc is being routed over the
 		// same incoming and outgoing channel. If our node does not
@@ -2607,9 +2624,54 @@ func (s *Switch) removeLink(chanID lnwire.ChannelID) ChannelLink {
 		}
 	}
 
+	// Finally, remove any alias mappings that point to this link so that
+	// none of its aliases resolve to a stale entry.
+	s.removeAliasMappings(link)
+
 	return link
 }
 
+// removeAliasMappings removes the aliasToReal and baseIndex entries of the
+// given link. Entries that were since taken over by another link are left
+// untouched.
+//
+// NOTE: This MUST be called with the indexMtx held.
+func (s *Switch) removeAliasMappings(link ChannelLink) {
+	if !link.negotiatedAliasFeature() && !link.isZeroConf() {
+		return
+	}
+
+	linkScid := link.ShortChanID()
+	removeBase := func(scid lnwire.ShortChannelID) {
+		if base, ok := s.baseIndex[scid]; ok && base == linkScid {
+			delete(s.baseIndex, scid)
+		}
+	}
+
+	var realScid lnwire.ShortChannelID
+	switch {
+	case link.isZeroConf() && link.zeroConfConfirmed():
+		realScid = link.confirmedScid()
+
+	case !link.isZeroConf():
+		realScid = linkScid
+	}
+
+	for _, alias := range link.getAliases() {
+		removeBase(alias)
+
+		mapped, ok := s.aliasToReal[alias]
+		if ok && mapped == realScid {
+			delete(s.aliasToReal, alias)
+		}
+	}
+
+	removeBase(linkScid)
+	if realScid != (lnwire.ShortChannelID{}) {
+		removeBase(realScid)
+	}
+}
+
 // UpdateShortChanID locates the link with the passed-in chanID and updates the
 // underlying channel state. This is only used in zero-conf channels to allow
 // the confirmed SCID to be updated.
@@ -2741,6 +2803,52 @@ func (s *Switch) BestHeight() uint32 {
 	return atomic.LoadUint32(&s.bestHeight)
 }
 
+// SetDraining puts the switch into or takes it out of drain mode. While the
+// switch is draining, all new forwards are failed back with a temporary
+// channel failure, while the HTLCs that are already in flight are resolved as
+// usual. This allows the node to be restarted without any HTLCs in flight
+// once NumInFlight drops to zero. Drain mode isn't persisted, so it's left
+// once the node restarts.
+func (s *Switch) SetDraining(draining bool) {
+	var val int32
+	if draining {
+		val = 1
+	}
+
+	old := atomic.SwapInt32(&s.draining, val)
+	switch {
+	case old == 0 && draining:
+		log.Infof("Switch entered drain mode with %d HTLC(s) in "+
+			"flight", s.NumInFlight())
+
+	case old == 1 && !draining:
+		log.Infof("Switch left drain mode")
+	}
+}
+
+// IsDraining returns true if the switch is in drain mode.
+func (s *Switch) IsDraining() bool {
+	return atomic.LoadInt32(&s.draining) == 1
+}
+
+// NumInFlight returns the number of HTLCs in flight through the switch, which
+// is the number of HTLCs on the commitments of the links. Unlike the payment
+// circuits, this includes the HTLCs that are held by the invoice registry and
+// the HTLCs whose circuits were already torn down while they're still
+// awaiting removal from the commitments. A forwarded HTLC is counted on both
+// its incoming and its outgoing channel.
+func (s *Switch) NumInFlight() int {
+	s.indexMtx.RLock()
+	defer s.indexMtx.RUnlock()
+
+	var numInFlight int
+	for _, link := range s.linkIndex {
+		numInFlight += link.NumPendingHtlcs()
+	}
+
+	return numInFlight
+}
+
 // evaluateDustThreshold takes in a ChannelLink, HTLC amount, and a boolean to
 // determine whether the default dust threshold has been exceeded. This
 // heuristic takes into account the trimmed-to-dust mechanism. The sum of the
@@ -2973,18 +3081,21 @@ func (s *Switch) failAliasUpdate(scid lnwire.ShortChannelID,
 func (s *Switch) AddAliasForLink(chanID lnwire.ChannelID,
 	alias lnwire.ShortChannelID) error {
 
+	// The index lock is held for the duration of the call since the
+	// alias maps below are modified.
+	s.indexMtx.Lock()
+	defer s.indexMtx.Unlock()
+
 	// Fetch the link so that we can update the underlying channel's set of
 	// aliases.
-	s.indexMtx.RLock()
 	link, err := s.getLink(chanID)
-	s.indexMtx.RUnlock()
 	if err != nil {
 		return err
 	}
 
-	// If the link is a channel where the option-scid-alias feature bit was
-	// not negotiated, we'll return an error.
-	if !link.negotiatedAliasFeature() {
+	// If the link is neither a zero-conf channel nor a channel where the
+	// option-scid-alias feature bit was negotiated, we'll return an error.
+	if !link.negotiatedAliasFeature() && !link.isZeroConf() {
 		return fmt.Errorf("attempted to update non-alias channel")
 	}
 
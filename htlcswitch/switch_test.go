@@ -8,6 +8,7 @@ import (
 	"io"
 	mrand "math/rand"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -864,6 +865,88 @@ func TestSwitchUpdateScid(t *testing.T) {
 	s.indexMtx.RUnlock()
 }
 
+// TestSwitchAliasMappings asserts that aliases added to a live link are
+// reflected in the Switch's alias maps and that the maps are pruned once the
+// link is removed.
+func TestSwitchAliasMappings(t *testing.T) {
+	t.Parallel()
+
+	peer, err := newMockServer(
+		t, "alice", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err, "unable to create alice server")
+
+	s, err := initSwitchWithDB(testStartingHeight, nil)
+	require.NoError(t, err)
+	err = s.Start()
+	require.NoError(t, err)
+	defer func() { _ = s.Stop() }()
+
+	chanID, chanID2, _, _ := genIDs()
+
+	alias := lnwire.ShortChannelID{
+		BlockHeight: 16_000_000,
+		TxIndex:     0,
+		TxPosition:  0,
+	}
+	alias2 := alias
+	alias2.TxPosition = 1
+
+	realScid := lnwire.ShortChannelID{
+		BlockHeight: 500000,
+		TxIndex:     0,
+		TxPosition:  0,
+	}
+
+	// Add a confirmed zero-conf link with a single alias.
+	link := newMockChannelLink(
+		s, chanID, alias, emptyScid, peer, true, false, true, false,
+	)
+	link.realScid = realScid
+	link.confirmedZC = true
+
+	err = s.AddLink(link)
+	require.NoError(t, err)
+
+	// A second alias added to the live link should resolve to both the
+	// link and the confirmed SCID.
+	link.addAlias(alias2)
+	err = s.AddAliasForLink(chanID, alias2)
+	require.NoError(t, err)
+
+	for _, scid := range []lnwire.ShortChannelID{alias2, realScid} {
+		found, err := s.GetLinkByShortID(scid)
+		require.NoError(t, err)
+		require.Equal(t, chanID, found.ChanID())
+	}
+
+	s.indexMtx.RLock()
+	realMapping, ok := s.aliasToReal[alias2]
+	require.True(t, ok)
+	require.Equal(t, realScid, realMapping)
+	s.indexMtx.RUnlock()
+
+	// Aliases can only be added to option-scid-alias channels.
+	link2 := newMockChannelLink(
+		s, chanID2, realScid, emptyScid, peer, true, false, false,
+		false,
+	)
+	err = s.AddLink(link2)
+	require.NoError(t, err)
+
+	err = s.AddAliasForLink(chanID2, alias2)
+	require.Error(t, err)
+
+	// Once the alias link is removed, none of its SCIDs should have
+	// entries in the alias maps anymore.
+	s.RemoveLink(chanID)
+
+	s.indexMtx.RLock()
+	require.Empty(t, s.aliasToReal)
+	require.Empty(t, s.baseIndex)
+	s.indexMtx.RUnlock()
+}
+
 // TestSwitchForward checks the ability of htlc switch to forward add/settle
 // requests.
 func TestSwitchForward(t *testing.T) {
@@ -979,6 +1062,143 @@ func TestSwitchForward(t *testing.T) {
 	}
 }
 
+// TestSwitchDrain checks that a switch in drain mode fails new forwards with a
+// temporary channel failure, while the HTLCs in flight are still resolved.
+func TestSwitchDrain(t *testing.T) {
+	t.Parallel()
+
+	alicePeer, err := newMockServer(
+		t, "alice", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err, "unable to create alice server")
+	bobPeer, err := newMockServer(
+		t, "bob", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err, "unable to create bob server")
+
+	s, err := initSwitchWithDB(testStartingHeight, nil)
+	require.NoError(t, err, "unable to init switch")
+	require.NoError(t, s.Start(), "unable to start switch")
+	defer func() {
+		_ = s.Stop()
+	}()
+
+	chanID1, chanID2, aliceChanID, bobChanID := genIDs()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, emptyScid, alicePeer, true, false,
+		false, false,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, emptyScid, bobPeer, true, false, false,
+		false,
+	)
+	require.NoError(t, s.AddLink(aliceChannelLink))
+	require.NoError(t, s.AddLink(bobChannelLink))
+
+	newAddPacket := func(htlcID uint64) *htlcPacket {
+		preimage, err := genPreimage()
+		require.NoError(t, err, "unable to generate preimage")
+
+		return &htlcPacket{
+			incomingChanID: aliceChannelLink.ShortChanID(),
+			incomingHTLCID: htlcID,
+			outgoingChanID: bobChannelLink.ShortChanID(),
+			obfuscator:     NewMockObfuscator(),
+			htlc: &lnwire.UpdateAddHTLC{
+				PaymentHash: sha256.Sum256(preimage[:]),
+				Amount:      1,
+			},
+		}
+	}
+
+	// Forward a first HTLC from Alice to Bob before the switch is put
+	// into drain mode.
+	packet := newAddPacket(0)
+	require.NoError(t, s.ForwardPackets(nil, packet))
+
+	select {
+	case <-bobChannelLink.packets:
+		require.NoError(t, bobChannelLink.completeCircuit(packet))
+	case <-time.After(time.Second):
+		t.Fatal("request was not propagated to destination")
+	}
+
+	// The HTLCs in flight are counted from the commitments of the links,
+	// where the forwarded HTLC is locked in on both channels.
+	atomic.StoreInt32(&aliceChannelLink.numPendingHtlcs, 1)
+	atomic.StoreInt32(&bobChannelLink.numPendingHtlcs, 1)
+	require.Equal(t, 2, s.NumInFlight())
+
+	s.SetDraining(true)
+	require.True(t, s.IsDraining())
+
+	// A new forward must now be failed back to Alice with a temporary
+	// channel failure.
+	require.NoError(t, s.ForwardPackets(nil, newAddPacket(1)))
+
+	select {
+	case pkt := <-aliceChannelLink.packets:
+		require.IsType(t, &lnwire.UpdateFailHTLC{}, pkt.htlc)
+		require.IsType(
+			t, &lnwire.FailTemporaryChannelFailure{},
+			pkt.linkFailure.WireMessage(),
+		)
+		require.Equal(
+			t, OutgoingFailureSwitchDraining,
+			pkt.linkFailure.FailureDetail,
+		)
+		require.NoError(t, aliceChannelLink.deleteCircuit(pkt))
+
+	case <-time.After(time.Second):
+		t.Fatal("failure was not propagated to source")
+	}
+
+	select {
+	case <-bobChannelLink.packets:
+		t.Fatal("htlc was forwarded while draining")
+	default:
+	}
+
+	// The HTLC that was already in flight must still be resolved.
+	require.NoError(t, s.ForwardPackets(nil, &htlcPacket{
+		outgoingChanID: bobChannelLink.ShortChanID(),
+		outgoingHTLCID: 0,
+		amount:         1,
+		htlc:           &lnwire.UpdateFulfillHTLC{},
+	}))
+
+	select {
+	case pkt := <-aliceChannelLink.packets:
+		require.IsType(t, &lnwire.UpdateFulfillHTLC{}, pkt.htlc)
+		require.NoError(t, aliceChannelLink.deleteCircuit(pkt))
+
+	case <-time.After(time.Second):
+		t.Fatal("settle was not propagated to source")
+	}
+
+	// Even though the circuit is closed, the HTLC is in flight until it's
+	// removed from the commitments.
+	require.Equal(t, 2, s.NumInFlight())
+
+	atomic.StoreInt32(&aliceChannelLink.numPendingHtlcs, 0)
+	atomic.StoreInt32(&bobChannelLink.numPendingHtlcs, 0)
+	require.Zero(t, s.NumInFlight())
+
+	// Once drain mode is left, forwards are accepted again.
+	s.SetDraining(false)
+	require.False(t, s.IsDraining())
+
+	packet = newAddPacket(2)
+	require.NoError(t, s.ForwardPackets(nil, packet))
+
+	select {
+	case <-bobChannelLink.packets:
+	case <-time.After(time.Second):
+		t.Fatal("request was not propagated to destination")
+	}
+}
+
 func TestSwitchForwardFailAfterFullAdd(t *testing.T) {
 	t.Parallel()
 
@@ -4096,6 +4316,145 @@ func TestSwitchHoldForward(t *testing.T) {
 	}
 }
 
+// assertNoIntercept asserts that no packet is offered to the interceptor.
+func assertNoIntercept(t *testing.T, interceptor *mockForwardInterceptor) {
+	select {
+	case <-interceptor.interceptedChan:
+		t.Fatal("unexpected interception")
+
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestSwitchHoldForwardFailover tests that held forwards are only offered to
+// the interceptor with the lowest priority value, and that they are offered to
+// the next interceptor once it disconnects or exceeds its health timeout.
+func TestSwitchHoldForwardFailover(t *testing.T) {
+	t.Parallel()
+
+	chanID1, chanID2, aliceChanID, bobChanID := genIDs()
+
+	alicePeer, err := newMockServer(
+		t, "alice", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err, "unable to create alice server")
+	bobPeer, err := newMockServer(
+		t, "bob", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err, "unable to create bob server")
+
+	cdb, err := channeldb.Open(t.TempDir())
+	require.NoError(t, err, "unable to open channeldb")
+
+	s, err := initSwitchWithDB(testStartingHeight, cdb)
+	require.NoError(t, err, "unable to init switch")
+	require.NoError(t, s.Start())
+	defer func() {
+		require.NoError(t, s.Stop())
+	}()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, emptyScid, alicePeer, true, false,
+		false, false,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, emptyScid, bobPeer, true, false, false,
+		false,
+	)
+	require.NoError(t, s.AddLink(aliceChannelLink))
+	require.NoError(t, s.AddLink(bobChannelLink))
+
+	preimage := [sha256.Size]byte{1}
+	rhash := sha256.Sum256(preimage[:])
+
+	const cltvRejectDelta = 13
+
+	packet := &htlcPacket{
+		incomingChanID:  aliceChannelLink.ShortChanID(),
+		incomingHTLCID:  1,
+		incomingTimeout: testStartingHeight + cltvRejectDelta + 1,
+		outgoingChanID:  bobChannelLink.ShortChanID(),
+		obfuscator:      NewMockObfuscator(),
+		htlc: &lnwire.UpdateAddHTLC{
+			PaymentHash: rhash,
+			Amount:      1,
+		},
+	}
+
+	primary := &mockForwardInterceptor{
+		t:               t,
+		interceptedChan: make(chan InterceptedPacket),
+	}
+	standby := &mockForwardInterceptor{
+		t:               t,
+		interceptedChan: make(chan InterceptedPacket),
+	}
+
+	switchForwardInterceptor := NewInterceptableSwitch(
+		s, cltvRejectDelta, true,
+	)
+	require.NoError(t, switchForwardInterceptor.Start())
+	defer func() {
+		require.NoError(t, switchForwardInterceptor.Stop())
+	}()
+
+	// Register the standby first, to assert that the priority rather than
+	// the registration order decides which interceptor is active.
+	standbyID := switchForwardInterceptor.AddInterceptor(
+		standby.InterceptForwardHtlc,
+		InterceptorOptions{Priority: 1},
+	)
+
+	unhealthy := make(chan struct{})
+	primaryID := switchForwardInterceptor.AddInterceptor(
+		primary.InterceptForwardHtlc, InterceptorOptions{},
+	)
+
+	// The forward is only offered to the primary.
+	linkQuit := make(chan struct{})
+	require.NoError(t, switchForwardInterceptor.ForwardPackets(
+		linkQuit, false, packet,
+	))
+	primary.getIntercepted()
+	assertNoIntercept(t, standby)
+
+	// Once the primary registers a health timeout it exceeds, it is
+	// unregistered and the held forward is offered to the standby.
+	switchForwardInterceptor.UpdateInterceptor(
+		primaryID, InterceptorOptions{
+			HealthTimeout: 200 * time.Millisecond,
+			OnUnhealthy: func() {
+				close(unhealthy)
+			},
+		},
+	)
+
+	intercepted := standby.getIntercepted()
+	select {
+	case <-unhealthy:
+	case <-time.After(time.Second):
+		t.Fatal("primary not marked unhealthy")
+	}
+	assertNoIntercept(t, primary)
+
+	// Removing the primary, which has already been unregistered, doesn't
+	// change the active interceptor.
+	switchForwardInterceptor.RemoveInterceptor(primaryID)
+	assertNoIntercept(t, standby)
+
+	// The standby settles the forward.
+	require.NoError(t, switchForwardInterceptor.Resolve(&FwdResolution{
+		Key:      intercepted.IncomingCircuit,
+		Action:   FwdActionSettle,
+		Preimage: preimage,
+	}))
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+	assertOutgoingLinkReceive(t, aliceChannelLink, true)
+	assertNumCircuits(t, s, 0, 0)
+
+	switchForwardInterceptor.RemoveInterceptor(standbyID)
+}
+
 // TestSwitchDustForwarding tests that the switch properly fails HTLC's which
 // have incoming or outgoing links that breach their dust thresholds.
 func TestSwitchDustForwarding(t *testing.T) {
@@ -0,0 +1,101 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr/musig2"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+var (
+	// ErrHSMTweakUnsupported is returned when a signature with a tweaked
+	// key is requested for a key that is kept in a hardware security
+	// module.
+	ErrHSMTweakUnsupported = errors.New("tweaked keys aren't supported " +
+		"for keys kept in the HSM")
+)
+
+// HSMSigner is a Signer that creates the signatures for keys that are kept in
+// the hardware security module of an HSMKeyRing through the key ring, and
+// passes all other requests on to the wrapped signer. Only ECDSA signatures
+// of segwit v0 outputs, like the funding output of a channel, can be created
+// for keys kept in the module.
+type HSMSigner struct {
+	Signer
+
+	keyRing *keychain.HSMKeyRing
+}
+
+// A compile time check to ensure HSMSigner implements the Signer interface.
+var _ Signer = (*HSMSigner)(nil)
+
+// NewHSMSigner creates a new signer that creates the signatures for keys kept
+// in the hardware security module of the passed key ring and uses the wrapped
+// signer for all other keys.
+func NewHSMSigner(signer Signer, keyRing *keychain.HSMKeyRing) *HSMSigner {
+	return &HSMSigner{
+		Signer:  signer,
+		keyRing: keyRing,
+	}
+}
+
+// SignOutputRaw generates a signature for the passed transaction according to
+// the data within the passed SignDescriptor.
+//
+// NOTE: This is part of the input.Signer interface.
+func (s *HSMSigner) SignOutputRaw(tx *wire.MsgTx,
+	signDesc *SignDescriptor) (Signature, error) {
+
+	if !s.keyRing.IsHSMKey(signDesc.KeyDesc) {
+		return s.Signer.SignOutputRaw(tx, signDesc)
+	}
+
+	switch {
+	case signDesc.SingleTweak != nil || signDesc.DoubleTweak != nil:
+		return nil, ErrHSMTweakUnsupported
+
+	case txscript.IsPayToTaproot(signDesc.Output.PkScript):
+		return nil, keychain.ErrHSMSchnorrUnsupported
+	}
+
+	sigHash, err := txscript.CalcWitnessSigHash(
+		signDesc.WitnessScript, signDesc.SigHashes, signDesc.HashType,
+		tx, signDesc.InputIndex, signDesc.Output.Value,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sigHash) != 32 {
+		return nil, fmt.Errorf("invalid sighash length %d",
+			len(sigHash))
+	}
+
+	var digest [32]byte
+	copy(digest[:], sigHash)
+
+	return s.keyRing.SignDigest(signDesc.KeyDesc.KeyLocator, digest)
+}
+
+// MuSig2CreateSession creates a new MuSig2 signing session using the local
+// key identified by the key locator. Keys kept in the hardware security
+// module can't be used for MuSig2 signing sessions.
+//
+// NOTE: This is part of the input.MuSig2Signer interface.
+func (s *HSMSigner) MuSig2CreateSession(keyLoc keychain.KeyLocator,
+	allSignerPubKeys []*btcec.PublicKey, tweaks *MuSig2Tweaks,
+	otherSignerNonces [][musig2.PubNonceSize]byte) (*MuSig2SessionInfo,
+	error) {
+
+	if s.keyRing.IsHSMKey(keychain.KeyDescriptor{KeyLocator: keyLoc}) {
+		return nil, keychain.ErrHSMSchnorrUnsupported
+	}
+
+	return s.Signer.MuSig2CreateSession(
+		keyLoc, allSignerPubKeys, tweaks, otherSignerNonces,
+	)
+}
@@ -0,0 +1,253 @@
+package invoices
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+)
+
+var (
+	// ErrHtlcNotHeld is returned when a held htlc is resolved that isn't
+	// (or no longer) held by the invoice registry.
+	ErrHtlcNotHeld = errors.New("htlc not held")
+)
+
+// HtlcHoldHook is an extension point that allows an external component to
+// hold an htlc paying to one of our invoices before the registry processes
+// it. A typical use is a receive flow through a liquidity service provider,
+// where the htlc is only released once a just-in-time (zero-conf) channel
+// has been opened, or once the fee the provider deducted from the payment
+// has been verified.
+type HtlcHoldHook interface {
+	// HoldHtlc is called for every htlc that pays to one of our invoices.
+	// If true is returned, the registry doesn't process the htlc until
+	// either Resume or Fail is called on the passed HeldHtlc. Held htlcs
+	// are not persisted, so after a restart the hook is called again for
+	// every htlc that is replayed by the links. Htlcs that expire within
+	// the hold expiry delta aren't offered to the hook, and held htlcs are
+	// failed back once they get that close to their expiry.
+	//
+	// NOTE: This method is called from the link's main loop and must not
+	// block.
+	HoldHtlc(htlc *HeldHtlc) bool
+}
+
+// HeldHtlc describes an htlc that is offered to the HtlcHoldHook.
+type HeldHtlc struct {
+	// Hash is the payment hash of the htlc.
+	Hash lntypes.Hash
+
+	// CircuitKey identifies the htlc.
+	CircuitKey channeldb.CircuitKey
+
+	// AmtPaid is the amount that is carried by the htlc.
+	AmtPaid lnwire.MilliSatoshi
+
+	// Expiry is the absolute expiry height of the htlc.
+	Expiry uint32
+
+	// CurrentHeight is the block height at which the htlc was received.
+	CurrentHeight int32
+
+	// CustomRecords are the custom records of the htlc's payload.
+	CustomRecords record.CustomSet
+
+	// MPP is the mpp record of the htlc's payload, if present.
+	MPP *record.MPP
+
+	// registry is the invoice registry that holds the htlc.
+	registry *InvoiceRegistry
+}
+
+// Resume releases the held htlc and lets the invoice registry process it.
+// The fee credit is added to the amount of the htlc when it is checked
+// against the invoice. This allows a service provider that opened a channel
+// to us on the fly to deduct its fee from the forwarded amount, without the
+// invoice being underpaid. The credit isn't part of the amount that is
+// recorded as paid to the invoice.
+func (h *HeldHtlc) Resume(feeCredit lnwire.MilliSatoshi) error {
+	return h.registry.resumeHeldHtlc(h.CircuitKey, feeCredit)
+}
+
+// Fail releases the held htlc and fails it back to the sender.
+func (h *HeldHtlc) Fail() error {
+	return h.registry.failHeldHtlc(h.CircuitKey)
+}
+
+// htlcFeeCredits maps the circuit keys of htlcs to their fee credits.
+type htlcFeeCredits = map[channeldb.CircuitKey]lnwire.MilliSatoshi
+
+// heldHtlc is the registry's internal bookkeeping for an htlc that is held
+// by the HtlcHoldHook.
+type heldHtlc struct {
+	// ctx is the update context of the htlc at the time it was held.
+	ctx invoiceUpdateCtx
+
+	// hodlChan is the channel over which the resolution of the htlc is
+	// delivered once it is released.
+	hodlChan chan<- interface{}
+}
+
+// holdHtlc offers the htlc to the configured HtlcHoldHook. If it is held,
+// the hodl channel is subscribed to the htlc and true is returned.
+func (i *InvoiceRegistry) holdHtlc(ctx *invoiceUpdateCtx,
+	hodlChan chan<- interface{}) bool {
+
+	if i.cfg.HtlcHoldHook == nil {
+		return false
+	}
+
+	// If the htlc is already held, it is replayed by the link. We only
+	// need to make sure the resolution is delivered to the new channel.
+	i.heldHtlcsMtx.Lock()
+	if _, ok := i.heldHtlcs[ctx.circuitKey]; ok {
+		i.heldHtlcs[ctx.circuitKey].hodlChan = hodlChan
+		i.heldHtlcsMtx.Unlock()
+
+		i.hodlSubscribe(hodlChan, ctx.circuitKey)
+
+		return true
+	}
+
+	// Don't hold htlcs that are about to expire, as we would need to
+	// cancel them back right away to prevent a force close.
+	expiryDelta := i.expiryWatcher.blockExpiryDelta
+	if ctx.expiry <= uint32(ctx.currentHeight)+expiryDelta {
+		i.heldHtlcsMtx.Unlock()
+		ctx.log("not offered to htlc hold hook, expiry too soon")
+
+		return false
+	}
+
+	// Register the htlc before offering it to the hook, so that it can be
+	// resolved from another goroutine right away.
+	i.heldHtlcs[ctx.circuitKey] = &heldHtlc{
+		ctx:      *ctx,
+		hodlChan: hodlChan,
+	}
+	i.heldHtlcsMtx.Unlock()
+
+	i.hodlSubscribe(hodlChan, ctx.circuitKey)
+
+	held := i.cfg.HtlcHoldHook.HoldHtlc(&HeldHtlc{
+		Hash:          ctx.hash,
+		CircuitKey:    ctx.circuitKey,
+		AmtPaid:       ctx.amtPaid,
+		Expiry:        ctx.expiry,
+		CurrentHeight: ctx.currentHeight,
+		CustomRecords: ctx.customRecords,
+		MPP:           ctx.mpp,
+		registry:      i,
+	})
+	if held {
+		ctx.log("held by htlc hold hook")
+
+		expiry := makeHeldHtlcExpiry(
+			ctx.hash, ctx.circuitKey, ctx.expiry,
+		)
+		i.expiryWatcher.AddInvoices(expiry)
+
+		return true
+	}
+
+	i.heldHtlcsMtx.Lock()
+	delete(i.heldHtlcs, ctx.circuitKey)
+	i.heldHtlcsMtx.Unlock()
+
+	i.hodlUnsubscribe(hodlChan, ctx.circuitKey)
+
+	return false
+}
+
+// releaseHeldHtlc removes the htlc with the given circuit key from the set of
+// held htlcs.
+func (i *InvoiceRegistry) releaseHeldHtlc(
+	key channeldb.CircuitKey) (*heldHtlc, error) {
+
+	i.heldHtlcsMtx.Lock()
+	defer i.heldHtlcsMtx.Unlock()
+
+	held, ok := i.heldHtlcs[key]
+	if !ok {
+		return nil, ErrHtlcNotHeld
+	}
+	delete(i.heldHtlcs, key)
+
+	return held, nil
+}
+
+// resumeHeldHtlc processes a previously held htlc and delivers its resolution
+// to the link, if it is resolved right away.
+func (i *InvoiceRegistry) resumeHeldHtlc(key channeldb.CircuitKey,
+	feeCredit lnwire.MilliSatoshi) error {
+
+	held, err := i.releaseHeldHtlc(key)
+	if err != nil {
+		return err
+	}
+
+	ctx := held.ctx
+	ctx.feeCredit = feeCredit
+	ctx.log(fmt.Sprintf("released by htlc hold hook with fee credit %v",
+		feeCredit))
+
+	resolution, err := i.processExitHopHtlc(ctx, held.hodlChan)
+	if err != nil {
+		i.notifyHodlSubscribers(ctx.failRes(ResultHtlcHoldFailed))
+
+		return err
+	}
+
+	// A nil resolution means that the htlc got accepted. Its resolution
+	// will be delivered through the hodl channel subscription later on.
+	if resolution != nil {
+		i.notifyHodlSubscribers(resolution)
+	}
+
+	return nil
+}
+
+// failHeldHtlc fails a previously held htlc back to the sender.
+func (i *InvoiceRegistry) failHeldHtlc(key channeldb.CircuitKey) error {
+	held, err := i.releaseHeldHtlc(key)
+	if err != nil {
+		return err
+	}
+
+	held.ctx.log("failed by htlc hold hook")
+	i.notifyHodlSubscribers(held.ctx.failRes(ResultHtlcHoldFailed))
+
+	return nil
+}
+
+// expireHeldHtlc fails a held htlc back to the sender once it is about to
+// expire. It is called by the expiry watcher.
+func (i *InvoiceRegistry) expireHeldHtlc(key channeldb.CircuitKey) error {
+	held, err := i.releaseHeldHtlc(key)
+	if err != nil {
+		return err
+	}
+
+	held.ctx.log("held htlc expired")
+	i.notifyHodlSubscribers(held.ctx.failRes(ResultExpiryTooSoon))
+
+	return nil
+}
+
+// heldFeeCredits returns a copy of the fee credits of the accepted htlcs that
+// were resumed by the htlc hold hook.
+func (i *InvoiceRegistry) heldFeeCredits() htlcFeeCredits {
+	i.heldHtlcsMtx.Lock()
+	defer i.heldHtlcsMtx.Unlock()
+
+	feeCredits := make(htlcFeeCredits, len(i.feeCredits))
+	for key, feeCredit := range i.feeCredits {
+		feeCredits[key] = feeCredit
+	}
+
+	return feeCredits
+}
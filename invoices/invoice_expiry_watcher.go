@@ -44,6 +44,10 @@ var _ invoiceExpiry = (*invoiceExpiryHeight)(nil)
 type invoiceExpiryHeight struct {
 	paymentHash  lntypes.Hash
 	expiryHeight uint32
+
+	// heldHtlc is set if the entry tracks an htlc that is held by the
+	// htlc hold hook, rather than the htlcs of an accepted invoice.
+	heldHtlc *channeldb.CircuitKey
 }
 
 // Less implements PriorityQueueItem.Less such that the top item in the
@@ -91,6 +95,10 @@ type InvoiceExpiryWatcher struct {
 	// cancelInvoice is a template method that cancels an expired invoice.
 	cancelInvoice func(lntypes.Hash, bool) error
 
+	// failHeldHtlc is a template method that fails an expired htlc that
+	// is held by the htlc hold hook.
+	failHeldHtlc func(channeldb.CircuitKey) error
+
 	// timestampExpiryQueue holds invoiceExpiry items and is used to find
 	// the next invoice to expire.
 	timestampExpiryQueue queue.PriorityQueue
@@ -133,9 +141,11 @@ func NewInvoiceExpiryWatcher(clock clock.Clock,
 // Start starts the the subscription handler and the main loop. Start() will
 // return with error if InvoiceExpiryWatcher is already started. Start()
 // expects a cancellation function passed that will be use to cancel expired
-// invoices by their payment hash.
+// invoices by their payment hash, and a function that fails expired htlcs
+// held by the htlc hold hook by their circuit key.
 func (ew *InvoiceExpiryWatcher) Start(
-	cancelInvoice func(lntypes.Hash, bool) error) error {
+	cancelInvoice func(lntypes.Hash, bool) error,
+	failHeldHtlc func(channeldb.CircuitKey) error) error {
 
 	ew.Lock()
 	defer ew.Unlock()
@@ -146,6 +156,7 @@ func (ew *InvoiceExpiryWatcher) Start(
 
 	ew.started = true
 	ew.cancelInvoice = cancelInvoice
+	ew.failHeldHtlc = failHeldHtlc
 
 	ntfn, err := ew.notifier.RegisterBlockEpochNtfn(&chainntnfs.BlockEpoch{
 		Height: int32(ew.currentHeight),
@@ -257,6 +268,18 @@ func makeHeightExpiry(paymentHash lntypes.Hash,
 	}
 }
 
+// makeHeldHtlcExpiry creates height-based expiry for an htlc that is held by
+// the htlc hold hook.
+func makeHeldHtlcExpiry(paymentHash lntypes.Hash, key channeldb.CircuitKey,
+	expiry uint32) *invoiceExpiryHeight {
+
+	return &invoiceExpiryHeight{
+		paymentHash:  paymentHash,
+		expiryHeight: expiry,
+		heldHtlc:     &key,
+	}
+}
+
 // AddInvoices adds invoices to the InvoiceExpiryWatcher.
 func (ew *InvoiceExpiryWatcher) AddInvoices(invoices ...invoiceExpiry) {
 	if len(invoices) == 0 {
@@ -335,6 +358,15 @@ func (ew *InvoiceExpiryWatcher) cancelNextHeightExpiredInvoice() {
 		return
 	}
 
+	// Held htlcs aren't part of the invoice yet, so we fail them back
+	// directly.
+	if top.heldHtlc != nil {
+		ew.expireHeldHtlc(*top.heldHtlc)
+		ew.blockExpiryQueue.Pop()
+
+		return
+	}
+
 	// We always force-cancel block-based expiry so that we can
 	// cancel invoices that have been accepted but not yet resolved.
 	// This helps us avoid force closes.
@@ -342,6 +374,21 @@ func (ew *InvoiceExpiryWatcher) cancelNextHeightExpiredInvoice() {
 	ew.blockExpiryQueue.Pop()
 }
 
+// expireHeldHtlc attempts to fail a held htlc and logs an error if we get an
+// unexpected error.
+func (ew *InvoiceExpiryWatcher) expireHeldHtlc(key channeldb.CircuitKey) {
+	err := ew.failHeldHtlc(key)
+	switch err {
+	case nil:
+
+	// The htlc was already resumed or failed by the hook.
+	case ErrHtlcNotHeld:
+
+	default:
+		log.Errorf("Unable to fail held htlc: %v: %v", key, err)
+	}
+}
+
 // expireInvoice attempts to expire an invoice and logs an error if we get an
 // unexpected error.
 func (ew *InvoiceExpiryWatcher) expireInvoice(hash lntypes.Hash, force bool) {
@@ -0,0 +1,246 @@
+package invoices
+
+import (
+	"compress/gzip"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lntypes"
+)
+
+var (
+	// ErrInvoiceGcDisabled is returned when expired invoices are garbage
+	// collected without an age, and no age was configured.
+	ErrInvoiceGcDisabled = errors.New("garbage collection of expired " +
+		"invoices is disabled")
+)
+
+// InvoiceGcStats summarizes the garbage collection of expired unpaid invoices.
+type InvoiceGcStats struct {
+	// LastRun is the time the last garbage collection finished. It is the
+	// zero time if no garbage collection has run yet.
+	LastRun time.Time
+
+	// LastDeleted is the number of invoices deleted by the last garbage
+	// collection.
+	LastDeleted uint64
+
+	// TotalDeleted is the number of invoices deleted by all garbage
+	// collections since the registry was started.
+	TotalDeleted uint64
+}
+
+// invoiceGc tracks the garbage collection of expired unpaid invoices.
+type invoiceGc struct {
+	// mu serializes garbage collections and guards stats.
+	mu sync.Mutex
+
+	stats InvoiceGcStats
+}
+
+// archivedInvoice is the record of an invoice in the archive file. Each
+// record is written as a single line of JSON.
+type archivedInvoice struct {
+	PaymentHash    string `json:"payment_hash"`
+	Preimage       string `json:"preimage,omitempty"`
+	PaymentAddr    string `json:"payment_addr"`
+	PaymentRequest string `json:"payment_request,omitempty"`
+	Memo           string `json:"memo,omitempty"`
+	ValueMsat      uint64 `json:"value_msat"`
+	CreationDate   int64  `json:"creation_date"`
+	Expiry         int64  `json:"expiry"`
+	AddIndex       uint64 `json:"add_index"`
+	State          string `json:"state"`
+}
+
+// newArchivedInvoice creates the archive record of an invoice.
+func newArchivedInvoice(payHash lntypes.Hash,
+	invoice *channeldb.Invoice) *archivedInvoice {
+
+	record := &archivedInvoice{
+		PaymentHash:    payHash.String(),
+		PaymentAddr:    hex.EncodeToString(invoice.Terms.PaymentAddr[:]),
+		PaymentRequest: string(invoice.PaymentRequest),
+		Memo:           string(invoice.Memo),
+		ValueMsat:      uint64(invoice.Terms.Value),
+		CreationDate:   invoice.CreationDate.Unix(),
+		Expiry:         int64(invoice.Terms.Expiry.Seconds()),
+		AddIndex:       invoice.AddIndex,
+		State:          invoice.State.String(),
+	}
+
+	if invoice.Terms.PaymentPreimage != nil {
+		record.Preimage = invoice.Terms.PaymentPreimage.String()
+	}
+
+	return record
+}
+
+// isExpiredUnpaid returns true if the invoice is unpaid and expired before the
+// given time. Expired invoices are canceled by the expiry watcher, so only
+// canceled invoices are considered.
+func isExpiredUnpaid(invoice *channeldb.Invoice, before time.Time) bool {
+	if invoice.State != channeldb.ContractCanceled || invoice.AmtPaid != 0 {
+		return false
+	}
+
+	for _, htlc := range invoice.Htlcs {
+		if htlc.State != channeldb.HtlcStateCanceled {
+			return false
+		}
+	}
+
+	expiry := invoice.CreationDate.Add(invoice.Terms.Expiry)
+
+	return expiry.Before(before)
+}
+
+// archiveInvoices appends the given invoices to the gzip compressed archive
+// file. Every call appends a new gzip member to the file, which readers
+// decompress as a single stream.
+func archiveInvoices(path string, records []*archivedInvoice) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(f)
+	enc := json.NewEncoder(zw)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// GcExpiredInvoices deletes all unpaid invoices that expired more than
+// expiredAfter ago. If expiredAfter is zero, the configured age is used. If
+// an archive file is configured, the invoices are appended to it before they
+// are deleted. The updated garbage collection stats are returned.
+func (i *InvoiceRegistry) GcExpiredInvoices(
+	expiredAfter time.Duration) (InvoiceGcStats, error) {
+
+	if expiredAfter == 0 {
+		expiredAfter = i.cfg.GcExpiredAfter
+	}
+	if expiredAfter <= 0 {
+		return InvoiceGcStats{}, ErrInvoiceGcDisabled
+	}
+
+	i.gc.mu.Lock()
+	defer i.gc.mu.Unlock()
+
+	var (
+		removable []channeldb.InvoiceDeleteRef
+		records   []*archivedInvoice
+	)
+
+	reset := func() {
+		removable = nil
+		records = nil
+	}
+
+	before := i.cfg.Clock.Now().Add(-expiredAfter)
+	scanFunc := func(paymentHash lntypes.Hash,
+		invoice *channeldb.Invoice) error {
+
+		if !isExpiredUnpaid(invoice, before) {
+			return nil
+		}
+
+		ref := channeldb.InvoiceDeleteRef{
+			PayHash:     paymentHash,
+			AddIndex:    invoice.AddIndex,
+			SettleIndex: invoice.SettleIndex,
+		}
+		if invoice.Terms.PaymentAddr != channeldb.BlankPayAddr {
+			ref.PayAddr = &invoice.Terms.PaymentAddr
+		}
+		removable = append(removable, ref)
+
+		if i.cfg.GcArchiveFile != "" {
+			records = append(
+				records, newArchivedInvoice(paymentHash, invoice),
+			)
+		}
+
+		return nil
+	}
+
+	if err := i.cdb.ScanInvoices(scanFunc, reset); err != nil {
+		return InvoiceGcStats{}, err
+	}
+
+	if len(removable) > 0 {
+		// The invoices are only deleted once they are safely
+		// archived.
+		if i.cfg.GcArchiveFile != "" {
+			err := archiveInvoices(i.cfg.GcArchiveFile, records)
+			if err != nil {
+				return InvoiceGcStats{}, fmt.Errorf("unable "+
+					"to archive invoices: %w", err)
+			}
+		}
+
+		if err := i.cdb.DeleteInvoice(removable); err != nil {
+			return InvoiceGcStats{}, err
+		}
+
+		log.Infof("Deleted %v expired unpaid invoices", len(removable))
+	}
+
+	i.gc.stats.LastRun = i.cfg.Clock.Now()
+	i.gc.stats.LastDeleted = uint64(len(removable))
+	i.gc.stats.TotalDeleted += uint64(len(removable))
+
+	return i.gc.stats, nil
+}
+
+// GcStats returns the stats of the garbage collection of expired unpaid
+// invoices.
+func (i *InvoiceRegistry) GcStats() InvoiceGcStats {
+	i.gc.mu.Lock()
+	defer i.gc.mu.Unlock()
+
+	return i.gc.stats
+}
+
+// invoiceGcLoop periodically garbage collects expired unpaid invoices.
+//
+// NOTE: This MUST be run as a goroutine.
+func (i *InvoiceRegistry) invoiceGcLoop() {
+	defer i.wg.Done()
+
+	for {
+		if _, err := i.GcExpiredInvoices(0); err != nil {
+			log.Errorf("Unable to garbage collect expired invoices: "+
+				"%v", err)
+		}
+
+		select {
+		case <-i.cfg.Clock.TickAfter(i.cfg.GcInterval):
+
+		case <-i.quit:
+			return
+		}
+	}
+}
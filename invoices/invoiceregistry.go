@@ -3,6 +3,7 @@ package invoices
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -27,6 +28,10 @@ var (
 	// ErrShuttingDown is returned when an operation failed because the
 	// invoice registry is shutting down.
 	ErrShuttingDown = errors.New("invoice registry shutting down")
+
+	// ErrInvoiceExpired is returned when attempting to reissue an invoice
+	// that has already expired.
+	ErrInvoiceExpired = errors.New("invoice expired")
 )
 
 const (
@@ -69,9 +74,29 @@ type RegistryConfig struct {
 	// canceled invoices on the fly.
 	GcCanceledInvoicesOnTheFly bool
 
+	// GcExpiredAfter is the time after their expiry after which unpaid
+	// invoices are garbage collected. If zero, expired invoices are only
+	// garbage collected on request.
+	GcExpiredAfter time.Duration
+
+	// GcInterval is the interval at which expired unpaid invoices are
+	// garbage collected if GcExpiredAfter is set.
+	GcInterval time.Duration
+
+	// GcArchiveFile is the path of the gzip compressed file that expired
+	// unpaid invoices are appended to before they are garbage collected.
+	// If empty, the invoices aren't archived.
+	GcArchiveFile string
+
 	// KeysendHoldTime indicates for how long we want to accept and hold
 	// spontaneous keysend payments.
 	KeysendHoldTime time.Duration
+
+	// HtlcHoldHook is an optional hook that is offered every htlc paying
+	// to one of our invoices before it is processed. It can hold the htlc
+	// until an external condition is met, such as a just-in-time channel
+	// being opened to us.
+	HtlcHoldHook HtlcHoldHook
 }
 
 // htlcReleaseEvent describes an htlc auto-release event. It is used to release
@@ -137,12 +162,26 @@ type InvoiceRegistry struct {
 	// subscriber. This is used to unsubscribe from all hashes efficiently.
 	hodlReverseSubscriptions map[chan<- interface{}]map[channeldb.CircuitKey]struct{}
 
+	// heldHtlcsMtx locks the heldHtlcs and feeCredits maps.
+	heldHtlcsMtx sync.Mutex
+
+	// heldHtlcs tracks the htlcs that are currently held by the htlc hold
+	// hook.
+	heldHtlcs map[channeldb.CircuitKey]*heldHtlc
+
+	// feeCredits tracks the fee credits of accepted htlcs that were
+	// resumed by the htlc hold hook, until the htlcs are resolved.
+	feeCredits htlcFeeCredits
+
 	// htlcAutoReleaseChan contains the new htlcs that need to be
 	// auto-released.
 	htlcAutoReleaseChan chan *htlcReleaseEvent
 
 	expiryWatcher *InvoiceExpiryWatcher
 
+	// gc tracks the garbage collection of expired unpaid invoices.
+	gc invoiceGc
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -161,6 +200,8 @@ func NewRegistry(cdb *channeldb.DB, expiryWatcher *InvoiceExpiryWatcher,
 		invoiceEvents:             make(chan *invoiceEvent, 100),
 		hodlSubscriptions:         make(map[channeldb.CircuitKey]map[chan<- interface{}]struct{}),
 		hodlReverseSubscriptions:  make(map[chan<- interface{}]map[channeldb.CircuitKey]struct{}),
+		heldHtlcs:                 make(map[channeldb.CircuitKey]*heldHtlc),
+		feeCredits:                make(htlcFeeCredits),
 		cfg:                       cfg,
 		htlcAutoReleaseChan:       make(chan *htlcReleaseEvent),
 		expiryWatcher:             expiryWatcher,
@@ -244,7 +285,7 @@ func (i *InvoiceRegistry) scanInvoicesOnStart() error {
 func (i *InvoiceRegistry) Start() error {
 	// Start InvoiceExpiryWatcher and prepopulate it with existing active
 	// invoices.
-	err := i.expiryWatcher.Start(i.cancelInvoiceImpl)
+	err := i.expiryWatcher.Start(i.cancelInvoiceImpl, i.expireHeldHtlc)
 	if err != nil {
 		return err
 	}
@@ -262,6 +303,11 @@ func (i *InvoiceRegistry) Start() error {
 		return err
 	}
 
+	if i.cfg.GcExpiredAfter > 0 {
+		i.wg.Add(1)
+		go i.invoiceGcLoop()
+	}
+
 	return nil
 }
 
@@ -374,6 +420,19 @@ func (i *InvoiceRegistry) dispatchToSingleClients(event *invoiceEvent) {
 			return
 		}
 
+		// If the settlement of an AMP htlc set was already sent to the
+		// client as part of the backlog, we can skip it.
+		if event.setID != nil {
+			ampState, ok := event.invoice.AMPState[*event.setID]
+			if ok && ampState.SettleIndex != 0 {
+				if ampState.SettleIndex <= client.settleIndex {
+					continue
+				}
+
+				client.settleIndex = ampState.SettleIndex
+			}
+		}
+
 		client.notify(event)
 	}
 }
@@ -522,9 +581,11 @@ func (i *InvoiceRegistry) deliverBacklogEvents(client *InvoiceSubscription) erro
 
 // deliverSingleBacklogEvents will attempt to query the invoice database to
 // retrieve the current invoice state and deliver this to the subscriber. Single
-// invoice subscribers will always receive the current state right after
-// subscribing. Only in case the invoice does not yet exist, nothing is sent
-// yet.
+// invoice subscribers without a checkpoint will always receive the current
+// state right after subscribing. Subscribers with a checkpoint first receive
+// the AMP htlc sets settled after their settle index, and only receive the
+// current state if they don't know it yet. Only in case the invoice does not
+// yet exist, nothing is sent yet.
 func (i *InvoiceRegistry) deliverSingleBacklogEvents(
 	client *SingleInvoiceSubscription) error {
 
@@ -546,6 +607,42 @@ func (i *InvoiceRegistry) deliverSingleBacklogEvents(
 		return nil
 	}
 
+	// If the client provided a settle index, we'll first replay the htlc
+	// sets of an AMP invoice that were settled after it, in the order they
+	// were settled. As with SubscribeNotifications, a zero index means
+	// that no settle events are replayed.
+	if client.settleIndex != 0 {
+		for _, setID := range settledSetsSince(&invoice,
+			client.settleIndex) {
+
+			setIDBytes := [32]byte(setID)
+			err := client.notify(&invoiceEvent{
+				hash:    *payHash,
+				invoice: &invoice,
+				setID:   &setIDBytes,
+			})
+			if err != nil {
+				return err
+			}
+
+			client.settleIndex = invoice.AMPState[setID].SettleIndex
+
+			log.Debugf("Client(id=%v) delivered single backlog "+
+				"settle event: payHash=%v, setID=%x",
+				client.id, payHash, setID[:])
+		}
+	}
+
+	// If the client provided a checkpoint, it already knows about the
+	// invoice if it is still open and was added before, so there's no need
+	// to send its current state.
+	checkpoint := client.addIndex != 0 || client.settleIndex != 0
+	if checkpoint && invoice.State == channeldb.ContractOpen &&
+		invoice.AddIndex <= client.addIndex {
+
+		return nil
+	}
+
 	err = client.notify(&invoiceEvent{
 		hash:    *payHash,
 		invoice: &invoice,
@@ -560,6 +657,32 @@ func (i *InvoiceRegistry) deliverSingleBacklogEvents(
 	return nil
 }
 
+// settledSetsSince returns the set IDs of the htlc sets of an AMP invoice that
+// were settled after the given settle index, ordered by their settle index.
+func settledSetsSince(invoice *channeldb.Invoice,
+	settleIndex uint64) []channeldb.SetID {
+
+	var setIDs []channeldb.SetID
+	for setID, ampState := range invoice.AMPState {
+		if ampState.State != channeldb.HtlcStateSettled ||
+			ampState.SettleIndex <= settleIndex {
+
+			continue
+		}
+
+		setIDs = append(setIDs, setID)
+	}
+
+	sort.Slice(setIDs, func(a, b int) bool {
+		settleIndexA := invoice.AMPState[setIDs[a]].SettleIndex
+		settleIndexB := invoice.AMPState[setIDs[b]].SettleIndex
+
+		return settleIndexA < settleIndexB
+	})
+
+	return setIDs
+}
+
 // AddInvoice adds a regular invoice for the specified amount, identified by
 // the passed preimage. Additionally, any memo or receipt data provided will
 // also be stored on-disk. Once this invoice is added, subsystems within the
@@ -568,6 +691,9 @@ func (i *InvoiceRegistry) deliverSingleBacklogEvents(
 // addIndex of the newly created invoice which monotonically increases for each
 // new invoice added.  A side effect of this function is that it also sets
 // AddIndex on the invoice argument.
+//
+// If ReissuedFrom is set on the invoice, it replaces the referenced invoice,
+// which must be open and not yet expired. The original invoice is canceled.
 func (i *InvoiceRegistry) AddInvoice(invoice *channeldb.Invoice,
 	paymentHash lntypes.Hash) (uint64, error) {
 
@@ -576,6 +702,25 @@ func (i *InvoiceRegistry) AddInvoice(invoice *channeldb.Invoice,
 	ref := channeldb.InvoiceRefByHash(paymentHash)
 	log.Debugf("Invoice%v: added with terms %v", ref, invoice.Terms)
 
+	// Reissuing an invoice that already expired would revive it, so we
+	// refuse to do so. As we hold the registry lock, the original invoice
+	// can't be paid before it is canceled below.
+	if invoice.ReissuedFrom != nil {
+		original, err := i.cdb.LookupInvoice(
+			channeldb.InvoiceRefByHash(*invoice.ReissuedFrom),
+		)
+		if err != nil {
+			i.Unlock()
+			return 0, err
+		}
+
+		expiry := original.CreationDate.Add(original.Terms.Expiry)
+		if !i.cfg.Clock.Now().Before(expiry) {
+			i.Unlock()
+			return 0, ErrInvoiceExpired
+		}
+	}
+
 	addIndex, err := i.cdb.AddInvoice(invoice, paymentHash)
 	if err != nil {
 		i.Unlock()
@@ -585,6 +730,23 @@ func (i *InvoiceRegistry) AddInvoice(invoice *channeldb.Invoice,
 	// Now that we've added the invoice, we'll send dispatch a message to
 	// notify the clients of this new invoice.
 	i.notifyClients(paymentHash, invoice, nil)
+
+	// The original of a reissued invoice was canceled, so we notify the
+	// clients of that as well.
+	if invoice.ReissuedFrom != nil {
+		log.Debugf("Invoice%v: reissued as %v",
+			channeldb.InvoiceRefByHash(*invoice.ReissuedFrom),
+			paymentHash)
+
+		original, err := i.cdb.LookupInvoice(
+			channeldb.InvoiceRefByHash(*invoice.ReissuedFrom),
+		)
+		if err != nil {
+			i.Unlock()
+			return 0, err
+		}
+		i.notifyClients(*invoice.ReissuedFrom, &original, nil)
+	}
 	i.Unlock()
 
 	// InvoiceExpiryWatcher.AddInvoice must not be locked by InvoiceRegistry
@@ -928,6 +1090,24 @@ func (i *InvoiceRegistry) NotifyExitHopHtlc(rHash lntypes.Hash,
 		metadata:             payload.Metadata(),
 	}
 
+	// Offer the htlc to the hold hook first. If it is held, a nil
+	// resolution is returned just like for accepted htlcs, and the final
+	// resolution is delivered through the hodl channel.
+	if i.holdHtlc(&ctx, hodlChan) {
+		return nil, nil
+	}
+
+	return i.processExitHopHtlc(ctx, hodlChan)
+}
+
+// processExitHopHtlc attempts to settle or cancel an htlc paying to one of
+// our invoices, described by the passed update context.
+func (i *InvoiceRegistry) processExitHopHtlc(ctx invoiceUpdateCtx,
+	hodlChan chan<- interface{}) (HtlcResolution, error) {
+
+	circuitKey := ctx.circuitKey
+	currentHeight := ctx.currentHeight
+
 	switch {
 	// If we are accepting spontaneous AMP payments and this payload
 	// contains an AMP record, create an AMP invoice that will be settled
@@ -1020,6 +1200,7 @@ func (i *InvoiceRegistry) notifyExitHopHtlcLocked(
 		resolution        HtlcResolution
 		updateSubscribers bool
 	)
+	ctx.setFeeCredits = i.heldFeeCredits()
 	invoice, err := i.cdb.UpdateInvoice(
 		ctx.invoiceRef(),
 		(*channeldb.SetID)(ctx.setID()),
@@ -1181,6 +1362,14 @@ func (i *InvoiceRegistry) notifyExitHopHtlcLocked(
 			"outcome: %v, at accept height: %v",
 			res.outcome, acceptHeight))
 
+		// Keep the fee credit of the htlc around, so that it is
+		// applied when the rest of its set arrives.
+		if ctx.feeCredit > 0 {
+			i.heldHtlcsMtx.Lock()
+			i.feeCredits[ctx.circuitKey] = ctx.feeCredit
+			i.heldHtlcsMtx.Unlock()
+		}
+
 		// Auto-release the htlc if the invoice is still open. It can
 		// only happen for mpp payments that there are htlcs in state
 		// Accepted while the invoice is Open.
@@ -1477,6 +1666,16 @@ type SingleInvoiceSubscription struct {
 	// Updates is a channel that we'll use to send all invoice events for
 	// the invoice that is subscribed to.
 	Updates chan *channeldb.Invoice
+
+	// addIndex is the highest add index the caller knows of. If the
+	// invoice was added before, its current state is only sent out as a
+	// backlog event if it isn't open anymore.
+	addIndex uint64
+
+	// settleIndex is the highest settle index the caller knows of. Any
+	// settled htlc sets of an AMP invoice with a greater settle index are
+	// sent out as backlog events before any new notifications.
+	settleIndex uint64
 }
 
 // Cancel unregisters the InvoiceSubscription, freeing any previously allocated
@@ -1613,8 +1812,25 @@ func (i *InvoiceRegistry) SubscribeNotifications(
 func (i *InvoiceRegistry) SubscribeSingleInvoice(
 	hash lntypes.Hash) (*SingleInvoiceSubscription, error) {
 
+	return i.SubscribeSingleInvoiceSince(hash, 0, 0)
+}
+
+// SubscribeSingleInvoiceSince returns an SingleInvoiceSubscription which
+// allows the caller to receive async notifications for a specific invoice.
+// The addIndex and settleIndex parameters are the streaming "checkpoint" of a
+// caller that subscribes again after some downtime, as with
+// SubscribeNotifications. We'll first send out all settled htlc sets of an
+// AMP invoice with a settle index _greater_ than settleIndex, followed by the
+// current state of the invoice unless it is still open and was added at or
+// before addIndex. If both indexes are zero, the current state is always sent
+// out first. Afterwards, we'll send out real-time notifications.
+func (i *InvoiceRegistry) SubscribeSingleInvoiceSince(hash lntypes.Hash,
+	addIndex, settleIndex uint64) (*SingleInvoiceSubscription, error) {
+
 	client := &SingleInvoiceSubscription{
-		Updates: make(chan *channeldb.Invoice),
+		Updates:     make(chan *channeldb.Invoice),
+		addIndex:    addIndex,
+		settleIndex: settleIndex,
 		invoiceSubscriptionKit: invoiceSubscriptionKit{
 			quit:             i.quit,
 			ntfnQueue:        queue.NewConcurrentQueue(20),
@@ -1686,6 +1902,11 @@ func (i *InvoiceRegistry) SubscribeSingleInvoice(
 // notifyHodlSubscribers sends out the htlc resolution to all current
 // subscribers.
 func (i *InvoiceRegistry) notifyHodlSubscribers(htlcResolution HtlcResolution) {
+	// The fee credit of the htlc is no longer needed once it is resolved.
+	i.heldHtlcsMtx.Lock()
+	delete(i.feeCredits, htlcResolution.CircuitKey())
+	i.heldHtlcsMtx.Unlock()
+
 	i.hodlSubscriptionsMux.Lock()
 	defer i.hodlSubscriptionsMux.Unlock()
 
@@ -1737,6 +1958,22 @@ func (i *InvoiceRegistry) hodlSubscribe(subscriber chan<- interface{},
 	reverseSubscriptions[circuitKey] = struct{}{}
 }
 
+// hodlUnsubscribe removes the subscription of the given subscriber to a
+// single circuit key.
+func (i *InvoiceRegistry) hodlUnsubscribe(subscriber chan<- interface{},
+	circuitKey channeldb.CircuitKey) {
+
+	i.hodlSubscriptionsMux.Lock()
+	defer i.hodlSubscriptionsMux.Unlock()
+
+	delete(i.hodlSubscriptions[circuitKey], subscriber)
+	if len(i.hodlSubscriptions[circuitKey]) == 0 {
+		delete(i.hodlSubscriptions, circuitKey)
+	}
+
+	delete(i.hodlReverseSubscriptions[subscriber], circuitKey)
+}
+
 // HodlUnsubscribeAll cancels the subscription.
 func (i *InvoiceRegistry) HodlUnsubscribeAll(subscriber chan<- interface{}) {
 	i.hodlSubscriptionsMux.Lock()
@@ -1,8 +1,13 @@
 package invoices
 
 import (
+	"compress/gzip"
 	"crypto/rand"
+	"encoding/json"
+	"io"
 	"math"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -607,6 +612,56 @@ func TestUnknownInvoice(t *testing.T) {
 	checkFailResolution(t, resolution, ResultInvoiceNotFound)
 }
 
+// TestReissueInvoice tests that reissuing an invoice cancels the original
+// invoice and notifies its subscribers, and that expired invoices can't be
+// reissued.
+func TestReissueInvoice(t *testing.T) {
+	defer timeout()()
+
+	ctx := newTestContext(t)
+	defer ctx.cleanup()
+
+	original := newTestInvoice(t, lntypes.Preimage{1}, testTime, time.Hour)
+	originalHash := original.Terms.PaymentPreimage.Hash()
+	_, err := ctx.registry.AddInvoice(original, originalHash)
+	require.NoError(t, err)
+
+	subscription, err := ctx.registry.SubscribeSingleInvoice(originalHash)
+	require.NoError(t, err)
+	defer subscription.Cancel()
+
+	update := <-subscription.Updates
+	require.Equal(t, channeldb.ContractOpen, update.State)
+
+	// Reissue the invoice with a fresh preimage. The subscriber of the
+	// original invoice is notified of its cancellation.
+	reissued := newTestInvoice(t, lntypes.Preimage{2}, testTime, time.Hour)
+	reissuedHash := reissued.Terms.PaymentPreimage.Hash()
+	reissued.ReissuedFrom = &originalHash
+	_, err = ctx.registry.AddInvoice(reissued, reissuedHash)
+	require.NoError(t, err)
+
+	// The add event of the original invoice may only be dispatched after
+	// the subscription was registered, in which case it shows up as a
+	// second open update before the cancellation.
+	update = <-subscription.Updates
+	for update.State == channeldb.ContractOpen {
+		update = <-subscription.Updates
+	}
+	require.Equal(t, channeldb.ContractCanceled, update.State)
+	require.Equal(t, &reissuedHash, update.ReissuedTo)
+
+	// Once the reissued invoice expired, it can't be reissued again.
+	ctx.clock.SetTime(testTime.Add(time.Hour))
+
+	expired := newTestInvoice(t, lntypes.Preimage{3}, testTime, time.Hour)
+	expired.ReissuedFrom = &reissuedHash
+	_, err = ctx.registry.AddInvoice(
+		expired, expired.Terms.PaymentPreimage.Hash(),
+	)
+	require.ErrorIs(t, err, ErrInvoiceExpired)
+}
+
 // TestKeySend tests receiving a spontaneous payment with and without keysend
 // enabled.
 func TestKeySend(t *testing.T) {
@@ -1108,6 +1163,108 @@ func TestOldInvoiceRemovalOnStart(t *testing.T) {
 	require.Equal(t, expected, response.Invoices)
 }
 
+// TestGcExpiredInvoices tests that unpaid invoices that expired long enough ago
+// are archived and deleted, while all other invoices are kept.
+func TestGcExpiredInvoices(t *testing.T) {
+	t.Parallel()
+
+	testClock := clock.NewTestClock(testTime)
+	cdb, err := newTestChannelDB(t, testClock)
+	require.NoError(t, err)
+
+	archiveFile := filepath.Join(t.TempDir(), "archive.jsonl.gz")
+	cfg := RegistryConfig{
+		FinalCltvRejectDelta: testFinalCltvRejectDelta,
+		Clock:                testClock,
+		GcArchiveFile:        archiveFile,
+	}
+
+	expiryWatcher := NewInvoiceExpiryWatcher(
+		cfg.Clock, 0, uint32(testCurrentHeight), nil, newMockNotifier(),
+	)
+	registry := NewRegistry(cdb, expiryWatcher, &cfg)
+
+	// Without a configured age, garbage collection must be requested
+	// with an age.
+	_, err = registry.GcExpiredInvoices(0)
+	require.ErrorIs(t, err, ErrInvoiceGcDisabled)
+
+	addInvoice := func(id byte, created time.Time,
+		state channeldb.ContractState) lntypes.Hash {
+
+		preimage := lntypes.Preimage{id}
+		invoice := newTestInvoice(t, preimage, created, time.Hour)
+		invoice.State = state
+
+		_, err := cdb.AddInvoice(invoice, preimage.Hash())
+		require.NoError(t, err)
+
+		return preimage.Hash()
+	}
+
+	// Add invoices that expired two days ago, of which only the canceled
+	// one is unpaid, and a canceled invoice that expired an hour ago.
+	old := testTime.Add(-49 * time.Hour)
+	oldCanceled := addInvoice(1, old, channeldb.ContractCanceled)
+	oldSettled := addInvoice(2, old, channeldb.ContractSettled)
+	recentCanceled := addInvoice(
+		3, testTime.Add(-2*time.Hour), channeldb.ContractCanceled,
+	)
+
+	readArchive := func() []lntypes.Hash {
+		f, err := os.Open(archiveFile)
+		require.NoError(t, err)
+		defer f.Close()
+
+		zr, err := gzip.NewReader(f)
+		require.NoError(t, err)
+
+		var hashes []lntypes.Hash
+		dec := json.NewDecoder(zr)
+		for {
+			var record archivedInvoice
+			err := dec.Decode(&record)
+			if err == io.EOF {
+				return hashes
+			}
+			require.NoError(t, err)
+
+			hash, err := lntypes.MakeHashFromStr(record.PaymentHash)
+			require.NoError(t, err)
+			hashes = append(hashes, hash)
+		}
+	}
+
+	// Only the old canceled invoice is deleted when collecting invoices
+	// that expired more than a day ago.
+	stats, err := registry.GcExpiredInvoices(24 * time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, testTime, stats.LastRun)
+	require.EqualValues(t, 1, stats.LastDeleted)
+	require.EqualValues(t, 1, stats.TotalDeleted)
+
+	_, err = registry.LookupInvoice(oldCanceled)
+	require.ErrorIs(t, err, channeldb.ErrInvoiceNotFound)
+	_, err = registry.LookupInvoice(oldSettled)
+	require.NoError(t, err)
+	_, err = registry.LookupInvoice(recentCanceled)
+	require.NoError(t, err)
+
+	require.Equal(t, []lntypes.Hash{oldCanceled}, readArchive())
+
+	// A shorter age also collects the recently canceled invoice, which is
+	// appended to the archive.
+	stats, err = registry.GcExpiredInvoices(time.Minute)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, stats.LastDeleted)
+	require.EqualValues(t, 2, stats.TotalDeleted)
+	require.Equal(t, stats, registry.GcStats())
+
+	require.Equal(
+		t, []lntypes.Hash{oldCanceled, recentCanceled}, readArchive(),
+	)
+}
+
 // TestHeightExpiryWithRegistry tests our height-based invoice expiry for
 // invoices paid with single and multiple htlcs, testing the case where the
 // invoice is settled before expiry (and thus not canceled), and the case
@@ -1754,3 +1911,316 @@ func testSpontaneousAmpPayment(
 		}
 	}
 }
+
+// mockHtlcHoldHook is an HtlcHoldHook that holds every htlc and hands it to
+// the test.
+type mockHtlcHoldHook struct {
+	held chan *HeldHtlc
+}
+
+// HoldHtlc holds the htlc and hands it to the test.
+func (m *mockHtlcHoldHook) HoldHtlc(htlc *HeldHtlc) bool {
+	m.held <- htlc
+	return true
+}
+
+// TestHtlcHoldHook asserts that htlcs held by the htlc hold hook are only
+// processed once released, and that a fee credit is applied when resuming.
+func TestHtlcHoldHook(t *testing.T) {
+	defer timeout()()
+
+	ctx := newTestContext(t)
+	defer ctx.cleanup()
+
+	hook := &mockHtlcHoldHook{held: make(chan *HeldHtlc, 1)}
+	ctx.registry.cfg.HtlcHoldHook = hook
+
+	_, err := ctx.registry.AddInvoice(testInvoice, testInvoicePaymentHash)
+	require.NoError(t, err)
+
+	// Pay the invoice with an htlc that is short of the invoice amount by
+	// the fee a service provider charged for opening a channel to us.
+	const fee = lnwire.MilliSatoshi(2000)
+	hodlChan := make(chan interface{}, 1)
+	resolution, err := ctx.registry.NotifyExitHopHtlc(
+		testInvoicePaymentHash, testInvoice.Terms.Value-fee,
+		testHtlcExpiry, testCurrentHeight, getCircuitKey(0), hodlChan,
+		testPayload,
+	)
+	require.NoError(t, err)
+	require.Nil(t, resolution)
+
+	held := <-hook.held
+	require.Equal(t, getCircuitKey(0), held.CircuitKey)
+	require.Equal(t, testInvoice.Terms.Value-fee, held.AmtPaid)
+
+	// A replay of the htlc shouldn't be offered to the hook again.
+	replayChan := make(chan interface{}, 1)
+	resolution, err = ctx.registry.NotifyExitHopHtlc(
+		testInvoicePaymentHash, testInvoice.Terms.Value-fee,
+		testHtlcExpiry, testCurrentHeight, getCircuitKey(0),
+		replayChan, testPayload,
+	)
+	require.NoError(t, err)
+	require.Nil(t, resolution)
+	require.Empty(t, hook.held)
+
+	// Resuming the htlc with the fee credit should settle the invoice.
+	require.NoError(t, held.Resume(fee))
+	for _, c := range []chan interface{}{hodlChan, replayChan} {
+		resolution, ok := (<-c).(HtlcResolution)
+		require.True(t, ok)
+		checkSettleResolution(t, resolution, testInvoicePreimage)
+	}
+
+	// The fee credit isn't recorded as paid to the invoice.
+	inv, err := ctx.registry.LookupInvoice(testInvoicePaymentHash)
+	require.NoError(t, err)
+	require.Equal(t, testInvoice.Terms.Value-fee, inv.AmtPaid)
+
+	// The htlc is no longer held.
+	require.ErrorIs(t, held.Fail(), ErrHtlcNotHeld)
+
+	// Another htlc to an unknown invoice is failed back by the hook.
+	resolution, err = ctx.registry.NotifyExitHopHtlc(
+		lntypes.Hash{1}, testInvoice.Terms.Value, testHtlcExpiry,
+		testCurrentHeight, getCircuitKey(1), hodlChan, testPayload,
+	)
+	require.NoError(t, err)
+	require.Nil(t, resolution)
+
+	held = <-hook.held
+	require.NoError(t, held.Fail())
+
+	resolution, ok := (<-hodlChan).(HtlcResolution)
+	require.True(t, ok)
+	checkFailResolution(t, resolution, ResultHtlcHoldFailed)
+}
+
+// TestHtlcHoldHookMppFeeCredit asserts that the fee credits of all the htlcs
+// of an mpp set are applied when checking whether the set is complete.
+func TestHtlcHoldHookMppFeeCredit(t *testing.T) {
+	defer timeout()()
+
+	ctx := newTestContext(t)
+	defer ctx.cleanup()
+
+	hook := &mockHtlcHoldHook{held: make(chan *HeldHtlc, 1)}
+	ctx.registry.cfg.HtlcHoldHook = hook
+
+	_, err := ctx.registry.AddInvoice(testInvoice, testInvoicePaymentHash)
+	require.NoError(t, err)
+
+	// Pay the invoice with two htlcs that both had a fee deducted.
+	const fee = lnwire.MilliSatoshi(1000)
+	mppPayload := &mockPayload{
+		mpp: record.NewMPP(testInvoiceAmt, [32]byte{}),
+	}
+	htlcAmt := testInvoiceAmt/2 - fee
+
+	hodlChan := make(chan interface{}, 2)
+	for i := uint64(0); i < 2; i++ {
+		resolution, err := ctx.registry.NotifyExitHopHtlc(
+			testInvoicePaymentHash, htlcAmt, testHtlcExpiry,
+			testCurrentHeight, getCircuitKey(i), hodlChan,
+			mppPayload,
+		)
+		require.NoError(t, err)
+		require.Nil(t, resolution)
+
+		held := <-hook.held
+		require.NoError(t, held.Resume(fee))
+	}
+
+	// The set is complete once the credit of both htlcs is applied.
+	for i := 0; i < 2; i++ {
+		resolution, ok := (<-hodlChan).(HtlcResolution)
+		require.True(t, ok)
+		checkSettleResolution(t, resolution, testInvoicePreimage)
+	}
+
+	inv, err := ctx.registry.LookupInvoice(testInvoicePaymentHash)
+	require.NoError(t, err)
+	require.Equal(t, channeldb.ContractSettled, inv.State)
+	require.Equal(t, 2*htlcAmt, inv.AmtPaid)
+}
+
+// TestHtlcHoldHookExpiry asserts that htlcs close to their expiry aren't held,
+// and that held htlcs are failed back once they get close to their expiry.
+func TestHtlcHoldHookExpiry(t *testing.T) {
+	defer timeout()()
+
+	ctx := newTestContext(t)
+	defer ctx.cleanup()
+
+	hook := &mockHtlcHoldHook{held: make(chan *HeldHtlc, 1)}
+	ctx.registry.cfg.HtlcHoldHook = hook
+
+	_, err := ctx.registry.AddInvoice(testInvoice, testInvoicePaymentHash)
+	require.NoError(t, err)
+
+	// An htlc that already expires isn't offered to the hook, but
+	// processed right away.
+	hodlChan := make(chan interface{}, 1)
+	resolution, err := ctx.registry.NotifyExitHopHtlc(
+		testInvoicePaymentHash, testInvoiceAmt,
+		uint32(testCurrentHeight), testCurrentHeight,
+		getCircuitKey(0), hodlChan, testPayload,
+	)
+	require.NoError(t, err)
+	checkFailResolution(t, resolution, ResultExpiryTooSoon)
+	require.Empty(t, hook.held)
+
+	// Hold an htlc that expires later on.
+	resolution, err = ctx.registry.NotifyExitHopHtlc(
+		testInvoicePaymentHash, testInvoiceAmt, testHtlcExpiry,
+		testCurrentHeight, getCircuitKey(1), hodlChan, testPayload,
+	)
+	require.NoError(t, err)
+	require.Nil(t, resolution)
+
+	held := <-hook.held
+
+	// Once its expiry height is mined, the htlc is failed back.
+	ctx.notifier.blockChan <- &chainntnfs.BlockEpoch{
+		Height: int32(testHtlcExpiry),
+	}
+
+	resolution, ok := (<-hodlChan).(HtlcResolution)
+	require.True(t, ok)
+	checkFailResolution(t, resolution, ResultExpiryTooSoon)
+
+	// The hook can no longer resume the htlc.
+	require.ErrorIs(t, held.Resume(0), ErrHtlcNotHeld)
+}
+
+// TestSubscribeSingleInvoiceSince asserts that single invoice subscribers that
+// provide an add and settle index checkpoint only receive the backlog they
+// don't know of yet.
+func TestSubscribeSingleInvoiceSince(t *testing.T) {
+	ctx := newTestContext(t)
+	defer ctx.cleanup()
+
+	addIdx, err := ctx.registry.AddInvoice(
+		testInvoice, testInvoicePaymentHash,
+	)
+	require.NoError(t, err)
+
+	// A subscriber that already knows about the open invoice doesn't
+	// receive its current state again.
+	subscription, err := ctx.registry.SubscribeSingleInvoiceSince(
+		testInvoicePaymentHash, addIdx, 0,
+	)
+	require.NoError(t, err)
+	defer subscription.Cancel()
+
+	select {
+	case update := <-subscription.Updates:
+		t.Fatalf("unexpected update: %v", update.State)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The checkpoint only applies to the backlog, so real-time updates of
+	// the open invoice, such as an accepted hodl invoice returning to the
+	// open state, are still delivered.
+	invoice, err := ctx.registry.LookupInvoice(testInvoicePaymentHash)
+	require.NoError(t, err)
+	ctx.registry.notifyClients(testInvoicePaymentHash, &invoice, nil)
+
+	select {
+	case update := <-subscription.Updates:
+		require.Equal(t, channeldb.ContractOpen, update.State)
+	case <-time.After(testTimeout):
+		t.Fatal("no update received")
+	}
+
+	// A subscriber that doesn't know about the invoice yet receives its
+	// current state.
+	unknownSubscription, err := ctx.registry.SubscribeSingleInvoiceSince(
+		testInvoicePaymentHash, addIdx-1, 0,
+	)
+	require.NoError(t, err)
+	defer unknownSubscription.Cancel()
+
+	select {
+	case update := <-unknownSubscription.Updates:
+		require.Equal(t, channeldb.ContractOpen, update.State)
+	case <-time.After(testTimeout):
+		t.Fatal("no update received")
+	}
+
+	// Settle the invoice. The settle event is delivered to the
+	// subscribers in real-time.
+	hodlChan := make(chan interface{}, 1)
+	resolution, err := ctx.registry.NotifyExitHopHtlc(
+		testInvoicePaymentHash, testInvoice.Terms.Value,
+		testHtlcExpiry, testCurrentHeight, getCircuitKey(0), hodlChan,
+		testPayload,
+	)
+	require.NoError(t, err)
+	checkSettleResolution(t, resolution, testInvoicePreimage)
+
+	var settleIdx uint64
+	select {
+	case update := <-subscription.Updates:
+		require.Equal(t, channeldb.ContractSettled, update.State)
+		settleIdx = update.SettleIndex
+	case <-time.After(testTimeout):
+		t.Fatal("no update received")
+	}
+
+	// A subscriber reconnecting after the settlement doesn't miss the
+	// terminal state, even if it already knew about the invoice being
+	// added.
+	lateSubscription, err := ctx.registry.SubscribeSingleInvoiceSince(
+		testInvoicePaymentHash, addIdx, settleIdx-1,
+	)
+	require.NoError(t, err)
+	defer lateSubscription.Cancel()
+
+	select {
+	case update := <-lateSubscription.Updates:
+		require.Equal(t, channeldb.ContractSettled, update.State)
+	case <-time.After(testTimeout):
+		t.Fatal("no update received")
+	}
+}
+
+// TestSettledSetsSince asserts that the settled htlc sets of an AMP invoice
+// after a settle index are returned in the order they were settled.
+func TestSettledSetsSince(t *testing.T) {
+	t.Parallel()
+
+	var setID1, setID2, setID3, setID4 channeldb.SetID
+	setID1[0], setID2[0], setID3[0], setID4[0] = 1, 2, 3, 4
+
+	invoice := &channeldb.Invoice{
+		AMPState: channeldb.AMPInvoiceState{
+			setID1: {
+				State:       channeldb.HtlcStateSettled,
+				SettleIndex: 7,
+			},
+			setID2: {
+				State:       channeldb.HtlcStateSettled,
+				SettleIndex: 3,
+			},
+			setID3: {
+				State: channeldb.HtlcStateCanceled,
+			},
+			setID4: {
+				State:       channeldb.HtlcStateSettled,
+				SettleIndex: 5,
+			},
+		},
+	}
+
+	require.Equal(
+		t, []channeldb.SetID{setID2, setID4, setID1},
+		settledSetsSince(invoice, 1),
+	)
+	require.Equal(
+		t, []channeldb.SetID{setID4, setID1},
+		settledSetsSince(invoice, 3),
+	)
+	require.Empty(t, settledSetsSince(invoice, 7))
+}
@@ -120,6 +120,10 @@ const (
 	// ResultAmpReconstruction is returned when the derived child
 	// hash/preimage pairs were invalid for at least one HTLC in the set.
 	ResultAmpReconstruction
+
+	// ResultHtlcHoldFailed is returned when an htlc that was held by the
+	// htlc hold hook is failed, or couldn't be processed once released.
+	ResultHtlcHoldFailed
 )
 
 // String returns a string representation of the result.
@@ -189,6 +193,9 @@ func (f FailResolutionResult) FailureString() string {
 	case ResultAmpReconstruction:
 		return "amp reconstruction failed"
 
+	case ResultHtlcHoldFailed:
+		return "htlc hold failed"
+
 	default:
 		return "unknown failure resolution result"
 	}
@@ -445,7 +445,7 @@ func setupHodlExpiry(t *testing.T, creationDate time.Time,
 		return nil
 	}
 
-	require.NoError(t, test.watcher.Start(cancelImpl))
+	require.NoError(t, test.watcher.Start(cancelImpl, nil))
 
 	// We set preimage and hash so that we can use our existing test
 	// helpers. In practice we would only have the hash, but this does not
@@ -12,11 +12,15 @@ import (
 )
 
 // invoiceUpdateCtx is an object that describes the context for the invoice
-// update to be carried out.
+// update to be carried out. The fee credits are only applied when checking the
+// amount that is paid against the invoice, they are never recorded as part of
+// the amount of an htlc.
 type invoiceUpdateCtx struct {
 	hash                 lntypes.Hash
 	circuitKey           channeldb.CircuitKey
 	amtPaid              lnwire.MilliSatoshi
+	feeCredit            lnwire.MilliSatoshi
+	setFeeCredits        htlcFeeCredits
 	expiry               uint32
 	currentHeight        int32
 	finalCltvRejectDelta int32
@@ -203,16 +207,16 @@ func updateMpp(ctx *invoiceUpdateCtx,
 
 	// Check whether total amt matches other htlcs in the set.
 	var newSetTotal lnwire.MilliSatoshi
-	for _, htlc := range htlcSet {
+	for key, htlc := range htlcSet {
 		if ctx.mpp.TotalMsat() != htlc.MppTotalAmt {
 			return nil, ctx.failRes(ResultHtlcSetTotalMismatch), nil
 		}
 
-		newSetTotal += htlc.Amt
+		newSetTotal += htlc.Amt + ctx.setFeeCredits[key]
 	}
 
 	// Add amount of new htlc.
-	newSetTotal += ctx.amtPaid
+	newSetTotal += ctx.amtPaid + ctx.feeCredit
 
 	// Make sure the communicated set total isn't overpaid.
 	if newSetTotal > ctx.mpp.TotalMsat() {
@@ -379,7 +383,7 @@ func updateLegacy(ctx *invoiceUpdateCtx,
 	// check this for duplicate payments if the invoice is already settled
 	// or accepted. In case this is a zero-valued invoice, it will always be
 	// enough.
-	if ctx.amtPaid < inv.Terms.Value {
+	if ctx.amtPaid+ctx.feeCredit < inv.Terms.Value {
 		return nil, ctx.failRes(ResultAmountTooLow), nil
 	}
 
@@ -0,0 +1,435 @@
+package keychain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+var (
+	// ErrHSMKeyNotExportable is returned when the private key of a key
+	// that is kept in a hardware security module is requested.
+	ErrHSMKeyNotExportable = errors.New("private keys kept in the HSM " +
+		"can't be exported")
+
+	// ErrHSMSchnorrUnsupported is returned when a Schnorr signature is
+	// requested for a key that is kept in a hardware security module, as
+	// PKCS#11 has no mechanism for BIP-340 signatures.
+	ErrHSMSchnorrUnsupported = errors.New("schnorr signatures aren't " +
+		"supported for keys kept in the HSM")
+
+	// DefaultHSMKeyFamilies are the key families whose keys are kept in
+	// the hardware security module by default: the node identity key and
+	// the multisig keys of the channel funding outputs.
+	DefaultHSMKeyFamilies = []KeyFamily{
+		KeyFamilyNodeKey, KeyFamilyMultiSig,
+	}
+)
+
+// HSM is the subset of the operations of a hardware security module, e.g. a
+// PKCS#11 token, that is needed to keep keys of a key ring in the module. All
+// keys are secp256k1 key pairs that are identified by a label, and their
+// private keys never leave the module.
+type HSM interface {
+	// PubKey returns the public key of the key pair with the given label.
+	// If no such key pair exists yet, it is generated within the module.
+	PubKey(label string) (*btcec.PublicKey, error)
+
+	// SignDigest signs the given digest with the private key of the key
+	// pair with the given label and returns the signature in the raw
+	// r || s format produced by the CKM_ECDSA mechanism.
+	SignDigest(label string, digest [32]byte) ([]byte, error)
+
+	// ECDH multiplies the private key of the key pair with the given label
+	// with the given public key and returns the resulting shared point.
+	ECDH(label string, pubKey *btcec.PublicKey) (*btcec.PublicKey, error)
+
+	// Ping checks that the module can be reached and that our session
+	// with it is still valid.
+	Ping() error
+}
+
+// HSMKeyRing is a SecretKeyRing that keeps the keys of a set of key families
+// in a hardware security module. The keys of all other families, like the
+// revocation root from which the per-commitment secrets are derived, are
+// served by a fallback key ring that holds them in memory.
+//
+// The fallback key ring is still used to allocate the indexes of new keys,
+// so the key locators of keys kept in the module are the same as the ones of
+// the keys they replace. Enabling the HSM for a node that already has
+// channels changes its identity and breaks the existing channels, as their
+// keys are no longer available.
+type HSMKeyRing struct {
+	hsm HSM
+
+	fallback SecretKeyRing
+
+	labelPrefix string
+
+	families map[KeyFamily]struct{}
+
+	// pubKeys caches the public keys of the keys kept in the module.
+	pubKeys map[KeyLocator]*btcec.PublicKey
+	mu      sync.Mutex
+}
+
+// A compile time check to ensure HSMKeyRing implements the SecretKeyRing
+// interface.
+var _ SecretKeyRing = (*HSMKeyRing)(nil)
+
+// NewHSMKeyRing creates a new key ring that keeps the keys of the given
+// families in the passed hardware security module and serves all other keys
+// from the fallback key ring. The keys in the module are labeled with the
+// given prefix, followed by the family and index of the key.
+func NewHSMKeyRing(hsm HSM, fallback SecretKeyRing, labelPrefix string,
+	families ...KeyFamily) *HSMKeyRing {
+
+	familySet := make(map[KeyFamily]struct{}, len(families))
+	for _, family := range families {
+		familySet[family] = struct{}{}
+	}
+
+	return &HSMKeyRing{
+		hsm:         hsm,
+		fallback:    fallback,
+		labelPrefix: labelPrefix,
+		families:    familySet,
+		pubKeys:     make(map[KeyLocator]*btcec.PublicKey),
+	}
+}
+
+// KeyLabel returns the label of the key with the given locator within the
+// hardware security module.
+func (r *HSMKeyRing) KeyLabel(keyLoc KeyLocator) string {
+	return fmt.Sprintf("%s-%d-%d", r.labelPrefix, keyLoc.Family,
+		keyLoc.Index)
+}
+
+// IsHSMKey returns true if the key described by the key descriptor is kept in
+// the hardware security module. If the descriptor includes a public key, it
+// must match the key in the module.
+func (r *HSMKeyRing) IsHSMKey(keyDesc KeyDescriptor) bool {
+	if _, ok := r.families[keyDesc.Family]; !ok {
+		return false
+	}
+
+	if keyDesc.PubKey == nil {
+		return true
+	}
+
+	pubKey, err := r.pubKey(keyDesc.KeyLocator)
+	if err != nil {
+		return false
+	}
+
+	return pubKey.IsEqual(keyDesc.PubKey)
+}
+
+// pubKey returns the public key of the key with the given locator that is
+// kept in the hardware security module.
+func (r *HSMKeyRing) pubKey(keyLoc KeyLocator) (*btcec.PublicKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if pubKey, ok := r.pubKeys[keyLoc]; ok {
+		return pubKey, nil
+	}
+
+	pubKey, err := r.hsm.PubKey(r.KeyLabel(keyLoc))
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch key %v from HSM: %w",
+			r.KeyLabel(keyLoc), err)
+	}
+
+	r.pubKeys[keyLoc] = pubKey
+
+	return pubKey, nil
+}
+
+// hsmKeyDesc replaces the public key of the passed key descriptor with the
+// one of the key with the same locator in the hardware security module, if
+// the key family is kept there.
+func (r *HSMKeyRing) hsmKeyDesc(keyDesc KeyDescriptor) (KeyDescriptor,
+	error) {
+
+	if _, ok := r.families[keyDesc.Family]; !ok {
+		return keyDesc, nil
+	}
+
+	pubKey, err := r.pubKey(keyDesc.KeyLocator)
+	if err != nil {
+		return KeyDescriptor{}, err
+	}
+	keyDesc.PubKey = pubKey
+
+	return keyDesc, nil
+}
+
+// DeriveNextKey attempts to derive the *next* key within the key family
+// (account in BIP43) specified. This method should return the next external
+// child within this branch.
+//
+// NOTE: This is part of the keychain.KeyRing interface.
+func (r *HSMKeyRing) DeriveNextKey(keyFam KeyFamily) (KeyDescriptor, error) {
+	keyDesc, err := r.fallback.DeriveNextKey(keyFam)
+	if err != nil {
+		return KeyDescriptor{}, err
+	}
+
+	return r.hsmKeyDesc(keyDesc)
+}
+
+// DeriveKey attempts to derive an arbitrary key specified by the passed
+// KeyLocator. This may be used in several recovery scenarios, or when manually
+// rotating something like our current default node key.
+//
+// NOTE: This is part of the keychain.KeyRing interface.
+func (r *HSMKeyRing) DeriveKey(keyLoc KeyLocator) (KeyDescriptor, error) {
+	if _, ok := r.families[keyLoc.Family]; !ok {
+		return r.fallback.DeriveKey(keyLoc)
+	}
+
+	return r.hsmKeyDesc(KeyDescriptor{KeyLocator: keyLoc})
+}
+
+// DerivePrivKey attempts to derive the private key that corresponds to the
+// passed key descriptor. The private keys of keys kept in the hardware
+// security module can't be derived.
+//
+// NOTE: This is part of the keychain.SecretKeyRing interface.
+func (r *HSMKeyRing) DerivePrivKey(keyDesc KeyDescriptor) (*btcec.PrivateKey,
+	error) {
+
+	if _, ok := r.families[keyDesc.Family]; ok {
+		return nil, ErrHSMKeyNotExportable
+	}
+
+	return r.fallback.DerivePrivKey(keyDesc)
+}
+
+// ECDH performs a scalar multiplication (ECDH-like operation) between the
+// target key descriptor and remote public key. The output returned will be
+// the sha256 of the resulting shared point serialized in compressed format.
+//
+// NOTE: This is part of the keychain.ECDHRing interface.
+func (r *HSMKeyRing) ECDH(keyDesc KeyDescriptor,
+	pub *btcec.PublicKey) ([32]byte, error) {
+
+	if !r.IsHSMKey(keyDesc) {
+		return r.fallback.ECDH(keyDesc, pub)
+	}
+
+	sharedPoint, err := r.hsm.ECDH(r.KeyLabel(keyDesc.KeyLocator), pub)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return sha256.Sum256(sharedPoint.SerializeCompressed()), nil
+}
+
+// SignDigest signs the given digest with the key with the given locator that
+// is kept in the hardware security module. The signature is normalized to a
+// low S value and verified before it is returned.
+func (r *HSMKeyRing) SignDigest(keyLoc KeyLocator,
+	digest [32]byte) (*ecdsa.Signature, error) {
+
+	sigR, sigS, err := r.signDigest(keyLoc, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return ecdsa.NewSignature(&sigR, &sigS), nil
+}
+
+// signDigest signs the given digest with the key with the given locator that
+// is kept in the hardware security module and returns the R and low S values
+// of the verified signature.
+func (r *HSMKeyRing) signDigest(keyLoc KeyLocator,
+	digest [32]byte) (btcec.ModNScalar, btcec.ModNScalar, error) {
+
+	var sigR, sigS btcec.ModNScalar
+
+	pubKey, err := r.pubKey(keyLoc)
+	if err != nil {
+		return sigR, sigS, err
+	}
+
+	rawSig, err := r.hsm.SignDigest(r.KeyLabel(keyLoc), digest)
+	if err != nil {
+		return sigR, sigS, err
+	}
+
+	if len(rawSig) != 64 {
+		return sigR, sigS, fmt.Errorf("invalid HSM signature length "+
+			"%d", len(rawSig))
+	}
+
+	if sigR.SetByteSlice(rawSig[:32]) || sigS.SetByteSlice(rawSig[32:]) {
+		return sigR, sigS, errors.New("HSM signature out of range")
+	}
+
+	// Bitcoin only accepts signatures with a low S value, which the
+	// module doesn't necessarily produce.
+	if sigS.IsOverHalfOrder() {
+		sigS.Negate()
+	}
+
+	sig := ecdsa.NewSignature(&sigR, &sigS)
+	if !sig.Verify(digest[:], pubKey) {
+		return sigR, sigS, errors.New("invalid HSM signature")
+	}
+
+	return sigR, sigS, nil
+}
+
+// messageDigest returns the single or double SHA256 hash of the message.
+func messageDigest(msg []byte, doubleHash bool) [32]byte {
+	if doubleHash {
+		return chainhash.DoubleHashH(msg)
+	}
+
+	return chainhash.HashH(msg)
+}
+
+// SignMessage signs the given message, single or double SHA256 hashing it
+// first, with the private key described in the key locator.
+//
+// NOTE: This is part of the keychain.MessageSignerRing interface.
+func (r *HSMKeyRing) SignMessage(keyLoc KeyLocator, msg []byte,
+	doubleHash bool) (*ecdsa.Signature, error) {
+
+	if _, ok := r.families[keyLoc.Family]; !ok {
+		return r.fallback.SignMessage(keyLoc, msg, doubleHash)
+	}
+
+	return r.SignDigest(keyLoc, messageDigest(msg, doubleHash))
+}
+
+// SignMessageCompact signs the given message, single or double SHA256 hashing
+// it first, with the private key described in the key locator and returns the
+// signature in the compact, public key recoverable format.
+//
+// NOTE: This is part of the keychain.MessageSignerRing interface.
+func (r *HSMKeyRing) SignMessageCompact(keyLoc KeyLocator, msg []byte,
+	doubleHash bool) ([]byte, error) {
+
+	if _, ok := r.families[keyLoc.Family]; !ok {
+		return r.fallback.SignMessageCompact(keyLoc, msg, doubleHash)
+	}
+
+	digest := messageDigest(msg, doubleHash)
+	sigR, sigS, err := r.signDigest(keyLoc, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, err := r.pubKey(keyLoc)
+	if err != nil {
+		return nil, err
+	}
+
+	// The module doesn't tell us the recovery code of the signature, so
+	// we find the one that recovers our public key.
+	var compactSig [65]byte
+	sigR.PutBytesUnchecked(compactSig[1:33])
+	sigS.PutBytesUnchecked(compactSig[33:65])
+
+	const compactMagicOffset = 27 + 4
+	for recoveryCode := byte(0); recoveryCode < 4; recoveryCode++ {
+		compactSig[0] = compactMagicOffset + recoveryCode
+
+		recovered, _, err := ecdsa.RecoverCompact(
+			compactSig[:], digest[:],
+		)
+		if err == nil && recovered.IsEqual(pubKey) {
+			return compactSig[:], nil
+		}
+	}
+
+	return nil, errors.New("unable to find recovery code of HSM " +
+		"signature")
+}
+
+// SignMessageSchnorr uses the Schnorr signature algorithm to sign the given
+// message, single or double SHA256 hashing it first, with the private key
+// described in the key locator and the optional tweak applied to the private
+// key. Keys kept in the hardware security module can't produce Schnorr
+// signatures.
+//
+// NOTE: This is part of the keychain.MessageSignerRing interface.
+func (r *HSMKeyRing) SignMessageSchnorr(keyLoc KeyLocator, msg []byte,
+	doubleHash bool, taprootTweak []byte) (*schnorr.Signature, error) {
+
+	if _, ok := r.families[keyLoc.Family]; ok {
+		return nil, ErrHSMSchnorrUnsupported
+	}
+
+	return r.fallback.SignMessageSchnorr(
+		keyLoc, msg, doubleHash, taprootTweak,
+	)
+}
+
+// sharedPointFromX returns the shared point of an ECDH operation between our
+// key pair with the given public key and the remote public key, given the x
+// coordinates of the shared points that the key pair computes with the remote
+// public key and with the remote public key plus the generator. This is needed
+// for modules that only return the x coordinate of the shared point, such as
+// PKCS#11 tokens with the CKM_ECDH1_DERIVE mechanism. Since k*(P+G) equals
+// k*P plus our public key k*G, only one of the two points with the first x
+// coordinate yields the second x coordinate when our public key is added.
+func sharedPointFromX(ourPubKey *btcec.PublicKey, x,
+	xPlusG []byte) (*btcec.PublicKey, error) {
+
+	var ourPoint btcec.JacobianPoint
+	ourPubKey.AsJacobian(&ourPoint)
+
+	for _, prefix := range []byte{0x02, 0x03} {
+		candidate, err := btcec.ParsePubKey(append([]byte{prefix}, x...))
+		if err != nil {
+			return nil, fmt.Errorf("invalid shared point: %w", err)
+		}
+
+		var candidatePoint, sum btcec.JacobianPoint
+		candidate.AsJacobian(&candidatePoint)
+		btcec.AddNonConst(&candidatePoint, &ourPoint, &sum)
+		sum.ToAffine()
+
+		if bytes.Equal(sum.X.Bytes()[:], xPlusG) {
+			return candidate, nil
+		}
+	}
+
+	return nil, errors.New("shared points don't match")
+}
+
+// pubKeyPlusGenerator returns the sum of the given public key and the
+// generator of the secp256k1 curve.
+func pubKeyPlusGenerator(pubKey *btcec.PublicKey) (*btcec.PublicKey, error) {
+	var (
+		one                      btcec.ModNScalar
+		point, generator, result btcec.JacobianPoint
+	)
+	one.SetInt(1)
+	btcec.ScalarBaseMultNonConst(&one, &generator)
+	pubKey.AsJacobian(&point)
+	btcec.AddNonConst(&point, &generator, &result)
+
+	if (result.X.IsZero() && result.Y.IsZero()) || result.Z.IsZero() {
+		return nil, errors.New("public key is the negated generator")
+	}
+	result.ToAffine()
+
+	return btcec.NewPublicKey(&result.X, &result.Y), nil
+}
+
+// HealthCheck checks that the hardware security module can be reached.
+func (r *HSMKeyRing) HealthCheck() error {
+	return r.hsm.Ping()
+}
@@ -0,0 +1,371 @@
+//go:build hsm
+// +build hsm
+
+package keychain
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/miekg/pkcs11"
+)
+
+var (
+	// secp256k1OID is the DER encoded object identifier of the secp256k1
+	// curve, which is used as the CKA_EC_PARAMS of our keys.
+	secp256k1OID = []byte{0x06, 0x05, 0x2b, 0x81, 0x04, 0x00, 0x0a}
+)
+
+// pkcs11HSM is an HSM that keeps the keys in a PKCS#11 token. All operations
+// are performed within a single session that is logged in as the user of the
+// token.
+type pkcs11HSM struct {
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+
+	// mu serializes the operations within the session, as PKCS#11
+	// sessions must not be used concurrently.
+	mu sync.Mutex
+}
+
+// A compile time check to ensure pkcs11HSM implements the HSM interface.
+var _ HSM = (*pkcs11HSM)(nil)
+
+// OpenPKCS11HSM loads the PKCS#11 module at the given path and opens a session
+// with the token with the given label that is logged in with the given user
+// PIN. The returned function closes the session and unloads the module.
+func OpenPKCS11HSM(modulePath, tokenLabel, pin string) (HSM, func(),
+	error) {
+
+	ctx := pkcs11.New(modulePath)
+	if ctx == nil {
+		return nil, nil, fmt.Errorf("unable to load PKCS#11 module %v",
+			modulePath)
+	}
+
+	if err := ctx.Initialize(); err != nil {
+		ctx.Destroy()
+		return nil, nil, fmt.Errorf("unable to initialize PKCS#11 "+
+			"module: %w", err)
+	}
+
+	session, err := openSession(ctx, tokenLabel, pin)
+	if err != nil {
+		_ = ctx.Finalize()
+		ctx.Destroy()
+		return nil, nil, err
+	}
+
+	cleanUp := func() {
+		_ = ctx.Logout(session)
+		_ = ctx.CloseSession(session)
+		_ = ctx.Finalize()
+		ctx.Destroy()
+	}
+
+	return &pkcs11HSM{
+		ctx:     ctx,
+		session: session,
+	}, cleanUp, nil
+}
+
+// openSession opens a read/write session with the token with the given label
+// and logs in as its user.
+func openSession(ctx *pkcs11.Ctx, tokenLabel,
+	pin string) (pkcs11.SessionHandle, error) {
+
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("unable to list PKCS#11 slots: %w", err)
+	}
+
+	for _, slot := range slots {
+		tokenInfo, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, fmt.Errorf("unable to get info of token in "+
+				"slot %d: %w", slot, err)
+		}
+
+		if tokenInfo.Label != tokenLabel {
+			continue
+		}
+
+		session, err := ctx.OpenSession(
+			slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("unable to open session with "+
+				"token %v: %w", tokenLabel, err)
+		}
+
+		// Sessions with a token share their login state, so another
+		// session of this process may have logged in already.
+		alreadyLoggedIn := pkcs11.Error(
+			pkcs11.CKR_USER_ALREADY_LOGGED_IN,
+		)
+		err = ctx.Login(session, pkcs11.CKU_USER, pin)
+		if err != nil && !errors.Is(err, alreadyLoggedIn) {
+			_ = ctx.CloseSession(session)
+			return 0, fmt.Errorf("unable to log into token %v: %w",
+				tokenLabel, err)
+		}
+
+		return session, nil
+	}
+
+	return 0, fmt.Errorf("no PKCS#11 token with label %v found",
+		tokenLabel)
+}
+
+// findKey returns the handle of the key object of the given class with the
+// given label, or false if there is no such object.
+//
+// NOTE: The caller must hold the mutex.
+func (h *pkcs11HSM) findKey(label string, class uint) (pkcs11.ObjectHandle,
+	bool, error) {
+
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+	if err := h.ctx.FindObjectsInit(h.session, template); err != nil {
+		return 0, false, err
+	}
+
+	objects, _, err := h.ctx.FindObjects(h.session, 1)
+	if finalErr := h.ctx.FindObjectsFinal(h.session); err == nil {
+		err = finalErr
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	if len(objects) == 0 {
+		return 0, false, nil
+	}
+
+	return objects[0], true, nil
+}
+
+// generateKey generates a secp256k1 key pair with the given label within the
+// token. The private key can't be extracted from the token.
+//
+// NOTE: The caller must hold the mutex.
+func (h *pkcs11HSM) generateKey(label string) (pkcs11.ObjectHandle, error) {
+	publicTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, secp256k1OID),
+		pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+	}
+	privateTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_DERIVE, true),
+	}
+
+	publicKey, _, err := h.ctx.GenerateKeyPair(
+		h.session, []*pkcs11.Mechanism{
+			pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil),
+		}, publicTemplate, privateTemplate,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("unable to generate key %v: %w", label,
+			err)
+	}
+
+	return publicKey, nil
+}
+
+// PubKey returns the public key of the key pair with the given label. If no
+// such key pair exists yet, it is generated within the module.
+//
+// NOTE: This is part of the HSM interface.
+func (h *pkcs11HSM) PubKey(label string) (*btcec.PublicKey, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	publicKey, ok, err := h.findKey(label, pkcs11.CKO_PUBLIC_KEY)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		publicKey, err = h.generateKey(label)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	attrs, err := h.ctx.GetAttributeValue(
+		h.session, publicKey, []*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The point is supposed to be wrapped in a DER octet string, which
+	// not all modules do.
+	point := attrs[0].Value
+	if len(point) == 67 && point[0] == 0x04 && point[1] == 0x41 {
+		point = point[2:]
+	}
+
+	return btcec.ParsePubKey(point)
+}
+
+// privateKey returns the handle of the private key with the given label.
+//
+// NOTE: The caller must hold the mutex.
+func (h *pkcs11HSM) privateKey(label string) (pkcs11.ObjectHandle, error) {
+	privateKey, ok, err := h.findKey(label, pkcs11.CKO_PRIVATE_KEY)
+	if err != nil {
+		return 0, err
+	}
+
+	if !ok {
+		return 0, fmt.Errorf("private key %v not found", label)
+	}
+
+	return privateKey, nil
+}
+
+// SignDigest signs the given digest with the private key of the key pair with
+// the given label and returns the signature in the raw r || s format produced
+// by the CKM_ECDSA mechanism.
+//
+// NOTE: This is part of the HSM interface.
+func (h *pkcs11HSM) SignDigest(label string, digest [32]byte) ([]byte,
+	error) {
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	privateKey, err := h.privateKey(label)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.ctx.SignInit(h.session, []*pkcs11.Mechanism{
+		pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil),
+	}, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.ctx.Sign(h.session, digest[:])
+}
+
+// deriveX returns the x coordinate of the shared point of the private key with
+// the given handle and the given public key.
+//
+// NOTE: The caller must hold the mutex.
+func (h *pkcs11HSM) deriveX(privateKey pkcs11.ObjectHandle,
+	pubKey *btcec.PublicKey) ([]byte, error) {
+
+	params := pkcs11.NewECDH1DeriveParams(
+		pkcs11.CKD_NULL, nil, pubKey.SerializeUncompressed(),
+	)
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(
+			pkcs11.CKA_KEY_TYPE, pkcs11.CKK_GENERIC_SECRET,
+		),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, false),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE_LEN, 32),
+	}
+
+	secret, err := h.ctx.DeriveKey(h.session, []*pkcs11.Mechanism{
+		pkcs11.NewMechanism(pkcs11.CKM_ECDH1_DERIVE, params),
+	}, privateKey, template)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = h.ctx.DestroyObject(h.session, secret)
+	}()
+
+	attrs, err := h.ctx.GetAttributeValue(
+		h.session, secret, []*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_VALUE, nil),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return attrs[0].Value, nil
+}
+
+// ECDH multiplies the private key of the key pair with the given label with
+// the given public key and returns the resulting shared point. As the
+// CKM_ECDH1_DERIVE mechanism only returns the x coordinate of the point, a
+// second derivation with the public key plus the generator is used to recover
+// the full point.
+//
+// NOTE: This is part of the HSM interface.
+func (h *pkcs11HSM) ECDH(label string,
+	pubKey *btcec.PublicKey) (*btcec.PublicKey, error) {
+
+	pubKeyPlusG, err := pubKeyPlusGenerator(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ourPubKey, err := h.PubKey(label)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	privateKey, err := h.privateKey(label)
+	if err != nil {
+		return nil, err
+	}
+
+	x, err := h.deriveX(privateKey, pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	xPlusG, err := h.deriveX(privateKey, pubKeyPlusG)
+	if err != nil {
+		return nil, err
+	}
+
+	return sharedPointFromX(ourPubKey, x, xPlusG)
+}
+
+// Ping checks that the module can be reached and that our session with it is
+// still valid.
+//
+// NOTE: This is part of the HSM interface.
+func (h *pkcs11HSM) Ping() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	info, err := h.ctx.GetSessionInfo(h.session)
+	if err != nil {
+		return err
+	}
+
+	if info.State != pkcs11.CKS_RW_USER_FUNCTIONS {
+		return fmt.Errorf("PKCS#11 session isn't logged in, state=%d",
+			info.State)
+	}
+
+	return nil
+}
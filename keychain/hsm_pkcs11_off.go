@@ -0,0 +1,15 @@
+//go:build !hsm
+// +build !hsm
+
+package keychain
+
+import (
+	"errors"
+)
+
+// OpenPKCS11HSM is required for lnd to compile so that the cgo based PKCS#11
+// binding can be hidden behind a build tag.
+func OpenPKCS11HSM(_, _, _ string) (HSM, func(), error) {
+	return nil, nil, errors.New("lnd must be built with the hsm tag to " +
+		"keep keys in a hardware security module")
+}
@@ -0,0 +1,280 @@
+package keychain
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/require"
+)
+
+// mockHSM is an in-memory HSM that derives its keys from their labels. It
+// returns signatures with a high S value to make sure they are normalized.
+type mockHSM struct{}
+
+func (m *mockHSM) privKey(label string) *btcec.PrivateKey {
+	seed := sha256.Sum256([]byte(label))
+	privKey, _ := btcec.PrivKeyFromBytes(seed[:])
+
+	return privKey
+}
+
+func (m *mockHSM) PubKey(label string) (*btcec.PublicKey, error) {
+	return m.privKey(label).PubKey(), nil
+}
+
+func (m *mockHSM) SignDigest(label string, digest [32]byte) ([]byte, error) {
+	compactSig, err := ecdsa.SignCompact(
+		m.privKey(label), digest[:], true,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Strip the recovery code and negate S.
+	var s btcec.ModNScalar
+	s.SetByteSlice(compactSig[33:])
+	s.Negate()
+
+	var rawSig [64]byte
+	copy(rawSig[:32], compactSig[1:33])
+	s.PutBytesUnchecked(rawSig[32:])
+
+	return rawSig[:], nil
+}
+
+func (m *mockHSM) ECDH(label string,
+	pubKey *btcec.PublicKey) (*btcec.PublicKey, error) {
+
+	var point, result btcec.JacobianPoint
+	pubKey.AsJacobian(&point)
+	btcec.ScalarMultNonConst(&m.privKey(label).Key, &point, &result)
+	result.ToAffine()
+
+	return btcec.NewPublicKey(&result.X, &result.Y), nil
+}
+
+func (m *mockHSM) Ping() error {
+	return nil
+}
+
+// mockFallbackKeyRing is an in-memory SecretKeyRing that derives its keys
+// from their locators.
+type mockFallbackKeyRing struct {
+	SecretKeyRing
+
+	nextIndex uint32
+}
+
+func (m *mockFallbackKeyRing) privKey(keyLoc KeyLocator) *btcec.PrivateKey {
+	var locBytes [8]byte
+	binary.BigEndian.PutUint32(locBytes[:4], uint32(keyLoc.Family))
+	binary.BigEndian.PutUint32(locBytes[4:], keyLoc.Index)
+	seed := sha256.Sum256(locBytes[:])
+	privKey, _ := btcec.PrivKeyFromBytes(seed[:])
+
+	return privKey
+}
+
+func (m *mockFallbackKeyRing) DeriveNextKey(
+	keyFam KeyFamily) (KeyDescriptor, error) {
+
+	m.nextIndex++
+
+	return m.DeriveKey(KeyLocator{Family: keyFam, Index: m.nextIndex})
+}
+
+func (m *mockFallbackKeyRing) DeriveKey(
+	keyLoc KeyLocator) (KeyDescriptor, error) {
+
+	return KeyDescriptor{
+		KeyLocator: keyLoc,
+		PubKey:     m.privKey(keyLoc).PubKey(),
+	}, nil
+}
+
+func (m *mockFallbackKeyRing) DerivePrivKey(
+	keyDesc KeyDescriptor) (*btcec.PrivateKey, error) {
+
+	return m.privKey(keyDesc.KeyLocator), nil
+}
+
+// TestHSMKeyRing asserts that the HSM key ring serves the keys of the HSM key
+// families from the HSM, and all other keys from the fallback key ring.
+func TestHSMKeyRing(t *testing.T) {
+	t.Parallel()
+
+	hsm := &mockHSM{}
+	fallback := &mockFallbackKeyRing{}
+	keyRing := NewHSMKeyRing(
+		hsm, fallback, "lnd", DefaultHSMKeyFamilies...,
+	)
+
+	nodeKeyLoc := KeyLocator{Family: KeyFamilyNodeKey}
+	nodeKeyLabel := fmt.Sprintf("lnd-%d-0", KeyFamilyNodeKey)
+	require.Equal(t, nodeKeyLabel, keyRing.KeyLabel(nodeKeyLoc))
+
+	hsmNodeKey, err := hsm.PubKey(nodeKeyLabel)
+	require.NoError(t, err)
+
+	// The node key must be served from the HSM.
+	nodeKeyDesc, err := keyRing.DeriveKey(nodeKeyLoc)
+	require.NoError(t, err)
+	require.Equal(t, nodeKeyLoc, nodeKeyDesc.KeyLocator)
+	require.True(t, hsmNodeKey.IsEqual(nodeKeyDesc.PubKey))
+	require.True(t, keyRing.IsHSMKey(nodeKeyDesc))
+
+	_, err = keyRing.DerivePrivKey(nodeKeyDesc)
+	require.ErrorIs(t, err, ErrHSMKeyNotExportable)
+
+	// New multisig keys get their index from the fallback key ring, but
+	// their public key from the HSM.
+	multiSigDesc, err := keyRing.DeriveNextKey(KeyFamilyMultiSig)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), multiSigDesc.Index)
+
+	hsmMultiSigKey, err := hsm.PubKey(
+		keyRing.KeyLabel(multiSigDesc.KeyLocator),
+	)
+	require.NoError(t, err)
+	require.True(t, hsmMultiSigKey.IsEqual(multiSigDesc.PubKey))
+
+	// The revocation root is still served by the fallback key ring, and
+	// its private key is available.
+	revRootLoc := KeyLocator{Family: KeyFamilyRevocationRoot}
+	revRootDesc, err := keyRing.DeriveKey(revRootLoc)
+	require.NoError(t, err)
+	require.False(t, keyRing.IsHSMKey(revRootDesc))
+
+	fallbackDesc, err := fallback.DeriveKey(revRootLoc)
+	require.NoError(t, err)
+	require.Equal(t, fallbackDesc, revRootDesc)
+
+	revRootPriv, err := keyRing.DerivePrivKey(revRootDesc)
+	require.NoError(t, err)
+	require.True(t, revRootPriv.PubKey().IsEqual(revRootDesc.PubKey))
+
+	// A descriptor of an HSM key family with a public key that doesn't
+	// match the one in the HSM isn't an HSM key.
+	require.False(t, keyRing.IsHSMKey(KeyDescriptor{
+		KeyLocator: nodeKeyLoc,
+		PubKey:     revRootDesc.PubKey,
+	}))
+}
+
+// TestHSMKeyRingSigning asserts that the signatures and shared secrets of keys
+// kept in the HSM are created by the HSM.
+func TestHSMKeyRingSigning(t *testing.T) {
+	t.Parallel()
+
+	hsm := &mockHSM{}
+	keyRing := NewHSMKeyRing(
+		hsm, &mockFallbackKeyRing{}, "lnd", DefaultHSMKeyFamilies...,
+	)
+
+	nodeKeyLoc := KeyLocator{Family: KeyFamilyNodeKey}
+	nodeKeyDesc, err := keyRing.DeriveKey(nodeKeyLoc)
+	require.NoError(t, err)
+
+	msg := []byte("message to sign")
+
+	// The high S value returned by the HSM must be normalized, which the
+	// signature parser enforces.
+	sig, err := keyRing.SignMessage(nodeKeyLoc, msg, true)
+	require.NoError(t, err)
+
+	digest := chainhash.DoubleHashB(msg)
+	require.True(t, sig.Verify(digest, nodeKeyDesc.PubKey))
+
+	_, err = ecdsa.ParseDERSignature(sig.Serialize())
+	require.NoError(t, err)
+
+	// The compact signature must recover the node key.
+	compactSig, err := keyRing.SignMessageCompact(nodeKeyLoc, msg, false)
+	require.NoError(t, err)
+
+	recovered, _, err := ecdsa.RecoverCompact(
+		compactSig, chainhash.HashB(msg),
+	)
+	require.NoError(t, err)
+	require.True(t, recovered.IsEqual(nodeKeyDesc.PubKey))
+
+	// The ECDH shared secret must match the one computed with the remote
+	// private key.
+	remotePriv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	secret, err := keyRing.ECDH(nodeKeyDesc, remotePriv.PubKey())
+	require.NoError(t, err)
+
+	remoteECDH := PrivKeyECDH{PrivKey: remotePriv}
+	expectedSecret, err := remoteECDH.ECDH(nodeKeyDesc.PubKey)
+	require.NoError(t, err)
+	require.Equal(t, expectedSecret, secret)
+
+	// Schnorr signatures can't be created in the HSM.
+	_, err = keyRing.SignMessageSchnorr(nodeKeyLoc, msg, false, nil)
+	require.ErrorIs(t, err, ErrHSMSchnorrUnsupported)
+
+	// The message signer of the node key can be used with the HSM key
+	// ring.
+	signer := NewPubKeyMessageSigner(
+		nodeKeyDesc.PubKey, nodeKeyLoc, keyRing,
+	)
+	sig, err = signer.SignMessage(msg, false)
+	require.NoError(t, err)
+	require.True(t, sig.Verify(chainhash.HashB(msg), nodeKeyDesc.PubKey))
+}
+
+// TestSharedPointFromX asserts that the full shared point of an ECDH operation
+// is recovered from the x coordinates that a PKCS#11 token returns.
+func TestSharedPointFromX(t *testing.T) {
+	t.Parallel()
+
+	hsm := &mockHSM{}
+
+	for i := 0; i < 20; i++ {
+		label := fmt.Sprintf("key-%d", i)
+		ourPubKey, err := hsm.PubKey(label)
+		require.NoError(t, err)
+
+		remoteKey := hsm.privKey(fmt.Sprintf("remote-%d", i))
+		remotePubKey := remoteKey.PubKey()
+
+		sharedPoint, err := hsm.ECDH(label, remotePubKey)
+		require.NoError(t, err)
+
+		// The sum of the remote key and the generator is the public
+		// key of the remote private key plus one.
+		remotePlusG, err := pubKeyPlusGenerator(remotePubKey)
+		require.NoError(t, err)
+
+		var one, remotePlusOne btcec.ModNScalar
+		one.SetInt(1)
+		remotePlusOne.Add2(&remoteKey.Key, &one)
+		expectedPlusG := btcec.PrivKeyFromScalar(&remotePlusOne).PubKey()
+		require.True(t, remotePlusG.IsEqual(expectedPlusG))
+
+		sharedPointPlusG, err := hsm.ECDH(label, remotePlusG)
+		require.NoError(t, err)
+
+		recovered, err := sharedPointFromX(
+			ourPubKey, sharedPoint.SerializeCompressed()[1:],
+			sharedPointPlusG.SerializeCompressed()[1:],
+		)
+		require.NoError(t, err)
+		require.True(t, recovered.IsEqual(sharedPoint))
+	}
+
+	// Mismatching x coordinates are rejected.
+	ourPubKey, err := hsm.PubKey("key-0")
+	require.NoError(t, err)
+
+	x := ourPubKey.SerializeCompressed()[1:]
+	_, err = sharedPointFromX(ourPubKey, x, x)
+	require.Error(t, err)
+}
@@ -71,6 +71,17 @@ type BoltBackendConfig struct {
 	// be considered again.
 	AutoCompactMinAge time.Duration
 
+	// CheckIntegrity specifies if the integrity of an existing bolt
+	// database file should be checked on startup. If the file is
+	// corrupted, a CorruptionError is returned instead of opening it.
+	CheckIntegrity bool
+
+	// SalvageCorrupt specifies if a corrupted bolt database file should be
+	// salvaged into a new file instead of failing the startup. The
+	// corrupted file is kept and a report of the lost buckets is written
+	// next to the database file. This implies CheckIntegrity.
+	SalvageCorrupt bool
+
 	// DBTimeout specifies the timeout value to use when opening the wallet
 	// database.
 	DBTimeout time.Duration
@@ -95,8 +106,15 @@ func GetBoltBackend(cfg *BoltBackendConfig) (Backend, error) {
 		)
 	}
 
-	// This is an existing database. We might want to compact it on startup
-	// to free up some space.
+	// This is an existing database. Before we touch it, we might want to
+	// make sure it isn't corrupted.
+	if cfg.CheckIntegrity || cfg.SalvageCorrupt {
+		if err := checkAndSalvage(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	// We might also want to compact it on startup to free up some space.
 	if cfg.AutoCompact {
 		if err := compactAndSwap(cfg); err != nil {
 			return nil, err
@@ -32,6 +32,17 @@ type BoltBackendConfig struct {
 	// be considered again.
 	AutoCompactMinAge time.Duration
 
+	// CheckIntegrity specifies if the integrity of an existing bolt
+	// database file should be checked on startup. If the file is
+	// corrupted, a CorruptionError is returned instead of opening it.
+	CheckIntegrity bool
+
+	// SalvageCorrupt specifies if a corrupted bolt database file should be
+	// salvaged into a new file instead of failing the startup. The
+	// corrupted file is kept and a report of the lost buckets is written
+	// next to the database file. This implies CheckIntegrity.
+	SalvageCorrupt bool
+
 	// DBTimeout specifies the timeout value to use when opening the wallet
 	// database.
 	DBTimeout time.Duration
@@ -46,6 +46,10 @@ type compacter struct {
 
 	// dbTimeout specifies the timeout value used when opening the db.
 	dbTimeout time.Duration
+
+	// salvager, if set, is used to walk the source database instead of
+	// the regular walk, skipping the parts of it that are corrupted.
+	salvager *salvager
 }
 
 // execute opens the source and destination databases and then compacts the
@@ -85,7 +89,7 @@ func (cmd *compacter) execute() (int64, int64, error) {
 
 	// Open source database. We open it in read only mode to avoid (and fix)
 	// possible freelist sync problems.
-	src, err := bbolt.Open(cmd.srcPath, 0444, &bbolt.Options{
+	src, err := openRecovering(cmd.srcPath, 0444, &bbolt.Options{
 		ReadOnly: true,
 		Timeout:  cmd.dbTimeout,
 	})
@@ -144,7 +148,12 @@ func (cmd *compacter) compact(dst, src *bbolt.DB) error {
 		_ = tx.Rollback()
 	}()
 
-	if err := cmd.walk(src, func(keys [][]byte, k, v []byte, seq uint64) error {
+	walk := cmd.walk
+	if cmd.salvager != nil {
+		walk = cmd.salvager.walk
+	}
+
+	if err := walk(src, func(keys [][]byte, k, v []byte, seq uint64) error {
 		// On each key/value, check if we have exceeded tx size.
 		sz := int64(len(k) + len(v))
 		if size+sz > cmd.txMaxSize && cmd.txMaxSize != 0 {
@@ -0,0 +1,434 @@
+//go:build !js
+// +build !js
+
+package kvdb
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// DefaultSalvageDBFileName is the default name of the temporary bolt
+	// DB file that we'll salvage the content of a corrupted DB file into.
+	DefaultSalvageDBFileName = "temp-salvage-dont-use.db"
+
+	// CorruptDBFileNameSuffix is the suffix we append to the file name of
+	// a corrupted database file, followed by the unix timestamp of the
+	// salvage, when we replace it with its salvaged copy.
+	CorruptDBFileNameSuffix = ".corrupt"
+
+	// SalvageReportFileNameSuffix is the suffix we append to the file name
+	// of a database file to store the report of its last salvage.
+	SalvageReportFileNameSuffix = ".salvage-report"
+
+	// maxIntegrityErrors is the maximum number of errors reported by the
+	// integrity check of a database file. A single corrupt page usually
+	// causes many follow-up errors that don't tell us anything new.
+	maxIntegrityErrors = 100
+)
+
+var (
+	// errBoltPanic is returned when bbolt panics while opening a database
+	// file.
+	errBoltPanic = errors.New("bbolt panicked")
+)
+
+// CorruptionError is returned when the integrity check of a bolt database file
+// fails and salvaging corrupt files isn't enabled.
+type CorruptionError struct {
+	// DBFile is the path of the corrupted database file.
+	DBFile string
+
+	// IntegrityErrors are the problems found by the integrity check.
+	IntegrityErrors []string
+}
+
+// Error returns a human readable description of the corruption.
+//
+// NOTE: This is part of the error interface.
+func (e *CorruptionError) Error() string {
+	return fmt.Sprintf("database file %v is corrupted (%d integrity "+
+		"errors, first: %v), enable salvaging of corrupt databases to "+
+		"attempt a recovery into a new file", e.DBFile,
+		len(e.IntegrityErrors), e.IntegrityErrors[0])
+}
+
+// SalvageReport describes the outcome of salvaging a corrupted database file.
+type SalvageReport struct {
+	// DBFile is the path of the database file that was salvaged.
+	DBFile string
+
+	// CorruptDBFile is the path the corrupted database file was moved to.
+	CorruptDBFile string
+
+	// IntegrityErrors are the problems found by the integrity check that
+	// triggered the salvage.
+	IntegrityErrors []string
+
+	// SalvagedBuckets is the number of buckets that were copied into the
+	// salvaged database file.
+	SalvagedBuckets int
+
+	// SalvagedKeys is the number of key/value pairs that were copied into
+	// the salvaged database file.
+	SalvagedKeys int
+
+	// LostBuckets are the buckets that couldn't be read completely, along
+	// with the reason. Some or all of their content is missing from the
+	// salvaged database file.
+	LostBuckets []string
+}
+
+// String returns the report in the format that is written to the report file.
+func (r *SalvageReport) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Salvage of %v\n", r.DBFile)
+	fmt.Fprintf(&b, "Corrupted file kept at: %v\n", r.CorruptDBFile)
+	fmt.Fprintf(&b, "Salvaged buckets: %d\n", r.SalvagedBuckets)
+	fmt.Fprintf(&b, "Salvaged keys: %d\n", r.SalvagedKeys)
+
+	fmt.Fprintf(&b, "\nIntegrity errors (%d):\n", len(r.IntegrityErrors))
+	for _, e := range r.IntegrityErrors {
+		fmt.Fprintf(&b, "  %v\n", e)
+	}
+
+	fmt.Fprintf(&b, "\nLost buckets (%d):\n", len(r.LostBuckets))
+	for _, bucket := range r.LostBuckets {
+		fmt.Fprintf(&b, "  %v\n", bucket)
+	}
+
+	return b.String()
+}
+
+// checkAndSalvage checks the integrity of an existing bolt database file. If
+// the file is corrupted, it is either salvaged into a new file or a
+// CorruptionError is returned, depending on the configuration.
+func checkAndSalvage(cfg *BoltBackendConfig) error {
+	dbFilePath := filepath.Join(cfg.DBPath, cfg.DBFileName)
+
+	log.Infof("Checking integrity of database file at %v", dbFilePath)
+
+	start := time.Now()
+	integrityErrs, err := checkIntegrity(dbFilePath, cfg.DBTimeout)
+	if err != nil {
+		return fmt.Errorf("unable to check integrity of database file "+
+			"%v: %w", dbFilePath, err)
+	}
+
+	if len(integrityErrs) == 0 {
+		log.Infof("Integrity check of %v passed in %v", dbFilePath,
+			time.Since(start).Truncate(time.Millisecond))
+
+		return nil
+	}
+
+	for _, integrityErr := range integrityErrs {
+		log.Errorf("Integrity check of %v failed: %v", dbFilePath,
+			integrityErr)
+	}
+
+	if !cfg.SalvageCorrupt {
+		return &CorruptionError{
+			DBFile:          dbFilePath,
+			IntegrityErrors: integrityErrs,
+		}
+	}
+
+	report, err := salvageAndSwap(cfg, integrityErrs)
+	if err != nil {
+		return fmt.Errorf("unable to salvage corrupted database file "+
+			"%v: %w", dbFilePath, err)
+	}
+
+	log.Warnf("Salvaged %d buckets and %d keys of corrupted database "+
+		"file %v, %d buckets were lost, see %s%s for the full report",
+		report.SalvagedBuckets, report.SalvagedKeys, dbFilePath,
+		len(report.LostBuckets), dbFilePath,
+		SalvageReportFileNameSuffix)
+
+	return nil
+}
+
+// checkIntegrity checks the bolt database file at the given path for
+// corruption and returns the problems found. An error is only returned if the
+// check itself couldn't be run.
+func checkIntegrity(dbFilePath string,
+	timeout time.Duration) (integrityErrs []string, err error) {
+
+	// bbolt opens the database with the valid one of its two meta pages,
+	// and only fails if both of them are corrupted. We open the database
+	// writable, so that bbolt loads the freelist the consistency check
+	// relies on, but never start a write transaction. Not syncing the
+	// freelist makes sure bbolt doesn't write it on open either.
+	db, err := openRecovering(dbFilePath, 0600, &bbolt.Options{
+		NoFreelistSync: true,
+		Timeout:        timeout,
+	})
+	switch {
+	case errors.Is(err, bbolt.ErrInvalid),
+		errors.Is(err, bbolt.ErrChecksum),
+		errors.Is(err, bbolt.ErrVersionMismatch),
+		errors.Is(err, errBoltPanic):
+
+		return []string{err.Error()}, nil
+
+	case err != nil:
+		return nil, err
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Errorf("Integrity check error: closing DB: %v", err)
+		}
+	}()
+
+	// First walk all buckets to make sure their pages can be read. bbolt's
+	// consistency check runs in its own goroutine, where a corrupt page
+	// would crash the whole process instead of being recovered from.
+	s := &salvager{}
+	err = s.walk(db, func(_ [][]byte, _, _ []byte, _ uint64) error {
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(s.lostBuckets) > 0 {
+		for _, bucket := range s.lostBuckets {
+			integrityErrs = appendIntegrityErr(
+				integrityErrs, "unreadable bucket "+bucket,
+			)
+		}
+
+		return integrityErrs, nil
+	}
+
+	// With all pages known to be readable, we can check that every page
+	// is either in use exactly once or free.
+	err = db.View(func(tx *bbolt.Tx) error {
+		for checkErr := range tx.Check() {
+			integrityErrs = appendIntegrityErr(
+				integrityErrs, checkErr.Error(),
+			)
+		}
+
+		return nil
+	})
+
+	return integrityErrs, err
+}
+
+// appendIntegrityErr appends the integrity error to the list, unless it
+// already contains the maximum number of errors.
+func appendIntegrityErr(integrityErrs []string, e string) []string {
+	if len(integrityErrs) >= maxIntegrityErrors {
+		return integrityErrs
+	}
+
+	return append(integrityErrs, e)
+}
+
+// salvageAndSwap copies everything that can still be read from a corrupted
+// database file into a new file, then moves the corrupted file aside and
+// replaces it with the salvaged copy. A report of the salvage is written next
+// to the database file.
+func salvageAndSwap(cfg *BoltBackendConfig,
+	integrityErrs []string) (*SalvageReport, error) {
+
+	sourceFilePath := filepath.Join(cfg.DBPath, cfg.DBFileName)
+	tempDestFilePath := filepath.Join(cfg.DBPath, DefaultSalvageDBFileName)
+
+	log.Infof("Salvaging corrupted database file at %v", sourceFilePath)
+
+	// If an old salvaged file still exists, a previous salvage didn't
+	// complete and we'll start over.
+	if fileExists(tempDestFilePath) {
+		log.Infof("Found old salvage DB @ %v, removing before salvage",
+			tempDestFilePath)
+
+		if err := os.Remove(tempDestFilePath); err != nil {
+			return nil, fmt.Errorf("unable to remove old salvage "+
+				"DB file: %v", err)
+		}
+	}
+	defer func() {
+		// This will only succeed if the salvage or the swap below
+		// fails.
+		_ = os.Remove(tempDestFilePath)
+	}()
+
+	s := &salvager{}
+	c := &compacter{
+		srcPath:   sourceFilePath,
+		dstPath:   tempDestFilePath,
+		dbTimeout: cfg.DBTimeout,
+		salvager:  s,
+	}
+	if _, _, err := c.execute(); err != nil {
+		return nil, err
+	}
+
+	report := &SalvageReport{
+		DBFile: sourceFilePath,
+		CorruptDBFile: fmt.Sprintf("%s%s-%d", sourceFilePath,
+			CorruptDBFileNameSuffix, time.Now().Unix()),
+		IntegrityErrors: integrityErrs,
+		SalvagedBuckets: s.buckets,
+		SalvagedKeys:    s.keys,
+		LostBuckets:     s.lostBuckets,
+	}
+
+	// We keep the corrupted file around, the salvage might have missed
+	// data that can still be recovered manually.
+	log.Infof("Moving corrupted DB file from %v to %v", sourceFilePath,
+		report.CorruptDBFile)
+
+	err := os.Rename(sourceFilePath, report.CorruptDBFile)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Swapping salvaged DB file from %v to %v", tempDestFilePath,
+		sourceFilePath)
+
+	if err := os.Rename(tempDestFilePath, sourceFilePath); err != nil {
+		// Put the corrupted file back, so we don't leave the node
+		// without a database file.
+		if rerr := os.Rename(
+			report.CorruptDBFile, sourceFilePath,
+		); rerr != nil {
+			log.Errorf("Unable to restore corrupted DB file %v: %v",
+				report.CorruptDBFile, rerr)
+		}
+
+		return nil, err
+	}
+
+	// The report is only informational, so not being able to write it
+	// shouldn't fail the startup.
+	reportFile := sourceFilePath + SalvageReportFileNameSuffix
+	err = ioutil.WriteFile(reportFile, []byte(report.String()), 0600)
+	if err != nil {
+		log.Warnf("Could not write salvage report to %v: %v",
+			reportFile, err)
+	}
+
+	return report, nil
+}
+
+// openRecovering opens a bolt database file, turning the panics caused by a
+// corrupted file into an error.
+func openRecovering(path string, mode os.FileMode,
+	options *bbolt.Options) (db *bbolt.DB, err error) {
+
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", errBoltPanic, r)
+		}
+	}()
+
+	return bbolt.Open(path, mode, options)
+}
+
+// salvager walks a possibly corrupted bolt database and skips the buckets
+// that can't be read completely.
+type salvager struct {
+	// buckets is the number of buckets that were walked.
+	buckets int
+
+	// keys is the number of key/value pairs that were walked.
+	keys int
+
+	// lostBuckets are the buckets that couldn't be read completely, along
+	// with the reason.
+	lostBuckets []string
+}
+
+// walk walks recursively the bolt database db, calling walkFn for each key it
+// can read.
+func (s *salvager) walk(db *bbolt.DB, walkFn walkFunc) error {
+	// Corrupt pages make bbolt panic or access memory outside of the
+	// mmap of the file. We turn the latter into a panic as well, so that
+	// we can recover from both.
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+
+	return db.View(func(tx *bbolt.Tx) error {
+		return s.recoverCorruption(nil, func() error {
+			return tx.ForEach(func(name []byte, b *bbolt.Bucket) error {
+				return s.walkBucket(b, nil, name, walkFn)
+			})
+		})
+	})
+}
+
+// walkBucket recursively walks through a bucket, skipping the parts of it
+// that can't be read.
+func (s *salvager) walkBucket(b *bbolt.Bucket, keyPath [][]byte, name []byte,
+	fn walkFunc) error {
+
+	bucketPath := make([][]byte, len(keyPath), len(keyPath)+1)
+	copy(bucketPath, keyPath)
+	bucketPath = append(bucketPath, name)
+
+	return s.recoverCorruption(bucketPath, func() error {
+		if err := fn(keyPath, name, nil, b.Sequence()); err != nil {
+			return err
+		}
+		s.buckets++
+
+		return b.ForEach(func(k, v []byte) error {
+			if v == nil {
+				return s.walkBucket(b.Bucket(k), bucketPath, k, fn)
+			}
+
+			if err := fn(bucketPath, k, v, 0); err != nil {
+				return err
+			}
+			s.keys++
+
+			return nil
+		})
+	})
+}
+
+// recoverCorruption runs f and records the bucket at the given path as lost
+// if bbolt panics because of a corrupt page. Everything that was read before
+// the panic is kept.
+func (s *salvager) recoverCorruption(bucketPath [][]byte,
+	f func() error) (err error) {
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		name := bucketPathName(bucketPath)
+		log.Warnf("Unable to read bucket %v completely: %v", name, r)
+
+		s.lostBuckets = append(
+			s.lostBuckets, fmt.Sprintf("%v: %v", name, r),
+		)
+	}()
+
+	return f()
+}
+
+// bucketPathName returns a printable name of the bucket at the given path.
+func bucketPathName(bucketPath [][]byte) string {
+	names := make([]string, len(bucketPath))
+	for i, key := range bucketPath {
+		names[i] = LoggableKeyName(key)
+	}
+
+	return "/" + strings.Join(names, "/")
+}
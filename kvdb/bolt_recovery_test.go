@@ -0,0 +1,123 @@
+//go:build !js
+// +build !js
+
+package kvdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+)
+
+// TestBoltIntegrityCheckAndSalvage tests that a corrupted bolt database file
+// is detected on startup, and that everything that can still be read is
+// salvaged into a new file.
+func TestBoltIntegrityCheckAndSalvage(t *testing.T) {
+	t.Parallel()
+
+	const dbName = "test.db"
+	dir := t.TempDir()
+	dbFile := filepath.Join(dir, dbName)
+
+	// Create a database with a bucket that is large enough to span
+	// multiple pages, and a small one.
+	db, err := bbolt.Open(dbFile, 0600, nil)
+	require.NoError(t, err)
+
+	var largeRoot uint64
+	err = db.Update(func(tx *bbolt.Tx) error {
+		large, err := tx.CreateBucket([]byte("large"))
+		if err != nil {
+			return err
+		}
+
+		value := bytes.Repeat([]byte{1}, 100)
+		for i := 0; i < 1000; i++ {
+			var key [8]byte
+			byteOrder.PutUint64(key[:], uint64(i))
+			if err := large.Put(key[:], value); err != nil {
+				return err
+			}
+		}
+
+		small, err := tx.CreateBucket([]byte("small"))
+		if err != nil {
+			return err
+		}
+
+		return small.Put([]byte("key"), []byte("value"))
+	})
+	require.NoError(t, err)
+
+	err = db.View(func(tx *bbolt.Tx) error {
+		largeRoot = uint64(tx.Bucket([]byte("large")).Root())
+		return nil
+	})
+	require.NoError(t, err)
+
+	pageSize := db.Info().PageSize
+	require.NoError(t, db.Close())
+
+	// Corrupt the root page of the large bucket by pointing its first
+	// child to a page far beyond the end of the file. The child reference
+	// follows the 16 byte page header and the key position and size of
+	// the first branch element.
+	f, err := os.OpenFile(dbFile, os.O_RDWR, 0600)
+	require.NoError(t, err)
+
+	var pgid [8]byte
+	binary.LittleEndian.PutUint64(pgid[:], 1<<30)
+	_, err = f.WriteAt(pgid[:], int64(largeRoot)*int64(pageSize)+24)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// With only the integrity check enabled, the corruption is reported
+	// instead of opening the database.
+	cfg := &BoltBackendConfig{
+		DBPath:         dir,
+		DBFileName:     dbName,
+		DBTimeout:      DefaultDBTimeout,
+		CheckIntegrity: true,
+	}
+	_, err = GetBoltBackend(cfg)
+
+	var corruptionErr *CorruptionError
+	require.ErrorAs(t, err, &corruptionErr)
+	require.Equal(t, dbFile, corruptionErr.DBFile)
+	require.NotEmpty(t, corruptionErr.IntegrityErrors)
+
+	// With salvaging enabled, the small bucket is recovered and the large
+	// one is reported as lost.
+	cfg.SalvageCorrupt = true
+	backend, err := GetBoltBackend(cfg)
+	require.NoError(t, err)
+
+	err = View(backend, func(tx RTx) error {
+		small := tx.ReadBucket([]byte("small"))
+		require.NotNil(t, small)
+		require.Equal(t, []byte("value"), small.Get([]byte("key")))
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+	require.NoError(t, backend.Close())
+
+	report, err := ioutil.ReadFile(dbFile + SalvageReportFileNameSuffix)
+	require.NoError(t, err)
+	require.Contains(t, string(report), "/large")
+
+	corruptFiles, err := filepath.Glob(dbFile + CorruptDBFileNameSuffix + "*")
+	require.NoError(t, err)
+	require.Len(t, corruptFiles, 1)
+
+	// The salvaged file passes the integrity check.
+	backend, err = GetBoltBackend(cfg)
+	require.NoError(t, err)
+	require.NoError(t, backend.Close())
+}
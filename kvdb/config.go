@@ -36,5 +36,9 @@ type BoltConfig struct {
 
 	AutoCompactMinAge time.Duration `long:"auto-compact-min-age" description:"How long ago the last compaction of a database file must be for it to be considered for auto compaction again. Can be set to 0 to compact on every startup."`
 
+	CheckIntegrity bool `long:"check-integrity" description:"Whether the integrity of the database files used within lnd should be checked on every startup. If a database file is corrupted, lnd refuses to start and logs the problems found, instead of crashing on the first access to the corrupted data. The check reads the whole database file, which slows down the startup."`
+
+	SalvageCorrupt bool `long:"salvage-corrupt" description:"Whether a database file that fails the integrity check should be salvaged into a new file instead. Everything that can still be read is copied, the corrupted file is kept next to the new one and a report of the lost buckets is written to a file with the suffix .salvage-report. Implies check-integrity."`
+
 	DBTimeout time.Duration `long:"dbtimeout" description:"Specify the timeout value used when opening the database."`
 }
@@ -0,0 +1,67 @@
+package lncfg
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	// DefaultBackupSyncRetryInterval is the default interval at which a
+	// failed synchronization of the channel backup to the companion
+	// device is retried.
+	DefaultBackupSyncRetryInterval = time.Minute
+
+	// DefaultBackupSyncTimeout is the default timeout of a request to the
+	// companion device.
+	DefaultBackupSyncTimeout = 30 * time.Second
+)
+
+// BackupSync holds the configuration for synchronizing the static channel
+// backup to a companion device.
+type BackupSync struct {
+	Enable        bool          `long:"enable" description:"Synchronize the static channel backup to a companion device, for example a phone, every time it changes."`
+	URL           string        `long:"url" description:"The HTTP(S) endpoint of the companion device. The backup is fetched with GET and pushed with PUT requests that carry its version, a conflicting version is rejected by the device with 409 Conflict."`
+	AuthToken     string        `long:"authtoken" description:"An optional bearer token that is sent in the Authorization header of every request to the companion device. Requires an https URL."`
+	Timeout       time.Duration `long:"timeout" description:"The timeout of a request to the companion device."`
+	RetryInterval time.Duration `long:"retryinterval" description:"The interval at which a failed synchronization is retried."`
+}
+
+// Validate checks the values configured for the channel backup
+// synchronization.
+func (b *BackupSync) Validate() error {
+	if !b.Enable {
+		return nil
+	}
+
+	if b.URL == "" {
+		return fmt.Errorf("backupsync.url must be set")
+	}
+
+	u, err := url.Parse(b.URL)
+	if err != nil {
+		return fmt.Errorf("invalid backupsync.url: %v", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("backupsync.url must be an http or https " +
+			"URL")
+	}
+
+	// The bearer token would be readable by anyone on the path to the
+	// companion device over plain http.
+	if b.AuthToken != "" && u.Scheme != "https" {
+		return fmt.Errorf("backupsync.authtoken requires an https " +
+			"backupsync.url")
+	}
+
+	if b.Timeout <= 0 {
+		return fmt.Errorf("backupsync.timeout must be positive")
+	}
+
+	if b.RetryInterval <= 0 {
+		return fmt.Errorf("backupsync.retryinterval must be positive")
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package lncfg
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// BreachNotify holds the configuration for notifying an external endpoint of
+// detected channel breaches.
+type BreachNotify struct {
+	URL      string        `long:"url" description:"The HTTP(S) endpoint a JSON notification is posted to as soon as a breach of one of our channels is detected, before the justice transaction confirms. Breach notifications are disabled if no URL is set."`
+	Timeout  time.Duration `long:"timeout" description:"The timeout of a single request to the breach notification endpoint."`
+	Attempts int           `long:"attempts" description:"The number of times the delivery of a breach notification is attempted, with an exponential backoff between attempts."`
+}
+
+// Validate checks the values configured for the breach notifications.
+func (b *BreachNotify) Validate() error {
+	if b.URL == "" {
+		return nil
+	}
+
+	u, err := url.Parse(b.URL)
+	if err != nil {
+		return fmt.Errorf("invalid breachnotify.url: %v", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("breachnotify.url must be an http or https " +
+			"URL")
+	}
+
+	if b.Timeout <= 0 {
+		return fmt.Errorf("breachnotify.timeout must be positive")
+	}
+
+	if b.Attempts <= 0 {
+		return fmt.Errorf("breachnotify.attempts must be positive")
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+)
+
+// Brontide holds the timeouts, key rotation policy and socket options that are
+// enforced on the encrypted connections to our peers.
+type Brontide struct {
+	HandshakeTimeout time.Duration `long:"handshaketimeout" description:"The time a peer has to deliver each act of the encrypted handshake before the connection is failed. Nodes connecting over high-latency Tor circuits may need a longer handshake timeout."`
+	ReadTimeout      time.Duration `long:"readtimeout" description:"The time a peer has to deliver the body of a message once its header has been received before the connection is failed."`
+	WriteTimeout     time.Duration `long:"writetimeout" description:"The time a peer has to accept a message we write to it before the connection is failed."`
+	RotateMessages   uint64        `long:"rotatemessages" description:"Rotate the key we encrypt messages with after this many messages, in addition to the rotation every 1000 encryptions. Only applied to peers that signal support for early key rotations. 0 disables message based rotations."`
+	RotateInterval   time.Duration `long:"rotateinterval" description:"Rotate the key we encrypt messages with once this much time has passed since the last rotation. Only applied to peers that signal support for early key rotations. 0 disables time based rotations."`
+	Nagle            bool          `long:"nagle" description:"Enable Nagle's algorithm on peer connections by not setting TCP_NODELAY. This reduces the number of small packets at the cost of latency."`
+	KeepAlive        time.Duration `long:"keepalive" description:"The period between TCP keep-alive probes on peer connections. 0 uses the default of 15s, a negative value disables keep-alive probes."`
+	ReusePort        bool          `long:"reuseport" description:"Set SO_REUSEPORT on the peer listeners, which allows multiple processes to listen on the same address with the kernel balancing connections between them. Only supported on Linux and BSD-like systems."`
+}
+
+// Validate checks the values configured for the brontide connections.
+func (b *Brontide) Validate() error {
+	if b.HandshakeTimeout <= 0 {
+		return fmt.Errorf("brontide.handshaketimeout must be positive")
+	}
+
+	if b.ReadTimeout <= 0 {
+		return fmt.Errorf("brontide.readtimeout must be positive")
+	}
+
+	if b.WriteTimeout <= 0 {
+		return fmt.Errorf("brontide.writetimeout must be positive")
+	}
+
+	if b.RotateInterval < 0 {
+		return fmt.Errorf("brontide.rotateinterval must not be " +
+			"negative")
+	}
+
+	return nil
+}
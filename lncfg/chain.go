@@ -20,6 +20,7 @@ type Chain struct {
 	SigNet          bool     `long:"signet" description:"Use the signet test network"`
 	SigNetChallenge string   `long:"signetchallenge" description:"Connect to a custom signet network defined by this challenge instead of using the global default signet test network -- Can be specified multiple times"`
 	SigNetSeedNode  []string `long:"signetseednode" description:"Specify a seed node for the signet network instead of using the global default signet network seed nodes"`
+	Network         string   `long:"network" description:"The name of the network to use. This is an alternative to the flags of the individual networks, and also allows selecting networks that don't have a flag of their own"`
 
 	DefaultNumChanConfs int                 `long:"defaultchanconfs" description:"The default number of confirmations a channel must have before it's considered open. If this is not set, we will scale the value according to the channel size."`
 	DefaultRemoteDelay  int                 `long:"defaultremotedelay" description:"The default number of blocks we will require our channel counterparty to wait before accessing its funds in case of unilateral close. If this is not set, we will scale the value according to the channel size."`
@@ -32,6 +33,41 @@ type Chain struct {
 	DNSSeeds            []string            `long:"dnsseed" description:"The seed DNS server(s) to use for initial peer discovery. Must be specified as a '<primary_dns>[,<soa_primary_dns>]' tuple where the SOA address is needed for DNS resolution through Tor but is optional for clearnet users. Multiple tuples can be specified, will overwrite the default seed servers."`
 }
 
+// Networks returns the names of the networks that were selected, either with
+// the flag of an individual network or by name.
+func (c *Chain) Networks() []string {
+	var names []string
+	addName := func(name string) {
+		for _, n := range names {
+			if n == name {
+				return
+			}
+		}
+		names = append(names, name)
+	}
+
+	if c.MainNet {
+		addName("mainnet")
+	}
+	if c.TestNet3 {
+		addName("testnet")
+	}
+	if c.RegTest {
+		addName("regtest")
+	}
+	if c.SimNet {
+		addName("simnet")
+	}
+	if c.SigNet {
+		addName("signet")
+	}
+	if c.Network != "" {
+		addName(c.Network)
+	}
+
+	return names
+}
+
 // Validate performs validation on our chain config.
 func (c *Chain) Validate(minTimeLockDelta uint32, minDelay uint16) error {
 	if c.TimeLockDelta < minTimeLockDelta {
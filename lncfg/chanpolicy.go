@@ -0,0 +1,141 @@
+package lncfg
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// ChanPolicy holds the limits of the channels we open with, or accept from,
+// our peers, in addition to maxchansize.
+type ChanPolicy struct {
+	MaxPeerExposure int64    `long:"maxpeerexposure" description:"The largest total amount (in satoshis) we may have in open and pending channels with a single peer. The reserve the peer has to keep in a channel doesn't count towards it. 0 disables the limit."`
+	WumboConfs      uint16   `long:"wumboconfs" description:"The minimum number of confirmations before a wumbo channel is considered open, regardless of the depth the remote party asks for. 0 uses the depth negotiated with the peer."`
+	Peers           []string `long:"peer" description:"Overrides the limits for a single peer, in the format <pubkey>[,maxchansize=<sat>][,maxpeerexposure=<sat>][,wumboconfs=<n>]. maxchansize overrides the global maxchansize option for the peer. Limits that aren't set default to the global ones -- Can be specified multiple times"`
+}
+
+// PeerChanPolicy is the policy of the channels with a single peer.
+type PeerChanPolicy struct {
+	// PubKey is the public key of the peer.
+	PubKey *btcec.PublicKey
+
+	// MaxChanSize is the largest channel with the peer in satoshis. It
+	// overrides the global maxchansize option if non-zero.
+	MaxChanSize int64
+
+	// MaxPeerExposure is the largest total amount in channels with the
+	// peer in satoshis.
+	MaxPeerExposure int64
+
+	// WumboConfs is the minimum number of confirmations of wumbo channels
+	// with the peer.
+	WumboConfs uint16
+}
+
+// Validate checks the values configured for the channel policy.
+func (c *ChanPolicy) Validate() error {
+	if c.MaxPeerExposure < 0 {
+		return fmt.Errorf("chanpolicy.maxpeerexposure must not be " +
+			"negative")
+	}
+
+	_, err := c.PeerPolicies()
+
+	return err
+}
+
+// PeerPolicies parses the policies configured for single peers. The limits
+// that aren't set for a peer are taken from the global policy, except for the
+// maximum channel size, which is left at zero to use the global maxchansize
+// option.
+func (c *ChanPolicy) PeerPolicies() ([]*PeerChanPolicy, error) {
+	policies := make([]*PeerChanPolicy, 0, len(c.Peers))
+	seen := make(map[string]struct{}, len(c.Peers))
+	for _, peer := range c.Peers {
+		policy, err := c.parsePeerPolicy(peer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chanpolicy.peer %v: %v",
+				peer, err)
+		}
+
+		key := string(policy.PubKey.SerializeCompressed())
+		if _, ok := seen[key]; ok {
+			return nil, fmt.Errorf("duplicate chanpolicy.peer %v",
+				peer)
+		}
+		seen[key] = struct{}{}
+
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+// parsePeerPolicy parses the policy of a single peer.
+func (c *ChanPolicy) parsePeerPolicy(peer string) (*PeerChanPolicy, error) {
+	fields := strings.Split(peer, ",")
+
+	keyBytes, err := hex.DecodeString(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := btcec.ParsePubKey(keyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &PeerChanPolicy{
+		PubKey:          pubKey,
+		MaxPeerExposure: c.MaxPeerExposure,
+		WumboConfs:      c.WumboConfs,
+	}
+
+	for _, field := range fields[1:] {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("expected key=value, got %v",
+				field)
+		}
+
+		key, value := strings.TrimSpace(parts[0]), parts[1]
+		switch key {
+		case "maxchansize":
+			policy.MaxChanSize, err = parseAmount(value)
+			if err == nil && policy.MaxChanSize == 0 {
+				err = fmt.Errorf("must be positive")
+			}
+
+		case "maxpeerexposure":
+			policy.MaxPeerExposure, err = parseAmount(value)
+
+		case "wumboconfs":
+			var confs uint64
+			confs, err = strconv.ParseUint(value, 10, 16)
+			policy.WumboConfs = uint16(confs)
+
+		default:
+			return nil, fmt.Errorf("unknown limit %v", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v: %v", key, err)
+		}
+	}
+
+	return policy, nil
+}
+
+// parseAmount parses a non-negative amount of satoshis.
+func parseAmount(value string) (int64, error) {
+	amt, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if amt < 0 {
+		return 0, fmt.Errorf("must not be negative")
+	}
+
+	return amt, nil
+}
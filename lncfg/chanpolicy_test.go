@@ -0,0 +1,85 @@
+package lncfg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testPeerKey = "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f28" +
+	"15b16f81798"
+
+// TestChanPolicyPeerPolicies tests that the policies of single peers are
+// parsed, and that the limits they don't set default to the global ones.
+// The maximum channel size isn't part of the policy, so it defaults to zero.
+func TestChanPolicyPeerPolicies(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		peer       string
+		maxChan    int64
+		maxExp     int64
+		wumboConfs uint16
+		err        bool
+	}{{
+		name:       "global limits",
+		peer:       testPeerKey,
+		maxExp:     5000000,
+		wumboConfs: 6,
+	}, {
+		name: "peer limits",
+		peer: testPeerKey + ",maxchansize=200000," +
+			"maxpeerexposure=400000,wumboconfs=12",
+		maxChan:    200000,
+		maxExp:     400000,
+		wumboConfs: 12,
+	}, {
+		name: "unknown limit",
+		peer: testPeerKey + ",maxhtlcs=10",
+		err:  true,
+	}, {
+		name: "negative amount",
+		peer: testPeerKey + ",maxchansize=-1",
+		err:  true,
+	}, {
+		name: "zero channel size",
+		peer: testPeerKey + ",maxchansize=0",
+		err:  true,
+	}, {
+		name: "invalid key",
+		peer: "02abcd,maxchansize=1",
+		err:  true,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &ChanPolicy{
+				MaxPeerExposure: 5000000,
+				WumboConfs:      6,
+				Peers:           []string{tc.peer},
+			}
+
+			policies, err := cfg.PeerPolicies()
+			if tc.err {
+				require.Error(t, err)
+				require.Error(t, cfg.Validate())
+				return
+			}
+
+			require.NoError(t, err)
+			require.NoError(t, cfg.Validate())
+			require.Len(t, policies, 1)
+			require.Equal(t, tc.maxChan, policies[0].MaxChanSize)
+			require.Equal(t, tc.maxExp, policies[0].MaxPeerExposure)
+			require.Equal(t, tc.wumboConfs, policies[0].WumboConfs)
+		})
+	}
+
+	// The same peer can't be configured twice.
+	cfg := &ChanPolicy{Peers: []string{testPeerKey, testPeerKey}}
+	require.Error(t, cfg.Validate())
+}
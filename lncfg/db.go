@@ -15,8 +15,8 @@ const (
 	channelDBName     = "channel.db"
 	macaroonDBName    = "macaroons.db"
 	decayedLogDbName  = "sphinxreplay.db"
-	towerClientDBName = "wtclient.db"
-	towerServerDBName = "watchtower.db"
+	TowerClientDBName = "wtclient.db"
+	TowerServerDBName = "watchtower.db"
 
 	BoltBackend                = "bolt"
 	EtcdBackend                = "etcd"
@@ -54,15 +54,23 @@ type DB struct {
 
 	BatchCommitInterval time.Duration `long:"batch-commit-interval" description:"The maximum duration the channel graph batch schedulers will wait before attempting to commit a batch of pending updates. This can be tradeoff database contenion for commit latency."`
 
+	GroupCommitInterval time.Duration `long:"group-commit-interval" description:"If set, non-critical updates such as gossip from remote peers and the forwarding log are batched and committed to disk at most once per interval, while channel state updates remain synchronous. This improves throughput on slow disks. A value of 0 disables group commit."`
+
 	Etcd *etcd.Config `group:"etcd" namespace:"etcd" description:"Etcd settings."`
 
 	Bolt *kvdb.BoltConfig `group:"bolt" namespace:"bolt" description:"Bolt settings."`
 
 	Postgres *postgres.Config `group:"postgres" namespace:"postgres" description:"Postgres settings."`
 
+	TowerBackend string `long:"tower-backend" description:"The database backend of the watchtower client and server databases. Defaults to the selected backend. Setting it to postgres while the backend is bolt keeps only the watchtower databases in postgres, so large towers don't run into the size limits of bolt. Existing bolt watchtower databases are migrated to postgres on startup and renamed with a .migrated suffix."`
+
 	NoGraphCache bool `long:"no-graph-cache" description:"Don't use the in-memory graph cache for path finding. Much slower but uses less RAM. Can only be used with a bolt database backend."`
 
+	GraphCacheMaxSize uint64 `long:"graph-cache-max-size" description:"The maximum size in bytes of the in-memory graph cache. Once the cache grows beyond this size, the least recently used nodes are evicted and read from the database when needed again. Allows devices with little memory to use the graph cache. A value of 0 means the cache is unbounded."`
+
 	PruneRevocation bool `long:"prune-revocation" description:"Run the optional migration that prunes the revocation logs to save disk space."`
+
+	CleanupDuplicatePayments bool `long:"cleanup-duplicate-payments" description:"On startup, delete the failed and incomplete legacy duplicate payments and dangling payment index entries left behind by old versions of lnd to save disk space. Succeeded duplicate payments are kept. Combine with --dry-run-migration to only report what would be deleted."`
 }
 
 // DefaultDB creates and returns a new default DB config.
@@ -104,6 +112,28 @@ func (db *DB) Validate() error {
 			"'%v'", BoltBackend, EtcdBackend)
 	}
 
+	if db.GroupCommitInterval < 0 {
+		return fmt.Errorf("group-commit-interval must not be negative")
+	}
+
+	switch db.TowerBackend {
+	case "", db.Backend:
+	case PostgresBackend:
+		if db.Backend != BoltBackend {
+			return fmt.Errorf("tower-backend can only be set to "+
+				"'%v' if the backend is '%v'", PostgresBackend,
+				BoltBackend)
+		}
+
+		if db.Postgres.Dsn == "" {
+			return fmt.Errorf("postgres dsn must be set")
+		}
+
+	default:
+		return fmt.Errorf("unknown tower-backend, must be either "+
+			"'%v' or the selected backend", PostgresBackend)
+	}
+
 	// The path finding uses a manual read transaction that's open for a
 	// potentially long time. That works fine with the locking model of
 	// bbolt but can lead to locks or rolled back transactions with etcd or
@@ -117,6 +147,11 @@ func (db *DB) Validate() error {
 			"backend '%v'", db.Backend)
 	}
 
+	if db.NoGraphCache && db.GraphCacheMaxSize != 0 {
+		return fmt.Errorf("cannot use graph-cache-max-size with " +
+			"no-graph-cache")
+	}
+
 	return nil
 }
 
@@ -138,7 +173,9 @@ func (db *DB) Init(ctx context.Context, dbPath string) error {
 		// the embedded instance.
 		db.Etcd = cfg
 
-	case db.Backend == PostgresBackend:
+	case db.Backend == PostgresBackend,
+		db.TowerBackend == PostgresBackend:
+
 		postgres.Init(db.Postgres.MaxConnections)
 	}
 
@@ -398,6 +435,8 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 		NoFreelistSync:    db.Bolt.NoFreelistSync,
 		AutoCompact:       db.Bolt.AutoCompact,
 		AutoCompactMinAge: db.Bolt.AutoCompactMinAge,
+		CheckIntegrity:    db.Bolt.CheckIntegrity,
+		SalvageCorrupt:    db.Bolt.SalvageCorrupt,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error opening bolt DB: %v", err)
@@ -411,6 +450,8 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 		NoFreelistSync:    db.Bolt.NoFreelistSync,
 		AutoCompact:       db.Bolt.AutoCompact,
 		AutoCompactMinAge: db.Bolt.AutoCompactMinAge,
+		CheckIntegrity:    db.Bolt.CheckIntegrity,
+		SalvageCorrupt:    db.Bolt.SalvageCorrupt,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error opening macaroon DB: %v", err)
@@ -424,6 +465,8 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 		NoFreelistSync:    db.Bolt.NoFreelistSync,
 		AutoCompact:       db.Bolt.AutoCompact,
 		AutoCompactMinAge: db.Bolt.AutoCompactMinAge,
+		CheckIntegrity:    db.Bolt.CheckIntegrity,
+		SalvageCorrupt:    db.Bolt.SalvageCorrupt,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error opening decayed log DB: %v", err)
@@ -433,15 +476,29 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 	// The tower client is optional and might not be enabled by the user. We
 	// handle it being nil properly in the main server.
 	var towerClientBackend kvdb.Backend
-	if towerClientEnabled {
+	switch {
+	case towerClientEnabled && db.TowerBackend == PostgresBackend:
+		towerClientBackend, err = kvdb.Open(
+			kvdb.PostgresBackendName, ctx, db.Postgres,
+			NSTowerClientDB,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error opening postgres tower "+
+				"client DB: %v", err)
+		}
+		closeFuncs[NSTowerClientDB] = towerClientBackend.Close
+
+	case towerClientEnabled:
 		towerClientBackend, err = kvdb.GetBoltBackend(
 			&kvdb.BoltBackendConfig{
 				DBPath:            chanDBPath,
-				DBFileName:        towerClientDBName,
+				DBFileName:        TowerClientDBName,
 				DBTimeout:         db.Bolt.DBTimeout,
 				NoFreelistSync:    db.Bolt.NoFreelistSync,
 				AutoCompact:       db.Bolt.AutoCompact,
 				AutoCompactMinAge: db.Bolt.AutoCompactMinAge,
+				CheckIntegrity:    db.Bolt.CheckIntegrity,
+				SalvageCorrupt:    db.Bolt.SalvageCorrupt,
 			},
 		)
 		if err != nil {
@@ -454,15 +511,29 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 	// The tower server is optional and might not be enabled by the user. We
 	// handle it being nil properly in the main server.
 	var towerServerBackend kvdb.Backend
-	if towerServerEnabled {
+	switch {
+	case towerServerEnabled && db.TowerBackend == PostgresBackend:
+		towerServerBackend, err = kvdb.Open(
+			kvdb.PostgresBackendName, ctx, db.Postgres,
+			NSTowerServerDB,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error opening postgres tower "+
+				"server DB: %v", err)
+		}
+		closeFuncs[NSTowerServerDB] = towerServerBackend.Close
+
+	case towerServerEnabled:
 		towerServerBackend, err = kvdb.GetBoltBackend(
 			&kvdb.BoltBackendConfig{
 				DBPath:            towerServerDBPath,
-				DBFileName:        towerServerDBName,
+				DBFileName:        TowerServerDBName,
 				DBTimeout:         db.Bolt.DBTimeout,
 				NoFreelistSync:    db.Bolt.NoFreelistSync,
 				AutoCompact:       db.Bolt.AutoCompact,
 				AutoCompactMinAge: db.Bolt.AutoCompactMinAge,
+				CheckIntegrity:    db.Bolt.CheckIntegrity,
+				SalvageCorrupt:    db.Bolt.SalvageCorrupt,
 			},
 		)
 		if err != nil {
@@ -22,3 +22,74 @@ func TestDBDefaultConfig(t *testing.T) {
 	require.False(t, defaultConfig.Bolt.AutoCompact)
 	require.True(t, defaultConfig.Bolt.NoFreelistSync)
 }
+
+// TestDBValidateTowerBackend tests that the tower backend can only be moved to
+// postgres if the rest of the databases are stored in bolt.
+func TestDBValidateTowerBackend(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		backend      string
+		towerBackend string
+		dsn          string
+		expErr       bool
+	}{
+		{
+			name:    "default tower backend",
+			backend: lncfg.BoltBackend,
+		},
+		{
+			name:         "same tower backend",
+			backend:      lncfg.PostgresBackend,
+			towerBackend: lncfg.PostgresBackend,
+			dsn:          "postgres://localhost",
+		},
+		{
+			name:         "postgres tower backend with bolt",
+			backend:      lncfg.BoltBackend,
+			towerBackend: lncfg.PostgresBackend,
+			dsn:          "postgres://localhost",
+		},
+		{
+			name:         "postgres tower backend without dsn",
+			backend:      lncfg.BoltBackend,
+			towerBackend: lncfg.PostgresBackend,
+			expErr:       true,
+		},
+		{
+			name:         "postgres tower backend with etcd",
+			backend:      lncfg.EtcdBackend,
+			towerBackend: lncfg.PostgresBackend,
+			dsn:          "postgres://localhost",
+			expErr:       true,
+		},
+		{
+			name:         "bolt tower backend with postgres",
+			backend:      lncfg.PostgresBackend,
+			towerBackend: lncfg.BoltBackend,
+			dsn:          "postgres://localhost",
+			expErr:       true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := lncfg.DefaultDB()
+			cfg.Backend = test.backend
+			cfg.TowerBackend = test.towerBackend
+			cfg.Postgres.Dsn = test.dsn
+			cfg.Etcd.Host = "localhost:2379"
+
+			err := cfg.Validate()
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package lncfg
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/lightningnetwork/lnd/discovery"
@@ -15,6 +16,10 @@ type Gossip struct {
 	MaxChannelUpdateBurst int `long:"max-channel-update-burst" description:"The maximum number of updates for a specific channel and direction that lnd will accept over the channel update interval."`
 
 	ChannelUpdateInterval time.Duration `long:"channel-update-interval" description:"The interval used to determine how often lnd should allow a burst of new updates for a specific channel and direction."`
+
+	RebroadcastInterval time.Duration `long:"rebroadcast-interval" description:"The interval after which lnd rebroadcasts the channel updates of its public channels and its node announcement, even if nothing changed. This keeps the channels from being pruned by the network, which commonly happens after two weeks without an update."`
+
+	RebroadcastJitter time.Duration `long:"rebroadcast-jitter" description:"The maximum duration by which the rebroadcast of each channel update is randomly brought forward, to avoid rebroadcasting the updates of all channels at once. Must be smaller than the rebroadcast interval."`
 }
 
 // Parse the pubkeys for the pinned syncers.
@@ -32,3 +37,26 @@ func (g *Gossip) Parse() error {
 
 	return nil
 }
+
+// Validate checks the values configured for the gossiper.
+func (g *Gossip) Validate() error {
+	// The rebroadcast interval must be short enough for our channel
+	// updates to be refreshed before the network considers them stale.
+	if g.RebroadcastInterval <= 0 ||
+		g.RebroadcastInterval >= discovery.ChannelUpdateStaleness {
+
+		return fmt.Errorf("gossip.rebroadcast-interval must be "+
+			"positive and below %v",
+			discovery.ChannelUpdateStaleness)
+	}
+
+	if g.RebroadcastJitter < 0 ||
+		g.RebroadcastJitter >= g.RebroadcastInterval {
+
+		return fmt.Errorf("gossip.rebroadcast-jitter must not be "+
+			"negative and below gossip.rebroadcast-interval (%v)",
+			g.RebroadcastInterval)
+	}
+
+	return nil
+}
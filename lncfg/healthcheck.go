@@ -32,6 +32,8 @@ type HealthCheckConfig struct {
 	TorConnection *CheckConfig `group:"torconnection" namespace:"torconnection"`
 
 	RemoteSigner *CheckConfig `group:"remotesigner" namespace:"remotesigner"`
+
+	HSM *CheckConfig `group:"hsm" namespace:"hsm"`
 }
 
 // Validate checks the values configured for our health checks.
@@ -58,6 +60,10 @@ func (h *HealthCheckConfig) Validate() error {
 		return err
 	}
 
+	if err := h.HSM.validate("hsm"); err != nil {
+		return err
+	}
+
 	return nil
 }
 
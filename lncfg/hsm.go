@@ -0,0 +1,44 @@
+package lncfg
+
+import (
+	"errors"
+)
+
+const (
+	// DefaultHSMKeyLabelPrefix is the default prefix of the labels of the
+	// keys lnd keeps in the hardware security module.
+	DefaultHSMKeyLabelPrefix = "lnd"
+)
+
+// HSM holds the configuration options for the hardware security module that
+// keeps the node identity key and the channel funding keys.
+type HSM struct {
+	Active         bool   `long:"active" description:"Keep the node identity key and the channel funding keys in a hardware security module that is accessed through PKCS#11. All other keys, like the ones the per-commitment secrets are derived from, are still derived from the wallet seed. Must be enabled when the node is created, as enabling it later changes the node's identity and breaks existing channels."`
+	Module         string `long:"module" description:"The path to the PKCS#11 module (shared library) of the HSM"`
+	TokenLabel     string `long:"tokenlabel" description:"The label of the token that holds the keys"`
+	PINFile        string `long:"pinfile" description:"The path to a file that contains the user PIN to log into the token with. The file should only be readable by the user running lnd."`
+	KeyLabelPrefix string `long:"keylabelprefix" description:"The prefix of the labels of the keys lnd keeps in the token. The family and index of a key are appended to the prefix."`
+}
+
+// Validate checks the values configured for our hardware security module.
+func (h *HSM) Validate() error {
+	if !h.Active {
+		return nil
+	}
+
+	switch {
+	case h.Module == "":
+		return errors.New("hsm: module must be set")
+
+	case h.TokenLabel == "":
+		return errors.New("hsm: tokenlabel must be set")
+
+	case h.PINFile == "":
+		return errors.New("hsm: pinfile must be set")
+
+	case h.KeyLabelPrefix == "":
+		return errors.New("hsm: keylabelprefix must be set")
+	}
+
+	return nil
+}
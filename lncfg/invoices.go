@@ -1,12 +1,41 @@
 package lncfg
 
+import (
+	"errors"
+	"time"
+)
+
 // DefaultHoldInvoiceExpiryDelta defines the number of blocks before the expiry
 // height of a hold invoice's htlc that lnd will automatically cancel the
 // invoice to prevent the channel from force closing. This value *must* be
 // greater than DefaultIncomingBroadcastDelta to prevent force closes.
 const DefaultHoldInvoiceExpiryDelta = DefaultIncomingBroadcastDelta + 2
 
+// DefaultInvoiceGcInterval is the default interval at which expired unpaid
+// invoices are garbage collected.
+const DefaultInvoiceGcInterval = time.Hour
+
 // Invoices holds the configuration options for invoices.
 type Invoices struct {
 	HoldExpiryDelta uint32 `long:"holdexpirydelta" description:"The number of blocks before a hold invoice's htlc expires that the invoice should be canceled to prevent a force close. Force closes will not be prevented if this value is not greater than DefaultIncomingBroadcastDelta."`
+
+	GcExpiredAfter time.Duration `long:"gc-expired-after" description:"Delete unpaid invoices once they expired more than this long ago, for example 720h for 30 days. 0 disables the garbage collection of expired invoices, unless it is triggered over RPC."`
+
+	GcInterval time.Duration `long:"gc-interval" description:"The interval at which expired unpaid invoices are garbage collected if gc-expired-after is set."`
+
+	GcArchiveFile string `long:"gc-archive-file" description:"If set, garbage collected invoices are appended to this gzip compressed file as JSON lines before they are deleted."`
+}
+
+// Validate checks the values configured for invoices.
+func (i *Invoices) Validate() error {
+	if i.GcExpiredAfter < 0 {
+		return errors.New("invoices.gc-expired-after must not be " +
+			"negative")
+	}
+
+	if i.GcExpiredAfter > 0 && i.GcInterval <= 0 {
+		return errors.New("invoices.gc-interval must be positive")
+	}
+
+	return nil
 }
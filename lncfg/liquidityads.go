@@ -0,0 +1,55 @@
+package lncfg
+
+import (
+	"fmt"
+)
+
+const (
+	// DefaultLeaseFeeRate is the default proportional lease fee, in basis
+	// points of the leased amount.
+	DefaultLeaseFeeRate = 100
+)
+
+// LiquidityAds holds the configuration for the lease policy under which we
+// answer funding requests of channel initiators (option_will_fund).
+type LiquidityAds struct {
+	Enable            bool   `long:"enable" description:"Answer funding requests of channel initiators with a quote of our lease rates."`
+	MinLeaseAmt       int64  `long:"min-lease-amt" description:"The smallest amount in satoshis we're willing to lease."`
+	MaxLeaseAmt       int64  `long:"max-lease-amt" description:"The largest amount in satoshis we're willing to lease."`
+	LeaseFeeBase      uint32 `long:"lease-fee-base" description:"The fixed fee in satoshis charged for every lease."`
+	LeaseFeeRate      uint16 `long:"lease-fee-rate" description:"The fee charged proportionally to the leased amount, in basis points."`
+	ChannelFeeMaxBase uint32 `long:"channel-fee-max-base" description:"The maximum base routing fee in millisatoshis we commit to charging over leased funds."`
+	ChannelFeeMaxRate uint16 `long:"channel-fee-max-rate" description:"The maximum proportional routing fee we commit to charging over leased funds, in thousandths of a percent."`
+}
+
+// Validate checks the values configured for liquidity ads.
+func (l *LiquidityAds) Validate() error {
+	if !l.Enable {
+		return nil
+	}
+
+	if l.MinLeaseAmt < 0 {
+		return fmt.Errorf("min lease amount must not be negative")
+	}
+	if l.MaxLeaseAmt <= 0 {
+		return fmt.Errorf("max lease amount must be positive")
+	}
+	if l.MinLeaseAmt > l.MaxLeaseAmt {
+		return fmt.Errorf("min lease amount must not exceed max " +
+			"lease amount")
+	}
+	if l.LeaseFeeRate > 10_000 {
+		return fmt.Errorf("lease fee rate must not exceed 10000 " +
+			"basis points")
+	}
+
+	return nil
+}
+
+// DefaultLiquidityAds returns the default values for the liquidity ads
+// configuration.
+func DefaultLiquidityAds() *LiquidityAds {
+	return &LiquidityAds{
+		LeaseFeeRate: DefaultLeaseFeeRate,
+	}
+}
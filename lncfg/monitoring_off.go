@@ -18,3 +18,10 @@ func DefaultPrometheus() Prometheus {
 func (p *Prometheus) Enabled() bool {
 	return false
 }
+
+// HtlcLatencyEnabled returns whether or not the latency of forwarded HTLCs
+// should be exported. Monitoring is currently disabled, so
+// HtlcLatencyEnabled will always return false.
+func (p *Prometheus) HtlcLatencyEnabled() bool {
+	return false
+}
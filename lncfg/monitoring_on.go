@@ -19,6 +19,11 @@ type Prometheus struct {
 	// generates additional data, and consume more memory for the
 	// Prometheus server.
 	PerfHistograms bool `long:"perfhistograms" description:"enable additional histogram to track gRPC call processing performance (latency, etc)"`
+
+	// HtlcLatency indicates if the latency of forwarded HTLCs should be
+	// traced and exported as histograms, with exemplars that identify
+	// individual HTLCs.
+	HtlcLatency bool `long:"htlclatency" description:"enable histograms of the time forwarded HTLCs spend in each stage of the forwarding pipeline, with exemplars of individual HTLCs"`
 }
 
 // DefaultPrometheus is the default configuration for the Prometheus metrics
@@ -35,3 +40,9 @@ func DefaultPrometheus() Prometheus {
 func (p *Prometheus) Enabled() bool {
 	return p.Enable
 }
+
+// HtlcLatencyEnabled returns whether or not the latency of forwarded HTLCs
+// should be exported.
+func (p *Prometheus) HtlcLatencyEnabled() bool {
+	return p.HtlcLatency
+}
@@ -42,6 +42,10 @@ type ProtocolOptions struct {
 	// NoOptionAnySegwit should be set to true if we don't want to use any
 	// Taproot (and beyond) addresses for co-op closing.
 	NoOptionAnySegwit bool `long:"no-any-segwit" description:"disallow using any segiwt witness version as a co-op close address"`
+
+	// NoOptionNoiseIK should be set to true if we don't want to accept or
+	// use the Noise_IK brontide handshake.
+	NoOptionNoiseIK bool `long:"no-noise-ik" description:"disable the Noise_IK handshake: neither signal support for it, nor accept it, nor use it to re-connect to peers"`
 }
 
 // Wumbo returns true if lnd should permit the creation and acceptance of wumbo
@@ -77,3 +81,9 @@ func (l *ProtocolOptions) ZeroConf() bool {
 func (l *ProtocolOptions) NoAnySegwit() bool {
 	return l.NoOptionAnySegwit
 }
+
+// NoNoiseIK returns true if we don't signal support for the Noise_IK brontide
+// handshake, reject it from our peers and don't use it to re-connect to them.
+func (l *ProtocolOptions) NoNoiseIK() bool {
+	return l.NoOptionNoiseIK
+}
@@ -1,8 +1,45 @@
 package lncfg
 
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
 // Routing holds the configuration options for routing.
 type Routing struct {
 	AssumeChannelValid bool `long:"assumechanvalid" description:"Skip checking channel spentness during graph validation. This speedup comes at the risk of using an unvalidated view of the network for routing. (default: false)"`
 
 	StrictZombiePruning bool `long:"strictgraphpruning" description:"If true, then the graph will be pruned more aggressively for zombies. In practice this means that edges with a single stale edge will be considered a zombie."`
+
+	LSPPeers []string `long:"lsppeer" description:"The hex-encoded public key of an LSP whose announced policies for our private channels with it are used for hop hints and self-payments -- Can be specified multiple times"`
+}
+
+// Validate checks the values configured for routing.
+func (r *Routing) Validate() error {
+	_, err := r.LSPPeerKeys()
+
+	return err
+}
+
+// LSPPeerKeys parses the public keys of the configured LSP peers.
+func (r *Routing) LSPPeerKeys() ([]*btcec.PublicKey, error) {
+	keys := make([]*btcec.PublicKey, 0, len(r.LSPPeers))
+	for _, peer := range r.LSPPeers {
+		keyBytes, err := hex.DecodeString(peer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LSP peer %v: %v", peer,
+				err)
+		}
+
+		key, err := btcec.ParsePubKey(keyBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LSP peer %v: %v", peer,
+				err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
 }
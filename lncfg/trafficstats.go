@@ -0,0 +1,31 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+)
+
+// TrafficStats holds the configuration for exporting per-peer traffic
+// statistics to an external collector.
+type TrafficStats struct {
+	Enable    bool          `long:"enable" description:"Export per-connection traffic statistics of our peers (bytes and messages per message type, timing histograms) to an external collector. The content of the messages is never exported."`
+	Collector string        `long:"collector" description:"The address of the collector socket the statistics are written to as JSON objects, one per line, for example unix:///tmp/collector.sock or tcp://127.0.0.1:9000"`
+	Interval  time.Duration `long:"interval" description:"The interval at which the statistics are exported"`
+}
+
+// Validate checks the values configured for the traffic statistics export.
+func (t *TrafficStats) Validate() error {
+	if !t.Enable {
+		return nil
+	}
+
+	if t.Collector == "" {
+		return fmt.Errorf("trafficstats.collector must be set")
+	}
+
+	if t.Interval <= 0 {
+		return fmt.Errorf("trafficstats.interval must be positive")
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package lncfg
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Wire holds the limits of the number of items in the messages we accept from
+// our peers. Messages that exceed them are dropped before they're processed.
+type Wire struct {
+	MaxTLVRecords   uint32   `long:"max-tlv-records" description:"The maximum number of TLV records in the extension of a message received from a peer. 0 disables the limit."`
+	MaxAddresses    uint32   `long:"max-addresses" description:"The maximum number of addresses in a node announcement received from a peer. 0 disables the limit."`
+	MaxShortChanIDs uint32   `long:"max-scids" description:"The maximum number of short channel IDs in a gossip query or reply received from a peer. 0 disables the limit."`
+	Peers           []string `long:"peer" description:"Overrides the limits for a single peer, in the format <pubkey>[,max-tlv-records=<n>][,max-addresses=<n>][,max-scids=<n>]. Limits that aren't set default to the global ones -- Can be specified multiple times"`
+}
+
+// PeerWire holds the limits of the messages we accept from a single peer.
+type PeerWire struct {
+	// PubKey is the public key of the peer.
+	PubKey *btcec.PublicKey
+
+	// Limits are the limits of the messages received from the peer.
+	Limits lnwire.ComplexityLimits
+}
+
+// DefaultWire returns the default limits of the messages we accept from our
+// peers.
+func DefaultWire() *Wire {
+	limits := lnwire.DefaultComplexityLimits()
+
+	return &Wire{
+		MaxTLVRecords:   limits.MaxTLVRecords,
+		MaxAddresses:    limits.MaxAddresses,
+		MaxShortChanIDs: limits.MaxShortChanIDs,
+	}
+}
+
+// Validate checks the limits configured for single peers.
+func (w *Wire) Validate() error {
+	_, err := w.PeerLimits()
+
+	return err
+}
+
+// ComplexityLimits returns the configured limits in the form the message
+// decoder of a peer applies them.
+func (w *Wire) ComplexityLimits() lnwire.ComplexityLimits {
+	return lnwire.ComplexityLimits{
+		MaxTLVRecords:   w.MaxTLVRecords,
+		MaxAddresses:    w.MaxAddresses,
+		MaxShortChanIDs: w.MaxShortChanIDs,
+	}
+}
+
+// PeerLimits parses the limits configured for single peers. The limits that
+// aren't set for a peer are taken from the global ones.
+func (w *Wire) PeerLimits() ([]*PeerWire, error) {
+	peers := make([]*PeerWire, 0, len(w.Peers))
+	seen := make(map[string]struct{}, len(w.Peers))
+	for _, peer := range w.Peers {
+		peerWire, err := w.parsePeerLimits(peer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid wire.peer %v: %v", peer,
+				err)
+		}
+
+		key := string(peerWire.PubKey.SerializeCompressed())
+		if _, ok := seen[key]; ok {
+			return nil, fmt.Errorf("duplicate wire.peer %v", peer)
+		}
+		seen[key] = struct{}{}
+
+		peers = append(peers, peerWire)
+	}
+
+	return peers, nil
+}
+
+// parsePeerLimits parses the limits of a single peer.
+func (w *Wire) parsePeerLimits(peer string) (*PeerWire, error) {
+	fields := strings.Split(peer, ",")
+
+	keyBytes, err := hex.DecodeString(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := btcec.ParsePubKey(keyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	peerWire := &PeerWire{
+		PubKey: pubKey,
+		Limits: w.ComplexityLimits(),
+	}
+
+	for _, field := range fields[1:] {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("expected key=value, got %v",
+				field)
+		}
+
+		key := strings.TrimSpace(parts[0])
+		value, err := strconv.ParseUint(parts[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v: %v", key, err)
+		}
+
+		switch key {
+		case "max-tlv-records":
+			peerWire.Limits.MaxTLVRecords = uint32(value)
+
+		case "max-addresses":
+			peerWire.Limits.MaxAddresses = uint32(value)
+
+		case "max-scids":
+			peerWire.Limits.MaxShortChanIDs = uint32(value)
+
+		default:
+			return nil, fmt.Errorf("unknown limit %v", key)
+		}
+	}
+
+	return peerWire, nil
+}
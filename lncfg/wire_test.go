@@ -0,0 +1,82 @@
+package lncfg
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWirePeerLimits tests that the limits of single peers are parsed, and
+// that the limits they don't set default to the global ones.
+func TestWirePeerLimits(t *testing.T) {
+	t.Parallel()
+
+	global := lnwire.ComplexityLimits{
+		MaxTLVRecords:   100,
+		MaxAddresses:    64,
+		MaxShortChanIDs: 16384,
+	}
+
+	testCases := []struct {
+		name   string
+		peer   string
+		limits lnwire.ComplexityLimits
+		err    bool
+	}{{
+		name:   "global limits",
+		peer:   testPeerKey,
+		limits: global,
+	}, {
+		name: "peer limits",
+		peer: testPeerKey + ",max-tlv-records=10,max-addresses=0," +
+			"max-scids=100000",
+		limits: lnwire.ComplexityLimits{
+			MaxTLVRecords:   10,
+			MaxAddresses:    0,
+			MaxShortChanIDs: 100000,
+		},
+	}, {
+		name: "unknown limit",
+		peer: testPeerKey + ",max-htlcs=10",
+		err:  true,
+	}, {
+		name: "negative limit",
+		peer: testPeerKey + ",max-scids=-1",
+		err:  true,
+	}, {
+		name: "invalid key",
+		peer: "02abcd,max-scids=1",
+		err:  true,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Wire{
+				MaxTLVRecords:   global.MaxTLVRecords,
+				MaxAddresses:    global.MaxAddresses,
+				MaxShortChanIDs: global.MaxShortChanIDs,
+				Peers:           []string{tc.peer},
+			}
+
+			peers, err := cfg.PeerLimits()
+			if tc.err {
+				require.Error(t, err)
+				require.Error(t, cfg.Validate())
+				return
+			}
+
+			require.NoError(t, err)
+			require.NoError(t, cfg.Validate())
+			require.Len(t, peers, 1)
+			require.Equal(t, tc.limits, peers[0].Limits)
+		})
+	}
+
+	// The same peer can't be configured twice.
+	cfg := &Wire{Peers: []string{testPeerKey, testPeerKey}}
+	require.Error(t, cfg.Validate())
+}
@@ -1,6 +1,23 @@
 package lncfg
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
+)
+
+const (
+	// defaultWtClientMinBackoff is the initial backoff the watchtower
+	// client applies if wtclient.min-backoff isn't set. It must match
+	// wtclient.DefaultMinBackoff.
+	defaultWtClientMinBackoff = 10 * time.Second
+
+	// defaultWtClientMaxBackoff is the maximum backoff the watchtower
+	// client applies if wtclient.max-backoff isn't set. It must match
+	// wtclient.DefaultMaxBackoff.
+	defaultWtClientMaxBackoff = 5 * time.Minute
+)
 
 // WtClient holds the configuration options for the daemon's watchtower client.
 type WtClient struct {
@@ -15,6 +32,64 @@ type WtClient struct {
 	// SweepFeeRate specifies the fee rate in sat/byte to be used when
 	// constructing justice transactions sent to the tower.
 	SweepFeeRate uint64 `long:"sweep-fee-rate" description:"Specifies the fee rate in sat/byte to be used when constructing justice transactions sent to the watchtower."`
+
+	// MaxSweepFeeRate specifies the highest fee rate in sat/byte the tower
+	// may choose at breach time for justice transactions. If set, the
+	// sweep fee rate becomes the lowest fee rate the tower may choose.
+	MaxSweepFeeRate uint64 `long:"max-sweep-fee-rate" description:"If set, lets the watchtower choose the fee rate of justice transactions at breach time based on its fee estimator. Specifies the highest fee rate in sat/byte the watchtower may choose, while sweep-fee-rate becomes the lowest."`
+
+	// SweepFeePercentile specifies the percentile of the tower's fee
+	// estimates at breach time that justice transactions target if
+	// max-sweep-fee-rate is set.
+	SweepFeePercentile uint8 `long:"sweep-fee-percentile" description:"The percentile (between 1 and 100) of the watchtower's fee estimates at breach time that justice transactions target if max-sweep-fee-rate is set. Defaults to 50 if not set."`
+
+	// MaxUpdates is the maximum number of updates that each session
+	// negotiated with a tower allows before it is exhausted.
+	MaxUpdates uint16 `long:"max-updates" description:"The maximum number of updates that each session negotiated with a watchtower allows. Once a session is exhausted, a new one is negotiated. Must be between 1 and 16384."`
+
+	// MinBackoff is the initial backoff applied after a failed connection
+	// attempt to a tower.
+	MinBackoff time.Duration `long:"min-backoff" description:"The initial backoff applied after a failed connection attempt to a watchtower. Defaults to 10s if not set."`
+
+	// MaxBackoff is the maximum backoff applied between connection
+	// attempts to a tower.
+	MaxBackoff time.Duration `long:"max-backoff" description:"The maximum backoff applied between connection attempts to a watchtower. Defaults to 5m if not set."`
+
+	// BackoffMultiplier is the factor by which the backoff grows after
+	// each consecutive failed connection attempt.
+	BackoffMultiplier float64 `long:"backoff-multiplier" description:"The factor by which the backoff grows after each consecutive failed connection attempt to a watchtower. Defaults to 2 if not set."`
+
+	// BackoffJitter is the fraction of the backoff by which each delay is
+	// randomly lengthened or shortened.
+	BackoffJitter float64 `long:"backoff-jitter" description:"The fraction (between 0 and 1) of the backoff by which each delay between connection attempts to a watchtower is randomly lengthened or shortened."`
+
+	// CircuitBreakerThreshold is the number of consecutive failed
+	// connection attempts after which a tower is no longer dialed until
+	// the circuit breaker cooldown has passed.
+	CircuitBreakerThreshold uint32 `long:"circuit-breaker-threshold" description:"The number of consecutive failed connection attempts after which a watchtower is no longer dialed until the circuit breaker cooldown has passed. Set to 0 to disable the circuit breaker."`
+
+	// CircuitBreakerCooldown is the duration for which a tower is not
+	// dialed once its circuit breaker has tripped.
+	CircuitBreakerCooldown time.Duration `long:"circuit-breaker-cooldown" description:"The duration for which a watchtower is not dialed once its circuit breaker has tripped. Defaults to 30m if not set."`
+
+	// SweepAddress is an address, typically of a cold wallet, that
+	// justice transactions pay into instead of a fresh address of the
+	// node's wallet.
+	SweepAddress string `long:"sweep-address" description:"An on-chain address, for example of a cold wallet, that justice transactions created by the watchtowers pay into instead of an address of the node's wallet. This protects recovered funds if the node itself is compromised. Only P2WKH, P2WSH and P2TR addresses are supported. Once set, all channels sweep into this address, including ones registered earlier. The address is persisted, it can't be changed afterwards and removing the option doesn't move channels back to the wallet."`
+
+	// MaxSessionsPerTower is the number of active sessions held with a
+	// tower after which new sessions are only negotiated with it if no
+	// other tower is available.
+	MaxSessionsPerTower uint32 `long:"max-sessions-per-tower" description:"The number of active sessions held with a watchtower, for legacy and anchor channels combined, after which new sessions are only negotiated with it if no other watchtower is available. Set to 0 to disable the quota."`
+
+	// DiversifyTowers specifies whether new sessions should prefer towers
+	// on a different network than the tower selected last.
+	DiversifyTowers bool `long:"diversify-towers" description:"Whether new sessions should prefer watchtowers on a different network (IPv4 /16 or IPv6 /32 subnet, or Tor) than the watchtower selected last, to spread backups across networks."`
+
+	// CPFPAnchor specifies whether the justice transactions of anchor
+	// channels should carry an extra output for the tower, which the
+	// tower can spend to bump the fee of the justice transaction.
+	CPFPAnchor bool `long:"cpfp-anchor" description:"Whether justice transactions of anchor channels should carry a small extra output paying the watchtower, which the watchtower can spend to bump the fee of the justice transaction via CPFP. Requires watchtowers that support the cpfp-anchor feature."`
 }
 
 // Validate ensures the user has provided a valid configuration.
@@ -30,6 +105,49 @@ func (c *WtClient) Validate() error {
 			"`lncli wtclient -h` for more information")
 	}
 
+	if c.MaxUpdates == 0 || c.MaxUpdates > wtpolicy.MaxSessionUpdates {
+		return fmt.Errorf("wtclient.max-updates must be between 1 "+
+			"and %d", wtpolicy.MaxSessionUpdates)
+	}
+
+	if c.MinBackoff < 0 || c.MaxBackoff < 0 {
+		return fmt.Errorf("wtclient backoff must not be negative")
+	}
+
+	// Unset backoffs are replaced by their defaults, so the range must
+	// also hold if only one of them is set.
+	minBackoff, maxBackoff := c.MinBackoff, c.MaxBackoff
+	if minBackoff == 0 {
+		minBackoff = defaultWtClientMinBackoff
+	}
+	if maxBackoff == 0 {
+		maxBackoff = defaultWtClientMaxBackoff
+	}
+	if minBackoff > maxBackoff {
+		return fmt.Errorf("wtclient.min-backoff (%v) must not exceed "+
+			"wtclient.max-backoff (%v)", minBackoff, maxBackoff)
+	}
+
+	if c.BackoffMultiplier != 0 && c.BackoffMultiplier < 1 {
+		return fmt.Errorf("wtclient.backoff-multiplier must be at " +
+			"least 1")
+	}
+
+	if c.BackoffJitter < 0 || c.BackoffJitter > 1 {
+		return fmt.Errorf("wtclient.backoff-jitter must be between 0 " +
+			"and 1")
+	}
+
+	if c.SweepFeePercentile != 0 && c.MaxSweepFeeRate == 0 {
+		return fmt.Errorf("wtclient.sweep-fee-percentile requires " +
+			"wtclient.max-sweep-fee-rate to be set")
+	}
+
+	if c.SweepFeePercentile > 100 {
+		return fmt.Errorf("wtclient.sweep-fee-percentile must be " +
+			"between 1 and 100")
+	}
+
 	return nil
 }
 
@@ -0,0 +1,127 @@
+package lncfg_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
+	"github.com/stretchr/testify/require"
+)
+
+// TestValidateWtClientBackoff asserts that the backoff range is validated
+// with the defaults of the unset backoffs applied.
+func TestValidateWtClientBackoff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		minBackoff time.Duration
+		maxBackoff time.Duration
+		valid      bool
+	}{
+		{
+			name:  "defaults",
+			valid: true,
+		},
+		{
+			name:       "explicit range",
+			minBackoff: time.Minute,
+			maxBackoff: time.Hour,
+			valid:      true,
+		},
+		{
+			name:       "min above max",
+			minBackoff: time.Hour,
+			maxBackoff: time.Minute,
+		},
+		{
+			name:       "min above default max",
+			minBackoff: 10 * time.Minute,
+		},
+		{
+			name:       "max below default min",
+			maxBackoff: time.Second,
+		},
+		{
+			name:       "negative backoff",
+			minBackoff: -time.Second,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &lncfg.WtClient{
+				MaxUpdates: wtpolicy.DefaultMaxUpdates,
+				MinBackoff: test.minBackoff,
+				MaxBackoff: test.maxBackoff,
+			}
+
+			err := cfg.Validate()
+			if test.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestValidateWtClientMaxUpdates asserts that the maximum number of updates
+// per session must be positive and within the policy limit.
+func TestValidateWtClientMaxUpdates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		maxUpdates uint16
+		valid      bool
+	}{
+		{
+			name:       "zero",
+			maxUpdates: 0,
+		},
+		{
+			name:       "one",
+			maxUpdates: 1,
+			valid:      true,
+		},
+		{
+			name:       "default",
+			maxUpdates: wtpolicy.DefaultMaxUpdates,
+			valid:      true,
+		},
+		{
+			name:       "policy limit",
+			maxUpdates: wtpolicy.MaxSessionUpdates,
+			valid:      true,
+		},
+		{
+			name:       "above policy limit",
+			maxUpdates: wtpolicy.MaxSessionUpdates + 1,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &lncfg.WtClient{
+				MaxUpdates: test.maxUpdates,
+			}
+
+			err := cfg.Validate()
+			if test.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package lncfg
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// ZeroReserve holds the configuration for negotiating channels without a
+// channel reserve with a set of trusted peers.
+type ZeroReserve struct {
+	Enable bool     `long:"enable" description:"Allow channels without a channel reserve to be negotiated with the peers in zeroreserve.peer. WARNING: A party that doesn't have to keep a reserve can broadcast a revoked commitment without risking any of its funds, so only list peers that are trusted not to cheat."`
+	Peers  []string `long:"peer" description:"The hex-encoded public key of a peer channels without a reserve may be negotiated with -- Can be specified multiple times"`
+}
+
+// Validate checks the values configured for zero reserve channels.
+func (z *ZeroReserve) Validate() error {
+	if !z.Enable {
+		return nil
+	}
+
+	if len(z.Peers) == 0 {
+		return fmt.Errorf("at least one zero reserve peer must be set")
+	}
+
+	_, err := z.PeerKeys()
+
+	return err
+}
+
+// PeerKeys parses the public keys of the configured zero reserve peers.
+func (z *ZeroReserve) PeerKeys() ([]*btcec.PublicKey, error) {
+	keys := make([]*btcec.PublicKey, 0, len(z.Peers))
+	for _, peer := range z.Peers {
+		keyBytes, err := hex.DecodeString(peer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid zero reserve peer %v: "+
+				"%v", peer, err)
+		}
+
+		key, err := btcec.ParsePubKey(keyBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid zero reserve peer %v: "+
+				"%v", peer, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
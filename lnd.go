@@ -5,6 +5,7 @@
 package lnd
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"errors"
@@ -37,6 +38,7 @@ import (
 	"github.com/lightningnetwork/lnd/tor"
 	"github.com/lightningnetwork/lnd/walletunlocker"
 	"github.com/lightningnetwork/lnd/watchtower"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
 	"golang.org/x/crypto/acme/autocert"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -160,27 +162,9 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 		"debuglevel=%s", build.Version(), build.Commit,
 		build.Deployment, build.LoggingType, cfg.DebugLevel)
 
-	var network string
-	switch {
-	case cfg.Bitcoin.TestNet3 || cfg.Litecoin.TestNet3:
-		network = "testnet"
-
-	case cfg.Bitcoin.MainNet || cfg.Litecoin.MainNet:
-		network = "mainnet"
-
-	case cfg.Bitcoin.SimNet || cfg.Litecoin.SimNet:
-		network = "simnet"
-
-	case cfg.Bitcoin.RegTest || cfg.Litecoin.RegTest:
-		network = "regtest"
-
-	case cfg.Bitcoin.SigNet:
-		network = "signet"
-	}
-
 	ltndLog.Infof("Active chain: %v (network=%v)",
 		strings.Title(cfg.registeredChains.PrimaryChain().String()),
-		network,
+		cfg.ActiveNetwork.Name,
 	)
 
 	// Enable http profiling server if requested.
@@ -217,13 +201,19 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 	}
 
 	// Only process macaroons if --no-macaroons isn't set.
-	serverOpts, restDialOpts, restListen, cleanUp, err := getTLSConfig(cfg)
+	serverOpts, restDialOpts, restListen, tlsMgr, cleanUp, err :=
+		getTLSConfig(cfg)
 	if err != nil {
 		return mkErr("unable to load TLS credentials: %v", err)
 	}
 
 	defer cleanUp()
 
+	if err := tlsMgr.Start(); err != nil {
+		return mkErr("unable to start TLS manager: %v", err)
+	}
+	defer tlsMgr.Stop()
+
 	// If we have chosen to start with a dedicated listener for the
 	// rpc server, we set it directly.
 	grpcListeners := append([]*ListenerWithSignal{}, lisCfg.RPCListeners...)
@@ -372,6 +362,20 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 
 	defer cleanUp()
 
+	// If the node identity and funding keys are kept in a hardware
+	// security module, we open a session with it before the wallet is
+	// wired up with the keys.
+	if cfg.HSM.Active {
+		hsm, cleanUp, err := implCfg.BuildHSM(cfg.HSM)
+		if err != nil {
+			return mkErr("unable to open HSM session: %v", err)
+		}
+
+		defer cleanUp()
+
+		partialChainControl.Cfg.HSM = hsm
+	}
+
 	activeChainControl, cleanUp, err := implCfg.BuildChainControl(
 		partialChainControl, walletConfig,
 	)
@@ -398,6 +402,26 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 		return mkErr("error deriving node key: %v", err)
 	}
 
+	// The node key changes if the HSM is enabled or disabled for an
+	// existing node, which breaks all of its channels. So we refuse to
+	// start with a different node key than the one of the source node
+	// that was stored on a previous start.
+	sourceNode, err := dbs.GraphDB.ChannelGraph().SourceNode()
+	switch {
+	case err == nil:
+		idKey := idKeyDesc.PubKey.SerializeCompressed()
+		if !bytes.Equal(sourceNode.PubKeyBytes[:], idKey) {
+			return mkErr("node key %x doesn't match the key %x of "+
+				"the existing node, refusing to start", idKey,
+				sourceNode.PubKeyBytes[:])
+		}
+
+	case !errors.Is(err, channeldb.ErrSourceNodeNotSet) &&
+		!errors.Is(err, channeldb.ErrGraphNotFound):
+
+		return mkErr("unable to fetch source node: %v", err)
+	}
+
 	if cfg.Tor.StreamIsolation && cfg.Tor.SkipProxyForClearNetTargets {
 		return errStreamIsolationWithProxySkip
 	}
@@ -439,6 +463,12 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 		}()
 	}
 
+	// The tower bumps the fee of its justice transactions using the
+	// server's sweeper, but the server is only created after the tower.
+	// The tower only publishes justice transactions once it is started,
+	// which happens after the server was created.
+	var sweepTowerAnchor func(*wtdb.JusticeAnchor) error
+
 	var tower *watchtower.Standalone
 	if cfg.Watchtower.Active {
 		towerKeyDesc, err := activeChainControl.KeyRing.DeriveKey(
@@ -455,6 +485,7 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 			BlockFetcher:   activeChainControl.ChainIO,
 			DB:             dbs.TowerServerDB,
 			EpochRegistrar: activeChainControl.ChainNotifier,
+			FeeEstimator:   activeChainControl.FeeEstimator,
 			Net:            cfg.net,
 			NewAddress: func() (btcutil.Address, error) {
 				return activeChainControl.Wallet.NewAddress(
@@ -466,9 +497,20 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 				towerKeyDesc, activeChainControl.KeyRing,
 			),
 			PublishTx: activeChainControl.Wallet.PublishTransaction,
+			CPFPAnchor: func(anchor *wtdb.JusticeAnchor) error {
+				return sweepTowerAnchor(anchor)
+			},
 			ChainHash: *cfg.ActiveNetParams.GenesisHash,
 		}
 
+		// If prometheus is enabled, export the metrics of the tower
+		// alongside the rest of lnd's metrics.
+		if cfg.Prometheus.Enabled() {
+			wtCfg.Metrics = monitoring.NewTowerMetrics(
+				dbs.TowerServerDB.StorageBytes,
+			)
+		}
+
 		// If there is a tor controller (user wants auto hidden
 		// services), then store a pointer in the watchtower config.
 		if torController != nil {
@@ -511,11 +553,13 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 	server, err := newServer(
 		cfg, cfg.Listeners, dbs, activeChainControl, &idKeyDesc,
 		activeChainControl.Cfg.WalletUnlockParams.ChansToRestore,
-		multiAcceptor, torController,
+		multiAcceptor, torController, implCfg.OnionProcessorBuilder,
+		implCfg.HtlcHoldHookBuilder, implCfg.EdgeScorerBuilder, tlsMgr,
 	)
 	if err != nil {
 		return mkErr("unable to create server: %v", err)
 	}
+	sweepTowerAnchor = server.sweepTowerAnchor
 
 	// Set up an autopilot manager from the current config. This will be
 	// used to manage the underlying autopilot agent, starting and stopping
@@ -623,26 +667,41 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 	}
 
 	// Wait for shutdown signal from either a graceful server stop or from
-	// the interrupt handler.
-	<-interceptor.ShutdownChannel()
-	return nil
+	// the interrupt handler. In the meantime, we reload the config
+	// whenever we receive SIGHUP.
+	for {
+		select {
+		case <-interceptor.ReloadChannel():
+			ltndLog.Infof("Received reload signal, reloading " +
+				"config")
+
+			if _, err := server.reloadConfig(); err != nil {
+				ltndLog.Errorf("Unable to reload config: %v",
+					err)
+			}
+
+		case <-interceptor.ShutdownChannel():
+			return nil
+		}
+	}
 }
 
 // getTLSConfig returns a TLS configuration for the gRPC server and credentials
-// and a proxy destination for the REST reverse proxy.
+// and a proxy destination for the REST reverse proxy. The returned TLS manager
+// holds the certificate that is served, and can replace it at runtime.
 func getTLSConfig(cfg *Config) ([]grpc.ServerOption, []grpc.DialOption,
-	func(net.Addr) (net.Listener, error), func(), error) {
+	func(net.Addr) (net.Listener, error), *tlsManager, func(), error) {
 
 	// Ensure we create TLS key and certificate if they don't exist.
 	if !fileExists(cfg.TLSCertPath) && !fileExists(cfg.TLSKeyPath) {
 		rpcsLog.Infof("Generating TLS certificates...")
 		err := cert.GenCertPair(
-			"lnd autogenerated cert", cfg.TLSCertPath,
+			tlsCertOrganization, cfg.TLSCertPath,
 			cfg.TLSKeyPath, cfg.TLSExtraIPs, cfg.TLSExtraDomains,
 			cfg.TLSDisableAutofill, cfg.TLSCertDuration,
 		)
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, err
 		}
 		rpcsLog.Infof("Done generating TLS certificates")
 	}
@@ -651,7 +710,7 @@ func getTLSConfig(cfg *Config) ([]grpc.ServerOption, []grpc.DialOption,
 		cfg.TLSCertPath, cfg.TLSKeyPath,
 	)
 	if err != nil {
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
 	}
 
 	// We check whether the certificate we have on disk match the IPs and
@@ -665,7 +724,7 @@ func getTLSConfig(cfg *Config) ([]grpc.ServerOption, []grpc.DialOption,
 			cfg.TLSExtraDomains, cfg.TLSDisableAutofill,
 		)
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, err
 		}
 	}
 
@@ -677,40 +736,51 @@ func getTLSConfig(cfg *Config) ([]grpc.ServerOption, []grpc.DialOption,
 
 		err := os.Remove(cfg.TLSCertPath)
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, err
 		}
 
 		err = os.Remove(cfg.TLSKeyPath)
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, err
 		}
 
 		rpcsLog.Infof("Renewing TLS certificates...")
 		err = cert.GenCertPair(
-			"lnd autogenerated cert", cfg.TLSCertPath,
+			tlsCertOrganization, cfg.TLSCertPath,
 			cfg.TLSKeyPath, cfg.TLSExtraIPs, cfg.TLSExtraDomains,
 			cfg.TLSDisableAutofill, cfg.TLSCertDuration,
 		)
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, err
 		}
 		rpcsLog.Infof("Done renewing TLS certificates")
 
 		// Reload the certificate data.
-		certData, _, err = cert.LoadCert(
+		certData, parsedCert, err = cert.LoadCert(
 			cfg.TLSCertPath, cfg.TLSKeyPath,
 		)
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, err
 		}
 	}
 
+	// The certificate is looked up from the TLS manager for every new
+	// connection, so that it can be rotated without restarting the
+	// listeners.
+	tlsMgr := newTLSManager(cfg, certData, parsedCert)
 	tlsCfg := cert.TLSConfFromCert(certData)
-
-	restCreds, err := credentials.NewClientTLSFromFile(cfg.TLSCertPath, "")
-	if err != nil {
-		return nil, nil, nil, nil, err
-	}
+	tlsCfg.Certificates = nil
+	tlsCfg.GetCertificate = tlsMgr.GetCertificate
+
+	// The REST proxy connects to our own gRPC server, so we only accept
+	// the certificate that we currently serve. This replaces the usual
+	// chain verification, which would fail after a rotation if the proxy
+	// kept trusting the certificate it was started with.
+	restCreds := credentials.NewTLS(&tls.Config{
+		InsecureSkipVerify:    true, // nolint:gosec
+		VerifyPeerCertificate: tlsMgr.verifyServerCert,
+		MinVersion:            tls.VersionTLS12,
+	})
 
 	// If Let's Encrypt is enabled, instantiate autocert to request/renew
 	// the certificates.
@@ -759,7 +829,7 @@ func getTLSConfig(cfg *Config) ([]grpc.ServerOption, []grpc.DialOption,
 			lecert, err := manager.GetCertificate(h)
 			if err != nil {
 				ltndLog.Errorf("GetCertificate: %v", err)
-				return &certData, nil
+				return tlsMgr.GetCertificate(h)
 			}
 
 			return lecert, err
@@ -796,7 +866,7 @@ func getTLSConfig(cfg *Config) ([]grpc.ServerOption, []grpc.DialOption,
 		return lncfg.TLSListenOnAddress(addr, tlsCfg)
 	}
 
-	return serverOpts, restDialOpts, restListen, cleanUp, nil
+	return serverOpts, restDialOpts, restListen, tlsMgr, cleanUp, nil
 }
 
 // fileExists reports whether the named file or directory exists.
@@ -1010,6 +1080,12 @@ func startRestProxy(cfg *Config, rpcServer *rpcServer, restDialOpts []grpc.DialO
 	restHandler := lnrpc.NewWebSocketProxy(
 		mux, rpcsLog, cfg.WSPingInterval, cfg.WSPongWait,
 		lnrpc.LndClientStreamingURIs,
+		lnrpc.WithHeartbeatInterval(cfg.WSHeartbeatInterval),
+		lnrpc.WithMaxFrameSize(cfg.WSMaxFrameSize),
+		lnrpc.WithResumableStreams(
+			cfg.WSResumeWindow, lnrpc.LndResumableStreamURIs,
+			rpcServer.interceptorChain.CheckMacaroon,
+		),
 	)
 
 	// Use a WaitGroup so we can be sure the instructions on how to input the
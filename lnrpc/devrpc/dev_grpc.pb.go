@@ -22,6 +22,12 @@ type DevClient interface {
 	// ImportGraph imports a ChannelGraph into the graph database. Should only be
 	// used for development.
 	ImportGraph(ctx context.Context, in *lnrpc.ChannelGraph, opts ...grpc.CallOption) (*ImportGraphResponse, error)
+	// SimulateForwards checks a sequence of synthetic forwards against the
+	// current liquidity and forwarding policies of our channels, without adding
+	// any HTLCs. The forwards are simulated in order, and every forward that
+	// would succeed reserves its amounts for the forwards that follow it. Should
+	// only be used for development and capacity planning.
+	SimulateForwards(ctx context.Context, in *SimulateForwardsRequest, opts ...grpc.CallOption) (*SimulateForwardsResponse, error)
 }
 
 type devClient struct {
@@ -41,6 +47,15 @@ func (c *devClient) ImportGraph(ctx context.Context, in *lnrpc.ChannelGraph, opt
 	return out, nil
 }
 
+func (c *devClient) SimulateForwards(ctx context.Context, in *SimulateForwardsRequest, opts ...grpc.CallOption) (*SimulateForwardsResponse, error) {
+	out := new(SimulateForwardsResponse)
+	err := c.cc.Invoke(ctx, "/devrpc.Dev/SimulateForwards", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DevServer is the server API for Dev service.
 // All implementations must embed UnimplementedDevServer
 // for forward compatibility
@@ -48,6 +63,12 @@ type DevServer interface {
 	// ImportGraph imports a ChannelGraph into the graph database. Should only be
 	// used for development.
 	ImportGraph(context.Context, *lnrpc.ChannelGraph) (*ImportGraphResponse, error)
+	// SimulateForwards checks a sequence of synthetic forwards against the
+	// current liquidity and forwarding policies of our channels, without adding
+	// any HTLCs. The forwards are simulated in order, and every forward that
+	// would succeed reserves its amounts for the forwards that follow it. Should
+	// only be used for development and capacity planning.
+	SimulateForwards(context.Context, *SimulateForwardsRequest) (*SimulateForwardsResponse, error)
 	mustEmbedUnimplementedDevServer()
 }
 
@@ -58,6 +79,9 @@ type UnimplementedDevServer struct {
 func (UnimplementedDevServer) ImportGraph(context.Context, *lnrpc.ChannelGraph) (*ImportGraphResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ImportGraph not implemented")
 }
+func (UnimplementedDevServer) SimulateForwards(context.Context, *SimulateForwardsRequest) (*SimulateForwardsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SimulateForwards not implemented")
+}
 func (UnimplementedDevServer) mustEmbedUnimplementedDevServer() {}
 
 // UnsafeDevServer may be embedded to opt out of forward compatibility for this service.
@@ -89,6 +113,24 @@ func _Dev_ImportGraph_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Dev_SimulateForwards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimulateForwardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DevServer).SimulateForwards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/devrpc.Dev/SimulateForwards",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DevServer).SimulateForwards(ctx, req.(*SimulateForwardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Dev_ServiceDesc is the grpc.ServiceDesc for Dev service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -100,6 +142,10 @@ var Dev_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ImportGraph",
 			Handler:    _Dev_ImportGraph_Handler,
 		},
+		{
+			MethodName: "SimulateForwards",
+			Handler:    _Dev_SimulateForwards_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "devrpc/dev.proto",
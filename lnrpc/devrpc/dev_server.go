@@ -17,6 +17,7 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -39,6 +40,10 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/devrpc.Dev/SimulateForwards": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 	}
 )
 
@@ -340,3 +345,59 @@ func (s *Server) ImportGraph(ctx context.Context,
 
 	return &ImportGraphResponse{}, nil
 }
+
+// SimulateForwards checks a sequence of synthetic forwards against the current
+// liquidity and forwarding policies of our channels, without adding any HTLCs.
+//
+// NOTE: Part of the DevServer interface.
+func (s *Server) SimulateForwards(ctx context.Context,
+	req *SimulateForwardsRequest) (*SimulateForwardsResponse, error) {
+
+	forwards := make([]htlcswitch.SimulatedForward, 0, len(req.Forwards))
+	for _, rpcForward := range req.Forwards {
+		if rpcForward.AmtOutMsat == 0 {
+			return nil, fmt.Errorf("amount to forward must be set")
+		}
+
+		forwards = append(forwards, htlcswitch.SimulatedForward{
+			IncomingChanID: lnwire.NewShortChanIDFromInt(
+				rpcForward.IncomingChanId,
+			),
+			OutgoingChanID: lnwire.NewShortChanIDFromInt(
+				rpcForward.OutgoingChanId,
+			),
+			AmtIn:          lnwire.MilliSatoshi(rpcForward.AmtInMsat),
+			AmtOut:         lnwire.MilliSatoshi(rpcForward.AmtOutMsat),
+			IncomingExpiry: rpcForward.IncomingExpiry,
+			OutgoingExpiry: rpcForward.OutgoingExpiry,
+		})
+	}
+
+	results := s.cfg.HtlcSwitch.SimulateForwards(forwards)
+
+	resp := &SimulateForwardsResponse{
+		Results: make([]*SimulatedForwardResult, 0, len(results)),
+	}
+	for _, result := range results {
+		fwd := result.Forward
+		rpcResult := &SimulatedForwardResult{
+			Forward: &SimulatedForward{
+				IncomingChanId: fwd.IncomingChanID.ToUint64(),
+				OutgoingChanId: fwd.OutgoingChanID.ToUint64(),
+				AmtInMsat:      uint64(fwd.AmtIn),
+				AmtOutMsat:     uint64(fwd.AmtOut),
+				IncomingExpiry: fwd.IncomingExpiry,
+				OutgoingExpiry: fwd.OutgoingExpiry,
+			},
+			Success:               result.Err == nil,
+			OutgoingBandwidthMsat: uint64(result.OutgoingBandwidth),
+		}
+		if result.Err != nil {
+			rpcResult.Failure = result.Err.Error()
+		}
+
+		resp.Results = append(resp.Results, rpcResult)
+	}
+
+	return resp, nil
+}
@@ -76,6 +76,10 @@ type AddInvoiceConfig struct {
 	// GetAlias allows the peer's alias SCID to be retrieved for private
 	// option_scid_alias channels.
 	GetAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error)
+
+	// LearnedPolicy returns the policy an LSP announced for the private
+	// channel with the given SCID, or nil if none was announced.
+	LearnedPolicy func(scid uint64) *channeldb.ChannelEdgePolicy
 }
 
 // AddInvoiceData contains the required data to create a new invoice.
@@ -129,6 +133,55 @@ type AddInvoiceData struct {
 	// RouteHints are optional route hints that can each be individually used
 	// to assist in reaching the invoice's destination.
 	RouteHints [][]zpay32.HopHint
+
+	// ReissuedFrom is the payment hash of the invoice the new invoice
+	// replaces. If set, the original invoice is canceled once the new
+	// invoice is added.
+	ReissuedFrom *lntypes.Hash
+}
+
+// ReissueInvoiceData returns the data to create a new invoice that replaces the
+// given original invoice. The new invoice copies the amount, memo, expiry and
+// payment request details of the original invoice, while the caller is
+// responsible to set the preimage or hash of the new invoice.
+func ReissueInvoiceData(original *channeldb.Invoice, payHash lntypes.Hash,
+	chainParams *chaincfg.Params) (*AddInvoiceData, error) {
+
+	// Keysend invoices don't have a payment request that could be handed
+	// out again and AMP invoices can be paid repeatedly anyway, so only
+	// regular invoices can be reissued.
+	isAMP := original.Terms.Features.HasFeature(lnwire.AMPOptional)
+	if len(original.PaymentRequest) == 0 || isAMP {
+		return nil, channeldb.ErrInvoiceNotReissuable
+	}
+
+	payReq, err := zpay32.Decode(
+		string(original.PaymentRequest), chainParams,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode payment request: %v",
+			err)
+	}
+
+	data := &AddInvoiceData{
+		Memo:         string(original.Memo),
+		Value:        original.Terms.Value,
+		Expiry:       int64(original.Terms.Expiry.Seconds()),
+		CltvExpiry:   uint64(original.Terms.FinalCltvDelta),
+		HodlInvoice:  original.HodlInvoice,
+		RouteHints:   payReq.RouteHints,
+		ReissuedFrom: &payHash,
+	}
+
+	if payReq.DescriptionHash != nil {
+		data.DescriptionHash = payReq.DescriptionHash[:]
+	}
+
+	if payReq.FallbackAddr != nil {
+		data.FallbackAddr = payReq.FallbackAddr.String()
+	}
+
+	return data, nil
 }
 
 // paymentHashAndPreimage returns the payment hash and preimage for this invoice
@@ -490,7 +543,8 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 			PaymentAddr:     paymentAddr,
 			Features:        invoiceFeatures,
 		},
-		HodlInvoice: invoice.HodlInvoice,
+		HodlInvoice:  invoice.HodlInvoice,
+		ReissuedFrom: invoice.ReissuedFrom,
 	}
 
 	log.Tracef("[addinvoice] adding new invoice %v",
@@ -548,6 +602,16 @@ func chanCanBeHopHint(channel *HopHintInfo, cfg *SelectHopHintsCfg) (
 		return nil, false
 	}
 
+	// A policy the remote party announced to us as our LSP takes
+	// precedence over the graph, which doesn't know the policies of private
+	// channels the LSP never sent a channel update for.
+	if cfg.LearnedPolicy != nil {
+		policy := cfg.LearnedPolicy(channel.ShortChannelID)
+		if policy != nil {
+			return policy, true
+		}
+	}
+
 	// Fetch the policies for each end of the channel.
 	info, p1, p2, err := cfg.FetchChannelEdgesByID(channel.ShortChannelID)
 	if err != nil {
@@ -664,6 +728,11 @@ type SelectHopHintsCfg struct {
 	// GetAlias allows the peer's alias SCID to be retrieved for private
 	// option_scid_alias channels.
 	GetAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error)
+
+	// LearnedPolicy returns the policy an LSP announced for the private
+	// channel with the given SCID, or nil if none was announced. If nil,
+	// only the policies of the graph are used.
+	LearnedPolicy func(scid uint64) *channeldb.ChannelEdgePolicy
 }
 
 func newSelectHopHintsCfg(invoicesCfg *AddInvoiceConfig) *SelectHopHintsCfg {
@@ -671,6 +740,7 @@ func newSelectHopHintsCfg(invoicesCfg *AddInvoiceConfig) *SelectHopHintsCfg {
 		IsPublicNode:          invoicesCfg.Graph.IsPublicNode,
 		FetchChannelEdgesByID: invoicesCfg.Graph.FetchChannelEdgesByID,
 		GetAlias:              invoicesCfg.GetAlias,
+		LearnedPolicy:         invoicesCfg.LearnedPolicy,
 	}
 }
 
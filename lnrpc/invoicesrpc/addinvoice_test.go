@@ -181,6 +181,11 @@ func TestSelectHopHints(t *testing.T) {
 		}
 	)
 
+	// Create a hop hint for private channel 1 based on the policy of
+	// private channel 2, which we'll pretend was learned from our LSP.
+	learnedChannel1Hint := privateChannel2Hint
+	learnedChannel1Hint.ChannelID = private1ShortID
+
 	// We can't copy in the above var decls, so we copy in our pubkey here.
 	var peer [33]byte
 	copy(peer[:], compressed)
@@ -230,6 +235,10 @@ func TestSelectHopHints(t *testing.T) {
 		channels  []*HopHintInfo
 		numHints  int
 
+		// learnedPolicy returns the policies learned from LSPs, if
+		// set.
+		learnedPolicy func(uint64) *channeldb.ChannelEdgePolicy
+
 		// expectedHints is the set of hop hints that we expect. We
 		// initialize this slice with our max hop hints length, so this
 		// value won't be nil even if its empty.
@@ -534,6 +543,35 @@ func TestSelectHopHints(t *testing.T) {
 				},
 			},
 		},
+		{
+			// A policy learned from our LSP is used instead of
+			// the graph, which isn't queried for the channel.
+			name: "learned policy",
+			setupMock: func(h *hopHintsConfigMock) {
+				h.Mock.On(
+					"IsPublicNode", peer,
+				).Once().Return(true, nil)
+			},
+			learnedPolicy: func(
+				scid uint64) *channeldb.ChannelEdgePolicy {
+
+				if scid != private1ShortID {
+					return nil
+				}
+
+				return privateChan2Policy
+			},
+			amount: 30,
+			channels: []*HopHintInfo{
+				privateChannel1,
+			},
+			numHints: 1,
+			expectedHints: [][]zpay32.HopHint{
+				{
+					learnedChannel1Hint,
+				},
+			},
+		},
 	}
 
 	getAlias := func(lnwire.ChannelID) (lnwire.ShortChannelID, error) {
@@ -553,6 +591,7 @@ func TestSelectHopHints(t *testing.T) {
 				IsPublicNode:          mock.IsPublicNode,
 				FetchChannelEdgesByID: mock.FetchChannelEdgesByID,
 				GetAlias:              getAlias,
+				LearnedPolicy:         test.learnedPolicy,
 			}
 
 			hints := SelectHopHints(
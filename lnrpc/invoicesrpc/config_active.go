@@ -4,6 +4,8 @@
 package invoicesrpc
 
 import (
+	"time"
+
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/invoices"
@@ -64,4 +66,16 @@ type Config struct {
 	// GetAlias returns the peer's alias SCID if it exists given the
 	// 32-byte ChannelID.
 	GetAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error)
+
+	// LearnedPolicy returns the policy an LSP announced for the private
+	// channel with the given SCID, or nil if none was announced.
+	LearnedPolicy func(scid uint64) *channeldb.ChannelEdgePolicy
+
+	// InvoiceGcExpiredAfter is the configured time after their expiry
+	// after which unpaid invoices are garbage collected.
+	InvoiceGcExpiredAfter time.Duration
+
+	// InvoiceGcArchiveFile is the configured file that garbage collected
+	// invoices are archived to.
+	InvoiceGcArchiveFile string
 }
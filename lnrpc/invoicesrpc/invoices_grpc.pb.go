@@ -36,6 +36,18 @@ type InvoicesClient interface {
 	// LookupInvoiceV2 attempts to look up at invoice. An invoice can be refrenced
 	// using either its payment hash, payment address, or set ID.
 	LookupInvoiceV2(ctx context.Context, in *LookupInvoiceMsg, opts ...grpc.CallOption) (*lnrpc.Invoice, error)
+	// ReissueInvoice replaces an open, unexpired invoice that hasn't received any
+	// payments with a new invoice that has a fresh payment address and expiry.
+	// The new invoice copies the amount, memo and route hints of the original
+	// invoice, which is canceled. Both invoices are linked to each other through
+	// their reissued_from and reissued_to fields.
+	ReissueInvoice(ctx context.Context, in *ReissueInvoiceRequest, opts ...grpc.CallOption) (*ReissueInvoiceResp, error)
+	// GcExpiredInvoices deletes all unpaid invoices that expired more than the
+	// configured or requested age ago. If an archive file is configured, the
+	// invoices are appended to it before they are deleted. The stats of the
+	// garbage collection are returned, which can also be queried without
+	// triggering a garbage collection.
+	GcExpiredInvoices(ctx context.Context, in *GcExpiredInvoicesRequest, opts ...grpc.CallOption) (*GcExpiredInvoicesResponse, error)
 }
 
 type invoicesClient struct {
@@ -114,6 +126,24 @@ func (c *invoicesClient) LookupInvoiceV2(ctx context.Context, in *LookupInvoiceM
 	return out, nil
 }
 
+func (c *invoicesClient) ReissueInvoice(ctx context.Context, in *ReissueInvoiceRequest, opts ...grpc.CallOption) (*ReissueInvoiceResp, error) {
+	out := new(ReissueInvoiceResp)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/ReissueInvoice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesClient) GcExpiredInvoices(ctx context.Context, in *GcExpiredInvoicesRequest, opts ...grpc.CallOption) (*GcExpiredInvoicesResponse, error) {
+	out := new(GcExpiredInvoicesResponse)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/GcExpiredInvoices", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InvoicesServer is the server API for Invoices service.
 // All implementations must embed UnimplementedInvoicesServer
 // for forward compatibility
@@ -135,6 +165,18 @@ type InvoicesServer interface {
 	// LookupInvoiceV2 attempts to look up at invoice. An invoice can be refrenced
 	// using either its payment hash, payment address, or set ID.
 	LookupInvoiceV2(context.Context, *LookupInvoiceMsg) (*lnrpc.Invoice, error)
+	// ReissueInvoice replaces an open, unexpired invoice that hasn't received any
+	// payments with a new invoice that has a fresh payment address and expiry.
+	// The new invoice copies the amount, memo and route hints of the original
+	// invoice, which is canceled. Both invoices are linked to each other through
+	// their reissued_from and reissued_to fields.
+	ReissueInvoice(context.Context, *ReissueInvoiceRequest) (*ReissueInvoiceResp, error)
+	// GcExpiredInvoices deletes all unpaid invoices that expired more than the
+	// configured or requested age ago. If an archive file is configured, the
+	// invoices are appended to it before they are deleted. The stats of the
+	// garbage collection are returned, which can also be queried without
+	// triggering a garbage collection.
+	GcExpiredInvoices(context.Context, *GcExpiredInvoicesRequest) (*GcExpiredInvoicesResponse, error)
 	mustEmbedUnimplementedInvoicesServer()
 }
 
@@ -157,6 +199,12 @@ func (UnimplementedInvoicesServer) SettleInvoice(context.Context, *SettleInvoice
 func (UnimplementedInvoicesServer) LookupInvoiceV2(context.Context, *LookupInvoiceMsg) (*lnrpc.Invoice, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LookupInvoiceV2 not implemented")
 }
+func (UnimplementedInvoicesServer) ReissueInvoice(context.Context, *ReissueInvoiceRequest) (*ReissueInvoiceResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReissueInvoice not implemented")
+}
+func (UnimplementedInvoicesServer) GcExpiredInvoices(context.Context, *GcExpiredInvoicesRequest) (*GcExpiredInvoicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GcExpiredInvoices not implemented")
+}
 func (UnimplementedInvoicesServer) mustEmbedUnimplementedInvoicesServer() {}
 
 // UnsafeInvoicesServer may be embedded to opt out of forward compatibility for this service.
@@ -263,6 +311,42 @@ func _Invoices_LookupInvoiceV2_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Invoices_ReissueInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReissueInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).ReissueInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/ReissueInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).ReissueInvoice(ctx, req.(*ReissueInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Invoices_GcExpiredInvoices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GcExpiredInvoicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).GcExpiredInvoices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/GcExpiredInvoices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).GcExpiredInvoices(ctx, req.(*GcExpiredInvoicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Invoices_ServiceDesc is the grpc.ServiceDesc for Invoices service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -286,6 +370,14 @@ var Invoices_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LookupInvoiceV2",
 			Handler:    _Invoices_LookupInvoiceV2_Handler,
 		},
+		{
+			MethodName: "ReissueInvoice",
+			Handler:    _Invoices_ReissueInvoice_Handler,
+		},
+		{
+			MethodName: "GcExpiredInvoices",
+			Handler:    _Invoices_GcExpiredInvoices_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -5,13 +5,16 @@ package invoicesrpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/invoices"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/macaroons"
@@ -65,6 +68,14 @@ var (
 			Entity: "invoices",
 			Action: "write",
 		}},
+		"/invoicesrpc.Invoices/ReissueInvoice": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
+		"/invoicesrpc.Invoices/GcExpiredInvoices": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
 	}
 
 	// DefaultInvoicesMacFilename is the default name of the invoices
@@ -237,7 +248,9 @@ func (s *Server) SubscribeSingleInvoice(req *SubscribeSingleInvoiceRequest,
 		return err
 	}
 
-	invoiceClient, err := s.cfg.InvoiceRegistry.SubscribeSingleInvoice(hash)
+	invoiceClient, err := s.cfg.InvoiceRegistry.SubscribeSingleInvoiceSince(
+		hash, req.AddIndex, req.SettleIndex,
+	)
 	if err != nil {
 		return err
 	}
@@ -332,6 +345,7 @@ func (s *Server) AddHoldInvoice(ctx context.Context,
 		GenInvoiceFeatures:    s.cfg.GenInvoiceFeatures,
 		GenAmpInvoiceFeatures: s.cfg.GenAmpInvoiceFeatures,
 		GetAlias:              s.cfg.GetAlias,
+		LearnedPolicy:         s.cfg.LearnedPolicy,
 	}
 
 	hash, err := lntypes.MakeHash(invoice.Hash)
@@ -380,65 +394,227 @@ func (s *Server) AddHoldInvoice(ctx context.Context,
 func (s *Server) LookupInvoiceV2(ctx context.Context,
 	req *LookupInvoiceMsg) (*lnrpc.Invoice, error) {
 
-	var invoiceRef channeldb.InvoiceRef
-
 	// First, we'll attempt to parse out the invoice ref from the proto
-	// oneof.  If none of the three currently supported types was
-	// specified, then we'll exit with an error.
+	// oneof.
+	invoiceRef, err := parseInvoiceRef(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Attempt to locate the invoice, returning a nice "not found" error if
+	// we can't find it in the database.
+	invoice, err := s.cfg.InvoiceRegistry.LookupInvoiceByRef(invoiceRef)
+	switch {
+	case err == channeldb.ErrInvoiceNotFound:
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	return CreateRPCInvoice(&invoice, s.cfg.ChainParams)
+}
+
+// parseInvoiceRef parses the invoice reference of a lookup request. Payment
+// addresses and set IDs must be 32 bytes, as a shorter value would silently be
+// padded to reference a different invoice, and each lookup modifier is only
+// valid with the kind of reference it applies to.
+func parseInvoiceRef(req *LookupInvoiceMsg) (channeldb.InvoiceRef, error) {
+	parse32 := func(b []byte, name string) ([32]byte, error) {
+		var ref [32]byte
+		if len(b) != len(ref) {
+			return ref, fmt.Errorf("%v must be %d bytes, got %d",
+				name, len(ref), len(b))
+		}
+		copy(ref[:], b)
+
+		return ref, nil
+	}
+
+	modifier := req.LookupModifier
+
 	switch {
 	case req.GetPaymentHash() != nil:
+		if modifier != LookupModifier_DEFAULT {
+			return channeldb.InvoiceRef{}, fmt.Errorf("lookup "+
+				"modifier %v can't be used with a payment hash",
+				modifier)
+		}
+
 		payHash, err := lntypes.MakeHash(req.GetPaymentHash())
 		if err != nil {
-			return nil, status.Error(
-				codes.InvalidArgument,
-				fmt.Sprintf("unable to parse pay hash: %v", err),
-			)
+			return channeldb.InvoiceRef{}, fmt.Errorf("unable to "+
+				"parse pay hash: %v", err)
 		}
 
-		invoiceRef = channeldb.InvoiceRefByHash(payHash)
+		return channeldb.InvoiceRefByHash(payHash), nil
+
+	case req.GetPaymentAddr() != nil:
+		payAddr, err := parse32(req.GetPaymentAddr(), "payment addr")
+		if err != nil {
+			return channeldb.InvoiceRef{}, err
+		}
 
-	case req.GetPaymentAddr() != nil &&
-		req.LookupModifier == LookupModifier_HTLC_SET_BLANK:
+		switch modifier {
+		case LookupModifier_DEFAULT:
+			return channeldb.InvoiceRefByAddr(payAddr), nil
 
-		var payAddr [32]byte
-		copy(payAddr[:], req.GetPaymentAddr())
+		case LookupModifier_HTLC_SET_BLANK:
+			return channeldb.InvoiceRefByAddrBlankHtlc(payAddr), nil
+		}
 
-		invoiceRef = channeldb.InvoiceRefByAddrBlankHtlc(payAddr)
+		return channeldb.InvoiceRef{}, fmt.Errorf("lookup modifier "+
+			"%v can't be used with a payment addr", modifier)
 
-	case req.GetPaymentAddr() != nil:
-		var payAddr [32]byte
-		copy(payAddr[:], req.GetPaymentAddr())
+	case req.GetSetId() != nil:
+		setID, err := parse32(req.GetSetId(), "set id")
+		if err != nil {
+			return channeldb.InvoiceRef{}, err
+		}
 
-		invoiceRef = channeldb.InvoiceRefByAddr(payAddr)
+		switch modifier {
+		case LookupModifier_DEFAULT:
+			return channeldb.InvoiceRefBySetID(setID), nil
 
-	case req.GetSetId() != nil &&
-		req.LookupModifier == LookupModifier_HTLC_SET_ONLY:
+		case LookupModifier_HTLC_SET_ONLY:
+			return channeldb.InvoiceRefBySetIDFiltered(setID), nil
+		}
 
-		var setID [32]byte
-		copy(setID[:], req.GetSetId())
+		return channeldb.InvoiceRef{}, fmt.Errorf("lookup modifier "+
+			"%v can't be used with a set id", modifier)
 
-		invoiceRef = channeldb.InvoiceRefBySetIDFiltered(setID)
+	default:
+		return channeldb.InvoiceRef{}, errors.New("invoice ref must " +
+			"be set")
+	}
+}
 
-	case req.GetSetId() != nil:
-		var setID [32]byte
-		copy(setID[:], req.GetSetId())
+// ReissueInvoice replaces an open, unexpired invoice that hasn't received any
+// payments with a new invoice that has a fresh payment address and expiry. The
+// original invoice is canceled.
+func (s *Server) ReissueInvoice(ctx context.Context,
+	req *ReissueInvoiceRequest) (*ReissueInvoiceResp, error) {
 
-		invoiceRef = channeldb.InvoiceRefBySetID(setID)
+	payHash, err := lntypes.MakeHash(req.PaymentHash)
+	if err != nil {
+		return nil, err
+	}
 
-	default:
-		return nil, status.Error(codes.InvalidArgument,
-			"invoice ref must be set")
+	original, err := s.cfg.InvoiceRegistry.LookupInvoice(payHash)
+	if err != nil {
+		return nil, err
 	}
 
-	// Attempt to locate the invoice, returning a nice "not found" error if
-	// we can't find it in the database.
-	invoice, err := s.cfg.InvoiceRegistry.LookupInvoiceByRef(invoiceRef)
+	addInvoiceData, err := ReissueInvoiceData(
+		&original, payHash, s.cfg.ChainParams,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Expiry != 0 {
+		addInvoiceData.Expiry = req.Expiry
+	}
+
+	// Invoices are indexed by their payment hash, so the new invoice
+	// always needs a new preimage or hash. Without either, we generate a
+	// random preimage, which isn't possible for hold invoices as their
+	// preimage is only known to the caller.
 	switch {
-	case err == channeldb.ErrInvoiceNotFound:
-		return nil, status.Error(codes.NotFound, err.Error())
-	case err != nil:
+	case len(req.Preimage) != 0 && len(req.Hash) != 0:
+		return nil, status.Error(
+			codes.InvalidArgument, "preimage and hash both set",
+		)
+
+	case len(req.Preimage) != 0:
+		preimage, err := lntypes.MakePreimage(req.Preimage)
+		if err != nil {
+			return nil, err
+		}
+		addInvoiceData.Preimage = &preimage
+		addInvoiceData.HodlInvoice = false
+
+	case len(req.Hash) != 0:
+		hash, err := lntypes.MakeHash(req.Hash)
+		if err != nil {
+			return nil, err
+		}
+		addInvoiceData.Hash = &hash
+		addInvoiceData.HodlInvoice = true
+
+	case original.HodlInvoice:
+		return nil, status.Error(
+			codes.InvalidArgument, "hash must be set to reissue "+
+				"a hold invoice",
+		)
+	}
+
+	addInvoiceCfg := &AddInvoiceConfig{
+		AddInvoice:            s.cfg.InvoiceRegistry.AddInvoice,
+		IsChannelActive:       s.cfg.IsChannelActive,
+		ChainParams:           s.cfg.ChainParams,
+		NodeSigner:            s.cfg.NodeSigner,
+		DefaultCLTVExpiry:     s.cfg.DefaultCLTVExpiry,
+		ChanDB:                s.cfg.ChanStateDB,
+		Graph:                 s.cfg.GraphDB,
+		GenInvoiceFeatures:    s.cfg.GenInvoiceFeatures,
+		GenAmpInvoiceFeatures: s.cfg.GenAmpInvoiceFeatures,
+		GetAlias:              s.cfg.GetAlias,
+		LearnedPolicy:         s.cfg.LearnedPolicy,
+	}
+
+	newHash, dbInvoice, err := AddInvoice(
+		ctx, addInvoiceCfg, addInvoiceData,
+	)
+	if err != nil {
 		return nil, err
 	}
 
-	return CreateRPCInvoice(&invoice, s.cfg.ChainParams)
+	log.Infof("Reissued invoice %v as %v", payHash, newHash)
+
+	return &ReissueInvoiceResp{
+		PaymentRequest: string(dbInvoice.PaymentRequest),
+		AddIndex:       dbInvoice.AddIndex,
+		PaymentAddr:    dbInvoice.Terms.PaymentAddr[:],
+		RHash:          newHash[:],
+	}, nil
+}
+
+// GcExpiredInvoices deletes all unpaid invoices that expired more than the
+// configured or requested age ago and returns the stats of the garbage
+// collection.
+func (s *Server) GcExpiredInvoices(_ context.Context,
+	req *GcExpiredInvoicesRequest) (*GcExpiredInvoicesResponse, error) {
+
+	registry := s.cfg.InvoiceRegistry
+	stats := registry.GcStats()
+
+	if !req.StatsOnly {
+		expiredAfter := time.Duration(req.ExpiredAfterSec) * time.Second
+
+		var err error
+		stats, err = registry.GcExpiredInvoices(expiredAfter)
+		switch {
+		case err == invoices.ErrInvoiceGcDisabled:
+			return nil, status.Error(
+				codes.InvalidArgument, "expired_after_sec "+
+					"must be set if invoices."+
+					"gc-expired-after isn't configured",
+			)
+
+		case err != nil:
+			return nil, err
+		}
+	}
+
+	resp := &GcExpiredInvoicesResponse{
+		LastDeleted:     stats.LastDeleted,
+		TotalDeleted:    stats.TotalDeleted,
+		ExpiredAfterSec: uint64(s.cfg.InvoiceGcExpiredAfter.Seconds()),
+		ArchiveFile:     s.cfg.InvoiceGcArchiveFile,
+	}
+	if !stats.LastRun.IsZero() {
+		resp.LastRun = stats.LastRun.Unix()
+	}
+
+	return resp, nil
 }
@@ -0,0 +1,112 @@
+//go:build invoicesrpc
+// +build invoicesrpc
+
+package invoicesrpc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseInvoiceRef tests that the invoice references of lookup requests are
+// parsed into the matching channeldb references, and that malformed ones are
+// rejected.
+func TestParseInvoiceRef(t *testing.T) {
+	t.Parallel()
+
+	var (
+		hash  = bytes.Repeat([]byte{1}, 32)
+		addr  = [32]byte{2}
+		setID = [32]byte{3}
+	)
+
+	testCases := []struct {
+		name string
+		req  *LookupInvoiceMsg
+		ref  channeldb.InvoiceRef
+		err  string
+	}{{
+		name: "payment addr",
+		req: &LookupInvoiceMsg{
+			InvoiceRef: &LookupInvoiceMsg_PaymentAddr{
+				PaymentAddr: addr[:],
+			},
+		},
+		ref: channeldb.InvoiceRefByAddr(addr),
+	}, {
+		name: "payment addr blank htlc set",
+		req: &LookupInvoiceMsg{
+			InvoiceRef: &LookupInvoiceMsg_PaymentAddr{
+				PaymentAddr: addr[:],
+			},
+			LookupModifier: LookupModifier_HTLC_SET_BLANK,
+		},
+		ref: channeldb.InvoiceRefByAddrBlankHtlc(addr),
+	}, {
+		name: "set id only",
+		req: &LookupInvoiceMsg{
+			InvoiceRef: &LookupInvoiceMsg_SetId{
+				SetId: setID[:],
+			},
+			LookupModifier: LookupModifier_HTLC_SET_ONLY,
+		},
+		ref: channeldb.InvoiceRefBySetIDFiltered(setID),
+	}, {
+		name: "short payment addr",
+		req: &LookupInvoiceMsg{
+			InvoiceRef: &LookupInvoiceMsg_PaymentAddr{
+				PaymentAddr: addr[:31],
+			},
+		},
+		err: "payment addr must be 32 bytes",
+	}, {
+		name: "long set id",
+		req: &LookupInvoiceMsg{
+			InvoiceRef: &LookupInvoiceMsg_SetId{
+				SetId: append(setID[:], 0),
+			},
+		},
+		err: "set id must be 32 bytes",
+	}, {
+		name: "set only modifier with payment addr",
+		req: &LookupInvoiceMsg{
+			InvoiceRef: &LookupInvoiceMsg_PaymentAddr{
+				PaymentAddr: addr[:],
+			},
+			LookupModifier: LookupModifier_HTLC_SET_ONLY,
+		},
+		err: "can't be used with a payment addr",
+	}, {
+		name: "modifier with payment hash",
+		req: &LookupInvoiceMsg{
+			InvoiceRef: &LookupInvoiceMsg_PaymentHash{
+				PaymentHash: hash,
+			},
+			LookupModifier: LookupModifier_HTLC_SET_BLANK,
+		},
+		err: "can't be used with a payment hash",
+	}, {
+		name: "no ref",
+		req:  &LookupInvoiceMsg{},
+		err:  "invoice ref must be set",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ref, err := parseInvoiceRef(tc.req)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.ref, ref)
+		})
+	}
+}
@@ -212,6 +212,14 @@ func CreateRPCInvoice(invoice *channeldb.Invoice,
 		rpcInvoice.RPreimage = preimage[:]
 	}
 
+	if invoice.ReissuedFrom != nil {
+		rpcInvoice.ReissuedFrom = invoice.ReissuedFrom[:]
+	}
+
+	if invoice.ReissuedTo != nil {
+		rpcInvoice.ReissuedTo = invoice.ReissuedTo[:]
+	}
+
 	return rpcInvoice, nil
 }
 
@@ -115,11 +115,28 @@ type LightningClient interface {
 	// ListChannels returns a description of all the open channels that this node
 	// is a participant in.
 	ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error)
+	// lncli: `channelliquidity`
+	// ChannelLiquidity returns a real-time breakdown of the liquidity of the
+	// active channels, including the amounts that can currently be sent and
+	// received, the pending HTLCs in both directions, the commitment fee buffer
+	// and the channel reserves.
+	ChannelLiquidity(ctx context.Context, in *ChannelLiquidityRequest, opts ...grpc.CallOption) (*ChannelLiquidityResponse, error)
+	// lncli: `channeldust`
+	// ChannelDust reports, for each open channel, the dust limits of both
+	// commitments, the amounts below which HTLCs are trimmed from them and the
+	// value that isn't protected on-chain because it's trimmed to dust.
+	ChannelDust(ctx context.Context, in *ChannelDustRequest, opts ...grpc.CallOption) (*ChannelDustResponse, error)
 	// SubscribeChannelEvents creates a uni-directional stream from the server to
 	// the client in which any updates relevant to the state of the channels are
 	// sent over. Events include new active channels, inactive channels, and closed
 	// channels.
 	SubscribeChannelEvents(ctx context.Context, in *ChannelEventSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelEventsClient, error)
+	// SubscribeChannelArbitratorEvents creates a uni-directional stream from the
+	// server to the client in which the state transitions of our channel
+	// arbitrators are sent over. This allows the progress of a force close to be
+	// followed from the decision to go on-chain until all of the channel's
+	// contracts are fully resolved.
+	SubscribeChannelArbitratorEvents(ctx context.Context, in *ChannelArbitratorEventSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelArbitratorEventsClient, error)
 	// lncli: `closedchannels`
 	// ClosedChannels returns a description of all the closed channels that
 	// this node was a participant in.
@@ -178,6 +195,13 @@ type LightningClient interface {
 	// never broadcast. Only available for non-externally funded channels in dev
 	// build.
 	AbandonChannel(ctx context.Context, in *AbandonChannelRequest, opts ...grpc.CallOption) (*AbandonChannelResponse, error)
+	// lncli: `closechannel --force --dry_run`
+	// DryRunForceClose returns what a force close of the channel would broadcast
+	// at its current state, without broadcasting anything or changing the state
+	// of the channel. This allows operators to evaluate the fees, time locks and
+	// HTLC outputs of a force close before deciding to go to chain. The returned
+	// details may become outdated as soon as the channel state advances.
+	DryRunForceClose(ctx context.Context, in *DryRunForceCloseRequest, opts ...grpc.CallOption) (*DryRunForceCloseResponse, error)
 	// Deprecated: Do not use.
 	// lncli: `sendpayment`
 	// Deprecated, use routerrpc.SendPaymentV2. SendPayment dispatches a
@@ -245,6 +269,13 @@ type LightningClient interface {
 	// DeleteAllPayments deletes all outgoing payments from DB. Note that it will
 	// not attempt to delete In-Flight payments, since that would be unsafe.
 	DeleteAllPayments(ctx context.Context, in *DeleteAllPaymentsRequest, opts ...grpc.CallOption) (*DeleteAllPaymentsResponse, error)
+	// lncli: `cleanupduplicatepayments`
+	// CleanupDuplicatePayments deletes the failed and incomplete duplicate
+	// payments and the dangling payment index entries left behind by old
+	// versions of lnd from the DB. Succeeded duplicate payments are kept. If
+	// dry_run is set, nothing is deleted and only a report of what would be
+	// deleted is returned.
+	CleanupDuplicatePayments(ctx context.Context, in *CleanupDuplicatePaymentsRequest, opts ...grpc.CallOption) (*CleanupDuplicatePaymentsResponse, error)
 	// lncli: `describegraph`
 	// DescribeGraph returns a description of the latest graph state from the
 	// point of view of the node. The graph information is partitioned into two
@@ -253,6 +284,13 @@ type LightningClient interface {
 	// the node directional specific routing policy which includes: the time lock
 	// delta, fee information, etc.
 	DescribeGraph(ctx context.Context, in *ChannelGraphRequest, opts ...grpc.CallOption) (*ChannelGraph, error)
+	// lncli: `describegraphslice`
+	// DescribeGraphSlice returns the part of the graph that is relevant to a
+	// light client: the nodes within a number of hops of the given nodes, and the
+	// channels between them that have at least the given capacity. This allows a
+	// light client to use our node as a gossip relay, rather than syncing and
+	// validating the full graph itself.
+	DescribeGraphSlice(ctx context.Context, in *GraphSliceRequest, opts ...grpc.CallOption) (*ChannelGraph, error)
 	// lncli: `getnodemetrics`
 	// GetNodeMetrics returns node metrics calculated from the graph. Currently
 	// the only supported metric is betweenness centrality of individual nodes.
@@ -283,6 +321,12 @@ type LightningClient interface {
 	// GetNetworkInfo returns some basic stats about the known channel graph from
 	// the point of view of the node.
 	GetNetworkInfo(ctx context.Context, in *NetworkInfoRequest, opts ...grpc.CallOption) (*NetworkInfo, error)
+	// lncli: `getrebroadcastschedule`
+	// GetRebroadcastSchedule returns the time at which the channel update of each
+	// of our public channels is next refreshed and rebroadcast to the network.
+	// Channel updates are rebroadcast periodically, even if nothing changed, to
+	// prevent the network from pruning our channels as stale.
+	GetRebroadcastSchedule(ctx context.Context, in *RebroadcastScheduleRequest, opts ...grpc.CallOption) (*RebroadcastScheduleResponse, error)
 	// lncli: `stop`
 	// StopDaemon will send a shutdown request to the interrupt handler, triggering
 	// a graceful shutdown of the daemon.
@@ -300,6 +344,23 @@ type LightningClient interface {
 	// level, or in a granular fashion to specify the logging for a target
 	// sub-system.
 	DebugLevel(ctx context.Context, in *DebugLevelRequest, opts ...grpc.CallOption) (*DebugLevelResponse, error)
+	// lncli: `reloadconfig`
+	// ReloadConfig re-reads lnd.conf and applies the options that can safely be
+	// changed while lnd is running: the debug level, the default routing policy
+	// of new channels, the throttling of incoming channel updates and the
+	// intervals of the health checks. All other options only take effect after
+	// a restart. Options set on the command line keep taking precedence. The
+	// same reload is triggered by sending SIGHUP to lnd.
+	// If tlsautorotate is set, changes of tlsextraip and tlsextradomain are
+	// applied by rotating the TLS certificate.
+	ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error)
+	// lncli: `rotatetlscert`
+	// RotateTLSCert regenerates the TLS certificate and key of the RPC server
+	// with the currently configured extra IPs and domains, and serves the new
+	// certificate for new connections without a restart. Existing connections
+	// are not affected. The new certificate is written to the configured
+	// tlscertpath, so clients need to reload it before they connect again.
+	RotateTLSCert(ctx context.Context, in *RotateTLSCertRequest, opts ...grpc.CallOption) (*RotateTLSCertResponse, error)
 	// lncli: `feereport`
 	// FeeReport allows the caller to obtain a report detailing the current fee
 	// schedule enforced by the node globally for each channel.
@@ -393,6 +454,13 @@ type LightningClient interface {
 	// SubscribeCustomMessages subscribes to a stream of incoming custom peer
 	// messages.
 	SubscribeCustomMessages(ctx context.Context, in *SubscribeCustomMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeCustomMessagesClient, error)
+	// lncli: `subscribepeermessages`
+	// SubscribePeerMessages returns a debug stream of summaries of the wire
+	// messages exchanged with our peers. The stream can be restricted to a set
+	// of peers and message types, and sampled to reduce its volume. This allows
+	// protocol issues to be inspected without restarting lnd with trace logging
+	// enabled.
+	SubscribePeerMessages(ctx context.Context, in *SubscribePeerMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribePeerMessagesClient, error)
 	// lncli: `listaliases`
 	// ListAliases returns the set of all aliases that have ever existed with
 	// their confirmed SCID (if it exists) and/or the base SCID (in the case of
@@ -625,6 +693,24 @@ func (c *lightningClient) ListChannels(ctx context.Context, in *ListChannelsRequ
 	return out, nil
 }
 
+func (c *lightningClient) ChannelLiquidity(ctx context.Context, in *ChannelLiquidityRequest, opts ...grpc.CallOption) (*ChannelLiquidityResponse, error) {
+	out := new(ChannelLiquidityResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ChannelLiquidity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ChannelDust(ctx context.Context, in *ChannelDustRequest, opts ...grpc.CallOption) (*ChannelDustResponse, error) {
+	out := new(ChannelDustResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ChannelDust", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) SubscribeChannelEvents(ctx context.Context, in *ChannelEventSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelEventsClient, error) {
 	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[2], "/lnrpc.Lightning/SubscribeChannelEvents", opts...)
 	if err != nil {
@@ -657,6 +743,38 @@ func (x *lightningSubscribeChannelEventsClient) Recv() (*ChannelEventUpdate, err
 	return m, nil
 }
 
+func (c *lightningClient) SubscribeChannelArbitratorEvents(ctx context.Context, in *ChannelArbitratorEventSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelArbitratorEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[3], "/lnrpc.Lightning/SubscribeChannelArbitratorEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeChannelArbitratorEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeChannelArbitratorEventsClient interface {
+	Recv() (*ChannelArbitratorEvent, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeChannelArbitratorEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeChannelArbitratorEventsClient) Recv() (*ChannelArbitratorEvent, error) {
+	m := new(ChannelArbitratorEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *lightningClient) ClosedChannels(ctx context.Context, in *ClosedChannelsRequest, opts ...grpc.CallOption) (*ClosedChannelsResponse, error) {
 	out := new(ClosedChannelsResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ClosedChannels", in, out, opts...)
@@ -676,7 +794,7 @@ func (c *lightningClient) OpenChannelSync(ctx context.Context, in *OpenChannelRe
 }
 
 func (c *lightningClient) OpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (Lightning_OpenChannelClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[3], "/lnrpc.Lightning/OpenChannel", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[4], "/lnrpc.Lightning/OpenChannel", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -726,7 +844,7 @@ func (c *lightningClient) FundingStateStep(ctx context.Context, in *FundingTrans
 }
 
 func (c *lightningClient) ChannelAcceptor(ctx context.Context, opts ...grpc.CallOption) (Lightning_ChannelAcceptorClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[4], "/lnrpc.Lightning/ChannelAcceptor", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[5], "/lnrpc.Lightning/ChannelAcceptor", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -757,7 +875,7 @@ func (x *lightningChannelAcceptorClient) Recv() (*ChannelAcceptRequest, error) {
 }
 
 func (c *lightningClient) CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[5], "/lnrpc.Lightning/CloseChannel", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[6], "/lnrpc.Lightning/CloseChannel", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -797,9 +915,18 @@ func (c *lightningClient) AbandonChannel(ctx context.Context, in *AbandonChannel
 	return out, nil
 }
 
+func (c *lightningClient) DryRunForceClose(ctx context.Context, in *DryRunForceCloseRequest, opts ...grpc.CallOption) (*DryRunForceCloseResponse, error) {
+	out := new(DryRunForceCloseResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/DryRunForceClose", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Deprecated: Do not use.
 func (c *lightningClient) SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[6], "/lnrpc.Lightning/SendPayment", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[7], "/lnrpc.Lightning/SendPayment", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -840,7 +967,7 @@ func (c *lightningClient) SendPaymentSync(ctx context.Context, in *SendRequest,
 
 // Deprecated: Do not use.
 func (c *lightningClient) SendToRoute(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendToRouteClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[7], "/lnrpc.Lightning/SendToRoute", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[8], "/lnrpc.Lightning/SendToRoute", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -907,7 +1034,7 @@ func (c *lightningClient) LookupInvoice(ctx context.Context, in *PaymentHash, op
 }
 
 func (c *lightningClient) SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[8], "/lnrpc.Lightning/SubscribeInvoices", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[9], "/lnrpc.Lightning/SubscribeInvoices", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -974,6 +1101,15 @@ func (c *lightningClient) DeleteAllPayments(ctx context.Context, in *DeleteAllPa
 	return out, nil
 }
 
+func (c *lightningClient) CleanupDuplicatePayments(ctx context.Context, in *CleanupDuplicatePaymentsRequest, opts ...grpc.CallOption) (*CleanupDuplicatePaymentsResponse, error) {
+	out := new(CleanupDuplicatePaymentsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/CleanupDuplicatePayments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) DescribeGraph(ctx context.Context, in *ChannelGraphRequest, opts ...grpc.CallOption) (*ChannelGraph, error) {
 	out := new(ChannelGraph)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/DescribeGraph", in, out, opts...)
@@ -983,6 +1119,15 @@ func (c *lightningClient) DescribeGraph(ctx context.Context, in *ChannelGraphReq
 	return out, nil
 }
 
+func (c *lightningClient) DescribeGraphSlice(ctx context.Context, in *GraphSliceRequest, opts ...grpc.CallOption) (*ChannelGraph, error) {
+	out := new(ChannelGraph)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/DescribeGraphSlice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) GetNodeMetrics(ctx context.Context, in *NodeMetricsRequest, opts ...grpc.CallOption) (*NodeMetricsResponse, error) {
 	out := new(NodeMetricsResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetNodeMetrics", in, out, opts...)
@@ -1028,6 +1173,15 @@ func (c *lightningClient) GetNetworkInfo(ctx context.Context, in *NetworkInfoReq
 	return out, nil
 }
 
+func (c *lightningClient) GetRebroadcastSchedule(ctx context.Context, in *RebroadcastScheduleRequest, opts ...grpc.CallOption) (*RebroadcastScheduleResponse, error) {
+	out := new(RebroadcastScheduleResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetRebroadcastSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) StopDaemon(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error) {
 	out := new(StopResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/StopDaemon", in, out, opts...)
@@ -1038,7 +1192,7 @@ func (c *lightningClient) StopDaemon(ctx context.Context, in *StopRequest, opts
 }
 
 func (c *lightningClient) SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[9], "/lnrpc.Lightning/SubscribeChannelGraph", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[10], "/lnrpc.Lightning/SubscribeChannelGraph", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1078,6 +1232,24 @@ func (c *lightningClient) DebugLevel(ctx context.Context, in *DebugLevelRequest,
 	return out, nil
 }
 
+func (c *lightningClient) ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error) {
+	out := new(ReloadConfigResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ReloadConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) RotateTLSCert(ctx context.Context, in *RotateTLSCertRequest, opts ...grpc.CallOption) (*RotateTLSCertResponse, error) {
+	out := new(RotateTLSCertResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/RotateTLSCert", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) FeeReport(ctx context.Context, in *FeeReportRequest, opts ...grpc.CallOption) (*FeeReportResponse, error) {
 	out := new(FeeReportResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/FeeReport", in, out, opts...)
@@ -1142,7 +1314,7 @@ func (c *lightningClient) RestoreChannelBackups(ctx context.Context, in *Restore
 }
 
 func (c *lightningClient) SubscribeChannelBackups(ctx context.Context, in *ChannelBackupSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelBackupsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[10], "/lnrpc.Lightning/SubscribeChannelBackups", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[11], "/lnrpc.Lightning/SubscribeChannelBackups", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1219,7 +1391,7 @@ func (c *lightningClient) CheckMacaroonPermissions(ctx context.Context, in *Chec
 }
 
 func (c *lightningClient) RegisterRPCMiddleware(ctx context.Context, opts ...grpc.CallOption) (Lightning_RegisterRPCMiddlewareClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[11], "/lnrpc.Lightning/RegisterRPCMiddleware", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[12], "/lnrpc.Lightning/RegisterRPCMiddleware", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1259,7 +1431,7 @@ func (c *lightningClient) SendCustomMessage(ctx context.Context, in *SendCustomM
 }
 
 func (c *lightningClient) SubscribeCustomMessages(ctx context.Context, in *SubscribeCustomMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeCustomMessagesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[12], "/lnrpc.Lightning/SubscribeCustomMessages", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[13], "/lnrpc.Lightning/SubscribeCustomMessages", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1290,6 +1462,38 @@ func (x *lightningSubscribeCustomMessagesClient) Recv() (*CustomMessage, error)
 	return m, nil
 }
 
+func (c *lightningClient) SubscribePeerMessages(ctx context.Context, in *SubscribePeerMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribePeerMessagesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[14], "/lnrpc.Lightning/SubscribePeerMessages", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribePeerMessagesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribePeerMessagesClient interface {
+	Recv() (*PeerMessage, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribePeerMessagesClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribePeerMessagesClient) Recv() (*PeerMessage, error) {
+	m := new(PeerMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *lightningClient) ListAliases(ctx context.Context, in *ListAliasesRequest, opts ...grpc.CallOption) (*ListAliasesResponse, error) {
 	out := new(ListAliasesResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListAliases", in, out, opts...)
@@ -1400,11 +1604,28 @@ type LightningServer interface {
 	// ListChannels returns a description of all the open channels that this node
 	// is a participant in.
 	ListChannels(context.Context, *ListChannelsRequest) (*ListChannelsResponse, error)
+	// lncli: `channelliquidity`
+	// ChannelLiquidity returns a real-time breakdown of the liquidity of the
+	// active channels, including the amounts that can currently be sent and
+	// received, the pending HTLCs in both directions, the commitment fee buffer
+	// and the channel reserves.
+	ChannelLiquidity(context.Context, *ChannelLiquidityRequest) (*ChannelLiquidityResponse, error)
+	// lncli: `channeldust`
+	// ChannelDust reports, for each open channel, the dust limits of both
+	// commitments, the amounts below which HTLCs are trimmed from them and the
+	// value that isn't protected on-chain because it's trimmed to dust.
+	ChannelDust(context.Context, *ChannelDustRequest) (*ChannelDustResponse, error)
 	// SubscribeChannelEvents creates a uni-directional stream from the server to
 	// the client in which any updates relevant to the state of the channels are
 	// sent over. Events include new active channels, inactive channels, and closed
 	// channels.
 	SubscribeChannelEvents(*ChannelEventSubscription, Lightning_SubscribeChannelEventsServer) error
+	// SubscribeChannelArbitratorEvents creates a uni-directional stream from the
+	// server to the client in which the state transitions of our channel
+	// arbitrators are sent over. This allows the progress of a force close to be
+	// followed from the decision to go on-chain until all of the channel's
+	// contracts are fully resolved.
+	SubscribeChannelArbitratorEvents(*ChannelArbitratorEventSubscription, Lightning_SubscribeChannelArbitratorEventsServer) error
 	// lncli: `closedchannels`
 	// ClosedChannels returns a description of all the closed channels that
 	// this node was a participant in.
@@ -1463,6 +1684,13 @@ type LightningServer interface {
 	// never broadcast. Only available for non-externally funded channels in dev
 	// build.
 	AbandonChannel(context.Context, *AbandonChannelRequest) (*AbandonChannelResponse, error)
+	// lncli: `closechannel --force --dry_run`
+	// DryRunForceClose returns what a force close of the channel would broadcast
+	// at its current state, without broadcasting anything or changing the state
+	// of the channel. This allows operators to evaluate the fees, time locks and
+	// HTLC outputs of a force close before deciding to go to chain. The returned
+	// details may become outdated as soon as the channel state advances.
+	DryRunForceClose(context.Context, *DryRunForceCloseRequest) (*DryRunForceCloseResponse, error)
 	// Deprecated: Do not use.
 	// lncli: `sendpayment`
 	// Deprecated, use routerrpc.SendPaymentV2. SendPayment dispatches a
@@ -1530,6 +1758,13 @@ type LightningServer interface {
 	// DeleteAllPayments deletes all outgoing payments from DB. Note that it will
 	// not attempt to delete In-Flight payments, since that would be unsafe.
 	DeleteAllPayments(context.Context, *DeleteAllPaymentsRequest) (*DeleteAllPaymentsResponse, error)
+	// lncli: `cleanupduplicatepayments`
+	// CleanupDuplicatePayments deletes the failed and incomplete duplicate
+	// payments and the dangling payment index entries left behind by old
+	// versions of lnd from the DB. Succeeded duplicate payments are kept. If
+	// dry_run is set, nothing is deleted and only a report of what would be
+	// deleted is returned.
+	CleanupDuplicatePayments(context.Context, *CleanupDuplicatePaymentsRequest) (*CleanupDuplicatePaymentsResponse, error)
 	// lncli: `describegraph`
 	// DescribeGraph returns a description of the latest graph state from the
 	// point of view of the node. The graph information is partitioned into two
@@ -1538,6 +1773,13 @@ type LightningServer interface {
 	// the node directional specific routing policy which includes: the time lock
 	// delta, fee information, etc.
 	DescribeGraph(context.Context, *ChannelGraphRequest) (*ChannelGraph, error)
+	// lncli: `describegraphslice`
+	// DescribeGraphSlice returns the part of the graph that is relevant to a
+	// light client: the nodes within a number of hops of the given nodes, and the
+	// channels between them that have at least the given capacity. This allows a
+	// light client to use our node as a gossip relay, rather than syncing and
+	// validating the full graph itself.
+	DescribeGraphSlice(context.Context, *GraphSliceRequest) (*ChannelGraph, error)
 	// lncli: `getnodemetrics`
 	// GetNodeMetrics returns node metrics calculated from the graph. Currently
 	// the only supported metric is betweenness centrality of individual nodes.
@@ -1568,6 +1810,12 @@ type LightningServer interface {
 	// GetNetworkInfo returns some basic stats about the known channel graph from
 	// the point of view of the node.
 	GetNetworkInfo(context.Context, *NetworkInfoRequest) (*NetworkInfo, error)
+	// lncli: `getrebroadcastschedule`
+	// GetRebroadcastSchedule returns the time at which the channel update of each
+	// of our public channels is next refreshed and rebroadcast to the network.
+	// Channel updates are rebroadcast periodically, even if nothing changed, to
+	// prevent the network from pruning our channels as stale.
+	GetRebroadcastSchedule(context.Context, *RebroadcastScheduleRequest) (*RebroadcastScheduleResponse, error)
 	// lncli: `stop`
 	// StopDaemon will send a shutdown request to the interrupt handler, triggering
 	// a graceful shutdown of the daemon.
@@ -1585,6 +1833,23 @@ type LightningServer interface {
 	// level, or in a granular fashion to specify the logging for a target
 	// sub-system.
 	DebugLevel(context.Context, *DebugLevelRequest) (*DebugLevelResponse, error)
+	// lncli: `reloadconfig`
+	// ReloadConfig re-reads lnd.conf and applies the options that can safely be
+	// changed while lnd is running: the debug level, the default routing policy
+	// of new channels, the throttling of incoming channel updates and the
+	// intervals of the health checks. All other options only take effect after
+	// a restart. Options set on the command line keep taking precedence. The
+	// same reload is triggered by sending SIGHUP to lnd.
+	// If tlsautorotate is set, changes of tlsextraip and tlsextradomain are
+	// applied by rotating the TLS certificate.
+	ReloadConfig(context.Context, *ReloadConfigRequest) (*ReloadConfigResponse, error)
+	// lncli: `rotatetlscert`
+	// RotateTLSCert regenerates the TLS certificate and key of the RPC server
+	// with the currently configured extra IPs and domains, and serves the new
+	// certificate for new connections without a restart. Existing connections
+	// are not affected. The new certificate is written to the configured
+	// tlscertpath, so clients need to reload it before they connect again.
+	RotateTLSCert(context.Context, *RotateTLSCertRequest) (*RotateTLSCertResponse, error)
 	// lncli: `feereport`
 	// FeeReport allows the caller to obtain a report detailing the current fee
 	// schedule enforced by the node globally for each channel.
@@ -1678,6 +1943,13 @@ type LightningServer interface {
 	// SubscribeCustomMessages subscribes to a stream of incoming custom peer
 	// messages.
 	SubscribeCustomMessages(*SubscribeCustomMessagesRequest, Lightning_SubscribeCustomMessagesServer) error
+	// lncli: `subscribepeermessages`
+	// SubscribePeerMessages returns a debug stream of summaries of the wire
+	// messages exchanged with our peers. The stream can be restricted to a set
+	// of peers and message types, and sampled to reduce its volume. This allows
+	// protocol issues to be inspected without restarting lnd with trace logging
+	// enabled.
+	SubscribePeerMessages(*SubscribePeerMessagesRequest, Lightning_SubscribePeerMessagesServer) error
 	// lncli: `listaliases`
 	// ListAliases returns the set of all aliases that have ever existed with
 	// their confirmed SCID (if it exists) and/or the base SCID (in the case of
@@ -1747,9 +2019,18 @@ func (UnimplementedLightningServer) PendingChannels(context.Context, *PendingCha
 func (UnimplementedLightningServer) ListChannels(context.Context, *ListChannelsRequest) (*ListChannelsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListChannels not implemented")
 }
+func (UnimplementedLightningServer) ChannelLiquidity(context.Context, *ChannelLiquidityRequest) (*ChannelLiquidityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChannelLiquidity not implemented")
+}
+func (UnimplementedLightningServer) ChannelDust(context.Context, *ChannelDustRequest) (*ChannelDustResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChannelDust not implemented")
+}
 func (UnimplementedLightningServer) SubscribeChannelEvents(*ChannelEventSubscription, Lightning_SubscribeChannelEventsServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeChannelEvents not implemented")
 }
+func (UnimplementedLightningServer) SubscribeChannelArbitratorEvents(*ChannelArbitratorEventSubscription, Lightning_SubscribeChannelArbitratorEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeChannelArbitratorEvents not implemented")
+}
 func (UnimplementedLightningServer) ClosedChannels(context.Context, *ClosedChannelsRequest) (*ClosedChannelsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ClosedChannels not implemented")
 }
@@ -1774,6 +2055,9 @@ func (UnimplementedLightningServer) CloseChannel(*CloseChannelRequest, Lightning
 func (UnimplementedLightningServer) AbandonChannel(context.Context, *AbandonChannelRequest) (*AbandonChannelResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AbandonChannel not implemented")
 }
+func (UnimplementedLightningServer) DryRunForceClose(context.Context, *DryRunForceCloseRequest) (*DryRunForceCloseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DryRunForceClose not implemented")
+}
 func (UnimplementedLightningServer) SendPayment(Lightning_SendPaymentServer) error {
 	return status.Errorf(codes.Unimplemented, "method SendPayment not implemented")
 }
@@ -1810,9 +2094,15 @@ func (UnimplementedLightningServer) DeletePayment(context.Context, *DeletePaymen
 func (UnimplementedLightningServer) DeleteAllPayments(context.Context, *DeleteAllPaymentsRequest) (*DeleteAllPaymentsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteAllPayments not implemented")
 }
+func (UnimplementedLightningServer) CleanupDuplicatePayments(context.Context, *CleanupDuplicatePaymentsRequest) (*CleanupDuplicatePaymentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CleanupDuplicatePayments not implemented")
+}
 func (UnimplementedLightningServer) DescribeGraph(context.Context, *ChannelGraphRequest) (*ChannelGraph, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DescribeGraph not implemented")
 }
+func (UnimplementedLightningServer) DescribeGraphSlice(context.Context, *GraphSliceRequest) (*ChannelGraph, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DescribeGraphSlice not implemented")
+}
 func (UnimplementedLightningServer) GetNodeMetrics(context.Context, *NodeMetricsRequest) (*NodeMetricsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetNodeMetrics not implemented")
 }
@@ -1828,6 +2118,9 @@ func (UnimplementedLightningServer) QueryRoutes(context.Context, *QueryRoutesReq
 func (UnimplementedLightningServer) GetNetworkInfo(context.Context, *NetworkInfoRequest) (*NetworkInfo, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetNetworkInfo not implemented")
 }
+func (UnimplementedLightningServer) GetRebroadcastSchedule(context.Context, *RebroadcastScheduleRequest) (*RebroadcastScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRebroadcastSchedule not implemented")
+}
 func (UnimplementedLightningServer) StopDaemon(context.Context, *StopRequest) (*StopResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StopDaemon not implemented")
 }
@@ -1837,6 +2130,12 @@ func (UnimplementedLightningServer) SubscribeChannelGraph(*GraphTopologySubscrip
 func (UnimplementedLightningServer) DebugLevel(context.Context, *DebugLevelRequest) (*DebugLevelResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DebugLevel not implemented")
 }
+func (UnimplementedLightningServer) ReloadConfig(context.Context, *ReloadConfigRequest) (*ReloadConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadConfig not implemented")
+}
+func (UnimplementedLightningServer) RotateTLSCert(context.Context, *RotateTLSCertRequest) (*RotateTLSCertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateTLSCert not implemented")
+}
 func (UnimplementedLightningServer) FeeReport(context.Context, *FeeReportRequest) (*FeeReportResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FeeReport not implemented")
 }
@@ -1885,6 +2184,9 @@ func (UnimplementedLightningServer) SendCustomMessage(context.Context, *SendCust
 func (UnimplementedLightningServer) SubscribeCustomMessages(*SubscribeCustomMessagesRequest, Lightning_SubscribeCustomMessagesServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeCustomMessages not implemented")
 }
+func (UnimplementedLightningServer) SubscribePeerMessages(*SubscribePeerMessagesRequest, Lightning_SubscribePeerMessagesServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribePeerMessages not implemented")
+}
 func (UnimplementedLightningServer) ListAliases(context.Context, *ListAliasesRequest) (*ListAliasesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListAliases not implemented")
 }
@@ -2249,6 +2551,42 @@ func _Lightning_ListChannels_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_ChannelLiquidity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelLiquidityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ChannelLiquidity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ChannelLiquidity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ChannelLiquidity(ctx, req.(*ChannelLiquidityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ChannelDust_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelDustRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ChannelDust(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ChannelDust",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ChannelDust(ctx, req.(*ChannelDustRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_SubscribeChannelEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(ChannelEventSubscription)
 	if err := stream.RecvMsg(m); err != nil {
@@ -2270,6 +2608,27 @@ func (x *lightningSubscribeChannelEventsServer) Send(m *ChannelEventUpdate) erro
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Lightning_SubscribeChannelArbitratorEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ChannelArbitratorEventSubscription)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeChannelArbitratorEvents(m, &lightningSubscribeChannelArbitratorEventsServer{stream})
+}
+
+type Lightning_SubscribeChannelArbitratorEventsServer interface {
+	Send(*ChannelArbitratorEvent) error
+	grpc.ServerStream
+}
+
+type lightningSubscribeChannelArbitratorEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeChannelArbitratorEventsServer) Send(m *ChannelArbitratorEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Lightning_ClosedChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ClosedChannelsRequest)
 	if err := dec(in); err != nil {
@@ -2428,6 +2787,24 @@ func _Lightning_AbandonChannel_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_DryRunForceClose_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DryRunForceCloseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).DryRunForceClose(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/DryRunForceClose",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).DryRunForceClose(ctx, req.(*DryRunForceCloseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_SendPayment_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(LightningServer).SendPayment(&lightningSendPaymentServer{stream})
 }
@@ -2663,6 +3040,24 @@ func _Lightning_DeleteAllPayments_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_CleanupDuplicatePayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CleanupDuplicatePaymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).CleanupDuplicatePayments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/CleanupDuplicatePayments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).CleanupDuplicatePayments(ctx, req.(*CleanupDuplicatePaymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_DescribeGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ChannelGraphRequest)
 	if err := dec(in); err != nil {
@@ -2681,6 +3076,24 @@ func _Lightning_DescribeGraph_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_DescribeGraphSlice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GraphSliceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).DescribeGraphSlice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/DescribeGraphSlice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).DescribeGraphSlice(ctx, req.(*GraphSliceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_GetNodeMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(NodeMetricsRequest)
 	if err := dec(in); err != nil {
@@ -2771,6 +3184,24 @@ func _Lightning_GetNetworkInfo_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_GetRebroadcastSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RebroadcastScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetRebroadcastSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetRebroadcastSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetRebroadcastSchedule(ctx, req.(*RebroadcastScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_StopDaemon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(StopRequest)
 	if err := dec(in); err != nil {
@@ -2828,6 +3259,42 @@ func _Lightning_DebugLevel_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_ReloadConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ReloadConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ReloadConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ReloadConfig(ctx, req.(*ReloadConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_RotateTLSCert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateTLSCertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).RotateTLSCert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/RotateTLSCert",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).RotateTLSCert(ctx, req.(*RotateTLSCertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_FeeReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FeeReportRequest)
 	if err := dec(in); err != nil {
@@ -3130,6 +3597,27 @@ func (x *lightningSubscribeCustomMessagesServer) Send(m *CustomMessage) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Lightning_SubscribePeerMessages_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribePeerMessagesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribePeerMessages(m, &lightningSubscribePeerMessagesServer{stream})
+}
+
+type Lightning_SubscribePeerMessagesServer interface {
+	Send(*PeerMessage) error
+	grpc.ServerStream
+}
+
+type lightningSubscribePeerMessagesServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribePeerMessagesServer) Send(m *PeerMessage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Lightning_ListAliases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListAliasesRequest)
 	if err := dec(in); err != nil {
@@ -3223,6 +3711,14 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListChannels",
 			Handler:    _Lightning_ListChannels_Handler,
 		},
+		{
+			MethodName: "ChannelLiquidity",
+			Handler:    _Lightning_ChannelLiquidity_Handler,
+		},
+		{
+			MethodName: "ChannelDust",
+			Handler:    _Lightning_ChannelDust_Handler,
+		},
 		{
 			MethodName: "ClosedChannels",
 			Handler:    _Lightning_ClosedChannels_Handler,
@@ -3243,6 +3739,10 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AbandonChannel",
 			Handler:    _Lightning_AbandonChannel_Handler,
 		},
+		{
+			MethodName: "DryRunForceClose",
+			Handler:    _Lightning_DryRunForceClose_Handler,
+		},
 		{
 			MethodName: "SendPaymentSync",
 			Handler:    _Lightning_SendPaymentSync_Handler,
@@ -3279,10 +3779,18 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteAllPayments",
 			Handler:    _Lightning_DeleteAllPayments_Handler,
 		},
+		{
+			MethodName: "CleanupDuplicatePayments",
+			Handler:    _Lightning_CleanupDuplicatePayments_Handler,
+		},
 		{
 			MethodName: "DescribeGraph",
 			Handler:    _Lightning_DescribeGraph_Handler,
 		},
+		{
+			MethodName: "DescribeGraphSlice",
+			Handler:    _Lightning_DescribeGraphSlice_Handler,
+		},
 		{
 			MethodName: "GetNodeMetrics",
 			Handler:    _Lightning_GetNodeMetrics_Handler,
@@ -3303,6 +3811,10 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetNetworkInfo",
 			Handler:    _Lightning_GetNetworkInfo_Handler,
 		},
+		{
+			MethodName: "GetRebroadcastSchedule",
+			Handler:    _Lightning_GetRebroadcastSchedule_Handler,
+		},
 		{
 			MethodName: "StopDaemon",
 			Handler:    _Lightning_StopDaemon_Handler,
@@ -3311,6 +3823,14 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DebugLevel",
 			Handler:    _Lightning_DebugLevel_Handler,
 		},
+		{
+			MethodName: "ReloadConfig",
+			Handler:    _Lightning_ReloadConfig_Handler,
+		},
+		{
+			MethodName: "RotateTLSCert",
+			Handler:    _Lightning_RotateTLSCert_Handler,
+		},
 		{
 			MethodName: "FeeReport",
 			Handler:    _Lightning_FeeReport_Handler,
@@ -3384,6 +3904,11 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Lightning_SubscribeChannelEvents_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeChannelArbitratorEvents",
+			Handler:       _Lightning_SubscribeChannelArbitratorEvents_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "OpenChannel",
 			Handler:       _Lightning_OpenChannel_Handler,
@@ -3438,6 +3963,11 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Lightning_SubscribeCustomMessages_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribePeerMessages",
+			Handler:       _Lightning_SubscribePeerMessages_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "lightning.proto",
 }
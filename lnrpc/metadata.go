@@ -16,6 +16,25 @@ var (
 		regexp.MustCompile("^/v1/middleware$"),
 	}
 
+	// LndResumableStreamURIs is a list of the lnd server-streaming RPCs
+	// that can be resumed through the WebsocketProxy after a client
+	// reconnects, if resumable streams are enabled.
+	LndResumableStreamURIs = []ResumableStreamURI{{
+		Pattern:    regexp.MustCompile("^/v1/invoices/subscribe$"),
+		FullMethod: "/lnrpc.Lightning/SubscribeInvoices",
+	}, {
+		Pattern: regexp.MustCompile(
+			"^/v2/invoices/subscribe/[^/]+$",
+		),
+		FullMethod: "/invoicesrpc.Invoices/SubscribeSingleInvoice",
+	}, {
+		Pattern:    regexp.MustCompile("^/v2/router/send$"),
+		FullMethod: "/routerrpc.Router/SendPaymentV2",
+	}, {
+		Pattern:    regexp.MustCompile("^/v2/router/track/[^/]+$"),
+		FullMethod: "/routerrpc.Router/TrackPaymentV2",
+	}}
+
 	// MaxGrpcMsgSize is used when we configure both server and clients to
 	// allow sending/receiving at most 200 MiB GRPC messages.
 	MaxGrpcMsgSize = 200 * 1024 * 1024
@@ -5,6 +5,7 @@ package neutrinorpc
 
 import (
 	"github.com/lightninglabs/neutrino"
+	"github.com/lightninglabs/neutrino/banman"
 )
 
 // Config is the primary configuration struct for the neutrino RPC server. It
@@ -15,4 +16,8 @@ import (
 type Config struct {
 	// ChainService is required to handle neutrino chain service requests.
 	NeutrinoCS *neutrino.ChainService
+
+	// BanStore is the store holding the bans of the peers of the chain
+	// service, which is used to look up the details of a ban.
+	BanStore banman.Store
 }
@@ -38,6 +38,17 @@ type NeutrinoKitClient interface {
 	GetCFilter(ctx context.Context, in *GetCFilterRequest, opts ...grpc.CallOption) (*GetCFilterResponse, error)
 	// GetBlockHash returns the header hash of a block at a given height.
 	GetBlockHash(ctx context.Context, in *GetBlockHashRequest, opts ...grpc.CallOption) (*GetBlockHashResponse, error)
+	// GetCFilters returns the compact filters, filter headers and block headers
+	// of the blocks within a range of heights.
+	GetCFilters(ctx context.Context, in *GetCFiltersRequest, opts ...grpc.CallOption) (*GetCFiltersResponse, error)
+	// MatchCFilters returns the blocks within a range of heights whose compact
+	// filters match any of the given output scripts, along with the scripts
+	// that matched. As compact filters are probabilistic, a match doesn't
+	// guarantee that a block actually contains a script.
+	MatchCFilters(ctx context.Context, in *MatchCFiltersRequest, opts ...grpc.CallOption) (*MatchCFiltersResponse, error)
+	// GetBanStatus returns whether a peer is banned, and if so, why and until
+	// when.
+	GetBanStatus(ctx context.Context, in *GetBanStatusRequest, opts ...grpc.CallOption) (*GetBanStatusResponse, error)
 }
 
 type neutrinoKitClient struct {
@@ -120,6 +131,33 @@ func (c *neutrinoKitClient) GetBlockHash(ctx context.Context, in *GetBlockHashRe
 	return out, nil
 }
 
+func (c *neutrinoKitClient) GetCFilters(ctx context.Context, in *GetCFiltersRequest, opts ...grpc.CallOption) (*GetCFiltersResponse, error) {
+	out := new(GetCFiltersResponse)
+	err := c.cc.Invoke(ctx, "/neutrinorpc.NeutrinoKit/GetCFilters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *neutrinoKitClient) MatchCFilters(ctx context.Context, in *MatchCFiltersRequest, opts ...grpc.CallOption) (*MatchCFiltersResponse, error) {
+	out := new(MatchCFiltersResponse)
+	err := c.cc.Invoke(ctx, "/neutrinorpc.NeutrinoKit/MatchCFilters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *neutrinoKitClient) GetBanStatus(ctx context.Context, in *GetBanStatusRequest, opts ...grpc.CallOption) (*GetBanStatusResponse, error) {
+	out := new(GetBanStatusResponse)
+	err := c.cc.Invoke(ctx, "/neutrinorpc.NeutrinoKit/GetBanStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NeutrinoKitServer is the server API for NeutrinoKit service.
 // All implementations must embed UnimplementedNeutrinoKitServer
 // for forward compatibility
@@ -144,6 +182,17 @@ type NeutrinoKitServer interface {
 	GetCFilter(context.Context, *GetCFilterRequest) (*GetCFilterResponse, error)
 	// GetBlockHash returns the header hash of a block at a given height.
 	GetBlockHash(context.Context, *GetBlockHashRequest) (*GetBlockHashResponse, error)
+	// GetCFilters returns the compact filters, filter headers and block headers
+	// of the blocks within a range of heights.
+	GetCFilters(context.Context, *GetCFiltersRequest) (*GetCFiltersResponse, error)
+	// MatchCFilters returns the blocks within a range of heights whose compact
+	// filters match any of the given output scripts, along with the scripts
+	// that matched. As compact filters are probabilistic, a match doesn't
+	// guarantee that a block actually contains a script.
+	MatchCFilters(context.Context, *MatchCFiltersRequest) (*MatchCFiltersResponse, error)
+	// GetBanStatus returns whether a peer is banned, and if so, why and until
+	// when.
+	GetBanStatus(context.Context, *GetBanStatusRequest) (*GetBanStatusResponse, error)
 	mustEmbedUnimplementedNeutrinoKitServer()
 }
 
@@ -175,6 +224,15 @@ func (UnimplementedNeutrinoKitServer) GetCFilter(context.Context, *GetCFilterReq
 func (UnimplementedNeutrinoKitServer) GetBlockHash(context.Context, *GetBlockHashRequest) (*GetBlockHashResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBlockHash not implemented")
 }
+func (UnimplementedNeutrinoKitServer) GetCFilters(context.Context, *GetCFiltersRequest) (*GetCFiltersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCFilters not implemented")
+}
+func (UnimplementedNeutrinoKitServer) MatchCFilters(context.Context, *MatchCFiltersRequest) (*MatchCFiltersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MatchCFilters not implemented")
+}
+func (UnimplementedNeutrinoKitServer) GetBanStatus(context.Context, *GetBanStatusRequest) (*GetBanStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBanStatus not implemented")
+}
 func (UnimplementedNeutrinoKitServer) mustEmbedUnimplementedNeutrinoKitServer() {}
 
 // UnsafeNeutrinoKitServer may be embedded to opt out of forward compatibility for this service.
@@ -332,6 +390,60 @@ func _NeutrinoKit_GetBlockHash_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NeutrinoKit_GetCFilters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCFiltersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NeutrinoKitServer).GetCFilters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/neutrinorpc.NeutrinoKit/GetCFilters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NeutrinoKitServer).GetCFilters(ctx, req.(*GetCFiltersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NeutrinoKit_MatchCFilters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MatchCFiltersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NeutrinoKitServer).MatchCFilters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/neutrinorpc.NeutrinoKit/MatchCFilters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NeutrinoKitServer).MatchCFilters(ctx, req.(*MatchCFiltersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NeutrinoKit_GetBanStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBanStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NeutrinoKitServer).GetBanStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/neutrinorpc.NeutrinoKit/GetBanStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NeutrinoKitServer).GetBanStatus(ctx, req.(*GetBanStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // NeutrinoKit_ServiceDesc is the grpc.ServiceDesc for NeutrinoKit service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -371,6 +483,18 @@ var NeutrinoKit_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetBlockHash",
 			Handler:    _NeutrinoKit_GetBlockHash_Handler,
 		},
+		{
+			MethodName: "GetCFilters",
+			Handler:    _NeutrinoKit_GetCFilters_Handler,
+		},
+		{
+			MethodName: "MatchCFilters",
+			Handler:    _NeutrinoKit_MatchCFilters_Handler,
+		},
+		{
+			MethodName: "GetBanStatus",
+			Handler:    _NeutrinoKit_GetBanStatus_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "neutrinorpc/neutrino.proto",
@@ -4,14 +4,18 @@
 package neutrinorpc
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 
 	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil/gcs"
+	"github.com/btcsuite/btcd/btcutil/gcs/builder"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/lightninglabs/neutrino/banman"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"google.golang.org/grpc"
 	"gopkg.in/macaroon-bakery.v2/bakery"
@@ -23,6 +27,10 @@ const (
 	// SubServerConfigDispatcher instance recognize it as the name of our
 	// RPC service.
 	subServerName = "NeutrinoKitRPC"
+
+	// maxCFilterRange is the maximum number of compact filters that can be
+	// queried or matched with a single call.
+	maxCFilterRange = 1000
 )
 
 var (
@@ -60,6 +68,18 @@ var (
 			Entity: "onchain",
 			Action: "read",
 		}},
+		"/neutrinorpc.NeutrinoKit/GetCFilters": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/neutrinorpc.NeutrinoKit/MatchCFilters": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/neutrinorpc.NeutrinoKit/GetBanStatus": {{
+			Entity: "info",
+			Action: "read",
+		}},
 	}
 
 	// ErrNeutrinoNotActive is an error returned when there is no running
@@ -441,3 +461,239 @@ func (s *Server) GetBlockHash(ctx context.Context,
 
 	return &GetBlockHashResponse{Hash: hash.String()}, nil
 }
+
+// GetCFilters returns the compact filters, filter headers and block headers of
+// the blocks within a range of heights.
+//
+// NOTE: Part of the NeutrinoKitServer interface.
+func (s *Server) GetCFilters(ctx context.Context,
+	in *GetCFiltersRequest) (*GetCFiltersResponse, error) {
+
+	if s.cfg.NeutrinoCS == nil {
+		return nil, ErrNeutrinoNotActive
+	}
+
+	err := s.checkCFilterRange(in.StartHeight, in.EndHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := make([]*CFilter, 0, in.EndHeight-in.StartHeight+1)
+	for height := in.StartHeight; height <= in.EndHeight; height++ {
+		// Fetching a filter may require a network round trip, so we
+		// stop as soon as the caller is no longer interested.
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		header, filter, err := s.fetchCFilter(height)
+		if err != nil {
+			return nil, err
+		}
+
+		var headerBuf bytes.Buffer
+		if err := header.Serialize(&headerBuf); err != nil {
+			return nil, err
+		}
+
+		filterBytes, err := filter.NBytes()
+		if err != nil {
+			return nil, err
+		}
+
+		filterHeader, err := s.cfg.NeutrinoCS.RegFilterHeaders.
+			FetchHeaderByHeight(uint32(height))
+		if err != nil {
+			return nil, fmt.Errorf("could not get filter header "+
+				"at height %d: %v", height, err)
+		}
+
+		filters = append(filters, &CFilter{
+			Height:       height,
+			BlockHash:    header.BlockHash().String(),
+			BlockHeader:  headerBuf.Bytes(),
+			Filter:       filterBytes,
+			FilterHeader: filterHeader.String(),
+		})
+	}
+
+	return &GetCFiltersResponse{Filters: filters}, nil
+}
+
+// MatchCFilters returns the blocks within a range of heights whose compact
+// filters match any of the given output scripts, along with the scripts that
+// matched.
+//
+// NOTE: Part of the NeutrinoKitServer interface.
+func (s *Server) MatchCFilters(ctx context.Context,
+	in *MatchCFiltersRequest) (*MatchCFiltersResponse, error) {
+
+	if s.cfg.NeutrinoCS == nil {
+		return nil, ErrNeutrinoNotActive
+	}
+
+	if len(in.Scripts) == 0 {
+		return nil, errors.New("at least one script must be given")
+	}
+
+	err := s.checkCFilterRange(in.StartHeight, in.EndHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*CFilterMatch
+	for height := in.StartHeight; height <= in.EndHeight; height++ {
+		// Fetching a filter may require a network round trip, so we
+		// stop as soon as the caller is no longer interested.
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		header, filter, err := s.fetchCFilter(height)
+		if err != nil {
+			return nil, err
+		}
+
+		blockHash := header.BlockHash()
+		scripts, err := matchingScripts(filter, blockHash, in.Scripts)
+		if err != nil {
+			return nil, err
+		}
+		if len(scripts) == 0 {
+			continue
+		}
+
+		matches = append(matches, &CFilterMatch{
+			Height:    height,
+			BlockHash: blockHash.String(),
+			Scripts:   scripts,
+		})
+	}
+
+	return &MatchCFiltersResponse{Matches: matches}, nil
+}
+
+// GetBanStatus returns whether a peer is banned, and if so, why and until
+// when.
+//
+// NOTE: Part of the NeutrinoKitServer interface.
+func (s *Server) GetBanStatus(ctx context.Context,
+	in *GetBanStatusRequest) (*GetBanStatusResponse, error) {
+
+	if s.cfg.NeutrinoCS == nil || s.cfg.BanStore == nil {
+		return nil, ErrNeutrinoNotActive
+	}
+
+	ipNet, err := banman.ParseIPNet(in.PeerAddrs, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid peer address %v: %v",
+			in.PeerAddrs, err)
+	}
+
+	status, err := s.cfg.BanStore.Status(ipNet)
+	if err != nil {
+		return nil, fmt.Errorf("could not get ban status: %v", err)
+	}
+
+	if !status.Banned {
+		return &GetBanStatusResponse{}, nil
+	}
+
+	return &GetBanStatusResponse{
+		Banned:     true,
+		Reason:     status.Reason.String(),
+		Expiration: status.Expiration.Unix(),
+	}, nil
+}
+
+// checkCFilterRange makes sure that the given range of heights is valid, known
+// to the chain service and doesn't exceed the maximum number of filters that
+// can be queried at once.
+func (s *Server) checkCFilterRange(startHeight, endHeight int32) error {
+	if err := validateCFilterRange(startHeight, endHeight); err != nil {
+		return err
+	}
+
+	bestBlock, err := s.cfg.NeutrinoCS.BestBlock()
+	if err != nil {
+		return fmt.Errorf("could not get best block: %v", err)
+	}
+
+	if endHeight > bestBlock.Height {
+		return fmt.Errorf("end height %d is above best block height %d",
+			endHeight, bestBlock.Height)
+	}
+
+	return nil
+}
+
+// fetchCFilter returns the block header and the regular compact filter of the
+// block at the given height. If the filter is missing from the database, it
+// is requested from the network.
+func (s *Server) fetchCFilter(height int32) (*wire.BlockHeader, *gcs.Filter,
+	error) {
+
+	header, err := s.cfg.NeutrinoCS.BlockHeaders.FetchHeaderByHeight(
+		uint32(height),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get block header at "+
+			"height %d: %v", height, err)
+	}
+
+	filter, err := s.cfg.NeutrinoCS.GetCFilter(
+		header.BlockHash(), wire.GCSFilterRegular,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get filter at height "+
+			"%d: %v", height, err)
+	}
+
+	return header, filter, nil
+}
+
+// validateCFilterRange makes sure that the given range of heights is well
+// formed and doesn't exceed the maximum number of filters that can be queried
+// at once.
+func validateCFilterRange(startHeight, endHeight int32) error {
+	if startHeight < 0 || endHeight < startHeight {
+		return fmt.Errorf("invalid height range [%d, %d]", startHeight,
+			endHeight)
+	}
+
+	if endHeight-startHeight >= maxCFilterRange {
+		return fmt.Errorf("at most %d filters can be queried at once",
+			maxCFilterRange)
+	}
+
+	return nil
+}
+
+// matchingScripts returns the scripts that match the compact filter of the
+// block with the given hash.
+func matchingScripts(filter *gcs.Filter, blockHash chainhash.Hash,
+	scripts [][]byte) ([][]byte, error) {
+
+	key := builder.DeriveKey(&blockHash)
+
+	// Most filters won't match any of the scripts, so we first check all
+	// of them at once before finding out which of them matched.
+	matched, err := filter.MatchAny(key, scripts)
+	if err != nil || !matched {
+		return nil, err
+	}
+
+	var matches [][]byte
+	for _, script := range scripts {
+		matched, err := filter.Match(key, script)
+		if err != nil {
+			return nil, err
+		}
+
+		if matched {
+			matches = append(matches, script)
+		}
+	}
+
+	return matches, nil
+}
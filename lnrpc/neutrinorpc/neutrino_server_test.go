@@ -0,0 +1,147 @@
+//go:build neutrinorpc
+// +build neutrinorpc
+
+package neutrinorpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil/gcs"
+	"github.com/btcsuite/btcd/btcutil/gcs/builder"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightninglabs/neutrino"
+	"github.com/lightninglabs/neutrino/banman"
+	"github.com/stretchr/testify/require"
+)
+
+// mockBanStore is a ban store that reports the same status for every peer.
+type mockBanStore struct {
+	banman.Store
+
+	status banman.Status
+}
+
+// Status returns the ban status for a given IP network.
+func (m *mockBanStore) Status(*net.IPNet) (banman.Status, error) {
+	return m.status, nil
+}
+
+// TestValidateCFilterRange asserts that malformed or too large ranges of
+// filter heights are rejected.
+func TestValidateCFilterRange(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		startHeight int32
+		endHeight   int32
+		valid       bool
+	}{{
+		name:        "single filter",
+		startHeight: 10,
+		endHeight:   10,
+		valid:       true,
+	}, {
+		name:        "maximum range",
+		startHeight: 0,
+		endHeight:   maxCFilterRange - 1,
+		valid:       true,
+	}, {
+		name:        "range too large",
+		startHeight: 0,
+		endHeight:   maxCFilterRange,
+	}, {
+		name:        "negative start height",
+		startHeight: -1,
+		endHeight:   10,
+	}, {
+		name:        "end before start",
+		startHeight: 10,
+		endHeight:   9,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateCFilterRange(
+				tc.startHeight, tc.endHeight,
+			)
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestMatchingScripts asserts that only the scripts that are part of a filter
+// are returned as matches.
+func TestMatchingScripts(t *testing.T) {
+	t.Parallel()
+
+	var blockHash chainhash.Hash
+	blockHash[0] = 1
+
+	scriptA := []byte{0x00, 0x14, 0x01}
+	scriptB := []byte{0x00, 0x14, 0x02}
+	scriptC := []byte{0x00, 0x14, 0x03}
+
+	filter, err := gcs.BuildGCSFilter(
+		builder.DefaultP, builder.DefaultM,
+		builder.DeriveKey(&blockHash), [][]byte{scriptA, scriptB},
+	)
+	require.NoError(t, err)
+
+	matches, err := matchingScripts(
+		filter, blockHash, [][]byte{scriptA, scriptC},
+	)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{scriptA}, matches)
+
+	matches, err = matchingScripts(filter, blockHash, [][]byte{scriptC})
+	require.NoError(t, err)
+	require.Empty(t, matches)
+}
+
+// TestGetBanStatus asserts that the status reported by the ban store is
+// returned to the caller.
+func TestGetBanStatus(t *testing.T) {
+	t.Parallel()
+
+	banStore := &mockBanStore{}
+	s := &Server{cfg: &Config{
+		NeutrinoCS: &neutrino.ChainService{},
+		BanStore:   banStore,
+	}}
+
+	req := &GetBanStatusRequest{PeerAddrs: "127.0.0.1:8333"}
+
+	resp, err := s.GetBanStatus(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, resp.Banned)
+
+	expiration := time.Now().Add(time.Hour)
+	banStore.status = banman.Status{
+		Banned:     true,
+		Reason:     banman.InvalidFilterHeader,
+		Expiration: expiration,
+	}
+
+	resp, err = s.GetBanStatus(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, resp.Banned)
+	require.Equal(t, banman.InvalidFilterHeader.String(), resp.Reason)
+	require.Equal(t, expiration.Unix(), resp.Expiration)
+
+	_, err = s.GetBanStatus(
+		context.Background(), &GetBanStatusRequest{PeerAddrs: "foo"},
+	)
+	require.Error(t, err)
+}
@@ -6,8 +6,11 @@ package peersrpc
 import (
 	"net"
 
+	"github.com/lightningnetwork/lnd/discovery"
+	"github.com/lightningnetwork/lnd/feature"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/routing/route"
 )
 
 // Config is the primary configuration struct for the peers RPC subserver.
@@ -26,4 +29,25 @@ type Config struct {
 	// UpdateNodeAnnouncement updates our node announcement applying the
 	// given NodeAnnModifiers and broadcasts the new version to the network.
 	UpdateNodeAnnouncement func(...netann.NodeAnnModifier) error
+
+	// GetFeatures returns the feature vector we currently advertise for
+	// the given feature set.
+	GetFeatures func(set feature.Set) *lnwire.FeatureVector
+
+	// UpdateFeatureBits enables or disables the given optional feature
+	// bits at runtime and broadcasts a new node announcement. If reconnect
+	// is true, all persistent peers are disconnected so that the new
+	// feature bits are negotiated with them right away.
+	UpdateFeatureBits func(updates map[lnwire.FeatureBit]bool,
+		reconnect bool) error
+
+	// SetGossipFilter overrides the gossip filter we send to the given
+	// peer, regardless of the sync type of its gossip syncer. A nil filter
+	// removes the override.
+	SetGossipFilter func(peer route.Vertex,
+		filter *discovery.GossipFilter) error
+
+	// GossipFilters returns the gossip filters that currently override
+	// the ones chosen for our peers by the gossip sync manager.
+	GossipFilters func() map[route.Vertex]*discovery.GossipFilter
 }
@@ -22,6 +22,25 @@ type PeersClient interface {
 	// UpdateNodeAnnouncement allows the caller to update the node parameters
 	// and broadcasts a new version of the node announcement to its peers.
 	UpdateNodeAnnouncement(ctx context.Context, in *NodeAnnouncementUpdateRequest, opts ...grpc.CallOption) (*NodeAnnouncementUpdateResponse, error)
+	// lncli: peers updatefeatures
+	// UpdateFeatures enables or disables optional feature bits that can be
+	// toggled while lnd is running, such as option_shutdown_anysegwit. The new
+	// feature set is advertised in a fresh node announcement and is sent to each
+	// peer in our Init message the next time it connects.
+	UpdateFeatures(ctx context.Context, in *UpdateFeaturesRequest, opts ...grpc.CallOption) (*UpdateFeaturesResponse, error)
+	// lncli: peers updategossipfilter
+	// UpdateGossipFilter overrides the gossip timestamp filter we send to a peer,
+	// regardless of whether we chose it as an active or passive gossip syncer.
+	// This allows gossip from selected peers to be muted or limited to reduce
+	// bandwidth, for example on metered connections. The filter is sent right
+	// away if the peer is connected, and every time it reconnects until the
+	// override is removed by setting the DEFAULT_GOSSIP mode. Overrides are not
+	// persisted across restarts.
+	UpdateGossipFilter(ctx context.Context, in *UpdateGossipFilterRequest, opts ...grpc.CallOption) (*UpdateGossipFilterResponse, error)
+	// lncli: peers listgossipfilters
+	// ListGossipFilters returns the gossip filters that currently override the
+	// ones chosen for our peers by the gossip sync manager.
+	ListGossipFilters(ctx context.Context, in *ListGossipFiltersRequest, opts ...grpc.CallOption) (*ListGossipFiltersResponse, error)
 }
 
 type peersClient struct {
@@ -41,6 +60,33 @@ func (c *peersClient) UpdateNodeAnnouncement(ctx context.Context, in *NodeAnnoun
 	return out, nil
 }
 
+func (c *peersClient) UpdateFeatures(ctx context.Context, in *UpdateFeaturesRequest, opts ...grpc.CallOption) (*UpdateFeaturesResponse, error) {
+	out := new(UpdateFeaturesResponse)
+	err := c.cc.Invoke(ctx, "/peersrpc.Peers/UpdateFeatures", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersClient) UpdateGossipFilter(ctx context.Context, in *UpdateGossipFilterRequest, opts ...grpc.CallOption) (*UpdateGossipFilterResponse, error) {
+	out := new(UpdateGossipFilterResponse)
+	err := c.cc.Invoke(ctx, "/peersrpc.Peers/UpdateGossipFilter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersClient) ListGossipFilters(ctx context.Context, in *ListGossipFiltersRequest, opts ...grpc.CallOption) (*ListGossipFiltersResponse, error) {
+	out := new(ListGossipFiltersResponse)
+	err := c.cc.Invoke(ctx, "/peersrpc.Peers/ListGossipFilters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PeersServer is the server API for Peers service.
 // All implementations must embed UnimplementedPeersServer
 // for forward compatibility
@@ -49,6 +95,25 @@ type PeersServer interface {
 	// UpdateNodeAnnouncement allows the caller to update the node parameters
 	// and broadcasts a new version of the node announcement to its peers.
 	UpdateNodeAnnouncement(context.Context, *NodeAnnouncementUpdateRequest) (*NodeAnnouncementUpdateResponse, error)
+	// lncli: peers updatefeatures
+	// UpdateFeatures enables or disables optional feature bits that can be
+	// toggled while lnd is running, such as option_shutdown_anysegwit. The new
+	// feature set is advertised in a fresh node announcement and is sent to each
+	// peer in our Init message the next time it connects.
+	UpdateFeatures(context.Context, *UpdateFeaturesRequest) (*UpdateFeaturesResponse, error)
+	// lncli: peers updategossipfilter
+	// UpdateGossipFilter overrides the gossip timestamp filter we send to a peer,
+	// regardless of whether we chose it as an active or passive gossip syncer.
+	// This allows gossip from selected peers to be muted or limited to reduce
+	// bandwidth, for example on metered connections. The filter is sent right
+	// away if the peer is connected, and every time it reconnects until the
+	// override is removed by setting the DEFAULT_GOSSIP mode. Overrides are not
+	// persisted across restarts.
+	UpdateGossipFilter(context.Context, *UpdateGossipFilterRequest) (*UpdateGossipFilterResponse, error)
+	// lncli: peers listgossipfilters
+	// ListGossipFilters returns the gossip filters that currently override the
+	// ones chosen for our peers by the gossip sync manager.
+	ListGossipFilters(context.Context, *ListGossipFiltersRequest) (*ListGossipFiltersResponse, error)
 	mustEmbedUnimplementedPeersServer()
 }
 
@@ -59,6 +124,15 @@ type UnimplementedPeersServer struct {
 func (UnimplementedPeersServer) UpdateNodeAnnouncement(context.Context, *NodeAnnouncementUpdateRequest) (*NodeAnnouncementUpdateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateNodeAnnouncement not implemented")
 }
+func (UnimplementedPeersServer) UpdateFeatures(context.Context, *UpdateFeaturesRequest) (*UpdateFeaturesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFeatures not implemented")
+}
+func (UnimplementedPeersServer) UpdateGossipFilter(context.Context, *UpdateGossipFilterRequest) (*UpdateGossipFilterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateGossipFilter not implemented")
+}
+func (UnimplementedPeersServer) ListGossipFilters(context.Context, *ListGossipFiltersRequest) (*ListGossipFiltersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGossipFilters not implemented")
+}
 func (UnimplementedPeersServer) mustEmbedUnimplementedPeersServer() {}
 
 // UnsafePeersServer may be embedded to opt out of forward compatibility for this service.
@@ -90,6 +164,60 @@ func _Peers_UpdateNodeAnnouncement_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Peers_UpdateFeatures_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFeaturesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersServer).UpdateFeatures(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peersrpc.Peers/UpdateFeatures",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersServer).UpdateFeatures(ctx, req.(*UpdateFeaturesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peers_UpdateGossipFilter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateGossipFilterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersServer).UpdateGossipFilter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peersrpc.Peers/UpdateGossipFilter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersServer).UpdateGossipFilter(ctx, req.(*UpdateGossipFilterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peers_ListGossipFilters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGossipFiltersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersServer).ListGossipFilters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peersrpc.Peers/ListGossipFilters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersServer).ListGossipFilters(ctx, req.(*ListGossipFiltersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Peers_ServiceDesc is the grpc.ServiceDesc for Peers service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -101,6 +229,18 @@ var Peers_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateNodeAnnouncement",
 			Handler:    _Peers_UpdateNodeAnnouncement_Handler,
 		},
+		{
+			MethodName: "UpdateFeatures",
+			Handler:    _Peers_UpdateFeatures_Handler,
+		},
+		{
+			MethodName: "UpdateGossipFilter",
+			Handler:    _Peers_UpdateGossipFilter_Handler,
+		},
+		{
+			MethodName: "ListGossipFilters",
+			Handler:    _Peers_ListGossipFilters_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "peersrpc/peers.proto",
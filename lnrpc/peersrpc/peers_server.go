@@ -7,14 +7,19 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"sort"
 	"sync/atomic"
+	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/lightningnetwork/lnd/discovery"
 	"github.com/lightningnetwork/lnd/feature"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/routing/route"
 	"google.golang.org/grpc"
 	"gopkg.in/macaroon-bakery.v2/bakery"
 )
@@ -34,6 +39,18 @@ var (
 			Entity: "peers",
 			Action: "write",
 		}},
+		"/peersrpc.Peers/UpdateFeatures": {{
+			Entity: "peers",
+			Action: "write",
+		}},
+		"/peersrpc.Peers/UpdateGossipFilter": {{
+			Entity: "peers",
+			Action: "write",
+		}},
+		"/peersrpc.Peers/ListGossipFilters": {{
+			Entity: "peers",
+			Action: "read",
+		}},
 	}
 )
 
@@ -401,3 +418,143 @@ func (s *Server) UpdateNodeAnnouncement(_ context.Context,
 
 	return resp, nil
 }
+
+// UpdateFeatures enables or disables optional feature bits that can be
+// toggled while lnd is running. The new feature set is advertised in a fresh
+// node announcement and is sent to each peer in our Init message the next time
+// it connects.
+func (s *Server) UpdateFeatures(_ context.Context,
+	req *UpdateFeaturesRequest) (*UpdateFeaturesResponse, error) {
+
+	if len(req.FeatureUpdates) == 0 {
+		return nil, fmt.Errorf("no feature updates specified")
+	}
+
+	updates := make(map[lnwire.FeatureBit]bool, len(req.FeatureUpdates))
+	for _, update := range req.FeatureUpdates {
+		bit := lnwire.FeatureBit(update.FeatureBit)
+		if _, ok := updates[bit]; ok {
+			return nil, fmt.Errorf("duplicate update for feature "+
+				"bit %v", update.FeatureBit)
+		}
+
+		switch update.Action {
+		case UpdateAction_ADD:
+			updates[bit] = true
+
+		case UpdateAction_REMOVE:
+			updates[bit] = false
+
+		default:
+			return nil, fmt.Errorf("invalid update action (%v) "+
+				"for bit %v", update.Action, update.FeatureBit)
+		}
+	}
+
+	err := s.cfg.UpdateFeatureBits(updates, req.ReconnectPeers)
+	if err != nil {
+		return nil, fmt.Errorf("unable to update feature bits: %w", err)
+	}
+
+	return &UpdateFeaturesResponse{
+		InitFeatures: invoicesrpc.CreateRPCFeatures(
+			s.cfg.GetFeatures(feature.SetInit),
+		),
+		NodeAnnouncementFeatures: invoicesrpc.CreateRPCFeatures(
+			s.cfg.GetFeatures(feature.SetNodeAnn),
+		),
+	}, nil
+}
+
+// UpdateGossipFilter overrides the gossip timestamp filter we send to a peer,
+// regardless of whether we chose it as an active or passive gossip syncer.
+func (s *Server) UpdateGossipFilter(_ context.Context,
+	req *UpdateGossipFilterRequest) (*UpdateGossipFilterResponse, error) {
+
+	peer, err := route.NewVertexFromStr(req.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid peer public key: %w", err)
+	}
+
+	var filter *discovery.GossipFilter
+	switch req.Mode {
+	// The default mode removes the override, so we'll leave the filter
+	// nil.
+	case GossipFilterMode_DEFAULT_GOSSIP:
+
+	case GossipFilterMode_MUTE_GOSSIP:
+		filter = &discovery.GossipFilter{
+			Mode: discovery.GossipFilterMute,
+		}
+
+	case GossipFilterMode_ALL_GOSSIP:
+		filter = &discovery.GossipFilter{
+			Mode: discovery.GossipFilterAll,
+		}
+
+	case GossipFilterMode_CUSTOM_GOSSIP:
+		filter = &discovery.GossipFilter{
+			Mode: discovery.GossipFilterCustom,
+			FirstTimestamp: time.Unix(
+				int64(req.FirstTimestamp), 0,
+			),
+			TimestampRange: req.TimestampRange,
+		}
+
+	default:
+		return nil, fmt.Errorf("invalid gossip filter mode %v",
+			req.Mode)
+	}
+
+	if err := s.cfg.SetGossipFilter(peer, filter); err != nil {
+		return nil, fmt.Errorf("unable to update gossip filter: %w",
+			err)
+	}
+
+	return &UpdateGossipFilterResponse{}, nil
+}
+
+// ListGossipFilters returns the gossip filters that currently override the
+// ones chosen for our peers by the gossip sync manager.
+func (s *Server) ListGossipFilters(_ context.Context,
+	_ *ListGossipFiltersRequest) (*ListGossipFiltersResponse, error) {
+
+	filters := s.cfg.GossipFilters()
+
+	resp := &ListGossipFiltersResponse{
+		Filters: make([]*GossipFilter, 0, len(filters)),
+	}
+	for peer, filter := range filters {
+		rpcFilter := &GossipFilter{
+			PubKey: peer.String(),
+		}
+
+		switch filter.Mode {
+		case discovery.GossipFilterMute:
+			rpcFilter.Mode = GossipFilterMode_MUTE_GOSSIP
+
+		case discovery.GossipFilterAll:
+			rpcFilter.Mode = GossipFilterMode_ALL_GOSSIP
+
+		case discovery.GossipFilterCustom:
+			rpcFilter.Mode = GossipFilterMode_CUSTOM_GOSSIP
+			rpcFilter.FirstTimestamp = uint32(
+				filter.FirstTimestamp.Unix(),
+			)
+			rpcFilter.TimestampRange = filter.TimestampRange
+
+		default:
+			return nil, fmt.Errorf("unknown gossip filter mode %v",
+				filter.Mode)
+		}
+
+		resp.Filters = append(resp.Filters, rpcFilter)
+	}
+
+	// Sort the filters by public key for a stable output.
+	sort.Slice(resp.Filters, func(i, j int) bool {
+		return resp.Filters[i].PubKey < resp.Filters[j].PubKey
+	})
+
+	return resp, nil
+}
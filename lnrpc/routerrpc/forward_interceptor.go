@@ -2,6 +2,7 @@ package routerrpc
 
 import (
 	"errors"
+	"time"
 
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/htlcswitch"
@@ -49,18 +50,68 @@ func newForwardInterceptor(htlcSwitch htlcswitch.InterceptableHtlcForwarder,
 // To coordinate all this and make sure it is safe for concurrent access all
 // packets are sent to the main where they are handled.
 func (r *forwardInterceptor) run() error {
-	// Register our interceptor so we receive all forwarded packets.
-	r.htlcSwitch.SetInterceptor(r.onIntercept)
-	defer r.htlcSwitch.SetInterceptor(nil)
+	// The switch unregisters our interceptor if it doesn't resolve a
+	// forward within its health timeout, in which case we close the
+	// stream.
+	unhealthy := make(chan struct{})
+	opts := htlcswitch.InterceptorOptions{
+		OnUnhealthy: func() {
+			close(unhealthy)
+		},
+	}
 
-	for {
-		resp, err := r.stream.Recv()
-		if err != nil {
-			return err
+	// Register our interceptor so we receive all forwarded packets, until
+	// the client registers its options.
+	id := r.htlcSwitch.AddInterceptor(r.onIntercept, opts)
+	defer r.htlcSwitch.RemoveInterceptor(id)
+
+	responses := make(chan *ForwardHtlcInterceptResponse)
+	errChan := make(chan error, 1)
+	quit := make(chan struct{})
+	defer close(quit)
+
+	go func() {
+		for {
+			resp, err := r.stream.Recv()
+			if err != nil {
+				errChan <- err
+				return
+			}
+
+			select {
+			case responses <- resp:
+			case <-quit:
+				return
+			}
 		}
+	}()
 
-		if err := r.resolveFromClient(resp); err != nil {
+	for {
+		select {
+		case resp := <-responses:
+			if resp.Registration != nil {
+				opts.Priority = resp.Registration.Priority
+				opts.HealthTimeout = time.Duration(
+					resp.Registration.HealthTimeoutMs,
+				) * time.Millisecond
+				r.htlcSwitch.UpdateInterceptor(id, opts)
+
+				continue
+			}
+
+			if err := r.resolveFromClient(resp); err != nil {
+				return err
+			}
+
+		case err := <-errChan:
 			return err
+
+		case <-unhealthy:
+			return status.Errorf(
+				codes.DeadlineExceeded, "interceptor didn't "+
+					"resolve forward within %v",
+				opts.HealthTimeout,
+			)
 		}
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	math "math"
 	"time"
 
@@ -35,6 +36,10 @@ const (
 	DefaultMaxParts = 16
 )
 
+// ErrSelfPayment is returned when a payment whose destination is our own node
+// isn't permitted by the request.
+var ErrSelfPayment = errors.New("self-payments not allowed")
+
 // RouterBackend contains the backend implementation of the router rpc sub
 // server calls.
 type RouterBackend struct {
@@ -45,6 +50,11 @@ type RouterBackend struct {
 	// capacity of a channel to populate in responses.
 	FetchChannelCapacity func(chanID uint64) (btcutil.Amount, error)
 
+	// FetchAmountPairCapacity determines the maximal channel capacity
+	// between two nodes given a certain amount.
+	FetchAmountPairCapacity func(nodeFrom, nodeTo route.Vertex,
+		amount lnwire.MilliSatoshi) (btcutil.Amount, error)
+
 	// FetchChannelEndpoints returns the pubkeys of both endpoints of the
 	// given channel id.
 	FetchChannelEndpoints func(chanID uint64) (route.Vertex,
@@ -96,6 +106,32 @@ type RouterBackend struct {
 	// SetChannelAuto exposes the ability to restore automatic channel state
 	// management after manually setting channel status.
 	SetChannelAuto func(wire.OutPoint) error
+
+	// SwitchDrainer exposes the ability to drain the switch of HTLCs in
+	// flight before the node is restarted.
+	SwitchDrainer SwitchDrainer
+
+	// AddLocalAlias adds the given alias to the open channel with the
+	// given base SCID.
+	AddLocalAlias func(alias, baseScid lnwire.ShortChannelID) error
+
+	// GetAliases returns the set of aliases of the channel with the given
+	// base SCID.
+	GetAliases func(baseScid lnwire.ShortChannelID) []lnwire.ShortChannelID
+}
+
+// SwitchDrainer defines the switch dependencies needed to drain the switch.
+type SwitchDrainer interface {
+	// SetDraining puts the switch into or takes it out of drain mode, in
+	// which it doesn't accept any new forwards.
+	SetDraining(draining bool)
+
+	// IsDraining returns true if the switch is in drain mode.
+	IsDraining() bool
+
+	// NumInFlight returns the number of HTLCs in flight through the
+	// switch.
+	NumInFlight() int
 }
 
 // MissionControl defines the mission control dependencies of routerrpc.
@@ -103,7 +139,7 @@ type MissionControl interface {
 	// GetProbability is expected to return the success probability of a
 	// payment from fromNode to toNode.
 	GetProbability(fromNode, toNode route.Vertex,
-		amt lnwire.MilliSatoshi) float64
+		amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64
 
 	// ResetHistory resets the history of MissionControl returning it to a
 	// state as if no payment attempts have been made.
@@ -118,6 +154,14 @@ type MissionControl interface {
 	// persisted across restarts.
 	ImportHistory(snapshot *routing.MissionControlSnapshot, force bool) error
 
+	// ExportHistory writes the mission control state to w in the versioned
+	// export format, and returns the number of exported pairs.
+	ExportHistory(w io.Writer, anonymize bool) (int, error)
+
+	// ImportExportedHistory imports mission control state in the versioned
+	// export format from r, and returns the number of imported results.
+	ImportExportedHistory(r io.Reader, force bool) (int, error)
+
 	// GetPairHistorySnapshot returns the stored history for a given node
 	// pair.
 	GetPairHistorySnapshot(fromNode,
@@ -129,6 +173,19 @@ type MissionControl interface {
 	// SetConfig sets mission control's config to the values provided, if
 	// they are valid.
 	SetConfig(cfg *routing.MissionControlConfig) error
+
+	// Session returns the isolated mission control session with the given
+	// name, creating it from the current mission control state if it
+	// doesn't exist yet.
+	Session(name string) (*routing.MissionControl, error)
+
+	// LookupSession returns the isolated mission control session with the
+	// given name, if it exists.
+	LookupSession(name string) (*routing.MissionControl, error)
+
+	// EndSession discards the isolated mission control session with the
+	// given name.
+	EndSession(name string) error
 }
 
 // QueryRoutes attempts to query the daemons' Channel Router for a possible
@@ -258,7 +315,7 @@ func (r *RouterBackend) QueryRoutes(ctx context.Context,
 	restrictions := &routing.RestrictParams{
 		FeeLimit: feeLimit,
 		ProbabilitySource: func(fromNode, toNode route.Vertex,
-			amt lnwire.MilliSatoshi) float64 {
+			amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64 {
 
 			if _, ok := ignoredNodes[fromNode]; ok {
 				return 0
@@ -277,7 +334,7 @@ func (r *RouterBackend) QueryRoutes(ctx context.Context,
 			}
 
 			return r.MissionControl.GetProbability(
-				fromNode, toNode, amt,
+				fromNode, toNode, amt, capacity,
 			)
 		},
 		DestCustomRecords: record.CustomSet(in.DestCustomRecords),
@@ -361,8 +418,16 @@ func (r *RouterBackend) getSuccessProbability(rt *route.Route) float64 {
 	for _, hop := range rt.Hops {
 		toNode := hop.PubKeyBytes
 
+		// Use the channel capacity as known from the graph. If it is
+		// unknown, we pass a zero capacity which lets the estimator
+		// make an assumption about it.
+		capacity, err := r.FetchChannelCapacity(hop.ChannelID)
+		if err != nil {
+			capacity = 0
+		}
+
 		probability := r.MissionControl.GetProbability(
-			fromNode, toNode, amtToFwd,
+			fromNode, toNode, amtToFwd, capacity,
 		)
 
 		successProb *= probability
@@ -553,6 +618,42 @@ func (r *RouterBackend) UnmarshallRoute(rpcroute *lnrpc.Route) (
 	return route, nil
 }
 
+// unmarshallVertexSet parses a list of node pubkeys into a set. A nil set is
+// returned if the list is empty.
+func unmarshallVertexSet(pubKeys [][]byte) (map[route.Vertex]struct{},
+	error) {
+
+	if len(pubKeys) == 0 {
+		return nil, nil
+	}
+
+	vertices := make(map[route.Vertex]struct{}, len(pubKeys))
+	for _, pubKey := range pubKeys {
+		vertex, err := route.NewVertexFromBytes(pubKey)
+		if err != nil {
+			return nil, err
+		}
+		vertices[vertex] = struct{}{}
+	}
+
+	return vertices, nil
+}
+
+// unmarshallChanIDSet turns a list of short channel ids into a set. A nil set
+// is returned if the list is empty.
+func unmarshallChanIDSet(chanIDs []uint64) map[uint64]struct{} {
+	if len(chanIDs) == 0 {
+		return nil
+	}
+
+	chanIDSet := make(map[uint64]struct{}, len(chanIDs))
+	for _, chanID := range chanIDs {
+		chanIDSet[chanID] = struct{}{}
+	}
+
+	return chanIDSet
+}
+
 // extractIntentFromSendRequest attempts to parse the SendRequest details
 // required to dispatch a client from the information presented by an RPC
 // client.
@@ -567,6 +668,17 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 	}
 	payIntent.TimePref = rpcPayReq.TimePref
 
+	// Run the payment in an isolated mission control session if requested.
+	if rpcPayReq.MissionControlSession != "" {
+		session, err := r.MissionControl.Session(
+			rpcPayReq.MissionControlSession,
+		)
+		if err != nil {
+			return nil, err
+		}
+		payIntent.MissionControl = session
+	}
+
 	// Pass along restrictions on the outgoing channels that may be used.
 	payIntent.OutgoingChannelIDs = rpcPayReq.OutgoingChanIds
 
@@ -593,6 +705,26 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 		payIntent.LastHop = &lastHop
 	}
 
+	// Pass along the nodes and channels that may or may not be used.
+	ignoredNodes, err := unmarshallVertexSet(rpcPayReq.IgnoredNodes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ignored node: %v", err)
+	}
+	payIntent.IgnoredNodes = ignoredNodes
+
+	allowedNodes, err := unmarshallVertexSet(rpcPayReq.AllowedNodes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid allowed node: %v", err)
+	}
+	payIntent.AllowedNodes = allowedNodes
+
+	payIntent.IgnoredChannels = unmarshallChanIDSet(
+		rpcPayReq.IgnoredChanIds,
+	)
+	payIntent.AllowedChannels = unmarshallChanIDSet(
+		rpcPayReq.AllowedChanIds,
+	)
+
 	// Take the CLTV limit from the request if set, otherwise use the max.
 	cltvLimit, err := ValidateCLTVLimit(
 		uint32(rpcPayReq.CltvLimit), r.MaxTotalTimelock,
@@ -611,6 +743,15 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 	}
 	payIntent.MaxParts = maxParts
 
+	// The number of shards in flight at the same time defaults to the
+	// maximum number of parts, so that all shards can be launched in
+	// parallel.
+	maxParallelism := rpcPayReq.MaxParallelism
+	if maxParallelism == 0 || maxParallelism > maxParts {
+		maxParallelism = maxParts
+	}
+	payIntent.MaxParallelism = maxParallelism
+
 	// If this payment had a max shard amount specified, then we'll apply
 	// that now, which'll force us to always make payment splits smaller
 	// than this.
@@ -619,6 +760,30 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 		payIntent.MaxShardAmt = &shardAmtMsat
 	}
 
+	// The minimum shard amount bounds how far the payment is split. It
+	// can't exceed the max shard amount, as no shard could be attempted
+	// otherwise.
+	if rpcPayReq.MinShardSizeMsat > 0 {
+		if rpcPayReq.MaxShardSizeMsat > 0 &&
+			rpcPayReq.MinShardSizeMsat > rpcPayReq.MaxShardSizeMsat {
+
+			return nil, fmt.Errorf("min_shard_size_msat %v exceeds "+
+				"max_shard_size_msat %v",
+				rpcPayReq.MinShardSizeMsat,
+				rpcPayReq.MaxShardSizeMsat)
+		}
+
+		minShardAmt := lnwire.MilliSatoshi(rpcPayReq.MinShardSizeMsat)
+		payIntent.MinShardAmt = &minShardAmt
+	}
+
+	payIntent.SplitStrategy, err = unmarshallSplitStrategy(
+		rpcPayReq.SplitStrategy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	// Take fee limit from request.
 	payIntent.FeeLimit, err = lnrpc.UnmarshallAmt(
 		rpcPayReq.FeeLimitSat, rpcPayReq.FeeLimitMsat,
@@ -768,6 +933,17 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 		payIntent.PaymentAddr = payAddr
 		payIntent.PaymentRequest = []byte(rpcPayReq.PaymentRequest)
 		payIntent.Metadata = payReq.Metadata
+
+		// If the invoice contains blinded paths, we'll pay to the
+		// first one instead of the destination, which is hidden.
+		if len(payReq.BlindedPaymentPaths) > 0 {
+			err := setBlindedPayment(
+				payIntent, payReq.BlindedPaymentPaths[0],
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
 	} else {
 		// Otherwise, If the payment request field was not specified
 		// (and a custom route wasn't specified), construct the payment
@@ -899,13 +1075,113 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 	}
 
 	// Check for disallowed payments to self.
-	if !rpcPayReq.AllowSelfPayment && payIntent.Target == r.SelfNode {
-		return nil, errors.New("self-payments not allowed")
+	err = r.checkSelfPayment(payIntent.Target, rpcPayReq)
+	if err != nil {
+		return nil, err
 	}
 
 	return payIntent, nil
 }
 
+// setBlindedPayment updates a payment intent that was created from an invoice
+// to pay to the given blinded path of the invoice.
+func setBlindedPayment(payIntent *routing.LightningPayment,
+	path *zpay32.BlindedPaymentPath) error {
+
+	blindedPayment := &routing.BlindedPayment{
+		BlindedPath:     path.Path,
+		BaseFee:         path.FeeBaseMsat,
+		ProportionalFee: path.FeeRate,
+		CltvExpiryDelta: path.CltvExpiryDelta,
+		HtlcMinimum:     lnwire.MilliSatoshi(path.HTLCMinMsat),
+		HtlcMaximum:     lnwire.MilliSatoshi(path.HTLCMaxMsat),
+		Features:        path.Features,
+	}
+	if err := blindedPayment.Validate(); err != nil {
+		return err
+	}
+
+	// The aggregate CLTV delta of the path already includes the final
+	// CLTV delta of the recipient. The recipient identifies the payment
+	// by the encrypted data of the path, so there's no payment address.
+	payIntent.BlindedPayment = blindedPayment
+	payIntent.Target = blindedPayment.Target()
+	payIntent.FinalCLTVDelta = 0
+	payIntent.RouteHints = nil
+	payIntent.PaymentAddr = nil
+
+	// The hops of a blinded path only carry TLV payloads, so the final hop
+	// must be reached with one as well.
+	features := lnwire.NewRawFeatureVector(
+		lnwire.TLVOnionPayloadOptional,
+	)
+	if payIntent.DestFeatures != nil {
+		features = payIntent.DestFeatures.Clone().RawFeatureVector
+		features.Set(lnwire.TLVOnionPayloadOptional)
+	}
+	payIntent.DestFeatures = lnwire.NewFeatureVector(
+		features, lnwire.Features,
+	)
+
+	return nil
+}
+
+// unmarshallSplitStrategy converts the rpc split strategy of a payment to the
+// strategy of the router.
+func unmarshallSplitStrategy(
+	strategy SplitStrategy) (routing.SplitStrategy, error) {
+
+	switch strategy {
+	case SplitStrategy_SPLIT_ADAPTIVE:
+		return routing.SplitAdaptive, nil
+
+	case SplitStrategy_SPLIT_EQUAL:
+		return routing.SplitEqual, nil
+
+	case SplitStrategy_SPLIT_LARGEST_CHANNEL:
+		return routing.SplitLargestChannel, nil
+
+	default:
+		return 0, fmt.Errorf("unknown split strategy: %v", strategy)
+	}
+}
+
+// checkSelfPayment returns ErrSelfPayment if the payment targets our own node
+// and the self-payment policy of the request doesn't permit routing it
+// circularly through the network.
+func (r *RouterBackend) checkSelfPayment(target route.Vertex,
+	rpcPayReq *SendPaymentRequest) error {
+
+	policy := rpcPayReq.SelfPaymentPolicy
+	if rpcPayReq.AllowSelfPayment &&
+		policy == SelfPaymentPolicy_SELF_PAYMENT_REJECT {
+
+		return errors.New("allow_self_payment can't be combined " +
+			"with the SELF_PAYMENT_REJECT self-payment policy")
+	}
+
+	var allowed bool
+	switch policy {
+	case SelfPaymentPolicy_SELF_PAYMENT_DEFAULT:
+		allowed = rpcPayReq.AllowSelfPayment
+
+	case SelfPaymentPolicy_SELF_PAYMENT_REJECT:
+		allowed = false
+
+	case SelfPaymentPolicy_SELF_PAYMENT_CIRCULAR:
+		allowed = true
+
+	default:
+		return fmt.Errorf("unknown self-payment policy: %v", policy)
+	}
+
+	if !allowed && target == r.SelfNode {
+		return ErrSelfPayment
+	}
+
+	return nil
+}
+
 // unmarshallRouteHints unmarshalls a list of route hints.
 func unmarshallRouteHints(rpcRouteHints []*lnrpc.RouteHint) (
 	[][]zpay32.HopHint, error) {
@@ -1064,7 +1340,12 @@ func UnmarshalAMP(reqAMP *lnrpc.AMPRecord) (*record.AMP, error) {
 	copy(rootShare[:], reqRootShare)
 	copy(setID[:], reqSetID)
 
-	return record.NewAMP(rootShare, setID, reqAMP.ChildIndex), nil
+	amp := record.NewAMP(rootShare, setID, reqAMP.ChildIndex)
+	if err := amp.Validate(); err != nil {
+		return nil, err
+	}
+
+	return amp, nil
 }
 
 // MarshalHTLCAttempt constructs an RPC HTLCAttempt from the db representation.
@@ -1080,6 +1361,7 @@ func (r *RouterBackend) MarshalHTLCAttempt(
 		AttemptId:     htlc.AttemptID,
 		AttemptTimeNs: MarshalTimeNano(htlc.AttemptTime),
 		Route:         route,
+		PartAmtMsat:   uint64(htlc.Route.ReceiverAmt()),
 	}
 
 	switch {
@@ -1101,6 +1383,10 @@ func (r *RouterBackend) MarshalHTLCAttempt(
 		if err != nil {
 			return nil, err
 		}
+
+		rpcAttempt.FailureSourcePubKey = failureSourcePubKey(
+			&htlc.Route, htlc.Failure,
+		)
 	default:
 		rpcAttempt.Status = lnrpc.HTLCAttempt_IN_FLIGHT
 	}
@@ -1108,6 +1394,31 @@ func (r *RouterBackend) MarshalHTLCAttempt(
 	return rpcAttempt, nil
 }
 
+// failureSourcePubKey returns the pubkey of the node that returned the given
+// failure of an htlc sent along the route. Nil is returned if the failure
+// wasn't returned by a known node of the route.
+func failureSourcePubKey(rt *route.Route,
+	failure *channeldb.HTLCFailInfo) []byte {
+
+	if failure.Reason != channeldb.HTLCFailMessage {
+		return nil
+	}
+
+	// The failure source index is zero for our own node, and the index of
+	// the hop plus one for the nodes along the route.
+	idx := int(failure.FailureSourceIndex)
+	switch {
+	case idx == 0:
+		return rt.SourcePubKey[:]
+
+	case idx <= len(rt.Hops):
+		return rt.Hops[idx-1].PubKeyBytes[:]
+
+	default:
+		return nil
+	}
+}
+
 // marshallHtlcFailure marshalls htlc fail info from the database to its rpc
 // representation.
 func marshallHtlcFailure(failure *channeldb.HTLCFailInfo) (*lnrpc.Failure,
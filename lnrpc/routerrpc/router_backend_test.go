@@ -145,18 +145,18 @@ func testQueryRoutes(t *testing.T, useMissionControl bool, useMsat bool,
 		}
 
 		if restrictions.ProbabilitySource(route.Vertex{2},
-			route.Vertex{1}, 0,
+			route.Vertex{1}, 0, 0,
 		) != 0 {
 			t.Fatal("expecting 0% probability for ignored edge")
 		}
 
 		if restrictions.ProbabilitySource(ignoreNodeVertex,
-			route.Vertex{6}, 0,
+			route.Vertex{6}, 0, 0,
 		) != 0 {
 			t.Fatal("expecting 0% probability for ignored node")
 		}
 
-		if restrictions.ProbabilitySource(node1, node2, 0) != 0 {
+		if restrictions.ProbabilitySource(node1, node2, 0, 0) != 0 {
 			t.Fatal("expecting 0% probability for ignored pair")
 		}
 
@@ -181,7 +181,7 @@ func testQueryRoutes(t *testing.T, useMissionControl bool, useMsat bool,
 			expectedProb = testMissionControlProb
 		}
 		if restrictions.ProbabilitySource(route.Vertex{4},
-			route.Vertex{5}, 0,
+			route.Vertex{5}, 0, 0,
 		) != expectedProb {
 			t.Fatal("expecting 100% probability")
 		}
@@ -239,7 +239,7 @@ type mockMissionControl struct {
 }
 
 func (m *mockMissionControl) GetProbability(fromNode, toNode route.Vertex,
-	amt lnwire.MilliSatoshi) float64 {
+	amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64 {
 
 	return testMissionControlProb
 }
@@ -424,6 +424,15 @@ func TestUnmarshalAMP(t *testing.T) {
 			},
 			outcome: invalid,
 		},
+		{
+			name: "valid root share blank set id",
+			amp: &lnrpc.AMPRecord{
+				RootShare:  rootShare,
+				SetId:      make([]byte, 32),
+				ChildIndex: childIndex,
+			},
+			outcome: invalid,
+		},
 		{
 			name: "valid root share valid set id",
 			amp: &lnrpc.AMPRecord{
@@ -468,3 +477,181 @@ func testUnmarshalAMP(t *testing.T, test unmarshalAMPTest) {
 		t.Fatalf("test case has non-standard outcome")
 	}
 }
+
+// TestCheckSelfPayment tests that the self-payment policy of a payment request
+// is correctly enforced for payments that target our own node.
+func TestCheckSelfPayment(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		target      route.Vertex
+		allowSelf   bool
+		policy      SelfPaymentPolicy
+		expectedErr error
+		expectFail  bool
+	}{
+		{
+			name:   "other node default policy",
+			target: node1,
+			policy: SelfPaymentPolicy_SELF_PAYMENT_DEFAULT,
+		},
+		{
+			name:   "other node reject policy",
+			target: node1,
+			policy: SelfPaymentPolicy_SELF_PAYMENT_REJECT,
+		},
+		{
+			name:        "self default policy",
+			target:      sourceKey,
+			policy:      SelfPaymentPolicy_SELF_PAYMENT_DEFAULT,
+			expectedErr: ErrSelfPayment,
+		},
+		{
+			name:      "self default policy allow self payment",
+			target:    sourceKey,
+			allowSelf: true,
+			policy:    SelfPaymentPolicy_SELF_PAYMENT_DEFAULT,
+		},
+		{
+			name:        "self reject policy",
+			target:      sourceKey,
+			policy:      SelfPaymentPolicy_SELF_PAYMENT_REJECT,
+			expectedErr: ErrSelfPayment,
+		},
+		{
+			name:   "self circular policy",
+			target: sourceKey,
+			policy: SelfPaymentPolicy_SELF_PAYMENT_CIRCULAR,
+		},
+		{
+			name:       "reject policy with allow self payment",
+			target:     node1,
+			allowSelf:  true,
+			policy:     SelfPaymentPolicy_SELF_PAYMENT_REJECT,
+			expectFail: true,
+		},
+		{
+			name:       "unknown policy",
+			target:     node1,
+			policy:     SelfPaymentPolicy(99),
+			expectFail: true,
+		},
+	}
+
+	backend := &RouterBackend{
+		SelfNode: sourceKey,
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := backend.checkSelfPayment(
+				test.target, &SendPaymentRequest{
+					AllowSelfPayment:  test.allowSelf,
+					SelfPaymentPolicy: test.policy,
+				},
+			)
+
+			switch {
+			case test.expectedErr != nil:
+				require.ErrorIs(t, err, test.expectedErr)
+
+			case test.expectFail:
+				require.Error(t, err)
+
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestUnmarshallSplitStrategy tests that the rpc split strategies are mapped to
+// the strategies of the router.
+func TestUnmarshallSplitStrategy(t *testing.T) {
+	t.Parallel()
+
+	strategies := map[SplitStrategy]routing.SplitStrategy{
+		SplitStrategy_SPLIT_ADAPTIVE:        routing.SplitAdaptive,
+		SplitStrategy_SPLIT_EQUAL:           routing.SplitEqual,
+		SplitStrategy_SPLIT_LARGEST_CHANNEL: routing.SplitLargestChannel,
+	}
+	for rpcStrategy, strategy := range strategies {
+		unmarshalled, err := unmarshallSplitStrategy(rpcStrategy)
+		require.NoError(t, err)
+		require.Equal(t, strategy, unmarshalled)
+	}
+
+	_, err := unmarshallSplitStrategy(SplitStrategy(99))
+	require.Error(t, err)
+}
+
+// TestFailureSourcePubKey tests that the node that returned the failure of an
+// htlc is looked up in its route.
+func TestFailureSourcePubKey(t *testing.T) {
+	t.Parallel()
+
+	rt := &route.Route{
+		SourcePubKey: sourceKey,
+		Hops: []*route.Hop{
+			{PubKeyBytes: node1},
+			{PubKeyBytes: node2},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		reason      channeldb.HTLCFailReason
+		sourceIndex uint32
+		expected    []byte
+	}{
+		{
+			name:        "own node",
+			reason:      channeldb.HTLCFailMessage,
+			sourceIndex: 0,
+			expected:    sourceKey[:],
+		},
+		{
+			name:        "first hop",
+			reason:      channeldb.HTLCFailMessage,
+			sourceIndex: 1,
+			expected:    node1[:],
+		},
+		{
+			name:        "final hop",
+			reason:      channeldb.HTLCFailMessage,
+			sourceIndex: 2,
+			expected:    node2[:],
+		},
+		{
+			name:        "index beyond route",
+			reason:      channeldb.HTLCFailMessage,
+			sourceIndex: 3,
+		},
+		{
+			name:        "unreadable failure",
+			reason:      channeldb.HTLCFailUnreadable,
+			sourceIndex: 1,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			pubKey := failureSourcePubKey(
+				rt, &channeldb.HTLCFailInfo{
+					Reason:             test.reason,
+					FailureSourceIndex: test.sourceIndex,
+				},
+			)
+			require.Equal(t, test.expected, pubKey)
+		})
+	}
+}
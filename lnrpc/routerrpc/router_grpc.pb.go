@@ -43,7 +43,8 @@ type RouterClient interface {
 	// swaps.
 	SendToRouteV2(ctx context.Context, in *SendToRouteRequest, opts ...grpc.CallOption) (*lnrpc.HTLCAttempt, error)
 	// ResetMissionControl clears all mission control state and starts with a clean
-	// slate.
+	// slate. If a session is specified, only that isolated mission control session
+	// is discarded.
 	ResetMissionControl(ctx context.Context, in *ResetMissionControlRequest, opts ...grpc.CallOption) (*ResetMissionControlResponse, error)
 	// QueryMissionControl exposes the internal mission control state to callers.
 	// It is a development feature.
@@ -53,6 +54,18 @@ type RouterClient interface {
 	// recent than our existing values. These values will only be imported
 	// in-memory, and will not be persisted across restarts.
 	XImportMissionControl(ctx context.Context, in *XImportMissionControlRequest, opts ...grpc.CallOption) (*XImportMissionControlResponse, error)
+	// ExportMissionControl exports the mission control state in a versioned
+	// binary format that can be imported by other nodes with
+	// ImportMissionControl. If anonymize is set, the pairs involving our own node
+	// are left out and the timestamps are truncated to the hour, so that the
+	// export can be shared without revealing our node.
+	ExportMissionControl(ctx context.Context, in *ExportMissionControlRequest, opts ...grpc.CallOption) (*ExportMissionControlResponse, error)
+	// ImportMissionControl imports mission control state that was exported with
+	// ExportMissionControl, for example to seed a fresh node. Only results that
+	// are fresher than our existing state are used, unless force is set. The
+	// import is only applied in-memory, and will not be persisted across
+	// restarts.
+	ImportMissionControl(ctx context.Context, in *ImportMissionControlRequest, opts ...grpc.CallOption) (*ImportMissionControlResponse, error)
 	// GetMissionControlConfig returns mission control's current config.
 	GetMissionControlConfig(ctx context.Context, in *GetMissionControlConfigRequest, opts ...grpc.CallOption) (*GetMissionControlConfigResponse, error)
 	// SetMissionControlConfig will set mission control's config, if the config
@@ -91,6 +104,18 @@ type RouterClient interface {
 	// channel to stay disabled until a subsequent manual request of either
 	// "enable" or "auto".
 	UpdateChanStatus(ctx context.Context, in *UpdateChanStatusRequest, opts ...grpc.CallOption) (*UpdateChanStatusResponse, error)
+	// DrainSwitch puts the switch into drain mode, in which it stops accepting
+	// new forwards and fails them back with a temporary channel failure, while
+	// the HTLCs in flight are resolved as usual. This allows the node to be
+	// restarted for maintenance without any HTLCs in flight. Drain mode is left
+	// by calling DrainSwitch with stop set, or by restarting the node.
+	DrainSwitch(ctx context.Context, in *DrainSwitchRequest, opts ...grpc.CallOption) (*DrainSwitchResponse, error)
+	// XAddLocalChanAliases is an experimental API that adds a set of local
+	// aliases to the open channels with the given base SCIDs. The aliases are
+	// persisted and can be used right away to forward HTLCs and in the channel
+	// updates of the channels. Only channels that negotiated the option-scid-alias
+	// feature or zero-conf channels can have aliases.
+	XAddLocalChanAliases(ctx context.Context, in *AddAliasesRequest, opts ...grpc.CallOption) (*AddAliasesResponse, error)
 }
 
 type routerClient struct {
@@ -220,6 +245,24 @@ func (c *routerClient) XImportMissionControl(ctx context.Context, in *XImportMis
 	return out, nil
 }
 
+func (c *routerClient) ExportMissionControl(ctx context.Context, in *ExportMissionControlRequest, opts ...grpc.CallOption) (*ExportMissionControlResponse, error) {
+	out := new(ExportMissionControlResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/ExportMissionControl", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) ImportMissionControl(ctx context.Context, in *ImportMissionControlRequest, opts ...grpc.CallOption) (*ImportMissionControlResponse, error) {
+	out := new(ImportMissionControlResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/ImportMissionControl", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *routerClient) GetMissionControlConfig(ctx context.Context, in *GetMissionControlConfigRequest, opts ...grpc.CallOption) (*GetMissionControlConfigResponse, error) {
 	out := new(GetMissionControlConfigResponse)
 	err := c.cc.Invoke(ctx, "/routerrpc.Router/GetMissionControlConfig", in, out, opts...)
@@ -394,6 +437,24 @@ func (c *routerClient) UpdateChanStatus(ctx context.Context, in *UpdateChanStatu
 	return out, nil
 }
 
+func (c *routerClient) DrainSwitch(ctx context.Context, in *DrainSwitchRequest, opts ...grpc.CallOption) (*DrainSwitchResponse, error) {
+	out := new(DrainSwitchResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/DrainSwitch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) XAddLocalChanAliases(ctx context.Context, in *AddAliasesRequest, opts ...grpc.CallOption) (*AddAliasesResponse, error) {
+	out := new(AddAliasesResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/XAddLocalChanAliases", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RouterServer is the server API for Router service.
 // All implementations must embed UnimplementedRouterServer
 // for forward compatibility
@@ -422,7 +483,8 @@ type RouterServer interface {
 	// swaps.
 	SendToRouteV2(context.Context, *SendToRouteRequest) (*lnrpc.HTLCAttempt, error)
 	// ResetMissionControl clears all mission control state and starts with a clean
-	// slate.
+	// slate. If a session is specified, only that isolated mission control session
+	// is discarded.
 	ResetMissionControl(context.Context, *ResetMissionControlRequest) (*ResetMissionControlResponse, error)
 	// QueryMissionControl exposes the internal mission control state to callers.
 	// It is a development feature.
@@ -432,6 +494,18 @@ type RouterServer interface {
 	// recent than our existing values. These values will only be imported
 	// in-memory, and will not be persisted across restarts.
 	XImportMissionControl(context.Context, *XImportMissionControlRequest) (*XImportMissionControlResponse, error)
+	// ExportMissionControl exports the mission control state in a versioned
+	// binary format that can be imported by other nodes with
+	// ImportMissionControl. If anonymize is set, the pairs involving our own node
+	// are left out and the timestamps are truncated to the hour, so that the
+	// export can be shared without revealing our node.
+	ExportMissionControl(context.Context, *ExportMissionControlRequest) (*ExportMissionControlResponse, error)
+	// ImportMissionControl imports mission control state that was exported with
+	// ExportMissionControl, for example to seed a fresh node. Only results that
+	// are fresher than our existing state are used, unless force is set. The
+	// import is only applied in-memory, and will not be persisted across
+	// restarts.
+	ImportMissionControl(context.Context, *ImportMissionControlRequest) (*ImportMissionControlResponse, error)
 	// GetMissionControlConfig returns mission control's current config.
 	GetMissionControlConfig(context.Context, *GetMissionControlConfigRequest) (*GetMissionControlConfigResponse, error)
 	// SetMissionControlConfig will set mission control's config, if the config
@@ -470,6 +544,18 @@ type RouterServer interface {
 	// channel to stay disabled until a subsequent manual request of either
 	// "enable" or "auto".
 	UpdateChanStatus(context.Context, *UpdateChanStatusRequest) (*UpdateChanStatusResponse, error)
+	// DrainSwitch puts the switch into drain mode, in which it stops accepting
+	// new forwards and fails them back with a temporary channel failure, while
+	// the HTLCs in flight are resolved as usual. This allows the node to be
+	// restarted for maintenance without any HTLCs in flight. Drain mode is left
+	// by calling DrainSwitch with stop set, or by restarting the node.
+	DrainSwitch(context.Context, *DrainSwitchRequest) (*DrainSwitchResponse, error)
+	// XAddLocalChanAliases is an experimental API that adds a set of local
+	// aliases to the open channels with the given base SCIDs. The aliases are
+	// persisted and can be used right away to forward HTLCs and in the channel
+	// updates of the channels. Only channels that negotiated the option-scid-alias
+	// feature or zero-conf channels can have aliases.
+	XAddLocalChanAliases(context.Context, *AddAliasesRequest) (*AddAliasesResponse, error)
 	mustEmbedUnimplementedRouterServer()
 }
 
@@ -501,6 +587,12 @@ func (UnimplementedRouterServer) QueryMissionControl(context.Context, *QueryMiss
 func (UnimplementedRouterServer) XImportMissionControl(context.Context, *XImportMissionControlRequest) (*XImportMissionControlResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method XImportMissionControl not implemented")
 }
+func (UnimplementedRouterServer) ExportMissionControl(context.Context, *ExportMissionControlRequest) (*ExportMissionControlResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportMissionControl not implemented")
+}
+func (UnimplementedRouterServer) ImportMissionControl(context.Context, *ImportMissionControlRequest) (*ImportMissionControlResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportMissionControl not implemented")
+}
 func (UnimplementedRouterServer) GetMissionControlConfig(context.Context, *GetMissionControlConfigRequest) (*GetMissionControlConfigResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMissionControlConfig not implemented")
 }
@@ -528,6 +620,12 @@ func (UnimplementedRouterServer) HtlcInterceptor(Router_HtlcInterceptorServer) e
 func (UnimplementedRouterServer) UpdateChanStatus(context.Context, *UpdateChanStatusRequest) (*UpdateChanStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateChanStatus not implemented")
 }
+func (UnimplementedRouterServer) DrainSwitch(context.Context, *DrainSwitchRequest) (*DrainSwitchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DrainSwitch not implemented")
+}
+func (UnimplementedRouterServer) XAddLocalChanAliases(context.Context, *AddAliasesRequest) (*AddAliasesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method XAddLocalChanAliases not implemented")
+}
 func (UnimplementedRouterServer) mustEmbedUnimplementedRouterServer() {}
 
 // UnsafeRouterServer may be embedded to opt out of forward compatibility for this service.
@@ -691,6 +789,42 @@ func _Router_XImportMissionControl_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Router_ExportMissionControl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportMissionControlRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).ExportMissionControl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/ExportMissionControl",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).ExportMissionControl(ctx, req.(*ExportMissionControlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_ImportMissionControl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportMissionControlRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).ImportMissionControl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/ImportMissionControl",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).ImportMissionControl(ctx, req.(*ImportMissionControlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Router_GetMissionControlConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetMissionControlConfigRequest)
 	if err := dec(in); err != nil {
@@ -870,6 +1004,42 @@ func _Router_UpdateChanStatus_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Router_DrainSwitch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainSwitchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).DrainSwitch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/DrainSwitch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).DrainSwitch(ctx, req.(*DrainSwitchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_XAddLocalChanAliases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddAliasesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).XAddLocalChanAliases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/XAddLocalChanAliases",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).XAddLocalChanAliases(ctx, req.(*AddAliasesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Router_ServiceDesc is the grpc.ServiceDesc for Router service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -901,6 +1071,14 @@ var Router_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "XImportMissionControl",
 			Handler:    _Router_XImportMissionControl_Handler,
 		},
+		{
+			MethodName: "ExportMissionControl",
+			Handler:    _Router_ExportMissionControl_Handler,
+		},
+		{
+			MethodName: "ImportMissionControl",
+			Handler:    _Router_ImportMissionControl_Handler,
+		},
 		{
 			MethodName: "GetMissionControlConfig",
 			Handler:    _Router_GetMissionControlConfig_Handler,
@@ -921,6 +1099,14 @@ var Router_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateChanStatus",
 			Handler:    _Router_UpdateChanStatus_Handler,
 		},
+		{
+			MethodName: "DrainSwitch",
+			Handler:    _Router_DrainSwitch_Handler,
+		},
+		{
+			MethodName: "XAddLocalChanAliases",
+			Handler:    _Router_XAddLocalChanAliases_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
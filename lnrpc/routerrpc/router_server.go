@@ -1,15 +1,18 @@
 package routerrpc
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"os"
 	"path/filepath"
 	"sync/atomic"
 	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
@@ -36,11 +39,6 @@ const (
 var (
 	errServerShuttingDown = errors.New("routerrpc server shutting down")
 
-	// ErrInterceptorAlreadyExists is an error returned when the a new stream
-	// is opened and there is already one active interceptor.
-	// The user must disconnect prior to open another stream.
-	ErrInterceptorAlreadyExists = errors.New("interceptor already exists")
-
 	// macaroonOps are the set of capabilities that our minted macaroon (if
 	// it doesn't already exist) will have.
 	macaroonOps = []bakery.Op{
@@ -84,6 +82,14 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/routerrpc.Router/ExportMissionControl": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/routerrpc.Router/ImportMissionControl": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/routerrpc.Router/GetMissionControlConfig": {{
 			Entity: "offchain",
 			Action: "read",
@@ -124,6 +130,14 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/routerrpc.Router/DrainSwitch": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/routerrpc.Router/XAddLocalChanAliases": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 	}
 
 	// DefaultRouterMacFilename is the default name of the router macaroon
@@ -142,9 +156,8 @@ type ServerShell struct {
 // Server is a stand alone sub RPC server which exposes functionality that
 // allows clients to route arbitrary payment through the Lightning Network.
 type Server struct {
-	started                  int32 // To be used atomically.
-	shutdown                 int32 // To be used atomically.
-	forwardInterceptorActive int32 // To be used atomically.
+	started  int32 // To be used atomically.
+	shutdown int32 // To be used atomically.
 
 	// Required by the grpc-gateway/v2 library for forward compatibility.
 	// Must be after the atomically used variables to not break struct
@@ -310,12 +323,32 @@ func (s *Server) SendPaymentV2(req *SendPaymentRequest,
 	stream Router_SendPaymentV2Server) error {
 
 	payment, err := s.cfg.RouterBackend.extractIntentFromSendRequest(req)
+	switch {
+	// Rejected self-payments are reported with a dedicated status code, so
+	// that callers can tell them apart from other invalid requests.
+	case errors.Is(err, ErrSelfPayment):
+		return status.Error(codes.FailedPrecondition, err.Error())
+
+	case err != nil:
+		return err
+	}
+
+	// If the macaroon used for this call has a spend limit, we'll count
+	// the maximum amount this payment can cost against it.
+	reservation, err := s.reserveSpend(
+		stream.Context(), payment.Amount+payment.FeeLimit,
+	)
 	if err != nil {
 		return err
 	}
 
 	err = s.cfg.Router.SendPaymentAsync(payment)
 	if err != nil {
+		if err := reservation.Cancel(); err != nil {
+			log.Errorf("Unable to cancel spend reservation: %v",
+				err)
+		}
+
 		// Transform user errors to grpc code.
 		if err == channeldb.ErrPaymentInFlight ||
 			err == channeldb.ErrAlreadyPaid {
@@ -334,7 +367,81 @@ func (s *Server) SendPaymentV2(req *SendPaymentRequest,
 		return err
 	}
 
-	return s.trackPayment(payment.Identifier(), stream, req.NoInflightUpdates)
+	err = s.trackPayment(payment.Identifier(), stream, req.NoInflightUpdates)
+
+	// If the client went away before the payment completed, we'll wait
+	// for its outcome in the background, so that the reservation is
+	// released or settled once the payment fails or succeeds.
+	if err != nil {
+		go s.resolveSpendReservation(reservation, payment.Identifier())
+	} else {
+		s.resolveSpendReservation(reservation, payment.Identifier())
+	}
+
+	return err
+}
+
+// reserveSpend counts the given amount against the spend limits of the
+// macaroon used for the call, if any.
+func (s *Server) reserveSpend(ctx context.Context,
+	amt lnwire.MilliSatoshi) (*macaroons.SpendReservation, error) {
+
+	if s.cfg.MacService == nil {
+		return nil, nil
+	}
+
+	return s.cfg.MacService.ReserveSpend(ctx, amt)
+}
+
+// resolveSpendReservation waits for the outcome of the payment with the given
+// identifier, and then settles or cancels the given spend reservation
+// depending on it. If we shut down while the payment is still in flight, the
+// full amount remains reserved.
+func (s *Server) resolveSpendReservation(
+	reservation *macaroons.SpendReservation, identifier lntypes.Hash) {
+
+	if reservation == nil {
+		return
+	}
+
+	subscription, err := s.cfg.RouterBackend.Tower.SubscribePayment(
+		identifier,
+	)
+	if err != nil {
+		log.Errorf("Unable to subscribe to payment %v to resolve "+
+			"spend reservation: %v", identifier, err)
+		return
+	}
+	defer subscription.Close()
+
+	// The updates are closed after the final update, which carries the
+	// outcome of the payment.
+	var payment *channeldb.MPPayment
+	for payment == nil || payment.Status == channeldb.StatusInFlight {
+		select {
+		case item, ok := <-subscription.Updates:
+			if !ok {
+				return
+			}
+			payment = item.(*channeldb.MPPayment)
+
+		case <-s.quit:
+			return
+		}
+	}
+
+	switch payment.Status {
+	case channeldb.StatusSucceeded:
+		amt, fees := payment.SentAmt()
+		err = reservation.Settle(amt + fees)
+
+	case channeldb.StatusFailed:
+		err = reservation.Cancel()
+	}
+	if err != nil {
+		log.Errorf("Unable to resolve spend reservation of payment "+
+			"%v: %v", identifier, err)
+	}
 }
 
 // EstimateRouteFee allows callers to obtain a lower bound w.r.t how much it
@@ -399,6 +506,18 @@ func (s *Server) SendToRouteV2(ctx context.Context,
 		return nil, err
 	}
 
+	customOnion, err := unmarshallCustomOnion(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// If the macaroon used for this call has a spend limit, we'll count
+	// the amount of the route against it.
+	reservation, err := s.reserveSpend(ctx, route.TotalAmount)
+	if err != nil {
+		return nil, err
+	}
+
 	var attempt *channeldb.HTLCAttempt
 
 	// Pass route to the router. This call returns the full htlc attempt
@@ -407,11 +526,28 @@ func (s *Server) SendToRouteV2(ctx context.Context,
 	// the attempt was already initiated before the error happened. In that
 	// case, we give precedence to the attempt information as stored in the
 	// db.
-	if req.SkipTempErr {
+	switch {
+	case customOnion != nil:
+		attempt, err = s.cfg.Router.SendToRouteCustomOnion(
+			hash, route, req.SkipTempErr, customOnion,
+		)
+
+	case req.SkipTempErr:
 		attempt, err = s.cfg.Router.SendToRouteSkipTempErr(hash, route)
-	} else {
+
+	default:
 		attempt, err = s.cfg.Router.SendToRoute(hash, route)
 	}
+
+	// The reserved amount is only released if the htlc wasn't sent or
+	// failed.
+	if attempt == nil || attempt.Failure != nil {
+		if err := reservation.Cancel(); err != nil {
+			log.Errorf("Unable to cancel spend reservation: %v",
+				err)
+		}
+	}
+
 	if attempt != nil {
 		rpcAttempt, err := s.cfg.RouterBackend.MarshalHTLCAttempt(
 			*attempt,
@@ -432,12 +568,59 @@ func (s *Server) SendToRouteV2(ctx context.Context,
 	return nil, err
 }
 
+// unmarshallCustomOnion returns the caller provided sphinx parameters of a
+// SendToRoute request, or nil if the request doesn't set any.
+func unmarshallCustomOnion(
+	req *SendToRouteRequest) (*routing.CustomOnion, error) {
+
+	if len(req.SessionKey) == 0 && len(req.OnionBlob) == 0 {
+		return nil, nil
+	}
+
+	customOnion := &routing.CustomOnion{}
+	if len(req.SessionKey) != 0 {
+		if len(req.SessionKey) != btcec.PrivKeyBytesLen {
+			return nil, fmt.Errorf("session key must be %d bytes",
+				btcec.PrivKeyBytesLen)
+		}
+
+		customOnion.SessionKey, _ = btcec.PrivKeyFromBytes(
+			req.SessionKey,
+		)
+	}
+
+	if len(req.OnionBlob) != 0 {
+		if customOnion.SessionKey == nil {
+			return nil, errors.New("onion blob requires the " +
+				"session key it was constructed with")
+		}
+		if len(req.OnionBlob) != lnwire.OnionPacketSize {
+			return nil, fmt.Errorf("onion blob must be %d bytes",
+				lnwire.OnionPacketSize)
+		}
+
+		customOnion.OnionBlob = req.OnionBlob
+	}
+
+	return customOnion, nil
+}
+
 // ResetMissionControl clears all mission control state and starts with a clean
-// slate.
+// slate. If a session is specified, only that isolated mission control session
+// is discarded.
 func (s *Server) ResetMissionControl(ctx context.Context,
 	req *ResetMissionControlRequest) (*ResetMissionControlResponse, error) {
 
-	err := s.cfg.RouterBackend.MissionControl.ResetHistory()
+	mc := s.cfg.RouterBackend.MissionControl
+	if req.Session != "" {
+		if err := mc.EndSession(req.Session); err != nil {
+			return nil, err
+		}
+
+		return &ResetMissionControlResponse{}, nil
+	}
+
+	err := mc.ResetHistory()
 	if err != nil {
 		return nil, err
 	}
@@ -450,39 +633,135 @@ func (s *Server) GetMissionControlConfig(ctx context.Context,
 	req *GetMissionControlConfigRequest) (*GetMissionControlConfigResponse,
 	error) {
 
+	// Query the current mission control config.
 	cfg := s.cfg.RouterBackend.MissionControl.GetConfig()
-	return &GetMissionControlConfigResponse{
+	resp := &GetMissionControlConfigResponse{
 		Config: &MissionControlConfig{
-			HalfLifeSeconds:             uint64(cfg.PenaltyHalfLife.Seconds()),
-			HopProbability:              float32(cfg.AprioriHopProbability),
-			Weight:                      float32(cfg.AprioriWeight),
 			MaximumPaymentResults:       uint32(cfg.MaxMcHistory),
 			MinimumFailureRelaxInterval: uint64(cfg.MinFailureRelaxInterval.Seconds()),
 		},
-	}, nil
+	}
+
+	// We only populate fields based on the current estimator.
+	switch v := cfg.Estimator.Config().(type) {
+	case routing.AprioriConfig:
+		resp.Config.Model = MissionControlConfig_APRIORI
+		aCfg := AprioriParameters{
+			HalfLifeSeconds: uint64(v.PenaltyHalfLife.Seconds()),
+			HopProbability:  v.AprioriHopProbability,
+			Weight:          v.AprioriWeight,
+		}
+
+		// Populate deprecated fields.
+		resp.Config.HalfLifeSeconds = uint64(
+			v.PenaltyHalfLife.Seconds(),
+		)
+		resp.Config.HopProbability = float32(v.AprioriHopProbability)
+		resp.Config.Weight = float32(v.AprioriWeight)
+
+		resp.Config.EstimatorConfig = &MissionControlConfig_Apriori{
+			Apriori: &aCfg,
+		}
+
+	case routing.BimodalConfig:
+		resp.Config.Model = MissionControlConfig_BIMODAL
+		bCfg := BimodalParameters{
+			NodeWeight: v.BimodalNodeWeight,
+			ScaleMsat:  uint64(v.BimodalScaleMsat),
+			DecayTime:  uint64(v.BimodalDecayTime.Seconds()),
+		}
+
+		resp.Config.EstimatorConfig = &MissionControlConfig_Bimodal{
+			Bimodal: &bCfg,
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown estimator config type %T", v)
+	}
+
+	return resp, nil
 }
 
-// SetMissionControlConfig returns our current mission control config.
+// SetMissionControlConfig sets parameters in the mission control config.
 func (s *Server) SetMissionControlConfig(ctx context.Context,
 	req *SetMissionControlConfigRequest) (*SetMissionControlConfigResponse,
 	error) {
 
-	cfg := &routing.MissionControlConfig{
-		ProbabilityEstimatorCfg: routing.ProbabilityEstimatorCfg{
-			PenaltyHalfLife: time.Duration(
-				req.Config.HalfLifeSeconds,
-			) * time.Second,
-			AprioriHopProbability: float64(req.Config.HopProbability),
-			AprioriWeight:         float64(req.Config.Weight),
-		},
+	mcCfg := &routing.MissionControlConfig{
 		MaxMcHistory: int(req.Config.MaximumPaymentResults),
 		MinFailureRelaxInterval: time.Duration(
 			req.Config.MinimumFailureRelaxInterval,
 		) * time.Second,
 	}
 
+	switch req.Config.Model {
+	case MissionControlConfig_APRIORI:
+		var aprioriConfig routing.AprioriConfig
+
+		// Determine the apriori config with backward compatibility
+		// should the api use deprecated fields.
+		switch v := req.Config.EstimatorConfig.(type) {
+		case *MissionControlConfig_Apriori:
+			aprioriConfig = routing.AprioriConfig{
+				PenaltyHalfLife: time.Duration(
+					v.Apriori.HalfLifeSeconds,
+				) * time.Second,
+				AprioriHopProbability: v.Apriori.HopProbability,
+				AprioriWeight:         v.Apriori.Weight,
+			}
+
+		// Use deprecated fields if no config is given.
+		case nil:
+			aprioriConfig = routing.AprioriConfig{
+				PenaltyHalfLife: time.Duration(
+					req.Config.HalfLifeSeconds,
+				) * time.Second,
+				AprioriHopProbability: float64(
+					req.Config.HopProbability,
+				),
+				AprioriWeight: float64(req.Config.Weight),
+			}
+
+		default:
+			return nil, fmt.Errorf("apriori config wrong type %T", v)
+		}
+
+		estimator, err := routing.NewAprioriEstimator(aprioriConfig)
+		if err != nil {
+			return nil, err
+		}
+		mcCfg.Estimator = estimator
+
+	case MissionControlConfig_BIMODAL:
+		cfg, ok := req.Config.
+			EstimatorConfig.(*MissionControlConfig_Bimodal)
+		if !ok {
+			return nil, fmt.Errorf("bimodal estimator requested " +
+				"but corresponding config not set")
+		}
+		bCfg := cfg.Bimodal
+
+		bimodalConfig := routing.BimodalConfig{
+			BimodalDecayTime: time.Duration(
+				bCfg.DecayTime,
+			) * time.Second,
+			BimodalScaleMsat:  lnwire.MilliSatoshi(bCfg.ScaleMsat),
+			BimodalNodeWeight: bCfg.NodeWeight,
+		}
+
+		estimator, err := routing.NewBimodalEstimator(bimodalConfig)
+		if err != nil {
+			return nil, err
+		}
+		mcCfg.Estimator = estimator
+
+	default:
+		return nil, fmt.Errorf("unknown estimator type %v",
+			req.Config.Model)
+	}
+
 	return &SetMissionControlConfigResponse{},
-		s.cfg.RouterBackend.MissionControl.SetConfig(cfg)
+		s.cfg.RouterBackend.MissionControl.SetConfig(mcCfg)
 }
 
 // QueryMissionControl exposes the internal mission control state to callers. It
@@ -490,7 +769,16 @@ func (s *Server) SetMissionControlConfig(ctx context.Context,
 func (s *Server) QueryMissionControl(ctx context.Context,
 	req *QueryMissionControlRequest) (*QueryMissionControlResponse, error) {
 
-	snapshot := s.cfg.RouterBackend.MissionControl.GetHistorySnapshot()
+	var mc MissionControl = s.cfg.RouterBackend.MissionControl
+	if req.Session != "" {
+		session, err := mc.LookupSession(req.Session)
+		if err != nil {
+			return nil, err
+		}
+		mc = session
+	}
+
+	snapshot := mc.GetHistorySnapshot()
 
 	rpcPairs := make([]*PairHistory, 0, len(snapshot.Pairs))
 	for _, p := range snapshot.Pairs {
@@ -568,6 +856,66 @@ func (s *Server) XImportMissionControl(ctx context.Context,
 	return &XImportMissionControlResponse{}, nil
 }
 
+// ExportMissionControl exports our mission control state, or the state of
+// the given session, in the versioned export format.
+func (s *Server) ExportMissionControl(ctx context.Context,
+	req *ExportMissionControlRequest) (*ExportMissionControlResponse,
+	error) {
+
+	var mc MissionControl = s.cfg.RouterBackend.MissionControl
+	if req.Session != "" {
+		session, err := mc.LookupSession(req.Session)
+		if err != nil {
+			return nil, err
+		}
+		mc = session
+	}
+
+	var b bytes.Buffer
+	numPairs, err := mc.ExportHistory(&b, req.Anonymize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExportMissionControlResponse{
+		Data:     b.Bytes(),
+		Version:  uint32(routing.MissionControlExportVersion),
+		NumPairs: uint32(numPairs),
+	}, nil
+}
+
+// ImportMissionControl imports mission control state in the versioned export
+// format into our mission control, or into the given session. Only entries
+// that are fresher than our existing state will be used, unless force is set.
+func (s *Server) ImportMissionControl(ctx context.Context,
+	req *ImportMissionControlRequest) (*ImportMissionControlResponse,
+	error) {
+
+	if len(req.Data) == 0 {
+		return nil, errors.New("no mission control data to import")
+	}
+
+	var mc MissionControl = s.cfg.RouterBackend.MissionControl
+	if req.Session != "" {
+		session, err := mc.Session(req.Session)
+		if err != nil {
+			return nil, err
+		}
+		mc = session
+	}
+
+	numResults, err := mc.ImportExportedHistory(
+		bytes.NewReader(req.Data), req.Force,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ImportMissionControlResponse{
+		NumResults: uint32(numResults),
+	}, nil
+}
+
 func toPairSnapshot(pairResult *PairHistory) (*routing.MissionControlPairSnapshot,
 	error) {
 
@@ -705,8 +1053,22 @@ func (s *Server) QueryProbability(ctx context.Context,
 
 	amt := lnwire.MilliSatoshi(req.AmtMsat)
 
+	// Compute the probability. We use the max channel capacity that we
+	// have between the two nodes for the amount. If the capacity can't be
+	// determined, a zero capacity is passed, which lets the estimator
+	// make an assumption about it.
+	capacity, err := s.cfg.RouterBackend.FetchAmountPairCapacity(
+		fromNode, toNode, amt,
+	)
+	if err != nil {
+		log.Debugf("Unable to determine capacity between %v and %v "+
+			"for %v: %v", fromNode, toNode, amt, err)
+
+		capacity = 0
+	}
+
 	mc := s.cfg.RouterBackend.MissionControl
-	prob := mc.GetProbability(fromNode, toNode, amt)
+	prob := mc.GetProbability(fromNode, toNode, amt, capacity)
 	history := mc.GetPairHistorySnapshot(fromNode, toNode)
 
 	return &QueryProbabilityResponse{
@@ -821,9 +1183,15 @@ func (s *Server) BuildRoute(ctx context.Context,
 		payAddr = &backingPayAddr
 	}
 
+	overrides, err := unmarshallHopPolicyOverrides(req.HopPolicyOverrides)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build the route and return it to the caller.
 	route, err := s.cfg.Router.BuildRoute(
 		amt, hops, outgoingChan, req.FinalCltvDelta, payAddr,
+		overrides,
 	)
 	if err != nil {
 		return nil, err
@@ -841,17 +1209,80 @@ func (s *Server) BuildRoute(ctx context.Context,
 	return routeResp, nil
 }
 
+// unmarshallHopPolicyOverrides converts the policy overrides of a BuildRoute
+// request into a map keyed by hop index.
+func unmarshallHopPolicyOverrides(
+	rpcOverrides []*HopPolicyOverride) (map[int]*routing.HopPolicyOverride,
+	error) {
+
+	overrides := make(map[int]*routing.HopPolicyOverride)
+	for _, rpcOverride := range rpcOverrides {
+		idx := int(rpcOverride.HopIndex)
+		if _, ok := overrides[idx]; ok {
+			return nil, fmt.Errorf("duplicate policy override "+
+				"for hop %d", idx)
+		}
+
+		if rpcOverride.FeeBaseMsat < 0 || rpcOverride.FeeRatePpm < 0 {
+			return nil, fmt.Errorf("negative fee in policy "+
+				"override for hop %d", idx)
+		}
+
+		if rpcOverride.TimeLockDelta > math.MaxUint16 {
+			return nil, fmt.Errorf("time lock delta %d of policy "+
+				"override for hop %d exceeds maximum of %d",
+				rpcOverride.TimeLockDelta, idx, math.MaxUint16)
+		}
+
+		overrides[idx] = &routing.HopPolicyOverride{
+			ChannelID: rpcOverride.ChanId,
+			FeeBaseMSat: lnwire.MilliSatoshi(
+				rpcOverride.FeeBaseMsat,
+			),
+			FeeProportionalMillionths: lnwire.MilliSatoshi(
+				rpcOverride.FeeRatePpm,
+			),
+			TimeLockDelta: uint16(rpcOverride.TimeLockDelta),
+		}
+	}
+
+	return overrides, nil
+}
+
 // SubscribeHtlcEvents creates a uni-directional stream from the server to
 // the client which delivers a stream of htlc events.
 func (s *Server) SubscribeHtlcEvents(req *SubscribeHtlcEventsRequest,
 	stream Router_SubscribeHtlcEventsServer) error {
 
+	filter, err := newHtlcEventFilter(req, s.cfg.RouterBackend)
+	if err != nil {
+		return err
+	}
+
 	htlcClient, err := s.cfg.RouterBackend.SubscribeHtlcEvents()
 	if err != nil {
 		return err
 	}
 	defer htlcClient.Cancel()
 
+	// If an aggregation interval is set, we count the matching events
+	// and only send their counts once per interval.
+	var (
+		aggregate      *HtlcEventAggregate
+		aggregateTicks <-chan time.Time
+	)
+	if req.AggregationIntervalSec > 0 {
+		interval := time.Duration(req.AggregationIntervalSec) *
+			time.Second
+		aggregateTicker := time.NewTicker(interval)
+		defer aggregateTicker.Stop()
+
+		aggregateTicks = aggregateTicker.C
+		aggregate = &HtlcEventAggregate{
+			StartTimestampNs: uint64(time.Now().UnixNano()),
+		}
+	}
+
 	for {
 		select {
 		case event := <-htlcClient.Updates():
@@ -860,10 +1291,35 @@ func (s *Server) SubscribeHtlcEvents(req *SubscribeHtlcEventsRequest,
 				return err
 			}
 
+			if !filter.matches(rpcEvent) {
+				continue
+			}
+
+			if aggregate != nil {
+				addToAggregate(aggregate, rpcEvent)
+				continue
+			}
+
 			if err := stream.Send(rpcEvent); err != nil {
 				return err
 			}
 
+		case now := <-aggregateTicks:
+			endTimestamp := uint64(now.UnixNano())
+			err := stream.Send(&HtlcEvent{
+				TimestampNs: endTimestamp,
+				Event: &HtlcEvent_AggregateEvent{
+					AggregateEvent: aggregate,
+				},
+			})
+			if err != nil {
+				return err
+			}
+
+			aggregate = &HtlcEventAggregate{
+				StartTimestampNs: endTimestamp,
+			}
+
 		// If the stream's context is cancelled, return an error.
 		case <-stream.Context().Done():
 			log.Debugf("htlc event stream cancelled")
@@ -883,18 +1339,12 @@ func (s *Server) SubscribeHtlcEvents(req *SubscribeHtlcEventsRequest,
 // HtlcInterceptor is a bidirectional stream for streaming interception
 // requests to the caller.
 // Upon connection it does the following:
-// 1. Check if there is already a live stream, if yes it rejects the request.
-// 2. Registered a ForwardInterceptor
-// 3. Delivers to the caller every √√ and detect his answer.
+// 1. Registered a ForwardInterceptor with the priority and health timeout
+// the caller registers, the stream with the lowest priority is active.
+// 2. Delivers to the caller every √√ and detect his answer.
 // It uses a local implementation of holdForwardsStore to keep all the hold
 // forwards and find them when manual resolution is later needed.
 func (s *Server) HtlcInterceptor(stream Router_HtlcInterceptorServer) error {
-	// We ensure there is only one interceptor at a time.
-	if !atomic.CompareAndSwapInt32(&s.forwardInterceptorActive, 0, 1) {
-		return ErrInterceptorAlreadyExists
-	}
-	defer atomic.CompareAndSwapInt32(&s.forwardInterceptorActive, 1, 0)
-
 	// run the forward interceptor.
 	return newForwardInterceptor(
 		s.cfg.RouterBackend.InterceptableForwarder, stream,
@@ -941,3 +1391,103 @@ func (s *Server) UpdateChanStatus(ctx context.Context,
 	}
 	return &UpdateChanStatusResponse{}, nil
 }
+
+// drainPollInterval is the interval at which the number of HTLCs in flight is
+// checked while waiting for the switch to be drained.
+var drainPollInterval = time.Second
+
+// DrainSwitch puts the switch into or takes it out of drain mode and reports
+// the number of HTLCs in flight. If requested, it blocks until the switch is
+// drained.
+func (s *Server) DrainSwitch(ctx context.Context,
+	req *DrainSwitchRequest) (*DrainSwitchResponse, error) {
+
+	drainer := s.cfg.RouterBackend.SwitchDrainer
+
+	log.Debugf("DrainSwitch called with stop=%v, wait=%v", req.Stop,
+		req.Wait)
+
+	drainer.SetDraining(!req.Stop)
+
+	if !req.Stop && req.Wait {
+		ticker := time.NewTicker(drainPollInterval)
+		defer ticker.Stop()
+
+		for drainer.NumInFlight() > 0 {
+			select {
+			case <-ticker.C:
+
+			case <-ctx.Done():
+				return nil, ctx.Err()
+
+			case <-s.quit:
+				return nil, errServerShuttingDown
+			}
+
+			// Bail out if drain mode was left by another call in
+			// the meantime, as the switch would never be empty.
+			if !drainer.IsDraining() {
+				return nil, errors.New("drain mode was left " +
+					"while waiting for the switch to drain")
+			}
+		}
+	}
+
+	numInFlight := drainer.NumInFlight()
+	draining := drainer.IsDraining()
+
+	return &DrainSwitchResponse{
+		Draining:    draining,
+		NumInFlight: uint32(numInFlight),
+		Drained:     draining && numInFlight == 0,
+	}, nil
+}
+
+// XAddLocalChanAliases adds the given aliases to the open channels with the
+// given base SCIDs and returns the resulting set of aliases of each of them.
+// The aliases are added one by one, so the ones added before an error remain
+// in place.
+func (s *Server) XAddLocalChanAliases(_ context.Context,
+	req *AddAliasesRequest) (*AddAliasesResponse, error) {
+
+	if len(req.AliasMaps) == 0 {
+		return nil, errors.New("at least one alias map required")
+	}
+
+	for _, aliasMap := range req.AliasMaps {
+		if len(aliasMap.Aliases) == 0 {
+			return nil, fmt.Errorf("no aliases given for base "+
+				"SCID %v", aliasMap.BaseScid)
+		}
+	}
+
+	backend := s.cfg.RouterBackend
+
+	resp := &AddAliasesResponse{}
+	for _, aliasMap := range req.AliasMaps {
+		baseScid := lnwire.NewShortChanIDFromInt(aliasMap.BaseScid)
+
+		for _, rpcAlias := range aliasMap.Aliases {
+			alias := lnwire.NewShortChanIDFromInt(rpcAlias)
+
+			err := backend.AddLocalAlias(alias, baseScid)
+			if err != nil {
+				return nil, fmt.Errorf("unable to add alias "+
+					"%v to channel %v: %w", alias,
+					baseScid, err)
+			}
+		}
+
+		rpcMap := &lnrpc.AliasMap{
+			BaseScid: aliasMap.BaseScid,
+		}
+		for _, alias := range backend.GetAliases(baseScid) {
+			rpcMap.Aliases = append(
+				rpcMap.Aliases, alias.ToUint64(),
+			)
+		}
+		resp.AliasMaps = append(resp.AliasMaps, rpcMap)
+	}
+
+	return resp, nil
+}
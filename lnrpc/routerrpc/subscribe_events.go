@@ -7,6 +7,7 @@ import (
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/invoices"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/routing/route"
 )
 
 // rpcHtlcEvent returns a rpc htlc event from a htlcswitch event.
@@ -238,8 +239,139 @@ func rpcOutgoingFailure(failureDetail htlcswitch.OutgoingFailure) (
 	case htlcswitch.OutgoingFailureForwardsDisabled:
 		return FailureDetail_FORWARDS_DISABLED, nil
 
+	case htlcswitch.OutgoingFailureSwitchDraining:
+		return FailureDetail_SWITCH_DRAINING, nil
+
 	default:
 		return 0, fmt.Errorf("unknown outgoing failure "+
 			"detail: %v", failureDetail.FailureString())
 	}
 }
+
+// htlcEventFilter decides which htlc events are streamed to a subscriber of
+// SubscribeHtlcEvents.
+type htlcEventFilter struct {
+	eventTypes map[HtlcEvent_EventType]struct{}
+	chanIDs    map[uint64]struct{}
+	peers      map[route.Vertex]struct{}
+
+	// selfNode is our own node, which is one of the endpoints of every
+	// channel an htlc event refers to.
+	selfNode route.Vertex
+
+	// fetchChannelEndpoints returns the pubkeys of both endpoints of the
+	// given channel.
+	fetchChannelEndpoints func(chanID uint64) (route.Vertex,
+		route.Vertex, error)
+
+	// chanPeers caches the peer of every channel that was looked up, so
+	// the graph is only queried once per channel.
+	chanPeers map[uint64]route.Vertex
+}
+
+// newHtlcEventFilter creates a filter from the subscription request.
+func newHtlcEventFilter(req *SubscribeHtlcEventsRequest,
+	backend *RouterBackend) (*htlcEventFilter, error) {
+
+	filter := &htlcEventFilter{
+		eventTypes:            make(map[HtlcEvent_EventType]struct{}),
+		chanIDs:               make(map[uint64]struct{}),
+		peers:                 make(map[route.Vertex]struct{}),
+		selfNode:              backend.SelfNode,
+		fetchChannelEndpoints: backend.FetchChannelEndpoints,
+		chanPeers:             make(map[uint64]route.Vertex),
+	}
+
+	for _, eventType := range req.EventTypes {
+		filter.eventTypes[eventType] = struct{}{}
+	}
+
+	for _, chanID := range req.ChanIds {
+		filter.chanIDs[chanID] = struct{}{}
+	}
+
+	for _, peer := range req.Peers {
+		vertex, err := route.NewVertexFromBytes(peer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer: %v", err)
+		}
+
+		filter.peers[vertex] = struct{}{}
+	}
+
+	return filter, nil
+}
+
+// matches returns true if the event passes all filters. Within each filter,
+// the event only has to match one of the listed values.
+func (f *htlcEventFilter) matches(event *HtlcEvent) bool {
+	if len(f.eventTypes) > 0 {
+		if _, ok := f.eventTypes[event.EventType]; !ok {
+			return false
+		}
+	}
+
+	if len(f.chanIDs) > 0 {
+		_, incoming := f.chanIDs[event.IncomingChannelId]
+		_, outgoing := f.chanIDs[event.OutgoingChannelId]
+		if !incoming && !outgoing {
+			return false
+		}
+	}
+
+	if len(f.peers) > 0 {
+		if !f.peerMatches(event.IncomingChannelId) &&
+			!f.peerMatches(event.OutgoingChannelId) {
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// peerMatches returns true if the given channel is with one of the peers of
+// the filter.
+func (f *htlcEventFilter) peerMatches(chanID uint64) bool {
+	// A zero channel id denotes that our node is the sender or receiver.
+	if chanID == 0 {
+		return false
+	}
+
+	peer, ok := f.chanPeers[chanID]
+	if !ok {
+		node1, node2, err := f.fetchChannelEndpoints(chanID)
+		if err != nil {
+			log.Debugf("Unable to fetch endpoints of channel %v "+
+				"for htlc event filter: %v", chanID, err)
+
+			return false
+		}
+
+		peer = node1
+		if node1 == f.selfNode {
+			peer = node2
+		}
+		f.chanPeers[chanID] = peer
+	}
+
+	_, ok = f.peers[peer]
+	return ok
+}
+
+// addToAggregate counts the event in the aggregate of its kind.
+func addToAggregate(aggregate *HtlcEventAggregate, event *HtlcEvent) {
+	switch event.Event.(type) {
+	case *HtlcEvent_ForwardEvent:
+		aggregate.ForwardEvents++
+
+	case *HtlcEvent_ForwardFailEvent:
+		aggregate.ForwardFailEvents++
+
+	case *HtlcEvent_SettleEvent:
+		aggregate.SettleEvents++
+
+	case *HtlcEvent_LinkFailEvent:
+		aggregate.LinkFailEvents++
+	}
+}
@@ -0,0 +1,146 @@
+package routerrpc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHtlcEventFilter tests that the htlc event filter only matches events of
+// the requested types, channels and peers.
+func TestHtlcEventFilter(t *testing.T) {
+	t.Parallel()
+
+	var (
+		self  = route.Vertex{1}
+		peerA = route.Vertex{2}
+		peerB = route.Vertex{3}
+
+		// Channel 10 is with peer A, channel 20 with peer B.
+		chanPeers = map[uint64]route.Vertex{10: peerA, 20: peerB}
+	)
+
+	backend := &RouterBackend{
+		SelfNode: self,
+		FetchChannelEndpoints: func(chanID uint64) (route.Vertex,
+			route.Vertex, error) {
+
+			peer, ok := chanPeers[chanID]
+			if !ok {
+				return route.Vertex{}, route.Vertex{},
+					errors.New("unknown channel")
+			}
+
+			return self, peer, nil
+		},
+	}
+
+	forward := &HtlcEvent{
+		IncomingChannelId: 10,
+		OutgoingChannelId: 20,
+		EventType:         HtlcEvent_FORWARD,
+	}
+	send := &HtlcEvent{
+		OutgoingChannelId: 10,
+		EventType:         HtlcEvent_SEND,
+	}
+	receive := &HtlcEvent{
+		IncomingChannelId: 20,
+		EventType:         HtlcEvent_RECEIVE,
+	}
+	unknownChan := &HtlcEvent{
+		IncomingChannelId: 30,
+		EventType:         HtlcEvent_RECEIVE,
+	}
+
+	testCases := []struct {
+		name    string
+		req     *SubscribeHtlcEventsRequest
+		matches []*HtlcEvent
+	}{{
+		name:    "no filter",
+		req:     &SubscribeHtlcEventsRequest{},
+		matches: []*HtlcEvent{forward, send, receive, unknownChan},
+	}, {
+		name: "event types",
+		req: &SubscribeHtlcEventsRequest{
+			EventTypes: []HtlcEvent_EventType{
+				HtlcEvent_SEND, HtlcEvent_RECEIVE,
+			},
+		},
+		matches: []*HtlcEvent{send, receive, unknownChan},
+	}, {
+		name: "channels",
+		req: &SubscribeHtlcEventsRequest{
+			ChanIds: []uint64{10},
+		},
+		matches: []*HtlcEvent{forward, send},
+	}, {
+		name: "peers",
+		req: &SubscribeHtlcEventsRequest{
+			Peers: [][]byte{peerB[:]},
+		},
+		matches: []*HtlcEvent{forward, receive},
+	}, {
+		name: "combined",
+		req: &SubscribeHtlcEventsRequest{
+			EventTypes: []HtlcEvent_EventType{HtlcEvent_FORWARD},
+			Peers:      [][]byte{peerA[:]},
+		},
+		matches: []*HtlcEvent{forward},
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := newHtlcEventFilter(tc.req, backend)
+			require.NoError(t, err)
+
+			var matches []*HtlcEvent
+			for _, event := range []*HtlcEvent{
+				forward, send, receive, unknownChan,
+			} {
+				if filter.matches(event) {
+					matches = append(matches, event)
+				}
+			}
+			require.Equal(t, tc.matches, matches)
+		})
+	}
+
+	// An invalid peer is rejected.
+	_, err := newHtlcEventFilter(&SubscribeHtlcEventsRequest{
+		Peers: [][]byte{{1, 2, 3}},
+	}, backend)
+	require.Error(t, err)
+}
+
+// TestAddToAggregate tests that events are counted by their kind.
+func TestAddToAggregate(t *testing.T) {
+	t.Parallel()
+
+	events := []*HtlcEvent{
+		{Event: &HtlcEvent_ForwardEvent{}},
+		{Event: &HtlcEvent_ForwardEvent{}},
+		{Event: &HtlcEvent_ForwardFailEvent{}},
+		{Event: &HtlcEvent_SettleEvent{}},
+		{Event: &HtlcEvent_LinkFailEvent{}},
+		{Event: &HtlcEvent_LinkFailEvent{}},
+		{Event: &HtlcEvent_LinkFailEvent{}},
+	}
+
+	aggregate := &HtlcEventAggregate{}
+	for _, event := range events {
+		addToAggregate(aggregate, event)
+	}
+
+	require.EqualValues(t, 2, aggregate.ForwardEvents)
+	require.EqualValues(t, 1, aggregate.ForwardFailEvents)
+	require.EqualValues(t, 1, aggregate.SettleEvents)
+	require.EqualValues(t, 3, aggregate.LinkFailEvents)
+}
@@ -5,6 +5,7 @@ package walletrpc
 
 import (
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
@@ -19,6 +20,13 @@ const (
 	SubServerName = "WalletKitRPC"
 )
 
+// OutputLabelStore stores the labels of the outputs of our transactions.
+type OutputLabelStore interface {
+	// PutOutputLabels stores the labels of the outputs of the given,
+	// possibly unsigned, transaction keyed by the index of the output.
+	PutOutputLabels(tx *wire.MsgTx, labels map[uint32]string) error
+}
+
 // Config is the primary configuration struct for the WalletKit RPC server. It
 // contains all the items required for the signer rpc server to carry out its
 // duties. The fields with struct tags are meant to be parsed as normal
@@ -71,4 +79,8 @@ type Config struct {
 	// CurrentNumAnchorChans returns the current number of non-private
 	// anchor channels the wallet should be ready to fee bump if needed.
 	CurrentNumAnchorChans func() (int, error)
+
+	// OutputLabels is where the labels of the outputs created with
+	// SendOutputs are stored.
+	OutputLabels OutputLabelStore
 }
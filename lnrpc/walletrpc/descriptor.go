@@ -0,0 +1,745 @@
+package walletrpc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/lightningnetwork/lnd/input"
+)
+
+const (
+	// descriptorChecksumLength is the number of characters of a
+	// descriptor checksum.
+	descriptorChecksumLength = 8
+
+	// descriptorInputCharset is the set of characters that can be used in
+	// a descriptor, ordered as required by the checksum algorithm.
+	descriptorInputCharset = "0123456789()[],'/*abcdefgh@:$%{}" +
+		"IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~" +
+		"ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+
+	// descriptorChecksumCharset is the set of characters used to encode
+	// the descriptor checksum.
+	descriptorChecksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	// externalBranch is the index of the branch of an account that
+	// external addresses are derived from.
+	externalBranch = 0
+
+	// internalBranch is the index of the branch of an account that change
+	// addresses are derived from.
+	internalBranch = 1
+
+	// defaultDescriptorRescanRange is the default number of addresses that
+	// are derived on each branch of a ranged descriptor before rescanning.
+	// It matches the default range of bitcoind's descriptor wallets.
+	defaultDescriptorRescanRange = 1000
+)
+
+var (
+	// descriptorChecksumGenerator holds the generator constants of the
+	// descriptor checksum, as defined in BIP-0380.
+	descriptorChecksumGenerator = [5]uint64{
+		0xf5dee51989, 0xa9fdca3312, 0x1bab10e32d, 0x3706b1677a,
+		0x644d626ffd,
+	}
+
+	// ErrUnsupportedDescriptor is returned if a descriptor is valid, but
+	// describes scripts that can't be watched by the wallet.
+	ErrUnsupportedDescriptor = errors.New("unsupported descriptor")
+)
+
+// descriptorChecksum computes the BIP-0380 checksum of a descriptor without
+// checksum.
+func descriptorChecksum(desc string) (string, error) {
+	polyMod := func(c uint64, val int) uint64 {
+		c0 := c >> 35
+		c = ((c & 0x7ffffffff) << 5) ^ uint64(val)
+		for i, gen := range descriptorChecksumGenerator {
+			if (c0>>i)&1 == 1 {
+				c ^= gen
+			}
+		}
+
+		return c
+	}
+
+	var (
+		c        uint64 = 1
+		cls      int
+		clsCount int
+	)
+	for i, ch := range desc {
+		pos := strings.IndexRune(descriptorInputCharset, ch)
+		if pos < 0 {
+			return "", fmt.Errorf("invalid character %q at "+
+				"position %d", ch, i)
+		}
+
+		// Emit a symbol for the position inside the group, for every
+		// character.
+		c = polyMod(c, pos&31)
+
+		// Accumulate the group numbers.
+		cls = cls*3 + pos>>5
+		clsCount++
+		if clsCount == 3 {
+			// Emit an extra symbol representing the group numbers,
+			// for every 3 characters.
+			c = polyMod(c, cls)
+			cls = 0
+			clsCount = 0
+		}
+	}
+	if clsCount > 0 {
+		c = polyMod(c, cls)
+	}
+
+	// Shift further to determine the checksum.
+	for i := 0; i < descriptorChecksumLength; i++ {
+		c = polyMod(c, 0)
+	}
+
+	// Prevent appending zeroes from not affecting the checksum.
+	c ^= 1
+
+	var checksum [descriptorChecksumLength]byte
+	for i := range checksum {
+		checksum[i] = descriptorChecksumCharset[(c>>(5*(7-i)))&31]
+	}
+
+	return string(checksum[:]), nil
+}
+
+// addDescriptorChecksum appends the checksum to a descriptor without
+// checksum.
+func addDescriptorChecksum(desc string) (string, error) {
+	checksum, err := descriptorChecksum(desc)
+	if err != nil {
+		return "", err
+	}
+
+	return desc + "#" + checksum, nil
+}
+
+// stripDescriptorChecksum removes the checksum from a descriptor, if present,
+// after verifying it.
+func stripDescriptorChecksum(desc string) (string, error) {
+	idx := strings.LastIndexByte(desc, '#')
+	if idx < 0 {
+		return desc, nil
+	}
+
+	desc, checksum := desc[:idx], desc[idx+1:]
+	if len(checksum) != descriptorChecksumLength {
+		return "", fmt.Errorf("expected %d character checksum, got %d",
+			descriptorChecksumLength, len(checksum))
+	}
+
+	expected, err := descriptorChecksum(desc)
+	if err != nil {
+		return "", err
+	}
+	if checksum != expected {
+		return "", fmt.Errorf("invalid descriptor checksum %v, "+
+			"expected %v", checksum, expected)
+	}
+
+	return desc, nil
+}
+
+// descriptorScript is the type of output script described by a descriptor.
+type descriptorScript uint8
+
+const (
+	// descriptorWPKH describes pay-to-witness-pubkey-hash outputs,
+	// wpkh(KEY).
+	descriptorWPKH descriptorScript = iota
+
+	// descriptorSHWPKH describes nested pay-to-witness-pubkey-hash
+	// outputs, sh(wpkh(KEY)).
+	descriptorSHWPKH
+
+	// descriptorTR describes pay-to-taproot outputs, either key spend
+	// only, tr(KEY), or with a single multisig script leaf,
+	// tr(KEY,multi_a(...)) or tr(KEY,sortedmulti_a(...)).
+	descriptorTR
+)
+
+// descriptorKey is a key expression of a descriptor.
+type descriptorKey struct {
+	// fingerprint is the fingerprint of the master key the key was
+	// derived from. It is only set if the key expression contains a key
+	// origin.
+	fingerprint uint32
+
+	// originPath is the derivation path from the master key to the key,
+	// as described by the key origin.
+	originPath string
+
+	// pubKey is the public key, if the expression is a plain public key.
+	pubKey *btcec.PublicKey
+
+	// extendedKey is the extended public key, if the expression is an
+	// extended key.
+	extendedKey *hdkeychain.ExtendedKey
+
+	// path is the fixed, non-hardened derivation path below the extended
+	// key. For ranged keys, it doesn't include the branch and wildcard.
+	path []uint32
+
+	// branches are the branches of a ranged key, that is the indexes
+	// before the trailing wildcard. It is empty for keys that aren't
+	// ranged.
+	branches []uint32
+}
+
+// ranged returns true if the key expression contains a wildcard.
+func (k *descriptorKey) ranged() bool {
+	return len(k.branches) > 0
+}
+
+// concretePubKey returns the public key of a key expression that isn't
+// ranged.
+func (k *descriptorKey) concretePubKey() (*btcec.PublicKey, error) {
+	if k.pubKey != nil {
+		return k.pubKey, nil
+	}
+
+	if k.ranged() {
+		return nil, errors.New("key expression is ranged")
+	}
+
+	key := k.extendedKey
+	for _, idx := range k.path {
+		var err error
+		key, err = key.Derive(idx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return key.ECPubKey()
+}
+
+// descriptor is a parsed output script descriptor.
+type descriptor struct {
+	// desc is the descriptor string without checksum.
+	desc string
+
+	// script is the type of output script that is described.
+	script descriptorScript
+
+	// key is the key of the output. For taproot outputs, it is the
+	// internal key.
+	key *descriptorKey
+
+	// threshold is the number of signatures required by the multisig
+	// script leaf of a taproot output. It is zero if there is no script
+	// leaf.
+	threshold int
+
+	// leafKeys are the keys of the multisig script leaf of a taproot
+	// output.
+	leafKeys []*descriptorKey
+
+	// sorted is true if the keys of the multisig script leaf are sorted.
+	sorted bool
+}
+
+// unwrapDescriptor returns the arguments of the descriptor function fn, and
+// whether desc is a call of that function.
+func unwrapDescriptor(desc, fn string) (string, bool) {
+	if !strings.HasPrefix(desc, fn+"(") || !strings.HasSuffix(desc, ")") {
+		return "", false
+	}
+
+	return desc[len(fn)+1 : len(desc)-1], true
+}
+
+// splitDescriptorArgs splits the arguments of a descriptor function at the
+// commas that aren't nested in any sub expression.
+func splitDescriptorArgs(args string) []string {
+	var (
+		result []string
+		depth  int
+		start  int
+	)
+	for i, ch := range args {
+		switch ch {
+		case '(', '{', '[':
+			depth++
+
+		case ')', '}', ']':
+			depth--
+
+		case ',':
+			if depth == 0 {
+				result = append(result, args[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(result, args[start:])
+}
+
+// parseDescriptor parses an output script descriptor, with or without
+// checksum. Only descriptors that describe scripts the wallet can watch are
+// supported: wpkh(KEY), sh(wpkh(KEY)), tr(KEY) and taproot outputs with a
+// single multisig script leaf, tr(KEY,multi_a(...)) or
+// tr(KEY,sortedmulti_a(...)).
+func parseDescriptor(desc string) (*descriptor, error) {
+	desc, err := stripDescriptorChecksum(strings.TrimSpace(desc))
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := parseDescriptorScript(desc)
+	if err != nil {
+		return nil, err
+	}
+	parsed.desc = desc
+
+	return parsed, nil
+}
+
+// parseDescriptorScript parses the script expression of a descriptor without
+// checksum.
+func parseDescriptorScript(desc string) (*descriptor, error) {
+	if inner, ok := unwrapDescriptor(desc, "sh"); ok {
+		arg, ok := unwrapDescriptor(inner, "wpkh")
+		if !ok {
+			return nil, fmt.Errorf("%w: only sh(wpkh(KEY)) is "+
+				"supported", ErrUnsupportedDescriptor)
+		}
+
+		key, err := parseDescriptorKey(arg, false)
+		if err != nil {
+			return nil, err
+		}
+
+		return &descriptor{script: descriptorSHWPKH, key: key}, nil
+	}
+
+	if arg, ok := unwrapDescriptor(desc, "wpkh"); ok {
+		key, err := parseDescriptorKey(arg, false)
+		if err != nil {
+			return nil, err
+		}
+
+		return &descriptor{script: descriptorWPKH, key: key}, nil
+	}
+
+	if args, ok := unwrapDescriptor(desc, "tr"); ok {
+		return parseTaprootDescriptor(splitDescriptorArgs(args))
+	}
+
+	return nil, fmt.Errorf("%w: %v", ErrUnsupportedDescriptor, desc)
+}
+
+// parseTaprootDescriptor parses the arguments of a tr() descriptor.
+func parseTaprootDescriptor(args []string) (*descriptor, error) {
+	key, err := parseDescriptorKey(args[0], true)
+	if err != nil {
+		return nil, err
+	}
+
+	desc := &descriptor{script: descriptorTR, key: key}
+	if len(args) == 1 {
+		return desc, nil
+	}
+
+	if len(args) != 2 {
+		return nil, fmt.Errorf("invalid number of tr() arguments: %d",
+			len(args))
+	}
+
+	// Ranged keys are imported as accounts, which can't have script
+	// leaves.
+	if key.ranged() {
+		return nil, fmt.Errorf("%w: taproot script leaves require a "+
+			"key that isn't ranged", ErrUnsupportedDescriptor)
+	}
+
+	multiArgs, ok := unwrapDescriptor(args[1], "multi_a")
+	if !ok {
+		multiArgs, ok = unwrapDescriptor(args[1], "sortedmulti_a")
+		desc.sorted = true
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: only a single multi_a or "+
+			"sortedmulti_a script leaf is supported",
+			ErrUnsupportedDescriptor)
+	}
+
+	parts := splitDescriptorArgs(multiArgs)
+	if len(parts) < 2 {
+		return nil, errors.New("multisig requires a threshold and at " +
+			"least one key")
+	}
+
+	desc.threshold, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid multisig threshold: %v", err)
+	}
+	if desc.threshold < 1 || desc.threshold > len(parts)-1 {
+		return nil, fmt.Errorf("invalid multisig threshold %d for %d "+
+			"keys", desc.threshold, len(parts)-1)
+	}
+
+	for _, part := range parts[1:] {
+		leafKey, err := parseDescriptorKey(part, true)
+		if err != nil {
+			return nil, err
+		}
+
+		if leafKey.ranged() {
+			return nil, fmt.Errorf("%w: multisig keys must not be "+
+				"ranged", ErrUnsupportedDescriptor)
+		}
+
+		desc.leafKeys = append(desc.leafKeys, leafKey)
+	}
+
+	return desc, nil
+}
+
+// parseDescriptorKey parses a key expression. Only public keys are supported,
+// as the described scripts are imported as watch-only. If xOnly is true,
+// plain public keys may be given as 32 byte x-only keys.
+func parseDescriptorKey(expr string, xOnly bool) (*descriptorKey, error) {
+	key := &descriptorKey{}
+
+	// Parse the optional key origin, [fingerprint/path].
+	if strings.HasPrefix(expr, "[") {
+		end := strings.IndexByte(expr, ']')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated key origin in %v",
+				expr)
+		}
+
+		origin := expr[1:end]
+		expr = expr[end+1:]
+
+		fingerprint := origin
+		if idx := strings.IndexByte(origin, '/'); idx >= 0 {
+			fingerprint = origin[:idx]
+			key.originPath = origin[idx+1:]
+		}
+
+		fpBytes, err := hex.DecodeString(fingerprint)
+		if err != nil || len(fpBytes) != 4 {
+			return nil, fmt.Errorf("invalid key origin "+
+				"fingerprint %v", fingerprint)
+		}
+		key.fingerprint = binary.BigEndian.Uint32(fpBytes)
+
+		if key.originPath != "" {
+			_, err := parseDerivationPath(
+				"m/" + normalizeHardened(key.originPath),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("invalid key origin "+
+					"path: %v", err)
+			}
+		}
+	}
+
+	// A plain public key is hex encoded.
+	if keyBytes, err := hex.DecodeString(expr); err == nil {
+		switch {
+		case xOnly && len(keyBytes) == schnorr.PubKeyBytesLen:
+			key.pubKey, err = schnorr.ParsePubKey(keyBytes)
+
+		case len(keyBytes) == btcec.PubKeyBytesLenCompressed:
+			key.pubKey, err = btcec.ParsePubKey(keyBytes)
+
+		default:
+			err = fmt.Errorf("invalid public key length %d",
+				len(keyBytes))
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return key, nil
+	}
+
+	parts := strings.Split(expr, "/")
+	extendedKey, err := hdkeychain.NewKeyFromString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid key %v: %v", parts[0], err)
+	}
+	if extendedKey.IsPrivate() {
+		return nil, fmt.Errorf("%w: private keys can't be imported",
+			ErrUnsupportedDescriptor)
+	}
+	key.extendedKey = extendedKey
+
+	parts = parts[1:]
+	if len(parts) > 0 && parts[len(parts)-1] == "*" {
+		// Ranged keys are imported as accounts, so we only accept the
+		// standard derivation below an account key.
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%w: ranged keys must be of "+
+				"the form KEY/<branch>/*",
+				ErrUnsupportedDescriptor)
+		}
+
+		key.branches, err = parseDescriptorBranches(parts[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return key, nil
+	}
+
+	for _, part := range parts {
+		if strings.ContainsAny(part, "'hH*") {
+			return nil, fmt.Errorf("%w: hardened derivation and "+
+				"hardened wildcards are not supported",
+				ErrUnsupportedDescriptor)
+		}
+
+		idx, err := strconv.ParseUint(part, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid derivation index %v: "+
+				"%v", part, err)
+		}
+		key.path = append(key.path, uint32(idx))
+	}
+
+	return key, nil
+}
+
+// parseDescriptorBranches parses the branch of a ranged key, which is either a
+// single external or internal branch index, or both as multipath expression
+// <0;1>.
+func parseDescriptorBranches(expr string) ([]uint32, error) {
+	var indexes []string
+	switch {
+	case strings.HasPrefix(expr, "<") && strings.HasSuffix(expr, ">"):
+		indexes = strings.Split(expr[1:len(expr)-1], ";")
+
+	default:
+		indexes = []string{expr}
+	}
+
+	branches := make([]uint32, 0, len(indexes))
+	for _, index := range indexes {
+		switch index {
+		case "0":
+			branches = append(branches, externalBranch)
+
+		case "1":
+			branches = append(branches, internalBranch)
+
+		default:
+			return nil, fmt.Errorf("%w: ranged keys must derive "+
+				"from the external (0) or internal (1) branch",
+				ErrUnsupportedDescriptor)
+		}
+	}
+
+	return branches, nil
+}
+
+// normalizeHardened replaces the alternative hardened derivation markers h and
+// H of a path by an apostrophe.
+func normalizeHardened(path string) string {
+	return strings.NewReplacer("h", "'", "H", "'").Replace(path)
+}
+
+// String returns the descriptor including its checksum.
+func (d *descriptor) String() string {
+	// The characters of a parsed descriptor were already checked, so
+	// computing the checksum can't fail.
+	desc, _ := addDescriptorChecksum(d.desc)
+
+	return desc
+}
+
+// addrType returns the wallet address type of the descriptor's output script.
+func (d *descriptor) addrType() waddrmgr.AddressType {
+	switch d.script {
+	case descriptorSHWPKH:
+		return waddrmgr.NestedWitnessPubKey
+
+	case descriptorTR:
+		return waddrmgr.TaprootPubKey
+
+	default:
+		return waddrmgr.WitnessPubKey
+	}
+}
+
+// address returns the address of a descriptor that isn't ranged.
+func (d *descriptor) address(params *chaincfg.Params) (btcutil.Address,
+	error) {
+
+	pubKey, err := d.key.concretePubKey()
+	if err != nil {
+		return nil, err
+	}
+
+	switch d.script {
+	case descriptorWPKH:
+		return btcutil.NewAddressWitnessPubKeyHash(
+			btcutil.Hash160(pubKey.SerializeCompressed()), params,
+		)
+
+	case descriptorSHWPKH:
+		witnessScript, err := input.WitnessPubKeyHash(
+			pubKey.SerializeCompressed(),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return btcutil.NewAddressScriptHash(witnessScript, params)
+
+	case descriptorTR:
+		var rootHash []byte
+		if len(d.leafKeys) > 0 {
+			leaf, err := d.multisigLeaf()
+			if err != nil {
+				return nil, err
+			}
+
+			leafHash := leaf.TapHash()
+			rootHash = leafHash[:]
+		}
+
+		outputKey := txscript.ComputeTaprootOutputKey(pubKey, rootHash)
+
+		return btcutil.NewAddressTaproot(
+			schnorr.SerializePubKey(outputKey), params,
+		)
+
+	default:
+		return nil, fmt.Errorf("unknown descriptor script %v", d.script)
+	}
+}
+
+// multisigLeaf returns the tapscript leaf of the multisig script of a taproot
+// descriptor.
+func (d *descriptor) multisigLeaf() (txscript.TapLeaf, error) {
+	keys := make([][]byte, len(d.leafKeys))
+	for i, leafKey := range d.leafKeys {
+		pubKey, err := leafKey.concretePubKey()
+		if err != nil {
+			return txscript.TapLeaf{}, err
+		}
+
+		keys[i] = schnorr.SerializePubKey(pubKey)
+	}
+
+	if d.sorted {
+		sort.Slice(keys, func(i, j int) bool {
+			return bytes.Compare(keys[i], keys[j]) < 0
+		})
+	}
+
+	// The script is <key_1> OP_CHECKSIG <key_2> OP_CHECKSIGADD ...
+	// <key_n> OP_CHECKSIGADD <threshold> OP_NUMEQUAL.
+	builder := txscript.NewScriptBuilder()
+	for i, key := range keys {
+		builder.AddData(key)
+		if i == 0 {
+			builder.AddOp(txscript.OP_CHECKSIG)
+		} else {
+			builder.AddOp(txscript.OP_CHECKSIGADD)
+		}
+	}
+	builder.AddInt64(int64(d.threshold))
+	builder.AddOp(txscript.OP_NUMEQUAL)
+
+	script, err := builder.Script()
+	if err != nil {
+		return txscript.TapLeaf{}, err
+	}
+
+	return txscript.NewBaseTapLeaf(script), nil
+}
+
+// accountDescriptors returns the descriptors of the external and internal
+// branches of an account.
+func accountDescriptors(account *Account,
+	params *chaincfg.Params) ([]*AccountDescriptor, error) {
+
+	accountKey, err := hdkeychain.NewKeyFromString(
+		account.ExtendedPublicKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Descriptors only know the BIP-0032 version bytes, the script type is
+	// defined by the descriptor itself.
+	accountKey, err = accountKey.CloneWithVersion(params.HDPublicKeyID[:])
+	if err != nil {
+		return nil, err
+	}
+
+	var origin string
+	if len(account.MasterKeyFingerprint) == 4 {
+		origin = fmt.Sprintf("[%x/%s]", account.MasterKeyFingerprint,
+			strings.TrimPrefix(account.DerivationPath, "m/"))
+	}
+
+	descriptors := make([]*AccountDescriptor, 0, 2)
+	for _, branch := range []uint32{externalBranch, internalBranch} {
+		key := fmt.Sprintf("%s%s/%d/*", origin, accountKey, branch)
+
+		var desc string
+		switch account.AddressType {
+		case AddressType_WITNESS_PUBKEY_HASH:
+			desc = fmt.Sprintf("wpkh(%s)", key)
+
+		case AddressType_NESTED_WITNESS_PUBKEY_HASH:
+			desc = fmt.Sprintf("sh(wpkh(%s))", key)
+
+		// Hybrid accounts use nested addresses externally and native
+		// addresses for change.
+		case AddressType_HYBRID_NESTED_WITNESS_PUBKEY_HASH:
+			desc = fmt.Sprintf("sh(wpkh(%s))", key)
+			if branch == internalBranch {
+				desc = fmt.Sprintf("wpkh(%s)", key)
+			}
+
+		case AddressType_TAPROOT_PUBKEY:
+			desc = fmt.Sprintf("tr(%s)", key)
+
+		default:
+			return nil, fmt.Errorf("unsupported address type %v",
+				account.AddressType)
+		}
+
+		desc, err = addDescriptorChecksum(desc)
+		if err != nil {
+			return nil, err
+		}
+
+		descriptors = append(descriptors, &AccountDescriptor{
+			AccountName: account.Name,
+			Desc:        desc,
+			Internal:    branch == internalBranch,
+		})
+	}
+
+	return descriptors, nil
+}
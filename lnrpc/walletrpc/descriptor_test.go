@@ -0,0 +1,198 @@
+package walletrpc
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// testAccountXPub is the BIP-0086 account key m/86'/0'/0' of the
+	// BIP-0086 test vectors.
+	testAccountXPub = "xpub6BgBgsespWvERF3LHQu6CnqdvfEvtMcQjYrcRzx53QJjS" +
+		"xarj2afYWcLteoGVky7D3UKDP9QyrLprQ3VCECoY49yfdDEHGCtMMj92pReUsQ"
+
+	// testPubKey is the public key of the private key 1.
+	testPubKey = "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2" +
+		"815b16f81798"
+)
+
+// TestDescriptorChecksum tests the descriptor checksum against the BIP-0380
+// test vectors.
+func TestDescriptorChecksum(t *testing.T) {
+	t.Parallel()
+
+	checksum, err := descriptorChecksum("raw(deadbeef)")
+	require.NoError(t, err)
+	require.Equal(t, "89f8spxm", checksum)
+
+	desc, err := stripDescriptorChecksum("raw(deadbeef)#89f8spxm")
+	require.NoError(t, err)
+	require.Equal(t, "raw(deadbeef)", desc)
+
+	_, err = stripDescriptorChecksum("raw(deedbeef)#89f8spxm")
+	require.ErrorContains(t, err, "invalid descriptor checksum")
+
+	_, err = stripDescriptorChecksum("raw(deadbeef)#89f8spx")
+	require.ErrorContains(t, err, "character checksum")
+
+	_, err = descriptorChecksum("raw(deadbeef)\n")
+	require.ErrorContains(t, err, "invalid character")
+}
+
+// TestParseDescriptor tests the parsing of the supported descriptors and the
+// addresses that are derived from descriptors that aren't ranged.
+func TestParseDescriptor(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		desc        string
+		expectedErr string
+		ranged      bool
+		branches    []uint32
+		addrType    waddrmgr.AddressType
+		address     string
+	}{{
+		name:     "wpkh single key",
+		desc:     "wpkh(" + testPubKey + ")",
+		addrType: waddrmgr.WitnessPubKey,
+		address:  "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+	}, {
+		name:     "tr derived key with origin",
+		desc:     "tr([73c5da0a/86'/0'/0']" + testAccountXPub + "/0/0)",
+		addrType: waddrmgr.TaprootPubKey,
+		address: "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwu" +
+			"dpxqkedrcr",
+	}, {
+		name:     "ranged sh wpkh",
+		desc:     "sh(wpkh([73c5da0a/49h/0h/0h]" + testAccountXPub + "/1/*))",
+		addrType: waddrmgr.NestedWitnessPubKey,
+		ranged:   true,
+		branches: []uint32{internalBranch},
+	}, {
+		name:     "ranged multipath tr",
+		desc:     "tr(" + testAccountXPub + "/<0;1>/*)",
+		addrType: waddrmgr.TaprootPubKey,
+		ranged:   true,
+		branches: []uint32{externalBranch, internalBranch},
+	}, {
+		name: "tr with multisig leaf",
+		desc: "tr(" + testPubKey + ",sortedmulti_a(1," + testPubKey[2:] +
+			"," + testAccountXPub + "/0/1))",
+		addrType: waddrmgr.TaprootPubKey,
+	}, {
+		name:        "wsh multi",
+		desc:        "wsh(multi(1," + testPubKey + "))",
+		expectedErr: "unsupported descriptor",
+	}, {
+		name:        "tr with ranged multisig key",
+		desc:        "tr(" + testPubKey + ",multi_a(1," + testAccountXPub + "/0/*))",
+		expectedErr: "multisig keys must not be ranged",
+	}, {
+		name:        "invalid multisig threshold",
+		desc:        "tr(" + testPubKey + ",multi_a(2," + testPubKey + "))",
+		expectedErr: "invalid multisig threshold",
+	}, {
+		name:        "hardened derivation",
+		desc:        "wpkh(" + testAccountXPub + "/0h/0)",
+		expectedErr: "hardened derivation",
+	}, {
+		name:        "non-standard range",
+		desc:        "wpkh(" + testAccountXPub + "/0/0/*)",
+		expectedErr: "ranged keys must be of the form",
+	}, {
+		name:        "x-only key outside of taproot",
+		desc:        "wpkh(" + testPubKey[2:] + ")",
+		expectedErr: "invalid public key length",
+	}, {
+		name:        "invalid fingerprint",
+		desc:        "wpkh([73c5da/84'/0'/0']" + testAccountXPub + "/0/*)",
+		expectedErr: "invalid key origin fingerprint",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			desc, err := parseDescriptor(tc.desc)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, tc.ranged, desc.key.ranged())
+			require.Equal(t, tc.branches, desc.key.branches)
+			require.Equal(t, tc.addrType, desc.addrType())
+
+			// Parsing the descriptor with its checksum must result
+			// in the same descriptor.
+			withChecksum, err := parseDescriptor(desc.String())
+			require.NoError(t, err)
+			require.Equal(t, desc, withChecksum)
+
+			if tc.ranged {
+				return
+			}
+
+			addr, err := desc.address(&chaincfg.MainNetParams)
+			require.NoError(t, err)
+
+			if tc.address != "" {
+				require.Equal(t, tc.address, addr.String())
+			}
+		})
+	}
+}
+
+// TestAccountDescriptors tests that exported account descriptors can be
+// parsed again.
+func TestAccountDescriptors(t *testing.T) {
+	t.Parallel()
+
+	fingerprint, err := hex.DecodeString("73c5da0a")
+	require.NoError(t, err)
+
+	account := &Account{
+		Name:                 "test",
+		AddressType:          AddressType_HYBRID_NESTED_WITNESS_PUBKEY_HASH,
+		ExtendedPublicKey:    testAccountXPub,
+		MasterKeyFingerprint: fingerprint,
+		DerivationPath:       "m/49'/0'/0'",
+	}
+
+	descs, err := accountDescriptors(account, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	require.Len(t, descs, 2)
+
+	require.Equal(t, "test", descs[0].AccountName)
+	require.False(t, descs[0].Internal)
+	require.Contains(
+		t, descs[0].Desc, "sh(wpkh([73c5da0a/49'/0'/0']"+testAccountXPub+
+			"/0/*))#",
+	)
+
+	require.True(t, descs[1].Internal)
+	require.Contains(
+		t, descs[1].Desc, "wpkh([73c5da0a/49'/0'/0']"+testAccountXPub+
+			"/1/*)#",
+	)
+
+	for i, expectedScript := range []descriptorScript{
+		descriptorSHWPKH, descriptorWPKH,
+	} {
+		desc, err := parseDescriptor(descs[i].Desc)
+		require.NoError(t, err)
+		require.Equal(t, expectedScript, desc.script)
+		require.Equal(t, []uint32{uint32(i)}, desc.key.branches)
+		require.EqualValues(
+			t, 0x73c5da0a, desc.key.fingerprint,
+		)
+	}
+}
@@ -103,6 +103,23 @@ type WalletKitClient interface {
 	// NOTE: Taproot keys imported through this RPC currently _cannot_ be used for
 	// funding PSBTs. Only tracking the balance and UTXOs is currently supported.
 	ImportTapscript(ctx context.Context, in *ImportTapscriptRequest, opts ...grpc.CallOption) (*ImportTapscriptResponse, error)
+	// ImportDescriptors imports a list of output script descriptors as watch-only
+	// into the wallet. Descriptors with a ranged extended public key, for example
+	// wpkh([d34db33f/84'/0'/0']xpub.../0/*), are imported as accounts. Descriptors
+	// with a single public key, like wpkh(KEY), tr(KEY) or taproot outputs with a
+	// single multisig leaf, tr(KEY,multi_a(K,KEY_1,...,KEY_N)), are imported as
+	// individual addresses. The descriptors are imported atomically, if one of
+	// them can't be imported, none of them are. Optionally, a rescan of the chain
+	// for past events of the imported descriptors is started in the background.
+	//
+	// NOTE: Only wpkh, sh(wpkh), tr, and tr with a single multi_a or
+	// sortedmulti_a leaf are supported.
+	ImportDescriptors(ctx context.Context, in *ImportDescriptorsRequest, opts ...grpc.CallOption) (*ImportDescriptorsResponse, error)
+	// ExportDescriptors exports the accounts of the wallet as ranged output script
+	// descriptors, one for the external and one for the internal branch of each
+	// account. The descriptors can be imported into other descriptor wallets, for
+	// example a watch-only bitcoind wallet.
+	ExportDescriptors(ctx context.Context, in *ExportDescriptorsRequest, opts ...grpc.CallOption) (*ExportDescriptorsResponse, error)
 	// PublishTransaction attempts to publish the passed transaction to the
 	// network. Once this returns without an error, the wallet will continually
 	// attempt to re-broadcast the transaction on start up, until it enters the
@@ -330,6 +347,24 @@ func (c *walletKitClient) ImportTapscript(ctx context.Context, in *ImportTapscri
 	return out, nil
 }
 
+func (c *walletKitClient) ImportDescriptors(ctx context.Context, in *ImportDescriptorsRequest, opts ...grpc.CallOption) (*ImportDescriptorsResponse, error) {
+	out := new(ImportDescriptorsResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/ImportDescriptors", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) ExportDescriptors(ctx context.Context, in *ExportDescriptorsRequest, opts ...grpc.CallOption) (*ExportDescriptorsResponse, error) {
+	out := new(ExportDescriptorsResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/ExportDescriptors", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *walletKitClient) PublishTransaction(ctx context.Context, in *Transaction, opts ...grpc.CallOption) (*PublishResponse, error) {
 	out := new(PublishResponse)
 	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/PublishTransaction", in, out, opts...)
@@ -508,6 +543,23 @@ type WalletKitServer interface {
 	// NOTE: Taproot keys imported through this RPC currently _cannot_ be used for
 	// funding PSBTs. Only tracking the balance and UTXOs is currently supported.
 	ImportTapscript(context.Context, *ImportTapscriptRequest) (*ImportTapscriptResponse, error)
+	// ImportDescriptors imports a list of output script descriptors as watch-only
+	// into the wallet. Descriptors with a ranged extended public key, for example
+	// wpkh([d34db33f/84'/0'/0']xpub.../0/*), are imported as accounts. Descriptors
+	// with a single public key, like wpkh(KEY), tr(KEY) or taproot outputs with a
+	// single multisig leaf, tr(KEY,multi_a(K,KEY_1,...,KEY_N)), are imported as
+	// individual addresses. The descriptors are imported atomically, if one of
+	// them can't be imported, none of them are. Optionally, a rescan of the chain
+	// for past events of the imported descriptors is started in the background.
+	//
+	// NOTE: Only wpkh, sh(wpkh), tr, and tr with a single multi_a or
+	// sortedmulti_a leaf are supported.
+	ImportDescriptors(context.Context, *ImportDescriptorsRequest) (*ImportDescriptorsResponse, error)
+	// ExportDescriptors exports the accounts of the wallet as ranged output script
+	// descriptors, one for the external and one for the internal branch of each
+	// account. The descriptors can be imported into other descriptor wallets, for
+	// example a watch-only bitcoind wallet.
+	ExportDescriptors(context.Context, *ExportDescriptorsRequest) (*ExportDescriptorsResponse, error)
 	// PublishTransaction attempts to publish the passed transaction to the
 	// network. Once this returns without an error, the wallet will continually
 	// attempt to re-broadcast the transaction on start up, until it enters the
@@ -654,6 +706,12 @@ func (UnimplementedWalletKitServer) ImportPublicKey(context.Context, *ImportPubl
 func (UnimplementedWalletKitServer) ImportTapscript(context.Context, *ImportTapscriptRequest) (*ImportTapscriptResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ImportTapscript not implemented")
 }
+func (UnimplementedWalletKitServer) ImportDescriptors(context.Context, *ImportDescriptorsRequest) (*ImportDescriptorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportDescriptors not implemented")
+}
+func (UnimplementedWalletKitServer) ExportDescriptors(context.Context, *ExportDescriptorsRequest) (*ExportDescriptorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportDescriptors not implemented")
+}
 func (UnimplementedWalletKitServer) PublishTransaction(context.Context, *Transaction) (*PublishResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PublishTransaction not implemented")
 }
@@ -931,6 +989,42 @@ func _WalletKit_ImportTapscript_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletKit_ImportDescriptors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportDescriptorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).ImportDescriptors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/ImportDescriptors",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).ImportDescriptors(ctx, req.(*ImportDescriptorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_ExportDescriptors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportDescriptorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).ExportDescriptors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/ExportDescriptors",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).ExportDescriptors(ctx, req.(*ExportDescriptorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WalletKit_PublishTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Transaction)
 	if err := dec(in); err != nil {
@@ -1170,6 +1264,14 @@ var WalletKit_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ImportTapscript",
 			Handler:    _WalletKit_ImportTapscript_Handler,
 		},
+		{
+			MethodName: "ImportDescriptors",
+			Handler:    _WalletKit_ImportDescriptors_Handler,
+		},
+		{
+			MethodName: "ExportDescriptors",
+			Handler:    _WalletKit_ExportDescriptors_Handler,
+		},
 		{
 			MethodName: "PublishTransaction",
 			Handler:    _WalletKit_PublishTransaction_Handler,
@@ -14,6 +14,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
@@ -21,7 +22,9 @@ import (
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/mempool"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/waddrmgr"
@@ -158,6 +161,14 @@ var (
 			Entity: "onchain",
 			Action: "write",
 		}},
+		"/walletrpc.WalletKit/ImportDescriptors": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
+		"/walletrpc.WalletKit/ExportDescriptors": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
 	}
 
 	// DefaultWalletKitMacFilename is the default name of the wallet kit
@@ -617,6 +628,15 @@ func (w *WalletKit) SendOutputs(ctx context.Context,
 	case len(req.Outputs) == 0:
 		return nil, fmt.Errorf("must specify at least one output " +
 			"to create")
+
+	// If labels for the outputs are given, there must be exactly one
+	// label for each output.
+	case len(req.OutputLabels) != 0 &&
+		len(req.OutputLabels) != len(req.Outputs):
+
+		return nil, fmt.Errorf("number of output labels (%d) must "+
+			"match number of outputs (%d)", len(req.OutputLabels),
+			len(req.Outputs))
 	}
 
 	// Before we can request this transaction to be created, we'll need to
@@ -642,23 +662,157 @@ func (w *WalletKit) SendOutputs(ctx context.Context,
 		return nil, err
 	}
 
-	// Now that we have the outputs mapped, we can request that the wallet
-	// attempt to create this transaction.
-	tx, err := w.cfg.Wallet.SendOutputs(
-		outputsToCreate, chainfee.SatPerKWeight(req.SatPerKw), minConfs, label,
+	for idx, outputLabel := range req.OutputLabels {
+		if _, err := labels.ValidateAPI(outputLabel); err != nil {
+			return nil, fmt.Errorf("invalid label for output %d: "+
+				"%v", idx, err)
+		}
+	}
+
+	feeRate := chainfee.SatPerKWeight(req.SatPerKw)
+
+	// If the transaction should neither signal replaceability nor be
+	// returned unsigned, the wallet can create and publish it in one go.
+	if !req.Rbf && !req.ReturnPsbt {
+		// Now that we have the outputs mapped, we can request that the
+		// wallet attempt to create this transaction.
+		tx, err := w.cfg.Wallet.SendOutputs(
+			outputsToCreate, feeRate, minConfs, label,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		sentOutputs := marshallSentOutputs(
+			tx.TxOut, outputsToCreate, req.OutputLabels,
+		)
+
+		// The transaction is already published at this point, so we
+		// don't fail the call if its output labels can't be stored.
+		err = w.storeOutputLabels(tx, sentOutputs)
+		if err != nil {
+			log.Errorf("Could not store output labels of %v: %v",
+				tx.TxHash(), err)
+		}
+
+		var b bytes.Buffer
+		if err := tx.Serialize(&b); err != nil {
+			return nil, err
+		}
+
+		return &SendOutputsResponse{
+			RawTx:   b.Bytes(),
+			Outputs: sentOutputs,
+		}, nil
+	}
+
+	// Otherwise we fund a PSBT with the outputs, so we can adjust the
+	// inputs before the transaction is signed or hand it back to the
+	// caller unsigned. The coin selection lock is held until the inputs
+	// are either spent by the published transaction or locked.
+	packet, err := psbt.New(nil, outputsToCreate, 2, 0, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not create PSBT: %v", err)
+	}
+
+	var (
+		resp  = &SendOutputsResponse{}
+		locks []*base.ListLeasedOutputResult
 	)
+	err = w.cfg.CoinSelectionLocker.WithCoinSelectLock(func() error {
+		_, err := w.cfg.Wallet.FundPsbt(
+			packet, minConfs, feeRate, lnwallet.DefaultAccountName,
+		)
+		if err != nil {
+			return fmt.Errorf("wallet couldn't fund PSBT: %v", err)
+		}
+
+		// Signal replaceability on all inputs if requested. This needs
+		// to happen before any of the inputs are signed.
+		if req.Rbf {
+			for _, txIn := range packet.UnsignedTx.TxIn {
+				txIn.Sequence = mempool.MaxRBFSequence
+			}
+		}
+
+		// The outputs are final now, so we store their labels before
+		// the transaction is handed out or published.
+		resp.Outputs = marshallSentOutputs(
+			packet.UnsignedTx.TxOut, outputsToCreate,
+			req.OutputLabels,
+		)
+		err = w.storeOutputLabels(packet.UnsignedTx, resp.Outputs)
+		if err != nil {
+			return fmt.Errorf("could not store output labels: %v",
+				err)
+		}
+
+		if req.ReturnPsbt {
+			var rawPsbt bytes.Buffer
+			err := packet.Serialize(&rawPsbt)
+			if err != nil {
+				return fmt.Errorf("error serializing funded "+
+					"PSBT: %v", err)
+			}
+			resp.FundedPsbt = rawPsbt.Bytes()
+
+			// Lock the inputs, so they aren't spent by anything
+			// else while the PSBT is awaiting approval.
+			locks, err = lockInputs(w.cfg.Wallet, packet)
+			if err != nil {
+				return fmt.Errorf("could not lock inputs: %v",
+					err)
+			}
+
+			return nil
+		}
+
+		err = w.cfg.Wallet.FinalizePsbt(
+			packet, lnwallet.DefaultAccountName,
+		)
+		if err != nil {
+			return fmt.Errorf("error finalizing PSBT: %v", err)
+		}
+
+		tx, err := psbt.Extract(packet)
+		if err != nil {
+			return fmt.Errorf("unable to extract final TX: %v",
+				err)
+		}
+
+		err = w.cfg.Wallet.PublishTransaction(tx, label)
+		if err != nil {
+			return err
+		}
+
+		var b bytes.Buffer
+		if err := tx.Serialize(&b); err != nil {
+			return err
+		}
+		resp.RawTx = b.Bytes()
+
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	var b bytes.Buffer
-	if err := tx.Serialize(&b); err != nil {
-		return nil, err
+	resp.LockedUtxos = marshallLeases(locks)
+
+	return resp, nil
+}
+
+// storeOutputLabels stores the labels of the sent outputs of the given
+// transaction, if any of them are labeled.
+func (w *WalletKit) storeOutputLabels(tx *wire.MsgTx,
+	sentOutputs []*SentOutput) error {
+
+	outputLabels := sentOutputLabels(sentOutputs)
+	if len(outputLabels) == 0 {
+		return nil
 	}
 
-	return &SendOutputsResponse{
-		RawTx: b.Bytes(),
-	}, nil
+	return w.cfg.OutputLabels.PutOutputLabels(tx, outputLabels)
 }
 
 // EstimateFee attempts to query the internal fee estimator of the wallet to
@@ -750,6 +904,11 @@ func (w *WalletKit) PendingSweeps(ctx context.Context,
 		requestedFee := pendingInput.Params.Fee
 		requestedFeeRate := uint64(requestedFee.FeeRate.FeePerKVByte() / 1000)
 
+		var conflictTxid string
+		if pendingInput.ConflictTxid != nil {
+			conflictTxid = pendingInput.ConflictTxid.String()
+		}
+
 		rpcPendingSweeps = append(rpcPendingSweeps, &PendingSweep{
 			Outpoint:             op,
 			WitnessType:          witnessType,
@@ -760,6 +919,7 @@ func (w *WalletKit) PendingSweeps(ctx context.Context,
 			RequestedSatPerVbyte: requestedFeeRate,
 			RequestedConfTarget:  requestedFee.ConfTarget,
 			Force:                pendingInput.Params.Force,
+			ConflictTxid:         conflictTxid,
 		})
 	}
 
@@ -1816,3 +1976,340 @@ func (w *WalletKit) ImportTapscript(_ context.Context,
 		P2TrAddress: addr.Address().String(),
 	}, nil
 }
+
+// descriptorAccount groups the ranged descriptors of a request that are
+// imported as the same account.
+type descriptorAccount struct {
+	// name is the name of the account.
+	name string
+
+	// descriptors maps the index of each descriptor within the request to
+	// the parsed descriptor.
+	descriptors map[int]*descriptor
+
+	// key is the shared key expression of the descriptors.
+	key *descriptorKey
+
+	// scripts maps the branches of the account to their script type.
+	scripts map[uint32]descriptorScript
+}
+
+// addDescriptor adds a ranged descriptor to the account, making sure it uses
+// the same account key as the other descriptors and doesn't describe a branch
+// that is already described.
+func (a *descriptorAccount) addDescriptor(idx int, desc *descriptor) error {
+	if a.key == nil {
+		a.key = desc.key
+	}
+
+	sameKey := desc.key.extendedKey.String() ==
+		a.key.extendedKey.String()
+	if !sameKey || desc.key.fingerprint != a.key.fingerprint {
+		return fmt.Errorf("descriptors of account %v use different "+
+			"keys", a.name)
+	}
+
+	for _, branch := range desc.key.branches {
+		if _, ok := a.scripts[branch]; ok {
+			return fmt.Errorf("branch %d of account %v is "+
+				"described more than once", branch, a.name)
+		}
+		a.scripts[branch] = desc.script
+	}
+	a.descriptors[idx] = desc
+
+	return nil
+}
+
+// accountKey returns the account key and address type to import the account
+// with.
+func (a *descriptorAccount) accountKey(
+	params *chaincfg.Params) (*hdkeychain.ExtendedKey,
+	waddrmgr.AddressType, error) {
+
+	external, hasExternal := a.scripts[externalBranch]
+	internal, hasInternal := a.scripts[internalBranch]
+
+	switch {
+	// Nested addresses on the external branch and native addresses on the
+	// internal one is the hybrid BIP-0049Plus schema. Importing a key with
+	// the BIP-0049 version as witness account selects that schema.
+	case hasExternal && hasInternal && external == descriptorSHWPKH &&
+		internal == descriptorWPKH:
+
+		version := waddrmgr.HDVersionTestNetBIP0049
+		if params.Net == chaincfg.MainNetParams.Net {
+			version = waddrmgr.HDVersionMainNetBIP0049
+		}
+
+		var versionBytes [4]byte
+		binary.BigEndian.PutUint32(versionBytes[:], uint32(version))
+		key, err := a.key.extendedKey.CloneWithVersion(versionBytes[:])
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return key, waddrmgr.WitnessPubKey, nil
+
+	case hasExternal && hasInternal && external != internal:
+		return nil, 0, fmt.Errorf("%w: unsupported combination of "+
+			"scripts for account %v", ErrUnsupportedDescriptor,
+			a.name)
+	}
+
+	for _, desc := range a.descriptors {
+		return a.key.extendedKey, desc.addrType(), nil
+	}
+
+	return nil, 0, fmt.Errorf("account %v has no descriptors", a.name)
+}
+
+// ImportDescriptors imports a list of output script descriptors as watch-only
+// into the wallet. Descriptors with a ranged extended public key are imported
+// as accounts, descriptors with a single public key are imported as
+// individual addresses. The descriptors are imported atomically, if one of them
+// can't be imported, none of them are. Optionally, a rescan of the chain for
+// past events of the imported descriptors is started in the background.
+func (w *WalletKit) ImportDescriptors(_ context.Context,
+	req *ImportDescriptorsRequest) (*ImportDescriptorsResponse, error) {
+
+	if len(req.Descriptors) == 0 {
+		return nil, errors.New("no descriptors to import")
+	}
+
+	// Parse all descriptors before importing any of them, so an invalid
+	// descriptor doesn't result in a partial import.
+	var (
+		descs        = make([]*descriptor, len(req.Descriptors))
+		accounts     = make(map[string]*descriptorAccount)
+		accountNames []string
+	)
+	for idx, descImport := range req.Descriptors {
+		desc, err := parseDescriptor(descImport.Desc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid descriptor %d: %w",
+				idx, err)
+		}
+		descs[idx] = desc
+
+		switch {
+		case !desc.key.ranged() && descImport.AccountName != "":
+			return nil, fmt.Errorf("descriptor %d isn't ranged "+
+				"and can't be imported as account", idx)
+
+		case !desc.key.ranged():
+			continue
+
+		case descImport.AccountName == "":
+			return nil, fmt.Errorf("descriptor %d is ranged and "+
+				"requires an account name", idx)
+		}
+
+		account, ok := accounts[descImport.AccountName]
+		if !ok {
+			account = &descriptorAccount{
+				name:        descImport.AccountName,
+				descriptors: make(map[int]*descriptor),
+				scripts:     make(map[uint32]descriptorScript),
+			}
+			accounts[account.name] = account
+			accountNames = append(accountNames, account.name)
+		}
+
+		if err := account.addDescriptor(idx, desc); err != nil {
+			return nil, err
+		}
+	}
+
+	rescanRange := req.RescanRange
+	if rescanRange == 0 {
+		rescanRange = defaultDescriptorRescanRange
+	}
+
+	// Collect everything into a single batch, so the wallet imports either
+	// all of the descriptors or none of them.
+	batch := &lnwallet.WatchOnlyImport{}
+	for _, name := range accountNames {
+		account, err := w.watchOnlyAccount(
+			accounts[name], req.Rescan, rescanRange,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error importing account %v: %w",
+				name, err)
+		}
+		batch.Accounts = append(batch.Accounts, account)
+	}
+
+	resp := &ImportDescriptorsResponse{
+		Descriptors: make([]*ImportedDescriptor, len(req.Descriptors)),
+	}
+	for idx, desc := range descs {
+		if desc.key.ranged() {
+			continue
+		}
+
+		addr, err := w.addWatchOnlyDescriptor(batch, desc)
+		if err != nil {
+			return nil, fmt.Errorf("error importing descriptor "+
+				"%d: %w", idx, err)
+		}
+
+		resp.Descriptors[idx] = &ImportedDescriptor{
+			Desc:    desc.String(),
+			Address: addr.String(),
+		}
+	}
+
+	accountProps, addrs, err := w.cfg.Wallet.ImportWatchOnly(batch)
+	if err != nil {
+		return nil, fmt.Errorf("error importing descriptors: %w", err)
+	}
+
+	for i, name := range accountNames {
+		rpcAccount, err := marshalWalletAccount(
+			w.internalScope(), accountProps[i],
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for idx, desc := range accounts[name].descriptors {
+			resp.Descriptors[idx] = &ImportedDescriptor{
+				Desc:    desc.String(),
+				Account: rpcAccount,
+			}
+		}
+	}
+
+	if !req.Rescan {
+		return resp, nil
+	}
+
+	// The rescan can take a long time, so it only is started here and
+	// happens in the background.
+	err = w.cfg.Wallet.RescanAddresses(addrs, int32(req.RescanStartHeight))
+	if err != nil {
+		return nil, fmt.Errorf("error starting rescan of imported "+
+			"descriptors: %v", err)
+	}
+
+	return resp, nil
+}
+
+// watchOnlyAccount returns the watch-only import of the ranged descriptors of
+// an account. If the account is to be rescanned, the given number of addresses
+// is derived on each described branch, so the wallet recognizes any past
+// events involving them during the rescan.
+func (w *WalletKit) watchOnlyAccount(account *descriptorAccount,
+	rescan bool, rescanRange uint32) (*lnwallet.WatchOnlyAccount, error) {
+
+	accountKey, addrType, err := account.accountKey(w.cfg.ChainParams)
+	if err != nil {
+		return nil, err
+	}
+
+	watchOnlyAccount := &lnwallet.WatchOnlyAccount{
+		Name:                 account.name,
+		AccountPubKey:        accountKey,
+		MasterKeyFingerprint: account.key.fingerprint,
+		AddrType:             addrType,
+	}
+	if !rescan {
+		return watchOnlyAccount, nil
+	}
+
+	for branch := range account.scripts {
+		if branch == internalBranch {
+			watchOnlyAccount.NumInternalAddrs = rescanRange
+		} else {
+			watchOnlyAccount.NumExternalAddrs = rescanRange
+		}
+	}
+
+	return watchOnlyAccount, nil
+}
+
+// addWatchOnlyDescriptor adds a descriptor that isn't ranged to the batch as a
+// single watch-only address and returns that address.
+func (w *WalletKit) addWatchOnlyDescriptor(batch *lnwallet.WatchOnlyImport,
+	desc *descriptor) (btcutil.Address, error) {
+
+	pubKey, err := desc.key.concretePubKey()
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := desc.address(w.cfg.ChainParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// Importing an address twice fails, so we check this up front to not
+	// bother the wallet with a batch that is bound to be rolled back.
+	if w.cfg.Wallet.IsOurAddress(addr) {
+		return nil, fmt.Errorf("address %v is already known to the "+
+			"wallet", addr)
+	}
+
+	// Taproot outputs with a script leaf are imported as tapscript.
+	if len(desc.leafKeys) > 0 {
+		leaf, err := desc.multisigLeaf()
+		if err != nil {
+			return nil, err
+		}
+
+		batch.Tapscripts = append(
+			batch.Tapscripts, input.TapscriptFullTree(pubKey, leaf),
+		)
+
+		return addr, nil
+	}
+
+	batch.PubKeys = append(batch.PubKeys, &lnwallet.WatchOnlyPubKey{
+		PubKey:   pubKey,
+		AddrType: desc.addrType(),
+	})
+
+	return addr, nil
+}
+
+// ExportDescriptors exports the accounts of the wallet as ranged output script
+// descriptors, one for the external and one for the internal branch of each
+// account.
+func (w *WalletKit) ExportDescriptors(ctx context.Context,
+	req *ExportDescriptorsRequest) (*ExportDescriptorsResponse, error) {
+
+	accounts, err := w.ListAccounts(ctx, &ListAccountsRequest{
+		Name:        req.Name,
+		AddressType: req.AddressType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	internalPath := fmt.Sprintf("%v/", w.internalScope())
+
+	resp := &ExportDescriptorsResponse{}
+	for _, account := range accounts.Accounts {
+		// The default imported accounts don't have an account key, and
+		// the accounts of lnd's internal key scope hold channel keys
+		// that aren't used for on-chain funds.
+		if account.ExtendedPublicKey == "" ||
+			strings.HasPrefix(account.DerivationPath, internalPath) {
+
+			continue
+		}
+
+		descriptors, err := accountDescriptors(
+			account, w.cfg.ChainParams,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error exporting account %v: %v",
+				account.Name, err)
+		}
+
+		resp.Descriptors = append(resp.Descriptors, descriptors...)
+	}
+
+	return resp, nil
+}
@@ -1,10 +1,12 @@
 package walletrpc
 
 import (
+	"bytes"
 	"fmt"
 	"strconv"
 	"strings"
 
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/lnrpc"
 )
 
@@ -74,3 +76,59 @@ func parseDerivationPath(path string) ([]uint32, error) {
 	}
 	return indices, nil
 }
+
+// marshallSentOutputs converts the outputs of a transaction created for the
+// given requested outputs into the RPC format. Each requested output is matched
+// to the first unclaimed transaction output with the same value and script,
+// as the wallet may reorder the outputs. Outputs that don't match any of the
+// requested outputs are reported as change. The labels are optional, but if
+// given there must be one for every requested output.
+func marshallSentOutputs(txOuts, requested []*wire.TxOut,
+	outputLabels []string) []*SentOutput {
+
+	claimed := make([]bool, len(requested))
+	sentOutputs := make([]*SentOutput, len(txOuts))
+	for idx, txOut := range txOuts {
+		sentOutput := &SentOutput{
+			OutputIndex: uint32(idx),
+			Value:       txOut.Value,
+			PkScript:    txOut.PkScript,
+			IsChange:    true,
+		}
+
+		for reqIdx, reqOut := range requested {
+			if claimed[reqIdx] || reqOut.Value != txOut.Value ||
+				!bytes.Equal(reqOut.PkScript, txOut.PkScript) {
+
+				continue
+			}
+
+			claimed[reqIdx] = true
+			sentOutput.IsChange = false
+			if len(outputLabels) > reqIdx {
+				sentOutput.Label = outputLabels[reqIdx]
+			}
+
+			break
+		}
+
+		sentOutputs[idx] = sentOutput
+	}
+
+	return sentOutputs
+}
+
+// sentOutputLabels returns the labels of the given sent outputs keyed by their
+// output index. Outputs without a label are left out.
+func sentOutputLabels(sentOutputs []*SentOutput) map[uint32]string {
+	outputLabels := make(map[uint32]string)
+	for _, sentOutput := range sentOutputs {
+		if sentOutput.Label == "" {
+			continue
+		}
+
+		outputLabels[sentOutput.OutputIndex] = sentOutput.Label
+	}
+
+	return outputLabels
+}
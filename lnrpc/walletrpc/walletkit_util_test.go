@@ -3,6 +3,7 @@ package walletrpc
 import (
 	"testing"
 
+	"github.com/btcsuite/btcd/wire"
 	"github.com/stretchr/testify/require"
 )
 
@@ -72,3 +73,69 @@ func TestParseDerivationPath(t *testing.T) {
 		})
 	}
 }
+
+// TestMarshallSentOutputs tests that the outputs of a transaction are matched
+// to the requested outputs and their labels, regardless of their order.
+func TestMarshallSentOutputs(t *testing.T) {
+	t.Parallel()
+
+	var (
+		scriptA = []byte{0x00, 0x14, 0x01}
+		scriptB = []byte{0x00, 0x14, 0x02}
+		change  = []byte{0x00, 0x14, 0x03}
+	)
+
+	// The first two requested outputs are identical, so they must each be
+	// matched to one of the transaction outputs.
+	requested := []*wire.TxOut{
+		{Value: 1000, PkScript: scriptA},
+		{Value: 1000, PkScript: scriptA},
+		{Value: 2000, PkScript: scriptB},
+	}
+	txOuts := []*wire.TxOut{
+		{Value: 2000, PkScript: scriptB},
+		{Value: 500, PkScript: change},
+		{Value: 1000, PkScript: scriptA},
+		{Value: 1000, PkScript: scriptA},
+	}
+
+	sentOutputs := marshallSentOutputs(
+		txOuts, requested, []string{"a1", "a2", "b"},
+	)
+	require.Equal(t, []*SentOutput{{
+		OutputIndex: 0,
+		Value:       2000,
+		PkScript:    scriptB,
+		Label:       "b",
+	}, {
+		OutputIndex: 1,
+		Value:       500,
+		PkScript:    change,
+		IsChange:    true,
+	}, {
+		OutputIndex: 2,
+		Value:       1000,
+		PkScript:    scriptA,
+		Label:       "a1",
+	}, {
+		OutputIndex: 3,
+		Value:       1000,
+		PkScript:    scriptA,
+		Label:       "a2",
+	}}, sentOutputs)
+
+	// Only the labeled outputs are stored, keyed by their output index.
+	require.Equal(t, map[uint32]string{
+		0: "b",
+		2: "a1",
+		3: "a2",
+	}, sentOutputLabels(sentOutputs))
+
+	// Without labels, only the change output is marked.
+	sentOutputs = marshallSentOutputs(txOuts, requested, nil)
+	for idx, sentOutput := range sentOutputs {
+		require.Empty(t, sentOutput.Label)
+		require.Equal(t, idx == 1, sentOutput.IsChange)
+	}
+	require.Empty(t, sentOutputLabels(sentOutputs))
+}
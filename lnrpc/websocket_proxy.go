@@ -5,16 +5,17 @@ package lnrpc
 
 import (
 	"bufio"
+	"context"
 	"io"
 	"net/http"
 	"net/textproto"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/btcsuite/btclog"
 	"github.com/gorilla/websocket"
-	"golang.org/x/net/context"
 )
 
 const (
@@ -38,6 +39,20 @@ const (
 	// an arbitrary non-empty message that has no deeper meaning but should
 	// be sent back by the client in the pong message.
 	PingContent = "are you there?"
+
+	// HeartbeatMessage is the text message we send on an idle stream if
+	// heartbeats are enabled. Unlike ping messages, which are handled by
+	// the browser itself, heartbeats are visible to the application and
+	// allow it to detect a stalled connection.
+	HeartbeatMessage = `{"heartbeat":{}}`
+
+	// DefaultMaxFrameSize is the default maximum size of a single message
+	// sent or received over the WebSocket.
+	DefaultMaxFrameSize = 4 * 1024 * 1024
+
+	// headerMacaroon is the header field that holds the macaroon of a
+	// forwarded request.
+	headerMacaroon = "Grpc-Metadata-Macaroon"
 )
 
 var (
@@ -66,6 +81,45 @@ var (
 	DefaultPongWait = time.Second * 5
 )
 
+// WebSocketProxyOption is a functional option that modifies the behavior of
+// a WebsocketProxy.
+type WebSocketProxyOption func(*WebsocketProxy)
+
+// WithHeartbeatInterval makes the proxy send a HeartbeatMessage on every
+// stream that hasn't sent a message for the given interval.
+func WithHeartbeatInterval(interval time.Duration) WebSocketProxyOption {
+	return func(p *WebsocketProxy) {
+		p.heartbeatInterval = interval
+	}
+}
+
+// WithMaxFrameSize sets the maximum size of a single message sent or received
+// over the WebSocket. A size of zero keeps the default.
+func WithMaxFrameSize(size int) WebSocketProxyOption {
+	return func(p *WebsocketProxy) {
+		if size > 0 {
+			p.maxFrameSize = size
+		}
+	}
+}
+
+// WithResumableStreams makes the server-streaming RPCs matching any of the
+// given URIs resumable. Every message of such a stream carries a resume token,
+// and the backend call is kept alive for the given window after the client
+// disconnects, so the client can reconnect with the token of the last message
+// it received and continue where it left off. A stream can only be resumed
+// with the macaroon it was started with, which is checked again with the given
+// checker on every resume, unless the checker is nil.
+func WithResumableStreams(window time.Duration, uris []ResumableStreamURI,
+	checkMacaroon MacaroonChecker) WebSocketProxyOption {
+
+	return func(p *WebsocketProxy) {
+		p.resumeWindow = window
+		p.resumableURIs = uris
+		p.checkMacaroon = checkMacaroon
+	}
+}
+
 // NewWebSocketProxy attempts to expose the underlying handler as a response-
 // streaming WebSocket stream with newline-delimited JSON as the content
 // encoding. If pingInterval is a non-zero duration, a ping message will be
@@ -76,7 +130,8 @@ var (
 // underlying grpc-gateway library.
 func NewWebSocketProxy(h http.Handler, logger btclog.Logger,
 	pingInterval, pongWait time.Duration,
-	clientStreamingURIs []*regexp.Regexp) http.Handler {
+	clientStreamingURIs []*regexp.Regexp,
+	opts ...WebSocketProxyOption) http.Handler {
 
 	p := &WebsocketProxy{
 		backend: h,
@@ -89,6 +144,8 @@ func NewWebSocketProxy(h http.Handler, logger btclog.Logger,
 			},
 		},
 		clientStreamingURIs: clientStreamingURIs,
+		maxFrameSize:        DefaultMaxFrameSize,
+		streams:             make(map[string]*resumableStream),
 	}
 
 	if pingInterval > 0 && pongWait > 0 {
@@ -96,6 +153,10 @@ func NewWebSocketProxy(h http.Handler, logger btclog.Logger,
 		p.pongWait = pongWait
 	}
 
+	for _, opt := range opts {
+		opt(p)
+	}
+
 	return p
 }
 
@@ -113,6 +174,35 @@ type WebsocketProxy struct {
 
 	pingInterval time.Duration
 	pongWait     time.Duration
+
+	// heartbeatInterval is the time after which we send a heartbeat
+	// message on an idle stream. Heartbeats are disabled if it is zero.
+	heartbeatInterval time.Duration
+
+	// maxFrameSize is the maximum size of a single message sent or
+	// received over the WebSocket.
+	maxFrameSize int
+
+	// resumeWindow is the time we keep the backend call of a resumable
+	// stream alive after its client disconnected. Resumable streams are
+	// disabled if it is zero.
+	resumeWindow time.Duration
+
+	// resumableURIs holds the URIs of the server-streaming RPC methods
+	// that can be resumed.
+	resumableURIs []ResumableStreamURI
+
+	// checkMacaroon checks the macaroon of a client that resumes a
+	// stream. It may be nil.
+	checkMacaroon MacaroonChecker
+
+	// streams holds the resumable streams, indexed by their ID.
+	streams    map[string]*resumableStream
+	streamsMtx sync.Mutex
+
+	// budget limits the size of the messages buffered by all resumable
+	// streams.
+	budget resumeBudget
 }
 
 // pingPongEnabled returns true if a ping interval is set to enable sending and
@@ -152,27 +242,14 @@ func (p *WebsocketProxy) upgradeToWebSocketProxy(w http.ResponseWriter,
 		}
 	}()
 
+	conn.SetReadLimit(int64(p.maxFrameSize))
+
 	ctx, cancelFn := context.WithCancel(r.Context())
 	defer cancelFn()
 
-	requestForwarder := newRequestForwardingReader()
-	request, err := http.NewRequestWithContext(
-		ctx, r.Method, r.URL.String(), requestForwarder,
-	)
-	if err != nil {
-		p.logger.Errorf("WS: error preparing request:", err)
-		return
-	}
-
-	// Allow certain headers to be forwarded, either from source headers
-	// or the special Sec-Websocket-Protocol header field.
-	forwardHeaders(r.Header, request.Header)
-
-	// Also allow the target request method to be overwritten, as all
-	// WebSocket establishment calls MUST be GET requests.
-	if m := r.URL.Query().Get(MethodOverrideParam); m != "" {
-		request.Method = m
-	}
+	// connDone is closed once we stop writing to the connection.
+	connDone := make(chan struct{})
+	defer close(connDone)
 
 	// Is this a call to a client-streaming RPC method?
 	clientStreaming := false
@@ -182,17 +259,132 @@ func (p *WebsocketProxy) upgradeToWebSocketProxy(w http.ResponseWriter,
 		}
 	}
 
-	responseForwarder := newResponseForwardingWriter()
-	go func() {
-		<-ctx.Done()
-		responseForwarder.Close()
-		requestForwarder.CloseWriter()
-	}()
+	// The frames channel delivers the messages to write to the WebSocket.
+	// If the client resumes a stream, there's no new request to forward
+	// its messages to, so requestForwarder stays nil.
+	var (
+		frames           <-chan []byte
+		requestForwarder *requestForwardingReader
+	)
+	switch {
+	// The client wants to resume a stream it was connected to before, so
+	// we attach to that stream instead of starting a new request.
+	case requestResumeToken(r.Header) != "":
+		token := requestResumeToken(r.Header)
+		stream, lastSeq, err := p.lookupStream(token)
+		if err != nil {
+			p.closeWithError(conn, err)
+			return
+		}
 
-	go func() {
-		defer cancelFn()
-		p.backend.ServeHTTP(responseForwarder, request)
-	}()
+		// There's no backend request that would check the macaroon of
+		// a resumed stream, so we need to check it ourselves.
+		err = p.authenticateResume(ctx, stream, r.Header)
+		if err != nil {
+			p.closeWithError(conn, err)
+			return
+		}
+
+		var generation uint64
+		frames, generation, err = stream.attach(ctx, lastSeq)
+		if err != nil {
+			p.closeWithError(conn, err)
+			return
+		}
+		defer p.detachStream(stream, generation)
+
+		p.logger.Debugf("WS: resumed stream %v after message %d",
+			stream.id, lastSeq)
+
+	default:
+		requestForwarder = newRequestForwardingReader()
+
+		// A resumable stream needs to outlive the WebSocket
+		// connection, so its backend request isn't bound to the
+		// connection's context.
+		fullMethod, resumable := p.resumableMethod(r.URL.Path)
+		resumable = resumable && !clientStreaming
+		requestCtx := ctx
+		var stream *resumableStream
+		if resumable {
+			stream, err = p.newStream(
+				fullMethod, requestMacaroon(r.Header),
+			)
+			if err != nil {
+				p.logger.Errorf("WS: error creating stream: %v",
+					err)
+				return
+			}
+			requestCtx = stream.ctx
+		}
+
+		request, err := http.NewRequestWithContext(
+			requestCtx, r.Method, r.URL.String(), requestForwarder,
+		)
+		if err != nil {
+			p.logger.Errorf("WS: error preparing request:", err)
+			if stream != nil {
+				p.removeStream(stream)
+			}
+			return
+		}
+
+		// Allow certain headers to be forwarded, either from source
+		// headers or the special Sec-Websocket-Protocol header field.
+		forwardHeaders(r.Header, request.Header)
+
+		// Also allow the target request method to be overwritten, as
+		// all WebSocket establishment calls MUST be GET requests.
+		if m := r.URL.Query().Get(MethodOverrideParam); m != "" {
+			request.Method = m
+		}
+
+		responseForwarder := newResponseForwardingWriter(
+			p.maxFrameSize,
+		)
+		go func() {
+			<-requestCtx.Done()
+			responseForwarder.Close()
+			requestForwarder.CloseWriter()
+		}()
+
+		// The frames are read until the WebSocket connection is done.
+		// The frames of a resumable stream are always read until the
+		// response is complete, so we never quit early.
+		var (
+			backendDone                 = cancelFn
+			scanQuit    <-chan struct{} = connDone
+		)
+		if resumable {
+			backendDone = stream.cancel
+			scanQuit = nil
+		}
+		go func() {
+			defer backendDone()
+			p.backend.ServeHTTP(responseForwarder, request)
+		}()
+
+		scannedFrames := p.scanFrames(scanQuit, responseForwarder)
+		if !resumable {
+			frames = scannedFrames
+			break
+		}
+
+		// The messages of a resumable stream are buffered by the
+		// stream, from where we receive them with their resume token
+		// attached.
+		go stream.run(scannedFrames)
+
+		var generation uint64
+		frames, generation, err = stream.attach(ctx, 0)
+		if err != nil {
+			p.logger.Errorf("WS: error attaching to stream: %v",
+				err)
+			p.removeStream(stream)
+			return
+		}
+		defer p.detachStream(stream, generation)
+	}
 
 	// Read loop: Take messages from websocket and write them to the payload
 	// channel. This needs to be its own goroutine because for non-client
@@ -253,6 +445,12 @@ func (p *WebsocketProxy) upgradeToWebSocketProxy(w http.ResponseWriter,
 				payload = newPayload
 			}
 
+			// A resumed stream already has its request, so we
+			// discard anything the client sends.
+			if requestForwarder == nil {
+				continue
+			}
+
 			_, err := requestForwarder.Write(payload)
 			if err != nil {
 				p.logger.Errorf("WS: error writing message "+
@@ -328,27 +526,102 @@ func (p *WebsocketProxy) upgradeToWebSocketProxy(w http.ResponseWriter,
 		}()
 	}
 
-	// Write loop: Take messages from the response forwarder and write them
-	// to the WebSocket.
-	for responseForwarder.Scan() {
-		if len(responseForwarder.Bytes()) == 0 {
-			p.logger.Errorf("WS: empty scan: %v",
-				responseForwarder.Err())
+	// Write loop: Take messages from the frames channel and write them to
+	// the WebSocket, sending heartbeats in between if enabled.
+	p.writeFrames(conn, frames)
+}
+
+// scanFrames reads the messages written by the backend from the response
+// forwarder and delivers them on the returned channel, which is closed once
+// the response is complete. The quit channel signals that nobody is reading
+// the frames anymore.
+func (p *WebsocketProxy) scanFrames(quit <-chan struct{},
+	responseForwarder *responseForwardingWriter) <-chan []byte {
+
+	frames := make(chan []byte)
+	go func() {
+		defer close(frames)
+
+		for responseForwarder.Scan() {
+			if len(responseForwarder.Bytes()) == 0 {
+				p.logger.Errorf("WS: empty scan: %v",
+					responseForwarder.Err())
+
+				continue
+			}
+
+			// The scanner reuses its buffer, so we need to copy
+			// the message before handing it off.
+			frame := make([]byte, len(responseForwarder.Bytes()))
+			copy(frame, responseForwarder.Bytes())
 
-			continue
+			select {
+			case frames <- frame:
+			case <-quit:
+				return
+			}
 		}
 
-		err := conn.WriteMessage(
-			websocket.TextMessage, responseForwarder.Bytes(),
-		)
+		err := responseForwarder.Err()
+		if err != nil && !IsClosedConnError(err) {
+			p.logger.Errorf("WS: scanner err: %v", err)
+		}
+	}()
+
+	return frames
+}
+
+// writeFrames writes the messages from the frames channel to the WebSocket
+// until the channel is closed. If heartbeats are enabled, a heartbeat message
+// is sent whenever the stream was idle for the heartbeat interval.
+func (p *WebsocketProxy) writeFrames(conn *websocket.Conn,
+	frames <-chan []byte) {
+
+	var heartbeat <-chan time.Time
+	if p.heartbeatInterval > 0 {
+		ticker := time.NewTicker(p.heartbeatInterval)
+		defer ticker.Stop()
+
+		heartbeat = ticker.C
+	}
+
+	lastWrite := time.Now()
+	for {
+		var frame []byte
+		select {
+		case newFrame, ok := <-frames:
+			if !ok {
+				return
+			}
+			frame = newFrame
+
+		case <-heartbeat:
+			if time.Since(lastWrite) < p.heartbeatInterval {
+				continue
+			}
+			frame = []byte(HeartbeatMessage)
+		}
+
+		err := conn.WriteMessage(websocket.TextMessage, frame)
 		if err != nil {
 			p.logger.Errorf("WS: error writing message: %v", err)
 			return
 		}
+		lastWrite = time.Now()
 	}
-	if err := responseForwarder.Err(); err != nil && !IsClosedConnError(err) {
-		p.logger.Errorf("WS: scanner err: %v", err)
-	}
+}
+
+// closeWithError sends a close message with the given error as the reason to
+// the client.
+func (p *WebsocketProxy) closeWithError(conn *websocket.Conn, err error) {
+	p.logger.Debugf("WS: closing connection: %v", err)
+
+	msg := websocket.FormatCloseMessage(
+		websocket.ClosePolicyViolation, err.Error(),
+	)
+	_ = conn.WriteControl(
+		websocket.CloseMessage, msg, time.Now().Add(time.Second),
+	)
 }
 
 // forwardHeaders forwards certain allowed header fields from the source request
@@ -369,18 +642,39 @@ func forwardHeaders(source, target http.Header) {
 	// requests. We need to allow them to submit the macaroon as a WS
 	// protocol, which is the only allowed header. Set any "protocols" we
 	// declare valid as header fields on the forwarded request.
-	protocol := source.Get(HeaderWebSocketProtocol)
 	for key := range defaultProtocolsToAllow {
-		if strings.HasPrefix(protocol, key) {
-			// The format is "<protocol name>+<value>". We know the
-			// protocol string starts with the name so we only need
-			// to set the value.
-			values := strings.Split(
-				protocol, WebSocketProtocolDelimiter,
-			)
-			target.Set(key, values[1])
+		if value, ok := protocolValue(source, key); ok {
+			target.Set(key, value)
+		}
+	}
+}
+
+// protocolValue returns the value of an additional header field that is
+// transported inside of the Sec-Websocket-Protocol header field. The header
+// field can hold several comma separated protocols, each of the format
+// "<protocol name>+<value>".
+func protocolValue(header http.Header, key string) (string, bool) {
+	prefix := key + WebSocketProtocolDelimiter
+	for _, protocols := range header.Values(HeaderWebSocketProtocol) {
+		for _, protocol := range strings.Split(protocols, ",") {
+			protocol = strings.TrimSpace(protocol)
+			if strings.HasPrefix(protocol, prefix) {
+				return strings.TrimPrefix(protocol, prefix), true
+			}
 		}
 	}
+
+	return "", false
+}
+
+// requestMacaroon returns the macaroon of a request, which is either set as a
+// header field or transported inside of the Sec-Websocket-Protocol header
+// field.
+func requestMacaroon(header http.Header) string {
+	forwarded := make(http.Header)
+	forwardHeaders(header, forwarded)
+
+	return forwarded.Get(headerMacaroon)
 }
 
 // newRequestForwardingReader creates a new request forwarding pipe.
@@ -410,12 +704,17 @@ func (r *requestForwardingReader) CloseWriter() {
 }
 
 // newResponseForwardingWriter creates a new http.ResponseWriter that intercepts
-// what's written to it and presents it through a bufio.Scanner interface.
-func newResponseForwardingWriter() *responseForwardingWriter {
+// what's written to it and presents it through a bufio.Scanner interface. The
+// scanner accepts messages of up to maxFrameSize bytes.
+func newResponseForwardingWriter(maxFrameSize int) *responseForwardingWriter {
 	r, w := io.Pipe()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxFrameSize)
+
 	return &responseForwardingWriter{
 		Writer:  w,
-		Scanner: bufio.NewScanner(r),
+		Scanner: scanner,
 		pipeR:   r,
 		pipeW:   w,
 		header:  http.Header{},
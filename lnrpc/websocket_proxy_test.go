@@ -0,0 +1,310 @@
+package lnrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btclog"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// dialProxy connects a WebSocket client to the given path of the test server,
+// sending the given additional header fields.
+func dialProxy(t *testing.T, server *httptest.Server, path string,
+	header http.Header) *websocket.Conn {
+
+	u := "ws" + strings.TrimPrefix(server.URL, "http") + path
+	conn, _, err := websocket.DefaultDialer.Dial(u, header)
+	require.NoError(t, err)
+
+	return conn
+}
+
+// protocolHeader returns a header that transports the given additional header
+// fields inside of the Sec-Websocket-Protocol header field, like a browser
+// does.
+func protocolHeader(fields map[string]string) http.Header {
+	protocols := make([]string, 0, len(fields))
+	for key, value := range fields {
+		protocols = append(
+			protocols, key+WebSocketProtocolDelimiter+value,
+		)
+	}
+
+	return http.Header{
+		HeaderWebSocketProtocol: []string{
+			strings.Join(protocols, ", "),
+		},
+	}
+}
+
+// assertClosedWithError asserts that the proxy closes the connection because
+// of a policy violation.
+func assertClosedWithError(t *testing.T, conn *websocket.Conn) {
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	_, _, err := conn.ReadMessage()
+	require.True(
+		t, websocket.IsCloseError(err, websocket.ClosePolicyViolation),
+	)
+}
+
+// readFrame reads a JSON message from the WebSocket.
+func readFrame(t *testing.T, conn *websocket.Conn) map[string]interface{} {
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	_, msg, err := conn.ReadMessage()
+	require.NoError(t, err)
+
+	var frame map[string]interface{}
+	require.NoError(t, json.Unmarshal(msg, &frame))
+
+	return frame
+}
+
+// TestWebSocketProxyResume tests that a client can resume a resumable stream
+// after reconnecting and receives the messages it missed.
+func TestWebSocketProxyResume(t *testing.T) {
+	t.Parallel()
+
+	next := make(chan struct{})
+	backend := http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		fmt.Fprintln(w, `{"result":{"n":1}}`)
+
+		select {
+		case <-next:
+		case <-r.Context().Done():
+			return
+		}
+
+		fmt.Fprintln(w, `{"result":{"n":2}}`)
+		fmt.Fprintln(w, `{"result":{"n":3}}`)
+	})
+
+	// The macaroon checker rejects revoked macaroons.
+	const fullMethod = "/test.Service/Stream"
+	checkMacaroon := func(_ context.Context, macaroon,
+		method string) error {
+
+		require.Equal(t, fullMethod, method)
+		if macaroon == "revoked" {
+			return errors.New("macaroon revoked")
+		}
+
+		return nil
+	}
+
+	proxy := NewWebSocketProxy(
+		backend, btclog.Disabled, 0, 0, nil,
+		WithResumableStreams(
+			time.Minute, []ResumableStreamURI{{
+				Pattern:    regexp.MustCompile("^/stream$"),
+				FullMethod: fullMethod,
+			}}, checkMacaroon,
+		),
+	)
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	// The first message carries the resume token.
+	conn := dialProxy(t, server, "/stream", http.Header{
+		headerMacaroon: []string{"mac"},
+	})
+	frame := readFrame(t, conn)
+	require.Equal(t, map[string]interface{}{"n": 1.0}, frame["result"])
+
+	token, ok := frame[ResumeTokenField].(string)
+	require.True(t, ok)
+	require.NoError(t, conn.Close())
+
+	// The backend keeps going while the client is disconnected.
+	close(next)
+
+	// The stream can't be resumed without the macaroon it was started
+	// with.
+	conn = dialProxy(t, server, "/stream", http.Header{
+		HeaderResumeToken: []string{token},
+	})
+	assertClosedWithError(t, conn)
+	require.NoError(t, conn.Close())
+
+	conn = dialProxy(t, server, "/stream", http.Header{
+		HeaderResumeToken: []string{token},
+		headerMacaroon:    []string{"other"},
+	})
+	assertClosedWithError(t, conn)
+	require.NoError(t, conn.Close())
+
+	// Resuming delivers the remaining messages, and the connection is
+	// closed once the stream is done. Like a browser, we pass the token
+	// and the macaroon as WebSocket protocols.
+	conn = dialProxy(t, server, "/stream", protocolHeader(
+		map[string]string{
+			HeaderResumeToken: token,
+			headerMacaroon:    "mac",
+		},
+	))
+	defer conn.Close()
+
+	for n := 2; n <= 3; n++ {
+		frame = readFrame(t, conn)
+		require.Equal(
+			t, map[string]interface{}{"n": float64(n)},
+			frame["result"],
+		)
+		require.NotEqual(t, token, frame[ResumeTokenField])
+	}
+
+	_, _, err := conn.ReadMessage()
+	require.Error(t, err)
+
+	// An unknown stream can't be resumed.
+	conn = dialProxy(t, server, "/stream", http.Header{
+		HeaderResumeToken: []string{"unknown-1"},
+		headerMacaroon:    []string{"mac"},
+	})
+	defer conn.Close()
+
+	assertClosedWithError(t, conn)
+}
+
+// TestWebSocketProxyResumeRevoked tests that a stream can't be resumed once
+// its macaroon is no longer accepted.
+func TestWebSocketProxyResumeRevoked(t *testing.T) {
+	t.Parallel()
+
+	backend := http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		fmt.Fprintln(w, `{"result":{"n":1}}`)
+		<-r.Context().Done()
+	})
+
+	proxy := NewWebSocketProxy(
+		backend, btclog.Disabled, 0, 0, nil,
+		WithResumableStreams(
+			time.Minute, []ResumableStreamURI{{
+				Pattern:    regexp.MustCompile("^/stream$"),
+				FullMethod: "/test.Service/Stream",
+			}}, func(context.Context, string, string) error {
+				return errors.New("macaroon revoked")
+			},
+		),
+	)
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	header := http.Header{headerMacaroon: []string{"mac"}}
+	conn := dialProxy(t, server, "/stream", header)
+	frame := readFrame(t, conn)
+	token, ok := frame[ResumeTokenField].(string)
+	require.True(t, ok)
+	require.NoError(t, conn.Close())
+
+	header.Set(HeaderResumeToken, token)
+	conn = dialProxy(t, server, "/stream", header)
+	defer conn.Close()
+
+	assertClosedWithError(t, conn)
+}
+
+// TestResumableStreamBufferLimit tests that a resumable stream drops its
+// oldest messages once it buffers more bytes than allowed.
+func TestResumableStreamBufferLimit(t *testing.T) {
+	t.Parallel()
+
+	budget := &resumeBudget{}
+	stream := &resumableStream{
+		budget:   budget,
+		firstSeq: 1,
+		update:   make(chan struct{}),
+	}
+
+	// Send messages that add up to twice the per-stream limit.
+	const frameSize = 1024 * 1024
+	numFrames := 2 * maxStreamBufferBytes / frameSize
+	frames := make(chan []byte)
+	go func() {
+		defer close(frames)
+
+		for i := 0; i < numFrames; i++ {
+			frames <- make([]byte, frameSize)
+		}
+	}()
+	stream.run(frames)
+
+	require.Equal(t, maxStreamBufferBytes, stream.bufferedBytes)
+	require.Len(t, stream.frames, maxStreamBufferBytes/frameSize)
+	require.EqualValues(
+		t, numFrames-len(stream.frames)+1, stream.firstSeq,
+	)
+	require.Equal(t, maxStreamBufferBytes, budget.used)
+
+	// Releasing the buffer returns its bytes to the budget.
+	stream.releaseBuffer()
+	require.Zero(t, budget.used)
+}
+
+// TestWebSocketProxyHeartbeat tests that heartbeats are sent on an idle
+// stream.
+func TestWebSocketProxyHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	backend := http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		<-r.Context().Done()
+	})
+
+	proxy := NewWebSocketProxy(
+		backend, btclog.Disabled, 0, 0, nil,
+		WithHeartbeatInterval(50*time.Millisecond),
+	)
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	conn := dialProxy(t, server, "/stream", nil)
+	defer conn.Close()
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, msg, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, HeartbeatMessage, string(msg))
+}
+
+// TestWebSocketProxyMaxFrameSize tests that messages larger than the default
+// buffer size of the scanner are delivered.
+func TestWebSocketProxyMaxFrameSize(t *testing.T) {
+	t.Parallel()
+
+	largeValue := strings.Repeat("a", 100_000)
+	backend := http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		fmt.Fprintf(w, `{"result":{"value":%q}}`+"\n", largeValue)
+	})
+
+	proxy := NewWebSocketProxy(backend, btclog.Disabled, 0, 0, nil)
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	conn := dialProxy(t, server, "/stream", nil)
+	defer conn.Close()
+
+	frame := readFrame(t, conn)
+	require.Equal(
+		t, map[string]interface{}{"value": largeValue},
+		frame["result"],
+	)
+}
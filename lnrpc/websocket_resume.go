@@ -0,0 +1,490 @@
+package lnrpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// HeaderResumeToken is the header field that holds the resume token of
+	// the last message a client received on a resumable stream, to resume
+	// that stream after reconnecting. As browsers can't set header fields
+	// on WebSocket requests, it can also be transported inside of the
+	// Sec-Websocket-Protocol header field. The token is deliberately not
+	// accepted as a query parameter, where it would end up in logs.
+	HeaderResumeToken = "Resume-Token"
+
+	// ResumeTokenField is the name of the JSON field that is added to
+	// every message of a resumable stream and holds the message's resume
+	// token.
+	ResumeTokenField = "resume_token"
+
+	// resumeBufferSize is the number of most recent messages a resumable
+	// stream keeps to be replayed to a resuming client.
+	resumeBufferSize = 1024
+
+	// maxStreamBufferBytes is the maximum total size of the messages a
+	// single resumable stream keeps to be replayed.
+	maxStreamBufferBytes = 8 * 1024 * 1024
+
+	// maxResumeBufferBytes is the maximum total size of the messages all
+	// resumable streams keep to be replayed.
+	maxResumeBufferBytes = 64 * 1024 * 1024
+)
+
+var (
+	// ErrInvalidResumeToken is returned when a client tries to resume a
+	// stream with a malformed resume token.
+	ErrInvalidResumeToken = errors.New("invalid resume token")
+
+	// ErrUnknownStream is returned when a client tries to resume a stream
+	// that doesn't exist, for example because it expired.
+	ErrUnknownStream = errors.New("unknown or expired stream")
+
+	// ErrResumeGap is returned when a client tries to resume a stream from
+	// a message that was already dropped from the stream's buffer.
+	ErrResumeGap = errors.New("messages since resume token no longer " +
+		"available")
+
+	// ErrResumeUnauthorized is returned when a client tries to resume a
+	// stream with a different macaroon than the one the stream was
+	// started with.
+	ErrResumeUnauthorized = errors.New("resume token not valid for " +
+		"macaroon")
+)
+
+// ResumableStreamURI describes a server-streaming RPC that can be resumed
+// through the WebsocketProxy.
+type ResumableStreamURI struct {
+	// Pattern matches the REST URI of the RPC.
+	Pattern *regexp.Regexp
+
+	// FullMethod is the full gRPC method name of the RPC. The macaroon
+	// of a client that resumes a stream is checked against the
+	// permissions of this method.
+	FullMethod string
+}
+
+// MacaroonChecker checks that the given hex encoded macaroon grants the
+// permissions needed to call the given gRPC method.
+type MacaroonChecker func(ctx context.Context, macaroon,
+	fullMethod string) error
+
+// resumeBudget limits the total size of the messages buffered by all
+// resumable streams of a proxy.
+type resumeBudget struct {
+	mtx  sync.Mutex
+	used int
+}
+
+// add accounts for the given number of bytes, which may be negative to
+// release them. It returns true if the limit is exceeded afterwards.
+func (b *resumeBudget) add(n int) bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.used += n
+
+	return b.used > maxResumeBufferBytes
+}
+
+// resumableMethod returns the full gRPC method name of the RPC at the given
+// URI path if it can be resumed.
+func (p *WebsocketProxy) resumableMethod(path string) (string, bool) {
+	if p.resumeWindow <= 0 {
+		return "", false
+	}
+
+	for _, uri := range p.resumableURIs {
+		if uri.Pattern.MatchString(path) {
+			return uri.FullMethod, true
+		}
+	}
+
+	return "", false
+}
+
+// newStream creates and registers a new resumable stream of the given gRPC
+// method. The stream can only be resumed with the given macaroon.
+func (p *WebsocketProxy) newStream(fullMethod,
+	macaroon string) (*resumableStream, error) {
+
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &resumableStream{
+		id:           hex.EncodeToString(id[:]),
+		fullMethod:   fullMethod,
+		macaroonHash: sha256.Sum256([]byte(macaroon)),
+		budget:       &p.budget,
+		ctx:          ctx,
+		cancel:       cancel,
+		firstSeq:     1,
+		update:       make(chan struct{}),
+	}
+
+	p.streamsMtx.Lock()
+	p.streams[stream.id] = stream
+	p.streamsMtx.Unlock()
+
+	return stream, nil
+}
+
+// lookupStream returns the stream identified by the given resume token, along
+// with the sequence number of the last message the client received.
+func (p *WebsocketProxy) lookupStream(token string) (*resumableStream,
+	uint64, error) {
+
+	id, lastSeq, err := parseResumeToken(token)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	p.streamsMtx.Lock()
+	stream, ok := p.streams[id]
+	p.streamsMtx.Unlock()
+
+	if !ok {
+		return nil, 0, ErrUnknownStream
+	}
+
+	return stream, lastSeq, nil
+}
+
+// authenticateResume checks that a client that resumes the given stream
+// presents the macaroon the stream was started with, and that the macaroon
+// still grants the permissions of the stream's RPC, as it could have been
+// revoked or expired since.
+func (p *WebsocketProxy) authenticateResume(ctx context.Context,
+	stream *resumableStream, header http.Header) error {
+
+	macaroon := requestMacaroon(header)
+	macaroonHash := sha256.Sum256([]byte(macaroon))
+	if subtle.ConstantTimeCompare(
+		macaroonHash[:], stream.macaroonHash[:],
+	) != 1 {
+
+		return ErrResumeUnauthorized
+	}
+
+	if p.checkMacaroon == nil {
+		return nil
+	}
+
+	return p.checkMacaroon(ctx, macaroon, stream.fullMethod)
+}
+
+// removeStream unregisters the given stream and cancels its backend request.
+func (p *WebsocketProxy) removeStream(stream *resumableStream) {
+	p.streamsMtx.Lock()
+	delete(p.streams, stream.id)
+	p.streamsMtx.Unlock()
+
+	stream.cancel()
+	stream.releaseBuffer()
+}
+
+// detachStream is called when the client connection with the given
+// generation stops reading from the stream. Unless another connection took
+// over the stream in the meantime, the stream is removed if the client
+// doesn't resume it within the resume window.
+func (p *WebsocketProxy) detachStream(stream *resumableStream,
+	generation uint64) {
+
+	stream.detach(generation, p.resumeWindow, func() {
+		p.logger.Debugf("WS: stream %v expired", stream.id)
+		p.removeStream(stream)
+	})
+}
+
+// resumableStream is the response of a server-streaming RPC call whose backend
+// request outlives the WebSocket connection it was started on. It buffers the
+// most recent messages so that a client can reconnect and resume receiving
+// them after the last message it got.
+type resumableStream struct {
+	id string
+
+	// fullMethod is the full gRPC method name of the stream's RPC.
+	fullMethod string
+
+	// macaroonHash is the hash of the macaroon the stream was started
+	// with, which a resuming client must present as well.
+	macaroonHash [32]byte
+
+	// budget limits the size of the buffered messages of all streams.
+	budget *resumeBudget
+
+	// ctx is the context of the backend request, which is canceled when
+	// the stream is removed.
+	ctx    context.Context
+	cancel func()
+
+	mtx sync.Mutex
+
+	// frames holds the buffered messages. The message at index i has the
+	// sequence number firstSeq+i.
+	frames   [][]byte
+	firstSeq uint64
+
+	// bufferedBytes is the total size of the buffered messages.
+	bufferedBytes int
+
+	// done is true once the backend response is complete.
+	done bool
+
+	// expired is true once the stream was removed after not being resumed
+	// within the resume window.
+	expired bool
+
+	// released is true once the stream was removed and doesn't buffer
+	// messages anymore.
+	released bool
+
+	// update is closed and replaced whenever a message is added, the
+	// stream is done, or another connection takes over the stream.
+	update chan struct{}
+
+	// generation is incremented every time a connection attaches to the
+	// stream. Only the connection of the current generation receives
+	// messages.
+	generation uint64
+
+	// expiry is the timer that removes the stream if it isn't resumed.
+	expiry *time.Timer
+}
+
+// notify wakes up the connection waiting for updates of the stream.
+//
+// NOTE: The stream's mutex must be held.
+func (s *resumableStream) notify() {
+	close(s.update)
+	s.update = make(chan struct{})
+}
+
+// run buffers the messages delivered on the given channel until it is closed.
+// The oldest messages are dropped once the stream buffers more messages or
+// bytes than allowed, or once the buffers of all streams exceed their limit.
+func (s *resumableStream) run(frames <-chan []byte) {
+	for frame := range frames {
+		s.mtx.Lock()
+		if s.released {
+			s.mtx.Unlock()
+			continue
+		}
+
+		s.frames = append(s.frames, frame)
+		s.bufferedBytes += len(frame)
+		overBudget := s.budget.add(len(frame))
+
+		for len(s.frames) > 1 && (len(s.frames) > resumeBufferSize ||
+			s.bufferedBytes > maxStreamBufferBytes || overBudget) {
+
+			dropped := len(s.frames[0])
+			s.frames = s.frames[1:]
+			s.firstSeq++
+			s.bufferedBytes -= dropped
+			overBudget = s.budget.add(-dropped)
+		}
+		s.notify()
+		s.mtx.Unlock()
+	}
+
+	s.mtx.Lock()
+	s.done = true
+	s.notify()
+	s.mtx.Unlock()
+}
+
+// releaseBuffer drops all buffered messages of the stream.
+func (s *resumableStream) releaseBuffer() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.budget.add(-s.bufferedBytes)
+	s.firstSeq += uint64(len(s.frames))
+	s.frames = nil
+	s.bufferedBytes = 0
+	s.released = true
+}
+
+// attach attaches a client connection to the stream. The messages following
+// the one with the given sequence number are delivered on the returned
+// channel, each carrying its resume token. The channel is closed once all
+// messages were delivered and the stream is done, when the context is
+// canceled, or when another connection takes over the stream. The returned
+// generation identifies the connection when detaching.
+func (s *resumableStream) attach(ctx context.Context,
+	lastSeq uint64) (<-chan []byte, uint64, error) {
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	switch {
+	case s.expired:
+		return nil, 0, ErrUnknownStream
+
+	case lastSeq+1 < s.firstSeq:
+		return nil, 0, ErrResumeGap
+
+	case lastSeq >= s.firstSeq+uint64(len(s.frames)):
+		return nil, 0, ErrInvalidResumeToken
+	}
+
+	if s.expiry != nil {
+		s.expiry.Stop()
+		s.expiry = nil
+	}
+
+	// Take over the stream from any previous connection.
+	s.generation++
+	generation := s.generation
+	s.notify()
+
+	frames := make(chan []byte)
+	go func() {
+		defer close(frames)
+
+		nextSeq := lastSeq + 1
+		for {
+			s.mtx.Lock()
+			if s.generation != generation ||
+				nextSeq < s.firstSeq {
+
+				s.mtx.Unlock()
+				return
+			}
+
+			idx := nextSeq - s.firstSeq
+			if idx < uint64(len(s.frames)) {
+				frame := injectResumeToken(
+					s.frames[idx],
+					formatResumeToken(s.id, nextSeq),
+				)
+				s.mtx.Unlock()
+
+				select {
+				case frames <- frame:
+				case <-ctx.Done():
+					return
+				}
+
+				nextSeq++
+				continue
+			}
+
+			if s.done {
+				s.mtx.Unlock()
+				return
+			}
+
+			update := s.update
+			s.mtx.Unlock()
+
+			select {
+			case <-update:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return frames, generation, nil
+}
+
+// detach starts the expiry timer of the stream if the connection with the
+// given generation is still the current one.
+func (s *resumableStream) detach(generation uint64, window time.Duration,
+	onExpiry func()) {
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.generation != generation {
+		return
+	}
+
+	s.expiry = time.AfterFunc(window, func() {
+		s.mtx.Lock()
+		if s.generation != generation || s.expired {
+			s.mtx.Unlock()
+			return
+		}
+		s.expired = true
+		s.mtx.Unlock()
+
+		onExpiry()
+	})
+}
+
+// formatResumeToken returns the resume token of the message with the given
+// sequence number of a stream. The token only consists of characters that are
+// allowed in the Sec-Websocket-Protocol header field.
+func formatResumeToken(id string, seq uint64) string {
+	return fmt.Sprintf("%s-%d", id, seq)
+}
+
+// parseResumeToken parses a resume token into the ID of its stream and the
+// sequence number of its message.
+func parseResumeToken(token string) (string, uint64, error) {
+	parts := strings.Split(token, "-")
+	if len(parts) != 2 || parts[0] == "" {
+		return "", 0, ErrInvalidResumeToken
+	}
+
+	seq, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return "", 0, ErrInvalidResumeToken
+	}
+
+	return parts[0], seq, nil
+}
+
+// requestResumeToken returns the resume token of a request, which is either
+// set as a header field or transported inside of the Sec-Websocket-Protocol
+// header field.
+func requestResumeToken(header http.Header) string {
+	if token := header.Get(HeaderResumeToken); token != "" {
+		return token
+	}
+
+	token, _ := protocolValue(header, HeaderResumeToken)
+	return token
+}
+
+// injectResumeToken adds the resume token as a field to the JSON object of a
+// message. Messages that aren't JSON objects are returned unchanged.
+func injectResumeToken(frame []byte, token string) []byte {
+	trimmed := bytes.TrimSpace(frame)
+	if len(trimmed) < 2 || trimmed[0] != '{' {
+		return frame
+	}
+
+	rest := bytes.TrimSpace(trimmed[1:])
+
+	var b bytes.Buffer
+	b.WriteByte('{')
+	b.WriteString(strconv.Quote(ResumeTokenField))
+	b.WriteByte(':')
+	b.WriteString(strconv.Quote(token))
+	if rest[0] != '}' {
+		b.WriteByte(',')
+	}
+	b.Write(rest)
+
+	return b.Bytes()
+}
@@ -2,6 +2,7 @@ package wtclientrpc
 
 import (
 	"github.com/btcsuite/btclog"
+	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/watchtower/wtclient"
 )
@@ -23,11 +24,20 @@ type Config struct {
 	// we'll interact through the watchtower RPC subserver.
 	AnchorClient wtclient.Client
 
+	// ChanStateDB is the channel state database, used to look up the
+	// number of revoked states of a channel whose backups are audited.
+	ChanStateDB *channeldb.ChannelStateDB
+
 	// Resolver is a custom resolver that will be used to resolve watchtower
 	// addresses to ensure we don't leak any information when running over
 	// non-clear networks, e.g. Tor, etc.
 	Resolver lncfg.TCPResolver
 
+	// LookupHost resolves a domain name to all of its IP addresses, so
+	// that each of them can be dialed when connecting to a watchtower that
+	// was added by its domain name.
+	LookupHost func(host string) ([]string, error)
+
 	// Log is the logger instance we should log output to.
 	Log btclog.Logger
 }
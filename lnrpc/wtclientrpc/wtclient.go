@@ -6,12 +6,16 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tor"
 	"github.com/lightningnetwork/lnd/watchtower"
 	"github.com/lightningnetwork/lnd/watchtower/wtclient"
 	"github.com/lightningnetwork/lnd/watchtower/wtdb"
@@ -57,6 +61,14 @@ var (
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/wtclientrpc.WatchtowerClient/AuditChannelBackups": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/wtclientrpc.WatchtowerClient/CleanupSessions": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 	}
 
 	// ErrWtclientNotActive signals that RPC calls cannot be processed
@@ -192,28 +204,85 @@ func (c *WatchtowerClient) AddTower(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
-	addr, err := lncfg.ParseAddressString(
-		req.Address, strconv.Itoa(watchtower.DefaultPeerPort),
-		c.cfg.Resolver,
-	)
+	addrs, err := c.resolveTowerAddrs(req.Address)
 	if err != nil {
 		return nil, fmt.Errorf("invalid address %v: %v", req.Address, err)
 	}
 
-	towerAddr := &lnwire.NetAddress{
-		IdentityKey: pubKey,
-		Address:     addr,
+	for _, addr := range addrs {
+		towerAddr := &lnwire.NetAddress{
+			IdentityKey: pubKey,
+			Address:     addr,
+		}
+
+		// TODO(conner): make atomic via multiplexed client
+		if err := c.cfg.Client.AddTower(towerAddr); err != nil {
+			return nil, err
+		}
+		err := c.cfg.AnchorClient.AddTower(towerAddr)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// TODO(conner): make atomic via multiplexed client
-	if err := c.cfg.Client.AddTower(towerAddr); err != nil {
-		return nil, err
+	return &AddTowerResponse{}, nil
+}
+
+// resolveTowerAddrs parses the given watchtower address. If its host is a
+// domain name, it's resolved to all of its IP addresses, so that the watchtower
+// remains reachable as long as any of them is.
+func (c *WatchtowerClient) resolveTowerAddrs(address string) ([]net.Addr,
+	error) {
+
+	defaultPort := strconv.Itoa(watchtower.DefaultPeerPort)
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		host, port = address, defaultPort
 	}
-	if err := c.cfg.AnchorClient.AddTower(towerAddr); err != nil {
-		return nil, err
+
+	// Addresses with an explicit network, IP addresses and onion addresses
+	// are parsed as is.
+	if c.cfg.LookupHost == nil || strings.Contains(address, "://") ||
+		net.ParseIP(host) != nil || tor.IsOnionHost(host) {
+
+		addr, err := lncfg.ParseAddressString(
+			address, defaultPort, c.cfg.Resolver,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return []net.Addr{addr}, nil
 	}
 
-	return &AddTowerResponse{}, nil
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %v: %v", port, err)
+	}
+
+	ips, err := c.cfg.LookupHost(host)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve %v: %v", host, err)
+	}
+
+	var addrs []net.Addr
+	for _, ip := range ips {
+		parsedIP := net.ParseIP(ip)
+		if parsedIP == nil {
+			continue
+		}
+
+		addrs = append(addrs, &net.TCPAddr{
+			IP:   parsedIP,
+			Port: portNum,
+		})
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no IP addresses found for %v", host)
+	}
+
+	return addrs, nil
 }
 
 // RemoveTower removes a watchtower from being considered for future session
@@ -275,12 +344,22 @@ func (c *WatchtowerClient) ListTowers(ctx context.Context,
 		return nil, err
 	}
 
-	// Filter duplicates.
+	// Filter duplicates. As both clients dial the towers independently,
+	// we'll report the worse backoff state and the combined health of the
+	// two.
 	towers := make(map[wtdb.TowerID]*wtclient.RegisteredTower)
 	for _, tower := range anchorTowers {
 		towers[tower.Tower.ID] = tower
 	}
 	for _, tower := range legacyTowers {
+		if anchorTower, ok := towers[tower.Tower.ID]; ok {
+			tower.BackoffState = worseBackoffState(
+				tower.BackoffState, anchorTower.BackoffState,
+			)
+			tower.Health = combineHealth(
+				tower.Health, anchorTower.Health,
+			)
+		}
 		towers[tower.Tower.ID] = tower
 	}
 
@@ -310,6 +389,19 @@ func (c *WatchtowerClient) GetTowerInfo(ctx context.Context,
 	tower, err = c.cfg.Client.LookupTower(pubKey)
 	if err == wtdb.ErrTowerNotFound {
 		tower, err = c.cfg.AnchorClient.LookupTower(pubKey)
+	} else if err == nil {
+		// The tower is known to the legacy client, so it may also be
+		// dialed by the anchor client. Report the worse backoff state
+		// and the combined health of the two.
+		anchorTower, anchorErr := c.cfg.AnchorClient.LookupTower(pubKey)
+		if anchorErr == nil {
+			tower.BackoffState = worseBackoffState(
+				tower.BackoffState, anchorTower.BackoffState,
+			)
+			tower.Health = combineHealth(
+				tower.Health, anchorTower.Health,
+			)
+		}
 	}
 	if err != nil {
 		return nil, err
@@ -385,6 +477,104 @@ func (c *WatchtowerClient) Policy(ctx context.Context,
 	}, nil
 }
 
+// AuditChannelBackups returns which revoked states of a channel were backed up
+// to which watchtower session, and verifies that all revoked states of the
+// channel are covered by a backup.
+func (c *WatchtowerClient) AuditChannelBackups(ctx context.Context,
+	req *AuditChannelBackupsRequest) (*AuditChannelBackupsResponse, error) {
+
+	if err := c.isActive(); err != nil {
+		return nil, err
+	}
+
+	if req.ChanPoint == nil {
+		return nil, errors.New("chan_point must be set")
+	}
+	txid, err := lnrpc.GetChanPointFundingTxid(req.ChanPoint)
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.OutPoint{
+		Hash:  *txid,
+		Index: req.ChanPoint.OutputIndex,
+	}
+
+	channel, err := c.cfg.ChanStateDB.FetchChannel(nil, chanPoint)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch channel %v: %v",
+			chanPoint, err)
+	}
+
+	// Every commitment of the remote party below its current one has been
+	// revoked, and should have been backed up. Both clients share the
+	// same database, so either of them can be used for the audit.
+	numRevoked := channel.RemoteCommitment.CommitHeight
+	chanID := lnwire.NewChanIDFromOutPoint(&chanPoint)
+
+	var audit *wtclient.BackupAudit
+	if numRevoked > req.StartHeight {
+		audit, err = c.cfg.Client.AuditChannel(
+			chanID, req.StartHeight, numRevoked-1,
+		)
+	} else {
+		// There are no revoked states to verify, so we only list the
+		// existing backups by auditing an empty range.
+		audit, err = c.cfg.Client.AuditChannel(chanID, 1, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rpcBackups := make([]*ChannelBackup, 0, len(audit.Backups))
+	for _, backup := range audit.Backups {
+		sessionID := backup.SessionID
+		towerKey := backup.Tower.IdentityKey.SerializeCompressed()
+		rpcBackups = append(rpcBackups, &ChannelBackup{
+			CommitHeight: backup.CommitHeight,
+			SessionId:    sessionID[:],
+			TowerPubkey:  towerKey,
+		})
+	}
+
+	rpcGaps := make([]*CommitHeightRange, 0, len(audit.Gaps))
+	for _, gap := range audit.Gaps {
+		rpcGaps = append(rpcGaps, &CommitHeightRange{
+			StartHeight: gap.Start,
+			EndHeight:   gap.End,
+		})
+	}
+
+	return &AuditChannelBackupsResponse{
+		Backups:          rpcBackups,
+		NumRevokedStates: numRevoked,
+		Gaps:             rpcGaps,
+		FullyCovered:     len(rpcGaps) == 0,
+	}, nil
+}
+
+// CleanupSessions deletes all watchtower sessions that are no longer needed
+// because all channels they backed up states of were cooperatively closed.
+func (c *WatchtowerClient) CleanupSessions(ctx context.Context,
+	req *CleanupSessionsRequest) (*CleanupSessionsResponse, error) {
+
+	if err := c.isActive(); err != nil {
+		return nil, err
+	}
+
+	numDeleted, err := c.cfg.Client.Cleanup()
+	if err != nil {
+		return nil, err
+	}
+	numAnchorDeleted, err := c.cfg.AnchorClient.Cleanup()
+	if err != nil {
+		return nil, err
+	}
+
+	return &CleanupSessionsResponse{
+		NumSessionsDeleted: uint32(numDeleted + numAnchorDeleted),
+	}, nil
+}
+
 // marshallTower converts a client registered watchtower into its corresponding
 // RPC type.
 func marshallTower(tower *wtclient.RegisteredTower, includeSessions bool) *Tower {
@@ -416,5 +606,104 @@ func marshallTower(tower *wtclient.RegisteredTower, includeSessions bool) *Tower
 		ActiveSessionCandidate: tower.ActiveSessionCandidate,
 		NumSessions:            uint32(len(tower.Sessions)),
 		Sessions:               rpcSessions,
+		BackoffState:           marshallBackoffState(&tower.BackoffState),
+		Health:                 marshallHealth(&tower.Health),
+	}
+}
+
+// marshallHealth converts the health of a tower into its corresponding RPC
+// type.
+func marshallHealth(health *wtclient.TowerHealth) *TowerHealth {
+	rpcHealth := &TowerHealth{
+		LatencyMs:          uint64(health.Latency.Milliseconds()),
+		SuccessfulAttempts: health.SuccessfulAttempts,
+		FailedAttempts:     health.FailedAttempts,
+		NumActiveSessions:  health.NumSessions,
+	}
+
+	if !health.LastSuccess.IsZero() {
+		rpcHealth.LastSuccess = health.LastSuccess.Unix()
+	}
+
+	if health.Negotiation != nil {
+		features := health.Negotiation.Features
+		rpcFeatures := make(
+			map[uint32]*lnrpc.Feature, len(features.Features()),
+		)
+		for bit := range features.Features() {
+			rpcFeatures[uint32(bit)] = &lnrpc.Feature{
+				Name:       features.Name(bit),
+				IsRequired: bit.IsRequired(),
+				IsKnown:    features.IsKnown(bit),
+			}
+		}
+
+		rpcHealth.ProtocolVersion = uint32(health.Negotiation.Version)
+		rpcHealth.NegotiatedFeatures = rpcFeatures
 	}
+
+	return rpcHealth
+}
+
+// marshallBackoffState converts the connection backoff state of a tower into
+// its corresponding RPC type.
+func marshallBackoffState(state *wtclient.TowerBackoffState) *TowerBackoffState {
+	rpcState := &TowerBackoffState{
+		ConsecutiveFailures: state.ConsecutiveFailures,
+		BackoffMs:           uint64(state.Backoff.Milliseconds()),
+		CircuitOpen:         state.CircuitOpen(time.Now()),
+	}
+
+	if !state.LastFailure.IsZero() {
+		rpcState.LastFailure = state.LastFailure.Unix()
+	}
+	if rpcState.CircuitOpen {
+		rpcState.CircuitOpenUntil = state.CircuitOpenUntil.Unix()
+	}
+
+	return rpcState
+}
+
+// worseBackoffState returns the backoff state with the most consecutive
+// connection failures.
+func worseBackoffState(a, b wtclient.TowerBackoffState) wtclient.TowerBackoffState {
+	if b.ConsecutiveFailures > a.ConsecutiveFailures {
+		return b
+	}
+
+	return a
+}
+
+// combineHealth returns the health of a tower across both the legacy and the
+// anchor client. The attempts and sessions are summed up, while the latency is
+// averaged.
+func combineHealth(a, b wtclient.TowerHealth) wtclient.TowerHealth {
+	combined := wtclient.TowerHealth{
+		Latency:            a.Latency,
+		SuccessfulAttempts: a.SuccessfulAttempts + b.SuccessfulAttempts,
+		FailedAttempts:     a.FailedAttempts + b.FailedAttempts,
+		LastSuccess:        a.LastSuccess,
+		NumSessions:        a.NumSessions + b.NumSessions,
+		Negotiation:        a.Negotiation,
+	}
+
+	// Prefer the negotiation of the anchor client, whose sessions rely on
+	// more features.
+	if b.Negotiation != nil {
+		combined.Negotiation = b.Negotiation
+	}
+
+	switch {
+	case a.Latency == 0:
+		combined.Latency = b.Latency
+
+	case b.Latency != 0:
+		combined.Latency = (a.Latency + b.Latency) / 2
+	}
+
+	if b.LastSuccess.After(a.LastSuccess) {
+		combined.LastSuccess = b.LastSuccess
+	}
+
+	return combined
 }
@@ -36,6 +36,17 @@ type WatchtowerClientClient interface {
 	Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
 	// Policy returns the active watchtower client policy configuration.
 	Policy(ctx context.Context, in *PolicyRequest, opts ...grpc.CallOption) (*PolicyResponse, error)
+	// AuditChannelBackups returns which revoked states of a channel were backed
+	// up to which watchtower session, and verifies that all revoked states of the
+	// channel are covered by a backup.
+	AuditChannelBackups(ctx context.Context, in *AuditChannelBackupsRequest, opts ...grpc.CallOption) (*AuditChannelBackupsResponse, error)
+	// CleanupSessions deletes all watchtower sessions that are no longer needed
+	// because all channels they backed up states of were cooperatively closed.
+	// The sessions are deleted at their watchtowers and from the client's
+	// database. This is done automatically whenever a channel is cooperatively
+	// closed, but can be forced with this call, for example to retry sessions
+	// whose watchtower was unreachable.
+	CleanupSessions(ctx context.Context, in *CleanupSessionsRequest, opts ...grpc.CallOption) (*CleanupSessionsResponse, error)
 }
 
 type watchtowerClientClient struct {
@@ -100,6 +111,24 @@ func (c *watchtowerClientClient) Policy(ctx context.Context, in *PolicyRequest,
 	return out, nil
 }
 
+func (c *watchtowerClientClient) AuditChannelBackups(ctx context.Context, in *AuditChannelBackupsRequest, opts ...grpc.CallOption) (*AuditChannelBackupsResponse, error) {
+	out := new(AuditChannelBackupsResponse)
+	err := c.cc.Invoke(ctx, "/wtclientrpc.WatchtowerClient/AuditChannelBackups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *watchtowerClientClient) CleanupSessions(ctx context.Context, in *CleanupSessionsRequest, opts ...grpc.CallOption) (*CleanupSessionsResponse, error) {
+	out := new(CleanupSessionsResponse)
+	err := c.cc.Invoke(ctx, "/wtclientrpc.WatchtowerClient/CleanupSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WatchtowerClientServer is the server API for WatchtowerClient service.
 // All implementations must embed UnimplementedWatchtowerClientServer
 // for forward compatibility
@@ -122,6 +151,17 @@ type WatchtowerClientServer interface {
 	Stats(context.Context, *StatsRequest) (*StatsResponse, error)
 	// Policy returns the active watchtower client policy configuration.
 	Policy(context.Context, *PolicyRequest) (*PolicyResponse, error)
+	// AuditChannelBackups returns which revoked states of a channel were backed
+	// up to which watchtower session, and verifies that all revoked states of the
+	// channel are covered by a backup.
+	AuditChannelBackups(context.Context, *AuditChannelBackupsRequest) (*AuditChannelBackupsResponse, error)
+	// CleanupSessions deletes all watchtower sessions that are no longer needed
+	// because all channels they backed up states of were cooperatively closed.
+	// The sessions are deleted at their watchtowers and from the client's
+	// database. This is done automatically whenever a channel is cooperatively
+	// closed, but can be forced with this call, for example to retry sessions
+	// whose watchtower was unreachable.
+	CleanupSessions(context.Context, *CleanupSessionsRequest) (*CleanupSessionsResponse, error)
 	mustEmbedUnimplementedWatchtowerClientServer()
 }
 
@@ -147,6 +187,12 @@ func (UnimplementedWatchtowerClientServer) Stats(context.Context, *StatsRequest)
 func (UnimplementedWatchtowerClientServer) Policy(context.Context, *PolicyRequest) (*PolicyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Policy not implemented")
 }
+func (UnimplementedWatchtowerClientServer) AuditChannelBackups(context.Context, *AuditChannelBackupsRequest) (*AuditChannelBackupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AuditChannelBackups not implemented")
+}
+func (UnimplementedWatchtowerClientServer) CleanupSessions(context.Context, *CleanupSessionsRequest) (*CleanupSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CleanupSessions not implemented")
+}
 func (UnimplementedWatchtowerClientServer) mustEmbedUnimplementedWatchtowerClientServer() {}
 
 // UnsafeWatchtowerClientServer may be embedded to opt out of forward compatibility for this service.
@@ -268,6 +314,42 @@ func _WatchtowerClient_Policy_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WatchtowerClient_AuditChannelBackups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditChannelBackupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WatchtowerClientServer).AuditChannelBackups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wtclientrpc.WatchtowerClient/AuditChannelBackups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WatchtowerClientServer).AuditChannelBackups(ctx, req.(*AuditChannelBackupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WatchtowerClient_CleanupSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CleanupSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WatchtowerClientServer).CleanupSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wtclientrpc.WatchtowerClient/CleanupSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WatchtowerClientServer).CleanupSessions(ctx, req.(*CleanupSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // WatchtowerClient_ServiceDesc is the grpc.ServiceDesc for WatchtowerClient service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -299,6 +381,14 @@ var WatchtowerClient_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Policy",
 			Handler:    _WatchtowerClient_Policy_Handler,
 		},
+		{
+			MethodName: "AuditChannelBackups",
+			Handler:    _WatchtowerClient_AuditChannelBackups_Handler,
+		},
+		{
+			MethodName: "CleanupSessions",
+			Handler:    _WatchtowerClient_CleanupSessions_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "wtclientrpc/wtclient.proto",
@@ -0,0 +1,213 @@
+package lntest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// artifactsIndexFileName is the name of the index file in the log
+	// directory that lists the artifacts collected for each test case.
+	artifactsIndexFileName = "index.json"
+
+	// goroutineDumpPrefix is the file name prefix of the goroutine dumps
+	// that are saved for the nodes of a failed test case.
+	goroutineDumpPrefix = "pprof-"
+
+	// raceReportPrefix is the file name prefix of the reports written by
+	// nodes that were built with the race detector. The race detector
+	// appends the PID of the process to the file name.
+	raceReportPrefix = "race-"
+
+	// coverageDirName is the name of the directory within the artifact
+	// directory of a test case that holds the coverage data of the nodes,
+	// in a sub directory per node.
+	coverageDirName = "cover"
+)
+
+// TestCaseArtifacts lists the artifacts that were collected while running a
+// single test case. All paths are relative to the log directory.
+type TestCaseArtifacts struct {
+	// Name is the name of the test case.
+	Name string `json:"name"`
+
+	// Passed indicates whether the test case passed.
+	Passed bool `json:"passed"`
+
+	// StartTime is the time the test case was set up at.
+	StartTime time.Time `json:"start_time"`
+
+	// Duration is the time it took to run the test case, including the
+	// set up and tear down of its nodes.
+	Duration string `json:"duration"`
+
+	// Flake is the name of the known flake signature that matched the
+	// logs of the test case after it failed, which caused it to be
+	// retried.
+	Flake string `json:"flake,omitempty"`
+
+	// Dir is the directory that holds all artifacts of the test case.
+	Dir string `json:"dir"`
+
+	// Logs are the log files of the nodes that were run.
+	Logs []string `json:"logs"`
+
+	// GoroutineDumps are the goroutine dumps of the nodes, which are only
+	// collected if the test case failed.
+	GoroutineDumps []string `json:"goroutine_dumps,omitempty"`
+
+	// RaceReports are the reports of the nodes that detected a data race.
+	RaceReports []string `json:"race_reports,omitempty"`
+
+	// Coverage are the directories holding the coverage data of the
+	// nodes, which are only written by nodes built with -cover.
+	Coverage []string `json:"coverage,omitempty"`
+}
+
+// testCaseArtifactDir returns the directory the artifacts of the test case
+// with the given name are collected in.
+func testCaseArtifactDir(testCase string) string {
+	return filepath.Join(GetLogDir(), testCase)
+}
+
+// nodeEnv returns the environment the lnd process of the given node is run
+// with. If log output is enabled, data races detected by nodes built with the
+// race detector are reported to a file in the artifact directory of the test
+// case, and if coverage collection is enabled, nodes built with -cover write
+// their coverage data to a directory of their own.
+func nodeEnv(hn *HarnessNode) ([]string, error) {
+	env := os.Environ()
+	if !*logOutput {
+		return env, nil
+	}
+
+	dir := testCaseArtifactDir(hn.Cfg.LogFilenamePrefix)
+	nodeName := fmt.Sprintf("%d-%s", hn.NodeID, hn.Cfg.Name)
+
+	racePath, err := filepath.Abs(
+		filepath.Join(dir, raceReportPrefix+nodeName),
+	)
+	if err != nil {
+		return nil, err
+	}
+	env = append(env, "GORACE=log_path="+racePath)
+
+	if !*coverage {
+		return env, nil
+	}
+
+	coverDir, err := filepath.Abs(
+		filepath.Join(dir, coverageDirName, nodeName),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(coverDir, 0700); err != nil {
+		return nil, err
+	}
+
+	return append(env, "GOCOVERDIR="+coverDir), nil
+}
+
+// collectArtifacts lists the artifacts found in the artifact directory of the
+// given test case.
+func collectArtifacts(testCase string) (*TestCaseArtifacts, error) {
+	logDir := GetLogDir()
+	dir := testCaseArtifactDir(testCase)
+
+	relDir, err := filepath.Rel(logDir, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts := &TestCaseArtifacts{
+		Name: testCase,
+		Dir:  relDir,
+	}
+
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry,
+		err error) error {
+
+		switch {
+		// A test case that failed before any node was started has no
+		// artifact directory.
+		case os.IsNotExist(err) && path == dir:
+			return nil
+
+		case err != nil:
+			return err
+		}
+
+		relPath, err := filepath.Rel(logDir, path)
+		if err != nil {
+			return err
+		}
+
+		name := d.Name()
+		switch {
+		case d.IsDir() && filepath.Base(filepath.Dir(path)) ==
+			coverageDirName:
+
+			artifacts.Coverage = append(artifacts.Coverage, relPath)
+
+			return filepath.SkipDir
+
+		case d.IsDir():
+			return nil
+
+		case strings.HasPrefix(name, goroutineDumpPrefix):
+			artifacts.GoroutineDumps = append(
+				artifacts.GoroutineDumps, relPath,
+			)
+
+		case strings.HasPrefix(name, raceReportPrefix):
+			artifacts.RaceReports = append(
+				artifacts.RaceReports, relPath,
+			)
+
+		case filepath.Ext(name) == ".log":
+			artifacts.Logs = append(artifacts.Logs, relPath)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(artifacts.Logs)
+	sort.Strings(artifacts.GoroutineDumps)
+	sort.Strings(artifacts.RaceReports)
+	sort.Strings(artifacts.Coverage)
+
+	return artifacts, nil
+}
+
+// writeArtifactsIndex writes the index of the artifacts of all test cases run
+// so far to the log directory, replacing any previous index.
+func writeArtifactsIndex(testCases []*TestCaseArtifacts) error {
+	index := struct {
+		TestCases []*TestCaseArtifacts `json:"test_cases"`
+	}{
+		TestCases: testCases,
+	}
+
+	indexJSON, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	indexFile := filepath.Join(GetLogDir(), artifactsIndexFileName)
+	tmpFile := indexFile + ".tmp"
+	if err := os.WriteFile(tmpFile, indexJSON, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile, indexFile)
+}
@@ -0,0 +1,81 @@
+package lntest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCollectArtifacts tests that the artifacts of a test case are found in
+// its artifact directory and listed in the index.
+func TestCollectArtifacts(t *testing.T) {
+	logDir := t.TempDir()
+
+	oldLogDir := *logSubDir
+	*logSubDir = logDir
+	defer func() {
+		*logSubDir = oldLogDir
+	}()
+
+	const testCase = "basic_funding_flow"
+	dir := testCaseArtifactDir(testCase)
+
+	files := []string{
+		"0-Alice-02aabbcc.log",
+		"1-Bob-03ddeeff.log",
+		"pprof-0-Alice-02aabbcc.log",
+		"race-1-Bob.4242",
+		"cover/0-Alice/covmeta.1234",
+	}
+	for _, file := range files {
+		path := filepath.Join(dir, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, nil, 0600))
+	}
+
+	artifacts, err := collectArtifacts(testCase)
+	require.NoError(t, err)
+	require.Equal(t, &TestCaseArtifacts{
+		Name: testCase,
+		Dir:  testCase,
+		Logs: []string{
+			filepath.Join(testCase, "0-Alice-02aabbcc.log"),
+			filepath.Join(testCase, "1-Bob-03ddeeff.log"),
+		},
+		GoroutineDumps: []string{
+			filepath.Join(testCase, "pprof-0-Alice-02aabbcc.log"),
+		},
+		RaceReports: []string{
+			filepath.Join(testCase, "race-1-Bob.4242"),
+		},
+		Coverage: []string{
+			filepath.Join(testCase, "cover", "0-Alice"),
+		},
+	}, artifacts)
+
+	// A test case that never started a node has no artifacts.
+	empty, err := collectArtifacts("no_nodes")
+	require.NoError(t, err)
+	require.Empty(t, empty.Logs)
+
+	// Both test cases are listed in the index.
+	artifacts.Passed = true
+	err = writeArtifactsIndex([]*TestCaseArtifacts{artifacts, empty})
+	require.NoError(t, err)
+
+	indexJSON, err := os.ReadFile(
+		filepath.Join(logDir, artifactsIndexFileName),
+	)
+	require.NoError(t, err)
+
+	var index struct {
+		TestCases []*TestCaseArtifacts `json:"test_cases"`
+	}
+	require.NoError(t, json.Unmarshal(indexJSON, &index))
+	require.Len(t, index.TestCases, 2)
+	require.Equal(t, artifacts, index.TestCases[0])
+	require.False(t, index.TestCases[1].Passed)
+}
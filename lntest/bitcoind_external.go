@@ -0,0 +1,253 @@
+package lntest
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/rpcclient"
+)
+
+const (
+	// EnvBitcoindRPCHost is the environment variable that holds the
+	// host:port of the RPC interface of an already running bitcoind. If it
+	// is set, the itests attach to that bitcoind instead of launching
+	// their own chain backend.
+	EnvBitcoindRPCHost = "LNTEST_BITCOIND_RPCHOST"
+
+	// EnvBitcoindRPCUser is the environment variable that holds the RPC
+	// user of the external bitcoind.
+	EnvBitcoindRPCUser = "LNTEST_BITCOIND_RPCUSER"
+
+	// EnvBitcoindRPCPass is the environment variable that holds the RPC
+	// password of the external bitcoind.
+	EnvBitcoindRPCPass = "LNTEST_BITCOIND_RPCPASS"
+
+	// EnvBitcoindZMQBlock is the environment variable that holds the ZMQ
+	// endpoint the external bitcoind publishes raw blocks on.
+	EnvBitcoindZMQBlock = "LNTEST_BITCOIND_ZMQPUBRAWBLOCK"
+
+	// EnvBitcoindZMQTx is the environment variable that holds the ZMQ
+	// endpoint the external bitcoind publishes raw transactions on.
+	EnvBitcoindZMQTx = "LNTEST_BITCOIND_ZMQPUBRAWTX"
+
+	// EnvBitcoindRPCPolling is the environment variable that, if set to
+	// true, makes the nodes poll the external bitcoind over RPC instead
+	// of subscribing to its ZMQ endpoints.
+	EnvBitcoindRPCPolling = "LNTEST_BITCOIND_RPCPOLLING"
+
+	// EnvBitcoindMinerHost is the environment variable that holds the host
+	// under which the external bitcoind reaches the test miner, for
+	// example host.docker.internal if bitcoind runs in a container. The
+	// port of the miner is kept. Defaults to the host the miner listens
+	// on.
+	EnvBitcoindMinerHost = "LNTEST_BITCOIND_MINERHOST"
+)
+
+// ExternalBitcoindBackendConfig is an implementation of the BackendConfig
+// interface backed by an already running bitcoind node, which is neither
+// started nor stopped by the harness.
+type ExternalBitcoindBackendConfig struct {
+	rpcHost      string
+	rpcUser      string
+	rpcPass      string
+	zmqBlockPath string
+	zmqTxPath    string
+	rpcPolling   bool
+	rpcClient    *rpcclient.Client
+
+	// minerAddr is the p2p address of the miner to connect to, as seen
+	// from the external bitcoind.
+	minerAddr string
+}
+
+// A compile time assertion to ensure ExternalBitcoindBackendConfig meets the
+// BackendConfig interface.
+var _ BackendConfig = (*ExternalBitcoindBackendConfig)(nil)
+
+// GenArgs returns the arguments needed to be passed to LND at startup for
+// using this node as a chain backend.
+func (b ExternalBitcoindBackendConfig) GenArgs() []string {
+	var args []string
+	args = append(args, "--bitcoin.node=bitcoind")
+	args = append(args, fmt.Sprintf("--bitcoind.rpchost=%v", b.rpcHost))
+	args = append(args, fmt.Sprintf("--bitcoind.rpcuser=%v", b.rpcUser))
+	args = append(args, fmt.Sprintf("--bitcoind.rpcpass=%v", b.rpcPass))
+
+	if b.rpcPolling {
+		args = append(args, "--bitcoind.rpcpolling")
+		args = append(args, "--bitcoind.blockpollinginterval=10ms")
+		args = append(args, "--bitcoind.txpollinginterval=10ms")
+	} else {
+		args = append(args, fmt.Sprintf("--bitcoind.zmqpubrawblock=%v",
+			b.zmqBlockPath))
+		args = append(args, fmt.Sprintf("--bitcoind.zmqpubrawtx=%v",
+			b.zmqTxPath))
+	}
+
+	return args
+}
+
+// ConnectMiner is called to establish a connection to the test miner.
+func (b ExternalBitcoindBackendConfig) ConnectMiner() error {
+	return b.rpcClient.AddNode(b.minerAddr, rpcclient.ANAdd)
+}
+
+// DisconnectMiner is called to disconnect the miner.
+func (b ExternalBitcoindBackendConfig) DisconnectMiner() error {
+	return b.rpcClient.AddNode(b.minerAddr, rpcclient.ANRemove)
+}
+
+// Credentials returns the rpc username, password and host for the backend.
+func (b ExternalBitcoindBackendConfig) Credentials() (string, string, string,
+	error) {
+
+	return b.rpcUser, b.rpcPass, b.rpcHost, nil
+}
+
+// Name returns the name of the backend type.
+func (b ExternalBitcoindBackendConfig) Name() string {
+	return "bitcoind"
+}
+
+// externalBitcoindFromEnv reads the endpoints of an external bitcoind from
+// the environment. It returns nil if EnvBitcoindRPCHost isn't set.
+func externalBitcoindFromEnv(miner string,
+	getEnv func(string) string) (*ExternalBitcoindBackendConfig, error) {
+
+	rpcHost := getEnv(EnvBitcoindRPCHost)
+	if rpcHost == "" {
+		return nil, nil
+	}
+
+	cfg := &ExternalBitcoindBackendConfig{
+		rpcHost:      rpcHost,
+		rpcUser:      getEnv(EnvBitcoindRPCUser),
+		rpcPass:      getEnv(EnvBitcoindRPCPass),
+		zmqBlockPath: getEnv(EnvBitcoindZMQBlock),
+		zmqTxPath:    getEnv(EnvBitcoindZMQTx),
+		minerAddr:    miner,
+	}
+
+	if polling := getEnv(EnvBitcoindRPCPolling); polling != "" {
+		var err error
+		cfg.rpcPolling, err = strconv.ParseBool(polling)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v: %v",
+				EnvBitcoindRPCPolling, err)
+		}
+	}
+
+	if cfg.rpcUser == "" || cfg.rpcPass == "" {
+		return nil, fmt.Errorf("%v and %v must be set with %v",
+			EnvBitcoindRPCUser, EnvBitcoindRPCPass,
+			EnvBitcoindRPCHost)
+	}
+
+	if !cfg.rpcPolling && (cfg.zmqBlockPath == "" || cfg.zmqTxPath == "") {
+		return nil, fmt.Errorf("%v and %v must be set unless %v is "+
+			"enabled", EnvBitcoindZMQBlock, EnvBitcoindZMQTx,
+			EnvBitcoindRPCPolling)
+	}
+
+	if minerHost := getEnv(EnvBitcoindMinerHost); minerHost != "" {
+		_, port, err := net.SplitHostPort(miner)
+		if err != nil {
+			return nil, fmt.Errorf("invalid miner address %v: %v",
+				miner, err)
+		}
+		cfg.minerAddr = net.JoinHostPort(minerHost, port)
+	}
+
+	return cfg, nil
+}
+
+// NewExternalBackend attaches to the bitcoind whose RPC and ZMQ endpoints are
+// given by the LNTEST_BITCOIND_* environment variables, and returns an
+// ExternalBitcoindBackendConfig for that node. The returned cleanup function
+// only disconnects from it, the bitcoind itself keeps running.
+func NewExternalBackend(miner string, netParams *chaincfg.Params) (
+	*ExternalBitcoindBackendConfig, func() error, error) {
+
+	if netParams != &chaincfg.RegressionNetParams {
+		return nil, nil, fmt.Errorf("only regtest supported")
+	}
+
+	cfg, err := externalBitcoindFromEnv(miner, os.Getenv)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg == nil {
+		return nil, nil, fmt.Errorf("%v not set", EnvBitcoindRPCHost)
+	}
+
+	rpcCfg := rpcclient.ConnConfig{
+		Host:                 cfg.rpcHost,
+		User:                 cfg.rpcUser,
+		Pass:                 cfg.rpcPass,
+		DisableConnectOnNew:  true,
+		DisableAutoReconnect: false,
+		DisableTLS:           true,
+		HTTPPostMode:         true,
+	}
+
+	client, err := rpcclient.New(&rpcCfg, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to create rpc client: %v",
+			err)
+	}
+
+	// Make sure the bitcoind is reachable and on regtest before any node
+	// is started against it.
+	info, err := client.GetBlockChainInfo()
+	if err != nil {
+		client.Shutdown()
+		return nil, nil, fmt.Errorf("unable to reach bitcoind at %v: %v",
+			cfg.rpcHost, err)
+	}
+	if info.Chain != "regtest" {
+		client.Shutdown()
+		return nil, nil, fmt.Errorf("bitcoind at %v runs on %v, "+
+			"expected regtest", cfg.rpcHost, info.Chain)
+	}
+
+	cfg.rpcClient = client
+
+	cleanUp := func() error {
+		// The miner is stopped with the harness, so we remove it from
+		// the node list of the shared bitcoind to not have it retry
+		// the connection forever.
+		err := cfg.DisconnectMiner()
+		client.Shutdown()
+
+		return err
+	}
+
+	return cfg, cleanUp, nil
+}
+
+// NewChainBackend returns the chain backend the itests run against. If the
+// LNTEST_BITCOIND_RPCHOST environment variable is set, it attaches to that
+// external bitcoind, otherwise it starts the backend selected by the build
+// tags.
+func NewChainBackend(miner string, netParams *chaincfg.Params) (BackendConfig,
+	func() error, error) {
+
+	if os.Getenv(EnvBitcoindRPCHost) != "" {
+		backend, cleanUp, err := NewExternalBackend(miner, netParams)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return backend, cleanUp, nil
+	}
+
+	backend, cleanUp, err := NewBackend(miner, netParams)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return backend, cleanUp, nil
+}
@@ -0,0 +1,117 @@
+package lntest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestExternalBitcoindFromEnv tests that the endpoints of an external bitcoind
+// are read from the environment and validated.
+func TestExternalBitcoindFromEnv(t *testing.T) {
+	const miner = "127.0.0.1:18444"
+
+	baseEnv := func() map[string]string {
+		return map[string]string{
+			EnvBitcoindRPCHost:  "bitcoind:18443",
+			EnvBitcoindRPCUser:  "user",
+			EnvBitcoindRPCPass:  "pass",
+			EnvBitcoindZMQBlock: "tcp://bitcoind:28332",
+			EnvBitcoindZMQTx:    "tcp://bitcoind:28333",
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		modify    func(env map[string]string)
+		expNil    bool
+		expErr    bool
+		expMiner  string
+		expArgs   []string
+		expNoArgs []string
+	}{{
+		name: "not configured",
+		modify: func(env map[string]string) {
+			delete(env, EnvBitcoindRPCHost)
+		},
+		expNil: true,
+	}, {
+		name:     "zmq",
+		modify:   func(env map[string]string) {},
+		expMiner: miner,
+		expArgs: []string{
+			"--bitcoind.rpchost=bitcoind:18443",
+			"--bitcoind.zmqpubrawblock=tcp://bitcoind:28332",
+			"--bitcoind.zmqpubrawtx=tcp://bitcoind:28333",
+		},
+		expNoArgs: []string{"--bitcoind.rpcpolling"},
+	}, {
+		name: "rpc polling without zmq",
+		modify: func(env map[string]string) {
+			delete(env, EnvBitcoindZMQBlock)
+			delete(env, EnvBitcoindZMQTx)
+			env[EnvBitcoindRPCPolling] = "true"
+		},
+		expMiner: miner,
+		expArgs:  []string{"--bitcoind.rpcpolling"},
+	}, {
+		name: "missing zmq",
+		modify: func(env map[string]string) {
+			delete(env, EnvBitcoindZMQTx)
+		},
+		expErr: true,
+	}, {
+		name: "missing credentials",
+		modify: func(env map[string]string) {
+			delete(env, EnvBitcoindRPCPass)
+		},
+		expErr: true,
+	}, {
+		name: "invalid rpc polling",
+		modify: func(env map[string]string) {
+			env[EnvBitcoindRPCPolling] = "sometimes"
+		},
+		expErr: true,
+	}, {
+		name: "miner host",
+		modify: func(env map[string]string) {
+			env[EnvBitcoindMinerHost] = "host.docker.internal"
+		},
+		expMiner: "host.docker.internal:18444",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			env := baseEnv()
+			tc.modify(env)
+
+			cfg, err := externalBitcoindFromEnv(
+				miner, func(key string) string {
+					return env[key]
+				},
+			)
+			if tc.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			if tc.expNil {
+				require.Nil(t, cfg)
+				return
+			}
+
+			require.Equal(t, tc.expMiner, cfg.minerAddr)
+
+			args := cfg.GenArgs()
+			for _, arg := range tc.expArgs {
+				require.Contains(t, args, arg)
+			}
+			for _, arg := range tc.expNoArgs {
+				require.NotContains(t, args, arg)
+			}
+		})
+	}
+}
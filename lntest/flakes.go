@@ -0,0 +1,123 @@
+package lntest
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// FlakeSignature describes a known flake of a test case by a pattern that is
+// logged by one of its nodes whenever the flake occurs. A test case that fails
+// with a known flake is retried once, so that the flake doesn't fail the test
+// run if the retry passes, and is told apart from a regression.
+type FlakeSignature struct {
+	// Name identifies the flake in the test output and the artifacts
+	// index, ideally by referencing the issue tracking it.
+	Name string
+
+	// LogPattern matches a line of a node log that is only written when
+	// the flake occurs.
+	LogPattern *regexp.Regexp
+}
+
+// NewFlakeSignature creates a new flake signature with the given name that
+// matches node log lines against the given regular expression. It panics if
+// the expression can't be compiled, as signatures are declared statically
+// along with the test cases.
+func NewFlakeSignature(name, logPattern string) *FlakeSignature {
+	return &FlakeSignature{
+		Name:       name,
+		LogPattern: regexp.MustCompile(logPattern),
+	}
+}
+
+// String returns the name of the flake signature.
+func (f *FlakeSignature) String() string {
+	return f.Name
+}
+
+// SetKnownFlakes sets the known flake signatures of the current test case.
+// As the directory of a node is removed when it's shut down, the log of each
+// node is searched for them right before. This must be called after the test
+// case was set up.
+func (n *NetworkHarness) SetKnownFlakes(flakes []*FlakeSignature) {
+	n.knownFlakes = flakes
+}
+
+// MatchedFlake returns the first of the known flake signatures of the current
+// test case that matched the log of one of its nodes that were shut down, or
+// nil if none matched. If the log of a node couldn't be searched, the error
+// is returned as well. The matched signature is added to the artifacts of the
+// test case.
+func (n *NetworkHarness) MatchedFlake() (*FlakeSignature, error) {
+	return n.currentFlake, n.flakeErr
+}
+
+// matchNodeFlake searches the log of the given node for the known flake
+// signatures of the current test case. The lnd log is written by every node,
+// regardless of the logoutput flag. This must be called after the node was
+// stopped and before its directory is removed.
+func (n *NetworkHarness) matchNodeFlake(node *HarnessNode) {
+	if n.currentFlake != nil || len(n.knownFlakes) == 0 {
+		return
+	}
+
+	logFile := filepath.Join(
+		node.Cfg.LogDir, "bitcoin", node.Cfg.NetParams.Name, "lnd.log",
+	)
+	flake, err := matchFlake([]string{logFile}, n.knownFlakes)
+	if err != nil {
+		if n.flakeErr == nil {
+			n.flakeErr = fmt.Errorf("unable to search log of node "+
+				"%v: %v", node.Name(), err)
+		}
+
+		return
+	}
+
+	n.currentFlake = flake
+}
+
+// matchFlake returns the first of the given flake signatures that matches a
+// line of one of the given log files, or nil if none matches.
+func matchFlake(logs []string, flakes []*FlakeSignature) (*FlakeSignature,
+	error) {
+
+	for _, flake := range flakes {
+		for _, log := range logs {
+			matched, err := logMatches(log, flake.LogPattern)
+			if err != nil {
+				return nil, err
+			}
+
+			if matched {
+				return flake, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// logMatches returns whether a line of the given log file matches the pattern.
+func logMatches(log string, pattern *regexp.Regexp) (bool, error) {
+	f, err := os.Open(log)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	// Node logs may contain long lines, such as dumped RPC responses, so
+	// we allow lines of up to 1 MiB.
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		if pattern.Match(scanner.Bytes()) {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
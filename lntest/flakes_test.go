@@ -0,0 +1,120 @@
+package lntest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestMatchFlake tests that a flake signature is matched by the first of the
+// given signatures that is found in any of the node logs.
+func TestMatchFlake(t *testing.T) {
+	dir := t.TempDir()
+
+	aliceLog := filepath.Join(dir, "0-Alice.log")
+	bobLog := filepath.Join(dir, "1-Bob.log")
+
+	err := os.WriteFile(aliceLog, []byte(
+		"[INF] PEER: Peer(bob) connected\n"+
+			"[ERR] PEER: Peer(bob) unable to read message: EOF\n",
+	), 0600)
+	require.NoError(t, err)
+	err = os.WriteFile(bobLog, []byte(
+		"[INF] HSWC: Channel(abc) link started\n",
+	), 0600)
+	require.NoError(t, err)
+
+	readFailed := NewFlakeSignature("read failed", "unable to read message")
+	linkStarted := NewFlakeSignature("link started", `link\s+started$`)
+	unknown := NewFlakeSignature("unknown", "chain reorg detected")
+
+	logs := []string{aliceLog, bobLog}
+
+	// The signatures are matched in order, regardless of the log they're
+	// found in.
+	flake, err := matchFlake(
+		logs, []*FlakeSignature{unknown, linkStarted, readFailed},
+	)
+	require.NoError(t, err)
+	require.Equal(t, linkStarted, flake)
+
+	// No flake is matched if none of the signatures is found.
+	flake, err = matchFlake(logs, []*FlakeSignature{unknown})
+	require.NoError(t, err)
+	require.Nil(t, flake)
+
+	// A missing log is an error.
+	_, err = matchFlake(
+		[]string{filepath.Join(dir, "2-Carol.log")},
+		[]*FlakeSignature{unknown},
+	)
+	require.Error(t, err)
+}
+
+// TestMatchNodeFlake tests that the lnd log of a node that is shut down is
+// searched for the known flakes of the current test case, and that the first
+// matched flake is kept.
+func TestMatchNodeFlake(t *testing.T) {
+	newNode := func(name, log string) *HarnessNode {
+		cfg := &BaseNodeConfig{
+			Name:      name,
+			LogDir:    t.TempDir(),
+			NetParams: harnessNetParams,
+		}
+
+		logDir := filepath.Join(
+			cfg.LogDir, "bitcoin", harnessNetParams.Name,
+		)
+		require.NoError(t, os.MkdirAll(logDir, 0700))
+		require.NoError(t, os.WriteFile(
+			filepath.Join(logDir, "lnd.log"), []byte(log), 0600,
+		))
+
+		return &HarnessNode{Cfg: cfg}
+	}
+
+	alice := newNode("Alice", "[INF] HSWC: Channel(abc) link started\n")
+	bob := newNode("Bob", "[ERR] PEER: unable to read message: EOF\n")
+
+	readFailed := NewFlakeSignature("read failed", "unable to read message")
+	linkStarted := NewFlakeSignature("link started", `link\s+started$`)
+
+	// Without known flakes, no log is searched.
+	n := &NetworkHarness{}
+	n.matchNodeFlake(bob)
+	flake, err := n.MatchedFlake()
+	require.NoError(t, err)
+	require.Nil(t, flake)
+
+	// Alice's log doesn't contain the flake, Bob's does.
+	n.SetKnownFlakes([]*FlakeSignature{readFailed})
+	n.matchNodeFlake(alice)
+	flake, err = n.MatchedFlake()
+	require.NoError(t, err)
+	require.Nil(t, flake)
+
+	n.matchNodeFlake(bob)
+	flake, err = n.MatchedFlake()
+	require.NoError(t, err)
+	require.Equal(t, readFailed, flake)
+
+	// Once a flake was matched, it's kept.
+	n.SetKnownFlakes([]*FlakeSignature{linkStarted, readFailed})
+	n.matchNodeFlake(alice)
+	flake, err = n.MatchedFlake()
+	require.NoError(t, err)
+	require.Equal(t, readFailed, flake)
+
+	// A node without a log results in an error.
+	n = &NetworkHarness{}
+	n.SetKnownFlakes([]*FlakeSignature{readFailed})
+	n.matchNodeFlake(&HarnessNode{Cfg: &BaseNodeConfig{
+		Name:      "Carol",
+		LogDir:    t.TempDir(),
+		NetParams: harnessNetParams,
+	}})
+	_, err = n.MatchedFlake()
+	require.Error(t, err)
+}
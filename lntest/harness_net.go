@@ -23,6 +23,7 @@ import (
 	"github.com/lightningnetwork/lnd"
 	"github.com/lightningnetwork/lnd/kvdb/etcd"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
 	"github.com/lightningnetwork/lnd/lntest/wait"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -48,6 +49,25 @@ type NetworkHarness struct {
 	// currentTestCase holds the name for the currently run test case.
 	currentTestCase string
 
+	// testCaseStart is the time the current test case was set up at.
+	testCaseStart time.Time
+
+	// knownFlakes are the known flake signatures of the current test
+	// case.
+	knownFlakes []*FlakeSignature
+
+	// currentFlake is the known flake signature that matched the logs of
+	// the current test case, if any.
+	currentFlake *FlakeSignature
+
+	// flakeErr is the first error encountered while searching the logs of
+	// the current test case for known flakes.
+	flakeErr error
+
+	// artifacts lists the artifacts collected for each test case that was
+	// run so far.
+	artifacts []*TestCaseArtifacts
+
 	// lndBinary is the full path to the lnd binary that was specifically
 	// compiled with all required itest flags.
 	lndBinary string
@@ -80,6 +100,10 @@ type NetworkHarness struct {
 	// lnd.
 	feeService *feeService
 
+	// links holds the proxies that apply network conditions between pairs
+	// of nodes, keyed by the names of the nodes.
+	links map[[2]string]*nodeLink
+
 	// runCtx is a context with cancel method. It's used to signal when the
 	// node needs to quit, and used as the parent context when spawning
 	// children contexts for RPC requests.
@@ -101,6 +125,7 @@ func NewNetworkHarness(m *HarnessMiner, b BackendConfig, lndBinary string,
 	n := NetworkHarness{
 		activeNodes:  make(map[int]*HarnessNode),
 		nodesByPub:   make(map[string]*HarnessNode),
+		links:        make(map[[2]string]*nodeLink),
 		lndErrorChan: make(chan error),
 		netParams:    m.ActiveNet,
 		Miner:        m,
@@ -147,6 +172,10 @@ func (n *NetworkHarness) SetUp(t *testing.T,
 	fakeLogger := grpclog.NewLoggerV2(io.Discard, io.Discard, io.Discard)
 	grpclog.SetLoggerV2(fakeLogger)
 	n.currentTestCase = testCase
+	n.testCaseStart = time.Now()
+	n.knownFlakes = nil
+	n.currentFlake = nil
+	n.flakeErr = nil
 	n.feeService = startFeeService(t)
 
 	// Start the initial seeder nodes within the test network, then connect
@@ -258,9 +287,40 @@ func (n *NetworkHarness) TearDown() error {
 		}
 	}
 
+	n.stopLinks()
+
 	return nil
 }
 
+// RecordArtifacts adds the artifacts that were collected for the current test
+// case to the artifacts index in the log directory. The index is rewritten on
+// every call, so that it's complete even if the test run is aborted. This must
+// be called after the nodes of the test case were shut down, so that their log
+// files are finalized.
+func (n *NetworkHarness) RecordArtifacts(passed bool) error {
+	// Artifacts are only collected if the logoutput flag is set.
+	if !*logOutput {
+		return nil
+	}
+
+	artifacts, err := collectArtifacts(n.currentTestCase)
+	if err != nil {
+		return fmt.Errorf("unable to collect artifacts of test case "+
+			"%v: %v", n.currentTestCase, err)
+	}
+
+	artifacts.Passed = passed
+	artifacts.StartTime = n.testCaseStart
+	artifacts.Duration = time.Since(n.testCaseStart).String()
+	if n.currentFlake != nil {
+		artifacts.Flake = n.currentFlake.Name
+	}
+
+	n.artifacts = append(n.artifacts, artifacts)
+
+	return writeArtifactsIndex(n.artifacts)
+}
+
 // Stop stops the test harness.
 func (n *NetworkHarness) Stop() {
 	close(n.lndErrorChan)
@@ -449,6 +509,85 @@ func (n *NetworkHarness) NewNodeRemoteSigner(name string, extraArgs []string,
 	return node, nil
 }
 
+// RemoteSignerPair is a watch-only node together with the signer node that
+// holds its private keys.
+type RemoteSignerPair struct {
+	// WatchOnly is the node with a watch-only wallet that uses the signer
+	// node for any operation that requires access to private keys.
+	WatchOnly *HarnessNode
+
+	// Signer is the node with the full wallet that signs on behalf of the
+	// watch-only node.
+	Signer *HarnessNode
+}
+
+// NewRemoteSignerPair creates a signer node with a random seed and a
+// watch-only node that uses it as its remote signer. Both nodes are running
+// once the method returns, and the watch-only node can be used like any other
+// harness node.
+func (n *NetworkHarness) NewRemoteSignerPair(t *testing.T, name string,
+	extraArgs []string) *RemoteSignerPair {
+
+	signer := n.NewNode(t, name+"Signer", nil)
+
+	ctxt, cancel := context.WithTimeout(n.runCtx, DefaultTimeout)
+	defer cancel()
+
+	// The watch-only wallet is created from the account xpubs of the
+	// signer's wallet.
+	rpcAccts, err := signer.WalletKitClient.ListAccounts(
+		ctxt, &walletrpc.ListAccountsRequest{},
+	)
+	require.NoError(t, err, "unable to list signer accounts")
+
+	watchOnlyAccounts, err := walletrpc.AccountsToWatchOnly(
+		rpcAccts.Accounts,
+	)
+	require.NoError(t, err, "unable to convert signer accounts")
+
+	args := append([]string{
+		"--remotesigner.enable",
+		fmt.Sprintf(
+			"--remotesigner.rpchost=localhost:%d",
+			signer.Cfg.RPCPort,
+		),
+		fmt.Sprintf(
+			"--remotesigner.tlscertpath=%s", signer.Cfg.TLSCertPath,
+		),
+		fmt.Sprintf(
+			"--remotesigner.macaroonpath=%s",
+			signer.Cfg.AdminMacPath,
+		),
+	}, extraArgs...)
+
+	watchOnly, err := n.NewNodeRemoteSigner(
+		name, args, []byte("itestpassword"), &lnrpc.WatchOnly{
+			Accounts: watchOnlyAccounts,
+		},
+	)
+	require.NoErrorf(t, err, "unable to create watch-only node %s", name)
+
+	// Both nodes must share the same identity, otherwise the watch-only
+	// node isn't using the signer's keys.
+	require.Equal(t, signer.PubKeyStr, watchOnly.PubKeyStr)
+
+	return &RemoteSignerPair{
+		WatchOnly: watchOnly,
+		Signer:    signer,
+	}
+}
+
+// ShutdownRemoteSignerPair stops the watch-only node and then its signer.
+func (n *NetworkHarness) ShutdownRemoteSignerPair(
+	pair *RemoteSignerPair) error {
+
+	if err := n.ShutdownNode(pair.WatchOnly); err != nil {
+		return err
+	}
+
+	return n.ShutdownNode(pair.Signer)
+}
+
 // RestoreNodeWithSeed fully initializes a HarnessNode using a chosen mnemonic,
 // password, recovery window, and optionally a set of static channel backups.
 // After providing the initialization request to unlock the node, this method
@@ -596,7 +735,7 @@ func (n *NetworkHarness) EnsureConnected(t *testing.T, a, b *HarnessNode) {
 		req := &lnrpc.ConnectPeerRequest{
 			Addr: &lnrpc.LightningAddress{
 				Pubkey: bInfo.IdentityPubkey,
-				Host:   b.Cfg.P2PAddr(),
+				Host:   n.p2pAddr(a, b),
 			},
 		}
 
@@ -727,7 +866,7 @@ func (n *NetworkHarness) connectNodes(t *testing.T, a, b *HarnessNode,
 	req := &lnrpc.ConnectPeerRequest{
 		Addr: &lnrpc.LightningAddress{
 			Pubkey: bobInfo.IdentityPubkey,
-			Host:   b.Cfg.P2PAddr(),
+			Host:   n.p2pAddr(a, b),
 		},
 		Perm: perm,
 	}
@@ -785,6 +924,137 @@ func (n *NetworkHarness) DisconnectNodes(a, b *HarnessNode) error {
 	return nil
 }
 
+// link returns the link between the two nodes, starting its proxies if it
+// doesn't exist yet.
+func (n *NetworkHarness) link(a, b *HarnessNode) (*nodeLink, error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	key := linkKey(a, b)
+	if link, ok := n.links[key]; ok {
+		return link, nil
+	}
+
+	// The proxies are ordered like the key, so that toB always leads to
+	// the node with the greater name.
+	if a.Cfg.Name > b.Cfg.Name {
+		a, b = b, a
+	}
+
+	seed := linkSeed(key)
+	toA, err := newLinkProxy(a.Cfg.P2PAddr(), seed)
+	if err != nil {
+		return nil, err
+	}
+	toB, err := newLinkProxy(b.Cfg.P2PAddr(), seed+1)
+	if err != nil {
+		toA.stop()
+		return nil, err
+	}
+
+	link := &nodeLink{toA: toA, toB: toB}
+	n.links[key] = link
+
+	return link, nil
+}
+
+// p2pAddr returns the address node a connects to in order to reach node b. If
+// network conditions were set up between the two nodes, this is the address
+// of the proxy that applies them.
+func (n *NetworkHarness) p2pAddr(a, b *HarnessNode) string {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	link, ok := n.links[linkKey(a, b)]
+	if !ok {
+		return b.Cfg.P2PAddr()
+	}
+
+	if a.Cfg.Name > b.Cfg.Name {
+		return link.toA.Addr()
+	}
+
+	return link.toB.Addr()
+}
+
+// updateLink applies the given modification to the conditions of the link
+// between the two nodes.
+func (n *NetworkHarness) updateLink(a, b *HarnessNode,
+	update func(*linkConditions)) error {
+
+	link, err := n.link(a, b)
+	if err != nil {
+		return err
+	}
+
+	conds := link.toB.conditions()
+	update(&conds)
+	link.setConditions(conds)
+
+	return nil
+}
+
+// PartitionNodes cuts the network between nodes a and b. All connections
+// between them are torn down and new connections are refused, until
+// HealNodes is called.
+//
+// NOTE: Network conditions only apply to connections that are established by
+// the harness after the conditions between the two nodes were first set.
+// Nodes that reconnect to the address a channel peer announced bypass them,
+// so the conditions should be set before the nodes are connected.
+func (n *NetworkHarness) PartitionNodes(a, b *HarnessNode) error {
+	return n.updateLink(a, b, func(conds *linkConditions) {
+		conds.partitioned = true
+	})
+}
+
+// HealNodes lifts a partition between nodes a and b that was created by
+// PartitionNodes, allowing them to reconnect.
+func (n *NetworkHarness) HealNodes(a, b *HarnessNode) error {
+	return n.updateLink(a, b, func(conds *linkConditions) {
+		conds.partitioned = false
+	})
+}
+
+// SetLatency delays all traffic between nodes a and b by the given duration
+// in each direction. A zero duration removes the latency.
+//
+// NOTE: See PartitionNodes for the connections network conditions apply to.
+func (n *NetworkHarness) SetLatency(a, b *HarnessNode, d time.Duration) error {
+	return n.updateLink(a, b, func(conds *linkConditions) {
+		conds.latency = d
+	})
+}
+
+// DropMessages makes the given percentage of the messages between nodes a and
+// b get lost. As every brontide message is authenticated, a lost message
+// breaks the connection it was sent on, so the nodes observe it as a
+// disconnection and need to reconnect. The lost messages are the same on
+// every run of a test.
+//
+// NOTE: See PartitionNodes for the connections network conditions apply to.
+func (n *NetworkHarness) DropMessages(a, b *HarnessNode, pct uint32) error {
+	if pct > 100 {
+		return fmt.Errorf("invalid percentage %v", pct)
+	}
+
+	return n.updateLink(a, b, func(conds *linkConditions) {
+		conds.dropPercent = pct
+	})
+}
+
+// stopLinks stops all proxies between nodes and removes their network
+// conditions.
+func (n *NetworkHarness) stopLinks() {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	for key, link := range n.links {
+		link.stop()
+		delete(n.links, key)
+	}
+}
+
 // RestartNode attempts to restart a lightning node by shutting it down
 // cleanly, then restarting the process. This function is fully blocking. Upon
 // restart, the RPC connection to the node will be re-attempted, continuing iff
@@ -868,7 +1138,15 @@ func (n *NetworkHarness) SuspendNode(node *HarnessNode) (func() error, error) {
 // ShutdownNode stops an active lnd process and returns when the process has
 // exited and any temporary directories have been cleaned up.
 func (n *NetworkHarness) ShutdownNode(node *HarnessNode) error {
-	if err := node.shutdown(); err != nil {
+	if err := node.stop(); err != nil {
+		return err
+	}
+
+	// The log of the node is removed along with its directory, so we
+	// search it for known flakes first.
+	n.matchNodeFlake(node)
+
+	if err := node.cleanup(); err != nil {
 		return err
 	}
 
@@ -931,10 +1209,17 @@ func saveProfilesPage(node *HarnessNode) error {
 			node.NodeID, node.Cfg.Name, err)
 	}
 
-	fileName := fmt.Sprintf(
-		"pprof-%d-%s-%s.log", node.NodeID, node.Cfg.Name,
-		hex.EncodeToString(node.PubKey[:logPubKeyBytes]),
-	)
+	dir := testCaseArtifactDir(node.Cfg.LogFilenamePrefix)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create dir for profile page "+
+			"(node_id=%d, name=%s): %v",
+			node.NodeID, node.Cfg.Name, err)
+	}
+
+	fileName := filepath.Join(dir, fmt.Sprintf(
+		"%s%d-%s-%s.log", goroutineDumpPrefix, node.NodeID,
+		node.Cfg.Name, hex.EncodeToString(node.PubKey[:logPubKeyBytes]),
+	))
 
 	logFile, err := os.Create(fileName)
 	if err != nil {
@@ -1709,6 +1994,45 @@ func (n *NetworkHarness) RestoreDb(hn *HarnessNode) error {
 	return nil
 }
 
+// SnapshotNode takes a snapshot of the state of the node, which can be
+// restored with RestoreNodeSnapshot. The node is restarted afterwards.
+func (n *NetworkHarness) SnapshotNode(hn *HarnessNode) (*NodeSnapshot, error) {
+	snapshot, err := hn.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := n.RestartNode(hn, nil); err != nil {
+		snapshot.Remove()
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// RestoreNodeSnapshot restarts the node with the state of the given snapshot.
+// The block the snapshot was taken at must still be part of the chain, the
+// node then catches up with the blocks that were mined since.
+func (n *NetworkHarness) RestoreNodeSnapshot(hn *HarnessNode,
+	snapshot *NodeSnapshot) error {
+
+	blockHash, err := n.Miner.Client.GetBlockHash(
+		int64(snapshot.BlockHeight),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to get block at snapshot height "+
+			"%v: %v", snapshot.BlockHeight, err)
+	}
+	if blockHash.String() != snapshot.BlockHash {
+		return fmt.Errorf("snapshot block %v at height %v was "+
+			"reorged out", snapshot.BlockHash, snapshot.BlockHeight)
+	}
+
+	return n.RestartNode(hn, func() error {
+		return hn.RestoreSnapshot(snapshot)
+	})
+}
+
 // getChanPointFundingTxid returns the given channel point's funding txid in
 // raw bytes.
 func getChanPointFundingTxid(chanPoint *lnrpc.ChannelPoint) ([]byte, error) {
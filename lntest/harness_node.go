@@ -108,8 +108,10 @@ type NodeConfig interface {
 type BaseNodeConfig struct {
 	Name string
 
-	// LogFilenamePrefix is used to prefix node log files. Can be used
-	// to store the current test case for simpler postmortem debugging.
+	// LogFilenamePrefix is the name of the sub directory of the log
+	// directory the node's log files and other artifacts are collected
+	// in. Can be used to store the current test case for simpler
+	// postmortem debugging.
 	LogFilenamePrefix string
 
 	BackendCfg BackendConfig
@@ -599,16 +601,19 @@ func (hn *HarnessNode) startLnd(lndBinary string, lndError chan<- error) error {
 	args := hn.Cfg.GenArgs()
 	hn.cmd = exec.Command(lndBinary, args...)
 
+	env, err := nodeEnv(hn)
+	if err != nil {
+		return err
+	}
+	hn.cmd.Env = env
+
 	// Redirect stderr output to buffer
 	var errb bytes.Buffer
 	hn.cmd.Stderr = &errb
 
 	// If the logoutput flag is passed, redirect output from the nodes to
 	// log files.
-	var (
-		fileName string
-		err      error
-	)
+	var fileName string
 	if *logOutput {
 		fileName, err = addLogFile(hn)
 		if err != nil {
@@ -1205,18 +1210,6 @@ func (hn *HarnessNode) stop() error {
 	return nil
 }
 
-// shutdown stops the active lnd process and cleans up any temporary
-// directories created along the way.
-func (hn *HarnessNode) shutdown() error {
-	if err := hn.stop(); err != nil {
-		return err
-	}
-	if err := hn.cleanup(); err != nil {
-		return err
-	}
-	return nil
-}
-
 // kill kills the lnd process.
 func (hn *HarnessNode) kill() error {
 	return hn.cmd.Process.Kill()
@@ -1830,9 +1823,8 @@ func getFinalizedLogFilePrefix(hn *HarnessNode) string {
 		hn.PubKey[:logPubKeyBytes],
 	)
 
-	return fmt.Sprintf("%s/%d-%s-%s-%s",
-		GetLogDir(), hn.NodeID,
-		hn.Cfg.LogFilenamePrefix,
+	return fmt.Sprintf("%s/%d-%s-%s",
+		testCaseArtifactDir(hn.Cfg.LogFilenamePrefix), hn.NodeID,
 		hn.Cfg.Name, pubKeyHex)
 }
 
@@ -1871,19 +1863,23 @@ func finalizeEtcdLog(hn *HarnessNode) {
 func addLogFile(hn *HarnessNode) (string, error) {
 	var fileName string
 
-	dir := GetLogDir()
-	fileName = fmt.Sprintf("%s/%d-%s-%s-%s.log", dir, hn.NodeID,
-		hn.Cfg.LogFilenamePrefix, hn.Cfg.Name,
-		hex.EncodeToString(hn.PubKey[:logPubKeyBytes]))
+	// The logs of all nodes of a test case are collected in the artifact
+	// directory of the test case.
+	dir := testCaseArtifactDir(hn.Cfg.LogFilenamePrefix)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fileName, err
+	}
+
+	fileName = fmt.Sprintf("%s/%d-%s-%s.log", dir, hn.NodeID,
+		hn.Cfg.Name, hex.EncodeToString(hn.PubKey[:logPubKeyBytes]))
 
 	// If the node's PubKey is not yet initialized, create a
 	// temporary file name. Later, after the PubKey has been
 	// initialized, the file can be moved to its final name with
 	// the PubKey included.
 	if bytes.Equal(hn.PubKey[:4], []byte{0, 0, 0, 0}) {
-		fileName = fmt.Sprintf("%s/%d-%s-%s-tmp__.log", dir,
-			hn.NodeID, hn.Cfg.LogFilenamePrefix,
-			hn.Cfg.Name)
+		fileName = fmt.Sprintf("%s/%d-%s-tmp__.log", dir,
+			hn.NodeID, hn.Cfg.Name)
 	}
 
 	// Create file if not exists, otherwise append.
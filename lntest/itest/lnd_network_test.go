@@ -229,6 +229,52 @@ func testReconnectAfterIPChange(net *lntest.NetworkHarness, t *harnessTest) {
 	assertConnected(t, dave, charlie)
 }
 
+// testWebSocketPeerConnection checks that a node can connect to a peer that
+// listens for peer connections over WebSocket, both over plain ws:// and over
+// wss:// secured by TLS.
+func testWebSocketPeerConnection(net *lntest.NetworkHarness, t *harnessTest) {
+	wsPort := lntest.NextAvailablePort()
+	wssPort := lntest.NextAvailablePort()
+
+	// Create Dave, who listens for WebSocket connections next to his
+	// regular p2p listener.
+	dave := net.NewNode(t.t, "Dave", []string{
+		fmt.Sprintf("--wslisten=127.0.0.1:%d", wsPort),
+		fmt.Sprintf("--wsslisten=127.0.0.1:%d", wssPort),
+	})
+	defer shutdownAndAssert(net, t, dave)
+
+	carol := net.NewNode(t.t, "Carol", nil)
+	defer shutdownAndAssert(net, t, carol)
+
+	// connectOverWebSocket connects Carol to Dave at the given URL.
+	connectOverWebSocket := func(url string) {
+		ctxt, cancel := context.WithTimeout(
+			context.Background(), defaultTimeout,
+		)
+		defer cancel()
+
+		_, err := carol.ConnectPeer(ctxt, &lnrpc.ConnectPeerRequest{
+			Addr: &lnrpc.LightningAddress{
+				Pubkey: dave.PubKeyStr,
+				Host:   url,
+			},
+		})
+		require.NoError(t.t, err)
+
+		assertConnected(t, carol, dave)
+	}
+
+	// Carol connects to Dave over a plain WebSocket connection first.
+	connectOverWebSocket(fmt.Sprintf("ws://127.0.0.1:%d", wsPort))
+
+	require.NoError(t.t, net.DisconnectNodes(carol, dave))
+	assertNotConnected(t, carol, dave)
+
+	// Then, she connects over a WebSocket connection secured by TLS.
+	connectOverWebSocket(fmt.Sprintf("wss://127.0.0.1:%d", wssPort))
+}
+
 // assertTimeoutError asserts that a connection timeout error is raised. A
 // context with a default timeout is used to make the request. If our customized
 // connection timeout is less than the default, we won't see the request context
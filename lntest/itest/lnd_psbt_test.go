@@ -251,6 +251,200 @@ func runPsbtChanFunding(net *lntest.NetworkHarness, t *harnessTest, carol,
 	closeChannelAndAssert(t, net, carol, chanPoint, false)
 }
 
+// testPsbtChanFundingBatchVerify makes sure that several channels can be
+// funded with one PSBT by verifying and finalizing them together with the
+// additional_pending_chan_ids field, and that a batch is verified either as a
+// whole or not at all.
+func testPsbtChanFundingBatchVerify(net *lntest.NetworkHarness,
+	t *harnessTest) {
+
+	ctxb := context.Background()
+	ctxt, cancel := context.WithTimeout(ctxb, defaultTimeout)
+	defer cancel()
+
+	carol := net.NewNode(t.t, "carol", nil)
+	defer shutdownAndAssert(net, t, carol)
+
+	dave := net.NewNode(t.t, "dave", nil)
+	defer shutdownAndAssert(net, t, dave)
+
+	const chanSize = funding.MaxBtcFundingAmount
+	net.SendCoins(t.t, btcutil.SatoshiPerBitcoin, dave)
+
+	net.EnsureConnected(t.t, carol, dave)
+	net.EnsureConnected(t.t, carol, net.Alice)
+
+	var pendingChanID, pendingChanID2 [32]byte
+	_, err := rand.Read(pendingChanID[:])
+	require.NoError(t.t, err)
+	_, err = rand.Read(pendingChanID2[:])
+	require.NoError(t.t, err)
+
+	// Carol opens two channels that both want the funding transaction to
+	// be published, the second one adding its output to the PSBT of the
+	// first one.
+	chanUpdates, tempPsbt, err := openChannelPsbt(
+		ctxt, carol, dave, lntest.OpenChannelParams{
+			Amt: chanSize,
+			FundingShim: &lnrpc.FundingShim{
+				Shim: &lnrpc.FundingShim_PsbtShim{
+					PsbtShim: &lnrpc.PsbtShim{
+						PendingChanId: pendingChanID[:],
+					},
+				},
+			},
+		},
+	)
+	require.NoError(t.t, err)
+
+	chanUpdates2, psbtBytes2, err := openChannelPsbt(
+		ctxt, carol, net.Alice, lntest.OpenChannelParams{
+			Amt: chanSize,
+			FundingShim: &lnrpc.FundingShim{
+				Shim: &lnrpc.FundingShim_PsbtShim{
+					PsbtShim: &lnrpc.PsbtShim{
+						PendingChanId: pendingChanID2[:],
+						BasePsbt:      tempPsbt,
+					},
+				},
+			},
+		},
+	)
+	require.NoError(t.t, err)
+
+	fundResp, err := dave.WalletKitClient.FundPsbt(
+		ctxt, &walletrpc.FundPsbtRequest{
+			Template: &walletrpc.FundPsbtRequest_Psbt{
+				Psbt: psbtBytes2,
+			},
+			Fees: &walletrpc.FundPsbtRequest_SatPerVbyte{
+				SatPerVbyte: 2,
+			},
+		},
+	)
+	require.NoError(t.t, err)
+
+	// Create a copy of the funded PSBT that lacks the funding output of
+	// the second channel.
+	templatePacket, err := psbt.NewFromRawBytes(
+		bytes.NewReader(psbtBytes2), false,
+	)
+	require.NoError(t.t, err)
+	secondOutput := templatePacket.UnsignedTx.TxOut[1]
+
+	partialPacket, err := psbt.NewFromRawBytes(
+		bytes.NewReader(fundResp.FundedPsbt), false,
+	)
+	require.NoError(t.t, err)
+	for idx, out := range partialPacket.UnsignedTx.TxOut {
+		if !bytes.Equal(out.PkScript, secondOutput.PkScript) {
+			continue
+		}
+
+		partialPacket.UnsignedTx.TxOut = append(
+			partialPacket.UnsignedTx.TxOut[:idx],
+			partialPacket.UnsignedTx.TxOut[idx+1:]...,
+		)
+		partialPacket.Outputs = append(
+			partialPacket.Outputs[:idx],
+			partialPacket.Outputs[idx+1:]...,
+		)
+		break
+	}
+	var partialBuf bytes.Buffer
+	require.NoError(t.t, partialPacket.Serialize(&partialBuf))
+
+	// Verifying the batch with that PSBT must fail for both channels.
+	// Would the first channel have been verified, the second attempt
+	// below would fail because of its state.
+	_, err = carol.FundingStateStep(ctxb, &lnrpc.FundingTransitionMsg{
+		Trigger: &lnrpc.FundingTransitionMsg_PsbtVerify{
+			PsbtVerify: &lnrpc.FundingPsbtVerify{
+				PendingChanId: pendingChanID[:],
+				FundedPsbt:    partialBuf.Bytes(),
+				AdditionalPendingChanIds: [][]byte{
+					pendingChanID2[:],
+				},
+			},
+		},
+	})
+	require.ErrorContains(t.t, err, "funding output of channel 1 not "+
+		"found in PSBT")
+
+	// Now verify and finalize the batch with the complete PSBT.
+	_, err = carol.FundingStateStep(ctxb, &lnrpc.FundingTransitionMsg{
+		Trigger: &lnrpc.FundingTransitionMsg_PsbtVerify{
+			PsbtVerify: &lnrpc.FundingPsbtVerify{
+				PendingChanId: pendingChanID[:],
+				FundedPsbt:    fundResp.FundedPsbt,
+				AdditionalPendingChanIds: [][]byte{
+					pendingChanID2[:],
+				},
+			},
+		},
+	})
+	require.NoError(t.t, err)
+
+	finalizeRes, err := dave.WalletKitClient.FinalizePsbt(
+		ctxt, &walletrpc.FinalizePsbtRequest{
+			FundedPsbt: fundResp.FundedPsbt,
+		},
+	)
+	require.NoError(t.t, err)
+
+	_, err = carol.FundingStateStep(ctxb, &lnrpc.FundingTransitionMsg{
+		Trigger: &lnrpc.FundingTransitionMsg_PsbtFinalize{
+			PsbtFinalize: &lnrpc.FundingPsbtFinalize{
+				PendingChanId: pendingChanID[:],
+				SignedPsbt:    finalizeRes.SignedPsbt,
+				AdditionalPendingChanIds: [][]byte{
+					pendingChanID2[:],
+				},
+			},
+		},
+	})
+	require.NoError(t.t, err)
+
+	// Both channels should become pending with their own output of the
+	// same funding transaction.
+	var chanPoints []*lnrpc.ChannelPoint
+	for _, updates := range []lnrpc.Lightning_OpenChannelClient{
+		chanUpdates, chanUpdates2,
+	} {
+		updateResp, err := receiveChanUpdate(ctxt, updates)
+		require.NoError(t.t, err)
+		upd, ok := updateResp.Update.(*lnrpc.OpenStatusUpdate_ChanPending)
+		require.True(t.t, ok)
+
+		chanPoints = append(chanPoints, &lnrpc.ChannelPoint{
+			FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+				FundingTxidBytes: upd.ChanPending.Txid,
+			},
+			OutputIndex: upd.ChanPending.OutputIndex,
+		})
+	}
+	require.NotEqual(
+		t.t, chanPoints[0].OutputIndex, chanPoints[1].OutputIndex,
+	)
+
+	// The funding transaction is published once both channels are
+	// signed.
+	var finalTx wire.MsgTx
+	err = finalTx.Deserialize(bytes.NewReader(finalizeRes.RawFinalTx))
+	require.NoError(t.t, err)
+
+	txHash := finalTx.TxHash()
+	block := mineBlocks(t, net, 6, 1)[0]
+	assertTxInBlock(t, block, &txHash)
+	for _, chanPoint := range chanPoints {
+		err = carol.WaitForNetworkChannelOpen(chanPoint)
+		require.NoError(t.t, err)
+	}
+
+	closeChannelAndAssert(t, net, carol, chanPoints[0], false)
+	closeChannelAndAssert(t, net, carol, chanPoints[1], false)
+}
+
 // testPsbtChanFundingExternal makes sure a channel can be opened between carol
 // and dave by using a Partially Signed Bitcoin Transaction that funds the
 // channel multisig funding output and is fully funded by an external third
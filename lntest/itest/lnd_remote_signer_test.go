@@ -240,6 +240,94 @@ func testRemoteSigner(net *lntest.NetworkHarness, t *harnessTest) {
 	}
 }
 
+// testRemoteSignerTopology tests the funding, payment and close flows of
+// watch-only nodes that use a remote signer. Payments are sent from one
+// watch-only node through another one, so that the forwarding of HTLCs is
+// covered as well:
+//
+//	Alice (watch-only) -> Bob (watch-only) -> Carol
+func testRemoteSignerTopology(net *lntest.NetworkHarness, t *harnessTest) {
+	const (
+		chanAmt     = btcutil.Amount(1_000_000)
+		pushAmt     = btcutil.Amount(100_000)
+		paymentAmt  = btcutil.Amount(10_000)
+		numPayments = 3
+	)
+
+	alice := net.NewRemoteSignerPair(t.t, "Alice", nil)
+	defer func() {
+		require.NoError(t.t, net.ShutdownRemoteSignerPair(alice))
+	}()
+
+	bob := net.NewRemoteSignerPair(t.t, "Bob", nil)
+	defer func() {
+		require.NoError(t.t, net.ShutdownRemoteSignerPair(bob))
+	}()
+
+	carol := net.NewNode(t.t, "Carol", nil)
+	defer shutdownAndAssert(net, t, carol)
+
+	// Both watch-only nodes need on-chain funds to open their channels,
+	// which also asserts that the signer can sign for the wallet's
+	// inputs.
+	net.SendCoins(t.t, btcutil.SatoshiPerBitcoin, alice.WatchOnly)
+	net.SendCoins(t.t, btcutil.SatoshiPerBitcoin, bob.WatchOnly)
+
+	net.ConnectNodes(t.t, alice.WatchOnly, bob.WatchOnly)
+	net.ConnectNodes(t.t, bob.WatchOnly, carol)
+
+	chanPointAlice := openChannelAndAssert(
+		t, net, alice.WatchOnly, bob.WatchOnly,
+		lntest.OpenChannelParams{
+			Amt: chanAmt,
+		},
+	)
+	chanPointBob := openChannelAndAssert(
+		t, net, bob.WatchOnly, carol, lntest.OpenChannelParams{
+			Amt:     chanAmt,
+			PushAmt: pushAmt,
+		},
+	)
+
+	nodes := []*lntest.HarnessNode{alice.WatchOnly, bob.WatchOnly, carol}
+	for _, node := range nodes {
+		for _, chanPoint := range []*lnrpc.ChannelPoint{
+			chanPointAlice, chanPointBob,
+		} {
+			err := node.WaitForNetworkChannelOpen(chanPoint)
+			require.NoError(t.t, err, "%s didn't see channel",
+				node.Name())
+		}
+	}
+
+	// Alice pays Carol through Bob, which requires both watch-only nodes
+	// to sign new commitments.
+	payReqs, _, _, err := createPayReqs(carol, paymentAmt, numPayments)
+	require.NoError(t.t, err)
+	err = completePaymentRequests(
+		alice.WatchOnly, alice.WatchOnly.RouterClient, payReqs, true,
+	)
+	require.NoError(t.t, err)
+
+	// Carol pays Alice back through Bob, so that the watch-only nodes
+	// also settle incoming HTLCs.
+	payReqs, _, _, err = createPayReqs(
+		alice.WatchOnly, paymentAmt, numPayments,
+	)
+	require.NoError(t.t, err)
+	err = completePaymentRequests(
+		carol, carol.RouterClient, payReqs, true,
+	)
+	require.NoError(t.t, err)
+
+	// Finally, we'll close Alice's channel cooperatively and force close
+	// Bob's channel, which requires the signer to sign the sweeps of
+	// Bob's outputs.
+	closeChannelAndAssert(t, net, alice.WatchOnly, chanPointAlice, false)
+	closeChannelAndAssert(t, net, bob.WatchOnly, chanPointBob, true)
+	cleanupForceClose(t, net, bob.WatchOnly, chanPointBob)
+}
+
 // deriveCustomScopeAccounts derives the first 255 default accounts of the custom lnd
 // internal key scope.
 func deriveCustomScopeAccounts(t *testing.T) []*lnrpc.WatchOnlyAccount {
@@ -14,7 +14,6 @@ import (
 	"github.com/lightningnetwork/lnd/funding"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/watchtowerrpc"
-	"github.com/lightningnetwork/lnd/lnrpc/wtclientrpc"
 	"github.com/lightningnetwork/lnd/lntest"
 	"github.com/lightningnetwork/lnd/lntest/wait"
 	"github.com/stretchr/testify/require"
@@ -962,24 +961,14 @@ func testRevokedCloseRetributionAltruistWatchtowerCase(
 	// Dave will be the breached party. We set --nolisten to ensure Carol
 	// won't be able to connect to him and trigger the channel data
 	// protection logic automatically.
-	daveArgs := []string{
-		"--nolisten",
-		"--wtclient.active",
-	}
+	daveArgs := []string{"--nolisten"}
 	if anchors {
 		daveArgs = append(daveArgs, "--protocol.anchors")
 	}
-	dave := net.NewNode(t.t, "Dave", daveArgs)
+	dave := net.NewWatchtowerClient(t.t, "Dave", daveArgs, 0)
 	defer shutdownAndAssert(net, t, dave)
 
-	ctxt, _ = context.WithTimeout(ctxb, defaultTimeout)
-	addTowerReq := &wtclientrpc.AddTowerRequest{
-		Pubkey:  willyInfo.Pubkey,
-		Address: listener,
-	}
-	if _, err := dave.WatchtowerClient.AddTower(ctxt, addTowerReq); err != nil {
-		t.Fatalf("unable to add willy's watchtower: %v", err)
-	}
+	net.AddTower(t.t, dave, willy)
 
 	// We must let Dave have an open channel before she can send a node
 	// announcement, so we open a channel with Carol,
@@ -1064,25 +1053,7 @@ func testRevokedCloseRetributionAltruistWatchtowerCase(
 
 	// Wait until the backup has been accepted by the watchtower before
 	// shutting down Dave.
-	err = wait.NoError(func() error {
-		ctxt, cancel := context.WithTimeout(ctxb, defaultTimeout)
-		defer cancel()
-		bkpStats, err := dave.WatchtowerClient.Stats(ctxt,
-			&wtclientrpc.StatsRequest{},
-		)
-		if err != nil {
-			return err
-		}
-		if bkpStats == nil {
-			return errors.New("no active backup sessions")
-		}
-		if bkpStats.NumBackups == 0 {
-			return errors.New("no backups accepted")
-		}
-
-		return nil
-	}, defaultTimeout)
-	if err != nil {
+	if err := dave.WaitForTowerBackups(1); err != nil {
 		t.Fatalf("unable to verify backup task completed: %v", err)
 	}
 
@@ -1620,12 +1620,22 @@ func testMissionControlCfg(t *testing.T, node *lntest.HarnessNode) {
 	)
 	require.NoError(t, err)
 
+	// Set the apriori estimator parameters, which are also reflected in
+	// the deprecated fields.
 	cfg := &routerrpc.MissionControlConfig{
 		HalfLifeSeconds:             8000,
 		HopProbability:              0.8,
 		Weight:                      0.3,
 		MaximumPaymentResults:       30,
 		MinimumFailureRelaxInterval: 60,
+		Model:                       routerrpc.MissionControlConfig_APRIORI,
+		EstimatorConfig: &routerrpc.MissionControlConfig_Apriori{
+			Apriori: &routerrpc.AprioriParameters{
+				HalfLifeSeconds: 8000,
+				HopProbability:  0.8,
+				Weight:          0.3,
+			},
+		},
 	}
 
 	_, err = node.RouterClient.SetMissionControlConfig(
@@ -1641,6 +1651,33 @@ func testMissionControlCfg(t *testing.T, node *lntest.HarnessNode) {
 	require.NoError(t, err)
 	require.True(t, proto.Equal(cfg, resp.Config))
 
+	// Switch to the bimodal estimator.
+	cfg = &routerrpc.MissionControlConfig{
+		MaximumPaymentResults:       30,
+		MinimumFailureRelaxInterval: 60,
+		Model:                       routerrpc.MissionControlConfig_BIMODAL,
+		EstimatorConfig: &routerrpc.MissionControlConfig_Bimodal{
+			Bimodal: &routerrpc.BimodalParameters{
+				NodeWeight: 0.3,
+				ScaleMsat:  1000,
+				DecayTime:  500,
+			},
+		},
+	}
+
+	_, err = node.RouterClient.SetMissionControlConfig(
+		ctxb, &routerrpc.SetMissionControlConfigRequest{
+			Config: cfg,
+		},
+	)
+	require.NoError(t, err)
+
+	resp, err = node.RouterClient.GetMissionControlConfig(
+		ctxb, &routerrpc.GetMissionControlConfigRequest{},
+	)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(cfg, resp.Config))
+
 	_, err = node.RouterClient.SetMissionControlConfig(
 		ctxb, &routerrpc.SetMissionControlConfigRequest{
 			Config: startCfg.Config,
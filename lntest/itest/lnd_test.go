@@ -45,8 +45,43 @@ var (
 	// dbBackendFlag specifies the backend to use.
 	dbBackendFlag = flag.String("dbbackend", "bbolt", "Database backend "+
 		"(bbolt, etcd, postgres)")
+
+	// retriedFlakes describes each test case that was retried after
+	// failing with a known flake, along with the flake and the outcome of
+	// its retry.
+	retriedFlakes []string
+
+	// onlyKnownFlakesFailed is set if all test cases passed, but some of
+	// them only after being retried because they failed with a known
+	// flake.
+	onlyKnownFlakesFailed bool
 )
 
+// TestMain runs the integration tests and reports the test cases that were
+// retried after failing with a known flake. As go test can't take back the
+// failure of a sub test, the failed first attempt of such a test case is still
+// reported as failed. If that's the only failure of the test run, because the
+// retry passed, the test run succeeds nonetheless.
+func TestMain(m *testing.M) {
+	code := m.Run()
+
+	if len(retriedFlakes) > 0 {
+		fmt.Println("Test cases retried after failing with a known " +
+			"flake:")
+		for _, retried := range retriedFlakes {
+			fmt.Printf("  %v\n", retried)
+		}
+	}
+
+	if code != 0 && onlyKnownFlakesFailed {
+		fmt.Println("All failed test cases passed on retry, ignoring " +
+			"the failures of known flakes")
+		code = 0
+	}
+
+	os.Exit(code)
+}
+
 // getTestCaseSplitTranche returns the sub slice of the test cases that should
 // be run as the current split tranche as well as the index and slice offset of
 // the tranche.
@@ -118,13 +153,18 @@ func TestLightningNetworkDaemon(t *testing.T) {
 		require.NoError(t, miner.Stop(), "failed to stop miner")
 	}()
 
-	// Start a chain backend.
-	chainBackend, cleanUp, err := lntest.NewBackend(
+	// Start a chain backend, or attach to an external one if configured
+	// through the environment.
+	chainBackend, cleanUp, err := lntest.NewChainBackend(
 		miner.P2PAddress(), harnessNetParams,
 	)
 	require.NoError(t, err, "new backend")
 	defer func() {
-		require.NoError(t, cleanUp(), "cleanup")
+		err := cleanUp()
+		if err != nil {
+			onlyKnownFlakesFailed = false
+		}
+		require.NoError(t, err, "cleanup")
 	}()
 
 	// Before we start anything, we want to overwrite some of the connection
@@ -202,48 +242,35 @@ func TestLightningNetworkDaemon(t *testing.T) {
 	}
 
 	// Run the subset of the test cases selected in this tranche.
+	allPassed := true
 	for idx, testCase := range testCases {
 		testCase := testCase
 		name := fmt.Sprintf("tranche%02d/%02d-of-%d/%s/%s",
 			trancheIndex, trancheOffset+uint(idx)+1,
 			len(allTestCases), chainBackend.Name(), testCase.name)
-
-		success := t.Run(name, func(t1 *testing.T) {
-			cleanTestCaseName := strings.ReplaceAll(
-				testCase.name, " ", "_",
-			)
-
-			err = lndHarness.SetUp(
-				t1, cleanTestCaseName, aliceBobArgs,
-			)
-			require.NoError(t1,
-				err, "unable to set up test lightning network",
-			)
-			defer func() {
-				require.NoError(t1, lndHarness.TearDown())
-			}()
-
-			lndHarness.EnsureConnected(
-				t1, lndHarness.Alice, lndHarness.Bob,
+		logName := strings.ReplaceAll(testCase.name, " ", "_")
+
+		success, flake := runTestCase(
+			t, lndHarness, testCase, name, logName, aliceBobArgs,
+		)
+
+		// A test case that failed with a known flake is retried once.
+		// It's retried under a name of its own, so that its artifacts
+		// don't overwrite the ones of the failed run.
+		if flake != nil {
+			t.Logf("Test case %v failed with known flake %v, "+
+				"retrying", testCase.name, flake)
+
+			success, _ = runTestCase(
+				t, lndHarness, testCase, name+"_retry",
+				logName+"_retry", aliceBobArgs,
 			)
 
-			logLine := fmt.Sprintf(
-				"STARTING ============ %v ============\n",
-				testCase.name,
-			)
-
-			lndHarness.Alice.AddToLogf(logLine)
-			lndHarness.Bob.AddToLogf(logLine)
-
-			// Start every test with the default static fee estimate.
-			lndHarness.SetFeeEstimate(12500)
-
-			// Create a separate harness test for the testcase to
-			// avoid overwriting the external harness test that is
-			// tied to the parent test.
-			ht := newHarnessTest(t1, lndHarness)
-			ht.RunTestCase(testCase)
-		})
+			retriedFlakes = append(retriedFlakes, fmt.Sprintf(
+				"%v (%v): passed=%v", testCase.name, flake,
+				success,
+			))
+		}
 
 		// Stop at the first failure. Mimic behavior of original test
 		// framework.
@@ -253,7 +280,77 @@ func TestLightningNetworkDaemon(t *testing.T) {
 			t.Logf("Failure time: %v", time.Now().Format(
 				"2006-01-02 15:04:05.000",
 			))
+			allPassed = false
 			break
 		}
 	}
+
+	// If the only failures were known flakes that passed on retry, they
+	// don't fail the test run.
+	onlyKnownFlakesFailed = allPassed && len(retriedFlakes) > 0
+}
+
+// runTestCase runs a single test case as a sub test of the given test, with its
+// nodes logging under the given name. If the test case fails, the known flake
+// signature that matches the logs of its nodes is returned, if any.
+func runTestCase(t *testing.T, lndHarness *lntest.NetworkHarness,
+	testCase *testCase, name, logName string,
+	lndArgs []string) (bool, *lntest.FlakeSignature) {
+
+	success := t.Run(name, func(t1 *testing.T) {
+		err := lndHarness.SetUp(t1, logName, lndArgs)
+		require.NoError(t1,
+			err, "unable to set up test lightning network",
+		)
+		lndHarness.SetKnownFlakes(testCase.knownFlakes)
+		defer func() {
+			// Dump the goroutines of the nodes before shutting
+			// them down if the test case failed.
+			if t1.Failed() {
+				lndHarness.SaveProfilesPages(t1)
+			}
+
+			require.NoError(t1, lndHarness.TearDown())
+		}()
+
+		lndHarness.EnsureConnected(
+			t1, lndHarness.Alice, lndHarness.Bob,
+		)
+
+		logLine := fmt.Sprintf(
+			"STARTING ============ %v ============\n",
+			testCase.name,
+		)
+
+		lndHarness.Alice.AddToLogf(logLine)
+		lndHarness.Bob.AddToLogf(logLine)
+
+		// Start every test with the default static fee estimate.
+		lndHarness.SetFeeEstimate(12500)
+
+		// Create a separate harness test for the testcase to avoid
+		// overwriting the external harness test that is tied to the
+		// parent test.
+		ht := newHarnessTest(t1, lndHarness)
+		ht.RunTestCase(testCase)
+	})
+
+	// The logs of the nodes were searched for known flakes when they were
+	// shut down.
+	var flake *lntest.FlakeSignature
+	if !success {
+		var err error
+		flake, err = lndHarness.MatchedFlake()
+		if err != nil {
+			t.Logf("Unable to match known flakes: %v", err)
+		}
+	}
+
+	// Add the artifacts of the test case to the index now that its nodes
+	// were shut down.
+	if err := lndHarness.RecordArtifacts(success); err != nil {
+		t.Logf("Unable to record artifacts: %v", err)
+	}
+
+	return success, flake
 }
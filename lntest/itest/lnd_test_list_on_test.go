@@ -3,6 +3,8 @@
 
 package itest
 
+import "github.com/lightningnetwork/lnd/lntest"
+
 var allTestCases = []*testCase{
 	{
 		name: "test multi-hop htlc",
@@ -56,6 +58,10 @@ var allTestCases = []*testCase{
 		name: "reconnect after ip change",
 		test: testReconnectAfterIPChange,
 	},
+	{
+		name: "websocket peer connection",
+		test: testWebSocketPeerConnection,
+	},
 	{
 		name: "graph topology notifications",
 		test: testGraphTopologyNotifications,
@@ -231,6 +237,10 @@ var allTestCases = []*testCase{
 		name: "revoked uncooperative close retribution altruist watchtower",
 		test: testRevokedCloseRetributionAltruistWatchtower,
 	},
+	{
+		name: "watchtower downtime and session exhaustion",
+		test: testWatchtowerDowntimeSessionExhaustion,
+	},
 	{
 		name: "data loss protection",
 		test: testDataLossProtection,
@@ -274,6 +284,9 @@ var allTestCases = []*testCase{
 	{
 		name: "cpfp",
 		test: testCPFP,
+		knownFlakes: []*lntest.FlakeSignature{
+			flakeNeutrinoQueryTimeout,
+		},
 	},
 	{
 		name: "anchors reserved value",
@@ -303,6 +316,10 @@ var allTestCases = []*testCase{
 		name: "psbt channel funding",
 		test: testPsbtChanFunding,
 	},
+	{
+		name: "psbt channel funding batch verify",
+		test: testPsbtChanFundingBatchVerify,
+	},
 	{
 		name: "psbt channel funding external",
 		test: testPsbtChanFundingExternal,
@@ -399,6 +416,10 @@ var allTestCases = []*testCase{
 		name: "remote signer",
 		test: testRemoteSigner,
 	},
+	{
+		name: "remote signer topology",
+		test: testRemoteSignerTopology,
+	},
 	{
 		name: "3rd party anchor spend",
 		test: testAnchorThirdPartySpend,
@@ -0,0 +1,183 @@
+package itest
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/funding"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/stretchr/testify/require"
+)
+
+// testWatchtowerDowntimeSessionExhaustion tests that a watchtower client keeps
+// backing up revoked states while its tower is temporarily offline and while
+// its sessions are exhausted, and that the tower punishes a breach of a state
+// that was backed up under these conditions on behalf of the offline client.
+func testWatchtowerDowntimeSessionExhaustion(net *lntest.NetworkHarness,
+	t *harnessTest) {
+
+	const (
+		chanAmt     = funding.MaxBtcFundingAmount
+		paymentAmt  = 10000
+		numInvoices = 6
+
+		// maxUpdates is the number of updates each session of Dave
+		// allows. It is chosen small enough so that the payments below
+		// exhaust several sessions.
+		maxUpdates = 2
+	)
+
+	// Carol will be the breaching party.
+	carol := net.NewNode(t.t, "Carol", []string{"--hodl.exit-settle"})
+	defer shutdownAndAssert(net, t, carol)
+
+	// Willy the watchtower will protect Dave from Carol's breach.
+	willy := net.NewWatchtower(t.t, "Willy", nil)
+	defer shutdownAndAssert(net, t, willy)
+
+	// Dave will be the breached party. We set --nolisten to ensure Carol
+	// won't be able to connect to him and trigger the channel data
+	// protection logic automatically. We also shorten the backoff of his
+	// watchtower client, so that he reconnects to Willy soon after Willy
+	// comes back online.
+	dave := net.NewWatchtowerClient(t.t, "Dave", []string{
+		"--nolisten",
+		"--wtclient.min-backoff=1s",
+		"--wtclient.max-backoff=2s",
+	}, maxUpdates)
+	defer shutdownAndAssert(net, t, dave)
+
+	net.AddTower(t.t, dave, willy)
+
+	// Open a channel between Dave and Carol and create a few invoices for
+	// Carol that Dave will pay to in order to advance the state of the
+	// channel.
+	net.ConnectNodes(t.t, dave, carol)
+	net.SendCoins(t.t, btcutil.SatoshiPerBitcoin, dave)
+
+	chanPoint := openChannelAndAssert(
+		t, net, dave, carol, lntest.OpenChannelParams{
+			Amt:     3 * (chanAmt / 4),
+			PushAmt: chanAmt / 4,
+		},
+	)
+
+	carolPayReqs, _, _, err := createPayReqs(
+		carol, paymentAmt, numInvoices,
+	)
+	require.NoError(t.t, err, "unable to create pay reqs")
+
+	err = dave.WaitForNetworkChannelOpen(chanPoint)
+	require.NoError(t.t, err, "dave didn't see the channel")
+
+	// Grab Carol's current commitment height and back up her current
+	// state, which she'll later broadcast after it has been revoked.
+	carolChan, err := getChanInfo(carol)
+	require.NoError(t.t, err, "unable to get carol's channel info")
+	carolStateNumPreCopy := carolChan.NumUpdates
+
+	require.NoError(t.t, net.BackupDb(carol), "unable to copy database")
+	net.EnsureConnected(t.t, dave, carol)
+
+	// Take Willy offline before sending the first payments, which revokes
+	// the state backed up above.
+	restartWilly, err := net.SuspendNode(willy)
+	require.NoError(t.t, err, "unable to suspend willy")
+
+	stats, err := dave.TowerStats()
+	require.NoError(t.t, err, "unable to get tower stats")
+	numBackupsPreDowntime := stats.NumBackups
+
+	err = completePaymentRequests(
+		dave, dave.RouterClient, carolPayReqs[:numInvoices/2], false,
+	)
+	require.NoError(t.t, err, "unable to send payments")
+
+	// As long as Willy is offline, none of the new states can be backed
+	// up.
+	err = wait.Invariant(func() bool {
+		stats, err := dave.TowerStats()
+		if err != nil {
+			return false
+		}
+
+		return stats.NumBackups == numBackupsPreDowntime
+	}, 5*time.Second)
+	require.NoError(t.t, err, "backups accepted while tower offline")
+
+	// Bring Willy back online and send the remaining payments. Dave should
+	// back up all revoked states, exhausting several sessions along the
+	// way.
+	require.NoError(t.t, restartWilly(), "unable to restart willy")
+
+	err = completePaymentRequests(
+		dave, dave.RouterClient, carolPayReqs[numInvoices/2:], false,
+	)
+	require.NoError(t.t, err, "unable to send payments")
+
+	err = dave.WaitForTowerBackups(numInvoices)
+	require.NoError(t.t, err, "backups not accepted after downtime")
+
+	err = dave.WaitForTowerSessions(2, 1)
+	require.NoError(t.t, err, "sessions not exhausted")
+
+	// Shut down Dave to simulate him going offline, then let Carol travel
+	// back in time and broadcast her revoked state.
+	restartDave, err := net.SuspendNode(dave)
+	require.NoError(t.t, err, "unable to suspend dave")
+
+	err = net.RestartNode(carol, func() error {
+		return net.RestoreDb(carol)
+	})
+	require.NoError(t.t, err, "unable to restart carol")
+
+	carolChan, err = getChanInfo(carol)
+	require.NoError(t.t, err, "unable to get carol's channel info")
+	require.Equal(
+		t.t, carolStateNumPreCopy, carolChan.NumUpdates,
+		"db copy failed",
+	)
+
+	closeUpdates, closeTxID, err := net.CloseChannel(carol, chanPoint, true)
+	require.NoError(t.t, err, "unable to close channel")
+
+	txid, err := waitForTxInMempool(net.Miner.Client, minerMempoolTimeout)
+	require.NoError(t.t, err, "unable to find carol's force close tx")
+	require.Equal(t.t, closeTxID, txid, "unexpected tx in mempool")
+
+	block := mineBlocks(t, net, 1, 1)[0]
+
+	breachTXID, err := net.WaitForChannelClose(closeUpdates)
+	require.NoError(t.t, err, "error while waiting for channel close")
+	assertTxInBlock(t, block, breachTXID)
+
+	// Willy should now publish the justice transaction on Dave's behalf,
+	// spending only outputs of Carol's breach transaction.
+	justiceTXID, err := waitForTxInMempool(
+		net.Miner.Client, minerMempoolTimeout,
+	)
+	require.NoError(t.t, err, "unable to find justice tx in mempool")
+
+	justiceTx, err := net.Miner.Client.GetRawTransaction(justiceTXID)
+	require.NoError(t.t, err, "unable to query for justice tx")
+	for _, txIn := range justiceTx.MsgTx().TxIn {
+		require.True(
+			t.t, bytes.Equal(
+				txIn.PreviousOutPoint.Hash[:], breachTXID[:],
+			), fmt.Sprintf("justice tx not spending commitment "+
+				"utxo, instead is: %v", txIn.PreviousOutPoint),
+		)
+	}
+
+	block = mineBlocks(t, net, 1, 1)[0]
+	assertTxInBlock(t, block, justiceTXID)
+
+	// Once Dave comes back online, he should detect the breach and find
+	// that the channel has already been swept by Willy.
+	require.NoError(t.t, restartDave(), "unable to restart dave")
+
+	assertNumPendingChannels(t, dave, 0, 0)
+}
@@ -33,6 +33,15 @@ var (
 	)
 
 	slowMineDelay = 20 * time.Millisecond
+
+	// flakeNeutrinoQueryTimeout matches a neutrino node giving up on
+	// fetching a block filter from the miner. The node then misses the
+	// transactions of the block until it rescans, which makes test cases
+	// waiting for those transactions time out.
+	flakeNeutrinoQueryTimeout = lntest.NewFlakeSignature(
+		"neutrino filter query timeout",
+		`BTCN: Query failed with 0 out of \d+ filters received`,
+	)
 )
 
 const (
@@ -151,6 +160,12 @@ func (h *harnessTest) getLndBinary() string {
 type testCase struct {
 	name string
 	test func(net *lntest.NetworkHarness, t *harnessTest)
+
+	// knownFlakes quarantines the known flakes of the test case. If the
+	// test case fails and one of the signatures matches the logs of its
+	// nodes, it's retried once, and reported separately. If the retry
+	// passes, the failed first attempt doesn't fail the test run.
+	knownFlakes []*lntest.FlakeSignature
 }
 
 // waitForTxInMempool polls until finding one transaction in the provided
@@ -0,0 +1,312 @@
+package lntest
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// proxyReadBufferSize is the size of the chunks the link proxy reads
+	// from a connection before forwarding them.
+	proxyReadBufferSize = 64 * 1024
+
+	// proxyQueueSize is the number of chunks that can be in flight on a
+	// connection with latency before the proxy stops reading from it.
+	proxyQueueSize = 1024
+)
+
+// linkConditions are the network conditions applied to the traffic between
+// two nodes.
+type linkConditions struct {
+	// partitioned is true if the nodes can't reach each other.
+	partitioned bool
+
+	// latency is the delay added to all traffic in each direction.
+	latency time.Duration
+
+	// dropPercent is the percentage of forwarded chunks that are lost.
+	dropPercent uint32
+}
+
+// linkProxy is a TCP proxy in front of the p2p listener of a node, that
+// connections from one specific peer are routed through. The proxy forwards
+// the encrypted brontide stream without terminating it, and applies the
+// conditions of the link between the two nodes to it.
+//
+// As brontide authenticates every message and derives the key of the next one
+// from it, a single lost message breaks the cipher state of the connection.
+// Dropping a message is therefore simulated by tearing down the connection it
+// was sent on, which is what the peers observe on a real lossy link once the
+// transport gives up.
+type linkProxy struct {
+	listener net.Listener
+	target   string
+
+	mu    sync.Mutex
+	conds linkConditions
+	rand  *rand.Rand
+	conns map[net.Conn]struct{}
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newLinkProxy starts a proxy that listens on a local port and forwards all
+// connections to the given target address. The seed makes the dropped
+// messages reproducible across test runs.
+func newLinkProxy(target string, seed int64) (*linkProxy, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("unable to start link proxy: %v", err)
+	}
+
+	p := &linkProxy{
+		listener: listener,
+		target:   target,
+		rand:     rand.New(rand.NewSource(seed)),
+		conns:    make(map[net.Conn]struct{}),
+		quit:     make(chan struct{}),
+	}
+
+	p.wg.Add(1)
+	go p.acceptConns()
+
+	return p, nil
+}
+
+// Addr returns the address connections need to be made to in order to be
+// routed through the proxy.
+func (p *linkProxy) Addr() string {
+	return p.listener.Addr().String()
+}
+
+// setConditions updates the conditions of the link. If the link is
+// partitioned, all connections through the proxy are torn down.
+func (p *linkProxy) setConditions(conds linkConditions) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.conds = conds
+
+	if conds.partitioned {
+		p.closeConnsLocked()
+	}
+}
+
+// conditions returns the current conditions of the link.
+func (p *linkProxy) conditions() linkConditions {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.conds
+}
+
+// shouldDrop decides whether the next forwarded chunk is lost.
+func (p *linkProxy) shouldDrop() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conds.dropPercent == 0 {
+		return false
+	}
+
+	return uint32(p.rand.Intn(100)) < p.conds.dropPercent
+}
+
+// acceptConns accepts new connections and forwards them to the target, unless
+// the link is partitioned.
+//
+// NOTE: This MUST be run as a goroutine.
+func (p *linkProxy) acceptConns() {
+	defer p.wg.Done()
+
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		// A partitioned node is unreachable, so we refuse the
+		// connection before it reaches the target.
+		if p.conditions().partitioned {
+			conn.Close()
+			continue
+		}
+
+		target, err := net.Dial("tcp", p.target)
+		if err != nil {
+			conn.Close()
+			continue
+		}
+
+		// The link may have been partitioned while we were dialing.
+		p.mu.Lock()
+		if p.conds.partitioned {
+			p.mu.Unlock()
+			conn.Close()
+			target.Close()
+			continue
+		}
+		p.conns[conn] = struct{}{}
+		p.conns[target] = struct{}{}
+		p.mu.Unlock()
+
+		p.wg.Add(2)
+		go p.forward(conn, target)
+		go p.forward(target, conn)
+	}
+}
+
+// proxyChunk is a chunk of the forwarded stream along with the time it may
+// be delivered at.
+type proxyChunk struct {
+	data      []byte
+	deliverAt time.Time
+}
+
+// forward copies the stream from src to dst, applying the conditions of the
+// link to it. Both connections are closed once either direction fails.
+//
+// NOTE: This MUST be run as a goroutine.
+func (p *linkProxy) forward(src, dst net.Conn) {
+	defer p.wg.Done()
+	defer p.closeConns(src, dst)
+
+	// Chunks are delivered by a separate goroutine, so that the latency
+	// doesn't throttle the throughput of the link and the order of the
+	// stream is kept.
+	chunks := make(chan proxyChunk, proxyQueueSize)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		for chunk := range chunks {
+			select {
+			case <-time.After(time.Until(chunk.deliverAt)):
+			case <-p.quit:
+				return
+			}
+
+			if _, err := dst.Write(chunk.data); err != nil {
+				return
+			}
+		}
+	}()
+	defer func() {
+		close(chunks)
+		<-done
+	}()
+
+	buf := make([]byte, proxyReadBufferSize)
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			if p.shouldDrop() {
+				return
+			}
+
+			data := make([]byte, n)
+			copy(data, buf[:n])
+
+			chunk := proxyChunk{
+				data: data,
+				deliverAt: time.Now().Add(
+					p.conditions().latency,
+				),
+			}
+
+			select {
+			case chunks <- chunk:
+			case <-done:
+				return
+			case <-p.quit:
+				return
+			}
+		}
+
+		// The connection was closed by either side, or torn down
+		// by a partition.
+		if err != nil {
+			return
+		}
+	}
+}
+
+// closeConns closes the given connections and stops tracking them.
+func (p *linkProxy) closeConns(conns ...net.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, conn := range conns {
+		conn.Close()
+		delete(p.conns, conn)
+	}
+}
+
+// closeConnsLocked closes all connections through the proxy.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (p *linkProxy) closeConnsLocked() {
+	for conn := range p.conns {
+		conn.Close()
+		delete(p.conns, conn)
+	}
+}
+
+// stop closes the listener and all connections of the proxy.
+func (p *linkProxy) stop() {
+	close(p.quit)
+	p.listener.Close()
+
+	p.mu.Lock()
+	p.closeConnsLocked()
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+// nodeLink holds the two proxies between a pair of nodes, one for the
+// connections each of them initiates.
+type nodeLink struct {
+	// toA is the proxy that connections from b to a are routed through.
+	toA *linkProxy
+
+	// toB is the proxy that connections from a to b are routed through.
+	toB *linkProxy
+}
+
+// setConditions applies the conditions to the traffic in both directions.
+func (l *nodeLink) setConditions(conds linkConditions) {
+	l.toA.setConditions(conds)
+	l.toB.setConditions(conds)
+}
+
+// stop stops both proxies of the link.
+func (l *nodeLink) stop() {
+	l.toA.stop()
+	l.toB.stop()
+}
+
+// linkKey returns the key of the link between two nodes, which is the same
+// regardless of their order.
+func linkKey(a, b *HarnessNode) [2]string {
+	if a.Cfg.Name > b.Cfg.Name {
+		a, b = b, a
+	}
+
+	return [2]string{a.Cfg.Name, b.Cfg.Name}
+}
+
+// linkSeed derives the seed of the proxies of a link from the names of the
+// nodes, so that dropped messages are the same on every run of a test.
+func linkSeed(key [2]string) int64 {
+	var seed int64
+	for _, c := range key[0] + "/" + key[1] {
+		seed = seed*31 + int64(c)
+	}
+
+	return seed
+}
@@ -0,0 +1,110 @@
+package lntest
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// startEchoServer starts a TCP server that echoes everything it receives.
+func startEchoServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// roundTrip sends a message through the connection and waits for its echo.
+func roundTrip(conn net.Conn, msg []byte) error {
+	if _, err := conn.Write(msg); err != nil {
+		return err
+	}
+
+	resp := make([]byte, len(msg))
+	_, err := io.ReadFull(conn, resp)
+
+	return err
+}
+
+// TestLinkProxy tests that the link proxy applies latency, drops and
+// partitions to the forwarded connections.
+func TestLinkProxy(t *testing.T) {
+	t.Parallel()
+
+	proxy, err := newLinkProxy(startEchoServer(t), 1)
+	require.NoError(t, err)
+	defer proxy.stop()
+
+	msg := []byte("ping")
+	dial := func() net.Conn {
+		conn, err := net.Dial("tcp", proxy.Addr())
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+
+		require.NoError(
+			t, conn.SetDeadline(time.Now().Add(5*time.Second)),
+		)
+
+		return conn
+	}
+
+	// Without any conditions, the stream is forwarded as is.
+	conn := dial()
+	require.NoError(t, roundTrip(conn, msg))
+
+	// With latency, the round trip takes at least twice the latency.
+	latency := 100 * time.Millisecond
+	proxy.setConditions(linkConditions{latency: latency})
+	start := time.Now()
+	require.NoError(t, roundTrip(conn, msg))
+	require.GreaterOrEqual(t, time.Since(start), 2*latency)
+
+	// Partitioning the link tears down the existing connection and
+	// refuses new ones.
+	proxy.setConditions(linkConditions{partitioned: true})
+	require.Error(t, roundTrip(conn, msg))
+	require.Error(t, roundTrip(dial(), msg))
+
+	// Once healed, new connections go through again.
+	proxy.setConditions(linkConditions{})
+	conn = dial()
+	require.NoError(t, roundTrip(conn, msg))
+
+	// If all messages are dropped, the connection breaks on the next
+	// message.
+	proxy.setConditions(linkConditions{dropPercent: 100})
+	require.Error(t, roundTrip(conn, msg))
+}
+
+// TestLinkKey tests that the link between two nodes is the same regardless of
+// their order.
+func TestLinkKey(t *testing.T) {
+	t.Parallel()
+
+	alice := &HarnessNode{Cfg: &BaseNodeConfig{Name: "Alice"}}
+	bob := &HarnessNode{Cfg: &BaseNodeConfig{Name: "Bob"}}
+
+	require.Equal(t, linkKey(alice, bob), linkKey(bob, alice))
+	require.Equal(
+		t, linkSeed(linkKey(alice, bob)),
+		linkSeed(linkKey(bob, alice)),
+	)
+}
@@ -139,6 +139,18 @@ func (w *WalletController) ImportTaprootScript(waddrmgr.KeyScope,
 	return nil, nil
 }
 
+// ImportWatchOnly currently returns dummy values.
+func (w *WalletController) ImportWatchOnly(*lnwallet.WatchOnlyImport) (
+	[]*waddrmgr.AccountProperties, []btcutil.Address, error) {
+
+	return nil, nil, nil
+}
+
+// RescanAddresses currently returns a dummy value.
+func (w *WalletController) RescanAddresses([]btcutil.Address, int32) error {
+	return nil
+}
+
 // SendOutputs currently returns dummy values.
 func (w *WalletController) SendOutputs([]*wire.TxOut,
 	chainfee.SatPerKWeight, int32, string) (*wire.MsgTx, error) {
@@ -0,0 +1,202 @@
+package lntest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+// NodeSnapshot is a copy of the state of a node at a certain block, which can
+// be restored to skip the expensive setup of a test case, such as funding
+// wallets and building a channel graph.
+type NodeSnapshot struct {
+	// dir is the temporary directory the files of the node are copied to.
+	dir string
+
+	// BlockHash is the hash of the block the node was synced to when the
+	// snapshot was taken.
+	BlockHash string
+
+	// BlockHeight is the height of the block the node was synced to when
+	// the snapshot was taken.
+	BlockHeight uint32
+
+	// openChans, closedChans and policyUpdates are the channel events the
+	// node observed up to the snapshot.
+	openChans     map[wire.OutPoint]int
+	closedChans   map[wire.OutPoint]struct{}
+	policyUpdates policyUpdateMap
+}
+
+// Remove deletes the files of the snapshot. It can't be restored afterwards.
+func (s *NodeSnapshot) Remove() error {
+	return os.RemoveAll(s.dir)
+}
+
+// Snapshot stops the node and copies its state, which consists of its data
+// directory, TLS certificate and key, and the channel events observed by the
+// harness. The node MUST be synced to the chain, and is left stopped. It can
+// be started again with NetworkHarness.RestartNode, which is done by
+// NetworkHarness.SnapshotNode.
+//
+// NOTE: Snapshots are only supported with the bbolt database backend, the
+// state of a remote database isn't captured.
+func (hn *HarnessNode) Snapshot() (*NodeSnapshot, error) {
+	if hn.Cfg.DbBackend != BackendBbolt {
+		return nil, errors.New("snapshots are only supported with " +
+			"the bbolt database backend")
+	}
+
+	ctxt, cancel := context.WithTimeout(hn.runCtx, DefaultTimeout)
+	defer cancel()
+
+	info, err := hn.rpc.LN.GetInfo(ctxt, &lnrpc.GetInfoRequest{})
+	if err != nil {
+		return nil, err
+	}
+	if !info.SyncedToChain {
+		return nil, fmt.Errorf("node %v not synced to chain", hn.Name())
+	}
+
+	// The files can only be copied consistently while lnd isn't running.
+	if err := hn.stop(); err != nil {
+		return nil, err
+	}
+
+	dir, err := ioutil.TempDir("", "lndtest-snapshot")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create snapshot dir: %v", err)
+	}
+
+	err = copySnapshotFiles(dir, hn.Cfg.BaseDir, hn.Cfg.LogDir)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("unable to copy node files: %v", err)
+	}
+
+	snapshot := &NodeSnapshot{
+		dir:           dir,
+		BlockHash:     info.BlockHash,
+		BlockHeight:   info.BlockHeight,
+		openChans:     make(map[wire.OutPoint]int, len(hn.openChans)),
+		closedChans:   make(map[wire.OutPoint]struct{}),
+		policyUpdates: copyPolicyUpdates(hn.policyUpdates),
+	}
+	for op, count := range hn.openChans {
+		snapshot.openChans[op] = count
+	}
+	for op := range hn.closedChans {
+		snapshot.closedChans[op] = struct{}{}
+	}
+
+	return snapshot, nil
+}
+
+// RestoreSnapshot replaces the state of the node with the given snapshot. The
+// node MUST be stopped, and needs to be started again to use the restored
+// state, which is done by NetworkHarness.RestoreNodeSnapshot. The snapshot
+// is kept and can be restored again.
+func (hn *HarnessNode) RestoreSnapshot(snapshot *NodeSnapshot) error {
+	if hn.Cfg.DbBackend != BackendBbolt {
+		return errors.New("snapshots are only supported with the " +
+			"bbolt database backend")
+	}
+
+	// Remove the current state, except for the logs which stay
+	// available for debugging.
+	entries, err := ioutil.ReadDir(hn.Cfg.BaseDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		path := filepath.Join(hn.Cfg.BaseDir, entry.Name())
+		if path == hn.Cfg.LogDir {
+			continue
+		}
+
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("unable to remove %v: %v", path, err)
+		}
+	}
+
+	if err := copyAll(hn.Cfg.BaseDir, snapshot.dir); err != nil {
+		return fmt.Errorf("unable to restore node files: %v", err)
+	}
+
+	// The channel events the node observed after the snapshot are
+	// discarded, so that waiting for a channel that only existed after
+	// the snapshot doesn't return early.
+	hn.openChans = make(map[wire.OutPoint]int, len(snapshot.openChans))
+	for op, count := range snapshot.openChans {
+		hn.openChans[op] = count
+	}
+	hn.closedChans = make(map[wire.OutPoint]struct{})
+	for op := range snapshot.closedChans {
+		hn.closedChans[op] = struct{}{}
+	}
+	hn.policyUpdates = copyPolicyUpdates(snapshot.policyUpdates)
+	hn.openChanWatchers = make(map[wire.OutPoint][]chan struct{})
+	hn.closeChanWatchers = make(map[wire.OutPoint][]chan struct{})
+
+	return nil
+}
+
+// copySnapshotFiles copies the files of the node in srcDir to dstDir, skipping
+// the log directory.
+func copySnapshotFiles(dstDir, srcDir, logDir string) error {
+	entries, err := ioutil.ReadDir(srcDir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		srcPath := filepath.Join(srcDir, entry.Name())
+		dstPath := filepath.Join(dstDir, entry.Name())
+
+		switch {
+		case srcPath == logDir:
+			continue
+
+		case entry.IsDir():
+			err := os.Mkdir(dstPath, entry.Mode())
+			if err != nil && !os.IsExist(err) {
+				return err
+			}
+
+			if err := copyAll(dstPath, srcPath); err != nil {
+				return err
+			}
+
+		default:
+			if err := CopyFile(dstPath, srcPath); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// copyPolicyUpdates returns a copy of the given policy updates.
+func copyPolicyUpdates(updates policyUpdateMap) policyUpdateMap {
+	updatesCopy := make(policyUpdateMap, len(updates))
+	for chanPoint, nodes := range updates {
+		nodesCopy := make(
+			map[string][]*lnrpc.RoutingPolicy, len(nodes),
+		)
+		for node, policies := range nodes {
+			nodesCopy[node] = append(
+				[]*lnrpc.RoutingPolicy(nil), policies...,
+			)
+		}
+		updatesCopy[chanPoint] = nodesCopy
+	}
+
+	return updatesCopy
+}
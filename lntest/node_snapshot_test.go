@@ -0,0 +1,91 @@
+package lntest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRestoreSnapshot tests that restoring a snapshot replaces the files of a
+// node, except for its logs, and its observed channel events.
+func TestRestoreSnapshot(t *testing.T) {
+	t.Parallel()
+
+	baseDir := t.TempDir()
+	cfg := &BaseNodeConfig{
+		BaseDir: baseDir,
+		DataDir: filepath.Join(baseDir, "data"),
+		LogDir:  filepath.Join(baseDir, "logs"),
+	}
+	hn := &HarnessNode{
+		Cfg:           cfg,
+		openChans:     make(map[wire.OutPoint]int),
+		closedChans:   make(map[wire.OutPoint]struct{}),
+		policyUpdates: policyUpdateMap{},
+	}
+
+	writeFile := func(path, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(
+			t, ioutil.WriteFile(path, []byte(content), 0600),
+		)
+	}
+	readFile := func(path string) string {
+		content, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+
+		return string(content)
+	}
+
+	dbPath := filepath.Join(cfg.DataDir, "graph", "channel.db")
+	logPath := filepath.Join(cfg.LogDir, "lnd.log")
+	writeFile(dbPath, "before")
+	writeFile(filepath.Join(baseDir, "tls.cert"), "cert")
+	writeFile(logPath, "log before")
+
+	chanBefore := wire.OutPoint{Index: 1}
+	hn.openChans[chanBefore] = 2
+
+	// Take the snapshot of the files directly, as the node isn't running.
+	snapshot := &NodeSnapshot{
+		dir:         t.TempDir(),
+		openChans:   map[wire.OutPoint]int{chanBefore: 2},
+		closedChans: make(map[wire.OutPoint]struct{}),
+	}
+	require.NoError(
+		t, copySnapshotFiles(snapshot.dir, baseDir, cfg.LogDir),
+	)
+
+	// The logs aren't part of the snapshot.
+	_, err := os.Stat(filepath.Join(snapshot.dir, "logs"))
+	require.True(t, os.IsNotExist(err))
+
+	// Change the state of the node after the snapshot.
+	chanAfter := wire.OutPoint{Index: 2}
+	hn.openChans[chanAfter] = 2
+	writeFile(dbPath, "after")
+	writeFile(filepath.Join(cfg.DataDir, "new.db"), "new")
+	writeFile(logPath, "log after")
+
+	require.NoError(t, hn.RestoreSnapshot(snapshot))
+
+	// The files are back to the snapshot, files created afterwards are
+	// removed, and the logs are kept.
+	require.Equal(t, "before", readFile(dbPath))
+	require.Equal(t, "cert", readFile(filepath.Join(baseDir, "tls.cert")))
+	require.Equal(t, "log after", readFile(logPath))
+	_, err = os.Stat(filepath.Join(cfg.DataDir, "new.db"))
+	require.True(t, os.IsNotExist(err))
+
+	// Only the channels observed up to the snapshot are known.
+	require.Equal(t, map[wire.OutPoint]int{chanBefore: 2}, hn.openChans)
+
+	// The snapshot can be restored again.
+	writeFile(dbPath, "after")
+	require.NoError(t, hn.RestoreSnapshot(snapshot))
+	require.Equal(t, "before", readFile(dbPath))
+}
@@ -46,7 +46,9 @@ var (
 		"log output from node n to file output-n.log")
 
 	// logSubDir is the default directory where the logs are written to if
-	// logOutput is true.
+	// logOutput is true. The artifacts of each test case are collected in
+	// a sub directory named after the test case, and are listed in an index
+	// file in the log directory.
 	logSubDir = flag.String("logdir", ".", "default dir to write logs to")
 
 	// goroutineDump is a flag that can be set to dump the active
@@ -54,6 +56,12 @@ var (
 	goroutineDump = flag.Bool("goroutinedump", false,
 		"write goroutine dump from node n to file pprof-n.log")
 
+	// coverage is a flag that can be set to collect the coverage data of
+	// test nodes that were built with -cover. It requires logOutput to be
+	// set as well.
+	coverage = flag.Bool("coverage", false, "collect coverage data "+
+		"from nodes built with -cover in the log dir")
+
 	// btcdExecutable is the full path to the btcd binary.
 	btcdExecutable = flag.String(
 		"btcdexec", "", "full path to btcd binary",
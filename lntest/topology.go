@@ -0,0 +1,220 @@
+package lntest
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/stretchr/testify/require"
+)
+
+// topologyFeeBuffer is the amount a node created by Build is funded with on
+// top of its channels, to pay for the fees of the funding transactions.
+const topologyFeeBuffer = btcutil.Amount(100000)
+
+// Topology declares a network of nodes and the channels between them. Each
+// entry maps the name of a node to the channels it funds, keyed by the name
+// of the remote node. For example, a route from Alice to Carol through Bob is
+// declared as:
+//
+//	Topology{
+//		"Alice": {"Bob": 100000},
+//		"Bob":   {"Carol": 100000},
+//	}
+type Topology map[string]map[string]btcutil.Amount
+
+// topologyChannel is a channel declared by a topology.
+type topologyChannel struct {
+	funder string
+	peer   string
+	amt    btcutil.Amount
+}
+
+// nodes returns the names of all nodes of the topology in lexicographical
+// order.
+func (t Topology) nodes() []string {
+	names := make(map[string]struct{})
+	for funder, peers := range t {
+		names[funder] = struct{}{}
+		for peer := range peers {
+			names[peer] = struct{}{}
+		}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	return sorted
+}
+
+// channels returns the channels of the topology ordered by funder and peer,
+// so that they're always opened in the same order.
+func (t Topology) channels() ([]topologyChannel, error) {
+	var chans []topologyChannel
+	for funder, peers := range t {
+		for peer, amt := range peers {
+			if funder == peer {
+				return nil, fmt.Errorf("%v can't open a "+
+					"channel to itself", funder)
+			}
+			if amt <= 0 {
+				return nil, fmt.Errorf("channel from %v to %v "+
+					"must have a positive amount", funder,
+					peer)
+			}
+
+			chans = append(chans, topologyChannel{
+				funder: funder,
+				peer:   peer,
+				amt:    amt,
+			})
+		}
+	}
+
+	sort.Slice(chans, func(i, j int) bool {
+		if chans[i].funder != chans[j].funder {
+			return chans[i].funder < chans[j].funder
+		}
+
+		return chans[i].peer < chans[j].peer
+	})
+
+	return chans, nil
+}
+
+// Network is a network built from a topology, with handles to its nodes and
+// channels.
+type Network struct {
+	// Nodes are the nodes of the network by name.
+	Nodes map[string]*HarnessNode
+
+	// chanPoints are the channels of the network, keyed by the names of
+	// the funder and the peer.
+	chanPoints map[[2]string]*lnrpc.ChannelPoint
+}
+
+// Node returns the node with the given name. It fails the test if the node
+// isn't part of the network.
+func (nw *Network) Node(t *testing.T, name string) *HarnessNode {
+	node, ok := nw.Nodes[name]
+	require.Truef(t, ok, "node %v not part of the network", name)
+
+	return node
+}
+
+// ChanPoint returns the channel funded by the node named funder to the node
+// named peer. It fails the test if the channel isn't part of the network.
+func (nw *Network) ChanPoint(t *testing.T,
+	funder, peer string) *lnrpc.ChannelPoint {
+
+	chanPoint, ok := nw.chanPoints[[2]string{funder, peer}]
+	require.Truef(t, ok, "no channel from %v to %v", funder, peer)
+
+	return chanPoint
+}
+
+// Build creates the network declared by the topology. It creates the nodes
+// that aren't running yet, funds those that open channels, connects the peers,
+// opens the channels one after another, and waits until every node of the
+// network has seen all channels in its graph. Nodes that are already running,
+// such as Alice and Bob, are reused by name and expected to be funded. The
+// channels of the topology must connect all of its nodes, otherwise the
+// announcements can't reach every node.
+func (n *NetworkHarness) Build(t *testing.T, topo Topology) *Network {
+	t.Helper()
+
+	chans, err := topo.channels()
+	require.NoError(t, err, "invalid topology")
+
+	nw := &Network{
+		Nodes:      make(map[string]*HarnessNode),
+		chanPoints: make(map[[2]string]*lnrpc.ChannelPoint),
+	}
+
+	// Reuse the running nodes, and create the other ones.
+	running := make(map[string]*HarnessNode, len(n.activeNodes))
+	for _, node := range n.activeNodes {
+		running[node.Cfg.Name] = node
+	}
+	created := make(map[string]bool)
+	for _, name := range topo.nodes() {
+		if node, ok := running[name]; ok {
+			nw.Nodes[name] = node
+			continue
+		}
+
+		nw.Nodes[name] = n.NewNode(t, name, nil)
+		created[name] = true
+	}
+
+	// Fund the new nodes with the amount of their channels.
+	for _, name := range topo.nodes() {
+		if !created[name] || len(topo[name]) == 0 {
+			continue
+		}
+
+		var total btcutil.Amount
+		for _, amt := range topo[name] {
+			total += amt
+		}
+		n.SendCoins(t, total+topologyFeeBuffer, nw.Nodes[name])
+	}
+
+	// Open the channels one at a time, confirming each of them before the
+	// next one, so that the change of a funding transaction can fund the
+	// next channel of the same node.
+	for _, c := range chans {
+		funder, peer := nw.Nodes[c.funder], nw.Nodes[c.peer]
+		n.EnsureConnected(t, funder, peer)
+
+		stream, err := n.OpenChannel(funder, peer, OpenChannelParams{
+			Amt: c.amt,
+		})
+		require.NoErrorf(t, err, "unable to open channel from %v to "+
+			"%v", c.funder, c.peer)
+
+		// Six confirmations are needed to announce the channel, the
+		// first of them needs to include the funding transaction.
+		err = wait.NoError(func() error {
+			mempool, err := n.Miner.Client.GetRawMempool()
+			if err != nil {
+				return err
+			}
+			if len(mempool) == 0 {
+				return fmt.Errorf("funding tx not in mempool")
+			}
+
+			return nil
+		}, DefaultTimeout)
+		require.NoErrorf(t, err, "funding tx of channel from %v to "+
+			"%v not published", c.funder, c.peer)
+
+		_, err = n.Miner.Client.Generate(6)
+		require.NoError(t, err, "unable to mine blocks")
+
+		chanPoint, err := n.WaitForChannelOpen(stream)
+		require.NoErrorf(t, err, "channel from %v to %v not opened",
+			c.funder, c.peer)
+
+		nw.chanPoints[[2]string{c.funder, c.peer}] = chanPoint
+	}
+
+	// Wait until the announcements of all channels have propagated to
+	// every node of the network.
+	for _, name := range topo.nodes() {
+		for _, c := range chans {
+			chanPoint := nw.chanPoints[[2]string{c.funder, c.peer}]
+			err := nw.Nodes[name].WaitForNetworkChannelOpen(chanPoint)
+			require.NoErrorf(t, err, "%v didn't see channel from "+
+				"%v to %v", name, c.funder, c.peer)
+		}
+	}
+
+	return nw
+}
@@ -0,0 +1,37 @@
+package lntest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestTopologyChannels tests that the nodes and channels of a topology are
+// listed in a deterministic order, and that invalid channels are rejected.
+func TestTopologyChannels(t *testing.T) {
+	t.Parallel()
+
+	topo := Topology{
+		"Bob":   {"Carol": 200000, "Alice": 50000},
+		"Alice": {"Bob": 100000},
+		"Dave":  {},
+	}
+
+	require.Equal(
+		t, []string{"Alice", "Bob", "Carol", "Dave"}, topo.nodes(),
+	)
+
+	chans, err := topo.channels()
+	require.NoError(t, err)
+	require.Equal(t, []topologyChannel{
+		{funder: "Alice", peer: "Bob", amt: 100000},
+		{funder: "Bob", peer: "Alice", amt: 50000},
+		{funder: "Bob", peer: "Carol", amt: 200000},
+	}, chans)
+
+	_, err = Topology{"Alice": {"Alice": 100000}}.channels()
+	require.Error(t, err)
+
+	_, err = Topology{"Alice": {"Bob": 0}}.channels()
+	require.Error(t, err)
+}
@@ -0,0 +1,159 @@
+package lntest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnrpc/watchtowerrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/wtclientrpc"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/stretchr/testify/require"
+)
+
+// NewWatchtower creates a new node that runs an altruist watchtower listening
+// on a free local port, so that several towers can be used within the same
+// test. The tower can be taken down and brought back up with SuspendNode and
+// RestartNode to simulate tower downtime, it keeps its listening address
+// across restarts.
+func (n *NetworkHarness) NewWatchtower(t *testing.T, name string,
+	extraArgs []string) *HarnessNode {
+
+	towerArgs := []string{
+		"--watchtower.active",
+		fmt.Sprintf("--watchtower.listen="+ListenerFormat,
+			NextAvailablePort()),
+	}
+	towerArgs = append(towerArgs, extraArgs...)
+
+	return n.NewNode(t, name, towerArgs)
+}
+
+// NewWatchtowerClient creates a new node that runs a watchtower client. If
+// maxUpdates is non-zero, the sessions negotiated by the client allow only
+// that many updates, which allows tests to exhaust sessions quickly.
+func (n *NetworkHarness) NewWatchtowerClient(t *testing.T, name string,
+	extraArgs []string, maxUpdates uint16) *HarnessNode {
+
+	clientArgs := []string{"--wtclient.active"}
+	if maxUpdates != 0 {
+		clientArgs = append(clientArgs, fmt.Sprintf(
+			"--wtclient.max-updates=%d", maxUpdates,
+		))
+	}
+	clientArgs = append(clientArgs, extraArgs...)
+
+	return n.NewNode(t, name, clientArgs)
+}
+
+// AddTower registers the watchtower running on the tower node with the
+// watchtower client of the client node.
+func (n *NetworkHarness) AddTower(t *testing.T, client,
+	tower *HarnessNode) {
+
+	ctxt, cancel := context.WithTimeout(n.runCtx, DefaultTimeout)
+	defer cancel()
+
+	towerInfo, err := tower.Watchtower.GetInfo(
+		ctxt, &watchtowerrpc.GetInfoRequest{},
+	)
+	require.NoErrorf(t, err, "unable to get tower info from %s",
+		tower.Name())
+	require.NotEmptyf(t, towerInfo.Listeners, "tower %s has no "+
+		"listeners", tower.Name())
+
+	_, err = client.WatchtowerClient.AddTower(
+		ctxt, &wtclientrpc.AddTowerRequest{
+			Pubkey:  towerInfo.Pubkey,
+			Address: towerInfo.Listeners[0],
+		},
+	)
+	require.NoErrorf(t, err, "unable to add tower %s to %s",
+		tower.Name(), client.Name())
+}
+
+// RemoveTower removes the watchtower running on the tower node from the
+// watchtower client of the client node.
+func (n *NetworkHarness) RemoveTower(t *testing.T, client,
+	tower *HarnessNode) {
+
+	ctxt, cancel := context.WithTimeout(n.runCtx, DefaultTimeout)
+	defer cancel()
+
+	_, err := client.WatchtowerClient.RemoveTower(
+		ctxt, &wtclientrpc.RemoveTowerRequest{
+			Pubkey: tower.PubKey[:],
+		},
+	)
+	require.NoErrorf(t, err, "unable to remove tower %s from %s",
+		tower.Name(), client.Name())
+}
+
+// WaitForTowerBackups waits until the watchtowers of the node's watchtower
+// client have acknowledged at least the given number of backups and no
+// backups are pending anymore.
+func (hn *HarnessNode) WaitForTowerBackups(numBackups uint32) error {
+	check := func(stats *wtclientrpc.StatsResponse) error {
+		if stats.NumBackups < numBackups {
+			return fmt.Errorf("expected at least %d backups, "+
+				"got %d", numBackups, stats.NumBackups)
+		}
+
+		if stats.NumPendingBackups != 0 {
+			return fmt.Errorf("expected no pending backups, got %d",
+				stats.NumPendingBackups)
+		}
+
+		return nil
+	}
+
+	return hn.waitForTowerStats(check)
+}
+
+// TowerStats returns the current stats of the node's watchtower client.
+func (hn *HarnessNode) TowerStats() (*wtclientrpc.StatsResponse, error) {
+	ctxt, cancel := context.WithTimeout(hn.runCtx, DefaultTimeout)
+	defer cancel()
+
+	return hn.WatchtowerClient.Stats(ctxt, &wtclientrpc.StatsRequest{})
+}
+
+// WaitForTowerSessions waits until the node's watchtower client has acquired
+// at least the given number of sessions, of which at least numExhausted are
+// exhausted.
+func (hn *HarnessNode) WaitForTowerSessions(numAcquired,
+	numExhausted uint32) error {
+
+	check := func(stats *wtclientrpc.StatsResponse) error {
+		if stats.NumSessionsAcquired < numAcquired {
+			return fmt.Errorf("expected at least %d acquired "+
+				"sessions, got %d", numAcquired,
+				stats.NumSessionsAcquired)
+		}
+
+		if stats.NumSessionsExhausted < numExhausted {
+			return fmt.Errorf("expected at least %d exhausted "+
+				"sessions, got %d", numExhausted,
+				stats.NumSessionsExhausted)
+		}
+
+		return nil
+	}
+
+	return hn.waitForTowerStats(check)
+}
+
+// waitForTowerStats polls the stats of the node's watchtower client until
+// they pass the given check or the default timeout expires.
+func (hn *HarnessNode) waitForTowerStats(
+	check func(*wtclientrpc.StatsResponse) error) error {
+
+	return wait.NoError(func() error {
+		stats, err := hn.TowerStats()
+		if err != nil {
+			return err
+		}
+
+		return check(stats)
+	}, DefaultTimeout)
+}
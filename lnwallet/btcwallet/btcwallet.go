@@ -6,10 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"regexp"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcjson"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -41,6 +45,18 @@ const (
 	// dry run is attempted.
 	dryRunImportAccountNumAddrs = 5
 
+	// isSecretScript denotes whether imported scripts are encrypted with
+	// the private key encryption key. We want to be able to import script
+	// addresses into a watch-only wallet, which is only possible if we
+	// don't. By specifying the script as being "not secret", we can also
+	// decrypt the script in a watch-only wallet.
+	isSecretScript = false
+
+	// witnessVersionTaproot is the witness version of imported scripts.
+	// Currently, only v1 (Taproot) scripts are supported. We don't even
+	// know what a v2 witness version would look like at this point.
+	witnessVersionTaproot byte = 1
+
 	// UnconfirmedHeight is the special case end height that is used to
 	// obtain unconfirmed transactions from ListTransactionDetails.
 	UnconfirmedHeight int32 = -1
@@ -890,21 +906,256 @@ func (b *BtcWallet) ImportPublicKey(pubKey *btcec.PublicKey,
 func (b *BtcWallet) ImportTaprootScript(scope waddrmgr.KeyScope,
 	tapscript *waddrmgr.Tapscript) (waddrmgr.ManagedAddress, error) {
 
-	// We want to be able to import script addresses into a watch-only
-	// wallet, which is only possible if we don't encrypt the script with
-	// the private key encryption key. By specifying the script as being
-	// "not secret", we can also decrypt the script in a watch-only wallet.
-	const isSecretScript = false
-
-	// Currently, only v1 (Taproot) scripts are supported. We don't even
-	// know what a v2 witness version would look like at this point.
-	const witnessVersionTaproot byte = 1
-
 	return b.wallet.ImportTaprootScript(
 		scope, tapscript, nil, witnessVersionTaproot, isSecretScript,
 	)
 }
 
+// ImportWatchOnly imports a batch of watch-only accounts, public keys and
+// taproot scripts within a single database transaction, so either all of them
+// or none of them are imported. The properties of the imported accounts are
+// returned in the order of the batch, together with all addresses that were
+// added to the wallet.
+//
+// This is a part of the WalletController interface.
+func (b *BtcWallet) ImportWatchOnly(batch *lnwallet.WatchOnlyImport) (
+	[]*waddrmgr.AccountProperties, []btcutil.Address, error) {
+
+	// The key scope and address schema of an account are derived from its
+	// public key by btcwallet, so we resolve them through a dry run. This
+	// also validates the account public keys before anything is written.
+	dryRunProps := make([]*waddrmgr.AccountProperties, len(batch.Accounts))
+	for i, account := range batch.Accounts {
+		addrType := account.AddrType
+		props, _, _, err := b.wallet.ImportAccountDryRun(
+			account.Name, account.AccountPubKey,
+			account.MasterKeyFingerprint, &addrType, 0,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid account %v: %w",
+				account.Name, err)
+		}
+		dryRunProps[i] = props
+	}
+
+	// The starting block of the imported scripts is the genesis block, as
+	// we don't know when they were first used.
+	genesis := &waddrmgr.BlockStamp{
+		Hash:      *b.netParams.GenesisHash,
+		Timestamp: b.netParams.GenesisBlock.Header.Timestamp,
+	}
+
+	var (
+		accountProps []*waddrmgr.AccountProperties
+		addrs        []btcutil.Address
+
+		// invalidate holds the accounts created within the transaction,
+		// whose cached state must be dropped if it is rolled back.
+		invalidate []func()
+	)
+	err := walletdb.Update(b.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+
+		for i, account := range batch.Accounts {
+			props, newAddrs, err := b.importWatchOnlyAccount(
+				ns, account, dryRunProps[i], &invalidate,
+			)
+			if err != nil {
+				return fmt.Errorf("unable to import account "+
+					"%v: %w", account.Name, err)
+			}
+
+			accountProps = append(accountProps, props)
+			addrs = append(addrs, newAddrs...)
+		}
+
+		for _, pubKey := range batch.PubKeys {
+			var scope waddrmgr.KeyScope
+			switch pubKey.AddrType {
+			case waddrmgr.NestedWitnessPubKey:
+				scope = waddrmgr.KeyScopeBIP0049Plus
+
+			case waddrmgr.WitnessPubKey:
+				scope = waddrmgr.KeyScopeBIP0084
+
+			case waddrmgr.TaprootPubKey:
+				scope = waddrmgr.KeyScopeBIP0086
+
+			default:
+				return fmt.Errorf("address type %v is not "+
+					"supported", pubKey.AddrType)
+			}
+
+			manager, err := b.wallet.Manager.FetchScopedKeyManager(
+				scope,
+			)
+			if err != nil {
+				return err
+			}
+
+			addr, err := manager.ImportPublicKey(
+				ns, pubKey.PubKey, nil,
+			)
+			if err != nil {
+				return err
+			}
+			addrs = append(addrs, addr.Address())
+		}
+
+		if len(batch.Tapscripts) == 0 {
+			return nil
+		}
+
+		manager, err := b.wallet.Manager.FetchScopedKeyManager(
+			waddrmgr.KeyScopeBIP0086,
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, tapscript := range batch.Tapscripts {
+			addr, err := manager.ImportTaprootScript(
+				ns, tapscript, genesis, witnessVersionTaproot,
+				isSecretScript,
+			)
+			if err != nil {
+				return err
+			}
+			addrs = append(addrs, addr.Address())
+		}
+
+		return nil
+	})
+	if err != nil {
+		for _, invalidateAccount := range invalidate {
+			invalidateAccount()
+		}
+
+		return nil, nil, err
+	}
+
+	for _, addr := range addrs {
+		log.Infof("Imported address %v", addr)
+	}
+
+	// Make sure the chain backend notifies us about any future events
+	// involving the imported addresses.
+	if err := b.chain.NotifyReceived(addrs); err != nil {
+		return nil, nil, fmt.Errorf("unable to subscribe for address "+
+			"notifications: %w", err)
+	}
+
+	return accountProps, addrs, nil
+}
+
+// importWatchOnlyAccount creates a watch-only account within the given
+// address manager namespace and derives the requested number of addresses on
+// both of its branches. The returned addresses only become known to the wallet
+// once the surrounding transaction is committed.
+func (b *BtcWallet) importWatchOnlyAccount(ns walletdb.ReadWriteBucket,
+	account *lnwallet.WatchOnlyAccount,
+	dryRunProps *waddrmgr.AccountProperties,
+	invalidate *[]func()) (*waddrmgr.AccountProperties, []btcutil.Address,
+	error) {
+
+	manager, err := b.wallet.Manager.FetchScopedKeyManager(
+		dryRunProps.KeyScope,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accountNum, err := manager.NewAccountWatchingOnly(
+		ns, account.Name, account.AccountPubKey,
+		account.MasterKeyFingerprint, dryRunProps.AddrSchema,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	*invalidate = append(*invalidate, func() {
+		manager.InvalidateAccountCache(accountNum)
+	})
+
+	var managedAddrs []waddrmgr.ManagedAddress
+	if account.NumExternalAddrs > 0 {
+		extAddrs, err := manager.NextExternalAddresses(
+			ns, accountNum, account.NumExternalAddrs,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		managedAddrs = append(managedAddrs, extAddrs...)
+	}
+	if account.NumInternalAddrs > 0 {
+		intAddrs, err := manager.NextInternalAddresses(
+			ns, accountNum, account.NumInternalAddrs,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		managedAddrs = append(managedAddrs, intAddrs...)
+	}
+
+	props, err := manager.AccountProperties(ns, accountNum)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	addrs := make([]btcutil.Address, len(managedAddrs))
+	for i, managedAddr := range managedAddrs {
+		addrs[i] = managedAddr.Address()
+	}
+
+	return props, addrs, nil
+}
+
+// RescanAddresses starts a rescan of the chain for transactions involving any
+// of the given addresses, beginning at the block with the given height. The
+// addresses must already be known to the wallet. The call returns once the
+// rescan has been queued, the rescan itself happens in the background and its
+// outcome is logged.
+//
+// This is a part of the WalletController interface.
+func (b *BtcWallet) RescanAddresses(addrs []btcutil.Address,
+	startHeight int32) error {
+
+	startHash, err := b.chain.GetBlockHash(int64(startHeight))
+	if err != nil {
+		return fmt.Errorf("unable to fetch block hash for height %d: "+
+			"%v", startHeight, err)
+	}
+
+	startHeader, err := b.chain.GetBlockHeader(startHash)
+	if err != nil {
+		return fmt.Errorf("unable to fetch block header %v: %v",
+			startHash, err)
+	}
+
+	job := &base.RescanJob{
+		Addrs:     addrs,
+		OutPoints: make(map[wire.OutPoint]btcutil.Address),
+		BlockStamp: waddrmgr.BlockStamp{
+			Height:    startHeight,
+			Hash:      *startHash,
+			Timestamp: startHeader.Timestamp,
+		},
+	}
+	errChan := b.wallet.SubmitRescan(job)
+
+	go func() {
+		if err := <-errChan; err != nil {
+			log.Errorf("Rescan of %d addresses from height %d "+
+				"failed: %v", len(addrs), startHeight, err)
+			return
+		}
+
+		log.Infof("Rescan of %d addresses from height %d finished",
+			len(addrs), startHeight)
+	}()
+
+	return nil
+}
+
 // SendOutputs funds, signs, and broadcasts a Bitcoin transaction paying out to
 // the specified outputs. In the case the wallet has insufficient funds, or the
 // outputs are non-standard, a non-nil error will be returned.
@@ -1169,12 +1420,94 @@ func (b *BtcWallet) PublishTransaction(tx *wire.MsgTx, label string) error {
 			return lnwallet.ErrDoubleSpend
 
 		default:
+			// If the backend rejected the transaction because of
+			// its mempool policy, wrap the error so callers can
+			// react to it.
+			policyErr := mempoolPolicyErr(err)
+			if policyErr != nil {
+				return fmt.Errorf("%w: %v", policyErr, err)
+			}
+
 			return err
 		}
 	}
 	return nil
 }
 
+// unmatchedBackendErrPrefix is the prefix btcwallet adds to the errors of the
+// backend that it doesn't recognize. Since the RPC error is only kept in its
+// formatted "code: message" form, we have to parse it from there.
+const unmatchedBackendErrPrefix = "unmatched backend error: "
+
+// bitcoindRejectReasons maps the reject reasons returned by bitcoind for
+// transactions that violate its mempool policy to our typed errors. The
+// reason is the first part of the message of the RPC error, which may be
+// followed by a comma and debug information.
+var bitcoindRejectReasons = map[string]error{
+	"min relay fee not met":   lnwallet.ErrMempoolMinFee,
+	"mempool min fee not met": lnwallet.ErrMempoolMinFee,
+	"mempool full":            lnwallet.ErrMempoolMinFee,
+	"too-long-mempool-chain":  lnwallet.ErrMempoolChainTooLong,
+}
+
+// btcdMinFeeReject matches the messages of the RPC errors returned by btcd for
+// transactions that don't pay enough fees to be accepted into its mempool.
+var btcdMinFeeReject = regexp.MustCompile(
+	`^TX rejected: transaction [0-9a-f]{64} has (\d+ fees which is ` +
+		`under the required amount of \d+|insufficient priority ` +
+		`\(.*\)|been rejected by the rate limiter due to low fees)$`,
+)
+
+// parseRPCError parses the RPC error returned by the backend from the error
+// returned by btcwallet when publishing a transaction.
+func parseRPCError(err error) (*btcjson.RPCError, bool) {
+	var rpcErr *btcjson.RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr, true
+	}
+
+	errStr := strings.TrimPrefix(err.Error(), unmatchedBackendErrPrefix)
+	parts := strings.SplitN(errStr, ": ", 2)
+	if len(parts) != 2 {
+		return nil, false
+	}
+
+	code, convErr := strconv.Atoi(parts[0])
+	if convErr != nil {
+		return nil, false
+	}
+
+	return &btcjson.RPCError{
+		Code:    btcjson.RPCErrorCode(code),
+		Message: parts[1],
+	}, true
+}
+
+// mempoolPolicyErr returns the typed error for the given error returned when
+// publishing a transaction if it indicates that the transaction was rejected
+// by the mempool policy of the backend, and nil otherwise. Both bitcoind and
+// btcd reject such transactions with the error code -26.
+//
+// NOTE: The rejections of neutrino are reported by btcwallet as replacement
+// errors and can't be told apart from them.
+func mempoolPolicyErr(err error) error {
+	rpcErr, ok := parseRPCError(err)
+	if !ok || rpcErr.Code != btcjson.ErrRPCTxRejected {
+		return nil
+	}
+
+	reason := strings.SplitN(rpcErr.Message, ",", 2)[0]
+	if policyErr, ok := bitcoindRejectReasons[reason]; ok {
+		return policyErr
+	}
+
+	if btcdMinFeeReject.MatchString(rpcErr.Message) {
+		return lnwallet.ErrMempoolMinFee
+	}
+
+	return nil
+}
+
 // LabelTransaction adds a label to a transaction. If the tx already
 // has a label, this call will fail unless the overwrite parameter
 // is set. Labels must not be empty, and they are limited to 500 chars.
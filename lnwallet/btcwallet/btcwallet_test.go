@@ -1,8 +1,10 @@
 package btcwallet
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcjson"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/wallet"
 	"github.com/lightningnetwork/lnd/lnwallet"
@@ -132,3 +134,91 @@ func TestPreviousOutpoints(t *testing.T) {
 		})
 	}
 }
+
+// TestMempoolPolicyErr tests that the mempool policy rejections of the
+// different backends are mapped to their typed errors.
+func TestMempoolPolicyErr(t *testing.T) {
+	t.Parallel()
+
+	const txid = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495" +
+		"991b7852b855"
+
+	testCases := []struct {
+		name   string
+		err    error
+		expErr error
+	}{{
+		name: "bitcoind min relay fee",
+		err: errors.New("unmatched backend error: -26: min relay " +
+			"fee not met, 100 < 141"),
+		expErr: lnwallet.ErrMempoolMinFee,
+	}, {
+		name: "bitcoind mempool min fee",
+		err: errors.New("unmatched backend error: -26: mempool min " +
+			"fee not met, 253 < 1000"),
+		expErr: lnwallet.ErrMempoolMinFee,
+	}, {
+		name: "bitcoind mempool full",
+		err: errors.New("unmatched backend error: -26: mempool " +
+			"full"),
+		expErr: lnwallet.ErrMempoolMinFee,
+	}, {
+		name: "bitcoind chain too long",
+		err: errors.New("unmatched backend error: -26: " +
+			"too-long-mempool-chain, too many unconfirmed " +
+			"ancestors [limit: 25]"),
+		expErr: lnwallet.ErrMempoolChainTooLong,
+	}, {
+		name: "typed rpc error",
+		err: &btcjson.RPCError{
+			Code:    btcjson.ErrRPCTxRejected,
+			Message: "min relay fee not met, 100 < 141",
+		},
+		expErr: lnwallet.ErrMempoolMinFee,
+	}, {
+		name: "btcd min fee",
+		err: errors.New("unmatched backend error: -26: TX rejected: " +
+			"transaction " + txid + " has 100 fees which is " +
+			"under the required amount of 141"),
+		expErr: lnwallet.ErrMempoolMinFee,
+	}, {
+		name: "btcd insufficient priority",
+		err: errors.New("unmatched backend error: -26: TX rejected: " +
+			"transaction " + txid + " has insufficient priority " +
+			"(0 <= 5.76e+07)"),
+		expErr: lnwallet.ErrMempoolMinFee,
+	}, {
+		name: "wrong error code",
+		err: errors.New("unmatched backend error: -25: min relay " +
+			"fee not met, 100 < 141"),
+	}, {
+		name: "reason only in debug message",
+		err: errors.New("unmatched backend error: -26: " +
+			"non-mandatory-script-verify-flag, min relay fee not " +
+			"met"),
+	}, {
+		name: "missing inputs",
+		err: errors.New("unmatched backend error: -25: " +
+			"bad-txns-inputs-missingorspent"),
+	}, {
+		name: "no rpc error",
+		err:  errors.New("min relay fee not met"),
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			policyErr := mempoolPolicyErr(tc.err)
+			require.Equal(t, tc.expErr, policyErr)
+		})
+	}
+
+	// The typed errors must still be recognized as mempool rejections.
+	require.ErrorIs(t, lnwallet.ErrMempoolMinFee,
+		lnwallet.ErrMempoolRejected)
+	require.ErrorIs(t, lnwallet.ErrMempoolChainTooLong,
+		lnwallet.ErrMempoolRejected)
+}
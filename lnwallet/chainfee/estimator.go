@@ -14,6 +14,7 @@ import (
 
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/lightningnetwork/lnd/subscribe"
 )
 
 const (
@@ -69,6 +70,25 @@ type Estimator interface {
 	RelayFeePerKW() SatPerKWeight
 }
 
+// RelayFeeUpdate is sent to the subscribers of a RelayFeeNotifier when the
+// minimum fee rate required for transactions to be relayed changes.
+type RelayFeeUpdate struct {
+	// OldFeePerKW is the previous minimum relay fee rate.
+	OldFeePerKW SatPerKWeight
+
+	// NewFeePerKW is the new minimum relay fee rate.
+	NewFeePerKW SatPerKWeight
+}
+
+// RelayFeeNotifier is implemented by the estimators that continuously track
+// the minimum relay fee rate of their backend, which is the maximum of its
+// minrelaytxfee and its mempool minimum fee.
+type RelayFeeNotifier interface {
+	// SubscribeRelayFee returns a client that receives a *RelayFeeUpdate
+	// whenever the minimum relay fee rate changes.
+	SubscribeRelayFee() (*subscribe.Client, error)
+}
+
 // StaticEstimator will return a static value for all fee calculation requests.
 // It is designed to be replaced by a proper fee calculation implementation.
 // The fees are not accessible directly, because changing them would not be
@@ -141,6 +161,9 @@ type BtcdEstimator struct {
 	// transaction to propagate through the network.
 	minFeeManager *minFeeManager
 
+	// feeCurve caches the estimates of the backend by conf target.
+	feeCurve *feeCurve
+
 	btcdConn *rpcclient.Client
 }
 
@@ -161,6 +184,7 @@ func NewBtcdEstimator(rpcConfig rpcclient.ConnConfig,
 
 	return &BtcdEstimator{
 		fallbackFeePerKW: fallBackFeeRate,
+		feeCurve:         newFeeCurve(defaultFeeCurveTTL),
 		btcdConn:         chainConn,
 	}, nil
 }
@@ -185,7 +209,7 @@ func (b *BtcdEstimator) Start() error {
 	}
 	b.minFeeManager = minRelayFeeManager
 
-	return nil
+	return b.minFeeManager.start(defaultMinFeePollInterval)
 }
 
 // fetchMinRelayFee fetches and returns the minimum relay fee in sat/kb from
@@ -211,6 +235,12 @@ func (b *BtcdEstimator) fetchMinRelayFee() (SatPerKWeight, error) {
 //
 // NOTE: This method is part of the Estimator interface.
 func (b *BtcdEstimator) Stop() error {
+	if b.minFeeManager != nil {
+		if err := b.minFeeManager.stop(); err != nil {
+			return err
+		}
+	}
+
 	b.btcdConn.Shutdown()
 
 	return nil
@@ -245,26 +275,24 @@ func (b *BtcdEstimator) RelayFeePerKW() SatPerKWeight {
 	return b.minFeeManager.fetchMinFee()
 }
 
+// SubscribeRelayFee returns a client that receives a *RelayFeeUpdate whenever
+// the minimum relay fee rate of the backend changes.
+//
+// NOTE: This method is part of the RelayFeeNotifier interface.
+func (b *BtcdEstimator) SubscribeRelayFee() (*subscribe.Client, error) {
+	return b.minFeeManager.subscribe()
+}
+
 // fetchEstimate returns a fee estimate for a transaction to be confirmed in
 // confTarget blocks. The estimate is returned in sat/kw.
 func (b *BtcdEstimator) fetchEstimate(confTarget uint32) (SatPerKWeight, error) {
-	// First, we'll fetch the estimate for our confirmation target.
-	btcPerKB, err := b.btcdConn.EstimateFee(int64(confTarget))
+	// First, we'll fetch the estimate for our confirmation target, which
+	// may be cached from a recent query.
+	satPerKw, err := b.feeCurve.fetch(confTarget, b.queryEstimate)
 	if err != nil {
 		return 0, err
 	}
 
-	// Next, we'll convert the returned value to satoshis, as it's
-	// currently returned in BTC.
-	satPerKB, err := btcutil.NewAmount(btcPerKB)
-	if err != nil {
-		return 0, err
-	}
-
-	// Since we use fee rates in sat/kw internally, we'll convert the
-	// estimated fee rate from its sat/kb representation to sat/kw.
-	satPerKw := SatPerKVByte(satPerKB).FeePerKWeight()
-
 	// Finally, we'll enforce our fee floor.
 	if satPerKw < b.minFeeManager.fetchMinFee() {
 		log.Debugf("Estimated fee rate of %v sat/kw is too low, "+
@@ -279,9 +307,32 @@ func (b *BtcdEstimator) fetchEstimate(confTarget uint32) (SatPerKWeight, error)
 	return satPerKw, nil
 }
 
+// queryEstimate queries the backend for the fee estimate of the conf target,
+// in sat/kw.
+func (b *BtcdEstimator) queryEstimate(confTarget uint32) (SatPerKWeight,
+	error) {
+
+	btcPerKB, err := b.btcdConn.EstimateFee(int64(confTarget))
+	if err != nil {
+		return 0, err
+	}
+
+	// Next, we'll convert the returned value to satoshis, as it's
+	// currently returned in BTC.
+	satPerKB, err := btcutil.NewAmount(btcPerKB)
+	if err != nil {
+		return 0, err
+	}
+
+	// Since we use fee rates in sat/kw internally, we'll convert the
+	// estimated fee rate from its sat/kb representation to sat/kw.
+	return SatPerKVByte(satPerKB).FeePerKWeight(), nil
+}
+
 // A compile-time assertion to ensure that BtcdEstimator implements the
-// Estimator interface.
+// Estimator and RelayFeeNotifier interfaces.
 var _ Estimator = (*BtcdEstimator)(nil)
+var _ RelayFeeNotifier = (*BtcdEstimator)(nil)
 
 // BitcoindEstimator is an implementation of the Estimator interface backed by
 // the RPC interface of an active bitcoind node. This implementation will proxy
@@ -298,6 +349,9 @@ type BitcoindEstimator struct {
 	// the transaction to propagate through the network.
 	minFeeManager *minFeeManager
 
+	// feeCurve caches the estimates of the backend by conf target.
+	feeCurve *feeCurve
+
 	// feeMode is the estimate_mode to use when calling "estimatesmartfee".
 	// It can be either "ECONOMICAL" or "CONSERVATIVE", and it's default
 	// to "CONSERVATIVE".
@@ -325,6 +379,7 @@ func NewBitcoindEstimator(rpcConfig rpcclient.ConnConfig, feeMode string,
 
 	return &BitcoindEstimator{
 		fallbackFeePerKW: fallBackFeeRate,
+		feeCurve:         newFeeCurve(defaultFeeCurveTTL),
 		bitcoindConn:     chainConn,
 		feeMode:          feeMode,
 	}, nil
@@ -347,7 +402,7 @@ func (b *BitcoindEstimator) Start() error {
 	}
 	b.minFeeManager = relayFeeManager
 
-	return nil
+	return b.minFeeManager.start(defaultMinFeePollInterval)
 }
 
 // fetchMinMempoolFee is used to fetch the minimum fee that the backend node
@@ -384,6 +439,10 @@ func (b *BitcoindEstimator) fetchMinMempoolFee() (SatPerKWeight, error) {
 //
 // NOTE: This method is part of the Estimator interface.
 func (b *BitcoindEstimator) Stop() error {
+	if b.minFeeManager != nil {
+		return b.minFeeManager.stop()
+	}
+
 	return nil
 }
 
@@ -425,11 +484,47 @@ func (b *BitcoindEstimator) RelayFeePerKW() SatPerKWeight {
 	return b.minFeeManager.fetchMinFee()
 }
 
+// SubscribeRelayFee returns a client that receives a *RelayFeeUpdate whenever
+// the minimum relay fee rate of the backend changes.
+//
+// NOTE: This method is part of the RelayFeeNotifier interface.
+func (b *BitcoindEstimator) SubscribeRelayFee() (*subscribe.Client, error) {
+	return b.minFeeManager.subscribe()
+}
+
 // fetchEstimate returns a fee estimate for a transaction to be confirmed in
 // confTarget blocks. The estimate is returned in sat/kw.
 func (b *BitcoindEstimator) fetchEstimate(confTarget uint32) (SatPerKWeight, error) {
-	// First, we'll send an "estimatesmartfee" command as a raw request,
-	// since it isn't supported by btcd but is available in bitcoind.
+	// First, we'll fetch the estimate for our confirmation target, which
+	// may be cached from a recent query.
+	satPerKw, err := b.feeCurve.fetch(confTarget, b.queryEstimate)
+	if err != nil {
+		return 0, err
+	}
+
+	// Finally, we'll enforce our fee floor.
+	minRelayFee := b.minFeeManager.fetchMinFee()
+	if satPerKw < minRelayFee {
+		log.Debugf("Estimated fee rate of %v sat/kw is too low, "+
+			"using fee floor of %v sat/kw instead", satPerKw,
+			minRelayFee)
+
+		satPerKw = minRelayFee
+	}
+
+	log.Debugf("Returning %v sat/kw for conf target of %v",
+		int64(satPerKw), confTarget)
+
+	return satPerKw, nil
+}
+
+// queryEstimate queries the backend for the fee estimate of the conf target,
+// in sat/kw.
+func (b *BitcoindEstimator) queryEstimate(confTarget uint32) (SatPerKWeight,
+	error) {
+
+	// We'll send an "estimatesmartfee" command as a raw request, since it
+	// isn't supported by btcd but is available in bitcoind.
 	target, err := json.Marshal(uint64(confTarget))
 	if err != nil {
 		return 0, err
@@ -466,27 +561,13 @@ func (b *BitcoindEstimator) fetchEstimate(confTarget uint32) (SatPerKWeight, err
 
 	// Since we use fee rates in sat/kw internally, we'll convert the
 	// estimated fee rate from its sat/kb representation to sat/kw.
-	satPerKw := SatPerKVByte(satPerKB).FeePerKWeight()
-
-	// Finally, we'll enforce our fee floor.
-	minRelayFee := b.minFeeManager.fetchMinFee()
-	if satPerKw < minRelayFee {
-		log.Debugf("Estimated fee rate of %v sat/kw is too low, "+
-			"using fee floor of %v sat/kw instead", satPerKw,
-			minRelayFee)
-
-		satPerKw = minRelayFee
-	}
-
-	log.Debugf("Returning %v sat/kw for conf target of %v",
-		int64(satPerKw), confTarget)
-
-	return satPerKw, nil
+	return SatPerKVByte(satPerKB).FeePerKWeight(), nil
 }
 
 // A compile-time assertion to ensure that BitcoindEstimator implements the
-// Estimator interface.
+// Estimator and RelayFeeNotifier interfaces.
 var _ Estimator = (*BitcoindEstimator)(nil)
+var _ RelayFeeNotifier = (*BitcoindEstimator)(nil)
 
 // WebAPIFeeSource is an interface allows the WebAPIEstimator to query an
 // arbitrary HTTP-based fee estimator. Each new set/network will gain an
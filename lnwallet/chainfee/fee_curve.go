@@ -0,0 +1,95 @@
+package chainfee
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultFeeCurveTTL is the time a fee estimate of the backend is cached for
+// its conf target. Estimates only change with new blocks and mempool
+// activity, so caching them for a short time saves the backend from being
+// queried for every transaction we construct.
+const defaultFeeCurveTTL = time.Minute
+
+// feeCurveEntry is a cached fee estimate for a single conf target.
+type feeCurveEntry struct {
+	feePerKW  SatPerKWeight
+	fetchedAt time.Time
+}
+
+// feeCurve caches the fee estimates of the backend by conf target, which
+// together make up its fee rate curve. The estimates are cached before our
+// fee floor is applied, as the floor can change independently.
+type feeCurve struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[uint32]feeCurveEntry
+
+	// now returns the current time, overwritten in tests.
+	now func() time.Time
+}
+
+// newFeeCurve returns a fee curve that caches estimates for the given ttl. A
+// zero ttl disables the cache.
+func newFeeCurve(ttl time.Duration) *feeCurve {
+	return &feeCurve{
+		ttl:     ttl,
+		entries: make(map[uint32]feeCurveEntry),
+		now:     time.Now,
+	}
+}
+
+// get returns the cached estimate for the conf target, if it hasn't expired.
+func (c *feeCurve) get(confTarget uint32) (SatPerKWeight, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[confTarget]
+	if !ok {
+		return 0, false
+	}
+
+	if c.now().Sub(entry.fetchedAt) >= c.ttl {
+		delete(c.entries, confTarget)
+		return 0, false
+	}
+
+	return entry.feePerKW, true
+}
+
+// set caches the estimate for the conf target.
+func (c *feeCurve) set(confTarget uint32, feePerKW SatPerKWeight) {
+	if c.ttl == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[confTarget] = feeCurveEntry{
+		feePerKW:  feePerKW,
+		fetchedAt: c.now(),
+	}
+}
+
+// fetch returns the cached estimate for the conf target, or queries it with
+// the given function and caches it. Failed queries and zero estimates, which
+// mean that the backend doesn't have enough data yet, aren't cached.
+func (c *feeCurve) fetch(confTarget uint32,
+	query func(uint32) (SatPerKWeight, error)) (SatPerKWeight, error) {
+
+	if feePerKW, ok := c.get(confTarget); ok {
+		return feePerKW, nil
+	}
+
+	feePerKW, err := query(confTarget)
+	if err != nil {
+		return 0, err
+	}
+
+	if feePerKW != 0 {
+		c.set(confTarget, feePerKW)
+	}
+
+	return feePerKW, nil
+}
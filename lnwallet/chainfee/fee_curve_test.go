@@ -0,0 +1,87 @@
+package chainfee
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestFeeCurve tests that the fee curve caches estimates by conf target until
+// they expire, and that failed and empty estimates aren't cached.
+func TestFeeCurve(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	curve := newFeeCurve(time.Minute)
+	curve.now = func() time.Time {
+		return now
+	}
+
+	var (
+		queries  int
+		estimate = SatPerKWeight(1000)
+		queryErr error
+	)
+	query := func(confTarget uint32) (SatPerKWeight, error) {
+		queries++
+		return estimate * SatPerKWeight(7-confTarget), queryErr
+	}
+
+	// The first query of a conf target goes to the backend.
+	feeRate, err := curve.fetch(6, query)
+	require.NoError(t, err)
+	require.Equal(t, SatPerKWeight(1000), feeRate)
+	require.Equal(t, 1, queries)
+
+	// The second one is served from the cache, while another conf target
+	// is queried.
+	feeRate, err = curve.fetch(6, query)
+	require.NoError(t, err)
+	require.Equal(t, SatPerKWeight(1000), feeRate)
+	require.Equal(t, 1, queries)
+
+	feeRate, err = curve.fetch(2, query)
+	require.NoError(t, err)
+	require.Equal(t, SatPerKWeight(5000), feeRate)
+	require.Equal(t, 2, queries)
+
+	// Once the ttl passed, the backend is queried again.
+	now = now.Add(time.Minute)
+	estimate = 2000
+	feeRate, err = curve.fetch(6, query)
+	require.NoError(t, err)
+	require.Equal(t, SatPerKWeight(2000), feeRate)
+	require.Equal(t, 3, queries)
+
+	// Errors aren't cached.
+	now = now.Add(time.Minute)
+	queryErr = errors.New("backend unavailable")
+	_, err = curve.fetch(6, query)
+	require.ErrorIs(t, err, queryErr)
+
+	queryErr = nil
+	_, err = curve.fetch(6, query)
+	require.NoError(t, err)
+	require.Equal(t, 5, queries)
+
+	// Neither are empty estimates.
+	now = now.Add(time.Minute)
+	estimate = 0
+	_, err = curve.fetch(6, query)
+	require.NoError(t, err)
+	_, err = curve.fetch(6, query)
+	require.NoError(t, err)
+	require.Equal(t, 7, queries)
+
+	// A zero ttl disables the cache.
+	curve = newFeeCurve(0)
+	estimate = 1000
+	queries = 0
+	_, err = curve.fetch(6, query)
+	require.NoError(t, err)
+	_, err = curve.fetch(6, query)
+	require.NoError(t, err)
+	require.Equal(t, 2, queries)
+}
@@ -3,9 +3,20 @@ package chainfee
 import (
 	"sync"
 	"time"
+
+	"github.com/lightningnetwork/lnd/subscribe"
 )
 
-const defaultUpdateInterval = 10 * time.Minute
+const (
+	defaultUpdateInterval = 10 * time.Minute
+
+	// defaultMinFeePollInterval is the interval at which the minimum fee
+	// of the backend is polled to notify subscribers of its changes. It is
+	// shorter than defaultUpdateInterval, so that spikes of the mempool
+	// minimum fee are detected before we construct transactions that
+	// can't be relayed.
+	defaultMinFeePollInterval = time.Minute
+)
 
 // minFeeManager is used to store and update the minimum fee that is required
 // by a transaction to be accepted to the mempool. The minFeeManager ensures
@@ -16,6 +27,14 @@ type minFeeManager struct {
 	lastUpdatedTime   time.Time
 	minUpdateInterval time.Duration
 	fetchFeeFunc      fetchFee
+
+	// notifier notifies subscribers of changes of the minimum fee, once
+	// started is set.
+	notifier *subscribe.Server
+	started  bool
+
+	quit chan struct{}
+	wg   sync.WaitGroup
 }
 
 // fetchFee represents a function that can be used to fetch a fee.
@@ -43,9 +62,64 @@ func newMinFeeManager(minUpdateInterval time.Duration,
 		lastUpdatedTime:   time.Now(),
 		minUpdateInterval: minUpdateInterval,
 		fetchFeeFunc:      fetchMinFee,
+		notifier:          subscribe.NewServer(),
+		quit:              make(chan struct{}),
 	}, nil
 }
 
+// start starts polling the backend for the minimum fee at the given interval,
+// notifying subscribers whenever it changes.
+func (m *minFeeManager) start(pollInterval time.Duration) error {
+	if err := m.notifier.Start(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.started = true
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+	go m.pollMinFee(pollInterval)
+
+	return nil
+}
+
+// stop stops polling the backend and cancels all subscriptions.
+func (m *minFeeManager) stop() error {
+	close(m.quit)
+	m.wg.Wait()
+
+	return m.notifier.Stop()
+}
+
+// pollMinFee periodically refreshes the minimum fee from the backend.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *minFeeManager) pollMinFee(pollInterval time.Duration) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.mu.Lock()
+			m.updateMinFee()
+			m.mu.Unlock()
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// subscribe returns a client that receives a RelayFeeUpdate whenever the
+// minimum fee changes.
+func (m *minFeeManager) subscribe() (*subscribe.Client, error) {
+	return m.notifier.Subscribe()
+}
+
 // fetchMinFee returns the stored minFeePerKW if it has been updated recently
 // or if the call to the chain backend fails. Otherwise, it sets the stored
 // minFeePerKW to the fee returned from the backend and floors it based on
@@ -58,6 +132,15 @@ func (m *minFeeManager) fetchMinFee() SatPerKWeight {
 		return m.minFeePerKW
 	}
 
+	return m.updateMinFee()
+}
+
+// updateMinFee queries the backend for the minimum fee, stores it and
+// notifies subscribers if it changed. If the call to the chain backend fails,
+// the last known minimum fee is returned.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (m *minFeeManager) updateMinFee() SatPerKWeight {
 	newMinFee, err := m.fetchFeeFunc()
 	if err != nil {
 		log.Errorf("Unable to fetch updated min fee from chain "+
@@ -69,14 +152,29 @@ func (m *minFeeManager) fetchMinFee() SatPerKWeight {
 	// By default, we'll use the backend node's minimum fee as the
 	// minimum fee rate we'll propose for transactions. However, if this
 	// happens to be lower than our fee floor, we'll enforce that instead.
-	m.minFeePerKW = newMinFee
-	if m.minFeePerKW < FeePerKwFloor {
-		m.minFeePerKW = FeePerKwFloor
+	if newMinFee < FeePerKwFloor {
+		newMinFee = FeePerKwFloor
 	}
+	oldMinFee := m.minFeePerKW
+	m.minFeePerKW = newMinFee
 	m.lastUpdatedTime = time.Now()
 
 	log.Debugf("Using minimum fee rate of %v sat/kw",
 		int64(m.minFeePerKW))
 
+	if newMinFee != oldMinFee && m.started {
+		log.Infof("Minimum relay fee rate changed from %v to %v",
+			oldMinFee, newMinFee)
+
+		err := m.notifier.SendUpdate(&RelayFeeUpdate{
+			OldFeePerKW: oldMinFee,
+			NewFeePerKW: newMinFee,
+		})
+		if err != nil {
+			log.Errorf("Unable to notify minimum relay fee "+
+				"update: %v", err)
+		}
+	}
+
 	return m.minFeePerKW
 }
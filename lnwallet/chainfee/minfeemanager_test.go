@@ -1,6 +1,7 @@
 package chainfee
 
 import (
+	"sync"
 	"testing"
 	"time"
 
@@ -57,3 +58,70 @@ func TestMinFeeManager(t *testing.T) {
 	require.Equal(t, SatPerKWeight(2000), minFee)
 	require.Equal(t, 2, chainBackend.callCount)
 }
+
+// TestMinFeeManagerNotifications tests that the minFeeManager polls the chain
+// backend and notifies its subscribers whenever the minimum fee changes.
+func TestMinFeeManagerNotifications(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu     sync.Mutex
+		minFee = SatPerKWeight(1000)
+	)
+	fetchFee := func() (SatPerKWeight, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return minFee, nil
+	}
+	setMinFee := func(fee SatPerKWeight) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		minFee = fee
+	}
+
+	feeManager, err := newMinFeeManager(time.Hour, fetchFee)
+	require.NoError(t, err)
+	require.NoError(t, feeManager.start(10*time.Millisecond))
+	defer func() {
+		require.NoError(t, feeManager.stop())
+	}()
+
+	client, err := feeManager.subscribe()
+	require.NoError(t, err)
+	defer client.Cancel()
+
+	assertUpdate := func(oldFee, newFee SatPerKWeight) {
+		t.Helper()
+
+		select {
+		case update := <-client.Updates():
+			require.Equal(t, &RelayFeeUpdate{
+				OldFeePerKW: oldFee,
+				NewFeePerKW: newFee,
+			}, update)
+
+		case <-time.After(time.Second):
+			t.Fatal("no relay fee update received")
+		}
+	}
+
+	// A mempool spike raises the minimum fee, which is picked up by the
+	// poller although the cached fee hasn't expired yet.
+	setMinFee(5000)
+	assertUpdate(1000, 5000)
+	require.Equal(t, SatPerKWeight(5000), feeManager.fetchMinFee())
+
+	// Once it drops below our fee floor, the floor is notified.
+	setMinFee(FeePerKwFloor - 1)
+	assertUpdate(5000, FeePerKwFloor)
+
+	// Polling an unchanged fee doesn't notify.
+	select {
+	case update := <-client.Updates():
+		t.Fatalf("unexpected update: %v", update)
+
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -157,6 +157,11 @@ type ChanCloseCfg struct {
 	// willing to pay to close the channel.
 	MaxFee chainfee.SatPerKWeight
 
+	// RelayFeePerKW, if set, returns the current minimum fee rate required
+	// for the closing transaction to be relayed. We neither propose nor
+	// accept a fee below it.
+	RelayFeePerKW func() chainfee.SatPerKWeight
+
 	// ChainParams holds the parameters of the chain that we're active on.
 	ChainParams *chaincfg.Params
 
@@ -238,6 +243,16 @@ func NewChanCloser(cfg ChanCloseCfg, deliveryScript []byte,
 	idealFeePerKw chainfee.SatPerKWeight, negotiationHeight uint32,
 	closeReq *htlcswitch.ChanClose, locallyInitiated bool) *ChanCloser {
 
+	// Our ideal fee rate must at least allow the closing transaction to be
+	// relayed.
+	if cfg.RelayFeePerKW != nil {
+		if relayFeePerKw := cfg.RelayFeePerKW(); idealFeePerKw <
+			relayFeePerKw {
+
+			idealFeePerKw = relayFeePerKw
+		}
+	}
+
 	// Given the target fee-per-kw, we'll compute what our ideal _total_
 	// fee will be starting at for this fee negotiation.
 	idealFeeSat := cfg.Channel.CalcFee(idealFeePerKw)
@@ -269,6 +284,16 @@ func NewChanCloser(cfg ChanCloseCfg, deliveryScript []byte,
 	}
 }
 
+// minRelayFee returns the lowest total fee of the closing transaction that
+// allows it to be relayed, or zero if the relay fee isn't known.
+func (c *ChanCloser) minRelayFee() btcutil.Amount {
+	if c.cfg.RelayFeePerKW == nil {
+		return 0
+	}
+
+	return c.cfg.Channel.CalcFee(c.cfg.RelayFeePerKW())
+}
+
 // initChanShutdown begins the shutdown process by un-registering the channel,
 // and creating a valid shutdown message to our target delivery address.
 func (c *ChanCloser) initChanShutdown() (*lnwire.Shutdown, error) {
@@ -565,15 +590,27 @@ func (c *ChanCloser) ProcessCloseMsg(msg lnwire.Message) ([]lnwire.Message,
 
 		// We'll compare the proposed total fee, to what we've proposed during
 		// the negotiations. If it doesn't match any of our prior offers, then
-		// we'll attempt to ratchet the fee closer to
+		// we'll attempt to ratchet the fee closer to a
+		// mutually acceptable fee. We also keep negotiating if the
+		// relay fee rose above the proposed fee in the meantime, as
+		// the closing transaction couldn't be relayed.
 		remoteProposedFee := closeSignedMsg.FeeSatoshis
-		if _, ok := c.priorFeeOffers[remoteProposedFee]; !ok {
+		minFee := c.minRelayFee()
+		_, isPriorOffer := c.priorFeeOffers[remoteProposedFee]
+		if !isPriorOffer || remoteProposedFee < minFee {
 			// We'll now attempt to ratchet towards a fee deemed acceptable by
 			// both parties, factoring in our ideal fee rate, and the last
 			// proposed fee by both sides.
 			feeProposal := calcCompromiseFee(c.chanPoint, c.idealFeeSat,
 				c.lastFeeProposal, remoteProposedFee,
 			)
+			if feeProposal < minFee {
+				chancloserLog.Infof("ChannelPoint(%v): raising "+
+					"fee proposal of %v to relay fee of %v",
+					c.chanPoint, feeProposal, minFee)
+
+				feeProposal = minFee
+			}
 			if c.cfg.Channel.IsInitiator() && feeProposal > c.maxFee {
 				return nil, false, fmt.Errorf("%w: %v > %v",
 					ErrProposalExeceedsMaxFee, feeProposal,
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -135,9 +137,20 @@ type mockChannel struct {
 	chanPoint   wire.OutPoint
 	initiator   bool
 	scid        lnwire.ShortChannelID
+
+	// feePerWeight, if set, makes CalcFee return the fee of a closing
+	// transaction of 1000 weight units instead of absoluteFee.
+	feePerWeight bool
+
+	// sig is the signature returned for close proposals.
+	sig input.Signature
 }
 
-func (m *mockChannel) CalcFee(chainfee.SatPerKWeight) btcutil.Amount {
+func (m *mockChannel) CalcFee(feeRate chainfee.SatPerKWeight) btcutil.Amount {
+	if m.feePerWeight {
+		return feeRate.FeeForWeight(1000)
+	}
+
 	return m.absoluteFee
 }
 
@@ -169,7 +182,7 @@ func (m *mockChannel) CreateCloseProposal(fee btcutil.Amount,
 	localScript, remoteScript []byte,
 ) (input.Signature, *chainhash.Hash, btcutil.Amount, error) {
 
-	return nil, nil, 0, nil
+	return m.sig, nil, 0, nil
 }
 
 func (m *mockChannel) CompleteCooperativeClose(localSig,
@@ -293,3 +306,53 @@ func TestMaxFeeBailOut(t *testing.T) {
 		})
 	}
 }
+
+// TestRelayFeeFloor tests that we neither propose nor accept a closing fee
+// below the relay fee.
+func TestRelayFeeFloor(t *testing.T) {
+	t.Parallel()
+
+	const idealFee = chainfee.SatPerKWeight(253)
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	sig := ecdsa.Sign(privKey, chainhash.DoubleHashB([]byte("close")))
+
+	relayFee := chainfee.SatPerKWeight(1000)
+	closeCfg := ChanCloseCfg{
+		Channel: &mockChannel{
+			feePerWeight: true,
+			initiator:    true,
+			sig:          sig,
+		},
+		MaxFee: relayFee * 10,
+		RelayFeePerKW: func() chainfee.SatPerKWeight {
+			return relayFee
+		},
+	}
+	chanCloser := NewChanCloser(closeCfg, nil, idealFee, 0, nil, false)
+
+	// Our ideal fee is raised to the relay fee.
+	require.Equal(t, relayFee.FeeForWeight(1000), chanCloser.idealFeeSat)
+
+	// We'll now force the channel state into the closeFeeNegotiation
+	// state, with an offer of ours at the current relay fee.
+	chanCloser.state = closeFeeNegotiation
+	offer, err := chanCloser.proposeCloseSigned(chanCloser.idealFeeSat)
+	require.NoError(t, err)
+
+	// Once the relay fee rises, the remote party accepting our prior
+	// offer doesn't end the negotiation, but we propose the new relay fee
+	// instead.
+	relayFee = 2000
+	msgs, done, err := chanCloser.ProcessCloseMsg(&lnwire.ClosingSigned{
+		FeeSatoshis: offer.FeeSatoshis,
+	})
+	require.NoError(t, err)
+	require.False(t, done)
+	require.Len(t, msgs, 1)
+
+	closeSigned, ok := msgs[0].(*lnwire.ClosingSigned)
+	require.True(t, ok)
+	require.Equal(t, relayFee.FeeForWeight(1000), closeSigned.FeeSatoshis)
+}
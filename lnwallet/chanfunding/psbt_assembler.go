@@ -10,6 +10,7 @@ import (
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/input"
@@ -148,6 +149,12 @@ type PsbtIntent struct {
 	// only closed exactly once.
 	signalPsbtReady sync.Once
 
+	// batch is set if the funding transaction of the intent also funds
+	// other channels. batchSigned is set once the remote party signed our
+	// commitment transaction and is guarded by the mutex of the batch.
+	batch       *psbtBatch
+	batchSigned bool
+
 	// netParams are the network parameters used to encode the P2WSH funding
 	// address.
 	netParams *chaincfg.Params
@@ -214,6 +221,18 @@ func (i *PsbtIntent) FundingParams() (btcutil.Address, int64, *psbt.Packet,
 // sends to the channel funding multisig address with the correct amount. A
 // simple check that at least a single input has been specified is performed.
 func (i *PsbtIntent) Verify(packet *psbt.Packet, skipFinalize bool) error {
+	if err := i.checkPsbt(packet); err != nil {
+		return err
+	}
+
+	i.markVerified(packet, skipFinalize)
+
+	return nil
+}
+
+// checkPsbt runs the checks of the Verify step against the given PSBT without
+// advancing the state of the intent.
+func (i *PsbtIntent) checkPsbt(packet *psbt.Packet) error {
 	if packet == nil {
 		return fmt.Errorf("PSBT is nil")
 	}
@@ -264,6 +283,12 @@ func (i *PsbtIntent) Verify(packet *psbt.Packet, skipFinalize bool) error {
 			"malleability: %v", err)
 	}
 
+	return nil
+}
+
+// markVerified advances the state of the intent after the given PSBT passed
+// the checks of the Verify step.
+func (i *PsbtIntent) markVerified(packet *psbt.Packet, skipFinalize bool) {
 	// In case we aren't going to publish any transaction, we now have
 	// everything we need and can skip the Finalize step.
 	i.PendingPsbt = packet
@@ -277,11 +302,10 @@ func (i *PsbtIntent) Verify(packet *psbt.Packet, skipFinalize bool) error {
 			close(i.PsbtReady)
 		})
 
-		return nil
+		return
 	}
 
 	i.State = PsbtVerified
-	return nil
 }
 
 // Finalize makes sure the final PSBT that is given to the intent is fully valid
@@ -473,6 +497,211 @@ func (i *PsbtIntent) ShouldPublishFundingTX() bool {
 	return i.shouldPublish
 }
 
+// CheckPsbtBatch checks a single PSBT against the funding intents of several
+// pending channels, for example to open channels to different peers in one
+// transaction, without advancing the state of any of them. Every intent's
+// funding output must be present in the PSBT and no two intents may claim the
+// same output.
+func CheckPsbtBatch(intents []*PsbtIntent, packet *psbt.Packet) error {
+	if packet == nil {
+		return fmt.Errorf("PSBT is nil")
+	}
+	if len(intents) == 0 {
+		return fmt.Errorf("no funding intents to verify")
+	}
+
+	// Locate the funding output of each channel first. The outputs of all
+	// channels must be distinct, otherwise two channels would end up with
+	// the same channel point.
+	claimedOutputs := make(map[uint32]int, len(intents))
+	for idx, intent := range intents {
+		if intent.State != PsbtOutputKnown {
+			return fmt.Errorf("invalid state for channel %d. got "+
+				"%v expected %v", idx, intent.State,
+				PsbtOutputKnown)
+		}
+
+		_, expectedOutput, err := intent.FundingOutput()
+		if err != nil {
+			return fmt.Errorf("funding output of channel %d "+
+				"cannot be created: %v", idx, err)
+		}
+
+		outputIndex, found := findOutput(
+			packet.UnsignedTx.TxOut, expectedOutput,
+		)
+		if !found {
+			return fmt.Errorf("funding output of channel %d not "+
+				"found in PSBT", idx)
+		}
+
+		if other, ok := claimedOutputs[outputIndex]; ok {
+			return fmt.Errorf("funding output %d claimed by both "+
+				"channel %d and %d", outputIndex, other, idx)
+		}
+		claimedOutputs[outputIndex] = idx
+	}
+
+	// All funding outputs are accounted for. The remaining checks only
+	// look at the inputs and total value of the PSBT, which are the same
+	// for every channel.
+	for idx, intent := range intents {
+		if err := intent.checkPsbt(packet); err != nil {
+			return fmt.Errorf("error verifying PSBT for channel "+
+				"%d: %v", idx, err)
+		}
+	}
+
+	return nil
+}
+
+// VerifyPsbtBatch verifies a single PSBT against the funding intents of
+// several pending channels with CheckPsbtBatch. Only once the PSBT passed the
+// checks for all of them is each intent advanced to the verified state, so
+// the batch is either verified as a whole or not at all.
+//
+// The intents are bound to each other, so that the shared funding transaction
+// is only published once the remote parties of all channels signed our
+// commitment transactions, see BatchFundingSigned.
+func VerifyPsbtBatch(intents []*PsbtIntent, packet *psbt.Packet,
+	skipFinalize bool) error {
+
+	if err := CheckPsbtBatch(intents, packet); err != nil {
+		return err
+	}
+
+	batch := &psbtBatch{
+		unsigned: len(intents),
+	}
+	for _, intent := range intents {
+		if intent.shouldPublish {
+			batch.shouldPublish = true
+		}
+
+		intent.batch = batch
+		intent.markVerified(packet, skipFinalize)
+	}
+
+	return nil
+}
+
+// FinalizePsbtBatch finalizes the funding intents of several pending channels
+// that were previously verified together with VerifyPsbtBatch, using a single
+// signed PSBT.
+func FinalizePsbtBatch(intents []*PsbtIntent, packet *psbt.Packet) error {
+	if packet == nil {
+		return fmt.Errorf("PSBT is nil")
+	}
+
+	err := psbt.MaybeFinalizeAll(packet)
+	if err != nil {
+		return fmt.Errorf("error finalizing PSBT: %v", err)
+	}
+	rawTx, err := psbt.Extract(packet)
+	if err != nil {
+		return fmt.Errorf("unable to extract funding TX: %v", err)
+	}
+
+	return FinalizeRawTXBatch(intents, rawTx)
+}
+
+// FinalizeRawTXBatch finalizes the funding intents of several pending channels
+// that were previously verified together with VerifyPsbtBatch, using a single
+// signed raw transaction.
+func FinalizeRawTXBatch(intents []*PsbtIntent, rawTx *wire.MsgTx) error {
+	if rawTx == nil {
+		return fmt.Errorf("raw transaction is nil")
+	}
+	if len(intents) == 0 {
+		return fmt.Errorf("no funding intents to finalize")
+	}
+
+	// Make sure all intents were verified against the same transaction
+	// before we signal any of them, so we don't end up with only part of
+	// the batch being finalized.
+	var verifiedTxid chainhash.Hash
+	for idx, intent := range intents {
+		if intent.State != PsbtVerified {
+			return fmt.Errorf("invalid state for channel %d. got "+
+				"%v expected %v", idx, intent.State,
+				PsbtVerified)
+		}
+		if intent.PendingPsbt == nil {
+			return fmt.Errorf("PSBT of channel %d was not "+
+				"verified first", idx)
+		}
+
+		txid := intent.PendingPsbt.UnsignedTx.TxHash()
+		if idx == 0 {
+			verifiedTxid = txid
+			continue
+		}
+		if txid != verifiedTxid {
+			return fmt.Errorf("channel %d was verified with a "+
+				"different PSBT than channel 0", idx)
+		}
+	}
+
+	for idx, intent := range intents {
+		if err := intent.FinalizeRawTX(rawTx); err != nil {
+			return fmt.Errorf("error finalizing channel %d: %v",
+				idx, err)
+		}
+	}
+
+	return nil
+}
+
+// findOutput returns the index of the first output that is equal to the
+// given output.
+func findOutput(outputs []*wire.TxOut, target *wire.TxOut) (uint32, bool) {
+	for idx, out := range outputs {
+		if psbt.TxOutsEqual(out, target) {
+			return uint32(idx), true
+		}
+	}
+
+	return 0, false
+}
+
+// psbtBatch is shared by the funding intents of several channels that are
+// funded by the same transaction.
+type psbtBatch struct {
+	mtx sync.Mutex
+
+	// unsigned is the number of channels of the batch for which the
+	// remote party didn't sign our commitment transaction yet.
+	unsigned int
+
+	// shouldPublish is true if any of the intents of the batch assumes
+	// that its assembler publishes the funding transaction.
+	shouldPublish bool
+}
+
+// BatchFundingSigned marks the channel of the intent as signed by the remote
+// party, meaning we have a valid commitment transaction for it. It returns
+// true if the intent is part of a batch that should be published and this was
+// the last channel of the batch waiting for its signature. Only then is it
+// safe to publish the shared funding transaction, as publishing it earlier
+// could leave the funds of the other channels locked in their funding
+// outputs without a commitment transaction to recover them.
+func (i *PsbtIntent) BatchFundingSigned() bool {
+	if i.batch == nil {
+		return false
+	}
+
+	i.batch.mtx.Lock()
+	defer i.batch.mtx.Unlock()
+
+	if i.batchSigned {
+		return false
+	}
+	i.batchSigned = true
+	i.batch.unsigned--
+
+	return i.batch.unsigned == 0 && i.batch.shouldPublish
+}
+
 // PsbtAssembler is a type of chanfunding.Assembler wherein the funding
 // transaction is constructed outside of lnd by using partially signed bitcoin
 // transactions (PSBT).
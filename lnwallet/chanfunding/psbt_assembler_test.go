@@ -667,6 +667,163 @@ func TestPsbtFinalize(t *testing.T) {
 	}
 }
 
+// newBatchIntents creates PSBT intents for channels with the given remote
+// multisig keys, each adding its funding output to the PSBT of the previous
+// one. The returned PSBT contains the funding outputs of all channels and is
+// funded by a single signed input.
+func newBatchIntents(t *testing.T,
+	remotePrivkeys ...[]byte) ([]*PsbtIntent, *psbt.Packet) {
+
+	_, localPubkey := btcec.PrivKeyFromBytes(localPrivkey)
+
+	var (
+		intents []*PsbtIntent
+		packet  *psbt.Packet
+	)
+	for _, remotePrivkey := range remotePrivkeys {
+		a := NewPsbtAssembler(chanCapacity, packet, &params, true)
+		intent, err := a.ProvisionChannel(
+			&Request{LocalAmt: chanCapacity},
+		)
+		require.NoError(t, err)
+		psbtIntent := intent.(*PsbtIntent)
+
+		_, remotePubkey := btcec.PrivKeyFromBytes(remotePrivkey)
+		psbtIntent.BindKeys(
+			&keychain.KeyDescriptor{PubKey: localPubkey},
+			remotePubkey,
+		)
+
+		_, _, packet, err = psbtIntent.FundingParams()
+		require.NoError(t, err)
+
+		intents = append(intents, psbtIntent)
+	}
+
+	packet.UnsignedTx.TxIn = []*wire.TxIn{{
+		PreviousOutPoint: wire.OutPoint{
+			Index: 1,
+			Hash:  chainhash.Hash{1, 2, 3},
+		},
+	}}
+	packet.Inputs = []psbt.PInput{{
+		WitnessUtxo: &wire.TxOut{
+			Value:    int64(chanCapacity)*int64(len(intents)) + 1,
+			PkScript: []byte{1, 2, 3},
+		},
+		FinalScriptWitness: []byte{0x01, 0x00},
+	}}
+
+	return intents, packet
+}
+
+// TestPsbtBatch tests that a single PSBT can be used to fund several
+// channels.
+func TestPsbtBatch(t *testing.T) {
+	t.Parallel()
+
+	otherRemotePrivkey := []byte{7, 7, 7, 7, 7, 7}
+
+	t.Run("output missing", func(t *testing.T) {
+		t.Parallel()
+
+		intents, packet := newBatchIntents(
+			t, remotePrivkey, otherRemotePrivkey,
+		)
+
+		// Remove the funding output of the second channel.
+		packet.UnsignedTx.TxOut = packet.UnsignedTx.TxOut[:1]
+		packet.Outputs = packet.Outputs[:1]
+
+		err := VerifyPsbtBatch(intents, packet, false)
+		require.ErrorContains(
+			t, err, "funding output of channel 1 not found in PSBT",
+		)
+
+		// None of the channels should have been verified.
+		for _, intent := range intents {
+			require.Equal(t, PsbtOutputKnown, intent.State)
+		}
+	})
+
+	t.Run("same output twice", func(t *testing.T) {
+		t.Parallel()
+
+		intents, packet := newBatchIntents(t, remotePrivkey)
+		intents = append(intents, intents[0])
+
+		err := VerifyPsbtBatch(intents, packet, false)
+		require.ErrorContains(
+			t, err, "funding output 0 claimed by both channel 0 "+
+				"and 1",
+		)
+		require.Equal(t, PsbtOutputKnown, intents[0].State)
+	})
+
+	t.Run("verified separately", func(t *testing.T) {
+		t.Parallel()
+
+		intents, packet := newBatchIntents(
+			t, remotePrivkey, otherRemotePrivkey,
+		)
+		require.NoError(t, VerifyPsbtBatch(intents, packet, false))
+
+		// Verify the second channel again with a different PSBT.
+		otherPacket := clonePsbt(t, packet)
+		otherPacket.UnsignedTx.TxIn[0].PreviousOutPoint.Index = 2
+		intents[1].State = PsbtOutputKnown
+		require.NoError(t, intents[1].Verify(otherPacket, false))
+
+		rawTx, err := psbt.Extract(packet)
+		require.NoError(t, err)
+
+		err = FinalizeRawTXBatch(intents, rawTx)
+		require.ErrorContains(
+			t, err, "channel 1 was verified with a different PSBT",
+		)
+		for _, intent := range intents {
+			require.Equal(t, PsbtVerified, intent.State)
+		}
+	})
+
+	t.Run("happy path", func(t *testing.T) {
+		t.Parallel()
+
+		intents, packet := newBatchIntents(
+			t, remotePrivkey, otherRemotePrivkey,
+		)
+		require.NoError(t, VerifyPsbtBatch(intents, packet, false))
+		for _, intent := range intents {
+			require.Equal(t, PsbtVerified, intent.State)
+		}
+
+		err := FinalizePsbtBatch(intents, clonePsbt(t, packet))
+		require.NoError(t, err)
+
+		// Each channel should end up with its own output of the same
+		// funding transaction.
+		txid := packet.UnsignedTx.TxHash()
+		for idx, intent := range intents {
+			require.Equal(t, PsbtFinalized, intent.State)
+
+			_, err := intent.CompileFundingTx()
+			require.NoError(t, err)
+
+			chanPoint, err := intent.ChanPoint()
+			require.NoError(t, err)
+			require.Equal(t, txid, chanPoint.Hash)
+			require.EqualValues(t, idx, chanPoint.Index)
+		}
+
+		// The shared funding transaction may only be published once
+		// the last channel of the batch was signed, and only once.
+		require.False(t, intents[1].BatchFundingSigned())
+		require.False(t, intents[1].BatchFundingSigned())
+		require.True(t, intents[0].BatchFundingSigned())
+		require.False(t, intents[0].BatchFundingSigned())
+	})
+}
+
 func TestVerifyAllInputsSegWit(t *testing.T) {
 	testCases := []struct {
 		name       string
@@ -3869,6 +3869,13 @@ func (lc *LightningChannel) ProcessChanSyncMsg(
 		closedCircuits []channeldb.CircuitKey
 	)
 
+	// We never construct funding transactions interactively, so there's
+	// no signing session the remote party could ask us to resume.
+	if msg.NextFundingTxid != nil {
+		lc.log.Debugf("Ignoring next funding txid %v in channel "+
+			"reestablish", chainhash.Hash(*msg.NextFundingTxid))
+	}
+
 	// If the remote party included the optional fields, then we'll verify
 	// their correctness first, as it will influence our decisions below.
 	hasRecoveryOptions := msg.LocalUnrevokedCommitPoint != nil
@@ -6453,6 +6460,41 @@ func (lc *LightningChannel) ForceClose() (*LocalForceCloseSummary, error) {
 	lc.Lock()
 	defer lc.Unlock()
 
+	summary, err := lc.forceCloseSummary()
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the channel state to indicate that the channel is now in a
+	// contested state.
+	lc.status = channelDispute
+
+	return summary, nil
+}
+
+// DryRunForceClose returns the LocalForceCloseSummary of a unilateral closure
+// at the current lowest commitment height of the channel, without moving the
+// channel into the contested state. This allows callers to inspect what a
+// force close would broadcast without committing to it.
+//
+// NOTE: The summary contains our fully signed commitment transaction, which
+// must not be broadcast once the channel has advanced to a later state.
+func (lc *LightningChannel) DryRunForceClose() (*LocalForceCloseSummary,
+	error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	return lc.forceCloseSummary()
+}
+
+// forceCloseSummary creates the LocalForceCloseSummary of a unilateral closure
+// at the current lowest commitment height of the channel.
+//
+// NOTE: This method requires the channel's lock to be held.
+func (lc *LightningChannel) forceCloseSummary() (*LocalForceCloseSummary,
+	error) {
+
 	// If we've detected local data loss for this channel, then we won't
 	// allow a force close, as it may be the case that we have a dated
 	// version of the commitment, or this is actually a channel shell.
@@ -6467,19 +6509,10 @@ func (lc *LightningChannel) ForceClose() (*LocalForceCloseSummary, error) {
 	}
 
 	localCommitment := lc.channelState.LocalCommitment
-	summary, err := NewLocalForceCloseSummary(
+	return NewLocalForceCloseSummary(
 		lc.channelState, lc.Signer, commitTx,
 		localCommitment.CommitHeight,
 	)
-	if err != nil {
-		return nil, err
-	}
-
-	// Set the channel state to indicate that the channel is now in a
-	// contested state.
-	lc.status = channelDispute
-
-	return summary, nil
 }
 
 // NewLocalForceCloseSummary generates a LocalForceCloseSummary from the given
@@ -7414,6 +7447,32 @@ func (lc *LightningChannel) ActiveHtlcs() []channeldb.HTLC {
 	return lc.channelState.ActiveHtlcs()
 }
 
+// NumPendingHtlcs returns the number of HTLCs that are on the local or the
+// remote commitment. Unlike ActiveHtlcs, this includes the HTLCs that were
+// only added to or removed from one of the commitments so far.
+func (lc *LightningChannel) NumPendingHtlcs() int {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	type htlcKey struct {
+		incoming bool
+		index    uint64
+	}
+
+	htlcs := make(map[htlcKey]struct{})
+	for _, commit := range []channeldb.ChannelCommitment{
+		lc.channelState.LocalCommitment,
+		lc.channelState.RemoteCommitment,
+	} {
+		for _, htlc := range commit.Htlcs {
+			key := htlcKey{htlc.Incoming, htlc.HtlcIndex}
+			htlcs[key] = struct{}{}
+		}
+	}
+
+	return len(htlcs)
+}
+
 // LocalChanReserve returns our local ChanReserve requirement for the remote party.
 func (lc *LightningChannel) LocalChanReserve() btcutil.Amount {
 	return lc.channelState.LocalChanCfg.ChanReserve
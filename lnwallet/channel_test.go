@@ -1092,6 +1092,38 @@ func testForceClose(t *testing.T, testCase *forceCloseTestCase) {
 	}
 }
 
+// TestDryRunForceClose tests that a dry run of a force close returns the same
+// summary as the actual force close, without moving the channel into the
+// contested state.
+func TestDryRunForceClose(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, _, cleanUp, err := CreateTestChannels(
+		channeldb.SingleFunderTweaklessBit | channeldb.AnchorOutputsBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+	defer cleanUp()
+
+	status := aliceChannel.status
+
+	dryRunSummary, err := aliceChannel.DryRunForceClose()
+	require.NoError(t, err)
+	require.Equal(t, status, aliceChannel.status)
+	require.NotNil(t, dryRunSummary.CommitResolution)
+	require.NotNil(t, dryRunSummary.AnchorResolution)
+
+	summary, err := aliceChannel.ForceClose()
+	require.NoError(t, err)
+	require.Equal(t, channelDispute, aliceChannel.status)
+	require.Equal(
+		t, summary.CloseTx.TxHash(), dryRunSummary.CloseTx.TxHash(),
+	)
+	require.Equal(
+		t, summary.CommitResolution.SelfOutPoint,
+		dryRunSummary.CommitResolution.SelfOutPoint,
+	)
+}
+
 // TestForceCloseDustOutput tests that if either side force closes with an
 // active dust output (for only a single party due to asymmetric dust values),
 // then the force close summary is well crafted.
@@ -2020,6 +2052,48 @@ func TestCancelHTLC(t *testing.T) {
 	}
 }
 
+// TestNumPendingHtlcs asserts that the HTLCs on the commitments of a channel
+// are counted until they're removed from both commitments.
+func TestNumPendingHtlcs(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := CreateTestChannels(
+		channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+	defer cleanUp()
+
+	htlc, _ := createHTLC(0, lnwire.NewMSatFromSatoshis(10000))
+	aliceHtlcIndex, err := aliceChannel.AddHTLC(htlc, nil)
+	require.NoError(t, err, "unable to add alice htlc")
+	bobHtlcIndex, err := bobChannel.ReceiveHTLC(htlc)
+	require.NoError(t, err, "unable to add bob htlc")
+
+	// The HTLC isn't on any of the commitments until it's signed for.
+	require.Zero(t, aliceChannel.NumPendingHtlcs())
+	require.Zero(t, bobChannel.NumPendingHtlcs())
+
+	err = ForceStateTransition(aliceChannel, bobChannel)
+	require.NoError(t, err, "unable to complete state update")
+
+	require.Equal(t, 1, aliceChannel.NumPendingHtlcs())
+	require.Equal(t, 1, bobChannel.NumPendingHtlcs())
+
+	// Once the HTLC is failed and removed from both commitments, it's no
+	// longer pending.
+	err = bobChannel.FailHTLC(bobHtlcIndex, []byte("failreason"), nil, nil,
+		nil)
+	require.NoError(t, err, "unable to cancel HTLC")
+	err = aliceChannel.ReceiveFailHTLC(aliceHtlcIndex, []byte("bad"))
+	require.NoError(t, err, "unable to recv htlc cancel")
+
+	err = ForceStateTransition(bobChannel, aliceChannel)
+	require.NoError(t, err, "unable to complete state update")
+
+	require.Zero(t, aliceChannel.NumPendingHtlcs())
+	require.Zero(t, bobChannel.NumPendingHtlcs())
+}
+
 func TestCooperativeCloseDustAdherence(t *testing.T) {
 	t.Parallel()
 
@@ -53,6 +53,10 @@ type Config struct {
 	// used for any incoming or outgoing channel reservation requests.
 	DefaultConstraints channeldb.ChannelConstraints
 
+	// DustLimits overrides the dust limit of the default constraints
+	// depending on the commitment type of a channel.
+	DustLimits DustLimitPolicy
+
 	// NetParams is the set of parameters that tells the wallet which chain
 	// it will be operating on.
 	NetParams chaincfg.Params
@@ -0,0 +1,182 @@
+package lnwallet
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// DustLimitPolicy selects the dust limit we require for our commitment
+// transaction based on the commitment type of a new channel. A zero dust
+// limit selects the default dust limit of the wallet's channel constraints.
+type DustLimitPolicy struct {
+	// Legacy is the dust limit of channels without anchor outputs.
+	Legacy btcutil.Amount
+
+	// Anchors is the dust limit of channels with anchor outputs, including
+	// script enforced leases.
+	Anchors btcutil.Amount
+}
+
+// Validate ensures that the dust limits of the policy are accepted by our
+// peers. Like we do for our peers, they require the dust limit to lie between
+// the dust limit of the largest witness program and three times that amount.
+func (p *DustLimitPolicy) Validate() error {
+	minDustLimit := DustLimitForSize(input.UnknownWitnessSize)
+	maxDustLimit := 3 * minDustLimit
+
+	checkLimit := func(name string, dustLimit btcutil.Amount) error {
+		if dustLimit == 0 {
+			return nil
+		}
+
+		if dustLimit < minDustLimit || dustLimit > maxDustLimit {
+			return fmt.Errorf("%s dust limit %v must be between "+
+				"%v and %v", name, dustLimit, minDustLimit,
+				maxDustLimit)
+		}
+
+		return nil
+	}
+
+	if err := checkLimit("legacy", p.Legacy); err != nil {
+		return err
+	}
+
+	return checkLimit("anchors", p.Anchors)
+}
+
+// DustLimit returns the dust limit to use for a channel of the given
+// commitment type, or defaultLimit if the policy doesn't set one.
+func (p *DustLimitPolicy) DustLimit(commitType CommitmentType,
+	defaultLimit btcutil.Amount) btcutil.Amount {
+
+	dustLimit := p.Legacy
+	if commitType.HasAnchors() {
+		dustLimit = p.Anchors
+	}
+
+	if dustLimit == 0 {
+		return defaultLimit
+	}
+
+	return dustLimit
+}
+
+// CommitDustInfo describes which outputs are trimmed from one of the
+// commitment transactions of a channel because they're below the dust limit
+// of its owner. The value of trimmed outputs goes to miners if the commitment
+// is broadcast, so it can't be enforced on-chain.
+type CommitDustInfo struct {
+	// DustLimit is the dust limit of the commitment's owner.
+	DustLimit btcutil.Amount
+
+	// FeePerKw is the fee rate of the commitment, which determines the fee
+	// of the second-level HTLC transactions.
+	FeePerKw chainfee.SatPerKWeight
+
+	// IncomingHtlcThreshold is the amount below which HTLCs offered by the
+	// remote party are trimmed from the commitment.
+	IncomingHtlcThreshold btcutil.Amount
+
+	// OutgoingHtlcThreshold is the amount below which HTLCs we offered are
+	// trimmed from the commitment.
+	OutgoingHtlcThreshold btcutil.Amount
+
+	// NumDustHtlcs is the number of HTLCs that are trimmed from the
+	// commitment.
+	NumDustHtlcs int
+
+	// DustHtlcAmount is the total amount of the HTLCs that are trimmed
+	// from the commitment.
+	DustHtlcAmount lnwire.MilliSatoshi
+
+	// TrimmedBalance is the total amount of the balances that are trimmed
+	// from the commitment.
+	TrimmedBalance lnwire.MilliSatoshi
+}
+
+// Exposure returns the total value that isn't protected on-chain by the
+// commitment, as it's trimmed to dust.
+func (c *CommitDustInfo) Exposure() lnwire.MilliSatoshi {
+	return c.DustHtlcAmount + c.TrimmedBalance
+}
+
+// ChannelDustInfo describes the trimmed outputs of both the local and the
+// remote commitment of a channel.
+type ChannelDustInfo struct {
+	// Local describes our latest commitment.
+	Local *CommitDustInfo
+
+	// Remote describes the remote party's latest commitment.
+	Remote *CommitDustInfo
+}
+
+// NewChannelDustInfo computes the trimmed outputs of the latest commitments of
+// the given channel.
+func NewChannelDustInfo(chanState *channeldb.OpenChannel) *ChannelDustInfo {
+	return &ChannelDustInfo{
+		Local: newCommitDustInfo(
+			chanState.ChanType, &chanState.LocalCommitment,
+			chanState.LocalChanCfg.DustLimit, true,
+		),
+		Remote: newCommitDustInfo(
+			chanState.ChanType, &chanState.RemoteCommitment,
+			chanState.RemoteChanCfg.DustLimit, false,
+		),
+	}
+}
+
+// newCommitDustInfo computes the trimmed outputs of the given commitment,
+// which is owned by us if ourCommit is set.
+func newCommitDustInfo(chanType channeldb.ChannelType,
+	commit *channeldb.ChannelCommitment, dustLimit btcutil.Amount,
+	ourCommit bool) *CommitDustInfo {
+
+	feePerKw := chainfee.SatPerKWeight(commit.FeePerKw)
+
+	// HTLCs offered by the commitment's owner are claimed through a
+	// second-level timeout transaction, the ones offered to it through a
+	// second-level success transaction. The fee of the second-level
+	// transaction is deducted from the HTLC before comparing it with the
+	// dust limit.
+	timeoutThreshold := dustLimit + HtlcTimeoutFee(chanType, feePerKw)
+	successThreshold := dustLimit + HtlcSuccessFee(chanType, feePerKw)
+
+	info := &CommitDustInfo{
+		DustLimit:             dustLimit,
+		FeePerKw:              feePerKw,
+		IncomingHtlcThreshold: successThreshold,
+		OutgoingHtlcThreshold: timeoutThreshold,
+	}
+	if !ourCommit {
+		info.IncomingHtlcThreshold = timeoutThreshold
+		info.OutgoingHtlcThreshold = successThreshold
+	}
+
+	for _, htlc := range commit.Htlcs {
+		if !HtlcIsDust(
+			chanType, htlc.Incoming, ourCommit, feePerKw,
+			htlc.Amt.ToSatoshis(), dustLimit,
+		) {
+			continue
+		}
+
+		info.NumDustHtlcs++
+		info.DustHtlcAmount += htlc.Amt
+	}
+
+	for _, balance := range []lnwire.MilliSatoshi{
+		commit.LocalBalance, commit.RemoteBalance,
+	} {
+		if balance.ToSatoshis() < dustLimit {
+			info.TrimmedBalance += balance
+		}
+	}
+
+	return info
+}
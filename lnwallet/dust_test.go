@@ -0,0 +1,110 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDustLimitPolicy asserts that the dust limit policy selects the dust
+// limit by commitment type and only accepts dust limits our peers accept.
+func TestDustLimitPolicy(t *testing.T) {
+	t.Parallel()
+
+	minDustLimit := DustLimitForSize(input.UnknownWitnessSize)
+	policy := &DustLimitPolicy{
+		Anchors: 2 * minDustLimit,
+	}
+	require.NoError(t, policy.Validate())
+
+	require.Equal(
+		t, minDustLimit,
+		policy.DustLimit(CommitmentTypeTweakless, minDustLimit),
+	)
+	require.Equal(
+		t, 2*minDustLimit, policy.DustLimit(
+			CommitmentTypeScriptEnforcedLease, minDustLimit,
+		),
+	)
+
+	policy.Legacy = minDustLimit - 1
+	require.Error(t, policy.Validate())
+
+	policy.Legacy = 3*minDustLimit + 1
+	require.Error(t, policy.Validate())
+}
+
+// TestChannelDustInfo asserts that the HTLC trimming thresholds and the value
+// trimmed to dust are computed for both commitments of a channel.
+func TestChannelDustInfo(t *testing.T) {
+	t.Parallel()
+
+	const (
+		chanType = channeldb.SingleFunderTweaklessBit
+		feePerKw = chainfee.SatPerKWeight(1000)
+	)
+
+	timeoutFee := HtlcTimeoutFee(chanType, feePerKw)
+	successFee := HtlcSuccessFee(chanType, feePerKw)
+
+	htlcs := []channeldb.HTLC{
+		{
+			Amt:      lnwire.NewMSatFromSatoshis(1000),
+			Incoming: true,
+		},
+		{
+			Amt: lnwire.NewMSatFromSatoshis(2000),
+		},
+	}
+
+	localBalance := lnwire.NewMSatFromSatoshis(btcutil.SatoshiPerBitcoin)
+	commit := channeldb.ChannelCommitment{
+		LocalBalance:  localBalance,
+		RemoteBalance: lnwire.NewMSatFromSatoshis(400),
+		FeePerKw:      btcutil.Amount(feePerKw),
+		Htlcs:         htlcs,
+	}
+
+	chanState := &channeldb.OpenChannel{
+		ChanType:         chanType,
+		LocalCommitment:  commit,
+		RemoteCommitment: commit,
+	}
+	chanState.LocalChanCfg.DustLimit = 500
+	chanState.RemoteChanCfg.DustLimit = 1500
+
+	info := NewChannelDustInfo(chanState)
+
+	// On our commitment, the incoming HTLC is claimed with a success
+	// transaction and trimmed, while the outgoing one is claimed with a
+	// timeout transaction and kept. The remote balance is trimmed.
+	require.Equal(t, &CommitDustInfo{
+		DustLimit:             500,
+		FeePerKw:              feePerKw,
+		IncomingHtlcThreshold: 500 + successFee,
+		OutgoingHtlcThreshold: 500 + timeoutFee,
+		NumDustHtlcs:          1,
+		DustHtlcAmount:        htlcs[0].Amt,
+		TrimmedBalance:        commit.RemoteBalance,
+	}, info.Local)
+	require.Equal(
+		t, htlcs[0].Amt+commit.RemoteBalance, info.Local.Exposure(),
+	)
+
+	// On the remote commitment, the second-level transactions are
+	// reversed, and the higher dust limit also trims the outgoing HTLC.
+	require.Equal(t, &CommitDustInfo{
+		DustLimit:             1500,
+		FeePerKw:              feePerKw,
+		IncomingHtlcThreshold: 1500 + timeoutFee,
+		OutgoingHtlcThreshold: 1500 + successFee,
+		NumDustHtlcs:          2,
+		DustHtlcAmount:        htlcs[0].Amt + htlcs[1].Amt,
+		TrimmedBalance:        commit.RemoteBalance,
+	}, info.Remote)
+}
@@ -152,6 +152,18 @@ func ErrChanTooLarge(chanSize, maxChanSize btcutil.Amount) ReservationError {
 	}
 }
 
+// ErrPeerExposureTooLarge returns an error indicating that a channel would
+// raise the total amount we have in channels with a peer above the configured
+// maximum.
+func ErrPeerExposureTooLarge(exposure,
+	maxExposure btcutil.Amount) ReservationError {
+
+	return ReservationError{
+		fmt.Errorf("peer exposure of %v exceeds maximum peer exposure "+
+			"of %v", exposure, maxExposure),
+	}
+}
+
 // ErrInvalidDustLimit returns an error indicating that a proposed DustLimit
 // was rejected.
 func ErrInvalidDustLimit(dustLimit btcutil.Amount) ReservationError {
@@ -160,6 +172,15 @@ func ErrInvalidDustLimit(dustLimit btcutil.Amount) ReservationError {
 	}
 }
 
+// ErrUpfrontShutdownRequired returns an error indicating that we require an
+// upfront shutdown script to be set for the channel, but the remote peer
+// doesn't support the option.
+func ErrUpfrontShutdownRequired() ReservationError {
+	return ReservationError{
+		errors.New("upfront shutdown script required"),
+	}
+}
+
 // ErrHtlcIndexAlreadyFailed is returned when the HTLC index has already been
 // failed, but has not been committed by our commitment state.
 type ErrHtlcIndexAlreadyFailed uint64
@@ -66,6 +66,27 @@ var (
 	// transaction.
 	ErrDoubleSpend = errors.New("transaction rejected: output already spent")
 
+	// ErrMempoolRejected is returned from PublishTransaction in case the
+	// tx being published is rejected by the mempool policy of the backend,
+	// for example because its fee rate is below the minimum relay fee or
+	// it has too many unconfirmed ancestors.
+	ErrMempoolRejected = errors.New("transaction rejected by mempool " +
+		"policy")
+
+	// ErrMempoolMinFee is returned from PublishTransaction in case the tx
+	// being published is rejected because its fee rate is below the
+	// minimum relay fee or the dynamic minimum fee of a full mempool. It
+	// wraps ErrMempoolRejected.
+	ErrMempoolMinFee = fmt.Errorf("%w: fee rate below mempool minimum",
+		ErrMempoolRejected)
+
+	// ErrMempoolChainTooLong is returned from PublishTransaction in case
+	// the tx being published is rejected because it would exceed the
+	// limits on unconfirmed ancestors or descendants. It wraps
+	// ErrMempoolRejected.
+	ErrMempoolChainTooLong = fmt.Errorf("%w: too many unconfirmed "+
+		"ancestors or descendants", ErrMempoolRejected)
+
 	// ErrNotMine is an error denoting that a WalletController instance is
 	// unable to spend a specified output.
 	ErrNotMine = errors.New("the passed output doesn't belong to the wallet")
@@ -105,6 +126,54 @@ type AccountIdentifier struct {
 	DerivationPath string
 }
 
+// WatchOnlyAccount describes an account backed by an account extended public
+// key that is imported as part of a WatchOnlyImport.
+type WatchOnlyAccount struct {
+	// Name is the name of the imported account.
+	Name string
+
+	// AccountPubKey is the extended public key of the account.
+	AccountPubKey *hdkeychain.ExtendedKey
+
+	// MasterKeyFingerprint is the fingerprint of the root key
+	// corresponding to the account public key.
+	MasterKeyFingerprint uint32
+
+	// AddrType is the address type used to map the account public key
+	// into the proper key scope.
+	AddrType waddrmgr.AddressType
+
+	// NumExternalAddrs and NumInternalAddrs are the number of addresses
+	// that are derived and stored on the external and internal branch of
+	// the account, so they are recognized by a subsequent rescan.
+	NumExternalAddrs uint32
+	NumInternalAddrs uint32
+}
+
+// WatchOnlyPubKey describes a single derived public key that is imported as
+// part of a WatchOnlyImport.
+type WatchOnlyPubKey struct {
+	// PubKey is the imported public key.
+	PubKey *btcec.PublicKey
+
+	// AddrType is the type of the address the public key is imported as.
+	AddrType waddrmgr.AddressType
+}
+
+// WatchOnlyImport is a batch of watch-only accounts, public keys and taproot
+// scripts that is imported into the wallet as a whole.
+type WatchOnlyImport struct {
+	// Accounts are the accounts to import.
+	Accounts []*WatchOnlyAccount
+
+	// PubKeys are the public keys to import.
+	PubKeys []*WatchOnlyPubKey
+
+	// Tapscripts are the taproot scripts to import into the BIP-0086 key
+	// scope.
+	Tapscripts []*waddrmgr.Tapscript
+}
+
 // Utxo is an unspent output denoted by its outpoint, and output value of the
 // original output.
 type Utxo struct {
@@ -325,6 +394,21 @@ type WalletController interface {
 	ImportTaprootScript(scope waddrmgr.KeyScope,
 		tapscript *waddrmgr.Tapscript) (waddrmgr.ManagedAddress, error)
 
+	// ImportWatchOnly imports a batch of watch-only accounts, public keys
+	// and taproot scripts within a single database transaction, so either
+	// all of them or none of them are imported. The properties of the
+	// imported accounts are returned in the order of the batch, together
+	// with all addresses that were added to the wallet.
+	ImportWatchOnly(batch *WatchOnlyImport) ([]*waddrmgr.AccountProperties,
+		[]btcutil.Address, error)
+
+	// RescanAddresses starts a rescan of the chain for transactions
+	// involving any of the given addresses, beginning at the block with
+	// the given height. The addresses must already be known to the
+	// wallet. The call returns once the rescan has been queued, the
+	// rescan itself happens in the background.
+	RescanAddresses(addrs []btcutil.Address, startHeight int32) error
+
 	// SendOutputs funds, signs, and broadcasts a Bitcoin transaction paying
 	// out to the specified outputs. In the case the wallet has insufficient
 	// funds, or the outputs are non-standard, an error should be returned.
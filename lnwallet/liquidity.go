@@ -0,0 +1,149 @@
+package lnwallet
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// HtlcTotals sums up the HTLCs pending in one direction of a channel.
+type HtlcTotals struct {
+	// NumHtlcs is the number of pending HTLCs.
+	NumHtlcs int
+
+	// Amount is the total amount of the pending HTLCs.
+	Amount lnwire.MilliSatoshi
+}
+
+// LiquiditySnapshot breaks down the liquidity of a channel at a single point
+// in time, based on our latest local commitment.
+type LiquiditySnapshot struct {
+	// LocalBalance is our balance on our latest commitment, after the
+	// pending HTLCs and, if we're the initiator, the commitment fee have
+	// been deducted.
+	LocalBalance lnwire.MilliSatoshi
+
+	// RemoteBalance is the remote party's balance on our latest
+	// commitment, after the pending HTLCs and, if it's the initiator, the
+	// commitment fee have been deducted.
+	RemoteBalance lnwire.MilliSatoshi
+
+	// Spendable is the largest HTLC we could add to the channel right
+	// now, see AvailableBalance.
+	Spendable lnwire.MilliSatoshi
+
+	// Receivable is an estimate of the largest HTLC the remote party
+	// could add to the channel right now, using the same rules as
+	// Spendable from the remote party's point of view.
+	Receivable lnwire.MilliSatoshi
+
+	// PendingOutgoing are the HTLCs we offered on our latest commitment.
+	PendingOutgoing HtlcTotals
+
+	// PendingIncoming are the HTLCs the remote party offered on our latest
+	// commitment.
+	PendingIncoming HtlcTotals
+
+	// CommitFee is the fee of our latest commitment, which is paid by the
+	// channel initiator.
+	CommitFee btcutil.Amount
+
+	// FeeBuffer is the commitment fee the initiator must be able to pay
+	// when another HTLC is added to the commitment. It's withheld from the
+	// initiator's balance when computing what it can spend.
+	FeeBuffer btcutil.Amount
+
+	// LocalReserve is the reserve we must keep in the channel, which is
+	// required by the remote party.
+	LocalReserve btcutil.Amount
+
+	// RemoteReserve is the reserve the remote party must keep in the
+	// channel, which is required by us.
+	RemoteReserve btcutil.Amount
+}
+
+// LiquiditySnapshot returns a breakdown of the liquidity of the channel at
+// this instant.
+func (lc *LightningChannel) LiquiditySnapshot() *LiquiditySnapshot {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	localCommit := lc.localCommitChain.tip()
+	snapshot := &LiquiditySnapshot{
+		LocalBalance:  localCommit.ourBalance,
+		RemoteBalance: localCommit.theirBalance,
+		CommitFee:     localCommit.fee,
+		LocalReserve:  lc.channelState.LocalChanCfg.ChanReserve,
+		RemoteReserve: lc.channelState.RemoteChanCfg.ChanReserve,
+	}
+
+	for _, htlc := range localCommit.outgoingHTLCs {
+		snapshot.PendingOutgoing.NumHtlcs++
+		snapshot.PendingOutgoing.Amount += htlc.Amount
+	}
+	for _, htlc := range localCommit.incomingHTLCs {
+		snapshot.PendingIncoming.NumHtlcs++
+		snapshot.PendingIncoming.Amount += htlc.Amount
+	}
+
+	spendable, commitWeight := lc.availableBalance()
+	snapshot.Spendable = spendable
+	snapshot.FeeBuffer = localCommit.feePerKw.FeeForWeight(
+		commitWeight + input.HTLCWeight,
+	)
+
+	// The remote party is bound by the same constraints when adding an
+	// HTLC, so we'll compute what it could send on both commitments from
+	// all the updates we know of, and report the lower amount.
+	htlcView := lc.fetchHTLCView(
+		lc.remoteUpdateLog.logIndex, lc.localUpdateLog.logIndex,
+	)
+	snapshot.Receivable = lc.receivableCommitmentBalance(htlcView, false)
+	remoteReceivable := lc.receivableCommitmentBalance(htlcView, true)
+	if remoteReceivable < snapshot.Receivable {
+		snapshot.Receivable = remoteReceivable
+	}
+
+	return snapshot
+}
+
+// receivableCommitmentBalance estimates the balance the remote party has
+// available for adding HTLCs on the local/remote commitment given the
+// htlcView. This is the counterpart of availableCommitmentBalance, which only
+// accounts for the reserve and the commitment fee of the remote party.
+func (lc *LightningChannel) receivableCommitmentBalance(view *htlcView,
+	remoteChain bool) lnwire.MilliSatoshi {
+
+	_, theirBalance, commitWeight, filteredView, err := lc.computeView(
+		view, remoteChain, false,
+	)
+	if err != nil {
+		lc.log.Errorf("Unable to fetch receivable balance: %v", err)
+		return 0
+	}
+
+	// The remote party can never spend from its channel reserve.
+	theirReserve := lnwire.NewMSatFromSatoshis(
+		lc.channelState.RemoteChanCfg.ChanReserve,
+	)
+	if theirBalance <= theirReserve {
+		return 0
+	}
+	theirBalance -= theirReserve
+
+	// If the remote party is the initiator, it must also be able to pay
+	// the commitment fee including the HTLC it adds.
+	if !lc.channelState.IsInitiator {
+		htlcCommitFee := lnwire.NewMSatFromSatoshis(
+			filteredView.feePerKw.FeeForWeight(
+				commitWeight + input.HTLCWeight,
+			),
+		)
+		if theirBalance < htlcCommitFee {
+			return 0
+		}
+		theirBalance -= htlcCommitFee
+	}
+
+	return theirBalance
+}
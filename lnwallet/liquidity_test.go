@@ -0,0 +1,91 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLiquiditySnapshot asserts that the liquidity snapshots of both ends of
+// a channel decompose the pending HTLCs and agree on what each party can
+// send.
+func TestLiquiditySnapshot(t *testing.T) {
+	t.Parallel()
+
+	// Create a test channel funded evenly with 5 BTC on each side, which
+	// is initiated by Alice.
+	aliceChannel, bobChannel, cleanUp, err := CreateTestChannels(
+		channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+	defer cleanUp()
+
+	// Add two HTLCs from Alice to Bob, and one from Bob to Alice.
+	htlcAmt := lnwire.NewMSatFromSatoshis(30000)
+	for i := 0; i < 2; i++ {
+		htlc, _ := createHTLC(i, htlcAmt)
+		_, err := aliceChannel.AddHTLC(htlc, nil)
+		require.NoError(t, err)
+		_, err = bobChannel.ReceiveHTLC(htlc)
+		require.NoError(t, err)
+	}
+
+	htlc, _ := createHTLC(0, htlcAmt)
+	_, err = bobChannel.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	_, err = aliceChannel.ReceiveHTLC(htlc)
+	require.NoError(t, err)
+
+	// Lock in the HTLCs on both commitments.
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+	require.NoError(t, ForceStateTransition(bobChannel, aliceChannel))
+
+	aliceSnapshot := aliceChannel.LiquiditySnapshot()
+	bobSnapshot := bobChannel.LiquiditySnapshot()
+
+	require.Equal(t, HtlcTotals{
+		NumHtlcs: 2,
+		Amount:   2 * htlcAmt,
+	}, aliceSnapshot.PendingOutgoing)
+	require.Equal(t, HtlcTotals{
+		NumHtlcs: 1,
+		Amount:   htlcAmt,
+	}, aliceSnapshot.PendingIncoming)
+	require.Equal(
+		t, aliceSnapshot.PendingOutgoing, bobSnapshot.PendingIncoming,
+	)
+	require.Equal(
+		t, aliceSnapshot.PendingIncoming, bobSnapshot.PendingOutgoing,
+	)
+
+	// Bob doesn't pay the commitment fee, so his balance is only reduced
+	// by the HTLCs.
+	require.Equal(
+		t, lnwire.NewMSatFromSatoshis(5*btcutil.SatoshiPerBitcoin)-
+			htlcAmt, aliceSnapshot.RemoteBalance,
+	)
+	require.Equal(
+		t, aliceSnapshot.LocalBalance, bobSnapshot.RemoteBalance,
+	)
+
+	require.Equal(t, aliceChannel.AvailableBalance(), aliceSnapshot.Spendable)
+	require.Equal(t, bobChannel.AvailableBalance(), bobSnapshot.Spendable)
+
+	// What each party can receive is what the other one can send.
+	require.Equal(t, bobSnapshot.Spendable, aliceSnapshot.Receivable)
+	require.Equal(t, aliceSnapshot.Spendable, bobSnapshot.Receivable)
+
+	// As Alice pays the commitment fee, she must keep enough to pay the
+	// fee buffer on top of her reserve.
+	require.Greater(t, aliceSnapshot.FeeBuffer, aliceSnapshot.CommitFee)
+	require.Equal(
+		t, aliceSnapshot.LocalBalance-lnwire.NewMSatFromSatoshis(
+			aliceSnapshot.LocalReserve+aliceSnapshot.FeeBuffer-
+				aliceSnapshot.CommitFee,
+		), aliceSnapshot.Spendable,
+	)
+	require.Equal(t, aliceSnapshot.LocalReserve, bobSnapshot.RemoteReserve)
+}
@@ -192,6 +192,10 @@ type ChannelReservation struct {
 	// commitment state.
 	pushMSat lnwire.MilliSatoshi
 
+	// allowZeroReserve is true if either party may be required to keep a
+	// channel reserve of zero.
+	allowZeroReserve bool
+
 	wallet     *LightningWallet
 	chanFunder chanfunding.Assembler
 
@@ -238,7 +242,9 @@ func NewChannelReservation(capacity, localFundingAmt btcutil.Amount,
 	}
 
 	// Used to cut down on verbosity.
-	defaultDust := wallet.Cfg.DefaultConstraints.DustLimit
+	defaultDust := wallet.Cfg.DustLimits.DustLimit(
+		req.CommitType, wallet.Cfg.DefaultConstraints.DustLimit,
+	)
 
 	// If we're the responder to a single-funder reservation, then we have
 	// no initial balance in the channel unless the remote party is pushing
@@ -384,10 +390,15 @@ func NewChannelReservation(capacity, localFundingAmt btcutil.Amount,
 		chanType |= channeldb.ScidAliasFeatureBit
 	}
 
+	// Our dust limit was selected for the commitment type above. The
+	// remaining constraints are set once our contribution is initialized.
+	ourChanCfg := &channeldb.ChannelConfig{}
+	ourChanCfg.DustLimit = defaultDust
+
 	return &ChannelReservation{
 		ourContribution: &ChannelContribution{
 			FundingAmount: ourBalance.ToSatoshis(),
-			ChannelConfig: &channeldb.ChannelConfig{},
+			ChannelConfig: ourChanCfg,
 		},
 		theirContribution: &ChannelContribution{
 			FundingAmount: theirBalance.ToSatoshis(),
@@ -417,11 +428,12 @@ func NewChannelReservation(capacity, localFundingAmt btcutil.Amount,
 			InitialLocalBalance:  ourBalance,
 			InitialRemoteBalance: theirBalance,
 		},
-		pushMSat:      req.PushMSat,
-		pendingChanID: req.PendingChanID,
-		reservationID: id,
-		wallet:        wallet,
-		chanFunder:    req.ChanFunder,
+		pushMSat:         req.PushMSat,
+		pendingChanID:    req.PendingChanID,
+		reservationID:    id,
+		allowZeroReserve: req.AllowZeroReserve,
+		wallet:           wallet,
+		chanFunder:       req.ChanFunder,
 	}, nil
 }
 
@@ -472,8 +484,10 @@ func (r *ChannelReservation) CommitConstraints(c *channeldb.ChannelConstraints,
 	}
 
 	// The channel reserve should always be greater or equal to the dust
-	// limit. The reservation request should be denied if otherwise.
-	if c.DustLimit > c.ChanReserve {
+	// limit. The reservation request should be denied if otherwise, unless
+	// a zero reserve is acceptable for this channel.
+	zeroReserve := r.allowZeroReserve && c.ChanReserve == 0
+	if c.DustLimit > c.ChanReserve && !zeroReserve {
 		return ErrChanReserveTooSmall(c.ChanReserve, c.DustLimit)
 	}
 
@@ -523,8 +537,11 @@ func (r *ChannelReservation) CommitConstraints(c *channeldb.ChannelConstraints,
 	}
 
 	// Our dust limit should always be less than or equal to our proposed
-	// channel reserve.
-	if responder && r.ourContribution.DustLimit > c.ChanReserve {
+	// channel reserve. A zero reserve is exempt, as our dust limit can't
+	// be lowered that far.
+	if responder && r.ourContribution.DustLimit > c.ChanReserve &&
+		!zeroReserve {
+
 		r.ourContribution.DustLimit = c.ChanReserve
 	}
 
@@ -539,27 +556,32 @@ func (r *ChannelReservation) CommitConstraints(c *channeldb.ChannelConstraints,
 
 // validateReserveBounds checks that both ChannelReserve values are above both
 // DustLimit values. This not only avoids stuck channels, but is also mandated
-// by BOLT#02 even if it's not explicit. This returns true if the bounds are
-// valid. This function should be called with the lock held.
+// by BOLT#02 even if it's not explicit. If zero reserves are allowed for the
+// channel, a ChannelReserve of zero is valid as well. This returns true if the
+// bounds are valid. This function should be called with the lock held.
 func (r *ChannelReservation) validateReserveBounds() bool {
 	ourDustLimit := r.ourContribution.DustLimit
 	ourRequiredReserve := r.ourContribution.ChanReserve
 	theirDustLimit := r.theirContribution.DustLimit
 	theirRequiredReserve := r.theirContribution.ChanReserve
 
-	// We take the smaller of the two ChannelReserves and compare it
-	// against the larger of the two DustLimits.
-	minChanReserve := ourRequiredReserve
-	if minChanReserve > theirRequiredReserve {
-		minChanReserve = theirRequiredReserve
-	}
-
 	maxDustLimit := ourDustLimit
 	if maxDustLimit < theirDustLimit {
 		maxDustLimit = theirDustLimit
 	}
 
-	return minChanReserve >= maxDustLimit
+	// We compare both ChannelReserves against the larger of the two
+	// DustLimits.
+	validReserve := func(reserve btcutil.Amount) bool {
+		if r.allowZeroReserve && reserve == 0 {
+			return true
+		}
+
+		return reserve >= maxDustLimit
+	}
+
+	return validReserve(ourRequiredReserve) &&
+		validReserve(theirRequiredReserve)
 }
 
 // OurContribution returns the wallet's fully populated contribution to the
@@ -632,6 +654,19 @@ func (r *ChannelReservation) RemoteCanceled() {
 	psbtIntent.RemoteCanceled()
 }
 
+// PsbtBatchSigned informs the PSBT funding intent of the reservation that the
+// remote party signed our commitment transaction. If the channel shares its
+// funding transaction with other channels and was the last of them to be
+// signed, the transaction is returned so it can be published.
+func (r *ChannelReservation) PsbtBatchSigned() (*wire.MsgTx, bool) {
+	psbtIntent, ok := r.fundingIntent.(*chanfunding.PsbtIntent)
+	if !ok || !psbtIntent.BatchFundingSigned() {
+		return nil, false
+	}
+
+	return psbtIntent.FinalTX, true
+}
+
 // ProcessSingleContribution verifies, and records the initiator's contribution
 // to this pending single funder channel. Internally, no further action is
 // taken other than recording the initiator's contribution to the single funder
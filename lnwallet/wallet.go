@@ -165,6 +165,10 @@ type InitFundingReserveMsg struct {
 	// negotiated.
 	ScidAliasFeature bool
 
+	// AllowZeroReserve is true if either party may be required to keep a
+	// channel reserve of zero, which is only safe with trusted peers.
+	AllowZeroReserve bool
+
 	// err is a channel in which all errors will be sent across. Will be
 	// nil if this initial set is successful.
 	//
@@ -610,29 +614,191 @@ func (l *LightningWallet) PsbtFundingVerify(pendingChanID [32]byte,
 		return fmt.Errorf("error verifying PSBT: %v", err)
 	}
 
-	// Get the channel reservation for that corresponds to this pending
-	// channel ID.
+	return l.enforcePsbtReservedValue(pendingChanID, intent)
+}
+
+// PsbtFundingVerifyBatch looks up the previously registered funding intents of
+// several pending channels and tries to advance their state machines by
+// verifying that the passed PSBT funds all of them. Either all channels are
+// verified or none is.
+func (l *LightningWallet) PsbtFundingVerifyBatch(pendingChanIDs [][32]byte,
+	packet *psbt.Packet, skipFinalize bool) error {
+
+	l.intentMtx.Lock()
+	defer l.intentMtx.Unlock()
+
+	psbtIntents, err := l.psbtIntents(pendingChanIDs)
+	if err != nil {
+		return err
+	}
+
+	for idx, psbtIntent := range psbtIntents {
+		if skipFinalize && psbtIntent.ShouldPublishFundingTX() {
+			return fmt.Errorf("cannot set skip_finalize for "+
+				"channel that did not set no_publish, "+
+				"pendingChannelID(%x)", pendingChanIDs[idx][:])
+		}
+	}
+
+	// Run all checks before any of the channels is advanced, so that a
+	// batch that fails for one channel doesn't leave the others verified.
+	err = chanfunding.CheckPsbtBatch(psbtIntents, packet)
+	if err != nil {
+		return fmt.Errorf("error verifying PSBT: %v", err)
+	}
+
+	// The value reserved for anchor fee bumping must be respected by the
+	// PSBT as a whole. As the intents aren't verified yet, we check the
+	// reserve against the inputs and outputs of the PSBT directly.
+	enforceReserve := false
+	for idx, psbtIntent := range psbtIntents {
+		isPublic, err := l.psbtReservationIsPublic(pendingChanIDs[idx])
+		if err != nil {
+			return err
+		}
+
+		if isPublic && psbtIntent.LocalFundingAmt() != 0 {
+			enforceReserve = true
+		}
+	}
+	if enforceReserve {
+		numAnchors, err := l.CurrentNumAnchorChans()
+		if err != nil {
+			return err
+		}
+
+		inputs := make([]wire.OutPoint, 0, len(packet.UnsignedTx.TxIn))
+		for _, in := range packet.UnsignedTx.TxIn {
+			inputs = append(inputs, in.PreviousOutPoint)
+		}
+
+		err = l.WithCoinSelectLock(func() error {
+			_, err := l.CheckReservedValue(
+				inputs, packet.UnsignedTx.TxOut, numAnchors,
+			)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = chanfunding.VerifyPsbtBatch(psbtIntents, packet, skipFinalize)
+	if err != nil {
+		return fmt.Errorf("error verifying PSBT: %v", err)
+	}
+
+	// The shared funding transaction is published by the funding manager
+	// once all channels of the batch are signed, so none of the channels
+	// must publish or rebroadcast it on its own.
+	for _, pendingChanID := range pendingChanIDs {
+		pendingReservation, err := l.psbtReservation(pendingChanID)
+		if err != nil {
+			return err
+		}
+
+		pendingReservation.Lock()
+		pendingReservation.partialState.ChanType |=
+			channeldb.NoFundingTxBit
+		pendingReservation.Unlock()
+	}
+
+	return nil
+}
+
+// psbtIntents looks up the PSBT funding intents of the given pending channel
+// IDs. Each pending channel ID may only be given once.
+//
+// NOTE: The intentMtx must be held when calling this method.
+func (l *LightningWallet) psbtIntents(
+	pendingChanIDs [][32]byte) ([]*chanfunding.PsbtIntent, error) {
+
+	if len(pendingChanIDs) == 0 {
+		return nil, fmt.Errorf("no pending channel IDs specified")
+	}
+
+	seen := make(map[[32]byte]struct{}, len(pendingChanIDs))
+	psbtIntents := make([]*chanfunding.PsbtIntent, 0, len(pendingChanIDs))
+	for _, pendingChanID := range pendingChanIDs {
+		if _, ok := seen[pendingChanID]; ok {
+			return nil, fmt.Errorf("duplicate pendingChannelID(%x)",
+				pendingChanID[:])
+		}
+		seen[pendingChanID] = struct{}{}
+
+		intent, ok := l.fundingIntents[pendingChanID]
+		if !ok {
+			return nil, fmt.Errorf("no funding intent found for "+
+				"pendingChannelID(%x)", pendingChanID[:])
+		}
+		psbtIntent, ok := intent.(*chanfunding.PsbtIntent)
+		if !ok {
+			return nil, fmt.Errorf("incompatible funding intent "+
+				"for pendingChannelID(%x)", pendingChanID[:])
+		}
+
+		psbtIntents = append(psbtIntents, psbtIntent)
+	}
+
+	return psbtIntents, nil
+}
+
+// enforcePsbtReservedValue checks that the value reserved for anchor fee
+// bumping is still respected after the PSBT of the given pending channel was
+// verified.
+func (l *LightningWallet) enforcePsbtReservedValue(pendingChanID [32]byte,
+	intent chanfunding.Intent) error {
+
+	isPublic, err := l.psbtReservationIsPublic(pendingChanID)
+	if err != nil {
+		return err
+	}
+
+	// Now the the PSBT has been populated and verified, we can again check
+	// whether the value reserved for anchor fee bumping is respected. As
+	// the reservation is still active, the channel is already accounted
+	// for as an anchor channel being funded, so we don't add it again.
+	return l.enforceNewReservedValue(intent, isPublic, false)
+}
+
+// psbtReservation returns the channel reservation that corresponds to the
+// given pending channel ID.
+func (l *LightningWallet) psbtReservation(
+	pendingChanID [32]byte) (*ChannelReservation, error) {
+
 	l.limboMtx.Lock()
+	defer l.limboMtx.Unlock()
+
 	pid, ok := l.reservationIDs[pendingChanID]
 	if !ok {
-		l.limboMtx.Unlock()
-		return fmt.Errorf("no channel reservation found for "+
+		return nil, fmt.Errorf("no channel reservation found for "+
 			"pendingChannelID(%x)", pendingChanID[:])
 	}
 
 	pendingReservation, ok := l.fundingLimbo[pid]
-	l.limboMtx.Unlock()
-
 	if !ok {
-		return fmt.Errorf("no channel reservation found for "+
+		return nil, fmt.Errorf("no channel reservation found for "+
 			"reservation ID %v", pid)
 	}
 
-	// Now the the PSBT has been populated and verified, we can again check
-	// whether the value reserved for anchor fee bumping is respected.
-	isPublic := pendingReservation.partialState.ChannelFlags&lnwire.FFAnnounceChannel != 0
-	hasAnchors := pendingReservation.partialState.ChanType.HasAnchors()
-	return l.enforceNewReservedValue(intent, isPublic, hasAnchors)
+	return pendingReservation, nil
+}
+
+// psbtReservationIsPublic returns true if the channel reservation that
+// corresponds to the given pending channel ID is for a public channel.
+func (l *LightningWallet) psbtReservationIsPublic(
+	pendingChanID [32]byte) (bool, error) {
+
+	pendingReservation, err := l.psbtReservation(pendingChanID)
+	if err != nil {
+		return false, err
+	}
+
+	pendingReservation.RLock()
+	defer pendingReservation.RUnlock()
+
+	flags := pendingReservation.partialState.ChannelFlags
+	return flags&lnwire.FFAnnounceChannel != 0, nil
 }
 
 // PsbtFundingFinalize looks up a previously registered funding intent by its
@@ -675,6 +841,41 @@ func (l *LightningWallet) PsbtFundingFinalize(pid [32]byte, packet *psbt.Packet,
 	return nil
 }
 
+// PsbtFundingFinalizeBatch looks up the previously registered funding intents
+// of several pending channels that were verified together and tries to advance
+// their state machines by finalizing the passed PSBT or raw transaction.
+func (l *LightningWallet) PsbtFundingFinalizeBatch(pendingChanIDs [][32]byte,
+	packet *psbt.Packet, rawTx *wire.MsgTx) error {
+
+	l.intentMtx.Lock()
+	defer l.intentMtx.Unlock()
+
+	psbtIntents, err := l.psbtIntents(pendingChanIDs)
+	if err != nil {
+		return err
+	}
+
+	// Either the PSBT or the raw TX must be set.
+	switch {
+	case packet != nil && rawTx == nil:
+		err := chanfunding.FinalizePsbtBatch(psbtIntents, packet)
+		if err != nil {
+			return fmt.Errorf("error finalizing PSBT: %v", err)
+		}
+
+	case rawTx != nil && packet == nil:
+		err := chanfunding.FinalizeRawTXBatch(psbtIntents, rawTx)
+		if err != nil {
+			return fmt.Errorf("error finalizing raw TX: %v", err)
+		}
+
+	default:
+		return fmt.Errorf("either a PSBT or raw TX must be specified")
+	}
+
+	return nil
+}
+
 // CancelFundingIntent allows a caller to cancel a previously registered
 // funding intent. If no intent was found, then an error will be returned.
 func (l *LightningWallet) CancelFundingIntent(pid [32]byte) error {
@@ -930,7 +1131,10 @@ func (l *LightningWallet) enforceNewReservedValue(fundingIntent chanfunding.Inte
 }
 
 // CurrentNumAnchorChans returns the current number of non-private anchor
-// channels the wallet should be ready to fee bump if needed.
+// channels the wallet should be ready to fee bump if needed. Besides the
+// channels in the database, this includes the channels whose funding flow is
+// still in flight, so that their reserve can't be swept away before the
+// funding flow completes.
 func (l *LightningWallet) CurrentNumAnchorChans() (int, error) {
 	// Count all anchor channels that are open or pending
 	// open, or waiting close.
@@ -980,9 +1184,35 @@ func (l *LightningWallet) CurrentNumAnchorChans() (int, error) {
 		cntChannel(c)
 	}
 
+	// Finally, we count the anchor channels that are still being funded.
+	numAnchors += l.numPendingAnchorReservations()
+
 	return numAnchors, nil
 }
 
+// numPendingAnchorReservations returns the number of active reservations for
+// non-private anchor channels. Once the funding flow of a reservation
+// completes, the channel is persisted and the reservation removed.
+func (l *LightningWallet) numPendingAnchorReservations() int {
+	l.limboMtx.RLock()
+	defer l.limboMtx.RUnlock()
+
+	var numAnchors int
+	for _, res := range l.fundingLimbo {
+		res.RLock()
+		chanState := res.partialState
+		isPublic := chanState.ChannelFlags&lnwire.FFAnnounceChannel != 0
+		hasAnchors := chanState.ChanType.HasAnchors()
+		res.RUnlock()
+
+		if isPublic && hasAnchors {
+			numAnchors++
+		}
+	}
+
+	return numAnchors
+}
+
 // CheckReservedValue checks whether publishing a transaction with the given
 // inputs and outputs would violate the value we reserve in the wallet for
 // bumping the fee of anchor channels. The numAnchorChans argument should be
@@ -1204,7 +1434,12 @@ func (l *LightningWallet) initOurContribution(reservation *ChannelReservation,
 	)
 
 	reservation.partialState.RevocationProducer = producer
+
+	// The dust limit was already selected for the commitment type of the
+	// channel when the reservation was created, so we keep it.
+	dustLimit := reservation.ourContribution.DustLimit
 	reservation.ourContribution.ChannelConstraints = l.Cfg.DefaultConstraints
+	reservation.ourContribution.DustLimit = dustLimit
 
 	return nil
 }
@@ -1891,10 +2126,52 @@ func (l *LightningWallet) handleFundingCounterPartySigs(msg *addCounterPartySigs
 		return
 	}
 
+	// If we funded the channel from our wallet, we know the value of all
+	// inputs of the funding transaction, so we can account the fee we paid
+	// for it to the channel. Failing to do so doesn't affect the channel
+	// itself, so we only log the error.
+	if fee, ok := fundingTxFee(res.fundingIntent, fundingTx); ok {
+		err := res.partialState.Db.PutChannelFee(
+			res.partialState.FundingOutpoint, fundingTx.TxHash(),
+			0, channeldb.ChannelFeeFunding, fee,
+		)
+		if err != nil {
+			walletLog.Errorf("Unable to record funding fee for "+
+				"ChannelPoint(%v): %v",
+				res.partialState.FundingOutpoint, err)
+		}
+	}
+
 	msg.completeChan <- res.partialState
 	msg.err <- nil
 }
 
+// fundingTxFee returns the fee of the given funding transaction if it was
+// funded by the wallet through the given intent. For other intents, like PSBT
+// funding, we don't know the value of all inputs, so false is returned.
+func fundingTxFee(intent chanfunding.Intent,
+	fundingTx *wire.MsgTx) (btcutil.Amount, bool) {
+
+	fullIntent, ok := intent.(*chanfunding.FullIntent)
+	if !ok || fundingTx == nil {
+		return 0, false
+	}
+
+	var totalIn, totalOut btcutil.Amount
+	for _, coin := range fullIntent.InputCoins {
+		totalIn += btcutil.Amount(coin.Value)
+	}
+	for _, txOut := range fundingTx.TxOut {
+		totalOut += btcutil.Amount(txOut.Value)
+	}
+
+	if totalIn < totalOut {
+		return 0, false
+	}
+
+	return totalIn - totalOut, true
+}
+
 // handleSingleFunderSigs is called once the remote peer who initiated the
 // single funder workflow has assembled the funding transaction, and generated
 // a signature for our version of the commitment transaction. This method
@@ -0,0 +1,52 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNumPendingAnchorReservations asserts that only active reservations of
+// non-private anchor channels are counted as anchor channels being funded.
+func TestNumPendingAnchorReservations(t *testing.T) {
+	t.Parallel()
+
+	newReservation := func(flags lnwire.FundingFlag,
+		chanType channeldb.ChannelType) *ChannelReservation {
+
+		return &ChannelReservation{
+			partialState: &channeldb.OpenChannel{
+				ChannelFlags: flags,
+				ChanType:     chanType,
+			},
+		}
+	}
+
+	anchors := channeldb.SingleFunderTweaklessBit |
+		channeldb.AnchorOutputsBit
+	legacy := channeldb.SingleFunderTweaklessBit
+
+	l := &LightningWallet{
+		fundingLimbo: map[uint64]*ChannelReservation{
+			// A public anchor channel is counted.
+			0: newReservation(lnwire.FFAnnounceChannel, anchors),
+
+			// A private anchor channel isn't counted.
+			1: newReservation(0, anchors),
+
+			// A public legacy channel isn't counted.
+			2: newReservation(lnwire.FFAnnounceChannel, legacy),
+
+			// Another public anchor channel is counted.
+			3: newReservation(lnwire.FFAnnounceChannel, anchors),
+		},
+	}
+	require.Equal(t, 2, l.numPendingAnchorReservations())
+
+	// Once the funding flow of a reservation completes, it is no longer
+	// counted.
+	delete(l.fundingLimbo, 0)
+	require.Equal(t, 1, l.numPendingAnchorReservations())
+}
@@ -105,6 +105,10 @@ type AcceptChannel struct {
 	// type.
 	LeaseExpiry *LeaseExpiry
 
+	// WillFund is the responder's optional answer to a funding request of
+	// the initiator, containing the rates it charges for the lease.
+	WillFund *WillFund
+
 	// ExtraData is the set of data that was appended to this message to
 	// fill out the full maximum transport message size. These fields can
 	// be used to specify optional data such as custom TLV fields.
@@ -134,6 +138,9 @@ func (a *AcceptChannel) Encode(w *bytes.Buffer, pver uint32) error {
 	if a.LeaseExpiry != nil {
 		recordProducers = append(recordProducers, a.LeaseExpiry)
 	}
+	if a.WillFund != nil {
+		recordProducers = append(recordProducers, a.WillFund)
+	}
 	err := EncodeMessageExtraData(&a.ExtraData, recordProducers...)
 	if err != nil {
 		return err
@@ -238,9 +245,11 @@ func (a *AcceptChannel) Decode(r io.Reader, pver uint32) error {
 	var (
 		chanType    ChannelType
 		leaseExpiry LeaseExpiry
+		willFund    WillFund
 	)
 	typeMap, err := tlvRecords.ExtractRecords(
 		&a.UpfrontShutdownScript, &chanType, &leaseExpiry,
+		&willFund,
 	)
 	if err != nil {
 		return err
@@ -253,6 +262,9 @@ func (a *AcceptChannel) Decode(r io.Reader, pver uint32) error {
 	if val, ok := typeMap[LeaseExpiryRecordType]; ok && val == nil {
 		a.LeaseExpiry = &leaseExpiry
 	}
+	if val, ok := typeMap[WillFundRecordType]; ok && val == nil {
+		a.WillFund = &willFund
+	}
 
 	a.ExtraData = tlvRecords
 
@@ -62,6 +62,13 @@ type ChannelReestablish struct {
 	// current un-revoked commitment transaction of the sending party.
 	LocalUnrevokedCommitPoint *btcec.PublicKey
 
+	// NextFundingTxid is an optional TLV field that is set if the sender
+	// has sent its commitment signatures for an interactively constructed
+	// funding (or splice) transaction, but hasn't received the
+	// tx_signatures of the receiver yet. It indicates that the signing of
+	// that transaction should be resumed.
+	NextFundingTxid *NextFundingTxid
+
 	// ExtraData is the set of data that was appended to this message to
 	// fill out the full maximum transport message size. These fields can
 	// be used to specify optional data such as custom TLV fields.
@@ -108,6 +115,28 @@ func (a *ChannelReestablish) Encode(w *bytes.Buffer, pver uint32) error {
 	if err := WritePublicKey(w, a.LocalUnrevokedCommitPoint); err != nil {
 		return err
 	}
+
+	// We'll only encode the NextFundingTxid in a TLV segment if it
+	// exists. Any unknown odd records of the existing extension are
+	// carried over, while a NextFundingTxid it contains is replaced.
+	if a.NextFundingTxid != nil {
+		var nextFundingTxid NextFundingTxid
+		extraData, err := NewExtraData(&nextFundingTxid)
+		if err != nil {
+			return err
+		}
+
+		if _, err := extraData.Decode(a.ExtraData); err != nil {
+			return err
+		}
+
+		nextFundingTxid = *a.NextFundingTxid
+		a.ExtraData, err = extraData.Encode()
+		if err != nil {
+			return err
+		}
+	}
+
 	return WriteBytes(w, a.ExtraData)
 }
 
@@ -156,7 +185,31 @@ func (a *ChannelReestablish) Decode(r io.Reader, pver uint32) error {
 		return err
 	}
 
-	return a.ExtraData.Decode(r)
+	if err := a.ExtraData.Decode(r); err != nil {
+		return err
+	}
+
+	// Next we'll parse out the set of known records. For now, this is just
+	// the NextFundingTxidRecordType. Unknown even records are rejected, as
+	// we'd fail to act on something the peer requires us to understand.
+	var nextFundingTxid NextFundingTxid
+	extraData, err := NewExtraData(&nextFundingTxid)
+	if err != nil {
+		return err
+	}
+
+	typeMap, err := extraData.Decode(a.ExtraData)
+	if err != nil {
+		return err
+	}
+
+	// We'll only set NextFundingTxid if the corresponding TLV type was
+	// included in the stream.
+	if val, ok := typeMap[NextFundingTxidRecordType]; ok && val == nil {
+		a.NextFundingTxid = &nextFundingTxid
+	}
+
+	return nil
 }
 
 // MsgType returns the integer uniquely identifying this message type on the
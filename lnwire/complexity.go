@@ -0,0 +1,195 @@
+package lnwire
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// DefaultMaxTLVRecords is the default maximum number of TLV records in
+	// the extension of a single message.
+	DefaultMaxTLVRecords = 100
+
+	// DefaultMaxAddresses is the default maximum number of addresses in a
+	// single node announcement.
+	DefaultMaxAddresses = 64
+
+	// DefaultMaxShortChanIDs is the default maximum number of short channel
+	// IDs in a single query or reply. It is above the number of short
+	// channel IDs that fit into a message without compression, so only
+	// compressed payloads are affected by it.
+	DefaultMaxShortChanIDs = 16384
+)
+
+// ComplexityLimits caps the number of items a single message may contain.
+// Messages within the size limit of the protocol can still contain large
+// numbers of items, for example a compressed reply_channel_range, which are
+// costly to decode and process. A zero value disables the respective limit.
+type ComplexityLimits struct {
+	// MaxTLVRecords is the maximum number of TLV records in the extension
+	// of a message.
+	MaxTLVRecords uint32
+
+	// MaxAddresses is the maximum number of addresses in a message.
+	MaxAddresses uint32
+
+	// MaxShortChanIDs is the maximum number of short channel IDs in a
+	// message.
+	MaxShortChanIDs uint32
+}
+
+// DefaultComplexityLimits returns the default limits of the number of items
+// in a message.
+func DefaultComplexityLimits() ComplexityLimits {
+	return ComplexityLimits{
+		MaxTLVRecords:   DefaultMaxTLVRecords,
+		MaxAddresses:    DefaultMaxAddresses,
+		MaxShortChanIDs: DefaultMaxShortChanIDs,
+	}
+}
+
+// complexityItem is a kind of item whose number within a message is limited.
+type complexityItem uint8
+
+const (
+	itemTLVRecord complexityItem = iota
+	itemAddress
+	itemShortChanID
+
+	numComplexityItems
+)
+
+// String returns a human readable name of the item.
+func (i complexityItem) String() string {
+	switch i {
+	case itemTLVRecord:
+		return "TLV records"
+	case itemAddress:
+		return "addresses"
+	case itemShortChanID:
+		return "short channel IDs"
+	default:
+		return "unknown items"
+	}
+}
+
+// ErrComplexityLimit is returned when a message contains more items of a
+// kind than the limits of the decoder allow. The message was read entirely,
+// so the following messages can still be decoded.
+type ErrComplexityLimit struct {
+	// MsgType is the type of the rejected message.
+	MsgType MessageType
+
+	// Item is the name of the kind of items that exceeded the limit.
+	Item string
+
+	// Limit is the limit that was exceeded.
+	Limit uint32
+}
+
+// Error returns a human readable description of the error.
+//
+// NOTE: This is part of the error interface.
+func (e *ErrComplexityLimit) Error() string {
+	return fmt.Sprintf("%v message exceeds limit of %d %v", e.MsgType,
+		e.Limit, e.Item)
+}
+
+// messageComplexity counts the items of the message that is being decoded.
+type messageComplexity struct {
+	limits ComplexityLimits
+	counts [numComplexityItems]uint32
+}
+
+// limit returns the limit of the given item, zero if it is unlimited.
+func (c *messageComplexity) limit(item complexityItem) uint32 {
+	switch item {
+	case itemTLVRecord:
+		return c.limits.MaxTLVRecords
+	case itemAddress:
+		return c.limits.MaxAddresses
+	case itemShortChanID:
+		return c.limits.MaxShortChanIDs
+	default:
+		return 0
+	}
+}
+
+// add counts n more items of the given kind, and returns an error if that
+// exceeds their limit.
+func (c *messageComplexity) add(item complexityItem, n int) error {
+	limit := c.limit(item)
+	if limit == 0 {
+		return nil
+	}
+
+	c.counts[item] += uint32(n)
+	if c.counts[item] > limit {
+		return &ErrComplexityLimit{
+			Item:  item.String(),
+			Limit: limit,
+		}
+	}
+
+	return nil
+}
+
+// reset clears the counts before the next message is decoded.
+func (c *messageComplexity) reset() {
+	c.counts = [numComplexityItems]uint32{}
+}
+
+// countItems accounts n items of the given kind of the message that is being
+// decoded from r. It only has an effect if r is the reader of a Decoder with
+// complexity limits, which is checked before the items are decoded so that
+// pathological messages are rejected early.
+func countItems(r io.Reader, item complexityItem, n int) error {
+	d, ok := r.(*decodeReader)
+	if !ok || d.complexity == nil {
+		return nil
+	}
+
+	return d.complexity.add(item, n)
+}
+
+// countTLVRecords accounts the TLV records of the raw TLV stream that is
+// decoded from r. The stream is only parsed if the records are limited, and
+// as far as it's well formed, malformed streams are rejected once they are
+// extracted.
+func countTLVRecords(r io.Reader, stream []byte) error {
+	d, ok := r.(*decodeReader)
+	if !ok || d.complexity == nil ||
+		d.complexity.limits.MaxTLVRecords == 0 {
+
+		return nil
+	}
+
+	var (
+		streamReader = bytes.NewReader(stream)
+		scratch      [8]byte
+	)
+	for streamReader.Len() > 0 {
+		if _, err := tlv.ReadVarInt(streamReader, &scratch); err != nil {
+			return nil
+		}
+		length, err := tlv.ReadVarInt(streamReader, &scratch)
+		if err != nil || length > uint64(streamReader.Len()) {
+			return nil
+		}
+
+		if err := d.complexity.add(itemTLVRecord, 1); err != nil {
+			return err
+		}
+
+		if _, err := streamReader.Seek(
+			int64(length), io.SeekCurrent,
+		); err != nil {
+			return nil
+		}
+	}
+
+	return nil
+}
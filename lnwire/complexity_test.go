@@ -0,0 +1,141 @@
+package lnwire_test
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDecoderComplexityLimits asserts that a decoder with complexity limits
+// rejects messages that contain more items than allowed, and that it keeps
+// decoding the following messages.
+func TestDecoderComplexityLimits(t *testing.T) {
+	t.Parallel()
+
+	scids := make([]lnwire.ShortChannelID, 10)
+	for i := range scids {
+		scids[i] = lnwire.NewShortChanIDFromInt(uint64(i + 1))
+	}
+
+	replyRange := func(
+		encoding lnwire.ShortChanIDEncoding) lnwire.Message {
+
+		return &lnwire.ReplyChannelRange{
+			EncodingType: encoding,
+			ShortChanIDs: scids,
+			ExtraData:    make([]byte, 0),
+		}
+	}
+
+	addrs := []net.Addr{
+		&net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 9735},
+		&net.TCPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 9735},
+		&net.TCPAddr{IP: net.IPv4(10, 0, 0, 3), Port: 9735},
+	}
+	nodeAnn := &lnwire.NodeAnnouncement{
+		Features:        lnwire.NewRawFeatureVector(),
+		Addresses:       addrs,
+		ExtraOpaqueData: make([]byte, 0),
+	}
+
+	// The extension of the channel update holds three TLV records of
+	// types 1, 3 and 5, with one byte of value each.
+	chanUpdate := &lnwire.ChannelUpdate{
+		ExtraOpaqueData: []byte{1, 1, 0, 3, 1, 0, 5, 1, 0},
+	}
+
+	testCases := []struct {
+		name   string
+		msg    lnwire.Message
+		limits lnwire.ComplexityLimits
+		item   string
+	}{
+		{
+			name:   "plain short channel ids",
+			msg:    replyRange(lnwire.EncodingSortedPlain),
+			limits: lnwire.ComplexityLimits{MaxShortChanIDs: 9},
+			item:   "short channel IDs",
+		},
+		{
+			name:   "zlib short channel ids",
+			msg:    replyRange(lnwire.EncodingSortedZlib),
+			limits: lnwire.ComplexityLimits{MaxShortChanIDs: 9},
+			item:   "short channel IDs",
+		},
+		{
+			name:   "addresses",
+			msg:    nodeAnn,
+			limits: lnwire.ComplexityLimits{MaxAddresses: 2},
+			item:   "addresses",
+		},
+		{
+			name:   "tlv records",
+			msg:    chanUpdate,
+			limits: lnwire.ComplexityLimits{MaxTLVRecords: 2},
+			item:   "TLV records",
+		},
+	}
+
+	// The ping that is decoded after each rejected message must not be
+	// affected by the counts of the previous message.
+	ping := &lnwire.Ping{PaddingBytes: make([]byte, 0)}
+
+	encode := func(msg lnwire.Message) []byte {
+		var buf bytes.Buffer
+		_, err := lnwire.WriteMessage(&buf, msg, 0)
+		require.NoError(t, err)
+
+		return buf.Bytes()
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			reader := bytes.NewReader(nil)
+
+			// Without limits, the message is decoded.
+			decoder := lnwire.NewDecoder(reader)
+			reader.Reset(encode(test.msg))
+			_, err := decoder.ReadMessage(0)
+			require.NoError(t, err)
+
+			// With limits that exactly fit the message, it is
+			// decoded as well.
+			limits := test.limits
+			limits.MaxShortChanIDs++
+			limits.MaxAddresses++
+			limits.MaxTLVRecords++
+			decoder = lnwire.NewDecoder(
+				reader, lnwire.WithComplexityLimits(limits),
+			)
+			reader.Reset(encode(test.msg))
+			_, err = decoder.ReadMessage(0)
+			require.NoError(t, err)
+
+			// With tighter limits, it is rejected.
+			decoder = lnwire.NewDecoder(
+				reader, lnwire.WithComplexityLimits(
+					test.limits,
+				),
+			)
+			reader.Reset(encode(test.msg))
+			_, err = decoder.ReadMessage(0)
+
+			var limitErr *lnwire.ErrComplexityLimit
+			require.ErrorAs(t, err, &limitErr)
+			require.Equal(t, test.msg.MsgType(), limitErr.MsgType)
+			require.Equal(t, test.item, limitErr.Item)
+
+			// The next message is decoded as usual.
+			reader.Reset(encode(ping))
+			_, err = decoder.ReadMessage(0)
+			require.NoError(t, err)
+		})
+	}
+}
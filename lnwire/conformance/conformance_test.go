@@ -0,0 +1,271 @@
+package conformance
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+)
+
+// vectorFile is a file of serialized test vectors of a message type.
+type vectorFile struct {
+	// Description describes how the vectors were produced.
+	Description string `json:"description"`
+
+	Vectors []*vector `json:"vectors"`
+}
+
+// vector is a serialized message together with the fields a conforming
+// decoder parses from it.
+type vector struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	// Hex is the serialized message, including its type.
+	Hex string `json:"hex"`
+
+	// Valid is false if conforming decoders must reject the message.
+	Valid bool `json:"valid"`
+
+	// KnownDivergence describes why lnwire doesn't handle the vector the
+	// way the specification requires. Vectors with a known divergence
+	// are asserted to still diverge, so that they are revisited once
+	// lnwire is fixed.
+	KnownDivergence string `json:"known_divergence"`
+
+	ChannelID    string `json:"channel_id"`
+	FeeSatoshis  uint64 `json:"fee_satoshis"`
+	Signature    string `json:"signature"`
+	ScriptPubKey string `json:"script_pubkey"`
+
+	FeeRange *struct {
+		MinFeeSatoshis uint64 `json:"min_fee_satoshis"`
+		MaxFeeSatoshis uint64 `json:"max_fee_satoshis"`
+	} `json:"fee_range"`
+}
+
+// loadVectors reads the test vectors of the given message type from all files
+// in the testdata directory whose name starts with it.
+func loadVectors(t testing.TB, msgType string) []*vector {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join("testdata", msgType+"*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	var vectors []*vector
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		var vectorFile vectorFile
+		require.NoError(t, json.Unmarshal(b, &vectorFile))
+		require.NotEmpty(t, vectorFile.Description, file)
+		require.NotEmpty(t, vectorFile.Vectors, file)
+
+		vectors = append(vectors, vectorFile.Vectors...)
+	}
+
+	return vectors
+}
+
+// rawMessage returns the serialized message of the vector.
+func (v *vector) rawMessage(t testing.TB) []byte {
+	t.Helper()
+
+	raw, err := hex.DecodeString(v.Hex)
+	require.NoError(t, err)
+
+	return raw
+}
+
+// Record returns a TLV record that decodes the fee_range record of
+// closing_signed with the tlv package, which lnwire uses for all of its TLV
+// extensions. lnwire itself doesn't decode the fee_range record yet.
+func (f *feeRange) Record() tlv.Record {
+	return tlv.MakeStaticRecord(
+		feeRangeType, f, feeRangeLen, tlv.ENOP,
+		func(r io.Reader, val interface{}, buf *[8]byte,
+			l uint64) error {
+
+			v, ok := val.(*feeRange)
+			if !ok || l != feeRangeLen {
+				return tlv.NewTypeForDecodingErr(
+					val, "feeRange", l, feeRangeLen,
+				)
+			}
+
+			err := tlv.DUint64(r, &v.MinFeeSatoshis, buf, 8)
+			if err != nil {
+				return err
+			}
+
+			return tlv.DUint64(r, &v.MaxFeeSatoshis, buf, 8)
+		},
+	)
+}
+
+// lnwireFeeRange extracts the fee_range record from the TLV extension of a
+// closing_signed message decoded by lnwire.
+func lnwireFeeRange(msg *lnwire.ClosingSigned) (*feeRange, error) {
+	var fr feeRange
+	typeMap, err := msg.ExtraData.ExtractRecords(&fr)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := typeMap[feeRangeType]; !ok {
+		return nil, nil
+	}
+
+	return &fr, nil
+}
+
+// decodeLnwire decodes the serialized message with lnwire, including the
+// records of its TLV extension.
+func decodeLnwire(raw []byte) (lnwire.Message, error) {
+	msg, err := lnwire.ReadMessage(bytes.NewReader(raw), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	switch msg := msg.(type) {
+	case *lnwire.ClosingSigned:
+		_, err = lnwireFeeRange(msg)
+
+	case *lnwire.Shutdown:
+		_, err = msg.ExtraData.ExtractRecords()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// TestClosingSignedVectors asserts that lnwire and the reference decoder
+// decode the closing_signed test vectors as expected.
+func TestClosingSignedVectors(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range loadVectors(t, "closing_signed") {
+		v := v
+		t.Run(v.Name, func(t *testing.T) {
+			t.Parallel()
+
+			raw := v.rawMessage(t)
+			require.Equal(t, uint16(lnwire.MsgClosingSigned),
+				uint16(raw[0])<<8|uint16(raw[1]))
+
+			// The reference decoder serves as a sanity check of
+			// the vector itself.
+			ref, err := decodeClosingSigned(raw[2:])
+			if err == nil {
+				_, err = ref.FeeRange()
+			}
+			require.Equal(t, v.Valid, err == nil, "reference: %v",
+				err)
+
+			msg, err := decodeLnwire(raw)
+			if v.KnownDivergence != "" {
+				require.NotEqual(
+					t, v.Valid, err == nil,
+					"divergence fixed: %v",
+					v.KnownDivergence,
+				)
+				return
+			}
+
+			if !v.Valid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			closingSigned := msg.(*lnwire.ClosingSigned)
+			require.Equal(
+				t, v.ChannelID,
+				hex.EncodeToString(closingSigned.ChannelID[:]),
+			)
+			require.Equal(
+				t, v.FeeSatoshis,
+				uint64(closingSigned.FeeSatoshis),
+			)
+			require.Equal(
+				t, v.Signature,
+				hex.EncodeToString(closingSigned.Signature[:]),
+			)
+
+			fr, err := lnwireFeeRange(closingSigned)
+			require.NoError(t, err)
+			if v.FeeRange == nil {
+				require.Nil(t, fr)
+				return
+			}
+
+			require.Equal(t, &feeRange{
+				MinFeeSatoshis: v.FeeRange.MinFeeSatoshis,
+				MaxFeeSatoshis: v.FeeRange.MaxFeeSatoshis,
+			}, fr)
+		})
+	}
+}
+
+// TestShutdownVectors asserts that lnwire and the reference decoder decode
+// the shutdown test vectors as expected.
+func TestShutdownVectors(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range loadVectors(t, "shutdown") {
+		v := v
+		t.Run(v.Name, func(t *testing.T) {
+			t.Parallel()
+
+			raw := v.rawMessage(t)
+			require.Equal(t, uint16(lnwire.MsgShutdown),
+				uint16(raw[0])<<8|uint16(raw[1]))
+
+			// The reference decoder serves as a sanity check of
+			// the vector itself.
+			ref, err := decodeShutdown(raw[2:])
+			if err == nil {
+				err = ref.ValidateTLVs()
+			}
+			require.Equal(t, v.Valid, err == nil, "reference: %v",
+				err)
+
+			msg, err := decodeLnwire(raw)
+			if v.KnownDivergence != "" {
+				require.NotEqual(
+					t, v.Valid, err == nil,
+					"divergence fixed: %v",
+					v.KnownDivergence,
+				)
+				return
+			}
+
+			if !v.Valid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			shutdown := msg.(*lnwire.Shutdown)
+			require.Equal(
+				t, v.ChannelID,
+				hex.EncodeToString(shutdown.ChannelID[:]),
+			)
+			require.Equal(
+				t, v.ScriptPubKey,
+				hex.EncodeToString(shutdown.Address),
+			)
+		})
+	}
+}
@@ -0,0 +1,6 @@
+// Package conformance checks the lnwire decoders against serialized test
+// vectors and against reference decoders that are written directly from the
+// BOLT message definitions. The reference decoders deliberately share no code
+// with lnwire, so that fuzzing both with the same input surfaces silent
+// divergences between lnwire and the specification.
+package conformance
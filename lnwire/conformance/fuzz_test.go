@@ -0,0 +1,121 @@
+package conformance
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// seedVectors adds the payloads of the test vectors of the given message type
+// to the seed corpus of the fuzz target. The message type is stripped, as the
+// fuzz targets add it themselves.
+func seedVectors(f *testing.F, msgType string) {
+	f.Helper()
+
+	for _, v := range loadVectors(f, msgType) {
+		f.Add(v.rawMessage(f)[2:])
+	}
+}
+
+// prefixWithMsgType prepends the given message type to the payload.
+func prefixWithMsgType(payload []byte, msgType lnwire.MessageType) []byte {
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], uint16(msgType))
+
+	return append(b[:], payload...)
+}
+
+// FuzzClosingSignedDifferential asserts that lnwire decodes closing_signed
+// messages the same way as the reference decoder.
+func FuzzClosingSignedDifferential(f *testing.F) {
+	seedVectors(f, "closing_signed")
+
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		raw := prefixWithMsgType(payload, lnwire.MsgClosingSigned)
+		msg, err := lnwire.ReadMessage(bytes.NewReader(raw), 0)
+
+		ref, refErr := decodeClosingSigned(payload)
+		if refErr != nil {
+			require.Error(t, err, "lnwire accepted message "+
+				"rejected by reference: %v", refErr)
+			return
+		}
+		require.NoError(t, err)
+
+		closingSigned := msg.(*lnwire.ClosingSigned)
+		require.Equal(
+			t, ref.ChannelID, [32]byte(closingSigned.ChannelID),
+		)
+		require.Equal(t, ref.FeeSatoshis,
+			uint64(closingSigned.FeeSatoshis))
+		require.Equal(t, ref.Signature[:], closingSigned.Signature[:])
+		require.Equal(t, ref.TLVs, []byte(closingSigned.ExtraData))
+
+		refRange, refErr := ref.FeeRange()
+		fr, err := lnwireFeeRange(closingSigned)
+		switch {
+		// lnwire doesn't reject unknown even records in the TLV
+		// extension, so it may accept them.
+		case errors.Is(refErr, errUnknownEvenType):
+
+		case refErr != nil:
+			require.Error(t, err, "lnwire accepted TLV extension "+
+				"rejected by reference: %v", refErr)
+
+		default:
+			require.NoError(t, err)
+			require.Equal(t, refRange, fr)
+		}
+	})
+}
+
+// FuzzShutdownDifferential asserts that lnwire decodes shutdown messages the
+// same way as the reference decoder.
+func FuzzShutdownDifferential(f *testing.F) {
+	seedVectors(f, "shutdown")
+
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		raw := prefixWithMsgType(payload, lnwire.MsgShutdown)
+		msg, err := lnwire.ReadMessage(bytes.NewReader(raw), 0)
+
+		ref, refErr := decodeShutdown(payload)
+		switch {
+		case refErr != nil:
+			require.Error(t, err, "lnwire accepted message "+
+				"rejected by reference: %v", refErr)
+			return
+
+		// lnwire caps delivery addresses at 34 bytes, which excludes
+		// the longer witness programs allowed by
+		// option_shutdown_anysegwit.
+		case len(ref.ScriptPubKey) > 34:
+			require.Error(t, err)
+			return
+		}
+		require.NoError(t, err)
+
+		shutdown := msg.(*lnwire.Shutdown)
+		require.Equal(t, ref.ChannelID, [32]byte(shutdown.ChannelID))
+		require.Equal(t, ref.ScriptPubKey, []byte(shutdown.Address))
+		require.Equal(t, ref.TLVs, []byte(shutdown.ExtraData))
+
+		refErr = ref.ValidateTLVs()
+		_, err = shutdown.ExtraData.ExtractRecords()
+		switch {
+		// lnwire doesn't reject unknown even records in the TLV
+		// extension, so it may accept them.
+		case errors.Is(refErr, errUnknownEvenType):
+
+		case refErr != nil:
+			require.Error(t, err, "lnwire accepted TLV extension "+
+				"rejected by reference: %v", refErr)
+
+		default:
+			require.NoError(t, err)
+		}
+	})
+}
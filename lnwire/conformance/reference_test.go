@@ -0,0 +1,275 @@
+package conformance
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	// feeRangeType is the TLV type of the fee_range record of
+	// closing_signed.
+	feeRangeType = 1
+
+	// feeRangeLen is the length of the fee_range record.
+	feeRangeLen = 16
+)
+
+var (
+	// errTruncated is returned when a message ends before all of its
+	// fields were read.
+	errTruncated = errors.New("message truncated")
+
+	// errNonMinimalBigSize is returned when a BigSize integer isn't
+	// encoded in its shortest form.
+	errNonMinimalBigSize = errors.New("non-minimal bigsize encoding")
+
+	// errTLVNotAscending is returned when the records of a TLV stream
+	// aren't ordered by strictly increasing type.
+	errTLVNotAscending = errors.New("tlv types not strictly increasing")
+
+	// errUnknownEvenType is returned when a TLV stream contains a record
+	// of an unknown even type.
+	errUnknownEvenType = errors.New("unknown even tlv type")
+)
+
+// reader reads the fields of a message in the order the BOLTs define them.
+type reader struct {
+	b []byte
+}
+
+// read returns the next n bytes of the message.
+func (r *reader) read(n int) ([]byte, error) {
+	if len(r.b) < n {
+		return nil, errTruncated
+	}
+
+	field := r.b[:n]
+	r.b = r.b[n:]
+
+	return field, nil
+}
+
+// u16 reads a big endian two byte integer.
+func (r *reader) u16() (uint16, error) {
+	b, err := r.read(2)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint16(b), nil
+}
+
+// u64 reads a big endian eight byte integer.
+func (r *reader) u64() (uint64, error) {
+	b, err := r.read(8)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// bigSize reads a BigSize integer, rejecting non-minimal encodings as
+// required by BOLT 1.
+func (r *reader) bigSize() (uint64, error) {
+	prefix, err := r.read(1)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		n   int
+		min uint64
+	)
+	switch prefix[0] {
+	case 0xfd:
+		n, min = 2, 0xfd
+
+	case 0xfe:
+		n, min = 4, 0x10000
+
+	case 0xff:
+		n, min = 8, 0x100000000
+
+	default:
+		return uint64(prefix[0]), nil
+	}
+
+	b, err := r.read(n)
+	if err != nil {
+		return 0, err
+	}
+
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+
+	if v < min {
+		return 0, errNonMinimalBigSize
+	}
+
+	return v, nil
+}
+
+// parseTLVStream parses a TLV stream as defined by BOLT 1. Records of the
+// known types are returned, the stream is rejected if it contains a record of
+// an unknown even type.
+func parseTLVStream(stream []byte,
+	known map[uint64]bool) (map[uint64][]byte, error) {
+
+	var (
+		r        = &reader{b: stream}
+		records  = make(map[uint64][]byte)
+		prevType uint64
+	)
+	for i := 0; len(r.b) > 0; i++ {
+		typ, err := r.bigSize()
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 && typ <= prevType {
+			return nil, errTLVNotAscending
+		}
+		prevType = typ
+
+		length, err := r.bigSize()
+		if err != nil {
+			return nil, err
+		}
+		if length > uint64(len(r.b)) {
+			return nil, errTruncated
+		}
+
+		value, _ := r.read(int(length))
+
+		switch {
+		case known[typ]:
+			records[typ] = value
+
+		case typ%2 == 0:
+			return nil, fmt.Errorf("%w: %d", errUnknownEvenType,
+				typ)
+		}
+	}
+
+	return records, nil
+}
+
+// feeRange is the fee_range record of closing_signed.
+type feeRange struct {
+	MinFeeSatoshis uint64
+	MaxFeeSatoshis uint64
+}
+
+// closingSigned is the reference decoding of a closing_signed message.
+type closingSigned struct {
+	ChannelID   [32]byte
+	FeeSatoshis uint64
+	Signature   [64]byte
+
+	// TLVs is the raw TLV extension of the message.
+	TLVs []byte
+}
+
+// decodeClosingSigned decodes the payload of a closing_signed message, which
+// excludes the message type.
+func decodeClosingSigned(payload []byte) (*closingSigned, error) {
+	var (
+		r   = &reader{b: payload}
+		msg closingSigned
+	)
+
+	chanID, err := r.read(32)
+	if err != nil {
+		return nil, err
+	}
+	copy(msg.ChannelID[:], chanID)
+
+	if msg.FeeSatoshis, err = r.u64(); err != nil {
+		return nil, err
+	}
+
+	sig, err := r.read(64)
+	if err != nil {
+		return nil, err
+	}
+	copy(msg.Signature[:], sig)
+
+	msg.TLVs = r.b
+
+	return &msg, nil
+}
+
+// FeeRange parses the TLV extension of the message and returns its fee_range
+// record, or nil if the message has none.
+func (c *closingSigned) FeeRange() (*feeRange, error) {
+	records, err := parseTLVStream(
+		c.TLVs, map[uint64]bool{feeRangeType: true},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := records[feeRangeType]
+	if !ok {
+		return nil, nil
+	}
+
+	if len(value) != feeRangeLen {
+		return nil, fmt.Errorf("fee_range has length %d, expected %d",
+			len(value), feeRangeLen)
+	}
+
+	return &feeRange{
+		MinFeeSatoshis: binary.BigEndian.Uint64(value[:8]),
+		MaxFeeSatoshis: binary.BigEndian.Uint64(value[8:]),
+	}, nil
+}
+
+// shutdown is the reference decoding of a shutdown message.
+type shutdown struct {
+	ChannelID    [32]byte
+	ScriptPubKey []byte
+
+	// TLVs is the raw TLV extension of the message.
+	TLVs []byte
+}
+
+// decodeShutdown decodes the payload of a shutdown message, which excludes
+// the message type.
+func decodeShutdown(payload []byte) (*shutdown, error) {
+	var (
+		r   = &reader{b: payload}
+		msg shutdown
+	)
+
+	chanID, err := r.read(32)
+	if err != nil {
+		return nil, err
+	}
+	copy(msg.ChannelID[:], chanID)
+
+	scriptLen, err := r.u16()
+	if err != nil {
+		return nil, err
+	}
+
+	if msg.ScriptPubKey, err = r.read(int(scriptLen)); err != nil {
+		return nil, err
+	}
+
+	msg.TLVs = r.b
+
+	return &msg, nil
+}
+
+// ValidateTLVs parses the TLV extension of the message. As shutdown defines
+// no records, the extension is only checked to be a well-formed stream
+// without unknown even records.
+func (s *shutdown) ValidateTLVs() error {
+	_, err := parseTLVStream(s.TLVs, nil)
+	return err
+}
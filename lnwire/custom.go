@@ -2,7 +2,6 @@ package lnwire
 
 import (
 	"bytes"
-	"errors"
 	"io"
 )
 
@@ -23,7 +22,7 @@ var _ Message = (*Custom)(nil)
 // NewCustom instanties a new custom message.
 func NewCustom(msgType MessageType, data []byte) (*Custom, error) {
 	if msgType < CustomTypeStart {
-		return nil, errors.New("msg type not in custom range")
+		return nil, ErrCustomTypeRange
 	}
 
 	return &Custom{
@@ -0,0 +1,277 @@
+package lnwire
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+var (
+	// ErrCustomTypeRange is returned when a message type outside of the
+	// custom range is registered.
+	ErrCustomTypeRange = errors.New("msg type not in custom range")
+
+	// ErrCustomTypeEven is returned when an even message type is
+	// registered. Peers that don't know the message would disconnect us
+	// when receiving an even type, so only odd types can be registered.
+	ErrCustomTypeEven = errors.New("only odd custom msg types can be " +
+		"registered")
+
+	// ErrCustomTypeRegistered is returned when a message type is
+	// registered twice.
+	ErrCustomTypeRegistered = errors.New("custom msg type already " +
+		"registered")
+)
+
+// ErrInvalidCustomMessage is returned when a received message of a registered
+// custom message type can't be decoded, exceeds the maximum payload of the type
+// or is rejected by its decode hook. As custom message types are odd, the
+// message can be ignored and the connection to the peer kept.
+type ErrInvalidCustomMessage struct {
+	// MsgType is the type of the invalid message.
+	MsgType MessageType
+
+	// Err is the reason the message is invalid.
+	Err error
+}
+
+// Error returns a human readable string describing the error.
+//
+// NOTE: This is part of the error interface.
+func (e *ErrInvalidCustomMessage) Error() string {
+	return fmt.Sprintf("invalid custom message of type %d: %v",
+		uint16(e.MsgType), e.Err)
+}
+
+// Unwrap returns the reason the message is invalid.
+func (e *ErrInvalidCustomMessage) Unwrap() error {
+	return e.Err
+}
+
+// CustomMessageFactory returns an empty instance of a custom message, which
+// the payload of a received message is decoded into.
+type CustomMessageFactory func() Message
+
+// CustomMessageHook is called with a custom message before it is encoded or
+// after it has been decoded. An error aborts the encoding or decoding of the
+// message, which allows applications to validate their messages.
+type CustomMessageHook func(Message) error
+
+// customMessageType houses the registration of a custom message type.
+type customMessageType struct {
+	// factory creates the empty messages that received messages are
+	// decoded into.
+	factory CustomMessageFactory
+
+	// maxPayload is the maximum size of the payload of the message. If
+	// zero, only MaxMsgBody applies.
+	maxPayload int
+
+	// encodeHook is called before a message is encoded, if set.
+	encodeHook CustomMessageHook
+
+	// decodeHook is called after a message has been decoded, if set.
+	decodeHook CustomMessageHook
+}
+
+// CustomMessageOption is a functional option that modifies the registration of
+// a custom message type.
+type CustomMessageOption func(*customMessageType)
+
+// WithMaxPayload limits the size of the payload of a custom message type. Both
+// sending and receiving a larger message fails.
+func WithMaxPayload(maxPayload int) CustomMessageOption {
+	return func(c *customMessageType) {
+		c.maxPayload = maxPayload
+	}
+}
+
+// WithEncodeHook sets a hook that is called with every message of a custom
+// message type before it is encoded.
+func WithEncodeHook(hook CustomMessageHook) CustomMessageOption {
+	return func(c *customMessageType) {
+		c.encodeHook = hook
+	}
+}
+
+// WithDecodeHook sets a hook that is called with every message of a custom
+// message type after it has been decoded.
+func WithDecodeHook(hook CustomMessageHook) CustomMessageOption {
+	return func(c *customMessageType) {
+		c.decodeHook = hook
+	}
+}
+
+var (
+	// customTypes holds the registered custom message types.
+	customTypes = make(map[MessageType]*customMessageType)
+
+	// customTypesMtx guards customTypes.
+	customTypesMtx sync.RWMutex
+)
+
+// RegisterCustomMessage registers an odd custom message type, so that received
+// messages of this type are decoded into the message returned by the factory
+// instead of a raw Custom message. The message returned by the factory must
+// return the registered type from MsgType.
+//
+// NOTE: This function is safe for concurrent use, but messages should be
+// registered before any peer connections are established.
+func RegisterCustomMessage(msgType MessageType, factory CustomMessageFactory,
+	opts ...CustomMessageOption) error {
+
+	switch {
+	case msgType < CustomTypeStart:
+		return ErrCustomTypeRange
+
+	case msgType%2 == 0:
+		return ErrCustomTypeEven
+
+	case factory == nil:
+		return errors.New("custom msg factory must be set")
+	}
+
+	if factoryType := factory().MsgType(); factoryType != msgType {
+		return fmt.Errorf("custom msg factory returns msg type %d, "+
+			"expected %d", uint16(factoryType), uint16(msgType))
+	}
+
+	customType := &customMessageType{
+		factory: factory,
+	}
+	for _, opt := range opts {
+		opt(customType)
+	}
+
+	if customType.maxPayload < 0 || customType.maxPayload > MaxMsgBody {
+		return fmt.Errorf("max payload of custom msg must be between "+
+			"0 and %d", MaxMsgBody)
+	}
+
+	customTypesMtx.Lock()
+	defer customTypesMtx.Unlock()
+
+	if _, ok := customTypes[msgType]; ok {
+		return ErrCustomTypeRegistered
+	}
+
+	customTypes[msgType] = customType
+
+	return nil
+}
+
+// UnregisterCustomMessage removes the registration of a custom message type.
+// Received messages of this type are decoded into raw Custom messages again.
+func UnregisterCustomMessage(msgType MessageType) {
+	customTypesMtx.Lock()
+	defer customTypesMtx.Unlock()
+
+	delete(customTypes, msgType)
+}
+
+// lookupCustomMessage returns the registration of a custom message type, if
+// any.
+func lookupCustomMessage(msgType MessageType) (*customMessageType, bool) {
+	customTypesMtx.RLock()
+	defer customTypesMtx.RUnlock()
+
+	customType, ok := customTypes[msgType]
+	return customType, ok
+}
+
+// checkPayload returns an error if the payload exceeds the maximum size of
+// the custom message type.
+func (c *customMessageType) checkPayload(size int) error {
+	if c.maxPayload == 0 || size <= c.maxPayload {
+		return nil
+	}
+
+	return fmt.Errorf("custom message payload is too large - %d bytes, "+
+		"but maximum payload is %d bytes", size, c.maxPayload)
+}
+
+// beforeEncode calls the encode hook of the custom message type, unless the
+// message is a raw Custom message.
+func (c *customMessageType) beforeEncode(msg Message) error {
+	if _, ok := msg.(*Custom); ok || c.encodeHook == nil {
+		return nil
+	}
+
+	return c.encodeHook(msg)
+}
+
+// decode reads the payload of a message of the custom message type from r and
+// decodes it into a new message created by the factory.
+func (c *customMessageType) decode(r io.Reader, pver uint32) (Message,
+	error) {
+
+	// Read at most one byte more than allowed, so that we don't buffer
+	// more than needed to detect an oversized payload.
+	if c.maxPayload != 0 {
+		r = io.LimitReader(r, int64(c.maxPayload)+1)
+	}
+
+	var payload bytes.Buffer
+	if _, err := io.Copy(&payload, r); err != nil {
+		return nil, err
+	}
+	if err := c.checkPayload(payload.Len()); err != nil {
+		return nil, err
+	}
+
+	msg := c.factory()
+	if err := msg.Decode(&payload, pver); err != nil {
+		return nil, err
+	}
+
+	if c.decodeHook != nil {
+		if err := c.decodeHook(msg); err != nil {
+			return nil, err
+		}
+	}
+
+	return msg, nil
+}
+
+// EncodeCustomMessage encodes a message of a registered custom message type
+// into a raw Custom message. This allows typed custom messages to be handed to
+// code that only deals with raw Custom messages.
+func EncodeCustomMessage(msg Message, pver uint32) (*Custom, error) {
+	if custom, ok := msg.(*Custom); ok {
+		return custom, nil
+	}
+
+	customType, ok := lookupCustomMessage(msg.MsgType())
+	if !ok {
+		return nil, fmt.Errorf("custom msg type %d not registered",
+			uint16(msg.MsgType()))
+	}
+
+	if err := customType.beforeEncode(msg); err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := msg.Encode(&b, pver); err != nil {
+		return nil, err
+	}
+	if err := customType.checkPayload(b.Len()); err != nil {
+		return nil, err
+	}
+
+	return NewCustom(msg.MsgType(), b.Bytes())
+}
+
+// DecodeCustomMessage decodes a raw Custom message into a message of its
+// registered custom message type. If the type isn't registered, the raw
+// Custom message is returned.
+func DecodeCustomMessage(custom *Custom, pver uint32) (Message, error) {
+	customType, ok := lookupCustomMessage(custom.Type)
+	if !ok {
+		return custom, nil
+	}
+
+	return customType.decode(bytes.NewReader(custom.Data), pver)
+}
@@ -0,0 +1,162 @@
+package lnwire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testCustomType is the custom message type used in the tests.
+const testCustomType = MessageType(32769)
+
+// testCustomMsg is a typed custom message that consists of a single counter.
+type testCustomMsg struct {
+	Counter uint32
+}
+
+// Encode writes the counter of the message.
+func (m *testCustomMsg) Encode(b *bytes.Buffer, _ uint32) error {
+	return WriteUint32(b, m.Counter)
+}
+
+// Decode reads the counter of the message.
+func (m *testCustomMsg) Decode(r io.Reader, _ uint32) error {
+	return ReadElement(r, &m.Counter)
+}
+
+// MsgType returns the custom message type of the message.
+func (m *testCustomMsg) MsgType() MessageType {
+	return testCustomType
+}
+
+// TestRegisterCustomMessageValidation asserts that invalid registrations of
+// custom message types are rejected.
+func TestRegisterCustomMessageValidation(t *testing.T) {
+	factory := func() Message {
+		return &testCustomMsg{}
+	}
+
+	err := RegisterCustomMessage(CustomTypeStart-1, factory)
+	require.ErrorIs(t, err, ErrCustomTypeRange)
+
+	err = RegisterCustomMessage(CustomTypeStart, factory)
+	require.ErrorIs(t, err, ErrCustomTypeEven)
+
+	err = RegisterCustomMessage(testCustomType, nil)
+	require.Error(t, err)
+
+	// The factory must create messages of the registered type.
+	err = RegisterCustomMessage(testCustomType+2, factory)
+	require.Error(t, err)
+
+	err = RegisterCustomMessage(
+		testCustomType, factory, WithMaxPayload(MaxMsgBody+1),
+	)
+	require.Error(t, err)
+
+	require.NoError(t, RegisterCustomMessage(testCustomType, factory))
+	defer UnregisterCustomMessage(testCustomType)
+
+	err = RegisterCustomMessage(testCustomType, factory)
+	require.ErrorIs(t, err, ErrCustomTypeRegistered)
+}
+
+// TestCustomMessageRegistry asserts that messages of registered custom types
+// are decoded into typed messages, and that the payload limit and hooks are
+// applied.
+func TestCustomMessageRegistry(t *testing.T) {
+	errInvalid := errors.New("invalid counter")
+
+	var decoded []uint32
+	err := RegisterCustomMessage(
+		testCustomType,
+		func() Message {
+			return &testCustomMsg{}
+		},
+		WithMaxPayload(4),
+		WithEncodeHook(func(msg Message) error {
+			if msg.(*testCustomMsg).Counter == 0 {
+				return errInvalid
+			}
+
+			return nil
+		}),
+		WithDecodeHook(func(msg Message) error {
+			counter := msg.(*testCustomMsg).Counter
+			if counter == 13 {
+				return errInvalid
+			}
+
+			decoded = append(decoded, counter)
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	// A typed message is read back as the same typed message.
+	var b bytes.Buffer
+	_, err = WriteMessage(&b, &testCustomMsg{Counter: 7}, 0)
+	require.NoError(t, err)
+
+	msg, err := ReadMessage(bytes.NewReader(b.Bytes()), 0)
+	require.NoError(t, err)
+	require.Equal(t, &testCustomMsg{Counter: 7}, msg)
+	require.Equal(t, []uint32{7}, decoded)
+
+	// The encode hook can reject a message, in which case nothing is
+	// written.
+	b.Reset()
+	_, err = WriteMessage(&b, &testCustomMsg{}, 0)
+	require.ErrorIs(t, err, errInvalid)
+	require.Zero(t, b.Len())
+
+	// A raw custom message of the registered type is decoded into the
+	// typed message, unless it exceeds the payload limit.
+	raw, err := NewCustom(testCustomType, []byte{0, 0, 0, 9})
+	require.NoError(t, err)
+
+	msg, err = DecodeCustomMessage(raw, 0)
+	require.NoError(t, err)
+	require.Equal(t, &testCustomMsg{Counter: 9}, msg)
+
+	encoded, err := EncodeCustomMessage(msg, 0)
+	require.NoError(t, err)
+	require.Equal(t, raw, encoded)
+
+	raw.Data = append(raw.Data, 0)
+	_, err = WriteMessage(&b, raw, 0)
+	require.Error(t, err)
+
+	b.Reset()
+	require.NoError(t, WriteUint16(&b, uint16(testCustomType)))
+	require.NoError(t, WriteBytes(&b, raw.Data))
+	_, err = ReadMessage(&b, 0)
+
+	// Received messages of the odd custom types that are invalid are
+	// reported with an error that allows ignoring them.
+	var errInvalidMsg *ErrInvalidCustomMessage
+	require.ErrorAs(t, err, &errInvalidMsg)
+	require.Equal(t, testCustomType, errInvalidMsg.MsgType)
+
+	b.Reset()
+	require.NoError(t, WriteUint16(&b, uint16(testCustomType)))
+	require.NoError(t, WriteUint32(&b, 13))
+	_, err = ReadMessage(&b, 0)
+	require.ErrorAs(t, err, &errInvalidMsg)
+	require.ErrorIs(t, err, errInvalid)
+
+	// Once the type is unregistered, messages are read as raw custom
+	// messages again.
+	UnregisterCustomMessage(testCustomType)
+
+	b.Reset()
+	_, err = WriteMessage(&b, raw, 0)
+	require.NoError(t, err)
+
+	msg, err = ReadMessage(&b, 0)
+	require.NoError(t, err)
+	require.Equal(t, raw, msg)
+}
@@ -0,0 +1,229 @@
+package lnwire
+
+import (
+	"encoding/binary"
+	"io"
+	"sync"
+)
+
+// pooledMessages are the pools of the message structs that a Decoder with
+// message pools enabled reuses. Only the messages that are exchanged for every
+// HTLC are pooled, as they make up the bulk of the traffic of a routing node.
+var pooledMessages = map[MessageType]*sync.Pool{
+	MsgUpdateAddHTLC: {New: func() interface{} {
+		return &UpdateAddHTLC{}
+	}},
+	MsgUpdateFulfillHTLC: {New: func() interface{} {
+		return &UpdateFulfillHTLC{}
+	}},
+	MsgUpdateFailHTLC: {New: func() interface{} {
+		return &UpdateFailHTLC{}
+	}},
+	MsgUpdateFailMalformedHTLC: {New: func() interface{} {
+		return &UpdateFailMalformedHTLC{}
+	}},
+	MsgCommitSig: {New: func() interface{} {
+		return &CommitSig{}
+	}},
+	MsgRevokeAndAck: {New: func() interface{} {
+		return &RevokeAndAck{}
+	}},
+}
+
+// resetMessage zeroes a pooled message before it is put back into its pool.
+// It returns false if the message isn't of a pooled type.
+func resetMessage(msg Message) bool {
+	switch m := msg.(type) {
+	case *UpdateAddHTLC:
+		*m = UpdateAddHTLC{}
+	case *UpdateFulfillHTLC:
+		*m = UpdateFulfillHTLC{}
+	case *UpdateFailHTLC:
+		*m = UpdateFailHTLC{}
+	case *UpdateFailMalformedHTLC:
+		*m = UpdateFailMalformedHTLC{}
+	case *CommitSig:
+		*m = CommitSig{}
+	case *RevokeAndAck:
+		*m = RevokeAndAck{}
+	default:
+		return false
+	}
+
+	return true
+}
+
+// decodeReader is the reader a Decoder passes to the Decode method of
+// messages. It carries a scratch buffer that ReadElement uses for fixed size
+// fields, which would otherwise be allocated for every field read.
+type decodeReader struct {
+	r io.Reader
+
+	scratch [8]byte
+
+	// complexity counts the items of the decoded message if the decoder
+	// has complexity limits, otherwise it is nil.
+	complexity *messageComplexity
+}
+
+// Read reads from the underlying reader.
+//
+// NOTE: This is part of the io.Reader interface.
+func (d *decodeReader) Read(p []byte) (int, error) {
+	return d.r.Read(p)
+}
+
+// scratchBytes returns a buffer of n bytes, which must be at most 8, to read a
+// fixed size field into. Readers of a Decoder provide a reusable buffer, for
+// all other readers a new one is allocated. The buffer must not be retained.
+func scratchBytes(r io.Reader, n int) []byte {
+	if d, ok := r.(*decodeReader); ok {
+		return d.scratch[:n]
+	}
+
+	return make([]byte, n)
+}
+
+// remainingLen returns the number of unread bytes of the reader, if the
+// reader is able to tell.
+func remainingLen(r io.Reader) (int, bool) {
+	if d, ok := r.(*decodeReader); ok {
+		r = d.r
+	}
+
+	lr, ok := r.(interface{ Len() int })
+	if !ok {
+		return 0, false
+	}
+
+	return lr.Len(), true
+}
+
+// DecoderOption is a functional option that modifies a Decoder.
+type DecoderOption func(*Decoder)
+
+// WithMessagePools makes the decoder take the structs of the messages that
+// are exchanged for every HTLC from pools instead of allocating them. Every
+// message decoded this way should be handed back with Release once the caller
+// no longer uses it.
+func WithMessagePools() DecoderOption {
+	return func(d *Decoder) {
+		d.usePools = true
+	}
+}
+
+// WithComplexityLimits makes the decoder reject messages that contain more
+// items than the given limits allow, with an ErrComplexityLimit.
+func WithComplexityLimits(limits ComplexityLimits) DecoderOption {
+	return func(d *Decoder) {
+		d.r.complexity = &messageComplexity{limits: limits}
+	}
+}
+
+// Decoder reads and decodes Lightning messages like ReadMessage, but reuses
+// its scratch buffers across messages and optionally takes message structs
+// from pools, which reduces the allocations per message. A Decoder must not be
+// used concurrently. The zero value decodes messages without pools once Reset
+// is called.
+type Decoder struct {
+	r decodeReader
+
+	usePools bool
+}
+
+// NewDecoder creates a new Decoder that reads messages from r.
+func NewDecoder(r io.Reader, opts ...DecoderOption) *Decoder {
+	d := &Decoder{
+		r: decodeReader{r: r},
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+// Reset makes the decoder read the next messages from r, which allows the
+// decoder to be reused for messages that are read into separate buffers.
+func (d *Decoder) Reset(r io.Reader) {
+	d.r.r = r
+}
+
+// ReadMessage reads, validates, and parses the next Lightning message from the
+// decoder's reader for the provided protocol version.
+func (d *Decoder) ReadMessage(pver uint32) (Message, error) {
+	// First, we'll read out the first two bytes of the message so we can
+	// create the proper empty message.
+	mType := d.r.scratch[:2]
+	if _, err := io.ReadFull(d.r.r, mType); err != nil {
+		return nil, err
+	}
+
+	msgType := MessageType(binary.BigEndian.Uint16(mType))
+
+	if d.r.complexity != nil {
+		d.r.complexity.reset()
+	}
+
+	// Messages of registered custom message types are decoded into the
+	// typed message of the application instead of a raw Custom message.
+	// The types are odd, so an invalid message must not be fatal.
+	if customType, ok := lookupCustomMessage(msgType); ok {
+		msg, err := customType.decode(&d.r, pver)
+		if err != nil {
+			return nil, &ErrInvalidCustomMessage{
+				MsgType: msgType,
+				Err:     withMsgType(err, msgType),
+			}
+		}
+
+		return msg, nil
+	}
+
+	var (
+		msg Message
+		err error
+	)
+	if pool, ok := pooledMessages[msgType]; ok && d.usePools {
+		msg = pool.Get().(Message)
+	} else {
+		msg, err = makeEmptyMessage(msgType)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := msg.Decode(&d.r, pver); err != nil {
+		d.Release(msg)
+		return nil, withMsgType(err, msgType)
+	}
+
+	return msg, nil
+}
+
+// withMsgType adds the type of the message that was rejected to an
+// ErrComplexityLimit. Other errors are returned unchanged.
+func withMsgType(err error, msgType MessageType) error {
+	if e, ok := err.(*ErrComplexityLimit); ok {
+		e.MsgType = msgType
+	}
+
+	return err
+}
+
+// Release hands a message that was returned by ReadMessage back to its pool,
+// so that its struct can be reused for one of the next messages. The message
+// must not be used anymore after it was released. Releasing a message of a
+// type that isn't pooled, or of a decoder without message pools, is a no-op.
+func (d *Decoder) Release(msg Message) {
+	if !d.usePools {
+		return
+	}
+
+	pool, ok := pooledMessages[msg.MsgType()]
+	if !ok || !resetMessage(msg) {
+		return
+	}
+
+	pool.Put(msg)
+}
@@ -0,0 +1,109 @@
+package lnwire_test
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDecoderReadMessage asserts that a Decoder that is reused for all types
+// of messages, with and without message pools, decodes them exactly like
+// ReadMessage.
+func TestDecoderReadMessage(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(42))
+	msgAll := makeAllMessages(t, r)
+
+	for _, pooled := range []bool{false, true} {
+		var opts []lnwire.DecoderOption
+		if pooled {
+			opts = append(opts, lnwire.WithMessagePools())
+		}
+
+		reader := bytes.NewReader(nil)
+		decoder := lnwire.NewDecoder(reader, opts...)
+
+		for _, msg := range msgAll {
+			var buf bytes.Buffer
+			_, err := lnwire.WriteMessage(&buf, msg, 0)
+			require.NoError(t, err)
+
+			expected, expectedErr := lnwire.ReadMessage(
+				bytes.NewReader(buf.Bytes()), 0,
+			)
+
+			// Some of the test messages can't be decoded, in
+			// which case the decoder must fail the same way.
+			reader.Reset(buf.Bytes())
+			decoded, err := decoder.ReadMessage(0)
+			if expectedErr != nil {
+				require.EqualError(t, err, expectedErr.Error())
+				continue
+			}
+			require.NoError(t, err)
+			require.Equal(t, expected, decoded)
+			require.Zero(t, reader.Len())
+
+			decoder.Release(decoded)
+		}
+	}
+}
+
+// TestDecoderMessagePools asserts that released messages are reset before
+// they are reused, and that pooling reduces the allocations per message.
+func TestDecoderMessagePools(t *testing.T) {
+	add := &lnwire.UpdateAddHTLC{
+		ID:        1,
+		Amount:    1000,
+		Expiry:    144,
+		ExtraData: []byte{1, 2, 3},
+	}
+	add.PaymentHash[0] = 1
+
+	// The second message has no extra data, so a stale value of the first
+	// one would be noticed.
+	add2 := &lnwire.UpdateAddHTLC{
+		ID:        2,
+		Amount:    2000,
+		Expiry:    40,
+		ExtraData: make([]byte, 0),
+	}
+
+	var buf1, buf2 bytes.Buffer
+	_, err := lnwire.WriteMessage(&buf1, add, 0)
+	require.NoError(t, err)
+	_, err = lnwire.WriteMessage(&buf2, add2, 0)
+	require.NoError(t, err)
+
+	reader := bytes.NewReader(buf1.Bytes())
+	decoder := lnwire.NewDecoder(reader, lnwire.WithMessagePools())
+
+	msg, err := decoder.ReadMessage(0)
+	require.NoError(t, err)
+	require.Equal(t, add, msg)
+	decoder.Release(msg)
+
+	decoder.Reset(bytes.NewReader(buf2.Bytes()))
+	msg, err = decoder.ReadMessage(0)
+	require.NoError(t, err)
+	require.Equal(t, add2, msg)
+	decoder.Release(msg)
+
+	readAllocs := testing.AllocsPerRun(100, func() {
+		_, err := lnwire.ReadMessage(bytes.NewReader(buf1.Bytes()), 0)
+		require.NoError(t, err)
+	})
+	decoderAllocs := testing.AllocsPerRun(100, func() {
+		reader.Reset(buf1.Bytes())
+		decoder.Reset(reader)
+
+		msg, err := decoder.ReadMessage(0)
+		require.NoError(t, err)
+		decoder.Release(msg)
+	})
+	require.Less(t, decoderAllocs, readAllocs)
+}
@@ -29,7 +29,19 @@ func (e *ExtraOpaqueData) Encode(w *bytes.Buffer) error {
 // Decode attempts to unpack the raw bytes encoded in the passed io.Reader as a
 // set of extra opaque data.
 func (e *ExtraOpaqueData) Decode(r io.Reader) error {
-	// First, we'll attempt to read a set of bytes contained within the
+	// If the reader knows how many bytes are left, we read them directly
+	// into a buffer of the right size instead of growing one.
+	if n, ok := remainingLen(r); ok {
+		rawBytes := make([]byte, n)
+		if _, err := io.ReadFull(r, rawBytes); err != nil {
+			return err
+		}
+		*e = rawBytes
+
+		return countTLVRecords(r, rawBytes)
+	}
+
+	// Otherwise, we'll attempt to read a set of bytes contained within the
 	// passed io.Reader (if any exist).
 	rawBytes, err := ioutil.ReadAll(r)
 	if err != nil {
@@ -45,7 +57,7 @@ func (e *ExtraOpaqueData) Decode(r io.Reader) error {
 		*e = make([]byte, 0)
 	}
 
-	return nil
+	return countTLVRecords(r, rawBytes)
 }
 
 // PackRecords attempts to encode the set of tlv records into the target
@@ -0,0 +1,150 @@
+package lnwire
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// ErrDuplicateRecord is returned when a record is registered with an
+	// ExtraData helper that already knows a record of the same type.
+	ErrDuplicateRecord = errors.New("duplicate tlv record type")
+
+	// ErrUnknownRequiredRecord is returned when a TLV stream contains an
+	// unknown record with an even type, which must not be ignored.
+	ErrUnknownRequiredRecord = errors.New("unknown required record")
+)
+
+// ExtraData composes and parses the TLV extension of a message from a set of
+// typed records, so that messages and external packages don't need to
+// assemble TLV streams by hand. The records are always encoded in ascending
+// type order, and parsing rejects streams that aren't canonical or that
+// contain an unknown record with an even type.
+//
+// To build an extension, register the records that should be included and
+// call Encode. To parse one, register all known records and call Decode,
+// which fills in the records found in the stream. Unknown odd records of a
+// decoded stream are kept and encoded again along with the registered
+// records, so that extensions we don't understand survive a round trip. The
+// zero value is ready to use.
+type ExtraData struct {
+	records map[tlv.Type]tlv.RecordProducer
+
+	// unknown holds the raw values of the unknown odd records found by
+	// the last call to Decode.
+	unknown tlv.TypeMap
+}
+
+// NewExtraData creates a new ExtraData helper with the given records
+// registered.
+func NewExtraData(records ...tlv.RecordProducer) (*ExtraData, error) {
+	e := &ExtraData{}
+	if err := e.Register(records...); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Register adds the given records to the set of records the helper encodes
+// and decodes. An error is returned if a record of the same type is already
+// registered.
+func (e *ExtraData) Register(records ...tlv.RecordProducer) error {
+	if e.records == nil {
+		e.records = make(map[tlv.Type]tlv.RecordProducer, len(records))
+	}
+
+	for _, producer := range records {
+		record := producer.Record()
+		typ := record.Type()
+		if _, ok := e.records[typ]; ok {
+			return fmt.Errorf("%w: %d", ErrDuplicateRecord, typ)
+		}
+
+		e.records[typ] = producer
+	}
+
+	return nil
+}
+
+// sortedRecords returns the registered records in ascending type order.
+func (e *ExtraData) sortedRecords() []tlv.Record {
+	records := make([]tlv.Record, 0, len(e.records))
+	for _, producer := range e.records {
+		records = append(records, producer.Record())
+	}
+	tlv.SortRecords(records)
+
+	return records
+}
+
+// Encode encodes all registered records, along with the unknown odd records
+// of the last decoded stream, into a canonical TLV stream.
+func (e *ExtraData) Encode() (ExtraOpaqueData, error) {
+	records := make([]tlv.Record, 0, len(e.records)+len(e.unknown))
+	for _, producer := range e.records {
+		records = append(records, producer.Record())
+	}
+
+	// A registered record takes precedence over an unknown record of the
+	// same type, which can only exist if it was registered after the
+	// stream was decoded.
+	for typ, value := range e.unknown {
+		if _, ok := e.records[typ]; ok {
+			continue
+		}
+
+		value := value
+		records = append(records, tlv.MakePrimitiveRecord(typ, &value))
+	}
+	tlv.SortRecords(records)
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return ExtraOpaqueData(b.Bytes()), nil
+}
+
+// Decode parses the TLV extension into the registered records. The returned
+// type map contains all types found in the stream. Known types map to a nil
+// value, while unknown odd types map to their raw value and are kept to be
+// encoded again. An error is returned if the stream isn't canonical or
+// contains an unknown even type.
+func (e *ExtraData) Decode(data ExtraOpaqueData) (tlv.TypeMap, error) {
+	stream, err := tlv.NewStream(e.sortedRecords()...)
+	if err != nil {
+		return nil, err
+	}
+
+	typeMap, err := stream.DecodeWithParsedTypes(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	unknown := make(tlv.TypeMap)
+	for typ, value := range typeMap {
+		if value == nil {
+			continue
+		}
+
+		if typ%2 == 0 {
+			return nil, fmt.Errorf("%w: %d",
+				ErrUnknownRequiredRecord, typ)
+		}
+
+		unknown[typ] = value
+	}
+	e.unknown = unknown
+
+	return typeMap, nil
+}
@@ -0,0 +1,237 @@
+package lnwire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExtraDataEncodeDecode tests that records registered in any order are
+// encoded canonically and decoded back into the registered records.
+func TestExtraDataEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	var nextFundingTxid NextFundingTxid
+	nextFundingTxid[0] = 1
+	leaseExpiry := LeaseExpiry(144)
+	channelType := ChannelType(*NewRawFeatureVector(
+		StaticRemoteKeyRequired,
+	))
+
+	// Register the records out of order, the encoding must still be
+	// canonical.
+	extraData, err := NewExtraData(&leaseExpiry, &channelType)
+	require.NoError(t, err)
+	require.NoError(t, extraData.Register(&nextFundingTxid))
+
+	encoded, err := extraData.Encode()
+	require.NoError(t, err)
+
+	var expected ExtraOpaqueData
+	require.NoError(t, expected.PackRecords(
+		&nextFundingTxid, &channelType, &leaseExpiry,
+	))
+	require.Equal(t, expected, encoded)
+
+	// Decode the stream with a helper that knows an additional record,
+	// which isn't part of the stream.
+	var (
+		decodedTxid        NextFundingTxid
+		decodedChannelType ChannelType
+		decodedExpiry      LeaseExpiry
+		requireConfirmed   RequireConfirmedInputs
+	)
+	parser, err := NewExtraData(
+		&decodedExpiry, &requireConfirmed, &decodedChannelType,
+		&decodedTxid,
+	)
+	require.NoError(t, err)
+
+	typeMap, err := parser.Decode(encoded)
+	require.NoError(t, err)
+	require.Equal(t, tlv.TypeMap{
+		NextFundingTxidRecordType: nil,
+		ChannelTypeRecordType:     nil,
+		LeaseExpiryRecordType:     nil,
+	}, typeMap)
+
+	require.Equal(t, nextFundingTxid, decodedTxid)
+	require.Equal(t, channelType, decodedChannelType)
+	require.Equal(t, leaseExpiry, decodedExpiry)
+}
+
+// TestExtraDataDuplicateRecord tests that two records of the same type can't
+// be registered.
+func TestExtraDataDuplicateRecord(t *testing.T) {
+	t.Parallel()
+
+	var (
+		nextFundingTxid NextFundingTxid
+		deliveryAddr    DeliveryAddress
+	)
+
+	_, err := NewExtraData(&nextFundingTxid, &deliveryAddr)
+	require.ErrorIs(t, err, ErrDuplicateRecord)
+
+	extraData, err := NewExtraData(&nextFundingTxid)
+	require.NoError(t, err)
+	require.ErrorIs(
+		t, extraData.Register(&deliveryAddr), ErrDuplicateRecord,
+	)
+}
+
+// TestExtraDataDecodeRejects tests that streams that aren't canonical or
+// contain unknown even records are rejected, while unknown odd records are
+// returned in the type map.
+func TestExtraDataDecodeRejects(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		data        ExtraOpaqueData
+		expectedErr error
+		typeMap     tlv.TypeMap
+	}{
+		{
+			name:    "empty",
+			data:    ExtraOpaqueData{},
+			typeMap: tlv.TypeMap{},
+		},
+		{
+			name: "unknown odd record",
+			data: ExtraOpaqueData{
+				0x02, 0x00, 0x05, 0x01, 0xff,
+			},
+			typeMap: tlv.TypeMap{
+				RequireConfirmedInputsRecordType: nil,
+				5:                                []byte{0xff},
+			},
+		},
+		{
+			name: "unknown even record",
+			data: ExtraOpaqueData{
+				0x02, 0x00, 0x04, 0x01, 0xff,
+			},
+			expectedErr: ErrUnknownRequiredRecord,
+		},
+		{
+			name: "records not ascending",
+			data: ExtraOpaqueData{
+				0x05, 0x00, 0x02, 0x00,
+			},
+			expectedErr: tlv.ErrStreamNotCanonical,
+		},
+		{
+			name: "duplicate record",
+			data: ExtraOpaqueData{
+				0x02, 0x00, 0x02, 0x00,
+			},
+			expectedErr: tlv.ErrStreamNotCanonical,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requireConfirmed RequireConfirmedInputs
+			parser, err := NewExtraData(&requireConfirmed)
+			require.NoError(t, err)
+
+			typeMap, err := parser.Decode(testCase.data)
+			if testCase.expectedErr != nil {
+				require.ErrorIs(t, err, testCase.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testCase.typeMap, typeMap)
+		})
+	}
+}
+
+// TestExtraDataRoundTripUnknownOdd tests that unknown odd records of a decoded
+// stream are encoded again along with the registered records.
+func TestExtraDataRoundTripUnknownOdd(t *testing.T) {
+	t.Parallel()
+
+	// The stream contains a known record of type 2 between two unknown
+	// odd records.
+	data := ExtraOpaqueData{
+		0x01, 0x01, 0xaa,
+		0x02, 0x00,
+		0x05, 0x02, 0xbb, 0xcc,
+	}
+
+	var requireConfirmed RequireConfirmedInputs
+	parser, err := NewExtraData(&requireConfirmed)
+	require.NoError(t, err)
+
+	_, err = parser.Decode(data)
+	require.NoError(t, err)
+
+	encoded, err := parser.Encode()
+	require.NoError(t, err)
+	require.Equal(t, data, encoded)
+
+	// A record registered after decoding replaces the unknown record of
+	// the same type.
+	channelType := ChannelType(*NewRawFeatureVector(
+		StaticRemoteKeyRequired,
+	))
+	require.NoError(t, parser.Register(&channelType))
+
+	encoded, err = parser.Encode()
+	require.NoError(t, err)
+
+	var expected ExtraOpaqueData
+	require.NoError(t, expected.PackRecords(
+		&channelType, &requireConfirmed,
+	))
+	expected = append(expected, 0x05, 0x02, 0xbb, 0xcc)
+	require.Equal(t, expected, encoded)
+}
+
+// TestChannelReestablishExtraData tests that channel_reestablish keeps unknown
+// odd records when its next funding txid is encoded, and rejects unknown even
+// records.
+func TestChannelReestablishExtraData(t *testing.T) {
+	t.Parallel()
+
+	_, pub := btcec.PrivKeyFromBytes(bytes.Repeat([]byte{1}, 32))
+	nextFundingTxid := NextFundingTxid{1, 2, 3}
+	msg := &ChannelReestablish{
+		LocalUnrevokedCommitPoint: pub,
+		NextFundingTxid:           &nextFundingTxid,
+		ExtraData:                 ExtraOpaqueData{0x03, 0x01, 0xaa},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, msg.Encode(&b, 0))
+
+	var decoded ChannelReestablish
+	require.NoError(t, decoded.Decode(bytes.NewReader(b.Bytes()), 0))
+	require.Equal(t, nextFundingTxid, *decoded.NextFundingTxid)
+
+	typeMap, err := decoded.ExtraData.ExtractRecords()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xaa}, typeMap[3])
+
+	// An unknown even record must be rejected.
+	msg = &ChannelReestablish{
+		LocalUnrevokedCommitPoint: pub,
+		ExtraData:                 ExtraOpaqueData{0x04, 0x01, 0xaa},
+	}
+
+	b.Reset()
+	require.NoError(t, msg.Encode(&b, 0))
+
+	err = decoded.Decode(bytes.NewReader(b.Bytes()), 0)
+	require.ErrorIs(t, err, ErrUnknownRequiredRecord)
+}
@@ -203,6 +203,10 @@ const (
 	// able and willing to accept keysend payments.
 	KeysendOptional = 55
 
+	// The feature bits below aren't part of the specification yet. They
+	// are taken from the range above the bits assigned by BOLT 9 that lnd
+	// uses for its experimental features.
+
 	// ScriptEnforcedLeaseOptional is an optional feature bit that signals
 	// that the node requires channels having zero-fee second-level HTLC
 	// transactions, which also imply anchor commitments, along with an
@@ -221,6 +225,26 @@ const (
 	// TODO: Decide on actual feature bit value.
 	ScriptEnforcedLeaseOptional FeatureBit = 2023
 
+	// NoiseIKRequired is a required feature bit that signals that the node
+	// accepts brontide connections established with the Noise_IK handshake
+	// in addition to the Noise_XK handshake of BOLT 8.
+	NoiseIKRequired FeatureBit = 2024
+
+	// NoiseIKOptional is an optional feature bit that signals that the
+	// node accepts brontide connections established with the Noise_IK
+	// handshake in addition to the Noise_XK handshake of BOLT 8.
+	NoiseIKOptional FeatureBit = 2025
+
+	// KeyRotationRequired is a required feature bit that signals that the
+	// node follows brontide key rotations that happen ahead of the
+	// rotation interval of BOLT 8.
+	KeyRotationRequired FeatureBit = 2026
+
+	// KeyRotationOptional is an optional feature bit that signals that the
+	// node follows brontide key rotations that happen ahead of the
+	// rotation interval of BOLT 8.
+	KeyRotationOptional FeatureBit = 2027
+
 	// maxAllowedSize is a maximum allowed size of feature vector.
 	//
 	// NOTE: Within the protocol, the maximum allowed message size is 65535
@@ -280,6 +304,10 @@ var Features = map[FeatureBit]string{
 	ZeroConfOptional:              "zero-conf",
 	ShutdownAnySegwitRequired:     "shutdown-any-segwit",
 	ShutdownAnySegwitOptional:     "shutdown-any-segwit",
+	NoiseIKRequired:               "noise-ik",
+	NoiseIKOptional:               "noise-ik",
+	KeyRotationRequired:           "key-rotation",
+	KeyRotationOptional:           "key-rotation",
 }
 
 // RawFeatureVector represents a set of feature bits as defined in BOLT-09.  A
@@ -0,0 +1,494 @@
+package lnwire_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/lnwire/lnwiretest"
+	"github.com/stretchr/testify/require"
+)
+
+// numSeedMsgs is the number of randomly generated messages that are added to
+// the seed corpus of each fuzz target.
+const numSeedMsgs = 10
+
+// prefixWithMsgType takes []byte and adds a wire protocol prefix
+// to make the []byte into an actual message to be used in fuzzing.
+func prefixWithMsgType(data []byte, prefix lnwire.MessageType) []byte {
+	var prefixBytes [2]byte
+	binary.BigEndian.PutUint16(prefixBytes[:], uint16(prefix))
+	data = append(prefixBytes[:], data...)
+
+	return data
+}
+
+// seedCorpus adds a set of randomly generated, valid messages of the given
+// type to the seed corpus of the fuzz target. The message type prefix is
+// stripped, as the fuzz targets add it themselves.
+func seedCorpus(f *testing.F, msgType lnwire.MessageType) {
+	f.Helper()
+
+	r := rand.New(rand.NewSource(int64(msgType)))
+	for i := 0; i < numSeedMsgs; i++ {
+		msg, err := lnwiretest.RandMessage(msgType, r)
+		require.NoError(f, err)
+
+		// The generated messages respect the message size limits, so
+		// they can always be encoded.
+		var b bytes.Buffer
+		_, err = lnwire.WriteMessage(&b, msg, 0)
+		require.NoError(f, err)
+
+		f.Add(b.Bytes()[2:])
+	}
+}
+
+// harness performs the actual fuzz testing of the appropriate wire message.
+// This function will check that the passed-in message passes wire length
+// checks, is a valid message once deserialized, and passes a sequence of
+// serialization and deserialization checks.
+func harness(t *testing.T, data []byte) {
+	t.Helper()
+
+	// Create a reader with the byte array.
+	r := bytes.NewReader(data)
+
+	// Check that the created message is not greater than the maximum
+	// message size.
+	if len(data) > lnwire.MaxSliceLength {
+		return
+	}
+
+	msg, err := lnwire.ReadMessage(r, 0)
+	if err != nil {
+		return
+	}
+
+	// We will serialize the message into a new bytes buffer.
+	var b bytes.Buffer
+	_, err = lnwire.WriteMessage(&b, msg, 0)
+	require.NoError(t, err)
+
+	// Deserialize the message from the serialized bytes buffer, and then
+	// assert that the original message is equal to the newly deserialized
+	// message.
+	newMsg, err := lnwire.ReadMessage(&b, 0)
+	require.NoError(t, err)
+	require.Equal(t, msg, newMsg)
+}
+
+// fuzzMsg registers a fuzz target for the given message type that is seeded
+// with randomly generated messages of that type and checks that any message
+// that can be decoded survives a serialization round trip.
+func fuzzMsg(f *testing.F, msgType lnwire.MessageType) {
+	seedCorpus(f, msgType)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with the message type.
+		data = prefixWithMsgType(data, msgType)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages!
+		harness(t, data)
+	})
+}
+
+func FuzzAcceptChannel(f *testing.F) {
+	seedCorpus(f, lnwire.MsgAcceptChannel)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with MsgAcceptChannel.
+		data = prefixWithMsgType(data, lnwire.MsgAcceptChannel)
+
+		// We have to do this here instead of in harness so that
+		// reflect.DeepEqual isn't called. Because of the
+		// UpfrontShutdownScript encoding, the first message and
+		// second message aren't deeply equal since the first has a
+		// nil slice and the other has an empty slice.
+		first, second := roundTrip(t, data)
+		if first == nil {
+			return
+		}
+
+		firstMsg := first.(*lnwire.AcceptChannel)
+		secondMsg := second.(*lnwire.AcceptChannel)
+		require.Equal(
+			t, []byte(firstMsg.UpfrontShutdownScript),
+			[]byte(secondMsg.UpfrontShutdownScript),
+		)
+
+		// With the shutdown scripts compared byte-wise, the remaining
+		// fields must match exactly.
+		firstMsg.UpfrontShutdownScript = nil
+		secondMsg.UpfrontShutdownScript = nil
+		require.Equal(t, firstMsg, secondMsg)
+	})
+}
+
+func FuzzOpenChannel(f *testing.F) {
+	seedCorpus(f, lnwire.MsgOpenChannel)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with MsgOpenChannel.
+		data = prefixWithMsgType(data, lnwire.MsgOpenChannel)
+
+		// We have to do this here instead of in harness so that
+		// reflect.DeepEqual isn't called. Because of the
+		// UpfrontShutdownScript encoding, the first message and
+		// second message aren't deeply equal since the first has a
+		// nil slice and the other has an empty slice.
+		first, second := roundTrip(t, data)
+		if first == nil {
+			return
+		}
+
+		firstMsg := first.(*lnwire.OpenChannel)
+		secondMsg := second.(*lnwire.OpenChannel)
+		require.Equal(
+			t, []byte(firstMsg.UpfrontShutdownScript),
+			[]byte(secondMsg.UpfrontShutdownScript),
+		)
+
+		// With the shutdown scripts compared byte-wise, the remaining
+		// fields must match exactly.
+		firstMsg.UpfrontShutdownScript = nil
+		secondMsg.UpfrontShutdownScript = nil
+		require.Equal(t, firstMsg, secondMsg)
+	})
+}
+
+func FuzzNodeAnnouncement(f *testing.F) {
+	seedCorpus(f, lnwire.MsgNodeAnnouncement)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with MsgNodeAnnouncement.
+		data = prefixWithMsgType(data, lnwire.MsgNodeAnnouncement)
+
+		// We have to do this here instead of in harness so that
+		// reflect.DeepEqual isn't called. Address (de)serialization
+		// messes up the fuzzing assertions.
+		first, second := roundTrip(t, data)
+		if first == nil {
+			return
+		}
+
+		firstMsg := first.(*lnwire.NodeAnnouncement)
+		secondMsg := second.(*lnwire.NodeAnnouncement)
+		require.Len(t, secondMsg.Addresses, len(firstMsg.Addresses))
+		for i := range firstMsg.Addresses {
+			require.Equal(
+				t, firstMsg.Addresses[i].String(),
+				secondMsg.Addresses[i].String(),
+			)
+		}
+
+		// With the addresses compared by their string representation,
+		// the remaining fields must match exactly.
+		firstMsg.Addresses = nil
+		secondMsg.Addresses = nil
+		require.Equal(t, firstMsg, secondMsg)
+	})
+}
+
+// roundTrip decodes the given message, and serializes and decodes it once
+// more. Both decoded messages are returned, or nil if the passed data isn't a
+// valid message.
+func roundTrip(t *testing.T, data []byte) (lnwire.Message, lnwire.Message) {
+	t.Helper()
+
+	// Make sure byte array length (excluding 2 bytes for message type) is
+	// less than max payload size for the wire message.
+	payloadLen := uint32(len(data)) - 2
+	if payloadLen > lnwire.MaxMsgBody {
+		return nil, nil
+	}
+
+	msg, err := lnwire.ReadMessage(bytes.NewReader(data), 0)
+	if err != nil {
+		return nil, nil
+	}
+
+	// We will serialize the message into a new bytes buffer.
+	var b bytes.Buffer
+	_, err = lnwire.WriteMessage(&b, msg, 0)
+	require.NoError(t, err)
+
+	// Deserialize the message from the serialized bytes buffer.
+	newMsg, err := lnwire.ReadMessage(&b, 0)
+	require.NoError(t, err)
+
+	return msg, newMsg
+}
+
+func FuzzAnnounceSignatures(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgAnnounceSignatures)
+}
+
+func FuzzChannelAnnouncement(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgChannelAnnouncement)
+}
+
+func FuzzChannelReestablish(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgChannelReestablish)
+}
+
+func FuzzChannelUpdate(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgChannelUpdate)
+}
+
+func FuzzClosingSigned(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgClosingSigned)
+}
+
+func FuzzCommitSig(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgCommitSig)
+}
+
+func FuzzError(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgError)
+}
+
+func FuzzWarning(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgWarning)
+}
+
+func FuzzFundingCreated(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgFundingCreated)
+}
+
+func FuzzFundingLocked(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgFundingLocked)
+}
+
+func FuzzFundingSigned(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgFundingSigned)
+}
+
+func FuzzGossipTimestampRange(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgGossipTimestampRange)
+}
+
+func FuzzInit(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgInit)
+}
+
+func FuzzOnionMessage(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgOnionMessage)
+}
+
+func FuzzPing(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgPing)
+}
+
+func FuzzPong(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgPong)
+}
+
+func FuzzQueryChannelRange(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgQueryChannelRange)
+}
+
+func FuzzQueryShortChanIDs(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgQueryShortChanIDs)
+}
+
+func FuzzReplyChannelRange(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgReplyChannelRange)
+}
+
+func FuzzReplyShortChanIDsEnd(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgReplyShortChanIDsEnd)
+}
+
+func FuzzRevokeAndAck(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgRevokeAndAck)
+}
+
+func FuzzShutdown(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgShutdown)
+}
+
+func FuzzSpliceAck(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgSpliceAck)
+}
+
+func FuzzSpliceInit(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgSpliceInit)
+}
+
+func FuzzSpliceLocked(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgSpliceLocked)
+}
+
+func FuzzUpdateAddHTLC(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgUpdateAddHTLC)
+}
+
+func FuzzUpdateFailHTLC(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgUpdateFailHTLC)
+}
+
+func FuzzUpdateFailMalformedHTLC(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgUpdateFailMalformedHTLC)
+}
+
+func FuzzUpdateFee(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgUpdateFee)
+}
+
+func FuzzUpdateFulfillHTLC(f *testing.F) {
+	fuzzMsg(f, lnwire.MsgUpdateFulfillHTLC)
+}
+
+// compressPayload zlib compresses the given data.
+func compressPayload(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zlibWriter := zlib.NewWriter(&buf)
+	_, err := zlibWriter.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, zlibWriter.Close())
+
+	return buf.Bytes()
+}
+
+func FuzzQueryShortChanIDsZlib(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		compressedPayload := compressPayload(t, data)
+
+		chainhash := []byte("00000000000000000000000000000000")
+		numBytesInBody := len(compressedPayload) + 1
+		zlibByte := []byte("\x01")
+
+		bodyBytes := make([]byte, 2)
+		binary.BigEndian.PutUint16(bodyBytes, uint16(numBytesInBody))
+
+		payload := append(chainhash, bodyBytes...)
+		payload = append(payload, zlibByte...)
+		payload = append(payload, compressedPayload...)
+
+		// Prefix with MsgQueryShortChanIDs.
+		payload = prefixWithMsgType(
+			payload, lnwire.MsgQueryShortChanIDs,
+		)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages!
+		harness(t, payload)
+	})
+}
+
+func FuzzReplyChannelRangeZlib(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		compressedPayload := compressPayload(t, data)
+
+		// Initialize some []byte vars which will prefix our payload
+		chainhash := []byte("00000000000000000000000000000000")
+		firstBlockHeight := []byte("\x00\x00\x00\x00")
+		numBlocks := []byte("\x00\x00\x00\x00")
+		completeByte := []byte("\x00")
+
+		numBytesInBody := len(compressedPayload) + 1
+		zlibByte := []byte("\x01")
+
+		bodyBytes := make([]byte, 2)
+		binary.BigEndian.PutUint16(bodyBytes, uint16(numBytesInBody))
+
+		payload := append(chainhash, firstBlockHeight...)
+		payload = append(payload, numBlocks...)
+		payload = append(payload, completeByte...)
+		payload = append(payload, bodyBytes...)
+		payload = append(payload, zlibByte...)
+		payload = append(payload, compressedPayload...)
+
+		// Prefix with MsgReplyChannelRange.
+		payload = prefixWithMsgType(
+			payload, lnwire.MsgReplyChannelRange,
+		)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages!
+		harness(t, payload)
+	})
+}
+
+// payloadCodec is a TLV payload of an onion message that can be serialized and
+// deserialized.
+type payloadCodec interface {
+	Encode(w io.Writer) error
+	Decode(r io.Reader) error
+}
+
+// payloadHarness checks that any payload that can be decoded by the passed
+// codecs survives a serialization round trip.
+func payloadHarness(t *testing.T, data []byte, first, second payloadCodec) {
+	t.Helper()
+
+	if err := first.Decode(bytes.NewReader(data)); err != nil {
+		return
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, first.Encode(&b))
+	require.NoError(t, second.Decode(&b))
+	require.Equal(t, first, second)
+}
+
+func FuzzOnionMessagePayload(f *testing.F) {
+	r := rand.New(rand.NewSource(int64(lnwire.MsgOnionMessage)))
+	seed := &lnwire.OnionMessagePayload{
+		ReplyPath: &lnwire.BlindedPath{
+			IntroductionNode: lnwiretest.RandPubKey(r),
+			BlindingPoint:    lnwiretest.RandPubKey(r),
+			Hops: []*lnwire.BlindedHop{{
+				BlindedNodeID: lnwiretest.RandPubKey(r),
+				EncryptedData: lnwiretest.RandBytes(r, 50),
+			}},
+		},
+		EncryptedData: lnwiretest.RandBytes(r, 50),
+		FinalHopPayloads: []*lnwire.FinalHopPayload{{
+			TLVType: lnwire.FinalHopPayloadTypeStart,
+			Value:   lnwiretest.RandBytes(r, 50),
+		}},
+	}
+
+	var b bytes.Buffer
+	require.NoError(f, seed.Encode(&b))
+	f.Add(b.Bytes())
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		payloadHarness(
+			t, data, &lnwire.OnionMessagePayload{},
+			&lnwire.OnionMessagePayload{},
+		)
+	})
+}
+
+func FuzzBlindedRouteData(f *testing.F) {
+	r := rand.New(rand.NewSource(int64(lnwire.MsgOnionMessage)))
+	scid := lnwire.NewShortChanIDFromInt(r.Uint64())
+	seed := &lnwire.BlindedRouteData{
+		Padding:              make([]byte, 10),
+		ShortChannelID:       &scid,
+		NextNodeID:           lnwiretest.RandPubKey(r),
+		PathID:               lnwiretest.RandBytes(r, 32),
+		NextBlindingOverride: lnwiretest.RandPubKey(r),
+	}
+
+	var b bytes.Buffer
+	require.NoError(f, seed.Encode(&b))
+	f.Add(b.Bytes())
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		payloadHarness(
+			t, data, &lnwire.BlindedRouteData{},
+			&lnwire.BlindedRouteData{},
+		)
+	})
+}
@@ -0,0 +1,398 @@
+package hoppayload
+
+import (
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// Exit is the next hop of the final hop of a route. As BOLT 04 requires the
+// final hop to omit the next hop id, a payload with this next hop is encoded
+// without it.
+var Exit lnwire.ShortChannelID
+
+// Payload is the TLV payload delivered to a single hop of a route in the
+// onion, as specified in BOLT 04. It holds the forwarding instructions for
+// the hop, and for the final hop, the records that are used to settle the
+// payment.
+type Payload struct {
+	// AmtToForward is the amount to forward to the next hop, or the amount
+	// to pay to the final hop.
+	AmtToForward lnwire.MilliSatoshi
+
+	// OutgoingCltv is the CLTV expiry of the HTLC offered to the next
+	// hop, or the expected CLTV expiry of the HTLC at the final hop.
+	OutgoingCltv uint32
+
+	// NextHop is the short channel id of the channel the HTLC should be
+	// forwarded over. It is Exit for the final hop.
+	NextHop lnwire.ShortChannelID
+
+	// MPP holds the option_mpp record, which may only be included for the
+	// final hop.
+	MPP *record.MPP
+
+	// AMP holds the option_amp record, which may only be included for the
+	// final hop.
+	AMP *record.AMP
+
+	// Metadata is additional data that is sent along with the payment to
+	// the payee.
+	Metadata []byte
+
+	// CustomRecords are user-defined records in the custom type range.
+	CustomRecords record.CustomSet
+
+	// EncryptedData is the data that the creator of a blinded route
+	// addressed to the hop. It is only set for hops within a blinded
+	// route, whose intermediate hops read their forwarding instructions
+	// from it instead of the amount, expiry and next hop of the payload.
+	EncryptedData []byte
+
+	// BlindingPoint is the blinding point of the introduction node of a
+	// blinded route. It is only set for the introduction node.
+	BlindingPoint *btcec.PublicKey
+
+	// TotalAmtMsat is the total amount of a payment to a blinded route. It
+	// may only be included for the final hop of a blinded route.
+	TotalAmtMsat lnwire.MilliSatoshi
+}
+
+// IsFinalHop returns true if the payload is destined for the final hop of a
+// route.
+func (p *Payload) IsFinalHop() bool {
+	return p.NextHop == Exit
+}
+
+// isBlindedIntermediateHop returns true if the payload is destined for an
+// intermediate hop of a blinded route, which only receives its encrypted data
+// and optionally the blinding point.
+func (p *Payload) isBlindedIntermediateHop() bool {
+	return p.EncryptedData != nil && !p.IsFinalHop()
+}
+
+// Validate checks that the payload only includes the records that are allowed
+// for its hop type by BOLT 04, and that all custom records are within the
+// custom type range.
+func (p *Payload) Validate() error {
+	isFinalHop := p.IsFinalHop()
+
+	switch {
+	// Intermediate nodes should never receive MPP fields.
+	case !isFinalHop && p.MPP != nil:
+		return ErrInvalidPayload{
+			Type:      record.MPPOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// Intermediate nodes should never receive AMP fields.
+	case !isFinalHop && p.AMP != nil:
+		return ErrInvalidPayload{
+			Type:      record.AMPOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// Only the final node of a blinded route receives the total amount.
+	case p.TotalAmtMsat != 0 && (!isFinalHop || p.EncryptedData == nil):
+		return ErrInvalidPayload{
+			Type:      record.TotalAmtMsatBlindedType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// The blinding point is only given to the introduction node of a
+	// blinded route.
+	case p.BlindingPoint != nil && p.EncryptedData == nil:
+		return ErrInvalidPayload{
+			Type:      record.BlindingPointOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// The forwarding instructions of intermediate nodes within a blinded
+	// route are part of their encrypted data, so they must not receive
+	// any other records.
+	case p.isBlindedIntermediateHop() && p.Metadata != nil:
+		return ErrInvalidPayload{
+			Type:      record.MetadataOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	case p.isBlindedIntermediateHop() && len(p.CustomRecords) > 0:
+		return ErrInvalidPayload{
+			Type:      record.CustomTypeStart,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+	}
+
+	return p.CustomRecords.Validate()
+}
+
+// Encode validates the payload and writes it to the passed io.Writer as a
+// canonical TLV stream, which can be placed directly into the onion.
+func (p *Payload) Encode(w io.Writer) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	var records []tlv.Record
+
+	// Every hop outside of a blinded route and the final hop of a blinded
+	// route must have an amount to forward and CLTV expiry. Intermediate
+	// hops of a blinded route find them in their encrypted data instead.
+	amt := uint64(p.AmtToForward)
+	cltv := p.OutgoingCltv
+	if !p.isBlindedIntermediateHop() {
+		records = append(records,
+			record.NewAmtToFwdRecord(&amt),
+			record.NewLockTimeRecord(&cltv),
+		)
+	}
+
+	// BOLT 04 says the next_hop_id should be omitted for the final hop,
+	// but present for all others outside of a blinded route.
+	if !p.IsFinalHop() && p.EncryptedData == nil {
+		nextHop := p.NextHop.ToUint64()
+		records = append(records, record.NewNextHopIDRecord(&nextHop))
+	}
+
+	if p.EncryptedData != nil {
+		encryptedData := p.EncryptedData
+		records = append(
+			records, record.NewEncryptedDataRecord(&encryptedData),
+		)
+	}
+
+	if p.BlindingPoint != nil {
+		blindingPoint := p.BlindingPoint
+		records = append(
+			records, record.NewBlindingPointRecord(&blindingPoint),
+		)
+	}
+
+	if p.TotalAmtMsat != 0 {
+		totalAmt := uint64(p.TotalAmtMsat)
+		records = append(
+			records, record.NewTotalAmtMsatBlindedRecord(&totalAmt),
+		)
+	}
+
+	if p.MPP != nil {
+		records = append(records, p.MPP.Record())
+	}
+
+	if p.AMP != nil {
+		records = append(records, p.AMP.Record())
+	}
+
+	if p.Metadata != nil {
+		metadata := p.Metadata
+		records = append(records, record.NewMetadataRecord(&metadata))
+	}
+
+	records = append(records, tlv.MapToRecords(p.CustomRecords)...)
+
+	// To ensure we produce a canonical stream, we'll sort the records
+	// before encoding them.
+	tlv.SortRecords(records)
+
+	tlvStream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+
+	return tlvStream.Encode(w)
+}
+
+// Decode parses a TLV onion payload from the passed io.Reader and validates
+// that it includes or omits the records required by BOLT 04 for its hop type.
+// If the payload is invalid, an ErrInvalidPayload is returned.
+func Decode(r io.Reader) (*Payload, error) {
+	var (
+		cid      uint64
+		amt      uint64
+		cltv     uint32
+		mpp      = &record.MPP{}
+		amp      = &record.AMP{}
+		metadata []byte
+	)
+
+	tlvStream, err := tlv.NewStream(
+		record.NewAmtToFwdRecord(&amt),
+		record.NewLockTimeRecord(&cltv),
+		record.NewNextHopIDRecord(&cid),
+		mpp.Record(),
+		amp.Record(),
+		record.NewMetadataRecord(&metadata),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTypes, err := tlvStream.DecodeWithParsedTypes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate whether the sender properly included or omitted tlv records
+	// in accordance with BOLT 04.
+	nextHop := lnwire.NewShortChanIDFromInt(cid)
+	err = ValidateParsedTypes(parsedTypes, nextHop)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check for violation of the rules for mandatory fields.
+	violatingType := getMinRequiredViolation(parsedTypes)
+	if violatingType != nil {
+		return nil, ErrInvalidPayload{
+			Type:      *violatingType,
+			Violation: RequiredViolation,
+			FinalHop:  nextHop == Exit,
+		}
+	}
+
+	// If no MPP field was parsed, set the MPP field on the resulting
+	// payload to nil.
+	if _, ok := parsedTypes[record.MPPOnionType]; !ok {
+		mpp = nil
+	}
+
+	// If no AMP field was parsed, set the AMP field on the resulting
+	// payload to nil.
+	if _, ok := parsedTypes[record.AMPOnionType]; !ok {
+		amp = nil
+	}
+
+	// If no metadata field was parsed, set the metadata field on the
+	// resulting payload to nil.
+	if _, ok := parsedTypes[record.MetadataOnionType]; !ok {
+		metadata = nil
+	}
+
+	return &Payload{
+		AmtToForward:  lnwire.MilliSatoshi(amt),
+		OutgoingCltv:  cltv,
+		NextHop:       nextHop,
+		MPP:           mpp,
+		AMP:           amp,
+		Metadata:      metadata,
+		CustomRecords: NewCustomRecords(parsedTypes),
+	}, nil
+}
+
+// NewCustomRecords filters the types parsed from the tlv stream for custom
+// records.
+func NewCustomRecords(parsedTypes tlv.TypeMap) record.CustomSet {
+	customRecords := make(record.CustomSet)
+	for t, parseResult := range parsedTypes {
+		if parseResult == nil || t < record.CustomTypeStart {
+			continue
+		}
+		customRecords[uint64(t)] = parseResult
+	}
+	return customRecords
+}
+
+// ValidateParsedTypes checks the types parsed from a hop payload to ensure
+// that the proper fields are either included or omitted. The requirements
+// for this method are described in BOLT 04.
+func ValidateParsedTypes(parsedTypes tlv.TypeMap,
+	nextHop lnwire.ShortChannelID) error {
+
+	isFinalHop := nextHop == Exit
+
+	_, hasAmt := parsedTypes[record.AmtOnionType]
+	_, hasLockTime := parsedTypes[record.LockTimeOnionType]
+	_, hasNextHop := parsedTypes[record.NextHopOnionType]
+	_, hasMPP := parsedTypes[record.MPPOnionType]
+	_, hasAMP := parsedTypes[record.AMPOnionType]
+
+	switch {
+
+	// All hops must include an amount to forward.
+	case !hasAmt:
+		return ErrInvalidPayload{
+			Type:      record.AmtOnionType,
+			Violation: OmittedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// All hops must include a cltv expiry.
+	case !hasLockTime:
+		return ErrInvalidPayload{
+			Type:      record.LockTimeOnionType,
+			Violation: OmittedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// The exit hop should omit the next hop id. If nextHop != Exit, the
+	// sender must have included a record, so we don't need to test for its
+	// inclusion at intermediate hops directly.
+	case isFinalHop && hasNextHop:
+		return ErrInvalidPayload{
+			Type:      record.NextHopOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  true,
+		}
+
+	// Intermediate nodes should never receive MPP fields.
+	case !isFinalHop && hasMPP:
+		return ErrInvalidPayload{
+			Type:      record.MPPOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// Intermediate nodes should never receive AMP fields.
+	case !isFinalHop && hasAMP:
+		return ErrInvalidPayload{
+			Type:      record.AMPOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+	}
+
+	return nil
+}
+
+// getMinRequiredViolation checks for unrecognized required (even) fields in the
+// standard range and returns the lowest required type. Always returning the
+// lowest required type allows a failure message to be deterministic.
+func getMinRequiredViolation(set tlv.TypeMap) *tlv.Type {
+	var (
+		requiredViolation        bool
+		minRequiredViolationType tlv.Type
+	)
+	for t, parseResult := range set {
+		// If a type is even but not known to us, we cannot process the
+		// payload. We are required to understand a field that we don't
+		// support.
+		//
+		// We always accept custom fields, because a higher level
+		// application may understand them.
+		if parseResult == nil || t%2 != 0 ||
+			t >= record.CustomTypeStart {
+
+			continue
+		}
+
+		if !requiredViolation || t < minRequiredViolationType {
+			minRequiredViolationType = t
+		}
+		requiredViolation = true
+	}
+
+	if requiredViolation {
+		return &minRequiredViolationType
+	}
+
+	return nil
+}
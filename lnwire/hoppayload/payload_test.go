@@ -0,0 +1,273 @@
+package hoppayload
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_, testBlindingPoint = btcec.PrivKeyFromBytes([]byte{1})
+)
+
+// TestPayloadEncodeDecode asserts that payloads survive an encode/decode round
+// trip unchanged.
+func TestPayloadEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	var (
+		paymentAddr = [32]byte{1}
+		rootShare   = [32]byte{2}
+		setID       = [32]byte{3}
+	)
+
+	tests := []struct {
+		name    string
+		payload *Payload
+	}{
+		{
+			name: "intermediate hop",
+			payload: &Payload{
+				AmtToForward:  1000,
+				OutgoingCltv:  500,
+				NextHop:       lnwire.NewShortChanIDFromInt(1),
+				CustomRecords: record.CustomSet{},
+			},
+		},
+		{
+			name: "final hop",
+			payload: &Payload{
+				AmtToForward: 1000,
+				OutgoingCltv: 500,
+				MPP: record.NewMPP(
+					2000, paymentAddr,
+				),
+				AMP: record.NewAMP(
+					rootShare, setID, 1,
+				),
+				Metadata: []byte{4, 5, 6},
+				CustomRecords: record.CustomSet{
+					record.CustomTypeStart: {7, 8},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b bytes.Buffer
+			require.NoError(t, test.payload.Encode(&b))
+
+			payload, err := Decode(&b)
+			require.NoError(t, err)
+			require.Equal(t, test.payload, payload)
+		})
+	}
+}
+
+// TestPayloadEncodeValidation asserts that payloads that include records that
+// aren't allowed for their hop type can't be encoded.
+func TestPayloadEncodeValidation(t *testing.T) {
+	t.Parallel()
+
+	nextHop := lnwire.NewShortChanIDFromInt(1)
+
+	tests := []struct {
+		name    string
+		payload *Payload
+		expErr  error
+	}{
+		{
+			name: "intermediate hop with mpp",
+			payload: &Payload{
+				NextHop: nextHop,
+				MPP:     record.NewMPP(1000, [32]byte{}),
+			},
+			expErr: ErrInvalidPayload{
+				Type:      record.MPPOnionType,
+				Violation: IncludedViolation,
+			},
+		},
+		{
+			name: "intermediate hop with amp",
+			payload: &Payload{
+				NextHop: nextHop,
+				AMP:     record.NewAMP([32]byte{}, [32]byte{}, 0),
+			},
+			expErr: ErrInvalidPayload{
+				Type:      record.AMPOnionType,
+				Violation: IncludedViolation,
+			},
+		},
+		{
+			name: "blinded intermediate hop with total amount",
+			payload: &Payload{
+				NextHop:       nextHop,
+				EncryptedData: []byte{1},
+				TotalAmtMsat:  1000,
+			},
+			expErr: ErrInvalidPayload{
+				Type:      record.TotalAmtMsatBlindedType,
+				Violation: IncludedViolation,
+			},
+		},
+		{
+			name: "final hop with total amount outside blinded route",
+			payload: &Payload{
+				TotalAmtMsat: 1000,
+			},
+			expErr: ErrInvalidPayload{
+				Type:      record.TotalAmtMsatBlindedType,
+				Violation: IncludedViolation,
+				FinalHop:  true,
+			},
+		},
+		{
+			name: "blinding point outside blinded route",
+			payload: &Payload{
+				NextHop:       nextHop,
+				BlindingPoint: testBlindingPoint,
+			},
+			expErr: ErrInvalidPayload{
+				Type:      record.BlindingPointOnionType,
+				Violation: IncludedViolation,
+			},
+		},
+		{
+			name: "blinded intermediate hop with metadata",
+			payload: &Payload{
+				NextHop:       nextHop,
+				EncryptedData: []byte{1},
+				Metadata:      []byte{2},
+			},
+			expErr: ErrInvalidPayload{
+				Type:      record.MetadataOnionType,
+				Violation: IncludedViolation,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b bytes.Buffer
+			err := test.payload.Encode(&b)
+			require.Equal(t, test.expErr, err)
+		})
+	}
+
+	// Custom records outside of the custom type range are rejected as
+	// well, as they could collide with the records of the payload.
+	payload := &Payload{
+		CustomRecords: record.CustomSet{
+			uint64(record.AmtOnionType): {1},
+		},
+	}
+
+	var b bytes.Buffer
+	require.Error(t, payload.Encode(&b))
+}
+
+// TestPayloadEncodeBlinded asserts that the payloads of hops within a blinded
+// route only include the records that BOLT 04 allows for them.
+func TestPayloadEncodeBlinded(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		payload  *Payload
+		expTypes []tlv.Type
+	}{
+		{
+			name: "introduction node",
+			payload: &Payload{
+				AmtToForward:  1000,
+				OutgoingCltv:  500,
+				NextHop:       lnwire.NewShortChanIDFromInt(1),
+				EncryptedData: []byte{1, 2, 3},
+				BlindingPoint: testBlindingPoint,
+			},
+			expTypes: []tlv.Type{
+				record.EncryptedDataOnionType,
+				record.BlindingPointOnionType,
+			},
+		},
+		{
+			name: "intermediate hop",
+			payload: &Payload{
+				AmtToForward:  1000,
+				OutgoingCltv:  500,
+				NextHop:       lnwire.NewShortChanIDFromInt(1),
+				EncryptedData: []byte{1, 2, 3},
+			},
+			expTypes: []tlv.Type{
+				record.EncryptedDataOnionType,
+			},
+		},
+		{
+			name: "final hop",
+			payload: &Payload{
+				AmtToForward:  1000,
+				OutgoingCltv:  500,
+				EncryptedData: []byte{1, 2, 3},
+				TotalAmtMsat:  2000,
+			},
+			expTypes: []tlv.Type{
+				record.AmtOnionType,
+				record.LockTimeOnionType,
+				record.EncryptedDataOnionType,
+				record.TotalAmtMsatBlindedType,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b bytes.Buffer
+			require.NoError(t, test.payload.Encode(&b))
+
+			var (
+				amt, cid, totalAmt uint64
+				cltv               uint32
+				encryptedData      []byte
+				blindingPoint      *btcec.PublicKey
+			)
+			tlvStream, err := tlv.NewStream(
+				record.NewAmtToFwdRecord(&amt),
+				record.NewLockTimeRecord(&cltv),
+				record.NewNextHopIDRecord(&cid),
+				record.NewEncryptedDataRecord(&encryptedData),
+				record.NewBlindingPointRecord(&blindingPoint),
+				record.NewTotalAmtMsatBlindedRecord(&totalAmt),
+			)
+			require.NoError(t, err)
+
+			parsedTypes, err := tlvStream.DecodeWithParsedTypes(&b)
+			require.NoError(t, err)
+			require.Len(t, parsedTypes, len(test.expTypes))
+			for _, expType := range test.expTypes {
+				require.Contains(t, parsedTypes, expType)
+			}
+
+			require.Equal(t, test.payload.EncryptedData, encryptedData)
+			require.Equal(t, test.payload.BlindingPoint, blindingPoint)
+			require.EqualValues(t, test.payload.TotalAmtMsat, totalAmt)
+		})
+	}
+}
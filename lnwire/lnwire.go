@@ -454,8 +454,8 @@ func ReadElement(r io.Reader, element interface{}) error {
 	var err error
 	switch e := element.(type) {
 	case *bool:
-		var b [1]byte
-		if _, err := io.ReadFull(r, b[:]); err != nil {
+		b := scratchBytes(r, 1)
+		if _, err := io.ReadFull(r, b); err != nil {
 			return err
 		}
 
@@ -476,65 +476,65 @@ func ReadElement(r io.Reader, element interface{}) error {
 
 		*e = alias
 	case *ShortChanIDEncoding:
-		var b [1]uint8
-		if _, err := r.Read(b[:]); err != nil {
+		b := scratchBytes(r, 1)
+		if _, err := r.Read(b); err != nil {
 			return err
 		}
 		*e = ShortChanIDEncoding(b[0])
 	case *uint8:
-		var b [1]uint8
-		if _, err := r.Read(b[:]); err != nil {
+		b := scratchBytes(r, 1)
+		if _, err := r.Read(b); err != nil {
 			return err
 		}
 		*e = b[0]
 	case *FundingFlag:
-		var b [1]uint8
-		if _, err := r.Read(b[:]); err != nil {
+		b := scratchBytes(r, 1)
+		if _, err := r.Read(b); err != nil {
 			return err
 		}
 		*e = FundingFlag(b[0])
 	case *uint16:
-		var b [2]byte
-		if _, err := io.ReadFull(r, b[:]); err != nil {
+		b := scratchBytes(r, 2)
+		if _, err := io.ReadFull(r, b); err != nil {
 			return err
 		}
-		*e = binary.BigEndian.Uint16(b[:])
+		*e = binary.BigEndian.Uint16(b)
 	case *ChanUpdateMsgFlags:
-		var b [1]uint8
-		if _, err := r.Read(b[:]); err != nil {
+		b := scratchBytes(r, 1)
+		if _, err := r.Read(b); err != nil {
 			return err
 		}
 		*e = ChanUpdateMsgFlags(b[0])
 	case *ChanUpdateChanFlags:
-		var b [1]uint8
-		if _, err := r.Read(b[:]); err != nil {
+		b := scratchBytes(r, 1)
+		if _, err := r.Read(b); err != nil {
 			return err
 		}
 		*e = ChanUpdateChanFlags(b[0])
 	case *uint32:
-		var b [4]byte
-		if _, err := io.ReadFull(r, b[:]); err != nil {
+		b := scratchBytes(r, 4)
+		if _, err := io.ReadFull(r, b); err != nil {
 			return err
 		}
-		*e = binary.BigEndian.Uint32(b[:])
+		*e = binary.BigEndian.Uint32(b)
 	case *uint64:
-		var b [8]byte
-		if _, err := io.ReadFull(r, b[:]); err != nil {
+		b := scratchBytes(r, 8)
+		if _, err := io.ReadFull(r, b); err != nil {
 			return err
 		}
-		*e = binary.BigEndian.Uint64(b[:])
+		*e = binary.BigEndian.Uint64(b)
 	case *MilliSatoshi:
-		var b [8]byte
-		if _, err := io.ReadFull(r, b[:]); err != nil {
+		b := scratchBytes(r, 8)
+		if _, err := io.ReadFull(r, b); err != nil {
 			return err
 		}
-		*e = MilliSatoshi(int64(binary.BigEndian.Uint64(b[:])))
+		*e = MilliSatoshi(int64(binary.BigEndian.Uint64(b)))
 	case *btcutil.Amount:
-		var b [8]byte
-		if _, err := io.ReadFull(r, b[:]); err != nil {
+		b := scratchBytes(r, 8)
+		if _, err := io.ReadFull(r, b); err != nil {
 			return err
 		}
-		*e = btcutil.Amount(int64(binary.BigEndian.Uint64(b[:])))
+		*e = btcutil.Amount(int64(binary.BigEndian.Uint64(b)))
 	case **btcec.PublicKey:
 		var b [btcec.PubKeyBytesLenCompressed]byte
 		if _, err = io.ReadFull(r, b[:]); err != nil {
@@ -556,11 +556,11 @@ func ReadElement(r io.Reader, element interface{}) error {
 		*e = f
 
 	case *[]Sig:
-		var l [2]byte
-		if _, err := io.ReadFull(r, l[:]); err != nil {
+		l := scratchBytes(r, 2)
+		if _, err := io.ReadFull(r, l); err != nil {
 			return err
 		}
-		numSigs := binary.BigEndian.Uint16(l[:])
+		numSigs := binary.BigEndian.Uint16(l)
 
 		var sigs []Sig
 		if numSigs > 0 {
@@ -579,44 +579,44 @@ func ReadElement(r io.Reader, element interface{}) error {
 			return err
 		}
 	case *OpaqueReason:
-		var l [2]byte
-		if _, err := io.ReadFull(r, l[:]); err != nil {
+		l := scratchBytes(r, 2)
+		if _, err := io.ReadFull(r, l); err != nil {
 			return err
 		}
-		reasonLen := binary.BigEndian.Uint16(l[:])
+		reasonLen := binary.BigEndian.Uint16(l)
 
 		*e = OpaqueReason(make([]byte, reasonLen))
 		if _, err := io.ReadFull(r, *e); err != nil {
 			return err
 		}
 	case *ErrorData:
-		var l [2]byte
-		if _, err := io.ReadFull(r, l[:]); err != nil {
+		l := scratchBytes(r, 2)
+		if _, err := io.ReadFull(r, l); err != nil {
 			return err
 		}
-		errorLen := binary.BigEndian.Uint16(l[:])
+		errorLen := binary.BigEndian.Uint16(l)
 
 		*e = ErrorData(make([]byte, errorLen))
 		if _, err := io.ReadFull(r, *e); err != nil {
 			return err
 		}
 	case *PingPayload:
-		var l [2]byte
-		if _, err := io.ReadFull(r, l[:]); err != nil {
+		l := scratchBytes(r, 2)
+		if _, err := io.ReadFull(r, l); err != nil {
 			return err
 		}
-		pingLen := binary.BigEndian.Uint16(l[:])
+		pingLen := binary.BigEndian.Uint16(l)
 
 		*e = PingPayload(make([]byte, pingLen))
 		if _, err := io.ReadFull(r, *e); err != nil {
 			return err
 		}
 	case *PongPayload:
-		var l [2]byte
-		if _, err := io.ReadFull(r, l[:]); err != nil {
+		l := scratchBytes(r, 2)
+		if _, err := io.ReadFull(r, l); err != nil {
 			return err
 		}
-		pongLen := binary.BigEndian.Uint16(l[:])
+		pongLen := binary.BigEndian.Uint16(l)
 
 		*e = PongPayload(make([]byte, pongLen))
 		if _, err := io.ReadFull(r, *e); err != nil {
@@ -646,12 +646,12 @@ func ReadElement(r io.Reader, element interface{}) error {
 			return err
 		}
 
-		var idxBytes [2]byte
-		_, err = io.ReadFull(r, idxBytes[:])
+		idxBytes := scratchBytes(r, 2)
+		_, err = io.ReadFull(r, idxBytes)
 		if err != nil {
 			return err
 		}
-		index := binary.BigEndian.Uint16(idxBytes[:])
+		index := binary.BigEndian.Uint16(idxBytes)
 
 		*e = wire.OutPoint{
 			Hash:  *hash,
@@ -667,25 +667,28 @@ func ReadElement(r io.Reader, element interface{}) error {
 		}
 
 	case *ShortChannelID:
-		var blockHeight [4]byte
-		if _, err = io.ReadFull(r, blockHeight[1:]); err != nil {
+		b := scratchBytes(r, 4)
+
+		b[0] = 0
+		if _, err = io.ReadFull(r, b[1:]); err != nil {
 			return err
 		}
+		blockHeight := binary.BigEndian.Uint32(b)
 
-		var txIndex [4]byte
-		if _, err = io.ReadFull(r, txIndex[1:]); err != nil {
+		b[0] = 0
+		if _, err = io.ReadFull(r, b[1:]); err != nil {
 			return err
 		}
+		txIndex := binary.BigEndian.Uint32(b)
 
-		var txPosition [2]byte
-		if _, err = io.ReadFull(r, txPosition[:]); err != nil {
+		if _, err = io.ReadFull(r, b[:2]); err != nil {
 			return err
 		}
 
 		*e = ShortChannelID{
-			BlockHeight: binary.BigEndian.Uint32(blockHeight[:]),
-			TxIndex:     binary.BigEndian.Uint32(txIndex[:]),
-			TxPosition:  binary.BigEndian.Uint16(txPosition[:]),
+			BlockHeight: blockHeight,
+			TxIndex:     txIndex,
+			TxPosition:  binary.BigEndian.Uint16(b[:2]),
 		}
 
 	case *[]net.Addr:
@@ -829,6 +832,10 @@ func ReadElement(r io.Reader, element interface{}) error {
 				addrBytesRead = addrsLen
 			}
 
+			if err := countItems(r, itemAddress, 1); err != nil {
+				return err
+			}
+
 			addresses = append(addresses, address)
 		}
 
@@ -0,0 +1,891 @@
+// Package lnwiretest provides randomized but valid lnwire messages for
+// property-based tests and to seed fuzz targets. The generators respect the
+// constraints that are enforced when messages are encoded and decoded, such
+// as the maximum message size and the length of delivery addresses, so that
+// every generated message survives an encode/decode round trip unchanged.
+package lnwiretest
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"math"
+	"math/rand"
+	"net"
+	"reflect"
+	"sort"
+	"testing/quick"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tor"
+)
+
+const (
+	// maxGenExtraData is the maximum number of extra opaque bytes that are
+	// appended to generated messages.
+	maxGenExtraData = 1000
+
+	// maxGenShortChanIDs is the maximum number of short channel IDs that
+	// are included in generated gossip queries and replies.
+	maxGenShortChanIDs = 5000
+
+	// deliveryAddressMaxSize is the maximum size of a delivery address
+	// that can be encoded on the wire.
+	deliveryAddressMaxSize = 34
+
+	// genAliasLetters are the characters generated node aliases consist
+	// of.
+	genAliasLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+)
+
+// generators maps every message type below the custom range to the function
+// that generates random messages of that type.
+var generators = map[lnwire.MessageType]func(*rand.Rand) lnwire.Message{
+	lnwire.MsgInit: func(r *rand.Rand) lnwire.Message {
+		return RandInit(r)
+	},
+	lnwire.MsgWarning: func(r *rand.Rand) lnwire.Message {
+		return RandWarning(r)
+	},
+	lnwire.MsgError: func(r *rand.Rand) lnwire.Message {
+		return RandError(r)
+	},
+	lnwire.MsgPing: func(r *rand.Rand) lnwire.Message {
+		return RandPing(r)
+	},
+	lnwire.MsgPong: func(r *rand.Rand) lnwire.Message {
+		return RandPong(r)
+	},
+	lnwire.MsgOpenChannel: func(r *rand.Rand) lnwire.Message {
+		return RandOpenChannel(r)
+	},
+	lnwire.MsgAcceptChannel: func(r *rand.Rand) lnwire.Message {
+		return RandAcceptChannel(r)
+	},
+	lnwire.MsgFundingCreated: func(r *rand.Rand) lnwire.Message {
+		return RandFundingCreated(r)
+	},
+	lnwire.MsgFundingSigned: func(r *rand.Rand) lnwire.Message {
+		return RandFundingSigned(r)
+	},
+	lnwire.MsgFundingLocked: func(r *rand.Rand) lnwire.Message {
+		return RandFundingLocked(r)
+	},
+	lnwire.MsgShutdown: func(r *rand.Rand) lnwire.Message {
+		return RandShutdown(r)
+	},
+	lnwire.MsgClosingSigned: func(r *rand.Rand) lnwire.Message {
+		return RandClosingSigned(r)
+	},
+	lnwire.MsgSpliceInit: func(r *rand.Rand) lnwire.Message {
+		return RandSpliceInit(r)
+	},
+	lnwire.MsgSpliceAck: func(r *rand.Rand) lnwire.Message {
+		return RandSpliceAck(r)
+	},
+	lnwire.MsgSpliceLocked: func(r *rand.Rand) lnwire.Message {
+		return RandSpliceLocked(r)
+	},
+	lnwire.MsgUpdateAddHTLC: func(r *rand.Rand) lnwire.Message {
+		return RandUpdateAddHTLC(r)
+	},
+	lnwire.MsgUpdateFulfillHTLC: func(r *rand.Rand) lnwire.Message {
+		return RandUpdateFulfillHTLC(r)
+	},
+	lnwire.MsgUpdateFailHTLC: func(r *rand.Rand) lnwire.Message {
+		return RandUpdateFailHTLC(r)
+	},
+	lnwire.MsgCommitSig: func(r *rand.Rand) lnwire.Message {
+		return RandCommitSig(r)
+	},
+	lnwire.MsgRevokeAndAck: func(r *rand.Rand) lnwire.Message {
+		return RandRevokeAndAck(r)
+	},
+	lnwire.MsgUpdateFee: func(r *rand.Rand) lnwire.Message {
+		return RandUpdateFee(r)
+	},
+	lnwire.MsgUpdateFailMalformedHTLC: func(r *rand.Rand) lnwire.Message {
+		return RandUpdateFailMalformedHTLC(r)
+	},
+	lnwire.MsgChannelReestablish: func(r *rand.Rand) lnwire.Message {
+		return RandChannelReestablish(r)
+	},
+	lnwire.MsgChannelAnnouncement: func(r *rand.Rand) lnwire.Message {
+		return RandChannelAnnouncement(r)
+	},
+	lnwire.MsgNodeAnnouncement: func(r *rand.Rand) lnwire.Message {
+		return RandNodeAnnouncement(r)
+	},
+	lnwire.MsgChannelUpdate: func(r *rand.Rand) lnwire.Message {
+		return RandChannelUpdate(r)
+	},
+	lnwire.MsgAnnounceSignatures: func(r *rand.Rand) lnwire.Message {
+		return RandAnnounceSignatures(r)
+	},
+	lnwire.MsgGossipTimestampRange: func(r *rand.Rand) lnwire.Message {
+		return RandGossipTimestampRange(r)
+	},
+	lnwire.MsgOnionMessage: func(r *rand.Rand) lnwire.Message {
+		return RandOnionMessage(r)
+	},
+	lnwire.MsgQueryShortChanIDs: func(r *rand.Rand) lnwire.Message {
+		return RandQueryShortChanIDs(r)
+	},
+	lnwire.MsgReplyShortChanIDsEnd: func(r *rand.Rand) lnwire.Message {
+		return RandReplyShortChanIDsEnd(r)
+	},
+	lnwire.MsgQueryChannelRange: func(r *rand.Rand) lnwire.Message {
+		return RandQueryChannelRange(r)
+	},
+	lnwire.MsgReplyChannelRange: func(r *rand.Rand) lnwire.Message {
+		return RandReplyChannelRange(r)
+	},
+}
+
+// MessageTypes returns the types of all messages below the custom range that
+// random messages can be generated for, in ascending order.
+func MessageTypes() []lnwire.MessageType {
+	msgTypes := make([]lnwire.MessageType, 0, len(generators))
+	for msgType := range generators {
+		msgTypes = append(msgTypes, msgType)
+	}
+	sort.Slice(msgTypes, func(i, j int) bool {
+		return msgTypes[i] < msgTypes[j]
+	})
+
+	return msgTypes
+}
+
+// RandMessage returns a random, valid message of the given type. Messages of
+// a type within the custom range are returned as Custom messages.
+func RandMessage(msgType lnwire.MessageType, r *rand.Rand) (lnwire.Message,
+	error) {
+
+	if msgType >= lnwire.CustomTypeStart {
+		msg := RandCustom(r)
+		msg.Type = msgType
+
+		return msg, nil
+	}
+
+	gen, ok := generators[msgType]
+	if !ok {
+		return nil, fmt.Errorf("no generator for message type %v",
+			msgType)
+	}
+
+	return gen(r), nil
+}
+
+// Msg wraps a random message of any of the known types, including custom
+// messages. It implements the quick.Generator interface, so that properties
+// checked with testing/quick can take random messages as arguments.
+type Msg struct {
+	lnwire.Message
+}
+
+// A compile-time check to ensure Msg implements the quick.Generator
+// interface.
+var _ quick.Generator = Msg{}
+
+// Generate returns a Msg wrapping a random message of a random type.
+//
+// NOTE: This is part of the quick.Generator interface.
+func (Msg) Generate(r *rand.Rand, _ int) reflect.Value {
+	// Every known type is picked with the same probability, and one more
+	// slot is used for custom messages.
+	msgTypes := MessageTypes()
+	idx := r.Intn(len(msgTypes) + 1)
+	if idx == len(msgTypes) {
+		return reflect.ValueOf(Msg{RandCustom(r)})
+	}
+
+	return reflect.ValueOf(Msg{generators[msgTypes[idx]](r)})
+}
+
+// RandBytes returns a slice of n random bytes. The slice is never nil, which
+// matches how empty byte slices are decoded.
+func RandBytes(r *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	_, _ = r.Read(b)
+
+	return b
+}
+
+// genExtraOpaqueData returns up to maxGenExtraData random bytes that are
+// appended to a message that doesn't parse its extra data as TLV records.
+func genExtraOpaqueData(r *rand.Rand) lnwire.ExtraOpaqueData {
+	return RandBytes(r, r.Intn(maxGenExtraData))
+}
+
+// genChannelID returns a random channel ID.
+func genChannelID(r *rand.Rand) lnwire.ChannelID {
+	var c lnwire.ChannelID
+	_, _ = r.Read(c[:])
+
+	return c
+}
+
+// genSig returns a random signature. Signatures are encoded as fixed-size
+// byte arrays, so any 64 bytes are a valid value on the wire.
+func genSig(r *rand.Rand) lnwire.Sig {
+	var s lnwire.Sig
+	_, _ = r.Read(s[:])
+
+	return s
+}
+
+// RandPubKey returns a public key derived from a random private key.
+func RandPubKey(r *rand.Rand) *btcec.PublicKey {
+	_, pub := btcec.PrivKeyFromBytes(RandBytes(r, 32))
+
+	return pub
+}
+
+// genRawKey returns a random public key in its compressed serialization.
+func genRawKey(r *rand.Rand) [33]byte {
+	var k [33]byte
+	copy(k[:], RandPubKey(r).SerializeCompressed())
+
+	return k
+}
+
+// genShortChanID returns a random short channel ID.
+func genShortChanID(r *rand.Rand) lnwire.ShortChannelID {
+	return lnwire.NewShortChanIDFromInt(uint64(r.Int63()))
+}
+
+// genShortChanIDs returns up to maxGenShortChanIDs random short channel IDs.
+// A nil slice is returned if there are none, which matches how an empty set
+// of short channel IDs is decoded.
+func genShortChanIDs(r *rand.Rand) []lnwire.ShortChannelID {
+	var sids []lnwire.ShortChannelID
+
+	numChanIDs := r.Intn(maxGenShortChanIDs)
+	for i := 0; i < numChanIDs; i++ {
+		sids = append(sids, genShortChanID(r))
+	}
+
+	return sids
+}
+
+// genEncodingType returns one of the supported short channel ID encodings.
+func genEncodingType(r *rand.Rand) lnwire.ShortChanIDEncoding {
+	if r.Intn(2) == 0 {
+		return lnwire.EncodingSortedZlib
+	}
+
+	return lnwire.EncodingSortedPlain
+}
+
+// genDeliveryAddress returns a random, non-empty delivery address of at most
+// the maximum size allowed on the wire. Empty addresses are tested
+// specifically.
+func genDeliveryAddress(r *rand.Rand) lnwire.DeliveryAddress {
+	size := r.Intn(deliveryAddressMaxSize) + 1
+
+	return RandBytes(r, size)
+}
+
+// genRawFeatureVector returns a feature vector with random bits set.
+func genRawFeatureVector(r *rand.Rand) *lnwire.RawFeatureVector {
+	featureVec := lnwire.NewRawFeatureVector()
+	for i := 0; i < 10000; i++ {
+		if r.Intn(2) == 0 {
+			featureVec.Set(lnwire.FeatureBit(i))
+		}
+	}
+
+	return featureVec
+}
+
+// genAlias returns a random node alias of printable letters.
+func genAlias(r *rand.Rand) lnwire.NodeAlias {
+	var a lnwire.NodeAlias
+	for i := range a {
+		a[i] = genAliasLetters[r.Intn(len(genAliasLetters))]
+	}
+
+	return a
+}
+
+// genPort returns a random port number.
+func genPort(r *rand.Rand) int {
+	return int(binary.BigEndian.Uint16(RandBytes(r, 2)))
+}
+
+// genOnionAddr returns a random onion address with a service ID of the given
+// decoded length.
+func genOnionAddr(r *rand.Rand, decodedLen int) *tor.OnionAddr {
+	onionService := tor.Base32Encoding.EncodeToString(
+		RandBytes(r, decodedLen),
+	)
+
+	return &tor.OnionAddr{
+		OnionService: onionService + tor.OnionSuffix,
+		Port:         genPort(r),
+	}
+}
+
+// genAddrs returns one random address of every type that can be announced.
+func genAddrs(r *rand.Rand) []net.Addr {
+	// The first byte of an opaque address is its type, so we set it to one
+	// that we definitely don't know about.
+	opaque := RandBytes(r, r.Intn(64)+1)
+	opaque[0] = math.MaxUint8
+
+	return []net.Addr{
+		&net.TCPAddr{IP: RandBytes(r, 4), Port: genPort(r)},
+		&net.TCPAddr{IP: RandBytes(r, 16), Port: genPort(r)},
+		genOnionAddr(r, tor.V2DecodedLen),
+		genOnionAddr(r, tor.V3DecodedLen),
+		&lnwire.OpaqueAddrs{Payload: opaque},
+	}
+}
+
+// RandInit returns a random Init message.
+func RandInit(r *rand.Rand) *lnwire.Init {
+	return lnwire.NewInitMessage(
+		genRawFeatureVector(r), genRawFeatureVector(r),
+	)
+}
+
+// genError returns a random lnwire.Error, with as much data as fits into a
+// message.
+func genError(r *rand.Rand) lnwire.Error {
+	return lnwire.Error{
+		ChanID: genChannelID(r),
+		Data: RandBytes(
+			r, r.Intn(lnwire.MaxMsgBody-len(lnwire.ChannelID{})-2),
+		),
+	}
+}
+
+// RandWarning returns a random Warning message.
+func RandWarning(r *rand.Rand) *lnwire.Warning {
+	return &lnwire.Warning{Error: genError(r)}
+}
+
+// RandError returns a random Error message.
+func RandError(r *rand.Rand) *lnwire.Error {
+	err := genError(r)
+
+	return &err
+}
+
+// RandPing returns a random Ping message that doesn't request more than
+// lnwire.MaxPongBytes and fits into a message.
+func RandPing(r *rand.Rand) *lnwire.Ping {
+	// The padding is prefixed with its length, and follows the 2 bytes of
+	// the number of requested pong bytes.
+	req := lnwire.Ping{
+		NumPongBytes: uint16(r.Intn(lnwire.MaxPongBytes + 1)),
+		PaddingBytes: RandBytes(r, r.Intn(lnwire.MaxMsgBody-3)),
+	}
+
+	return &req
+}
+
+// RandPong returns a random Pong message with at most lnwire.MaxPongBytes.
+func RandPong(r *rand.Rand) *lnwire.Pong {
+	req := lnwire.Pong{
+		PongBytes: RandBytes(r, r.Intn(lnwire.MaxPongBytes+1)),
+	}
+
+	return &req
+}
+
+// RandOpenChannel returns a random OpenChannel message. Half of the generated
+// messages carry the optional TLV records.
+func RandOpenChannel(r *rand.Rand) *lnwire.OpenChannel {
+	req := lnwire.OpenChannel{
+		FundingAmount:        btcutil.Amount(r.Int63()),
+		PushAmount:           lnwire.MilliSatoshi(r.Int63()),
+		DustLimit:            btcutil.Amount(r.Int63()),
+		MaxValueInFlight:     lnwire.MilliSatoshi(r.Int63()),
+		ChannelReserve:       btcutil.Amount(r.Int63()),
+		HtlcMinimum:          lnwire.MilliSatoshi(r.Int31()),
+		FeePerKiloWeight:     uint32(r.Int63()),
+		CsvDelay:             uint16(r.Int31()),
+		MaxAcceptedHTLCs:     uint16(r.Int31()),
+		FundingKey:           RandPubKey(r),
+		RevocationPoint:      RandPubKey(r),
+		PaymentPoint:         RandPubKey(r),
+		DelayedPaymentPoint:  RandPubKey(r),
+		HtlcPoint:            RandPubKey(r),
+		FirstCommitmentPoint: RandPubKey(r),
+		ChannelFlags:         lnwire.FundingFlag(uint8(r.Int31())),
+	}
+	_, _ = r.Read(req.ChainHash[:])
+	_, _ = r.Read(req.PendingChannelID[:])
+
+	// 1/2 chance empty TLV records.
+	if r.Intn(2) == 0 {
+		req.UpfrontShutdownScript = genDeliveryAddress(r)
+
+		channelType := lnwire.ChannelType(*genRawFeatureVector(r))
+		req.ChannelType = &channelType
+
+		leaseExpiry := lnwire.LeaseExpiry(r.Uint32())
+		req.LeaseExpiry = &leaseExpiry
+
+		req.RequestFunds = &lnwire.RequestFunds{
+			RequestedAmt: btcutil.Amount(r.Int63()),
+			BlockHeight:  r.Uint32(),
+		}
+	} else {
+		req.UpfrontShutdownScript = []byte{}
+	}
+
+	// 1/2 chance additional TLV data.
+	if r.Intn(2) == 0 {
+		req.ExtraData = []byte{0xfd, 0x00, 0xff, 0x00}
+	}
+
+	return &req
+}
+
+// RandAcceptChannel returns a random AcceptChannel message. Half of the
+// generated messages carry the optional TLV records.
+func RandAcceptChannel(r *rand.Rand) *lnwire.AcceptChannel {
+	req := lnwire.AcceptChannel{
+		DustLimit:            btcutil.Amount(r.Int63()),
+		MaxValueInFlight:     lnwire.MilliSatoshi(r.Int63()),
+		ChannelReserve:       btcutil.Amount(r.Int63()),
+		MinAcceptDepth:       uint32(r.Int31()),
+		HtlcMinimum:          lnwire.MilliSatoshi(r.Int31()),
+		CsvDelay:             uint16(r.Int31()),
+		MaxAcceptedHTLCs:     uint16(r.Int31()),
+		FundingKey:           RandPubKey(r),
+		RevocationPoint:      RandPubKey(r),
+		PaymentPoint:         RandPubKey(r),
+		DelayedPaymentPoint:  RandPubKey(r),
+		HtlcPoint:            RandPubKey(r),
+		FirstCommitmentPoint: RandPubKey(r),
+	}
+	_, _ = r.Read(req.PendingChannelID[:])
+
+	// 1/2 chance empty TLV records.
+	if r.Intn(2) == 0 {
+		req.UpfrontShutdownScript = genDeliveryAddress(r)
+
+		channelType := lnwire.ChannelType(*genRawFeatureVector(r))
+		req.ChannelType = &channelType
+
+		leaseExpiry := lnwire.LeaseExpiry(r.Uint32())
+		req.LeaseExpiry = &leaseExpiry
+
+		req.WillFund = &lnwire.WillFund{LeaseRates: lnwire.LeaseRates{
+			FundingFeeBase:    btcutil.Amount(r.Uint32()),
+			FundingFeeRate:    uint16(r.Uint32()),
+			ChannelFeeMaxBase: lnwire.MilliSatoshi(r.Uint32()),
+			ChannelFeeMaxRate: uint16(r.Uint32()),
+		}}
+	} else {
+		req.UpfrontShutdownScript = []byte{}
+	}
+
+	// 1/2 chance additional TLV data.
+	if r.Intn(2) == 0 {
+		req.ExtraData = []byte{0xfd, 0x00, 0xff, 0x00}
+	}
+
+	return &req
+}
+
+// RandFundingCreated returns a random FundingCreated message. The index of the
+// funding outpoint is limited to 16 bits, as mandated by the wire encoding.
+func RandFundingCreated(r *rand.Rand) *lnwire.FundingCreated {
+	req := lnwire.FundingCreated{
+		CommitSig: genSig(r),
+		ExtraData: genExtraOpaqueData(r),
+	}
+	_, _ = r.Read(req.PendingChannelID[:])
+	_, _ = r.Read(req.FundingPoint.Hash[:])
+	req.FundingPoint.Index = uint32(r.Intn(math.MaxUint16 + 1))
+
+	return &req
+}
+
+// RandFundingSigned returns a random FundingSigned message.
+func RandFundingSigned(r *rand.Rand) *lnwire.FundingSigned {
+	req := lnwire.FundingSigned{
+		ChanID:    genChannelID(r),
+		CommitSig: genSig(r),
+		ExtraData: genExtraOpaqueData(r),
+	}
+
+	return &req
+}
+
+// RandFundingLocked returns a random FundingLocked message. Half of the
+// generated messages carry an alias short channel ID.
+func RandFundingLocked(r *rand.Rand) *lnwire.FundingLocked {
+	req := lnwire.NewFundingLocked(genChannelID(r), RandPubKey(r))
+
+	if r.Intn(2) == 0 {
+		aliasScid := lnwire.NewShortChanIDFromInt(r.Uint64())
+		req.AliasScid = &aliasScid
+	}
+
+	return req
+}
+
+// RandShutdown returns a random Shutdown message.
+func RandShutdown(r *rand.Rand) *lnwire.Shutdown {
+	req := lnwire.Shutdown{
+		ChannelID: genChannelID(r),
+		Address:   genDeliveryAddress(r),
+		ExtraData: genExtraOpaqueData(r),
+	}
+
+	return &req
+}
+
+// RandClosingSigned returns a random ClosingSigned message.
+func RandClosingSigned(r *rand.Rand) *lnwire.ClosingSigned {
+	req := lnwire.ClosingSigned{
+		ChannelID:   genChannelID(r),
+		FeeSatoshis: btcutil.Amount(r.Int63()),
+		Signature:   genSig(r),
+		ExtraData:   genExtraOpaqueData(r),
+	}
+
+	return &req
+}
+
+// RandSpliceInit returns a random SpliceInit message. The funding contribution
+// may be negative, and half of the generated messages require confirmed inputs.
+func RandSpliceInit(r *rand.Rand) *lnwire.SpliceInit {
+	req := lnwire.SpliceInit{
+		ChanID:                 genChannelID(r),
+		FundingContribution:    btcutil.Amount(r.Int63() - r.Int63()),
+		FundingFeePerKw:        r.Uint32(),
+		Locktime:               r.Uint32(),
+		FundingKey:             RandPubKey(r),
+		RequireConfirmedInputs: r.Intn(2) == 0,
+		ExtraData:              make([]byte, 0),
+	}
+
+	return &req
+}
+
+// RandSpliceAck returns a random SpliceAck message. The funding contribution
+// may be negative, and half of the generated messages require confirmed inputs.
+func RandSpliceAck(r *rand.Rand) *lnwire.SpliceAck {
+	req := lnwire.SpliceAck{
+		ChanID:                 genChannelID(r),
+		FundingContribution:    btcutil.Amount(r.Int63() - r.Int63()),
+		FundingKey:             RandPubKey(r),
+		RequireConfirmedInputs: r.Intn(2) == 0,
+		ExtraData:              make([]byte, 0),
+	}
+
+	return &req
+}
+
+// RandSpliceLocked returns a random SpliceLocked message.
+func RandSpliceLocked(r *rand.Rand) *lnwire.SpliceLocked {
+	req := lnwire.SpliceLocked{
+		ChanID:    genChannelID(r),
+		ExtraData: genExtraOpaqueData(r),
+	}
+	_, _ = r.Read(req.SpliceTxid[:])
+
+	return &req
+}
+
+// RandUpdateAddHTLC returns a random UpdateAddHTLC message.
+func RandUpdateAddHTLC(r *rand.Rand) *lnwire.UpdateAddHTLC {
+	req := lnwire.UpdateAddHTLC{
+		ChanID:    genChannelID(r),
+		ID:        r.Uint64(),
+		Amount:    lnwire.MilliSatoshi(r.Uint64()),
+		Expiry:    r.Uint32(),
+		ExtraData: genExtraOpaqueData(r),
+	}
+	_, _ = r.Read(req.PaymentHash[:])
+	_, _ = r.Read(req.OnionBlob[:])
+
+	return &req
+}
+
+// RandUpdateFulfillHTLC returns a random UpdateFulfillHTLC message.
+func RandUpdateFulfillHTLC(r *rand.Rand) *lnwire.UpdateFulfillHTLC {
+	req := lnwire.UpdateFulfillHTLC{
+		ChanID:    genChannelID(r),
+		ID:        r.Uint64(),
+		ExtraData: genExtraOpaqueData(r),
+	}
+	_, _ = r.Read(req.PaymentPreimage[:])
+
+	return &req
+}
+
+// RandUpdateFailHTLC returns a random UpdateFailHTLC message. The size of the
+// failure reason is that of a regular onion error.
+func RandUpdateFailHTLC(r *rand.Rand) *lnwire.UpdateFailHTLC {
+	req := lnwire.UpdateFailHTLC{
+		ChanID:    genChannelID(r),
+		ID:        r.Uint64(),
+		Reason:    RandBytes(r, r.Intn(lnwire.FailureMessageLength*2)),
+		ExtraData: genExtraOpaqueData(r),
+	}
+
+	return &req
+}
+
+// RandCommitSig returns a random CommitSig message with at most as many htlc
+// signatures as there can be htlcs on a commitment.
+func RandCommitSig(r *rand.Rand) *lnwire.CommitSig {
+	req := lnwire.NewCommitSig()
+	req.ChanID = genChannelID(r)
+	req.CommitSig = genSig(r)
+	req.ExtraData = genExtraOpaqueData(r)
+
+	// Only create the slice if there will be any signatures in it, as an
+	// empty set of signatures is decoded as a nil slice.
+	numSigs := r.Intn(input.MaxHTLCNumber + 1)
+	if numSigs > 0 {
+		req.HtlcSigs = make([]lnwire.Sig, numSigs)
+	}
+	for i := range req.HtlcSigs {
+		req.HtlcSigs[i] = genSig(r)
+	}
+
+	return req
+}
+
+// RandRevokeAndAck returns a random RevokeAndAck message.
+func RandRevokeAndAck(r *rand.Rand) *lnwire.RevokeAndAck {
+	req := lnwire.NewRevokeAndAck()
+	req.ChanID = genChannelID(r)
+	req.NextRevocationKey = RandPubKey(r)
+	req.ExtraData = genExtraOpaqueData(r)
+	_, _ = r.Read(req.Revocation[:])
+
+	return req
+}
+
+// RandUpdateFee returns a random UpdateFee message.
+func RandUpdateFee(r *rand.Rand) *lnwire.UpdateFee {
+	req := lnwire.UpdateFee{
+		ChanID:    genChannelID(r),
+		FeePerKw:  r.Uint32(),
+		ExtraData: genExtraOpaqueData(r),
+	}
+
+	return &req
+}
+
+// RandUpdateFailMalformedHTLC returns a random UpdateFailMalformedHTLC message.
+func RandUpdateFailMalformedHTLC(r *rand.Rand) *lnwire.UpdateFailMalformedHTLC {
+	req := lnwire.UpdateFailMalformedHTLC{
+		ChanID:      genChannelID(r),
+		ID:          r.Uint64(),
+		FailureCode: lnwire.FailCode(r.Intn(math.MaxUint16 + 1)),
+		ExtraData:   genExtraOpaqueData(r),
+	}
+	_, _ = r.Read(req.ShaOnionBlob[:])
+
+	return &req
+}
+
+// RandChannelReestablish returns a random ChannelReestablish message. Half of
+// the generated messages carry the optional data loss protection fields, and
+// half of those the next funding txid TLV record.
+func RandChannelReestablish(r *rand.Rand) *lnwire.ChannelReestablish {
+	req := lnwire.ChannelReestablish{
+		ChanID:                 genChannelID(r),
+		NextLocalCommitHeight:  uint64(r.Int63()),
+		RemoteCommitTailHeight: uint64(r.Int63()),
+		ExtraData:              make([]byte, 0),
+	}
+
+	if r.Intn(2) == 0 {
+		_, _ = r.Read(req.LastRemoteCommitSecret[:])
+		req.LocalUnrevokedCommitPoint = RandPubKey(r)
+
+		if r.Intn(2) == 0 {
+			var txid lnwire.NextFundingTxid
+			_, _ = r.Read(txid[:])
+			req.NextFundingTxid = &txid
+		}
+	}
+
+	return &req
+}
+
+// RandChannelAnnouncement returns a random ChannelAnnouncement message.
+func RandChannelAnnouncement(r *rand.Rand) *lnwire.ChannelAnnouncement {
+	req := lnwire.ChannelAnnouncement{
+		NodeSig1:        genSig(r),
+		NodeSig2:        genSig(r),
+		BitcoinSig1:     genSig(r),
+		BitcoinSig2:     genSig(r),
+		Features:        genRawFeatureVector(r),
+		ShortChannelID:  genShortChanID(r),
+		NodeID1:         genRawKey(r),
+		NodeID2:         genRawKey(r),
+		BitcoinKey1:     genRawKey(r),
+		BitcoinKey2:     genRawKey(r),
+		ExtraOpaqueData: genExtraOpaqueData(r),
+	}
+	_, _ = r.Read(req.ChainHash[:])
+
+	return &req
+}
+
+// RandNodeAnnouncement returns a random NodeAnnouncement message, announcing an
+// address of every type.
+func RandNodeAnnouncement(r *rand.Rand) *lnwire.NodeAnnouncement {
+	req := lnwire.NodeAnnouncement{
+		Signature: genSig(r),
+		Features:  genRawFeatureVector(r),
+		Timestamp: uint32(r.Int31()),
+		NodeID:    genRawKey(r),
+		RGBColor: color.RGBA{
+			R: uint8(r.Int31()),
+			G: uint8(r.Int31()),
+			B: uint8(r.Int31()),
+		},
+		Alias:           genAlias(r),
+		Addresses:       genAddrs(r),
+		ExtraOpaqueData: genExtraOpaqueData(r),
+	}
+
+	return &req
+}
+
+// RandChannelUpdate returns a random ChannelUpdate message. The max htlc field
+// is only set if it is flagged as being part of the update, as it's ignored
+// otherwise.
+func RandChannelUpdate(r *rand.Rand) *lnwire.ChannelUpdate {
+	msgFlags := lnwire.ChanUpdateMsgFlags(r.Int31())
+	maxHtlc := lnwire.MilliSatoshi(r.Int63())
+	if msgFlags&lnwire.ChanUpdateOptionMaxHtlc == 0 {
+		maxHtlc = 0
+	}
+
+	req := lnwire.ChannelUpdate{
+		Signature:       genSig(r),
+		ShortChannelID:  genShortChanID(r),
+		Timestamp:       uint32(r.Int31()),
+		MessageFlags:    msgFlags,
+		ChannelFlags:    lnwire.ChanUpdateChanFlags(r.Int31()),
+		TimeLockDelta:   uint16(r.Int31()),
+		HtlcMinimumMsat: lnwire.MilliSatoshi(r.Int63()),
+		HtlcMaximumMsat: maxHtlc,
+		BaseFee:         uint32(r.Int31()),
+		FeeRate:         uint32(r.Int31()),
+		ExtraOpaqueData: genExtraOpaqueData(r),
+	}
+	_, _ = r.Read(req.ChainHash[:])
+
+	return &req
+}
+
+// RandAnnounceSignatures returns a random AnnounceSignatures message.
+func RandAnnounceSignatures(r *rand.Rand) *lnwire.AnnounceSignatures {
+	req := lnwire.AnnounceSignatures{
+		ChannelID:        genChannelID(r),
+		ShortChannelID:   genShortChanID(r),
+		NodeSignature:    genSig(r),
+		BitcoinSignature: genSig(r),
+		ExtraOpaqueData:  genExtraOpaqueData(r),
+	}
+
+	return &req
+}
+
+// RandGossipTimestampRange returns a random GossipTimestampRange message.
+func RandGossipTimestampRange(r *rand.Rand) *lnwire.GossipTimestampRange {
+	req := lnwire.GossipTimestampRange{
+		FirstTimestamp: r.Uint32(),
+		TimestampRange: r.Uint32(),
+		ExtraData:      genExtraOpaqueData(r),
+	}
+	_, _ = r.Read(req.ChainHash[:])
+
+	return &req
+}
+
+// RandOnionMessage returns a random OnionMessage message with an onion packet
+// of the size used for onion messages that fit into a single packet.
+func RandOnionMessage(r *rand.Rand) *lnwire.OnionMessage {
+	req := lnwire.OnionMessage{
+		BlindingPoint: RandPubKey(r),
+		OnionBlob:     RandBytes(r, 1366),
+		ExtraData:     genExtraOpaqueData(r),
+	}
+
+	return &req
+}
+
+// RandQueryShortChanIDs returns a random QueryShortChanIDs message, using
+// either of the supported encodings.
+func RandQueryShortChanIDs(r *rand.Rand) *lnwire.QueryShortChanIDs {
+	req := lnwire.QueryShortChanIDs{
+		EncodingType: genEncodingType(r),
+		ShortChanIDs: genShortChanIDs(r),
+		ExtraData:    genExtraOpaqueData(r),
+	}
+	_, _ = r.Read(req.ChainHash[:])
+
+	return &req
+}
+
+// RandReplyShortChanIDsEnd returns a random ReplyShortChanIDsEnd message.
+func RandReplyShortChanIDsEnd(r *rand.Rand) *lnwire.ReplyShortChanIDsEnd {
+	req := lnwire.ReplyShortChanIDsEnd{
+		Complete:  uint8(r.Intn(2)),
+		ExtraData: genExtraOpaqueData(r),
+	}
+	_, _ = r.Read(req.ChainHash[:])
+
+	return &req
+}
+
+// RandQueryChannelRange returns a random QueryChannelRange message.
+func RandQueryChannelRange(r *rand.Rand) *lnwire.QueryChannelRange {
+	req := lnwire.QueryChannelRange{
+		FirstBlockHeight: r.Uint32(),
+		NumBlocks:        r.Uint32(),
+		ExtraData:        genExtraOpaqueData(r),
+	}
+	_, _ = r.Read(req.ChainHash[:])
+
+	return &req
+}
+
+// RandReplyChannelRange returns a random ReplyChannelRange message, using
+// either of the supported encodings.
+func RandReplyChannelRange(r *rand.Rand) *lnwire.ReplyChannelRange {
+	req := lnwire.ReplyChannelRange{
+		FirstBlockHeight: uint32(r.Int31()),
+		NumBlocks:        uint32(r.Int31()),
+		Complete:         uint8(r.Intn(2)),
+		EncodingType:     genEncodingType(r),
+		ShortChanIDs:     genShortChanIDs(r),
+		ExtraData:        genExtraOpaqueData(r),
+	}
+	_, _ = r.Read(req.ChainHash[:])
+
+	return &req
+}
+
+// RandCustom returns a random Custom message with a type within the custom
+// range.
+func RandCustom(r *rand.Rand) *lnwire.Custom {
+	numTypes := math.MaxUint16 - int(lnwire.CustomTypeStart) + 1
+	msgType := lnwire.MessageType(r.Intn(numTypes))
+	req := lnwire.Custom{
+		Type: lnwire.CustomTypeStart + msgType,
+		Data: RandBytes(r, r.Intn(lnwire.MaxMsgBody)),
+	}
+
+	return &req
+}
@@ -34,6 +34,9 @@ const (
 	MsgFundingLocked                       = 36
 	MsgShutdown                            = 38
 	MsgClosingSigned                       = 39
+	MsgSpliceLocked                        = 77
+	MsgSpliceInit                          = 80
+	MsgSpliceAck                           = 81
 	MsgUpdateAddHTLC                       = 128
 	MsgUpdateFulfillHTLC                   = 130
 	MsgUpdateFailHTLC                      = 131
@@ -51,6 +54,7 @@ const (
 	MsgQueryChannelRange                   = 263
 	MsgReplyChannelRange                   = 264
 	MsgGossipTimestampRange                = 265
+	MsgOnionMessage                        = 513
 )
 
 // ErrorEncodeMessage is used when failed to encode the message payload.
@@ -94,6 +98,12 @@ func (t MessageType) String() string {
 		return "Shutdown"
 	case MsgClosingSigned:
 		return "ClosingSigned"
+	case MsgSpliceInit:
+		return "SpliceInit"
+	case MsgSpliceAck:
+		return "SpliceAck"
+	case MsgSpliceLocked:
+		return "SpliceLocked"
 	case MsgUpdateAddHTLC:
 		return "UpdateAddHTLC"
 	case MsgUpdateFailHTLC:
@@ -134,6 +144,8 @@ func (t MessageType) String() string {
 		return "ReplyChannelRange"
 	case MsgGossipTimestampRange:
 		return "GossipTimestampRange"
+	case MsgOnionMessage:
+		return "OnionMessage"
 	default:
 		return "<unknown>"
 	}
@@ -196,6 +208,12 @@ func makeEmptyMessage(msgType MessageType) (Message, error) {
 		msg = &Shutdown{}
 	case MsgClosingSigned:
 		msg = &ClosingSigned{}
+	case MsgSpliceInit:
+		msg = &SpliceInit{}
+	case MsgSpliceAck:
+		msg = &SpliceAck{}
+	case MsgSpliceLocked:
+		msg = &SpliceLocked{}
 	case MsgUpdateAddHTLC:
 		msg = &UpdateAddHTLC{}
 	case MsgUpdateFailHTLC:
@@ -236,6 +254,8 @@ func makeEmptyMessage(msgType MessageType) (Message, error) {
 		msg = &ReplyChannelRange{}
 	case MsgGossipTimestampRange:
 		msg = &GossipTimestampRange{}
+	case MsgOnionMessage:
+		msg = &OnionMessage{}
 	default:
 		if msgType < CustomTypeStart {
 			return nil, &UnknownMessage{msgType}
@@ -268,6 +288,15 @@ func WriteMessage(buf *bytes.Buffer, msg Message, pver uint32) (int, error) {
 		return 0
 	}
 
+	// Registered custom message types may validate the message before
+	// it is encoded and limit the size of its payload.
+	customType, isCustom := lookupCustomMessage(msg.MsgType())
+	if isCustom {
+		if err := customType.beforeEncode(msg); err != nil {
+			return 0, ErrorEncodeMessage(err)
+		}
+	}
+
 	// Write the message type.
 	var mType [2]byte
 	binary.BigEndian.PutUint16(mType[:], uint16(msg.MsgType()))
@@ -289,6 +318,11 @@ func WriteMessage(buf *bytes.Buffer, msg Message, pver uint32) (int, error) {
 	if lenp > MaxMsgBody {
 		return cleanBrokenBytes(buf), ErrorPayloadTooLarge(lenp)
 	}
+	if isCustom {
+		if err := customType.checkPayload(lenp); err != nil {
+			return cleanBrokenBytes(buf), err
+		}
+	}
 
 	return buf.Len() - oldByteSize, nil
 }
@@ -305,6 +339,21 @@ func ReadMessage(r io.Reader, pver uint32) (Message, error) {
 
 	msgType := MessageType(binary.BigEndian.Uint16(mType[:]))
 
+	// Messages of registered custom message types are decoded into the
+	// typed message of the application instead of a raw Custom message.
+	// The types are odd, so an invalid message must not be fatal.
+	if customType, ok := lookupCustomMessage(msgType); ok {
+		msg, err := customType.decode(r, pver)
+		if err != nil {
+			return nil, &ErrInvalidCustomMessage{
+				MsgType: msgType,
+				Err:     err,
+			}
+		}
+
+		return msg, nil
+	}
+
 	// Now that we know the target message type, we can create the proper
 	// empty message type and decode the message into it.
 	msg, err := makeEmptyMessage(msgType)
@@ -250,6 +250,53 @@ func BenchmarkReadMessage(b *testing.B) {
 	}
 }
 
+// BenchmarkDecoderReadMessage benchmarks the performance of reading messages
+// with an lnwire.Decoder, with and without message pools, so that the
+// allocations can be compared to those of BenchmarkReadMessage.
+func BenchmarkDecoderReadMessage(b *testing.B) {
+	// Create testing messages. We will use a constant seed to make sure
+	// the benchmark uses the same data every time.
+	r := rand.New(rand.NewSource(42))
+	msgAll := makeAllMessages(b, r)
+
+	for _, msg := range msgAll {
+		var buf bytes.Buffer
+		_, err := lnwire.WriteMessage(&buf, msg, 0)
+		require.NoError(b, err, "unable to write msg")
+
+		for _, pooled := range []bool{false, true} {
+			var opts []lnwire.DecoderOption
+			name := msg.MsgType().String()
+			if pooled {
+				opts = append(opts, lnwire.WithMessagePools())
+				name += "/pooled"
+			}
+
+			// The reader and the decoder are reused across
+			// messages, just like a peer reuses them for all the
+			// messages it reads.
+			reader := bytes.NewReader(buf.Bytes())
+			decoder := lnwire.NewDecoder(reader, opts...)
+
+			b.Run(name, func(b *testing.B) {
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					reader.Reset(buf.Bytes())
+
+					m, err := decoder.ReadMessage(0)
+					require.NoError(
+						b, err, "unable to read msg",
+					)
+
+					decoder.Release(m)
+				}
+			})
+		}
+	}
+}
+
 // makeAllMessages is used to create testing messages for each lnwire message
 // type.
 //
@@ -270,6 +317,9 @@ func makeAllMessages(t testing.TB, r *rand.Rand) []lnwire.Message {
 	msgAll = append(msgAll, newMsgFundingLocked(t, r))
 	msgAll = append(msgAll, newMsgShutdown(t, r))
 	msgAll = append(msgAll, newMsgClosingSigned(t, r))
+	msgAll = append(msgAll, newMsgSpliceInit(t, r))
+	msgAll = append(msgAll, newMsgSpliceAck(t, r))
+	msgAll = append(msgAll, newMsgSpliceLocked(t, r))
 	msgAll = append(msgAll, newMsgUpdateAddHTLC(t, r))
 	msgAll = append(msgAll, newMsgUpdateFulfillHTLC(t, r))
 	msgAll = append(msgAll, newMsgUpdateFailHTLC(t, r))
@@ -287,6 +337,7 @@ func makeAllMessages(t testing.TB, r *rand.Rand) []lnwire.Message {
 	msgAll = append(msgAll, newMsgQueryChannelRange(t, r))
 	msgAll = append(msgAll, newMsgReplyChannelRange(t, r))
 	msgAll = append(msgAll, newMsgGossipTimestampRange(t, r))
+	msgAll = append(msgAll, newMsgOnionMessage(t, r))
 	msgAll = append(msgAll, newMsgQueryShortChanIDsZlib(t, r))
 	msgAll = append(msgAll, newMsgReplyChannelRangeZlib(t, r))
 
@@ -473,6 +524,55 @@ func newMsgClosingSigned(t testing.TB, r *rand.Rand) *lnwire.ClosingSigned {
 	return msg
 }
 
+func newMsgSpliceInit(t testing.TB, r *rand.Rand) *lnwire.SpliceInit {
+	t.Helper()
+
+	msg := &lnwire.SpliceInit{
+		FundingContribution:    btcutil.Amount(r.Int63()),
+		FundingFeePerKw:        r.Uint32(),
+		Locktime:               r.Uint32(),
+		FundingKey:             randPubKey(t),
+		RequireConfirmedInputs: true,
+		ExtraData:              make([]byte, 0),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgSpliceAck(t testing.TB, r *rand.Rand) *lnwire.SpliceAck {
+	t.Helper()
+
+	msg := &lnwire.SpliceAck{
+		FundingContribution: -btcutil.Amount(r.Int63()),
+		FundingKey:          randPubKey(t),
+		ExtraData:           make([]byte, 0),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgSpliceLocked(t testing.TB, r *rand.Rand) *lnwire.SpliceLocked {
+	t.Helper()
+
+	msg := &lnwire.SpliceLocked{
+		ExtraData: createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	_, err = r.Read(msg.SpliceTxid[:])
+	require.NoError(t, err, "unable to generate splice txid")
+
+	return msg
+}
+
 func newMsgUpdateAddHTLC(t testing.TB, r *rand.Rand) *lnwire.UpdateAddHTLC {
 	t.Helper()
 
@@ -857,6 +957,21 @@ func newMsgGossipTimestampRange(t testing.TB,
 	return msg
 }
 
+func newMsgOnionMessage(t testing.TB, r *rand.Rand) *lnwire.OnionMessage {
+	t.Helper()
+
+	msg := &lnwire.OnionMessage{
+		BlindingPoint: randPubKey(t),
+		OnionBlob:     make([]byte, 1366),
+		ExtraData:     createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.OnionBlob)
+	require.NoError(t, err, "unable to generate onion blob")
+
+	return msg
+}
+
 func randRawKey(t testing.TB) [33]byte {
 	t.Helper()
 
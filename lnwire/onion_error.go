@@ -80,6 +80,7 @@ const (
 	CodeExpiryTooFar                     FailCode = 21
 	CodeInvalidOnionPayload                       = FlagPerm | 22
 	CodeMPPTimeout                       FailCode = 23
+	CodeInvalidOnionBlinding                      = FlagBadOnion | FlagPerm | 24
 )
 
 // String returns the string representation of the failure code.
@@ -157,6 +158,9 @@ func (c FailCode) String() string {
 	case CodeMPPTimeout:
 		return "MPPTimeout"
 
+	case CodeInvalidOnionBlinding:
+		return "InvalidOnionBlinding"
+
 	default:
 		return "<unknown>"
 	}
@@ -1215,6 +1219,52 @@ func (f *FailMPPTimeout) Error() string {
 	return f.Code().String()
 }
 
+// FailInvalidOnionBlinding is returned if there was an error processing a
+// payment within a blinded route. Nodes inside of a blinded route don't
+// reveal the reason for the failure, so the error is always reported from the
+// introduction node of the route.
+//
+// NOTE: May be returned by any node in a blinded route.
+type FailInvalidOnionBlinding struct {
+	// OnionSHA256 hash of the onion blob which haven't been proceeded.
+	OnionSHA256 [sha256.Size]byte
+}
+
+// NewInvalidOnionBlinding creates new instance of the
+// FailInvalidOnionBlinding.
+func NewInvalidOnionBlinding(onion []byte) *FailInvalidOnionBlinding {
+	return &FailInvalidOnionBlinding{OnionSHA256: sha256.Sum256(onion)}
+}
+
+// Code returns the failure unique code.
+//
+// NOTE: Part of the FailureMessage interface.
+func (f *FailInvalidOnionBlinding) Code() FailCode {
+	return CodeInvalidOnionBlinding
+}
+
+// Decode decodes the failure from bytes stream.
+//
+// NOTE: Part of the Serializable interface.
+func (f *FailInvalidOnionBlinding) Decode(r io.Reader, pver uint32) error {
+	return ReadElement(r, f.OnionSHA256[:])
+}
+
+// Encode writes the failure in bytes stream.
+//
+// NOTE: Part of the Serializable interface.
+func (f *FailInvalidOnionBlinding) Encode(w *bytes.Buffer, pver uint32) error {
+	return WriteBytes(w, f.OnionSHA256[:])
+}
+
+// Returns a human readable string describing the target FailureMessage.
+//
+// NOTE: Implements the error interface.
+func (f *FailInvalidOnionBlinding) Error() string {
+	return fmt.Sprintf("InvalidOnionBlinding(onion_sha=%x)",
+		f.OnionSHA256[:])
+}
+
 // DecodeFailure decodes, validates, and parses the lnwire onion failure, for
 // the provided protocol version.
 func DecodeFailure(r io.Reader, pver uint32) (FailureMessage, error) {
@@ -1410,6 +1460,9 @@ func makeEmptyOnionError(code FailCode) (FailureMessage, error) {
 	case CodeMPPTimeout:
 		return &FailMPPTimeout{}, nil
 
+	case CodeInvalidOnionBlinding:
+		return &FailInvalidOnionBlinding{}, nil
+
 	default:
 		return nil, errors.Errorf("unknown error code: %v", code)
 	}
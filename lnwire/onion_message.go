@@ -0,0 +1,85 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+	"math"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// OnionMessage is a message that carries an onion encrypted payload through a
+// route of blinded hops, without relying on any channels between them. Every
+// hop peels one layer of the onion and relays the inner packet to the next
+// node, until it reaches the final recipient.
+type OnionMessage struct {
+	// BlindingPoint is the ephemeral key the receiving node uses to
+	// derive the shared secret of its blinded hop, which it needs to
+	// decrypt the encrypted data of its payload.
+	BlindingPoint *btcec.PublicKey
+
+	// OnionBlob is the onion packet that carries the payloads of all
+	// remaining hops of the message.
+	OnionBlob []byte
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure OnionMessage implements the lnwire.Message
+// interface.
+var _ Message = (*OnionMessage)(nil)
+
+// Decode deserializes the serialized OnionMessage stored in the passed
+// io.Reader into the target OnionMessage using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) Decode(r io.Reader, pver uint32) error {
+	var blobLen uint16
+	if err := ReadElements(r, &o.BlindingPoint, &blobLen); err != nil {
+		return err
+	}
+
+	o.OnionBlob = make([]byte, blobLen)
+	if _, err := io.ReadFull(r, o.OnionBlob); err != nil {
+		return err
+	}
+
+	return o.ExtraData.Decode(r)
+}
+
+// Encode serializes the target OnionMessage into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) Encode(w *bytes.Buffer, pver uint32) error {
+	if len(o.OnionBlob) > math.MaxUint16 {
+		return ErrOnionBlobTooLarge
+	}
+
+	if err := WritePublicKey(w, o.BlindingPoint); err != nil {
+		return err
+	}
+
+	if err := WriteUint16(w, uint16(len(o.OnionBlob))); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, o.OnionBlob); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, o.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) MsgType() MessageType {
+	return MsgOnionMessage
+}
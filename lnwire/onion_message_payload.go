@@ -0,0 +1,461 @@
+package lnwire
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// replyPathType is the record type of the blinded path that the final
+	// recipient of an onion message can use to reply.
+	replyPathType tlv.Type = 2
+
+	// encryptedDataType is the record type of the encrypted data that the
+	// creator of a blinded path addressed to the node of a blinded hop.
+	encryptedDataType tlv.Type = 4
+
+	// FinalHopPayloadTypeStart is the lowest record type of the payloads
+	// addressed to the final recipient of an onion message, such as
+	// BOLT 12 invoice requests and invoices.
+	FinalHopPayloadTypeStart tlv.Type = 64
+
+	// paddingType is the record type of the padding of blinded route
+	// data, which is used to give all hops of a blinded path the same
+	// size.
+	paddingType tlv.Type = 1
+
+	// shortChannelIDType is the record type of the outgoing channel of
+	// blinded route data.
+	shortChannelIDType tlv.Type = 2
+
+	// nextNodeIDType is the record type of the next node of blinded route
+	// data.
+	nextNodeIDType tlv.Type = 4
+
+	// pathIDType is the record type of the secret that the creator of a
+	// blinded path addressed to itself in blinded route data.
+	pathIDType tlv.Type = 6
+
+	// nextBlindingOverrideType is the record type of the blinding point
+	// that replaces the derived one of the next hop in blinded route data.
+	nextBlindingOverrideType tlv.Type = 8
+)
+
+var (
+	// ErrOnionBlobTooLarge is returned when the onion packet of an onion
+	// message doesn't fit its two byte length prefix.
+	ErrOnionBlobTooLarge = errors.New("onion message blob too large")
+
+	// ErrNoBlindedHops is returned when a blinded path without any hops
+	// is encoded or decoded.
+	ErrNoBlindedHops = errors.New("blinded path has no hops")
+)
+
+// BlindedHop is a hop of a blinded path.
+type BlindedHop struct {
+	// BlindedNodeID is the blinded public key of the node of the hop.
+	BlindedNodeID *btcec.PublicKey
+
+	// EncryptedData is the data the creator of the path addressed to the
+	// node of the hop, encrypted with their shared secret. Once
+	// decrypted, it is decoded as BlindedRouteData.
+	EncryptedData []byte
+}
+
+// BlindedPath is a route to a node whose nodes are hidden behind blinded
+// public keys. Only the introduction node of the path is known to the sender.
+type BlindedPath struct {
+	// IntroductionNode is the real public key of the first node of the
+	// path.
+	IntroductionNode *btcec.PublicKey
+
+	// BlindingPoint is the ephemeral key the introduction node uses to
+	// derive the shared secret of its hop.
+	BlindingPoint *btcec.PublicKey
+
+	// Hops are the blinded hops of the path, starting with the one of the
+	// introduction node.
+	Hops []*BlindedHop
+}
+
+// Encode serializes the blinded path into the passed io.Writer.
+func (b *BlindedPath) Encode(w io.Writer) error {
+	if len(b.Hops) == 0 {
+		return ErrNoBlindedHops
+	}
+	if len(b.Hops) > math.MaxUint8 {
+		return fmt.Errorf("blinded path has %d hops, max is %d",
+			len(b.Hops), math.MaxUint8)
+	}
+
+	var buf bytes.Buffer
+	if err := WritePublicKey(&buf, b.IntroductionNode); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(&buf, b.BlindingPoint); err != nil {
+		return err
+	}
+
+	if err := WriteUint8(&buf, uint8(len(b.Hops))); err != nil {
+		return err
+	}
+
+	for _, hop := range b.Hops {
+		if len(hop.EncryptedData) > math.MaxUint16 {
+			return fmt.Errorf("encrypted data of blinded hop too "+
+				"large: %d bytes", len(hop.EncryptedData))
+		}
+
+		if err := WritePublicKey(&buf, hop.BlindedNodeID); err != nil {
+			return err
+		}
+
+		dataLen := uint16(len(hop.EncryptedData))
+		if err := WriteUint16(&buf, dataLen); err != nil {
+			return err
+		}
+
+		if err := WriteBytes(&buf, hop.EncryptedData); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Decode deserializes a blinded path from the passed io.Reader.
+func (b *BlindedPath) Decode(r io.Reader) error {
+	var numHops uint8
+	err := ReadElements(r,
+		&b.IntroductionNode,
+		&b.BlindingPoint,
+		&numHops,
+	)
+	if err != nil {
+		return err
+	}
+
+	if numHops == 0 {
+		return ErrNoBlindedHops
+	}
+
+	b.Hops = make([]*BlindedHop, numHops)
+	for i := range b.Hops {
+		var (
+			hop     BlindedHop
+			dataLen uint16
+		)
+		err := ReadElements(r, &hop.BlindedNodeID, &dataLen)
+		if err != nil {
+			return err
+		}
+
+		hop.EncryptedData = make([]byte, dataLen)
+		if _, err := io.ReadFull(r, hop.EncryptedData); err != nil {
+			return err
+		}
+
+		b.Hops[i] = &hop
+	}
+
+	return nil
+}
+
+// FinalHopPayload is a record of an onion message payload that is addressed
+// to the final recipient of the message.
+type FinalHopPayload struct {
+	// TLVType is the record type of the payload. It is at least
+	// FinalHopPayloadTypeStart.
+	TLVType tlv.Type
+
+	// Value is the raw value of the payload.
+	Value []byte
+}
+
+// OnionMessagePayload is the payload of a hop of an onion message, which is
+// decrypted from the onion packet by the node of the hop.
+type OnionMessagePayload struct {
+	// ReplyPath is an optional blinded path the final recipient can use
+	// to reply to the message.
+	ReplyPath *BlindedPath
+
+	// EncryptedData is the encrypted data the creator of the blinded path
+	// addressed to the node of the hop. It tells relaying nodes where to
+	// forward the message to.
+	EncryptedData []byte
+
+	// FinalHopPayloads are the payloads addressed to the final recipient
+	// of the message, ordered by type.
+	FinalHopPayloads []*FinalHopPayload
+}
+
+// Encode serializes the onion message payload as a TLV stream into the passed
+// io.Writer.
+func (o *OnionMessagePayload) Encode(w io.Writer) error {
+	var records []tlv.Record
+
+	if o.ReplyPath != nil {
+		var b bytes.Buffer
+		if err := o.ReplyPath.Encode(&b); err != nil {
+			return err
+		}
+
+		replyPath := b.Bytes()
+		records = append(
+			records, tlv.MakePrimitiveRecord(
+				replyPathType, &replyPath,
+			),
+		)
+	}
+
+	if o.EncryptedData != nil {
+		records = append(
+			records, tlv.MakePrimitiveRecord(
+				encryptedDataType, &o.EncryptedData,
+			),
+		)
+	}
+
+	// The stream asserts that the payloads are ordered by type.
+	for _, payload := range o.FinalHopPayloads {
+		if payload.TLVType < FinalHopPayloadTypeStart {
+			return fmt.Errorf("final hop payload type %d below %d",
+				payload.TLVType, FinalHopPayloadTypeStart)
+		}
+
+		records = append(
+			records, tlv.MakePrimitiveRecord(
+				payload.TLVType, &payload.Value,
+			),
+		)
+	}
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+
+	return stream.Encode(w)
+}
+
+// Decode deserializes an onion message payload from the TLV stream in the
+// passed io.Reader. Records of an unknown odd type below
+// FinalHopPayloadTypeStart are ignored.
+func (o *OnionMessagePayload) Decode(r io.Reader) error {
+	var replyPath, encryptedData []byte
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(replyPathType, &replyPath),
+		tlv.MakePrimitiveRecord(encryptedDataType, &encryptedData),
+	)
+	if err != nil {
+		return err
+	}
+
+	parsedTypes, err := stream.DecodeWithParsedTypes(r)
+	if err != nil {
+		return err
+	}
+
+	*o = OnionMessagePayload{}
+
+	if _, ok := parsedTypes[replyPathType]; ok {
+		o.ReplyPath = &BlindedPath{}
+
+		r := bytes.NewReader(replyPath)
+		if err := o.ReplyPath.Decode(r); err != nil {
+			return err
+		}
+
+		// The record must consist of exactly one blinded path.
+		if r.Len() != 0 {
+			return fmt.Errorf("%d trailing bytes after reply path",
+				r.Len())
+		}
+	}
+
+	if _, ok := parsedTypes[encryptedDataType]; ok {
+		o.EncryptedData = encryptedData
+	}
+
+	for _, typ := range sortedTypes(parsedTypes) {
+		value := parsedTypes[typ]
+
+		switch {
+		// Known records are recorded without a value.
+		case value == nil:
+			continue
+
+		case typ >= FinalHopPayloadTypeStart:
+			o.FinalHopPayloads = append(
+				o.FinalHopPayloads, &FinalHopPayload{
+					TLVType: typ,
+					Value:   value,
+				},
+			)
+
+		case typ%2 == 0:
+			return fmt.Errorf("%w: type %d",
+				ErrUnknownRequiredRecord, typ)
+		}
+	}
+
+	return nil
+}
+
+// BlindedRouteData is the data the creator of a blinded path addresses to the
+// node of a blinded hop. It is encrypted with their shared secret and carried
+// in the EncryptedData of the hop.
+type BlindedRouteData struct {
+	// Padding is ignored by the node of the hop. It is used to give all
+	// hops of a blinded path the same size.
+	Padding []byte
+
+	// ShortChannelID is the optional channel the message or payment is
+	// forwarded over.
+	ShortChannelID *ShortChannelID
+
+	// NextNodeID is the optional node the message is forwarded to.
+	NextNodeID *btcec.PublicKey
+
+	// PathID is an optional secret the creator of the path addressed to
+	// itself, which lets it verify that the path was used as intended.
+	PathID []byte
+
+	// NextBlindingOverride is an optional blinding point that replaces the
+	// one the next hop would otherwise derive, which allows two blinded
+	// paths to be concatenated.
+	NextBlindingOverride *btcec.PublicKey
+}
+
+// Encode serializes the blinded route data as a TLV stream into the passed
+// io.Writer.
+func (b *BlindedRouteData) Encode(w io.Writer) error {
+	var records []tlv.Record
+
+	if b.Padding != nil {
+		records = append(
+			records, tlv.MakePrimitiveRecord(
+				paddingType, &b.Padding,
+			),
+		)
+	}
+
+	if b.ShortChannelID != nil {
+		scid := b.ShortChannelID.ToUint64()
+		records = append(
+			records, tlv.MakePrimitiveRecord(
+				shortChannelIDType, &scid,
+			),
+		)
+	}
+
+	if b.NextNodeID != nil {
+		records = append(
+			records, tlv.MakePrimitiveRecord(
+				nextNodeIDType, &b.NextNodeID,
+			),
+		)
+	}
+
+	if b.PathID != nil {
+		records = append(
+			records, tlv.MakePrimitiveRecord(pathIDType, &b.PathID),
+		)
+	}
+
+	if b.NextBlindingOverride != nil {
+		records = append(
+			records, tlv.MakePrimitiveRecord(
+				nextBlindingOverrideType,
+				&b.NextBlindingOverride,
+			),
+		)
+	}
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+
+	return stream.Encode(w)
+}
+
+// Decode deserializes blinded route data from the TLV stream in the passed
+// io.Reader. Records of an unknown odd type are ignored.
+func (b *BlindedRouteData) Decode(r io.Reader) error {
+	var (
+		padding, pathID      []byte
+		scid                 uint64
+		nextNodeID, blinding *btcec.PublicKey
+	)
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(paddingType, &padding),
+		tlv.MakePrimitiveRecord(shortChannelIDType, &scid),
+		tlv.MakePrimitiveRecord(nextNodeIDType, &nextNodeID),
+		tlv.MakePrimitiveRecord(pathIDType, &pathID),
+		tlv.MakePrimitiveRecord(nextBlindingOverrideType, &blinding),
+	)
+	if err != nil {
+		return err
+	}
+
+	parsedTypes, err := stream.DecodeWithParsedTypes(r)
+	if err != nil {
+		return err
+	}
+
+	for typ, value := range parsedTypes {
+		if value != nil && typ%2 == 0 {
+			return fmt.Errorf("%w: type %d",
+				ErrUnknownRequiredRecord, typ)
+		}
+	}
+
+	*b = BlindedRouteData{}
+
+	if _, ok := parsedTypes[paddingType]; ok {
+		b.Padding = padding
+	}
+
+	if _, ok := parsedTypes[shortChannelIDType]; ok {
+		shortChanID := NewShortChanIDFromInt(scid)
+		b.ShortChannelID = &shortChanID
+	}
+
+	if _, ok := parsedTypes[nextNodeIDType]; ok {
+		b.NextNodeID = nextNodeID
+	}
+
+	if _, ok := parsedTypes[pathIDType]; ok {
+		b.PathID = pathID
+	}
+
+	if _, ok := parsedTypes[nextBlindingOverrideType]; ok {
+		b.NextBlindingOverride = blinding
+	}
+
+	return nil
+}
+
+// sortedTypes returns the types of the type map in ascending order.
+func sortedTypes(typeMap tlv.TypeMap) []tlv.Type {
+	types := make([]tlv.Type, 0, len(typeMap))
+	for typ := range typeMap {
+		types = append(types, typ)
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		return types[i] < types[j]
+	})
+
+	return types
+}
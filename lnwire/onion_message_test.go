@@ -0,0 +1,139 @@
+package lnwire_test
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/lnwire/lnwiretest"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOnionMessagePayload asserts that onion message payloads survive a
+// serialization round trip, and that invalid payloads are rejected.
+func TestOnionMessagePayload(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	payload := &lnwire.OnionMessagePayload{
+		ReplyPath: &lnwire.BlindedPath{
+			IntroductionNode: lnwiretest.RandPubKey(r),
+			BlindingPoint:    lnwiretest.RandPubKey(r),
+			Hops: []*lnwire.BlindedHop{{
+				BlindedNodeID: lnwiretest.RandPubKey(r),
+				EncryptedData: lnwiretest.RandBytes(r, 20),
+			}, {
+				BlindedNodeID: lnwiretest.RandPubKey(r),
+				EncryptedData: []byte{},
+			}},
+		},
+		EncryptedData: lnwiretest.RandBytes(r, 40),
+		FinalHopPayloads: []*lnwire.FinalHopPayload{
+			{
+				TLVType: lnwire.FinalHopPayloadTypeStart,
+				Value:   lnwiretest.RandBytes(r, 10),
+			},
+			{
+				TLVType: lnwire.FinalHopPayloadTypeStart + 3,
+				Value:   []byte{},
+			},
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, payload.Encode(&b))
+
+	decoded := &lnwire.OnionMessagePayload{}
+	require.NoError(t, decoded.Decode(bytes.NewReader(b.Bytes())))
+	require.Equal(t, payload, decoded)
+
+	// Payloads for the final recipient must use the types reserved for
+	// them, in ascending order.
+	payload.FinalHopPayloads[0].TLVType =
+		lnwire.FinalHopPayloadTypeStart - 1
+	require.Error(t, payload.Encode(&b))
+
+	payload.FinalHopPayloads[0].TLVType =
+		lnwire.FinalHopPayloadTypeStart + 5
+	require.ErrorIs(t, payload.Encode(&b), tlv.ErrStreamNotCanonical)
+
+	// A reply path needs at least one hop.
+	payload = &lnwire.OnionMessagePayload{
+		ReplyPath: &lnwire.BlindedPath{
+			IntroductionNode: lnwiretest.RandPubKey(r),
+			BlindingPoint:    lnwiretest.RandPubKey(r),
+		},
+	}
+	require.ErrorIs(t, payload.Encode(&b), lnwire.ErrNoBlindedHops)
+
+	// Unknown odd records are ignored, while unknown even records are
+	// rejected.
+	odd, even := []byte{}, []byte{}
+	for _, unknown := range []struct {
+		typ   tlv.Type
+		value *[]byte
+		err   error
+	}{
+		{typ: 3, value: &odd},
+		{typ: 6, value: &even, err: lnwire.ErrUnknownRequiredRecord},
+	} {
+		stream := tlv.MustNewStream(
+			tlv.MakePrimitiveRecord(unknown.typ, unknown.value),
+		)
+
+		b.Reset()
+		require.NoError(t, stream.Encode(&b))
+
+		err := (&lnwire.OnionMessagePayload{}).Decode(&b)
+		if unknown.err == nil {
+			require.NoError(t, err)
+		} else {
+			require.ErrorIs(t, err, unknown.err)
+		}
+	}
+}
+
+// TestBlindedRouteData asserts that blinded route data survives a
+// serialization round trip, and that unknown even records are rejected.
+func TestBlindedRouteData(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	scid := lnwire.NewShortChanIDFromInt(r.Uint64())
+	data := &lnwire.BlindedRouteData{
+		Padding:              make([]byte, 30),
+		ShortChannelID:       &scid,
+		NextNodeID:           lnwiretest.RandPubKey(r),
+		PathID:               lnwiretest.RandBytes(r, 32),
+		NextBlindingOverride: lnwiretest.RandPubKey(r),
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, data.Encode(&b))
+
+	decoded := &lnwire.BlindedRouteData{}
+	require.NoError(t, decoded.Decode(bytes.NewReader(b.Bytes())))
+	require.Equal(t, data, decoded)
+
+	// Relaying nodes may only be told where to forward the message.
+	data = &lnwire.BlindedRouteData{
+		NextNodeID: lnwiretest.RandPubKey(r),
+	}
+
+	b.Reset()
+	require.NoError(t, data.Encode(&b))
+
+	decoded = &lnwire.BlindedRouteData{}
+	require.NoError(t, decoded.Decode(&b))
+	require.Equal(t, data, decoded)
+
+	unknown := []byte{1}
+	stream := tlv.MustNewStream(tlv.MakePrimitiveRecord(10, &unknown))
+
+	b.Reset()
+	require.NoError(t, stream.Encode(&b))
+	err := (&lnwire.BlindedRouteData{}).Decode(&b)
+	require.ErrorIs(t, err, lnwire.ErrUnknownRequiredRecord)
+}
@@ -141,6 +141,10 @@ type OpenChannel struct {
 	// type.
 	LeaseExpiry *LeaseExpiry
 
+	// RequestFunds is an optional request for the responder to contribute
+	// funds to the channel in exchange for a lease fee.
+	RequestFunds *RequestFunds
+
 	// ExtraData is the set of data that was appended to this message to
 	// fill out the full maximum transport message size. These fields can
 	// be used to specify optional data such as custom TLV fields.
@@ -168,6 +172,9 @@ func (o *OpenChannel) Encode(w *bytes.Buffer, pver uint32) error {
 	if o.LeaseExpiry != nil {
 		recordProducers = append(recordProducers, o.LeaseExpiry)
 	}
+	if o.RequestFunds != nil {
+		recordProducers = append(recordProducers, o.RequestFunds)
+	}
 	err := EncodeMessageExtraData(&o.ExtraData, recordProducers...)
 	if err != nil {
 		return err
@@ -290,11 +297,13 @@ func (o *OpenChannel) Decode(r io.Reader, pver uint32) error {
 	// Next we'll parse out the set of known records, keeping the raw tlv
 	// bytes untouched to ensure we don't drop any bytes erroneously.
 	var (
-		chanType    ChannelType
-		leaseExpiry LeaseExpiry
+		chanType     ChannelType
+		leaseExpiry  LeaseExpiry
+		requestFunds RequestFunds
 	)
 	typeMap, err := tlvRecords.ExtractRecords(
 		&o.UpfrontShutdownScript, &chanType, &leaseExpiry,
+		&requestFunds,
 	)
 	if err != nil {
 		return err
@@ -307,6 +316,9 @@ func (o *OpenChannel) Decode(r io.Reader, pver uint32) error {
 	if val, ok := typeMap[LeaseExpiryRecordType]; ok && val == nil {
 		o.LeaseExpiry = &leaseExpiry
 	}
+	if val, ok := typeMap[RequestFundsRecordType]; ok && val == nil {
+		o.RequestFunds = &requestFunds
+	}
 
 	o.ExtraData = tlvRecords
 
@@ -0,0 +1,80 @@
+package lnwire_test
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/lnwire/lnwiretest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLightningWireProtocol uses the testing/quick package to create a series
+// of fuzz tests to attempt to break a primary scenario which is implemented as
+// property based testing scenario.
+func TestLightningWireProtocol(t *testing.T) {
+	t.Parallel()
+
+	// mainScenario is the primary test that will programmatically be
+	// executed for all registered wire messages. The quick-checker within
+	// testing/quick will attempt to find an input to this function, s.t
+	// the function returns false, if so then we've found an input that
+	// violates our model of the system.
+	mainScenario := func(msg lnwire.Message) bool {
+		// Give a new message, we'll serialize the message into a new
+		// bytes buffer.
+		var b bytes.Buffer
+		if _, err := lnwire.WriteMessage(&b, msg, 0); err != nil {
+			t.Fatalf("unable to write msg: %v", err)
+			return false
+		}
+
+		// Next, we'll ensure that the serialized payload (subtracting
+		// the 2 bytes for the message type) is _below_ the specified
+		// max payload size for this message.
+		payloadLen := uint32(b.Len()) - 2
+		if payloadLen > lnwire.MaxMsgBody {
+			t.Fatalf("msg payload constraint violated: %v > %v",
+				payloadLen, lnwire.MaxMsgBody)
+			return false
+		}
+
+		// Finally, we'll deserialize the message from the written
+		// buffer, and finally assert that the messages are equal.
+		newMsg, err := lnwire.ReadMessage(&b, 0)
+		if err != nil {
+			t.Fatalf("unable to read msg: %v", err)
+			return false
+		}
+		if !assert.Equalf(t, msg, newMsg, "message mismatch") {
+			return false
+		}
+
+		return true
+	}
+
+	// The messages are generated by the lnwiretest package, which knows
+	// how to generate valid values of every message type. Custom messages
+	// are checked using the first type of the custom range.
+	msgTypes := append(lnwiretest.MessageTypes(), lnwire.CustomTypeStart)
+	for _, msgType := range msgTypes {
+		cfg := &quick.Config{
+			Values: func(v []reflect.Value, r *rand.Rand) {
+				msg, err := lnwiretest.RandMessage(msgType, r)
+				require.NoError(t, err)
+
+				v[0] = reflect.ValueOf(msg)
+			},
+		}
+
+		t.Logf("Running fuzz tests for msgType=%v", msgType)
+		if err := quick.Check(mainScenario, cfg); err != nil {
+			t.Fatalf("fuzz checks for msg=%v failed: %v",
+				msgType, err)
+		}
+	}
+}
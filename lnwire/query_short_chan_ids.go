@@ -181,6 +181,11 @@ func decodeShortChanIDs(r io.Reader) (ShortChanIDEncoding, []ShortChannelID, err
 			return encodingType, nil, nil
 		}
 
+		err := countItems(r, itemShortChanID, numShortChanIDs)
+		if err != nil {
+			return 0, nil, err
+		}
+
 		// Finally, we'll read out the exact number of short channel
 		// ID's to conclude our parsing.
 		shortChanIDs := make([]ShortChannelID, numShortChanIDs)
@@ -262,6 +267,13 @@ func decodeShortChanIDs(r io.Reader) (ShortChanIDEncoding, []ShortChannelID, err
 					"ID: %v", err)
 			}
 
+			// We stop decompressing as soon as the payload holds
+			// more IDs than we're willing to process.
+			err = countItems(r, itemShortChanID, 1)
+			if err != nil {
+				return 0, nil, err
+			}
+
 			// We successfully read the next ID, so we'll collect
 			// that in the set of final ID's to return.
 			shortChanIDs = append(shortChanIDs, cid)
@@ -0,0 +1,96 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// SpliceAck is the message a node sends in response to SpliceInit to accept
+// the splicing of a channel.
+type SpliceAck struct {
+	// ChanID is the channel ID of the channel to splice.
+	ChanID ChannelID
+
+	// FundingContribution is the amount the sender adds to the channel,
+	// or removes from it if negative.
+	FundingContribution btcutil.Amount
+
+	// FundingKey is the key of the sender to be used in the 2-of-2
+	// multi-sig script of the new funding output.
+	FundingKey *btcec.PublicKey
+
+	// RequireConfirmedInputs is set if the sender requires the initiator
+	// to only contribute confirmed inputs to the splice transaction.
+	RequireConfirmedInputs bool
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure SpliceAck implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceAck)(nil)
+
+// Decode deserializes the serialized SpliceAck message stored in the passed
+// io.Reader into the target SpliceAck using the deserialization rules defined
+// by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) Decode(r io.Reader, pver uint32) error {
+	err := ReadElements(r,
+		&s.ChanID,
+		&s.FundingContribution,
+		&s.FundingKey,
+		&s.ExtraData,
+	)
+	if err != nil {
+		return err
+	}
+
+	s.RequireConfirmedInputs, err = extractRequireConfirmedInputs(
+		s.ExtraData,
+	)
+
+	return err
+}
+
+// Encode serializes the target SpliceAck into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, s.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, s.FundingContribution); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, s.FundingKey); err != nil {
+		return err
+	}
+
+	err := encodeRequireConfirmedInputs(
+		&s.ExtraData, s.RequireConfirmedInputs,
+	)
+	if err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) MsgType() MessageType {
+	return MsgSpliceAck
+}
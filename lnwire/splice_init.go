@@ -0,0 +1,150 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// SpliceInit is the message a node sends to initiate the splicing of an
+// existing channel, which replaces the funding output of the channel with a
+// new one that adds or removes funds.
+type SpliceInit struct {
+	// ChanID is the channel ID of the channel to splice.
+	ChanID ChannelID
+
+	// FundingContribution is the amount the sender adds to the channel,
+	// or removes from it if negative.
+	FundingContribution btcutil.Amount
+
+	// FundingFeePerKw is the fee rate in sat/kw that the sender proposes
+	// for the splice transaction.
+	FundingFeePerKw uint32
+
+	// Locktime is the locktime of the splice transaction.
+	Locktime uint32
+
+	// FundingKey is the key of the sender to be used in the 2-of-2
+	// multi-sig script of the new funding output.
+	FundingKey *btcec.PublicKey
+
+	// RequireConfirmedInputs is set if the sender requires the receiver
+	// to only contribute confirmed inputs to the splice transaction.
+	RequireConfirmedInputs bool
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure SpliceInit implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceInit)(nil)
+
+// Decode deserializes the serialized SpliceInit message stored in the passed
+// io.Reader into the target SpliceInit using the deserialization rules defined
+// by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) Decode(r io.Reader, pver uint32) error {
+	err := ReadElements(r,
+		&s.ChanID,
+		&s.FundingContribution,
+		&s.FundingFeePerKw,
+		&s.Locktime,
+		&s.FundingKey,
+		&s.ExtraData,
+	)
+	if err != nil {
+		return err
+	}
+
+	s.RequireConfirmedInputs, err = extractRequireConfirmedInputs(
+		s.ExtraData,
+	)
+
+	return err
+}
+
+// Encode serializes the target SpliceInit into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, s.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, s.FundingContribution); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, s.FundingFeePerKw); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, s.Locktime); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, s.FundingKey); err != nil {
+		return err
+	}
+
+	err := encodeRequireConfirmedInputs(
+		&s.ExtraData, s.RequireConfirmedInputs,
+	)
+	if err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) MsgType() MessageType {
+	return MsgSpliceInit
+}
+
+// encodeRequireConfirmedInputs packs the RequireConfirmedInputs record into
+// the extra data if it is set. If the extra data already signals the same,
+// it is left untouched so that any unknown records are preserved.
+func encodeRequireConfirmedInputs(extraData *ExtraOpaqueData,
+	requireConfirmed bool) error {
+
+	included, err := extractRequireConfirmedInputs(*extraData)
+	if err == nil && included == requireConfirmed {
+		return nil
+	}
+
+	var recordProducers []tlv.RecordProducer
+	if requireConfirmed {
+		recordProducers = append(
+			recordProducers, &RequireConfirmedInputs{},
+		)
+	}
+
+	return EncodeMessageExtraData(extraData, recordProducers...)
+}
+
+// extractRequireConfirmedInputs returns whether the RequireConfirmedInputs
+// record is included in the extra data.
+func extractRequireConfirmedInputs(extraData ExtraOpaqueData) (bool, error) {
+	var requireConfirmed RequireConfirmedInputs
+	typeMap, err := extraData.ExtractRecords(&requireConfirmed)
+	if err != nil {
+		return false, err
+	}
+
+	val, ok := typeMap[RequireConfirmedInputsRecordType]
+
+	return ok && val == nil, nil
+}
@@ -0,0 +1,183 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// RequestFundsRecordType is the type of the experimental record an
+	// initiator uses to request that the responder contributes funds to
+	// the channel in exchange for a lease fee (option_will_fund).
+	//
+	// The liquidity ads proposal hasn't been assigned TLV types yet, so
+	// both records use types from the experimental range starting at 2^16,
+	// right after the LeaseExpiryRecordType. The type is odd so that peers
+	// that don't support leases ignore the request and proceed with a
+	// regular channel open. Both types are experimental and will be
+	// replaced once the proposal is merged into the spec.
+	RequestFundsRecordType tlv.Type = 1<<16 + 1
+
+	// WillFundRecordType is the type of the experimental record a
+	// responder uses to answer a funding request with the rates it
+	// charges for the lease. The type is even, as it's only sent in reply
+	// to a RequestFunds record and the initiator must not ignore the terms
+	// of the lease it's offered.
+	WillFundRecordType tlv.Type = 1<<16 + 2
+
+	// leaseRatesLen is the length of the encoded LeaseRates.
+	leaseRatesLen = 12
+
+	// requestFundsLen is the length of the encoded RequestFunds.
+	requestFundsLen = 12
+)
+
+// LeaseRates are the rates a node charges for contributing funds to a
+// channel, and the maximum routing fees it commits to charging over the
+// leased funds for the duration of the lease.
+type LeaseRates struct {
+	// FundingFeeBase is the fixed fee charged for every lease.
+	FundingFeeBase btcutil.Amount
+
+	// FundingFeeRate is the fee charged proportionally to the leased
+	// amount, in basis points.
+	FundingFeeRate uint16
+
+	// ChannelFeeMaxBase is the maximum base routing fee charged over the
+	// channel during the lease.
+	ChannelFeeMaxBase MilliSatoshi
+
+	// ChannelFeeMaxRate is the maximum proportional routing fee charged
+	// over the channel during the lease, in thousandths of a percent.
+	ChannelFeeMaxRate uint16
+}
+
+// LeaseFee returns the fee charged for leasing the given amount.
+func (l *LeaseRates) LeaseFee(amt btcutil.Amount) btcutil.Amount {
+	return l.FundingFeeBase + amt*btcutil.Amount(l.FundingFeeRate)/10_000
+}
+
+// RequestFunds is the experimental record an initiator includes in the
+// OpenChannel message to request that the responder contributes funds to the
+// channel.
+type RequestFunds struct {
+	// RequestedAmt is the amount the responder is asked to contribute.
+	RequestedAmt btcutil.Amount
+
+	// BlockHeight is the initiator's view of the current block height,
+	// which the lease duration is counted from.
+	BlockHeight uint32
+}
+
+// Record returns a TLV record that can be used to encode/decode the
+// RequestFunds type from a given TLV stream.
+func (r *RequestFunds) Record() tlv.Record {
+	return tlv.MakeStaticRecord(
+		RequestFundsRecordType, r, requestFundsLen,
+		requestFundsEncoder, requestFundsDecoder,
+	)
+}
+
+// requestFundsEncoder is a custom TLV encoder for the RequestFunds record.
+func requestFundsEncoder(w io.Writer, val interface{}, buf *[8]byte) error {
+	if v, ok := val.(*RequestFunds); ok {
+		err := tlv.EUint64T(w, uint64(v.RequestedAmt), buf)
+		if err != nil {
+			return err
+		}
+
+		return tlv.EUint32T(w, v.BlockHeight, buf)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.RequestFunds")
+}
+
+// requestFundsDecoder is a custom TLV decoder for the RequestFunds record.
+func requestFundsDecoder(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*RequestFunds); ok && l == requestFundsLen {
+		var amt uint64
+		if err := tlv.DUint64(r, &amt, buf, 8); err != nil {
+			return err
+		}
+		if err := tlv.DUint32(r, &v.BlockHeight, buf, 4); err != nil {
+			return err
+		}
+		v.RequestedAmt = btcutil.Amount(amt)
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(
+		val, "lnwire.RequestFunds", l, requestFundsLen,
+	)
+}
+
+// WillFund is the experimental record a responder includes in the
+// AcceptChannel message to answer a RequestFunds record with the rates it
+// charges for the lease.
+type WillFund struct {
+	LeaseRates
+}
+
+// Record returns a TLV record that can be used to encode/decode the WillFund
+// type from a given TLV stream.
+func (w *WillFund) Record() tlv.Record {
+	return tlv.MakeStaticRecord(
+		WillFundRecordType, w, leaseRatesLen, willFundEncoder,
+		willFundDecoder,
+	)
+}
+
+// willFundEncoder is a custom TLV encoder for the WillFund record.
+func willFundEncoder(w io.Writer, val interface{}, buf *[8]byte) error {
+	if v, ok := val.(*WillFund); ok {
+		err := tlv.EUint32T(w, uint32(v.FundingFeeBase), buf)
+		if err != nil {
+			return err
+		}
+		if err := tlv.EUint16T(w, v.FundingFeeRate, buf); err != nil {
+			return err
+		}
+		err = tlv.EUint32T(w, uint32(v.ChannelFeeMaxBase), buf)
+		if err != nil {
+			return err
+		}
+
+		return tlv.EUint16T(w, v.ChannelFeeMaxRate, buf)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.WillFund")
+}
+
+// willFundDecoder is a custom TLV decoder for the WillFund record.
+func willFundDecoder(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*WillFund); ok && l == leaseRatesLen {
+		var feeBase, maxBase uint32
+		if err := tlv.DUint32(r, &feeBase, buf, 4); err != nil {
+			return err
+		}
+		if err := tlv.DUint16(r, &v.FundingFeeRate, buf, 2); err != nil {
+			return err
+		}
+		if err := tlv.DUint32(r, &maxBase, buf, 4); err != nil {
+			return err
+		}
+		if err := tlv.DUint16(r, &v.ChannelFeeMaxRate, buf, 2); err != nil {
+			return err
+		}
+		v.FundingFeeBase = btcutil.Amount(feeBase)
+		v.ChannelFeeMaxBase = MilliSatoshi(maxBase)
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(
+		val, "lnwire.WillFund", l, leaseRatesLen,
+	)
+}
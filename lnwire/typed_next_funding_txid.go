@@ -0,0 +1,28 @@
+package lnwire
+
+import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// NextFundingTxidRecordType is the type of the record a node includes
+	// in the ChannelReestablish message to signal the txid of the
+	// interactive funding (or splice) transaction it has sent its
+	// commitment signatures for, but not yet received the peer's
+	// tx_signatures for.
+	NextFundingTxidRecordType tlv.Type = 0
+)
+
+// NextFundingTxid is the txid of an interactively constructed funding
+// transaction that a node expects its peer to resume signing after a
+// reconnection.
+type NextFundingTxid chainhash.Hash
+
+// Record returns a TLV record that can be used to encode/decode the
+// NextFundingTxid type from a given TLV stream.
+func (n *NextFundingTxid) Record() tlv.Record {
+	return tlv.MakePrimitiveRecord(
+		NextFundingTxidRecordType, (*[32]byte)(n),
+	)
+}
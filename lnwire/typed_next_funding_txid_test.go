@@ -0,0 +1,25 @@
+package lnwire
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestNextFundingTxidEncodeDecode tests that we're able to properly encode and
+// decode the next funding txid within TLV streams.
+func TestNextFundingTxidEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	nextFundingTxid := NextFundingTxid{1, 2, 3}
+
+	var extraData ExtraOpaqueData
+	require.NoError(t, extraData.PackRecords(&nextFundingTxid))
+
+	var nextFundingTxid2 NextFundingTxid
+	tlvs, err := extraData.ExtractRecords(&nextFundingTxid2)
+	require.NoError(t, err)
+
+	require.Contains(t, tlvs, NextFundingTxidRecordType)
+	require.Equal(t, nextFundingTxid, nextFundingTxid2)
+}
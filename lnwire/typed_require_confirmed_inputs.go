@@ -0,0 +1,54 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// RequireConfirmedInputsRecordType is the type of the record a node
+	// includes in the splice_init and splice_ack messages to require its
+	// peer to only contribute confirmed inputs to the splice transaction.
+	RequireConfirmedInputsRecordType tlv.Type = 2
+)
+
+// RequireConfirmedInputs is a zero-length record that signals that the peer
+// must only contribute confirmed inputs to an interactively constructed
+// transaction.
+type RequireConfirmedInputs struct{}
+
+// Record returns a TLV record that can be used to encode/decode the
+// RequireConfirmedInputs type from a given TLV stream.
+func (c *RequireConfirmedInputs) Record() tlv.Record {
+	return tlv.MakeStaticRecord(
+		RequireConfirmedInputsRecordType, c, 0,
+		requireConfirmedInputsEncoder, requireConfirmedInputsDecoder,
+	)
+}
+
+// requireConfirmedInputsEncoder is a custom TLV encoder for the
+// RequireConfirmedInputs record.
+func requireConfirmedInputsEncoder(w io.Writer, val interface{},
+	buf *[8]byte) error {
+
+	if _, ok := val.(*RequireConfirmedInputs); ok {
+		return nil
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.RequireConfirmedInputs")
+}
+
+// requireConfirmedInputsDecoder is a custom TLV decoder for the
+// RequireConfirmedInputs record.
+func requireConfirmedInputsDecoder(r io.Reader, val interface{},
+	buf *[8]byte, l uint64) error {
+
+	if _, ok := val.(*RequireConfirmedInputs); ok && l == 0 {
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(
+		val, "lnwire.RequireConfirmedInputs", l, 0,
+	)
+}
@@ -0,0 +1,350 @@
+package macaroons
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"gopkg.in/macaroon-bakery.v2/bakery/checkers"
+	macaroon "gopkg.in/macaroon.v2"
+)
+
+const (
+	// CondSpendLimit is the first party caveat condition name that limits
+	// the cumulative amount a macaroon can spend in Lightning payments
+	// within a time period. The caveat is encoded as the string
+	// "spend-limit <max-amount-msat> <period-seconds>".
+	CondSpendLimit = "spend-limit"
+)
+
+var (
+	// spendLogBucketName is the name of the top level bucket that holds
+	// the amounts spent by spend limited macaroons, keyed by macaroon ID.
+	spendLogBucketName = []byte("macspendlog")
+
+	// ErrSpendLimitExceeded is returned when a payment would exceed one
+	// of the spend limits of the macaroon used to authenticate it.
+	ErrSpendLimitExceeded = errors.New("macaroon spend limit exceeded")
+
+	// ErrSpendLimitUnsupported is returned when a spend limited macaroon
+	// is used for a call that can't enforce its spend limits.
+	ErrSpendLimitUnsupported = errors.New("call not supported by spend " +
+		"limited macaroons")
+)
+
+// SpendLimit is the maximum amount a macaroon can spend within a period.
+type SpendLimit struct {
+	// MaxAmt is the maximum cumulative amount, including fees, that can
+	// be spent within the period.
+	MaxAmt lnwire.MilliSatoshi
+
+	// Period is the duration of the sliding window the amount is limited
+	// over.
+	Period time.Duration
+}
+
+// String returns the caveat condition of the spend limit.
+func (s SpendLimit) String() string {
+	return fmt.Sprintf("%d %d", uint64(s.MaxAmt),
+		int64(s.Period/time.Second))
+}
+
+// parseSpendLimit parses the condition of a spend limit caveat.
+func parseSpendLimit(cond string) (SpendLimit, error) {
+	parts := strings.Split(cond, " ")
+	if len(parts) != 2 {
+		return SpendLimit{}, fmt.Errorf("invalid spend limit "+
+			"condition: %v", cond)
+	}
+
+	maxAmt, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return SpendLimit{}, fmt.Errorf("invalid spend limit amount: "+
+			"%v", err)
+	}
+
+	period, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return SpendLimit{}, fmt.Errorf("invalid spend limit period: "+
+			"%v", err)
+	}
+	if period <= 0 {
+		return SpendLimit{}, fmt.Errorf("spend limit period must be " +
+			"positive")
+	}
+
+	return SpendLimit{
+		MaxAmt: lnwire.MilliSatoshi(maxAmt),
+		Period: time.Duration(period) * time.Second,
+	}, nil
+}
+
+// SpendLimitConstraint limits the cumulative amount the macaroon can spend in
+// Lightning payments within the given period.
+func SpendLimitConstraint(maxAmt lnwire.MilliSatoshi,
+	period time.Duration) func(*macaroon.Macaroon) error {
+
+	return func(mac *macaroon.Macaroon) error {
+		if period < time.Second {
+			return fmt.Errorf("spend limit period must be at " +
+				"least one second")
+		}
+
+		limit := SpendLimit{MaxAmt: maxAmt, Period: period}
+		caveat := checkers.Condition(CondSpendLimit, limit.String())
+
+		return mac.AddFirstPartyCaveat([]byte(caveat))
+	}
+}
+
+// SpendLimitChecker checks that the spend limit caveat of a macaroon is well
+// formed. The limit itself can only be enforced by the RPC calls that send
+// payments, using Service.ReserveSpend. It is of the `Checker` type.
+func SpendLimitChecker() (string, checkers.Func) {
+	return CondSpendLimit, func(_ context.Context, _, arg string) error {
+		_, err := parseSpendLimit(arg)
+		return err
+	}
+}
+
+// SpendLimits returns all spend limits of the given macaroon.
+func SpendLimits(mac *macaroon.Macaroon) ([]SpendLimit, error) {
+	var limits []SpendLimit
+	for _, caveat := range mac.Caveats() {
+		cond, arg, err := checkers.ParseCaveat(string(caveat.Id))
+		if err != nil || cond != CondSpendLimit {
+			continue
+		}
+
+		limit, err := parseSpendLimit(arg)
+		if err != nil {
+			return nil, err
+		}
+		limits = append(limits, limit)
+	}
+
+	return limits, nil
+}
+
+// spendLimitsFromContext returns the ID and the spend limits of the macaroon
+// in the given request context. If the context doesn't contain a macaroon, no
+// limits are returned.
+func spendLimitsFromContext(ctx context.Context) ([]byte, []SpendLimit,
+	error) {
+
+	macHex, err := RawMacaroonFromContext(ctx)
+	if err != nil {
+		return nil, nil, nil
+	}
+
+	macBytes, err := hex.DecodeString(macHex)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mac := &macaroon.Macaroon{}
+	if err := mac.UnmarshalBinary(macBytes); err != nil {
+		return nil, nil, err
+	}
+
+	limits, err := SpendLimits(mac)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return mac.Id(), limits, nil
+}
+
+// HasSpendLimit returns true if the macaroon in the given request context has
+// a spend limit.
+func HasSpendLimit(ctx context.Context) bool {
+	_, limits, err := spendLimitsFromContext(ctx)
+
+	return err != nil || len(limits) > 0
+}
+
+// SpendReservation is an amount that has been counted against the spend
+// limits of a macaroon for a payment that is in flight.
+type SpendReservation struct {
+	db    kvdb.Backend
+	macID []byte
+	key   [8]byte
+}
+
+// Cancel removes the reserved amount again, e.g. because the payment failed.
+// It is safe to call on a nil reservation.
+func (r *SpendReservation) Cancel() error {
+	if r == nil {
+		return nil
+	}
+
+	return kvdb.Update(r.db, func(tx kvdb.RwTx) error {
+		macBucket := spendLogMacBucket(tx, r.macID)
+		if macBucket == nil {
+			return nil
+		}
+
+		return macBucket.Delete(r.key[:])
+	}, func() {})
+}
+
+// Settle replaces the reserved amount with the amount that was actually
+// spent, including fees. It is safe to call on a nil reservation.
+func (r *SpendReservation) Settle(amt lnwire.MilliSatoshi) error {
+	if r == nil {
+		return nil
+	}
+
+	return kvdb.Update(r.db, func(tx kvdb.RwTx) error {
+		macBucket := spendLogMacBucket(tx, r.macID)
+		if macBucket == nil {
+			return nil
+		}
+
+		var amtBytes [8]byte
+		binary.BigEndian.PutUint64(amtBytes[:], uint64(amt))
+
+		return macBucket.Put(r.key[:], amtBytes[:])
+	}, func() {})
+}
+
+// spendLogMacBucket returns the spend log bucket of the given macaroon ID, or
+// nil if it doesn't exist.
+func spendLogMacBucket(tx kvdb.RwTx, macID []byte) kvdb.RwBucket {
+	spendLog := tx.ReadWriteBucket(spendLogBucketName)
+	if spendLog == nil {
+		return nil
+	}
+
+	return spendLog.NestedReadWriteBucket(macID)
+}
+
+// reserveSpend counts the given amount against all spend limits, and returns
+// a reservation if none of them is exceeded. Amounts that are older than the
+// longest period are pruned from the spend log.
+func reserveSpend(db kvdb.Backend, macID []byte, limits []SpendLimit,
+	amt lnwire.MilliSatoshi, now time.Time) (*SpendReservation, error) {
+
+	var maxPeriod time.Duration
+	for _, limit := range limits {
+		if limit.Period > maxPeriod {
+			maxPeriod = limit.Period
+		}
+	}
+
+	reservation := &SpendReservation{
+		db:    db,
+		macID: macID,
+	}
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		spendLog, err := tx.CreateTopLevelBucket(spendLogBucketName)
+		if err != nil {
+			return err
+		}
+		macBucket, err := spendLog.CreateBucketIfNotExists(macID)
+		if err != nil {
+			return err
+		}
+
+		// Sum up the amounts spent within each limit's period, and
+		// collect the ones that are too old to matter anymore.
+		var (
+			spent = make([]lnwire.MilliSatoshi, len(limits))
+			stale [][]byte
+		)
+		err = macBucket.ForEach(func(k, v []byte) error {
+			if len(k) != 8 || len(v) != 8 {
+				return fmt.Errorf("invalid spend log entry")
+			}
+
+			ts := time.Unix(0, int64(binary.BigEndian.Uint64(k)))
+			age := now.Sub(ts)
+			if age >= maxPeriod {
+				stale = append(stale, k)
+				return nil
+			}
+
+			entryAmt := binary.BigEndian.Uint64(v)
+			for i, limit := range limits {
+				if age < limit.Period {
+					spent[i] += lnwire.MilliSatoshi(
+						entryAmt,
+					)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, k := range stale {
+			if err := macBucket.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		for i, limit := range limits {
+			if spent[i]+amt > limit.MaxAmt {
+				return fmt.Errorf("%w: %v of %v spent within "+
+					"%v", ErrSpendLimitExceeded, spent[i],
+					limit.MaxAmt, limit.Period)
+			}
+		}
+
+		// Find a free key for the new entry. Reservations made within
+		// the same nanosecond are shifted by one.
+		ts := now.UnixNano()
+		for {
+			binary.BigEndian.PutUint64(
+				reservation.key[:], uint64(ts),
+			)
+			if macBucket.Get(reservation.key[:]) == nil {
+				break
+			}
+			ts++
+		}
+
+		var amtBytes [8]byte
+		binary.BigEndian.PutUint64(amtBytes[:], uint64(amt))
+
+		return macBucket.Put(reservation.key[:], amtBytes[:])
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// ReserveSpend counts the given amount against the spend limits of the
+// macaroon in the given request context. If the macaroon doesn't have any
+// spend limits, a nil reservation is returned. Otherwise the returned
+// reservation must be cancelled if the payment fails, or settled with the
+// amount that was actually spent.
+func (svc *Service) ReserveSpend(ctx context.Context,
+	amt lnwire.MilliSatoshi) (*SpendReservation, error) {
+
+	macID, limits, err := spendLimitsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(limits) == 0 {
+		return nil, nil
+	}
+
+	rks, ok := svc.rks.(*RootKeyStorage)
+	if !ok {
+		return nil, ErrSpendLimitUnsupported
+	}
+
+	return reserveSpend(rks.Backend, macID, limits, amt, time.Now())
+}
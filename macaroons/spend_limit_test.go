@@ -0,0 +1,99 @@
+package macaroons_test
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/macaroons"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"gopkg.in/macaroon-bakery.v2/bakery"
+)
+
+// TestSpendLimit tests that the cumulative amount spent by a spend limited
+// macaroon is tracked and enforced.
+func TestSpendLimit(t *testing.T) {
+	db := setupTestRootKeyStorage(t)
+	rootKeyStore, err := macaroons.NewRootKeyStorage(db)
+	require.NoError(t, err)
+	service, err := macaroons.NewService(
+		rootKeyStore, "lnd", false, macaroons.SpendLimitChecker,
+	)
+	require.NoError(t, err)
+	defer service.Close()
+
+	require.NoError(t, service.CreateUnlock(&defaultPw))
+
+	mac, err := service.NewMacaroon(
+		context.TODO(), macaroons.DefaultRootKeyID, testOperation,
+	)
+	require.NoError(t, err)
+
+	// Limit the macaroon to 1000 msat per day.
+	const limit = lnwire.MilliSatoshi(1000)
+	limitedMac, err := macaroons.AddConstraints(
+		mac.M(), macaroons.SpendLimitConstraint(limit, 24*time.Hour),
+	)
+	require.NoError(t, err)
+
+	limits, err := macaroons.SpendLimits(limitedMac)
+	require.NoError(t, err)
+	require.Equal(t, []macaroons.SpendLimit{{
+		MaxAmt: limit,
+		Period: 24 * time.Hour,
+	}}, limits)
+
+	macBinary, err := limitedMac.MarshalBinary()
+	require.NoError(t, err)
+	ctx := metadata.NewIncomingContext(
+		context.Background(), metadata.New(map[string]string{
+			"macaroon": hex.EncodeToString(macBinary),
+		}),
+	)
+
+	// The spend limit caveat itself doesn't prevent the macaroon from
+	// being valid.
+	err = service.ValidateMacaroon(
+		ctx, []bakery.Op{testOperation}, "FooMethod",
+	)
+	require.NoError(t, err)
+	require.True(t, macaroons.HasSpendLimit(ctx))
+
+	// Reserve most of the budget, which leaves too little for a second
+	// payment of the same size.
+	reservation, err := service.ReserveSpend(ctx, 600)
+	require.NoError(t, err)
+	require.NotNil(t, reservation)
+
+	_, err = service.ReserveSpend(ctx, 600)
+	require.ErrorIs(t, err, macaroons.ErrSpendLimitExceeded)
+
+	// Once the first payment settles for less than reserved, the second
+	// one fits.
+	require.NoError(t, reservation.Settle(400))
+	reservation, err = service.ReserveSpend(ctx, 600)
+	require.NoError(t, err)
+
+	// Cancelling a reservation frees up the budget again.
+	_, err = service.ReserveSpend(ctx, 1)
+	require.ErrorIs(t, err, macaroons.ErrSpendLimitExceeded)
+	require.NoError(t, reservation.Cancel())
+	_, err = service.ReserveSpend(ctx, 600)
+	require.NoError(t, err)
+
+	// A macaroon without spend limits isn't tracked at all.
+	macBinary, err = mac.M().MarshalBinary()
+	require.NoError(t, err)
+	ctx = metadata.NewIncomingContext(
+		context.Background(), metadata.New(map[string]string{
+			"macaroon": hex.EncodeToString(macBinary),
+		}),
+	)
+	require.False(t, macaroons.HasSpendLimit(ctx))
+	reservation, err = service.ReserveSpend(ctx, 1_000_000)
+	require.NoError(t, err)
+	require.Nil(t, reservation)
+}
@@ -1,11 +1,9 @@
-FUZZPKG = brontide lnwire wtwire zpay32
-FUZZ_TEST_RUN_TIME = 30
-FUZZ_TEST_TIMEOUT = 20
+FUZZPKG = brontide lnwire watchtower/wtwire zpay32
+FUZZ_TEST_RUN_TIME = 30s
+FUZZ_TEST_TIMEOUT = 20m
 FUZZ_NUM_PROCESSES = 4
-FUZZ_BASE_WORKDIR = $(shell pwd)/fuzz
 
-# If specific package is being fuzzed, construct the full name of the
-# subpackage.
+# If specific package is being fuzzed, only run the targets of that package.
 ifneq ($(pkg),)
 FUZZPKG := $(pkg)
 endif
@@ -27,8 +25,3 @@ ifneq ($(processes),)
 FUZZ_NUM_PROCESSES := $(processes)
 endif
 
-# Overwrite the base work directory for the fuzz run. Can be used to supply any
-# previously generated corpus.
-ifneq ($(base_workdir),)
-FUZZ_BASE_WORKDIR := $(base_workdir)
-endif
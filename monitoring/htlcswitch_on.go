@@ -0,0 +1,104 @@
+//go:build monitoring
+// +build monitoring
+
+package monitoring
+
+import (
+	"github.com/lightningnetwork/lnd/htlcswitch/htlcmetrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// htlcInterval is an interval of the forwarding pipeline whose latency is
+// exported.
+type htlcInterval struct {
+	name     string
+	from, to htlcmetrics.Stage
+}
+
+// htlcIntervals are the intervals of the forwarding pipeline whose latency is
+// exported, followed by the total time an HTLC spent in the node.
+var htlcIntervals = []htlcInterval{
+	{
+		name: "decide",
+		from: htlcmetrics.StageAddReceived,
+		to:   htlcmetrics.StageForwardDecision,
+	},
+	{
+		name: "switch",
+		from: htlcmetrics.StageForwardDecision,
+		to:   htlcmetrics.StageDownstreamAdd,
+	},
+	{
+		name: "downstream",
+		from: htlcmetrics.StageDownstreamAdd,
+		to:   htlcmetrics.StageSettleReceived,
+	},
+	{
+		name: "return",
+		from: htlcmetrics.StageSettleReceived,
+		to:   htlcmetrics.StageSettleSent,
+	},
+	{
+		name: "total",
+		from: htlcmetrics.StageAddReceived,
+		to:   htlcmetrics.StageSettleSent,
+	},
+}
+
+// htlcMetrics is a htlcmetrics.Recorder that exports the latency of forwarded
+// HTLCs as Prometheus histograms. Each observation carries the ID of the HTLC
+// as an exemplar, which allows operators to look up individual slow HTLCs.
+type htlcMetrics struct {
+	latency *prometheus.HistogramVec
+}
+
+// A compile-time assertion to ensure htlcMetrics implements the
+// htlcmetrics.Recorder interface.
+var _ htlcmetrics.Recorder = (*htlcMetrics)(nil)
+
+// NewHtlcMetrics creates a htlcmetrics.Recorder whose metrics are registered
+// with the default Prometheus registry, and are therefore served by the
+// exporter started by ExportPrometheusMetrics.
+func NewHtlcMetrics() htlcmetrics.Recorder {
+	m := &htlcMetrics{
+		latency: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: "lnd",
+				Subsystem: "htlcswitch",
+				Name:      "htlc_latency_seconds",
+				Help: "Time forwarded HTLCs spent in each " +
+					"stage of the forwarding pipeline.",
+				Buckets: prometheus.ExponentialBuckets(
+					0.001, 2, 18,
+				),
+			}, []string{"interval", "outcome"},
+		),
+	}
+
+	prometheus.MustRegister(m.latency)
+
+	return m
+}
+
+// HtlcResolved records the trace of a resolved HTLC.
+//
+// NOTE: This is part of the htlcmetrics.Recorder interface.
+func (m *htlcMetrics) HtlcResolved(trace *htlcmetrics.Trace) {
+	outcome := "fail"
+	if trace.Settled {
+		outcome = "settle"
+	}
+
+	exemplar := prometheus.Labels{"htlc_id": trace.ID()}
+	for _, interval := range htlcIntervals {
+		latency, ok := trace.Interval(interval.from, interval.to)
+		if !ok {
+			continue
+		}
+
+		observer := m.latency.WithLabelValues(interval.name, outcome)
+		observer.(prometheus.ExemplarObserver).ObserveWithExemplar(
+			latency.Seconds(), exemplar,
+		)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
 )
@@ -43,7 +44,14 @@ func ExportPrometheusMetrics(grpcServer *grpc.Server, cfg lncfg.Prometheus) erro
 			grpc_prometheus.EnableHandlingTimeHistogram()
 		}
 
-		http.Handle("/metrics", promhttp.Handler())
+		// Serve the OpenMetrics format to scrapers that accept it,
+		// as it is the only format that carries exemplars.
+		http.Handle("/metrics", promhttp.InstrumentMetricHandler(
+			prometheus.DefaultRegisterer, promhttp.HandlerFor(
+				prometheus.DefaultGatherer,
+				promhttp.HandlerOpts{EnableOpenMetrics: true},
+			),
+		))
 		go func() {
 			http.ListenAndServe(cfg.Listen, nil)
 		}()
@@ -0,0 +1,176 @@
+//go:build monitoring
+// +build monitoring
+
+package monitoring
+
+import (
+	"time"
+
+	"github.com/lightningnetwork/lnd/watchtower/wtmetrics"
+	"github.com/lightningnetwork/lnd/watchtower/wtwire"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// towerMetrics is a wtmetrics.Recorder that exports the activity of the
+// watchtower server as Prometheus metrics.
+type towerMetrics struct {
+	sessionsCreated   prometheus.Counter
+	updatesStored     prometheus.Counter
+	updateBytes       prometheus.Counter
+	justiceBroadcasts prometheus.Counter
+	msgLatency        *prometheus.HistogramVec
+	limitsExceeded    *prometheus.CounterVec
+	clientsBanned     prometheus.Counter
+	bannedConns       prometheus.Counter
+}
+
+// A compile-time assertion to ensure towerMetrics implements the
+// wtmetrics.Recorder interface.
+var _ wtmetrics.Recorder = (*towerMetrics)(nil)
+
+// NewTowerMetrics creates a wtmetrics.Recorder whose metrics are registered
+// with the default Prometheus registry, and are therefore served by the
+// exporter started by ExportPrometheusMetrics. The passed storageBytes
+// closure is queried each time the metrics are scraped to report the size of
+// the tower's database.
+func NewTowerMetrics(storageBytes func() (uint64, error)) wtmetrics.Recorder {
+	m := &towerMetrics{
+		sessionsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "lnd",
+			Subsystem: "watchtower",
+			Name:      "sessions_created_total",
+			Help:      "Number of sessions negotiated with clients.",
+		}),
+		updatesStored: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "lnd",
+			Subsystem: "watchtower",
+			Name:      "updates_stored_total",
+			Help:      "Number of state updates accepted from clients.",
+		}),
+		updateBytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "lnd",
+			Subsystem: "watchtower",
+			Name:      "update_blob_bytes_total",
+			Help: "Number of bytes of encrypted blobs accepted " +
+				"from clients.",
+		}),
+		justiceBroadcasts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "lnd",
+			Subsystem: "watchtower",
+			Name:      "justice_broadcasts_total",
+			Help:      "Number of justice transactions published.",
+		}),
+		msgLatency: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: "lnd",
+				Subsystem: "watchtower",
+				Name:      "message_latency_seconds",
+				Help: "Time taken to process messages " +
+					"received from clients.",
+				Buckets: prometheus.DefBuckets,
+			}, []string{"msg_type"},
+		),
+		limitsExceeded: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "lnd",
+				Subsystem: "watchtower",
+				Name:      "limits_exceeded_total",
+				Help: "Number of client requests " +
+					"rejected for exceeding a limit.",
+			}, []string{"limit"},
+		),
+		clientsBanned: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "lnd",
+			Subsystem: "watchtower",
+			Name:      "clients_banned_total",
+			Help:      "Number of clients banned for abuse.",
+		}),
+		bannedConns: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "lnd",
+			Subsystem: "watchtower",
+			Name:      "banned_connections_rejected_total",
+			Help: "Number of connections of banned clients " +
+				"rejected.",
+		}),
+	}
+
+	storage := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "lnd",
+		Subsystem: "watchtower",
+		Name:      "storage_bytes",
+		Help: "Number of bytes occupied by the sessions and state " +
+			"updates stored by the tower.",
+	}, func() float64 {
+		numBytes, err := storageBytes()
+		if err != nil {
+			log.Errorf("Unable to fetch tower storage size: %v",
+				err)
+			return 0
+		}
+
+		return float64(numBytes)
+	})
+
+	prometheus.MustRegister(
+		m.sessionsCreated, m.updatesStored, m.updateBytes,
+		m.justiceBroadcasts, m.msgLatency, m.limitsExceeded,
+		m.clientsBanned, m.bannedConns, storage,
+	)
+
+	return m
+}
+
+// SessionCreated records that a new session was negotiated with a client.
+//
+// NOTE: This is part of the wtmetrics.Recorder interface.
+func (m *towerMetrics) SessionCreated() {
+	m.sessionsCreated.Inc()
+}
+
+// UpdateStored records that a state update was accepted and persisted.
+//
+// NOTE: This is part of the wtmetrics.Recorder interface.
+func (m *towerMetrics) UpdateStored(blobSize int) {
+	m.updatesStored.Inc()
+	m.updateBytes.Add(float64(blobSize))
+}
+
+// JusticeBroadcast records that a justice transaction was published.
+//
+// NOTE: This is part of the wtmetrics.Recorder interface.
+func (m *towerMetrics) JusticeBroadcast() {
+	m.justiceBroadcasts.Inc()
+}
+
+// MessageProcessed records the time it took to process a client message.
+//
+// NOTE: This is part of the wtmetrics.Recorder interface.
+func (m *towerMetrics) MessageProcessed(msgType wtwire.MessageType,
+	latency time.Duration) {
+
+	m.msgLatency.WithLabelValues(msgType.String()).Observe(
+		latency.Seconds(),
+	)
+}
+
+// LimitExceeded records that a client request exceeded a limit.
+//
+// NOTE: This is part of the wtmetrics.Recorder interface.
+func (m *towerMetrics) LimitExceeded(limit wtmetrics.Limit) {
+	m.limitsExceeded.WithLabelValues(string(limit)).Inc()
+}
+
+// ClientBanned records that a client was banned.
+//
+// NOTE: This is part of the wtmetrics.Recorder interface.
+func (m *towerMetrics) ClientBanned() {
+	m.clientsBanned.Inc()
+}
+
+// BannedConnRejected records that a connection of a banned client was
+// rejected.
+//
+// NOTE: This is part of the wtmetrics.Recorder interface.
+func (m *towerMetrics) BannedConnRejected() {
+	m.bannedConns.Inc()
+}
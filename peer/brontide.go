@@ -50,14 +50,6 @@ const (
 	// idleTimeout is the duration of inactivity before we time out a peer.
 	idleTimeout = 5 * time.Minute
 
-	// writeMessageTimeout is the timeout used when writing a message to the
-	// peer.
-	writeMessageTimeout = 5 * time.Second
-
-	// readMessageTimeout is the timeout used when reading a message from a
-	// peer.
-	readMessageTimeout = 5 * time.Second
-
 	// handshakeTimeout is the timeout used when waiting for the peer's init
 	// message.
 	handshakeTimeout = 15 * time.Second
@@ -159,6 +151,11 @@ type Config struct {
 	// a channel reenable, beginning from the time the peer was started.
 	ChanActiveTimeout time.Duration
 
+	// WireLimits caps the number of items in the messages we accept from
+	// the peer. Messages that exceed them are rejected while they're
+	// decoded.
+	WireLimits lnwire.ComplexityLimits
+
 	// ErrorBuffer stores a set of errors related to a peer. It contains error
 	// messages that our peer has recently sent us over the wire and records of
 	// unknown messages that were sent to us so that we can have a full track
@@ -167,6 +164,12 @@ type Config struct {
 	// disconnecting.
 	ErrorBuffer *queue.CircularBuffer
 
+	// EventLog records the most recent significant events of the peer,
+	// such as connects, channel reestablishes, errors and flow control
+	// stalls. Like the ErrorBuffer, it is carried across connections with
+	// the peer.
+	EventLog *EventLog
+
 	// WritePool is the task pool that manages reuse of write buffers. Write
 	// tasks are submitted to the pool in order to conserve the total number of
 	// write buffers allocated at any one time, and decouple write buffer
@@ -231,7 +234,7 @@ type Config struct {
 
 	// Sphinx is used when setting up ChannelLinks so they can decode sphinx
 	// onion blobs.
-	Sphinx *hop.OnionProcessor
+	Sphinx hop.Processor
 
 	// WitnessBeacon is used when setting up ChannelLinks so they can add any
 	// preimages that they learn.
@@ -249,6 +252,10 @@ type Config struct {
 	// HtlcNotifier is used when creating a ChannelLink.
 	HtlcNotifier *htlcswitch.HtlcNotifier
 
+	// HtlcTracer is an optional tracer of the latency of forwarded HTLCs
+	// that is used when creating a ChannelLink.
+	HtlcTracer *htlcswitch.HtlcTracer
+
 	// TowerClient is used by legacy channels to backup revoked states.
 	TowerClient wtclient.Client
 
@@ -327,8 +334,15 @@ type Config struct {
 	ChannelCommitBatchSize uint32
 
 	// HandleCustomMessage is called whenever a custom message is received
-	// from the peer.
-	HandleCustomMessage func(peer [33]byte, msg *lnwire.Custom) error
+	// from the peer. The message is a raw Custom message, or a typed
+	// message if its type was registered with lnwire.RegisterCustomMessage.
+	HandleCustomMessage func(peer [33]byte, msg lnwire.Message) error
+
+	// HandleWireMessage, if non-nil, is called for every wire message that
+	// is sent to or received from the peer along with the size of its
+	// serialization. It must not block.
+	HandleWireMessage func(peer [33]byte, msg lnwire.Message, size uint32,
+		inbound bool)
 
 	// GetAliases is passed to created links so the Switch and link can be
 	// aware of the channel's aliases.
@@ -386,6 +400,12 @@ type Brontide struct {
 
 	cfg Config
 
+	// msgReader and msgDecoder are reused to decode all messages read from
+	// the connection, which saves allocations on every message. They are
+	// only used by readNextMessage, which is never called concurrently.
+	msgReader  bytes.Reader
+	msgDecoder *lnwire.Decoder
+
 	// activeSignal when closed signals that the peer is now active and
 	// ready to process messages.
 	activeSignal chan struct{}
@@ -496,6 +516,10 @@ func NewBrontide(cfg Config) *Brontide {
 		log:                build.NewPrefixLog(logPrefix, peerLog),
 	}
 
+	p.msgDecoder = lnwire.NewDecoder(
+		&p.msgReader, lnwire.WithComplexityLimits(cfg.WireLimits),
+	)
+
 	return p
 }
 
@@ -599,6 +623,10 @@ func (p *Brontide) Start() error {
 
 	p.startTime = time.Now()
 
+	p.cfg.EventLog.Add(EventConnected, "inbound=%v, addr=%v, "+
+		"active_channels=%d", p.cfg.Inbound, p.cfg.Conn.RemoteAddr(),
+		len(activeChans))
+
 	p.wg.Add(5)
 	go p.queueHandler()
 	go p.writeHandler()
@@ -977,6 +1005,7 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 		NotifyActiveChannel:     p.cfg.ChannelNotifier.NotifyActiveChannelEvent,
 		NotifyInactiveChannel:   p.cfg.ChannelNotifier.NotifyInactiveChannelEvent,
 		HtlcNotifier:            p.cfg.HtlcNotifier,
+		HtlcTracer:              p.cfg.HtlcTracer,
 		GetAliases:              p.cfg.GetAliases,
 	}
 
@@ -1050,6 +1079,7 @@ func (p *Brontide) Disconnect(reason error) {
 
 	err := fmt.Errorf("disconnecting %s, reason: %v", p, reason)
 	p.storeError(err)
+	p.cfg.EventLog.Add(EventDisconnected, "reason: %v", reason)
 
 	p.log.Infof(err.Error())
 
@@ -1087,17 +1117,12 @@ func (p *Brontide) readNextMessage() (lnwire.Message, error) {
 		msgLen  uint64
 	)
 	err = p.cfg.ReadPool.Submit(func(buf *buffer.Read) error {
-		// Before reading the body of the message, set the read timeout
-		// accordingly to ensure we don't block other readers using the
-		// pool. We do so only after the task has been scheduled to
-		// ensure the deadline doesn't expire while the message is in
+		// The ReadNextBody method enforces the read timeout of the
+		// connection, which ensures we don't block other readers using
+		// the pool. We call it only after the task has been scheduled
+		// to ensure the deadline doesn't expire while the message is in
 		// the process of being scheduled.
-		readDeadline := time.Now().Add(readMessageTimeout)
-		readErr := noiseConn.SetReadDeadline(readDeadline)
-		if readErr != nil {
-			return readErr
-		}
-
+		//
 		// The ReadNextBody method will actually end up re-using the
 		// buffer, so within this closure, we can continue to use
 		// rawMsg as it's just a slice into the buf from the buffer
@@ -1108,10 +1133,11 @@ func (p *Brontide) readNextMessage() (lnwire.Message, error) {
 		}
 		msgLen = uint64(len(rawMsg))
 
-		// Next, create a new io.Reader implementation from the raw
-		// message, and use this to decode the message directly from.
-		msgReader := bytes.NewReader(rawMsg)
-		nextMsg, err = lnwire.ReadMessage(msgReader, 0)
+		// Next, point our reader to the raw message, and use it to
+		// decode the message directly from.
+		p.msgReader.Reset(rawMsg)
+		p.msgDecoder.Reset(&p.msgReader)
+		nextMsg, err = p.msgDecoder.ReadMessage(0)
 		if err != nil {
 			return err
 		}
@@ -1126,6 +1152,8 @@ func (p *Brontide) readNextMessage() (lnwire.Message, error) {
 	}
 
 	p.logWireMessage(nextMsg, true)
+	p.recordWireEvent(nextMsg, true)
+	p.notifyWireMessage(nextMsg, msgLen, true)
 
 	return nextMsg, nil
 }
@@ -1152,6 +1180,11 @@ type msgStream struct {
 
 	producerSema chan struct{}
 
+	// stalled is true while the producer is blocked on a full queue. It
+	// is only used by the producer, so that a stall is recorded in the
+	// peer's event log once instead of for every blocked message.
+	stalled bool
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -1272,10 +1305,23 @@ func (ms *msgStream) AddMsg(msg lnwire.Message) {
 	// we're signalled to quit, or a slot is freed up.
 	select {
 	case <-ms.producerSema:
-	case <-ms.peer.quit:
-		return
-	case <-ms.quit:
-		return
+		ms.stalled = false
+
+	default:
+		if !ms.stalled {
+			ms.stalled = true
+			ms.peer.cfg.EventLog.Add(EventFlowControlStall,
+				"message queue full, delaying %v(%v)",
+				msg.MsgType(), MessageSummary(msg))
+		}
+
+		select {
+		case <-ms.producerSema:
+		case <-ms.peer.quit:
+			return
+		case <-ms.quit:
+			return
+		}
 	}
 
 	// Next, we'll lock the condition, and add the message to the end of
@@ -1474,6 +1520,22 @@ out:
 				idleTimer.Reset(idleTimeout)
 				continue
 
+			// If the message contains more items than we're
+			// willing to process, we drop it. The message was
+			// read entirely, so we can continue with the next one.
+			case *lnwire.ErrComplexityLimit:
+				p.storeError(e)
+				idleTimer.Reset(idleTimeout)
+				continue
+
+			// Messages of the custom types registered by the
+			// application are odd, so if one is invalid we drop it
+			// and continue with the next one.
+			case *lnwire.ErrInvalidCustomMessage:
+				p.storeError(e)
+				idleTimer.Reset(idleTimeout)
+				continue
+
 			// If the NodeAnnouncement has an invalid alias, then
 			// we'll log that error above and continue so we can
 			// continue to read messages from the peer. We do not
@@ -1588,6 +1650,19 @@ out:
 			}
 
 		default:
+			// Messages of custom types that were registered by
+			// the application are decoded into typed messages,
+			// which we hand to the custom message handler as is.
+			if msg.MsgType() >= lnwire.CustomTypeStart {
+				err := p.handleCustomMessage(msg)
+				if err != nil {
+					p.storeError(err)
+					p.log.Errorf("%v", err)
+				}
+
+				break
+			}
+
 			// If the message we received is unknown to us, store
 			// the type to track the failure.
 			err := fmt.Errorf("unknown message type %v received",
@@ -1625,8 +1700,9 @@ out:
 }
 
 // handleCustomMessage handles the given custom message if a handler is
-// registered.
-func (p *Brontide) handleCustomMessage(msg *lnwire.Custom) error {
+// registered. The message is either a raw Custom message or a typed message of
+// a custom message type registered by the application.
+func (p *Brontide) handleCustomMessage(msg lnwire.Message) error {
 	if p.cfg.HandleCustomMessage == nil {
 		return fmt.Errorf("no custom message handler for "+
 			"message type %v", uint16(msg.MsgType()))
@@ -1709,10 +1785,10 @@ func (p *Brontide) handleError(msg *lnwire.Error) bool {
 	}
 }
 
-// messageSummary returns a human-readable string that summarizes a
+// MessageSummary returns a human-readable string that summarizes a
 // incoming/outgoing message. Not all messages will have a summary, only those
 // which have additional data that can be informative at a glance.
-func messageSummary(msg lnwire.Message) string {
+func MessageSummary(msg lnwire.Message) string {
 	switch msg := msg.(type) {
 	case *lnwire.Init:
 		// No summary.
@@ -1857,7 +1933,7 @@ func (p *Brontide) logWireMessage(msg lnwire.Message, read bool) {
 
 	p.log.Debugf("%v", newLogClosure(func() string {
 		// Debug summary of message.
-		summary := messageSummary(msg)
+		summary := MessageSummary(msg)
 		if len(summary) > 0 {
 			summary = "(" + summary + ")"
 		}
@@ -1888,6 +1964,42 @@ func (p *Brontide) logWireMessage(msg lnwire.Message, read bool) {
 	}))
 }
 
+// recordWireEvent records the receipt or sending of the wire messages that
+// are significant enough to be kept in the peer's event log.
+func (p *Brontide) recordWireEvent(msg lnwire.Message, read bool) {
+	direction := "received"
+	if !read {
+		direction = "sent"
+	}
+
+	switch msg := msg.(type) {
+	case *lnwire.ChannelReestablish:
+		p.cfg.EventLog.Add(EventReestablish, "%v channel_reestablish: "+
+			"chan_id=%v, %v", direction, msg.ChanID,
+			MessageSummary(msg))
+
+	case *lnwire.Warning:
+		p.cfg.EventLog.Add(EventError, "%v warning: chan_id=%v, %v",
+			direction, msg.ChanID, msg.Error.Error())
+
+	case *lnwire.Error:
+		p.cfg.EventLog.Add(EventError, "%v error: chan_id=%v, %v",
+			direction, msg.ChanID, msg.Error())
+	}
+}
+
+// notifyWireMessage hands the given wire message to the HandleWireMessage
+// callback, if one was configured.
+func (p *Brontide) notifyWireMessage(msg lnwire.Message, size uint64,
+	read bool) {
+
+	if p.cfg.HandleWireMessage == nil {
+		return
+	}
+
+	p.cfg.HandleWireMessage(p.cfg.PubKeyBytes, msg, uint32(size), read)
+}
+
 // writeMessage writes and flushes the target lnwire.Message to the remote peer.
 // If the passed message is nil, this method will only try to flush an existing
 // message buffered on the connection. It is safe to call this method again
@@ -1902,22 +2014,16 @@ func (p *Brontide) writeMessage(msg lnwire.Message) error {
 	// Only log the message on the first attempt.
 	if msg != nil {
 		p.logWireMessage(msg, false)
+		p.recordWireEvent(msg, false)
 	}
 
 	noiseConn := p.cfg.Conn
 
 	flushMsg := func() error {
-		// Ensure the write deadline is set before we attempt to send
-		// the message.
-		writeDeadline := time.Now().Add(writeMessageTimeout)
-		err := noiseConn.SetWriteDeadline(writeDeadline)
-		if err != nil {
-			return err
-		}
-
-		// Flush the pending message to the wire. If an error is
-		// encountered, e.g. write timeout, the number of bytes written
-		// so far will be returned.
+		// Flush the pending message to the wire, which enforces the
+		// write timeout of the connection. If an error is encountered,
+		// e.g. write timeout, the number of bytes written so far will
+		// be returned.
 		n, err := noiseConn.Flush()
 
 		// Record the number of bytes written on the wire, if any.
@@ -1937,6 +2043,7 @@ func (p *Brontide) writeMessage(msg lnwire.Message) error {
 
 	// Otherwise, this is a new message. We'll acquire a write buffer to
 	// serialize the message and buffer the ciphertext on the connection.
+	var msgLen uint64
 	err := p.cfg.WritePool.Submit(func(buf *bytes.Buffer) error {
 		// Using a buffer allocated by the write pool, encode the
 		// message directly into the buffer.
@@ -1944,6 +2051,7 @@ func (p *Brontide) writeMessage(msg lnwire.Message) error {
 		if writeErr != nil {
 			return writeErr
 		}
+		msgLen = uint64(buf.Len())
 
 		// Finally, write the message itself in a single swoop. This
 		// will buffer the ciphertext on the underlying connection. We
@@ -1955,6 +2063,8 @@ func (p *Brontide) writeMessage(msg lnwire.Message) error {
 		return err
 	}
 
+	p.notifyWireMessage(msg, msgLen, false)
+
 	return flushMsg()
 }
 
@@ -2682,8 +2792,8 @@ func (p *Brontide) restartCoopClose(lnChan *lnwallet.LightningChannel) (
 	}
 
 	// This does not need a mutex even though it is in a different
-	// goroutine since this is done before the channelManager goroutine is
-	// created.
+	// goroutine since this is done either before the channelManager
+	// goroutine is created or from within it.
 	chanID := lnwire.NewChanIDFromOutPoint(&c.FundingOutpoint)
 	p.activeChanCloses[chanID] = chanCloser
 
@@ -2726,7 +2836,8 @@ func (p *Brontide) createChanCloser(channel *lnwallet.LightningChannel,
 					op, false,
 				)
 			},
-			MaxFee: maxFee,
+			MaxFee:        maxFee,
+			RelayFeePerKW: p.cfg.FeeEstimator.RelayFeePerKW,
 			Disconnect: func() error {
 				return p.cfg.DisconnectPeer(p.IdentityKey())
 			},
@@ -2748,6 +2859,14 @@ func (p *Brontide) createChanCloser(channel *lnwallet.LightningChannel,
 func (p *Brontide) handleLocalCloseReq(req *htlcswitch.ChanClose) {
 	chanID := lnwire.NewChanIDFromOutPoint(req.ChanPoint)
 
+	// The link of a channel whose commitment was rejected is gone, so its
+	// cooperative closure is started from its state on disk. The result
+	// is sent on the error channel, even if it's nil.
+	if req.CloseType == contractcourt.CloseRejectedCommit {
+		req.Err <- p.coopCloseRejectedCommit(chanID, req.ChanPoint)
+		return
+	}
+
 	p.activeChanMtx.RLock()
 	channel, ok := p.activeChannels[chanID]
 	p.activeChanMtx.RUnlock()
@@ -2841,6 +2960,50 @@ func (p *Brontide) handleLocalCloseReq(req *htlcswitch.ChanClose) {
 	}
 }
 
+// coopCloseRejectedCommit starts the cooperative closure of a channel that was
+// force closed by us, but whose commitment transaction was rejected by the
+// mempool policy of our backend. The closure is driven like one that is
+// restarted after a reconnection, which also takes over if we disconnect
+// before it's finished.
+func (p *Brontide) coopCloseRejectedCommit(chanID lnwire.ChannelID,
+	chanPoint *wire.OutPoint) error {
+
+	// The closure may be in progress already, for example because it was
+	// restarted when we reconnected to the peer.
+	if _, ok := p.activeChanCloses[chanID]; ok {
+		return nil
+	}
+
+	dbChan, err := p.cfg.ChannelDB.FetchChannel(nil, *chanPoint)
+	if err != nil {
+		return err
+	}
+
+	lnChan, err := lnwallet.NewLightningChannel(
+		p.cfg.Signer, dbChan, p.cfg.SigPool,
+	)
+	if err != nil {
+		return err
+	}
+
+	shutdownMsg, err := p.restartCoopClose(lnChan)
+	if err != nil {
+		return err
+	}
+
+	// A nil message means a closing transaction was negotiated already.
+	if shutdownMsg == nil {
+		return nil
+	}
+
+	p.log.Infof("Starting cooperative close of ChannelPoint(%v) as its "+
+		"commitment was rejected", chanPoint)
+
+	p.queueMsg(shutdownMsg, nil)
+
+	return nil
+}
+
 // linkFailureReport is sent to the channelManager whenever a link reports a
 // link failure, and is forced to exit. The report houses the necessary
 // information to clean up the channel state, send back the error message, and
@@ -2873,6 +3036,13 @@ func (p *Brontide) handleLinkFailure(failure linkFailureReport) {
 	// being applied.
 	p.WipeChannel(&failure.chanPoint)
 
+	eventType := EventError
+	if failure.linkErr.RemoteUnresponsive() {
+		eventType = EventFlowControlStall
+	}
+	p.cfg.EventLog.Add(eventType, "link(%v) failed: %v",
+		failure.shortChanID, failure.linkErr)
+
 	// If the error encountered was severe enough, we'll now force close the
 	// channel to prevent reading it to the switch in the future.
 	if failure.linkErr.ForceClose {
@@ -3450,6 +3620,11 @@ func (p *Brontide) ErrorBuffer() *queue.CircularBuffer {
 	return p.cfg.ErrorBuffer
 }
 
+// EventLog is a getter for the Brontide's event log in cfg.
+func (p *Brontide) EventLog() *EventLog {
+	return p.cfg.EventLog
+}
+
 // SetAddress sets the remote peer's address given an address.
 func (p *Brontide) SetAddress(address net.Addr) {
 	p.cfg.Addr.Address = address
@@ -1054,11 +1054,11 @@ func TestPeerCustomMessage(t *testing.T) {
 		Conn:                          mockConn,
 		ChainNotifier:                 notifier,
 		HandleCustomMessage: func(
-			peer [33]byte, msg *lnwire.Custom) error {
+			peer [33]byte, msg lnwire.Message) error {
 
 			receivedCustomChan <- &customMsg{
 				peer: peer,
-				msg:  *msg,
+				msg:  *msg.(*lnwire.Custom),
 			}
 			return nil
 		},
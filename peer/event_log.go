@@ -0,0 +1,136 @@
+package peer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/queue"
+)
+
+// EventLogSize is the number of recent significant events that we keep for
+// each peer.
+const EventLogSize = 50
+
+// EventType is the type of significant event that is recorded in a peer's
+// event log.
+type EventType uint8
+
+const (
+	// EventConnected is recorded once the connection to the peer is
+	// established and the init messages were exchanged.
+	EventConnected EventType = iota
+
+	// EventDisconnected is recorded when we disconnect from the peer.
+	EventDisconnected
+
+	// EventReestablish is recorded when a channel_reestablish message is
+	// sent to or received from the peer.
+	EventReestablish
+
+	// EventError is recorded when an error or warning is sent to or
+	// received from the peer, or one of our links with the peer fails.
+	EventError
+
+	// EventFlowControlStall is recorded when the processing of the peer's
+	// messages stalls, either because the queue of a message stream is
+	// full or because the peer doesn't respond to our channel updates in
+	// time.
+	EventFlowControlStall
+)
+
+// String returns a human readable name of the event type.
+func (e EventType) String() string {
+	switch e {
+	case EventConnected:
+		return "Connected"
+
+	case EventDisconnected:
+		return "Disconnected"
+
+	case EventReestablish:
+		return "Reestablish"
+
+	case EventError:
+		return "Error"
+
+	case EventFlowControlStall:
+		return "FlowControlStall"
+
+	default:
+		return fmt.Sprintf("Unknown(%d)", uint8(e))
+	}
+}
+
+// Event is a significant event in the lifetime of a peer connection.
+type Event struct {
+	// Timestamp is the time the event was recorded.
+	Timestamp time.Time
+
+	// Type is the type of the event.
+	Type EventType
+
+	// Details is a human readable description of the event.
+	Details string
+}
+
+// EventLog is a bounded, in-memory log of the most recent significant events
+// of a peer. It allows debugging a single peer without having to enable debug
+// logging for all peers. Once the log is full, the oldest events are
+// overwritten. The log is safe for concurrent use, and a nil log silently
+// discards all events.
+type EventLog struct {
+	events *queue.CircularBuffer
+	mtx    sync.Mutex
+}
+
+// NewEventLog creates a new event log that keeps the given number of most
+// recent events.
+func NewEventLog(size int) (*EventLog, error) {
+	events, err := queue.NewCircularBuffer(size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventLog{
+		events: events,
+	}, nil
+}
+
+// Add records a new event of the given type, with the details formatted
+// according to the format specifier.
+func (e *EventLog) Add(eventType EventType, format string,
+	args ...interface{}) {
+
+	if e == nil {
+		return
+	}
+
+	event := &Event{
+		Timestamp: time.Now(),
+		Type:      eventType,
+		Details:   fmt.Sprintf(format, args...),
+	}
+
+	e.mtx.Lock()
+	e.events.Add(event)
+	e.mtx.Unlock()
+}
+
+// List returns the recorded events ordered from the oldest to the newest.
+func (e *EventLog) List() []*Event {
+	if e == nil {
+		return nil
+	}
+
+	e.mtx.Lock()
+	items := e.events.List()
+	e.mtx.Unlock()
+
+	events := make([]*Event, 0, len(items))
+	for _, item := range items {
+		events = append(events, item.(*Event))
+	}
+
+	return events
+}
@@ -0,0 +1,44 @@
+package peer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestEventLog tests that the event log keeps the most recent events in order
+// and that a nil log discards all events.
+func TestEventLog(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewEventLog(0)
+	require.Error(t, err)
+
+	const size = 3
+	eventLog, err := NewEventLog(size)
+	require.NoError(t, err)
+	require.Empty(t, eventLog.List())
+
+	eventLog.Add(EventConnected, "inbound=%v", true)
+	events := eventLog.List()
+	require.Len(t, events, 1)
+	require.Equal(t, EventConnected, events[0].Type)
+	require.Equal(t, "inbound=true", events[0].Details)
+	require.False(t, events[0].Timestamp.IsZero())
+
+	// Overflow the log, only the most recent events must be kept.
+	for i := 0; i < size+1; i++ {
+		eventLog.Add(EventError, "error %d", i)
+	}
+	events = eventLog.List()
+	require.Len(t, events, size)
+	for i, event := range events {
+		require.Equal(t, EventError, event.Type)
+		require.Equal(t, fmt.Sprintf("error %d", i+1), event.Details)
+	}
+
+	var nilLog *EventLog
+	nilLog.Add(EventDisconnected, "reason")
+	require.Nil(t, nilLog.List())
+}
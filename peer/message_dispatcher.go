@@ -0,0 +1,202 @@
+package peer
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/queue"
+)
+
+// WireMessage is a summary of a wire message that was sent to or received
+// from a peer.
+type WireMessage struct {
+	// Peer is the peer pubkey.
+	Peer [33]byte
+
+	// Inbound is true if the message was received from the peer.
+	Inbound bool
+
+	// MsgType is the type of the wire message.
+	MsgType lnwire.MessageType
+
+	// Size is the size of the serialized message in bytes.
+	Size uint32
+
+	// Summary is a short summary of the most relevant message fields.
+	Summary string
+}
+
+// MessageFilter selects the wire messages that are dispatched to a message
+// subscription.
+type MessageFilter struct {
+	// Peers is the set of peers whose messages are selected. An empty set
+	// selects the messages of all peers.
+	Peers map[[33]byte]struct{}
+
+	// MsgTypes is the set of message types that are selected. An empty
+	// set selects messages of all types.
+	MsgTypes map[lnwire.MessageType]struct{}
+
+	// SampleRate selects only one out of every SampleRate messages that
+	// match the other criteria. A rate of 0 or 1 selects all of them.
+	SampleRate uint64
+}
+
+// MessageSubscription is a subscription to the summaries of the wire messages
+// selected by its filter.
+type MessageSubscription struct {
+	id     uint64
+	filter MessageFilter
+
+	// numMatched is the number of messages that matched the filter so
+	// far, it must be accessed atomically.
+	numMatched uint64
+
+	updates    *queue.ConcurrentQueue
+	dispatcher *MessageDispatcher
+	cancelOnce sync.Once
+}
+
+// matches returns whether the given message is selected by the
+// subscription's filter. Every call for a matching message counts towards
+// the sample rate.
+func (s *MessageSubscription) matches(peer [33]byte,
+	msgType lnwire.MessageType) bool {
+
+	if len(s.filter.Peers) > 0 {
+		if _, ok := s.filter.Peers[peer]; !ok {
+			return false
+		}
+	}
+
+	if len(s.filter.MsgTypes) > 0 {
+		if _, ok := s.filter.MsgTypes[msgType]; !ok {
+			return false
+		}
+	}
+
+	if s.filter.SampleRate <= 1 {
+		return true
+	}
+
+	numMatched := atomic.AddUint64(&s.numMatched, 1)
+
+	return (numMatched-1)%s.filter.SampleRate == 0
+}
+
+// Updates returns the channel over which the summaries of the selected
+// messages are delivered.
+func (s *MessageSubscription) Updates() <-chan interface{} {
+	return s.updates.ChanOut()
+}
+
+// Quit returns a channel that is closed once the dispatcher shuts down.
+func (s *MessageSubscription) Quit() <-chan struct{} {
+	return s.dispatcher.quit
+}
+
+// Cancel removes the subscription from the dispatcher. No more messages are
+// delivered once it returns.
+func (s *MessageSubscription) Cancel() {
+	s.cancelOnce.Do(func() {
+		s.dispatcher.mu.Lock()
+		delete(s.dispatcher.subscriptions, s.id)
+		s.dispatcher.mu.Unlock()
+
+		s.updates.Stop()
+	})
+}
+
+// MessageDispatcher dispatches summaries of the wire messages exchanged with
+// our peers to the subscriptions whose filter selects them. As a summary is
+// comparatively expensive to create, it is only created for messages that at
+// least one subscription selects.
+type MessageDispatcher struct {
+	// summarize creates the summary of a message, it is replaced in
+	// tests.
+	summarize func(lnwire.Message) string
+
+	mu            sync.RWMutex
+	subscriptions map[uint64]*MessageSubscription
+	nextID        uint64
+
+	stopOnce sync.Once
+	quit     chan struct{}
+}
+
+// NewMessageDispatcher creates a new MessageDispatcher without any
+// subscriptions.
+func NewMessageDispatcher() *MessageDispatcher {
+	return &MessageDispatcher{
+		summarize:     MessageSummary,
+		subscriptions: make(map[uint64]*MessageSubscription),
+		quit:          make(chan struct{}),
+	}
+}
+
+// Stop signals all subscriptions that the dispatcher is shutting down.
+func (d *MessageDispatcher) Stop() error {
+	d.stopOnce.Do(func() {
+		close(d.quit)
+	})
+
+	return nil
+}
+
+// Subscribe creates a new subscription to the messages selected by the given
+// filter. The subscription must be canceled once it is no longer needed.
+func (d *MessageDispatcher) Subscribe(
+	filter MessageFilter) *MessageSubscription {
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	sub := &MessageSubscription{
+		id:         d.nextID,
+		filter:     filter,
+		updates:    queue.NewConcurrentQueue(20),
+		dispatcher: d,
+	}
+	sub.updates.Start()
+
+	d.subscriptions[sub.id] = sub
+	d.nextID++
+
+	return sub
+}
+
+// Dispatch delivers a summary of the given message to all subscriptions that
+// select it. The summary is created right away, as the message itself may be
+// re-used once we return.
+func (d *MessageDispatcher) Dispatch(peer [33]byte, msg lnwire.Message,
+	size uint32, inbound bool) {
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var update *WireMessage
+	for _, sub := range d.subscriptions {
+		if !sub.matches(peer, msg.MsgType()) {
+			continue
+		}
+
+		// Only summarize the message once, and only if any
+		// subscription selects it.
+		if update == nil {
+			update = &WireMessage{
+				Peer:    peer,
+				Inbound: inbound,
+				MsgType: msg.MsgType(),
+				Size:    size,
+				Summary: d.summarize(msg),
+			}
+		}
+
+		select {
+		case sub.updates.ChanIn() <- update:
+		case <-d.quit:
+			return
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package peer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// receiveWireMessage returns the next message delivered to the subscription.
+func receiveWireMessage(t *testing.T, sub *MessageSubscription) *WireMessage {
+	t.Helper()
+
+	select {
+	case update := <-sub.Updates():
+		return update.(*WireMessage)
+
+	case <-time.After(time.Second):
+		t.Fatalf("no message received")
+		return nil
+	}
+}
+
+// assertNoWireMessage asserts that no message is delivered to the
+// subscription.
+func assertNoWireMessage(t *testing.T, sub *MessageSubscription) {
+	t.Helper()
+
+	select {
+	case update := <-sub.Updates():
+		t.Fatalf("unexpected message: %v", update)
+
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// TestMessageDispatcher asserts that messages are only delivered to the
+// subscriptions whose filter selects them, and that a message is only
+// summarized if any subscription selects it.
+func TestMessageDispatcher(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = [33]byte{1}
+		bob   = [33]byte{2}
+		ping  = lnwire.NewPing(0)
+		pong  = lnwire.NewPong(nil)
+	)
+
+	dispatcher := NewMessageDispatcher()
+	defer dispatcher.Stop()
+
+	var numSummaries int
+	dispatcher.summarize = func(msg lnwire.Message) string {
+		numSummaries++
+		return msg.MsgType().String()
+	}
+
+	// Without any subscription, no message is summarized.
+	dispatcher.Dispatch(alice, ping, 10, true)
+	require.Zero(t, numSummaries)
+
+	aliceSub := dispatcher.Subscribe(MessageFilter{
+		Peers: map[[33]byte]struct{}{alice: {}},
+	})
+	defer aliceSub.Cancel()
+
+	pingSub := dispatcher.Subscribe(MessageFilter{
+		MsgTypes: map[lnwire.MessageType]struct{}{
+			lnwire.MsgPing: {},
+		},
+	})
+	defer pingSub.Cancel()
+
+	// Neither subscription selects a pong of Bob, so it isn't summarized.
+	dispatcher.Dispatch(bob, pong, 10, false)
+	require.Zero(t, numSummaries)
+	assertNoWireMessage(t, aliceSub)
+	assertNoWireMessage(t, pingSub)
+
+	// A ping of Alice is selected by both subscriptions, but it's only
+	// summarized once.
+	dispatcher.Dispatch(alice, ping, 10, true)
+	require.Equal(t, 1, numSummaries)
+
+	expected := &WireMessage{
+		Peer:    alice,
+		Inbound: true,
+		MsgType: lnwire.MsgPing,
+		Size:    10,
+		Summary: ping.MsgType().String(),
+	}
+	require.Equal(t, expected, receiveWireMessage(t, aliceSub))
+	require.Equal(t, expected, receiveWireMessage(t, pingSub))
+
+	// A ping of Bob is only selected by the ping subscription.
+	dispatcher.Dispatch(bob, ping, 20, false)
+	require.Equal(t, 2, numSummaries)
+	require.Equal(t, bob, receiveWireMessage(t, pingSub).Peer)
+	assertNoWireMessage(t, aliceSub)
+
+	// Once canceled, a subscription no longer receives any messages.
+	aliceSub.Cancel()
+	dispatcher.Dispatch(alice, pong, 10, true)
+	require.Equal(t, 2, numSummaries)
+
+	// Stopping the dispatcher signals the remaining subscriptions.
+	require.NoError(t, dispatcher.Stop())
+	select {
+	case <-pingSub.Quit():
+	default:
+		t.Fatalf("subscription not signaled")
+	}
+}
+
+// TestMessageDispatcherSampleRate asserts that only one out of every sample
+// rate matching messages is summarized and delivered.
+func TestMessageDispatcherSampleRate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = [33]byte{1}
+		ping  = lnwire.NewPing(0)
+	)
+
+	dispatcher := NewMessageDispatcher()
+	defer dispatcher.Stop()
+
+	var numSummaries int
+	dispatcher.summarize = func(msg lnwire.Message) string {
+		numSummaries++
+		return ""
+	}
+
+	sub := dispatcher.Subscribe(MessageFilter{SampleRate: 3})
+	defer sub.Cancel()
+
+	for i := 0; i < 7; i++ {
+		dispatcher.Dispatch(alice, ping, uint32(i), true)
+	}
+
+	// The first, fourth and seventh message are selected.
+	require.Equal(t, 3, numSummaries)
+	for _, size := range []uint32{0, 3, 6} {
+		require.Equal(t, size, receiveWireMessage(t, sub).Size)
+	}
+	assertNoWireMessage(t, sub)
+}
@@ -355,6 +355,11 @@ func createTestPeer(notifier chainntnfs.ChainNotifier,
 		return nil, nil, nil, err
 	}
 
+	eventLog, err := NewEventLog(EventLogSize)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	var pubKey [33]byte
 	copy(pubKey[:], aliceKeyPub.SerializeCompressed())
 
@@ -368,6 +373,7 @@ func createTestPeer(notifier chainntnfs.ChainNotifier,
 		Addr:              cfgAddr,
 		PubKeyBytes:       pubKey,
 		ErrorBuffer:       errBuffer,
+		EventLog:          eventLog,
 		ChainIO:           chainIO,
 		Switch:            mockSwitch,
 		ChanActiveTimeout: chanActiveTimeout,
@@ -450,6 +456,11 @@ func (m *mockUpdateHandler) ChanID() lnwire.ChannelID { return m.cid }
 // Bandwidth currently returns a dummy value.
 func (m *mockUpdateHandler) Bandwidth() lnwire.MilliSatoshi { return 0 }
 
+// LiquiditySnapshot currently returns an empty snapshot.
+func (m *mockUpdateHandler) LiquiditySnapshot() *lnwallet.LiquiditySnapshot {
+	return &lnwallet.LiquiditySnapshot{}
+}
+
 // EligibleToForward currently returns a dummy value.
 func (m *mockUpdateHandler) EligibleToForward() bool { return false }
 
@@ -0,0 +1,140 @@
+package record_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+)
+
+// fuzzRecordRoundTrip decodes the record from the data and checks that it
+// encodes back to the same bytes.
+func fuzzRecordRoundTrip(t *testing.T, data []byte, r tlv.RecordProducer) bool {
+	t.Helper()
+
+	stream, err := tlv.NewStream(r.Record())
+	require.NoError(t, err)
+
+	if err := stream.Decode(bytes.NewReader(data)); err != nil {
+		return false
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, stream.Encode(&b))
+
+	// Decoding the encoded record must yield the same encoding.
+	stream2, err := tlv.NewStream(r.Record())
+	require.NoError(t, err)
+	require.NoError(t, stream2.Decode(bytes.NewReader(b.Bytes())))
+
+	var b2 bytes.Buffer
+	require.NoError(t, stream2.Encode(&b2))
+	require.Equal(t, b.Bytes(), b2.Bytes())
+
+	return true
+}
+
+// encodeRecord encodes the record as a tlv stream to seed the fuzz corpus.
+func encodeRecord(f *testing.F, r tlv.RecordProducer) []byte {
+	stream, err := tlv.NewStream(r.Record())
+	require.NoError(f, err)
+
+	var b bytes.Buffer
+	require.NoError(f, stream.Encode(&b))
+
+	return b.Bytes()
+}
+
+// FuzzMPP fuzzes the decoding and validation of MPP records.
+func FuzzMPP(f *testing.F) {
+	f.Add(encodeRecord(f, record.NewMPP(testTotal, testAddr)))
+	f.Add(encodeRecord(f, record.NewMPP(0, testAddr)))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		mpp := &record.MPP{}
+		if !fuzzRecordRoundTrip(t, data, mpp) {
+			return
+		}
+
+		err := mpp.Validate()
+		if mpp.TotalMsat() == 0 {
+			require.ErrorIs(t, err, record.ErrMPPTotalZero)
+		} else {
+			require.NoError(t, err)
+		}
+	})
+}
+
+// FuzzAMP fuzzes the decoding and validation of AMP records.
+func FuzzAMP(f *testing.F) {
+	f.Add(encodeRecord(f, record.NewAMP(testShare, testSetID, 1)))
+	f.Add(encodeRecord(f, record.NewAMP(testShare, [32]byte{}, 0)))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		amp := &record.AMP{}
+		if !fuzzRecordRoundTrip(t, data, amp) {
+			return
+		}
+
+		err := amp.Validate()
+		if amp.SetID() == [32]byte{} {
+			require.ErrorIs(t, err, record.ErrAMPBlankSetID)
+		} else {
+			require.NoError(t, err)
+		}
+	})
+}
+
+// FuzzAMPSet fuzzes the construction and validation of AMP sets, checking
+// that every constructed set is valid, complete and reconstructs its root
+// seed.
+func FuzzAMPSet(f *testing.F) {
+	f.Add(testShare[:], testSetID[:], uint8(3), uint64(1000))
+
+	f.Fuzz(func(t *testing.T, root, setID []byte, n uint8,
+		total uint64) {
+
+		if len(root) != 32 || len(setID) != 32 || n == 0 {
+			return
+		}
+
+		var rootSeed, id [32]byte
+		copy(rootSeed[:], root)
+		copy(id[:], setID)
+
+		records, err := record.NewAMPSet(rootSeed, id, int(n))
+		if id == [32]byte{} {
+			require.ErrorIs(t, err, record.ErrAMPBlankSetID)
+			return
+		}
+		require.NoError(t, err)
+		require.Equal(t, rootSeed, record.AMPRootSeed(records))
+
+		// Split the total amount over the htlcs of the set, the last
+		// one carrying the remainder.
+		total = total%1_000_000_000 + uint64(n)
+		mpp := record.NewMPP(lnwire.MilliSatoshi(total), testAddr)
+		share := total / uint64(n)
+
+		htlcs := make([]record.HTLCRecords, 0, n)
+		for i, amp := range records {
+			amt := share
+			if i == len(records)-1 {
+				amt = total - share*uint64(n-1)
+			}
+
+			htlcs = append(htlcs, record.HTLCRecords{
+				Amt: lnwire.MilliSatoshi(amt),
+				MPP: mpp,
+				AMP: amp,
+			})
+		}
+
+		complete, err := record.ValidateHTLCSet(htlcs)
+		require.NoError(t, err)
+		require.True(t, complete)
+	})
+}
@@ -1,6 +1,7 @@
 package record
 
 import (
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightningnetwork/lnd/tlv"
 )
 
@@ -17,9 +18,22 @@ const (
 	// of the next hop.
 	NextHopOnionType tlv.Type = 6
 
+	// EncryptedDataOnionType is the type used in the onion to reference
+	// the encrypted data that the creator of a blinded route addressed to
+	// the hop.
+	EncryptedDataOnionType tlv.Type = 10
+
+	// BlindingPointOnionType is the type used in the onion to reference
+	// the blinding point of the introduction node of a blinded route.
+	BlindingPointOnionType tlv.Type = 12
+
 	// MetadataOnionType is the type used in the onion for the payment
 	// metadata.
 	MetadataOnionType tlv.Type = 16
+
+	// TotalAmtMsatBlindedType is the type used in the onion to reference
+	// the total amount of a payment to a blinded route.
+	TotalAmtMsatBlindedType tlv.Type = 18
 )
 
 // NewAmtToFwdRecord creates a tlv.Record that encodes the amount_to_forward
@@ -61,3 +75,26 @@ func NewMetadataRecord(metadata *[]byte) tlv.Record {
 		tlv.EVarBytes, tlv.DVarBytes,
 	)
 }
+
+// NewEncryptedDataRecord creates a tlv.Record that encodes the encrypted_data
+// (type 10) for an onion payload.
+func NewEncryptedDataRecord(data *[]byte) tlv.Record {
+	return tlv.MakePrimitiveRecord(EncryptedDataOnionType, data)
+}
+
+// NewBlindingPointRecord creates a tlv.Record that encodes the
+// current_blinding_point (type 12) for an onion payload.
+func NewBlindingPointRecord(point **btcec.PublicKey) tlv.Record {
+	return tlv.MakePrimitiveRecord(BlindingPointOnionType, point)
+}
+
+// NewTotalAmtMsatBlindedRecord creates a tlv.Record that encodes the
+// total_amount_msat (type 18) for the final hop of a blinded route.
+func NewTotalAmtMsatBlindedRecord(amt *uint64) tlv.Record {
+	return tlv.MakeDynamicRecord(
+		TotalAmtMsatBlindedType, amt, func() uint64 {
+			return tlv.SizeTUint64(*amt)
+		},
+		tlv.ETUint64, tlv.DTUint64,
+	)
+}
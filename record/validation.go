@@ -0,0 +1,233 @@
+package record
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrMPPTotalZero is returned if an MPP record commits to a total
+	// amount of zero.
+	ErrMPPTotalZero = errors.New("mpp total amount is zero")
+
+	// ErrMPPAmtExceedsTotal is returned if the amount of an HTLC exceeds
+	// the total amount of its MPP record.
+	ErrMPPAmtExceedsTotal = errors.New("htlc amount exceeds mpp total " +
+		"amount")
+
+	// ErrMPPTotalMismatch is returned if the HTLCs of a set commit to
+	// different total amounts.
+	ErrMPPTotalMismatch = errors.New("mpp total amount mismatch in htlc " +
+		"set")
+
+	// ErrMPPPaymentAddrMismatch is returned if the HTLCs of a set carry
+	// different payment addresses.
+	ErrMPPPaymentAddrMismatch = errors.New("mpp payment address " +
+		"mismatch in htlc set")
+
+	// ErrMPPSetOverpaid is returned if the HTLCs of a set pay more than
+	// the total amount they commit to.
+	ErrMPPSetOverpaid = errors.New("htlc set exceeds mpp total amount")
+
+	// ErrAMPWithoutMPP is returned if an HTLC carries an AMP record but no
+	// MPP record, which is required to know the total amount of the set.
+	ErrAMPWithoutMPP = errors.New("amp record without mpp record")
+
+	// ErrAMPBlankSetID is returned if an AMP record carries an all-zero
+	// set id.
+	ErrAMPBlankSetID = errors.New("amp set id is blank")
+
+	// ErrAMPSetIDMismatch is returned if the HTLCs of a set carry
+	// different AMP set ids, or only some of them carry an AMP record.
+	ErrAMPSetIDMismatch = errors.New("amp set id mismatch in htlc set")
+
+	// ErrAMPDuplicateChild is returned if two HTLCs of a set carry the
+	// same share and child index, which would derive the same payment
+	// hash.
+	ErrAMPDuplicateChild = errors.New("duplicate amp child in htlc set")
+
+	// ErrEmptyHTLCSet is returned when validating a set without HTLCs.
+	ErrEmptyHTLCSet = errors.New("htlc set is empty")
+)
+
+// Validate checks that the MPP record commits to a non-zero total amount.
+func (r *MPP) Validate() error {
+	if r.totalMsat == 0 {
+		return ErrMPPTotalZero
+	}
+
+	return nil
+}
+
+// Validate checks that the AMP record carries a set id.
+func (a *AMP) Validate() error {
+	if a.setID == [32]byte{} {
+		return ErrAMPBlankSetID
+	}
+
+	return nil
+}
+
+// HTLCRecords holds the amount of a single HTLC that pays to the final hop,
+// along with the MPP and AMP records of its payload.
+type HTLCRecords struct {
+	// Amt is the amount of the HTLC.
+	Amt lnwire.MilliSatoshi
+
+	// MPP is the MPP record of the HTLC, if any.
+	MPP *MPP
+
+	// AMP is the AMP record of the HTLC, if any.
+	AMP *AMP
+}
+
+// Validate checks that the records of a single HTLC are consistent with each
+// other and with the amount of the HTLC.
+func (h *HTLCRecords) Validate() error {
+	if h.MPP == nil {
+		if h.AMP != nil {
+			return ErrAMPWithoutMPP
+		}
+
+		return nil
+	}
+
+	if err := h.MPP.Validate(); err != nil {
+		return err
+	}
+
+	if h.Amt > h.MPP.TotalMsat() {
+		return fmt.Errorf("%w: %v > %v", ErrMPPAmtExceedsTotal, h.Amt,
+			h.MPP.TotalMsat())
+	}
+
+	if h.AMP != nil {
+		return h.AMP.Validate()
+	}
+
+	return nil
+}
+
+// ValidateHTLCSet checks that the HTLCs of an MPP or AMP set are consistent
+// with each other: all of them must commit to the same total amount and
+// payment address, carry the same AMP set id if any, and must not pay more
+// than the total amount together. AMP children must be unique. It returns
+// whether the set is complete, meaning that it pays exactly the total amount.
+func ValidateHTLCSet(htlcs []HTLCRecords) (bool, error) {
+	if len(htlcs) == 0 {
+		return false, ErrEmptyHTLCSet
+	}
+
+	type ampChild struct {
+		share [32]byte
+		index uint32
+	}
+
+	var (
+		first    = htlcs[0]
+		total    lnwire.MilliSatoshi
+		children = make(map[ampChild]struct{})
+	)
+	for i := range htlcs {
+		htlc := &htlcs[i]
+		if err := htlc.Validate(); err != nil {
+			return false, err
+		}
+
+		// A set without MPP records can only consist of a single
+		// HTLC.
+		if htlc.MPP == nil || first.MPP == nil {
+			if len(htlcs) > 1 {
+				return false, ErrMPPTotalMismatch
+			}
+
+			return true, nil
+		}
+
+		if htlc.MPP.TotalMsat() != first.MPP.TotalMsat() {
+			return false, ErrMPPTotalMismatch
+		}
+
+		if htlc.MPP.PaymentAddr() != first.MPP.PaymentAddr() {
+			return false, ErrMPPPaymentAddrMismatch
+		}
+
+		switch {
+		case (htlc.AMP == nil) != (first.AMP == nil):
+			return false, ErrAMPSetIDMismatch
+
+		case htlc.AMP != nil:
+			if htlc.AMP.SetID() != first.AMP.SetID() {
+				return false, ErrAMPSetIDMismatch
+			}
+
+			child := ampChild{
+				share: htlc.AMP.RootShare(),
+				index: htlc.AMP.ChildIndex(),
+			}
+			if _, ok := children[child]; ok {
+				return false, ErrAMPDuplicateChild
+			}
+			children[child] = struct{}{}
+		}
+
+		total += htlc.Amt
+		if total > first.MPP.TotalMsat() {
+			return false, ErrMPPSetOverpaid
+		}
+	}
+
+	return total == first.MPP.TotalMsat(), nil
+}
+
+// NewAMPSet splits the root seed of an AMP payment into the AMP records of n
+// HTLCs that share the given set id. The shares of the records XOR to the root
+// seed and each record uses its position in the set as child index.
+func NewAMPSet(root, setID [32]byte, n int) ([]*AMP, error) {
+	if n <= 0 {
+		return nil, errors.New("amp set must contain at least one " +
+			"htlc")
+	}
+
+	if setID == [32]byte{} {
+		return nil, ErrAMPBlankSetID
+	}
+
+	// All but the last share are random, the last one is chosen so that
+	// all of them XOR to the root seed.
+	records := make([]*AMP, 0, n)
+	last := root
+	for i := 0; i < n-1; i++ {
+		var share [32]byte
+		if _, err := io.ReadFull(rand.Reader, share[:]); err != nil {
+			return nil, err
+		}
+
+		for j := range last {
+			last[j] ^= share[j]
+		}
+
+		records = append(records, NewAMP(share, setID, uint32(i)))
+	}
+	records = append(records, NewAMP(last, setID, uint32(n-1)))
+
+	return records, nil
+}
+
+// AMPRootSeed reconstructs the root seed of an AMP payment by XORing the
+// shares of all records of the set.
+func AMPRootSeed(records []*AMP) [32]byte {
+	var root [32]byte
+	for _, record := range records {
+		share := record.RootShare()
+		for i := range root {
+			root[i] ^= share[i]
+		}
+	}
+
+	return root
+}
@@ -0,0 +1,188 @@
+package record_test
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/stretchr/testify/require"
+)
+
+// TestValidateHTLCSet tests the validation of MPP and AMP htlc sets.
+func TestValidateHTLCSet(t *testing.T) {
+	t.Parallel()
+
+	var (
+		otherAddr  = [32]byte{0x07}
+		otherSetID = [32]byte{0x08}
+		mpp        = record.NewMPP(testTotal, testAddr)
+	)
+
+	ampRecords, err := record.NewAMPSet(testShare, testSetID, 2)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		htlcs       []record.HTLCRecords
+		expComplete bool
+		expErr      error
+	}{{
+		name:   "empty set",
+		expErr: record.ErrEmptyHTLCSet,
+	}, {
+		name: "single htlc without mpp",
+		htlcs: []record.HTLCRecords{
+			{Amt: 10},
+		},
+		expComplete: true,
+	}, {
+		name: "partial mpp set",
+		htlcs: []record.HTLCRecords{
+			{Amt: 20, MPP: mpp},
+		},
+	}, {
+		name: "complete mpp set",
+		htlcs: []record.HTLCRecords{
+			{Amt: 20, MPP: mpp},
+			{Amt: testTotal - 20, MPP: mpp},
+		},
+		expComplete: true,
+	}, {
+		name: "zero total",
+		htlcs: []record.HTLCRecords{
+			{Amt: 0, MPP: record.NewMPP(0, testAddr)},
+		},
+		expErr: record.ErrMPPTotalZero,
+	}, {
+		name: "htlc exceeds total",
+		htlcs: []record.HTLCRecords{
+			{Amt: testTotal + 1, MPP: mpp},
+		},
+		expErr: record.ErrMPPAmtExceedsTotal,
+	}, {
+		name: "overpaid set",
+		htlcs: []record.HTLCRecords{
+			{Amt: testTotal, MPP: mpp},
+			{Amt: 1, MPP: mpp},
+		},
+		expErr: record.ErrMPPSetOverpaid,
+	}, {
+		name: "total mismatch",
+		htlcs: []record.HTLCRecords{
+			{Amt: 1, MPP: mpp},
+			{Amt: 1, MPP: record.NewMPP(testTotal+1, testAddr)},
+		},
+		expErr: record.ErrMPPTotalMismatch,
+	}, {
+		name: "missing mpp in set",
+		htlcs: []record.HTLCRecords{
+			{Amt: 1, MPP: mpp},
+			{Amt: 1},
+		},
+		expErr: record.ErrMPPTotalMismatch,
+	}, {
+		name: "payment addr mismatch",
+		htlcs: []record.HTLCRecords{
+			{Amt: 1, MPP: mpp},
+			{Amt: 1, MPP: record.NewMPP(testTotal, otherAddr)},
+		},
+		expErr: record.ErrMPPPaymentAddrMismatch,
+	}, {
+		name: "amp without mpp",
+		htlcs: []record.HTLCRecords{
+			{Amt: 1, AMP: ampRecords[0]},
+		},
+		expErr: record.ErrAMPWithoutMPP,
+	}, {
+		name: "complete amp set",
+		htlcs: []record.HTLCRecords{
+			{Amt: 20, MPP: mpp, AMP: ampRecords[0]},
+			{Amt: testTotal - 20, MPP: mpp, AMP: ampRecords[1]},
+		},
+		expComplete: true,
+	}, {
+		name: "blank set id",
+		htlcs: []record.HTLCRecords{
+			{
+				Amt: 1, MPP: mpp,
+				AMP: record.NewAMP(testShare, [32]byte{}, 0),
+			},
+		},
+		expErr: record.ErrAMPBlankSetID,
+	}, {
+		name: "set id mismatch",
+		htlcs: []record.HTLCRecords{
+			{Amt: 1, MPP: mpp, AMP: ampRecords[0]},
+			{
+				Amt: 1, MPP: mpp,
+				AMP: record.NewAMP(testShare, otherSetID, 1),
+			},
+		},
+		expErr: record.ErrAMPSetIDMismatch,
+	}, {
+		name: "mixed mpp and amp",
+		htlcs: []record.HTLCRecords{
+			{Amt: 1, MPP: mpp, AMP: ampRecords[0]},
+			{Amt: 1, MPP: mpp},
+		},
+		expErr: record.ErrAMPSetIDMismatch,
+	}, {
+		name: "duplicate amp child",
+		htlcs: []record.HTLCRecords{
+			{Amt: 1, MPP: mpp, AMP: ampRecords[0]},
+			{Amt: 1, MPP: mpp, AMP: ampRecords[0]},
+		},
+		expErr: record.ErrAMPDuplicateChild,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			complete, err := record.ValidateHTLCSet(tc.htlcs)
+			require.ErrorIs(t, err, tc.expErr)
+			require.Equal(t, tc.expComplete, complete)
+		})
+	}
+}
+
+// TestNewAMPSet tests that the shares of a new AMP set reconstruct the root
+// seed.
+func TestNewAMPSet(t *testing.T) {
+	t.Parallel()
+
+	for n := 1; n <= 5; n++ {
+		records, err := record.NewAMPSet(testShare, testSetID, n)
+		require.NoError(t, err)
+		require.Len(t, records, n)
+
+		mpp := record.NewMPP(lnwire.MilliSatoshi(n), testAddr)
+		htlcs := make([]record.HTLCRecords, 0, n)
+		for i, amp := range records {
+			require.Equal(t, testSetID, amp.SetID())
+			require.EqualValues(t, i, amp.ChildIndex())
+
+			htlcs = append(htlcs, record.HTLCRecords{
+				Amt: 1,
+				MPP: mpp,
+				AMP: amp,
+			})
+		}
+
+		require.Equal(
+			t, [32]byte(testShare), record.AMPRootSeed(records),
+		)
+
+		complete, err := record.ValidateHTLCSet(htlcs)
+		require.NoError(t, err)
+		require.True(t, complete)
+	}
+
+	_, err := record.NewAMPSet(testShare, testSetID, 0)
+	require.Error(t, err)
+
+	_, err = record.NewAMPSet(testShare, [32]byte{}, 1)
+	require.ErrorIs(t, err, record.ErrAMPBlankSetID)
+}
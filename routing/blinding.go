@@ -0,0 +1,233 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+var (
+	// ErrNoBlindedPath is returned when a blinded payment without a
+	// blinded path is made.
+	ErrNoBlindedPath = errors.New("blinded payment requires a blinded path")
+
+	// ErrHTLCRestrictions is returned when a blinded payment has invalid
+	// HTLC minimum and maximum restrictions.
+	ErrHTLCRestrictions = errors.New("invalid htlc minimum and maximum")
+)
+
+// BlindedPayment holds the information that is required to pay to a blinded
+// path. The recipient provides it along with the aggregate forwarding policy
+// of all the hops of the path, as the sender doesn't know the individual
+// policies of the blinded hops.
+type BlindedPayment struct {
+	// BlindedPath is the blinded path that the payment is routed to.
+	BlindedPath *lnwire.BlindedPath
+
+	// BaseFee is the aggregate base fee of the blinded path.
+	BaseFee uint32
+
+	// ProportionalFee is the aggregate proportional fee of the blinded
+	// path, in parts per million.
+	ProportionalFee uint32
+
+	// CltvExpiryDelta is the aggregate CLTV expiry delta of the blinded
+	// path, including the final CLTV delta of the recipient.
+	CltvExpiryDelta uint16
+
+	// HtlcMinimum is the minimum HTLC that the blinded path accepts.
+	HtlcMinimum lnwire.MilliSatoshi
+
+	// HtlcMaximum is the maximum HTLC that the blinded path accepts. If
+	// zero, the HTLC amount isn't restricted.
+	HtlcMaximum lnwire.MilliSatoshi
+
+	// Features are the features of the blinded path.
+	Features *lnwire.FeatureVector
+}
+
+// Validate performs validation on a blinded payment.
+func (b *BlindedPayment) Validate() error {
+	if b.BlindedPath == nil {
+		return ErrNoBlindedPath
+	}
+
+	// The sender needs at least the introduction node of the path to be
+	// able to route to it.
+	if len(b.BlindedPath.Hops) == 0 {
+		return lnwire.ErrNoBlindedHops
+	}
+
+	if b.BlindedPath.IntroductionNode == nil ||
+		b.BlindedPath.BlindingPoint == nil {
+
+		return fmt.Errorf("blinded path requires introduction node " +
+			"and blinding point")
+	}
+
+	for i, hop := range b.BlindedPath.Hops {
+		if hop.BlindedNodeID == nil {
+			return fmt.Errorf("blinded hop %v has no node id", i)
+		}
+
+		if len(hop.EncryptedData) == 0 {
+			return fmt.Errorf("blinded hop %v has no encrypted "+
+				"data", i)
+		}
+	}
+
+	if b.HtlcMaximum != 0 && b.HtlcMaximum < b.HtlcMinimum {
+		return fmt.Errorf("%w: %v < %v", ErrHTLCRestrictions,
+			b.HtlcMaximum, b.HtlcMinimum)
+	}
+
+	return nil
+}
+
+// Target returns the vertex that path finding targets for the blinded path.
+// This is the blinded node id of the final hop of the path, or the
+// introduction node if the path only consists of the introduction node.
+func (b *BlindedPayment) Target() route.Vertex {
+	hops := b.BlindedPath.Hops
+	if len(hops) == 1 {
+		return route.NewVertex(b.BlindedPath.IntroductionNode)
+	}
+
+	return route.NewVertex(hops[len(hops)-1].BlindedNodeID)
+}
+
+// blindedChanID returns the channel id that is used for the edge leading to
+// the blinded hop with the given index. As the channels within a blinded path
+// are hidden from the sender, these ids are only used to tell the hops of the
+// path apart, and they are never put into the onion.
+func blindedChanID(hopIdx int) uint64 {
+	return uint64(hopIdx)
+}
+
+// addEdges converts the blinded path into edges and adds them to the given
+// additional edges for path finding. The edges lead from the introduction
+// node through the blinded node ids of the path. As the policies of the
+// individual hops are unknown, the aggregate policy of the path is applied to
+// the edge that leaves the introduction node, and the other edges don't
+// charge any fees.
+func (b *BlindedPayment) addEdges(
+	edges map[route.Vertex][]*channeldb.CachedEdgePolicy) {
+
+	// The nodes within the blinded path must support the TLV onion
+	// payload, as their hops only carry the encrypted data.
+	features := lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(lnwire.TLVOnionPayloadOptional),
+		lnwire.Features,
+	)
+
+	hops := b.BlindedPath.Hops
+	fromNode := route.NewVertex(b.BlindedPath.IntroductionNode)
+	for i := 1; i < len(hops); i++ {
+		toNode := route.NewVertex(hops[i].BlindedNodeID)
+
+		edge := &channeldb.CachedEdgePolicy{
+			ToNodePubKey: func() route.Vertex {
+				return toNode
+			},
+			ToNodeFeatures: features,
+			ChannelID:      blindedChanID(i),
+		}
+
+		if i == 1 {
+			edge.FeeBaseMSat = lnwire.MilliSatoshi(b.BaseFee)
+			edge.FeeProportionalMillionths = lnwire.MilliSatoshi(
+				b.ProportionalFee,
+			)
+			edge.TimeLockDelta = b.CltvExpiryDelta
+			edge.MinHTLC = b.HtlcMinimum
+
+			if b.HtlcMaximum != 0 {
+				edge.MessageFlags = lnwire.ChanUpdateOptionMaxHtlc
+				edge.MaxHTLC = b.HtlcMaximum
+			}
+		}
+
+		edges[fromNode] = append(edges[fromNode], edge)
+		fromNode = toNode
+	}
+}
+
+// addBlindedData adds the encrypted data and blinding point of the blinded
+// path to the hops of a route that was found to the path's target. The final
+// hops of the route are the hops of the blinded path, starting with the
+// introduction node.
+func (b *BlindedPayment) addBlindedData(rt *route.Route,
+	totalAmt lnwire.MilliSatoshi) error {
+
+	hops := b.BlindedPath.Hops
+
+	// If we are the introduction node, the route doesn't contain all the
+	// hops of the path. We can't pay to a blinded path that starts with
+	// ourselves, as we don't know the channel to the next hop.
+	introIdx := len(rt.Hops) - len(hops)
+	if introIdx < 0 {
+		return fmt.Errorf("route of %v hops can't contain blinded "+
+			"path of %v hops", len(rt.Hops), len(hops))
+	}
+
+	introNode := route.NewVertex(b.BlindedPath.IntroductionNode)
+	if rt.Hops[introIdx].PubKeyBytes != introNode {
+		return fmt.Errorf("route doesn't lead through introduction "+
+			"node %v", introNode)
+	}
+
+	// The hops of a blinded path only understand TLV payloads, as that's
+	// the only way to deliver their encrypted data.
+	for i, hop := range hops {
+		routeHop := rt.Hops[introIdx+i]
+		routeHop.EncryptedData = hop.EncryptedData
+		routeHop.LegacyPayload = false
+
+		if i == 0 {
+			routeHop.BlindingPoint = b.BlindedPath.BlindingPoint
+		}
+	}
+
+	rt.Hops[len(rt.Hops)-1].TotalAmtMsat = totalAmt
+
+	return nil
+}
+
+// validateBlindedPayment checks that a payment to a blinded path doesn't set
+// any fields that can't be used with blinded paths.
+func validateBlindedPayment(p *LightningPayment) error {
+	if err := p.BlindedPayment.Validate(); err != nil {
+		return err
+	}
+
+	switch {
+	case p.Target != p.BlindedPayment.Target():
+		return fmt.Errorf("payment target %v doesn't match blinded "+
+			"path target %v", p.Target, p.BlindedPayment.Target())
+
+	case len(p.RouteHints) > 0:
+		return errors.New("route hints can't be used for blinded " +
+			"payments")
+
+	// The recipient of a blinded path identifies the payment with the
+	// path id in its encrypted data, so there is no payment address.
+	case p.PaymentAddr != nil:
+		return errors.New("payment address can't be used for blinded " +
+			"payments")
+
+	case p.amp != nil:
+		return errors.New("amp can't be used for blinded payments")
+
+	// The hops of a blinded path only carry TLV payloads, including the
+	// final hop.
+	case p.DestFeatures == nil ||
+		!p.DestFeatures.HasFeature(lnwire.TLVOnionPayloadOptional):
+
+		return errNoTlvPayload
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package routing
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestBlindedPayment creates a blinded payment to a path with the given
+// introduction node and number of blinded hops after it.
+func newTestBlindedPayment(t *testing.T, introNode route.Vertex,
+	numBlindedHops int) *BlindedPayment {
+
+	introKey, err := btcec.ParsePubKey(introNode[:])
+	require.NoError(t, err)
+
+	_, blindingPoint := btcec.PrivKeyFromBytes([]byte{1})
+
+	path := &lnwire.BlindedPath{
+		IntroductionNode: introKey,
+		BlindingPoint:    blindingPoint,
+	}
+	for i := 0; i <= numBlindedHops; i++ {
+		_, blindedKey := btcec.PrivKeyFromBytes([]byte{byte(i + 2)})
+		path.Hops = append(path.Hops, &lnwire.BlindedHop{
+			BlindedNodeID: blindedKey,
+			EncryptedData: []byte{byte(i), 1, 2, 3},
+		})
+	}
+
+	return &BlindedPayment{
+		BlindedPath:     path,
+		BaseFee:         1000,
+		ProportionalFee: 2000,
+		CltvExpiryDelta: 120,
+		HtlcMinimum:     1,
+		HtlcMaximum:     lnwire.NewMSatFromSatoshis(1000),
+		Features:        tlvFeatures,
+	}
+}
+
+// TestBlindedPaymentRoute tests that a route to a blinded path leads through
+// the introduction node and the blinded hops of the path, that it pays the
+// aggregate fee of the path, and that the onion can be built for it.
+func TestBlindedPaymentRoute(t *testing.T) {
+	t.Parallel()
+
+	graph, err := parseTestGraph(t, true, basicGraphFilePath)
+	require.NoError(t, err)
+
+	sourceNode, err := graph.graph.SourceNode()
+	require.NoError(t, err)
+
+	blindedPayment := newTestBlindedPayment(
+		t, graph.aliasMap["sophon"], 2,
+	)
+	require.NoError(t, blindedPayment.Validate())
+
+	additionalEdges := make(map[route.Vertex][]*channeldb.CachedEdgePolicy)
+	blindedPayment.addEdges(additionalEdges)
+
+	restrictions := *noRestrictions
+	restrictions.DestFeatures = tlvFeatures
+
+	paymentAmt := lnwire.NewMSatFromSatoshis(100)
+	path, err := dbFindPath(
+		graph.graph, additionalEdges, &mockBandwidthHints{},
+		&restrictions, testPathFindingConfig,
+		sourceNode.PubKeyBytes, blindedPayment.Target(), paymentAmt,
+		0, 0,
+	)
+	require.NoError(t, err)
+
+	// The path should lead through songoku to the introduction node, and
+	// through both blinded hops after it.
+	blindedHops := blindedPayment.BlindedPath.Hops
+	require.Len(t, path, 4)
+	require.Equal(t, graph.aliasMap["songoku"], path[0].ToNodePubKey())
+	require.Equal(t, graph.aliasMap["sophon"], path[1].ToNodePubKey())
+	require.Equal(t,
+		route.NewVertex(blindedHops[1].BlindedNodeID),
+		path[2].ToNodePubKey(),
+	)
+	require.Equal(t, blindedPayment.Target(), path[3].ToNodePubKey())
+
+	rt, err := newRoute(
+		sourceNode.PubKeyBytes, path, 100, finalHopParams{
+			amt:       paymentAmt,
+			totalAmt:  paymentAmt,
+			cltvDelta: BlockPadding,
+		},
+	)
+	require.NoError(t, err)
+	require.NoError(t, blindedPayment.addBlindedData(rt, paymentAmt))
+
+	// The introduction node forwards the amount of the payment, and is
+	// paid the aggregate fee of the blinded path.
+	introHop := rt.Hops[1]
+	require.Equal(t, paymentAmt, introHop.AmtToForward)
+	require.Equal(t,
+		paymentAmt+path[2].ComputeFee(paymentAmt),
+		rt.Hops[0].AmtToForward,
+	)
+	require.Equal(t,
+		rt.Hops[0].OutgoingTimeLock-uint32(
+			blindedPayment.CltvExpiryDelta,
+		),
+		introHop.OutgoingTimeLock,
+	)
+
+	// Each hop of the blinded path is given its encrypted data, the
+	// introduction node the blinding point and the final hop the total
+	// amount of the payment.
+	for i, hop := range blindedHops {
+		require.Equal(t, hop.EncryptedData, rt.Hops[i+1].EncryptedData)
+	}
+	require.Nil(t, rt.Hops[0].EncryptedData)
+	blindingPoint := blindedPayment.BlindedPath.BlindingPoint
+	require.Equal(t, blindingPoint, introHop.BlindingPoint)
+	require.Equal(t, paymentAmt, rt.Hops[3].TotalAmtMsat)
+
+	_, err = rt.ToSphinxPath()
+	require.NoError(t, err)
+}
+
+// TestValidateBlindedPayment tests that payments to blinded paths that set
+// fields which can't be used with blinded paths are rejected.
+func TestValidateBlindedPayment(t *testing.T) {
+	t.Parallel()
+
+	var introNode route.Vertex
+	_, introKey := btcec.PrivKeyFromBytes([]byte{10})
+	copy(introNode[:], introKey.SerializeCompressed())
+
+	newPayment := func() *LightningPayment {
+		blindedPayment := newTestBlindedPayment(t, introNode, 1)
+
+		return &LightningPayment{
+			Target:         blindedPayment.Target(),
+			BlindedPayment: blindedPayment,
+			DestFeatures:   tlvFeatures,
+		}
+	}
+
+	require.NoError(t, validateBlindedPayment(newPayment()))
+
+	payment := newPayment()
+	payment.Target = introNode
+	require.ErrorContains(t, validateBlindedPayment(payment), "target")
+
+	payment = newPayment()
+	payment.RouteHints = [][]zpay32.HopHint{{}}
+	require.ErrorContains(t, validateBlindedPayment(payment), "hints")
+
+	payment = newPayment()
+	payment.PaymentAddr = &[32]byte{1}
+	require.ErrorContains(t, validateBlindedPayment(payment), "address")
+
+	payment = newPayment()
+	payment.DestFeatures = lnwire.EmptyFeatureVector()
+	require.ErrorIs(t, validateBlindedPayment(payment), errNoTlvPayload)
+
+	payment = newPayment()
+	payment.BlindedPayment.HtlcMaximum = 0
+	payment.BlindedPayment.HtlcMinimum = 10
+	require.NoError(t, validateBlindedPayment(payment))
+
+	payment.BlindedPayment.HtlcMaximum = 5
+	require.ErrorIs(t, validateBlindedPayment(payment), ErrHTLCRestrictions)
+
+	payment = newPayment()
+	payment.BlindedPayment.BlindedPath.Hops[1].EncryptedData = nil
+	require.ErrorContains(t, validateBlindedPayment(payment), "encrypted")
+}
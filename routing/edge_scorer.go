@@ -0,0 +1,163 @@
+package routing
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// ScoredEdge describes an edge that path finding considers adding to a
+// route. As the search is performed backwards from the target, the part of
+// the route after the edge is already known.
+type ScoredEdge struct {
+	// From is the node that forwards the payment over the edge.
+	From route.Vertex
+
+	// To is the node that receives the payment over the edge.
+	To route.Vertex
+
+	// Policy is the policy of the edge. It may be an additional edge that
+	// isn't part of the graph, such as a route hint.
+	Policy *channeldb.CachedEdgePolicy
+
+	// Capacity is the capacity of the channel of the edge. It is zero if
+	// it isn't known.
+	Capacity btcutil.Amount
+
+	// Amount is the amount that is sent over the edge.
+	Amount lnwire.MilliSatoshi
+
+	// Fee is the fee that the From node charges for forwarding the
+	// amount. It is zero if the From node is the source of the route.
+	Fee lnwire.MilliSatoshi
+
+	// TimeLockDelta is the time lock delta that the From node adds to the
+	// route. It is zero if the From node is the source of the route.
+	TimeLockDelta uint16
+}
+
+// EdgeScore is the score that an EdgeScorer assigns to an edge.
+type EdgeScore struct {
+	// Probability is the success probability of the edge in the range
+	// [0, 1]. The probabilities of all edges of a route are multiplied,
+	// and edges with a probability of zero aren't used at all.
+	Probability float64
+
+	// Penalty is a virtual cost of using the edge, expressed in
+	// millisatoshis. The penalties of all edges of a route are added up
+	// and traded off against the route's probability of succeeding.
+	Penalty lnwire.MilliSatoshi
+}
+
+// EdgeScorer is an interface that scores the edges path finding considers,
+// which allows biasing the selected routes. Path finding combines the scores
+// of a pipeline of scorers. The default pipeline scores the success
+// probability with mission control and the fees and time lock of each edge,
+// and additional scorers can be appended to it with the EdgeScorers of the
+// PathFindingConfig.
+//
+// NOTE: ScoreEdge is called for every edge that is expanded during path
+// finding, so implementations must be fast and must not block.
+type EdgeScorer interface {
+	// ScoreEdge returns the score of the given edge.
+	ScoreEdge(edge *ScoredEdge) EdgeScore
+}
+
+// EdgeScorerFunc is a function that implements the EdgeScorer interface.
+type EdgeScorerFunc func(edge *ScoredEdge) EdgeScore
+
+// ScoreEdge returns the score of the given edge.
+//
+// NOTE: This is part of the EdgeScorer interface.
+func (f EdgeScorerFunc) ScoreEdge(edge *ScoredEdge) EdgeScore {
+	return f(edge)
+}
+
+// probabilityScorer scores the edges with the success probability returned
+// by a probability source, which is usually mission control.
+type probabilityScorer struct {
+	probabilitySource func(route.Vertex, route.Vertex,
+		lnwire.MilliSatoshi, btcutil.Amount) float64
+}
+
+// ScoreEdge returns the success probability of the given edge.
+//
+// NOTE: This is part of the EdgeScorer interface.
+func (p *probabilityScorer) ScoreEdge(edge *ScoredEdge) EdgeScore {
+	return EdgeScore{
+		Probability: p.probabilitySource(
+			edge.From, edge.To, edge.Amount, edge.Capacity,
+		),
+	}
+}
+
+// feeScorer scores the edges with a penalty composed of the fee that is
+// charged for the edge and the time lock risk of the amount that is locked
+// in the edge's HTLC.
+type feeScorer struct{}
+
+// ScoreEdge returns the fee and time lock penalty of the given edge.
+//
+// NOTE: This is part of the EdgeScorer interface.
+func (feeScorer) ScoreEdge(edge *ScoredEdge) EdgeScore {
+	weight := edgeWeight(edge.Amount+edge.Fee, edge.Fee, edge.TimeLockDelta)
+
+	return EdgeScore{
+		Probability: 1,
+		Penalty:     lnwire.MilliSatoshi(weight),
+	}
+}
+
+// edgeScorerPipeline combines the scores of several edge scorers into a
+// single score. The probabilities of the scorers are multiplied and their
+// penalties are added up.
+type edgeScorerPipeline []EdgeScorer
+
+// newEdgeScorerPipeline returns the pipeline of scorers that path finding
+// uses for the given restrictions and config. The default scorers come first,
+// followed by the additional scorers of the config.
+func newEdgeScorerPipeline(r *RestrictParams,
+	cfg *PathFindingConfig) edgeScorerPipeline {
+
+	pipeline := edgeScorerPipeline{
+		&probabilityScorer{probabilitySource: r.ProbabilitySource},
+		feeScorer{},
+	}
+
+	return append(pipeline, cfg.EdgeScorers...)
+}
+
+// ScoreEdge returns the combined score of all scorers of the pipeline. Once
+// the probability drops to zero, the remaining scorers aren't consulted.
+//
+// NOTE: This is part of the EdgeScorer interface.
+func (p edgeScorerPipeline) ScoreEdge(edge *ScoredEdge) EdgeScore {
+	score := EdgeScore{
+		Probability: 1,
+	}
+
+	for _, scorer := range p {
+		s := scorer.ScoreEdge(edge)
+
+		// Probabilities outside of [0, 1] would break the assumption
+		// of path finding that adding an edge to a route can only
+		// lower its probability.
+		switch {
+		case s.Probability < 0:
+			s.Probability = 0
+
+		case s.Probability > 1:
+			s.Probability = 1
+		}
+
+		score.Probability *= s.Probability
+		score.Penalty += s.Penalty
+
+		if score.Probability == 0 {
+			break
+		}
+	}
+
+	return score
+}
@@ -65,7 +65,11 @@ func TestProbabilityExtrapolation(t *testing.T) {
 	// If we use a static value for the node probability (no extrapolation
 	// of data from other channels), all ten bad channels will be tried
 	// first before switching to the paid channel.
-	ctx.mcCfg.AprioriWeight = 1
+	aprioriCfg := ctx.mcCfg.Estimator.Config().(AprioriConfig)
+	aprioriCfg.AprioriWeight = 1
+	ctx.mcCfg.Estimator, err = NewAprioriEstimator(aprioriCfg)
+	require.NoError(t, err)
+
 	attempts, err = ctx.testPayment(1)
 	require.NoError(t, err, "payment failed")
 	if len(attempts) != 11 {
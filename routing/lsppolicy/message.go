@@ -0,0 +1,100 @@
+package lsppolicy
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// MsgTypeChannelPolicy is the odd custom message type of ChannelPolicy
+// messages, so that peers that don't know the message ignore it.
+const MsgTypeChannelPolicy lnwire.MessageType = 33769
+
+// ChannelPolicy is a custom message that an LSP sends to announce the policy
+// it applies when forwarding HTLCs to us over one of our private channels with
+// it. As private channels aren't gossiped and the LSP may not send us a
+// channel update for them, this is the only way we learn their fees.
+type ChannelPolicy struct {
+	// ShortChannelID identifies the channel the policy applies to. It may
+	// be the confirmed SCID of the channel or one of its aliases.
+	ShortChannelID lnwire.ShortChannelID
+
+	// Timestamp orders the policies announced for the same channel. Only
+	// the policy with the latest timestamp is used.
+	Timestamp uint32
+
+	// TimeLockDelta is the CLTV delta the LSP requires for HTLCs forwarded
+	// over the channel.
+	TimeLockDelta uint16
+
+	// HtlcMinimumMsat is the smallest HTLC the LSP forwards over the
+	// channel.
+	HtlcMinimumMsat lnwire.MilliSatoshi
+
+	// HtlcMaximumMsat is the largest HTLC the LSP forwards over the
+	// channel.
+	HtlcMaximumMsat lnwire.MilliSatoshi
+
+	// BaseFee is the base fee in millisatoshi the LSP charges for
+	// forwarding an HTLC over the channel.
+	BaseFee uint32
+
+	// FeeRate is the fee rate in parts per million the LSP charges for
+	// forwarding an HTLC over the channel.
+	FeeRate uint32
+}
+
+// A compile time check to ensure ChannelPolicy implements the lnwire.Message
+// interface.
+var _ lnwire.Message = (*ChannelPolicy)(nil)
+
+// Decode deserializes a serialized ChannelPolicy message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *ChannelPolicy) Decode(r io.Reader, pver uint32) error {
+	return lnwire.ReadElements(r,
+		&c.ShortChannelID,
+		&c.Timestamp,
+		&c.TimeLockDelta,
+		&c.HtlcMinimumMsat,
+		&c.HtlcMaximumMsat,
+		&c.BaseFee,
+		&c.FeeRate,
+	)
+}
+
+// Encode serializes the target ChannelPolicy into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *ChannelPolicy) Encode(w *bytes.Buffer, pver uint32) error {
+	return lnwire.WriteElements(w,
+		c.ShortChannelID,
+		c.Timestamp,
+		c.TimeLockDelta,
+		c.HtlcMinimumMsat,
+		c.HtlcMaximumMsat,
+		c.BaseFee,
+		c.FeeRate,
+	)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *ChannelPolicy) MsgType() lnwire.MessageType {
+	return MsgTypeChannelPolicy
+}
+
+// RegisterMessages registers the custom message types of this package with
+// lnwire, so that they are decoded into typed messages when received.
+func RegisterMessages() error {
+	return lnwire.RegisterCustomMessage(
+		MsgTypeChannelPolicy, func() lnwire.Message {
+			return &ChannelPolicy{}
+		},
+	)
+}
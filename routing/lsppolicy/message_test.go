@@ -0,0 +1,32 @@
+package lsppolicy
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChannelPolicyEncodeDecode tests that a ChannelPolicy survives a round
+// trip through its wire encoding.
+func TestChannelPolicyEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	policy := &ChannelPolicy{
+		ShortChannelID:  lnwire.NewShortChanIDFromInt(12345),
+		Timestamp:       1000,
+		TimeLockDelta:   144,
+		HtlcMinimumMsat: 1000,
+		HtlcMaximumMsat: 100_000_000,
+		BaseFee:         1000,
+		FeeRate:         2500,
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, policy.Encode(&b, 0))
+
+	var decoded ChannelPolicy
+	require.NoError(t, decoded.Decode(bytes.NewReader(b.Bytes()), 0))
+	require.Equal(t, policy, &decoded)
+}
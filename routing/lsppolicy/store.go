@@ -0,0 +1,281 @@
+package lsppolicy
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channelnotifier"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/subscribe"
+)
+
+var (
+	// ErrNotLSP is returned when a channel policy is received from a peer
+	// that isn't a whitelisted LSP.
+	ErrNotLSP = errors.New("peer is not a whitelisted LSP")
+
+	// ErrPrivateChannelOnly is returned when an LSP announces the policy
+	// of a public channel, whose policy is gossiped instead.
+	ErrPrivateChannelOnly = errors.New("policies can only be learned " +
+		"for private channels")
+)
+
+// Channel describes one of our channels as needed to verify the policies
+// announced for it.
+type Channel struct {
+	// ShortChannelID is the SCID our channel state refers to the channel
+	// by, which the learned policy is stored under.
+	ShortChannelID lnwire.ShortChannelID
+
+	// ChanPoint is the funding outpoint of the channel, which identifies
+	// the channel once it's closed, even if its SCID changed in between.
+	ChanPoint wire.OutPoint
+
+	// IsPublic indicates whether the channel is announced to the network.
+	IsPublic bool
+}
+
+// Config houses the dependencies of the Store.
+type Config struct {
+	// LSPs are the peers whose channel policies are accepted.
+	LSPs []route.Vertex
+
+	// SelfNode is our own node, which the channels of learned policies
+	// lead to.
+	SelfNode route.Vertex
+
+	// FetchChannel returns our channel with the given peer that is
+	// identified by the given SCID, which may be its confirmed SCID or
+	// one of its aliases.
+	FetchChannel func(peer route.Vertex,
+		scid lnwire.ShortChannelID) (*Channel, error)
+
+	// SubscribeChannelEvents, if set, provides a subscription to channel
+	// events, through which the store learns about closed channels so it
+	// can forget their policies. Otherwise, policies are kept until the
+	// node restarts.
+	SubscribeChannelEvents func() (*subscribe.Client, error)
+}
+
+// learnedPolicy is a channel policy learned from an LSP.
+type learnedPolicy struct {
+	lsp       route.Vertex
+	chanPoint wire.OutPoint
+	policy    *ChannelPolicy
+}
+
+// Store keeps the policies that whitelisted LSPs announce for our private
+// channels with them. The policies are only kept in memory, LSPs are expected
+// to announce them again whenever they reconnect.
+type Store struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	lsps map[route.Vertex]struct{}
+
+	mu       sync.RWMutex
+	policies map[lnwire.ShortChannelID]*learnedPolicy
+
+	sub  *subscribe.Client
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewStore creates a new Store accepting the policies of the configured LSPs.
+func NewStore(cfg *Config) *Store {
+	lsps := make(map[route.Vertex]struct{}, len(cfg.LSPs))
+	for _, lsp := range cfg.LSPs {
+		lsps[lsp] = struct{}{}
+	}
+
+	return &Store{
+		cfg:      cfg,
+		lsps:     lsps,
+		policies: make(map[lnwire.ShortChannelID]*learnedPolicy),
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start subscribes to the channel events, if configured, to forget the
+// policies of closed channels.
+func (s *Store) Start() error {
+	var err error
+	s.started.Do(func() {
+		if s.cfg.SubscribeChannelEvents == nil {
+			return
+		}
+
+		s.sub, err = s.cfg.SubscribeChannelEvents()
+		if err != nil {
+			return
+		}
+
+		s.wg.Add(1)
+		go s.handleClosedChannels()
+	})
+
+	return err
+}
+
+// Stop cancels the subscription to the channel events.
+func (s *Store) Stop() error {
+	s.stopped.Do(func() {
+		if s.sub != nil {
+			s.sub.Cancel()
+		}
+		close(s.quit)
+		s.wg.Wait()
+	})
+
+	return nil
+}
+
+// handleClosedChannels forgets the policies of the channels that are closed,
+// such that they're no longer used for invoice hints and pathfinding.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *Store) handleClosedChannels() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case update, ok := <-s.sub.Updates():
+			if !ok {
+				return
+			}
+
+			event, ok := update.(channelnotifier.ClosedChannelEvent)
+			if !ok {
+				continue
+			}
+
+			s.forgetChannel(event.CloseSummary.ChanPoint)
+
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// forgetChannel removes the policy learned for the channel with the given
+// funding outpoint, if any.
+func (s *Store) forgetChannel(chanPoint wire.OutPoint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for scid, learned := range s.policies {
+		if learned.chanPoint == chanPoint {
+			delete(s.policies, scid)
+		}
+	}
+}
+
+// IsLSP returns whether the given peer is a whitelisted LSP.
+func (s *Store) IsLSP(peer route.Vertex) bool {
+	_, ok := s.lsps[peer]
+	return ok
+}
+
+// HandleMessage processes a channel policy announced by the given peer. The
+// policy is only accepted if the peer is a whitelisted LSP, and the channel is
+// a private channel with the peer. Policies older than the one already known
+// for the channel are ignored.
+func (s *Store) HandleMessage(peer route.Vertex, msg *ChannelPolicy) error {
+	if !s.IsLSP(peer) {
+		return ErrNotLSP
+	}
+
+	channel, err := s.cfg.FetchChannel(peer, msg.ShortChannelID)
+	if err != nil {
+		return fmt.Errorf("unable to fetch channel %v with %v: %w",
+			msg.ShortChannelID, peer, err)
+	}
+
+	if channel.IsPublic {
+		return ErrPrivateChannelOnly
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	known, ok := s.policies[channel.ShortChannelID]
+	if ok && known.policy.Timestamp >= msg.Timestamp {
+		return nil
+	}
+
+	s.policies[channel.ShortChannelID] = &learnedPolicy{
+		lsp:       peer,
+		chanPoint: channel.ChanPoint,
+		policy:    msg,
+	}
+
+	return nil
+}
+
+// EdgePolicy returns the policy learned for the channel with the given SCID as
+// the edge policy of the LSP, or nil if no policy was learned.
+func (s *Store) EdgePolicy(scid uint64) *channeldb.ChannelEdgePolicy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	learned, ok := s.policies[lnwire.NewShortChanIDFromInt(scid)]
+	if !ok {
+		return nil
+	}
+
+	policy := learned.policy
+
+	return &channeldb.ChannelEdgePolicy{
+		ChannelID:     scid,
+		LastUpdate:    time.Unix(int64(policy.Timestamp), 0),
+		MessageFlags:  lnwire.ChanUpdateOptionMaxHtlc,
+		TimeLockDelta: policy.TimeLockDelta,
+		MinHTLC:       policy.HtlcMinimumMsat,
+		MaxHTLC:       policy.HtlcMaximumMsat,
+		FeeBaseMSat:   lnwire.MilliSatoshi(policy.BaseFee),
+		FeeProportionalMillionths: lnwire.MilliSatoshi(
+			policy.FeeRate,
+		),
+	}
+}
+
+// Edges returns the learned policies as additional edges for pathfinding,
+// indexed by the LSP the edges start at. All edges lead to our own node, so
+// they're used for payments to ourselves and for routes from another source
+// that pass through our node.
+func (s *Store) Edges() map[route.Vertex][]*channeldb.CachedEdgePolicy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	selfNode := s.cfg.SelfNode
+	edges := make(map[route.Vertex][]*channeldb.CachedEdgePolicy)
+	for scid, learned := range s.policies {
+		policy := learned.policy
+		edge := &channeldb.CachedEdgePolicy{
+			ChannelID:     scid.ToUint64(),
+			MessageFlags:  lnwire.ChanUpdateOptionMaxHtlc,
+			TimeLockDelta: policy.TimeLockDelta,
+			MinHTLC:       policy.HtlcMinimumMsat,
+			MaxHTLC:       policy.HtlcMaximumMsat,
+			FeeBaseMSat:   lnwire.MilliSatoshi(policy.BaseFee),
+			FeeProportionalMillionths: lnwire.MilliSatoshi(
+				policy.FeeRate,
+			),
+			ToNodePubKey: func() route.Vertex {
+				return selfNode
+			},
+			ToNodeFeatures: lnwire.EmptyFeatureVector(),
+		}
+
+		edges[learned.lsp] = append(edges[learned.lsp], edge)
+	}
+
+	return edges
+}
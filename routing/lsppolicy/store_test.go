@@ -0,0 +1,175 @@
+package lsppolicy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channelnotifier"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/subscribe"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	lsp      = route.Vertex{1}
+	peer     = route.Vertex{2}
+	selfNode = route.Vertex{3}
+
+	privateSCID = lnwire.NewShortChanIDFromInt(100)
+	aliasSCID   = lnwire.NewShortChanIDFromInt(200)
+	publicSCID  = lnwire.NewShortChanIDFromInt(300)
+
+	privateChanPoint = wire.OutPoint{Hash: chainhash.Hash{1}}
+)
+
+// fetchChannel returns the private channel with the LSP under its SCID or its
+// alias, and the public channel with the LSP.
+func fetchChannel(_ route.Vertex,
+	scid lnwire.ShortChannelID) (*Channel, error) {
+
+	switch scid {
+	case privateSCID, aliasSCID:
+		return &Channel{
+			ShortChannelID: privateSCID,
+			ChanPoint:      privateChanPoint,
+		}, nil
+
+	case publicSCID:
+		return &Channel{ShortChannelID: publicSCID, IsPublic: true}, nil
+
+	default:
+		return nil, errors.New("unknown channel")
+	}
+}
+
+// TestStore tests that policies are only learned from whitelisted LSPs for
+// private channels, and that only the latest policy of a channel is kept.
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(&Config{
+		LSPs:         []route.Vertex{lsp},
+		SelfNode:     selfNode,
+		FetchChannel: fetchChannel,
+	})
+
+	policy := &ChannelPolicy{
+		ShortChannelID:  aliasSCID,
+		Timestamp:       10,
+		TimeLockDelta:   40,
+		HtlcMinimumMsat: 1,
+		HtlcMaximumMsat: 1000,
+		BaseFee:         100,
+		FeeRate:         1000,
+	}
+
+	// Policies of peers that aren't LSPs are rejected.
+	require.ErrorIs(t, store.HandleMessage(peer, policy), ErrNotLSP)
+	require.Nil(t, store.EdgePolicy(privateSCID.ToUint64()))
+
+	// Policies of public channels are rejected, as are policies of
+	// unknown channels.
+	public := *policy
+	public.ShortChannelID = publicSCID
+	require.ErrorIs(
+		t, store.HandleMessage(lsp, &public), ErrPrivateChannelOnly,
+	)
+
+	unknown := *policy
+	unknown.ShortChannelID = lnwire.NewShortChanIDFromInt(400)
+	require.Error(t, store.HandleMessage(lsp, &unknown))
+
+	// The policy announced under the channel's alias is stored under the
+	// SCID of the channel.
+	require.NoError(t, store.HandleMessage(lsp, policy))
+
+	edgePolicy := store.EdgePolicy(privateSCID.ToUint64())
+	require.NotNil(t, edgePolicy)
+	require.Equal(t, privateSCID.ToUint64(), edgePolicy.ChannelID)
+	require.Equal(t, uint16(40), edgePolicy.TimeLockDelta)
+	require.Equal(t, lnwire.MilliSatoshi(1), edgePolicy.MinHTLC)
+	require.Equal(t, lnwire.MilliSatoshi(1000), edgePolicy.MaxHTLC)
+	require.Equal(t, lnwire.MilliSatoshi(100), edgePolicy.FeeBaseMSat)
+	require.Equal(
+		t, lnwire.MilliSatoshi(1000),
+		edgePolicy.FeeProportionalMillionths,
+	)
+	require.Equal(t, int64(10), edgePolicy.LastUpdate.Unix())
+
+	// An older policy is ignored, while a newer one replaces it.
+	older := *policy
+	older.Timestamp = 5
+	older.BaseFee = 1
+	require.NoError(t, store.HandleMessage(lsp, &older))
+	require.Equal(
+		t, lnwire.MilliSatoshi(100),
+		store.EdgePolicy(privateSCID.ToUint64()).FeeBaseMSat,
+	)
+
+	newer := *policy
+	newer.Timestamp = 20
+	newer.BaseFee = 200
+	require.NoError(t, store.HandleMessage(lsp, &newer))
+	require.Equal(
+		t, lnwire.MilliSatoshi(200),
+		store.EdgePolicy(privateSCID.ToUint64()).FeeBaseMSat,
+	)
+
+	// The learned policy is returned as an edge from the LSP to our own
+	// node.
+	edges := store.Edges()
+	require.Len(t, edges, 1)
+	require.Len(t, edges[lsp], 1)
+
+	edge := edges[lsp][0]
+	require.Equal(t, privateSCID.ToUint64(), edge.ChannelID)
+	require.Equal(t, lnwire.MilliSatoshi(200), edge.FeeBaseMSat)
+	require.Equal(t, selfNode, edge.ToNodePubKey())
+}
+
+// TestStoreForgetsClosedChannels tests that the policy of a channel is
+// forgotten once the channel is closed.
+func TestStoreForgetsClosedChannels(t *testing.T) {
+	t.Parallel()
+
+	chanEvents := subscribe.NewServer()
+	require.NoError(t, chanEvents.Start())
+	t.Cleanup(func() {
+		require.NoError(t, chanEvents.Stop())
+	})
+
+	store := NewStore(&Config{
+		LSPs:                   []route.Vertex{lsp},
+		SelfNode:               selfNode,
+		FetchChannel:           fetchChannel,
+		SubscribeChannelEvents: chanEvents.Subscribe,
+	})
+	require.NoError(t, store.Start())
+	t.Cleanup(func() {
+		require.NoError(t, store.Stop())
+	})
+
+	require.NoError(t, store.HandleMessage(lsp, &ChannelPolicy{
+		ShortChannelID: aliasSCID,
+		Timestamp:      10,
+	}))
+	require.NotNil(t, store.EdgePolicy(privateSCID.ToUint64()))
+
+	// Once the channel is closed, neither its policy nor its edge is
+	// returned anymore.
+	err := chanEvents.SendUpdate(channelnotifier.ClosedChannelEvent{
+		CloseSummary: &channeldb.ChannelCloseSummary{
+			ChanPoint: privateChanPoint,
+		},
+	})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return store.EdgePolicy(privateSCID.ToUint64()) == nil
+	}, time.Second, 10*time.Millisecond)
+	require.Empty(t, store.Edges())
+}
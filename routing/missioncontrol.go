@@ -3,9 +3,11 @@ package routing
 import (
 	"errors"
 	"fmt"
+	"io"
 	"sync"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -61,6 +63,21 @@ const (
 	// have passed since the previously recorded failure before the failure
 	// amount may be raised.
 	DefaultMinFailureRelaxInterval = time.Minute
+
+	// DefaultEstimator is the default estimator used for computing
+	// probabilities in pathfinding.
+	DefaultEstimator = AprioriEstimatorName
+)
+
+const (
+	// mcSessionIdleTimeout is the time after which a mission control
+	// session that wasn't requested anymore is evicted.
+	mcSessionIdleTimeout = time.Hour
+
+	// maxMcSessions is the maximum number of mission control sessions that
+	// are kept. If a new session is requested once this number is reached,
+	// the least recently used session is evicted.
+	maxMcSessions = 100
 )
 
 var (
@@ -72,8 +89,31 @@ var (
 	// ErrInvalidFailureInterval is returned if we get an invalid failure
 	// interval.
 	ErrInvalidFailureInterval = errors.New("failure interval must be >= 0")
+
+	// ErrEmptyMcSessionName is returned if a mission control session is
+	// requested without a name.
+	ErrEmptyMcSessionName = errors.New("mission control session name " +
+		"must not be empty")
+
+	// ErrNestedMcSession is returned if a session is requested from a
+	// mission control session.
+	ErrNestedMcSession = errors.New("mission control sessions cannot be " +
+		"nested")
+
+	// ErrMcSessionNotFound is returned if a mission control session with
+	// the requested name doesn't exist.
+	ErrMcSessionNotFound = errors.New("mission control session not found")
 )
 
+// mcSession is an isolated mission control session that was forked off a
+// mission control instance.
+type mcSession struct {
+	*MissionControl
+
+	// lastUsed is the time at which the session was last requested.
+	lastUsed time.Time
+}
+
 // NodeResults contains previous results from a node to its peers.
 type NodeResults map[route.Vertex]TimedPairResult
 
@@ -102,7 +142,13 @@ type MissionControl struct {
 
 	// estimator is the probability estimator that is used with the payment
 	// results that mission control collects.
-	estimator *probabilityEstimator
+	estimator Estimator
+
+	// sessions holds the isolated mission control sessions that were
+	// forked off this instance, keyed by their name.
+	//
+	// NOTE: This is nil for the sessions themselves.
+	sessions map[string]*mcSession
 
 	sync.Mutex
 
@@ -115,9 +161,8 @@ type MissionControl struct {
 // MissionControlConfig defines parameters that control mission control
 // behaviour.
 type MissionControlConfig struct {
-	// ProbabilityEstimatorConfig is the config we will use for probability
-	// calculations.
-	ProbabilityEstimatorCfg
+	// Estimator gives probability estimates for node pairs.
+	Estimator Estimator
 
 	// MaxMcHistory defines the maximum number of payment results that are
 	// held on disk.
@@ -134,7 +179,11 @@ type MissionControlConfig struct {
 }
 
 func (c *MissionControlConfig) validate() error {
-	if err := c.ProbabilityEstimatorCfg.validate(); err != nil {
+	if c.Estimator == nil {
+		return errors.New("no probability estimator set")
+	}
+
+	if err := c.Estimator.Config().validate(); err != nil {
 		return err
 	}
 
@@ -151,11 +200,9 @@ func (c *MissionControlConfig) validate() error {
 
 // String returns a string representation of a mission control config.
 func (c *MissionControlConfig) String() string {
-	return fmt.Sprintf("Penalty Half Life: %v, Apriori Hop "+
-		"Probablity: %v, Maximum History: %v, Apriori Weight: %v, "+
-		"Minimum Failure Relax Interval: %v", c.PenaltyHalfLife,
-		c.AprioriHopProbability, c.MaxMcHistory, c.AprioriWeight,
-		c.MinFailureRelaxInterval)
+	return fmt.Sprintf("Estimator: (%v), Maximum History: %v, "+
+		"Minimum Failure Relax Interval: %v", c.Estimator,
+		c.MaxMcHistory, c.MinFailureRelaxInterval)
 }
 
 // TimedPairResult describes a timestamped pair result.
@@ -224,17 +271,13 @@ func NewMissionControl(db kvdb.Backend, self route.Vertex,
 		return nil, err
 	}
 
-	estimator := &probabilityEstimator{
-		ProbabilityEstimatorCfg: cfg.ProbabilityEstimatorCfg,
-		prevSuccessProbability:  prevSuccessProbability,
-	}
-
 	mc := &MissionControl{
 		state:     newMissionControlState(cfg.MinFailureRelaxInterval),
 		now:       time.Now,
 		selfNode:  self,
 		store:     store,
-		estimator: estimator,
+		estimator: cfg.Estimator,
+		sessions:  make(map[string]*mcSession),
 	}
 
 	if err := mc.init(); err != nil {
@@ -246,14 +289,152 @@ func NewMissionControl(db kvdb.Backend, self route.Vertex,
 
 // RunStoreTicker runs the mission control store's ticker.
 func (m *MissionControl) RunStoreTicker() {
+	if m.store == nil {
+		return
+	}
+
 	m.store.run()
 }
 
 // StopStoreTicker stops the mission control store's ticker.
 func (m *MissionControl) StopStoreTicker() {
+	if m.store == nil {
+		return
+	}
+
 	m.store.stop()
 }
 
+// Session returns the isolated mission control session with the given name.
+// If no such session exists yet, a new one is created that is seeded with the
+// current state of this mission control instance. Results reported to a
+// session only update the state of that session, so that payments can probe
+// the network aggressively without affecting the pathfinding of other
+// payments. Sessions are kept in memory only and won't survive restarts. They
+// are evicted once they weren't requested for mcSessionIdleTimeout, or to make
+// room for a new session once maxMcSessions is reached.
+func (m *MissionControl) Session(name string) (*MissionControl, error) {
+	if name == "" {
+		return nil, ErrEmptyMcSessionName
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	// Sessions can't be nested, as they don't track sessions of their
+	// own.
+	if m.sessions == nil {
+		return nil, ErrNestedMcSession
+	}
+
+	now := m.now()
+	m.evictIdleSessions(now)
+
+	if session, ok := m.sessions[name]; ok {
+		session.lastUsed = now
+		return session.MissionControl, nil
+	}
+
+	if len(m.sessions) >= maxMcSessions {
+		m.evictLeastRecentlyUsedSession()
+	}
+
+	// The session shares the state with this instance until either of
+	// them modifies it.
+	session := &mcSession{
+		MissionControl: &MissionControl{
+			state:     m.state.copy(),
+			now:       m.now,
+			selfNode:  m.selfNode,
+			estimator: m.estimator,
+		},
+		lastUsed: now,
+	}
+	m.sessions[name] = session
+
+	log.Debugf("Created mission control session %v", name)
+
+	return session.MissionControl, nil
+}
+
+// evictIdleSessions evicts the sessions that weren't requested for longer
+// than mcSessionIdleTimeout.
+//
+// NOTE: The caller must hold the mutex.
+func (m *MissionControl) evictIdleSessions(now time.Time) {
+	for name, session := range m.sessions {
+		if now.Sub(session.lastUsed) <= mcSessionIdleTimeout {
+			continue
+		}
+
+		delete(m.sessions, name)
+
+		log.Debugf("Evicted idle mission control session %v", name)
+	}
+}
+
+// evictLeastRecentlyUsedSession evicts the session that was requested least
+// recently.
+//
+// NOTE: The caller must hold the mutex.
+func (m *MissionControl) evictLeastRecentlyUsedSession() {
+	var (
+		lruName string
+		lru     *mcSession
+	)
+	for name, session := range m.sessions {
+		if lru == nil || session.lastUsed.Before(lru.lastUsed) {
+			lruName = name
+			lru = session
+		}
+	}
+
+	if lru == nil {
+		return
+	}
+
+	delete(m.sessions, lruName)
+
+	log.Debugf("Evicted least recently used mission control session %v",
+		lruName)
+}
+
+// LookupSession returns the isolated mission control session with the given
+// name, or ErrMcSessionNotFound if no such session exists.
+func (m *MissionControl) LookupSession(name string) (*MissionControl, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	now := m.now()
+	m.evictIdleSessions(now)
+
+	session, ok := m.sessions[name]
+	if !ok {
+		return nil, ErrMcSessionNotFound
+	}
+	session.lastUsed = now
+
+	return session.MissionControl, nil
+}
+
+// EndSession discards the isolated mission control session with the given
+// name. Using the same name afterwards will create a fresh session that is
+// seeded with the then current state of this mission control instance.
+func (m *MissionControl) EndSession(name string) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if _, ok := m.sessions[name]; !ok {
+		return ErrMcSessionNotFound
+	}
+
+	delete(m.sessions, name)
+
+	log.Debugf("Ended mission control session %v", name)
+
+	return nil
+}
+
 // init initializes mission control with historical data.
 func (m *MissionControl) init() error {
 	log.Debugf("Mission control state reconstruction started")
@@ -282,12 +463,19 @@ func (m *MissionControl) GetConfig() *MissionControlConfig {
 	m.Lock()
 	defer m.Unlock()
 
-	return &MissionControlConfig{
-		ProbabilityEstimatorCfg: m.estimator.ProbabilityEstimatorCfg,
-		MaxMcHistory:            m.store.maxRecords,
-		McFlushInterval:         m.store.flushInterval,
+	cfg := &MissionControlConfig{
+		Estimator:               m.estimator,
 		MinFailureRelaxInterval: m.state.minFailureRelaxInterval,
 	}
+
+	// Sessions don't persist their results, so they don't have any store
+	// settings.
+	if m.store != nil {
+		cfg.MaxMcHistory = m.store.maxRecords
+		cfg.McFlushInterval = m.store.flushInterval
+	}
+
+	return cfg
 }
 
 // SetConfig validates the config provided and updates mission control's config
@@ -306,9 +494,11 @@ func (m *MissionControl) SetConfig(cfg *MissionControlConfig) error {
 
 	log.Infof("Updating mission control cfg: %v", cfg)
 
-	m.store.maxRecords = cfg.MaxMcHistory
+	if m.store != nil {
+		m.store.maxRecords = cfg.MaxMcHistory
+	}
 	m.state.minFailureRelaxInterval = cfg.MinFailureRelaxInterval
-	m.estimator.ProbabilityEstimatorCfg = cfg.ProbabilityEstimatorCfg
+	m.estimator = cfg.Estimator
 
 	return nil
 }
@@ -319,8 +509,10 @@ func (m *MissionControl) ResetHistory() error {
 	m.Lock()
 	defer m.Unlock()
 
-	if err := m.store.clear(); err != nil {
-		return err
+	if m.store != nil {
+		if err := m.store.clear(); err != nil {
+			return err
+		}
 	}
 
 	m.state.resetHistory()
@@ -331,9 +523,10 @@ func (m *MissionControl) ResetHistory() error {
 }
 
 // GetProbability is expected to return the success probability of a payment
-// from fromNode along edge.
+// from fromNode along edge. The capacity is the capacity of the channel that is
+// used, or zero if it isn't known.
 func (m *MissionControl) GetProbability(fromNode, toNode route.Vertex,
-	amt lnwire.MilliSatoshi) float64 {
+	amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64 {
 
 	m.Lock()
 	defer m.Unlock()
@@ -343,10 +536,12 @@ func (m *MissionControl) GetProbability(fromNode, toNode route.Vertex,
 
 	// Use a distinct probability estimation function for local channels.
 	if fromNode == m.selfNode {
-		return m.estimator.getLocalPairProbability(now, results, toNode)
+		return m.estimator.LocalPairProbability(now, results, toNode)
 	}
 
-	return m.estimator.getPairProbability(now, results, toNode, amt)
+	return m.estimator.PairProbability(
+		now, results, toNode, amt, capacity,
+	)
 }
 
 // GetHistorySnapshot takes a snapshot from the current mission control state
@@ -383,6 +578,55 @@ func (m *MissionControl) ImportHistory(history *MissionControlSnapshot,
 	return nil
 }
 
+// ExportHistory writes the current mission control state to w in the
+// versioned export format, and returns the number of exported pairs. If
+// anonymize is set, the pairs involving our own node are left out and the
+// timestamps are coarsened, so that the export can be shared with other
+// nodes.
+func (m *MissionControl) ExportHistory(w io.Writer, anonymize bool) (int,
+	error) {
+
+	snapshot := m.GetHistorySnapshot()
+	if anonymize {
+		snapshot = anonymizeSnapshot(snapshot, m.selfNode)
+	}
+
+	log.Infof("Exporting %v pairs of mission control (anonymized=%v)",
+		len(snapshot.Pairs), anonymize)
+
+	err := SerializeMissionControlSnapshot(w, snapshot)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(snapshot.Pairs), nil
+}
+
+// ImportExportedHistory reads mission control state in the versioned export
+// format from r and imports it into our in-memory state like ImportHistory.
+// It returns the number of imported results.
+func (m *MissionControl) ImportExportedHistory(r io.Reader, force bool) (int,
+	error) {
+
+	snapshot, err := DeserializeMissionControlSnapshot(r)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read mission control export: "+
+			"%w", err)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	log.Infof("Importing exported history with %v pairs to mission "+
+		"control", len(snapshot.Pairs))
+
+	imported := m.state.importSnapshot(snapshot, force)
+
+	log.Infof("Imported %v results to mission control", imported)
+
+	return imported, nil
+}
+
 // GetPairHistorySnapshot returns the stored history for a given node pair.
 func (m *MissionControl) GetPairHistorySnapshot(
 	fromNode, toNode route.Vertex) TimedPairResult {
@@ -451,8 +695,11 @@ func (m *MissionControl) ReportPaymentSuccess(paymentID uint64,
 func (m *MissionControl) processPaymentResult(result *paymentResult) (
 	*channeldb.FailureReason, error) {
 
-	// Store complete result in database.
-	m.store.AddResult(result)
+	// Store complete result in database, unless this is an isolated
+	// session whose results are only kept in memory.
+	if m.store != nil {
+		m.store.AddResult(result)
+	}
 
 	m.Lock()
 	defer m.Unlock()
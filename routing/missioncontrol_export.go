@@ -0,0 +1,201 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+const (
+	// MissionControlExportVersion is the version of the serialized format
+	// that mission control state is exported in.
+	MissionControlExportVersion uint8 = 1
+
+	// maxExportedPairs is the maximum number of pairs that are read from
+	// a serialized export, to guard against allocating large amounts of
+	// memory for corrupt or malicious data.
+	maxExportedPairs = 1_000_000
+
+	// anonymizedTimeGranularity is the granularity that the timestamps of
+	// an anonymized export are truncated to, so that they can't be used
+	// to correlate the results with individual payments.
+	anonymizedTimeGranularity = time.Hour
+)
+
+var (
+	// ErrUnknownMcExportVersion is returned when a serialized mission
+	// control export has a version that we don't know how to read.
+	ErrUnknownMcExportVersion = errors.New("unknown mission control " +
+		"export version")
+)
+
+// SerializeMissionControlSnapshot writes the given snapshot to w in the
+// versioned export format. The format starts with the version byte and the
+// number of pairs, followed by the node pair, the failure and the success
+// result of each pair. Timestamps are encoded as unix nanoseconds, with zero
+// denoting the absence of a result.
+func SerializeMissionControlSnapshot(w io.Writer,
+	snapshot *MissionControlSnapshot) error {
+
+	err := channeldb.WriteElements(
+		w, MissionControlExportVersion, uint32(len(snapshot.Pairs)),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, pair := range snapshot.Pairs {
+		if _, err := w.Write(pair.Pair.From[:]); err != nil {
+			return err
+		}
+		if _, err := w.Write(pair.Pair.To[:]); err != nil {
+			return err
+		}
+
+		err := channeldb.WriteElements(
+			w, exportTime(pair.FailTime), pair.FailAmt,
+			exportTime(pair.SuccessTime), pair.SuccessAmt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeserializeMissionControlSnapshot reads a snapshot in the versioned export
+// format from r.
+func DeserializeMissionControlSnapshot(r io.Reader) (*MissionControlSnapshot,
+	error) {
+
+	var (
+		version  uint8
+		numPairs uint32
+	)
+	if err := channeldb.ReadElements(r, &version, &numPairs); err != nil {
+		return nil, err
+	}
+
+	if version != MissionControlExportVersion {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownMcExportVersion,
+			version)
+	}
+
+	if numPairs > maxExportedPairs {
+		return nil, fmt.Errorf("export contains %v pairs, at most %v "+
+			"are allowed", numPairs, maxExportedPairs)
+	}
+
+	snapshot := &MissionControlSnapshot{
+		Pairs: make([]MissionControlPairSnapshot, 0, numPairs),
+	}
+	for i := uint32(0); i < numPairs; i++ {
+		var (
+			from, to              route.Vertex
+			failTime, successTime uint64
+			failAmt, successAmt   lnwire.MilliSatoshi
+		)
+		if _, err := io.ReadFull(r, from[:]); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(r, to[:]); err != nil {
+			return nil, err
+		}
+
+		err := channeldb.ReadElements(
+			r, &failTime, &failAmt, &successTime, &successAmt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if from == to {
+			return nil, fmt.Errorf("pair %v -> %v: source and "+
+				"destination node must differ", from, to)
+		}
+
+		if failTime == 0 && successTime == 0 {
+			return nil, fmt.Errorf("pair %v -> %v: either success "+
+				"or failure result required", from, to)
+		}
+
+		pair := MissionControlPairSnapshot{
+			Pair: NewDirectedNodePair(from, to),
+			TimedPairResult: TimedPairResult{
+				FailTime:    importTime(failTime),
+				FailAmt:     failAmt,
+				SuccessTime: importTime(successTime),
+				SuccessAmt:  successAmt,
+			},
+		}
+		snapshot.Pairs = append(snapshot.Pairs, pair)
+	}
+
+	return snapshot, nil
+}
+
+// anonymizeSnapshot returns a copy of the snapshot that can be shared with
+// others without revealing our node. The results of the pairs involving our
+// own node are dropped, as they reveal our identity and the balances of our
+// channels, and the timestamps are truncated so that the results can't be
+// correlated with individual payments.
+func anonymizeSnapshot(snapshot *MissionControlSnapshot,
+	selfNode route.Vertex) *MissionControlSnapshot {
+
+	anonymized := &MissionControlSnapshot{
+		Pairs: make(
+			[]MissionControlPairSnapshot, 0, len(snapshot.Pairs),
+		),
+	}
+	for _, pair := range snapshot.Pairs {
+		if pair.Pair.From == selfNode || pair.Pair.To == selfNode {
+			continue
+		}
+
+		result := pair.TimedPairResult
+		if !result.FailTime.IsZero() {
+			result.FailTime = result.FailTime.Truncate(
+				anonymizedTimeGranularity,
+			)
+		}
+		if !result.SuccessTime.IsZero() {
+			result.SuccessTime = result.SuccessTime.Truncate(
+				anonymizedTimeGranularity,
+			)
+		}
+
+		anonymized.Pairs = append(
+			anonymized.Pairs, MissionControlPairSnapshot{
+				Pair:            pair.Pair,
+				TimedPairResult: result,
+			},
+		)
+	}
+
+	return anonymized
+}
+
+// exportTime encodes a result timestamp, with zero for the absence of a
+// result.
+func exportTime(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return uint64(t.UnixNano())
+}
+
+// importTime decodes a result timestamp that was encoded by exportTime.
+func importTime(ts uint64) time.Time {
+	if ts == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, int64(ts))
+}
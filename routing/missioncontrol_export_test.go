@@ -0,0 +1,125 @@
+package routing
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMissionControlExportSerialization tests that snapshots survive a round
+// trip through the export format, and that invalid exports are rejected.
+func TestMissionControlExportSerialization(t *testing.T) {
+	t.Parallel()
+
+	// Use local timestamps, as that's what the deserialized ones are.
+	now := time.Unix(0, mcTestTime.UnixNano())
+	snapshot := &MissionControlSnapshot{
+		Pairs: []MissionControlPairSnapshot{{
+			Pair: NewDirectedNodePair(mcTestNode1, mcTestNode2),
+			TimedPairResult: TimedPairResult{
+				FailTime: now,
+				FailAmt:  2000,
+			},
+		}, {
+			Pair: NewDirectedNodePair(mcTestNode2, mcTestNode1),
+			TimedPairResult: TimedPairResult{
+				FailTime:    now.Add(-time.Hour),
+				FailAmt:     5000,
+				SuccessTime: now,
+				SuccessAmt:  1000,
+			},
+		}},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, SerializeMissionControlSnapshot(&b, snapshot))
+	data := b.Bytes()
+
+	decoded, err := DeserializeMissionControlSnapshot(
+		bytes.NewReader(data),
+	)
+	require.NoError(t, err)
+	require.Equal(t, snapshot, decoded)
+
+	// Exports of an unknown version are rejected.
+	unknownVersion := append([]byte{}, data...)
+	unknownVersion[0] = MissionControlExportVersion + 1
+	_, err = DeserializeMissionControlSnapshot(
+		bytes.NewReader(unknownVersion),
+	)
+	require.ErrorIs(t, err, ErrUnknownMcExportVersion)
+
+	// So are truncated exports.
+	_, err = DeserializeMissionControlSnapshot(
+		bytes.NewReader(data[:len(data)-1]),
+	)
+	require.Error(t, err)
+
+	// Pairs without any result can't be imported.
+	b.Reset()
+	require.NoError(t, SerializeMissionControlSnapshot(
+		&b, &MissionControlSnapshot{
+			Pairs: []MissionControlPairSnapshot{{
+				Pair: NewDirectedNodePair(
+					mcTestNode1, mcTestNode2,
+				),
+			}},
+		},
+	))
+	_, err = DeserializeMissionControlSnapshot(&b)
+	require.ErrorContains(t, err, "either success or failure result")
+}
+
+// TestMissionControlExportImport tests that mission control state exported by
+// one node can be imported by another, and that anonymized exports leave out
+// the pairs of the exporting node.
+func TestMissionControlExportImport(t *testing.T) {
+	ctx := createMcTestContext(t)
+	ctx.now = testTime.Add(30 * time.Minute)
+
+	// The failure results in a failure of the pair from node 1 to node 2
+	// and a success of our own channel to node 1.
+	ctx.reportFailure(1000, lnwire.NewTemporaryChannelFailure(nil))
+	ctx.expectP(1000, 0)
+
+	var export bytes.Buffer
+	numPairs, err := ctx.mc.ExportHistory(&export, false)
+	require.NoError(t, err)
+	require.Equal(t, 2, numPairs)
+
+	// A fresh node starts without any knowledge about the pair, and takes
+	// over the failure with the import.
+	other := createMcTestContext(t)
+	other.now = ctx.now
+	other.expectP(1000, testAprioriHopProbability)
+
+	imported, err := other.mc.ImportExportedHistory(&export, false)
+	require.NoError(t, err)
+	require.Positive(t, imported)
+	other.expectP(1000, 0)
+
+	// The anonymized export only contains the pair from node 1 to node 2,
+	// with its failure time truncated to the hour.
+	export.Reset()
+	numPairs, err = ctx.mc.ExportHistory(&export, true)
+	require.NoError(t, err)
+	require.Equal(t, 1, numPairs)
+
+	snapshot, err := DeserializeMissionControlSnapshot(&export)
+	require.NoError(t, err)
+	require.Len(t, snapshot.Pairs, 1)
+
+	pair := snapshot.Pairs[0]
+	require.Equal(t, NewDirectedNodePair(mcTestNode1, mcTestNode2), pair.Pair)
+	require.True(t, testTime.Equal(pair.FailTime))
+	require.EqualValues(t, 1000, pair.FailAmt)
+
+	// Corrupt exports aren't imported.
+	_, err = other.mc.ImportExportedHistory(
+		bytes.NewReader([]byte{MissionControlExportVersion}), false,
+	)
+	require.Error(t, err)
+}
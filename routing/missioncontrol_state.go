@@ -24,6 +24,16 @@ type missionControlState struct {
 	// since the previously recorded failure before the failure amount may
 	// be raised.
 	minFailureRelaxInterval time.Duration
+
+	// shared indicates that the maps of the state may be referenced by
+	// copies of the state, so they must be copied before they're
+	// modified.
+	shared bool
+
+	// ownedResults holds the nodes whose results were copied since the
+	// state was last copied, so that they may be modified in place. A nil
+	// map indicates that the results of all nodes are owned by the state.
+	ownedResults map[route.Vertex]struct{}
 }
 
 // newMissionControlState instantiates a new mission control state object.
@@ -37,6 +47,79 @@ func newMissionControlState(
 	}
 }
 
+// copy returns a copy of the mission control state. The copy shares its maps
+// with the state they were copied from, which is why both of them only copy a
+// map once they modify it. This keeps copying cheap for large states, as only
+// the results of the nodes that are modified afterwards are copied.
+func (m *missionControlState) copy() *missionControlState {
+	m.shared = true
+	m.ownedResults = make(map[route.Vertex]struct{})
+
+	return &missionControlState{
+		lastPairResult:          m.lastPairResult,
+		lastSecondChance:        m.lastSecondChance,
+		minFailureRelaxInterval: m.minFailureRelaxInterval,
+		shared:                  true,
+		ownedResults:            make(map[route.Vertex]struct{}),
+	}
+}
+
+// own copies the maps of the state if they are shared with copies of the
+// state. The results of the individual nodes remain shared until they are
+// modified.
+func (m *missionControlState) own() {
+	if !m.shared {
+		return
+	}
+
+	lastPairResult := make(
+		map[route.Vertex]NodeResults, len(m.lastPairResult),
+	)
+	for node, results := range m.lastPairResult {
+		lastPairResult[node] = results
+	}
+	m.lastPairResult = lastPairResult
+
+	lastSecondChance := make(
+		map[DirectedNodePair]time.Time, len(m.lastSecondChance),
+	)
+	for pair, timestamp := range m.lastSecondChance {
+		lastSecondChance[pair] = timestamp
+	}
+	m.lastSecondChance = lastSecondChance
+
+	m.shared = false
+}
+
+// ownLastPairResult returns the results of the given node, which may be
+// modified in place. If the results are shared with copies of the state, they
+// are copied first.
+func (m *missionControlState) ownLastPairResult(
+	node route.Vertex) NodeResults {
+
+	m.own()
+
+	results, ok := m.lastPairResult[node]
+	if ok && m.ownedResults == nil {
+		return results
+	}
+	if _, owned := m.ownedResults[node]; ok && owned {
+		return results
+	}
+
+	ownedResults := make(NodeResults, len(results))
+	for toNode, result := range results {
+		ownedResults[toNode] = result
+	}
+	m.lastPairResult[node] = ownedResults
+
+	if m.ownedResults != nil {
+		m.ownedResults[node] = struct{}{}
+	}
+
+	return ownedResults
+}
+
 // getLastPairResult returns the current state for connections to the given node.
 func (m *missionControlState) getLastPairResult(node route.Vertex) (NodeResults,
 	bool) {
@@ -50,17 +133,15 @@ func (m *missionControlState) getLastPairResult(node route.Vertex) (NodeResults,
 func (m *missionControlState) resetHistory() {
 	m.lastPairResult = make(map[route.Vertex]NodeResults)
 	m.lastSecondChance = make(map[DirectedNodePair]time.Time)
+	m.shared = false
+	m.ownedResults = nil
 }
 
 // setLastPairResult stores a result for a node pair.
 func (m *missionControlState) setLastPairResult(fromNode, toNode route.Vertex,
 	timestamp time.Time, result *pairResult, force bool) {
 
-	nodePairs, ok := m.lastPairResult[fromNode]
-	if !ok {
-		nodePairs = make(NodeResults)
-		m.lastPairResult[fromNode] = nodePairs
-	}
+	nodePairs := m.ownLastPairResult(fromNode)
 
 	current := nodePairs[toNode]
 
@@ -144,10 +225,15 @@ func (m *missionControlState) setAllFail(node route.Vertex,
 
 	for fromNode, nodePairs := range m.lastPairResult {
 		for toNode := range nodePairs {
-			if fromNode == node || toNode == node {
-				nodePairs[toNode] = TimedPairResult{
-					FailTime: timestamp,
-				}
+			if fromNode != node && toNode != node {
+				continue
+			}
+
+			// The results of the node are copied first if they
+			// are shared with copies of the state.
+			nodePairs = m.ownLastPairResult(fromNode)
+			nodePairs[toNode] = TimedPairResult{
+				FailTime: timestamp,
 			}
 		}
 	}
@@ -168,6 +254,7 @@ func (m *missionControlState) requestSecondChance(timestamp time.Time,
 	// If the channel hasn't already be given a second chance or its last
 	// second chance was long ago, we give it another chance.
 	if !ok || timestamp.Sub(lastSecondChance) > minSecondChanceInterval {
+		m.own()
 		m.lastSecondChance[pair] = timestamp
 
 		log.Debugf("Second chance granted for %v->%v", fromNode, toNode)
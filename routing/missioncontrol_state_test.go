@@ -91,3 +91,59 @@ func TestMissionControlStateSetLastPairResult(t *testing.T) {
 	}
 	require.Equal(t, expected, result[to])
 }
+
+// TestMissionControlStateCopy tests that a copy of the mission control state
+// and the state it was copied from don't see each other's modifications,
+// even though they share their results until they modify them.
+func TestMissionControlStateCopy(t *testing.T) {
+	state := newMissionControlState(time.Minute)
+
+	var (
+		node1 = route.Vertex{1}
+		node2 = route.Vertex{2}
+		node3 = route.Vertex{3}
+	)
+
+	state.setLastPairResult(
+		node1, node2, testTime, &pairResult{amt: 1000}, false,
+	)
+	state.setLastPairResult(
+		node2, node3, testTime, &pairResult{amt: 2000}, false,
+	)
+
+	stateCopy := state.copy()
+
+	// A failure reported to the copy only affects the copy, while the
+	// results of the other node remain shared.
+	later := testTime.Add(time.Hour)
+	stateCopy.setLastPairResult(
+		node1, node2, later, &pairResult{amt: 500}, false,
+	)
+	stateCopy.requestSecondChance(later, node1, node3)
+
+	results, _ := state.getLastPairResult(node1)
+	require.EqualValues(t, 1000, results[node2].FailAmt)
+	results, _ = stateCopy.getLastPairResult(node1)
+	require.EqualValues(t, 500, results[node2].FailAmt)
+	require.NotContains(
+		t, state.lastSecondChance, DirectedNodePair{node1, node3},
+	)
+
+	// A node failure reported to the original state doesn't affect the
+	// copy.
+	state.setAllFail(node3, later)
+
+	results, _ = state.getLastPairResult(node2)
+	require.Equal(t, later, results[node3].FailTime)
+	require.Zero(t, results[node3].FailAmt)
+	results, _ = stateCopy.getLastPairResult(node2)
+	require.Equal(t, testTime, results[node3].FailTime)
+	require.EqualValues(t, 2000, results[node3].FailAmt)
+
+	// Once the results of a node were copied, they are modified in place.
+	results = stateCopy.ownLastPairResult(node1)
+	require.Equal(t, results, stateCopy.ownLastPairResult(node1))
+	results[node3] = TimedPairResult{FailAmt: 1}
+	results, _ = stateCopy.getLastPairResult(node1)
+	require.Contains(t, results, node3)
+}
@@ -1,10 +1,12 @@
 package routing
 
 import (
+	"fmt"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/routing/route"
@@ -37,6 +39,7 @@ var (
 	testPenaltyHalfLife       = 30 * time.Minute
 	testAprioriHopProbability = 0.9
 	testAprioriWeight         = 0.5
+	testCapacity              = btcutil.Amount(100_000)
 )
 
 type mcTestContext struct {
@@ -90,15 +93,16 @@ func (ctx *mcTestContext) restartMc() {
 		require.NoError(ctx.t, ctx.mc.store.storeResults())
 	}
 
+	estimator, err := NewAprioriEstimator(AprioriConfig{
+		PenaltyHalfLife:       testPenaltyHalfLife,
+		AprioriHopProbability: testAprioriHopProbability,
+		AprioriWeight:         testAprioriWeight,
+	})
+	require.NoError(ctx.t, err)
+
 	mc, err := NewMissionControl(
 		ctx.db, mcTestSelf,
-		&MissionControlConfig{
-			ProbabilityEstimatorCfg: ProbabilityEstimatorCfg{
-				PenaltyHalfLife:       testPenaltyHalfLife,
-				AprioriHopProbability: testAprioriHopProbability,
-				AprioriWeight:         testAprioriWeight,
-			},
-		},
+		&MissionControlConfig{Estimator: estimator},
 	)
 	if err != nil {
 		ctx.t.Fatal(err)
@@ -112,7 +116,7 @@ func (ctx *mcTestContext) restartMc() {
 func (ctx *mcTestContext) expectP(amt lnwire.MilliSatoshi, expected float64) {
 	ctx.t.Helper()
 
-	p := ctx.mc.GetProbability(mcTestNode1, mcTestNode2, amt)
+	p := ctx.mc.GetProbability(mcTestNode1, mcTestNode2, amt, testCapacity)
 	if p != expected {
 		ctx.t.Fatalf("expected probability %v but got %v", expected, p)
 	}
@@ -150,7 +154,7 @@ func TestMissionControl(t *testing.T) {
 
 	// For local channels, we expect a higher probability than our a prior
 	// test probability.
-	selfP := ctx.mc.GetProbability(mcTestSelf, mcTestNode1, 100)
+	selfP := ctx.mc.GetProbability(mcTestSelf, mcTestNode1, 100, testCapacity)
 	if selfP != prevSuccessProbability {
 		t.Fatalf("expected prev success prob for untried local chans")
 	}
@@ -222,3 +226,118 @@ func TestMissionControlChannelUpdate(t *testing.T) {
 	)
 	ctx.expectP(100, 0)
 }
+
+// TestMissionControlSession tests that results reported to an isolated mission
+// control session don't affect the mission control it was created from.
+func TestMissionControlSession(t *testing.T) {
+	ctx := createMcTestContext(t)
+
+	ctx.now = testTime
+
+	// Report a failure to the global mission control, which a new session
+	// is expected to be seeded with.
+	ctx.reportFailure(1000, lnwire.NewTemporaryChannelFailure(nil))
+	ctx.expectP(1000, 0)
+
+	_, err := ctx.mc.Session("")
+	require.ErrorIs(t, err, ErrEmptyMcSessionName)
+
+	session, err := ctx.mc.Session("probe")
+	require.NoError(t, err)
+
+	p := session.GetProbability(mcTestNode1, mcTestNode2, 1000, testCapacity)
+	require.Zero(t, p)
+
+	// Requesting the session again returns the same instance, while
+	// sessions can't be nested.
+	sameSession, err := ctx.mc.Session("probe")
+	require.NoError(t, err)
+	require.Same(t, session, sameSession)
+
+	_, err = session.Session("nested")
+	require.ErrorIs(t, err, ErrNestedMcSession)
+
+	// Let the edge recover half way and fail it again in the session. Only
+	// the session should see the new failure.
+	ctx.now = testTime.Add(30 * time.Minute)
+	mcTestRoute.Hops[0].AmtToForward = 1000
+	errorSourceIdx := 1
+	_, err = session.ReportPaymentFail(
+		ctx.pid, mcTestRoute, &errorSourceIdx,
+		lnwire.NewTemporaryChannelFailure(nil),
+	)
+	require.NoError(t, err)
+
+	p = session.GetProbability(mcTestNode1, mcTestNode2, 1000, testCapacity)
+	require.Zero(t, p)
+	ctx.expectP(1000, 0.3)
+
+	// The session's results also aren't persisted.
+	ctx.restartMc()
+	ctx.expectP(1000, 0.3)
+
+	// Ending the session removes it, after which a fresh session can be
+	// created under the same name.
+	session, err = ctx.mc.Session("probe")
+	require.NoError(t, err)
+	require.NoError(t, ctx.mc.EndSession("probe"))
+	require.ErrorIs(t, ctx.mc.EndSession("probe"), ErrMcSessionNotFound)
+
+	_, err = ctx.mc.LookupSession("probe")
+	require.ErrorIs(t, err, ErrMcSessionNotFound)
+
+	newSession, err := ctx.mc.Session("probe")
+	require.NoError(t, err)
+	require.NotSame(t, session, newSession)
+
+	p = newSession.GetProbability(
+		mcTestNode1, mcTestNode2, 1000, testCapacity,
+	)
+	require.Equal(t, 0.3, p)
+}
+
+// TestMissionControlSessionEviction tests that idle mission control sessions
+// are evicted, and that the least recently used session makes room for a new
+// one once the maximum number of sessions is reached.
+func TestMissionControlSessionEviction(t *testing.T) {
+	ctx := createMcTestContext(t)
+
+	ctx.now = testTime
+
+	_, err := ctx.mc.Session("idle")
+	require.NoError(t, err)
+
+	ctx.now = testTime.Add(mcSessionIdleTimeout / 2)
+	_, err = ctx.mc.Session("busy")
+	require.NoError(t, err)
+
+	// Only the session that wasn't requested for the idle timeout is
+	// evicted.
+	ctx.now = testTime.Add(mcSessionIdleTimeout + time.Minute)
+	_, err = ctx.mc.LookupSession("idle")
+	require.ErrorIs(t, err, ErrMcSessionNotFound)
+
+	busy, err := ctx.mc.LookupSession("busy")
+	require.NoError(t, err)
+
+	for i := 1; i < maxMcSessions; i++ {
+		ctx.now = ctx.now.Add(time.Second)
+		_, err := ctx.mc.Session(fmt.Sprintf("session%d", i))
+		require.NoError(t, err)
+	}
+	require.Len(t, ctx.mc.sessions, maxMcSessions)
+
+	// A new session evicts the least recently used one.
+	_, err = ctx.mc.Session("new")
+	require.NoError(t, err)
+	require.Len(t, ctx.mc.sessions, maxMcSessions)
+
+	_, err = ctx.mc.LookupSession("busy")
+	require.ErrorIs(t, err, ErrMcSessionNotFound)
+
+	// The evicted session remains usable by the payments that hold it,
+	// while a new session is created under its name.
+	newBusy, err := ctx.mc.Session("busy")
+	require.NoError(t, err)
+	require.NotSame(t, busy, newBusy)
+}
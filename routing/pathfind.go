@@ -7,6 +7,7 @@ import (
 	"math"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	sphinx "github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/feature"
@@ -304,9 +305,10 @@ type graphParams struct {
 // found path must adhere to.
 type RestrictParams struct {
 	// ProbabilitySource is a callback that is expected to return the
-	// success probability of traversing the channel from the node.
+	// success probability of traversing the channel from the node. The
+	// capacity of the channel is zero if it isn't known.
 	ProbabilitySource func(route.Vertex, route.Vertex,
-		lnwire.MilliSatoshi) float64
+		lnwire.MilliSatoshi, btcutil.Amount) float64
 
 	// FeeLimit is a maximum fee amount allowed to be used on the path from
 	// the source to the target.
@@ -320,6 +322,23 @@ type RestrictParams struct {
 	// is reached. If nil, any node may be used.
 	LastHop *route.Vertex
 
+	// IgnoredNodes is the set of nodes that must not be used as
+	// intermediate hops of the route.
+	IgnoredNodes map[route.Vertex]struct{}
+
+	// AllowedNodes is the set of nodes that may be used as intermediate
+	// hops of the route. If nil, any node may be used.
+	AllowedNodes map[route.Vertex]struct{}
+
+	// IgnoredChannels is the set of channels that must not be used in the
+	// route.
+	IgnoredChannels map[uint64]struct{}
+
+	// AllowedChannels is the set of channels that may be used in the
+	// route, including the channels of route hints. If nil, any channel
+	// may be used.
+	AllowedChannels map[uint64]struct{}
+
 	// CltvLimit is the maximum time lock of the route excluding the final
 	// ctlv. After path finding is complete, the caller needs to increase
 	// all cltv expiry heights with the required final cltv delta.
@@ -344,6 +363,36 @@ type RestrictParams struct {
 	Metadata []byte
 }
 
+// nodeAllowed returns true if the given node may be used as an intermediate
+// hop of the route.
+func (r *RestrictParams) nodeAllowed(node route.Vertex) bool {
+	if _, ok := r.IgnoredNodes[node]; ok {
+		return false
+	}
+
+	if r.AllowedNodes != nil {
+		_, ok := r.AllowedNodes[node]
+		return ok
+	}
+
+	return true
+}
+
+// channelAllowed returns true if the channel with the given ID may be used in
+// the route.
+func (r *RestrictParams) channelAllowed(chanID uint64) bool {
+	if _, ok := r.IgnoredChannels[chanID]; ok {
+		return false
+	}
+
+	if r.AllowedChannels != nil {
+		_, ok := r.AllowedChannels[chanID]
+		return ok
+	}
+
+	return true
+}
+
 // PathFindingConfig defines global parameters that control the trade-off in
 // path finding between fees and probabiity.
 type PathFindingConfig struct {
@@ -361,6 +410,12 @@ type PathFindingConfig struct {
 	// MinProbability defines the minimum success probability of the
 	// returned route.
 	MinProbability float64
+
+	// EdgeScorers is an optional list of scorers that are consulted in
+	// addition to the default probability and fee scoring of the edges.
+	// Their probabilities are multiplied with the default probability and
+	// their penalties are added to the default weight of an edge.
+	EdgeScorers []EdgeScorer
 }
 
 // getOutgoingBalance returns the maximum available balance in any of the
@@ -616,11 +671,16 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 	log.Debugf("Pathfinding absolute attempt cost: %v sats",
 		absoluteAttemptCost/1000)
 
+	// Assemble the pipeline of scorers that determine the probability and
+	// weight of the edges.
+	scorer := newEdgeScorerPipeline(r, cfg)
+
 	// processEdge is a helper closure that will be used to make sure edges
 	// satisfy our specific requirements.
 	processEdge := func(fromVertex route.Vertex,
 		fromFeatures *lnwire.FeatureVector,
-		edge *channeldb.CachedEdgePolicy, toNodeDist *nodeWithDist) {
+		edge *channeldb.CachedEdgePolicy, capacity btcutil.Amount,
+		toNodeDist *nodeWithDist) {
 
 		edgesExpanded++
 
@@ -628,23 +688,6 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		// out.
 		amountToSend := toNodeDist.amountToReceive
 
-		// Request the success probability for this edge.
-		edgeProbability := r.ProbabilitySource(
-			fromVertex, toNodeDist.node, amountToSend,
-		)
-
-		log.Trace(newLogClosure(func() string {
-			return fmt.Sprintf("path finding probability: fromnode=%v,"+
-				" tonode=%v, amt=%v, probability=%v",
-				fromVertex, toNodeDist.node, amountToSend,
-				edgeProbability)
-		}))
-
-		// If the probability is zero, there is no point in trying.
-		if edgeProbability == 0 {
-			return
-		}
-
 		// Compute fee that fromVertex is charging. It is based on the
 		// amount that needs to be sent to the next node in the route.
 		//
@@ -662,6 +705,34 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 			timeLockDelta = edge.TimeLockDelta
 		}
 
+		// Score the edge with the scoring pipeline. By default, this
+		// yields the success probability that mission control
+		// estimates for the edge and a weight composed of the fee
+		// that fromVertex will charge and the amount that will be
+		// locked for timeLockDelta blocks in the HTLC that is handed
+		// out to fromVertex.
+		score := scorer.ScoreEdge(&ScoredEdge{
+			From:          fromVertex,
+			To:            toNodeDist.node,
+			Policy:        edge,
+			Capacity:      capacity,
+			Amount:        amountToSend,
+			Fee:           fee,
+			TimeLockDelta: timeLockDelta,
+		})
+
+		log.Trace(newLogClosure(func() string {
+			return fmt.Sprintf("path finding probability: fromnode=%v,"+
+				" tonode=%v, amt=%v, probability=%v, "+
+				"penalty=%v", fromVertex, toNodeDist.node,
+				amountToSend, score.Probability, score.Penalty)
+		}))
+
+		// If the probability is zero, there is no point in trying.
+		if score.Probability == 0 {
+			return
+		}
+
 		incomingCltv := toNodeDist.incomingCltv + int32(timeLockDelta)
 
 		// Check that we are within our CLTV limit.
@@ -686,7 +757,7 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		// Calculate total probability of successfully reaching target
 		// by multiplying the probabilities. Both this edge and the rest
 		// of the route must succeed.
-		probability := toNodeDist.probability * edgeProbability
+		probability := toNodeDist.probability * score.Probability
 
 		// If the probability is below the specified lower bound, we can
 		// abandon this direction. Adding further nodes can only lower
@@ -696,10 +767,8 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		}
 
 		// By adding fromVertex in the route, there will be an extra
-		// weight composed of the fee that this node will charge and
-		// the amount that will be locked for timeLockDelta blocks in
-		// the HTLC that is handed out to fromVertex.
-		weight := edgeWeight(amountToReceive, fee, timeLockDelta)
+		// weight that is the penalty the edge was scored with.
+		weight := int64(score.Penalty)
 
 		// Compute the tentative weight to this new channel/edge
 		// which is the weight from our toNode to the target node
@@ -843,6 +912,7 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 
 		// Create unified policies for all incoming connections.
 		u := newUnifiedPolicies(self, pivot, outgoingChanMap)
+		u.chanRestr = r.channelAllowed
 
 		err := u.addGraphPolicies(g.graph)
 		if err != nil {
@@ -874,6 +944,13 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 				continue
 			}
 
+			// Skip nodes that may not be used as intermediate
+			// hops. The source is the first hop of the route and
+			// can't be excluded.
+			if fromNode != source && !r.nodeAllowed(fromNode) {
+				continue
+			}
+
 			policy := unifiedPolicy.getPolicy(
 				amtToSend, g.bandwidthHints,
 			)
@@ -895,7 +972,11 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 
 			// Check if this candidate node is better than what we
 			// already have.
-			processEdge(fromNode, fromFeatures, policy, partialPath)
+			capacity := unifiedPolicy.capacity(amtToSend)
+			processEdge(
+				fromNode, fromFeatures, policy, capacity,
+				partialPath,
+			)
 		}
 
 		if nodeHeap.Len() == 0 {
@@ -105,7 +105,9 @@ var (
 
 // noProbabilitySource is used in testing to return the same probability 1 for
 // all edges.
-func noProbabilitySource(route.Vertex, route.Vertex, lnwire.MilliSatoshi) float64 {
+func noProbabilitySource(route.Vertex, route.Vertex, lnwire.MilliSatoshi,
+	btcutil.Amount) float64 {
+
 	return 1
 }
 
@@ -165,7 +167,7 @@ func makeTestGraph(t *testing.T, useCache bool) (*channeldb.ChannelGraph,
 	graph, err := channeldb.NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
 		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		useCache, false,
+		opts.GraphCacheMaxSize, useCache, false,
 	)
 	if err != nil {
 		return nil, nil, err
@@ -812,6 +814,12 @@ func TestPathFinding(t *testing.T) {
 	}, {
 		name: "restrict last hop",
 		fn:   runRestrictLastHop,
+	}, {
+		name: "restrict nodes and channels",
+		fn:   runRestrictNodesAndChannels,
+	}, {
+		name: "edge scorers",
+		fn:   runEdgeScorers,
 	}, {
 		name: "CLTV limit",
 		fn:   runCltvLimit,
@@ -2510,6 +2518,145 @@ func runRestrictLastHop(t *testing.T, useCache bool) {
 	}
 }
 
+// runRestrictNodesAndChannels asserts that path finding only uses the nodes and
+// channels that are allowed and not ignored.
+func runRestrictNodesAndChannels(t *testing.T, useCache bool) {
+	// Set up a test graph with three possible paths from roasbeef to
+	// target. The path via a is the lowest cost path, followed by the path
+	// via b and the path via c.
+	testChannels := []*testChannel{
+		symmetricTestChannel("source", "a", 100000, &testChannelPolicy{
+			Expiry: 144,
+		}, 1),
+		symmetricTestChannel("a", "target", 100000, &testChannelPolicy{
+			Expiry:  144,
+			FeeRate: 400,
+		}, 2),
+		symmetricTestChannel("source", "b", 100000, &testChannelPolicy{
+			Expiry: 144,
+		}, 3),
+		symmetricTestChannel("b", "target", 100000, &testChannelPolicy{
+			Expiry:  144,
+			FeeRate: 800,
+		}, 4),
+		symmetricTestChannel("source", "c", 100000, &testChannelPolicy{
+			Expiry: 144,
+		}, 5),
+		symmetricTestChannel("c", "target", 100000, &testChannelPolicy{
+			Expiry:  144,
+			FeeRate: 1200,
+		}, 6),
+	}
+
+	ctx := newPathFindingTestContext(t, useCache, testChannels, "source")
+
+	paymentAmt := lnwire.NewMSatFromSatoshis(100)
+	target := ctx.keyFromAlias("target")
+
+	assertFirstChannel := func(expectedChannel uint64) {
+		path, err := ctx.findPath(target, paymentAmt)
+		require.NoError(t, err, "unable to find path")
+		require.Equal(t, expectedChannel, path[0].ChannelID)
+	}
+
+	// Ignoring a should force the path via b.
+	ctx.restrictParams.IgnoredNodes = map[route.Vertex]struct{}{
+		ctx.keyFromAlias("a"): {},
+	}
+	assertFirstChannel(3)
+
+	// Ignoring the channel from b to the target as well leaves the path
+	// via c.
+	ctx.restrictParams.IgnoredChannels = map[uint64]struct{}{4: {}}
+	assertFirstChannel(5)
+
+	// Only allowing c as an intermediate hop should also select the path
+	// via c.
+	ctx.restrictParams.IgnoredNodes = nil
+	ctx.restrictParams.IgnoredChannels = nil
+	ctx.restrictParams.AllowedNodes = map[route.Vertex]struct{}{
+		ctx.keyFromAlias("c"): {},
+	}
+	assertFirstChannel(5)
+
+	// Only allowing the channels via b should select the path via b.
+	ctx.restrictParams.AllowedNodes = nil
+	ctx.restrictParams.AllowedChannels = map[uint64]struct{}{
+		3: {}, 4: {},
+	}
+	assertFirstChannel(3)
+
+	// If not all channels of a path are allowed, no path should be found.
+	ctx.restrictParams.AllowedChannels = map[uint64]struct{}{3: {}}
+	_, err := ctx.findPath(target, paymentAmt)
+	require.ErrorIs(t, err, errNoPathFound)
+}
+
+// runEdgeScorers asserts that additional edge scorers bias the path that is
+// selected.
+func runEdgeScorers(t *testing.T, useCache bool) {
+	// Set up a test graph with two possible paths from source to target.
+	// Without additional scorers, the path via a is selected because it
+	// is cheaper than the path via b.
+	testChannels := []*testChannel{
+		symmetricTestChannel("source", "a", 100000, &testChannelPolicy{
+			Expiry: 144,
+		}, 1),
+		symmetricTestChannel("a", "target", 100000, &testChannelPolicy{
+			Expiry:  144,
+			FeeRate: 400,
+		}, 2),
+		symmetricTestChannel("source", "b", 100000, &testChannelPolicy{
+			Expiry: 144,
+		}, 3),
+		symmetricTestChannel("b", "target", 100000, &testChannelPolicy{
+			Expiry:  144,
+			FeeRate: 800,
+		}, 4),
+	}
+
+	ctx := newPathFindingTestContext(t, useCache, testChannels, "source")
+
+	paymentAmt := lnwire.NewMSatFromSatoshis(100)
+	target := ctx.keyFromAlias("target")
+	nodeA := ctx.keyFromAlias("a")
+
+	assertFirstChannel := func(expectedChannel uint64) {
+		path, err := ctx.findPath(target, paymentAmt)
+		require.NoError(t, err, "unable to find path")
+		require.Equal(t, expectedChannel, path[0].ChannelID)
+	}
+
+	assertFirstChannel(1)
+
+	// A penalty on the edges that are forwarded by a that outweighs the
+	// fee difference should select the path via b.
+	ctx.pathFindingConfig.EdgeScorers = []EdgeScorer{
+		EdgeScorerFunc(func(edge *ScoredEdge) EdgeScore {
+			score := EdgeScore{Probability: 1}
+			if edge.From == nodeA {
+				score.Penalty = 1000
+			}
+
+			return score
+		}),
+	}
+	assertFirstChannel(3)
+
+	// A scorer that assigns a probability of zero to the edges into a
+	// should select the path via b as well, even if a is cheaper.
+	ctx.pathFindingConfig.EdgeScorers = []EdgeScorer{
+		EdgeScorerFunc(func(edge *ScoredEdge) EdgeScore {
+			if edge.To == nodeA {
+				return EdgeScore{}
+			}
+
+			return EdgeScore{Probability: 1}
+		}),
+	}
+	assertFirstChannel(3)
+}
+
 // runCltvLimit asserts that a cltv limit is obeyed by the path finding
 // algorithm.
 func runCltvLimit(t *testing.T, useCache bool) {
@@ -2745,7 +2892,7 @@ func testProbabilityRouting(t *testing.T, useCache bool,
 
 	// Configure a probability source with the test parameters.
 	ctx.restrictParams.ProbabilitySource = func(fromNode, toNode route.Vertex,
-		amt lnwire.MilliSatoshi) float64 {
+		amt lnwire.MilliSatoshi, _ btcutil.Amount) float64 {
 
 		if amt == 0 {
 			t.Fatal("expected non-zero amount")
@@ -2827,7 +2974,7 @@ func runEqualCostRouteSelection(t *testing.T, useCache bool) {
 	target := ctx.testGraphInstance.aliasMap["target"]
 
 	ctx.restrictParams.ProbabilitySource = func(fromNode, toNode route.Vertex,
-		amt lnwire.MilliSatoshi) float64 {
+		amt lnwire.MilliSatoshi, _ btcutil.Amount) float64 {
 
 		switch {
 		case fromNode == alias["source"] && toNode == alias["a"]:
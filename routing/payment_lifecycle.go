@@ -22,14 +22,20 @@ var errShardHandlerExiting = fmt.Errorf("shard handler exiting")
 // paymentLifecycle holds all information about the current state of a payment
 // needed to resume if from any point.
 type paymentLifecycle struct {
-	router        *ChannelRouter
-	totalAmount   lnwire.MilliSatoshi
-	feeLimit      lnwire.MilliSatoshi
-	identifier    lntypes.Hash
-	paySession    PaymentSession
-	shardTracker  shards.ShardTracker
-	timeoutChan   <-chan time.Time
-	currentHeight int32
+	router         *ChannelRouter
+	totalAmount    lnwire.MilliSatoshi
+	feeLimit       lnwire.MilliSatoshi
+	identifier     lntypes.Hash
+	paySession     PaymentSession
+	shardTracker   shards.ShardTracker
+	timeoutChan    <-chan time.Time
+	currentHeight  int32
+	missionControl MissionController
+
+	// maxParallelism is the maximum number of shards that may be in
+	// flight at the same time. A value of zero means that only the
+	// payment session limits the number of shards.
+	maxParallelism uint32
 }
 
 // payemntState holds a number of key insights learned from a given MPPayment
@@ -120,12 +126,14 @@ func (p *paymentLifecycle) fetchPaymentState() (*channeldb.MPPayment,
 // resumePayment resumes the paymentLifecycle from the current state.
 func (p *paymentLifecycle) resumePayment() ([32]byte, *route.Route, error) {
 	shardHandler := &shardHandler{
-		router:       p.router,
-		identifier:   p.identifier,
-		shardTracker: p.shardTracker,
-		shardErrors:  make(chan error),
-		quit:         make(chan struct{}),
-		paySession:   p.paySession,
+		router:         p.router,
+		identifier:     p.identifier,
+		shardTracker:   p.shardTracker,
+		shardErrors:    make(chan error),
+		quit:           make(chan struct{}),
+		paySession:     p.paySession,
+		missionControl: p.missionControl,
+		limiter:        newShardLimiter(p.maxParallelism),
 	}
 
 	// When the payment lifecycle loop exits, we make sure to signal any
@@ -150,6 +158,10 @@ func (p *paymentLifecycle) resumePayment() ([32]byte, *route.Route, error) {
 		shardHandler.collectResultAsync(&a.HTLCAttemptInfo)
 	}
 
+	// routesExhausted is set if the payment session found fewer routes
+	// than shards could be launched the last time it was asked.
+	var routesExhausted bool
+
 	// We'll continue until either our payment succeeds, or we encounter a
 	// critical error during path finding.
 lifecycle:
@@ -249,12 +261,41 @@ lifecycle:
 		default:
 		}
 
-		// Create a new payment attempt from the given payment session.
-		rt, err := p.paySession.RequestRoute(
-			currentState.remainingAmt, currentState.remainingFees,
-			uint32(currentState.numShardsInFlight),
-			uint32(p.currentHeight),
+		// If we already have as many shards in flight as the limiter
+		// currently allows, we'll wait for one of them to return
+		// before launching another one.
+		numLaunchable := shardHandler.limiter.numLaunchable(
+			currentState.numShardsInFlight,
 		)
+		if numLaunchable == 0 {
+			log.Debugf("Payment %v has %v shards in flight, "+
+				"waiting for a shard to return (limit=%v)",
+				p.identifier, currentState.numShardsInFlight,
+				shardHandler.limiter.currentLimit())
+
+			if err := shardHandler.waitForShard(); err != nil {
+				return [32]byte{}, nil, err
+			}
+			continue lifecycle
+		}
+
+		// If the payment session couldn't find a route for every shard
+		// that could be launched the last time, it won't find one now
+		// either. We wait for a shard in flight to return first, as
+		// its outcome is what changes the routes found.
+		if routesExhausted && currentState.numShardsInFlight > 0 {
+			routesExhausted = false
+
+			if err := shardHandler.waitForShard(); err != nil {
+				return [32]byte{}, nil, err
+			}
+			continue lifecycle
+		}
+		routesExhausted = false
+
+		// Request routes for as many new shards as the limiter
+		// currently allows from the payment session.
+		routes, err := p.requestRoutes(currentState, numLaunchable)
 		if err != nil {
 			log.Warnf("Failed to find route for payment %v: %v",
 				p.identifier, err)
@@ -291,54 +332,122 @@ lifecycle:
 			continue lifecycle
 		}
 
-		// If this route will consume the last remaining amount to send
-		// to the receiver, this will be our last shard (for now).
-		lastShard := rt.ReceiverAmt() == currentState.remainingAmt
+		// If these routes will consume the last remaining amount to
+		// send to the receiver, the last of them will be our last
+		// shard (for now).
+		var routesAmt lnwire.MilliSatoshi
+		for _, rt := range routes {
+			routesAmt += rt.ReceiverAmt()
+		}
+		lastShard := routesAmt == currentState.remainingAmt
+		routesExhausted = uint32(len(routes)) < numLaunchable &&
+			!lastShard
+
+		// We found routes to try, launch the new shards in parallel.
+		launches := shardHandler.launchShards(routes, lastShard)
+
+		// Handle the outcome of every launch, even if one of them
+		// returned a critical error, so the results of the shards
+		// that were sent are still collected.
+		var criticalErr error
+		for _, launch := range launches {
+			switch {
+			// We may get a terminal error if we've processed a
+			// shard with a terminal state (settled or permanent
+			// failure), while we were pathfinding. We know we're
+			// in a terminal state here, so we can continue and
+			// wait for our last shards to return.
+			case launch.err == channeldb.ErrPaymentTerminal:
+				log.Infof("Payment %v in terminal state, "+
+					"abandoning shard", p.identifier)
+
+				continue
+
+			case launch.err != nil:
+				if criticalErr == nil {
+					criticalErr = launch.err
+				}
 
-		// We found a route to try, launch a new shard.
-		attempt, outcome, err := shardHandler.launchShard(rt, lastShard)
-		switch {
-		// We may get a terminal error if we've processed a shard with
-		// a terminal state (settled or permanent failure), while we
-		// were pathfinding. We know we're in a terminal state here,
-		// so we can continue and wait for our last shards to return.
-		case err == channeldb.ErrPaymentTerminal:
-			log.Infof("Payment %v in terminal state, abandoning "+
-				"shard", p.identifier)
+				continue
+			}
 
-			continue lifecycle
+			// If we encountered a non-critical error when
+			// launching the shard, handle it.
+			if launch.outcome.err != nil {
+				log.Warnf("Failed to launch shard %v for "+
+					"payment %v: %v",
+					launch.attempt.AttemptID, p.identifier,
+					launch.outcome.err)
+
+				shardHandler.limiter.shardFailed()
+
+				// We must inspect the error to know whether it
+				// was critical or not, to decide whether we
+				// should continue trying.
+				err := shardHandler.handleSendError(
+					launch.attempt, launch.outcome.err,
+				)
+				if err != nil && criticalErr == nil {
+					criticalErr = err
+				}
 
-		case err != nil:
-			return [32]byte{}, nil, err
+				continue
+			}
+
+			// Now that the shard was successfully sent, launch a
+			// go routine that will handle its result when its
+			// back.
+			shardHandler.collectResultAsync(launch.attempt)
 		}
 
-		// If we encountered a non-critical error when launching the
-		// shard, handle it.
-		if outcome.err != nil {
-			log.Warnf("Failed to launch shard %v for "+
-				"payment %v: %v", attempt.AttemptID,
-				p.identifier, outcome.err)
-
-			// We must inspect the error to know whether it was
-			// critical or not, to decide whether we should
-			// continue trying.
-			err := shardHandler.handleSendError(
-				attempt, outcome.err,
-			)
-			if err != nil {
-				return [32]byte{}, nil, err
+		if criticalErr != nil {
+			return [32]byte{}, nil, criticalErr
+		}
+	}
+}
+
+// requestRoutes requests routes for up to numShards new shards from the
+// payment session. Each route is requested for the amount and fees that
+// remain after the routes before it, so that together they don't exceed the
+// payment. Once no further route can be found, the routes found so far are
+// returned, and the error is only returned if there are none.
+func (p *paymentLifecycle) requestRoutes(state *paymentState,
+	numShards uint32) ([]*route.Route, error) {
+
+	var (
+		routes        []*route.Route
+		remainingAmt  = state.remainingAmt
+		remainingFees = state.remainingFees
+	)
+	for uint32(len(routes)) < numShards && remainingAmt > 0 {
+		rt, err := p.paySession.RequestRoute(
+			remainingAmt, remainingFees,
+			uint32(state.numShardsInFlight+len(routes)),
+			uint32(p.currentHeight),
+		)
+		if err != nil {
+			if len(routes) == 0 {
+				return nil, err
 			}
 
-			// Error was handled successfully, continue to make a
-			// new attempt.
-			continue lifecycle
+			log.Debugf("Launching %v shards for payment %v, "+
+				"unable to find route for another one: %v",
+				len(routes), p.identifier, err)
+
+			break
 		}
 
-		// Now that the shard was successfully sent, launch a go
-		// routine that will handle its result when its back.
-		shardHandler.collectResultAsync(attempt)
+		routes = append(routes, rt)
 
+		remainingAmt -= rt.ReceiverAmt()
+		if rt.TotalFees() >= remainingFees {
+			remainingFees = 0
+		} else {
+			remainingFees -= rt.TotalFees()
+		}
 	}
+
+	return routes, nil
 }
 
 // shardHandler holds what is necessary to send and collect the result of
@@ -349,6 +458,20 @@ type shardHandler struct {
 	shardTracker shards.ShardTracker
 	paySession   PaymentSession
 
+	// missionControl is the mission control instance that the results of
+	// the shards are reported to.
+	missionControl MissionController
+
+	// customOnion holds optional, caller provided sphinx parameters that
+	// are used instead of generating the onion of an attempt ourselves.
+	// It is only set for SendToRoute payments.
+	customOnion *CustomOnion
+
+	// limiter is an optional limiter of the number of shards in flight
+	// that is notified of failed and succeeded shards. It is only set for
+	// payments driven by the payment lifecycle.
+	limiter *shardLimiter
+
 	// shardErrors is a channel where errors collected by calling
 	// collectResultAsync will be delivered. These results are meant to be
 	// inspected by calling waitForShard or checkShards, and the channel
@@ -437,14 +560,84 @@ func (p *shardHandler) launchShard(rt *route.Route,
 		return nil, nil, err
 	}
 
+	outcome, err := p.registerAndSendAttempt(attempt, firstHop, htlcAdd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return attempt, outcome, nil
+}
+
+// shardLaunch holds the result of launching a single shard.
+type shardLaunch struct {
+	// attempt is the attempt that was created for the shard.
+	attempt *channeldb.HTLCAttemptInfo
+
+	// outcome is the outcome of sending the attempt.
+	outcome *launchOutcome
+
+	// err is a critical error that was encountered while launching the
+	// shard.
+	err error
+}
+
+// launchShards launches a shard for each of the given routes. The attempts are
+// created in order, as the shard tracker of an AMP payment derives the last
+// shard from the ones before it. They are then registered and sent in
+// parallel. The launches are returned in the order of the routes. If lastShard
+// is true, the last route will be the last shard of the payment. Once an
+// attempt can't be created, no further shards are launched.
+func (p *shardHandler) launchShards(routes []*route.Route,
+	lastShard bool) []*shardLaunch {
+
+	launches := make([]*shardLaunch, 0, len(routes))
+
+	var wg sync.WaitGroup
+	for i, rt := range routes {
+		last := lastShard && i == len(routes)-1
+		firstHop, htlcAdd, attempt, err := p.createNewPaymentAttempt(
+			rt, last,
+		)
+		if err != nil {
+			launches = append(launches, &shardLaunch{err: err})
+			break
+		}
+
+		launch := &shardLaunch{
+			attempt: attempt,
+		}
+		launches = append(launches, launch)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			launch.outcome, launch.err = p.registerAndSendAttempt(
+				attempt, firstHop, htlcAdd,
+			)
+		}()
+	}
+	wg.Wait()
+
+	return launches
+}
+
+// registerAndSendAttempt registers the given attempt with the control tower
+// and sends it to the switch. A critical error is returned if the attempt
+// can't be registered or failed, otherwise the returned launchOutcome
+// indicates whether the attempt was sent.
+func (p *shardHandler) registerAndSendAttempt(
+	attempt *channeldb.HTLCAttemptInfo, firstHop lnwire.ShortChannelID,
+	htlcAdd *lnwire.UpdateAddHTLC) (*launchOutcome, error) {
+
 	// Before sending this HTLC to the switch, we checkpoint the fresh
 	// paymentID and route to the DB. This lets us know on startup the ID
 	// of the payment that we attempted to send, such that we can query the
 	// Switch for its whereabouts. The route is needed to handle the result
 	// when it eventually comes back.
-	err = p.router.cfg.Control.RegisterAttempt(p.identifier, attempt)
+	err := p.router.cfg.Control.RegisterAttempt(p.identifier, attempt)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// Now that the attempt is created and checkpointed to the DB, we send
@@ -455,17 +648,17 @@ func (p *shardHandler) launchShard(rt *route.Route,
 		// from real send errors.
 		htlcAttempt, err := p.failAttempt(attempt, sendErr)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
 		// Return a launchOutcome indicating the shard failed.
-		return attempt, &launchOutcome{
+		return &launchOutcome{
 			attempt: htlcAttempt,
 			err:     sendErr,
 		}, nil
 	}
 
-	return attempt, &launchOutcome{}, nil
+	return &launchOutcome{}, nil
 }
 
 // shardResult holds the resulting outcome of a shard sent.
@@ -523,12 +716,20 @@ func (p *shardHandler) collectResultAsync(attempt *channeldb.HTLCAttemptInfo) {
 		// If a non-critical error was encountered handle it and mark
 		// the payment failed if the failure was terminal.
 		if result.err != nil {
+			if p.limiter != nil {
+				p.limiter.shardFailed()
+			}
+
 			// Overwrite the param errToSend and return so that the
 			// defer function will use the param to proceed. Notice
 			// that the errToSend could be nil here.
 			errToSend = p.handleSendError(attempt, result.err)
 			return
 		}
+
+		if p.limiter != nil {
+			p.limiter.shardSucceeded()
+		}
 	}()
 }
 
@@ -630,7 +831,7 @@ func (p *shardHandler) collectResult(attempt *channeldb.HTLCAttemptInfo) (
 		p.identifier, attempt.AttemptID)
 
 	// Report success to mission control.
-	err = p.router.cfg.MissionControl.ReportPaymentSuccess(
+	err = p.missionControl.ReportPaymentSuccess(
 		attempt.AttemptID, &attempt.Route,
 	)
 	if err != nil {
@@ -662,10 +863,19 @@ func (p *shardHandler) createNewPaymentAttempt(rt *route.Route, lastShard bool)
 	lnwire.ShortChannelID, *lnwire.UpdateAddHTLC,
 	*channeldb.HTLCAttemptInfo, error) {
 
-	// Generate a new key to be used for this attempt.
-	sessionKey, err := generateNewSessionKey()
-	if err != nil {
-		return lnwire.ShortChannelID{}, nil, nil, err
+	// Generate a new key to be used for this attempt, unless the caller
+	// provided one.
+	var (
+		sessionKey *btcec.PrivateKey
+		err        error
+	)
+	if p.customOnion != nil && p.customOnion.SessionKey != nil {
+		sessionKey = p.customOnion.SessionKey
+	} else {
+		sessionKey, err = generateNewSessionKey()
+		if err != nil {
+			return lnwire.ShortChannelID{}, nil, nil, err
+		}
 	}
 
 	// We generate a new, unique payment ID that we will use for
@@ -698,11 +908,18 @@ func (p *shardHandler) createNewPaymentAttempt(rt *route.Route, lastShard bool)
 
 	// Generate the raw encoded sphinx packet to be included along
 	// with the htlcAdd message that we send directly to the
-	// switch.
+	// switch. A pre-built onion provided by the caller is sent as is.
 	hash := shard.Hash()
-	onionBlob, _, err := generateSphinxPacket(rt, hash[:], sessionKey)
-	if err != nil {
-		return lnwire.ShortChannelID{}, nil, nil, err
+	var onionBlob []byte
+	if p.customOnion != nil && p.customOnion.OnionBlob != nil {
+		onionBlob = p.customOnion.OnionBlob
+	} else {
+		onionBlob, _, err = generateSphinxPacket(
+			rt, hash[:], sessionKey,
+		)
+		if err != nil {
+			return lnwire.ShortChannelID{}, nil, nil, err
+		}
 	}
 
 	// Craft an HTLC packet to send to the layer 2 switch. The
@@ -803,7 +1020,7 @@ func (p *shardHandler) handleSendError(attempt *channeldb.HTLCAttemptInfo,
 	// control, it will further fail the payment via control tower.
 	reportFail := func(srcIdx *int, msg lnwire.FailureMessage) error {
 		// Report outcome to mission control.
-		reason, err := p.router.cfg.MissionControl.ReportPaymentFail(
+		reason, err := p.missionControl.ReportPaymentFail(
 			attempt.AttemptID, &attempt.Route, srcIdx, msg,
 		)
 		if err != nil {
@@ -65,6 +65,10 @@ type paymentLifecycleTestCase struct {
 	// should be nil for tests with paymentSuccess steps and non-nil for
 	// payments with paymentError steps.
 	paymentErr error
+
+	// maxParallelism is the maximum number of shards of the payment that
+	// may be in flight at the same time.
+	maxParallelism uint32
 }
 
 const (
@@ -94,6 +98,10 @@ const (
 	// allow it to respond to our test with a route.
 	routeRelease = "PaymentSession:release"
 
+	// routeNotRequested is a test step where we assert that the
+	// router doesn't request another route for now.
+	routeNotRequested = "PaymentSession:not-requested"
+
 	// sendToSwitchSuccess is a step where we expect the router to
 	// call send the payment attempt to the switch, and we will
 	// respond with a non-error, indicating that the payment
@@ -425,6 +433,134 @@ func TestRouterPaymentStateMachine(t *testing.T) {
 	}
 }
 
+// TestRouterPaymentParallelShards tests that the shards of a payment are
+// launched in parallel up to the limit of shards in flight, and that the
+// router waits for a shard to return once the limit is reached.
+func TestRouterPaymentParallelShards(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+
+	chanCapSat := btcutil.Amount(100000)
+	testChannels := []*testChannel{
+		symmetricTestChannel("a", "b", chanCapSat, &testChannelPolicy{
+			Expiry:  144,
+			FeeRate: 400,
+			MinHTLC: 1,
+			MaxHTLC: lnwire.NewMSatFromSatoshis(chanCapSat),
+		}, 1),
+		symmetricTestChannel("b", "c", chanCapSat, &testChannelPolicy{
+			Expiry:  144,
+			FeeRate: 400,
+			MinHTLC: 1,
+			MaxHTLC: lnwire.NewMSatFromSatoshis(chanCapSat),
+		}, 2),
+	}
+
+	testGraph, err := createTestGraphFromChannels(t, true, testChannels, "a")
+	require.NoError(t, err, "unable to create graph")
+
+	paymentAmt := lnwire.NewMSatFromSatoshis(1000)
+
+	// Each shard pays half of the payment amount.
+	rt, err := createTestRoute(paymentAmt/2, testGraph.aliasMap)
+	require.NoError(t, err, "unable to create route")
+
+	tests := []paymentLifecycleTestCase{
+		{
+			// Both shards are launched before any of them
+			// returns.
+			name: "parallel launch",
+
+			steps: []string{
+				routerInitPayment,
+
+				// Both routes are requested first, then both
+				// shards are registered and sent.
+				routeRelease,
+				routeRelease,
+				routerRegisterAttempt,
+				routerRegisterAttempt,
+				sendToSwitchSuccess,
+				sendToSwitchSuccess,
+
+				getPaymentResultSuccess,
+				routerSettleAttempt,
+				getPaymentResultSuccess,
+				routerSettleAttempt,
+				paymentSuccess,
+			},
+			routes:         []*route.Route{rt, rt},
+			maxParallelism: 2,
+		},
+		{
+			// With a single shard allowed in flight, the second
+			// shard is only launched once the first one failed.
+			name: "sequential launch",
+
+			steps: []string{
+				routerInitPayment,
+				routeRelease,
+				routerRegisterAttempt,
+				sendToSwitchSuccess,
+
+				// The limit is reached, so no further route
+				// is requested until the shard returns.
+				routeNotRequested,
+
+				getPaymentResultTempFailure,
+				routerFailAttempt,
+
+				routeRelease,
+				routerRegisterAttempt,
+				sendToSwitchSuccess,
+				getPaymentResultSuccess,
+				routerSettleAttempt,
+				paymentSuccess,
+			},
+			routes:         []*route.Route{rt, rt},
+			maxParallelism: 1,
+		},
+		{
+			// Only as many shards as routes are found are
+			// launched, the payment fails once no more routes are
+			// found.
+			name: "partial launch",
+
+			steps: []string{
+				routerInitPayment,
+
+				// Only one route is found for the two shards
+				// that may be launched.
+				routeRelease,
+				routeRelease,
+				routerRegisterAttempt,
+				sendToSwitchSuccess,
+
+				getPaymentResultTempFailure,
+				routerFailAttempt,
+
+				routeRelease,
+				routerFailPayment,
+				paymentError,
+			},
+			routes:         []*route.Route{rt},
+			paymentErr:     channeldb.FailureReasonNoRoute,
+			maxParallelism: 2,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			testPaymentLifecycle(
+				t, test, paymentAmt, startingBlockHeight,
+				testGraph,
+			)
+		})
+	}
+}
+
 func testPaymentLifecycle(t *testing.T, test paymentLifecycleTestCase,
 	paymentAmt lnwire.MilliSatoshi, startingBlockHeight uint32,
 	testGraph *testGraphInstance) {
@@ -526,10 +662,11 @@ func testPaymentLifecycle(t *testing.T, test paymentLifecycleTestCase,
 	payHash := preImage.Hash()
 
 	payment := LightningPayment{
-		Target:      testGraph.aliasMap["c"],
-		Amount:      paymentAmt,
-		FeeLimit:    noFeeLimit,
-		paymentHash: &payHash,
+		Target:         testGraph.aliasMap["c"],
+		Amount:         paymentAmt,
+		FeeLimit:       noFeeLimit,
+		MaxParallelism: test.maxParallelism,
+		paymentHash:    &payHash,
 	}
 
 	// Setup our payment session source to block on release of
@@ -587,6 +724,13 @@ func testPaymentLifecycle(t *testing.T, test paymentLifecycleTestCase,
 				fatal("no route requested")
 			}
 
+		case routeNotRequested:
+			select {
+			case <-routeChan:
+				fatal("route requested")
+			case <-time.After(100 * time.Millisecond):
+			}
+
 		// In this step we expect the router to make a call to
 		// register a new attempt with the ControlTower.
 		case routerRegisterAttempt:
@@ -186,6 +186,10 @@ type paymentSession struct {
 	// will happen and this value remains unused.
 	minShardAmt lnwire.MilliSatoshi
 
+	// equalParts is the number of equal parts the payment is currently
+	// split into. It is only used with the SplitEqual strategy.
+	equalParts uint32
+
 	// log is a payment session-specific logger.
 	log btclog.Logger
 }
@@ -202,8 +206,23 @@ func newPaymentSession(p *LightningPayment,
 		return nil, err
 	}
 
+	// If the payment is made to a blinded path, the path is added to the
+	// graph as edges from the introduction node to the path's target.
+	if p.BlindedPayment != nil {
+		if err := validateBlindedPayment(p); err != nil {
+			return nil, err
+		}
+
+		p.BlindedPayment.addEdges(edges)
+	}
+
 	logPrefix := fmt.Sprintf("PaymentSession(%x):", p.Identifier())
 
+	minShardAmt := DefaultShardMinAmt
+	if p.MinShardAmt != nil {
+		minShardAmt = *p.MinShardAmt
+	}
+
 	return &paymentSession{
 		additionalEdges:   edges,
 		getBandwidthHints: getBandwidthHints,
@@ -212,7 +231,8 @@ func newPaymentSession(p *LightningPayment,
 		getRoutingGraph:   getRoutingGraph,
 		pathFindingConfig: pathFindingConfig,
 		missionControl:    missionControl,
-		minShardAmt:       DefaultShardMinAmt,
+		minShardAmt:       minShardAmt,
+		equalParts:        1,
 		log:               build.NewPrefixLog(logPrefix, log),
 	}, nil
 }
@@ -253,6 +273,10 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 		FeeLimit:           feeLimit,
 		OutgoingChannelIDs: p.payment.OutgoingChannelIDs,
 		LastHop:            p.payment.LastHop,
+		IgnoredNodes:       p.payment.IgnoredNodes,
+		AllowedNodes:       p.payment.AllowedNodes,
+		IgnoredChannels:    p.payment.IgnoredChannels,
+		AllowedChannels:    p.payment.AllowedChannels,
 		CltvLimit:          cltvLimit,
 		DestCustomRecords:  p.payment.DestCustomRecords,
 		DestFeatures:       p.payment.DestFeatures,
@@ -274,6 +298,15 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 		maxAmt = *p.payment.MaxShardAmt
 	}
 
+	// With the equal split strategy, the shards are sized to split the
+	// full payment amount into the current number of parts.
+	if p.payment.SplitStrategy == SplitEqual {
+		shardAmt := equalShardAmt(p.payment.Amount, p.equalParts)
+		if maxAmt > shardAmt {
+			maxAmt = shardAmt
+		}
+	}
+
 	for {
 		// Get a routing graph.
 		routingGraph, cleanup, err := p.getRoutingGraph()
@@ -292,10 +325,32 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 			return nil, err
 		}
 
-		p.log.Debugf("pathfinding for amt=%v", maxAmt)
-
 		sourceVertex := routingGraph.sourceNode()
 
+		// With the largest channel split strategy, we don't attempt
+		// shards that are larger than what any of our channels can
+		// carry. Shards are never capped below the minimum shard
+		// amount though.
+		if p.payment.SplitStrategy == SplitLargestChannel {
+			largest, err := p.largestOutgoingBalance(
+				routingGraph, bandwidthHints, sourceVertex,
+			)
+			if err != nil {
+				cleanup()
+				return nil, err
+			}
+
+			if maxAmt > largest && largest >= p.minShardAmt {
+				p.log.Debugf("Clamping payment attempt from %v "+
+					"to largest channel balance %v",
+					maxAmt, largest)
+
+				maxAmt = largest
+			}
+		}
+
+		p.log.Debugf("pathfinding for amt=%v", maxAmt)
+
 		// Find a route for the current amount.
 		path, err := p.pathFinder(
 			&graphParams{
@@ -314,8 +369,11 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 		switch {
 		case err == errNoPathFound:
 			// Don't split if this is a legacy payment without mpp
-			// record.
-			if p.payment.PaymentAddr == nil {
+			// record. Payments to blinded paths identify their
+			// shards with the total amount of the final hop.
+			if p.payment.PaymentAddr == nil &&
+				p.payment.BlindedPayment == nil {
+
 				p.log.Debugf("not splitting because payment " +
 					"address is unspecified")
 
@@ -349,8 +407,8 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 			}
 
 			// This is where the magic happens. If we can't find a
-			// route, try it for half the amount.
-			maxAmt /= 2
+			// route, try it for a smaller amount.
+			maxAmt = p.splitAmt(maxAmt)
 
 			// Put a lower bound on the minimum shard size.
 			if maxAmt < p.minShardAmt {
@@ -396,10 +454,66 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 			return nil, err
 		}
 
+		// Add the encrypted data of the blinded path to its hops.
+		if p.payment.BlindedPayment != nil {
+			err := p.payment.BlindedPayment.addBlindedData(
+				route, p.payment.Amount,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		return route, err
 	}
 }
 
+// splitAmt returns the amount to try next after no route was found for the
+// given amount, according to the split strategy of the payment.
+func (p *paymentSession) splitAmt(
+	failedAmt lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+	if p.payment.SplitStrategy != SplitEqual {
+		return failedAmt / 2
+	}
+
+	// Split the payment into more equal parts. If that exceeds the
+	// maximum number of parts, the payment can't be completed with shards
+	// of this size, which is signaled by returning a zero amount.
+	p.equalParts = nextEqualParts(
+		p.payment.Amount, failedAmt, p.equalParts,
+	)
+	if p.equalParts > p.payment.MaxParts {
+		p.log.Debugf("not splitting because %v equal parts exceed "+
+			"the shard limit %v", p.equalParts, p.payment.MaxParts)
+
+		return 0
+	}
+
+	return equalShardAmt(p.payment.Amount, p.equalParts)
+}
+
+// largestOutgoingBalance returns the largest balance that is available in any
+// of our channels that the payment may use.
+func (p *paymentSession) largestOutgoingBalance(g routingGraph,
+	bandwidthHints bandwidthHints,
+	self route.Vertex) (lnwire.MilliSatoshi, error) {
+
+	var outgoingChans map[uint64]struct{}
+	if len(p.payment.OutgoingChannelIDs) > 0 {
+		outgoingChans = make(map[uint64]struct{})
+		for _, chanID := range p.payment.OutgoingChannelIDs {
+			outgoingChans[chanID] = struct{}{}
+		}
+	}
+
+	largest, _, err := getOutgoingBalance(
+		self, outgoingChans, bandwidthHints, g,
+	)
+
+	return largest, err
+}
+
 // UpdateAdditionalEdge updates the channel edge policy for a private edge. It
 // validates the message signature and checks it's up to date, then applies the
 // updates to the supplied policy. It returns a boolean to indicate whether
@@ -41,6 +41,14 @@ type SessionSource struct {
 	// PathFindingConfig defines global parameters that control the
 	// trade-off in path finding between fees and probabiity.
 	PathFindingConfig PathFindingConfig
+
+	// LearnedEdges returns the edges of our private channels whose
+	// policies were learned from our LSPs, indexed by the LSP the edges
+	// start at. They're added to the additional edges of every payment
+	// session, and are used by the routes that reach our own node through
+	// these channels, such as circular rebalances. If nil, no learned
+	// edges are used.
+	LearnedEdges func() map[route.Vertex][]*channeldb.CachedEdgePolicy
 }
 
 // getRoutingGraph returns a routing graph and a clean-up function for
@@ -71,17 +79,60 @@ func (m *SessionSource) NewPaymentSession(p *LightningPayment) (
 		)
 	}
 
+	// Use the payment's own mission control for pathfinding if it runs
+	// in an isolated session.
+	missionControl := m.MissionControl
+	if p.MissionControl != nil {
+		missionControl = p.MissionControl
+	}
+
 	session, err := newPaymentSession(
 		p, getBandwidthHints, m.getRoutingGraph,
-		m.MissionControl, m.PathFindingConfig,
+		missionControl, m.PathFindingConfig,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	// Routes may also reach our own node through the private channels
+	// whose policies our LSPs announced.
+	session.additionalEdges = withLearnedEdges(
+		session.additionalEdges, m.LearnedEdges,
+	)
+
 	return session, nil
 }
 
+// withLearnedEdges returns the given additional edges extended by the edges
+// returned by learnedEdges, or the given edges as is if learnedEdges is nil.
+// The given map isn't modified.
+func withLearnedEdges(edges map[route.Vertex][]*channeldb.CachedEdgePolicy,
+	learnedEdges func() map[route.Vertex][]*channeldb.CachedEdgePolicy) (
+	merged map[route.Vertex][]*channeldb.CachedEdgePolicy) {
+
+	if learnedEdges == nil {
+		return edges
+	}
+
+	learned := learnedEdges()
+	if len(learned) == 0 {
+		return edges
+	}
+
+	merged = make(
+		map[route.Vertex][]*channeldb.CachedEdgePolicy,
+		len(edges)+len(learned),
+	)
+	for vertex, vertexEdges := range edges {
+		merged[vertex] = append(merged[vertex], vertexEdges...)
+	}
+	for vertex, vertexEdges := range learned {
+		merged[vertex] = append(merged[vertex], vertexEdges...)
+	}
+
+	return merged
+}
+
 // NewPaymentSessionEmpty creates a new paymentSession instance that is empty,
 // and will be exhausted immediately. Used for failure reporting to
 // missioncontrol for resumed payment we don't want to make more attempts for.
@@ -257,3 +257,208 @@ type sessionGraph struct {
 func (g *sessionGraph) sourceNode() route.Vertex {
 	return route.Vertex{}
 }
+
+// splitGraph is a routing graph whose source node has the given channels.
+type splitGraph struct {
+	sessionGraph
+
+	channels []*channeldb.DirectedChannel
+}
+
+func (g *splitGraph) forEachNodeChannel(_ route.Vertex,
+	cb func(channel *channeldb.DirectedChannel) error) error {
+
+	for _, channel := range g.channels {
+		if err := cb(channel); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TestRequestRouteSplitStrategy tests that the amounts of the shards that are
+// attempted follow the split strategy of the payment.
+func TestRequestRouteSplitStrategy(t *testing.T) {
+	t.Parallel()
+
+	const (
+		// maxRouteAmt is the largest amount that a route is found
+		// for.
+		maxRouteAmt = lnwire.MilliSatoshi(5000)
+
+		height = 10
+	)
+
+	testCases := []struct {
+		name          string
+		strategy      SplitStrategy
+		maxParts      uint32
+		expectedAmts  []lnwire.MilliSatoshi
+		expectNoRoute bool
+	}{
+		{
+			name:     "adaptive",
+			strategy: SplitAdaptive,
+			maxParts: 16,
+			expectedAmts: []lnwire.MilliSatoshi{
+				12000, 6000, 3000,
+			},
+		},
+		{
+			name:     "equal",
+			strategy: SplitEqual,
+			maxParts: 16,
+			expectedAmts: []lnwire.MilliSatoshi{
+				12000, 6000, 4000,
+			},
+		},
+		{
+			name:     "equal exceeding max parts",
+			strategy: SplitEqual,
+			maxParts: 2,
+			expectedAmts: []lnwire.MilliSatoshi{
+				12000, 6000,
+			},
+			expectNoRoute: true,
+		},
+		{
+			name:     "largest channel",
+			strategy: SplitLargestChannel,
+			maxParts: 16,
+			expectedAmts: []lnwire.MilliSatoshi{
+				4500,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			minShardAmt := lnwire.MilliSatoshi(1000)
+			payment := &LightningPayment{
+				CltvLimit:      30,
+				FinalCLTVDelta: 8,
+				Amount:         12000,
+				FeeLimit:       1000,
+				PaymentAddr:    &[32]byte{1},
+				DestFeatures: lnwire.NewFeatureVector(
+					lnwire.NewRawFeatureVector(
+						lnwire.TLVOnionPayloadOptional,
+						lnwire.PaymentAddrOptional,
+						lnwire.MPPOptional,
+					), lnwire.Features,
+				),
+				MaxParts:      testCase.maxParts,
+				MinShardAmt:   &minShardAmt,
+				SplitStrategy: testCase.strategy,
+			}
+			require.NoError(t, payment.SetPaymentHash(lntypes.Hash{}))
+
+			bandwidth := &mockBandwidthHints{
+				hints: map[uint64]lnwire.MilliSatoshi{
+					1: 4500,
+					2: 2000,
+				},
+			}
+			graph := &splitGraph{
+				channels: []*channeldb.DirectedChannel{
+					{ChannelID: 1, OutPolicySet: true},
+					{ChannelID: 2, OutPolicySet: true},
+				},
+			}
+
+			session, err := newPaymentSession(
+				payment,
+				func(routingGraph) (bandwidthHints, error) {
+					return bandwidth, nil
+				},
+				func() (routingGraph, func(), error) {
+					return graph, func() {}, nil
+				},
+				&MissionControl{}, PathFindingConfig{},
+			)
+			require.NoError(t, err)
+
+			// Record the attempted amounts, and only find a route
+			// for amounts that fit into it.
+			var amts []lnwire.MilliSatoshi
+			session.pathFinder = func(_ *graphParams,
+				_ *RestrictParams, _ *PathFindingConfig,
+				_, _ route.Vertex, amt lnwire.MilliSatoshi,
+				_ float64, _ int32) ([]*channeldb.CachedEdgePolicy,
+				error) {
+
+				amts = append(amts, amt)
+				if amt > maxRouteAmt {
+					return nil, errNoPathFound
+				}
+
+				return []*channeldb.CachedEdgePolicy{{
+					ToNodePubKey: func() route.Vertex {
+						return route.Vertex{}
+					},
+					ToNodeFeatures: payment.DestFeatures,
+				}}, nil
+			}
+
+			rt, err := session.RequestRoute(
+				payment.Amount, payment.FeeLimit, 0, height,
+			)
+			require.Equal(t, testCase.expectedAmts, amts)
+
+			if testCase.expectNoRoute {
+				require.ErrorIs(t, err, errNoPathFound)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(
+				t, amts[len(amts)-1], rt.ReceiverAmt(),
+			)
+		})
+	}
+}
+
+// TestWithLearnedEdges asserts that the learned edges are added to the given
+// additional edges without modifying them.
+func TestWithLearnedEdges(t *testing.T) {
+	t.Parallel()
+
+	var (
+		hintNode = route.Vertex{1}
+		lspNode  = route.Vertex{2}
+
+		hintEdge    = &channeldb.CachedEdgePolicy{ChannelID: 1}
+		learnedEdge = &channeldb.CachedEdgePolicy{ChannelID: 2}
+		lspEdge     = &channeldb.CachedEdgePolicy{ChannelID: 3}
+	)
+
+	edges := map[route.Vertex][]*channeldb.CachedEdgePolicy{
+		hintNode: {hintEdge},
+		lspNode:  {lspEdge},
+	}
+
+	// Without learned edges, the given edges are returned as is.
+	require.Equal(t, edges, withLearnedEdges(edges, nil))
+
+	learnedEdges := func() map[route.Vertex][]*channeldb.CachedEdgePolicy {
+		return map[route.Vertex][]*channeldb.CachedEdgePolicy{
+			lspNode: {learnedEdge},
+		}
+	}
+
+	merged := withLearnedEdges(edges, learnedEdges)
+	require.Equal(t, map[route.Vertex][]*channeldb.CachedEdgePolicy{
+		hintNode: {hintEdge},
+		lspNode:  {lspEdge, learnedEdge},
+	}, merged)
+
+	// The given edges are left untouched.
+	require.Equal(
+		t, []*channeldb.CachedEdgePolicy{lspEdge}, edges[lspNode],
+	)
+}
@@ -0,0 +1,280 @@
+package routing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// bimodalTestScale is the liquidity scale used in the bimodal tests.
+	bimodalTestScale = lnwire.MilliSatoshi(1_000_000)
+
+	// bimodalTestCapacity is the channel capacity used in the bimodal
+	// tests.
+	bimodalTestCapacity = lnwire.MilliSatoshi(10_000_000)
+
+	// bimodalTestNodeWeight is the node weight used in the bimodal tests.
+	bimodalTestNodeWeight = 0.2
+
+	// bimodalTestDecayTime is the decay time used in the bimodal tests.
+	bimodalTestDecayTime = time.Hour
+
+	// bimodalTolerance is the tolerance for comparing probabilities.
+	bimodalTolerance = 1e-9
+)
+
+// newTestBimodalEstimator returns a bimodal estimator with the test
+// parameters.
+func newTestBimodalEstimator(t *testing.T) *BimodalEstimator {
+	estimator, err := NewBimodalEstimator(BimodalConfig{
+		BimodalScaleMsat:  bimodalTestScale,
+		BimodalNodeWeight: bimodalTestNodeWeight,
+		BimodalDecayTime:  bimodalTestDecayTime,
+	})
+	require.NoError(t, err)
+
+	return estimator
+}
+
+// TestBimodalProbabilityFormula tests the direct channel probability of the
+// bimodal estimator for different combinations of previous successes and
+// failures.
+func TestBimodalProbabilityFormula(t *testing.T) {
+	t.Parallel()
+
+	estimator := newTestBimodalEstimator(t)
+
+	testCases := []struct {
+		name          string
+		successAmount lnwire.MilliSatoshi
+		failAmount    lnwire.MilliSatoshi
+		amount        lnwire.MilliSatoshi
+		expectedProb  float64
+	}{
+		{
+			name:         "no info, small amount",
+			failAmount:   bimodalTestCapacity,
+			amount:       100_000,
+			expectedProb: 0.9524141612480959,
+		},
+		{
+			name:         "no info, half the capacity",
+			failAmount:   bimodalTestCapacity,
+			amount:       bimodalTestCapacity / 2,
+			expectedProb: 0.5,
+		},
+		{
+			name:         "no info, large amount",
+			failAmount:   bimodalTestCapacity,
+			amount:       bimodalTestCapacity - 100_000,
+			expectedProb: 0.047585838751904086,
+		},
+		{
+			name:         "no info, full capacity",
+			failAmount:   bimodalTestCapacity,
+			amount:       bimodalTestCapacity,
+			expectedProb: 0,
+		},
+		{
+			name:         "above capacity",
+			failAmount:   bimodalTestCapacity,
+			amount:       bimodalTestCapacity + 1,
+			expectedProb: 0,
+		},
+		{
+			name:          "amount below success amount",
+			successAmount: 2_000_000,
+			failAmount:    bimodalTestCapacity,
+			amount:        1_000_000,
+			expectedProb:  1,
+		},
+		{
+			name:          "amount above success amount",
+			successAmount: 2_000_000,
+			failAmount:    bimodalTestCapacity,
+			amount:        3_000_000,
+			expectedProb:  0.9241162176760548,
+		},
+		{
+			name:         "amount below fail amount",
+			failAmount:   4_000_000,
+			amount:       1_000_000,
+			expectedProb: 0.35759862857707436,
+		},
+		{
+			name:          "amount between success and fail amount",
+			successAmount: 1_000_000,
+			failAmount:    4_000_000,
+			amount:        2_000_000,
+			expectedProb:  0.3386088415660505,
+		},
+		{
+			name:         "amount above fail amount",
+			failAmount:   4_000_000,
+			amount:       5_000_000,
+			expectedProb: 0,
+		},
+		{
+			name:          "fail amount below success amount",
+			successAmount: 5_000_000,
+			failAmount:    4_000_000,
+			amount:        1_000_000,
+			expectedProb:  0,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := estimator.probabilityFormula(
+				bimodalTestCapacity, testCase.successAmount,
+				testCase.failAmount, testCase.amount,
+			)
+			require.NoError(t, err)
+			require.InDelta(
+				t, testCase.expectedProb, p, bimodalTolerance,
+			)
+		})
+	}
+}
+
+// TestBimodalTimeDecay tests that learned success and failure amounts decay
+// over time.
+func TestBimodalTimeDecay(t *testing.T) {
+	t.Parallel()
+
+	now := testTime
+
+	// Fresh results are taken as they are.
+	require.Equal(
+		t, lnwire.MilliSatoshi(1000),
+		canSend(1000, now, now, bimodalTestDecayTime),
+	)
+	require.Equal(
+		t, lnwire.MilliSatoshi(1000),
+		cannotSend(1000, 2000, now, now, bimodalTestDecayTime),
+	)
+
+	// After a long time, we assume that we can't send anything anymore
+	// and that the full capacity can't be sent either.
+	later := now.Add(100 * bimodalTestDecayTime)
+	require.Zero(t, canSend(1000, later, now, bimodalTestDecayTime))
+	require.Equal(
+		t, lnwire.MilliSatoshi(2000),
+		cannotSend(1000, 2000, later, now, bimodalTestDecayTime),
+	)
+
+	// Fail amounts larger than the capacity are capped.
+	require.Equal(
+		t, lnwire.MilliSatoshi(2000),
+		cannotSend(3000, 2000, now, now, bimodalTestDecayTime),
+	)
+}
+
+// TestBimodalPairProbability tests how the results of other channels of a node
+// are mixed into the probability of a channel.
+func TestBimodalPairProbability(t *testing.T) {
+	t.Parallel()
+
+	estimator := newTestBimodalEstimator(t)
+
+	var (
+		now       = testTime
+		toNode    = route.Vertex{node1}
+		otherNode = route.Vertex{node2}
+		capacity  = bimodalTestCapacity.ToSatoshis()
+		amount    = bimodalTestCapacity / 2
+		w         = bimodalTestNodeWeight
+	)
+
+	// Without any results, the probability for half the capacity is one
+	// half.
+	p := estimator.PairProbability(now, nil, toNode, amount, capacity)
+	require.InDelta(t, 0.5, p, bimodalTolerance)
+
+	// A fresh success on another channel of the node increases the
+	// probability.
+	results := NodeResults{
+		otherNode: TimedPairResult{
+			SuccessAmt:  amount,
+			SuccessTime: now,
+		},
+	}
+	p = estimator.PairProbability(now, results, toNode, amount, capacity)
+	require.InDelta(t, (0.5+w)/(1+w), p, bimodalTolerance)
+
+	// A fresh failure on another channel of the node decreases it.
+	results[otherNode] = TimedPairResult{
+		FailAmt:  amount,
+		FailTime: now,
+	}
+	p = estimator.PairProbability(now, results, toNode, amount, capacity)
+	require.InDelta(t, 0.5/(1+w), p, bimodalTolerance)
+
+	// Recent information about the channel itself takes precedence over
+	// the results of other channels.
+	results[toNode] = TimedPairResult{
+		SuccessAmt:  amount,
+		SuccessTime: now,
+	}
+	p = estimator.PairProbability(now, results, toNode, amount, capacity)
+	require.InDelta(t, 1, p, bimodalTolerance)
+
+	// Channels without a known capacity, such as those of route hints, are
+	// assumed to be large, so amounts close to the capacity of a small
+	// channel are more likely to succeed.
+	largeAmount := bimodalTestCapacity - 100_000
+	pSmall := estimator.PairProbability(
+		now, nil, toNode, largeAmount, capacity,
+	)
+	pHint := estimator.PairProbability(now, nil, toNode, largeAmount, 0)
+	require.Greater(t, pHint, pSmall)
+
+	// Our own channels are assumed to work, unless they recently failed.
+	require.Equal(t, 1.0, estimator.LocalPairProbability(now, nil, toNode))
+
+	results[toNode] = TimedPairResult{
+		FailAmt:  amount,
+		FailTime: now,
+	}
+	require.Zero(t, estimator.LocalPairProbability(now, results, toNode))
+	require.Equal(
+		t, 1.0, estimator.LocalPairProbability(
+			now.Add(localFailureInterval), results, toNode,
+		),
+	)
+}
+
+// TestBimodalConfigValidation tests that invalid bimodal configurations are
+// rejected.
+func TestBimodalConfigValidation(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, DefaultBimodalConfig().validate())
+
+	cfg := DefaultBimodalConfig()
+	cfg.BimodalScaleMsat = 0
+	require.ErrorIs(t, cfg.validate(), ErrInvalidScale)
+
+	cfg = DefaultBimodalConfig()
+	cfg.BimodalScaleMsat = BimodalScaleMsatMax + 1
+	require.ErrorIs(t, cfg.validate(), ErrInvalidScale)
+
+	cfg = DefaultBimodalConfig()
+	cfg.BimodalNodeWeight = 1.1
+	require.ErrorIs(t, cfg.validate(), ErrInvalidNodeWeight)
+
+	cfg = DefaultBimodalConfig()
+	cfg.BimodalDecayTime = 0
+	require.ErrorIs(t, cfg.validate(), ErrInvalidDecayTime)
+
+	_, err := NewBimodalEstimator(cfg)
+	require.Error(t, err)
+}
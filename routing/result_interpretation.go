@@ -327,6 +327,25 @@ func (i *interpretedResult) processPaymentOutcomeIntermediate(
 	case *lnwire.InvalidOnionPayload:
 		reportNode()
 
+	// The introduction node of a blinded route reports all failures
+	// within the route as invalid blinding, because the nodes within the
+	// route don't reveal why they failed the payment. The failure can't be
+	// attributed to a single node of the blinded route, so we penalize
+	// the connection into it, which disqualifies the blinded route.
+	// Other nodes aren't allowed to report this failure.
+	case *lnwire.FailInvalidOnionBlinding:
+		if errorSourceIdx != blindedIntroIdx(route) {
+			i.failNode(route, errorSourceIdx)
+
+			return
+		}
+
+		reportOutgoing()
+
+		// All nodes up to the introduction node must have forwarded
+		// successfully.
+		i.successPairRange(route, 0, errorSourceIdx-1)
+
 	// If the next hop in the route wasn't known or offline, we'll only
 	// penalize the channel set which we attempted to route over. This is
 	// conservative, and it can handle faulty channels between nodes
@@ -419,6 +438,19 @@ func (i *interpretedResult) processPaymentOutcomeUnknown(route *route.Route) {
 	i.failPairRange(route, 0, n-1)
 }
 
+// blindedIntroIdx returns the index of the introduction node of the blinded
+// route that the route leads to, in the indexing of the failure source. If
+// the route doesn't lead to a blinded route, -1 is returned.
+func blindedIntroIdx(rt *route.Route) int {
+	for idx, hop := range rt.Hops {
+		if hop.BlindingPoint != nil {
+			return idx + 1
+		}
+	}
+
+	return -1
+}
+
 // failNode marks the node indicated by idx in the route as failed. It also
 // marks the incoming and outgoing channels of the node as failed. This function
 // intentionally panics when the self node is failed.
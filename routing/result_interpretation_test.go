@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/routing/route"
@@ -51,6 +52,28 @@ var (
 			{PubKeyBytes: hops[4], AmtToForward: 90},
 		},
 	}
+
+	_, blindingPoint = btcec.PrivKeyFromBytes([]byte{1})
+
+	routeThreeHopBlinded = route.Route{
+		SourcePubKey: hops[0],
+		TotalAmount:  100,
+		Hops: []*route.Hop{
+			{PubKeyBytes: hops[1], AmtToForward: 99},
+			{
+				PubKeyBytes:   hops[2],
+				AmtToForward:  95,
+				EncryptedData: []byte{1},
+				BlindingPoint: blindingPoint,
+			},
+			{
+				PubKeyBytes:   hops[3],
+				AmtToForward:  95,
+				EncryptedData: []byte{2},
+				TotalAmtMsat:  95,
+			},
+		},
+	}
 )
 
 func getTestPair(from, to int) DirectedNodePair {
@@ -366,6 +389,45 @@ var resultTestCases = []resultTestCase{
 			policyFailure: getPolicyFailure(1, 2),
 		},
 	},
+
+	// Test an invalid blinding failure from the introduction node of a
+	// blinded route. The connection into the blinded route should be
+	// penalized, and the hops up to the introduction node succeeded.
+	{
+		name:          "blinded route invalid blinding",
+		route:         &routeThreeHopBlinded,
+		failureSrcIdx: 2,
+		failure:       &lnwire.FailInvalidOnionBlinding{},
+
+		expectedResult: &interpretedResult{
+			pairResults: map[DirectedNodePair]pairResult{
+				getTestPair(0, 1): successPairResult(100),
+				getTestPair(1, 2): successPairResult(99),
+				getTestPair(2, 3): failPairResult(0),
+				getTestPair(3, 2): failPairResult(0),
+			},
+		},
+	},
+
+	// Test an invalid blinding failure from a node that isn't the
+	// introduction node of a blinded route. As only the introduction node
+	// may report this failure, the reporting node should be penalized.
+	{
+		name:          "invalid blinding outside blinded route",
+		route:         &routeThreeHopBlinded,
+		failureSrcIdx: 1,
+		failure:       &lnwire.FailInvalidOnionBlinding{},
+
+		expectedResult: &interpretedResult{
+			pairResults: map[DirectedNodePair]pairResult{
+				getTestPair(0, 1): failPairResult(0),
+				getTestPair(1, 0): failPairResult(0),
+				getTestPair(1, 2): failPairResult(0),
+				getTestPair(2, 1): failPairResult(0),
+			},
+			nodeFailure: &hops[1],
+		},
+	},
 }
 
 // TestResultInterpretation executes a list of test cases that test the result
@@ -13,6 +13,7 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2"
 	sphinx "github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/lnwire/hoppayload"
 	"github.com/lightningnetwork/lnd/record"
 	"github.com/lightningnetwork/lnd/tlv"
 )
@@ -131,6 +132,20 @@ type Hop struct {
 	// Metadata is additional data that is sent along with the payment to
 	// the payee.
 	Metadata []byte
+
+	// EncryptedData is the data that the creator of a blinded route
+	// addressed to this hop. It is only set for hops within a blinded
+	// route, whose PubKeyBytes are the blinded public key of the node,
+	// except for the introduction node.
+	EncryptedData []byte
+
+	// BlindingPoint is the blinding point of the blinded route. It is only
+	// set for the introduction node of a blinded route.
+	BlindingPoint *btcec.PublicKey
+
+	// TotalAmtMsat is the total amount of a payment to a blinded route. It
+	// is only set for the final hop of a blinded route.
+	TotalAmtMsat lnwire.MilliSatoshi
 }
 
 // Copy returns a deep copy of the Hop.
@@ -165,71 +180,37 @@ func (h *Hop) PackHopPayload(w io.Writer, nextChanID uint64) error {
 			"payloads")
 	}
 
-	// Otherwise, we'll need to make a new stream that includes our
-	// required routing fields, as well as these optional values.
-	var records []tlv.Record
-
-	// Every hop must have an amount to forward and CLTV expiry.
-	amt := uint64(h.AmtToForward)
-	records = append(records,
-		record.NewAmtToFwdRecord(&amt),
-		record.NewLockTimeRecord(&h.OutgoingTimeLock),
-	)
-
-	// BOLT 04 says the next_hop_id should be omitted for the final hop,
-	// but present for all others.
-	//
-	// TODO(conner): test using hop.Exit once available
-	if nextChanID != 0 {
-		records = append(records,
-			record.NewNextHopIDRecord(&nextChanID),
-		)
-	}
-
 	// If an MPP record is destined for this hop, ensure that we only ever
 	// attach it to the final hop. Otherwise the route was constructed
 	// incorrectly.
-	if h.MPP != nil {
-		if nextChanID == 0 {
-			records = append(records, h.MPP.Record())
-		} else {
-			return ErrIntermediateMPPHop
-		}
+	if h.MPP != nil && nextChanID != 0 {
+		return ErrIntermediateMPPHop
 	}
 
 	// If an AMP record is destined for this hop, ensure that we only ever
 	// attach it if we also have an MPP record. We can infer that this is
 	// already a final hop if MPP is non-nil otherwise we would have exited
 	// above.
-	if h.AMP != nil {
-		if h.MPP != nil {
-			records = append(records, h.AMP.Record())
-		} else {
-			return ErrAMPMissingMPP
-		}
-	}
-
-	// If metadata is specified, generate a tlv record for it.
-	if h.Metadata != nil {
-		records = append(records,
-			record.NewMetadataRecord(&h.Metadata),
-		)
+	if h.AMP != nil && h.MPP == nil {
+		return ErrAMPMissingMPP
 	}
 
-	// Append any custom types destined for this hop.
-	tlvRecords := tlv.MapToRecords(h.CustomRecords)
-	records = append(records, tlvRecords...)
-
-	// To ensure we produce a canonical stream, we'll sort the records
-	// before encoding them as a stream in the hop payload.
-	tlv.SortRecords(records)
-
-	tlvStream, err := tlv.NewStream(records...)
-	if err != nil {
-		return err
+	// Otherwise, we'll encode our required routing fields, as well as
+	// these optional values, as a canonical hop payload.
+	payload := &hoppayload.Payload{
+		AmtToForward:  h.AmtToForward,
+		OutgoingCltv:  h.OutgoingTimeLock,
+		NextHop:       lnwire.NewShortChanIDFromInt(nextChanID),
+		MPP:           h.MPP,
+		AMP:           h.AMP,
+		Metadata:      h.Metadata,
+		CustomRecords: h.CustomRecords,
+		EncryptedData: h.EncryptedData,
+		BlindingPoint: h.BlindingPoint,
+		TotalAmtMsat:  h.TotalAmtMsat,
 	}
 
-	return tlvStream.Encode(w)
+	return payload.Encode(w)
 }
 
 // Size returns the total size this hop's payload would take up in the onion
@@ -246,20 +227,53 @@ func (h *Hop) PayloadSize(nextChanID uint64) uint64 {
 			tlv.VarIntSize(length) + length
 	}
 
-	// Add amount size.
-	addRecord(record.AmtOnionType, tlv.SizeTUint64(uint64(h.AmtToForward)))
+	// Intermediate hops of a blinded route find their amount, lock time
+	// and next hop in their encrypted data.
+	blindedIntermediate := h.EncryptedData != nil && nextChanID != 0
 
-	// Add lock time size.
-	addRecord(
-		record.LockTimeOnionType,
-		tlv.SizeTUint64(uint64(h.OutgoingTimeLock)),
-	)
+	if !blindedIntermediate {
+		// Add amount size.
+		addRecord(
+			record.AmtOnionType,
+			tlv.SizeTUint64(uint64(h.AmtToForward)),
+		)
+
+		// Add lock time size.
+		addRecord(
+			record.LockTimeOnionType,
+			tlv.SizeTUint64(uint64(h.OutgoingTimeLock)),
+		)
+	}
 
 	// Add next hop if present.
-	if nextChanID != 0 {
+	if nextChanID != 0 && h.EncryptedData == nil {
 		addRecord(record.NextHopOnionType, 8)
 	}
 
+	// Add encrypted data if present.
+	if h.EncryptedData != nil {
+		addRecord(
+			record.EncryptedDataOnionType,
+			uint64(len(h.EncryptedData)),
+		)
+	}
+
+	// Add blinding point if present.
+	if h.BlindingPoint != nil {
+		addRecord(
+			record.BlindingPointOnionType,
+			btcec.PubKeyBytesLenCompressed,
+		)
+	}
+
+	// Add total amount if present.
+	if h.TotalAmtMsat != 0 {
+		addRecord(
+			record.TotalAmtMsatBlindedType,
+			tlv.SizeTUint64(uint64(h.TotalAmtMsat)),
+		)
+	}
+
 	// Add mpp if present.
 	if h.MPP != nil {
 		addRecord(record.MPPOnionType, h.MPP.PayloadSize())
@@ -214,3 +214,64 @@ func TestPayloadSize(t *testing.T) {
 		}
 	}
 }
+
+// TestBlindedPayloadSize tests the payload size calculation for the hops of a
+// route to a blinded path, whose intermediate hops only receive their
+// encrypted data.
+func TestBlindedPayloadSize(t *testing.T) {
+	hops := []*Hop{
+		{
+			PubKeyBytes:      testPubKeyBytes,
+			AmtToForward:     1500,
+			OutgoingTimeLock: 700100,
+			ChannelID:        63584534844,
+		},
+		{
+			PubKeyBytes:      testPubKeyBytes,
+			AmtToForward:     1200,
+			OutgoingTimeLock: 700050,
+			ChannelID:        3432483437438,
+			EncryptedData:    []byte{1, 2, 3, 4},
+			BlindingPoint:    testPubKey,
+		},
+		{
+			PubKeyBytes:      testPubKeyBytes,
+			AmtToForward:     1200,
+			OutgoingTimeLock: 700000,
+			ChannelID:        1,
+			EncryptedData:    []byte{5, 6, 7},
+		},
+		{
+			PubKeyBytes:      testPubKeyBytes,
+			AmtToForward:     1200,
+			OutgoingTimeLock: 700000,
+			ChannelID:        2,
+			EncryptedData:    []byte{8, 9},
+			TotalAmtMsat:     2400,
+		},
+	}
+
+	rt := Route{
+		Hops: hops,
+	}
+	path, err := rt.ToSphinxPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, onionHop := range path[:path.TrueRouteLength()] {
+		hop := hops[i]
+		var nextChan uint64
+		if i < len(hops)-1 {
+			nextChan = hops[i+1].ChannelID
+		}
+
+		expected := uint64(onionHop.HopPayload.NumBytes())
+		actual := hop.PayloadSize(nextChan)
+		if expected != actual {
+			t.Fatalf("unexpected payload size at hop %v: "+
+				"expected %v, got %v",
+				i, expected, actual)
+		}
+	}
+}
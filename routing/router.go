@@ -225,9 +225,10 @@ type MissionController interface {
 	ReportPaymentSuccess(attemptID uint64, rt *route.Route) error
 
 	// GetProbability is expected to return the success probability of a
-	// payment from fromNode along edge.
+	// payment from fromNode along edge. The capacity is the capacity of
+	// the channel used, or zero if it isn't known.
 	GetProbability(fromNode, toNode route.Vertex,
-		amt lnwire.MilliSatoshi) float64
+		amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64
 }
 
 // FeeSchema is the set fee configuration for a Lightning Node on the network.
@@ -362,6 +363,11 @@ type Config struct {
 	// IsAlias returns whether a passed ShortChannelID is an alias. This is
 	// only used for our local channels.
 	IsAlias func(scid lnwire.ShortChannelID) bool
+
+	// LearnedEdges returns the edges of our private channels whose
+	// policies were learned from our LSPs, which are added to the route
+	// hints of every route query. If nil, no learned edges are used.
+	LearnedEdges func() map[route.Vertex][]*channeldb.CachedEdgePolicy
 }
 
 // EdgeLocator is a struct used to identify a specific edge.
@@ -665,11 +671,13 @@ func (r *ChannelRouter) Start() error {
 			// don't need it to timeout. It will stop immediately
 			// after the existing attempt has finished anyway. We
 			// also set a zero fee limit, as no more routes should
-			// be tried.
+			// be tried. As mission control sessions don't survive
+			// restarts, the result is reported to our own mission
+			// control.
 			_, _, err := r.sendPayment(
 				payment.Info.Value, 0,
 				payment.Info.PaymentIdentifier, 0, paySession,
-				shardTracker,
+				shardTracker, nil, 0,
 			)
 			if err != nil {
 				log.Errorf("Resuming payment %v failed: %v.",
@@ -1777,9 +1785,11 @@ func (r *ChannelRouter) FindRoute(source, target route.Vertex,
 
 	path, err := findPath(
 		&graphParams{
-			additionalEdges: routeHints,
-			bandwidthHints:  bandwidthHints,
-			graph:           r.cachedGraph,
+			additionalEdges: withLearnedEdges(
+				routeHints, r.cfg.LearnedEdges,
+			),
+			bandwidthHints: bandwidthHints,
+			graph:          r.cachedGraph,
 		},
 		restrictions,
 		&r.cfg.PathFindingConfig,
@@ -1934,6 +1944,14 @@ type LightningPayment struct {
 	// destination successfully.
 	RouteHints [][]zpay32.HopHint
 
+	// BlindedPayment holds the blinded path that the payment is routed to,
+	// along with its aggregate forwarding policy. If set, Target must be
+	// the target of the blinded path, and neither route hints nor a
+	// payment address may be set.
+	//
+	// NOTE: This is optional unless required by the payment.
+	BlindedPayment *BlindedPayment
+
 	// OutgoingChannelIDs is the list of channels that are allowed for the
 	// first hop. If nil, any channel may be used.
 	OutgoingChannelIDs []uint64
@@ -1942,6 +1960,23 @@ type LightningPayment struct {
 	// is reached. If nil, any node may be used.
 	LastHop *route.Vertex
 
+	// IgnoredNodes is the set of nodes that must not be used as
+	// intermediate hops of the payment's routes.
+	IgnoredNodes map[route.Vertex]struct{}
+
+	// AllowedNodes is the set of nodes that may be used as intermediate
+	// hops of the payment's routes. If nil, any node may be used.
+	AllowedNodes map[route.Vertex]struct{}
+
+	// IgnoredChannels is the set of channels that must not be used in the
+	// payment's routes.
+	IgnoredChannels map[uint64]struct{}
+
+	// AllowedChannels is the set of channels that may be used in the
+	// payment's routes, including the channels of route hints. If nil, any
+	// channel may be used.
+	AllowedChannels map[uint64]struct{}
+
 	// DestFeatures specifies the set of features we assume the final node
 	// has for pathfinding. Typically these will be taken directly from an
 	// invoice, but they can also be manually supplied or assumed by the
@@ -1969,6 +2004,13 @@ type LightningPayment struct {
 	// to complete the full amount.
 	MaxParts uint32
 
+	// MaxParallelism is the maximum number of partial payments that may
+	// be in flight at the same time. The payment starts out launching
+	// this many shards in parallel, backs off when shards fail and
+	// recovers as shards succeed. If zero, shards are launched one at a
+	// time and the number in flight is only limited by MaxParts.
+	MaxParallelism uint32
+
 	// MaxShardAmt is the largest shard that we'll attempt to split using.
 	// If this field is set, and we need to split, rather than attempting
 	// half of the original payment amount, we'll use this value if half
@@ -1977,6 +2019,17 @@ type LightningPayment struct {
 	// NOTE: This field is _optional_.
 	MaxShardAmt *lnwire.MilliSatoshi
 
+	// MinShardAmt is the smallest shard that we'll attempt to split
+	// into. If no route is found for a shard of this size, the payment
+	// isn't split any further.
+	//
+	// NOTE: This field is _optional_. If nil, DefaultShardMinAmt is used.
+	MinShardAmt *lnwire.MilliSatoshi
+
+	// SplitStrategy determines how the payment is split into shards if no
+	// route is found for the remaining amount.
+	SplitStrategy SplitStrategy
+
 	// TimePref is the time preference for this payment. Set to -1 to
 	// optimize for fees only, to 1 to optimize for reliability only or a
 	// value in between for a mix.
@@ -1985,6 +2038,15 @@ type LightningPayment struct {
 	// Metadata is additional data that is sent along with the payment to
 	// the payee.
 	Metadata []byte
+
+	// MissionControl is the mission control instance that is used for
+	// pathfinding and to which the results of the payment's attempts are
+	// reported. This allows a payment to run in an isolated mission
+	// control session.
+	//
+	// NOTE: This field is _optional_. If nil, the router's mission
+	// control is used.
+	MissionControl MissionController
 }
 
 // AMPOptions houses information that must be known in order to send an AMP
@@ -2050,6 +2112,7 @@ func (r *ChannelRouter) SendPayment(payment *LightningPayment) ([32]byte,
 	return r.sendPayment(
 		payment.Amount, payment.FeeLimit, payment.Identifier(),
 		payment.PayAttemptTimeout, paySession, shardTracker,
+		payment.MissionControl, payment.MaxParallelism,
 	)
 }
 
@@ -2073,6 +2136,7 @@ func (r *ChannelRouter) SendPaymentAsync(payment *LightningPayment) error {
 		_, _, err := r.sendPayment(
 			payment.Amount, payment.FeeLimit, payment.Identifier(),
 			payment.PayAttemptTimeout, paySession, shardTracker,
+			payment.MissionControl, payment.MaxParallelism,
 		)
 		if err != nil {
 			log.Errorf("Payment %x failed: %v",
@@ -2171,6 +2235,57 @@ func (r *ChannelRouter) SendToRouteSkipTempErr(htlcHash lntypes.Hash,
 	return r.sendToRoute(htlcHash, rt, true)
 }
 
+// CustomOnion holds the caller provided sphinx parameters of a SendToRoute
+// attempt, which allow the caller to use an HTLC as the transport of a custom
+// onion payload.
+type CustomOnion struct {
+	// SessionKey is the sphinx session key the onion is constructed with.
+	// It is persisted with the attempt, so that errors returned by the
+	// hops of the route can be decrypted. If nil, a random session key is
+	// generated.
+	SessionKey *btcec.PrivateKey
+
+	// OnionBlob is an optional, fully constructed onion packet that is
+	// sent instead of the one we'd construct for the route. It must have
+	// been created with SessionKey for the hops of the route, otherwise
+	// failures can't be attributed to a hop.
+	OnionBlob []byte
+}
+
+// validate checks that the custom onion parameters are consistent.
+func (c *CustomOnion) validate() error {
+	if c.OnionBlob == nil {
+		return nil
+	}
+
+	if c.SessionKey == nil {
+		return errors.New("a custom onion blob requires the session " +
+			"key it was constructed with")
+	}
+
+	if len(c.OnionBlob) != lnwire.OnionPacketSize {
+		return fmt.Errorf("custom onion blob must be %d bytes, got %d",
+			lnwire.OnionPacketSize, len(c.OnionBlob))
+	}
+
+	return nil
+}
+
+// SendToRouteCustomOnion sends a payment using the provided route like
+// SendToRoute, but constructs the HTLC's onion with the given sphinx
+// parameters. If skipTempErr is true, the payment is only failed when a
+// terminal error is returned from the attempt.
+func (r *ChannelRouter) SendToRouteCustomOnion(htlcHash lntypes.Hash,
+	rt *route.Route, skipTempErr bool,
+	onion *CustomOnion) (*channeldb.HTLCAttempt, error) {
+
+	if err := onion.validate(); err != nil {
+		return nil, err
+	}
+
+	return r.sendToRouteWithOnion(htlcHash, rt, skipTempErr, onion)
+}
+
 // sendToRoute attempts to send a payment with the given hash through the
 // provided route. This function is blocking and will return the attempt
 // information as it is stored in the database. For a successful htlc, this
@@ -2180,6 +2295,15 @@ func (r *ChannelRouter) SendToRouteSkipTempErr(htlcHash lntypes.Hash,
 func (r *ChannelRouter) sendToRoute(htlcHash lntypes.Hash, rt *route.Route,
 	skipTempErr bool) (*channeldb.HTLCAttempt, error) {
 
+	return r.sendToRouteWithOnion(htlcHash, rt, skipTempErr, nil)
+}
+
+// sendToRouteWithOnion is sendToRoute with optional, caller provided sphinx
+// parameters. If customOnion is nil, the onion is constructed as usual.
+func (r *ChannelRouter) sendToRouteWithOnion(htlcHash lntypes.Hash,
+	rt *route.Route, skipTempErr bool,
+	customOnion *CustomOnion) (*channeldb.HTLCAttempt, error) {
+
 	// Calculate amount paid to receiver.
 	amt := rt.ReceiverAmt()
 
@@ -2237,9 +2361,11 @@ func (r *ChannelRouter) sendToRoute(htlcHash lntypes.Hash, rt *route.Route,
 
 	// Launch a shard along the given route.
 	sh := &shardHandler{
-		router:       r,
-		identifier:   paymentIdentifier,
-		shardTracker: shardTracker,
+		router:         r,
+		identifier:     paymentIdentifier,
+		shardTracker:   shardTracker,
+		customOnion:    customOnion,
+		missionControl: r.cfg.MissionControl,
 	}
 
 	var shardError error
@@ -2329,6 +2455,10 @@ func (r *ChannelRouter) sendToRoute(htlcHash lntypes.Hash, rt *route.Route,
 // haven't had any payment attempt sent to the switch yet. If it has had an
 // attempt already, it should be passed such that the result can be retrieved.
 //
+// The missionControl argument can be used to report the attempt results to an
+// isolated mission control session. If it is nil, the router's mission control
+// is used.
+//
 // This method relies on the ControlTower's internal payment state machine to
 // carry out its execution. After restarts it is safe, and assumed, that the
 // router will call this method for every payment still in-flight according to
@@ -2336,7 +2466,8 @@ func (r *ChannelRouter) sendToRoute(htlcHash lntypes.Hash, rt *route.Route,
 func (r *ChannelRouter) sendPayment(
 	totalAmt, feeLimit lnwire.MilliSatoshi, identifier lntypes.Hash,
 	timeout time.Duration, paySession PaymentSession,
-	shardTracker shards.ShardTracker) ([32]byte, *route.Route, error) {
+	shardTracker shards.ShardTracker, missionControl MissionController,
+	maxParallelism uint32) ([32]byte, *route.Route, error) {
 
 	// We'll also fetch the current block height so we can properly
 	// calculate the required HTLC time locks within the route.
@@ -2345,16 +2476,22 @@ func (r *ChannelRouter) sendPayment(
 		return [32]byte{}, nil, err
 	}
 
+	if missionControl == nil {
+		missionControl = r.cfg.MissionControl
+	}
+
 	// Now set up a paymentLifecycle struct with these params, such that we
 	// can resume the payment from the current state.
 	p := &paymentLifecycle{
-		router:        r,
-		totalAmount:   totalAmt,
-		feeLimit:      feeLimit,
-		identifier:    identifier,
-		paySession:    paySession,
-		shardTracker:  shardTracker,
-		currentHeight: currentHeight,
+		router:         r,
+		totalAmount:    totalAmt,
+		feeLimit:       feeLimit,
+		identifier:     identifier,
+		paySession:     paySession,
+		shardTracker:   shardTracker,
+		currentHeight:  currentHeight,
+		missionControl: missionControl,
+		maxParallelism: maxParallelism,
 	}
 
 	// If a timeout is specified, create a timeout channel. If no timeout is
@@ -2692,16 +2829,53 @@ func (e ErrNoChannel) Error() string {
 		"node %v (%v)", e.position, e.fromNode)
 }
 
+// HopPolicyOverride overrides the forwarding policy of the channel that leads
+// to a hop of a route built by BuildRoute. This allows routes to be built with
+// fees and time lock deltas that differ from the ones announced in the graph,
+// for example values taken from a route hint or privately agreed on with the
+// forwarding node.
+type HopPolicyOverride struct {
+	// ChannelID is the channel that leads to the hop. If set, the channel
+	// doesn't need to be known in the graph. If zero, the channel is
+	// selected from the graph and only its fees and time lock delta are
+	// overridden.
+	ChannelID uint64
+
+	// FeeBaseMSat is the base fee that is charged for forwarding over the
+	// channel.
+	FeeBaseMSat lnwire.MilliSatoshi
+
+	// FeeProportionalMillionths is the fee rate that is charged for
+	// forwarding over the channel.
+	FeeProportionalMillionths lnwire.MilliSatoshi
+
+	// TimeLockDelta is the time lock delta that is required for forwarding
+	// over the channel.
+	TimeLockDelta uint16
+}
+
 // BuildRoute returns a fully specified route based on a list of pubkeys. If
 // amount is nil, the minimum routable amount is used. To force a specific
-// outgoing channel, use the outgoingChan parameter.
+// outgoing channel, use the outgoingChan parameter. The policies of the
+// channels leading to the hops can be overridden through the overrides map,
+// which is keyed by the index of the hop within the list of pubkeys. As the
+// first hop is reached over one of our own channels, its policy can't be
+// overridden.
 func (r *ChannelRouter) BuildRoute(amt *lnwire.MilliSatoshi,
-	hops []route.Vertex, outgoingChan *uint64,
-	finalCltvDelta int32, payAddr *[32]byte) (*route.Route, error) {
+	hops []route.Vertex, outgoingChan *uint64, finalCltvDelta int32,
+	payAddr *[32]byte,
+	overrides map[int]*HopPolicyOverride) (*route.Route, error) {
 
 	log.Tracef("BuildRoute called: hopsCount=%v, amt=%v",
 		len(hops), amt)
 
+	for idx := range overrides {
+		if idx < 1 || idx >= len(hops) {
+			return nil, fmt.Errorf("invalid policy override for "+
+				"hop %d of route with %d hops", idx, len(hops))
+		}
+	}
+
 	var outgoingChans map[uint64]struct{}
 	if outgoingChan != nil {
 		outgoingChans = map[uint64]struct{}{
@@ -2761,12 +2935,23 @@ func (r *ChannelRouter) BuildRoute(amt *lnwire.MilliSatoshi,
 		localChan := i == 0
 
 		// Build unified policies for this hop based on the channels
-		// known in the graph.
+		// known in the graph, unless the channel to use is given by a
+		// policy override.
 		u := newUnifiedPolicies(source, toNode, outgoingChans)
 
-		err := u.addGraphPolicies(r.cachedGraph)
-		if err != nil {
-			return nil, err
+		override := overrides[i]
+		if override != nil && override.ChannelID != 0 {
+			policy, err := r.overridePolicy(toNode, override)
+			if err != nil {
+				return nil, err
+			}
+
+			u.addPolicy(fromNode, policy, 0)
+		} else {
+			err := u.addGraphPolicies(r.cachedGraph)
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		// Exit if there are no channels.
@@ -2778,6 +2963,12 @@ func (r *ChannelRouter) BuildRoute(amt *lnwire.MilliSatoshi,
 			}
 		}
 
+		// Replace the fees and time lock delta of the graph policies if
+		// they are overridden.
+		if override != nil && override.ChannelID == 0 {
+			unifiedPolicy.overridePolicies(override)
+		}
+
 		// If using min amt, increase amt if needed.
 		if useMinAmt {
 			min := unifiedPolicy.minAmt()
@@ -2843,3 +3034,28 @@ func (r *ChannelRouter) BuildRoute(amt *lnwire.MilliSatoshi,
 		},
 	)
 }
+
+// overridePolicy returns the policy of a channel leading to toNode that isn't
+// necessarily known in the graph, as described by a policy override.
+func (r *ChannelRouter) overridePolicy(toNode route.Vertex,
+	override *HopPolicyOverride) (*channeldb.CachedEdgePolicy, error) {
+
+	// The features of the node are needed to determine the payload format
+	// of the hop. If the node isn't known, no features are assumed to be
+	// supported.
+	features, err := r.cachedGraph.fetchNodeFeatures(toNode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &channeldb.CachedEdgePolicy{
+		ChannelID:                 override.ChannelID,
+		TimeLockDelta:             override.TimeLockDelta,
+		FeeBaseMSat:               override.FeeBaseMSat,
+		FeeProportionalMillionths: override.FeeProportionalMillionths,
+		ToNodePubKey: func() route.Vertex {
+			return toNode
+		},
+		ToNodeFeatures: features,
+	}, nil
+}
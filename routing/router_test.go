@@ -119,12 +119,15 @@ func createTestCtxFromGraphInstanceAssumeValid(t *testing.T,
 		AttemptCost:    100,
 	}
 
+	estimator, err := NewAprioriEstimator(AprioriConfig{
+		PenaltyHalfLife:       time.Hour,
+		AprioriHopProbability: 0.9,
+		AprioriWeight:         0.5,
+	})
+	require.NoError(t, err)
+
 	mcConfig := &MissionControlConfig{
-		ProbabilityEstimatorCfg: ProbabilityEstimatorCfg{
-			PenaltyHalfLife:       time.Hour,
-			AprioriHopProbability: 0.9,
-			AprioriWeight:         0.5,
-		},
+		Estimator: estimator,
 	}
 
 	mc, err := NewMissionControl(
@@ -3101,7 +3104,7 @@ func TestBuildRoute(t *testing.T) {
 
 	// Build the route for the given amount.
 	rt, err := ctx.router.BuildRoute(
-		&amt, hops, nil, 40, &payAddr,
+		&amt, hops, nil, 40, &payAddr, nil,
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -3117,7 +3120,7 @@ func TestBuildRoute(t *testing.T) {
 
 	// Build the route for the minimum amount.
 	rt, err = ctx.router.BuildRoute(
-		nil, hops, nil, 40, &payAddr,
+		nil, hops, nil, 40, &payAddr, nil,
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -3138,7 +3141,7 @@ func TestBuildRoute(t *testing.T) {
 		ctx.aliases["e"], ctx.aliases["c"],
 	}
 	_, err = ctx.router.BuildRoute(
-		nil, hops, nil, 40, nil,
+		nil, hops, nil, 40, nil, nil,
 	)
 	errNoChannel, ok := err.(ErrNoChannel)
 	if !ok {
@@ -3151,6 +3154,65 @@ func TestBuildRoute(t *testing.T) {
 	if errNoChannel.fromNode != ctx.aliases["a"] {
 		t.Fatalf("unexpected no channel error node")
 	}
+
+	// Override the policy of the channels from b to c. The route should
+	// be built with the overridden fee and time lock delta instead of the
+	// ones known in the graph.
+	hops = []route.Vertex{
+		ctx.aliases["b"], ctx.aliases["c"],
+	}
+	rt, err = ctx.router.BuildRoute(
+		&amt, hops, nil, 40, &payAddr, map[int]*HopPolicyOverride{
+			1: {
+				FeeBaseMSat:   1000,
+				TimeLockDelta: 10,
+			},
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, rt.Hops, 2)
+	require.EqualValues(t, 101000, rt.TotalAmount)
+	require.EqualValues(
+		t, startingBlockHeight+40+10, rt.TotalTimeLock,
+	)
+
+	// Build a route to a node that isn't known in the graph, over a
+	// channel that is only given by a policy override.
+	var unknownNode route.Vertex
+	unknownNode[0] = 0x02
+	unknownNode[1] = 0xaa
+
+	hops = []route.Vertex{
+		ctx.aliases["b"], unknownNode,
+	}
+	rt, err = ctx.router.BuildRoute(
+		&amt, hops, nil, 40, nil, map[int]*HopPolicyOverride{
+			1: {
+				ChannelID:     99,
+				FeeBaseMSat:   2000,
+				TimeLockDelta: 20,
+			},
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, rt.Hops, 2)
+	require.EqualValues(t, 102000, rt.TotalAmount)
+	require.EqualValues(t, 99, rt.Hops[1].ChannelID)
+	require.Equal(t, unknownNode, rt.Hops[1].PubKeyBytes)
+	require.EqualValues(
+		t, startingBlockHeight+40+20, rt.TotalTimeLock,
+	)
+
+	// The policy of our own channel to the first hop can't be
+	// overridden.
+	_, err = ctx.router.BuildRoute(
+		&amt, hops, nil, 40, nil, map[int]*HopPolicyOverride{
+			0: {
+				FeeBaseMSat: 1000,
+			},
+		},
+	)
+	require.Error(t, err)
 }
 
 // edgeCreationModifier is an enum-like type used to modify steps that are
@@ -4512,3 +4574,110 @@ func TestSendToRouteTempFailure(t *testing.T) {
 	payer.AssertExpectations(t)
 	missionControl.AssertExpectations(t)
 }
+
+// TestSendToRouteCustomOnion asserts that SendToRoute uses the caller
+// provided session key and onion for the attempt, and that inconsistent
+// custom onion parameters are rejected.
+func TestSendToRouteCustomOnion(t *testing.T) {
+	var (
+		payHash     lntypes.Hash
+		payAmt      = lnwire.MilliSatoshi(10000)
+		testAttempt = &channeldb.HTLCAttempt{}
+	)
+
+	node, err := createTestNode()
+	require.NoError(t, err)
+
+	// Create a simple 1-hop route.
+	hops := []*route.Hop{
+		{
+			ChannelID:    1,
+			PubKeyBytes:  node.PubKeyBytes,
+			AmtToForward: payAmt,
+		},
+	}
+	rt, err := route.NewRouteFromHops(payAmt, 100, node.PubKeyBytes, hops)
+	require.NoError(t, err)
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	onionBlob := bytes.Repeat([]byte{0xaa}, lnwire.OnionPacketSize)
+
+	// Create mockers.
+	controlTower := &mockControlTower{}
+	payer := &mockPaymentAttemptDispatcher{}
+	missionControl := &mockMissionControl{}
+
+	// Create the router.
+	router := &ChannelRouter{cfg: &Config{
+		Control:        controlTower,
+		Payer:          payer,
+		MissionControl: missionControl,
+		Clock:          clock.NewTestClock(time.Unix(1, 0)),
+		NextPaymentID: func() (uint64, error) {
+			return 0, nil
+		},
+	}}
+
+	// Inconsistent custom onion parameters are rejected before the
+	// payment is initiated.
+	_, err = router.SendToRouteCustomOnion(
+		payHash, rt, false, &CustomOnion{OnionBlob: onionBlob},
+	)
+	require.Error(t, err)
+
+	_, err = router.SendToRouteCustomOnion(
+		payHash, rt, false, &CustomOnion{
+			SessionKey: sessionKey,
+			OnionBlob:  onionBlob[1:],
+		},
+	)
+	require.Error(t, err)
+
+	// Register mockers with the expected method calls. The attempt must
+	// be stored with our session key, and the htlc must carry our onion.
+	controlTower.On("InitPayment", payHash, mock.Anything).Return(nil)
+	controlTower.On("RegisterAttempt", payHash, mock.MatchedBy(
+		func(a *channeldb.HTLCAttemptInfo) bool {
+			return a.SessionKey().Key.Equals(&sessionKey.Key)
+		},
+	)).Return(nil)
+	controlTower.On("SettleAttempt",
+		payHash, mock.Anything, mock.Anything,
+	).Return(testAttempt, nil)
+
+	payer.On("SendHTLC", mock.Anything, mock.Anything, mock.MatchedBy(
+		func(htlcAdd *lnwire.UpdateAddHTLC) bool {
+			return bytes.Equal(htlcAdd.OnionBlob[:], onionBlob)
+		},
+	)).Return(nil)
+
+	// Create a buffered chan and it will be returned by GetPaymentResult.
+	payer.resultChan = make(chan *htlcswitch.PaymentResult, 1)
+	payer.On("GetPaymentResult",
+		mock.Anything, mock.Anything, mock.Anything,
+	).Run(func(_ mock.Arguments) {
+		// Send a successful payment result.
+		payer.resultChan <- &htlcswitch.PaymentResult{}
+	})
+
+	missionControl.On("ReportPaymentSuccess",
+		mock.Anything, rt,
+	).Return(nil)
+
+	// Expect a successful send to route.
+	attempt, err := router.SendToRouteCustomOnion(
+		payHash, rt, false, &CustomOnion{
+			SessionKey: sessionKey,
+			OnionBlob:  onionBlob,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, testAttempt, attempt)
+
+	// Assert the above methods are called as expected.
+	controlTower.AssertExpectations(t)
+	payer.AssertExpectations(t)
+	missionControl.AssertExpectations(t)
+}
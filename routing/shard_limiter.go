@@ -0,0 +1,78 @@
+package routing
+
+import "sync"
+
+// shardLimiter limits the number of shards of a payment that are in flight at
+// the same time. The limit adapts to the outcome of the shards: it is halved
+// every time a shard fails, and grows by one every time a shard succeeds, up
+// to the configured maximum. This lets a multi-part payment launch its shards
+// in parallel while backing off when the network is failing them.
+type shardLimiter struct {
+	// maxParallelism is the upper bound of the limit. A value of zero
+	// means that the number of shards in flight isn't limited, and shards
+	// are launched one at a time.
+	maxParallelism uint32
+
+	// limit is the current number of shards that may be in flight.
+	limit uint32
+
+	mtx sync.Mutex
+}
+
+// newShardLimiter creates a new shard limiter that allows up to
+// maxParallelism shards in flight. A maxParallelism of zero disables the
+// limiter.
+func newShardLimiter(maxParallelism uint32) *shardLimiter {
+	return &shardLimiter{
+		maxParallelism: maxParallelism,
+		limit:          maxParallelism,
+	}
+}
+
+// numLaunchable returns the number of shards that may be launched in parallel
+// given the number of shards currently in flight. If the limiter is disabled,
+// a single shard may be launched at a time.
+func (l *shardLimiter) numLaunchable(numShardsInFlight int) uint32 {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.maxParallelism == 0 {
+		return 1
+	}
+
+	if uint32(numShardsInFlight) >= l.limit {
+		return 0
+	}
+
+	return l.limit - uint32(numShardsInFlight)
+}
+
+// currentLimit returns the number of shards that may currently be in flight.
+func (l *shardLimiter) currentLimit() uint32 {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	return l.limit
+}
+
+// shardSucceeded grows the limit by one after a shard was settled.
+func (l *shardLimiter) shardSucceeded() {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.limit < l.maxParallelism {
+		l.limit++
+	}
+}
+
+// shardFailed halves the limit after a shard failed, never going below a
+// single shard in flight.
+func (l *shardLimiter) shardFailed() {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.limit /= 2
+	if l.limit == 0 && l.maxParallelism != 0 {
+		l.limit = 1
+	}
+}
@@ -0,0 +1,47 @@
+package routing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestShardLimiter asserts that the shard limiter backs off when shards fail
+// and recovers as shards succeed.
+func TestShardLimiter(t *testing.T) {
+	t.Parallel()
+
+	limiter := newShardLimiter(8)
+	require.EqualValues(t, 8, limiter.currentLimit())
+	require.EqualValues(t, 8, limiter.numLaunchable(0))
+	require.EqualValues(t, 1, limiter.numLaunchable(7))
+	require.EqualValues(t, 0, limiter.numLaunchable(8))
+
+	// Every failure halves the limit, but we always allow a single shard
+	// in flight.
+	limiter.shardFailed()
+	require.EqualValues(t, 4, limiter.currentLimit())
+	require.EqualValues(t, 0, limiter.numLaunchable(4))
+
+	limiter.shardFailed()
+	limiter.shardFailed()
+	limiter.shardFailed()
+	require.EqualValues(t, 1, limiter.currentLimit())
+	require.EqualValues(t, 1, limiter.numLaunchable(0))
+	require.EqualValues(t, 0, limiter.numLaunchable(1))
+
+	// Succeeded shards grow the limit by one, up to the maximum.
+	limiter.shardSucceeded()
+	require.EqualValues(t, 2, limiter.currentLimit())
+
+	for i := 0; i < 10; i++ {
+		limiter.shardSucceeded()
+	}
+	require.EqualValues(t, 8, limiter.currentLimit())
+
+	// A limiter without a maximum launches one shard at a time, but
+	// never limits the shards in flight.
+	limiter = newShardLimiter(0)
+	limiter.shardFailed()
+	require.EqualValues(t, 1, limiter.numLaunchable(100))
+}
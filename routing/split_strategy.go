@@ -0,0 +1,86 @@
+package routing
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// SplitStrategy determines how a multi-part payment is split into shards when
+// no route is found for the amount that is left to send.
+type SplitStrategy uint8
+
+const (
+	// SplitAdaptive tries to send the remaining amount in a single shard,
+	// and halves the amount each time no route is found for it. This is
+	// the default strategy.
+	SplitAdaptive SplitStrategy = iota
+
+	// SplitEqual splits the payment into shards of equal size. Each time
+	// no route is found, the payment is split into more parts so that the
+	// shard size drops below the amount that failed.
+	SplitEqual
+
+	// SplitLargestChannel caps every shard at the largest balance that is
+	// available in any of our outgoing channels, so that shards aren't
+	// attempted that can't leave our node. If no route is found, the
+	// amount is halved as with SplitAdaptive.
+	SplitLargestChannel
+)
+
+// String returns a human readable representation of the split strategy.
+func (s SplitStrategy) String() string {
+	switch s {
+	case SplitAdaptive:
+		return "adaptive"
+
+	case SplitEqual:
+		return "equal"
+
+	case SplitLargestChannel:
+		return "largest_channel"
+
+	default:
+		return "unknown"
+	}
+}
+
+// equalShardAmt returns the size of the shards if the total amount is split
+// into the given number of equal parts. The amount is rounded up, so that the
+// parts add up to at least the total amount.
+func equalShardAmt(total lnwire.MilliSatoshi,
+	parts uint32) lnwire.MilliSatoshi {
+
+	if parts == 0 {
+		return total
+	}
+
+	return (total + lnwire.MilliSatoshi(parts) - 1) /
+		lnwire.MilliSatoshi(parts)
+}
+
+// nextEqualParts returns the number of equal parts that the total amount
+// needs to be split into for the shards to be smaller than the amount no
+// route was found for. The number of parts grows by at least one.
+func nextEqualParts(total, failedAmt lnwire.MilliSatoshi,
+	parts uint32) uint32 {
+
+	next := uint64(parts) + 1
+
+	// A shard of the total split into n parts is smaller than the failed
+	// amount if n >= total / (failedAmt - 1), rounded up.
+	if failedAmt > 1 {
+		divisor := uint64(failedAmt) - 1
+		minParts := (uint64(total) + divisor - 1) / divisor
+		if minParts > next {
+			next = minParts
+		}
+	} else {
+		// No shard can be smaller than a single millisatoshi.
+		next = uint64(total) + 1
+	}
+
+	if next > uint64(^uint32(0)) {
+		return ^uint32(0)
+	}
+
+	return uint32(next)
+}
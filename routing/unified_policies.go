@@ -22,6 +22,10 @@ type unifiedPolicies struct {
 	// outChanRestr is an optional outgoing channel restriction for the
 	// local channel to use.
 	outChanRestr map[uint64]struct{}
+
+	// chanRestr is an optional restriction on the channels that may be
+	// used. Channels for which it returns false are skipped.
+	chanRestr func(chanID uint64) bool
 }
 
 // newUnifiedPolicies instantiates a new unifiedPolicies object. Channel
@@ -51,6 +55,11 @@ func (u *unifiedPolicies) addPolicy(fromNode route.Vertex,
 		}
 	}
 
+	// Skip channels that may not be used.
+	if u.chanRestr != nil && !u.chanRestr(edge.ChannelID) {
+		return
+	}
+
 	// Update the policies map.
 	policy, ok := u.policies[fromNode]
 	if !ok {
@@ -198,6 +207,25 @@ func (u *unifiedPolicy) getPolicyLocal(amt lnwire.MilliSatoshi,
 	return bestPolicy
 }
 
+// capacity returns the largest capacity of the channels of this connection
+// that can carry the given amount. With non-strict forwarding, the routing node
+// may use any of them. Zero is returned if the capacity isn't known, which is
+// the case for channels from route hints.
+func (u *unifiedPolicy) capacity(amt lnwire.MilliSatoshi) btcutil.Amount {
+	var maxCapacity btcutil.Amount
+	for _, edge := range u.edges {
+		if !edge.amtInRange(amt) {
+			continue
+		}
+
+		if edge.capacity > maxCapacity {
+			maxCapacity = edge.capacity
+		}
+	}
+
+	return maxCapacity
+}
+
 // getPolicyNetwork returns the optimal policy to use for this connection given
 // a specific amount to send. The goal is to return a policy that maximizes the
 // probability of a successful forward in a non-strict forwarding context.
@@ -261,6 +289,21 @@ func (u *unifiedPolicy) getPolicyNetwork(
 	return &modifiedPolicy
 }
 
+// overridePolicies replaces the fees and time lock delta of all channels of
+// this connection with the values of the given override. The policies are
+// copied, so the policies known in the graph remain unchanged.
+func (u *unifiedPolicy) overridePolicies(override *HopPolicyOverride) {
+	for _, edge := range u.edges {
+		policy := *edge.policy
+		policy.FeeBaseMSat = override.FeeBaseMSat
+		policy.FeeProportionalMillionths =
+			override.FeeProportionalMillionths
+		policy.TimeLockDelta = override.TimeLockDelta
+
+		edge.policy = &policy
+	}
+}
+
 // minAmt returns the minimum amount that can be forwarded on this connection.
 func (u *unifiedPolicy) minAmt() lnwire.MilliSatoshi {
 	min := lnwire.MaxMilliSatoshi
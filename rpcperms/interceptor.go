@@ -14,6 +14,7 @@ import (
 	"github.com/lightningnetwork/lnd/monitoring"
 	"github.com/lightningnetwork/lnd/subscribe"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"gopkg.in/macaroon-bakery.v2/bakery"
 )
 
@@ -674,6 +675,20 @@ func (r *InterceptorChain) checkMacaroon(ctx context.Context,
 	return validator.ValidateMacaroon(ctx, uriPermissions, fullMethod)
 }
 
+// CheckMacaroon validates that the given hex encoded macaroon grants the
+// permissions needed to invoke the given RPC method. It is used for requests
+// that are authenticated outside of the gRPC server, such as resumed
+// WebSocket streams of the REST proxy.
+func (r *InterceptorChain) CheckMacaroon(ctx context.Context, macHex,
+	fullMethod string) error {
+
+	ctx = metadata.NewIncomingContext(
+		ctx, metadata.Pairs("macaroon", macHex),
+	)
+
+	return r.checkMacaroon(ctx, fullMethod)
+}
+
 // MacaroonUnaryServerInterceptor is a GRPC interceptor that checks whether the
 // request is authorized by the included macaroons.
 func (r *InterceptorChain) MacaroonUnaryServerInterceptor() grpc.UnaryServerInterceptor {
@@ -50,7 +50,6 @@ import (
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/labels"
-	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
@@ -362,6 +361,10 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/DryRunForceClose": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/GetInfo": {{
 			Entity: "info",
 			Action: "read",
@@ -394,10 +397,22 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/ChannelLiquidity": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ChannelDust": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/SubscribeChannelEvents": {{
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/SubscribeChannelArbitratorEvents": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/ClosedChannels": {{
 			Entity: "offchain",
 			Action: "read",
@@ -446,6 +461,10 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "info",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/DescribeGraphSlice": {{
+			Entity: "info",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/GetNodeMetrics": {{
 			Entity: "info",
 			Action: "read",
@@ -466,6 +485,10 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "info",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/GetRebroadcastSchedule": {{
+			Entity: "info",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/StopDaemon": {{
 			Entity: "info",
 			Action: "write",
@@ -486,10 +509,22 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/CleanupDuplicatePayments": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/DebugLevel": {{
 			Entity: "info",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/ReloadConfig": {{
+			Entity: "info",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/RotateTLSCert": {{
+			Entity: "info",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/DecodePayReq": {{
 			Entity: "offchain",
 			Action: "read",
@@ -576,6 +611,10 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/SubscribePeerMessages": {{
+			Entity: "peers",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/ListAliases": {{
 			Entity: "offchain",
 			Action: "read",
@@ -695,6 +734,46 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 			}
 			return info.Capacity, nil
 		},
+		FetchAmountPairCapacity: func(nodeFrom, nodeTo route.Vertex,
+			amount lnwire.MilliSatoshi) (btcutil.Amount, error) {
+
+			// Determine the largest channel from nodeFrom to nodeTo
+			// that is able to carry the amount.
+			var capacity btcutil.Amount
+			err := graph.ForEachNodeChannel(nil, nodeTo,
+				func(c *channeldb.DirectedChannel) error {
+					if c.OtherNode != nodeFrom ||
+						c.InPolicy == nil {
+
+						return nil
+					}
+
+					chanAmt := lnwire.NewMSatFromSatoshis(
+						c.Capacity,
+					)
+					if chanAmt < amount {
+						return nil
+					}
+
+					if c.Capacity > capacity {
+						capacity = c.Capacity
+					}
+
+					return nil
+				},
+			)
+			if err != nil {
+				return 0, err
+			}
+
+			if capacity == 0 {
+				return 0, fmt.Errorf("no channel from %v to "+
+					"%v can carry %v", nodeFrom, nodeTo,
+					amount)
+			}
+
+			return capacity, nil
+		},
 		FetchChannelEndpoints: func(chanID uint64) (route.Vertex,
 			route.Vertex, error) {
 
@@ -725,6 +804,9 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 			return s.chanStatusMgr.RequestDisable(outpoint, true)
 		},
 		SetChannelAuto: s.chanStatusMgr.RequestAuto,
+		SwitchDrainer:  s.htlcSwitch,
+		AddLocalAlias:  s.addLocalAlias,
+		GetAliases:     s.aliasMgr.GetAliases,
 	}
 
 	genInvoiceFeatures := func() *lnwire.FeatureVector {
@@ -759,8 +841,9 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 		s.sweeper, tower, s.towerClient, s.anchorTowerClient,
 		r.cfg.net.ResolveTCPAddr, genInvoiceFeatures,
 		genAmpInvoiceFeatures, getNodeAnnouncement,
-		s.updateAndBrodcastSelfNode, parseAddr, rpcsLog,
-		s.aliasMgr.GetPeerAlias,
+		s.updateAndBrodcastSelfNode, parseAddr, s.featureMgr.Get,
+		s.updateFeatureBits, s.authGossiper.SyncManager(), rpcsLog,
+		s.aliasMgr.GetPeerAlias, s.lspPolicies.EdgePolicy,
 	)
 	if err != nil {
 		return err
@@ -1321,9 +1404,9 @@ func (r *rpcServer) SendCoins(ctx context.Context,
 		if err == lnwallet.ErrReservedValueInvalidated {
 			sweepTxPkg.CancelSweepAttempt()
 
-			rpcsLog.Debugf("Reserved value %v not satisfied after "+
-				"send_all, trying with change output",
-				reservedVal)
+			rpcsLog.Infof("Reserved value %v not satisfied after "+
+				"send_all, keeping it in the wallet with a "+
+				"change output", reservedVal)
 
 			// We'll request a change address from the wallet,
 			// where we'll send this reserved value back to. This
@@ -1949,6 +2032,33 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 			"greater than %v", maxHtlcs, input.MaxHTLCNumber/2)
 	}
 
+	// A custom channel reserve can't be combined with not requiring a
+	// reserve at all.
+	if in.ZeroReserve && in.RemoteChanReserveSat != 0 {
+		return nil, fmt.Errorf("remote_chan_reserve_sat cannot be " +
+			"set for a zero reserve channel")
+	}
+
+	// If we ask the remote peer to contribute funds to the channel, we'll
+	// let it know our view of the current height the lease starts at.
+	var requestFunds *lnwire.RequestFunds
+	switch {
+	case in.RequestFundsSat != 0:
+		_, bestHeight, err := r.server.cc.ChainIO.GetBestBlock()
+		if err != nil {
+			return nil, err
+		}
+
+		requestFunds = &lnwire.RequestFunds{
+			RequestedAmt: btcutil.Amount(in.RequestFundsSat),
+			BlockHeight:  uint32(bestHeight),
+		}
+
+	case in.MaxLeaseFeeSat != 0:
+		return nil, fmt.Errorf("max_lease_fee_sat can only be set " +
+			"when requesting funds")
+	}
+
 	// Then, we'll extract the minimum number of confirmations that each
 	// output we use to fund the channel's funding transaction should
 	// satisfy.
@@ -2092,6 +2202,12 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 		MaxHtlcs:         maxHtlcs,
 		MaxLocalCsv:      uint16(in.MaxLocalCsv),
 		ChannelType:      channelType,
+		RemoteChanReserve: btcutil.Amount(
+			in.RemoteChanReserveSat,
+		),
+		ZeroReserve:  in.ZeroReserve,
+		RequestFunds: requestFunds,
+		MaxLeaseFee:  btcutil.Amount(in.MaxLeaseFeeSat),
 	}, nil
 }
 
@@ -2736,6 +2852,98 @@ func (r *rpcServer) AbandonChannel(_ context.Context,
 	return &lnrpc.AbandonChannelResponse{}, nil
 }
 
+// DryRunForceClose returns what a force close of the channel would broadcast at
+// its current state, without broadcasting anything or changing the state of
+// the channel.
+func (r *rpcServer) DryRunForceClose(_ context.Context,
+	in *lnrpc.DryRunForceCloseRequest) (*lnrpc.DryRunForceCloseResponse,
+	error) {
+
+	if in.ChannelPoint == nil {
+		return nil, fmt.Errorf("channel point must be specified")
+	}
+
+	txid, err := lnrpc.GetChanPointFundingTxid(in.GetChannelPoint())
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	dbChan, err := r.server.chanStateDB.FetchChannel(nil, *chanPoint)
+	if err != nil {
+		return nil, err
+	}
+	if dbChan.IsPending {
+		return nil, fmt.Errorf("channel %v is still pending", chanPoint)
+	}
+
+	// We use a fresh channel state machine from the database, the same way
+	// the chain arbitrator does when it actually force closes the channel.
+	// The state machine is only used to derive the close summary, so the
+	// channel itself is left untouched.
+	chanMachine, err := lnwallet.NewLightningChannel(
+		r.server.cc.Wallet.Cfg.Signer, dbChan, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := chanMachine.DryRunForceClose()
+	if err != nil {
+		return nil, err
+	}
+
+	localCommit := summary.ChanSnapshot.ChannelCommitment
+	closeTxid := summary.CloseTx.TxHash()
+	resp := &lnrpc.DryRunForceCloseResponse{
+		ClosingTxid:  closeTxid.String(),
+		CommitHeight: localCommit.CommitHeight,
+		CommitFeeSat: int64(localCommit.CommitFee),
+		FeePerKw:     int64(localCommit.FeePerKw),
+		CommitWeight: blockchain.GetTransactionWeight(
+			btcutil.NewTx(summary.CloseTx),
+		),
+		CsvDelay: uint32(dbChan.LocalChanCfg.CsvDelay),
+		RemoteBalanceSat: int64(
+			localCommit.RemoteBalance.ToSatoshis(),
+		),
+	}
+
+	if summary.CommitResolution != nil {
+		commitRes := summary.CommitResolution
+		resp.LocalBalanceSat = commitRes.SelfOutputSignDesc.Output.Value
+		resp.LocalOutpoint = commitRes.SelfOutPoint.String()
+	}
+
+	if summary.AnchorResolution != nil {
+		anchorRes := summary.AnchorResolution
+		anchorOutput := anchorRes.AnchorSignDescriptor.Output
+		resp.AnchorOutpoint = anchorRes.CommitAnchor.String()
+		resp.AnchorAmountSat = anchorOutput.Value
+	}
+
+	for _, htlc := range localCommit.Htlcs {
+		rpcHtlc := &lnrpc.ForceCloseHtlc{
+			Incoming:     htlc.Incoming,
+			AmountSat:    int64(htlc.Amt.ToSatoshis()),
+			Hash:         htlc.RHash[:],
+			ExpiryHeight: htlc.RefundTimeout,
+		}
+
+		// Dust htlcs don't have an output on the commitment
+		// transaction.
+		if htlc.OutputIndex >= 0 {
+			rpcHtlc.Outpoint = wire.NewOutPoint(
+				&closeTxid, uint32(htlc.OutputIndex),
+			).String()
+		}
+
+		resp.Htlcs = append(resp.Htlcs, rpcHtlc)
+	}
+
+	return resp, nil
+}
+
 // GetInfo returns general information concerning the lightning node including
 // its identity pubkey, alias, the chains it is connected to, and information
 // concerning the number of open+pending channels.
@@ -2793,7 +3001,7 @@ func (r *rpcServer) GetInfo(_ context.Context,
 		isSynced = isSynced && uint32(bestHeight) == routerHeight
 	}
 
-	network := lncfg.NormalizeNetwork(r.cfg.ActiveNetParams.Name)
+	network := r.cfg.ActiveNetwork.Name
 	activeChains := make([]*lnrpc.Chain, r.cfg.registeredChains.NumActiveChains())
 	for i, chain := range r.cfg.registeredChains.ActiveChains() {
 		activeChains[i] = &lnrpc.Chain{
@@ -2981,6 +3189,13 @@ func (r *rpcServer) ListPeers(ctx context.Context,
 			rpcPeer.Errors = append(rpcPeer.Errors, rpcErr)
 		}
 
+		// Add the peer's recent events if they were requested.
+		if in.IncludeEvents {
+			rpcPeer.Events = marshallPeerEvents(
+				serverPeer.EventLog().List(),
+			)
+		}
+
 		// If the server has started, we can query the event store
 		// for our peer's flap count. If we do so when the server has
 		// not started, the request will block.
@@ -3013,6 +3228,43 @@ func (r *rpcServer) ListPeers(ctx context.Context,
 	return resp, nil
 }
 
+// marshallPeerEvents converts the events of a peer's event log into their RPC
+// representation.
+func marshallPeerEvents(events []*peer.Event) []*lnrpc.PeerLogEvent {
+	rpcEvents := make([]*lnrpc.PeerLogEvent, 0, len(events))
+	for _, event := range events {
+		var eventType lnrpc.PeerLogEvent_EventType
+		switch event.Type {
+		case peer.EventConnected:
+			eventType = lnrpc.PeerLogEvent_CONNECTED
+
+		case peer.EventDisconnected:
+			eventType = lnrpc.PeerLogEvent_DISCONNECTED
+
+		case peer.EventReestablish:
+			eventType = lnrpc.PeerLogEvent_REESTABLISH
+
+		case peer.EventError:
+			eventType = lnrpc.PeerLogEvent_ERROR
+
+		case peer.EventFlowControlStall:
+			eventType = lnrpc.PeerLogEvent_FLOW_CONTROL_STALL
+
+		default:
+			rpcsLog.Warnf("Unknown peer event type: %v", event.Type)
+			continue
+		}
+
+		rpcEvents = append(rpcEvents, &lnrpc.PeerLogEvent{
+			TimestampNs: event.Timestamp.UnixNano(),
+			Type:        eventType,
+			Details:     event.Details,
+		})
+	}
+
+	return rpcEvents
+}
+
 // SubscribePeerEvents returns a uni-directional stream (server -> client)
 // for notifying the client of peer online and offline events.
 func (r *rpcServer) SubscribePeerEvents(req *lnrpc.PeerEventSubscription,
@@ -3822,6 +4074,13 @@ func (r *rpcServer) ClosedChannels(ctx context.Context,
 		return nil, err
 	}
 
+	// Fetch the on-chain fees of all channels at once, instead of once per
+	// channel.
+	chanFees, err := r.server.chanStateDB.FetchAllChannelFees()
+	if err != nil {
+		return nil, err
+	}
+
 	// In order to make the response easier to parse for clients, we'll
 	// sort the set of closed channels by their closing height before
 	// serializing the proto response.
@@ -3861,7 +4120,9 @@ func (r *rpcServer) ClosedChannels(ctx context.Context,
 			}
 		}
 
-		channel, err := r.createRPCClosedChannel(dbChannel)
+		channel, err := r.createRPCClosedChannel(
+			dbChannel, chanFees[dbChannel.ChanPoint],
+		)
 		if err != nil {
 			return nil, err
 		}
@@ -3902,6 +4163,13 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 	rpcsLog.Debugf("[listchannels] fetched %v channels from DB",
 		len(dbChannels))
 
+	// Fetch the on-chain fees of all channels at once, instead of once per
+	// channel.
+	chanFees, err := r.server.chanStateDB.FetchAllChannelFees()
+	if err != nil {
+		return nil, err
+	}
+
 	for _, dbChannel := range dbChannels {
 		nodePub := dbChannel.IdentityPub
 		nodePubBytes := nodePub.SerializeCompressed()
@@ -3930,7 +4198,9 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 		// Next, we'll determine whether we should add this channel to
 		// our list depending on the type of channels requested to us.
 		isActive := peerOnline && linkActive
-		channel, err := createRPCOpenChannel(r, dbChannel, isActive)
+		channel, err := createRPCOpenChannel(
+			r, dbChannel, isActive, chanFees[chanPoint],
+		)
 		if err != nil {
 			return nil, err
 		}
@@ -3954,6 +4224,151 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 	return resp, nil
 }
 
+// ChannelLiquidity returns a real-time breakdown of the liquidity of the
+// requested channel, or of all active channels if no channel is given.
+func (r *rpcServer) ChannelLiquidity(_ context.Context,
+	in *lnrpc.ChannelLiquidityRequest) (*lnrpc.ChannelLiquidityResponse,
+	error) {
+
+	var dbChannels []*channeldb.OpenChannel
+	if in.ChanPoint != nil {
+		txid, err := lnrpc.GetChanPointFundingTxid(in.ChanPoint)
+		if err != nil {
+			return nil, err
+		}
+		chanPoint := wire.NewOutPoint(txid, in.ChanPoint.OutputIndex)
+
+		dbChannel, err := r.server.chanStateDB.FetchChannel(
+			nil, *chanPoint,
+		)
+		if err != nil {
+			return nil, err
+		}
+		dbChannels = append(dbChannels, dbChannel)
+	} else {
+		var err error
+		dbChannels, err = r.server.chanStateDB.FetchAllOpenChannels()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &lnrpc.ChannelLiquidityResponse{}
+	for _, dbChannel := range dbChannels {
+		chanPoint := dbChannel.FundingOutpoint
+		chanID := lnwire.NewChanIDFromOutPoint(&chanPoint)
+
+		// The liquidity can only be inspected while the channel has an
+		// active link, as it reflects the updates in flight.
+		link, err := r.server.htlcSwitch.GetLink(chanID)
+		if err != nil {
+			if in.ChanPoint != nil {
+				return nil, fmt.Errorf("channel %v has no active "+
+					"link: %w", chanPoint, err)
+			}
+
+			continue
+		}
+
+		snapshot := link.LiquiditySnapshot()
+		resp.Channels = append(resp.Channels, &lnrpc.ChannelLiquidity{
+			ChannelPoint:      chanPoint.String(),
+			ChanId:            dbChannel.ShortChannelID.ToUint64(),
+			LocalBalanceMsat:  uint64(snapshot.LocalBalance),
+			RemoteBalanceMsat: uint64(snapshot.RemoteBalance),
+			SpendableMsat:     uint64(snapshot.Spendable),
+			ReceivableMsat:    uint64(snapshot.Receivable),
+			PendingOutgoing: &lnrpc.HtlcTotals{
+				NumHtlcs: uint32(
+					snapshot.PendingOutgoing.NumHtlcs,
+				),
+				AmountMsat: uint64(
+					snapshot.PendingOutgoing.Amount,
+				),
+			},
+			PendingIncoming: &lnrpc.HtlcTotals{
+				NumHtlcs: uint32(
+					snapshot.PendingIncoming.NumHtlcs,
+				),
+				AmountMsat: uint64(
+					snapshot.PendingIncoming.Amount,
+				),
+			},
+			CommitFeeSat:     int64(snapshot.CommitFee),
+			FeeBufferSat:     int64(snapshot.FeeBuffer),
+			LocalReserveSat:  int64(snapshot.LocalReserve),
+			RemoteReserveSat: int64(snapshot.RemoteReserve),
+		})
+	}
+
+	return resp, nil
+}
+
+// ChannelDust reports the dust limits, the HTLC trimming thresholds and the
+// value trimmed to dust of both commitments of the requested channel, or of
+// all open channels if no channel is given.
+func (r *rpcServer) ChannelDust(_ context.Context,
+	in *lnrpc.ChannelDustRequest) (*lnrpc.ChannelDustResponse, error) {
+
+	var dbChannels []*channeldb.OpenChannel
+	if in.ChanPoint != nil {
+		txid, err := lnrpc.GetChanPointFundingTxid(in.ChanPoint)
+		if err != nil {
+			return nil, err
+		}
+		chanPoint := wire.NewOutPoint(txid, in.ChanPoint.OutputIndex)
+
+		dbChannel, err := r.server.chanStateDB.FetchChannel(
+			nil, *chanPoint,
+		)
+		if err != nil {
+			return nil, err
+		}
+		dbChannels = append(dbChannels, dbChannel)
+	} else {
+		var err error
+		dbChannels, err = r.server.chanStateDB.FetchAllOpenChannels()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dustThreshold := lnwire.NewMSatFromSatoshis(
+		btcutil.Amount(r.cfg.DustThreshold),
+	)
+	resp := &lnrpc.ChannelDustResponse{
+		DustThresholdMsat: uint64(dustThreshold),
+	}
+	for _, dbChannel := range dbChannels {
+		dustInfo := lnwallet.NewChannelDustInfo(dbChannel)
+
+		resp.Channels = append(resp.Channels, &lnrpc.ChannelDust{
+			ChannelPoint:     dbChannel.FundingOutpoint.String(),
+			ChanId:           dbChannel.ShortChannelID.ToUint64(),
+			CommitmentType:   rpcCommitmentType(dbChannel.ChanType),
+			LocalCommitment:  marshallCommitDust(dustInfo.Local),
+			RemoteCommitment: marshallCommitDust(dustInfo.Remote),
+		})
+	}
+
+	return resp, nil
+}
+
+// marshallCommitDust converts the dust of a commitment into its rpc
+// representation.
+func marshallCommitDust(info *lnwallet.CommitDustInfo) *lnrpc.CommitmentDust {
+	return &lnrpc.CommitmentDust{
+		DustLimitSat:             int64(info.DustLimit),
+		FeePerKw:                 int64(info.FeePerKw),
+		IncomingHtlcThresholdSat: int64(info.IncomingHtlcThreshold),
+		OutgoingHtlcThresholdSat: int64(info.OutgoingHtlcThreshold),
+		NumDustHtlcs:             uint32(info.NumDustHtlcs),
+		DustHtlcMsat:             uint64(info.DustHtlcAmount),
+		TrimmedBalanceMsat:       uint64(info.TrimmedBalance),
+		ExposureMsat:             uint64(info.Exposure()),
+	}
+}
+
 // rpcCommitmentType takes the channel type and converts it to an rpc commitment
 // type value.
 func rpcCommitmentType(chanType channeldb.ChannelType) lnrpc.CommitmentType {
@@ -4001,7 +4416,8 @@ func isPrivate(dbChannel *channeldb.OpenChannel) bool {
 
 // createRPCOpenChannel creates an *lnrpc.Channel from the *channeldb.Channel.
 func createRPCOpenChannel(r *rpcServer, dbChannel *channeldb.OpenChannel,
-	isActive bool) (*lnrpc.Channel, error) {
+	isActive bool, chanFees *channeldb.ChannelFees) (*lnrpc.Channel,
+	error) {
 
 	nodePub := dbChannel.IdentityPub
 	nodeID := hex.EncodeToString(nodePub.SerializeCompressed())
@@ -4171,6 +4587,8 @@ func createRPCOpenChannel(r *rpcServer, dbChannel *channeldb.OpenChannel,
 		channel.CloseAddress = addresses[0].String()
 	}
 
+	channel.OnChainFees = marshalChannelFees(chanFees)
+
 	// If the server hasn't fully started yet, it's possible that the
 	// channel event store hasn't either, so it won't be able to consume any
 	// requests until then. To prevent blocking, we'll just omit the uptime
@@ -4209,10 +4627,29 @@ func createRPCOpenChannel(r *rpcServer, dbChannel *channeldb.OpenChannel,
 	return channel, nil
 }
 
+// marshalChannelFees converts the on-chain fees recorded for a channel into
+// their RPC counterpart. A nil value means that no fees were recorded.
+func marshalChannelFees(
+	fees *channeldb.ChannelFees) *lnrpc.ChannelOnChainFees {
+
+	if fees == nil {
+		fees = &channeldb.ChannelFees{}
+	}
+
+	return &lnrpc.ChannelOnChainFees{
+		FundingFeeSat:          int64(fees.Funding),
+		CommitmentFeeSat:       int64(fees.Commitment),
+		CooperativeCloseFeeSat: int64(fees.CooperativeClose),
+		SweepFeeSat:            int64(fees.Sweep),
+		TotalFeeSat:            int64(fees.Total()),
+	}
+}
+
 // createRPCClosedChannel creates an *lnrpc.ClosedChannelSummary from a
 // *channeldb.ChannelCloseSummary.
 func (r *rpcServer) createRPCClosedChannel(
-	dbChannel *channeldb.ChannelCloseSummary) (*lnrpc.ChannelCloseSummary, error) {
+	dbChannel *channeldb.ChannelCloseSummary,
+	chanFees *channeldb.ChannelFees) (*lnrpc.ChannelCloseSummary, error) {
 
 	nodePub := dbChannel.RemotePub
 	nodeID := hex.EncodeToString(nodePub.SerializeCompressed())
@@ -4277,6 +4714,8 @@ func (r *rpcServer) createRPCClosedChannel(
 		)
 	}
 
+	channel.OnChainFees = marshalChannelFees(chanFees)
+
 	// Populate any historical data that the summary needs.
 	histChan, err := r.server.chanStateDB.FetchHistoricalChannel(
 		&dbChannel.ChanPoint,
@@ -4488,8 +4927,16 @@ func (r *rpcServer) SubscribeChannelEvents(req *lnrpc.ChannelEventSubscription,
 					},
 				}
 			case channelnotifier.OpenChannelEvent:
+				chanDB := r.server.chanStateDB
+				chanFees, err := chanDB.FetchChannelFees(
+					event.Channel.FundingOutpoint,
+				)
+				if err != nil {
+					return err
+				}
+
 				channel, err := createRPCOpenChannel(
-					r, event.Channel, true,
+					r, event.Channel, true, chanFees,
 				)
 				if err != nil {
 					return err
@@ -4503,8 +4950,16 @@ func (r *rpcServer) SubscribeChannelEvents(req *lnrpc.ChannelEventSubscription,
 				}
 
 			case channelnotifier.ClosedChannelEvent:
+				chanDB := r.server.chanStateDB
+				chanFees, err := chanDB.FetchChannelFees(
+					event.CloseSummary.ChanPoint,
+				)
+				if err != nil {
+					return err
+				}
+
 				closedChannel, err := r.createRPCClosedChannel(
-					event.CloseSummary,
+					event.CloseSummary, chanFees,
 				)
 				if err != nil {
 					return err
@@ -4584,6 +5039,112 @@ func (r *rpcServer) SubscribeChannelEvents(req *lnrpc.ChannelEventSubscription,
 	}
 }
 
+// SubscribeChannelArbitratorEvents returns a uni-directional stream (server ->
+// client) for notifying the client of the state transitions of our channel
+// arbitrators.
+func (r *rpcServer) SubscribeChannelArbitratorEvents(
+	req *lnrpc.ChannelArbitratorEventSubscription,
+	updateStream lnrpc.Lightning_SubscribeChannelArbitratorEventsServer) error {
+
+	arbSub, err := r.server.chainArb.SubscribeArbitratorUpdates()
+	if err != nil {
+		return err
+	}
+
+	// Ensure that the resources for the client is cleaned up once either
+	// the server, or client exits.
+	defer arbSub.Cancel()
+
+	for {
+		select {
+		case e := <-arbSub.Updates():
+			update, ok := e.(*contractcourt.ArbitratorStateUpdate)
+			if !ok {
+				return fmt.Errorf("unexpected arbitrator event "+
+					"update: %v", e)
+			}
+
+			if err := updateStream.Send(
+				marshallArbitratorEvent(update),
+			); err != nil {
+				return err
+			}
+
+		case <-arbSub.Quit():
+			return errors.New("channel arbitrator event " +
+				"subscription terminated")
+
+		// The response stream's context for whatever reason has been
+		// closed. If context is closed by an exceeded deadline we will
+		// return an error.
+		case <-updateStream.Context().Done():
+			if errors.Is(updateStream.Context().Err(), context.Canceled) {
+				return nil
+			}
+			return updateStream.Context().Err()
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// marshallArbitratorEvent converts a channel arbitrator state update into its
+// RPC counterpart.
+func marshallArbitratorEvent(update *contractcourt.ArbitratorStateUpdate) (
+	event *lnrpc.ChannelArbitratorEvent) {
+
+	event = &lnrpc.ChannelArbitratorEvent{
+		ChannelPoint: &lnrpc.ChannelPoint{
+			FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+				FundingTxidBytes: update.ChanPoint.Hash[:],
+			},
+			OutputIndex: update.ChanPoint.Index,
+		},
+		PrevState:     marshallArbitratorState(update.PrevState),
+		NewState:      marshallArbitratorState(update.NewState),
+		TriggerHeight: update.TriggerHeight,
+		Reason:        update.Reason,
+	}
+	if update.CloseTxid != nil {
+		event.ClosingTxid = update.CloseTxid.String()
+	}
+	if update.BroadcastErr != nil {
+		event.BroadcastError = update.BroadcastErr.Error()
+	}
+
+	return event
+}
+
+// marshallArbitratorState converts a channel arbitrator state into its RPC
+// counterpart.
+func marshallArbitratorState(state contractcourt.ArbitratorState) (
+	rpcState lnrpc.ChannelArbitratorEvent_ArbitratorState) {
+
+	switch state {
+	case contractcourt.StateBroadcastCommit:
+		return lnrpc.ChannelArbitratorEvent_BROADCAST_COMMIT
+
+	case contractcourt.StateCommitmentBroadcasted:
+		return lnrpc.ChannelArbitratorEvent_COMMITMENT_BROADCASTED
+
+	case contractcourt.StateContractClosed:
+		return lnrpc.ChannelArbitratorEvent_CONTRACT_CLOSED
+
+	case contractcourt.StateWaitingFullResolution:
+		return lnrpc.ChannelArbitratorEvent_WAITING_FULL_RESOLUTION
+
+	case contractcourt.StateFullyResolved:
+		return lnrpc.ChannelArbitratorEvent_FULLY_RESOLVED
+
+	case contractcourt.StateError:
+		return lnrpc.ChannelArbitratorEvent_ERROR
+
+	default:
+		return lnrpc.ChannelArbitratorEvent_DEFAULT
+	}
+}
+
 // paymentStream enables different types of payment streams, such as:
 // lnrpc.Lightning_SendPaymentServer and lnrpc.Lightning_SendToRouteServer to
 // execute sendPayment. We use this struct as a sort of bridge to enable code
@@ -4605,6 +5166,12 @@ type rpcPaymentRequest struct {
 // bi-directional stream allowing clients to rapidly send payments through the
 // Lightning Network with a single persistent connection.
 func (r *rpcServer) SendPayment(stream lnrpc.Lightning_SendPaymentServer) error {
+	// The streaming variant can't account for the amounts spent, so it
+	// can't be used with spend limited macaroons.
+	if macaroons.HasSpendLimit(stream.Context()) {
+		return macaroons.ErrSpendLimitUnsupported
+	}
+
 	var lock sync.Mutex
 
 	return r.sendPayment(&paymentStream{
@@ -4633,6 +5200,12 @@ func (r *rpcServer) SendPayment(stream lnrpc.Lightning_SendPaymentServer) error
 // rapidly send payments through the Lightning Network with a single persistent
 // connection.
 func (r *rpcServer) SendToRoute(stream lnrpc.Lightning_SendToRouteServer) error {
+	// The streaming variant can't account for the amounts spent, so it
+	// can't be used with spend limited macaroons.
+	if macaroons.HasSpendLimit(stream.Context()) {
+		return macaroons.ErrSpendLimitUnsupported
+	}
+
 	var lock sync.Mutex
 
 	return r.sendPayment(&paymentStream{
@@ -5274,6 +5847,23 @@ func (r *rpcServer) SendToRouteSync(ctx context.Context,
 	return r.sendPaymentSync(ctx, paymentRequest)
 }
 
+// reserveSpend counts the maximum amount the given payment intent can cost
+// against the spend limits of the macaroon used for the call, if any.
+func (r *rpcServer) reserveSpend(ctx context.Context,
+	payIntent *rpcPaymentIntent) (*macaroons.SpendReservation, error) {
+
+	if r.macService == nil {
+		return nil, nil
+	}
+
+	amt := payIntent.msat + payIntent.feeLimit
+	if payIntent.route != nil {
+		amt = payIntent.route.TotalAmount
+	}
+
+	return r.macService.ReserveSpend(ctx, amt)
+}
+
 // sendPaymentSync is the synchronous variant of sendPayment. It will block and
 // wait until the payment has been fully completed.
 func (r *rpcServer) sendPaymentSync(ctx context.Context,
@@ -5293,20 +5883,38 @@ func (r *rpcServer) sendPaymentSync(ctx context.Context,
 		return nil, err
 	}
 
+	// If the macaroon used for this call has a spend limit, we'll count
+	// the maximum amount this payment can cost against it.
+	reservation, err := r.reserveSpend(ctx, &payIntent)
+	if err != nil {
+		return nil, err
+	}
+
 	// With the payment validated, we'll now attempt to dispatch the
 	// payment.
 	resp, saveErr := r.dispatchPaymentIntent(&payIntent)
 	switch {
+	// If we don't know whether the payment went out, the full amount
+	// remains reserved.
 	case saveErr != nil:
 		return nil, saveErr
 
 	case resp.Err != nil:
+		if err := reservation.Cancel(); err != nil {
+			rpcsLog.Errorf("Unable to cancel spend reservation: %v",
+				err)
+		}
+
 		return &lnrpc.SendResponse{
 			PaymentError: resp.Err.Error(),
 			PaymentHash:  payIntent.rHash[:],
 		}, nil
 	}
 
+	if err := reservation.Settle(resp.Route.TotalAmount); err != nil {
+		rpcsLog.Errorf("Unable to settle spend reservation: %v", err)
+	}
+
 	rpcRoute, err := r.routerBackend.MarshallRoute(resp.Route)
 	if err != nil {
 		return nil, err
@@ -5344,7 +5952,8 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		GenAmpInvoiceFeatures: func() *lnwire.FeatureVector {
 			return r.server.featureMgr.Get(feature.SetInvoiceAmp)
 		},
-		GetAlias: r.server.aliasMgr.GetPeerAlias,
+		GetAlias:      r.server.aliasMgr.GetPeerAlias,
+		LearnedPolicy: r.server.lspPolicies.EdgePolicy,
 	}
 
 	value, err := lnrpc.UnmarshallAmt(invoice.Value, invoice.ValueMsat)
@@ -5610,7 +6219,40 @@ func (r *rpcServer) GetTransactions(ctx context.Context,
 		return nil, err
 	}
 
-	return lnrpc.RPCTransactionDetails(transactions), nil
+	// Look up the labels that were set for the outputs of the
+	// transactions with SendOutputs.
+	txs := make([]*wire.MsgTx, 0, len(transactions))
+	for _, tx := range transactions {
+		msgTx := &wire.MsgTx{}
+		err := msgTx.Deserialize(bytes.NewReader(tx.RawTx))
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode tx %v: %v",
+				tx.Hash, err)
+		}
+		txs = append(txs, msgTx)
+	}
+
+	outputLabels, err := r.server.chanStateDB.FetchOutputLabels(txs...)
+	if err != nil {
+		return nil, err
+	}
+
+	txDetails := lnrpc.RPCTransactionDetails(transactions)
+	for _, tx := range txDetails.Transactions {
+		txHash, err := chainhash.NewHashFromStr(tx.TxHash)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, output := range tx.OutputDetails {
+			output.Label = outputLabels[wire.OutPoint{
+				Hash:  *txHash,
+				Index: uint32(output.OutputIndex),
+			}]
+		}
+	}
+
+	return txDetails, nil
 }
 
 // DescribeGraph returns a description of the latest graph state from the PoV
@@ -5648,25 +6290,7 @@ func (r *rpcServer) DescribeGraph(ctx context.Context,
 	// within the graph), collating their current state into the RPC
 	// response.
 	err := graph.ForEachNode(func(_ kvdb.RTx, node *channeldb.LightningNode) error {
-		nodeAddrs := make([]*lnrpc.NodeAddress, 0)
-		for _, addr := range node.Addresses {
-			nodeAddr := &lnrpc.NodeAddress{
-				Network: addr.Network(),
-				Addr:    addr.String(),
-			}
-			nodeAddrs = append(nodeAddrs, nodeAddr)
-		}
-
-		lnNode := &lnrpc.LightningNode{
-			LastUpdate: uint32(node.LastUpdate.Unix()),
-			PubKey:     hex.EncodeToString(node.PubKeyBytes[:]),
-			Addresses:  nodeAddrs,
-			Alias:      node.Alias,
-			Color:      routing.EncodeHexColor(node.Color),
-			Features:   invoicesrpc.CreateRPCFeatures(node.Features),
-		}
-
-		resp.Nodes = append(resp.Nodes, lnNode)
+		resp.Nodes = append(resp.Nodes, marshalDbNode(node))
 
 		return nil
 	})
@@ -5707,6 +6331,77 @@ func (r *rpcServer) DescribeGraph(ctx context.Context,
 	return resp, nil
 }
 
+// DescribeGraphSlice returns the nodes within a number of hops of the
+// requested nodes, and the channels between them above a minimum capacity.
+// This allows light clients to sync only the part of the graph they need.
+func (r *rpcServer) DescribeGraphSlice(ctx context.Context,
+	req *lnrpc.GraphSliceRequest) (*lnrpc.ChannelGraph, error) {
+
+	if len(req.PubKeys) == 0 {
+		return nil, fmt.Errorf("at least one node must be specified")
+	}
+	if req.MinCapacitySat < 0 {
+		return nil, fmt.Errorf("min capacity must not be negative")
+	}
+
+	query := &discovery.GraphSliceQuery{
+		MaxHops:            req.MaxHops,
+		MinCapacity:        btcutil.Amount(req.MinCapacitySat),
+		IncludeUnannounced: req.IncludeUnannounced,
+	}
+	if query.MaxHops == 0 {
+		query.MaxHops = discovery.DefaultGraphSliceHops
+	}
+	for _, pubKey := range req.PubKeys {
+		vertex, err := route.NewVertexFromStr(pubKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %v: %v",
+				pubKey, err)
+		}
+		query.Roots = append(query.Roots, vertex)
+	}
+
+	slice, err := discovery.FetchGraphSlice(r.server.graphDB, query)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ChannelGraph{}
+	for _, node := range slice.Nodes {
+		resp.Nodes = append(resp.Nodes, marshalDbNode(node))
+	}
+	for _, channel := range slice.Channels {
+		edge := marshalDbEdge(
+			channel.Info, channel.Policy1, channel.Policy2,
+		)
+		resp.Edges = append(resp.Edges, edge)
+	}
+
+	return resp, nil
+}
+
+// marshalDbNode converts a node of the channel graph into its RPC
+// representation.
+func marshalDbNode(node *channeldb.LightningNode) *lnrpc.LightningNode {
+	nodeAddrs := make([]*lnrpc.NodeAddress, 0)
+	for _, addr := range node.Addresses {
+		nodeAddr := &lnrpc.NodeAddress{
+			Network: addr.Network(),
+			Addr:    addr.String(),
+		}
+		nodeAddrs = append(nodeAddrs, nodeAddr)
+	}
+
+	return &lnrpc.LightningNode{
+		LastUpdate: uint32(node.LastUpdate.Unix()),
+		PubKey:     hex.EncodeToString(node.PubKeyBytes[:]),
+		Addresses:  nodeAddrs,
+		Alias:      node.Alias,
+		Color:      routing.EncodeHexColor(node.Color),
+		Features:   invoicesrpc.CreateRPCFeatures(node.Features),
+	}
+}
+
 func marshalDbEdge(edgeInfo *channeldb.ChannelEdgeInfo,
 	c1, c2 *channeldb.ChannelEdgePolicy) *lnrpc.ChannelEdge {
 
@@ -6082,6 +6777,42 @@ func (r *rpcServer) GetNetworkInfo(ctx context.Context,
 	return netInfo, nil
 }
 
+// GetRebroadcastSchedule returns the time at which the channel update of each
+// of our public channels is next refreshed and rebroadcast to the network.
+func (r *rpcServer) GetRebroadcastSchedule(_ context.Context,
+	_ *lnrpc.RebroadcastScheduleRequest) (
+	*lnrpc.RebroadcastScheduleResponse, error) {
+
+	schedule, err := r.server.authGossiper.RebroadcastSchedule()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.RebroadcastScheduleResponse{
+		Channels: make(
+			[]*lnrpc.ScheduledRebroadcast, 0, len(schedule),
+		),
+	}
+	for _, rebroadcast := range schedule {
+		rpcRebroadcast := &lnrpc.ScheduledRebroadcast{
+			ChanId:          rebroadcast.ChannelID.ToUint64(),
+			ChanPoint:       rebroadcast.ChannelPoint.String(),
+			LastUpdate:      rebroadcast.LastUpdate.Unix(),
+			NextRebroadcast: rebroadcast.NextRebroadcast.Unix(),
+		}
+		resp.Channels = append(resp.Channels, rpcRebroadcast)
+	}
+
+	// Order the channels by their next rebroadcast so the channels that
+	// are refreshed first are listed first.
+	sort.Slice(resp.Channels, func(i, j int) bool {
+		return resp.Channels[i].NextRebroadcast <
+			resp.Channels[j].NextRebroadcast
+	})
+
+	return resp, nil
+}
+
 // StopDaemon will send a shutdown request to the interrupt handler, triggering
 // a graceful shutdown of the daemon.
 func (r *rpcServer) StopDaemon(_ context.Context,
@@ -6342,6 +7073,29 @@ func (r *rpcServer) DeleteAllPayments(ctx context.Context,
 	return &lnrpc.DeleteAllPaymentsResponse{}, nil
 }
 
+// CleanupDuplicatePayments deletes the failed and incomplete legacy duplicate
+// payments and dangling payment index entries from the DB.
+func (r *rpcServer) CleanupDuplicatePayments(ctx context.Context,
+	req *lnrpc.CleanupDuplicatePaymentsRequest) (
+	*lnrpc.CleanupDuplicatePaymentsResponse, error) {
+
+	rpcsLog.Infof("[CleanupDuplicatePayments] dry_run=%v", req.DryRun)
+
+	report, err := r.server.miscDB.CleanupDuplicatePayments(req.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.CleanupDuplicatePaymentsResponse{
+		NumPaymentsWithDuplicates: report.NumPaymentsWithDuplicates,
+		NumDuplicates:             report.NumDuplicates,
+		NumDuplicatesDeleted:      report.NumDuplicatesDeleted,
+		NumDuplicatesKept:         report.NumDuplicatesKept,
+		NumDanglingIndexes:        report.NumDanglingIndexes,
+		ReclaimedBytes:            report.ReclaimedBytes,
+	}, nil
+}
+
 // DebugLevel allows a caller to programmatically set the logging verbosity of
 // lnd. The logging can be targeted according to a coarse daemon-wide logging
 // level, or in a granular fashion to specify the logging for a target
@@ -6371,6 +7125,57 @@ func (r *rpcServer) DebugLevel(ctx context.Context,
 	return &lnrpc.DebugLevelResponse{}, nil
 }
 
+// ReloadConfig re-reads lnd.conf and applies the options that can safely be
+// changed while lnd is running. The options that were changed are returned.
+func (r *rpcServer) ReloadConfig(ctx context.Context,
+	_ *lnrpc.ReloadConfigRequest) (*lnrpc.ReloadConfigResponse, error) {
+
+	rpcsLog.Infof("[reloadconfig] reloading config")
+
+	changes, err := r.server.reloadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ReloadConfigResponse{
+		Changes: make([]*lnrpc.ConfigChange, 0, len(changes)),
+	}
+	for _, change := range changes {
+		resp.Changes = append(resp.Changes, &lnrpc.ConfigChange{
+			Option:   change.option,
+			OldValue: change.oldValue,
+			NewValue: change.newValue,
+		})
+	}
+
+	return resp, nil
+}
+
+// RotateTLSCert regenerates the TLS certificate and key of the RPC server and
+// starts serving the new certificate without a restart.
+func (r *rpcServer) RotateTLSCert(ctx context.Context,
+	_ *lnrpc.RotateTLSCertRequest) (*lnrpc.RotateTLSCertResponse, error) {
+
+	rpcsLog.Infof("[rotatetlscert] rotating TLS certificate")
+
+	newCert, err := r.server.tlsManager.Rotate()
+	if err != nil {
+		return nil, fmt.Errorf("unable to rotate TLS certificate: %w",
+			err)
+	}
+
+	ipAddresses := make([]string, 0, len(newCert.IPAddresses))
+	for _, ip := range newCert.IPAddresses {
+		ipAddresses = append(ipAddresses, ip.String())
+	}
+
+	return &lnrpc.RotateTLSCertResponse{
+		Expiry:      newCert.NotAfter.Unix(),
+		DnsNames:    newCert.DNSNames,
+		IpAddresses: ipAddresses,
+	}, nil
+}
+
 // DecodePayReq takes an encoded payment request string and attempts to decode
 // it, returning a full description of the conditions encoded within the
 // payment request.
@@ -7474,6 +8279,28 @@ func (r *rpcServer) FundingStateStep(ctx context.Context,
 			return nil, fmt.Errorf("error parsing psbt: %v", err)
 		}
 
+		// If the PSBT funds additional channels, all of them are
+		// verified together.
+		additionalIDs := in.GetPsbtVerify().AdditionalPendingChanIds
+		if len(additionalIDs) > 0 {
+			pendingChanIDs, err := parsePendingChanIDs(
+				pendingChanID, additionalIDs,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			err = r.server.cc.Wallet.PsbtFundingVerifyBatch(
+				pendingChanIDs, packet,
+				in.GetPsbtVerify().SkipFinalize,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			break
+		}
+
 		err = r.server.cc.Wallet.PsbtFundingVerify(
 			pendingChanID, packet, in.GetPsbtVerify().SkipFinalize,
 		)
@@ -7527,6 +8354,26 @@ func (r *rpcServer) FundingStateStep(ctx context.Context,
 				"finalize missing")
 		}
 
+		// If the transaction funds additional channels, all of them are
+		// finalized together.
+		if len(msg.AdditionalPendingChanIds) > 0 {
+			pendingChanIDs, err := parsePendingChanIDs(
+				pendingChanID, msg.AdditionalPendingChanIds,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			err = r.server.cc.Wallet.PsbtFundingFinalizeBatch(
+				pendingChanIDs, packet, rawTx,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			break
+		}
+
 		err = r.server.cc.Wallet.PsbtFundingFinalize(
 			pendingChanID, packet, rawTx,
 		)
@@ -7542,6 +8389,27 @@ func (r *rpcServer) FundingStateStep(ctx context.Context,
 	return &lnrpc.FundingStateStepResp{}, nil
 }
 
+// parsePendingChanIDs returns the list of pending channel IDs of a PSBT that
+// funds several channels, starting with the given primary one.
+func parsePendingChanIDs(primaryID [32]byte,
+	additionalIDs [][]byte) ([][32]byte, error) {
+
+	pendingChanIDs := make([][32]byte, 0, len(additionalIDs)+1)
+	pendingChanIDs = append(pendingChanIDs, primaryID)
+	for _, rawID := range additionalIDs {
+		if len(rawID) != 32 {
+			return nil, fmt.Errorf("invalid pending channel ID "+
+				"length %d, expected 32", len(rawID))
+		}
+
+		var pendingChanID [32]byte
+		copy(pendingChanID[:], rawID)
+		pendingChanIDs = append(pendingChanIDs, pendingChanID)
+	}
+
+	return pendingChanIDs, nil
+}
+
 // RegisterRPCMiddleware adds a new gRPC middleware to the interceptor chain. A
 // gRPC middleware is software component external to lnd that aims to add
 // additional business logic to lnd by observing/intercepting/validating
@@ -7723,10 +8591,97 @@ func (r *rpcServer) SubscribeCustomMessages(req *lnrpc.SubscribeCustomMessagesRe
 		case update := <-client.Updates():
 			customMsg := update.(*CustomMessage)
 
+			// Typed messages of registered custom types are
+			// serialized back into their raw payload.
+			var data []byte
+			switch msg := customMsg.Msg.(type) {
+			case *lnwire.Custom:
+				data = msg.Data
+
+			default:
+				var b bytes.Buffer
+				if err := msg.Encode(&b, 0); err != nil {
+					rpcsLog.Errorf("Unable to encode custom "+
+						"message type %d: %v",
+						msg.MsgType(), err)
+					continue
+				}
+				data = b.Bytes()
+			}
+
 			err := server.Send(&lnrpc.CustomMessage{
 				Peer: customMsg.Peer[:],
-				Data: customMsg.Msg.Data,
-				Type: uint32(customMsg.Msg.Type),
+				Data: data,
+				Type: uint32(customMsg.Msg.MsgType()),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// SubscribePeerMessages subscribes to a debug stream of summaries of the wire
+// messages exchanged with our peers.
+func (r *rpcServer) SubscribePeerMessages(
+	req *lnrpc.SubscribePeerMessagesRequest,
+	server lnrpc.Lightning_SubscribePeerMessagesServer) error {
+
+	// Build the filter up front, so that messages are only summarized
+	// if we're going to send them. An empty filter matches everything.
+	filter := peer.MessageFilter{
+		Peers: make(map[[33]byte]struct{}, len(req.Peers)),
+		MsgTypes: make(
+			map[lnwire.MessageType]struct{}, len(req.MsgTypes),
+		),
+		SampleRate: uint64(req.SampleRate),
+	}
+	for _, rawPeer := range req.Peers {
+		peerPub, err := route.NewVertexFromBytes(rawPeer)
+		if err != nil {
+			return err
+		}
+		filter.Peers[peerPub] = struct{}{}
+	}
+
+	for _, msgType := range req.MsgTypes {
+		if msgType > math.MaxUint16 {
+			return fmt.Errorf("invalid message type: %v", msgType)
+		}
+		filter.MsgTypes[lnwire.MessageType(msgType)] = struct{}{}
+	}
+
+	sub := r.server.SubscribePeerMessages(filter)
+	defer sub.Cancel()
+
+	for {
+		select {
+		case <-sub.Quit():
+			return errors.New("shutdown")
+
+		case <-server.Context().Done():
+			return server.Context().Err()
+
+		case update := <-sub.Updates():
+			peerMsg := update.(*peer.WireMessage)
+
+			direction := lnrpc.PeerMessage_OUTBOUND
+			if peerMsg.Inbound {
+				direction = lnrpc.PeerMessage_INBOUND
+			}
+
+			typeName := "custom"
+			if peerMsg.MsgType < lnwire.CustomTypeStart {
+				typeName = peerMsg.MsgType.String()
+			}
+
+			err := server.Send(&lnrpc.PeerMessage{
+				Peer:      peerMsg.Peer[:],
+				Direction: direction,
+				Type:      uint32(peerMsg.MsgType),
+				TypeName:  typeName,
+				Size:      peerMsg.Size,
+				Summary:   peerMsg.Summary,
 			})
 			if err != nil {
 				return err
@@ -7744,16 +8699,33 @@ func (r *rpcServer) ListAliases(ctx context.Context,
 	// Fetch the map of all aliases.
 	mapAliases := r.server.aliasMgr.ListAliases()
 
-	// Fill out the response. This does not include the zero-conf confirmed
-	// SCID. Doing so would require more database lookups and it can be
-	// cross-referenced with the output of listchannels/closedchannels.
+	// For open zero-conf channels that have confirmed, the base SCID is an
+	// alias itself, so we'll look up the confirmed SCID its aliases map
+	// to. Closed channels can be cross-referenced with the output of
+	// closedchannels.
+	openChannels, err := r.server.chanStateDB.FetchAllOpenChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	confirmedScids := make(map[lnwire.ShortChannelID]lnwire.ShortChannelID)
+	for _, dbChan := range openChannels {
+		if !dbChan.IsZeroConf() || !dbChan.ZeroConfConfirmed() {
+			continue
+		}
+
+		confirmedScids[dbChan.ShortChanID()] = dbChan.ZeroConfRealScid()
+	}
+
+	// Fill out the response.
 	resp := &lnrpc.ListAliasesResponse{
 		AliasMaps: make([]*lnrpc.AliasMap, 0),
 	}
 
 	for base, set := range mapAliases {
 		rpcMap := &lnrpc.AliasMap{
-			BaseScid: base.ToUint64(),
+			BaseScid:      base.ToUint64(),
+			ConfirmedScid: confirmedScids[base].ToUint64(),
 		}
 		for _, alias := range set {
 			rpcMap.Aliases = append(
@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"crypto/rand"
+	"crypto/tls"
 	"encoding/hex"
 	"fmt"
 	"math/big"
 	prand "math/rand"
 	"net"
+	"net/http"
 	"strconv"
 	"strings"
 	"sync"
@@ -18,6 +20,7 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/connmgr"
 	"github.com/btcsuite/btcd/txscript"
@@ -26,7 +29,9 @@ import (
 	sphinx "github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/aliasmgr"
 	"github.com/lightningnetwork/lnd/autopilot"
+	"github.com/lightningnetwork/lnd/breachnotify"
 	"github.com/lightningnetwork/lnd/brontide"
+	"github.com/lightningnetwork/lnd/brontide/ws"
 	"github.com/lightningnetwork/lnd/cert"
 	"github.com/lightningnetwork/lnd/chainreg"
 	"github.com/lightningnetwork/lnd/chanacceptor"
@@ -52,8 +57,10 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwallet/chancloser"
 	"github.com/lightningnetwork/lnd/lnwallet/rpcwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/monitoring"
 	"github.com/lightningnetwork/lnd/nat"
 	"github.com/lightningnetwork/lnd/netann"
 	"github.com/lightningnetwork/lnd/peer"
@@ -62,14 +69,18 @@ import (
 	"github.com/lightningnetwork/lnd/queue"
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/routing/localchans"
+	"github.com/lightningnetwork/lnd/routing/lsppolicy"
 	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/subscribe"
 	"github.com/lightningnetwork/lnd/sweep"
 	"github.com/lightningnetwork/lnd/ticker"
 	"github.com/lightningnetwork/lnd/tor"
+	"github.com/lightningnetwork/lnd/trafficstats"
 	"github.com/lightningnetwork/lnd/walletunlocker"
+	"github.com/lightningnetwork/lnd/watchtower"
 	"github.com/lightningnetwork/lnd/watchtower/blob"
 	"github.com/lightningnetwork/lnd/watchtower/wtclient"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
 	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
 	"github.com/lightningnetwork/lnd/watchtower/wtserver"
 )
@@ -161,6 +172,10 @@ type server struct {
 	// identityKeyLoc is the key locator for the above wrapped identity key.
 	identityKeyLoc keychain.KeyLocator
 
+	// noiseIKPeers tracks the peers that we re-connect to with the
+	// Noise_IK handshake.
+	noiseIKPeers *noiseIKPeers
+
 	// nodeSigner is an implementation of the MessageSigner implementation
 	// that's backed by the identity private key of the running lnd node.
 	nodeSigner *netann.NodeSigner
@@ -212,6 +227,16 @@ type server struct {
 	// as a key for this map.
 	peerErrors map[string]*queue.CircularBuffer
 
+	// peerEventLogs keeps the event logs of peers with channels that have
+	// disconnected from us, so that the recent events of a peer are kept
+	// across connections. The string of the peer's compressed pubkey is
+	// used as a key for this map.
+	peerEventLogs map[string]*peer.EventLog
+
+	// peerWireLimits holds the limits of the messages we accept from
+	// peers that override the global ones.
+	peerWireLimits map[route.Vertex]lnwire.ComplexityLimits
+
 	// ignorePeerTermination tracks peers for which the server has initiated
 	// a disconnect. Adding a peer to this map causes the peer termination
 	// watcher to short circuit in the event that peers are purposefully
@@ -247,6 +272,10 @@ type server struct {
 
 	aliasMgr *aliasmgr.Manager
 
+	// addAliasMtx serializes the addition of aliases to live channels, so
+	// that an alias can't be added to two channels at once.
+	addAliasMtx sync.Mutex
+
 	htlcSwitch *htlcswitch.Switch
 
 	interceptableSwitch *htlcswitch.InterceptableSwitch
@@ -259,10 +288,18 @@ type server struct {
 
 	htlcNotifier *htlcswitch.HtlcNotifier
 
+	// htlcTracer records the latency of forwarded HTLCs. It is nil unless
+	// HTLC latency metrics are exported.
+	htlcTracer *htlcswitch.HtlcTracer
+
 	witnessBeacon contractcourt.WitnessBeacon
 
 	breachArbiter *contractcourt.BreachArbiter
 
+	// breachNotifier posts detected breaches to an external endpoint if
+	// configured, nil otherwise.
+	breachNotifier *breachnotify.Webhook
+
 	missionControl *routing.MissionControl
 
 	chanRouter *routing.ChannelRouter
@@ -279,7 +316,7 @@ type server struct {
 
 	chainArb *contractcourt.ChainArbitrator
 
-	sphinx *hop.OnionProcessor
+	sphinx hop.Processor
 
 	towerClient wtclient.Client
 
@@ -302,6 +339,11 @@ type server struct {
 	// changed since last start.
 	currentNodeAnn *lnwire.NodeAnnouncement
 
+	// featureUpdateMtx serializes runtime updates of our feature bits, so
+	// that a failed update can be rolled back without clobbering another
+	// one.
+	featureUpdateMtx sync.Mutex
+
 	// chansToRestore is the set of channels that upon starting, the server
 	// should attempt to restore/recover.
 	chansToRestore walletunlocker.ChannelsToRecover
@@ -311,6 +353,10 @@ type server struct {
 	// channelNotifier to be notified of newly opened and closed channels.
 	chanSubSwapper *chanbackup.SubSwapper
 
+	// backupSyncer synchronizes the channel backup to a companion device
+	// if enabled.
+	backupSyncer *chanbackup.SyncSwapper
+
 	// chanEventStore tracks the behaviour of channels and their remote peers to
 	// provide insights into their health and performance.
 	chanEventStore *chanfitness.ChannelEventStore
@@ -320,8 +366,33 @@ type server struct {
 	// livelinessMonitor monitors that lnd has access to critical resources.
 	livelinessMonitor *healthcheck.Monitor
 
+	// reloadMtx serializes config reloads. It also guards the liveliness
+	// monitor, which is replaced if a health check interval is reloaded.
+	reloadMtx sync.Mutex
+
+	// reloadedCfg holds the current values of the options that can be
+	// reloaded while lnd is running.
+	reloadedCfg *reloadableConfig
+
+	// tlsManager holds the TLS certificate of the RPC server, which is
+	// rotated if the extra IPs or domains of the certificate are
+	// reloaded.
+	tlsManager *tlsManager
+
 	customMessageServer *subscribe.Server
 
+	// lspPolicies holds the policies our LSPs announced for our private
+	// channels with them.
+	lspPolicies *lsppolicy.Store
+
+	// peerMessages dispatches summaries of the wire messages exchanged
+	// with our peers to the debug peer message subscribers.
+	peerMessages *peer.MessageDispatcher
+
+	// trafficStats exports per-connection traffic statistics of our peers
+	// to an external collector. It is nil if the export isn't enabled.
+	trafficStats *trafficstats.Exporter
+
 	quit chan struct{}
 
 	wg sync.WaitGroup
@@ -409,17 +480,119 @@ func (s *server) updatePersistentPeerAddrs() error {
 	return nil
 }
 
+// updateTowerAddrs subscribes to topology changes and hands the advertised
+// addresses of any watchtowers that are also Lightning nodes over to the
+// watchtower clients, so that they can still reach the towers after they
+// changed their address.
+func (s *server) updateTowerAddrs() error {
+	var clients []wtclient.Client
+	if s.towerClient != nil {
+		clients = append(clients, s.towerClient)
+	}
+	if s.anchorTowerClient != nil {
+		clients = append(clients, s.anchorTowerClient)
+	}
+	if len(clients) == 0 {
+		return nil
+	}
+
+	graphSub, err := s.chanRouter.SubscribeTopology()
+	if err != nil {
+		return err
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer func() {
+			graphSub.Cancel()
+			s.wg.Done()
+		}()
+
+		for {
+			select {
+			case <-s.quit:
+				return
+
+			case topChange, ok := <-graphSub.TopologyChanges:
+				// If the router is shutting down, then we will
+				// as well.
+				if !ok {
+					return
+				}
+
+				for _, update := range topChange.NodeUpdates {
+					for _, c := range clients {
+						refreshTowerAddrs(c, update)
+					}
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// refreshTowerAddrs replaces the announced addresses of the watchtower with
+// the same identity key by the clearnet addresses advertised in the node
+// update, if the client considers it for new sessions. The tower listens on a
+// different port than the node, so the port of the tower's known clearnet
+// address is used. Onion addresses are skipped, since they belong to the
+// node's own onion service rather than the tower's.
+func refreshTowerAddrs(client wtclient.Client,
+	update *routing.NetworkNodeUpdate) {
+
+	tower, err := client.LookupTower(update.IdentityKey)
+	if err != nil || !tower.ActiveSessionCandidate {
+		return
+	}
+
+	port := watchtower.DefaultPeerPort
+	for _, addr := range tower.Addresses {
+		if tcpAddr, ok := addr.(*net.TCPAddr); ok {
+			port = tcpAddr.Port
+			break
+		}
+	}
+
+	var addrs []net.Addr
+	for _, addr := range update.Addresses {
+		tcpAddr, ok := addr.(*net.TCPAddr)
+		if !ok {
+			continue
+		}
+
+		addrs = append(addrs, &net.TCPAddr{
+			IP:   tcpAddr.IP,
+			Port: port,
+		})
+	}
+
+	err = client.SetAnnouncedTowerAddrs(update.IdentityKey, addrs)
+	if err != nil {
+		srvrLog.Errorf("Unable to set addresses of watchtower %x: %v",
+			update.IdentityKey.SerializeCompressed(), err)
+	}
+}
+
 // CustomMessage is a custom message that is received from a peer.
 type CustomMessage struct {
 	// Peer is the peer pubkey
 	Peer [33]byte
 
-	// Msg is the custom wire message.
-	Msg *lnwire.Custom
+	// Msg is the custom wire message. It is a raw Custom message, or a
+	// typed message if its type was registered with
+	// lnwire.RegisterCustomMessage.
+	Msg lnwire.Message
 }
 
-// parseAddr parses an address from its string format to a net.Addr.
+// parseAddr parses an address from its string format to a net.Addr. A ws:// or
+// wss:// URL is parsed as the address of a WebSocket listener, which is
+// resolved when it's dialed.
 func parseAddr(address string, netCfg tor.Net) (net.Addr, error) {
+	if ws.IsAddr(address) {
+		return ws.ParseAddr(address)
+	}
+
 	var (
 		host string
 		port int
@@ -454,15 +627,166 @@ func parseAddr(address string, netCfg tor.Net) (net.Addr, error) {
 	return netCfg.ResolveTCPAddr("tcp", hostPort)
 }
 
+// noiseIKRetryDelay is the time after a failed Noise_IK handshake with a peer
+// during which we only use the Noise_XK handshake to connect to it.
+const noiseIKRetryDelay = time.Hour
+
+// noiseIKPeers tracks the peers that signaled support for the Noise_IK
+// brontide handshake in their most recent init message. As the initiator of
+// the handshake must know up front that the responder supports it, we only use
+// it to re-connect to these peers.
+type noiseIKPeers struct {
+	mu sync.Mutex
+
+	// peers maps the peers that support the Noise_IK handshake to the
+	// time of the last failed Noise_IK handshake with them, which is zero
+	// if it never failed.
+	peers map[route.Vertex]time.Time
+}
+
+// newNoiseIKPeers returns an empty set of Noise_IK capable peers.
+func newNoiseIKPeers() *noiseIKPeers {
+	return &noiseIKPeers{
+		peers: make(map[route.Vertex]time.Time),
+	}
+}
+
+// update records whether the given peer supports the Noise_IK handshake. The
+// time of the last failed handshake with a peer that still supports it is
+// kept.
+func (n *noiseIKPeers) update(peer route.Vertex, supported bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if !supported {
+		delete(n.peers, peer)
+		return
+	}
+
+	if _, ok := n.peers[peer]; !ok {
+		n.peers[peer] = time.Time{}
+	}
+}
+
+// failed records that a Noise_IK handshake with the given peer failed. The
+// failure may as well be caused by the network as by the peer, so we only
+// refrain from using the handshake for noiseIKRetryDelay.
+func (n *noiseIKPeers) failed(peer route.Vertex) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if _, ok := n.peers[peer]; ok {
+		n.peers[peer] = time.Now()
+	}
+}
+
+// supports returns true if the given peer is known to support the Noise_IK
+// handshake and no handshake with it failed within the last
+// noiseIKRetryDelay.
+func (n *noiseIKPeers) supports(peer route.Vertex) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	failedAt, ok := n.peers[peer]
+	if !ok {
+		return false
+	}
+
+	return failedAt.IsZero() || time.Since(failedAt) >= noiseIKRetryDelay
+}
+
+// dialNoise establishes an encrypted connection to the given peer. If the peer
+// is known to support the Noise_IK handshake, it is used so the peer learns
+// our identity with the first act. Should that fail, we fall back to the
+// Noise_XK handshake, as the peer may no longer support Noise_IK.
+func dialNoise(idKey keychain.SingleKeyECDH, addr *lnwire.NetAddress,
+	timeout time.Duration, dialer tor.DialFunc,
+	brontideCfg *brontide.Config, ikPeers *noiseIKPeers) (*brontide.Conn,
+	error) {
+
+	// Peers listening for WebSocket connections are dialed over
+	// WebSocket, all others over plain TCP.
+	dial := func(options ...func(*brontide.Machine)) (*brontide.Conn,
+		error) {
+
+		wsAddr, ok := addr.Address.(*ws.Addr)
+		if !ok {
+			return brontide.Dial(
+				idKey, addr, timeout, dialer, brontideCfg,
+				options...,
+			)
+		}
+
+		return ws.Dial(
+			idKey, addr.IdentityKey, wsAddr.String(), timeout,
+			dialer, brontideCfg, options...,
+		)
+	}
+
+	peer := route.NewVertex(addr.IdentityKey)
+	if ikPeers.supports(peer) {
+		conn, err := dial(brontide.NoisePattern(brontide.PatternIK))
+		if err == nil {
+			return conn, nil
+		}
+
+		srvrLog.Debugf("Noise_IK handshake with %v failed, falling "+
+			"back to Noise_XK: %v", addr, err)
+
+		ikPeers.failed(peer)
+	}
+
+	return dial()
+}
+
 // noiseDial is a factory function which creates a connmgr compliant dialing
 // function by returning a closure which includes the server's identity key.
-func noiseDial(idKey keychain.SingleKeyECDH,
-	netCfg tor.Net, timeout time.Duration) func(net.Addr) (net.Conn, error) {
+func noiseDial(idKey keychain.SingleKeyECDH, netCfg tor.Net,
+	timeout time.Duration, brontideCfg *brontide.Config,
+	ikPeers *noiseIKPeers) func(net.Addr) (net.Conn, error) {
 
 	return func(a net.Addr) (net.Conn, error) {
 		lnAddr := a.(*lnwire.NetAddress)
-		return brontide.Dial(idKey, lnAddr, timeout, netCfg.Dial)
+		return dialNoise(
+			idKey, lnAddr, timeout, netCfg.Dial, brontideCfg,
+			ikPeers,
+		)
+	}
+}
+
+// brontideConfig returns the timeouts and socket options to enforce on the
+// encrypted connections to our peers.
+func brontideConfig(cfg *Config) *brontide.Config {
+	return &brontide.Config{
+		HandshakeTimeout: cfg.Brontide.HandshakeTimeout,
+		ReadTimeout:      cfg.Brontide.ReadTimeout,
+		WriteTimeout:     cfg.Brontide.WriteTimeout,
+		OnKeyRotation:    logKeyRotation,
+		Socket: brontide.SocketConfig{
+			Nagle:     cfg.Brontide.Nagle,
+			KeepAlive: cfg.Brontide.KeepAlive,
+			ReusePort: cfg.Brontide.ReusePort,
+		},
+		NoNoiseIK: cfg.ProtocolOptions.NoNoiseIK(),
+	}
+}
+
+// logKeyRotation logs a rotation of the sending or receiving key of the
+// encrypted connection to a peer. The rotations every 1000 encryptions are
+// only logged at trace level, as they happen frequently on busy connections.
+func logKeyRotation(r brontide.KeyRotation) {
+	direction := "receiving"
+	if r.Send {
+		direction = "sending"
 	}
+
+	logf := srvrLog.Debugf
+	if r.Reason == brontide.RotationNonceLimit {
+		logf = srvrLog.Tracef
+	}
+
+	logf("Rotated %v key of connection to peer %x: %v", direction,
+		r.RemotePub.SerializeCompressed(), r.Reason)
 }
 
 // newServer creates a new instance of the server which is to listen using the
@@ -471,8 +795,12 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	dbs *DatabaseInstances, cc *chainreg.ChainControl,
 	nodeKeyDesc *keychain.KeyDescriptor,
 	chansToRestore walletunlocker.ChannelsToRecover,
-	chanPredicate chanacceptor.ChannelAcceptor,
-	torController *tor.Controller) (*server, error) {
+	chanPredicate chanacceptor.MultiplexAcceptor,
+	torController *tor.Controller,
+	onionProcessorBuilder OnionProcessorBuilder,
+	htlcHoldHookBuilder HtlcHoldHookBuilder,
+	edgeScorerBuilder EdgeScorerBuilder,
+	tlsMgr *tlsManager) (*server, error) {
 
 	var (
 		err         error
@@ -485,17 +813,52 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		)
 	)
 
-	listeners := make([]net.Listener, len(listenAddrs))
-	for i, listenAddr := range listenAddrs {
-		// Note: though brontide.NewListener uses ResolveTCPAddr, it
+	listeners := make([]net.Listener, 0, len(listenAddrs))
+	for _, listenAddr := range listenAddrs {
+		// Note: though brontide.NewListener resolves the address, it
 		// doesn't need to call the general lndResolveTCP function
 		// since we are resolving a local address.
-		listeners[i], err = brontide.NewListener(
-			nodeKeyECDH, listenAddr.String(),
+		listener, err := brontide.NewListener(
+			nodeKeyECDH, listenAddr.String(), brontideConfig(cfg),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		listeners = append(listeners, listener)
+	}
+
+	// Peers connecting over WebSocket are handed to the connection
+	// manager just like the ones connecting over plain TCP, as the
+	// listeners return the same authenticated brontide connections.
+	for _, listenAddr := range cfg.WSListeners {
+		listener, err := ws.NewListener(
+			nodeKeyECDH, listenAddr.String(), brontideConfig(cfg),
+			nil,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		listeners = append(listeners, listener)
+	}
+
+	// The wss listeners serve the certificate of the RPC server, such
+	// that a rotated certificate is picked up without a restart.
+	wssConfig := &tls.Config{
+		GetCertificate: tlsMgr.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+	for _, listenAddr := range cfg.WSSListeners {
+		listener, err := ws.NewListener(
+			nodeKeyECDH, listenAddr.String(), brontideConfig(cfg),
+			wssConfig,
 		)
 		if err != nil {
 			return nil, err
 		}
+
+		listeners = append(listeners, listener)
 	}
 
 	var serializedPubKey [33]byte
@@ -509,6 +872,16 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		nodeKeyECDH, cfg.ActiveNetParams.Params, replayLog,
 	)
 
+	// TODO(roasbeef): derive proper onion key based on rotation
+	// schedule
+	onionProcessor, err := onionProcessorBuilder.BuildOnionProcessor(
+		sphinxRouter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create onion processor: %v",
+			err)
+	}
+
 	writeBufferPool := pool.NewWriteBuffer(
 		pool.DefaultWriteBufferGCInterval,
 		pool.DefaultWriteBufferExpiryInterval,
@@ -537,6 +910,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		NoOptionScidAlias:        !cfg.ProtocolOptions.ScidAlias(),
 		NoZeroConf:               !cfg.ProtocolOptions.ZeroConf(),
 		NoAnySegwit:              cfg.ProtocolOptions.NoAnySegwit(),
+		NoNoiseIK:                cfg.ProtocolOptions.NoNoiseIK(),
 	})
 	if err != nil {
 		return nil, err
@@ -550,11 +924,26 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		AcceptAMP:                   cfg.AcceptAMP,
 		GcCanceledInvoicesOnStartup: cfg.GcCanceledInvoicesOnStartup,
 		GcCanceledInvoicesOnTheFly:  cfg.GcCanceledInvoicesOnTheFly,
+		GcExpiredAfter:              cfg.Invoices.GcExpiredAfter,
+		GcInterval:                  cfg.Invoices.GcInterval,
+		GcArchiveFile:               cfg.Invoices.GcArchiveFile,
 		KeysendHoldTime:             cfg.KeysendHoldTime,
 	}
 
+	peerWireLimits := make(map[route.Vertex]lnwire.ComplexityLimits)
+	peerWires, err := cfg.Wire.PeerLimits()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range peerWires {
+		peerWireLimits[route.NewVertex(p.PubKey)] = p.Limits
+	}
+
 	s := &server{
 		cfg:            cfg,
+		noiseIKPeers:   newNoiseIKPeers(),
+		reloadedCfg:    newReloadableConfig(cfg),
+		tlsManager:     tlsMgr,
 		graphDB:        dbs.GraphDB.ChannelGraph(),
 		chanStateDB:    dbs.ChanStateDB.ChannelStateDB(),
 		addrSource:     dbs.ChanStateDB,
@@ -575,9 +964,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 
 		listenAddrs: listenAddrs,
 
-		// TODO(roasbeef): derive proper onion key based on rotation
-		// schedule
-		sphinx: hop.NewOnionProcessor(sphinxRouter),
+		sphinx: onionProcessor,
 
 		torController: torController,
 
@@ -587,6 +974,8 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		persistentPeerAddrs:     make(map[string][]*lnwire.NetAddress),
 		persistentRetryCancels:  make(map[string]chan struct{}),
 		peerErrors:              make(map[string]*queue.CircularBuffer),
+		peerEventLogs:           make(map[string]*peer.EventLog),
+		peerWireLimits:          peerWireLimits,
 		ignorePeerTermination:   make(map[*peer.Brontide]struct{}),
 		scheduledPeerConnection: make(map[string]func()),
 		pongBuf:                 make([]byte, lnwire.MaxPongBytes),
@@ -598,6 +987,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		peerDisconnectedListeners: make(map[string][]chan<- struct{}),
 
 		customMessageServer: subscribe.NewServer(),
+		peerMessages:        peer.NewMessageDispatcher(),
 
 		featureMgr: featureMgr,
 		quit:       make(chan struct{}),
@@ -612,12 +1002,36 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		clock.NewDefaultClock(), cfg.Invoices.HoldExpiryDelta,
 		uint32(currentHeight), currentHash, cc.ChainNotifier,
 	)
+	// Give a custom implementation the chance to hold htlcs paying to our
+	// invoices, e.g. until a just-in-time channel has been opened to us.
+	htlcHoldHook, err := htlcHoldHookBuilder.BuildHtlcHoldHook(
+		&HtlcHoldHookDeps{
+			ChannelAcceptor: chanPredicate,
+			OpenChannel:     s.OpenChannel,
+			ChannelNotifier: s.channelNotifier,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create htlc hold hook: %v",
+			err)
+	}
+	registryConfig.HtlcHoldHook = htlcHoldHook
+
 	s.invoices = invoices.NewRegistry(
 		dbs.ChanStateDB, expiryWatcher, &registryConfig,
 	)
 
 	s.htlcNotifier = htlcswitch.NewHtlcNotifier(time.Now)
 
+	// If requested, export the latency of forwarded HTLCs alongside the
+	// rest of lnd's Prometheus metrics.
+	if cfg.Prometheus.Enabled() && cfg.Prometheus.HtlcLatencyEnabled() {
+		s.htlcTracer = htlcswitch.NewHtlcTracer(
+			monitoring.NewHtlcMetrics(),
+			htlcswitch.DefaultMaxHtlcTraces, time.Now,
+		)
+	}
+
 	thresholdSats := btcutil.Amount(cfg.DustThreshold)
 	thresholdMSats := lnwire.NewMSatFromSatoshis(thresholdSats)
 
@@ -854,20 +1268,45 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	// servers, the mission control instance itself can be moved there too.
 	routingConfig := routerrpc.GetRoutingConfig(cfg.SubRPCServers.RouterRPC)
 
-	estimatorCfg := routing.ProbabilityEstimatorCfg{
-		AprioriHopProbability: routingConfig.AprioriHopProbability,
-		PenaltyHalfLife:       routingConfig.PenaltyHalfLife,
-		AprioriWeight:         routingConfig.AprioriWeight,
+	var estimator routing.Estimator
+	switch routingConfig.ProbabilityEstimatorType {
+	case routing.AprioriEstimatorName:
+		aCfg := routing.AprioriConfig{
+			AprioriHopProbability: routingConfig.AprioriHopProbability,
+			PenaltyHalfLife:       routingConfig.PenaltyHalfLife,
+			AprioriWeight:         routingConfig.AprioriWeight,
+		}
+		estimator, err = routing.NewAprioriEstimator(aCfg)
+		if err != nil {
+			return nil, err
+		}
+
+	case routing.BimodalEstimatorName:
+		bCfg := routing.BimodalConfig{
+			BimodalNodeWeight: routingConfig.BimodalConfig.NodeWeight,
+			BimodalScaleMsat: lnwire.MilliSatoshi(
+				routingConfig.BimodalConfig.Scale,
+			),
+			BimodalDecayTime: routingConfig.BimodalConfig.DecayTime,
+		}
+		estimator, err = routing.NewBimodalEstimator(bCfg)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown estimator type %v",
+			routingConfig.ProbabilityEstimatorType)
 	}
 
+	mcCfg := &routing.MissionControlConfig{
+		Estimator:               estimator,
+		MaxMcHistory:            routingConfig.MaxMcHistory,
+		McFlushInterval:         routingConfig.McFlushInterval,
+		MinFailureRelaxInterval: routing.DefaultMinFailureRelaxInterval,
+	}
 	s.missionControl, err = routing.NewMissionControl(
-		dbs.ChanStateDB, selfNode.PubKeyBytes,
-		&routing.MissionControlConfig{
-			ProbabilityEstimatorCfg: estimatorCfg,
-			MaxMcHistory:            routingConfig.MaxMcHistory,
-			McFlushInterval:         routingConfig.McFlushInterval,
-			MinFailureRelaxInterval: routing.DefaultMinFailureRelaxInterval,
-		},
+		dbs.ChanStateDB, selfNode.PubKeyBytes, mcCfg,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("can't create mission control: %v", err)
@@ -887,16 +1326,51 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		MinProbability: routingConfig.MinRouteProbability,
 	}
 
+	// Give a custom implementation the chance to bias the routes that are
+	// selected by path finding.
+	edgeScorers, err := edgeScorerBuilder.BuildEdgeScorers(chanGraph)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create edge scorers: %v", err)
+	}
+	pathFindingConfig.EdgeScorers = edgeScorers
+
 	sourceNode, err := chanGraph.SourceNode()
 	if err != nil {
 		return nil, fmt.Errorf("error getting source node: %v", err)
 	}
+
+	lspKeys, err := cfg.Routing.LSPPeerKeys()
+	if err != nil {
+		return nil, err
+	}
+	lsps := make([]route.Vertex, 0, len(lspKeys))
+	for _, lspKey := range lspKeys {
+		lsps = append(lsps, route.NewVertex(lspKey))
+	}
+	if len(lsps) > 0 {
+		err := lsppolicy.RegisterMessages()
+		if err != nil &&
+			!errors.Is(err, lnwire.ErrCustomTypeRegistered) {
+
+			return nil, err
+		}
+	}
+	s.lspPolicies = lsppolicy.NewStore(&lsppolicy.Config{
+		LSPs:         lsps,
+		SelfNode:     sourceNode.PubKeyBytes,
+		FetchChannel: s.fetchLSPChannel,
+		SubscribeChannelEvents: func() (*subscribe.Client, error) {
+			return s.channelNotifier.SubscribeChannelEvents()
+		},
+	})
+
 	paymentSessionSource := &routing.SessionSource{
 		Graph:             chanGraph,
 		SourceNode:        sourceNode,
 		MissionControl:    s.missionControl,
 		GetLink:           s.htlcSwitch.GetLinkByShortID,
 		PathFindingConfig: pathFindingConfig,
+		LearnedEdges:      s.lspPolicies.Edges,
 	}
 
 	paymentControl := channeldb.NewPaymentControl(dbs.ChanStateDB)
@@ -924,6 +1398,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		Clock:               clock.NewDefaultClock(),
 		StrictZombiePruning: strictPruning,
 		IsAlias:             aliasmgr.IsAlias,
+		LearnedEdges:        s.lspPolicies.Edges,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("can't create router: %v", err)
@@ -953,7 +1428,8 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		ProofMatureDelta:        0,
 		TrickleDelay:            time.Millisecond * time.Duration(cfg.TrickleDelay),
 		RetransmitTicker:        ticker.New(time.Minute * 30),
-		RebroadcastInterval:     time.Hour * 24,
+		RebroadcastInterval:     cfg.Gossip.RebroadcastInterval,
+		RebroadcastJitter:       cfg.Gossip.RebroadcastJitter,
 		WaitingProofStore:       waitingProofStore,
 		MessageStore:            gossipMessageStore,
 		AnnSigner:               s.nodeSigner,
@@ -1013,6 +1489,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		NextAttemptDeltaFunc: sweep.DefaultNextAttemptDeltaFunc,
 		MaxFeeRate:           sweep.DefaultMaxFeeRate,
 		FeeRateBucketSize:    sweep.DefaultFeeRateBucketSize,
+		LookupMempoolSpend:   cc.LookupMempoolSpend,
 	})
 
 	s.utxoNursery = contractcourt.NewUtxoNursery(&contractcourt.NurseryConfig{
@@ -1056,6 +1533,18 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		),
 	})
 
+	// If configured, notify an external endpoint of every detected breach,
+	// so that operators can react before the justice transaction confirms.
+	if cfg.BreachNotify.URL != "" {
+		s.breachNotifier = breachnotify.New(&breachnotify.Config{
+			URL:               cfg.BreachNotify.URL,
+			Timeout:           cfg.BreachNotify.Timeout,
+			Attempts:          cfg.BreachNotify.Attempts,
+			SubscribeBreaches: s.breachArbiter.SubscribeBreaches,
+			Client:            http.DefaultClient,
+		})
+	}
+
 	s.chainArb = contractcourt.NewChainArbitrator(contractcourt.ChainArbitratorConfig{
 		ChainHash:              *s.cfg.ActiveNetParams.GenesisHash,
 		IncomingBroadcastDelta: lncfg.DefaultIncomingBroadcastDelta,
@@ -1102,7 +1591,8 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			s.htlcSwitch.RemoveLink(chanID)
 			return nil
 		},
-		IsOurAddress: cc.Wallet.IsOurAddress,
+		RequestCoopClose: s.requestCoopClose,
+		IsOurAddress:     cc.Wallet.IsOurAddress,
 		ContractBreach: func(chanPoint wire.OutPoint,
 			breachRet *lnwallet.BreachRetribution) error {
 
@@ -1214,8 +1704,82 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return ourPolicy, err
 	}
 
+	// If liquidity ads are enabled, we'll answer funding requests of
+	// channel initiators with the configured lease rates.
+	var leasePolicy *funding.LeasePolicy
+	if cfg.LiquidityAds.Enable {
+		leasePolicy = &funding.LeasePolicy{
+			Rates: lnwire.LeaseRates{
+				FundingFeeBase: btcutil.Amount(
+					cfg.LiquidityAds.LeaseFeeBase,
+				),
+				FundingFeeRate: cfg.LiquidityAds.LeaseFeeRate,
+				ChannelFeeMaxBase: lnwire.MilliSatoshi(
+					cfg.LiquidityAds.ChannelFeeMaxBase,
+				),
+				ChannelFeeMaxRate: cfg.LiquidityAds.ChannelFeeMaxRate,
+			},
+			MinLeaseAmt: btcutil.Amount(cfg.LiquidityAds.MinLeaseAmt),
+			MaxLeaseAmt: btcutil.Amount(cfg.LiquidityAds.MaxLeaseAmt),
+		}
+	}
+
+	// If zero reserve channels are enabled, we'll allow them to be
+	// negotiated with the configured peers only.
+	zeroReservePeers := make(map[route.Vertex]struct{})
+	if cfg.ZeroReserve.Enable {
+		peerKeys, err := cfg.ZeroReserve.PeerKeys()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, peerKey := range peerKeys {
+			srvrLog.Warnf("Allowing channels without reserve with "+
+				"peer %x, which can broadcast revoked states "+
+				"without risking any funds",
+				peerKey.SerializeCompressed())
+
+			zeroReservePeers[route.NewVertex(peerKey)] = struct{}{}
+		}
+	}
+
+	// Assemble the limits of the channels with our peers. Peers without
+	// their own policy use the global one, which leaves the maximum
+	// channel size to the maxchansize option.
+	globalChanPolicy := funding.PeerPolicy{
+		MaxExposure: btcutil.Amount(cfg.ChanPolicy.MaxPeerExposure),
+		WumboConfs:  cfg.ChanPolicy.WumboConfs,
+	}
+	peerChanPolicies := make(map[route.Vertex]funding.PeerPolicy)
+	peerPolicies, err := cfg.ChanPolicy.PeerPolicies()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range peerPolicies {
+		peerChanPolicies[route.NewVertex(p.PubKey)] = funding.PeerPolicy{
+			MaxChanSize: btcutil.Amount(p.MaxChanSize),
+			MaxExposure: btcutil.Amount(p.MaxPeerExposure),
+			WumboConfs:  p.WumboConfs,
+		}
+	}
+
+	// Parse the static upfront shutdown address for inbound channels, if
+	// one is configured.
+	upfrontShutdown, err := chancloser.ParseUpfrontShutdownAddress(
+		cfg.UpfrontShutdownAddr, cfg.ActiveNetParams.Params,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upfront shutdown address: %v",
+			err)
+	}
+
 	s.fundingMgr, err = funding.NewFundingManager(funding.Config{
-		NoWumboChans:       !cfg.ProtocolOptions.Wumbo(),
+		NoWumboChans: func() bool {
+			initFeatures := s.featureMgr.Get(feature.SetInit)
+			return !initFeatures.HasFeature(
+				lnwire.WumboChannelsOptional,
+			)
+		},
 		IDKey:              nodeKeyDesc.PubKey,
 		IDKeyLoc:           nodeKeyDesc.KeyLocator,
 		Wallet:             cc.Wallet,
@@ -1369,6 +1933,18 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 
 			return reserve
 		},
+		IsZeroReservePeer: func(peer *btcec.PublicKey) bool {
+			_, ok := zeroReservePeers[route.NewVertex(peer)]
+			return ok
+		},
+		PeerPolicy: func(peer *btcec.PublicKey) funding.PeerPolicy {
+			policy, ok := peerChanPolicies[route.NewVertex(peer)]
+			if !ok {
+				return globalChanPolicy
+			}
+
+			return policy
+		},
 		RequiredRemoteMaxValue: func(chanAmt btcutil.Amount) lnwire.MilliSatoshi {
 			// By default, we'll allow the remote peer to fully
 			// utilize the full bandwidth of the channel, minus our
@@ -1396,11 +1972,14 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		OpenChannelPredicate:          chanPredicate,
 		NotifyPendingOpenChannelEvent: s.channelNotifier.NotifyPendingOpenChannelEvent,
 		EnableUpfrontShutdown:         cfg.EnableUpfrontShutdown,
+		UpfrontShutdownScript:         upfrontShutdown,
+		RequireUpfrontShutdown:        cfg.RequireUpfrontShutdown,
 		RegisteredChains:              cfg.registeredChains,
 		MaxAnchorsCommitFeeRate: chainfee.SatPerKVByte(
 			s.cfg.MaxCommitFeeRateAnchors * 1000).FeePerKWeight(),
 		DeleteAliasEdge: deleteAliasEdge,
 		AliasManager:    s.aliasMgr,
+		LeasePolicy:     leasePolicy,
 	})
 	if err != nil {
 		return nil, err
@@ -1412,7 +1991,24 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		chanNotifier: s.channelNotifier,
 		addrs:        dbs.ChanStateDB,
 	}
-	backupFile := chanbackup.NewMultiFile(cfg.BackupFilePath)
+	var backupSwapper chanbackup.Swapper = chanbackup.NewMultiFile(
+		cfg.BackupFilePath,
+	)
+
+	// If enabled, every update of the backup file is also synchronized to
+	// a companion device.
+	if cfg.BackupSync.Enable {
+		syncTarget := chanbackup.NewHTTPSyncTarget(
+			cfg.BackupSync.URL, cfg.BackupSync.AuthToken,
+			&http.Client{Timeout: cfg.BackupSync.Timeout},
+		)
+		s.backupSyncer = chanbackup.NewSyncSwapper(
+			backupSwapper, syncTarget, s.cc.KeyRing,
+			ticker.New(cfg.BackupSync.RetryInterval),
+		)
+		backupSwapper = s.backupSyncer
+	}
+
 	startingChans, err := chanbackup.FetchStaticChanBackups(
 		s.chanStateDB, s.addrSource,
 	)
@@ -1420,7 +2016,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return nil, err
 	}
 	s.chanSubSwapper, err = chanbackup.NewSubSwapper(
-		startingChans, chanNotifier, s.cc.KeyRing, backupFile,
+		startingChans, chanNotifier, s.cc.KeyRing, backupSwapper,
 	)
 	if err != nil {
 		return nil, err
@@ -1430,6 +2026,25 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	// to peer online and offline events.
 	s.peerNotifier = peernotifier.New()
 
+	// If enabled, create the exporter of the traffic statistics of our
+	// peers.
+	if cfg.TrafficStats.Enable {
+		collector, err := lncfg.ParseAddressString(
+			cfg.TrafficStats.Collector, "", cfg.net.ResolveTCPAddr,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid traffic stats collector "+
+				"address: %v", err)
+		}
+
+		s.trafficStats = trafficstats.New(&trafficstats.Config{
+			Collector: collector,
+			Dial:      net.Dial,
+			Ticker:    ticker.New(cfg.TrafficStats.Interval),
+			Clock:     clock.NewDefaultClock(),
+		})
+	}
+
 	// Create a channel event store which monitors all open channels.
 	s.chanEventStore = chanfitness.NewChannelEventStore(&chanfitness.Config{
 		SubscribeChannelEvents: func() (subscribe.Subscription, error) {
@@ -1457,6 +2072,30 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			policy.SweepFeeRate = sweepRateSatPerVByte.FeePerKWeight()
 		}
 
+		policy.MaxUpdates = cfg.WtClient.MaxUpdates
+
+		// If a max sweep fee rate is set, the tower chooses the fee
+		// rate of the justice transactions at breach time within the
+		// range of the two fee rates.
+		if cfg.WtClient.MaxSweepFeeRate != 0 {
+			maxSweepRateSatPerVByte := chainfee.SatPerKVByte(
+				1000 * cfg.WtClient.MaxSweepFeeRate,
+			)
+
+			policy.TxPolicy.BlobType |= blob.Type(
+				blob.FlagDynamicFee,
+			)
+			policy.MaxSweepFeeRate =
+				maxSweepRateSatPerVByte.FeePerKWeight()
+			policy.SweepFeePercentile =
+				wtpolicy.DefaultSweepFeePercentile
+
+			if cfg.WtClient.SweepFeePercentile != 0 {
+				policy.SweepFeePercentile =
+					cfg.WtClient.SweepFeePercentile
+			}
+		}
+
 		if err := policy.Validate(); err != nil {
 			return nil, err
 		}
@@ -1469,21 +2108,74 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 
 			return brontide.Dial(
 				localKey, netAddr, cfg.ConnectionTimeout, dialer,
+				brontideConfig(cfg),
+			)
+		}
+
+		// Both clients retry failed connections to towers according to
+		// the same backoff policy.
+		backoff := wtclient.BackoffPolicy{
+			MinBackoff:              cfg.WtClient.MinBackoff,
+			MaxBackoff:              cfg.WtClient.MaxBackoff,
+			Multiplier:              cfg.WtClient.BackoffMultiplier,
+			Jitter:                  cfg.WtClient.BackoffJitter,
+			CircuitBreakerThreshold: cfg.WtClient.CircuitBreakerThreshold,
+			CircuitBreakerCooldown:  cfg.WtClient.CircuitBreakerCooldown,
+		}
+
+		// Both clients rank the towers for new sessions on their own,
+		// based on the health they observe.
+		selectionPolicy := wtclient.TowerSelectionPolicy{
+			MaxSessionsPerTower: cfg.WtClient.MaxSessionsPerTower,
+			Diversify:           cfg.WtClient.DiversifyTowers,
+		}
+		legacySelector := wtclient.NewPolicyTowerSelector(
+			selectionPolicy,
+		)
+		anchorSelector := wtclient.NewPolicyTowerSelector(
+			selectionPolicy,
+		)
+
+		// If a sweep address is configured, the justice transactions
+		// pay into it rather than into our wallet.
+		var sweepPkScript []byte
+		if cfg.WtClient.SweepAddress != "" {
+			sweepPkScript, err = sweepAddrPkScript(
+				cfg.WtClient.SweepAddress,
+				cfg.ActiveNetParams.Params,
 			)
+			if err != nil {
+				return nil, fmt.Errorf("invalid "+
+					"wtclient.sweep-address: %v", err)
+			}
+		}
+
+		// The clients delete the sessions of cooperatively closed
+		// channels, for which they need to learn about closed channels.
+		subscribeChanEvents := func() (subscribe.Subscription, error) {
+			return s.channelNotifier.SubscribeChannelEvents()
 		}
 
+		// Both clients share the same towers, so their sessions count
+		// towards the same per-tower session quota.
+		sessionCounter := wtclient.NewSessionCounter()
+
 		s.towerClient, err = wtclient.New(&wtclient.Config{
-			Signer:         cc.Wallet.Cfg.Signer,
-			NewAddress:     newSweepPkScriptGen(cc.Wallet),
-			SecretKeyRing:  s.cc.KeyRing,
-			Dial:           cfg.net.Dial,
-			AuthDial:       authDial,
-			DB:             dbs.TowerClientDB,
-			Policy:         policy,
-			ChainHash:      *s.cfg.ActiveNetParams.GenesisHash,
-			MinBackoff:     10 * time.Second,
-			MaxBackoff:     5 * time.Minute,
-			ForceQuitDelay: wtclient.DefaultForceQuitDelay,
+			Signer:                 cc.Wallet.Cfg.Signer,
+			NewAddress:             newSweepPkScriptGen(cc.Wallet),
+			SweepPkScript:          sweepPkScript,
+			SecretKeyRing:          s.cc.KeyRing,
+			Dial:                   cfg.net.Dial,
+			AuthDial:               authDial,
+			DB:                     dbs.TowerClientDB,
+			Policy:                 policy,
+			ChainHash:              *s.cfg.ActiveNetParams.GenesisHash,
+			Backoff:                backoff,
+			TowerSelector:          legacySelector,
+			SessionCounter:         sessionCounter,
+			ForceQuitDelay:         wtclient.DefaultForceQuitDelay,
+			SubscribeChannelEvents: subscribeChanEvents,
+			FetchClosedChannels:    s.chanStateDB.FetchClosedChannels,
 		})
 		if err != nil {
 			return nil, err
@@ -1495,18 +2187,29 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		anchorPolicy.TxPolicy.BlobType |=
 			blob.Type(blob.FlagAnchorChannel)
 
+		// If enabled, let the tower bump the fee of justice
+		// transactions of anchor channels via an extra output.
+		if cfg.WtClient.CPFPAnchor {
+			anchorPolicy.TxPolicy.BlobType |=
+				blob.Type(blob.FlagCPFPAnchor)
+		}
+
 		s.anchorTowerClient, err = wtclient.New(&wtclient.Config{
-			Signer:         cc.Wallet.Cfg.Signer,
-			NewAddress:     newSweepPkScriptGen(cc.Wallet),
-			SecretKeyRing:  s.cc.KeyRing,
-			Dial:           cfg.net.Dial,
-			AuthDial:       authDial,
-			DB:             dbs.TowerClientDB,
-			Policy:         anchorPolicy,
-			ChainHash:      *s.cfg.ActiveNetParams.GenesisHash,
-			MinBackoff:     10 * time.Second,
-			MaxBackoff:     5 * time.Minute,
-			ForceQuitDelay: wtclient.DefaultForceQuitDelay,
+			Signer:                 cc.Wallet.Cfg.Signer,
+			NewAddress:             newSweepPkScriptGen(cc.Wallet),
+			SweepPkScript:          sweepPkScript,
+			SecretKeyRing:          s.cc.KeyRing,
+			Dial:                   cfg.net.Dial,
+			AuthDial:               authDial,
+			DB:                     dbs.TowerClientDB,
+			Policy:                 anchorPolicy,
+			ChainHash:              *s.cfg.ActiveNetParams.GenesisHash,
+			Backoff:                backoff,
+			TowerSelector:          anchorSelector,
+			SessionCounter:         sessionCounter,
+			ForceQuitDelay:         wtclient.DefaultForceQuitDelay,
+			SubscribeChannelEvents: subscribeChanEvents,
+			FetchClosedChannels:    s.chanStateDB.FetchClosedChannels,
 		})
 		if err != nil {
 			return nil, err
@@ -1546,6 +2249,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		TargetOutbound: 100,
 		Dial: noiseDial(
 			nodeKeyECDH, s.cfg.net, s.cfg.ConnectionTimeout,
+			brontideConfig(s.cfg), s.noiseIKPeers,
 		),
 		OnConnection: s.OutboundPeerConnected,
 	})
@@ -1700,6 +2404,21 @@ func (s *server) createLivenessMonitor(cfg *Config, cc *chainreg.ChainControl) {
 		checks = append(checks, remoteSignerConnectionCheck)
 	}
 
+	// If the node identity and funding keys are kept in a hardware
+	// security module, add a health check that makes sure we can still
+	// reach it.
+	if cc.Cfg.HSM != nil {
+		hsmCheck := healthcheck.NewObservation(
+			"hsm",
+			cc.Cfg.HSM.Ping,
+			cfg.HealthChecks.HSM.Interval,
+			cfg.HealthChecks.HSM.Timeout,
+			cfg.HealthChecks.HSM.Backoff,
+			cfg.HealthChecks.HSM.Attempts,
+		)
+		checks = append(checks, hsmCheck)
+	}
+
 	// If we have not disabled all of our health checks, we create a
 	// liveliness monitor with our configured checks.
 	s.livelinessMonitor = healthcheck.NewMonitor(
@@ -1755,6 +2474,14 @@ func (s *server) Start() error {
 		}
 		cleanup = cleanup.add(s.customMessageServer.Stop)
 
+		if s.trafficStats != nil {
+			if err := s.trafficStats.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.trafficStats.Stop)
+		}
+
 		if s.hostAnn != nil {
 			if err := s.hostAnn.Start(); err != nil {
 				startErr = err
@@ -1819,6 +2546,12 @@ func (s *server) Start() error {
 		}
 		cleanup = cleanup.add(s.htlcNotifier.Stop)
 
+		if err := s.lspPolicies.Start(); err != nil {
+			startErr = err
+			return
+		}
+		cleanup = cleanup.add(s.lspPolicies.Stop)
+
 		if s.towerClient != nil {
 			if err := s.towerClient.Start(); err != nil {
 				startErr = err
@@ -1852,6 +2585,14 @@ func (s *server) Start() error {
 		}
 		cleanup = cleanup.add(s.breachArbiter.Stop)
 
+		if s.breachNotifier != nil {
+			if err := s.breachNotifier.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.breachNotifier.Stop)
+		}
+
 		if err := s.fundingMgr.Start(); err != nil {
 			startErr = err
 			return
@@ -1956,6 +2697,14 @@ func (s *server) Start() error {
 			}
 		}
 
+		if s.backupSyncer != nil {
+			if err := s.backupSyncer.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.backupSyncer.Stop)
+		}
+
 		if err := s.chanSubSwapper.Start(); err != nil {
 			startErr = err
 			return
@@ -2026,6 +2775,13 @@ func (s *server) Start() error {
 			return
 		}
 
+		// Likewise, subscribe to NodeAnnouncements of watchtowers that
+		// are also Lightning nodes to learn about their new addresses.
+		if err := s.updateTowerAddrs(); err != nil {
+			startErr = err
+			return
+		}
+
 		// With all the relevant sub-systems started, we'll now attempt
 		// to establish persistent connections to our direct channel
 		// collaborators within the network. Before doing so however,
@@ -2041,63 +2797,6 @@ func (s *server) Start() error {
 			return
 		}
 
-		// setSeedList is a helper function that turns multiple DNS seed
-		// server tuples from the command line or config file into the
-		// data structure we need and does a basic formal sanity check
-		// in the process.
-		setSeedList := func(tuples []string, genesisHash chainhash.Hash) {
-			if len(tuples) == 0 {
-				return
-			}
-
-			result := make([][2]string, len(tuples))
-			for idx, tuple := range tuples {
-				tuple = strings.TrimSpace(tuple)
-				if len(tuple) == 0 {
-					return
-				}
-
-				servers := strings.Split(tuple, ",")
-				if len(servers) > 2 || len(servers) == 0 {
-					srvrLog.Warnf("Ignoring invalid DNS "+
-						"seed tuple: %v", servers)
-					return
-				}
-
-				copy(result[idx][:], servers)
-			}
-
-			chainreg.ChainDNSSeeds[genesisHash] = result
-		}
-
-		// Let users overwrite the DNS seed nodes. We only allow them
-		// for bitcoin mainnet/testnet and litecoin mainnet, all other
-		// combinations will just be ignored.
-		if s.cfg.Bitcoin.Active && s.cfg.Bitcoin.MainNet {
-			setSeedList(
-				s.cfg.Bitcoin.DNSSeeds,
-				chainreg.BitcoinMainnetGenesis,
-			)
-		}
-		if s.cfg.Bitcoin.Active && s.cfg.Bitcoin.TestNet3 {
-			setSeedList(
-				s.cfg.Bitcoin.DNSSeeds,
-				chainreg.BitcoinTestnetGenesis,
-			)
-		}
-		if s.cfg.Bitcoin.Active && s.cfg.Bitcoin.SigNet {
-			setSeedList(
-				s.cfg.Bitcoin.DNSSeeds,
-				chainreg.BitcoinSignetGenesis,
-			)
-		}
-		if s.cfg.Litecoin.Active && s.cfg.Litecoin.MainNet {
-			setSeedList(
-				s.cfg.Litecoin.DNSSeeds,
-				chainreg.LitecoinMainnetGenesis,
-			)
-		}
-
 		// If network bootstrapping hasn't been disabled, then we'll
 		// configure the set of active bootstrappers, and launch a
 		// dedicated goroutine to maintain a set of persistent
@@ -2173,23 +2872,47 @@ func (s *server) Stop() error {
 		if err := s.sweeper.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop sweeper: %v", err)
 		}
+		if err := s.lspPolicies.Stop(); err != nil {
+			srvrLog.Warnf("failed to stop lspPolicies: %v", err)
+		}
 		if err := s.channelNotifier.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop channelNotifier: %v", err)
 		}
 		if err := s.peerNotifier.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop peerNotifier: %v", err)
 		}
+		if err := s.peerMessages.Stop(); err != nil {
+			srvrLog.Warnf("failed to stop peerMessages: %v", err)
+		}
 		if err := s.htlcNotifier.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop htlcNotifier: %v", err)
 		}
 		if err := s.chanSubSwapper.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop chanSubSwapper: %v", err)
 		}
+		if s.backupSyncer != nil {
+			if err := s.backupSyncer.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop backupSyncer: %v",
+					err)
+			}
+		}
 		if err := s.cc.ChainNotifier.Stop(); err != nil {
 			srvrLog.Warnf("Unable to stop ChainNotifier: %v", err)
 		}
 		s.chanEventStore.Stop()
 		s.missionControl.StopStoreTicker()
+		if s.trafficStats != nil {
+			if err := s.trafficStats.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop trafficStats: %v",
+					err)
+			}
+		}
+		if s.breachNotifier != nil {
+			if err := s.breachNotifier.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop breachNotifier: %v",
+					err)
+			}
+		}
 
 		// Disconnect from each active peers to ensure that
 		// peerTerminationWatchers signal completion to each peer.
@@ -2227,12 +2950,14 @@ func (s *server) Stop() error {
 			}
 		}
 
+		s.reloadMtx.Lock()
 		if s.livelinessMonitor != nil {
 			if err := s.livelinessMonitor.Stop(); err != nil {
 				srvrLog.Warnf("unable to shutdown liveliness "+
 					"monitor: %v", err)
 			}
 		}
+		s.reloadMtx.Unlock()
 
 		// Wait for all lingering goroutines to quit.
 		s.wg.Wait()
@@ -2450,14 +3175,14 @@ func initNetworkBootstrappers(s *server) ([]discovery.NetworkPeerBootstrapper, e
 	}
 	bootStrappers = append(bootStrappers, graphBootstrapper)
 
-	// If this isn't simnet mode, then one of our additional bootstrapping
-	// sources will be the set of running DNS seeds.
-	if !s.cfg.Bitcoin.SimNet || !s.cfg.Litecoin.SimNet {
-		dnsSeeds, ok := chainreg.ChainDNSSeeds[*s.cfg.ActiveNetParams.GenesisHash]
+	// If this isn't a development network, then one of our additional
+	// bootstrapping sources will be the set of running DNS seeds.
+	if !s.cfg.ActiveNetwork.DevNetwork {
+		dnsSeeds := s.dnsSeeds()
 
 		// If we have a set of DNS seeds for this chain, then we'll add
 		// it as an additional bootstrapping source.
-		if ok {
+		if len(dnsSeeds) > 0 {
 			srvrLog.Infof("Creating DNS peer bootstrapper with "+
 				"seeds: %v", dnsSeeds)
 
@@ -2471,6 +3196,41 @@ func initNetworkBootstrappers(s *server) ([]discovery.NetworkPeerBootstrapper, e
 	return bootStrappers, nil
 }
 
+// dnsSeeds returns the DNS seeds used to bootstrap peers on the active
+// network. Users can overwrite the seeds of the network, so the seed server
+// tuples from the command line or config file are turned into the data
+// structure we need, after a basic formal sanity check.
+func (s *server) dnsSeeds() [][2]string {
+	homeChainConfig := s.cfg.Bitcoin
+	if s.cfg.Litecoin.Active {
+		homeChainConfig = s.cfg.Litecoin
+	}
+
+	tuples := homeChainConfig.DNSSeeds
+	if len(tuples) == 0 {
+		return s.cfg.ActiveNetwork.DNSSeeds
+	}
+
+	result := make([][2]string, len(tuples))
+	for idx, tuple := range tuples {
+		tuple = strings.TrimSpace(tuple)
+		if len(tuple) == 0 {
+			return s.cfg.ActiveNetwork.DNSSeeds
+		}
+
+		servers := strings.Split(tuple, ",")
+		if len(servers) > 2 || len(servers) == 0 {
+			srvrLog.Warnf("Ignoring invalid DNS seed tuple: %v",
+				servers)
+			return s.cfg.ActiveNetwork.DNSSeeds
+		}
+
+		copy(result[idx][:], servers)
+	}
+
+	return result
+}
+
 // createBootstrapIgnorePeers creates a map of peers that the bootstrap process
 // needs to ignore, which is made of three parts,
 //   - the node itself needs to be skipped as it doesn't make sense to connect
@@ -2905,6 +3665,71 @@ func (s *server) updateAndBrodcastSelfNode(
 	return nil
 }
 
+// updateFeatureBits enables or disables the given optional feature bits while
+// the daemon is running, then signs and broadcasts a new node announcement
+// advertising the updated feature set. As the Init message can only be sent
+// once per connection, our existing peers will learn about the new features
+// the next time they reconnect. If reconnect is true, we'll disconnect all
+// peers we maintain a persistent connection with so that the connections are
+// re-established, and the features re-negotiated, right away.
+func (s *server) updateFeatureBits(updates map[lnwire.FeatureBit]bool,
+	reconnect bool) error {
+
+	s.featureUpdateMtx.Lock()
+	defer s.featureUpdateMtx.Unlock()
+
+	// Snapshot our feature sets and node announcement, so that we can roll
+	// back if the new announcement can't be signed or stored. Otherwise
+	// the new bits would be advertised in Init messages and invoices even
+	// though the update failed.
+	oldSets := s.featureMgr.Snapshot()
+	oldNodeAnn, err := s.genNodeAnnouncement(false)
+	if err != nil {
+		return err
+	}
+
+	if err := s.featureMgr.UpdateOptional(updates); err != nil {
+		return err
+	}
+
+	err = s.updateAndBrodcastSelfNode(netann.NodeAnnSetFeatures(
+		s.featureMgr.GetRaw(feature.SetNodeAnn),
+	))
+	if err != nil {
+		s.featureMgr.Restore(oldSets)
+
+		s.mu.Lock()
+		s.currentNodeAnn = &oldNodeAnn
+		s.mu.Unlock()
+
+		return err
+	}
+
+	if !reconnect {
+		return nil
+	}
+
+	s.mu.RLock()
+	var persistentPeers []*peer.Brontide
+	for pubStr, p := range s.peersByPub {
+		if _, ok := s.persistentPeers[pubStr]; ok {
+			persistentPeers = append(persistentPeers, p)
+		}
+	}
+	s.mu.RUnlock()
+
+	// The peer termination watcher will take care of reconnecting to each
+	// of these peers, at which point our new Init message is sent.
+	for _, p := range persistentPeers {
+		srvrLog.Infof("Disconnecting from %v to re-negotiate feature "+
+			"bits", p)
+
+		p.Disconnect(fmt.Errorf("server: feature bits updated"))
+	}
+
+	return nil
+}
+
 type nodeAddresses struct {
 	pubKey    *btcec.PublicKey
 	addresses []net.Addr
@@ -3260,6 +4085,53 @@ func (s *server) FindPeer(peerKey *btcec.PublicKey) (*peer.Brontide, error) {
 	return s.findPeerByPubStr(pubStr)
 }
 
+// requestCoopClose asks the peer of the channel with the given channel point
+// to close it cooperatively, as our commitment transaction was rejected by the
+// mempool policy of our backend after we force closed the channel.
+func (s *server) requestCoopClose(chanPoint wire.OutPoint) (<-chan error,
+	error) {
+
+	channel, err := s.chanStateDB.FetchChannel(nil, chanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	peer, err := s.FindPeer(channel.IdentityPub)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &htlcswitch.ChanClose{
+		CloseType: contractcourt.CloseRejectedCommit,
+		ChanPoint: &chanPoint,
+		Err:       make(chan error, 1),
+	}
+
+	// The request is handed to the peer in a goroutine, so that the
+	// caller isn't blocked while the peer handles it.
+	result := make(chan error, 1)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		peer.HandleLocalCloseChanReqs(req)
+
+		select {
+		case err := <-req.Err:
+			result <- err
+
+		case <-peer.QuitSignal():
+			result <- lnpeer.ErrPeerExiting
+
+		case <-s.quit:
+			result <- ErrServerShuttingDown
+		}
+	}()
+
+	return result, nil
+}
+
 // FindPeerByPubStr will return the peer that corresponds to the passed peerID,
 // which should be a string representation of the peer's serialized, compressed
 // public key.
@@ -3598,9 +4470,15 @@ func (s *server) cancelConnReqs(pubStr string, skip *uint64) {
 
 // handleCustomMessage dispatches an incoming custom peers message to
 // subscribers.
-func (s *server) handleCustomMessage(peer [33]byte, msg *lnwire.Custom) error {
+func (s *server) handleCustomMessage(peer [33]byte, msg lnwire.Message) error {
 	srvrLog.Debugf("Custom message received: peer=%x, type=%d",
-		peer, msg.Type)
+		peer, msg.MsgType())
+
+	// The channel policy type is only registered, and its messages thus
+	// only decoded into policies, when LSPs are configured.
+	if policy, ok := msg.(*lsppolicy.ChannelPolicy); ok {
+		s.handleLSPPolicy(peer, policy)
+	}
 
 	return s.customMessageServer.SendUpdate(&CustomMessage{
 		Peer: peer,
@@ -3608,12 +4486,164 @@ func (s *server) handleCustomMessage(peer [33]byte, msg *lnwire.Custom) error {
 	})
 }
 
+// handleLSPPolicy learns the channel policy announced by one of our LSPs.
+// Invalid policies are only logged, as the message is still dispatched to the
+// custom message subscribers.
+func (s *server) handleLSPPolicy(peer [33]byte,
+	policy *lsppolicy.ChannelPolicy) {
+
+	err := s.lspPolicies.HandleMessage(route.Vertex(peer), policy)
+	if err != nil {
+		srvrLog.Warnf("Unable to learn policy of channel %v from "+
+			"%x: %v", policy.ShortChannelID, peer, err)
+		return
+	}
+
+	srvrLog.Debugf("Learned policy of channel %v from LSP %x",
+		policy.ShortChannelID, peer)
+}
+
+// fetchLSPChannel returns our channel with the given LSP that is identified by
+// the given SCID, which may be the channel's confirmed SCID or any of its
+// aliases.
+func (s *server) fetchLSPChannel(peer route.Vertex,
+	scid lnwire.ShortChannelID) (*lsppolicy.Channel, error) {
+
+	peerKey, err := btcec.ParsePubKey(peer[:])
+	if err != nil {
+		return nil, err
+	}
+
+	channels, err := s.chanStateDB.FetchOpenChannels(peerKey)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range channels {
+		scids := append(
+			s.aliasMgr.GetAliases(c.ShortChannelID),
+			c.ShortChannelID, c.ZeroConfRealScid(),
+		)
+
+		chanID := lnwire.NewChanIDFromOutPoint(&c.FundingOutpoint)
+		peerAlias, err := s.aliasMgr.GetPeerAlias(chanID)
+		if err == nil {
+			scids = append(scids, peerAlias)
+		}
+
+		for _, channelSCID := range scids {
+			if channelSCID != scid {
+				continue
+			}
+
+			return &lsppolicy.Channel{
+				ShortChannelID: c.ShortChannelID,
+				ChanPoint:      c.FundingOutpoint,
+				IsPublic: c.ChannelFlags&
+					lnwire.FFAnnounceChannel != 0,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no open channel %v", scid)
+}
+
 // SubscribeCustomMessages subscribes to a stream of incoming custom peer
 // messages.
 func (s *server) SubscribeCustomMessages() (*subscribe.Client, error) {
 	return s.customMessageServer.Subscribe()
 }
 
+// handleWireMessage accounts a wire message exchanged with a peer in the
+// traffic statistics, if enabled, and dispatches a summary of it to the peer
+// message subscribers that select it.
+func (s *server) handleWireMessage(peerPub [33]byte, msg lnwire.Message,
+	size uint32, inbound bool) {
+
+	if s.trafficStats != nil {
+		s.trafficStats.RecordMessage(
+			peerPub, msg.MsgType(), size, inbound,
+		)
+	}
+
+	s.peerMessages.Dispatch(peerPub, msg, size, inbound)
+}
+
+// SubscribePeerMessages subscribes to a stream of summaries of the wire
+// messages exchanged with our peers that are selected by the given filter.
+// The subscription must be canceled once it is no longer needed.
+func (s *server) SubscribePeerMessages(
+	filter peer.MessageFilter) *peer.MessageSubscription {
+
+	return s.peerMessages.Subscribe(filter)
+}
+
+// addLocalAlias adds the given alias to the open channel with the given base
+// SCID. The alias is persisted and, if the channel's link is active, added to
+// the link so that it can be used right away.
+func (s *server) addLocalAlias(alias, baseScid lnwire.ShortChannelID) error {
+	if !aliasmgr.IsAlias(alias) {
+		return fmt.Errorf("%v is not in the alias range", alias)
+	}
+
+	s.addAliasMtx.Lock()
+	defer s.addAliasMtx.Unlock()
+
+	// An alias must resolve to a single channel, so it can't be added if
+	// it's already in use.
+	for base, aliases := range s.aliasMgr.ListAliases() {
+		if base == alias {
+			return fmt.Errorf("alias %v already in use", alias)
+		}
+
+		for _, existing := range aliases {
+			if existing == alias {
+				return fmt.Errorf("alias %v already in use",
+					alias)
+			}
+		}
+	}
+
+	openChannels, err := s.chanStateDB.FetchAllOpenChannels()
+	if err != nil {
+		return err
+	}
+
+	var dbChan *channeldb.OpenChannel
+	for _, c := range openChannels {
+		if c.ShortChanID() == baseScid {
+			dbChan = c
+			break
+		}
+	}
+	if dbChan == nil {
+		return fmt.Errorf("no open channel with base SCID %v",
+			baseScid)
+	}
+
+	// Only channels that negotiated the option-scid-alias feature and
+	// zero-conf channels can have aliases.
+	if !dbChan.NegotiatedAliasFeature() && !dbChan.IsZeroConf() {
+		return fmt.Errorf("channel %v doesn't support aliases",
+			baseScid)
+	}
+
+	err = s.aliasMgr.AddLocalAlias(alias, baseScid, true)
+	if err != nil {
+		return err
+	}
+
+	// If the channel's link isn't active, it picks up the alias from the
+	// alias manager once it is.
+	chanID := lnwire.NewChanIDFromOutPoint(&dbChan.FundingOutpoint)
+	err = s.htlcSwitch.AddAliasForLink(chanID, alias)
+	if err != nil && err != htlcswitch.ErrChannelLinkNotFound {
+		return err
+	}
+
+	return nil
+}
+
 // peerConnected is a function that handles initialization a newly connected
 // peer by adding it to the server's global list of all active peers, and
 // starting all the goroutines the peer needs to function properly. The inbound
@@ -3652,6 +4682,24 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		}
 	}
 
+	// Likewise, continue the event log of the peer if we still have it.
+	eventLog, ok := s.peerEventLogs[pkStr]
+	if !ok {
+		var err error
+		eventLog, err = peer.NewEventLog(peer.EventLogSize)
+		if err != nil {
+			srvrLog.Errorf("unable to create peer %v", err)
+			return
+		}
+	}
+
+	// The limits of the messages we accept from the peer may be
+	// overridden for this peer.
+	wireLimits, ok := s.peerWireLimits[route.NewVertex(peerAddr.IdentityKey)]
+	if !ok {
+		wireLimits = s.cfg.Wire.ComplexityLimits()
+	}
+
 	// Now that we've established a connection, create a peer, and it to the
 	// set of currently active peers. Configure the peer with the incoming
 	// and outgoing broadcast deltas to prevent htlcs from being accepted or
@@ -3667,7 +4715,9 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		LegacyFeatures:          legacyFeatures,
 		OutgoingCltvRejectDelta: lncfg.DefaultOutgoingCltvRejectDelta,
 		ChanActiveTimeout:       s.cfg.ChanEnableTimeout,
+		WireLimits:              wireLimits,
 		ErrorBuffer:             errBuffer,
+		EventLog:                eventLog,
 		WritePool:               s.writePool,
 		ReadPool:                s.readPool,
 		Switch:                  s.htlcSwitch,
@@ -3689,6 +4739,7 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		Invoices:                s.invoices,
 		ChannelNotifier:         s.channelNotifier,
 		HtlcNotifier:            s.htlcNotifier,
+		HtlcTracer:              s.htlcTracer,
 		TowerClient:             s.towerClient,
 		AnchorTowerClient:       s.anchorTowerClient,
 		DisconnectPeer:          s.DisconnectPeer,
@@ -3713,6 +4764,7 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		PendingCommitInterval:  s.cfg.PendingCommitInterval,
 		ChannelCommitBatchSize: s.cfg.ChannelCommitBatchSize,
 		HandleCustomMessage:    s.handleCustomMessage,
+		HandleWireMessage:      s.handleWireMessage,
 		GetAliases:             s.aliasMgr.GetAliases,
 		RequestAlias:           s.aliasMgr.RequestAlias,
 		AddLocalAlias:          s.aliasMgr.AddLocalAlias,
@@ -3724,6 +4776,14 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 
 	p := peer.NewBrontide(pCfg)
 
+	// Start accounting the traffic of the new connection separately from
+	// any previous connection to the peer.
+	if s.trafficStats != nil {
+		var pub [33]byte
+		copy(pub[:], pubKey.SerializeCompressed())
+		s.trafficStats.StartSession(pub, addr, inbound)
+	}
+
 	// TODO(roasbeef): update IP address for link-node
 	//  * also mark last-seen, do it one single transaction?
 
@@ -3733,6 +4793,7 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 	// delete the previous error buffer from the server's map of error
 	// buffers.
 	delete(s.peerErrors, pkStr)
+	delete(s.peerEventLogs, pkStr)
 
 	// Dispatch a goroutine to asynchronously start the peer. This process
 	// includes sending and receiving Init messages, which would be a DOS
@@ -3820,6 +4881,31 @@ func (s *server) peerInitializer(p *peer.Brontide) {
 	// was successful, and to begin watching the peer's wait group.
 	close(ready)
 
+	// Now that we received the peer's init message, we'll remember whether
+	// we can use the Noise_IK handshake to re-connect to it. We only do so
+	// if we signal support for it ourselves.
+	ourFeatures := s.featureMgr.Get(feature.SetInit)
+	s.noiseIKPeers.update(
+		route.NewVertex(p.IdentityKey()),
+		ourFeatures.HasFeature(lnwire.NoiseIKOptional) &&
+			p.RemoteFeatures().HasFeature(lnwire.NoiseIKOptional),
+	)
+
+	// If the peer follows key rotations ahead of the rotation interval, we
+	// can apply our key rotation policy to the connection.
+	policy := brontide.KeyRotationPolicy{
+		Messages: s.cfg.Brontide.RotateMessages,
+		Interval: s.cfg.Brontide.RotateInterval,
+	}
+	if policy != (brontide.KeyRotationPolicy{}) &&
+		ourFeatures.HasFeature(lnwire.KeyRotationOptional) &&
+		p.RemoteFeatures().HasFeature(lnwire.KeyRotationOptional) {
+
+		if conn, ok := p.Conn().(*brontide.Conn); ok {
+			conn.SetKeyRotationPolicy(policy)
+		}
+	}
+
 	pubStr := string(p.IdentityKey().SerializeCompressed())
 
 	s.mu.Lock()
@@ -4172,11 +5258,22 @@ func (s *server) removePeer(p *peer.Brontide) {
 		s.peerErrors[pubStr] = p.ErrorBuffer()
 	}
 
+	// We also keep the peer's event log, but only if we have channels
+	// with the peer, as otherwise a peer could make us keep an event log
+	// for every identity it connects with.
+	if len(p.ChannelSnapshots()) > 0 {
+		s.peerEventLogs[pubStr] = p.EventLog()
+	}
+
 	// Inform the peer notifier of a peer offline event so that it can be
 	// reported to clients listening for peer events.
 	var pubKey [33]byte
 	copy(pubKey[:], pubSer)
 
+	if s.trafficStats != nil {
+		s.trafficStats.EndSession(pubKey)
+	}
+
 	s.peerNotifier.NotifyPeerOffline(pubKey)
 }
 
@@ -4263,8 +5360,9 @@ func (s *server) ConnectToPeer(addr *lnwire.NetAddress,
 func (s *server) connectToPeer(addr *lnwire.NetAddress,
 	errChan chan<- error, timeout time.Duration) {
 
-	conn, err := brontide.Dial(
+	conn, err := dialNoise(
 		s.identityECDH, addr, timeout, s.cfg.net.Dial,
+		brontideConfig(s.cfg), s.noiseIKPeers,
 	)
 	if err != nil {
 		srvrLog.Errorf("Unable to connect to %v: %v", addr, err)
@@ -4545,16 +5643,96 @@ func newSweepPkScriptGen(
 	}
 }
 
+// sweepAddrPkScript decodes the given sweep address, ensuring that it belongs
+// to the active network, and returns its public key script. Only segwit
+// addresses are accepted, as the watchtower client can't estimate the weight
+// of justice transactions that pay into other outputs.
+func sweepAddrPkScript(addrStr string, params *chaincfg.Params) ([]byte,
+	error) {
+
+	addr, err := btcutil.DecodeAddress(addrStr, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if !addr.IsForNet(params) {
+		return nil, fmt.Errorf("address %v is not for network %v",
+			addrStr, params.Name)
+	}
+
+	switch addr.(type) {
+	case *btcutil.AddressWitnessPubKeyHash,
+		*btcutil.AddressWitnessScriptHash,
+		*btcutil.AddressTaproot:
+
+	default:
+		return nil, fmt.Errorf("address %v is not a P2WKH, P2WSH or "+
+			"P2TR address", addrStr)
+	}
+
+	return txscript.PayToAddrScript(addr)
+}
+
+// sweepTowerAnchor bumps the fee of a justice transaction published by our
+// watchtower by handing its anchor output, which pays to our wallet, to the
+// sweeper. The sweeper adds wallet inputs as needed so that the justice
+// transaction and the sweep together pay the requested fee rate.
+func (s *server) sweepTowerAnchor(anchor *wtdb.JusticeAnchor) error {
+	utxo, err := s.cc.Wallet.FetchInputInfo(&anchor.OutPoint)
+	if err != nil {
+		return err
+	}
+
+	signDesc := &input.SignDescriptor{
+		Output:   anchor.TxOut,
+		HashType: txscript.SigHashAll,
+	}
+
+	var witnessType input.WitnessType
+	switch utxo.AddressType {
+	case lnwallet.WitnessPubKey:
+		witnessType = input.WitnessKeyHash
+	case lnwallet.NestedWitnessPubKey:
+		witnessType = input.NestedWitnessKeyHash
+	case lnwallet.TaprootPubkey:
+		witnessType = input.TaprootPubKeySpend
+		signDesc.HashType = txscript.SigHashDefault
+	default:
+		return fmt.Errorf("unknown input witness %v", anchor.OutPoint)
+	}
+
+	// The justice transaction is unconfirmed, so we use the current
+	// height as the height hint.
+	_, currentHeight, err := s.cc.ChainIO.GetBestBlock()
+	if err != nil {
+		return fmt.Errorf("unable to retrieve current height: %v", err)
+	}
+
+	anchorInput := input.MakeBaseInput(
+		&anchor.OutPoint, witnessType, signDesc, uint32(currentHeight),
+		&input.TxInfo{
+			Fee:    anchor.JusticeFee,
+			Weight: anchor.JusticeWeight,
+		},
+	)
+
+	// The anchor output alone doesn't pay for its sweep, so the sweep is
+	// forced.
+	_, err = s.sweeper.SweepInput(&anchorInput, sweep.Params{
+		Fee: sweep.FeePreference{
+			FeeRate: anchor.FeeRate,
+		},
+		Force: true,
+	})
+
+	return err
+}
+
 // shouldPeerBootstrap returns true if we should attempt to perform peer
 // bootstrapping to actively seek our peers using the set of active network
 // bootstrappers.
 func shouldPeerBootstrap(cfg *Config) bool {
-	isSimnet := (cfg.Bitcoin.SimNet || cfg.Litecoin.SimNet)
-	isSignet := (cfg.Bitcoin.SigNet || cfg.Litecoin.SigNet)
-	isRegtest := (cfg.Bitcoin.RegTest || cfg.Litecoin.RegTest)
-	isDevNetwork := isSimnet || isSignet || isRegtest
-
 	// TODO(yy): remove the check on simnet/regtest such that the itest is
 	// covering the bootstrapping process.
-	return !cfg.NoNetBootstrap && !isDevNetwork
+	return !cfg.NoNetBootstrap && !cfg.ActiveNetwork.DevNetwork
 }
@@ -18,7 +18,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/chainreg"
+	"github.com/lightningnetwork/lnd/feature"
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/netann"
 	"github.com/stretchr/testify/require"
 )
 
@@ -71,7 +79,7 @@ func TestTLSAutoRegeneration(t *testing.T) {
 		TLSCertDuration: 42 * time.Hour,
 		RPCListeners:    rpcListeners,
 	}
-	_, _, _, cleanUp, err := getTLSConfig(cfg)
+	_, _, _, _, cleanUp, err := getTLSConfig(cfg)
 	if err != nil {
 		t.Fatalf("couldn't retrieve TLS config")
 	}
@@ -154,6 +162,15 @@ func genExpiredCertPair(t *testing.T, certDirPath string) ([]byte, []byte) {
 func TestShouldPeerBootstrap(t *testing.T) {
 	t.Parallel()
 
+	network := func(name string) *chainreg.Network {
+		network, err := chainreg.LookupNetwork(
+			chainreg.BitcoinChain, name,
+		)
+		require.NoError(t, err)
+
+		return network
+	}
+
 	testCases := []struct {
 		cfg            *Config
 		shouldBoostrap bool
@@ -161,40 +178,28 @@ func TestShouldPeerBootstrap(t *testing.T) {
 		// Simnet active, no bootstrap.
 		{
 			cfg: &Config{
-				Bitcoin: &lncfg.Chain{
-					SimNet: true,
-				},
-				Litecoin: &lncfg.Chain{},
+				ActiveNetwork: network("simnet"),
 			},
 		},
 
 		// Regtest active, no bootstrap.
 		{
 			cfg: &Config{
-				Bitcoin: &lncfg.Chain{
-					RegTest: true,
-				},
-				Litecoin: &lncfg.Chain{},
+				ActiveNetwork: network("regtest"),
 			},
 		},
 
 		// Signet active, no bootstrap.
 		{
 			cfg: &Config{
-				Bitcoin: &lncfg.Chain{
-					SigNet: true,
-				},
-				Litecoin: &lncfg.Chain{},
+				ActiveNetwork: network("signet"),
 			},
 		},
 
 		// Mainnet active, but bootstrap disabled, no bootstrap.
 		{
 			cfg: &Config{
-				Bitcoin: &lncfg.Chain{
-					MainNet: true,
-				},
-				Litecoin:       &lncfg.Chain{},
+				ActiveNetwork:  network("mainnet"),
 				NoNetBootstrap: true,
 			},
 		},
@@ -202,10 +207,7 @@ func TestShouldPeerBootstrap(t *testing.T) {
 		// Mainnet active, should bootstrap.
 		{
 			cfg: &Config{
-				Bitcoin: &lncfg.Chain{
-					MainNet: true,
-				},
-				Litecoin: &lncfg.Chain{},
+				ActiveNetwork: network("mainnet"),
 			},
 			shouldBoostrap: true,
 		},
@@ -213,10 +215,7 @@ func TestShouldPeerBootstrap(t *testing.T) {
 		// Testnet active, should bootstrap.
 		{
 			cfg: &Config{
-				Bitcoin: &lncfg.Chain{
-					TestNet3: true,
-				},
-				Litecoin: &lncfg.Chain{},
+				ActiveNetwork: network("testnet"),
 			},
 			shouldBoostrap: true,
 		},
@@ -229,3 +228,136 @@ func TestShouldPeerBootstrap(t *testing.T) {
 		}
 	}
 }
+
+// TestDNSSeeds asserts that the DNS seeds configured by the user take
+// precedence over the seeds of the active network, unless they are invalid.
+func TestDNSSeeds(t *testing.T) {
+	t.Parallel()
+
+	mainnet, err := chainreg.LookupNetwork(chainreg.BitcoinChain, "mainnet")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		tuples   []string
+		expected [][2]string
+	}{
+		{
+			name:     "network seeds",
+			expected: mainnet.DNSSeeds,
+		},
+		{
+			name:   "user seeds",
+			tuples: []string{"seed.example.com,soa.example.com"},
+			expected: [][2]string{
+				{"seed.example.com", "soa.example.com"},
+			},
+		},
+		{
+			name:     "invalid user seeds",
+			tuples:   []string{"a,b,c"},
+			expected: mainnet.DNSSeeds,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &server{
+				cfg: &Config{
+					Bitcoin: &lncfg.Chain{
+						Active:   true,
+						DNSSeeds: tc.tuples,
+					},
+					Litecoin:      &lncfg.Chain{},
+					ActiveNetwork: mainnet,
+				},
+			}
+			require.Equal(t, tc.expected, s.dnsSeeds())
+		})
+	}
+}
+
+// TestSweepAddrPkScript asserts that only segwit addresses of the active
+// network are accepted as the sweep address of the watchtower client.
+func TestSweepAddrPkScript(t *testing.T) {
+	t.Parallel()
+
+	params := &chaincfg.MainNetParams
+	hash20 := bytes.Repeat([]byte{0x01}, 20)
+	hash32 := bytes.Repeat([]byte{0x02}, 32)
+
+	p2wkh, err := btcutil.NewAddressWitnessPubKeyHash(hash20, params)
+	require.NoError(t, err)
+	p2wsh, err := btcutil.NewAddressWitnessScriptHash(hash32, params)
+	require.NoError(t, err)
+	p2tr, err := btcutil.NewAddressTaproot(hash32, params)
+	require.NoError(t, err)
+
+	for _, addr := range []btcutil.Address{p2wkh, p2wsh, p2tr} {
+		_, err := sweepAddrPkScript(addr.EncodeAddress(), params)
+		require.NoError(t, err, addr)
+	}
+
+	// Legacy addresses are rejected, as the weight of justice
+	// transactions paying into them can't be estimated.
+	p2pkh, err := btcutil.NewAddressPubKeyHash(hash20, params)
+	require.NoError(t, err)
+	p2sh, err := btcutil.NewAddressScriptHashFromHash(hash20, params)
+	require.NoError(t, err)
+
+	for _, addr := range []btcutil.Address{p2pkh, p2sh} {
+		_, err := sweepAddrPkScript(addr.EncodeAddress(), params)
+		require.Error(t, err, addr)
+	}
+
+	// Addresses of another network are rejected as well.
+	_, err = sweepAddrPkScript(
+		p2wkh.EncodeAddress(), &chaincfg.TestNet3Params,
+	)
+	require.Error(t, err)
+}
+
+// TestUpdateFeatureBitsRollback asserts that our feature bits and node
+// announcement are left untouched if the node announcement advertising the
+// updated feature bits can't be signed.
+func TestUpdateFeatureBitsRollback(t *testing.T) {
+	featureMgr, err := feature.NewManager(feature.Config{})
+	require.NoError(t, err)
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	// The node signer only signs with the node key, so any announcement
+	// signed with our multisig key locator fails.
+	keySigner := keychain.NewPrivKeyMessageSigner(
+		privKey, keychain.KeyLocator{Family: keychain.KeyFamilyNodeKey},
+	)
+	nodeAnn := &lnwire.NodeAnnouncement{
+		Features: featureMgr.GetRaw(feature.SetNodeAnn),
+	}
+	s := &server{
+		featureMgr: featureMgr,
+		nodeSigner: netann.NewNodeSigner(keySigner),
+		identityKeyLoc: keychain.KeyLocator{
+			Family: keychain.KeyFamilyMultiSig,
+		},
+		currentNodeAnn: nodeAnn,
+	}
+
+	oldSets := featureMgr.Snapshot()
+	oldNodeAnn := *nodeAnn
+
+	err = s.updateFeatureBits(map[lnwire.FeatureBit]bool{
+		lnwire.WumboChannelsOptional: false,
+	}, false)
+	require.Error(t, err)
+
+	require.Equal(t, oldSets, featureMgr.Snapshot())
+	require.True(t, featureMgr.Get(feature.SetInit).HasFeature(
+		lnwire.WumboChannelsOptional,
+	))
+	require.Equal(t, oldNodeAnn, *s.currentNodeAnn)
+}
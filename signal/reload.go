@@ -0,0 +1,12 @@
+//go:build !js
+// +build !js
+
+package signal
+
+import (
+	"os"
+	"syscall"
+)
+
+// reloadSignals are the signals that request the daemon to reload its config.
+var reloadSignals = []os.Signal{syscall.SIGHUP}
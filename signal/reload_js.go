@@ -0,0 +1,7 @@
+package signal
+
+import "os"
+
+// reloadSignals are the signals that request the daemon to reload its config.
+// There is no SIGHUP on js, so reloads can't be requested by a signal.
+var reloadSignals []os.Signal
@@ -99,6 +99,10 @@ type Interceptor struct {
 	// interruptChannel is used to receive SIGINT (Ctrl+C) signals.
 	interruptChannel chan os.Signal
 
+	// reloadChannel is used to receive SIGHUP signals, which request the
+	// daemon to reload its config.
+	reloadChannel chan os.Signal
+
 	// shutdownChannel is closed once the main interrupt handler exits.
 	shutdownChannel chan struct{}
 
@@ -124,6 +128,7 @@ func Intercept() (Interceptor, error) {
 
 	channels := Interceptor{
 		interruptChannel:       make(chan os.Signal, 1),
+		reloadChannel:          make(chan os.Signal, 1),
 		shutdownChannel:        make(chan struct{}),
 		shutdownRequestChannel: make(chan struct{}),
 		quit:                   make(chan struct{}),
@@ -136,6 +141,9 @@ func Intercept() (Interceptor, error) {
 		syscall.SIGQUIT,
 	}
 	signal.Notify(channels.interruptChannel, signalsToCatch...)
+	if len(reloadSignals) > 0 {
+		signal.Notify(channels.reloadChannel, reloadSignals...)
+	}
 	go channels.mainInterruptHandler()
 
 	return channels, nil
@@ -185,6 +193,7 @@ func (c *Interceptor) mainInterruptHandler() {
 			log.Infof("Gracefully shutting down.")
 			close(c.shutdownChannel)
 			signal.Stop(c.interruptChannel)
+			signal.Stop(c.reloadChannel)
 			return
 		}
 	}
@@ -222,6 +231,12 @@ func (c *Interceptor) RequestShutdown() {
 	}
 }
 
+// ReloadChannel returns the channel that receives a signal whenever the daemon
+// is requested to reload its config.
+func (c *Interceptor) ReloadChannel() <-chan os.Signal {
+	return c.reloadChannel
+}
+
 // ShutdownChannel returns the channel that will be closed once the main
 // interrupt handler has exited.
 func (c *Interceptor) ShutdownChannel() <-chan struct{} {
@@ -10,6 +10,8 @@ import (
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/chainreg"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/discovery"
+	"github.com/lightningnetwork/lnd/feature"
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/invoices"
 	"github.com/lightningnetwork/lnd/lncfg"
@@ -121,8 +123,13 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 	getNodeAnnouncement func() (lnwire.NodeAnnouncement, error),
 	updateNodeAnnouncement func(modifiers ...netann.NodeAnnModifier) error,
 	parseAddr func(addr string) (net.Addr, error),
+	getFeatures func(set feature.Set) *lnwire.FeatureVector,
+	updateFeatureBits func(updates map[lnwire.FeatureBit]bool,
+		reconnect bool) error,
+	syncMgr *discovery.SyncManager,
 	rpcLogger btclog.Logger,
-	getAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error)) error {
+	getAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error),
+	learnedPolicy func(uint64) *channeldb.ChannelEdgePolicy) error {
 
 	// First, we'll use reflect to obtain a version of the config struct
 	// that allows us to programmatically inspect its fields.
@@ -197,6 +204,9 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 			subCfgValue.FieldByName("CurrentNumAnchorChans").Set(
 				reflect.ValueOf(cc.Wallet.CurrentNumAnchorChans),
 			)
+			subCfgValue.FieldByName("OutputLabels").Set(
+				reflect.ValueOf(chanStateDB),
+			)
 
 		case *autopilotrpc.Config:
 			subCfgValue := extractReflectValue(subCfg)
@@ -261,6 +271,15 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 			subCfgValue.FieldByName("GetAlias").Set(
 				reflect.ValueOf(getAlias),
 			)
+			subCfgValue.FieldByName("LearnedPolicy").Set(
+				reflect.ValueOf(learnedPolicy),
+			)
+			subCfgValue.FieldByName("InvoiceGcExpiredAfter").Set(
+				reflect.ValueOf(cfg.Invoices.GcExpiredAfter),
+			)
+			subCfgValue.FieldByName("InvoiceGcArchiveFile").Set(
+				reflect.ValueOf(cfg.Invoices.GcArchiveFile),
+			)
 
 		case *neutrinorpc.Config:
 			subCfgValue := extractReflectValue(subCfg)
@@ -268,6 +287,9 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 			subCfgValue.FieldByName("NeutrinoCS").Set(
 				reflect.ValueOf(cc.Cfg.NeutrinoCS),
 			)
+			subCfgValue.FieldByName("BanStore").Set(
+				reflect.ValueOf(&cc.Cfg.NeutrinoBanStore).Elem(),
+			)
 
 		// RouterRPC isn't conditionally compiled and doesn't need to be
 		// populated using reflection.
@@ -297,9 +319,15 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 					reflect.ValueOf(anchorTowerClient),
 				)
 			}
+			subCfgValue.FieldByName("ChanStateDB").Set(
+				reflect.ValueOf(chanStateDB),
+			)
 			subCfgValue.FieldByName("Resolver").Set(
 				reflect.ValueOf(tcpResolver),
 			)
+			subCfgValue.FieldByName("LookupHost").Set(
+				reflect.ValueOf(cfg.net.LookupHost),
+			)
 			subCfgValue.FieldByName("Log").Set(
 				reflect.ValueOf(rpcLogger),
 			)
@@ -315,6 +343,10 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 				reflect.ValueOf(graphDB),
 			)
 
+			subCfgValue.FieldByName("HtlcSwitch").Set(
+				reflect.ValueOf(htlcSwitch),
+			)
+
 		case *peersrpc.Config:
 			subCfgValue := extractReflectValue(subCfg)
 
@@ -330,6 +362,22 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 				reflect.ValueOf(updateNodeAnnouncement),
 			)
 
+			subCfgValue.FieldByName("GetFeatures").Set(
+				reflect.ValueOf(getFeatures),
+			)
+
+			subCfgValue.FieldByName("UpdateFeatureBits").Set(
+				reflect.ValueOf(updateFeatureBits),
+			)
+
+			subCfgValue.FieldByName("SetGossipFilter").Set(
+				reflect.ValueOf(syncMgr.SetGossipFilter),
+			)
+
+			subCfgValue.FieldByName("GossipFilters").Set(
+				reflect.ValueOf(syncMgr.GossipFilters),
+			)
+
 		default:
 			return fmt.Errorf("unknown field: %v, %T", fieldName,
 				cfg)
@@ -24,6 +24,10 @@ type mockBackend struct {
 	unconfirmedTxes        map[chainhash.Hash]*wire.MsgTx
 	unconfirmedSpendInputs map[wire.OutPoint]struct{}
 
+	// replaceable holds the unconfirmed txes that are replaced by a
+	// conflicting tx instead of causing it to be rejected.
+	replaceable map[chainhash.Hash]struct{}
+
 	publishChan chan wire.MsgTx
 
 	walletUtxos []*lnwallet.Utxo
@@ -37,6 +41,7 @@ func newMockBackend(t *testing.T, notifier *MockNotifier) *mockBackend {
 		unconfirmedTxes:        make(map[chainhash.Hash]*wire.MsgTx),
 		confirmedSpendInputs:   make(map[wire.OutPoint]struct{}),
 		unconfirmedSpendInputs: make(map[wire.OutPoint]struct{}),
+		replaceable:            make(map[chainhash.Hash]struct{}),
 		publishChan:            make(chan wire.MsgTx, 2),
 	}
 }
@@ -52,6 +57,25 @@ func (b *mockBackend) publishTransaction(tx *wire.MsgTx) error {
 		return lnwallet.ErrDoubleSpend
 	}
 
+	// Evict the conflicting txes if all of them are replaceable.
+	var conflicts []*wire.MsgTx
+	for _, in := range tx.TxIn {
+		conflict := b.mempoolSpend(in.PreviousOutPoint)
+		if conflict == nil {
+			continue
+		}
+
+		if _, ok := b.replaceable[conflict.TxHash()]; !ok {
+			conflicts = nil
+			break
+		}
+
+		conflicts = append(conflicts, conflict)
+	}
+	for _, conflict := range conflicts {
+		b.removeUnconfirmed(conflict)
+	}
+
 	for _, in := range tx.TxIn {
 		if _, ok := b.unconfirmedSpendInputs[in.PreviousOutPoint]; ok {
 			// Double spend
@@ -120,7 +144,14 @@ func (b *mockBackend) deleteUnconfirmed(txHash chainhash.Hash) {
 	}
 
 	testLog.Tracef("mockBackend delete tx %v", tx.TxHash())
-	delete(b.unconfirmedTxes, txHash)
+	b.removeUnconfirmed(tx)
+}
+
+// removeUnconfirmed removes the given tx from the mempool.
+//
+// NOTE: The caller must hold the lock.
+func (b *mockBackend) removeUnconfirmed(tx *wire.MsgTx) {
+	delete(b.unconfirmedTxes, tx.TxHash())
 	for _, in := range tx.TxIn {
 		delete(b.unconfirmedSpendInputs, in.PreviousOutPoint)
 	}
@@ -148,6 +179,33 @@ func (b *mockBackend) mine() {
 	}
 }
 
+// lookupMempoolSpend returns the unconfirmed tx that spends the given
+// outpoint, if any.
+func (b *mockBackend) lookupMempoolSpend(op wire.OutPoint) (*wire.MsgTx,
+	error) {
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.mempoolSpend(op), nil
+}
+
+// mempoolSpend returns the unconfirmed tx that spends the given outpoint, if
+// any.
+//
+// NOTE: The caller must hold the lock.
+func (b *mockBackend) mempoolSpend(op wire.OutPoint) *wire.MsgTx {
+	for _, tx := range b.unconfirmedTxes {
+		for _, in := range tx.TxIn {
+			if in.PreviousOutPoint == op {
+				return tx
+			}
+		}
+	}
+
+	return nil
+}
+
 func (b *mockBackend) isDone() bool {
 	return len(b.unconfirmedTxes) == 0
 }
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/kvdb"
@@ -27,6 +28,13 @@ var (
 	// maps: txHash -> empty slice
 	txHashesBucketKey = []byte("sweeper-tx-hashes")
 
+	// feeSharesBucketKey is the key that points to a bucket containing
+	// the share of the fee of each published sweep tx that is attributed
+	// to each of its inputs.
+	//
+	// maps: txHash || outpoint -> fee share
+	feeSharesBucketKey = []byte("sweeper-fee-shares")
+
 	// utxnChainPrefix is the bucket prefix for nursery buckets.
 	utxnChainPrefix = []byte("utxn")
 
@@ -41,6 +49,8 @@ var (
 	byteOrder = binary.BigEndian
 
 	errNoTxHashesBucket = errors.New("tx hashes bucket does not exist")
+
+	errNoFeeSharesBucket = errors.New("fee shares bucket does not exist")
 )
 
 // SweeperStore stores published txes.
@@ -49,8 +59,15 @@ type SweeperStore interface {
 	// hash.
 	IsOurTx(hash chainhash.Hash) (bool, error)
 
-	// NotifyPublishTx signals that we are about to publish a tx.
-	NotifyPublishTx(*wire.MsgTx) error
+	// NotifyPublishTx signals that we are about to publish a tx. The share
+	// of the fee of the tx attributed to each of its inputs is stored
+	// along with it.
+	NotifyPublishTx(*wire.MsgTx, map[wire.OutPoint]btcutil.Amount) error
+
+	// FeeShare returns the share of the fee of a tx that we published
+	// which is attributed to the given input. Zero is returned if the
+	// share isn't known.
+	FeeShare(hash chainhash.Hash, op wire.OutPoint) (btcutil.Amount, error)
 
 	// GetLastPublishedTx returns the last tx that we called NotifyPublishTx
 	// for.
@@ -76,6 +93,11 @@ func NewSweeperStore(db kvdb.Backend, chainHash *chainhash.Hash) (
 			return err
 		}
 
+		_, err = tx.CreateTopLevelBucket(feeSharesBucketKey)
+		if err != nil {
+			return err
+		}
+
 		if tx.ReadWriteBucket(txHashesBucketKey) != nil {
 			return nil
 		}
@@ -168,8 +190,11 @@ func migrateTxHashes(tx kvdb.RwTx, txHashesBucket kvdb.RwBucket,
 	return nil
 }
 
-// NotifyPublishTx signals that we are about to publish a tx.
-func (s *sweeperStore) NotifyPublishTx(sweepTx *wire.MsgTx) error {
+// NotifyPublishTx signals that we are about to publish a tx. The share of the
+// fee of the tx attributed to each of its inputs is stored along with it.
+func (s *sweeperStore) NotifyPublishTx(sweepTx *wire.MsgTx,
+	feeShares map[wire.OutPoint]btcutil.Amount) error {
+
 	return kvdb.Update(s.db, func(tx kvdb.RwTx) error {
 		lastTxBucket := tx.ReadWriteBucket(lastTxBucketKey)
 		if lastTxBucket == nil {
@@ -191,11 +216,76 @@ func (s *sweeperStore) NotifyPublishTx(sweepTx *wire.MsgTx) error {
 		}
 
 		hash := sweepTx.TxHash()
+		if err := txHashesBucket.Put(hash[:], []byte{}); err != nil {
+			return err
+		}
 
-		return txHashesBucket.Put(hash[:], []byte{})
+		feeSharesBucket := tx.ReadWriteBucket(feeSharesBucketKey)
+		if feeSharesBucket == nil {
+			return errNoFeeSharesBucket
+		}
+
+		for op, share := range feeShares {
+			var value [8]byte
+			byteOrder.PutUint64(value[:], uint64(share))
+
+			err := feeSharesBucket.Put(
+				feeShareKey(hash, op), value[:],
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
 	}, func() {})
 }
 
+// feeShareKey returns the key under which the fee share of the given input of
+// the tx with the given hash is stored.
+func feeShareKey(hash chainhash.Hash, op wire.OutPoint) []byte {
+	key := make([]byte, 2*chainhash.HashSize+4)
+	copy(key, hash[:])
+	copy(key[chainhash.HashSize:], op.Hash[:])
+	byteOrder.PutUint32(key[2*chainhash.HashSize:], op.Index)
+
+	return key
+}
+
+// FeeShare returns the share of the fee of a tx that we published which is
+// attributed to the given input. Zero is returned if the share isn't known.
+func (s *sweeperStore) FeeShare(hash chainhash.Hash,
+	op wire.OutPoint) (btcutil.Amount, error) {
+
+	var share btcutil.Amount
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		feeSharesBucket := tx.ReadBucket(feeSharesBucketKey)
+		if feeSharesBucket == nil {
+			return errNoFeeSharesBucket
+		}
+
+		value := feeSharesBucket.Get(feeShareKey(hash, op))
+		if value == nil {
+			return nil
+		}
+		if len(value) != 8 {
+			return fmt.Errorf("invalid fee share for %v in %v", op,
+				hash)
+		}
+
+		share = btcutil.Amount(byteOrder.Uint64(value))
+
+		return nil
+	}, func() {
+		share = 0
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return share, nil
+}
+
 // GetLastPublishedTx returns the last tx that we called NotifyPublishTx
 // for.
 func (s *sweeperStore) GetLastPublishedTx() (*wire.MsgTx, error) {
@@ -1,6 +1,7 @@
 package sweep
 
 import (
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 )
@@ -8,14 +9,18 @@ import (
 // MockSweeperStore is a mock implementation of sweeper store. This type is
 // exported, because it is currently used in nursery tests too.
 type MockSweeperStore struct {
-	lastTx  *wire.MsgTx
-	ourTxes map[chainhash.Hash]struct{}
+	lastTx    *wire.MsgTx
+	ourTxes   map[chainhash.Hash]struct{}
+	feeShares map[chainhash.Hash]map[wire.OutPoint]btcutil.Amount
 }
 
 // NewMockSweeperStore returns a new instance.
 func NewMockSweeperStore() *MockSweeperStore {
 	return &MockSweeperStore{
 		ourTxes: make(map[chainhash.Hash]struct{}),
+		feeShares: make(
+			map[chainhash.Hash]map[wire.OutPoint]btcutil.Amount,
+		),
 	}
 }
 
@@ -27,14 +32,25 @@ func (s *MockSweeperStore) IsOurTx(hash chainhash.Hash) (bool, error) {
 }
 
 // NotifyPublishTx signals that we are about to publish a tx.
-func (s *MockSweeperStore) NotifyPublishTx(tx *wire.MsgTx) error {
+func (s *MockSweeperStore) NotifyPublishTx(tx *wire.MsgTx,
+	feeShares map[wire.OutPoint]btcutil.Amount) error {
+
 	txHash := tx.TxHash()
 	s.ourTxes[txHash] = struct{}{}
+	s.feeShares[txHash] = feeShares
 	s.lastTx = tx
 
 	return nil
 }
 
+// FeeShare returns the share of the fee of a tx that we published which is
+// attributed to the given input.
+func (s *MockSweeperStore) FeeShare(hash chainhash.Hash,
+	op wire.OutPoint) (btcutil.Amount, error) {
+
+	return s.feeShares[hash][op], nil
+}
+
 // GetLastPublishedTx returns the last tx that we called NotifyPublishTx
 // for.
 func (s *MockSweeperStore) GetLastPublishedTx() (*wire.MsgTx, error) {
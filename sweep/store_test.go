@@ -3,6 +3,7 @@ package sweep
 import (
 	"testing"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/channeldb"
@@ -64,7 +65,7 @@ func testStore(t *testing.T, createStore func() (SweeperStore, error)) {
 		},
 	})
 
-	err = store.NotifyPublishTx(&tx1)
+	err = store.NotifyPublishTx(&tx1, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -77,7 +78,10 @@ func testStore(t *testing.T, createStore func() (SweeperStore, error)) {
 		},
 	})
 
-	err = store.NotifyPublishTx(&tx2)
+	tx2FeeShares := map[wire.OutPoint]btcutil.Amount{
+		tx2.TxIn[0].PreviousOutPoint: 1000,
+	}
+	err = store.NotifyPublishTx(&tx2, tx2FeeShares)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -115,6 +119,16 @@ func testStore(t *testing.T, createStore func() (SweeperStore, error)) {
 		t.Fatal("expected tx to be ours")
 	}
 
+	// The fee shares of tx2 should have been persisted, while tx1 was
+	// published without any.
+	share, err := store.FeeShare(tx2.TxHash(), tx2.TxIn[0].PreviousOutPoint)
+	require.NoError(t, err)
+	require.Equal(t, btcutil.Amount(1000), share)
+
+	share, err = store.FeeShare(tx1.TxHash(), tx1.TxIn[0].PreviousOutPoint)
+	require.NoError(t, err)
+	require.Zero(t, share)
+
 	// An different hash should be reported as not being ours.
 	var unknownHash chainhash.Hash
 	ours, err = store.IsOurTx(unknownHash)
@@ -37,6 +37,12 @@ const (
 	//   #1: min = 1 sat/vbyte, max = 10 sat/vbyte
 	//   #2: min = 11 sat/vbyte, max = 20 sat/vbyte...
 	DefaultFeeRateBucketSize = 10
+
+	// maxConflictReplacements is the number of times we try to replace a
+	// third party transaction that spends one of our inputs in the
+	// mempool, doubling the fee rate each time, before we wait for the
+	// conflict to be resolved.
+	maxConflictReplacements = 3
 )
 
 var (
@@ -130,6 +136,29 @@ type pendingInput struct {
 	// lastFeeRate is the most recent fee rate used for this input within a
 	// transaction broadcast to the network.
 	lastFeeRate chainfee.SatPerKWeight
+
+	// conflictTxid is the hash of a transaction that isn't ours which
+	// spends this input in the mempool. As long as it is set, the input
+	// is only swept on its own in an attempt to replace the conflicting
+	// transaction.
+	conflictTxid *chainhash.Hash
+
+	// replaceAttempts is the number of replacements of the conflicting
+	// transaction that were rejected so far. Once it reaches
+	// maxConflictReplacements, the input is left alone until the
+	// conflict is resolved.
+	replaceAttempts int
+
+	// replaceFeeRate is the fee rate of the next attempt to replace the
+	// conflicting transaction.
+	replaceFeeRate chainfee.SatPerKWeight
+}
+
+// replacing returns true if the input is spent by a third party in the mempool
+// and we're still trying to replace the conflicting transaction.
+func (p *pendingInput) replacing() bool {
+	return p.conflictTxid != nil &&
+		p.replaceAttempts < maxConflictReplacements
 }
 
 // parameters returns the sweep parameters for this input.
@@ -184,6 +213,11 @@ type PendingInput struct {
 
 	// Params contains the sweep parameters for this pending request.
 	Params Params
+
+	// ConflictTxid is the hash of a transaction that isn't ours which
+	// spends the input in the mempool, if any. While it is set, the input
+	// is only swept in attempts to replace the conflicting transaction.
+	ConflictTxid *chainhash.Hash
 }
 
 // updateReq is an internal message we'll use to represent an external caller's
@@ -231,7 +265,11 @@ type UtxoSweeper struct {
 
 	currentOutputScript []byte
 
+	// relayFeeRate is the minimum fee rate required for our sweeps to be
+	// relayed. It follows the relay fee of the backend if the fee
+	// estimator tracks it.
 	relayFeeRate chainfee.SatPerKWeight
+	relayFeeMtx  sync.RWMutex
 
 	quit chan struct{}
 	wg   sync.WaitGroup
@@ -296,6 +334,13 @@ type UtxoSweeperConfig struct {
 	//   #1: min = 1 sat/vbyte, max (exclusive) = 11 sat/vbyte
 	//   #2: min = 11 sat/vbyte, max (exclusive) = 21 sat/vbyte...
 	FeeRateBucketSize int
+
+	// LookupMempoolSpend returns the unconfirmed transaction that spends
+	// the given outpoint in the mempool of the chain backend, or nil if
+	// there is none. It is used to detect inputs that are spent by third
+	// parties so they can be dropped from our sweeps instead of being
+	// rebroadcast in vain. If it is nil, such conflicts aren't detected.
+	LookupMempoolSpend func(op wire.OutPoint) (*wire.MsgTx, error)
 }
 
 // Result is the struct that is pushed through the result channel. Callers can
@@ -309,6 +354,13 @@ type Result struct {
 
 	// Tx is the transaction that spent the input.
 	Tx *wire.MsgTx
+
+	// Fee is the share of the fee of our sweep transaction that is
+	// attributed to the input, in proportion to the weight it added to the
+	// transaction. It is zero if the input was spent by another party, or
+	// if the share isn't known, for example because the sweep transaction
+	// was published by an older version that didn't store it.
+	Fee btcutil.Amount
 }
 
 // sweepInputMessage structs are used in the internal channel between the
@@ -364,9 +416,28 @@ func (s *UtxoSweeper) Start() error {
 		}
 	}
 
-	// Retrieve relay fee for dust limit calculation. Assume that this will
-	// not change from here on.
-	s.relayFeeRate = s.cfg.FeeEstimator.RelayFeePerKW()
+	// Retrieve relay fee for dust limit calculation.
+	s.setRelayFeeRate(s.cfg.FeeEstimator.RelayFeePerKW())
+
+	// If the fee estimator tracks the relay fee of the backend, we follow
+	// its changes, so that we don't publish sweeps that can't be relayed
+	// while the mempool minimum fee is raised.
+	var relayFeeUpdates <-chan interface{}
+	if notifier, ok := s.cfg.FeeEstimator.(chainfee.RelayFeeNotifier); ok {
+		relayFeeClient, err := notifier.SubscribeRelayFee()
+		if err != nil {
+			return fmt.Errorf("subscribe relay fee: %v", err)
+		}
+		relayFeeUpdates = relayFeeClient.Updates()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+
+			<-s.quit
+			relayFeeClient.Cancel()
+		}()
+	}
 
 	// We need to register for block epochs and retry sweeping every block.
 	// We should get a notification with the current best block immediately
@@ -382,7 +453,7 @@ func (s *UtxoSweeper) Start() error {
 		defer blockEpochs.Cancel()
 		defer s.wg.Done()
 
-		s.collector(blockEpochs.Epochs)
+		s.collector(blockEpochs.Epochs, relayFeeUpdates)
 
 		// The collector exited and won't longer handle incoming
 		// requests. This can happen on shutdown, when the block
@@ -418,9 +489,21 @@ func (s *UtxoSweeper) Start() error {
 // RelayFeePerKW returns the minimum fee rate required for transactions to be
 // relayed.
 func (s *UtxoSweeper) RelayFeePerKW() chainfee.SatPerKWeight {
+	s.relayFeeMtx.RLock()
+	defer s.relayFeeMtx.RUnlock()
+
 	return s.relayFeeRate
 }
 
+// setRelayFeeRate sets the minimum fee rate required for our sweeps to be
+// relayed.
+func (s *UtxoSweeper) setRelayFeeRate(feeRate chainfee.SatPerKWeight) {
+	s.relayFeeMtx.Lock()
+	defer s.relayFeeMtx.Unlock()
+
+	s.relayFeeRate = feeRate
+}
+
 // Stop stops sweeper from listening to block epochs and constructing sweep
 // txes.
 func (s *UtxoSweeper) Stop() error {
@@ -498,9 +581,9 @@ func (s *UtxoSweeper) feeRateForPreference(
 	if err != nil {
 		return 0, err
 	}
-	if feeRate < s.relayFeeRate {
+	if relayFeeRate := s.RelayFeePerKW(); feeRate < relayFeeRate {
 		return 0, fmt.Errorf("fee preference resulted in invalid fee "+
-			"rate %v, minimum is %v", feeRate, s.relayFeeRate)
+			"rate %v, minimum is %v", feeRate, relayFeeRate)
 	}
 	if feeRate > s.cfg.MaxFeeRate {
 		return 0, fmt.Errorf("fee preference resulted in invalid fee "+
@@ -587,7 +670,9 @@ func (s *UtxoSweeper) removeLastSweepDescendants(spendingTx *wire.MsgTx) error {
 
 // collector is the sweeper main loop. It processes new inputs, spend
 // notifications and counts down to publication of the sweep tx.
-func (s *UtxoSweeper) collector(blockEpochs <-chan *chainntnfs.BlockEpoch) {
+func (s *UtxoSweeper) collector(blockEpochs <-chan *chainntnfs.BlockEpoch,
+	relayFeeUpdates <-chan interface{}) {
+
 	// We registered for the block epochs with a nil request. The notifier
 	// should send us the current best block immediately. So we need to wait
 	// for it here because we need to know the current best height.
@@ -734,8 +819,14 @@ func (s *UtxoSweeper) collector(blockEpochs <-chan *chainntnfs.BlockEpoch) {
 				}
 
 				// Return either a nil or a remote spend result.
-				var err error
-				if !isOurTx {
+				// Only our own sweeps come with a fee share.
+				var (
+					err error
+					fee btcutil.Amount
+				)
+				if isOurTx {
+					fee = s.feeShare(spendHash, outpoint)
+				} else {
 					err = ErrRemoteSpend
 				}
 
@@ -743,6 +834,7 @@ func (s *UtxoSweeper) collector(blockEpochs <-chan *chainntnfs.BlockEpoch) {
 				s.signalAndRemove(&outpoint, Result{
 					Tx:  spend.SpendingTx,
 					Err: err,
+					Fee: fee,
 				})
 
 				// Remove all other inputs in this exclusive
@@ -813,6 +905,30 @@ func (s *UtxoSweeper) collector(blockEpochs <-chan *chainntnfs.BlockEpoch) {
 			log.Debugf("New block: height=%v, sha=%v",
 				epoch.Height, epoch.Hash)
 
+			// The conflicting transactions of our inputs may have
+			// been replaced or evicted from the mempool, so we
+			// check whether they can be swept again.
+			s.refreshConflicts()
+
+			if err := s.scheduleSweep(bestHeight); err != nil {
+				log.Errorf("schedule sweep: %v", err)
+			}
+
+		// The relay fee of the backend changed. Inputs whose fee rate
+		// is below the new relay fee are skipped until it drops again,
+		// and inputs that were skipped before may be swept now.
+		case update := <-relayFeeUpdates:
+			relayFeeUpdate, ok := update.(*chainfee.RelayFeeUpdate)
+			if !ok {
+				continue
+			}
+
+			log.Infof("Relay fee rate changed from %v to %v",
+				relayFeeUpdate.OldFeePerKW,
+				relayFeeUpdate.NewFeePerKW)
+
+			s.setRelayFeeRate(relayFeeUpdate.NewFeePerKW)
+
 			if err := s.scheduleSweep(bestHeight); err != nil {
 				log.Errorf("schedule sweep: %v", err)
 			}
@@ -884,11 +1000,12 @@ func (s *UtxoSweeper) bucketForFeeRate(
 	// to prevent very small outputs (anchors) from becoming uneconomical if
 	// their fee rate would be averaged with higher fee rate inputs in a
 	// regular bucket.
-	if feeRate == s.relayFeeRate {
+	relayFeeRate := s.RelayFeePerKW()
+	if feeRate == relayFeeRate {
 		return 0
 	}
 
-	return 1 + int(feeRate-s.relayFeeRate)/s.cfg.FeeRateBucketSize
+	return 1 + int(feeRate-relayFeeRate)/s.cfg.FeeRateBucketSize
 }
 
 // createInputClusters creates a list of input clusters from the set of pending
@@ -896,7 +1013,22 @@ func (s *UtxoSweeper) bucketForFeeRate(
 // 1) Required tx locktime
 // 2) Similar fee rates.
 func (s *UtxoSweeper) createInputClusters() []inputCluster {
-	inputs := s.pendingInputs
+	// Inputs that are spent by a third party in the mempool are swept on
+	// their own, so the fee rate needed to replace the conflicting
+	// transaction isn't paid for other inputs as well.
+	inputs := make(pendingInputs, len(s.pendingInputs))
+	var replaceClusters []inputCluster
+	for op, input := range s.pendingInputs {
+		switch {
+		case input.conflictTxid == nil:
+			inputs[op] = input
+
+		case input.replacing():
+			replaceClusters = append(
+				replaceClusters, s.replacementCluster(op, input),
+			)
+		}
+	}
 
 	// We start by getting the inputs clusters by locktime. Since the
 	// inputs commit to the locktime, they can only be clustered together
@@ -909,7 +1041,29 @@ func (s *UtxoSweeper) createInputClusters() []inputCluster {
 	// Since the inputs that we clustered by fee rate don't commit to a
 	// specific locktime, we can try to merge a locktime cluster with a fee
 	// cluster.
-	return zipClusters(lockTimeClusters, feeClusters)
+	clusters := zipClusters(lockTimeClusters, feeClusters)
+
+	return append(clusters, replaceClusters...)
+}
+
+// replacementCluster returns a cluster that only holds the given input, which
+// is spent by a third party in the mempool, at the fee rate of the next
+// attempt to replace the conflicting transaction.
+func (s *UtxoSweeper) replacementCluster(op wire.OutPoint,
+	input *pendingInput) inputCluster {
+
+	input.lastFeeRate = input.replaceFeeRate
+
+	cluster := inputCluster{
+		sweepFeeRate: input.replaceFeeRate,
+		inputs:       pendingInputs{op: input},
+	}
+
+	if lt, ok := input.RequiredLockTime(); ok {
+		cluster.lockTime = &lt
+	}
+
+	return cluster
 }
 
 // clusterByLockTime takes the given set of pending inputs and clusters those
@@ -1184,6 +1338,22 @@ func (s *UtxoSweeper) scheduleSweep(currentHeight int32) error {
 	return nil
 }
 
+// feeShare returns the share of the fee of our sweep transaction with the
+// given hash that is attributed to the given input. As the share is only
+// informational, a failure to look it up is logged and zero is returned.
+func (s *UtxoSweeper) feeShare(hash chainhash.Hash,
+	outpoint wire.OutPoint) btcutil.Amount {
+
+	fee, err := s.cfg.Store.FeeShare(hash, outpoint)
+	if err != nil {
+		log.Errorf("Unable to fetch fee share of %v in sweep tx %v: %v",
+			outpoint, hash, err)
+		return 0
+	}
+
+	return fee
+}
+
 // signalAndRemove notifies the listeners of the final result of the input
 // sweep. It cancels any pending spend notification and removes the input from
 // the list of pending inputs. When this function returns, the sweeper has
@@ -1246,6 +1416,13 @@ func (s *UtxoSweeper) getInputLists(cluster inputCluster,
 			continue
 		}
 
+		// Skip inputs that are spent by a third party in the mempool
+		// once we gave up replacing the conflicting transaction, as a
+		// sweep including them would be rejected.
+		if input.conflictTxid != nil && !input.replacing() {
+			continue
+		}
+
 		// Add input to the either one of the lists.
 		if input.publishAttempts == 0 {
 			newInputs = append(newInputs, input)
@@ -1313,8 +1490,10 @@ func (s *UtxoSweeper) sweep(inputs inputSet, feeRate chainfee.SatPerKWeight,
 	// by this tx is ours. Otherwise if the publish doesn't return, but did
 	// publish, we loose track of this tx. Even republication on startup
 	// doesn't prevent this, because that call returns a double spend error
-	// then and would also not add the hash to the store.
-	err = s.cfg.Store.NotifyPublishTx(tx)
+	// then and would also not add the hash to the store. The share of the
+	// fee of each of our inputs is stored along with it, so we can report
+	// it once the transaction confirms, even after a restart.
+	err = s.cfg.Store.NotifyPublishTx(tx, inputFeeShares(inputs, tx))
 	if err != nil {
 		return fmt.Errorf("notify publish tx: %v", err)
 	}
@@ -1344,6 +1523,25 @@ func (s *UtxoSweeper) sweep(inputs inputSet, feeRate chainfee.SatPerKWeight,
 		s.currentOutputScript = nil
 	}
 
+	// Remember which of the inputs were swept to replace a conflicting
+	// transaction, so we can tell whether the replacement succeeded.
+	replacing := make(map[wire.OutPoint]chainhash.Hash)
+	for _, input := range tx.TxIn {
+		pi, ok := s.pendingInputs[input.PreviousOutPoint]
+		if ok && pi.conflictTxid != nil {
+			replacing[input.PreviousOutPoint] = *pi.conflictTxid
+		}
+	}
+
+	// If the sweep was rejected as a double spend, some of its inputs may
+	// have been spent by a third party. We look for those so they are only
+	// swept in attempts to replace the conflicting transaction.
+	if err == lnwallet.ErrDoubleSpend {
+		for _, input := range tx.TxIn {
+			s.checkConflict(input.PreviousOutPoint)
+		}
+	}
+
 	// Reschedule sweep.
 	for _, input := range tx.TxIn {
 		pi, ok := s.pendingInputs[input.PreviousOutPoint]
@@ -1356,6 +1554,33 @@ func (s *UtxoSweeper) sweep(inputs inputSet, feeRate chainfee.SatPerKWeight,
 			continue
 		}
 
+		conflictTxid, wasReplacing := replacing[input.PreviousOutPoint]
+		switch {
+		// Our sweep replaced the conflicting transaction, so the input
+		// is swept like any other from now on.
+		case wasReplacing && err == nil:
+			log.Infof("Sweep tx %v replaced conflicting tx %v of "+
+				"input %v", tx.TxHash(), conflictTxid,
+				input.PreviousOutPoint)
+
+			pi.conflictTxid = nil
+			pi.replaceAttempts = 0
+
+		// The replacement was rejected, so we try again with a higher
+		// fee rate. Inputs spent by a third party don't count towards
+		// the maximum number of sweep attempts.
+		case wasReplacing && pi.conflictTxid != nil &&
+			*pi.conflictTxid == conflictTxid:
+
+			s.bumpReplacement(input.PreviousOutPoint, pi,
+				currentHeight)
+
+			continue
+
+		case pi.conflictTxid != nil:
+			continue
+		}
+
 		// Record another publish attempt.
 		pi.publishAttempts++
 
@@ -1385,6 +1610,130 @@ func (s *UtxoSweeper) sweep(inputs inputSet, feeRate chainfee.SatPerKWeight,
 	return nil
 }
 
+// checkConflict looks up whether the given pending input is spent in the
+// mempool by a transaction that isn't ours, and records or clears the conflict
+// accordingly. Spends by our own sweep transactions aren't conflicts, as those
+// are replaced by our next sweep of the input.
+func (s *UtxoSweeper) checkConflict(outpoint wire.OutPoint) {
+	pi, ok := s.pendingInputs[outpoint]
+	if !ok || s.cfg.LookupMempoolSpend == nil {
+		return
+	}
+
+	spendingTx, err := s.cfg.LookupMempoolSpend(outpoint)
+	if err != nil {
+		log.Errorf("Unable to look up mempool spend of %v: %v",
+			outpoint, err)
+		return
+	}
+
+	var conflictTxid *chainhash.Hash
+	if spendingTx != nil {
+		txid := spendingTx.TxHash()
+		isOurTx, err := s.cfg.Store.IsOurTx(txid)
+		if err != nil {
+			log.Errorf("Cannot determine if tx %v is ours: %v",
+				txid, err)
+			return
+		}
+
+		if !isOurTx {
+			conflictTxid = &txid
+		}
+	}
+
+	switch {
+	case conflictTxid != nil && (pi.conflictTxid == nil ||
+		*pi.conflictTxid != *conflictTxid):
+
+		log.Infof("Input %v is spent by third party tx %v in the "+
+			"mempool, attempting to replace it", outpoint,
+			conflictTxid)
+
+		// Start over with the replacement attempts, as each
+		// conflicting transaction may pay a different fee.
+		pi.replaceAttempts = 0
+		pi.replaceFeeRate = s.replacementFeeRate(pi.lastFeeRate)
+		pi.minPublishHeight = 0
+
+		// There's no point in a replacement that doesn't pay a higher
+		// fee rate than our rejected sweep.
+		if pi.replaceFeeRate <= pi.lastFeeRate {
+			log.Infof("Cannot raise fee rate of input %v above "+
+				"%v, excluding it from sweeps", outpoint,
+				pi.lastFeeRate)
+
+			pi.replaceAttempts = maxConflictReplacements
+		}
+
+	case conflictTxid == nil && pi.conflictTxid != nil:
+		log.Infof("Conflicting tx %v of input %v left the mempool, "+
+			"resuming sweeps", pi.conflictTxid, outpoint)
+
+		// Give the input a fresh chance to be swept right away.
+		pi.minPublishHeight = 0
+		pi.replaceAttempts = 0
+	}
+
+	pi.conflictTxid = conflictTxid
+}
+
+// replacementFeeRate returns the fee rate for an attempt to replace a
+// conflicting transaction that was published at the given fee rate. The fee
+// rate is doubled, but not raised above the maximum fee rate.
+func (s *UtxoSweeper) replacementFeeRate(
+	feeRate chainfee.SatPerKWeight) chainfee.SatPerKWeight {
+
+	feeRate *= 2
+	if feeRate > s.cfg.MaxFeeRate {
+		feeRate = s.cfg.MaxFeeRate
+	}
+
+	return feeRate
+}
+
+// bumpReplacement records a rejected attempt to replace the conflicting
+// transaction of the given input and raises the fee rate of the next attempt,
+// which is made at the next block. Once maxConflictReplacements attempts were
+// rejected, the input is left alone until the conflict is resolved.
+func (s *UtxoSweeper) bumpReplacement(outpoint wire.OutPoint,
+	pi *pendingInput, currentHeight int32) {
+
+	pi.replaceAttempts++
+	pi.minPublishHeight = currentHeight + 1
+
+	feeRate := s.replacementFeeRate(pi.replaceFeeRate)
+	if feeRate <= pi.replaceFeeRate {
+		pi.replaceAttempts = maxConflictReplacements
+	}
+	pi.replaceFeeRate = feeRate
+
+	if pi.replaceAttempts >= maxConflictReplacements {
+		log.Infof("Unable to replace conflicting tx %v of input %v "+
+			"after %v attempts, excluding it from sweeps until "+
+			"the conflict is resolved", pi.conflictTxid, outpoint,
+			pi.replaceAttempts)
+
+		return
+	}
+
+	log.Debugf("Replacement of conflicting tx %v of input %v was "+
+		"rejected, retrying at fee_rate=%v", pi.conflictTxid,
+		outpoint, pi.replaceFeeRate)
+}
+
+// refreshConflicts checks for all pending inputs that are spent by a third
+// party in the mempool whether the conflicting transaction is still there.
+func (s *UtxoSweeper) refreshConflicts() {
+	for outpoint, pi := range s.pendingInputs {
+		if pi.conflictTxid == nil {
+			continue
+		}
+
+		s.checkConflict(outpoint)
+	}
+}
+
 // waitForSpend registers a spend notification with the chain notifier. It
 // returns a cancel function that can be used to cancel the registration.
 func (s *UtxoSweeper) waitForSpend(outpoint wire.OutPoint,
@@ -1470,6 +1819,7 @@ func (s *UtxoSweeper) handlePendingSweepsReq(
 			BroadcastAttempts:   pendingInput.publishAttempts,
 			NextBroadcastHeight: uint32(pendingInput.minPublishHeight),
 			Params:              pendingInput.params,
+			ConflictTxid:        pendingInput.conflictTxid,
 		}
 	}
 
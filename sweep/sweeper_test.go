@@ -147,8 +147,9 @@ func createSweeperTestContext(t *testing.T) *sweeperTestContext {
 			// Use delta func without random factor.
 			return 1 << uint(attempts-1)
 		},
-		MaxFeeRate:        DefaultMaxFeeRate,
-		FeeRateBucketSize: DefaultFeeRateBucketSize,
+		MaxFeeRate:         DefaultMaxFeeRate,
+		FeeRateBucketSize:  DefaultFeeRateBucketSize,
+		LookupMempoolSpend: backend.lookupMempoolSpend,
 	})
 
 	ctx.sweeper.Start()
@@ -1011,6 +1012,201 @@ func TestRetry(t *testing.T) {
 	ctx.finish(1)
 }
 
+// TestMempoolConflict asserts that inputs spent by a third party in the
+// mempool are reported as conflicted and swept on their own at a higher fee
+// rate in an attempt to replace the conflicting transaction, while the other
+// inputs are swept as usual.
+func TestMempoolConflict(t *testing.T) {
+	ctx := createSweeperTestContext(t)
+
+	// The conflicted input must be large enough to pay for its
+	// replacement.
+	largeInput := createTestInput(
+		btcutil.SatoshiPerBitcoin, input.CommitmentTimeLock,
+	)
+	resultChan0, err := ctx.sweeper.SweepInput(&largeInput, defaultFeePref)
+	require.NoError(t, err)
+
+	resultChan1, err := ctx.sweeper.SweepInput(
+		spendableInputs[1], defaultFeePref,
+	)
+	require.NoError(t, err)
+
+	changePk, err := ctx.sweeper.cfg.GenSweepScript()
+	require.NoError(t, err)
+
+	// A third party spends the first input in the mempool.
+	remoteTx := &wire.MsgTx{
+		TxIn: []*wire.TxIn{{
+			PreviousOutPoint: *largeInput.OutPoint(),
+		}},
+	}
+	require.NoError(t, ctx.backend.publishTransaction(remoteTx))
+
+	// Our sweep of both inputs is rejected as a double spend.
+	ctx.tick()
+	sweepTx := ctx.receiveTx()
+	feeRate := ctx.estimator.feePerKW
+	assertTxFeeRate(
+		t, &sweepTx, feeRate, changePk, &largeInput, spendableInputs[1],
+	)
+
+	// The conflicted input is reported as such, while the other one is
+	// still waiting to be retried.
+	pendingInputs, err := ctx.sweeper.PendingInputs()
+	require.NoError(t, err)
+	require.Len(t, pendingInputs, 2)
+
+	remoteTxid := remoteTx.TxHash()
+	input0 := pendingInputs[*largeInput.OutPoint()]
+	require.Equal(t, &remoteTxid, input0.ConflictTxid)
+	require.Zero(t, input0.BroadcastAttempts)
+
+	input1 := pendingInputs[*spendableInputs[1].OutPoint()]
+	require.Nil(t, input1.ConflictTxid)
+	require.Equal(t, 1, input1.BroadcastAttempts)
+
+	// On the next block, we first try to replace the conflicting tx with
+	// a sweep of the conflicted input at twice the fee rate. It is
+	// rejected, as the remote tx can't be replaced.
+	ctx.notifier.NotifyEpoch(mockChainHeight + 1)
+	ctx.tick()
+	replaceTx := ctx.receiveTx()
+	assertTxFeeRate(t, &replaceTx, 2*feeRate, changePk, &largeInput)
+
+	// The input without a conflict is swept as usual.
+	sweepTx = ctx.receiveTx()
+	assertTxSweepsInputs(t, &sweepTx, spendableInputs[1])
+
+	ctx.backend.mine()
+	ctx.expectResult(resultChan1, nil)
+
+	// The remote tx confirmed together with our sweep, so the conflicted
+	// input is reported as spent by the remote party.
+	select {
+	case result := <-resultChan0:
+		require.ErrorIs(t, result.Err, ErrRemoteSpend)
+		require.Equal(t, remoteTxid, result.Tx.TxHash())
+
+	case <-time.After(defaultTestTimeout):
+		t.Fatalf("no result received")
+	}
+
+	ctx.finish(1)
+}
+
+// TestMempoolConflictReplaced asserts that a conflicting transaction that is
+// replaced by our sweep at a higher fee rate no longer marks the input as
+// conflicted.
+func TestMempoolConflictReplaced(t *testing.T) {
+	ctx := createSweeperTestContext(t)
+
+	largeInput := createTestInput(
+		btcutil.SatoshiPerBitcoin, input.CommitmentTimeLock,
+	)
+	resultChan, err := ctx.sweeper.SweepInput(&largeInput, defaultFeePref)
+	require.NoError(t, err)
+
+	// A third party spends the input in the mempool, which makes our sweep
+	// fail.
+	remoteTx := &wire.MsgTx{
+		TxIn: []*wire.TxIn{{
+			PreviousOutPoint: *largeInput.OutPoint(),
+		}},
+	}
+	require.NoError(t, ctx.backend.publishTransaction(remoteTx))
+
+	ctx.tick()
+	ctx.receiveTx()
+
+	// The remote tx pays a fee rate between the one of our rejected sweep
+	// and twice that, so our next attempt replaces it.
+	ctx.backend.replaceable[remoteTx.TxHash()] = struct{}{}
+
+	ctx.notifier.NotifyEpoch(mockChainHeight + 1)
+	ctx.tick()
+	replaceTx := ctx.receiveTx()
+	assertTxSweepsInputs(t, &replaceTx, &largeInput)
+
+	pendingInputs, err := ctx.sweeper.PendingInputs()
+	require.NoError(t, err)
+
+	pendingInput := pendingInputs[*largeInput.OutPoint()]
+	require.Nil(t, pendingInput.ConflictTxid)
+	require.Equal(t, 1, pendingInput.BroadcastAttempts)
+
+	ctx.backend.mine()
+	ctx.expectResult(resultChan, nil)
+
+	ctx.finish(1)
+}
+
+// TestMempoolConflictEvicted asserts that we stop trying to replace a
+// conflicting transaction after maxConflictReplacements attempts, and that the
+// input is swept again once the conflicting transaction is evicted from the
+// mempool.
+func TestMempoolConflictEvicted(t *testing.T) {
+	ctx := createSweeperTestContext(t)
+
+	largeInput := createTestInput(
+		btcutil.SatoshiPerBitcoin, input.CommitmentTimeLock,
+	)
+	resultChan, err := ctx.sweeper.SweepInput(&largeInput, defaultFeePref)
+	require.NoError(t, err)
+
+	changePk, err := ctx.sweeper.cfg.GenSweepScript()
+	require.NoError(t, err)
+
+	// A third party spends the input in the mempool, which makes our sweep
+	// fail.
+	remoteTx := &wire.MsgTx{
+		TxIn: []*wire.TxIn{{
+			PreviousOutPoint: *largeInput.OutPoint(),
+		}},
+	}
+	require.NoError(t, ctx.backend.publishTransaction(remoteTx))
+
+	ctx.tick()
+	ctx.receiveTx()
+
+	// On each of the next blocks, we try to replace the conflicting tx at
+	// twice the fee rate of the previous attempt.
+	feeRate := ctx.estimator.feePerKW
+	height := mockChainHeight
+	for i := 0; i < maxConflictReplacements; i++ {
+		feeRate *= 2
+		height++
+
+		ctx.notifier.NotifyEpoch(height)
+		ctx.tick()
+		replaceTx := ctx.receiveTx()
+		assertTxFeeRate(t, &replaceTx, feeRate, changePk, &largeInput)
+	}
+
+	// All replacements were rejected, so no more sweeps are attempted
+	// while the conflicting tx remains in the mempool.
+	height++
+	ctx.notifier.NotifyEpoch(height)
+	ctx.assertNoTick()
+
+	// Once the conflicting tx is evicted, the input is swept again at its
+	// regular fee rate on the next block.
+	ctx.backend.deleteUnconfirmed(remoteTx.TxHash())
+	height++
+	ctx.notifier.NotifyEpoch(height)
+	ctx.tick()
+
+	sweepTx := ctx.receiveTx()
+	assertTxFeeRate(
+		t, &sweepTx, ctx.estimator.feePerKW, changePk, &largeInput,
+	)
+
+	ctx.backend.mine()
+	ctx.expectResult(resultChan, nil)
+
+	ctx.finish(1)
+}
+
 // TestGiveUp asserts that the sweeper gives up on an input if it can't be swept
 // after a configured number of attempts.a
 func TestGiveUp(t *testing.T) {
@@ -411,3 +411,61 @@ func inputTypeSummary(inputs []input.Input) string {
 	}
 	return strings.Join(parts, ", ")
 }
+
+// inputFeeShares splits the fee paid by the given sweep transaction among its
+// inputs, in proportion to the weight each of them adds to the transaction. An
+// empty map is returned if the value of any of the transaction's inputs isn't
+// known.
+func inputFeeShares(inputs []input.Input,
+	tx *wire.MsgTx) map[wire.OutPoint]btcutil.Amount {
+
+	inputsByOutpoint := make(map[wire.OutPoint]input.Input, len(inputs))
+	for _, inp := range inputs {
+		inputsByOutpoint[*inp.OutPoint()] = inp
+	}
+
+	var (
+		totalIn, totalOut btcutil.Amount
+		totalWeight       int64
+		weights           = make(map[wire.OutPoint]int64, len(tx.TxIn))
+	)
+	for _, txIn := range tx.TxIn {
+		inp, ok := inputsByOutpoint[txIn.PreviousOutPoint]
+		if !ok {
+			return nil
+		}
+		totalIn += btcutil.Amount(inp.SignDesc().Output.Value)
+
+		// The weight of the input is the weight it adds to an empty
+		// transaction, including the output it may commit to.
+		estimator := newWeightEstimator(0)
+		baseWeight := estimator.weight()
+		if err := estimator.add(inp); err != nil {
+			return nil
+		}
+		if inp.RequiredTxOut() != nil {
+			estimator.addOutput(inp.RequiredTxOut())
+		}
+
+		weight := int64(estimator.weight() - baseWeight)
+		weights[txIn.PreviousOutPoint] = weight
+		totalWeight += weight
+	}
+
+	for _, txOut := range tx.TxOut {
+		totalOut += btcutil.Amount(txOut.Value)
+	}
+
+	if totalIn < totalOut || totalWeight == 0 {
+		return nil
+	}
+
+	fee := totalIn - totalOut
+	shares := make(map[wire.OutPoint]btcutil.Amount, len(weights))
+	for outpoint, weight := range weights {
+		shares[outpoint] = fee * btcutil.Amount(weight) /
+			btcutil.Amount(totalWeight)
+	}
+
+	return shares
+}
@@ -158,3 +158,50 @@ func testUnknownScriptInner(t *testing.T, pkscript []byte, expectFail bool) {
 		require.NoError(t, err)
 	}
 }
+
+// TestInputFeeShares asserts that the fee of a sweep transaction is split
+// among its inputs in proportion to their weight.
+func TestInputFeeShares(t *testing.T) {
+	t.Parallel()
+
+	newInput := func(i int, witnessType input.WitnessType,
+		value int64) input.Input {
+
+		return input.NewBaseInput(
+			&wire.OutPoint{Hash: chainhash.Hash{byte(i)}},
+			witnessType, &input.SignDescriptor{
+				Output: &wire.TxOut{Value: value},
+			}, 0,
+		)
+	}
+
+	inputs := []input.Input{
+		newInput(0, input.WitnessKeyHash, 10_000),
+		newInput(1, input.WitnessKeyHash, 20_000),
+		newInput(2, input.CommitmentTimeLock, 30_000),
+	}
+
+	tx := wire.NewMsgTx(2)
+	for _, inp := range inputs {
+		tx.AddTxIn(&wire.TxIn{PreviousOutPoint: *inp.OutPoint()})
+	}
+	tx.AddTxOut(&wire.TxOut{Value: 59_000})
+
+	shares := inputFeeShares(inputs, tx)
+	require.Len(t, shares, 3)
+
+	// Inputs of the same type carry the same weight and therefore pay the
+	// same share, while the larger commitment input pays more.
+	op0, op1, op2 := *inputs[0].OutPoint(), *inputs[1].OutPoint(),
+		*inputs[2].OutPoint()
+	require.Equal(t, shares[op0], shares[op1])
+	require.Greater(t, shares[op2], shares[op0])
+
+	// The shares never exceed the fee paid by the transaction.
+	total := shares[op0] + shares[op1] + shares[op2]
+	require.LessOrEqual(t, int64(total), int64(1_000))
+	require.Greater(t, int64(total), int64(1_000-3))
+
+	// If an input of the transaction is unknown, no shares are returned.
+	require.Nil(t, inputFeeShares(inputs[:2], tx))
+}
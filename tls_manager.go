@@ -0,0 +1,305 @@
+package lnd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/cert"
+)
+
+const (
+	// tlsCertOrganization is the organization of the self-signed TLS
+	// certificates that lnd generates.
+	tlsCertOrganization = "lnd autogenerated cert"
+
+	// tlsRotationCheckInterval is the maximum time the TLS manager waits
+	// before it checks again whether the certificate must be rotated. This
+	// makes sure that changes of the wall clock are picked up.
+	tlsRotationCheckInterval = time.Hour
+
+	// tlsRotationRetryInterval is the time the TLS manager waits before it
+	// tries again to rotate the certificate after a failed attempt.
+	tlsRotationRetryInterval = time.Minute
+)
+
+// tlsManager holds the TLS certificate that is served by the RPC and REST
+// listeners, and regenerates it before it expires or when requested. As the
+// listeners look up the certificate for every new connection, a new
+// certificate is served without restarting them. Existing connections keep
+// using the certificate they were established with.
+type tlsManager struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	// mtx guards the fields below.
+	mtx sync.RWMutex
+
+	// certData and parsedCert are the certificate that is currently
+	// served.
+	certData   tls.Certificate
+	parsedCert *x509.Certificate
+
+	// prevCertData is the certificate that was served before the last
+	// rotation, if any. It's still accepted by the REST proxy, so that a
+	// connection that was being established during a rotation succeeds.
+	prevCertData *tls.Certificate
+
+	// extraIPs and extraDomains are added to the certificate when it's
+	// regenerated.
+	extraIPs     []string
+	extraDomains []string
+
+	// rotated is signaled after every rotation, so that the next
+	// automatic rotation is scheduled for the new certificate.
+	rotated chan struct{}
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newTLSManager creates a TLS manager that serves the given certificate,
+// which was loaded from the paths of the config.
+func newTLSManager(cfg *Config, certData tls.Certificate,
+	parsedCert *x509.Certificate) *tlsManager {
+
+	return &tlsManager{
+		cfg:          cfg,
+		certData:     certData,
+		parsedCert:   parsedCert,
+		extraIPs:     cfg.TLSExtraIPs,
+		extraDomains: cfg.TLSExtraDomains,
+		rotated:      make(chan struct{}, 1),
+		quit:         make(chan struct{}),
+	}
+}
+
+// Start starts the automatic rotation of the certificate if it's enabled.
+func (m *tlsManager) Start() error {
+	m.started.Do(func() {
+		if !m.cfg.TLSAutoRotate {
+			return
+		}
+
+		rpcsLog.Infof("Automatic TLS certificate rotation enabled, "+
+			"rotating %v before expiry", m.cfg.TLSRotateBefore)
+
+		m.wg.Add(1)
+		go m.rotationHandler()
+	})
+
+	return nil
+}
+
+// Stop stops the automatic rotation of the certificate.
+func (m *tlsManager) Stop() error {
+	m.stopped.Do(func() {
+		close(m.quit)
+		m.wg.Wait()
+	})
+
+	return nil
+}
+
+// GetCertificate returns the certificate that is currently served. It is
+// meant to be used as the GetCertificate callback of a tls.Config.
+func (m *tlsManager) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate,
+	error) {
+
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	certData := m.certData
+
+	return &certData, nil
+}
+
+// Certificate returns the parsed certificate that is currently served.
+func (m *tlsManager) Certificate() *x509.Certificate {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	return m.parsedCert
+}
+
+// verifyServerCert checks that the certificate presented by a server is the
+// one we currently serve, or the one served before the last rotation. It is
+// used by the REST proxy, which connects to our own gRPC server, instead of
+// a fixed pool of root certificates that would go stale on rotation.
+func (m *tlsManager) verifyServerCert(rawCerts [][]byte,
+	_ [][]*x509.Certificate) error {
+
+	if len(rawCerts) == 0 {
+		return errors.New("no server certificate presented")
+	}
+
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	if bytes.Equal(rawCerts[0], m.certData.Certificate[0]) {
+		return nil
+	}
+
+	if m.prevCertData != nil &&
+		bytes.Equal(rawCerts[0], m.prevCertData.Certificate[0]) {
+
+		return nil
+	}
+
+	return errors.New("server certificate doesn't match the TLS " +
+		"certificate of lnd")
+}
+
+// Rotate regenerates the TLS certificate and key with the current extra IPs
+// and domains, writes them to disk and starts serving the new certificate.
+// The new certificate is returned.
+func (m *tlsManager) Rotate() (*x509.Certificate, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.rotate()
+}
+
+// SetExtraSANs replaces the extra IPs and domains of the certificate and
+// rotates it if they differ from the ones of the current certificate.
+func (m *tlsManager) SetExtraSANs(extraIPs,
+	extraDomains []string) (*x509.Certificate, error) {
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	outdated, err := cert.IsOutdated(
+		m.parsedCert, extraIPs, extraDomains,
+		m.cfg.TLSDisableAutofill,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.extraIPs = extraIPs
+	m.extraDomains = extraDomains
+
+	if !outdated {
+		return m.parsedCert, nil
+	}
+
+	return m.rotate()
+}
+
+// rotate generates a new certificate and key pair and starts serving it.
+//
+// NOTE: This method must be called while holding the mtx.
+func (m *tlsManager) rotate() (*x509.Certificate, error) {
+	// The new pair is generated next to the current one and only moved in
+	// place once it was loaded successfully, so that a failed rotation
+	// leaves the current pair untouched.
+	tmpCertPath := m.cfg.TLSCertPath + ".tmp"
+	tmpKeyPath := m.cfg.TLSKeyPath + ".tmp"
+	defer func() {
+		_ = os.Remove(tmpCertPath)
+		_ = os.Remove(tmpKeyPath)
+	}()
+
+	rpcsLog.Infof("Rotating TLS certificate...")
+	err := cert.GenCertPair(
+		tlsCertOrganization, tmpCertPath, tmpKeyPath, m.extraIPs,
+		m.extraDomains, m.cfg.TLSDisableAutofill,
+		m.cfg.TLSCertDuration,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	certData, parsedCert, err := cert.LoadCert(tmpCertPath, tmpKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(tmpKeyPath, m.cfg.TLSKeyPath); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmpCertPath, m.cfg.TLSCertPath); err != nil {
+		return nil, err
+	}
+
+	prevCertData := m.certData
+	m.prevCertData = &prevCertData
+	m.certData = certData
+	m.parsedCert = parsedCert
+
+	rpcsLog.Infof("Rotated TLS certificate, new certificate expires at %v",
+		parsedCert.NotAfter)
+
+	select {
+	case m.rotated <- struct{}{}:
+	default:
+	}
+
+	return parsedCert, nil
+}
+
+// tlsRotationDelay returns how long to wait until a certificate that expires
+// at the given time must be rotated.
+func tlsRotationDelay(notAfter time.Time, rotateBefore time.Duration,
+	now time.Time) time.Duration {
+
+	delay := notAfter.Add(-rotateBefore).Sub(now)
+	if delay < 0 {
+		return 0
+	}
+
+	return delay
+}
+
+// rotationHandler rotates the certificate once it's about to expire.
+//
+// NOTE: This method must be run as a goroutine.
+func (m *tlsManager) rotationHandler() {
+	defer m.wg.Done()
+
+	failed := false
+	for {
+		delay := tlsRotationDelay(
+			m.Certificate().NotAfter, m.cfg.TLSRotateBefore,
+			time.Now(),
+		)
+		if failed && delay < tlsRotationRetryInterval {
+			delay = tlsRotationRetryInterval
+		}
+		if delay > tlsRotationCheckInterval {
+			delay = tlsRotationCheckInterval
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+			notAfter := m.Certificate().NotAfter
+			if tlsRotationDelay(notAfter, m.cfg.TLSRotateBefore,
+				time.Now()) > 0 {
+
+				continue
+			}
+
+			_, err := m.Rotate()
+			if err != nil {
+				rpcsLog.Errorf("Unable to rotate TLS "+
+					"certificate: %v", err)
+			}
+			failed = err != nil
+
+		case <-m.rotated:
+			timer.Stop()
+			failed = false
+
+		case <-m.quit:
+			timer.Stop()
+			return
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package lnd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/cert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTLSManagerRotate tests that a rotated certificate is written to disk
+// and served right away, and that the REST proxy accepts it.
+func TestTLSManagerRotate(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := &Config{
+		TLSCertPath:        filepath.Join(tempDir, "tls.cert"),
+		TLSKeyPath:         filepath.Join(tempDir, "tls.key"),
+		TLSDisableAutofill: true,
+		TLSCertDuration:    42 * time.Hour,
+	}
+	err := cert.GenCertPair(
+		tlsCertOrganization, cfg.TLSCertPath, cfg.TLSKeyPath, nil, nil,
+		cfg.TLSDisableAutofill, cfg.TLSCertDuration,
+	)
+	require.NoError(t, err)
+
+	certData, parsedCert, err := cert.LoadCert(
+		cfg.TLSCertPath, cfg.TLSKeyPath,
+	)
+	require.NoError(t, err)
+
+	mgr := newTLSManager(cfg, certData, parsedCert)
+	oldCert := certData.Certificate[0]
+	require.NoError(t, mgr.verifyServerCert([][]byte{oldCert}, nil))
+
+	// Setting the same extra IPs and domains doesn't rotate the
+	// certificate.
+	sameCert, err := mgr.SetExtraSANs(nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, parsedCert, sameCert)
+
+	// Adding a domain rotates the certificate, which is then served and
+	// written to disk.
+	newCert, err := mgr.SetExtraSANs(nil, []string{"node.example.com"})
+	require.NoError(t, err)
+	require.Contains(t, newCert.DNSNames, "node.example.com")
+
+	served, err := mgr.GetCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, newCert.Raw, served.Certificate[0])
+
+	_, diskCert, err := cert.LoadCert(cfg.TLSCertPath, cfg.TLSKeyPath)
+	require.NoError(t, err)
+	require.Equal(t, newCert.Raw, diskCert.Raw)
+
+	// The REST proxy accepts both the new and the previous certificate.
+	require.NoError(t, mgr.verifyServerCert([][]byte{newCert.Raw}, nil))
+	require.NoError(t, mgr.verifyServerCert([][]byte{oldCert}, nil))
+
+	// After another rotation, the first certificate is rejected.
+	_, err = mgr.Rotate()
+	require.NoError(t, err)
+	require.Error(t, mgr.verifyServerCert([][]byte{oldCert}, nil))
+}
+
+// TestTLSRotationDelay tests that a certificate is rotated the configured
+// time before it expires, and right away if that time has passed.
+func TestTLSRotationDelay(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1000000, 0)
+	notAfter := now.Add(10 * time.Hour)
+
+	require.Equal(t,
+		8*time.Hour, tlsRotationDelay(notAfter, 2*time.Hour, now),
+	)
+	require.Zero(t, tlsRotationDelay(notAfter, 10*time.Hour, now))
+	require.Zero(t, tlsRotationDelay(notAfter, 20*time.Hour, now))
+}
@@ -4,7 +4,6 @@ go 1.16
 
 require (
 	github.com/btcsuite/btcd v0.23.1
-	github.com/dvyukov/go-fuzz v0.0.0-20210602112143-b1f3d6f4ef4e
 	github.com/elazarl/go-bindata-assetfs v1.0.1 // indirect
 	github.com/golangci/golangci-lint v1.46.2
 	github.com/ory/go-acc v0.2.6
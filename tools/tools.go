@@ -9,9 +9,6 @@ package lnd
 // dependencies here and pin the version in go.mod.
 import (
 	_ "github.com/btcsuite/btcd"
-	_ "github.com/dvyukov/go-fuzz/go-fuzz"
-	_ "github.com/dvyukov/go-fuzz/go-fuzz-build"
-	_ "github.com/dvyukov/go-fuzz/go-fuzz-dep"
 	_ "github.com/golangci/golangci-lint/cmd/golangci-lint"
 	_ "github.com/ory/go-acc"
 	_ "github.com/rinchsan/gosimports/cmd/gosimports"
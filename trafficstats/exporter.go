@@ -0,0 +1,262 @@
+package trafficstats
+
+import (
+	"encoding/json"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/ticker"
+)
+
+const (
+	// DefaultInterval is the default interval at which the traffic
+	// statistics are exported.
+	DefaultInterval = time.Minute
+
+	// writeTimeout is the maximum time we wait for a batch of snapshots to
+	// be written to the collector.
+	writeTimeout = 10 * time.Second
+)
+
+// Config houses the dependencies of the Exporter.
+type Config struct {
+	// Collector is the address of the socket the traffic statistics are
+	// exported to.
+	Collector net.Addr
+
+	// Dial establishes a connection to the collector.
+	Dial func(network, address string) (net.Conn, error)
+
+	// Ticker signals when the traffic statistics should be exported.
+	Ticker ticker.Ticker
+
+	// Clock is used to timestamp messages and snapshots.
+	Clock clock.Clock
+}
+
+// Exporter keeps per-connection traffic statistics of our peers and
+// periodically exports them to an external collector socket, for example for
+// research or anomaly detection without having to capture packets. Each
+// export writes one JSON encoded Snapshot per line for every open connection
+// and for every connection that was closed since the last export. The
+// statistics only contain message types, sizes and timings, never the
+// content of the messages.
+type Exporter struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	// mu guards sessions and closed.
+	mu sync.Mutex
+
+	// sessions holds the statistics of the open connections, indexed by
+	// the public key of the peer.
+	sessions map[[33]byte]*session
+
+	// closed holds the final snapshots of the connections that were
+	// closed since the last export.
+	closed []*Snapshot
+
+	// conn is the connection to the collector. It is only accessed by the
+	// exportLoop goroutine.
+	conn net.Conn
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a new traffic statistics exporter.
+func New(cfg *Config) *Exporter {
+	return &Exporter{
+		cfg:      cfg,
+		sessions: make(map[[33]byte]*session),
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start launches the goroutine that exports the traffic statistics.
+func (e *Exporter) Start() error {
+	e.started.Do(func() {
+		log.Infof("Exporting peer traffic statistics to %v",
+			e.cfg.Collector)
+
+		e.cfg.Ticker.Resume()
+
+		e.wg.Add(1)
+		go e.exportLoop()
+	})
+
+	return nil
+}
+
+// Stop exports the statistics a last time and shuts down the exporter.
+func (e *Exporter) Stop() error {
+	e.stopped.Do(func() {
+		log.Info("Traffic statistics exporter shutting down")
+
+		close(e.quit)
+		e.wg.Wait()
+
+		e.cfg.Ticker.Stop()
+	})
+
+	return nil
+}
+
+// StartSession starts a new statistics session for a connection that was
+// established with the peer. The statistics of a previous connection to the
+// peer are finalized.
+func (e *Exporter) StartSession(peer [33]byte, addr net.Addr, inbound bool) {
+	now := e.cfg.Clock.Now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.endSession(peer, now)
+	e.sessions[peer] = newSession(peer, addr, inbound, now)
+}
+
+// EndSession finalizes the statistics of the connection to the peer. They are
+// exported a last time with the next batch.
+func (e *Exporter) EndSession(peer [33]byte) {
+	now := e.cfg.Clock.Now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.endSession(peer, now)
+}
+
+// endSession moves the session of the peer, if any, to the closed sessions.
+// The caller must hold mu.
+func (e *Exporter) endSession(peer [33]byte, now time.Time) {
+	s, ok := e.sessions[peer]
+	if !ok {
+		return
+	}
+
+	e.closed = append(e.closed, s.snapshot(now, true))
+	delete(e.sessions, peer)
+}
+
+// RecordMessage accounts a wire message of the given type and size that was
+// sent to or received from the peer. If there's no session for the peer yet,
+// one is started. It is safe to call from the read and write goroutines of
+// the peers, as it doesn't block.
+func (e *Exporter) RecordMessage(peer [33]byte, msgType lnwire.MessageType,
+	size uint32, inbound bool) {
+
+	now := e.cfg.Clock.Now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	s, ok := e.sessions[peer]
+	if !ok {
+		s = newSession(peer, nil, inbound, now)
+		e.sessions[peer] = s
+	}
+
+	s.record(msgType, size, inbound, now)
+}
+
+// Snapshots returns the statistics of all open connections and of the
+// connections that were closed since the last call. The closed connections
+// are only returned once.
+func (e *Exporter) Snapshots() []*Snapshot {
+	now := e.cfg.Clock.Now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	snapshots := make([]*Snapshot, 0, len(e.closed)+len(e.sessions))
+	snapshots = append(snapshots, e.closed...)
+	for _, s := range e.sessions {
+		snapshots = append(snapshots, s.snapshot(now, false))
+	}
+	e.closed = nil
+
+	return snapshots
+}
+
+// exportLoop exports the statistics on every tick of the ticker, and a last
+// time when the exporter is stopped.
+//
+// NOTE: This method MUST be run as a goroutine.
+func (e *Exporter) exportLoop() {
+	defer e.wg.Done()
+
+	defer func() {
+		if e.conn != nil {
+			e.conn.Close()
+		}
+	}()
+
+	for {
+		select {
+		case <-e.cfg.Ticker.Ticks():
+			if err := e.export(); err != nil {
+				log.Warnf("Unable to export traffic "+
+					"statistics: %v", err)
+			}
+
+		case <-e.quit:
+			if err := e.export(); err != nil {
+				log.Warnf("Unable to export final traffic "+
+					"statistics: %v", err)
+			}
+
+			return
+		}
+	}
+}
+
+// export writes the current snapshots to the collector, connecting to it
+// first if needed. If the snapshots can't be delivered, they are dropped and
+// the connection is re-established with the next export.
+func (e *Exporter) export() error {
+	snapshots := e.Snapshots()
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	if e.conn == nil {
+		conn, err := e.cfg.Dial(
+			e.cfg.Collector.Network(), e.cfg.Collector.String(),
+		)
+		if err != nil {
+			return err
+		}
+		e.conn = conn
+	}
+
+	err := e.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err != nil {
+		return e.resetConn(err)
+	}
+
+	encoder := json.NewEncoder(e.conn)
+	for _, snapshot := range snapshots {
+		if err := encoder.Encode(snapshot); err != nil {
+			return e.resetConn(err)
+		}
+	}
+
+	log.Debugf("Exported traffic statistics of %v connections",
+		len(snapshots))
+
+	return nil
+}
+
+// resetConn closes the connection to the collector so that it's
+// re-established with the next export, and returns the given error.
+func (e *Exporter) resetConn(err error) error {
+	e.conn.Close()
+	e.conn = nil
+
+	return err
+}
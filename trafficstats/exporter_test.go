@@ -0,0 +1,141 @@
+package trafficstats
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/stretchr/testify/require"
+)
+
+var testTime = time.Date(2022, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+// TestExporter asserts that the traffic statistics of the connections to our
+// peers are accounted per message type and exported to the collector.
+func TestExporter(t *testing.T) {
+	t.Parallel()
+
+	var (
+		testClock = clock.NewTestClock(testTime)
+		forceTick = ticker.NewForce(time.Hour)
+		conns     = make(chan net.Conn, 1)
+		peerA     = [33]byte{2, 1}
+		peerB     = [33]byte{3, 2}
+		addrA     = &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 9735}
+	)
+
+	exporter := New(&Config{
+		Collector: &net.UnixAddr{Name: "collector.sock", Net: "unix"},
+		Dial: func(network, address string) (net.Conn, error) {
+			require.Equal(t, "unix", network)
+			require.Equal(t, "collector.sock", address)
+
+			local, remote := net.Pipe()
+			conns <- remote
+
+			return local, nil
+		},
+		Ticker: forceTick,
+		Clock:  testClock,
+	})
+	require.NoError(t, exporter.Start())
+	t.Cleanup(func() {
+		require.NoError(t, exporter.Stop())
+	})
+
+	// Peer A connects to us and exchanges a few messages.
+	exporter.StartSession(peerA, addrA, true)
+	exporter.RecordMessage(peerA, lnwire.MsgInit, 20, true)
+
+	testClock.SetTime(testTime.Add(5 * time.Millisecond))
+	exporter.RecordMessage(peerA, lnwire.MsgInit, 22, false)
+	exporter.RecordMessage(peerA, lnwire.MsgPing, 30, true)
+
+	testClock.SetTime(testTime.Add(2 * time.Minute))
+	exporter.RecordMessage(peerA, lnwire.MsgPing, 30, true)
+
+	// We connect to peer B, which immediately disconnects again. Its
+	// session is started by its first message.
+	exporter.RecordMessage(peerB, lnwire.MsgInit, 20, false)
+	exporter.EndSession(peerB)
+
+	// Trigger an export and read the snapshots from the collector.
+	forceTick.Force <- testClock.Now()
+
+	var collector net.Conn
+	select {
+	case collector = <-conns:
+	case <-time.After(time.Second):
+		t.Fatal("exporter didn't connect to collector")
+	}
+
+	// Close the collector before the exporter is stopped, so the final
+	// export doesn't block.
+	t.Cleanup(func() {
+		collector.Close()
+	})
+
+	scanner := bufio.NewScanner(collector)
+	readSnapshot := func() *Snapshot {
+		require.True(t, scanner.Scan())
+
+		var snapshot Snapshot
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &snapshot))
+
+		return &snapshot
+	}
+
+	// The closed session of peer B is exported first.
+	snapshot := readSnapshot()
+	require.Equal(t, "0302", snapshot.Peer[:4])
+	require.True(t, snapshot.Closed)
+	require.False(t, snapshot.Inbound)
+	require.Empty(t, snapshot.Address)
+	require.Equal(t, map[string]MessageStats{
+		"Init": {OutMsgs: 1, OutBytes: 20},
+	}, snapshot.Messages)
+
+	snapshot = readSnapshot()
+	require.Equal(t, "0201", snapshot.Peer[:4])
+	require.False(t, snapshot.Closed)
+	require.True(t, snapshot.Inbound)
+	require.Equal(t, addrA.String(), snapshot.Address)
+	require.True(t, testTime.Equal(snapshot.SessionStart))
+	require.Equal(t, map[string]MessageStats{
+		"Init": {InMsgs: 1, InBytes: 20, OutMsgs: 1, OutBytes: 22},
+		"Ping": {InMsgs: 2, InBytes: 60},
+	}, snapshot.Messages)
+
+	// The gaps between the inbound messages were 5ms and almost two
+	// minutes, the single outbound message has no gap yet.
+	require.Equal(t, []uint64{0, 1, 0, 0, 0, 0, 1}, snapshot.InGaps.Counts)
+	require.Equal(t, []uint64{0, 0, 0, 0, 0, 0, 0}, snapshot.OutGaps.Counts)
+	require.Equal(
+		t, []int64{1, 10, 100, 1000, 10000, 60000},
+		snapshot.InGaps.BoundsMs,
+	)
+
+	// All inbound messages fall into the smallest size bucket.
+	require.Equal(t, []uint64{3, 0, 0, 0, 0, 0}, snapshot.InSizes.Counts)
+
+	// The closed session is only exported once, the open session is
+	// exported again with the next batch.
+	snapshots := exporter.Snapshots()
+	require.Len(t, snapshots, 1)
+	require.False(t, snapshots[0].Closed)
+
+	// When peer A reconnects, the statistics of its previous connection
+	// are finalized and a new session is started.
+	exporter.StartSession(peerA, addrA, false)
+	snapshots = exporter.Snapshots()
+	require.Len(t, snapshots, 2)
+	require.True(t, snapshots[0].Closed)
+	require.Len(t, snapshots[0].Messages, 2)
+	require.False(t, snapshots[1].Closed)
+	require.Empty(t, snapshots[1].Messages)
+}
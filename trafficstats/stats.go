@@ -0,0 +1,268 @@
+package trafficstats
+
+import (
+	"encoding/hex"
+	"math"
+	"net"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// GapBuckets are the upper bounds of the buckets of the histograms that count
+// the time between two consecutive messages sent or received on a
+// connection. Gaps larger than the last bound are counted in an additional
+// overflow bucket.
+var GapBuckets = []time.Duration{
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+	10 * time.Second,
+	time.Minute,
+}
+
+// SizeBuckets are the upper bounds in bytes of the buckets of the histograms
+// that count the size of the messages received on a connection. The last
+// bound is the maximum size of a message.
+var SizeBuckets = []uint32{64, 256, 1024, 4096, 16384, math.MaxUint16}
+
+// MessageStats are the traffic counters of a single message type.
+type MessageStats struct {
+	// InMsgs is the number of messages received from the peer.
+	InMsgs uint64 `json:"in_msgs"`
+
+	// InBytes is the total size of the messages received from the peer.
+	InBytes uint64 `json:"in_bytes"`
+
+	// OutMsgs is the number of messages sent to the peer.
+	OutMsgs uint64 `json:"out_msgs"`
+
+	// OutBytes is the total size of the messages sent to the peer.
+	OutBytes uint64 `json:"out_bytes"`
+}
+
+// Histogram counts the time between consecutive messages in one direction of
+// a connection.
+type Histogram struct {
+	// BoundsMs are the upper bounds of the buckets in milliseconds.
+	BoundsMs []int64 `json:"bounds_ms"`
+
+	// Counts are the number of gaps within each bucket. It has one more
+	// entry than BoundsMs, which counts the gaps that exceed the last
+	// bound.
+	Counts []uint64 `json:"counts"`
+}
+
+// newHistogram returns an empty histogram with the buckets of GapBuckets.
+func newHistogram() Histogram {
+	bounds := make([]int64, len(GapBuckets))
+	for i, bound := range GapBuckets {
+		bounds[i] = bound.Milliseconds()
+	}
+
+	return Histogram{
+		BoundsMs: bounds,
+		Counts:   make([]uint64, len(GapBuckets)+1),
+	}
+}
+
+// add counts the given gap in the bucket it belongs to.
+func (h *Histogram) add(gap time.Duration) {
+	for i, bound := range GapBuckets {
+		if gap <= bound {
+			h.Counts[i]++
+			return
+		}
+	}
+
+	h.Counts[len(GapBuckets)]++
+}
+
+// copy returns a deep copy of the histogram.
+func (h *Histogram) copy() Histogram {
+	counts := make([]uint64, len(h.Counts))
+	copy(counts, h.Counts)
+
+	return Histogram{
+		BoundsMs: h.BoundsMs,
+		Counts:   counts,
+	}
+}
+
+// SizeHistogram counts the size of the messages received from a peer. An
+// unusual share of large messages can point to a peer that tries to waste our
+// resources with expensive messages.
+type SizeHistogram struct {
+	// BoundsBytes are the upper bounds of the buckets in bytes.
+	BoundsBytes []uint32 `json:"bounds_bytes"`
+
+	// Counts are the number of messages within each bucket.
+	Counts []uint64 `json:"counts"`
+}
+
+// newSizeHistogram returns an empty histogram with the buckets of
+// SizeBuckets.
+func newSizeHistogram() SizeHistogram {
+	return SizeHistogram{
+		BoundsBytes: SizeBuckets,
+		Counts:      make([]uint64, len(SizeBuckets)),
+	}
+}
+
+// add counts a message of the given size in the bucket it belongs to.
+func (h *SizeHistogram) add(size uint32) {
+	for i, bound := range SizeBuckets {
+		if size <= bound {
+			h.Counts[i]++
+			return
+		}
+	}
+
+	// Messages can't exceed the last bound, but we count them in the
+	// largest bucket should the limit ever be raised.
+	h.Counts[len(SizeBuckets)-1]++
+}
+
+// copy returns a deep copy of the histogram.
+func (h *SizeHistogram) copy() SizeHistogram {
+	counts := make([]uint64, len(h.Counts))
+	copy(counts, h.Counts)
+
+	return SizeHistogram{
+		BoundsBytes: h.BoundsBytes,
+		Counts:      counts,
+	}
+}
+
+// Snapshot holds the traffic statistics of a single connection to a peer at
+// a point in time. Snapshots are exported to the collector as JSON objects,
+// one per line.
+type Snapshot struct {
+	// Peer is the hex encoded public key of the peer.
+	Peer string `json:"peer"`
+
+	// Address is the network address of the connection, if known.
+	Address string `json:"address,omitempty"`
+
+	// Inbound indicates whether the connection was initiated by the peer.
+	Inbound bool `json:"inbound"`
+
+	// SessionStart is the time the connection was established.
+	SessionStart time.Time `json:"session_start"`
+
+	// Timestamp is the time the snapshot was taken.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Closed indicates that the connection was closed, so this is the
+	// last snapshot for it.
+	Closed bool `json:"closed"`
+
+	// Messages are the traffic counters of the connection, indexed by the
+	// name of the message type.
+	Messages map[string]MessageStats `json:"messages"`
+
+	// InGaps is the histogram of the time between consecutive messages
+	// received from the peer.
+	InGaps Histogram `json:"in_gaps"`
+
+	// OutGaps is the histogram of the time between consecutive messages
+	// sent to the peer.
+	OutGaps Histogram `json:"out_gaps"`
+
+	// InSizes is the histogram of the size of the messages received from
+	// the peer.
+	InSizes SizeHistogram `json:"in_sizes"`
+}
+
+// session tracks the traffic of a single connection to a peer.
+type session struct {
+	peer    [33]byte
+	address net.Addr
+	inbound bool
+	start   time.Time
+
+	messages map[lnwire.MessageType]*MessageStats
+	inGaps   Histogram
+	outGaps  Histogram
+	inSizes  SizeHistogram
+
+	// lastIn and lastOut are the times the last message was received from
+	// and sent to the peer, respectively.
+	lastIn  time.Time
+	lastOut time.Time
+}
+
+// newSession creates the statistics of a connection established at the given
+// time.
+func newSession(peer [33]byte, addr net.Addr, inbound bool,
+	start time.Time) *session {
+
+	return &session{
+		peer:     peer,
+		address:  addr,
+		inbound:  inbound,
+		start:    start,
+		messages: make(map[lnwire.MessageType]*MessageStats),
+		inGaps:   newHistogram(),
+		outGaps:  newHistogram(),
+		inSizes:  newSizeHistogram(),
+	}
+}
+
+// record accounts a message of the given type and size that was sent or
+// received at the given time.
+func (s *session) record(msgType lnwire.MessageType, size uint32,
+	inbound bool, now time.Time) {
+
+	stats, ok := s.messages[msgType]
+	if !ok {
+		stats = &MessageStats{}
+		s.messages[msgType] = stats
+	}
+
+	if inbound {
+		stats.InMsgs++
+		stats.InBytes += uint64(size)
+		s.inSizes.add(size)
+
+		if !s.lastIn.IsZero() {
+			s.inGaps.add(now.Sub(s.lastIn))
+		}
+		s.lastIn = now
+
+		return
+	}
+
+	stats.OutMsgs++
+	stats.OutBytes += uint64(size)
+
+	if !s.lastOut.IsZero() {
+		s.outGaps.add(now.Sub(s.lastOut))
+	}
+	s.lastOut = now
+}
+
+// snapshot returns a copy of the statistics of the session.
+func (s *session) snapshot(now time.Time, closed bool) *Snapshot {
+	snapshot := &Snapshot{
+		Peer:         hex.EncodeToString(s.peer[:]),
+		Inbound:      s.inbound,
+		SessionStart: s.start,
+		Timestamp:    now,
+		Closed:       closed,
+		Messages:     make(map[string]MessageStats, len(s.messages)),
+		InGaps:       s.inGaps.copy(),
+		OutGaps:      s.outGaps.copy(),
+		InSizes:      s.inSizes.copy(),
+	}
+	if s.address != nil {
+		snapshot.Address = s.address.String()
+	}
+
+	for msgType, stats := range s.messages {
+		snapshot.Messages[msgType.String()] = *stats
+	}
+
+	return snapshot
+}
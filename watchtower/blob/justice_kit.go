@@ -37,6 +37,19 @@ const (
 	//    commit to-remote sig:           64 bytes, maybe blank
 	V0PlaintextSize = 274
 
+	// NumDynamicFeeRates is the number of sweep fee rates for which a blob
+	// with FlagDynamicFee carries signatures.
+	NumDynamicFeeRates = 4
+
+	// V1PlaintextSize is the plaintext size of a version 1 encoded blob,
+	// which extends the version 0 encoding with the signatures for the
+	// additional sweep fee rates of a dynamic fee session.
+	//    version 0 plaintext:           274 bytes
+	//    for each additional fee rate:
+	//      commit to-local revocation sig: 64 bytes
+	//      commit to-remote sig:           64 bytes, maybe blank
+	V1PlaintextSize = V0PlaintextSize + (NumDynamicFeeRates-1)*128
+
 	// MaxSweepAddrSize defines the maximum sweep address size that can be
 	// encoded in a blob.
 	MaxSweepAddrSize = 42
@@ -54,6 +67,8 @@ func Size(blobType Type) int {
 // PlaintextSize returns the size of the encoded-but-unencrypted blob in bytes.
 func PlaintextSize(blobType Type) int {
 	switch {
+	case blobType.Has(FlagCommitOutputs) && blobType.HasDynamicFee():
+		return V1PlaintextSize
 	case blobType.Has(FlagCommitOutputs):
 		return V0PlaintextSize
 	default:
@@ -88,11 +103,27 @@ var (
 		"sweep address must be less than or equal to %d bytes long",
 		MaxSweepAddrSize,
 	)
+
+	// ErrUnknownFeeRate is returned when trying to select the signatures
+	// for a sweep fee rate that the blob doesn't contain.
+	ErrUnknownFeeRate = errors.New("blob has no signatures for fee rate")
 )
 
 // PubKey is a 33-byte, serialized compressed public key.
 type PubKey [33]byte
 
+// FeeRateSigs holds the signatures of a justice transaction sweeping the
+// commitment outputs at one of the fee rates of a dynamic fee session.
+type FeeRateSigs struct {
+	// CommitToLocalSig is a signature under RevocationPubKey using
+	// SIGHASH_ALL.
+	CommitToLocalSig lnwire.Sig
+
+	// CommitToRemoteSig is a signature under CommitToRemotePubKey using
+	// SIGHASH_ALL.
+	CommitToRemoteSig lnwire.Sig
+}
+
 // JusticeKit is lé Blob of Justice. The JusticeKit contains information
 // required to construct a justice transaction, that sweeps a remote party's
 // revoked commitment transaction. It supports encryption and decryption using
@@ -148,6 +179,36 @@ type JusticeKit struct {
 	// NOTE: This value is only used if CommitToRemotePubKey contains a valid
 	// compressed public key.
 	CommitToRemoteSig lnwire.Sig
+
+	// DynamicFeeSigs holds the signatures for the justice transactions at
+	// the higher fee rates of a dynamic fee session, in increasing order
+	// of fee rate. The signatures above are those for the lowest fee rate.
+	//
+	// NOTE: This value is only used if BlobType has FlagDynamicFee.
+	DynamicFeeSigs [NumDynamicFeeRates - 1]FeeRateSigs
+}
+
+// ForFeeRate returns a copy of the JusticeKit whose to-local and to-remote
+// signatures are the ones for the justice transaction at the fee rate with the
+// given index, where index zero is the lowest fee rate of the session.
+func (b *JusticeKit) ForFeeRate(index int) (*JusticeKit, error) {
+	kit := *b
+
+	switch {
+	case index == 0:
+		return &kit, nil
+
+	case !b.BlobType.HasDynamicFee() || index < 0 ||
+		index >= NumDynamicFeeRates:
+
+		return nil, ErrUnknownFeeRate
+	}
+
+	sigs := b.DynamicFeeSigs[index-1]
+	kit.CommitToLocalSig = sigs.CommitToLocalSig
+	kit.CommitToRemoteSig = sigs.CommitToRemoteSig
+
+	return &kit, nil
 }
 
 // CommitToLocalWitnessScript returns the serialized witness script for the
@@ -324,6 +385,8 @@ func Decrypt(key BreachKey, ciphertext []byte,
 // error if the version is unknown.
 func (b *JusticeKit) encode(w io.Writer, blobType Type) error {
 	switch {
+	case blobType.Has(FlagCommitOutputs) && blobType.HasDynamicFee():
+		return b.encodeV1(w)
 	case blobType.Has(FlagCommitOutputs):
 		return b.encodeV0(w)
 	default:
@@ -335,6 +398,8 @@ func (b *JusticeKit) encode(w io.Writer, blobType Type) error {
 // error if the version is unknown.
 func (b *JusticeKit) decode(r io.Reader, blobType Type) error {
 	switch {
+	case blobType.Has(FlagCommitOutputs) && blobType.HasDynamicFee():
+		return b.decodeV1(r)
 	case blobType.Has(FlagCommitOutputs):
 		return b.decodeV0(r)
 	default:
@@ -503,3 +568,81 @@ func (b *JusticeKit) decodeV0(r io.Reader) error {
 
 	return nil
 }
+
+// encodeV1 encodes the JusticeKit using the version 1 encoding scheme to the
+// provided io.Writer. The encoding extends version 0 with the signatures for
+// the additional fee rates of a dynamic fee session, producing a
+// constant-size plaintext of 658 bytes.
+//
+// blob version 1 plaintext encoding:
+//
+//	version 0 plaintext:            274 bytes
+//	for each additional fee rate:
+//	  commit to-local revocation sig: 64 bytes
+//	  commit to-remote sig:           64 bytes, maybe blank
+func (b *JusticeKit) encodeV1(w io.Writer) error {
+	if err := b.encodeV0(w); err != nil {
+		return err
+	}
+
+	for _, sigs := range b.DynamicFeeSigs {
+		// Write 64-byte revocation signature for commit to-local
+		// output.
+		_, err := w.Write(sigs.CommitToLocalSig[:])
+		if err != nil {
+			return err
+		}
+
+		// Write 64-byte commit to-remote signature, which may be
+		// blank.
+		_, err = w.Write(sigs.CommitToRemoteSig[:])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeV1 reconstructs a JusticeKit from the io.Reader, using version 1
+// encoding scheme. This will parse a constant size input stream of 658 bytes
+// to recover the version 0 contents along with the signatures for the
+// additional fee rates of a dynamic fee session.
+//
+// blob version 1 plaintext encoding:
+//
+//	version 0 plaintext:            274 bytes
+//	for each additional fee rate:
+//	  commit to-local revocation sig: 64 bytes
+//	  commit to-remote sig:           64 bytes, maybe blank
+func (b *JusticeKit) decodeV1(r io.Reader) error {
+	if err := b.decodeV0(r); err != nil {
+		return err
+	}
+
+	hasToRemote := b.HasCommitToRemoteOutput()
+	for i := range b.DynamicFeeSigs {
+		sigs := &b.DynamicFeeSigs[i]
+
+		// Read 64-byte revocation signature for commit to-local
+		// output.
+		_, err := io.ReadFull(r, sigs.CommitToLocalSig[:])
+		if err != nil {
+			return err
+		}
+
+		// Read 64-byte commit to-remote signature, which is discarded
+		// if the blob has no commit to-remote output.
+		var commitToRemoteSig lnwire.Sig
+		_, err = io.ReadFull(r, commitToRemoteSig[:])
+		if err != nil {
+			return err
+		}
+
+		if hasToRemote {
+			sigs.CommitToRemoteSig = commitToRemoteSig
+		}
+	}
+
+	return nil
+}
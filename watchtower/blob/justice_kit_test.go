@@ -42,6 +42,22 @@ func makeAddr(size int) []byte {
 	return addr
 }
 
+// makeFeeRateSigs creates distinct signatures for the additional fee rates of
+// a dynamic fee blob.
+func makeFeeRateSigs(
+	hasToRemote bool) [blob.NumDynamicFeeRates - 1]blob.FeeRateSigs {
+
+	var sigs [blob.NumDynamicFeeRates - 1]blob.FeeRateSigs
+	for i := range sigs {
+		sigs[i].CommitToLocalSig = makeSig(10 + 2*i)
+		if hasToRemote {
+			sigs[i].CommitToRemoteSig = makeSig(11 + 2*i)
+		}
+	}
+
+	return sigs
+}
+
 type descriptorTest struct {
 	name                 string
 	encVersion           blob.Type
@@ -54,6 +70,7 @@ type descriptorTest struct {
 	hasCommitToRemote    bool
 	commitToRemotePubKey blob.PubKey
 	commitToRemoteSig    lnwire.Sig
+	dynamicFeeSigs       [blob.NumDynamicFeeRates - 1]blob.FeeRateSigs
 	encErr               error
 	decErr               error
 }
@@ -82,6 +99,31 @@ var descriptorTests = []descriptorTest{
 		commitToRemotePubKey: makePubKey(2),
 		commitToRemoteSig:    makeSig(2),
 	},
+	{
+		name:             "dynamic fee to-local only",
+		encVersion:       blob.TypeAltruistDynamicFeeCommit,
+		decVersion:       blob.TypeAltruistDynamicFeeCommit,
+		sweepAddr:        makeAddr(22),
+		revPubKey:        makePubKey(0),
+		delayPubKey:      makePubKey(1),
+		csvDelay:         144,
+		commitToLocalSig: makeSig(1),
+		dynamicFeeSigs:   makeFeeRateSigs(false),
+	},
+	{
+		name:                 "dynamic fee to-local and p2wsh",
+		encVersion:           blob.TypeAltruistAnchorDynamicFeeCommit,
+		decVersion:           blob.TypeAltruistAnchorDynamicFeeCommit,
+		sweepAddr:            makeAddr(22),
+		revPubKey:            makePubKey(0),
+		delayPubKey:          makePubKey(1),
+		csvDelay:             144,
+		commitToLocalSig:     makeSig(1),
+		hasCommitToRemote:    true,
+		commitToRemotePubKey: makePubKey(2),
+		commitToRemoteSig:    makeSig(2),
+		dynamicFeeSigs:       makeFeeRateSigs(true),
+	},
 	{
 		name:             "unknown encrypt version",
 		encVersion:       0,
@@ -159,6 +201,7 @@ func testBlobJusticeKitEncryptDecrypt(t *testing.T, test descriptorTest) {
 		CommitToLocalSig:     test.commitToLocalSig,
 		CommitToRemotePubKey: test.commitToRemotePubKey,
 		CommitToRemoteSig:    test.commitToRemoteSig,
+		DynamicFeeSigs:       test.dynamicFeeSigs,
 	}
 
 	// Generate a random encryption key for the blob. The key is
@@ -214,6 +257,45 @@ func testBlobJusticeKitEncryptDecrypt(t *testing.T, test descriptorTest) {
 	}
 }
 
+// TestJusticeKitForFeeRate asserts that the signatures of a dynamic fee blob
+// are selected by the index of their fee rate.
+func TestJusticeKitForFeeRate(t *testing.T) {
+	t.Parallel()
+
+	kit := &blob.JusticeKit{
+		BlobType:          blob.TypeAltruistDynamicFeeCommit,
+		CommitToLocalSig:  makeSig(1),
+		CommitToRemoteSig: makeSig(2),
+		DynamicFeeSigs:    makeFeeRateSigs(true),
+	}
+
+	// The lowest fee rate uses the version 0 signatures.
+	kit0, err := kit.ForFeeRate(0)
+	require.NoError(t, err)
+	require.Equal(t, kit, kit0)
+
+	for i := 1; i < blob.NumDynamicFeeRates; i++ {
+		kitI, err := kit.ForFeeRate(i)
+		require.NoError(t, err)
+
+		sigs := kit.DynamicFeeSigs[i-1]
+		require.Equal(t, sigs.CommitToLocalSig, kitI.CommitToLocalSig)
+		require.Equal(t, sigs.CommitToRemoteSig, kitI.CommitToRemoteSig)
+	}
+
+	// The original kit must not be modified.
+	require.Equal(t, makeSig(1), kit.CommitToLocalSig)
+
+	_, err = kit.ForFeeRate(blob.NumDynamicFeeRates)
+	require.ErrorIs(t, err, blob.ErrUnknownFeeRate)
+
+	// A blob without dynamic fees only has signatures for a single fee
+	// rate.
+	kit.BlobType = blob.TypeAltruistCommit
+	_, err = kit.ForFeeRate(1)
+	require.ErrorIs(t, err, blob.ErrUnknownFeeRate)
+}
+
 type remoteWitnessTest struct {
 	name             string
 	blobType         blob.Type
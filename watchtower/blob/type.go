@@ -24,6 +24,17 @@ const (
 	// channel, and therefore must expect a P2WSH-style to-remote output if
 	// one exists.
 	FlagAnchorChannel Flag = 1 << 2
+
+	// FlagDynamicFee signals that the blob contains signatures for a ladder
+	// of sweep fee rates between the bounds negotiated for the session,
+	// allowing the tower to choose the fee rate of the justice transaction
+	// using its fee estimator at the time of the breach.
+	FlagDynamicFee Flag = 1 << 3
+
+	// FlagCPFPAnchor signals that the justice transaction contains an
+	// additional anchor output paying to the tower, which the tower can
+	// spend to bump the fee of the justice transaction using CPFP.
+	FlagCPFPAnchor Flag = 1 << 4
 )
 
 // Type returns a Type consisting solely of this flag enabled.
@@ -40,6 +51,10 @@ func (f Flag) String() string {
 		return "FlagCommitOutputs"
 	case FlagAnchorChannel:
 		return "FlagAnchorChannel"
+	case FlagDynamicFee:
+		return "FlagDynamicFee"
+	case FlagCPFPAnchor:
+		return "FlagCPFPAnchor"
 	default:
 		return "FlagUnknown"
 	}
@@ -65,6 +80,37 @@ const (
 	// TypeRewardCommit sweeps only commitment outputs to a sweep address
 	// controlled by the user, and pays a negotiated reward to the tower.
 	TypeRewardCommit = Type(FlagCommitOutputs | FlagReward)
+
+	// TypeAltruistDynamicFeeCommit sweeps only commitment outputs to a
+	// sweep address controlled by the user at a fee rate chosen by the
+	// tower at breach time, and does not give the tower a reward.
+	TypeAltruistDynamicFeeCommit = Type(FlagCommitOutputs | FlagDynamicFee)
+
+	// TypeAltruistAnchorDynamicFeeCommit sweeps only commitment outputs
+	// from an anchor commitment to a sweep address controlled by the user
+	// at a fee rate chosen by the tower at breach time, and does not give
+	// the tower a reward.
+	TypeAltruistAnchorDynamicFeeCommit = Type(
+		FlagCommitOutputs | FlagAnchorChannel | FlagDynamicFee,
+	)
+
+	// TypeAltruistAnchorCPFPCommit sweeps only commitment outputs from an
+	// anchor commitment to a sweep address controlled by the user, and
+	// adds an anchor output that allows the tower to bump the fee of the
+	// justice transaction.
+	TypeAltruistAnchorCPFPCommit = Type(
+		FlagCommitOutputs | FlagAnchorChannel | FlagCPFPAnchor,
+	)
+
+	// TypeAltruistAnchorDynamicFeeCPFPCommit sweeps only commitment
+	// outputs from an anchor commitment to a sweep address controlled by
+	// the user at a fee rate chosen by the tower at breach time, and adds
+	// an anchor output that allows the tower to bump the fee of the
+	// justice transaction further.
+	TypeAltruistAnchorDynamicFeeCPFPCommit = Type(
+		FlagCommitOutputs | FlagAnchorChannel | FlagDynamicFee |
+			FlagCPFPAnchor,
+	)
 )
 
 // Has returns true if the Type has the passed flag enabled.
@@ -87,11 +133,25 @@ func (t Type) IsAnchorChannel() bool {
 	return t.Has(FlagAnchorChannel)
 }
 
+// HasDynamicFee returns true if the blob type lets the tower choose the sweep
+// fee rate at breach time.
+func (t Type) HasDynamicFee() bool {
+	return t.Has(FlagDynamicFee)
+}
+
+// HasCPFPAnchor returns true if the blob type adds an anchor output for the
+// tower to the justice transaction.
+func (t Type) HasCPFPAnchor() bool {
+	return t.Has(FlagCPFPAnchor)
+}
+
 // knownFlags maps the supported flags to their name.
 var knownFlags = map[Flag]struct{}{
 	FlagReward:        {},
 	FlagCommitOutputs: {},
 	FlagAnchorChannel: {},
+	FlagDynamicFee:    {},
+	FlagCPFPAnchor:    {},
 }
 
 // String returns a human readable description of a Type.
@@ -141,6 +201,12 @@ var supportedTypes = map[Type]struct{}{
 	TypeAltruistCommit:       {},
 	TypeRewardCommit:         {},
 	TypeAltruistAnchorCommit: {},
+
+	TypeAltruistDynamicFeeCommit:       {},
+	TypeAltruistAnchorDynamicFeeCommit: {},
+
+	TypeAltruistAnchorCPFPCommit:           {},
+	TypeAltruistAnchorDynamicFeeCPFPCommit: {},
 }
 
 // IsSupportedType returns true if the given type is supported by the package.
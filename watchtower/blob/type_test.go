@@ -6,7 +6,7 @@ import (
 	"github.com/lightningnetwork/lnd/watchtower/blob"
 )
 
-var unknownFlag = blob.Flag(16)
+var unknownFlag = blob.Flag(32)
 
 type typeStringTest struct {
 	name   string
@@ -16,19 +16,35 @@ type typeStringTest struct {
 
 var typeStringTests = []typeStringTest{
 	{
-		name:   "commit no-reward",
-		typ:    blob.TypeAltruistCommit,
-		expStr: "[No-FlagAnchorChannel|FlagCommitOutputs|No-FlagReward]",
+		name: "commit no-reward",
+		typ:  blob.TypeAltruistCommit,
+		expStr: "[No-FlagCPFPAnchor|No-FlagDynamicFee|" +
+			"No-FlagAnchorChannel|FlagCommitOutputs|No-FlagReward]",
 	},
 	{
-		name:   "commit reward",
-		typ:    blob.TypeRewardCommit,
-		expStr: "[No-FlagAnchorChannel|FlagCommitOutputs|FlagReward]",
+		name: "commit reward",
+		typ:  blob.TypeRewardCommit,
+		expStr: "[No-FlagCPFPAnchor|No-FlagDynamicFee|" +
+			"No-FlagAnchorChannel|FlagCommitOutputs|FlagReward]",
 	},
 	{
-		name:   "unknown flag",
-		typ:    unknownFlag.Type(),
-		expStr: "0000000000010000[No-FlagAnchorChannel|No-FlagCommitOutputs|No-FlagReward]",
+		name: "commit dynamic fee",
+		typ:  blob.TypeAltruistDynamicFeeCommit,
+		expStr: "[No-FlagCPFPAnchor|FlagDynamicFee|" +
+			"No-FlagAnchorChannel|FlagCommitOutputs|No-FlagReward]",
+	},
+	{
+		name: "anchor commit cpfp anchor",
+		typ:  blob.TypeAltruistAnchorCPFPCommit,
+		expStr: "[FlagCPFPAnchor|No-FlagDynamicFee|FlagAnchorChannel|" +
+			"FlagCommitOutputs|No-FlagReward]",
+	},
+	{
+		name: "unknown flag",
+		typ:  unknownFlag.Type(),
+		expStr: "0000000000100000[No-FlagCPFPAnchor|" +
+			"No-FlagDynamicFee|No-FlagAnchorChannel|" +
+			"No-FlagCommitOutputs|No-FlagReward]",
 	},
 }
 
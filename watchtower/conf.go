@@ -1,8 +1,11 @@
 package watchtower
 
 import (
+	"fmt"
 	"strconv"
 	"time"
+
+	"github.com/lightningnetwork/lnd/watchtower/wtserver"
 )
 
 // Conf specifies the watchtower options that can be configured from the command
@@ -21,6 +24,21 @@ type Conf struct {
 	// WriteTimeout specifies the duration the tower will wait when trying
 	// to write a message from a client before hanging up.
 	WriteTimeout time.Duration `long:"writetimeout" description:"Duration the watchtower server will wait for messages to be written before hanging up on client connections"`
+
+	// MaxUpdateRate specifies the number of state updates per second a
+	// session may send on average.
+	MaxUpdateRate float64 `long:"maxupdaterate" description:"The number of state updates per second a session may send on average, 0 to disable"`
+
+	// MaxStoragePerSession specifies the total size of the encrypted
+	// blobs a session may store.
+	MaxStoragePerSession uint64 `long:"maxstoragepersession" description:"The total size in bytes of the encrypted blobs a session may store, 0 to disable"`
+
+	// MaxViolations specifies how often a client may exceed its limits
+	// before it is banned.
+	MaxViolations uint32 `long:"maxviolations" description:"The number of times a client may exceed its limits before it is banned, 0 to never ban clients"`
+
+	// BanDuration specifies for how long banned clients are rejected.
+	BanDuration time.Duration `long:"banduration" description:"Duration for which the connections of a banned client are rejected"`
 }
 
 // Apply completes the passed Config struct by applying any parsed Conf options.
@@ -86,5 +104,20 @@ func (c *Conf) Apply(cfg *Config,
 		cfg.WriteTimeout = c.WriteTimeout
 	}
 
+	// Apply the parsed client limits unless the Config has limits of its
+	// own.
+	if c.MaxUpdateRate < 0 {
+		return nil, fmt.Errorf("maxupdaterate must not be negative: "+
+			"%v", c.MaxUpdateRate)
+	}
+	if cfg.RateLimits == (wtserver.RateLimits{}) {
+		cfg.RateLimits = wtserver.RateLimits{
+			MaxUpdateRate:        c.MaxUpdateRate,
+			MaxStoragePerSession: c.MaxStoragePerSession,
+			MaxViolations:        c.MaxViolations,
+			BanDuration:          c.BanDuration,
+		}
+	}
+
 	return cfg, nil
 }
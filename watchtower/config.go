@@ -9,8 +9,12 @@ import (
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/tor"
 	"github.com/lightningnetwork/lnd/watchtower/lookout"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/lightningnetwork/lnd/watchtower/wtmetrics"
+	"github.com/lightningnetwork/lnd/watchtower/wtserver"
 )
 
 const (
@@ -25,6 +29,11 @@ const (
 	// DefaultWriteTimeout is the default timeout after which the tower will
 	// hang up on a client if it is unable to send a message.
 	DefaultWriteTimeout = 15 * time.Second
+
+	// DefaultBanDuration is the default duration for which the tower
+	// rejects the connections of a client that exceeded its limits too
+	// often.
+	DefaultBanDuration = 24 * time.Hour
 )
 
 var (
@@ -74,6 +83,16 @@ type Config struct {
 	// have stronger guarantees wrt. returned error types.
 	PublishTx func(*wire.MsgTx, string) error
 
+	// FeeEstimator is used to choose the fee rate of justice transactions
+	// for sessions with dynamic fees. If nil, such justice transactions
+	// use the highest fee rate allowed by their session.
+	FeeEstimator chainfee.Estimator
+
+	// CPFPAnchor spends the anchor output of a published justice
+	// transaction to bump its fee. If nil, justice transactions with an
+	// anchor output are never bumped.
+	CPFPAnchor func(*wtdb.JusticeAnchor) error
+
 	// ListenAddrs specifies the listening addresses of the tower.
 	ListenAddrs []net.Addr
 
@@ -101,4 +120,13 @@ type Config struct {
 	// Type specifies the hidden service type (V2 or V3) that the watchtower
 	// will create.
 	Type tor.OnionType
+
+	// Metrics records the activity of the tower, such as the sessions
+	// created and justice transactions broadcast. If nil, no metrics are
+	// recorded.
+	Metrics wtmetrics.Recorder
+
+	// RateLimits configures the limits the tower enforces on each client
+	// to protect itself from abusive clients.
+	RateLimits wtserver.RateLimits
 }
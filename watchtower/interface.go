@@ -13,6 +13,10 @@ import (
 type DB interface {
 	lookout.DB
 	wtserver.DB
+
+	// StorageBytes returns the number of bytes occupied by the encoded
+	// sessions and state updates currently stored by the tower.
+	StorageBytes() (uint64, error)
 }
 
 // AddressNormalizer is a function signature that allows the tower to resolve
@@ -43,6 +43,24 @@ type DB interface {
 	// SetLookoutTip writes the best epoch for which the watchtower has
 	// queried for breach hints.
 	SetLookoutTip(*chainntnfs.BlockEpoch) error
+
+	AnchorDB
+}
+
+// AnchorDB persists the anchor outputs of published justice transactions whose
+// fee the tower bumps, so that the bumps can be resumed after a restart until
+// the justice transactions confirm.
+type AnchorDB interface {
+	// PutJusticeAnchor stores the anchor output of a published justice
+	// transaction.
+	PutJusticeAnchor(*wtdb.JusticeAnchor) error
+
+	// DeleteJusticeAnchor removes the anchor output with the given
+	// outpoint.
+	DeleteJusticeAnchor(*wire.OutPoint) error
+
+	// ListJusticeAnchors returns all stored anchor outputs.
+	ListJusticeAnchors() ([]*wtdb.JusticeAnchor, error)
 }
 
 // EpochRegistrar supports the ability to register for events corresponding to
@@ -12,6 +12,7 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/watchtower/blob"
 	"github.com/lightningnetwork/lnd/watchtower/wtdb"
 )
@@ -42,6 +43,11 @@ type JusticeDescriptor struct {
 	// JusticeKit contains the decrypted blob and information required to
 	// construct the transaction scripts and witnesses.
 	JusticeKit *blob.JusticeKit
+
+	// FeeRateIndex is the index into the session policy's SweepFeeRates
+	// of the fee rate the justice transaction is created with. This is
+	// always zero for sessions without dynamic fees.
+	FeeRateIndex int
 }
 
 // breachedInput contains the required information to construct and spend
@@ -54,10 +60,12 @@ type breachedInput struct {
 }
 
 // commitToLocalInput extracts the information required to spend the commit
-// to-local output.
-func (p *JusticeDescriptor) commitToLocalInput() (*breachedInput, error) {
+// to-local output using the signature contained in the given justice kit.
+func (p *JusticeDescriptor) commitToLocalInput(
+	justiceKit *blob.JusticeKit) (*breachedInput, error) {
+
 	// Retrieve the to-local witness script from the justice kit.
-	toLocalScript, err := p.JusticeKit.CommitToLocalWitnessScript()
+	toLocalScript, err := justiceKit.CommitToLocalWitnessScript()
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +94,7 @@ func (p *JusticeDescriptor) commitToLocalInput() (*breachedInput, error) {
 
 	// Retrieve to-local witness stack, which primarily includes a signature
 	// under the revocation pubkey.
-	witnessStack, err := p.JusticeKit.CommitToLocalRevokeWitnessStack()
+	witnessStack, err := justiceKit.CommitToLocalRevokeWitnessStack()
 	if err != nil {
 		return nil, err
 	}
@@ -99,10 +107,12 @@ func (p *JusticeDescriptor) commitToLocalInput() (*breachedInput, error) {
 }
 
 // commitToRemoteInput extracts the information required to spend the commit
-// to-remote output.
-func (p *JusticeDescriptor) commitToRemoteInput() (*breachedInput, error) {
+// to-remote output using the signature contained in the given justice kit.
+func (p *JusticeDescriptor) commitToRemoteInput(
+	justiceKit *blob.JusticeKit) (*breachedInput, error) {
+
 	// Retrieve the to-remote witness script from the justice kit.
-	toRemoteScript, err := p.JusticeKit.CommitToRemoteWitnessScript()
+	toRemoteScript, err := justiceKit.CommitToRemoteWitnessScript()
 	if err != nil {
 		return nil, err
 	}
@@ -111,7 +121,7 @@ func (p *JusticeDescriptor) commitToRemoteInput() (*breachedInput, error) {
 		toRemoteScriptHash []byte
 		toRemoteSequence   uint32
 	)
-	if p.JusticeKit.BlobType.IsAnchorChannel() {
+	if justiceKit.BlobType.IsAnchorChannel() {
 		toRemoteScriptHash, err = input.WitnessScriptHash(
 			toRemoteScript,
 		)
@@ -155,7 +165,7 @@ func (p *JusticeDescriptor) commitToRemoteInput() (*breachedInput, error) {
 
 	// Retrieve the to-remote witness stack, which is just a signature under
 	// the to-remote pubkey.
-	witnessStack, err := p.JusticeKit.CommitToRemoteWitnessStack()
+	witnessStack, err := justiceKit.CommitToRemoteWitnessStack()
 	if err != nil {
 		return nil, err
 	}
@@ -170,8 +180,10 @@ func (p *JusticeDescriptor) commitToRemoteInput() (*breachedInput, error) {
 
 // assembleJusticeTxn accepts the breached inputs recovered from state update
 // and attempts to construct the justice transaction that sweeps the victims
-// funds to their wallet and claims the watchtower's reward.
+// funds to their wallet at the given fee rate and claims the watchtower's
+// reward.
 func (p *JusticeDescriptor) assembleJusticeTxn(txWeight int64,
+	feeRate chainfee.SatPerKWeight,
 	inputs ...*breachedInput) (*wire.MsgTx, error) {
 
 	justiceTxn := wire.NewMsgTx(2)
@@ -193,7 +205,7 @@ func (p *JusticeDescriptor) assembleJusticeTxn(txWeight int64,
 	// reward sweep, there will be two outputs, one of which pays back to
 	// the victim while the other gives a cut to the tower.
 	outputs, err := p.SessionInfo.Policy.ComputeJusticeTxOuts(
-		totalAmt, txWeight, p.JusticeKit.SweepAddress[:],
+		totalAmt, txWeight, feeRate, p.JusticeKit.SweepAddress[:],
 		p.SessionInfo.RewardAddress,
 	)
 	if err != nil {
@@ -264,28 +276,43 @@ func (p *JusticeDescriptor) CreateJusticeTxn() (*wire.MsgTx, error) {
 		weightEstimate input.TxWeightEstimator
 	)
 
-	// Add the sweep address's contribution, depending on whether it is a
-	// p2wkh or p2wsh output.
-	switch len(p.JusticeKit.SweepAddress) {
-	case input.P2WPKHSize:
-		weightEstimate.AddP2WKHOutput()
+	// Select the fee rate of the justice transaction, along with the
+	// signatures the client created for it.
+	feeRates := p.SessionInfo.Policy.SweepFeeRates()
+	if p.FeeRateIndex < 0 || p.FeeRateIndex >= len(feeRates) {
+		return nil, blob.ErrUnknownFeeRate
+	}
+	feeRate := feeRates[p.FeeRateIndex]
 
-	case input.P2WSHSize:
-		weightEstimate.AddP2WSHOutput()
+	justiceKit, err := p.JusticeKit.ForFeeRate(p.FeeRateIndex)
+	if err != nil {
+		return nil, err
+	}
 
-	default:
-		return nil, ErrUnknownSweepAddrType
+	// Add the sweep address's contribution, depending on whether it is a
+	// p2wkh or p2wsh output.
+	err = addOutputWeight(&weightEstimate, p.JusticeKit.SweepAddress)
+	if err != nil {
+		return nil, err
 	}
 
 	// Add our reward address to the weight estimate if the policy's blob
-	// type specifies a reward output.
-	if p.SessionInfo.Policy.BlobType.Has(blob.FlagReward) {
-		weightEstimate.AddP2WKHOutput()
+	// type specifies a reward or anchor output for us. The address is
+	// either a p2wkh or a p2tr output, the latter of which has the same
+	// size as a p2wsh output.
+	policy := p.SessionInfo.Policy
+	if policy.BlobType.Has(blob.FlagReward) || policy.HasCPFPAnchor() {
+		err := addOutputWeight(
+			&weightEstimate, p.SessionInfo.RewardAddress,
+		)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Assemble the breached to-local output from the justice descriptor and
 	// add it to our weight estimate.
-	toLocalInput, err := p.commitToLocalInput()
+	toLocalInput, err := p.commitToLocalInput(justiceKit)
 	if err != nil {
 		return nil, err
 	}
@@ -310,7 +337,7 @@ func (p *JusticeDescriptor) CreateJusticeTxn() (*wire.MsgTx, error) {
 	// output, we'll also try to assemble the output and add it to weight
 	// estimate if successful.
 	if p.JusticeKit.HasCommitToRemoteOutput() {
-		toRemoteInput, err := p.commitToRemoteInput()
+		toRemoteInput, err := p.commitToRemoteInput(justiceKit)
 		if err != nil {
 			return nil, err
 		}
@@ -330,7 +357,27 @@ func (p *JusticeDescriptor) CreateJusticeTxn() (*wire.MsgTx, error) {
 
 	txWeight := int64(weightEstimate.Weight())
 
-	return p.assembleJusticeTxn(txWeight, sweepInputs...)
+	return p.assembleJusticeTxn(txWeight, feeRate, sweepInputs...)
+}
+
+// addOutputWeight adds the weight of an output paying to the given pkScript to
+// the weight estimate, depending on whether it is a p2wkh or p2wsh sized
+// output.
+func addOutputWeight(weightEstimate *input.TxWeightEstimator,
+	pkScript []byte) error {
+
+	switch len(pkScript) {
+	case input.P2WPKHSize:
+		weightEstimate.AddP2WKHOutput()
+
+	case input.P2WSHSize:
+		weightEstimate.AddP2WSHOutput()
+
+	default:
+		return ErrUnknownSweepAddrType
+	}
+
+	return nil
 }
 
 // findTxOutByPkScript searches the given transaction for an output whose
@@ -12,6 +12,7 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/watchtower/blob"
 	"github.com/lightningnetwork/lnd/watchtower/lookout"
@@ -73,6 +74,22 @@ func TestJusticeDescriptor(t *testing.T) {
 			name:     "altruist anchor commit type",
 			blobType: altruistAnchorCommitType,
 		},
+		{
+			name:     "altruist dynamic fee commit type",
+			blobType: blob.TypeAltruistDynamicFeeCommit,
+		},
+		{
+			name:     "altruist anchor dynamic fee commit type",
+			blobType: blob.TypeAltruistAnchorDynamicFeeCommit,
+		},
+		{
+			name:     "altruist anchor cpfp commit type",
+			blobType: blob.TypeAltruistAnchorCPFPCommit,
+		},
+		{
+			name:     "altruist anchor dynamic fee cpfp type",
+			blobType: blob.TypeAltruistAnchorDynamicFeeCPFPCommit,
+		},
 	}
 
 	for _, test := range tests {
@@ -205,7 +222,7 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 		weightEstimate.AddWitnessInput(input.P2WKHWitnessSize)
 	}
 	weightEstimate.AddP2WKHOutput()
-	if blobType.Has(blob.FlagReward) {
+	if blobType.Has(blob.FlagReward) || blobType.HasCPFPAnchor() {
 		weightEstimate.AddP2WKHOutput()
 	}
 	txWeight := weightEstimate.Weight()
@@ -220,6 +237,18 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 			RewardRate:   900000,
 		},
 	}
+
+	// For dynamic fee sessions, the tower's fee estimate of 5000 sat/kw
+	// should select the third fee rate of the ladder 2000, 4000, 6000 and
+	// 8000 sat/kw.
+	var feeRateIndex int
+	if blobType.HasDynamicFee() {
+		policy.MaxSweepFeeRate = 8000
+		policy.SweepFeePercentile = 50
+		feeRateIndex = 2
+	}
+	feeRate := policy.SweepFeeRates()[feeRateIndex]
+
 	sessionInfo := &wtdb.SessionInfo{
 		Policy:        policy,
 		RewardAddress: makeAddrSlice(22),
@@ -260,7 +289,7 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 	}
 
 	outputs, err := policy.ComputeJusticeTxOuts(
-		totalAmount, int64(txWeight), justiceKit.SweepAddress,
+		totalAmount, int64(txWeight), feeRate, justiceKit.SweepAddress,
 		sessionInfo.RewardAddress,
 	)
 	require.Nil(t, err)
@@ -319,9 +348,16 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 	toRemoteSig, err := lnwire.NewSigFromSignature(toRemoteSigRaw)
 	require.Nil(t, err)
 
-	// Complete our justice kit by copying the signatures into the payload.
-	copy(justiceKit.CommitToLocalSig[:], toLocalSig[:])
-	copy(justiceKit.CommitToRemoteSig[:], toRemoteSig[:])
+	// Complete our justice kit by copying the signatures into the payload,
+	// using the signatures of the fee rate we expect the tower to select.
+	if feeRateIndex == 0 {
+		copy(justiceKit.CommitToLocalSig[:], toLocalSig[:])
+		copy(justiceKit.CommitToRemoteSig[:], toRemoteSig[:])
+	} else {
+		sigs := &justiceKit.DynamicFeeSigs[feeRateIndex-1]
+		copy(sigs.CommitToLocalSig[:], toLocalSig[:])
+		copy(sigs.CommitToRemoteSig[:], toRemoteSig[:])
+	}
 
 	justiceDesc := &lookout.JusticeDescriptor{
 		BreachedCommitTx: breachTxn,
@@ -330,14 +366,23 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 	}
 
 	// Construct a breach punisher that will feed published transactions
-	// over the buffered channel.
+	// over the buffered channel, and record the anchor outputs it spends
+	// to bump the fee of justice transactions.
 	publications := make(chan *wire.MsgTx, 1)
-	punisher := lookout.NewBreachPunisher(&lookout.PunisherConfig{
+	var bumped []*wtdb.JusticeAnchor
+	punisherCfg := &lookout.PunisherConfig{
 		PublishTx: func(tx *wire.MsgTx, _ string) error {
 			publications <- tx
 			return nil
 		},
-	})
+		FeeEstimator: chainfee.NewStaticEstimator(5000, 0),
+		CPFPAnchor: func(anchor *wtdb.JusticeAnchor) error {
+			bumped = append(bumped, anchor)
+			return nil
+		},
+		DB: wtmock.NewTowerDB(),
+	}
+	punisher := lookout.NewBreachPunisher(punisherCfg)
 
 	// Exact retribution on the offender. If no error is returned, we expect
 	// the justice transaction to be published via the channel.
@@ -367,4 +412,43 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 
 	// Assert that the watchtower derives the same justice txn.
 	require.Equal(t, justiceTxn, wtJusticeTxn)
+
+	// Only justice transactions with a CPFP anchor that pay less than the
+	// tower's estimate of 5000 sat/kw are bumped.
+	anchors, err := punisherCfg.DB.ListJusticeAnchors()
+	require.NoError(t, err)
+	if !blobType.HasCPFPAnchor() || feeRate >= 5000 {
+		require.Empty(t, bumped)
+		require.Empty(t, anchors)
+
+		return
+	}
+
+	// The anchor output pays to the reward address, and the justice
+	// transaction pays the value of the breached outputs that it doesn't
+	// sweep as fee.
+	require.Len(t, bumped, 1)
+	anchor := bumped[0]
+	justiceFee := totalAmount
+	for _, txOut := range wtJusticeTxn.TxOut {
+		justiceFee -= btcutil.Amount(txOut.Value)
+	}
+	require.Equal(t, wtJusticeTxn.TxHash(), anchor.OutPoint.Hash)
+	require.Equal(
+		t, wtJusticeTxn.TxOut[anchor.OutPoint.Index], anchor.TxOut,
+	)
+	require.Equal(t, sessionInfo.RewardAddress, anchor.TxOut.PkScript)
+	require.Equal(t, justiceFee, anchor.JusticeFee)
+	require.Equal(t, chainfee.SatPerKWeight(5000), anchor.FeeRate)
+
+	// The anchor is stored, so that the bump is resumed after a restart
+	// at the then current estimate.
+	require.Equal(t, []*wtdb.JusticeAnchor{anchor}, anchors)
+
+	punisherCfg.FeeEstimator = chainfee.NewStaticEstimator(7000, 0)
+	punisher = lookout.NewBreachPunisher(punisherCfg)
+	require.NoError(t, punisher.ResumeFeeBumps())
+	require.Len(t, bumped, 2)
+	require.Equal(t, anchor.OutPoint, bumped[1].OutPoint)
+	require.Equal(t, chainfee.SatPerKWeight(7000), bumped[1].FeeRate)
 }
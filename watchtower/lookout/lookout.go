@@ -4,9 +4,11 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/watchtower/blob"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
 )
 
 // Config houses the Lookout's required resources to properly fulfill it's duty,
@@ -160,12 +162,20 @@ func (l *Lookout) processEpoch(epoch *chainntnfs.BlockEpoch,
 	// the hint back to it's original transaction.
 	hintToTx := make(map[blob.BreachHint]*wire.MsgTx, numTxnsInBlock)
 	txHints := make([]blob.BreachHint, 0, numTxnsInBlock)
+	txids := make(map[chainhash.Hash]struct{}, numTxnsInBlock)
 	for _, tx := range block.Transactions {
 		hash := tx.TxHash()
 		hint := blob.NewBreachHintFromHash(&hash)
 
 		txHints = append(txHints, hint)
 		hintToTx[hint] = tx
+		txids[hash] = struct{}{}
+	}
+
+	// The fees of justice transactions that confirmed in this block no
+	// longer need to be bumped.
+	if err := l.forgetConfirmedAnchors(epoch, txids); err != nil {
+		return err
 	}
 
 	// Query the database to see if any of the breach hints cause a match
@@ -247,6 +257,34 @@ func (l *Lookout) processEpoch(epoch *chainntnfs.BlockEpoch,
 	return l.cfg.DB.SetLookoutTip(epoch)
 }
 
+// forgetConfirmedAnchors removes the stored anchor outputs of the justice
+// transactions among the given confirmed transactions, so that their fees
+// aren't bumped again after a restart.
+func (l *Lookout) forgetConfirmedAnchors(epoch *chainntnfs.BlockEpoch,
+	txids map[chainhash.Hash]struct{}) error {
+
+	anchors, err := l.cfg.DB.ListJusticeAnchors()
+	if err != nil {
+		return err
+	}
+
+	for _, anchor := range anchors {
+		if _, ok := txids[anchor.OutPoint.Hash]; !ok {
+			continue
+		}
+
+		log.Infof("Justice txn %v confirmed at height %d",
+			anchor.OutPoint.Hash, epoch.Height)
+
+		err := l.cfg.DB.DeleteJusticeAnchor(&anchor.OutPoint)
+		if err != nil && err != wtdb.ErrJusticeAnchorNotFound {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // dispatchPunisher accepts a justice descriptor corresponding to a successfully
 // decrypted blob.  The punisher will then construct the witness scripts and
 // witness stacks for the breached outputs. If construction of the justice
@@ -262,3 +262,50 @@ func TestLookoutBreachMatching(t *testing.T) {
 	case <-time.After(50 * time.Millisecond):
 	}
 }
+
+// TestLookoutForgetsConfirmedAnchors asserts that the lookout stops tracking
+// the anchor output of a justice transaction once the justice transaction
+// confirmed, so that its fee isn't bumped again after a restart.
+func TestLookoutForgetsConfirmedAnchors(t *testing.T) {
+	db := wtmock.NewTowerDB()
+	backend := lookout.NewMockBackend()
+
+	watcher := lookout.New(&lookout.Config{
+		BlockFetcher:   backend,
+		DB:             db,
+		EpochRegistrar: backend,
+		Punisher:       &mockPunisher{},
+	})
+	require.NoError(t, watcher.Start())
+
+	justiceTx := wire.NewMsgTx(wire.TxVersion)
+	otherTx := wire.NewMsgTx(wire.TxVersion + 1)
+
+	confirmed := &wtdb.JusticeAnchor{
+		OutPoint: wire.OutPoint{Hash: justiceTx.TxHash()},
+		TxOut:    wire.NewTxOut(330, makeAddrSlice(22)),
+	}
+	pending := &wtdb.JusticeAnchor{
+		OutPoint: wire.OutPoint{Hash: otherTx.TxHash(), Index: 1},
+		TxOut:    wire.NewTxOut(330, makeAddrSlice(22)),
+	}
+	require.NoError(t, db.PutJusticeAnchor(confirmed))
+	require.NoError(t, db.PutJusticeAnchor(pending))
+
+	block := &wire.MsgBlock{
+		Header:       wire.BlockHeader{Nonce: 1},
+		Transactions: []*wire.MsgTx{justiceTx},
+	}
+	blockHash := block.BlockHash()
+	backend.ConnectEpoch(&chainntnfs.BlockEpoch{
+		Hash:   &blockHash,
+		Height: 1,
+	}, block)
+
+	require.Eventually(t, func() bool {
+		anchors, err := db.ListJusticeAnchors()
+		require.NoError(t, err)
+
+		return len(anchors) == 1 && anchors[0] == pending
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -1,16 +1,46 @@
 package lookout
 
 import (
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/labels"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/lightningnetwork/lnd/watchtower/wtmetrics"
 )
 
+// CPFPConfTarget is the confirmation target of the fee rate that justice
+// transactions with an anchor output are bumped to, if they pay less.
+const CPFPConfTarget = 6
+
 // PunisherConfig houses the resources required by the Punisher.
 type PunisherConfig struct {
 	// PublishTx provides the ability to send a signed transaction to the
 	// network.
 	PublishTx func(*wire.MsgTx, string) error
 
+	// FeeEstimator is used to choose the fee rate of justice transactions
+	// for sessions with dynamic fees. If nil, such justice transactions
+	// use the highest fee rate allowed by their session.
+	FeeEstimator chainfee.Estimator
+
+	// Metrics records the justice transactions that are published. If
+	// nil, no metrics are recorded.
+	Metrics wtmetrics.Recorder
+
+	// CPFPAnchor spends the anchor output of a published justice
+	// transaction to bump its fee. It is only used for sessions with a
+	// CPFP anchor, and only if the justice transaction pays less than the
+	// FeeEstimator's estimate for CPFPConfTarget. If nil, justice
+	// transactions are never bumped.
+	CPFPAnchor func(*wtdb.JusticeAnchor) error
+
+	// DB persists the anchor outputs handed to CPFPAnchor, so that the
+	// bumps can be resumed after a restart. If nil, bumps are lost on
+	// restart.
+	DB AnchorDB
+
 	// TODO(conner) add DB tracking and spend ntfn registration to see if
 	// ours confirmed or not
 }
@@ -24,6 +54,10 @@ type BreachPunisher struct {
 
 // NewBreachPunisher constructs a new BreachPunisher given a PunisherConfig.
 func NewBreachPunisher(cfg *PunisherConfig) *BreachPunisher {
+	if cfg.Metrics == nil {
+		cfg.Metrics = wtmetrics.NoOpRecorder{}
+	}
+
 	return &BreachPunisher{
 		cfg: cfg,
 	}
@@ -32,6 +66,18 @@ func NewBreachPunisher(cfg *PunisherConfig) *BreachPunisher {
 // Punish constructs a justice transaction given a JusticeDescriptor and
 // publishes is it to the network.
 func (p *BreachPunisher) Punish(desc *JusticeDescriptor, quit <-chan struct{}) error {
+	// For sessions with dynamic fees, choose the fee rate of the justice
+	// transaction based on the current fee estimates.
+	policy := desc.SessionInfo.Policy
+	if policy.HasDynamicFee() {
+		desc.FeeRateIndex = p.sweepFeeRateIndex(desc)
+
+		feeRate := policy.SweepFeeRates()[desc.FeeRateIndex]
+		log.Debugf("Using sweep fee rate %v for client=%s with "+
+			"breach-txid=%s", feeRate, desc.SessionInfo.ID,
+			desc.BreachedCommitTx.TxHash())
+	}
+
 	justiceTxn, err := desc.CreateJusticeTxn()
 	if err != nil {
 		log.Errorf("Unable to create justice txn for "+
@@ -52,8 +98,168 @@ func (p *BreachPunisher) Punish(desc *JusticeDescriptor, quit <-chan struct{}) e
 		return err
 	}
 
+	p.cfg.Metrics.JusticeBroadcast()
+
+	// If the session allows us to, bump the fee of the justice
+	// transaction in case it pays less than the current estimate.
+	if policy.HasCPFPAnchor() {
+		p.bumpJusticeFee(desc, justiceTxn)
+	}
+
 	// TODO(conner): register for spend and remove from db after
 	// confirmation
 
 	return nil
 }
+
+// bumpJusticeFee spends the anchor output of a published justice transaction
+// to bump its fee, if the justice transaction pays less than the fee rate
+// estimated for CPFPConfTarget. Failures are only logged, as the justice
+// transaction has already been published.
+func (p *BreachPunisher) bumpJusticeFee(desc *JusticeDescriptor,
+	justiceTxn *wire.MsgTx) {
+
+	if p.cfg.CPFPAnchor == nil || p.cfg.FeeEstimator == nil {
+		return
+	}
+
+	justiceTxid := justiceTxn.TxHash()
+	targetFeeRate, err := p.cfg.FeeEstimator.EstimateFeePerKW(
+		CPFPConfTarget,
+	)
+	if err != nil {
+		log.Warnf("Unable to estimate fee rate to bump justice txn "+
+			"%v: %v", justiceTxid, err)
+		return
+	}
+
+	feeRate := desc.SessionInfo.Policy.SweepFeeRates()[desc.FeeRateIndex]
+	if targetFeeRate <= feeRate {
+		return
+	}
+
+	anchorIndex, anchorTxOut, err := findTxOutByPkScript(
+		justiceTxn, desc.SessionInfo.RewardAddress,
+	)
+	if err != nil {
+		log.Errorf("Unable to find anchor output of justice txn %v: "+
+			"%v", justiceTxid, err)
+		return
+	}
+
+	// The fee of the justice transaction is the value of the breached
+	// outputs it spends that isn't paid to its outputs.
+	var justiceFee btcutil.Amount
+	breachTxid := desc.BreachedCommitTx.TxHash()
+	for _, txIn := range justiceTxn.TxIn {
+		prevOut := txIn.PreviousOutPoint
+		if prevOut.Hash != breachTxid ||
+			int(prevOut.Index) >= len(desc.BreachedCommitTx.TxOut) {
+
+			log.Errorf("Justice txn %v spends unknown input %v",
+				justiceTxid, prevOut)
+			return
+		}
+
+		breachedOut := desc.BreachedCommitTx.TxOut[prevOut.Index]
+		justiceFee += btcutil.Amount(breachedOut.Value)
+	}
+	for _, txOut := range justiceTxn.TxOut {
+		justiceFee -= btcutil.Amount(txOut.Value)
+	}
+
+	anchor := &wtdb.JusticeAnchor{
+		OutPoint: wire.OutPoint{
+			Hash:  justiceTxid,
+			Index: anchorIndex,
+		},
+		TxOut:      anchorTxOut,
+		JusticeFee: justiceFee,
+		JusticeWeight: blockchain.GetTransactionWeight(
+			btcutil.NewTx(justiceTxn),
+		),
+		FeeRate: targetFeeRate,
+	}
+
+	log.Infof("Bumping fee of justice txn %v from %v to %v using its "+
+		"anchor output", justiceTxid, feeRate, targetFeeRate)
+
+	// The sweeper doesn't persist its inputs, so we store the anchor to
+	// hand it over again after a restart.
+	if p.cfg.DB != nil {
+		if err := p.cfg.DB.PutJusticeAnchor(anchor); err != nil {
+			log.Errorf("Unable to store anchor of justice txn %v: "+
+				"%v", justiceTxid, err)
+		}
+	}
+
+	if err := p.cfg.CPFPAnchor(anchor); err != nil {
+		log.Errorf("Unable to bump fee of justice txn %v: %v",
+			justiceTxid, err)
+	}
+}
+
+// ResumeFeeBumps hands the anchor outputs of all justice transactions whose
+// fee was bumped before a restart, and that haven't confirmed since, to
+// CPFPAnchor again. If the fee rate estimated for CPFPConfTarget rose in the
+// meantime, the anchors are bumped to the new estimate.
+func (p *BreachPunisher) ResumeFeeBumps() error {
+	if p.cfg.CPFPAnchor == nil || p.cfg.DB == nil {
+		return nil
+	}
+
+	anchors, err := p.cfg.DB.ListJusticeAnchors()
+	if err != nil {
+		return err
+	}
+
+	for _, anchor := range anchors {
+		justiceTxid := anchor.OutPoint.Hash
+
+		if p.cfg.FeeEstimator != nil {
+			feeRate, err := p.cfg.FeeEstimator.EstimateFeePerKW(
+				CPFPConfTarget,
+			)
+			if err != nil {
+				log.Warnf("Unable to estimate fee rate to "+
+					"bump justice txn %v: %v", justiceTxid,
+					err)
+			} else if feeRate > anchor.FeeRate {
+				anchor.FeeRate = feeRate
+			}
+		}
+
+		log.Infof("Resuming fee bump of justice txn %v to %v",
+			justiceTxid, anchor.FeeRate)
+
+		if err := p.cfg.CPFPAnchor(anchor); err != nil {
+			log.Errorf("Unable to bump fee of justice txn %v: %v",
+				justiceTxid, err)
+		}
+	}
+
+	return nil
+}
+
+// sweepFeeRateIndex returns the index of the fee rate of a dynamic fee
+// session that the justice transaction should use given the current fee
+// estimates. If no estimate is available, the highest fee rate is used to
+// make sure the justice transaction confirms in time.
+func (p *BreachPunisher) sweepFeeRateIndex(desc *JusticeDescriptor) int {
+	policy := desc.SessionInfo.Policy
+	maxIndex := len(policy.SweepFeeRates()) - 1
+
+	if p.cfg.FeeEstimator == nil {
+		return maxIndex
+	}
+
+	feeRate, err := policy.EstimateSweepFeeRate(p.cfg.FeeEstimator)
+	if err != nil {
+		log.Warnf("Unable to estimate sweep fee rate for client=%s, "+
+			"using max sweep fee rate: %v", desc.SessionInfo.ID,
+			err)
+		return maxIndex
+	}
+
+	return policy.SweepFeeRateIndex(feeRate)
+}
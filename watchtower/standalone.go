@@ -33,6 +33,10 @@ type Standalone struct {
 	// transactions found in new blocks against the state updates received
 	// by the server.
 	lookout lookout.Service
+
+	// punisher publishes the justice transactions of the breaches found by
+	// the lookout and bumps their fees.
+	punisher *lookout.BreachPunisher
 }
 
 // New validates the passed Config and returns a fresh Standalone instance if
@@ -54,8 +58,17 @@ func New(cfg *Config) (*Standalone, error) {
 		cfg.WriteTimeout = DefaultWriteTimeout
 	}
 
+	// Assign the default ban duration if clients may be banned.
+	if cfg.RateLimits.MaxViolations > 0 && cfg.RateLimits.BanDuration == 0 {
+		cfg.RateLimits.BanDuration = DefaultBanDuration
+	}
+
 	punisher := lookout.NewBreachPunisher(&lookout.PunisherConfig{
-		PublishTx: cfg.PublishTx,
+		PublishTx:    cfg.PublishTx,
+		FeeEstimator: cfg.FeeEstimator,
+		Metrics:      cfg.Metrics,
+		CPFPAnchor:   cfg.CPFPAnchor,
+		DB:           cfg.DB,
 	})
 
 	// Initialize the lookout service with its required resources.
@@ -72,7 +85,7 @@ func New(cfg *Config) (*Standalone, error) {
 	listeners := make([]net.Listener, 0, len(cfg.ListenAddrs))
 	for _, listenAddr := range cfg.ListenAddrs {
 		listener, err := brontide.NewListener(
-			cfg.NodeKeyECDH, listenAddr.String(),
+			cfg.NodeKeyECDH, listenAddr.String(), nil,
 		)
 		if err != nil {
 			return nil, err
@@ -91,6 +104,8 @@ func New(cfg *Config) (*Standalone, error) {
 		WriteTimeout:  cfg.WriteTimeout,
 		NewAddress:    cfg.NewAddress,
 		DisableReward: true,
+		Metrics:       cfg.Metrics,
+		RateLimits:    cfg.RateLimits,
 	})
 	if err != nil {
 		return nil, err
@@ -101,6 +116,7 @@ func New(cfg *Config) (*Standalone, error) {
 		listeners: listeners,
 		server:    server,
 		lookout:   lookout,
+		punisher:  punisher,
 	}, nil
 }
 
@@ -125,6 +141,14 @@ func (w *Standalone) Start() error {
 	if err := w.lookout.Start(); err != nil {
 		return err
 	}
+
+	// Resume bumping the fees of the justice transactions that haven't
+	// confirmed before we were shut down.
+	if err := w.punisher.ResumeFeeBumps(); err != nil {
+		w.lookout.Stop()
+		return err
+	}
+
 	if err := w.server.Start(); err != nil {
 		w.lookout.Stop()
 		return err
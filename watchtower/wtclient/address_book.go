@@ -0,0 +1,184 @@
+package wtclient
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/lightningnetwork/lnd/watchtower/wtserver"
+)
+
+// maxAnnouncedTowerAddrs is the maximum number of addresses learned from the
+// node announcement of a tower that are dialed in addition to the tower's
+// persisted addresses. It bounds the number of connections a single
+// announcement makes the client open in parallel.
+const maxAnnouncedTowerAddrs = 4
+
+// towerAddrBook tracks the current set of addresses of each tower the client
+// holds sessions with. Session queues are created with a snapshot of their
+// tower, so the address book lets them pick up addresses that were added or
+// removed afterwards.
+type towerAddrBook struct {
+	mu    sync.Mutex
+	addrs map[wtdb.TowerID][]net.Addr
+
+	// announced holds the addresses learned from the latest node
+	// announcement of each tower. They're kept apart from the addresses
+	// added by the user and never persisted. Each newer announcement
+	// replaces them, so that addresses the tower no longer announces
+	// aren't dialed anymore.
+	announced map[wtdb.TowerID][]net.Addr
+}
+
+// newTowerAddrBook creates an empty address book.
+func newTowerAddrBook() *towerAddrBook {
+	return &towerAddrBook{
+		addrs:     make(map[wtdb.TowerID][]net.Addr),
+		announced: make(map[wtdb.TowerID][]net.Addr),
+	}
+}
+
+// update replaces the addresses of the tower with the ones it currently holds.
+func (b *towerAddrBook) update(tower *wtdb.Tower) {
+	addrs := make([]net.Addr, len(tower.Addresses))
+	copy(addrs, tower.Addresses)
+
+	b.mu.Lock()
+	b.addrs[tower.ID] = addrs
+	b.mu.Unlock()
+}
+
+// setAnnounced replaces the announced addresses of the tower with the given
+// ones. Only the first maxAnnouncedTowerAddrs addresses are kept.
+func (b *towerAddrBook) setAnnounced(id wtdb.TowerID, addrs []net.Addr) {
+	if len(addrs) > maxAnnouncedTowerAddrs {
+		addrs = addrs[:maxAnnouncedTowerAddrs]
+	}
+
+	announced := make([]net.Addr, len(addrs))
+	copy(announced, addrs)
+
+	b.mu.Lock()
+	b.announced[id] = announced
+	b.mu.Unlock()
+}
+
+// removeAnnounced removes the given address from the announced addresses of
+// the tower. If no address is given, all announced addresses of the tower are
+// removed.
+func (b *towerAddrBook) removeAnnounced(id wtdb.TowerID, addr net.Addr) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if addr == nil {
+		delete(b.announced, id)
+		return
+	}
+
+	var announced []net.Addr
+	for _, a := range b.announced[id] {
+		if a.String() != addr.String() {
+			announced = append(announced, a)
+		}
+	}
+	b.announced[id] = announced
+}
+
+// get returns the addresses of the given tower, or the fallback addresses if
+// the address book doesn't know the tower, followed by the tower's announced
+// addresses that aren't among them.
+func (b *towerAddrBook) get(id wtdb.TowerID, fallback []net.Addr) []net.Addr {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	addrs, ok := b.addrs[id]
+	if !ok || len(addrs) == 0 {
+		addrs = fallback
+	}
+
+	addrsCopy := make([]net.Addr, len(addrs))
+	copy(addrsCopy, addrs)
+
+	for _, announced := range b.announced[id] {
+		known := false
+		for _, addr := range addrs {
+			if addr.String() == announced.String() {
+				known = true
+				break
+			}
+		}
+
+		if !known {
+			addrsCopy = append(addrsCopy, announced)
+		}
+	}
+
+	return addrsCopy
+}
+
+// dialResult is the outcome of dialing a single address of a tower.
+type dialResult struct {
+	conn wtserver.Peer
+	addr *lnwire.NetAddress
+	err  error
+}
+
+// dialAny dials all given addresses of a tower in parallel and returns the
+// first connection that is established along with its address. Connections
+// established afterwards are closed right away. An error is only returned if
+// none of the addresses could be dialed.
+func dialAny(dial func(*lnwire.NetAddress) (wtserver.Peer, error),
+	pubKey *btcec.PublicKey,
+	addrs []net.Addr) (wtserver.Peer, *lnwire.NetAddress, error) {
+
+	if len(addrs) == 0 {
+		return nil, nil, ErrNoTowerAddrs
+	}
+
+	results := make(chan *dialResult, len(addrs))
+	for _, addr := range addrs {
+		lnAddr := &lnwire.NetAddress{
+			IdentityKey: pubKey,
+			Address:     addr,
+		}
+
+		go func() {
+			conn, err := dial(lnAddr)
+			results <- &dialResult{
+				conn: conn,
+				addr: lnAddr,
+				err:  err,
+			}
+		}()
+	}
+
+	var errs []string
+	for i := 0; i < len(addrs); i++ {
+		result := <-results
+		if result.err != nil {
+			errs = append(errs, fmt.Sprintf("%v: %v", result.addr,
+				result.err))
+			continue
+		}
+
+		// Close any connection to the remaining addresses that
+		// completes after this one.
+		remaining := len(addrs) - i - 1
+		go func() {
+			for j := 0; j < remaining; j++ {
+				if late := <-results; late.err == nil {
+					late.conn.Close()
+				}
+			}
+		}()
+
+		return result.conn, result.addr, nil
+	}
+
+	return nil, nil, fmt.Errorf("unable to dial any tower address: %v",
+		strings.Join(errs, ", "))
+}
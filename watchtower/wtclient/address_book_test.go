@@ -0,0 +1,111 @@
+package wtclient
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/lightningnetwork/lnd/watchtower/wtmock"
+	"github.com/lightningnetwork/lnd/watchtower/wtserver"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDialAny asserts that dialAny returns a connection to any reachable
+// address of a tower, and only fails if none of them can be reached.
+func TestDialAny(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	pubKey := privKey.PubKey()
+
+	unreachable := &net.TCPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 9911}
+	reachable := &net.TCPAddr{IP: net.IPv4(5, 6, 7, 8), Port: 9911}
+
+	dial := func(addr *lnwire.NetAddress) (wtserver.Peer, error) {
+		if addr.Address.String() == unreachable.String() {
+			return nil, errors.New("unreachable")
+		}
+
+		return wtmock.NewMockPeer(
+			pubKey, pubKey, addr.Address, 0,
+		), nil
+	}
+
+	_, _, err = dialAny(dial, pubKey, nil)
+	require.ErrorIs(t, err, ErrNoTowerAddrs)
+
+	_, _, err = dialAny(dial, pubKey, []net.Addr{unreachable})
+	require.Error(t, err)
+
+	conn, addr, err := dialAny(
+		dial, pubKey, []net.Addr{unreachable, reachable},
+	)
+	require.NoError(t, err)
+	require.Equal(t, reachable, addr.Address)
+	require.Equal(t, reachable, conn.RemoteAddr())
+}
+
+// TestTowerAddrBook asserts that the address book returns the latest
+// addresses of a tower, falling back to the given ones for unknown towers.
+func TestTowerAddrBook(t *testing.T) {
+	t.Parallel()
+
+	addr1 := &net.TCPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 9911}
+	addr2 := &net.TCPAddr{IP: net.IPv4(5, 6, 7, 8), Port: 9911}
+
+	book := newTowerAddrBook()
+	require.Equal(t, []net.Addr{addr1}, book.get(1, []net.Addr{addr1}))
+
+	tower := &wtdb.Tower{ID: 1, Addresses: []net.Addr{addr1}}
+	book.update(tower)
+	tower.AddAddress(addr2)
+	require.Equal(t, []net.Addr{addr1}, book.get(1, nil))
+
+	book.update(tower)
+	require.ElementsMatch(t, []net.Addr{addr1, addr2}, book.get(1, nil))
+}
+
+// TestTowerAddrBookAnnounced asserts that announced addresses are dialed along
+// with the persisted ones, are capped, and are replaced by newer
+// announcements.
+func TestTowerAddrBookAnnounced(t *testing.T) {
+	t.Parallel()
+
+	persisted := &net.TCPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 9911}
+
+	var announced []net.Addr
+	for i := 0; i < maxAnnouncedTowerAddrs+2; i++ {
+		announced = append(announced, &net.TCPAddr{
+			IP: net.IPv4(5, 6, 7, byte(i)), Port: 9911,
+		})
+	}
+
+	book := newTowerAddrBook()
+	book.update(&wtdb.Tower{ID: 1, Addresses: []net.Addr{persisted}})
+
+	// Only the first announced addresses are kept, and an announced
+	// address that is already persisted isn't dialed twice.
+	book.setAnnounced(1, append([]net.Addr{persisted}, announced...))
+	require.Equal(
+		t, append([]net.Addr{persisted}, announced[:3]...),
+		book.get(1, nil),
+	)
+
+	// A newer announcement replaces the previous addresses.
+	book.setAnnounced(1, announced[4:5])
+	require.Equal(
+		t, []net.Addr{persisted, announced[4]}, book.get(1, nil),
+	)
+
+	// Removed addresses are no longer dialed.
+	book.removeAnnounced(1, announced[4])
+	require.Equal(t, []net.Addr{persisted}, book.get(1, nil))
+
+	book.setAnnounced(1, announced[:1])
+	book.removeAnnounced(1, nil)
+	require.Equal(t, []net.Addr{persisted}, book.get(1, nil))
+}
@@ -0,0 +1,107 @@
+package wtclient
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+)
+
+// ChannelBackup records that a revoked state of a channel was backed up to a
+// watchtower.
+type ChannelBackup struct {
+	// CommitHeight is the commitment height of the revoked state.
+	CommitHeight uint64
+
+	// SessionID is the session the state was backed up with.
+	SessionID wtdb.SessionID
+
+	// Tower is the watchtower the state was backed up to.
+	Tower *wtdb.Tower
+}
+
+// HeightRange is an inclusive range of commitment heights.
+type HeightRange struct {
+	Start uint64
+	End   uint64
+}
+
+// BackupAudit lists which revoked states of a channel were backed up to which
+// watchtower, and which states within the audited range weren't backed up at
+// all.
+type BackupAudit struct {
+	// Backups are the backed up states of the channel, sorted by commit
+	// height.
+	Backups []*ChannelBackup
+
+	// Gaps are the ranges of commit heights within the audited range that
+	// haven't been backed up to any watchtower.
+	Gaps []HeightRange
+}
+
+// AuditChannel returns the revoked states of the given channel that were
+// backed up to a watchtower, and the gaps in the coverage of the inclusive
+// range [startHeight, endHeight].
+func (c *TowerClient) AuditChannel(chanID lnwire.ChannelID, startHeight,
+	endHeight uint64) (*BackupAudit, error) {
+
+	chanBackups, err := c.cfg.DB.FetchChanBackups(chanID)
+	if err != nil {
+		return nil, err
+	}
+
+	towers := make(map[wtdb.TowerID]*wtdb.Tower)
+	backups := make([]*ChannelBackup, 0, len(chanBackups))
+	heights := make([]uint64, 0, len(chanBackups))
+	for _, chanBackup := range chanBackups {
+		tower, ok := towers[chanBackup.TowerID]
+		if !ok {
+			tower, err = c.cfg.DB.LoadTowerByID(chanBackup.TowerID)
+			if err != nil {
+				return nil, err
+			}
+			towers[chanBackup.TowerID] = tower
+		}
+
+		backups = append(backups, &ChannelBackup{
+			CommitHeight: chanBackup.CommitHeight,
+			SessionID:    chanBackup.SessionID,
+			Tower:        tower,
+		})
+		heights = append(heights, chanBackup.CommitHeight)
+	}
+
+	return &BackupAudit{
+		Backups: backups,
+		Gaps:    findGaps(heights, startHeight, endHeight),
+	}, nil
+}
+
+// findGaps returns the ranges of heights within [start, end] that aren't
+// covered by the given heights, which must be sorted in ascending order.
+func findGaps(heights []uint64, start, end uint64) []HeightRange {
+	var (
+		gaps []HeightRange
+		next = start
+	)
+	for _, height := range heights {
+		if height > end {
+			break
+		}
+		if height < next {
+			continue
+		}
+
+		if height > next {
+			gaps = append(gaps, HeightRange{
+				Start: next,
+				End:   height - 1,
+			})
+		}
+		next = height + 1
+	}
+
+	if next <= end {
+		gaps = append(gaps, HeightRange{Start: next, End: end})
+	}
+
+	return gaps
+}
@@ -0,0 +1,362 @@
+package wtclient
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/lightningnetwork/lnd/watchtower/wtwire"
+)
+
+const (
+	// DefaultMinBackoff is the default initial backoff applied after a
+	// failed connection attempt to a tower.
+	DefaultMinBackoff = 10 * time.Second
+
+	// DefaultMaxBackoff is the default maximum backoff applied between
+	// connection attempts to a tower.
+	DefaultMaxBackoff = 5 * time.Minute
+
+	// DefaultBackoffMultiplier is the default factor by which the backoff
+	// grows after each consecutive failed connection attempt.
+	DefaultBackoffMultiplier = 2.0
+
+	// DefaultCircuitBreakerCooldown is the default duration for which a
+	// tower is not dialed once its circuit breaker has tripped.
+	DefaultCircuitBreakerCooldown = 30 * time.Minute
+)
+
+var (
+	// ErrInvalidBackoffMultiplier signals that the configured backoff
+	// multiplier would not grow the backoff.
+	ErrInvalidBackoffMultiplier = errors.New("backoff multiplier must be " +
+		"at least 1")
+
+	// ErrInvalidBackoffJitter signals that the configured backoff jitter
+	// is not a fraction between 0 and 1.
+	ErrInvalidBackoffJitter = errors.New("backoff jitter must be " +
+		"between 0 and 1")
+
+	// ErrInvalidBackoffRange signals that the configured minimum backoff
+	// exceeds the configured maximum backoff.
+	ErrInvalidBackoffRange = errors.New("min backoff must not exceed " +
+		"max backoff")
+)
+
+// BackoffPolicy describes how the client backs off after failed connection
+// attempts to a watchtower.
+type BackoffPolicy struct {
+	// MinBackoff defines the initial backoff applied to connections with
+	// watchtowers. Subsequent backoff durations will grow exponentially up
+	// until MaxBackoff.
+	MinBackoff time.Duration
+
+	// MaxBackoff defines the maximum backoff applied to connections with
+	// watchtowers. If the exponential backoff produces a timeout greater
+	// than this value, the backoff will be clamped to MaxBackoff.
+	MaxBackoff time.Duration
+
+	// Multiplier is the factor by which the backoff grows after each
+	// consecutive failure.
+	Multiplier float64
+
+	// Jitter is the fraction of the backoff by which each delay is
+	// randomly lengthened or shortened. This prevents connection attempts
+	// to the same tower from being synchronized. A value of zero disables
+	// jitter.
+	Jitter float64
+
+	// CircuitBreakerThreshold is the number of consecutive failed
+	// connection attempts after which a tower is no longer dialed until
+	// CircuitBreakerCooldown has passed. A value of zero disables the
+	// circuit breaker.
+	CircuitBreakerThreshold uint32
+
+	// CircuitBreakerCooldown is the duration for which a tower is not
+	// dialed once its circuit breaker has tripped.
+	CircuitBreakerCooldown time.Duration
+}
+
+// DefaultBackoffPolicy returns the backoff policy used if none is configured.
+func DefaultBackoffPolicy() BackoffPolicy {
+	return BackoffPolicy{
+		MinBackoff:             DefaultMinBackoff,
+		MaxBackoff:             DefaultMaxBackoff,
+		Multiplier:             DefaultBackoffMultiplier,
+		CircuitBreakerCooldown: DefaultCircuitBreakerCooldown,
+	}
+}
+
+// Validate ensures that the backoff policy is sane.
+func (p *BackoffPolicy) Validate() error {
+	if p.Multiplier < 1 {
+		return ErrInvalidBackoffMultiplier
+	}
+
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return ErrInvalidBackoffJitter
+	}
+
+	if p.MinBackoff > p.MaxBackoff {
+		return ErrInvalidBackoffRange
+	}
+
+	return nil
+}
+
+// nextBackoff returns the backoff that follows the given one, clamped to the
+// policy's min and max backoff.
+func (p *BackoffPolicy) nextBackoff(backoff time.Duration) time.Duration {
+	if backoff == 0 {
+		return p.MinBackoff
+	}
+
+	next := time.Duration(float64(backoff) * p.Multiplier)
+	if next > p.MaxBackoff || next < backoff {
+		next = p.MaxBackoff
+	}
+
+	return next
+}
+
+// withJitter randomly lengthens or shortens the given delay by at most the
+// policy's jitter fraction.
+func (p *BackoffPolicy) withJitter(delay time.Duration) time.Duration {
+	if p.Jitter == 0 || delay == 0 {
+		return delay
+	}
+
+	offset := (2*rand.Float64() - 1) * p.Jitter * float64(delay)
+
+	return delay + time.Duration(offset)
+}
+
+// TowerBackoffState is a snapshot of the connection backoff state of a single
+// tower.
+type TowerBackoffState struct {
+	// ConsecutiveFailures is the number of connection attempts to the
+	// tower that failed since the last successful one.
+	ConsecutiveFailures uint32
+
+	// Backoff is the backoff applied before the next connection attempt,
+	// not including any jitter.
+	Backoff time.Duration
+
+	// LastFailure is the time of the last failed connection attempt.
+	LastFailure time.Time
+
+	// CircuitOpenUntil is the time until which the tower won't be dialed
+	// because its circuit breaker tripped. It is the zero time if the
+	// circuit breaker is closed.
+	CircuitOpenUntil time.Time
+}
+
+// CircuitOpen returns true if the tower's circuit breaker was tripped and its
+// cooldown has not yet passed at the given time.
+func (s *TowerBackoffState) CircuitOpen(now time.Time) bool {
+	return now.Before(s.CircuitOpenUntil)
+}
+
+// latencySmoothing is the weight given to a new latency sample in the moving
+// average of a tower's latency.
+const latencySmoothing = 0.2
+
+// TowerHealth summarizes how reliably a tower could be reached since the
+// client started.
+type TowerHealth struct {
+	// Latency is the exponentially weighted moving average of the time
+	// taken to connect to the tower. It is zero if the tower was never
+	// reached.
+	Latency time.Duration
+
+	// SuccessfulAttempts is the number of attempts to reach the tower
+	// that succeeded.
+	SuccessfulAttempts uint32
+
+	// FailedAttempts is the number of attempts to reach the tower that
+	// failed.
+	FailedAttempts uint32
+
+	// LastSuccess is the time of the last successful attempt.
+	LastSuccess time.Time
+
+	// NumSessions is the number of active sessions held with the tower
+	// across the clients sharing the same SessionCounter. Exhausted
+	// sessions aren't counted.
+	NumSessions uint32
+
+	// Negotiation is the protocol version and features negotiated during
+	// the latest exchange of Init messages with the tower, or nil if none
+	// took place yet.
+	Negotiation *wtwire.Negotiation
+}
+
+// towerBackoff tracks the connection backoff state and health of a single
+// tower. It is shared by all session queues and negotiations dialing the same
+// tower.
+type towerBackoff struct {
+	policy *BackoffPolicy
+
+	mu     sync.Mutex
+	state  TowerBackoffState
+	health TowerHealth
+}
+
+// recordFailure registers a failed connection attempt to the tower and
+// returns the delay to wait before the next attempt.
+func (b *towerBackoff) recordFailure() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.health.FailedAttempts++
+	b.state.ConsecutiveFailures++
+	b.state.LastFailure = now
+	b.state.Backoff = b.policy.nextBackoff(b.state.Backoff)
+
+	// Trip the circuit breaker once the threshold is reached. A failure
+	// after the cooldown passed re-trips it right away.
+	threshold := b.policy.CircuitBreakerThreshold
+	if threshold > 0 && b.state.ConsecutiveFailures >= threshold {
+		b.state.CircuitOpenUntil = now.Add(
+			b.policy.CircuitBreakerCooldown,
+		)
+
+		return b.policy.CircuitBreakerCooldown
+	}
+
+	return b.policy.withJitter(b.state.Backoff)
+}
+
+// recordSuccess registers a successful connection attempt to the tower,
+// resetting its backoff and closing its circuit breaker.
+func (b *towerBackoff) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = TowerBackoffState{}
+	b.health.SuccessfulAttempts++
+	b.health.LastSuccess = time.Now()
+}
+
+// recordLatency adds the time taken to connect to the tower to its moving
+// average latency.
+func (b *towerBackoff) recordLatency(latency time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.health.Latency == 0 {
+		b.health.Latency = latency
+		return
+	}
+
+	b.health.Latency += time.Duration(
+		latencySmoothing * float64(latency-b.health.Latency),
+	)
+}
+
+// recordNegotiation stores the outcome of the latest exchange of Init messages
+// with the tower.
+func (b *towerBackoff) recordNegotiation(negotiation *wtwire.Negotiation) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.health.Negotiation = negotiation
+}
+
+// dialDelay returns the duration until the tower may be dialed again because
+// its circuit breaker tripped, or zero if it can be dialed right away.
+func (b *towerBackoff) dialDelay() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delay := time.Until(b.state.CircuitOpenUntil)
+	if delay < 0 {
+		return 0
+	}
+
+	return delay
+}
+
+// snapshot returns a copy of the tower's current backoff state.
+func (b *towerBackoff) snapshot() TowerBackoffState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// healthSnapshot returns a copy of the tower's current health.
+func (b *towerBackoff) healthSnapshot() TowerHealth {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.health
+}
+
+// towerBackoffSet tracks the connection backoff state of all towers the client
+// dials.
+type towerBackoffSet struct {
+	policy   *BackoffPolicy
+	sessions *SessionCounter
+
+	mu       sync.Mutex
+	backoffs map[wtdb.TowerID]*towerBackoff
+}
+
+// newTowerBackoffSet creates a towerBackoffSet applying the given policy. The
+// number of sessions held with each tower is taken from the given counter.
+func newTowerBackoffSet(policy *BackoffPolicy,
+	sessions *SessionCounter) *towerBackoffSet {
+
+	return &towerBackoffSet{
+		policy:   policy,
+		sessions: sessions,
+		backoffs: make(map[wtdb.TowerID]*towerBackoff),
+	}
+}
+
+// get returns the backoff state tracker of the given tower, creating it if
+// it doesn't exist yet.
+func (s *towerBackoffSet) get(id wtdb.TowerID) *towerBackoff {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.backoffs[id]
+	if !ok {
+		b = &towerBackoff{policy: s.policy}
+		s.backoffs[id] = b
+	}
+
+	return b
+}
+
+// state returns a snapshot of the backoff state of the given tower.
+func (s *towerBackoffSet) state(id wtdb.TowerID) TowerBackoffState {
+	s.mu.Lock()
+	b, ok := s.backoffs[id]
+	s.mu.Unlock()
+
+	if !ok {
+		return TowerBackoffState{}
+	}
+
+	return b.snapshot()
+}
+
+// health returns a snapshot of the health of the given tower.
+func (s *towerBackoffSet) health(id wtdb.TowerID) TowerHealth {
+	s.mu.Lock()
+	b, ok := s.backoffs[id]
+	s.mu.Unlock()
+
+	var health TowerHealth
+	if ok {
+		health = b.healthSnapshot()
+	}
+	health.NumSessions = s.sessions.count(id)
+
+	return health
+}
@@ -0,0 +1,129 @@
+package wtclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBackoffPolicyNextBackoff asserts that the backoff grows by the
+// configured multiplier and is clamped to the policy's max backoff.
+func TestBackoffPolicyNextBackoff(t *testing.T) {
+	t.Parallel()
+
+	policy := &BackoffPolicy{
+		MinBackoff: time.Second,
+		MaxBackoff: 10 * time.Second,
+		Multiplier: 3,
+	}
+	require.NoError(t, policy.Validate())
+
+	var backoff time.Duration
+	expected := []time.Duration{
+		time.Second, 3 * time.Second, 9 * time.Second,
+		10 * time.Second, 10 * time.Second,
+	}
+	for _, exp := range expected {
+		backoff = policy.nextBackoff(backoff)
+		require.Equal(t, exp, backoff)
+	}
+}
+
+// TestBackoffPolicyJitter asserts that jittered delays stay within the
+// configured fraction of the backoff.
+func TestBackoffPolicyJitter(t *testing.T) {
+	t.Parallel()
+
+	policy := &BackoffPolicy{
+		MinBackoff: time.Second,
+		MaxBackoff: time.Minute,
+		Multiplier: 2,
+		Jitter:     0.25,
+	}
+	require.NoError(t, policy.Validate())
+
+	const delay = 10 * time.Second
+	for i := 0; i < 1000; i++ {
+		jittered := policy.withJitter(delay)
+		require.GreaterOrEqual(t, jittered, 7500*time.Millisecond)
+		require.LessOrEqual(t, jittered, 12500*time.Millisecond)
+	}
+}
+
+// TestBackoffPolicyValidate asserts that invalid backoff policies are
+// rejected.
+func TestBackoffPolicyValidate(t *testing.T) {
+	t.Parallel()
+
+	policy := DefaultBackoffPolicy()
+	require.NoError(t, policy.Validate())
+
+	policy.Multiplier = 0.5
+	require.ErrorIs(t, policy.Validate(), ErrInvalidBackoffMultiplier)
+
+	policy = DefaultBackoffPolicy()
+	policy.Jitter = 1.5
+	require.ErrorIs(t, policy.Validate(), ErrInvalidBackoffJitter)
+
+	policy = DefaultBackoffPolicy()
+	policy.MinBackoff = policy.MaxBackoff + 1
+	require.ErrorIs(t, policy.Validate(), ErrInvalidBackoffRange)
+}
+
+// TestTowerBackoffCircuitBreaker asserts that a tower's circuit breaker trips
+// once the configured number of consecutive failures is reached, and that a
+// successful connection resets the tower's backoff state.
+func TestTowerBackoffCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	policy := &BackoffPolicy{
+		MinBackoff:              time.Millisecond,
+		MaxBackoff:              time.Second,
+		Multiplier:              2,
+		CircuitBreakerThreshold: 3,
+		CircuitBreakerCooldown:  time.Hour,
+	}
+	sessions := NewSessionCounter()
+	backoffs := newTowerBackoffSet(policy, sessions)
+
+	var id wtdb.TowerID = 1
+	b := backoffs.get(id)
+	require.Same(t, b, backoffs.get(id))
+
+	// The first failures below the threshold only apply the exponential
+	// backoff.
+	require.Equal(t, time.Millisecond, b.recordFailure())
+	require.Equal(t, 2*time.Millisecond, b.recordFailure())
+	require.Zero(t, b.dialDelay())
+
+	// Reaching the threshold trips the circuit breaker.
+	require.Equal(t, time.Hour, b.recordFailure())
+	require.NotZero(t, b.dialDelay())
+
+	state := backoffs.state(id)
+	require.EqualValues(t, 3, state.ConsecutiveFailures)
+	require.True(t, state.CircuitOpen(time.Now()))
+
+	// A successful connection resets the state entirely.
+	b.recordSuccess()
+	require.Zero(t, b.dialDelay())
+	require.Equal(t, TowerBackoffState{}, backoffs.state(id))
+
+	// Towers that were never dialed have an empty state.
+	require.Equal(t, TowerBackoffState{}, backoffs.state(2))
+
+	// The health of the tower isn't reset by a successful connection.
+	sessions.add(newCountedSession(id, 1, 0))
+	b.recordLatency(100 * time.Millisecond)
+	b.recordLatency(200 * time.Millisecond)
+
+	health := backoffs.health(id)
+	require.EqualValues(t, 3, health.FailedAttempts)
+	require.EqualValues(t, 1, health.SuccessfulAttempts)
+	require.EqualValues(t, 1, health.NumSessions)
+	require.False(t, health.LastSuccess.IsZero())
+	require.Equal(t, 120*time.Millisecond, health.Latency)
+	require.Equal(t, TowerHealth{}, backoffs.health(2))
+}
@@ -53,6 +53,12 @@ type backupTask struct {
 
 	blobType blob.Type
 	outputs  []*wire.TxOut
+
+	// dynamicFeeOutputs holds the outputs of the justice transactions at
+	// the higher fee rates of a dynamic fee session, in increasing order
+	// of fee rate. The outputs at the lowest fee rate are stored in
+	// outputs.
+	dynamicFeeOutputs [][]*wire.TxOut
 }
 
 // newBackupTask initializes a new backupTask and populates all state-dependent
@@ -235,9 +241,11 @@ func (t *backupTask) bindSession(session *wtdb.ClientSessionBody) error {
 		return err
 	}
 
-	// If the justice transaction has a reward output, add the output's
-	// contribution to the weight estimate.
-	if session.Policy.BlobType.Has(blob.FlagReward) {
+	// If the justice transaction has a reward or anchor output for the
+	// tower, add the output's contribution to the weight estimate.
+	if session.Policy.BlobType.Has(blob.FlagReward) ||
+		session.Policy.HasCPFPAnchor() {
+
 		err := addScriptWeight(&weightEstimate, session.RewardPkScript)
 		if err != nil {
 			return err
@@ -251,17 +259,28 @@ func (t *backupTask) bindSession(session *wtdb.ClientSessionBody) error {
 	}
 
 	// Now, compute the output values depending on whether FlagReward is set
-	// in the current session's policy.
-	outputs, err := session.Policy.ComputeJusticeTxOuts(
-		t.totalAmt, int64(weightEstimate.Weight()),
-		t.sweepPkScript, session.RewardPkScript,
-	)
-	if err != nil {
-		return err
+	// in the current session's policy. Dynamic fee sessions require a
+	// justice transaction for each of the session's fee rates, all of
+	// which must be valid for the task to be bound.
+	feeRates := session.Policy.SweepFeeRates()
+	txOuts := make([][]*wire.TxOut, 0, len(feeRates))
+	for _, feeRate := range feeRates {
+		outputs, err := session.Policy.ComputeJusticeTxOuts(
+			t.totalAmt, int64(weightEstimate.Weight()), feeRate,
+			t.sweepPkScript, session.RewardPkScript,
+		)
+		if err != nil {
+			return err
+		}
+
+		txOuts = append(txOuts, outputs)
 	}
 
 	t.blobType = session.Policy.BlobType
-	t.outputs = outputs
+	t.outputs = txOuts[0]
+	if len(txOuts) > 1 {
+		t.dynamicFeeOutputs = txOuts[1:]
+	}
 
 	return nil
 }
@@ -298,6 +317,46 @@ func (t *backupTask) craftSessionPayload(
 		)
 	}
 
+	// Sign the justice transaction at the session's lowest fee rate, and
+	// for dynamic fee sessions at each of the higher fee rates.
+	sigs, err := t.signJusticeTxn(signer, t.outputs)
+	if err != nil {
+		return hint, nil, err
+	}
+	justiceKit.CommitToLocalSig = sigs.CommitToLocalSig
+	justiceKit.CommitToRemoteSig = sigs.CommitToRemoteSig
+
+	for i, outputs := range t.dynamicFeeOutputs {
+		sigs, err := t.signJusticeTxn(signer, outputs)
+		if err != nil {
+			return hint, nil, err
+		}
+		justiceKit.DynamicFeeSigs[i] = *sigs
+	}
+
+	breachTxID := t.breachInfo.BreachTxHash
+
+	// Compute the breach key as SHA256(txid).
+	hint, key := blob.NewBreachHintAndKeyFromHash(&breachTxID)
+
+	// Then, we'll encrypt the computed justice kit using the full breach
+	// transaction id, which will allow the tower to recover the contents
+	// after the transaction is seen in the chain or mempool.
+	encBlob, err := justiceKit.Encrypt(key)
+	if err != nil {
+		return hint, nil, err
+	}
+
+	return hint, encBlob, nil
+}
+
+// signJusticeTxn constructs the justice transaction with the given outputs and
+// signs it, returning the signatures for the to-local and to-remote inputs.
+func (t *backupTask) signJusticeTxn(signer input.Signer,
+	outputs []*wire.TxOut) (*blob.FeeRateSigs, error) {
+
+	var sigs blob.FeeRateSigs
+
 	// Now, begin construction of the justice transaction. We'll start with
 	// a version 2 transaction.
 	justiceTxn := wire.NewMsgTx(2)
@@ -319,7 +378,7 @@ func (t *backupTask) craftSessionPayload(
 
 	// Add the sweep output paying directly to the user and possibly a
 	// reward output, using the outputs computed when the task was bound.
-	justiceTxn.TxOut = t.outputs
+	justiceTxn.TxOut = outputs
 
 	// Sort the justice transaction according to BIP69.
 	txsort.InPlaceSort(justiceTxn)
@@ -328,7 +387,7 @@ func (t *backupTask) craftSessionPayload(
 	// before attempting to attach the witnesses.
 	btx := btcutil.NewTx(justiceTxn)
 	if err := blockchain.CheckTransactionSanity(btx); err != nil {
-		return hint, nil, err
+		return nil, err
 	}
 
 	// Construct a sighash cache to improve signing performance.
@@ -354,7 +413,7 @@ func (t *backupTask) craftSessionPayload(
 			signer, justiceTxn, hashCache, prevOutputFetcher, i,
 		)
 		if err != nil {
-			return hint, nil, err
+			return nil, err
 		}
 
 		// Parse the DER-encoded signature from the first position of
@@ -367,42 +426,29 @@ func (t *backupTask) craftSessionPayload(
 		// signature.
 		signature, err := lnwire.NewSigFromRawSignature(rawSignature)
 		if err != nil {
-			return hint, nil, err
+			return nil, err
 		}
 
-		// Finally, copy the serialized signature into the justice kit,
+		// Finally, copy the serialized signature into the result,
 		// using the input's witness type to select the appropriate
 		// field.
 		switch inp.WitnessType() {
 		case input.CommitmentRevoke:
-			copy(justiceKit.CommitToLocalSig[:], signature[:])
+			copy(sigs.CommitToLocalSig[:], signature[:])
 
 		case input.CommitSpendNoDelayTweakless:
 			fallthrough
 		case input.CommitmentNoDelay:
 			fallthrough
 		case input.CommitmentToRemoteConfirmed:
-			copy(justiceKit.CommitToRemoteSig[:], signature[:])
+			copy(sigs.CommitToRemoteSig[:], signature[:])
 		default:
-			return hint, nil, fmt.Errorf("invalid witness type: %v",
+			return nil, fmt.Errorf("invalid witness type: %v",
 				inp.WitnessType())
 		}
 	}
 
-	breachTxID := t.breachInfo.BreachTxHash
-
-	// Compute the breach key as SHA256(txid).
-	hint, key := blob.NewBreachHintAndKeyFromHash(&breachTxID)
-
-	// Then, we'll encrypt the computed justice kit using the full breach
-	// transaction id, which will allow the tower to recover the contents
-	// after the transaction is seen in the chain or mempool.
-	encBlob, err := justiceKit.Encrypt(key)
-	if err != nil {
-		return hint, nil, err
-	}
-
-	return hint, encBlob, nil
+	return &sigs, nil
 }
 
 // toBlobPubKey serializes the given pubkey into a blob.PubKey that can be set
@@ -227,6 +227,20 @@ func genTaskTest(
 		}
 	}
 
+	// Dynamic fee sessions sign justice transactions for fee rates of up to
+	// four times the given sweep fee rate.
+	policy := wtpolicy.Policy{
+		TxPolicy: wtpolicy.TxPolicy{
+			BlobType:     blobType,
+			SweepFeeRate: sweepFeeRate,
+			RewardRate:   10000,
+		},
+	}
+	if blobType.HasDynamicFee() {
+		policy.MaxSweepFeeRate = 4 * sweepFeeRate
+		policy.SweepFeePercentile = wtpolicy.DefaultSweepFeePercentile
+	}
+
 	return backupTaskTest{
 		name:             name,
 		breachInfo:       breachInfo,
@@ -237,13 +251,7 @@ func genTaskTest(
 		expRewardAmt:     expRewardAmt,
 		expRewardScript:  rewardScript,
 		session: &wtdb.ClientSessionBody{
-			Policy: wtpolicy.Policy{
-				TxPolicy: wtpolicy.TxPolicy{
-					BlobType:     blobType,
-					SweepFeeRate: sweepFeeRate,
-					RewardRate:   10000,
-				},
-			},
+			Policy:         policy,
 			RewardPkScript: rewardScript,
 		},
 		bindErr:        bindErr,
@@ -258,6 +266,8 @@ var (
 
 	blobTypeCommitReward = (blob.FlagCommitOutputs | blob.FlagReward).Type()
 
+	blobTypeCommitDynamicFee = blob.TypeAltruistDynamicFeeCommit
+
 	addr, _ = btcutil.DecodeAddress(
 		"tb1pw8gzj8clt3v5lxykpgacpju5n8xteskt7gxhmudu6pa70nwfhe6s3unsyk",
 		&chaincfg.TestNet3Params,
@@ -479,6 +489,32 @@ func TestBackupTask(t *testing.T) {
 				wtpolicy.ErrCreatesDust, // bindErr
 				chanType,
 			),
+			genTaskTest(
+				"commit dynamic fee, both outputs",
+				400,                        // stateNum
+				200000,                     // toLocalAmt
+				100000,                     // toRemoteAmt
+				blobTypeCommitDynamicFee,   // blobType
+				1000,                       // sweepFeeRate
+				nil,                        // rewardScript
+				expSweepCommitNoRewardBoth, // expSweepAmt
+				0,                          // expRewardAmt
+				nil,                        // bindErr
+				chanType,
+			),
+			genTaskTest(
+				"commit dynamic fee, max fee rate creates dust",
+				401,                              // stateNum
+				0,                                // toLocalAmt
+				100000,                           // toRemoteAmt
+				blobTypeCommitDynamicFee,         // blobType
+				sweepFeeRateNoRewardRemoteDust/4, // sweepFeeRate
+				nil,                              // rewardScript
+				0,                                // expSweepAmt
+				0,                                // expRewardAmt
+				wtpolicy.ErrCreatesDust,          // bindErr
+				chanType,
+			),
 		}...)
 	}
 
@@ -574,6 +610,8 @@ func testBackupTask(t *testing.T, test backupTaskTest) {
 				"found: %v", task.outputs)
 		}
 
+		require.Nil(t, task.dynamicFeeOutputs)
+
 		return
 	}
 
@@ -698,4 +736,28 @@ func testBackupTask(t *testing.T, test backupTaskTest) {
 	case !hasToRemote && !emptyToRemoteSig:
 		t.Fatalf("to-remote signature should be empty")
 	}
+
+	// Dynamic fee sessions also carry signatures for each of the higher
+	// fee rates of the session.
+	if !policy.HasDynamicFee() {
+		require.Nil(t, task.dynamicFeeOutputs)
+		return
+	}
+
+	require.Len(t, task.dynamicFeeOutputs, blob.NumDynamicFeeRates-1)
+	for i, sigs := range jKit.DynamicFeeSigs {
+		// Higher fee rates sweep less than the lowest one.
+		require.Less(
+			t, task.dynamicFeeOutputs[i][0].Value, test.expSweepAmt,
+		)
+
+		require.Equal(
+			t, hasToLocal,
+			!bytes.Equal(sigs.CommitToLocalSig[:], zeroSig[:]),
+		)
+		require.Equal(
+			t, hasToRemote,
+			!bytes.Equal(sigs.CommitToRemoteSig[:], zeroSig[:]),
+		)
+	}
 }
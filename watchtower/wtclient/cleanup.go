@@ -0,0 +1,296 @@
+package wtclient
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channelnotifier"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/subscribe"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/lightningnetwork/lnd/watchtower/wtserver"
+	"github.com/lightningnetwork/lnd/watchtower/wtwire"
+)
+
+// isClosable returns whether the given session can be deleted, which is the
+// case once it is exhausted, all of its updates were acked by the tower, and
+// all of the channels it backed up states of were cooperatively closed. As a
+// cooperative close can't be breached, the tower no longer needs any of the
+// session's justice transactions.
+func isClosable(s *wtdb.ClientSession,
+	closedChans map[lnwire.ChannelID]struct{}) bool {
+
+	if s.SeqNum < s.Policy.MaxUpdates || len(s.CommittedUpdates) > 0 ||
+		len(s.AckedUpdates) == 0 {
+
+		return false
+	}
+
+	for _, backupID := range s.AckedUpdates {
+		if _, ok := closedChans[backupID.ChanID]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Cleanup deletes all sessions of the client that are no longer needed
+// because all channels they backed up states of were cooperatively closed.
+// Each session is first deleted at its tower, and only then removed from the
+// database. Sessions that can't be deleted at their tower, for example because
+// it is unreachable, are kept and retried by the next cleanup. The number of
+// deleted sessions is returned.
+func (c *TowerClient) Cleanup() (int, error) {
+	c.cleanupMu.Lock()
+	defer c.cleanupMu.Unlock()
+
+	closedChans, err := c.cfg.DB.FetchClosedChannels()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := c.reconcileClosedChannels(closedChans); err != nil {
+		return 0, err
+	}
+	if len(closedChans) == 0 {
+		return 0, nil
+	}
+
+	sessions, err := c.cfg.DB.ListClientSessions(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	// Both clients share the same database, so we only delete the
+	// sessions negotiated for our own channel type.
+	isAnchorClient := c.cfg.Policy.IsAnchorChannel()
+
+	var numDeleted int
+	for _, s := range sessions {
+		if s.Policy.IsAnchorChannel() != isAnchorClient ||
+			!isClosable(s, closedChans) {
+
+			continue
+		}
+
+		if err := c.deleteSession(s); err != nil {
+			c.log.Warnf("Unable to delete session %s: %v", s.ID,
+				err)
+			continue
+		}
+
+		c.log.Infof("Deleted session %s, all of its channels are "+
+			"closed", s.ID)
+		numDeleted++
+	}
+
+	return numDeleted, nil
+}
+
+// reconcileClosedChannels marks the registered channels that channeldb knows
+// as cooperatively closed as such, and adds them to the given set of closed
+// channels. This catches the channels whose close we didn't learn about from a
+// channel event, because they were closed while lnd was down or before the
+// client deleted sessions at all.
+//
+// NOTE: The cleanup mutex MUST be held.
+func (c *TowerClient) reconcileClosedChannels(
+	closedChans map[lnwire.ChannelID]struct{}) error {
+
+	if c.cfg.FetchClosedChannels == nil {
+		return nil
+	}
+
+	summaries, err := c.cfg.FetchClosedChannels(false)
+	if err != nil {
+		return err
+	}
+
+	for _, summary := range summaries {
+		// A pending close can still be replaced by a revoked
+		// commitment, so we keep its sessions until it confirmed.
+		if summary.IsPending ||
+			summary.CloseType != channeldb.CooperativeClose {
+
+			continue
+		}
+
+		chanID := lnwire.NewChanIDFromOutPoint(&summary.ChanPoint)
+		if _, ok := closedChans[chanID]; ok {
+			continue
+		}
+
+		c.backupMu.Lock()
+		_, ok := c.summaries[chanID]
+		c.backupMu.Unlock()
+		if !ok {
+			continue
+		}
+
+		if err := c.cfg.DB.MarkChannelClosed(chanID); err != nil {
+			return err
+		}
+		closedChans[chanID] = struct{}{}
+
+		c.log.Debugf("Marked channel %v closed while it was closed "+
+			"without a channel event", chanID)
+	}
+
+	return nil
+}
+
+// deleteSession requests the session's tower to delete the session, and then
+// removes the session from the database.
+func (c *TowerClient) deleteSession(s *wtdb.ClientSession) error {
+	tower, err := c.cfg.DB.LoadTowerByID(s.TowerID)
+	if err != nil {
+		return err
+	}
+
+	// The tower authenticates the request by the session key, so we'll
+	// need to rederive it to connect.
+	keyDesc, err := c.cfg.SecretKeyRing.DeriveKey(keychain.KeyLocator{
+		Family: keychain.KeyFamilyTowerSession,
+		Index:  s.KeyIndex,
+	})
+	if err != nil {
+		return err
+	}
+	sessionKey := keychain.NewPubKeyECDH(keyDesc, c.cfg.SecretKeyRing)
+
+	addrs := c.addrBook.get(tower.ID, tower.Addresses)
+	dial := func(addr *lnwire.NetAddress) (wtserver.Peer, error) {
+		return c.dial(sessionKey, addr)
+	}
+	conn, _, err := dialAny(dial, tower.IdentityKey, addrs)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := c.sendDeleteSession(conn); err != nil {
+		return err
+	}
+
+	return c.cfg.DB.DeleteSession(s.ID)
+}
+
+// sendDeleteSession exchanges Init messages with the tower over the given
+// connection, and then requests it to delete the session the connection was
+// authenticated with.
+func (c *TowerClient) sendDeleteSession(conn wtserver.Peer) error {
+	localInit := wtwire.NewInitMessage(
+		lnwire.NewRawFeatureVector(wtwire.AltruistSessionsRequired),
+		c.cfg.ChainHash,
+	)
+	if err := c.sendMessage(conn, localInit); err != nil {
+		return err
+	}
+
+	remoteMsg, err := c.readMessage(conn)
+	if err != nil {
+		return err
+	}
+	remoteInit, ok := remoteMsg.(*wtwire.Init)
+	if !ok {
+		return fmt.Errorf("watchtower responded with %T to Init",
+			remoteMsg)
+	}
+	_, err = localInit.Negotiate(remoteInit, wtwire.FeatureNames)
+	if err != nil {
+		return err
+	}
+
+	if err := c.sendMessage(conn, &wtwire.DeleteSession{}); err != nil {
+		return err
+	}
+
+	remoteMsg, err = c.readMessage(conn)
+	if err != nil {
+		return err
+	}
+	reply, ok := remoteMsg.(*wtwire.DeleteSessionReply)
+	if !ok {
+		return fmt.Errorf("watchtower responded with %T to "+
+			"DeleteSession", remoteMsg)
+	}
+
+	// A tower that doesn't know the session anymore already deleted it.
+	switch reply.Code {
+	case wtwire.CodeOK, wtwire.DeleteSessionCodeNotFound:
+		return nil
+
+	default:
+		return fmt.Errorf("received error code %v in "+
+			"DeleteSessionReply", reply.Code)
+	}
+}
+
+// handleClosedChannels marks the registered channels that are cooperatively
+// closed as such, and deletes the sessions that are no longer needed as a
+// result.
+//
+// NOTE: This method MUST be run as a goroutine.
+func (c *TowerClient) handleClosedChannels(sub subscribe.Subscription) {
+	defer c.wg.Done()
+	defer sub.Cancel()
+
+	// Channels might have been closed while we weren't running, so we
+	// delete the sessions that are no longer needed because of them
+	// right away.
+	if _, err := c.Cleanup(); err != nil {
+		c.log.Errorf("Unable to clean up sessions: %v", err)
+	}
+
+	for {
+		select {
+		case update, ok := <-sub.Updates():
+			if !ok {
+				return
+			}
+
+			event, ok := update.(channelnotifier.ClosedChannelEvent)
+			if !ok {
+				continue
+			}
+
+			summary := event.CloseSummary
+			if summary.CloseType != channeldb.CooperativeClose {
+				continue
+			}
+
+			c.handleClosedChannel(
+				lnwire.NewChanIDFromOutPoint(&summary.ChanPoint),
+			)
+
+		case <-c.pipeline.quit:
+			return
+
+		case <-c.forceQuit:
+			return
+		}
+	}
+}
+
+// handleClosedChannel marks the given channel as closed if it was registered
+// with the client, and deletes the sessions that are no longer needed.
+func (c *TowerClient) handleClosedChannel(chanID lnwire.ChannelID) {
+	c.backupMu.Lock()
+	_, ok := c.summaries[chanID]
+	c.backupMu.Unlock()
+	if !ok {
+		return
+	}
+
+	if err := c.cfg.DB.MarkChannelClosed(chanID); err != nil {
+		c.log.Errorf("Unable to mark channel %v closed: %v", chanID,
+			err)
+		return
+	}
+
+	if _, err := c.Cleanup(); err != nil {
+		c.log.Errorf("Unable to clean up sessions: %v", err)
+	}
+}
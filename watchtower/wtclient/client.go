@@ -17,6 +17,7 @@ import (
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/subscribe"
 	"github.com/lightningnetwork/lnd/tor"
 	"github.com/lightningnetwork/lnd/watchtower/wtdb"
 	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
@@ -63,6 +64,14 @@ type RegisteredTower struct {
 	// ActiveSessionCandidate determines whether the watchtower is currently
 	// being considered for new sessions.
 	ActiveSessionCandidate bool
+
+	// BackoffState is the current connection backoff state of the
+	// watchtower.
+	BackoffState TowerBackoffState
+
+	// Health summarizes how reliably the watchtower could be reached
+	// since the client started.
+	Health TowerHealth
 }
 
 // Client is the primary interface used by the daemon to control a client's
@@ -74,6 +83,13 @@ type Client interface {
 	// session negotiations and backups.
 	AddTower(*lnwire.NetAddress) error
 
+	// SetAnnouncedTowerAddrs replaces the addresses learned from the node
+	// announcement of a watchtower that is currently considered for new
+	// sessions. They're dialed along with the watchtower's addresses, but
+	// aren't persisted. Addresses of unknown or removed watchtowers are
+	// ignored.
+	SetAnnouncedTowerAddrs(*btcec.PublicKey, []net.Addr) error
+
 	// RemoveTower removes a watchtower from being considered for future
 	// session negotiations and from being used for any subsequent backups
 	// until it's added again. If an address is provided, then this call
@@ -88,6 +104,18 @@ type Client interface {
 	// LookupTower retrieves a registered watchtower through its public key.
 	LookupTower(*btcec.PublicKey) (*RegisteredTower, error)
 
+	// AuditChannel returns the revoked states of the given channel that
+	// were backed up to a watchtower, and the gaps in the coverage of the
+	// inclusive range of commit heights [startHeight, endHeight].
+	AuditChannel(chanID lnwire.ChannelID, startHeight,
+		endHeight uint64) (*BackupAudit, error)
+
+	// Cleanup deletes all sessions of the client that are no longer needed
+	// because all channels they backed up states of were cooperatively
+	// closed, both at their towers and from the database. The number of
+	// deleted sessions is returned.
+	Cleanup() (int, error)
+
 	// Stats returns the in-memory statistics of the client since startup.
 	Stats() ClientStats
 
@@ -136,6 +164,16 @@ type Config struct {
 	// NewAddress generates a new on-chain sweep pkscript.
 	NewAddress func() ([]byte, error)
 
+	// SweepPkScript, if set, is the pkscript that justice transactions pay
+	// into instead of one generated by NewAddress, for example the script
+	// of a cold address that remains safe if the node is compromised.
+	// Channels that were registered with another pkscript are moved to it
+	// on startup, so all states backed up from then on sweep into it. The
+	// pkscript is persisted the first time it is set. Afterwards, a
+	// different pkscript is rejected and unsetting it keeps using the
+	// persisted one.
+	SweepPkScript []byte
+
 	// SecretKeyRing is used to derive the session keys used to communicate
 	// with the tower. The client only stores the KeyLocators internally so
 	// that we never store private keys on disk.
@@ -179,15 +217,34 @@ type Config struct {
 	// to zero, the default will be used instead.
 	WriteTimeout time.Duration
 
-	// MinBackoff defines the initial backoff applied to connections with
-	// watchtowers. Subsequent backoff durations will grow exponentially up
-	// until MaxBackoff.
-	MinBackoff time.Duration
-
-	// MaxBackoff defines the maximum backoff applied to connections with
-	// watchtowers. If the exponential backoff produces a timeout greater
-	// than this value, the backoff will be clamped to MaxBackoff.
-	MaxBackoff time.Duration
+	// Backoff is the policy applied when retrying failed connection
+	// attempts to watchtowers. Any unset durations or multiplier are
+	// replaced by their defaults.
+	Backoff BackoffPolicy
+
+	// TowerSelector, if set, decides which tower new sessions are
+	// negotiated with. Otherwise towers are tried in the order they were
+	// added.
+	TowerSelector TowerSelector
+
+	// SessionCounter counts the active sessions held with each tower. It
+	// should be shared by all clients using the same towers, such that the
+	// TowerSelector's MaxSessionsPerTower applies to the sessions of all of
+	// them. If nil, the client counts its own sessions only.
+	SessionCounter *SessionCounter
+
+	// SubscribeChannelEvents, if set, provides a subscription to channel
+	// events, through which the client learns about cooperatively closed
+	// channels so it can delete the sessions that are no longer needed.
+	// Otherwise, sessions are only deleted when Cleanup is called.
+	SubscribeChannelEvents func() (subscribe.Subscription, error)
+
+	// FetchClosedChannels, if set, returns the close summaries of all
+	// closed channels. Cleanup uses them to learn about the channels that
+	// were cooperatively closed while no channel events were received, for
+	// example while lnd was down.
+	FetchClosedChannels func(pendingOnly bool) (
+		[]*channeldb.ChannelCloseSummary, error)
 }
 
 // newTowerMsg is an internal message we'll use within the TowerClient to signal
@@ -242,6 +299,12 @@ type TowerClient struct {
 	candidateSessions map[wtdb.SessionID]*wtdb.ClientSession
 	activeSessions    sessionQueueSet
 
+	// towerBackoffs tracks the connection backoff state of each tower.
+	towerBackoffs *towerBackoffSet
+
+	// addrBook tracks the current addresses of each tower.
+	addrBook *towerAddrBook
+
 	sessionQueue *sessionQueue
 	prevTask     *backupTask
 
@@ -249,6 +312,9 @@ type TowerClient struct {
 	summaries         wtdb.ChannelSummaries
 	chanCommitHeights map[lnwire.ChannelID]uint64
 
+	// cleanupMu ensures that only one cleanup deletes sessions at a time.
+	cleanupMu sync.Mutex
+
 	statTicker *time.Ticker
 	stats      *ClientStats
 
@@ -281,6 +347,24 @@ func New(config *Config) (*TowerClient, error) {
 		cfg.WriteTimeout = DefaultWriteTimeout
 	}
 
+	// Fill in the defaults of any unset backoff parameters before making
+	// sure the resulting policy is sane.
+	if cfg.Backoff.MinBackoff <= 0 {
+		cfg.Backoff.MinBackoff = DefaultMinBackoff
+	}
+	if cfg.Backoff.MaxBackoff <= 0 {
+		cfg.Backoff.MaxBackoff = DefaultMaxBackoff
+	}
+	if cfg.Backoff.Multiplier == 0 {
+		cfg.Backoff.Multiplier = DefaultBackoffMultiplier
+	}
+	if cfg.Backoff.CircuitBreakerCooldown <= 0 {
+		cfg.Backoff.CircuitBreakerCooldown = DefaultCircuitBreakerCooldown
+	}
+	if err := cfg.Backoff.Validate(); err != nil {
+		return nil, err
+	}
+
 	prefix := "(legacy)"
 	if cfg.Policy.IsAnchorChannel() {
 		prefix = "(anchor)"
@@ -300,11 +384,29 @@ func New(config *Config) (*TowerClient, error) {
 		return nil, err
 	}
 
+	if cfg.SessionCounter == nil {
+		cfg.SessionCounter = NewSessionCounter()
+	}
+
+	towerBackoffs := newTowerBackoffSet(&cfg.Backoff, cfg.SessionCounter)
+	addrBook := newTowerAddrBook()
+
 	var candidateTowers []*wtdb.Tower
 	for _, s := range candidateSessions {
 		plog.Infof("Using private watchtower %s, offering policy %s",
 			s.Tower, cfg.Policy)
 		candidateTowers = append(candidateTowers, s.Tower)
+		cfg.SessionCounter.add(s)
+		addrBook.update(s.Tower)
+	}
+
+	var towerIterator TowerCandidateIterator
+	if cfg.TowerSelector != nil {
+		towerIterator = newTowerSelectorIterator(
+			cfg.TowerSelector, towerBackoffs, candidateTowers...,
+		)
+	} else {
+		towerIterator = newTowerListIterator(candidateTowers...)
 	}
 
 	// Load the sweep pkscripts that have been generated for all previously
@@ -314,13 +416,37 @@ func New(config *Config) (*TowerClient, error) {
 		return nil, err
 	}
 
+	// Persist the configured sweep pkscript, or load the one persisted
+	// earlier. Once persisted, it can't be changed or removed, so changing
+	// the configuration of a compromised node doesn't redirect the funds
+	// of future justice transactions.
+	sweepPkScript, err := cfg.DB.RatchetSweepPkScript(cfg.SweepPkScript)
+	if err != nil {
+		return nil, fmt.Errorf("unable to persist sweep pkscript: %w",
+			err)
+	}
+	cfg.SweepPkScript = sweepPkScript
+
+	// If there is a sweep pkscript, move all channels that sweep elsewhere
+	// to it.
+	if len(cfg.SweepPkScript) > 0 {
+		err := ratchetSweepPkScripts(
+			cfg.DB, chanSummaries, cfg.SweepPkScript,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	c := &TowerClient{
 		cfg:               cfg,
 		log:               plog,
 		pipeline:          newTaskPipeline(plog),
-		candidateTowers:   newTowerListIterator(candidateTowers...),
+		candidateTowers:   towerIterator,
 		candidateSessions: candidateSessions,
 		activeSessions:    make(sessionQueueSet),
+		towerBackoffs:     towerBackoffs,
+		addrBook:          addrBook,
 		summaries:         chanSummaries,
 		statTicker:        time.NewTicker(DefaultStatInterval),
 		stats:             new(ClientStats),
@@ -337,8 +463,9 @@ func New(config *Config) (*TowerClient, error) {
 		ReadMessage:   c.readMessage,
 		Dial:          c.dial,
 		Candidates:    c.candidateTowers,
-		MinBackoff:    cfg.MinBackoff,
-		MaxBackoff:    cfg.MaxBackoff,
+		Backoff:       &cfg.Backoff,
+		TowerBackoffs: c.towerBackoffs,
+		AddrBook:      c.addrBook,
 		Log:           plog,
 	})
 
@@ -469,6 +596,20 @@ func (c *TowerClient) Start() error {
 		c.wg.Add(1)
 		go c.backupDispatcher()
 
+		// If we can learn about closed channels, we'll delete the
+		// sessions that are no longer needed as soon as their channels
+		// are closed.
+		if c.cfg.SubscribeChannelEvents != nil {
+			var sub subscribe.Subscription
+			sub, err = c.cfg.SubscribeChannelEvents()
+			if err != nil {
+				return
+			}
+
+			c.wg.Add(1)
+			go c.handleClosedChannels(sub)
+		}
+
 		c.log.Infof("Watchtower client started successfully")
 	})
 	return err
@@ -578,16 +719,20 @@ func (c *TowerClient) RegisterChannel(chanID lnwire.ChannelID) error {
 		return nil
 	}
 
-	// Otherwise, generate a new sweep pkscript used to sweep funds for this
-	// channel.
-	pkScript, err := c.cfg.NewAddress()
-	if err != nil {
-		return err
+	// Otherwise, use the configured sweep pkscript or generate a new one
+	// used to sweep funds for this channel.
+	pkScript := c.cfg.SweepPkScript
+	if len(pkScript) == 0 {
+		var err error
+		pkScript, err = c.cfg.NewAddress()
+		if err != nil {
+			return err
+		}
 	}
 
 	// Persist the sweep pkscript so that restarts will not introduce
 	// address inflation when the channel is reregistered after a restart.
-	err = c.cfg.DB.RegisterChannel(chanID, pkScript)
+	err := c.cfg.DB.RegisterChannel(chanID, pkScript)
 	if err != nil {
 		return err
 	}
@@ -601,6 +746,34 @@ func (c *TowerClient) RegisterChannel(chanID lnwire.ChannelID) error {
 	return nil
 }
 
+// ratchetSweepPkScripts replaces the sweep pkscript of all registered channels
+// that don't already sweep into the given pkscript, both in the database and in
+// the passed summaries.
+func ratchetSweepPkScripts(db DB, summaries wtdb.ChannelSummaries,
+	sweepPkScript []byte) error {
+
+	for chanID, summary := range summaries {
+		if bytes.Equal(summary.SweepPkScript, sweepPkScript) {
+			continue
+		}
+
+		err := db.UpdateChannelSweepPkScript(chanID, sweepPkScript)
+		if err != nil {
+			return fmt.Errorf("unable to update sweep pkscript of "+
+				"channel %v: %v", chanID, err)
+		}
+
+		log.Infof("Justice transactions of channel %v now sweep into "+
+			"the configured sweep address", chanID)
+
+		summaries[chanID] = wtdb.ClientChanSummary{
+			SweepPkScript: sweepPkScript,
+		}
+	}
+
+	return nil
+}
+
 // BackupState initiates a request to back up a particular revoked state. If the
 // method returns nil, the backup is guaranteed to be successful unless the:
 //   - client is force quit,
@@ -709,6 +882,7 @@ func (c *TowerClient) backupDispatcher() {
 				c.log.Infof("Acquired new session with id=%s",
 					session.ID)
 				c.candidateSessions[session.ID] = session
+				c.cfg.SessionCounter.add(session)
 				c.stats.sessionAcquired()
 
 				// We'll continue to choose the newly negotiated
@@ -780,6 +954,7 @@ func (c *TowerClient) backupDispatcher() {
 				c.log.Warnf("Acquired new session with id=%s "+
 					"while processing tasks", session.ID)
 				c.candidateSessions[session.ID] = session
+				c.cfg.SessionCounter.add(session)
 				c.stats.sessionAcquired()
 
 			case <-c.statTicker.C:
@@ -856,7 +1031,7 @@ func (c *TowerClient) taskAccepted(task *backupTask, newStatus reserveStatus) {
 	// The sessionQueue is full after accepting this task, so we will need
 	// to request a new one before proceeding.
 	case reserveExhausted:
-		c.stats.sessionExhausted()
+		c.sessionExhausted()
 
 		c.log.Debugf("Session %s exhausted", c.sessionQueue.ID())
 
@@ -905,7 +1080,7 @@ func (c *TowerClient) taskRejected(task *backupTask, curStatus reserveStatus) {
 	// The sessionQueue rejected the task because it is full, we will stash
 	// this task and try to add it to the next available sessionQueue.
 	case reserveExhausted:
-		c.stats.sessionExhausted()
+		c.sessionExhausted()
 
 		c.log.Debugf("Session %v exhausted, %v queued for next session",
 			c.sessionQueue.ID(), task.id)
@@ -1005,8 +1180,8 @@ func (c *TowerClient) newSessionQueue(s *wtdb.ClientSession) *sessionQueue {
 		SendMessage:   c.sendMessage,
 		Signer:        c.cfg.Signer,
 		DB:            c.cfg.DB,
-		MinBackoff:    c.cfg.MinBackoff,
-		MaxBackoff:    c.cfg.MaxBackoff,
+		TowerBackoff:  c.towerBackoffs.get(s.TowerID),
+		AddrBook:      c.addrBook,
 		Log:           c.log,
 	})
 }
@@ -1070,6 +1245,33 @@ func (c *TowerClient) AddTower(addr *lnwire.NetAddress) error {
 	}
 }
 
+// SetAnnouncedTowerAddrs replaces the addresses learned from the node
+// announcement of a watchtower that is currently considered for new sessions.
+// They're dialed along with the watchtower's addresses, but aren't persisted.
+// Addresses of unknown or removed watchtowers are ignored.
+func (c *TowerClient) SetAnnouncedTowerAddrs(pubKey *btcec.PublicKey,
+	addrs []net.Addr) error {
+
+	tower, err := c.cfg.DB.LoadTower(pubKey)
+	switch {
+	case err == wtdb.ErrTowerNotFound:
+		return nil
+
+	case err != nil:
+		return err
+
+	case !c.candidateTowers.IsActive(tower.ID):
+		return nil
+	}
+
+	c.log.Debugf("Setting announced addresses of tower %x to %v",
+		pubKey.SerializeCompressed(), addrs)
+
+	c.addrBook.setAnnounced(tower.ID, addrs)
+
+	return nil
+}
+
 // handleNewTower handles a request for a new tower to be added. If the tower
 // already exists, then its corresponding sessions, if any, will be set
 // considered as candidates.
@@ -1082,6 +1284,7 @@ func (c *TowerClient) handleNewTower(msg *newTowerMsg) error {
 		return err
 	}
 	c.candidateTowers.AddCandidate(tower)
+	c.addrBook.update(tower)
 
 	// Include all of its corresponding sessions to our set of candidates.
 	isAnchorClient := c.cfg.Policy.IsAnchorChannel()
@@ -1095,6 +1298,7 @@ func (c *TowerClient) handleNewTower(msg *newTowerMsg) error {
 	}
 	for id, session := range sessions {
 		c.candidateSessions[id] = session
+		c.cfg.SessionCounter.add(session)
 	}
 
 	return nil
@@ -1129,6 +1333,16 @@ func (c *TowerClient) RemoveTower(pubKey *btcec.PublicKey, addr net.Addr) error
 	}
 }
 
+// sessionExhausted records that all updates of the active session queue have
+// been used, so the session no longer counts towards the session quota of its
+// tower.
+func (c *TowerClient) sessionExhausted() {
+	c.stats.sessionExhausted()
+
+	session := c.sessionQueue.cfg.ClientSession
+	c.cfg.SessionCounter.remove(session.TowerID, session.ID)
+}
+
 // handleNewTower handles a request for an existing tower to be removed. If none
 // of the tower's sessions have pending updates, then they will become inactive
 // and removed as candidates. If the active session queue corresponds to any of
@@ -1152,10 +1366,16 @@ func (c *TowerClient) handleStaleTower(msg *staleTowerMsg) error {
 	}
 
 	// If an address was provided, then we're only meant to remove the
-	// address from the tower, so there's nothing left for us to do.
+	// address from the tower, so there's nothing left for us to do once
+	// it's no longer dialed.
 	if msg.addr != nil {
+		tower.RemoveAddress(msg.addr)
+		c.addrBook.update(tower)
+		c.addrBook.removeAnnounced(tower.ID, msg.addr)
+
 		return nil
 	}
+	c.addrBook.removeAnnounced(tower.ID, nil)
 
 	// Otherwise, the tower should no longer be used for future session
 	// negotiations and backups.
@@ -1167,6 +1387,7 @@ func (c *TowerClient) handleStaleTower(msg *staleTowerMsg) error {
 	}
 	for sessionID := range sessions {
 		delete(c.candidateSessions, sessionID)
+		c.cfg.SessionCounter.remove(tower.ID, sessionID)
 	}
 
 	// If our active session queue corresponds to the stale tower, we'll
@@ -1215,6 +1436,8 @@ func (c *TowerClient) RegisteredTowers() ([]*RegisteredTower, error) {
 			Tower:                  tower,
 			Sessions:               towerSessions[tower.ID],
 			ActiveSessionCandidate: isActive,
+			BackoffState:           c.towerBackoffs.state(tower.ID),
+			Health:                 c.towerBackoffs.health(tower.ID),
 		})
 	}
 
@@ -1237,6 +1460,8 @@ func (c *TowerClient) LookupTower(pubKey *btcec.PublicKey) (*RegisteredTower, er
 		Tower:                  tower,
 		Sessions:               towerSessions,
 		ActiveSessionCandidate: c.candidateTowers.IsActive(tower.ID),
+		BackoffState:           c.towerBackoffs.state(tower.ID),
+		Health:                 c.towerBackoffs.health(tower.ID),
 	}, nil
 }
 
@@ -1,7 +1,9 @@
 package wtclient_test
 
 import (
+	"bytes"
 	"encoding/binary"
+	"fmt"
 	"net"
 	"sync"
 	"testing"
@@ -16,6 +18,7 @@ import (
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lntest/wait"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/tor"
@@ -78,11 +81,13 @@ func randPrivKey(t *testing.T) *btcec.PrivateKey {
 type mockNet struct {
 	mu           sync.RWMutex
 	connCallback func(wtserver.Peer)
+	unreachable  map[string]struct{}
 }
 
 func newMockNet(cb func(wtserver.Peer)) *mockNet {
 	return &mockNet{
 		connCallback: cb,
+		unreachable:  make(map[string]struct{}),
 	}
 }
 
@@ -108,6 +113,14 @@ func (m *mockNet) AuthDial(local keychain.SingleKeyECDH,
 	netAddr *lnwire.NetAddress,
 	dialer tor.DialFunc) (wtserver.Peer, error) {
 
+	m.mu.RLock()
+	_, unreachable := m.unreachable[netAddr.Address.String()]
+	m.mu.RUnlock()
+	if unreachable {
+		return nil, fmt.Errorf("address %v unreachable",
+			netAddr.Address)
+	}
+
 	localPk := local.PubKey()
 	localAddr := &net.TCPAddr{
 		IP:   net.IP{0x32, 0x31, 0x30, 0x29},
@@ -125,6 +138,13 @@ func (m *mockNet) AuthDial(local keychain.SingleKeyECDH,
 	return localPeer, nil
 }
 
+// setUnreachable makes all following dials to the given address fail.
+func (m *mockNet) setUnreachable(addr net.Addr) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.unreachable[addr.String()] = struct{}{}
+}
+
 func (m *mockNet) setConnCallback(cb func(wtserver.Peer)) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -437,10 +457,12 @@ func newHarness(t *testing.T, cfg harnessCfg) *testHarness {
 		NewAddress: func() ([]byte, error) {
 			return addrScript, nil
 		},
-		ReadTimeout:    timeout,
-		WriteTimeout:   timeout,
-		MinBackoff:     time.Millisecond,
-		MaxBackoff:     time.Second,
+		ReadTimeout:  timeout,
+		WriteTimeout: timeout,
+		Backoff: wtclient.BackoffPolicy{
+			MinBackoff: time.Millisecond,
+			MaxBackoff: time.Second,
+		},
 		ForceQuitDelay: 10 * time.Second,
 	}
 	client, err := wtclient.New(clientCfg)
@@ -1462,6 +1484,332 @@ var clientTests = []clientTest{
 			h.waitServerUpdates(hints[numUpdates/2:], 5*time.Second)
 		},
 	},
+	{
+		// Asserts that addresses added to a tower after its sessions
+		// were created are picked up by the session queues, which
+		// dial all addresses of the tower in parallel.
+		name: "add tower address",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				TxPolicy: wtpolicy.TxPolicy{
+					BlobType:     blob.TypeAltruistCommit,
+					SweepFeeRate: wtpolicy.DefaultSweepFeeRate,
+				},
+				MaxUpdates: 5,
+			},
+		},
+		fn: func(h *testHarness) {
+			const (
+				chanID     = 0
+				numUpdates = 4
+			)
+
+			// Back up the first two states through the tower's
+			// original address.
+			hints := h.advanceChannelN(chanID, numUpdates)
+			h.backupStates(chanID, 0, numUpdates/2, nil)
+			h.waitServerUpdates(hints[:numUpdates/2], 5*time.Second)
+
+			// Make the original address unreachable. The
+			// remaining states can't be delivered anymore.
+			h.net.setUnreachable(h.serverAddr.Address)
+			h.backupStates(chanID, numUpdates/2, numUpdates, nil)
+			h.waitServerUpdates(hints[:numUpdates/2], time.Second)
+
+			// Addresses of unknown towers are ignored.
+			privKey, err := btcec.NewPrivateKey()
+			require.NoError(h.t, err)
+			newAddr := &net.TCPAddr{
+				IP: net.IPv4(18, 28, 243, 3), Port: 9911,
+			}
+			err = h.client.SetAnnouncedTowerAddrs(
+				privKey.PubKey(), []net.Addr{newAddr},
+			)
+			require.NoError(h.t, err)
+			_, err = h.clientDB.LoadTower(privKey.PubKey())
+			require.ErrorIs(h.t, err, wtdb.ErrTowerNotFound)
+
+			// Once a reachable address is announced by the
+			// tower, the remaining states are delivered through
+			// it.
+			err = h.client.SetAnnouncedTowerAddrs(
+				h.serverAddr.IdentityKey, []net.Addr{newAddr},
+			)
+			require.NoError(h.t, err)
+			h.waitServerUpdates(hints, 5*time.Second)
+
+			// The announced address is kept apart from the
+			// tower's persisted addresses.
+			tower, err := h.clientDB.LoadTower(
+				h.serverAddr.IdentityKey,
+			)
+			require.NoError(h.t, err)
+			require.Equal(
+				h.t, []net.Addr{h.serverAddr.Address},
+				tower.Addresses,
+			)
+		},
+	},
+	{
+		// Asserts that the client reports which states of a channel
+		// were backed up to which tower, and the gaps in between.
+		name: "audit channel backups",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				TxPolicy: wtpolicy.TxPolicy{
+					BlobType:     blob.TypeAltruistCommit,
+					SweepFeeRate: wtpolicy.DefaultSweepFeeRate,
+				},
+				MaxUpdates: 10,
+			},
+		},
+		fn: func(h *testHarness) {
+			const (
+				chanID     = 0
+				numUpdates = 6
+			)
+
+			// Back up all states but the fourth and the last.
+			hints := h.advanceChannelN(chanID, numUpdates)
+			h.backupStates(chanID, 0, 3, nil)
+			h.backupState(chanID, 4, nil)
+			h.waitServerUpdates(
+				append(hints[:3:3], hints[4]), time.Second,
+			)
+
+			// Wait for the client to process the acks of the
+			// tower, after which the audit should report the
+			// missing states as gaps.
+			var audit *wtclient.BackupAudit
+			err := wait.Predicate(func() bool {
+				var err error
+				audit, err = h.client.AuditChannel(
+					chanIDFromInt(chanID), 0, numUpdates-1,
+				)
+				require.NoError(h.t, err)
+
+				return len(audit.Backups) == 4
+			}, time.Second)
+			require.NoError(h.t, err)
+
+			for i, height := range []uint64{0, 1, 2, 4} {
+				backup := audit.Backups[i]
+				require.Equal(h.t, height, backup.CommitHeight)
+				require.True(h.t, backup.Tower.IdentityKey.IsEqual(
+					h.serverAddr.IdentityKey,
+				))
+			}
+			require.Equal(h.t, []wtclient.HeightRange{
+				{Start: 3, End: 3},
+				{Start: 5, End: 5},
+			}, audit.Gaps)
+		},
+	},
+	{
+		// Asserts that an exhausted session whose channels were all
+		// cooperatively closed is deleted at the tower and from the
+		// client's database.
+		name: "cleanup sessions of closed channels",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				TxPolicy: wtpolicy.TxPolicy{
+					BlobType:     blob.TypeAltruistCommit,
+					SweepFeeRate: wtpolicy.DefaultSweepFeeRate,
+				},
+				MaxUpdates: 5,
+			},
+		},
+		fn: func(h *testHarness) {
+			const (
+				chanID     = 0
+				numUpdates = 5
+			)
+
+			// Exhaust the session negotiated for the channel.
+			hints := h.advanceChannelN(chanID, numUpdates)
+			h.backupStates(chanID, 0, numUpdates, nil)
+			h.waitServerUpdates(hints, time.Second)
+
+			// Wait for the client to process all acks, after
+			// which the session can no longer be used.
+			var sessionID wtdb.SessionID
+			err := wait.Predicate(func() bool {
+				sessions, err := h.clientDB.ListClientSessions(
+					nil,
+				)
+				require.NoError(h.t, err)
+
+				for id, s := range sessions {
+					if len(s.AckedUpdates) == numUpdates {
+						sessionID = id
+						return true
+					}
+				}
+
+				return false
+			}, time.Second)
+			require.NoError(h.t, err)
+
+			// As long as the channel is open, the session must be
+			// kept.
+			numDeleted, err := h.client.Cleanup()
+			require.NoError(h.t, err)
+			require.Zero(h.t, numDeleted)
+
+			err = h.clientDB.MarkChannelClosed(
+				chanIDFromInt(chanID),
+			)
+			require.NoError(h.t, err)
+
+			numDeleted, err = h.client.Cleanup()
+			require.NoError(h.t, err)
+			require.Equal(h.t, 1, numDeleted)
+
+			sessions, err := h.clientDB.ListClientSessions(nil)
+			require.NoError(h.t, err)
+			require.NotContains(h.t, sessions, sessionID)
+
+			_, err = h.serverDB.GetSessionInfo(&sessionID)
+			require.ErrorIs(h.t, err, wtdb.ErrSessionNotFound)
+		},
+	},
+	{
+		// Asserts that a session whose channel was cooperatively closed
+		// while the client was down is deleted by the next cleanup, as
+		// the close is learned from the channel's close summary.
+		name: "cleanup sessions of channels closed while down",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				TxPolicy: wtpolicy.TxPolicy{
+					BlobType:     blob.TypeAltruistCommit,
+					SweepFeeRate: wtpolicy.DefaultSweepFeeRate,
+				},
+				MaxUpdates: 5,
+			},
+		},
+		fn: func(h *testHarness) {
+			const (
+				chanID     = 0
+				numUpdates = 5
+			)
+
+			// Exhaust the session negotiated for the channel, and
+			// wait for the client to process all acks.
+			hints := h.advanceChannelN(chanID, numUpdates)
+			h.backupStates(chanID, 0, numUpdates, nil)
+			h.waitServerUpdates(hints, time.Second)
+
+			err := wait.Predicate(func() bool {
+				sessions, err := h.clientDB.ListClientSessions(
+					nil,
+				)
+				require.NoError(h.t, err)
+
+				for _, s := range sessions {
+					if len(s.AckedUpdates) == numUpdates {
+						return true
+					}
+				}
+
+				return false
+			}, time.Second)
+			require.NoError(h.t, err)
+
+			// The channel is closed while the client is down, so
+			// it doesn't receive a channel event for the close.
+			// With the index 0, the channel ID of the channel
+			// point is the txid.
+			h.client.Stop()
+
+			closedChanID := chanIDFromInt(chanID)
+			closeSummaries := []*channeldb.ChannelCloseSummary{{
+				ChanPoint: wire.OutPoint{
+					Hash: chainhash.Hash(closedChanID),
+				},
+				CloseType: channeldb.CooperativeClose,
+			}}
+			h.clientCfg.FetchClosedChannels = func(bool) (
+				[]*channeldb.ChannelCloseSummary, error) {
+
+				return closeSummaries, nil
+			}
+			h.startClient()
+
+			numDeleted, err := h.client.Cleanup()
+			require.NoError(h.t, err)
+			require.Equal(h.t, 1, numDeleted)
+
+			closedChans, err := h.clientDB.FetchClosedChannels()
+			require.NoError(h.t, err)
+			require.Contains(h.t, closedChans, closedChanID)
+		},
+	},
+	{
+		// Asserts that a configured sweep pkscript is persisted and
+		// used for all channels, that it stays in use once it is
+		// unset, and that a different one is rejected.
+		name: "ratchet sweep pkscript",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				TxPolicy: wtpolicy.TxPolicy{
+					BlobType:     blob.TypeAltruistCommit,
+					SweepFeeRate: wtpolicy.DefaultSweepFeeRate,
+				},
+				MaxUpdates: 5,
+			},
+		},
+		fn: func(h *testHarness) {
+			sweepPkScript := func(id uint64) []byte {
+				summaries, err := h.clientDB.FetchChanSummaries()
+				require.NoError(h.t, err)
+
+				return summaries[chanIDFromInt(id)].SweepPkScript
+			}
+
+			// The first channel was registered with an address of
+			// the wallet.
+			require.Equal(h.t, addrScript, sweepPkScript(0))
+
+			// Restart the client with a cold P2WSH sweep pkscript,
+			// which moves the channel to it.
+			coldPkScript := append(
+				[]byte{txscript.OP_0, txscript.OP_DATA_32},
+				bytes.Repeat([]byte{0x01}, 32)...,
+			)
+			require.NoError(h.t, h.client.Stop())
+			h.clientCfg.SweepPkScript = coldPkScript
+			h.startClient()
+			require.Equal(h.t, coldPkScript, sweepPkScript(0))
+
+			// Unsetting the sweep pkscript keeps using the
+			// persisted one, also for new channels.
+			require.NoError(h.t, h.client.Stop())
+			h.clientCfg.SweepPkScript = nil
+			h.startClient()
+
+			h.makeChannel(1, localBalance, remoteBalance)
+			h.registerChannel(1)
+			require.Equal(h.t, coldPkScript, sweepPkScript(0))
+			require.Equal(h.t, coldPkScript, sweepPkScript(1))
+
+			// Finally, a client with a different sweep pkscript
+			// can't be created.
+			cfg := *h.clientCfg
+			cfg.SweepPkScript = addrScript
+			_, err := wtclient.New(&cfg)
+			require.ErrorIs(h.t, err, wtdb.ErrSweepPkScriptMismatch)
+		},
+	},
 	{
 		// Asserts that the client's force quite delay will properly
 		// shutdown the client if it is unable to completely drain the
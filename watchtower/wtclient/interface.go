@@ -76,6 +76,35 @@ type DB interface {
 	// the client's active policy.
 	RegisterChannel(lnwire.ChannelID, []byte) error
 
+	// UpdateChannelSweepPkScript replaces the sweep pkscript of a
+	// registered channel. States that are backed up afterwards sweep into
+	// the new pkscript.
+	UpdateChannelSweepPkScript(lnwire.ChannelID, []byte) error
+
+	// RatchetSweepPkScript persists the given sweep pkscript, unless one
+	// was persisted before, and returns the persisted sweep pkscript. A
+	// persisted sweep pkscript can't be changed or removed.
+	RatchetSweepPkScript([]byte) ([]byte, error)
+
+	// FetchChanBackups returns a record for every revoked state of the
+	// given channel that was acknowledged by a tower, sorted by commit
+	// height.
+	FetchChanBackups(lnwire.ChannelID) ([]wtdb.ChanBackup, error)
+
+	// MarkChannelClosed records that the registered channel was
+	// cooperatively closed, so that none of its revoked states need to be
+	// backed up anymore.
+	MarkChannelClosed(lnwire.ChannelID) error
+
+	// FetchClosedChannels returns the set of registered channels that were
+	// marked as closed.
+	FetchClosedChannels() (map[lnwire.ChannelID]struct{}, error)
+
+	// DeleteSession removes the client session with the given id from the
+	// database. If the session still has unacked updates,
+	// wtdb.ErrSessionHasUnackedUpdates is returned.
+	DeleteSession(wtdb.SessionID) error
+
 	// MarkBackupIneligible records that the state identified by the
 	// (channel id, commit height) tuple was ineligible for being backed up
 	// under the current policy. This state can be retried later under a
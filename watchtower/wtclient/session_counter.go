@@ -0,0 +1,68 @@
+package wtclient
+
+import (
+	"sync"
+
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+)
+
+// SessionCounter counts the active sessions held with each tower, which are
+// the sessions that aren't exhausted yet. It can be shared by the clients of
+// different channel types, such that the MaxSessionsPerTower quota applies to
+// the sessions of all of them.
+type SessionCounter struct {
+	mu       sync.Mutex
+	sessions map[wtdb.TowerID]map[wtdb.SessionID]struct{}
+}
+
+// NewSessionCounter creates a SessionCounter without any sessions.
+func NewSessionCounter() *SessionCounter {
+	return &SessionCounter{
+		sessions: make(map[wtdb.TowerID]map[wtdb.SessionID]struct{}),
+	}
+}
+
+// add registers the given session with its tower, unless all of its updates
+// have been used already. Adding the same session again has no effect.
+func (c *SessionCounter) add(s *wtdb.ClientSession) {
+	if s.SeqNum >= s.Policy.MaxUpdates {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sessions, ok := c.sessions[s.TowerID]
+	if !ok {
+		sessions = make(map[wtdb.SessionID]struct{})
+		c.sessions[s.TowerID] = sessions
+	}
+	sessions[s.ID] = struct{}{}
+}
+
+// remove unregisters a session held with the given tower, because it was
+// exhausted or its tower was removed.
+func (c *SessionCounter) remove(towerID wtdb.TowerID,
+	sessionID wtdb.SessionID) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sessions, ok := c.sessions[towerID]
+	if !ok {
+		return
+	}
+
+	delete(sessions, sessionID)
+	if len(sessions) == 0 {
+		delete(c.sessions, towerID)
+	}
+}
+
+// count returns the number of active sessions held with the given tower.
+func (c *SessionCounter) count(towerID wtdb.TowerID) uint32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return uint32(len(c.sessions[towerID]))
+}
@@ -0,0 +1,83 @@
+package wtclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
+	"github.com/stretchr/testify/require"
+)
+
+// newCountedSession creates a session with the given tower that used seqNum of
+// its ten updates.
+func newCountedSession(towerID wtdb.TowerID, id byte,
+	seqNum uint16) *wtdb.ClientSession {
+
+	return &wtdb.ClientSession{
+		ID: wtdb.SessionID{id},
+		ClientSessionBody: wtdb.ClientSessionBody{
+			SeqNum:  seqNum,
+			TowerID: towerID,
+			Policy: wtpolicy.Policy{
+				MaxUpdates: 10,
+			},
+		},
+	}
+}
+
+// TestSessionCounter asserts that only the active sessions are counted per
+// tower, and that sessions are no longer counted once removed.
+func TestSessionCounter(t *testing.T) {
+	t.Parallel()
+
+	counter := NewSessionCounter()
+
+	// Sessions are counted once per tower, and exhausted sessions aren't
+	// counted at all.
+	counter.add(newCountedSession(1, 1, 0))
+	counter.add(newCountedSession(1, 1, 0))
+	counter.add(newCountedSession(1, 2, 9))
+	counter.add(newCountedSession(1, 3, 10))
+	counter.add(newCountedSession(2, 4, 0))
+
+	require.EqualValues(t, 2, counter.count(1))
+	require.EqualValues(t, 1, counter.count(2))
+	require.Zero(t, counter.count(3))
+
+	// Once a session is exhausted, it no longer counts towards the quota
+	// of its tower.
+	counter.remove(1, wtdb.SessionID{1})
+	counter.remove(1, wtdb.SessionID{4})
+	require.EqualValues(t, 1, counter.count(1))
+
+	counter.remove(2, wtdb.SessionID{4})
+	require.Zero(t, counter.count(2))
+	require.NotContains(t, counter.sessions, wtdb.TowerID(2))
+}
+
+// TestSessionCounterShared asserts that the health of a tower reports the
+// sessions of all clients sharing the same SessionCounter.
+func TestSessionCounterShared(t *testing.T) {
+	t.Parallel()
+
+	policy := &BackoffPolicy{
+		MinBackoff: time.Millisecond,
+		MaxBackoff: time.Second,
+		Multiplier: 2,
+	}
+
+	counter := NewSessionCounter()
+	legacyBackoffs := newTowerBackoffSet(policy, counter)
+	anchorBackoffs := newTowerBackoffSet(policy, counter)
+
+	counter.add(newCountedSession(1, 1, 0))
+	counter.add(newCountedSession(1, 2, 0))
+
+	require.EqualValues(t, 2, legacyBackoffs.health(1).NumSessions)
+	require.EqualValues(t, 2, anchorBackoffs.health(1).NumSessions)
+
+	counter.remove(1, wtdb.SessionID{2})
+	require.EqualValues(t, 1, legacyBackoffs.health(1).NumSessions)
+	require.EqualValues(t, 1, anchorBackoffs.health(1).NumSessions)
+}
@@ -74,18 +74,18 @@ type NegotiatorConfig struct {
 	// originate from this chain.
 	ChainHash chainhash.Hash
 
-	// MinBackoff defines the initial backoff applied by the session
-	// negotiator after all tower candidates have been exhausted and
-	// reattempting negotiation with the same set of candidates. Subsequent
-	// backoff durations will grow exponentially.
-	MinBackoff time.Duration
-
-	// MaxBackoff defines the maximum backoff applied by the session
-	// negotiator after all tower candidates have been exhausted and
-	// reattempting negotiation with the same set of candidates. If the
-	// exponential backoff produces a timeout greater than this value, the
-	// backoff duration will be clamped to MaxBackoff.
-	MaxBackoff time.Duration
+	// Backoff defines the backoff applied by the session negotiator after
+	// all tower candidates have been exhausted and reattempting
+	// negotiation with the same set of candidates.
+	Backoff *BackoffPolicy
+
+	// TowerBackoffs tracks the connection backoff state of each tower.
+	// Towers whose circuit breaker tripped are skipped.
+	TowerBackoffs *towerBackoffSet
+
+	// AddrBook tracks the current addresses of each tower, including the
+	// ones learned from its node announcement.
+	AddrBook *towerAddrBook
 
 	// Log specifies the desired log output, which should be prefixed by the
 	// client type, e.g. anchor or legacy.
@@ -121,13 +121,19 @@ func newSessionNegotiator(cfg *NegotiatorConfig) *sessionNegotiator {
 	// Generate the set of features the negotiator will present to the tower
 	// upon connection. For anchor channels, we'll conditionally signal that
 	// we require support for anchor channels depending on the requested
-	// policy.
+	// policy. The same applies to dynamic fees.
 	features := []lnwire.FeatureBit{
 		wtwire.AltruistSessionsRequired,
 	}
 	if cfg.Policy.IsAnchorChannel() {
 		features = append(features, wtwire.AnchorCommitRequired)
 	}
+	if cfg.Policy.HasDynamicFee() {
+		features = append(features, wtwire.DynamicFeeRequired)
+	}
+	if cfg.Policy.HasCPFPAnchor() {
+		features = append(features, wtwire.CPFPAnchorRequired)
+	}
 
 	localInit := wtwire.NewInitMessage(
 		lnwire.NewRawFeatureVector(features...),
@@ -252,21 +258,14 @@ func (n *sessionNegotiator) negotiate() {
 	// Create a closure to update the backoff upon failure such that it
 	// stays within our min and max backoff parameters.
 	updateBackoff := func() {
-		if backoff == 0 {
-			backoff = n.cfg.MinBackoff
-		} else {
-			backoff *= 2
-			if backoff > n.cfg.MaxBackoff {
-				backoff = n.cfg.MaxBackoff
-			}
-		}
+		backoff = n.cfg.Backoff.nextBackoff(backoff)
 	}
 
 retryWithBackoff:
 	// If we are retrying, wait out the delay before continuing.
 	if backoff > 0 {
 		select {
-		case <-time.After(backoff):
+		case <-time.After(n.cfg.Backoff.withJitter(backoff)):
 		case <-n.quit:
 			return
 		}
@@ -299,6 +298,16 @@ retryWithBackoff:
 		}
 
 		towerPub := tower.IdentityKey.SerializeCompressed()
+
+		// Skip any tower whose circuit breaker tripped, it'll be
+		// considered again once its cooldown has passed.
+		towerBackoff := n.cfg.TowerBackoffs.get(tower.ID)
+		if delay := towerBackoff.dialDelay(); delay > 0 {
+			n.log.Debugf("Skipping tower=%x, circuit breaker is "+
+				"open for another %v", towerPub, delay)
+			continue
+		}
+
 		n.log.Debugf("Attempting session negotiation with tower=%x",
 			towerPub)
 
@@ -319,6 +328,7 @@ retryWithBackoff:
 		err = n.createSession(tower, keyIndex)
 		if err != nil {
 			// An unexpected error occurred, updpate our backoff.
+			towerBackoff.recordFailure()
 			updateBackoff()
 
 			n.log.Debugf("Session negotiation with tower=%x "+
@@ -329,14 +339,17 @@ retryWithBackoff:
 		}
 
 		// Success.
+		towerBackoff.recordSuccess()
 		return
 	}
 }
 
 // createSession takes a tower an attempts to negotiate a session using any of
-// its stored addresses. This method returns after the first successful
-// negotiation, or after all addresses have failed with ErrFailedNegotiation. If
-// the tower has no addresses, ErrNoTowerAddrs is returned.
+// its stored addresses. All addresses are dialed in parallel, and the session
+// is negotiated over the first connection that is established. This method
+// returns ErrFailedNegotiation if none of the addresses could be dialed or the
+// negotiation failed. If the tower has no addresses, ErrNoTowerAddrs is
+// returned.
 func (n *sessionNegotiator) createSession(tower *wtdb.Tower,
 	keyIndex uint32) error {
 
@@ -358,41 +371,46 @@ func (n *sessionNegotiator) createSession(tower *wtdb.Tower,
 		sessionKeyDesc, n.cfg.SecretKeyRing,
 	)
 
-	for _, lnAddr := range tower.LNAddrs() {
-		err := n.tryAddress(sessionKey, keyIndex, tower, lnAddr)
-		switch {
-		case err == ErrPermanentTowerFailure:
-			// TODO(conner): report to iterator? can then be reset
-			// with restart
-			fallthrough
-
-		case err != nil:
-			n.log.Debugf("Request for session negotiation with "+
-				"tower=%s failed, trying again -- reason: "+
-				"%v", lnAddr, err)
-			continue
-
-		default:
-			return nil
-		}
+	// Connect to the tower using our generated session key.
+	dial := func(addr *lnwire.NetAddress) (wtserver.Peer, error) {
+		return n.cfg.Dial(sessionKey, addr)
 	}
 
-	return ErrFailedNegotiation
-}
+	dialStart := time.Now()
+	addrs := n.cfg.AddrBook.get(tower.ID, tower.Addresses)
+	conn, lnAddr, err := dialAny(dial, tower.IdentityKey, addrs)
+	if err != nil {
+		n.log.Debugf("Unable to dial tower=%x for session "+
+			"negotiation: %v",
+			tower.IdentityKey.SerializeCompressed(), err)
 
-// tryAddress executes a single create session dance using the given address.
-// The address should belong to the tower's set of addresses. This method only
-// returns true if all steps succeed and the new session has been persisted, and
-// fails otherwise.
-func (n *sessionNegotiator) tryAddress(sessionKey keychain.SingleKeyECDH,
-	keyIndex uint32, tower *wtdb.Tower, lnAddr *lnwire.NetAddress) error {
+		return ErrFailedNegotiation
+	}
+	n.cfg.TowerBackoffs.get(tower.ID).recordLatency(time.Since(dialStart))
 
-	// Connect to the tower address using our generated session key.
-	conn, err := n.cfg.Dial(sessionKey, lnAddr)
+	err = n.tryAddress(conn, sessionKey, keyIndex, tower, lnAddr)
 	if err != nil {
-		return err
+		// TODO(conner): report ErrPermanentTowerFailure to iterator?
+		// can then be reset with restart
+		n.log.Debugf("Request for session negotiation with "+
+			"tower=%s failed, trying again -- reason: "+
+			"%v", lnAddr, err)
+
+		return ErrFailedNegotiation
 	}
 
+	return nil
+}
+
+// tryAddress executes a single create session dance over the given connection
+// to the tower at the given address. This method only returns nil if all steps
+// succeed and the new session has been persisted, and fails otherwise.
+func (n *sessionNegotiator) tryAddress(conn wtserver.Peer,
+	sessionKey keychain.SingleKeyECDH, keyIndex uint32, tower *wtdb.Tower,
+	lnAddr *lnwire.NetAddress) error {
+
+	var err error
+
 	// Send local Init message.
 	err = n.cfg.SendMessage(conn, n.localInit)
 	if err != nil {
@@ -411,19 +429,30 @@ func (n *sessionNegotiator) tryAddress(sessionKey keychain.SingleKeyECDH,
 		return fmt.Errorf("expected Init, got %T in reply", remoteMsg)
 	}
 
-	// Verify the watchtower's remote Init message against our own.
-	err = n.localInit.CheckRemoteInit(remoteInit, wtwire.FeatureNames)
+	// Verify the watchtower's remote Init message against our own, and
+	// make sure the tower supports the features our sessions rely on.
+	negotiation, err := n.localInit.Negotiate(
+		remoteInit, wtwire.FeatureNames,
+	)
 	if err != nil {
 		return err
 	}
+	n.cfg.TowerBackoffs.get(tower.ID).recordNegotiation(negotiation)
+
+	if !negotiation.AllowsBlobType(n.cfg.Policy.BlobType) {
+		return fmt.Errorf("negotiated %v doesn't allow blob type %s",
+			negotiation, n.cfg.Policy.BlobType)
+	}
 
 	policy := n.cfg.Policy
 	createSession := &wtwire.CreateSession{
-		BlobType:     policy.BlobType,
-		MaxUpdates:   policy.MaxUpdates,
-		RewardBase:   policy.RewardBase,
-		RewardRate:   policy.RewardRate,
-		SweepFeeRate: policy.SweepFeeRate,
+		BlobType:           policy.BlobType,
+		MaxUpdates:         policy.MaxUpdates,
+		RewardBase:         policy.RewardBase,
+		RewardRate:         policy.RewardRate,
+		SweepFeeRate:       policy.SweepFeeRate,
+		MaxSweepFeeRate:    policy.MaxSweepFeeRate,
+		SweepFeePercentile: policy.SweepFeePercentile,
 	}
 
 	// Send CreateSession message.
@@ -455,6 +484,13 @@ func (n *sessionNegotiator) tryAddress(sessionKey keychain.SingleKeyECDH,
 		// TODO(conner): validate reward address
 		rewardPkScript := createSessionReply.Data
 
+		// Sessions with a CPFP anchor pay the anchor output to the
+		// tower, so the tower must have given us an address.
+		if policy.HasCPFPAnchor() && len(rewardPkScript) == 0 {
+			return fmt.Errorf("tower didn't provide an address " +
+				"for the cpfp anchor")
+		}
+
 		sessionID := wtdb.NewSessionIDFromPubKey(sessionKey.PubKey())
 		clientSession := &wtdb.ClientSession{
 			ClientSessionBody: wtdb.ClientSessionBody{
@@ -59,18 +59,14 @@ type sessionQueueConfig struct {
 	// DB provides access to the client's stable storage.
 	DB DB
 
-	// MinBackoff defines the initial backoff applied by the session
-	// queue before reconnecting to the tower after a failed or partially
-	// successful batch is sent. Subsequent backoff durations will grow
-	// exponentially up until MaxBackoff.
-	MinBackoff time.Duration
-
-	// MaxBackoff defines the maximum backoff applied by the session
-	// queue before reconnecting to the tower after a failed or partially
-	// successful batch is sent. If the exponential backoff produces a
-	// timeout greater than this value, the backoff duration will be clamped
-	// to MaxBackoff.
-	MaxBackoff time.Duration
+	// TowerBackoff tracks the backoff applied by the session queue before
+	// reconnecting to the tower after a failed or partially successful
+	// batch is sent. It is shared with all other users of the same tower.
+	TowerBackoff *towerBackoff
+
+	// AddrBook tracks the current addresses of the session's tower, all
+	// of which are dialed in parallel when connecting to it.
+	AddrBook *towerAddrBook
 
 	// Log specifies the desired log output, which should be prefixed by the
 	// client type, e.g. anchor or legacy.
@@ -101,8 +97,6 @@ type sessionQueue struct {
 
 	seqNum uint16
 
-	retryBackoff time.Duration
-
 	quit      chan struct{}
 	forceQuit chan struct{}
 	shutdown  chan struct{}
@@ -128,7 +122,6 @@ func newSessionQueue(cfg *sessionQueueConfig) *sessionQueue {
 		localInit:    localInit,
 		towerAddr:    towerAddr,
 		seqNum:       cfg.ClientSession.SeqNum,
-		retryBackoff: cfg.MinBackoff,
 		quit:         make(chan struct{}),
 		forceQuit:    make(chan struct{}),
 		shutdown:     make(chan struct{}),
@@ -291,21 +284,43 @@ func (q *sessionQueue) sessionManager() {
 
 // drainBackups attempts to send all pending updates in the queue to the tower.
 func (q *sessionQueue) drainBackups() {
-	// First, check that we are able to dial this session's tower.
-	conn, err := q.cfg.Dial(q.cfg.ClientSession.SessionKeyECDH, q.towerAddr)
-	if err != nil {
-		q.log.Errorf("SessionQueue(%s) unable to dial tower at %v: %v",
-			q.ID(), q.towerAddr, err)
+	// If the tower's circuit breaker tripped, we'll wait out its cooldown
+	// before dialing it again.
+	if delay := q.cfg.TowerBackoff.dialDelay(); delay > 0 {
+		q.log.Debugf("SessionQueue(%s) circuit breaker of tower at %v "+
+			"is open, retrying after %v", q.ID(), q.towerAddr, delay)
 
-		q.increaseBackoff()
 		select {
-		case <-time.After(q.retryBackoff):
+		case <-time.After(delay):
 		case <-q.forceQuit:
 		}
 		return
 	}
+
+	// First, check that we are able to dial this session's tower through
+	// any of its addresses.
+	tower := q.cfg.ClientSession.Tower
+	addrs := q.cfg.AddrBook.get(tower.ID, tower.Addresses)
+	dial := func(addr *lnwire.NetAddress) (wtserver.Peer, error) {
+		return q.cfg.Dial(q.cfg.ClientSession.SessionKeyECDH, addr)
+	}
+
+	dialStart := time.Now()
+	conn, towerAddr, err := dialAny(dial, tower.IdentityKey, addrs)
+	if err != nil {
+		q.log.Errorf("SessionQueue(%s) unable to dial tower %x: %v",
+			q.ID(), tower.IdentityKey.SerializeCompressed(), err)
+
+		q.backoff()
+		return
+	}
 	defer conn.Close()
 
+	q.log.Debugf("SessionQueue(%s) connected to tower at %v", q.ID(),
+		towerAddr)
+
+	q.cfg.TowerBackoff.recordLatency(time.Since(dialStart))
+
 	// Begin draining the queue of pending state updates. Before the first
 	// update is sent, we will precede it with an Init message. If the first
 	// is successful, subsequent updates can be streamed without sending an
@@ -329,11 +344,7 @@ func (q *sessionQueue) drainBackups() {
 			q.log.Errorf("SessionQueue(%s) unable to send state "+
 				"update: %v", q.ID(), err)
 
-			q.increaseBackoff()
-			select {
-			case <-time.After(q.retryBackoff):
-			case <-q.forceQuit:
-			}
+			q.backoff()
 			return
 		}
 
@@ -345,7 +356,7 @@ func (q *sessionQueue) drainBackups() {
 		// clear any accumulated backoff as this batch was able to be
 		// sent reliably.
 		if stateUpdate.IsComplete == 1 {
-			q.resetBackoff()
+			q.cfg.TowerBackoff.recordSuccess()
 			return
 		}
 
@@ -507,12 +518,13 @@ func (q *sessionQueue) sendStateUpdate(conn wtserver.Peer,
 		}
 
 		// Validate Init.
-		err = q.localInit.CheckRemoteInit(
+		negotiation, err := q.localInit.Negotiate(
 			remoteInit, wtwire.FeatureNames,
 		)
 		if err != nil {
 			return err
 		}
+		q.cfg.TowerBackoff.recordNegotiation(negotiation)
 	}
 
 	// Send StateUpdate to tower.
@@ -614,17 +626,14 @@ func (q *sessionQueue) reserveStatus() reserveStatus {
 
 }
 
-// resetBackoff returns the connection backoff the minimum configured backoff.
-func (q *sessionQueue) resetBackoff() {
-	q.retryBackoff = q.cfg.MinBackoff
-}
+// backoff records a failed attempt to reach the tower and waits out the
+// resulting backoff, unless a force quit is requested in the meantime.
+func (q *sessionQueue) backoff() {
+	delay := q.cfg.TowerBackoff.recordFailure()
 
-// increaseBackoff doubles the current connection backoff, clamping to the
-// configured maximum backoff if it would exceed the limit.
-func (q *sessionQueue) increaseBackoff() {
-	q.retryBackoff *= 2
-	if q.retryBackoff > q.cfg.MaxBackoff {
-		q.retryBackoff = q.cfg.MaxBackoff
+	select {
+	case <-time.After(delay):
+	case <-q.forceQuit:
 	}
 }
 
@@ -0,0 +1,316 @@
+package wtclient
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/tor"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+)
+
+// TowerCandidate is a tower that may be selected for a new session, along
+// with its current connection state.
+type TowerCandidate struct {
+	// Tower is the candidate tower.
+	Tower *wtdb.Tower
+
+	// Health summarizes how reliably the tower could be reached.
+	Health TowerHealth
+
+	// BackoffState is the tower's current connection backoff state.
+	BackoffState TowerBackoffState
+}
+
+// TowerSelector decides which tower the client negotiates its next session
+// with.
+type TowerSelector interface {
+	// SelectTower returns the candidate to negotiate the next session
+	// with, or nil if none of the candidates should be used right now.
+	// The candidates are given in the order they were added to the
+	// client.
+	SelectTower(candidates []*TowerCandidate) *TowerCandidate
+}
+
+// TowerSelectionPolicy configures how the PolicyTowerSelector ranks towers.
+type TowerSelectionPolicy struct {
+	// MaxSessionsPerTower is the number of active sessions held with a
+	// tower after which it is only selected if no other tower is
+	// available. A value of zero disables the quota.
+	MaxSessionsPerTower uint32
+
+	// Diversify, if set, prefers towers on a different network than the
+	// tower selected last, so that sessions are spread across clearnet
+	// subnets and Tor.
+	Diversify bool
+}
+
+// PolicyTowerSelector is a TowerSelector that spreads sessions across towers
+// based on their health. Towers whose circuit breaker tripped are never
+// selected. The remaining towers are ranked by, in order:
+//   - their number of consecutive connection failures,
+//   - whether they reached the session quota,
+//   - whether they are on a different network than the last selected tower,
+//     if diversification is enabled,
+//   - their number of sessions,
+//   - their latency.
+type PolicyTowerSelector struct {
+	policy TowerSelectionPolicy
+
+	mu        sync.Mutex
+	lastGroup string
+}
+
+// Compile-time constraint to ensure *PolicyTowerSelector implements the
+// TowerSelector interface.
+var _ TowerSelector = (*PolicyTowerSelector)(nil)
+
+// NewPolicyTowerSelector creates a new PolicyTowerSelector applying the given
+// policy.
+func NewPolicyTowerSelector(policy TowerSelectionPolicy) *PolicyTowerSelector {
+	return &PolicyTowerSelector{
+		policy: policy,
+	}
+}
+
+// SelectTower returns the highest ranked candidate, or nil if the circuit
+// breakers of all candidates tripped.
+//
+// NOTE: Part of the TowerSelector interface.
+func (p *PolicyTowerSelector) SelectTower(
+	candidates []*TowerCandidate) *TowerCandidate {
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	eligible := make([]*TowerCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.BackoffState.CircuitOpen(now) {
+			continue
+		}
+		eligible = append(eligible, candidate)
+	}
+	if len(eligible) == 0 {
+		return nil
+	}
+
+	overQuota := func(c *TowerCandidate) bool {
+		quota := p.policy.MaxSessionsPerTower
+		return quota > 0 && c.Health.NumSessions >= quota
+	}
+	sameGroup := func(c *TowerCandidate) bool {
+		return p.policy.Diversify && p.lastGroup != "" &&
+			networkGroup(c.Tower) == p.lastGroup
+	}
+
+	// Sort stably, so that towers that rank the same are selected in the
+	// order they were added.
+	sort.SliceStable(eligible, func(i, j int) bool {
+		a, b := eligible[i], eligible[j]
+
+		aFailures := a.BackoffState.ConsecutiveFailures
+		bFailures := b.BackoffState.ConsecutiveFailures
+		if aFailures != bFailures {
+			return aFailures < bFailures
+		}
+
+		if overQuota(a) != overQuota(b) {
+			return !overQuota(a)
+		}
+
+		if sameGroup(a) != sameGroup(b) {
+			return !sameGroup(a)
+		}
+
+		if a.Health.NumSessions != b.Health.NumSessions {
+			return a.Health.NumSessions < b.Health.NumSessions
+		}
+
+		return a.Health.Latency < b.Health.Latency
+	})
+
+	selected := eligible[0]
+	p.lastGroup = networkGroup(selected.Tower)
+
+	return selected
+}
+
+// networkGroup returns an identifier of the network the tower is reached
+// over, using its first address. Towers sharing an IPv4 /16 or IPv6 /32
+// subnet, and all onion services, belong to the same group.
+func networkGroup(tower *wtdb.Tower) string {
+	if len(tower.Addresses) == 0 {
+		return ""
+	}
+
+	switch addr := tower.Addresses[0].(type) {
+	case *tor.OnionAddr:
+		return "onion"
+
+	case *net.TCPAddr:
+		if ip4 := addr.IP.To4(); ip4 != nil {
+			subnet := ip4.Mask(net.CIDRMask(16, 32))
+			return fmt.Sprintf("ipv4:%v", subnet)
+		}
+
+		subnet := addr.IP.Mask(net.CIDRMask(32, 128))
+		return fmt.Sprintf("ipv6:%v", subnet)
+
+	default:
+		return addr.Network()
+	}
+}
+
+// towerSelectorIterator is a TowerCandidateIterator that leaves the order in
+// which towers are proposed to a TowerSelector. Each tower is proposed at
+// most once until the iterator is reset.
+type towerSelectorIterator struct {
+	selector TowerSelector
+	backoffs *towerBackoffSet
+
+	mu         sync.Mutex
+	order      []wtdb.TowerID
+	candidates map[wtdb.TowerID]*wtdb.Tower
+	taken      map[wtdb.TowerID]struct{}
+}
+
+// Compile-time constraint to ensure *towerSelectorIterator implements the
+// TowerCandidateIterator interface.
+var _ TowerCandidateIterator = (*towerSelectorIterator)(nil)
+
+// newTowerSelectorIterator initializes a new towerSelectorIterator that ranks
+// the given candidates using the selector and the health tracked by backoffs.
+func newTowerSelectorIterator(selector TowerSelector,
+	backoffs *towerBackoffSet,
+	candidates ...*wtdb.Tower) *towerSelectorIterator {
+
+	iter := &towerSelectorIterator{
+		selector:   selector,
+		backoffs:   backoffs,
+		candidates: make(map[wtdb.TowerID]*wtdb.Tower),
+		taken:      make(map[wtdb.TowerID]struct{}),
+	}
+
+	for _, candidate := range candidates {
+		iter.AddCandidate(candidate)
+	}
+
+	return iter
+}
+
+// Reset makes all towers that were previously proposed available again.
+//
+// NOTE: Part of the TowerCandidateIterator interface.
+func (t *towerSelectorIterator) Reset() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.taken = make(map[wtdb.TowerID]struct{})
+
+	return nil
+}
+
+// Next returns the tower picked by the selector among the towers that were
+// not yet proposed. If there are none or the selector picks none,
+// ErrTowerCandidatesExhausted is returned.
+//
+// NOTE: Part of the TowerCandidateIterator interface.
+func (t *towerSelectorIterator) Next() (*wtdb.Tower, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var candidates []*TowerCandidate
+	for _, id := range t.order {
+		if _, ok := t.taken[id]; ok {
+			continue
+		}
+
+		candidates = append(candidates, &TowerCandidate{
+			Tower:        t.candidates[id],
+			Health:       t.backoffs.health(id),
+			BackoffState: t.backoffs.state(id),
+		})
+	}
+	if len(candidates) == 0 {
+		return nil, ErrTowerCandidatesExhausted
+	}
+
+	selected := t.selector.SelectTower(candidates)
+	if selected == nil {
+		return nil, ErrTowerCandidatesExhausted
+	}
+	t.taken[selected.Tower.ID] = struct{}{}
+
+	return selected.Tower, nil
+}
+
+// AddCandidate adds a new candidate tower to the iterator. If the candidate
+// already exists, then any new addresses are added to it.
+//
+// NOTE: Part of the TowerCandidateIterator interface.
+func (t *towerSelectorIterator) AddCandidate(candidate *wtdb.Tower) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tower, ok := t.candidates[candidate.ID]
+	if !ok {
+		t.order = append(t.order, candidate.ID)
+		t.candidates[candidate.ID] = candidate
+		return
+	}
+
+	for _, addr := range candidate.Addresses {
+		tower.AddAddress(addr)
+	}
+}
+
+// RemoveCandidate removes an existing candidate tower from the iterator. An
+// optional address can be provided to indicate a stale tower address to remove
+// it. If it isn't provided, then the tower is completely removed from the
+// iterator.
+//
+// NOTE: Part of the TowerCandidateIterator interface.
+func (t *towerSelectorIterator) RemoveCandidate(candidate wtdb.TowerID,
+	addr net.Addr) error {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tower, ok := t.candidates[candidate]
+	if !ok {
+		return nil
+	}
+	if addr != nil {
+		tower.RemoveAddress(addr)
+		if len(tower.Addresses) == 0 {
+			return wtdb.ErrLastTowerAddr
+		}
+
+		return nil
+	}
+
+	delete(t.candidates, candidate)
+	delete(t.taken, candidate)
+	for i, id := range t.order {
+		if id == candidate {
+			t.order = append(t.order[:i], t.order[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// IsActive determines whether a given tower exists within the iterator.
+//
+// NOTE: Part of the TowerCandidateIterator interface.
+func (t *towerSelectorIterator) IsActive(tower wtdb.TowerID) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, ok := t.candidates[tower]
+	return ok
+}
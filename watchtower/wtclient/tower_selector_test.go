@@ -0,0 +1,237 @@
+package wtclient
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/tor"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/stretchr/testify/require"
+)
+
+// newSelectorCandidate creates a tower candidate with the given id, reachable
+// at the given address.
+func newSelectorCandidate(id wtdb.TowerID, addr net.Addr) *TowerCandidate {
+	return &TowerCandidate{
+		Tower: &wtdb.Tower{
+			ID:        id,
+			Addresses: []net.Addr{addr},
+		},
+	}
+}
+
+// TestPolicyTowerSelector asserts that the PolicyTowerSelector ranks towers by
+// their connection failures, session quota, network diversity, number of
+// sessions and latency.
+func TestPolicyTowerSelector(t *testing.T) {
+	t.Parallel()
+
+	clearnetA := &net.TCPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 9911}
+	clearnetB := &net.TCPAddr{IP: net.IPv4(1, 2, 5, 6), Port: 9911}
+	onion := &tor.OnionAddr{OnionService: "3g2upl4pq6kufc4m.onion"}
+
+	tests := []struct {
+		name        string
+		policy      TowerSelectionPolicy
+		lastGroup   string
+		candidates  func() []*TowerCandidate
+		expSelected wtdb.TowerID
+	}{
+		{
+			name: "first tower if all rank the same",
+			candidates: func() []*TowerCandidate {
+				return []*TowerCandidate{
+					newSelectorCandidate(1, clearnetA),
+					newSelectorCandidate(2, clearnetB),
+				}
+			},
+			expSelected: 1,
+		},
+		{
+			name: "no tower if all circuits are open",
+			candidates: func() []*TowerCandidate {
+				c := newSelectorCandidate(1, clearnetA)
+				openUntil := time.Now().Add(time.Hour)
+				c.BackoffState.CircuitOpenUntil = openUntil
+
+				return []*TowerCandidate{c}
+			},
+		},
+		{
+			name: "fewer consecutive failures",
+			candidates: func() []*TowerCandidate {
+				a := newSelectorCandidate(1, clearnetA)
+				a.BackoffState.ConsecutiveFailures = 2
+				b := newSelectorCandidate(2, clearnetB)
+				b.BackoffState.ConsecutiveFailures = 1
+				b.Health.NumSessions = 10
+
+				return []*TowerCandidate{a, b}
+			},
+			expSelected: 2,
+		},
+		{
+			name: "below session quota",
+			policy: TowerSelectionPolicy{
+				MaxSessionsPerTower: 2,
+			},
+			candidates: func() []*TowerCandidate {
+				a := newSelectorCandidate(1, clearnetA)
+				a.Health.NumSessions = 2
+				b := newSelectorCandidate(2, clearnetB)
+				b.Health.NumSessions = 1
+				b.Health.Latency = time.Second
+
+				return []*TowerCandidate{a, b}
+			},
+			expSelected: 2,
+		},
+		{
+			name: "over session quota if no other tower",
+			policy: TowerSelectionPolicy{
+				MaxSessionsPerTower: 2,
+			},
+			candidates: func() []*TowerCandidate {
+				a := newSelectorCandidate(1, clearnetA)
+				a.Health.NumSessions = 2
+
+				return []*TowerCandidate{a}
+			},
+			expSelected: 1,
+		},
+		{
+			name: "different network than last tower",
+			policy: TowerSelectionPolicy{
+				Diversify: true,
+			},
+			lastGroup: "ipv4:1.2.0.0",
+			candidates: func() []*TowerCandidate {
+				a := newSelectorCandidate(1, clearnetB)
+				b := newSelectorCandidate(2, onion)
+				b.Health.NumSessions = 1
+
+				return []*TowerCandidate{a, b}
+			},
+			expSelected: 2,
+		},
+		{
+			name:      "diversity ignored if disabled",
+			lastGroup: "ipv4:1.2.0.0",
+			candidates: func() []*TowerCandidate {
+				a := newSelectorCandidate(1, clearnetB)
+				b := newSelectorCandidate(2, onion)
+				b.Health.NumSessions = 1
+
+				return []*TowerCandidate{a, b}
+			},
+			expSelected: 1,
+		},
+		{
+			name: "fewer sessions",
+			candidates: func() []*TowerCandidate {
+				a := newSelectorCandidate(1, clearnetA)
+				a.Health.NumSessions = 3
+				b := newSelectorCandidate(2, clearnetB)
+				b.Health.NumSessions = 2
+				b.Health.Latency = time.Second
+
+				return []*TowerCandidate{a, b}
+			},
+			expSelected: 2,
+		},
+		{
+			name: "lower latency",
+			candidates: func() []*TowerCandidate {
+				a := newSelectorCandidate(1, clearnetA)
+				a.Health.Latency = time.Second
+				b := newSelectorCandidate(2, clearnetB)
+				b.Health.Latency = time.Millisecond
+
+				return []*TowerCandidate{a, b}
+			},
+			expSelected: 2,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			selector := NewPolicyTowerSelector(test.policy)
+			selector.lastGroup = test.lastGroup
+
+			selected := selector.SelectTower(test.candidates())
+			if test.expSelected == 0 {
+				require.Nil(t, selected)
+				return
+			}
+
+			require.NotNil(t, selected)
+			require.Equal(t, test.expSelected, selected.Tower.ID)
+		})
+	}
+}
+
+// TestTowerSelectorIterator asserts that the towerSelectorIterator proposes
+// each tower once per round in the order chosen by the selector, using the
+// health tracked for each tower.
+func TestTowerSelectorIterator(t *testing.T) {
+	t.Parallel()
+
+	sessions := NewSessionCounter()
+	backoffs := newTowerBackoffSet(&BackoffPolicy{
+		MinBackoff:              time.Millisecond,
+		MaxBackoff:              time.Second,
+		Multiplier:              2,
+		CircuitBreakerThreshold: 1,
+		CircuitBreakerCooldown:  time.Hour,
+	}, sessions)
+
+	addr := &net.TCPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 9911}
+	tower1 := newSelectorCandidate(1, addr).Tower
+	tower2 := newSelectorCandidate(2, addr).Tower
+	tower3 := newSelectorCandidate(3, addr).Tower
+
+	selector := NewPolicyTowerSelector(TowerSelectionPolicy{})
+	iter := newTowerSelectorIterator(
+		selector, backoffs, tower1, tower2, tower1,
+	)
+	iter.AddCandidate(tower3)
+
+	// The first tower holds a session, and the circuit breaker of the
+	// third one tripped, so the second tower is preferred.
+	sessions.add(newCountedSession(1, 1, 0))
+	backoffs.get(3).recordFailure()
+
+	tower, err := iter.Next()
+	require.NoError(t, err)
+	require.Equal(t, tower2, tower)
+
+	tower, err = iter.Next()
+	require.NoError(t, err)
+	require.Equal(t, tower1, tower)
+
+	_, err = iter.Next()
+	require.ErrorIs(t, err, ErrTowerCandidatesExhausted)
+
+	// Once reset, the towers are proposed again.
+	require.NoError(t, iter.Reset())
+	tower, err = iter.Next()
+	require.NoError(t, err)
+	require.Equal(t, tower2, tower)
+
+	// Removing a tower excludes it from all following rounds.
+	require.NoError(t, iter.RemoveCandidate(tower1.ID, nil))
+	require.False(t, iter.IsActive(tower1.ID))
+	require.True(t, iter.IsActive(tower2.ID))
+
+	_, err = iter.Next()
+	require.ErrorIs(t, err, ErrTowerCandidatesExhausted)
+
+	require.NoError(t, iter.Reset())
+	tower, err = iter.Next()
+	require.NoError(t, err)
+	require.Equal(t, tower2, tower)
+}
@@ -30,3 +30,16 @@ func (s *ClientChanSummary) Encode(w io.Writer) error {
 func (s *ClientChanSummary) Decode(r io.Reader) error {
 	return ReadElement(r, &s.SweepPkScript)
 }
+
+// ChanBackup records that a revoked state of a channel was backed up to a
+// tower, i.e. that the tower acknowledged the update holding it.
+type ChanBackup struct {
+	// CommitHeight is the commitment height of the revoked state.
+	CommitHeight uint64
+
+	// SessionID is the session the state was backed up with.
+	SessionID SessionID
+
+	// TowerID is the tower the session was negotiated with.
+	TowerID TowerID
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"sort"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightningnetwork/lnd/kvdb"
@@ -22,6 +23,22 @@ var (
 	//   channel-id -> encoded ClientChanSummary.
 	cChanSummaryBkt = []byte("client-channel-summary-bucket")
 
+	// cClosedChanBkt is a top-level bucket storing:
+	//   channel-id -> empty value.
+	// It records the registered channels that were cooperatively closed,
+	// whose backups are no longer needed.
+	cClosedChanBkt = []byte("client-closed-channel-bucket")
+
+	// cSweepPkScriptBkt is a top-level bucket storing:
+	//   cSweepPkScriptKey -> sweep pkscript.
+	// It records the sweep pkscript that justice transactions of all
+	// channels pay into once it was configured.
+	cSweepPkScriptBkt = []byte("client-sweep-pkscript-bucket")
+
+	// cSweepPkScriptKey is the key of the sweep pkscript within
+	// cSweepPkScriptBkt.
+	cSweepPkScriptKey = []byte("sweep-pkscript")
+
 	// cSessionBkt is a top-level bucket storing:
 	//   session-id => cSessionBody -> encoded ClientSessionBody
 	//              => cSessionCommits => seqnum -> encoded CommittedUpdate
@@ -108,6 +125,16 @@ var (
 	// ErrLastTowerAddr is an error returned when the last address of a
 	// watchtower is attempted to be removed.
 	ErrLastTowerAddr = errors.New("cannot remove last tower address")
+
+	// ErrSessionHasUnackedUpdates is an error returned when a client
+	// session is attempted to be deleted while it still has updates that
+	// weren't acked by the tower.
+	ErrSessionHasUnackedUpdates = errors.New("session has unacked updates")
+
+	// ErrSweepPkScriptMismatch is returned when a sweep pkscript is
+	// configured that differs from the one that was persisted earlier.
+	ErrSweepPkScriptMismatch = errors.New("sweep pkscript differs from " +
+		"the persisted one")
 )
 
 // NewBoltBackendCreator returns a function that creates a new bbolt backend for
@@ -131,6 +158,8 @@ func NewBoltBackendCreator(active bool, dbPath,
 			NoFreelistSync:    boltCfg.NoFreelistSync,
 			AutoCompact:       boltCfg.AutoCompact,
 			AutoCompactMinAge: boltCfg.AutoCompactMinAge,
+			CheckIntegrity:    boltCfg.CheckIntegrity,
+			SalvageCorrupt:    boltCfg.SalvageCorrupt,
 			DBTimeout:         boltCfg.DBTimeout,
 		}
 
@@ -192,6 +221,8 @@ func initClientDBBuckets(tx kvdb.RwTx) error {
 	buckets := [][]byte{
 		cSessionKeyIndexBkt,
 		cChanSummaryBkt,
+		cClosedChanBkt,
+		cSweepPkScriptBkt,
 		cSessionBkt,
 		cTowerBkt,
 		cTowerIndexBkt,
@@ -750,6 +781,61 @@ func (c *ClientDB) FetchChanSummaries() (ChannelSummaries, error) {
 	return summaries, nil
 }
 
+// FetchChanBackups returns a record for every revoked state of the given
+// channel that was acknowledged by a tower, sorted by commit height. A state
+// that was backed up with multiple sessions is returned once for each of them.
+func (c *ClientDB) FetchChanBackups(chanID lnwire.ChannelID) ([]ChanBackup,
+	error) {
+
+	var backups []ChanBackup
+	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
+		sessions := tx.ReadBucket(cSessionBkt)
+		if sessions == nil {
+			return ErrUninitializedDB
+		}
+
+		// The acked updates of each session already record which
+		// states were backed up with it, so we only need to collect
+		// the ones of the requested channel.
+		return sessions.ForEach(func(k, _ []byte) error {
+			session, err := getClientSessionBody(sessions, k)
+			if err != nil {
+				return err
+			}
+
+			ackedUpdates, err := getClientSessionAcks(sessions, k)
+			if err != nil {
+				return err
+			}
+
+			for _, backupID := range ackedUpdates {
+				if backupID.ChanID != chanID {
+					continue
+				}
+
+				backups = append(backups, ChanBackup{
+					CommitHeight: backupID.CommitHeight,
+					SessionID:    session.ID,
+					TowerID:      session.TowerID,
+				})
+			}
+
+			return nil
+		})
+	}, func() {
+		backups = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CommitHeight < backups[j].CommitHeight
+	})
+
+	return backups, nil
+}
+
 // RegisterChannel registers a channel for use within the client database. For
 // now, all that is stored in the channel summary is the sweep pkscript that
 // we'd like any tower sweeps to pay into. In the future, this will be extended
@@ -787,6 +873,158 @@ func (c *ClientDB) RegisterChannel(chanID lnwire.ChannelID,
 	}, func() {})
 }
 
+// UpdateChannelSweepPkScript replaces the sweep pkscript of a registered
+// channel. Justice transactions of states that are backed up afterwards pay
+// into the new pkscript, states that were backed up earlier are unaffected.
+func (c *ClientDB) UpdateChannelSweepPkScript(chanID lnwire.ChannelID,
+	sweepPkScript []byte) error {
+
+	return kvdb.Update(c.db, func(tx kvdb.RwTx) error {
+		chanSummaries := tx.ReadWriteBucket(cChanSummaryBkt)
+		if chanSummaries == nil {
+			return ErrUninitializedDB
+		}
+
+		summary, err := getChanSummary(chanSummaries, chanID)
+		if err != nil {
+			return err
+		}
+
+		summary.SweepPkScript = sweepPkScript
+
+		return putChanSummary(chanSummaries, chanID, summary)
+	}, func() {})
+}
+
+// RatchetSweepPkScript persists the given sweep pkscript, unless one was
+// persisted before, and returns the persisted sweep pkscript. Once persisted,
+// it can't be changed or removed: ErrSweepPkScriptMismatch is returned if a
+// different pkscript is passed, and an empty pkscript returns the persisted
+// one, which is nil if none was persisted yet.
+func (c *ClientDB) RatchetSweepPkScript(sweepPkScript []byte) ([]byte,
+	error) {
+
+	var persisted []byte
+	err := kvdb.Update(c.db, func(tx kvdb.RwTx) error {
+		sweepPkScriptBkt := tx.ReadWriteBucket(cSweepPkScriptBkt)
+		if sweepPkScriptBkt == nil {
+			return ErrUninitializedDB
+		}
+
+		stored := sweepPkScriptBkt.Get(cSweepPkScriptKey)
+		switch {
+		case len(stored) > 0 && len(sweepPkScript) > 0 &&
+			!bytes.Equal(stored, sweepPkScript):
+
+			return ErrSweepPkScriptMismatch
+
+		case len(stored) > 0:
+			persisted = make([]byte, len(stored))
+			copy(persisted, stored)
+
+			return nil
+
+		case len(sweepPkScript) == 0:
+			return nil
+		}
+
+		persisted = sweepPkScript
+
+		return sweepPkScriptBkt.Put(cSweepPkScriptKey, sweepPkScript)
+	}, func() {
+		persisted = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return persisted, nil
+}
+
+// MarkChannelClosed records that the registered channel was cooperatively
+// closed, so that none of its revoked states need to be backed up anymore.
+// ErrChannelNotRegistered is returned if the channel was never registered.
+func (c *ClientDB) MarkChannelClosed(chanID lnwire.ChannelID) error {
+	return kvdb.Update(c.db, func(tx kvdb.RwTx) error {
+		chanSummaries := tx.ReadBucket(cChanSummaryBkt)
+		if chanSummaries == nil {
+			return ErrUninitializedDB
+		}
+		closedChans := tx.ReadWriteBucket(cClosedChanBkt)
+		if closedChans == nil {
+			return ErrUninitializedDB
+		}
+
+		_, err := getChanSummary(chanSummaries, chanID)
+		if err != nil {
+			return err
+		}
+
+		return closedChans.Put(chanID[:], nil)
+	}, func() {})
+}
+
+// FetchClosedChannels returns the set of registered channels that were marked
+// as closed.
+func (c *ClientDB) FetchClosedChannels() (map[lnwire.ChannelID]struct{},
+	error) {
+
+	var closedChans map[lnwire.ChannelID]struct{}
+	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
+		closedChanBkt := tx.ReadBucket(cClosedChanBkt)
+		if closedChanBkt == nil {
+			return ErrUninitializedDB
+		}
+
+		return closedChanBkt.ForEach(func(k, _ []byte) error {
+			var chanID lnwire.ChannelID
+			copy(chanID[:], k)
+			closedChans[chanID] = struct{}{}
+
+			return nil
+		})
+	}, func() {
+		closedChans = make(map[lnwire.ChannelID]struct{})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return closedChans, nil
+}
+
+// DeleteSession removes the client session with the given id along with its
+// acked updates from the database. This should only be done once the tower
+// deleted the session as well. ErrSessionHasUnackedUpdates is returned if the
+// session still has committed updates that weren't acked by the tower.
+func (c *ClientDB) DeleteSession(id SessionID) error {
+	return kvdb.Update(c.db, func(tx kvdb.RwTx) error {
+		sessions := tx.ReadWriteBucket(cSessionBkt)
+		if sessions == nil {
+			return ErrUninitializedDB
+		}
+
+		// Make sure the session exists before inspecting its committed
+		// updates.
+		_, err := getClientSessionBody(sessions, id[:])
+		if err != nil {
+			return err
+		}
+
+		committedUpdates, err := getClientSessionCommits(
+			sessions, id[:],
+		)
+		if err != nil {
+			return err
+		}
+		if len(committedUpdates) > 0 {
+			return ErrSessionHasUnackedUpdates
+		}
+
+		return sessions.DeleteNestedBucket(id[:])
+	}, func() {})
+}
+
 // MarkBackupIneligible records that the state identified by the (channel id,
 // commit height) tuple was ineligible for being backed up under the current
 // policy. This state can be retried later under a different policy.
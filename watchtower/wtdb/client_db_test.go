@@ -194,6 +194,35 @@ func (h *clientDBHarness) registerChan(chanID lnwire.ChannelID,
 	}
 }
 
+func (h *clientDBHarness) updateChanSweepPkScript(chanID lnwire.ChannelID,
+	sweepPkScript []byte, expErr error) {
+
+	h.t.Helper()
+
+	err := h.db.UpdateChannelSweepPkScript(chanID, sweepPkScript)
+	if err != expErr {
+		h.t.Fatalf("expected update sweep pkscript error: %v, got: %v",
+			expErr, err)
+	}
+}
+
+func (h *clientDBHarness) ratchetSweepPkScript(sweepPkScript,
+	expPkScript []byte, expErr error) {
+
+	h.t.Helper()
+
+	pkScript, err := h.db.RatchetSweepPkScript(sweepPkScript)
+	if err != expErr {
+		h.t.Fatalf("expected ratchet sweep pkscript error: %v, got: %v",
+			expErr, err)
+	}
+
+	if !bytes.Equal(expPkScript, pkScript) {
+		h.t.Fatalf("pkscript mismatch, want: %x, got: %x",
+			expPkScript, pkScript)
+	}
+}
+
 func (h *clientDBHarness) commitUpdate(id *wtdb.SessionID,
 	update *wtdb.CommittedUpdate, expErr error) uint16 {
 
@@ -220,6 +249,52 @@ func (h *clientDBHarness) ackUpdate(id *wtdb.SessionID, seqNum uint16,
 	}
 }
 
+func (h *clientDBHarness) fetchChanBackups(
+	chanID lnwire.ChannelID) []wtdb.ChanBackup {
+
+	h.t.Helper()
+
+	backups, err := h.db.FetchChanBackups(chanID)
+	if err != nil {
+		h.t.Fatalf("unable to fetch chan backups: %v", err)
+	}
+
+	return backups
+}
+
+func (h *clientDBHarness) markChannelClosed(chanID lnwire.ChannelID,
+	expErr error) {
+
+	h.t.Helper()
+
+	err := h.db.MarkChannelClosed(chanID)
+	if err != expErr {
+		h.t.Fatalf("expected mark channel closed error: %v, got: %v",
+			expErr, err)
+	}
+}
+
+func (h *clientDBHarness) fetchClosedChannels() map[lnwire.ChannelID]struct{} {
+	h.t.Helper()
+
+	closedChans, err := h.db.FetchClosedChannels()
+	if err != nil {
+		h.t.Fatalf("unable to fetch closed channels: %v", err)
+	}
+
+	return closedChans
+}
+
+func (h *clientDBHarness) deleteSession(id wtdb.SessionID, expErr error) {
+	h.t.Helper()
+
+	err := h.db.DeleteSession(id)
+	if err != expErr {
+		h.t.Fatalf("expected delete session error: %v, got: %v",
+			expErr, err)
+	}
+}
+
 // testCreateClientSession asserts various conditions regarding the creation of
 // a new ClientSession. The test asserts:
 //   - client sessions can only be created if a session key index is reserved.
@@ -530,9 +605,57 @@ func testChanSummaries(h *clientDBHarness) {
 			expPkScript, summary.SweepPkScript)
 	}
 
-	// Finally, assert that re-registering the same channel produces a
-	// failure.
+	// Assert that re-registering the same channel produces a failure.
 	h.registerChan(chanID, expPkScript, wtdb.ErrChannelAlreadyRegistered)
+
+	// Replace the sweep pkscript of the channel and assert that the new
+	// one is returned.
+	newPkScript := make([]byte, 22)
+	if _, err := io.ReadFull(crand.Reader, newPkScript); err != nil {
+		h.t.Fatalf("unable to generate pkscript: %v", err)
+	}
+	h.updateChanSweepPkScript(chanID, newPkScript, nil)
+
+	summary = h.fetchChanSummaries()[chanID]
+	if !bytes.Equal(newPkScript, summary.SweepPkScript) {
+		h.t.Fatalf("pkscript mismatch, want: %x, got: %x",
+			newPkScript, summary.SweepPkScript)
+	}
+
+	// Finally, assert that the pkscript of an unregistered channel can't
+	// be replaced.
+	h.updateChanSweepPkScript(
+		lnwire.ChannelID{0x01}, newPkScript,
+		wtdb.ErrChannelNotRegistered,
+	)
+}
+
+// testRatchetSweepPkScript tests that the first sweep pkscript is persisted,
+// and that it can neither be replaced by a different one nor removed.
+func testRatchetSweepPkScript(h *clientDBHarness) {
+	// Nothing is persisted as long as no sweep pkscript is set.
+	h.ratchetSweepPkScript(nil, nil, nil)
+
+	pkScript := make([]byte, 22)
+	if _, err := io.ReadFull(crand.Reader, pkScript); err != nil {
+		h.t.Fatalf("unable to generate pkscript: %v", err)
+	}
+	h.ratchetSweepPkScript(pkScript, pkScript, nil)
+
+	// Setting the same pkscript again and setting no pkscript both return
+	// the persisted one.
+	h.ratchetSweepPkScript(pkScript, pkScript, nil)
+	h.ratchetSweepPkScript(nil, pkScript, nil)
+
+	// Finally, assert that a different pkscript is rejected.
+	otherPkScript := make([]byte, 34)
+	if _, err := io.ReadFull(crand.Reader, otherPkScript); err != nil {
+		h.t.Fatalf("unable to generate pkscript: %v", err)
+	}
+	h.ratchetSweepPkScript(
+		otherPkScript, nil, wtdb.ErrSweepPkScriptMismatch,
+	)
+	h.ratchetSweepPkScript(nil, pkScript, nil)
 }
 
 // testCommitUpdate tests the behavior of CommitUpdate, ensuring that they can
@@ -726,6 +849,144 @@ func testAckUpdate(h *clientDBHarness) {
 	h.ackUpdate(&session.ID, 4, 3, wtdb.ErrUnallocatedLastApplied)
 }
 
+// testFetchChanBackups asserts that FetchChanBackups only returns the acked
+// updates of the requested channel, sorted by commit height.
+func testFetchChanBackups(h *clientDBHarness) {
+	const blobType = blob.TypeAltruistCommit
+
+	session := &wtdb.ClientSession{
+		ClientSessionBody: wtdb.ClientSessionBody{
+			TowerID: wtdb.TowerID(3),
+			Policy: wtpolicy.Policy{
+				TxPolicy: wtpolicy.TxPolicy{
+					BlobType: blobType,
+				},
+				MaxUpdates: 100,
+			},
+			RewardPkScript: []byte{0x01, 0x02, 0x03},
+		},
+		ID: wtdb.SessionID([33]byte{0x03}),
+	}
+	session.KeyIndex = h.nextKeyIndex(session.TowerID, blobType)
+	h.insertSession(session, nil)
+
+	// Commit updates for two channels, with the heights of the first one
+	// out of order.
+	update1 := randCommittedUpdate(h.t, 1)
+	update1.BackupID.CommitHeight = 5
+	chanID := update1.BackupID.ChanID
+
+	update2 := randCommittedUpdate(h.t, 2)
+
+	update3 := randCommittedUpdate(h.t, 3)
+	update3.BackupID.ChanID = chanID
+	update3.BackupID.CommitHeight = 3
+
+	update4 := randCommittedUpdate(h.t, 4)
+	update4.BackupID.ChanID = chanID
+	update4.BackupID.CommitHeight = 7
+
+	for _, update := range []*wtdb.CommittedUpdate{
+		update1, update2, update3, update4,
+	} {
+		h.commitUpdate(&session.ID, update, nil)
+	}
+
+	// Nothing is returned before the tower acks any of the updates.
+	if backups := h.fetchChanBackups(chanID); len(backups) != 0 {
+		h.t.Fatalf("expected no backups, got: %v", backups)
+	}
+
+	// Ack all but the last update.
+	h.ackUpdate(&session.ID, 1, 1, nil)
+	h.ackUpdate(&session.ID, 2, 2, nil)
+	h.ackUpdate(&session.ID, 3, 3, nil)
+
+	expBackups := []wtdb.ChanBackup{
+		{
+			CommitHeight: 3,
+			SessionID:    session.ID,
+			TowerID:      session.TowerID,
+		},
+		{
+			CommitHeight: 5,
+			SessionID:    session.ID,
+			TowerID:      session.TowerID,
+		},
+	}
+	backups := h.fetchChanBackups(chanID)
+	if !reflect.DeepEqual(expBackups, backups) {
+		h.t.Fatalf("backups mismatch, want: %v, got: %v", expBackups,
+			backups)
+	}
+}
+
+// testDeleteClientSession asserts that only registered channels can be marked
+// as closed, and that sessions can only be deleted once all of their updates
+// were acked.
+func testDeleteClientSession(h *clientDBHarness) {
+	const blobType = blob.TypeAltruistCommit
+
+	// Only registered channels can be marked as closed.
+	var chanID lnwire.ChannelID
+	chanID[0] = 0x01
+	h.markChannelClosed(chanID, wtdb.ErrChannelNotRegistered)
+
+	h.registerChan(chanID, []byte{0x01}, nil)
+	h.markChannelClosed(chanID, nil)
+
+	// Marking a channel closed twice is harmless.
+	h.markChannelClosed(chanID, nil)
+
+	expClosedChans := map[lnwire.ChannelID]struct{}{
+		chanID: {},
+	}
+	closedChans := h.fetchClosedChannels()
+	if !reflect.DeepEqual(expClosedChans, closedChans) {
+		h.t.Fatalf("closed channels mismatch, want: %v, got: %v",
+			expClosedChans, closedChans)
+	}
+
+	session := &wtdb.ClientSession{
+		ClientSessionBody: wtdb.ClientSessionBody{
+			TowerID: wtdb.TowerID(3),
+			Policy: wtpolicy.Policy{
+				TxPolicy: wtpolicy.TxPolicy{
+					BlobType: blobType,
+				},
+				MaxUpdates: 100,
+			},
+			RewardPkScript: []byte{0x01, 0x02, 0x03},
+		},
+		ID: wtdb.SessionID([33]byte{0x03}),
+	}
+
+	// Deleting an unknown session fails.
+	h.deleteSession(session.ID, wtdb.ErrClientSessionNotFound)
+
+	session.KeyIndex = h.nextKeyIndex(session.TowerID, blobType)
+	h.insertSession(session, nil)
+
+	// A session with an unacked update can't be deleted.
+	update := randCommittedUpdate(h.t, 1)
+	update.BackupID.ChanID = chanID
+	h.commitUpdate(&session.ID, update, nil)
+	h.deleteSession(session.ID, wtdb.ErrSessionHasUnackedUpdates)
+
+	// Once the update is acked, the session can be deleted, after which it
+	// and its backups are gone.
+	h.ackUpdate(&session.ID, 1, 1, nil)
+	h.deleteSession(session.ID, nil)
+
+	if sessions := h.listSessions(nil); len(sessions) != 0 {
+		h.t.Fatalf("expected no sessions, got: %v", sessions)
+	}
+	if backups := h.fetchChanBackups(chanID); len(backups) != 0 {
+		h.t.Fatalf("expected no backups, got: %v", backups)
+	}
+	h.deleteSession(session.ID, wtdb.ErrClientSessionNotFound)
+}
+
 // checkCommittedUpdates asserts that the CommittedUpdates on session match the
 // expUpdates provided.
 func checkCommittedUpdates(t *testing.T, session *wtdb.ClientSession,
@@ -832,6 +1093,46 @@ func TestClientDB(t *testing.T) {
 				return db
 			},
 		},
+		{
+			name: "migrated clientdb",
+			init: func(t *testing.T) wtclient.DB {
+				src, err := wtdb.NewBoltBackendCreator(
+					true, t.TempDir(), "wtclient.db",
+				)(dbCfg)
+				if err != nil {
+					t.Fatalf("unable to open db: %v", err)
+				}
+
+				srcDB, err := wtdb.OpenClientDB(src)
+				if err != nil {
+					t.Fatalf("unable to open db: %v", err)
+				}
+
+				dst, err := wtdb.NewBoltBackendCreator(
+					true, t.TempDir(), "wtclient.db",
+				)(dbCfg)
+				if err != nil {
+					t.Fatalf("unable to open db: %v", err)
+				}
+
+				err = wtdb.MigrateBackend(src, dst)
+				if err != nil {
+					t.Fatalf("unable to migrate: %v", err)
+				}
+				srcDB.Close()
+
+				db, err := wtdb.OpenClientDB(dst)
+				if err != nil {
+					t.Fatalf("unable to open db: %v", err)
+				}
+
+				t.Cleanup(func() {
+					db.Close()
+				})
+
+				return db
+			},
+		},
 		{
 			name: "mock",
 			init: func(t *testing.T) wtclient.DB {
@@ -864,6 +1165,10 @@ func TestClientDB(t *testing.T) {
 			name: "chan summaries",
 			run:  testChanSummaries,
 		},
+		{
+			name: "ratchet sweep pkscript",
+			run:  testRatchetSweepPkScript,
+		},
 		{
 			name: "commit update",
 			run:  testCommitUpdate,
@@ -872,6 +1177,14 @@ func TestClientDB(t *testing.T) {
 			name: "ack update",
 			run:  testAckUpdate,
 		},
+		{
+			name: "fetch chan backups",
+			run:  testFetchChanBackups,
+		},
+		{
+			name: "delete client session",
+			run:  testDeleteClientSession,
+		},
 	}
 
 	for _, database := range dbs {
@@ -60,6 +60,23 @@ func ReadElement(r io.Reader, element interface{}) error {
 		e.BlobType = blob.Type(blobType)
 		e.SweepFeeRate = chainfee.SatPerKWeight(sweepFeeRate)
 
+		// The dynamic fee parameters are only present for dynamic fee
+		// sessions, such that existing sessions can still be read.
+		if !e.BlobType.HasDynamicFee() {
+			return nil
+		}
+
+		var maxSweepFeeRate uint64
+		err = channeldb.ReadElements(r,
+			&maxSweepFeeRate,
+			&e.SweepFeePercentile,
+		)
+		if err != nil {
+			return err
+		}
+
+		e.MaxSweepFeeRate = chainfee.SatPerKWeight(maxSweepFeeRate)
+
 	// Type is still unknown to wtdb extensions, fail.
 	default:
 		return channeldb.NewUnknownElementType(
@@ -100,13 +117,25 @@ func WriteElement(w io.Writer, element interface{}) error {
 		}
 
 	case wtpolicy.Policy:
-		return channeldb.WriteElements(w,
+		err := channeldb.WriteElements(w,
 			uint16(e.BlobType),
 			e.MaxUpdates,
 			e.RewardBase,
 			e.RewardRate,
 			uint64(e.SweepFeeRate),
 		)
+		if err != nil {
+			return err
+		}
+
+		if !e.BlobType.HasDynamicFee() {
+			return nil
+		}
+
+		return channeldb.WriteElements(w,
+			uint64(e.MaxSweepFeeRate),
+			e.SweepFeePercentile,
+		)
 
 	// Type is still unknown to wtdb extensions, fail.
 	default:
@@ -11,10 +11,46 @@ import (
 	"testing/quick"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/tor"
+	"github.com/lightningnetwork/lnd/watchtower/blob"
 	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
 )
 
+// randValue populates the object pointed to by obj with random values.
+func randValue(t *testing.T, r *rand.Rand, obj interface{}) {
+	objPtr := reflect.ValueOf(obj)
+
+	value, ok := quick.Value(objPtr.Elem().Type(), r)
+	if !ok {
+		t.Fatalf("unable to generate random %T", obj)
+	}
+
+	objPtr.Elem().Set(value)
+}
+
+// randPolicy creates a random session policy. The dynamic fee parameters are
+// only set for dynamic fee policies, as they aren't serialized otherwise.
+func randPolicy(r *rand.Rand) wtpolicy.Policy {
+	policy := wtpolicy.Policy{
+		TxPolicy: wtpolicy.TxPolicy{
+			BlobType:     blob.Type(r.Int31()),
+			RewardBase:   r.Uint32(),
+			RewardRate:   r.Uint32(),
+			SweepFeeRate: chainfee.SatPerKWeight(r.Uint64()),
+		},
+		MaxUpdates: uint16(r.Int31()),
+	}
+
+	if policy.HasDynamicFee() {
+		policy.MaxSweepFeeRate = chainfee.SatPerKWeight(r.Uint64())
+		policy.SweepFeePercentile = uint8(r.Int31())
+	}
+
+	return policy
+}
+
 func randPubKey() (*btcec.PublicKey, error) {
 	priv, err := btcec.NewPrivateKey()
 	if err != nil {
@@ -196,6 +232,20 @@ func TestCodec(tt *testing.T) {
 				Addresses:   addrs,
 			}
 
+			v[0] = reflect.ValueOf(obj)
+		},
+		"SessionInfo": func(v []reflect.Value, r *rand.Rand) {
+			var obj wtdb.SessionInfo
+			randValue(t, r, &obj)
+			obj.Policy = randPolicy(r)
+
+			v[0] = reflect.ValueOf(obj)
+		},
+		"ClientSessionBody": func(v []reflect.Value, r *rand.Rand) {
+			var obj wtdb.ClientSessionBody
+			randValue(t, r, &obj)
+			obj.Policy = randPolicy(r)
+
 			v[0] = reflect.ValueOf(obj)
 		},
 	}
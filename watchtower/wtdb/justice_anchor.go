@@ -0,0 +1,72 @@
+package wtdb
+
+import (
+	"errors"
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+// ErrJusticeAnchorNotFound is returned when deleting the anchor of a justice
+// transaction that isn't stored.
+var ErrJusticeAnchorNotFound = errors.New("justice anchor not found")
+
+// JusticeAnchor describes the anchor output of a published justice
+// transaction, which the tower can spend to bump the fee of the justice
+// transaction using CPFP.
+type JusticeAnchor struct {
+	// OutPoint is the outpoint of the anchor output.
+	OutPoint wire.OutPoint
+
+	// TxOut is the anchor output, which pays to the tower's address.
+	TxOut *wire.TxOut
+
+	// JusticeFee is the fee paid by the justice transaction.
+	JusticeFee btcutil.Amount
+
+	// JusticeWeight is the weight of the justice transaction.
+	JusticeWeight int64
+
+	// FeeRate is the fee rate that the justice transaction together with
+	// the transaction spending the anchor should pay.
+	FeeRate chainfee.SatPerKWeight
+}
+
+// Encode serializes the justice anchor to the given io.Writer.
+func (a *JusticeAnchor) Encode(w io.Writer) error {
+	return WriteElements(w,
+		a.OutPoint,
+		a.TxOut.Value,
+		a.TxOut.PkScript,
+		a.JusticeFee,
+		a.JusticeWeight,
+		uint64(a.FeeRate),
+	)
+}
+
+// Decode deserializes the justice anchor from the given io.Reader.
+func (a *JusticeAnchor) Decode(r io.Reader) error {
+	var (
+		value   int64
+		script  []byte
+		feeRate uint64
+	)
+	err := ReadElements(r,
+		&a.OutPoint,
+		&value,
+		&script,
+		&a.JusticeFee,
+		&a.JusticeWeight,
+		&feeRate,
+	)
+	if err != nil {
+		return err
+	}
+
+	a.TxOut = wire.NewTxOut(value, script)
+	a.FeeRate = chainfee.SatPerKWeight(feeRate)
+
+	return nil
+}
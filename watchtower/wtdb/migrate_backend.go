@@ -0,0 +1,111 @@
+package wtdb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// ErrBackendInitialized signals that a database can't be migrated into a
+// backend that already holds an initialized database.
+var ErrBackendInitialized = errors.New("target backend already holds an " +
+	"initialized database")
+
+// MigrateBackend copies the tower or client database stored in src into dst,
+// for example to move a bolt database into a postgres backend once it grew too
+// large for bolt. The migration copies one top-level bucket per transaction
+// and the metadata bucket last, so an interrupted migration leaves dst
+// uninitialized and can simply be run again. An error is returned if src
+// isn't initialized or dst already is.
+func MigrateBackend(src, dst kvdb.Backend) error {
+	srcFirstInit, err := isFirstInit(src)
+	if err != nil {
+		return err
+	}
+	if srcFirstInit {
+		return ErrUninitializedDB
+	}
+
+	dstFirstInit, err := isFirstInit(dst)
+	if err != nil {
+		return err
+	}
+	if !dstFirstInit {
+		return ErrBackendInitialized
+	}
+
+	// The sequences of buckets are only accessible through read-write
+	// transactions. We never write to src, so the transaction is rolled
+	// back at the end.
+	srcTx, err := src.BeginReadWriteTx()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = srcTx.Rollback()
+	}()
+
+	var topLevelKeys [][]byte
+	err = srcTx.ForEachBucket(func(key []byte) error {
+		if string(key) == string(metadataBkt) {
+			return nil
+		}
+
+		keyCopy := make([]byte, len(key))
+		copy(keyCopy, key)
+		topLevelKeys = append(topLevelKeys, keyCopy)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	topLevelKeys = append(topLevelKeys, metadataBkt)
+
+	for _, key := range topLevelKeys {
+		log.Infof("Migrating bucket %s of watchtower database", key)
+
+		srcBucket := srcTx.ReadWriteBucket(key)
+		err := kvdb.Update(dst, func(tx kvdb.RwTx) error {
+			dstBucket, err := tx.CreateTopLevelBucket(key)
+			if err != nil {
+				return err
+			}
+
+			return copyBucket(srcBucket, dstBucket)
+		}, func() {})
+		if err != nil {
+			return fmt.Errorf("unable to migrate bucket %s: %v",
+				key, err)
+		}
+	}
+
+	return nil
+}
+
+// copyBucket recursively copies all key-value pairs, nested buckets and
+// sequences of src into dst.
+func copyBucket(src, dst kvdb.RwBucket) error {
+	err := src.ForEach(func(k, v []byte) error {
+		// Nested buckets are reported with a nil value.
+		if v == nil {
+			nestedSrc := src.NestedReadWriteBucket(k)
+			if nestedSrc != nil {
+				nestedDst, err := dst.CreateBucketIfNotExists(k)
+				if err != nil {
+					return err
+				}
+
+				return copyBucket(nestedSrc, nestedDst)
+			}
+		}
+
+		return dst.Put(k, v)
+	})
+	if err != nil {
+		return err
+	}
+
+	return dst.SetSequence(src.Sequence())
+}
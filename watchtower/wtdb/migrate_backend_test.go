@@ -0,0 +1,88 @@
+package wtdb_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/watchtower/blob"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMigrateBackend asserts that migrating a client database into a new
+// backend carries over all records as well as the bucket sequences used to
+// allocate new ids, and that a migration never overwrites an initialized
+// database.
+func TestMigrateBackend(t *testing.T) {
+	t.Parallel()
+
+	dbCfg := &kvdb.BoltConfig{DBTimeout: kvdb.DefaultDBTimeout}
+	newBackend := func() kvdb.Backend {
+		backend, err := wtdb.NewBoltBackendCreator(
+			true, t.TempDir(), "wtclient.db",
+		)(dbCfg)
+		require.NoError(t, err)
+
+		return backend
+	}
+
+	newTowerAddr := func() *lnwire.NetAddress {
+		pk, err := randPubKey()
+		require.NoError(t, err)
+
+		return &lnwire.NetAddress{
+			IdentityKey: pk,
+			Address: &net.TCPAddr{
+				IP: []byte{0x01, 0x00, 0x00, 0x00}, Port: 9911,
+			},
+		}
+	}
+
+	// An uninitialized database can't be migrated.
+	src := newBackend()
+	err := wtdb.MigrateBackend(src, newBackend())
+	require.ErrorIs(t, err, wtdb.ErrUninitializedDB)
+
+	// Populate the source database with a tower and a reserved session
+	// key index.
+	srcDB, err := wtdb.OpenClientDB(src)
+	require.NoError(t, err)
+
+	tower, err := srcDB.CreateTower(newTowerAddr())
+	require.NoError(t, err)
+
+	blobType := blob.TypeAltruistCommit
+	keyIndex, err := srcDB.NextSessionKeyIndex(tower.ID, blobType)
+	require.NoError(t, err)
+
+	dst := newBackend()
+	require.NoError(t, wtdb.MigrateBackend(src, dst))
+
+	// Migrating again must fail now that the target is initialized.
+	err = wtdb.MigrateBackend(src, dst)
+	require.ErrorIs(t, err, wtdb.ErrBackendInitialized)
+	require.NoError(t, srcDB.Close())
+
+	dstDB, err := wtdb.OpenClientDB(dst)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, dstDB.Close())
+	})
+
+	// The tower and its reserved key index must have been carried over.
+	migratedTower, err := dstDB.LoadTowerByID(tower.ID)
+	require.NoError(t, err)
+	require.Equal(t, tower, migratedTower)
+
+	migratedIndex, err := dstDB.NextSessionKeyIndex(tower.ID, blobType)
+	require.NoError(t, err)
+	require.Equal(t, keyIndex, migratedIndex)
+
+	// New towers must continue from the migrated sequence rather than
+	// reusing the ids of migrated towers.
+	tower2, err := dstDB.CreateTower(newTowerAddr())
+	require.NoError(t, err)
+	require.Greater(t, tower2.ID, tower.ID)
+}
@@ -3,8 +3,10 @@ package wtdb
 import (
 	"bytes"
 	"errors"
+	"sync/atomic"
 
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/watchtower/blob"
@@ -38,6 +40,12 @@ var (
 	// epoch from the lookoutTipBkt.
 	lookoutTipKey = []byte("lookout-tip")
 
+	// justiceAnchorsBkt is a bucket containing the anchor outputs of the
+	// published justice transactions whose fee the tower bumps, until the
+	// justice transactions confirm.
+	//   anchor outpoint -> justice anchor
+	justiceAnchorsBkt = []byte("justice-anchors-bucket")
+
 	// ErrNoSessionHintIndex signals that an active session does not have an
 	// initialized index for tracking its own state updates.
 	ErrNoSessionHintIndex = errors.New("session hint index missing")
@@ -50,6 +58,13 @@ var (
 // TowerDB is single database providing a persistent storage engine for the
 // wtserver and lookout subsystems.
 type TowerDB struct {
+	// storageBytes is the number of bytes occupied by the encoded sessions
+	// and state updates. It is computed once when opening the database
+	// and kept up to date by every write, so reading it is cheap.
+	//
+	// NOTE: This MUST be used atomically.
+	storageBytes uint64
+
 	db kvdb.Backend
 }
 
@@ -87,6 +102,12 @@ func OpenTowerDB(db kvdb.Backend) (*TowerDB, error) {
 		return nil, err
 	}
 
+	towerDB.storageBytes, err = towerDB.countStorageBytes()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
 	return towerDB, nil
 }
 
@@ -98,6 +119,7 @@ func initTowerDBBuckets(tx kvdb.RwTx) error {
 		updateIndexBkt,
 		updatesBkt,
 		lookoutTipBkt,
+		justiceAnchorsBkt,
 	}
 
 	for _, bucket := range buckets {
@@ -167,7 +189,8 @@ func (t *TowerDB) GetSessionInfo(id *SessionID) (*SessionInfo, error) {
 // InsertSessionInfo records a negotiated session in the tower database. An
 // error is returned if the session already exists.
 func (t *TowerDB) InsertSessionInfo(session *SessionInfo) error {
-	return kvdb.Update(t.db, func(tx kvdb.RwTx) error {
+	var sizeDelta int64
+	err := kvdb.Update(t.db, func(tx kvdb.RwTx) error {
 		sessions := tx.ReadWriteBucket(sessionsBkt)
 		if sessions == nil {
 			return ErrUninitializedDB
@@ -196,7 +219,7 @@ func (t *TowerDB) InsertSessionInfo(session *SessionInfo) error {
 			return err
 		}
 
-		err = putSession(sessions, session)
+		sizeDelta, err = putSession(sessions, session)
 		if err != nil {
 			return err
 		}
@@ -207,7 +230,16 @@ func (t *TowerDB) InsertSessionInfo(session *SessionInfo) error {
 		// be deleted without needing to iterate over the entire
 		// database.
 		return touchSessionHintBkt(updateIndex, &session.ID)
-	}, func() {})
+	}, func() {
+		sizeDelta = 0
+	})
+	if err != nil {
+		return err
+	}
+
+	t.addStorageBytes(sizeDelta)
+
+	return nil
 }
 
 // InsertStateUpdate stores an update sent by the client after validating that
@@ -215,7 +247,10 @@ func (t *TowerDB) InsertSessionInfo(session *SessionInfo) error {
 // session. This include verifying that the sequence number is incremented
 // properly and the last applied values echoed by the client are sane.
 func (t *TowerDB) InsertStateUpdate(update *SessionStateUpdate) (uint16, error) {
-	var lastApplied uint16
+	var (
+		lastApplied uint16
+		sizeDelta   int64
+	)
 	err := kvdb.Update(t.db, func(tx kvdb.RwTx) error {
 		sessions := tx.ReadWriteBucket(sessionsBkt)
 		if sessions == nil {
@@ -262,7 +297,7 @@ func (t *TowerDB) InsertStateUpdate(update *SessionStateUpdate) (uint16, error)
 
 		// Store the updated session to persist the updated last applied
 		// values.
-		err = putSession(sessions, session)
+		sizeDelta, err = putSession(sessions, session)
 		if err != nil {
 			return err
 		}
@@ -280,6 +315,8 @@ func (t *TowerDB) InsertStateUpdate(update *SessionStateUpdate) (uint16, error)
 			return err
 		}
 
+		oldUpdate := hints.Get(update.ID[:])
+		sizeDelta += int64(b.Len()) - int64(len(oldUpdate))
 		err = hints.Put(update.ID[:], b.Bytes())
 		if err != nil {
 			return err
@@ -291,18 +328,22 @@ func (t *TowerDB) InsertStateUpdate(update *SessionStateUpdate) (uint16, error)
 		return putHintForSession(updateIndex, &update.ID, update.Hint)
 	}, func() {
 		lastApplied = 0
+		sizeDelta = 0
 	})
 	if err != nil {
 		return 0, err
 	}
 
+	t.addStorageBytes(sizeDelta)
+
 	return lastApplied, nil
 }
 
 // DeleteSession removes all data associated with a particular session id from
 // the tower's database.
 func (t *TowerDB) DeleteSession(target SessionID) error {
-	return kvdb.Update(t.db, func(tx kvdb.RwTx) error {
+	var sizeDelta int64
+	err := kvdb.Update(t.db, func(tx kvdb.RwTx) error {
 		sessions := tx.ReadWriteBucket(sessionsBkt)
 		if sessions == nil {
 			return ErrUninitializedDB
@@ -323,6 +364,7 @@ func (t *TowerDB) DeleteSession(target SessionID) error {
 		if err != nil {
 			return err
 		}
+		sizeDelta = -int64(len(sessions.Get(target[:])))
 
 		// Remove the target session.
 		err = sessions.Delete(target[:])
@@ -350,6 +392,7 @@ func (t *TowerDB) DeleteSession(target SessionID) error {
 				continue
 			}
 
+			sizeDelta -= int64(len(update))
 			err := updatesForHint.Delete(target[:])
 			if err != nil {
 				return err
@@ -380,7 +423,16 @@ func (t *TowerDB) DeleteSession(target SessionID) error {
 		// Finally, remove this session from the update index, which
 		// also removes any of the indexed hints beneath it.
 		return removeSessionHintBkt(updateIndex, &target)
-	}, func() {})
+	}, func() {
+		sizeDelta = 0
+	})
+	if err != nil {
+		return err
+	}
+
+	t.addStorageBytes(sizeDelta)
+
+	return nil
 }
 
 // QueryMatches searches against all known state updates for any that match the
@@ -469,6 +521,65 @@ func (t *TowerDB) QueryMatches(breachHints []blob.BreachHint) ([]Match, error) {
 	return matches, nil
 }
 
+// StorageBytes returns the number of bytes occupied by the encoded sessions
+// and state updates currently stored by the tower. The value is tracked in
+// memory, so calling this doesn't access the database.
+func (t *TowerDB) StorageBytes() (uint64, error) {
+	return atomic.LoadUint64(&t.storageBytes), nil
+}
+
+// addStorageBytes adjusts the number of bytes occupied by the encoded sessions
+// and state updates by the given, possibly negative, delta.
+func (t *TowerDB) addStorageBytes(delta int64) {
+	atomic.AddUint64(&t.storageBytes, uint64(delta))
+}
+
+// countStorageBytes scans the database for the number of bytes occupied by the
+// encoded sessions and state updates.
+func (t *TowerDB) countStorageBytes() (uint64, error) {
+	var numBytes uint64
+	err := kvdb.View(t.db, func(tx kvdb.RTx) error {
+		sessions := tx.ReadBucket(sessionsBkt)
+		if sessions == nil {
+			return ErrUninitializedDB
+		}
+
+		updates := tx.ReadBucket(updatesBkt)
+		if updates == nil {
+			return ErrUninitializedDB
+		}
+
+		err := sessions.ForEach(func(_, v []byte) error {
+			numBytes += uint64(len(v))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// The updates bucket only contains a nested bucket for each
+		// breach hint, which in turn holds the updates themselves.
+		return updates.ForEach(func(hint, _ []byte) error {
+			updatesForHint := updates.NestedReadBucket(hint)
+			if updatesForHint == nil {
+				return nil
+			}
+
+			return updatesForHint.ForEach(func(_, v []byte) error {
+				numBytes += uint64(len(v))
+				return nil
+			})
+		})
+	}, func() {
+		numBytes = 0
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numBytes, nil
+}
+
 // SetLookoutTip stores the provided epoch as the latest lookout tip epoch in
 // the tower database.
 func (t *TowerDB) SetLookoutTip(epoch *chainntnfs.BlockEpoch) error {
@@ -505,6 +616,81 @@ func (t *TowerDB) GetLookoutTip() (*chainntnfs.BlockEpoch, error) {
 	return epoch, nil
 }
 
+// PutJusticeAnchor stores the anchor output of a published justice transaction
+// whose fee the tower bumps, so that the bump can be resumed after a restart.
+func (t *TowerDB) PutJusticeAnchor(anchor *JusticeAnchor) error {
+	var key, value bytes.Buffer
+	if err := WriteElement(&key, anchor.OutPoint); err != nil {
+		return err
+	}
+	if err := anchor.Encode(&value); err != nil {
+		return err
+	}
+
+	return kvdb.Update(t.db, func(tx kvdb.RwTx) error {
+		anchors := tx.ReadWriteBucket(justiceAnchorsBkt)
+		if anchors == nil {
+			return ErrUninitializedDB
+		}
+
+		return anchors.Put(key.Bytes(), value.Bytes())
+	}, func() {})
+}
+
+// DeleteJusticeAnchor removes the anchor output with the given outpoint, once
+// its justice transaction confirmed. ErrJusticeAnchorNotFound is returned if
+// the anchor output isn't stored.
+func (t *TowerDB) DeleteJusticeAnchor(outPoint *wire.OutPoint) error {
+	var key bytes.Buffer
+	if err := WriteElement(&key, *outPoint); err != nil {
+		return err
+	}
+
+	return kvdb.Update(t.db, func(tx kvdb.RwTx) error {
+		anchors := tx.ReadWriteBucket(justiceAnchorsBkt)
+		if anchors == nil {
+			return ErrUninitializedDB
+		}
+
+		if anchors.Get(key.Bytes()) == nil {
+			return ErrJusticeAnchorNotFound
+		}
+
+		return anchors.Delete(key.Bytes())
+	}, func() {})
+}
+
+// ListJusticeAnchors returns the anchor outputs of all published justice
+// transactions whose fee the tower bumps and that haven't confirmed yet.
+func (t *TowerDB) ListJusticeAnchors() ([]*JusticeAnchor, error) {
+	var justiceAnchors []*JusticeAnchor
+	err := kvdb.View(t.db, func(tx kvdb.RTx) error {
+		anchors := tx.ReadBucket(justiceAnchorsBkt)
+		if anchors == nil {
+			return ErrUninitializedDB
+		}
+
+		return anchors.ForEach(func(_, v []byte) error {
+			anchor := &JusticeAnchor{}
+			err := anchor.Decode(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+
+			justiceAnchors = append(justiceAnchors, anchor)
+
+			return nil
+		})
+	}, func() {
+		justiceAnchors = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return justiceAnchors, nil
+}
+
 // getSession retrieves the session info from the sessions bucket identified by
 // its session id. An error is returned if the session is not found or a
 // deserialization error occurs.
@@ -524,15 +710,18 @@ func getSession(sessions kvdb.RBucket, id []byte) (*SessionInfo, error) {
 }
 
 // putSession stores the session info in the sessions bucket identified by its
-// session id. An error is returned if a serialization error occurs.
-func putSession(sessions kvdb.RwBucket, session *SessionInfo) error {
+// session id. The change in the number of bytes stored in the bucket is
+// returned. An error is returned if a serialization error occurs.
+func putSession(sessions kvdb.RwBucket, session *SessionInfo) (int64, error) {
 	var b bytes.Buffer
 	err := session.Encode(&b)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	return sessions.Put(session.ID[:], b.Bytes())
+	sizeDelta := int64(b.Len()) - int64(len(sessions.Get(session.ID[:])))
+
+	return sizeDelta, sessions.Put(session.ID[:], b.Bytes())
 }
 
 // touchSessionHintBkt initializes the session-hint bucket for a particular
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/watchtower"
@@ -14,6 +15,7 @@ import (
 	"github.com/lightningnetwork/lnd/watchtower/wtdb"
 	"github.com/lightningnetwork/lnd/watchtower/wtmock"
 	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -112,6 +114,22 @@ func (h *towerDBHarness) queryMatches(hint blob.BreachHint) []wtdb.Match {
 	return matches
 }
 
+// assertStorageBytes asserts that the database reports the expected number of
+// bytes being stored.
+func (h *towerDBHarness) assertStorageBytes(expBytes uint64) {
+	h.t.Helper()
+
+	numBytes, err := h.db.StorageBytes()
+	if err != nil {
+		h.t.Fatalf("unable to fetch storage bytes: %v", err)
+	}
+
+	if numBytes != expBytes {
+		h.t.Fatalf("expected %d storage bytes, got: %d", expBytes,
+			numBytes)
+	}
+}
+
 // hasUpdate queries the database for the passed breach hint, asserting that
 // only one match is present and that the hints indeed match. If successful, the
 // match is returned.
@@ -277,6 +295,53 @@ func testLookoutTip(h *towerDBHarness) {
 	}
 }
 
+// testJusticeAnchors asserts that the anchor outputs of justice transactions
+// can be stored, listed and deleted.
+func testJusticeAnchors(h *towerDBHarness) {
+	anchors, err := h.db.ListJusticeAnchors()
+	require.NoError(h.t, err)
+	require.Empty(h.t, anchors)
+
+	anchor1 := &wtdb.JusticeAnchor{
+		OutPoint: wire.OutPoint{
+			Hash:  chainhash.Hash{1},
+			Index: 2,
+		},
+		TxOut:         wire.NewTxOut(330, []byte{0x51, 0x20, 0x01}),
+		JusticeFee:    1000,
+		JusticeWeight: 800,
+		FeeRate:       5000,
+	}
+	anchor2 := &wtdb.JusticeAnchor{
+		OutPoint: wire.OutPoint{
+			Hash: chainhash.Hash{2},
+		},
+		TxOut:         wire.NewTxOut(330, []byte{0x00, 0x14, 0x02}),
+		JusticeFee:    2000,
+		JusticeWeight: 900,
+		FeeRate:       6000,
+	}
+
+	require.NoError(h.t, h.db.PutJusticeAnchor(anchor1))
+	require.NoError(h.t, h.db.PutJusticeAnchor(anchor2))
+
+	anchors, err = h.db.ListJusticeAnchors()
+	require.NoError(h.t, err)
+	require.ElementsMatch(
+		h.t, []*wtdb.JusticeAnchor{anchor1, anchor2}, anchors,
+	)
+
+	require.NoError(h.t, h.db.DeleteJusticeAnchor(&anchor1.OutPoint))
+	require.ErrorIs(
+		h.t, h.db.DeleteJusticeAnchor(&anchor1.OutPoint),
+		wtdb.ErrJusticeAnchorNotFound,
+	)
+
+	anchors, err = h.db.ListJusticeAnchors()
+	require.NoError(h.t, err)
+	require.Equal(h.t, []*wtdb.JusticeAnchor{anchor2}, anchors)
+}
+
 // testDeleteSession asserts the behavior of a tower database when deleting
 // session data. The test asserts that the only proper the target session is
 // remmoved, and that only updates for a particular session are pruned.
@@ -371,6 +436,47 @@ func testDeleteSession(h *towerDBHarness) {
 	}
 }
 
+// testStorageBytes asserts that the storage size reported by the database
+// tracks the sessions and state updates that are inserted and deleted.
+func testStorageBytes(h *towerDBHarness) {
+	// A fresh database doesn't store any sessions or updates.
+	h.assertStorageBytes(0)
+
+	id0 := id(0)
+	session := &wtdb.SessionInfo{
+		ID: *id0,
+		Policy: wtpolicy.Policy{
+			TxPolicy: wtpolicy.TxPolicy{
+				BlobType:     blob.TypeAltruistCommit,
+				SweepFeeRate: wtpolicy.DefaultSweepFeeRate,
+			},
+			MaxUpdates: 3,
+		},
+		RewardAddress: []byte{},
+	}
+	h.insertSession(session, nil)
+
+	var b bytes.Buffer
+	if err := session.Encode(&b); err != nil {
+		h.t.Fatalf("unable to encode session: %v", err)
+	}
+	h.assertStorageBytes(uint64(b.Len()))
+
+	// Inserting an update should account for the encoded update on top of
+	// the session.
+	update := updateFromInt(id0, 1, 0)
+	h.insertUpdate(update, nil)
+
+	if err := update.Encode(&b); err != nil {
+		h.t.Fatalf("unable to encode update: %v", err)
+	}
+	h.assertStorageBytes(uint64(b.Len()))
+
+	// Deleting the session removes both the session and its update.
+	h.deleteSession(*id0, nil)
+	h.assertStorageBytes(0)
+}
+
 type stateUpdateTest struct {
 	session    *wtdb.SessionInfo
 	sessionErr error
@@ -696,6 +802,49 @@ func TestTowerDB(t *testing.T) {
 				return db
 			},
 		},
+		{
+			name: "migrated boltdb",
+			init: func(t *testing.T) watchtower.DB {
+				src, err := wtdb.NewBoltBackendCreator(
+					true, t.TempDir(), "watchtower.db",
+				)(dbCfg)
+				if err != nil {
+					t.Fatalf("unable to open db: %v", err)
+				}
+
+				srcDB, err := wtdb.OpenTowerDB(src)
+				if err != nil {
+					t.Fatalf("unable to open db: %v", err)
+				}
+
+				// Migrate the initialized database into a new
+				// backend, ensuring all buckets are carried
+				// over.
+				dst, err := wtdb.NewBoltBackendCreator(
+					true, t.TempDir(), "watchtower.db",
+				)(dbCfg)
+				if err != nil {
+					t.Fatalf("unable to open db: %v", err)
+				}
+
+				err = wtdb.MigrateBackend(src, dst)
+				if err != nil {
+					t.Fatalf("unable to migrate: %v", err)
+				}
+				srcDB.Close()
+
+				db, err := wtdb.OpenTowerDB(dst)
+				if err != nil {
+					t.Fatalf("unable to open db: %v", err)
+				}
+
+				t.Cleanup(func() {
+					db.Close()
+				})
+
+				return db
+			},
+		},
 		{
 			name: "mock",
 			init: func(t *testing.T) watchtower.DB {
@@ -764,6 +913,14 @@ func TestTowerDB(t *testing.T) {
 			name: "lookout tip",
 			run:  testLookoutTip,
 		},
+		{
+			name: "storage bytes",
+			run:  testStorageBytes,
+		},
+		{
+			name: "justice anchors",
+			run:  testJusticeAnchors,
+		},
 	}
 
 	for _, database := range dbs {
@@ -782,6 +939,60 @@ func TestTowerDB(t *testing.T) {
 	}
 }
 
+// TestTowerDBStorageBytesReopen asserts that the storage size tracked by the
+// tower database is restored when the database is opened again.
+func TestTowerDBStorageBytesReopen(t *testing.T) {
+	t.Parallel()
+
+	dbCfg := &kvdb.BoltConfig{DBTimeout: kvdb.DefaultDBTimeout}
+	path := t.TempDir()
+
+	openDB := func() *wtdb.TowerDB {
+		bdb, err := wtdb.NewBoltBackendCreator(
+			true, path, "watchtower.db",
+		)(dbCfg)
+		require.NoError(t, err)
+
+		db, err := wtdb.OpenTowerDB(bdb)
+		require.NoError(t, err)
+
+		return db
+	}
+
+	db := openDB()
+
+	id0 := id(0)
+	err := db.InsertSessionInfo(&wtdb.SessionInfo{
+		ID: *id0,
+		Policy: wtpolicy.Policy{
+			TxPolicy: wtpolicy.TxPolicy{
+				BlobType:     blob.TypeAltruistCommit,
+				SweepFeeRate: wtpolicy.DefaultSweepFeeRate,
+			},
+			MaxUpdates: 3,
+		},
+		RewardAddress: []byte{},
+	})
+	require.NoError(t, err)
+
+	_, err = db.InsertStateUpdate(updateFromInt(id0, 1, 0))
+	require.NoError(t, err)
+
+	numBytes, err := db.StorageBytes()
+	require.NoError(t, err)
+	require.NotZero(t, numBytes)
+	require.NoError(t, db.Close())
+
+	db = openDB()
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	reopenedBytes, err := db.StorageBytes()
+	require.NoError(t, err)
+	require.Equal(t, numBytes, reopenedBytes)
+}
+
 // id creates a session id from an integer.
 func id(i int) *wtdb.SessionID {
 	var id wtdb.SessionID
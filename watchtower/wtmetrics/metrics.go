@@ -0,0 +1,98 @@
+package wtmetrics
+
+import (
+	"time"
+
+	"github.com/lightningnetwork/lnd/watchtower/wtwire"
+)
+
+// Limit identifies one of the limits a watchtower server enforces on each of
+// its clients.
+type Limit string
+
+const (
+	// LimitUpdateRate limits the rate at which a client sends state
+	// updates.
+	LimitUpdateRate Limit = "update_rate"
+
+	// LimitStorage limits the total size of the encrypted blobs a session
+	// may store.
+	LimitStorage Limit = "storage"
+)
+
+// Recorder records metrics about the operation of a watchtower server, so
+// that tower operators can monitor the load on their tower and the justice
+// transactions it broadcasts on behalf of its clients.
+type Recorder interface {
+	// SessionCreated records that a new session was negotiated with a
+	// client.
+	SessionCreated()
+
+	// UpdateStored records that a state update with an encrypted blob of
+	// the given size was accepted and persisted.
+	UpdateStored(blobSize int)
+
+	// JusticeBroadcast records that a justice transaction was published
+	// on behalf of a client.
+	JusticeBroadcast()
+
+	// MessageProcessed records the time it took to process a message of
+	// the given type received from a client, including sending the reply.
+	MessageProcessed(msgType wtwire.MessageType, latency time.Duration)
+
+	// LimitExceeded records that a request of a client was rejected
+	// because it exceeded the given limit.
+	LimitExceeded(limit Limit)
+
+	// ClientBanned records that a client was banned after exceeding its
+	// limits too often.
+	ClientBanned()
+
+	// BannedConnRejected records that a connection of a banned client was
+	// rejected.
+	BannedConnRejected()
+}
+
+// NoOpRecorder is a Recorder that discards all metrics. It is used if the
+// metrics of the tower aren't exported.
+type NoOpRecorder struct{}
+
+// A compile-time assertion to ensure NoOpRecorder implements the Recorder
+// interface.
+var _ Recorder = (*NoOpRecorder)(nil)
+
+// SessionCreated records that a new session was negotiated with a client.
+//
+// NOTE: This is part of the Recorder interface.
+func (NoOpRecorder) SessionCreated() {}
+
+// UpdateStored records that a state update was accepted and persisted.
+//
+// NOTE: This is part of the Recorder interface.
+func (NoOpRecorder) UpdateStored(int) {}
+
+// JusticeBroadcast records that a justice transaction was published.
+//
+// NOTE: This is part of the Recorder interface.
+func (NoOpRecorder) JusticeBroadcast() {}
+
+// MessageProcessed records the time it took to process a client message.
+//
+// NOTE: This is part of the Recorder interface.
+func (NoOpRecorder) MessageProcessed(wtwire.MessageType, time.Duration) {}
+
+// LimitExceeded records that a client request exceeded a limit.
+//
+// NOTE: This is part of the Recorder interface.
+func (NoOpRecorder) LimitExceeded(Limit) {}
+
+// ClientBanned records that a client was banned.
+//
+// NOTE: This is part of the Recorder interface.
+func (NoOpRecorder) ClientBanned() {}
+
+// BannedConnRejected records that a connection of a banned client was
+// rejected.
+//
+// NOTE: This is part of the Recorder interface.
+func (NoOpRecorder) BannedConnRejected() {}
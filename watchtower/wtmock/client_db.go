@@ -1,7 +1,9 @@
 package wtmock
 
 import (
+	"bytes"
 	"net"
+	"sort"
 	"sync"
 	"sync/atomic"
 
@@ -25,6 +27,8 @@ type ClientDB struct {
 
 	mu             sync.Mutex
 	summaries      map[lnwire.ChannelID]wtdb.ClientChanSummary
+	closedChans    map[lnwire.ChannelID]struct{}
+	sweepPkScript  []byte
 	activeSessions map[wtdb.SessionID]wtdb.ClientSession
 	towerIndex     map[towerPK]wtdb.TowerID
 	towers         map[wtdb.TowerID]*wtdb.Tower
@@ -38,6 +42,7 @@ type ClientDB struct {
 func NewClientDB() *ClientDB {
 	return &ClientDB{
 		summaries:      make(map[lnwire.ChannelID]wtdb.ClientChanSummary),
+		closedChans:    make(map[lnwire.ChannelID]struct{}),
 		activeSessions: make(map[wtdb.SessionID]wtdb.ClientSession),
 		towerIndex:     make(map[towerPK]wtdb.TowerID),
 		towers:         make(map[wtdb.TowerID]*wtdb.Tower),
@@ -425,6 +430,36 @@ func (m *ClientDB) FetchChanSummaries() (wtdb.ChannelSummaries, error) {
 	return summaries, nil
 }
 
+// FetchChanBackups returns a record for every revoked state of the given
+// channel that was acknowledged by a tower, sorted by commit height.
+func (m *ClientDB) FetchChanBackups(
+	chanID lnwire.ChannelID) ([]wtdb.ChanBackup, error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var backups []wtdb.ChanBackup
+	for id, session := range m.activeSessions {
+		for _, backupID := range session.AckedUpdates {
+			if backupID.ChanID != chanID {
+				continue
+			}
+
+			backups = append(backups, wtdb.ChanBackup{
+				CommitHeight: backupID.CommitHeight,
+				SessionID:    id,
+				TowerID:      session.TowerID,
+			})
+		}
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CommitHeight < backups[j].CommitHeight
+	})
+
+	return backups, nil
+}
+
 // RegisterChannel registers a channel for use within the client database. For
 // now, all that is stored in the channel summary is the sweep pkscript that
 // we'd like any tower sweeps to pay into. In the future, this will be extended
@@ -447,6 +482,96 @@ func (m *ClientDB) RegisterChannel(chanID lnwire.ChannelID,
 	return nil
 }
 
+// UpdateChannelSweepPkScript replaces the sweep pkscript of a registered
+// channel.
+func (m *ClientDB) UpdateChannelSweepPkScript(chanID lnwire.ChannelID,
+	sweepPkScript []byte) error {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.summaries[chanID]; !ok {
+		return wtdb.ErrChannelNotRegistered
+	}
+
+	m.summaries[chanID] = wtdb.ClientChanSummary{
+		SweepPkScript: cloneBytes(sweepPkScript),
+	}
+
+	return nil
+}
+
+// MarkChannelClosed records that the registered channel was cooperatively
+// closed.
+func (m *ClientDB) MarkChannelClosed(chanID lnwire.ChannelID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.summaries[chanID]; !ok {
+		return wtdb.ErrChannelNotRegistered
+	}
+
+	m.closedChans[chanID] = struct{}{}
+
+	return nil
+}
+
+// FetchClosedChannels returns the set of registered channels that were marked
+// as closed.
+func (m *ClientDB) FetchClosedChannels() (map[lnwire.ChannelID]struct{},
+	error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	closedChans := make(map[lnwire.ChannelID]struct{}, len(m.closedChans))
+	for chanID := range m.closedChans {
+		closedChans[chanID] = struct{}{}
+	}
+
+	return closedChans, nil
+}
+
+// DeleteSession removes the client session with the given id, unless it still
+// has unacked updates.
+func (m *ClientDB) DeleteSession(id wtdb.SessionID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.activeSessions[id]
+	if !ok {
+		return wtdb.ErrClientSessionNotFound
+	}
+	if len(session.CommittedUpdates) > 0 {
+		return wtdb.ErrSessionHasUnackedUpdates
+	}
+
+	delete(m.activeSessions, id)
+
+	return nil
+}
+
+// RatchetSweepPkScript persists the given sweep pkscript, unless one was
+// persisted before, and returns the persisted sweep pkscript.
+func (m *ClientDB) RatchetSweepPkScript(sweepPkScript []byte) ([]byte,
+	error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch {
+	case len(m.sweepPkScript) > 0 && len(sweepPkScript) > 0 &&
+		!bytes.Equal(m.sweepPkScript, sweepPkScript):
+
+		return nil, wtdb.ErrSweepPkScriptMismatch
+
+	case len(m.sweepPkScript) == 0 && len(sweepPkScript) > 0:
+		m.sweepPkScript = cloneBytes(sweepPkScript)
+	}
+
+	return cloneBytes(m.sweepPkScript), nil
+}
+
 func cloneBytes(b []byte) []byte {
 	if b == nil {
 		return nil
@@ -1,8 +1,10 @@
 package wtmock
 
 import (
+	"bytes"
 	"sync"
 
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/watchtower/blob"
 	"github.com/lightningnetwork/lnd/watchtower/wtdb"
@@ -14,6 +16,7 @@ type TowerDB struct {
 	lastEpoch *chainntnfs.BlockEpoch
 	sessions  map[wtdb.SessionID]*wtdb.SessionInfo
 	blobs     map[blob.BreachHint]map[wtdb.SessionID]*wtdb.SessionStateUpdate
+	anchors   map[wire.OutPoint]*wtdb.JusticeAnchor
 }
 
 // NewTowerDB initializes a fresh mock TowerDB.
@@ -21,6 +24,7 @@ func NewTowerDB() *TowerDB {
 	return &TowerDB{
 		sessions: make(map[wtdb.SessionID]*wtdb.SessionInfo),
 		blobs:    make(map[blob.BreachHint]map[wtdb.SessionID]*wtdb.SessionStateUpdate),
+		anchors:  make(map[wire.OutPoint]*wtdb.JusticeAnchor),
 	}
 }
 
@@ -156,6 +160,30 @@ func (db *TowerDB) QueryMatches(
 	return matches, nil
 }
 
+// StorageBytes returns the number of bytes occupied by the encoded sessions
+// and state updates currently stored by the tower.
+func (db *TowerDB) StorageBytes() (uint64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	var b bytes.Buffer
+	for _, info := range db.sessions {
+		if err := info.Encode(&b); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, sessionUpdates := range db.blobs {
+		for _, update := range sessionUpdates {
+			if err := update.Encode(&b); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return uint64(b.Len()), nil
+}
+
 // SetLookoutTip stores the provided epoch as the latest lookout tip epoch in
 // the tower database.
 func (db *TowerDB) SetLookoutTip(epoch *chainntnfs.BlockEpoch) error {
@@ -171,3 +199,42 @@ func (db *TowerDB) GetLookoutTip() (*chainntnfs.BlockEpoch, error) {
 
 	return db.lastEpoch, nil
 }
+
+// PutJusticeAnchor stores the anchor output of a published justice transaction
+// whose fee the tower bumps.
+func (db *TowerDB) PutJusticeAnchor(anchor *wtdb.JusticeAnchor) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.anchors[anchor.OutPoint] = anchor
+
+	return nil
+}
+
+// DeleteJusticeAnchor removes the anchor output with the given outpoint.
+// ErrJusticeAnchorNotFound is returned if the anchor output isn't stored.
+func (db *TowerDB) DeleteJusticeAnchor(outPoint *wire.OutPoint) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.anchors[*outPoint]; !ok {
+		return wtdb.ErrJusticeAnchorNotFound
+	}
+	delete(db.anchors, *outPoint)
+
+	return nil
+}
+
+// ListJusticeAnchors returns the anchor outputs of all published justice
+// transactions whose fee the tower bumps.
+func (db *TowerDB) ListJusticeAnchors() ([]*wtdb.JusticeAnchor, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	anchors := make([]*wtdb.JusticeAnchor, 0, len(db.anchors))
+	for _, anchor := range db.anchors {
+		anchors = append(anchors, anchor)
+	}
+
+	return anchors, nil
+}
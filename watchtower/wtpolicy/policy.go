@@ -3,6 +3,7 @@ package wtpolicy
 import (
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/wire"
@@ -21,6 +22,11 @@ const (
 	// can send to the tower in a single session.
 	DefaultMaxUpdates = 1024
 
+	// MaxSessionUpdates is the highest number of updates a client may
+	// negotiate for a single session. It keeps the encrypted blobs a tower
+	// stores for a session within a few megabytes.
+	MaxSessionUpdates = 16384
+
 	// DefaultRewardRate specifies the fraction of the channel that the
 	// tower takes if it successfully sweeps a breach. The value is
 	// expressed in millionths of the channel capacity.
@@ -33,8 +39,27 @@ const (
 	// MinSweepFeeRate is the minimum sweep fee rate a client may use in its
 	// policy, the current value is 4 sat/vbyte.
 	MinSweepFeeRate = chainfee.SatPerKWeight(1000)
+
+	// DefaultSweepFeePercentile is the percentile of the tower's fee
+	// estimates that dynamic fee sessions use by default to choose the
+	// sweep fee rate at breach time.
+	DefaultSweepFeePercentile = 50
+
+	// MaxSweepFeePercentile is the highest percentile a dynamic fee
+	// session may use.
+	MaxSweepFeePercentile = 100
+
+	// CPFPAnchorAmount is the value of the anchor output that justice
+	// transactions of sessions with a CPFP anchor pay to the tower. It
+	// matches the value of the anchor outputs of commitment transactions.
+	CPFPAnchorAmount = btcutil.Amount(330)
 )
 
+// SweepFeeConfTargets are the confirmation targets for which the tower queries
+// its fee estimator when choosing the sweep fee rate of a dynamic fee session.
+// The session's percentile is taken over the resulting fee rates.
+var SweepFeeConfTargets = []uint32{1, 2, 3, 6, 12, 25, 144}
+
 var (
 	// ErrFeeExceedsInputs signals that the total input value of breaching
 	// commitment txn is insufficient to cover the fees required to sweep
@@ -59,6 +84,28 @@ var (
 	// ErrSweepFeeRateTooLow signals that the policy's fee rate is too low
 	// to get into the mempool during low congestion.
 	ErrSweepFeeRateTooLow = errors.New("sweep fee rate too low")
+
+	// ErrStaticFeeDynamicParams signals that the policy is invalid because
+	// it contains a non-zero MaxSweepFeeRate or SweepFeePercentile on a
+	// policy without dynamic fees.
+	ErrStaticFeeDynamicParams = errors.New("static fee policy has " +
+		"dynamic fee params")
+
+	// ErrInvalidSweepFeeBounds signals that the policy's maximum sweep fee
+	// rate is below its minimum sweep fee rate.
+	ErrInvalidSweepFeeBounds = errors.New("max sweep fee rate below " +
+		"sweep fee rate")
+
+	// ErrInvalidSweepFeePercentile signals that the policy's sweep fee
+	// percentile is not within [1, 100].
+	ErrInvalidSweepFeePercentile = errors.New("sweep fee percentile must " +
+		"be between 1 and 100")
+
+	// ErrCPFPAnchorNotAnchorChannel signals that the policy is invalid
+	// because it adds a CPFP anchor to the justice transactions of a
+	// session that isn't for anchor channels.
+	ErrCPFPAnchorNotAnchorChannel = errors.New("cpfp anchor requires " +
+		"anchor channel policy")
 )
 
 // DefaultPolicy returns a Policy containing the default parameters that can be
@@ -98,7 +145,25 @@ type TxPolicy struct {
 	// constructing the justice transaction. All sweep transactions created
 	// for this session must use this value during construction, and the
 	// signatures must implicitly commit to the resulting output values.
+	// For dynamic fee sessions, this is the lowest fee rate the tower may
+	// choose.
 	SweepFeeRate chainfee.SatPerKWeight
+
+	// MaxSweepFeeRate is the highest fee rate the tower may choose for the
+	// justice transaction of a dynamic fee session. The client signs
+	// justice transactions for a ladder of fee rates between SweepFeeRate
+	// and MaxSweepFeeRate.
+	//
+	// NOTE: This value is only used if BlobType has blob.FlagDynamicFee.
+	MaxSweepFeeRate chainfee.SatPerKWeight
+
+	// SweepFeePercentile is the percentile of the tower's fee estimates at
+	// the time of the breach that a dynamic fee session targets. The tower
+	// picks the lowest fee rate of the ladder that is at least the
+	// targeted fee rate.
+	//
+	// NOTE: This value is only used if BlobType has blob.FlagDynamicFee.
+	SweepFeePercentile uint8
 }
 
 // Policy defines the negotiated parameters for a session between a client and
@@ -115,6 +180,14 @@ type Policy struct {
 
 // String returns a human-readable description of the current policy.
 func (p Policy) String() string {
+	if p.HasDynamicFee() {
+		return fmt.Sprintf("(blob-type=%b max-updates=%d "+
+			"reward-rate=%d sweep-fee-rate=%d "+
+			"max-sweep-fee-rate=%d sweep-fee-percentile=%d)",
+			p.BlobType, p.MaxUpdates, p.RewardRate, p.SweepFeeRate,
+			p.MaxSweepFeeRate, p.SweepFeePercentile)
+	}
+
 	return fmt.Sprintf("(blob-type=%b max-updates=%d reward-rate=%d "+
 		"sweep-fee-rate=%d)", p.BlobType, p.MaxUpdates, p.RewardRate,
 		p.SweepFeeRate)
@@ -125,6 +198,85 @@ func (p Policy) IsAnchorChannel() bool {
 	return p.TxPolicy.BlobType.IsAnchorChannel()
 }
 
+// HasDynamicFee returns true if the tower chooses the sweep fee rate of the
+// session's justice transactions at breach time.
+func (p Policy) HasDynamicFee() bool {
+	return p.TxPolicy.BlobType.HasDynamicFee()
+}
+
+// HasCPFPAnchor returns true if the session's justice transactions contain an
+// anchor output that allows the tower to bump their fee using CPFP.
+func (p Policy) HasCPFPAnchor() bool {
+	return p.TxPolicy.BlobType.HasCPFPAnchor()
+}
+
+// SweepFeeRates returns the fee rates for which justice transactions are
+// signed under the policy, in increasing order. Policies without dynamic fees
+// only have SweepFeeRate, while dynamic fee policies have a ladder of
+// blob.NumDynamicFeeRates evenly spaced fee rates from SweepFeeRate to
+// MaxSweepFeeRate.
+func (p Policy) SweepFeeRates() []chainfee.SatPerKWeight {
+	if !p.HasDynamicFee() {
+		return []chainfee.SatPerKWeight{p.SweepFeeRate}
+	}
+
+	const steps = blob.NumDynamicFeeRates - 1
+
+	feeRates := make([]chainfee.SatPerKWeight, blob.NumDynamicFeeRates)
+	feeRange := p.MaxSweepFeeRate - p.SweepFeeRate
+	for i := range feeRates {
+		step := chainfee.SatPerKWeight(i)
+		feeRates[i] = p.SweepFeeRate + feeRange*step/steps
+	}
+
+	return feeRates
+}
+
+// EstimateSweepFeeRate queries the estimator for each of the
+// SweepFeeConfTargets and returns the fee rate at the policy's
+// SweepFeePercentile of the estimates.
+func (p Policy) EstimateSweepFeeRate(
+	estimator chainfee.Estimator) (chainfee.SatPerKWeight, error) {
+
+	estimates := make([]chainfee.SatPerKWeight, 0, len(SweepFeeConfTargets))
+	for _, target := range SweepFeeConfTargets {
+		feeRate, err := estimator.EstimateFeePerKW(target)
+		if err != nil {
+			return 0, err
+		}
+
+		estimates = append(estimates, feeRate)
+	}
+
+	sort.Slice(estimates, func(i, j int) bool {
+		return estimates[i] < estimates[j]
+	})
+
+	// Use the nearest-rank method, so that the 100th percentile is the
+	// highest estimate.
+	rank := (int(p.SweepFeePercentile)*len(estimates) +
+		MaxSweepFeePercentile - 1) / MaxSweepFeePercentile
+	if rank < 1 {
+		rank = 1
+	}
+
+	return estimates[rank-1], nil
+}
+
+// SweepFeeRateIndex returns the index into SweepFeeRates of the fee rate the
+// justice transaction should use to pay at least the target fee rate. If the
+// target exceeds all of the policy's fee rates, the highest one is used.
+func (p Policy) SweepFeeRateIndex(target chainfee.SatPerKWeight) int {
+	feeRates := p.SweepFeeRates()
+	for i, feeRate := range feeRates {
+		if feeRate >= target {
+			return i
+		}
+	}
+
+	return len(feeRates) - 1
+}
+
 // Validate ensures that the policy satisfies some minimal correctness
 // constraints.
 func (p Policy) Validate() error {
@@ -141,23 +293,50 @@ func (p Policy) Validate() error {
 		return ErrNoMaxUpdates
 	}
 
+	// A CPFP anchor is only supported for anchor channels.
+	if p.HasCPFPAnchor() && !p.IsAnchorChannel() {
+		return ErrCPFPAnchorNotAnchorChannel
+	}
+
 	// SweepFeeRate must be sane enough to get in the mempool during low
 	// congestion.
 	if p.SweepFeeRate < MinSweepFeeRate {
 		return ErrSweepFeeRateTooLow
 	}
 
+	// MaxSweepFeeRate and SweepFeePercentile should not be set if the
+	// policy doesn't have dynamic fees.
+	if !p.HasDynamicFee() {
+		if p.MaxSweepFeeRate != 0 || p.SweepFeePercentile != 0 {
+			return ErrStaticFeeDynamicParams
+		}
+
+		return nil
+	}
+
+	// The fee ladder of a dynamic fee policy must not be empty.
+	if p.MaxSweepFeeRate < p.SweepFeeRate {
+		return ErrInvalidSweepFeeBounds
+	}
+
+	if p.SweepFeePercentile == 0 ||
+		p.SweepFeePercentile > MaxSweepFeePercentile {
+
+		return ErrInvalidSweepFeePercentile
+	}
+
 	return nil
 }
 
 // ComputeAltruistOutput computes the lone output value of a justice transaction
 // that pays no reward to the tower. The value is computed using the weight of
 // of the justice transaction and subtracting an amount that satisfies the
-// policy's fee rate.
+// given fee rate.
 func (p *Policy) ComputeAltruistOutput(totalAmt btcutil.Amount,
-	txWeight int64, sweepScript []byte) (btcutil.Amount, error) {
+	txWeight int64, feeRate chainfee.SatPerKWeight,
+	sweepScript []byte) (btcutil.Amount, error) {
 
-	txFee := p.SweepFeeRate.FeeForWeight(txWeight)
+	txFee := feeRate.FeeForWeight(txWeight)
 	if txFee > totalAmt {
 		return 0, ErrFeeExceedsInputs
 	}
@@ -174,14 +353,14 @@ func (p *Policy) ComputeAltruistOutput(totalAmt btcutil.Amount,
 }
 
 // ComputeRewardOutputs splits the total funds in a breaching commitment
-// transaction between the victim and the tower, according to the sweep fee rate
-// and reward rate. The reward to he tower is subtracted first, before
-// splitting the remaining balance amongst the victim and fees.
+// transaction between the victim and the tower, according to the given sweep
+// fee rate and the reward rate. The reward to he tower is subtracted first,
+// before splitting the remaining balance amongst the victim and fees.
 func (p *Policy) ComputeRewardOutputs(totalAmt btcutil.Amount,
-	txWeight int64,
+	txWeight int64, feeRate chainfee.SatPerKWeight,
 	rewardScript []byte) (btcutil.Amount, btcutil.Amount, error) {
 
-	txFee := p.SweepFeeRate.FeeForWeight(txWeight)
+	txFee := feeRate.FeeForWeight(txWeight)
 	if txFee > totalAmt {
 		return 0, 0, ErrFeeExceedsInputs
 	}
@@ -230,7 +409,8 @@ func ComputeRewardAmount(total btcutil.Amount, base, rate uint32) btcutil.Amount
 }
 
 // ComputeJusticeTxOuts constructs the justice transaction outputs for the
-// given policy. If the policy specifies a reward for the tower, there will be
+// given policy at the given sweep fee rate, which must be one of the policy's
+// SweepFeeRates. If the policy specifies a reward for the tower, there will be
 // two outputs paying to the victim and the tower. Otherwise there will be a
 // single output sweeping funds back to the victim. The totalAmt should be the
 // sum of any inputs used in the transaction. The passed txWeight should
@@ -239,12 +419,29 @@ func ComputeRewardAmount(total btcutil.Amount, base, rate uint32) btcutil.Amount
 // should be the pkScript of the victim to which funds will be recovered. The
 // rewardPkScript is the pkScript of the tower where its reward will be
 // deposited, and will be
-// ignored if the blob type does not specify a reward.
+// ignored if the blob type does not specify a reward. If the policy has a
+// CPFP anchor, an additional output of CPFPAnchorAmount pays to the
+// rewardPkScript, and its value is taken from the funds of the victim.
 func (p *Policy) ComputeJusticeTxOuts(totalAmt btcutil.Amount, txWeight int64,
-	sweepPkScript, rewardPkScript []byte) ([]*wire.TxOut, error) {
+	feeRate chainfee.SatPerKWeight, sweepPkScript,
+	rewardPkScript []byte) ([]*wire.TxOut, error) {
 
 	var outputs []*wire.TxOut
 
+	// The anchor output of the tower is funded before the remaining funds
+	// are split.
+	if p.HasCPFPAnchor() {
+		if CPFPAnchorAmount > totalAmt {
+			return nil, ErrFeeExceedsInputs
+		}
+		totalAmt -= CPFPAnchorAmount
+
+		outputs = append(outputs, &wire.TxOut{
+			PkScript: rewardPkScript,
+			Value:    int64(CPFPAnchorAmount),
+		})
+	}
+
 	// If the policy specifies a reward for the tower, compute a split of
 	// the funds based on the policy's parameters. Otherwise, we will use
 	// the altruist output computation and sweep as much of the funds
@@ -258,7 +455,7 @@ func (p *Policy) ComputeJusticeTxOuts(totalAmt btcutil.Amount, txWeight int64,
 		// divided according to the pre negotiated reward rate from the
 		// client's session info.
 		sweepAmt, rewardAmt, err := p.ComputeRewardOutputs(
-			totalAmt, txWeight, rewardPkScript,
+			totalAmt, txWeight, feeRate, rewardPkScript,
 		)
 		if err != nil {
 			return nil, err
@@ -279,7 +476,7 @@ func (p *Policy) ComputeJusticeTxOuts(totalAmt btcutil.Amount, txWeight int64,
 		// returned to the victim. To do so, the required transaction
 		// fee is subtracted from the total input amount.
 		sweepAmt, err := p.ComputeAltruistOutput(
-			totalAmt, txWeight, sweepPkScript,
+			totalAmt, txWeight, feeRate, sweepPkScript,
 		)
 		if err != nil {
 			return nil, err
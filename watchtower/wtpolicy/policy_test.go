@@ -3,11 +3,17 @@ package wtpolicy_test
 import (
 	"testing"
 
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/watchtower/blob"
 	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
 	"github.com/stretchr/testify/require"
 )
 
+const dynamicFeeType = blob.TypeAltruistDynamicFeeCommit
+
 var validationTests = []struct {
 	name   string
 	policy wtpolicy.Policy
@@ -76,6 +82,90 @@ var validationTests = []struct {
 		name:   "valid default policy",
 		policy: wtpolicy.DefaultPolicy(),
 	},
+	{
+		name: "fail static fee with max sweep fee rate",
+		policy: wtpolicy.Policy{
+			TxPolicy: wtpolicy.TxPolicy{
+				BlobType:        blob.TypeAltruistCommit,
+				SweepFeeRate:    wtpolicy.DefaultSweepFeeRate,
+				MaxSweepFeeRate: wtpolicy.DefaultSweepFeeRate,
+			},
+			MaxUpdates: 1,
+		},
+		expErr: wtpolicy.ErrStaticFeeDynamicParams,
+	},
+	{
+		name: "fail dynamic fee with max below min",
+		policy: wtpolicy.Policy{
+			TxPolicy: wtpolicy.TxPolicy{
+				BlobType:           dynamicFeeType,
+				SweepFeeRate:       wtpolicy.DefaultSweepFeeRate,
+				MaxSweepFeeRate:    wtpolicy.MinSweepFeeRate,
+				SweepFeePercentile: 50,
+			},
+			MaxUpdates: 1,
+		},
+		expErr: wtpolicy.ErrInvalidSweepFeeBounds,
+	},
+	{
+		name: "fail dynamic fee without percentile",
+		policy: wtpolicy.Policy{
+			TxPolicy: wtpolicy.TxPolicy{
+				BlobType:        dynamicFeeType,
+				SweepFeeRate:    wtpolicy.MinSweepFeeRate,
+				MaxSweepFeeRate: wtpolicy.DefaultSweepFeeRate,
+			},
+			MaxUpdates: 1,
+		},
+		expErr: wtpolicy.ErrInvalidSweepFeePercentile,
+	},
+	{
+		name: "fail dynamic fee with percentile above 100",
+		policy: wtpolicy.Policy{
+			TxPolicy: wtpolicy.TxPolicy{
+				BlobType:           dynamicFeeType,
+				SweepFeeRate:       wtpolicy.MinSweepFeeRate,
+				MaxSweepFeeRate:    wtpolicy.DefaultSweepFeeRate,
+				SweepFeePercentile: 101,
+			},
+			MaxUpdates: 1,
+		},
+		expErr: wtpolicy.ErrInvalidSweepFeePercentile,
+	},
+	{
+		name: "valid dynamic fee policy",
+		policy: wtpolicy.Policy{
+			TxPolicy: wtpolicy.TxPolicy{
+				BlobType:           dynamicFeeType,
+				SweepFeeRate:       wtpolicy.MinSweepFeeRate,
+				MaxSweepFeeRate:    wtpolicy.DefaultSweepFeeRate,
+				SweepFeePercentile: 100,
+			},
+			MaxUpdates: 1,
+		},
+	},
+	{
+		name: "fail cpfp anchor without anchor channel",
+		policy: wtpolicy.Policy{
+			TxPolicy: wtpolicy.TxPolicy{
+				BlobType: blob.TypeAltruistCommit |
+					blob.Type(blob.FlagCPFPAnchor),
+				SweepFeeRate: wtpolicy.DefaultSweepFeeRate,
+			},
+			MaxUpdates: 1,
+		},
+		expErr: wtpolicy.ErrCPFPAnchorNotAnchorChannel,
+	},
+	{
+		name: "valid cpfp anchor policy",
+		policy: wtpolicy.Policy{
+			TxPolicy: wtpolicy.TxPolicy{
+				BlobType:     blob.TypeAltruistAnchorCPFPCommit,
+				SweepFeeRate: wtpolicy.DefaultSweepFeeRate,
+			},
+			MaxUpdates: 1,
+		},
+	},
 }
 
 // TestPolicyValidate asserts that the sanity checks for policies behave as
@@ -110,3 +200,131 @@ func TestPolicyIsAnchorChannel(t *testing.T) {
 	}
 	require.Equal(t, true, policyAnchor.IsAnchorChannel())
 }
+
+// confTargetEstimator is a fee estimator that returns a fixed fee rate per
+// confirmation target.
+type confTargetEstimator struct {
+	chainfee.Estimator
+
+	feeRates map[uint32]chainfee.SatPerKWeight
+}
+
+// EstimateFeePerKW returns the fee rate of the given confirmation target.
+func (e *confTargetEstimator) EstimateFeePerKW(
+	numBlocks uint32) (chainfee.SatPerKWeight, error) {
+
+	return e.feeRates[numBlocks], nil
+}
+
+// TestPolicyDynamicFee asserts that dynamic fee policies produce the expected
+// fee ladder, and select the fee rate targeted by their percentile of the fee
+// estimates.
+func TestPolicyDynamicFee(t *testing.T) {
+	t.Parallel()
+
+	policy := wtpolicy.Policy{
+		TxPolicy: wtpolicy.TxPolicy{
+			BlobType:           dynamicFeeType,
+			SweepFeeRate:       1000,
+			MaxSweepFeeRate:    10000,
+			SweepFeePercentile: 50,
+		},
+	}
+
+	require.Equal(t, []chainfee.SatPerKWeight{1000, 4000, 7000, 10000},
+		policy.SweepFeeRates())
+
+	// A static fee policy only has a single fee rate.
+	staticPolicy := wtpolicy.DefaultPolicy()
+	require.Equal(
+		t, []chainfee.SatPerKWeight{wtpolicy.DefaultSweepFeeRate},
+		staticPolicy.SweepFeeRates(),
+	)
+	require.Equal(t, 0, staticPolicy.SweepFeeRateIndex(100000))
+
+	// The lowest fee rate paying at least the target is selected, capped
+	// at the highest fee rate.
+	require.Equal(t, 0, policy.SweepFeeRateIndex(500))
+	require.Equal(t, 0, policy.SweepFeeRateIndex(1000))
+	require.Equal(t, 1, policy.SweepFeeRateIndex(1001))
+	require.Equal(t, 2, policy.SweepFeeRateIndex(7000))
+	require.Equal(t, 3, policy.SweepFeeRateIndex(100000))
+
+	estimator := &confTargetEstimator{
+		feeRates: map[uint32]chainfee.SatPerKWeight{
+			1:   9000,
+			2:   8000,
+			3:   6000,
+			6:   5000,
+			12:  3000,
+			25:  2000,
+			144: 1000,
+		},
+	}
+
+	percentileTests := []struct {
+		percentile uint8
+		expFeeRate chainfee.SatPerKWeight
+	}{
+		{percentile: 1, expFeeRate: 1000},
+		{percentile: 50, expFeeRate: 5000},
+		{percentile: 75, expFeeRate: 8000},
+		{percentile: 100, expFeeRate: 9000},
+	}
+	for _, test := range percentileTests {
+		policy.SweepFeePercentile = test.percentile
+
+		feeRate, err := policy.EstimateSweepFeeRate(estimator)
+		require.NoError(t, err)
+		require.Equal(t, test.expFeeRate, feeRate)
+	}
+}
+
+// TestComputeJusticeTxOutsCPFPAnchor asserts that policies with a CPFP anchor
+// add an anchor output paying to the tower, which is funded from the victim's
+// output.
+func TestComputeJusticeTxOutsCPFPAnchor(t *testing.T) {
+	t.Parallel()
+
+	var (
+		totalAmt      = btcutil.Amount(100000)
+		txWeight      = int64(1000)
+		feeRate       = wtpolicy.DefaultSweepFeeRate
+		sweepPkScript = make([]byte, input.P2WPKHSize)
+		towerPkScript = make([]byte, input.P2WPKHSize)
+	)
+	towerPkScript[input.P2WPKHSize-1] = 1
+
+	policy := wtpolicy.Policy{
+		TxPolicy: wtpolicy.TxPolicy{
+			BlobType:     blob.TypeAltruistAnchorCommit,
+			SweepFeeRate: feeRate,
+		},
+	}
+	outputs, err := policy.ComputeJusticeTxOuts(
+		totalAmt, txWeight, feeRate, sweepPkScript, towerPkScript,
+	)
+	require.NoError(t, err)
+	require.Len(t, outputs, 1)
+	sweepAmt := outputs[0].Value
+
+	policy.BlobType = blob.TypeAltruistAnchorCPFPCommit
+	outputs, err = policy.ComputeJusticeTxOuts(
+		totalAmt, txWeight, feeRate, sweepPkScript, towerPkScript,
+	)
+	require.NoError(t, err)
+	require.Equal(t, []*wire.TxOut{{
+		PkScript: towerPkScript,
+		Value:    int64(wtpolicy.CPFPAnchorAmount),
+	}, {
+		PkScript: sweepPkScript,
+		Value:    sweepAmt - int64(wtpolicy.CPFPAnchorAmount),
+	}}, outputs)
+
+	// The anchor can't be funded if the inputs are below its value.
+	_, err = policy.ComputeJusticeTxOuts(
+		wtpolicy.CPFPAnchorAmount-1, txWeight, feeRate, sweepPkScript,
+		towerPkScript,
+	)
+	require.ErrorIs(t, err, wtpolicy.ErrFeeExceedsInputs)
+}
@@ -11,9 +11,10 @@ import (
 // handleCreateSession processes a CreateSession message from the peer, and returns
 // a CreateSessionReply in response. This method will only succeed if no existing
 // session info is known about the session id. If an existing session is found,
-// the reward address is returned in case the client lost our reply.
+// the reward address is returned in case the client lost our reply. The
+// requested blob type must be allowed by the features negotiated with the peer.
 func (s *Server) handleCreateSession(peer Peer, id *wtdb.SessionID,
-	req *wtwire.CreateSession) error {
+	req *wtwire.CreateSession, negotiation *wtwire.Negotiation) error {
 
 	// TODO(conner): validate accept against policy
 
@@ -54,6 +55,17 @@ func (s *Server) handleCreateSession(peer Peer, id *wtdb.SessionID,
 		)
 	}
 
+	// The peer must have negotiated the features the blob type relies on.
+	if !negotiation.AllowsBlobType(req.BlobType) {
+		log.Debugf("Rejecting CreateSession from %s, blob type %s "+
+			"not allowed by negotiated %v", id, req.BlobType,
+			negotiation)
+		return s.replyCreateSession(
+			peer, id, wtwire.CreateSessionCodeRejectBlobType, 0,
+			nil,
+		)
+	}
+
 	// If the request asks for a reward session and the tower has them
 	// disabled, we will reject the request.
 	if s.cfg.DisableReward && req.BlobType.Has(blob.FlagReward) {
@@ -65,13 +77,45 @@ func (s *Server) handleCreateSession(peer Peer, id *wtdb.SessionID,
 		)
 	}
 
+	// Dynamic fee sessions must specify a valid range of fee rates and
+	// percentile of our fee estimates to choose from at breach time.
+	maxPercentile := uint8(wtpolicy.MaxSweepFeePercentile)
+	if req.BlobType.HasDynamicFee() &&
+		(req.MaxSweepFeeRate < req.SweepFeeRate ||
+			req.SweepFeePercentile == 0 ||
+			req.SweepFeePercentile > maxPercentile) {
+
+		log.Debugf("Rejecting CreateSession from %s, invalid dynamic "+
+			"fee params: sweep-fee-rate=%v max-sweep-fee-rate=%v "+
+			"sweep-fee-percentile=%d", id, req.SweepFeeRate,
+			req.MaxSweepFeeRate, req.SweepFeePercentile)
+		return s.replyCreateSession(
+			peer, id, wtwire.CreateSessionCodeRejectSweepFeeRate,
+			0, nil,
+		)
+	}
+
+	// The encrypted blobs of the session must fit into the storage a
+	// session may take up, so we reject the requested maximum number of
+	// updates otherwise.
+	err = s.limiter.allowSession(id, req.MaxUpdates, req.BlobType)
+	if err != nil {
+		log.Debugf("Rejecting CreateSession from %s@%s: %v", id,
+			peer.RemoteAddr(), err)
+		return s.replyCreateSession(
+			peer, id, wtwire.CreateSessionCodeRejectMaxUpdates, 0,
+			nil,
+		)
+	}
+
 	// Now that we've established that this session does not exist in the
 	// database, retrieve the sweep address that will be given to the
 	// client. This address is to be included by the client when signing
 	// sweep transactions destined for this tower, if its negotiated output
-	// is not dust.
+	// is not dust. Sessions with a CPFP anchor pay the anchor output of
+	// their justice transactions to the same address.
 	var rewardScript []byte
-	if req.BlobType.Has(blob.FlagReward) {
+	if req.BlobType.Has(blob.FlagReward) || req.BlobType.HasCPFPAnchor() {
 		rewardAddress, err := s.cfg.NewAddress()
 		if err != nil {
 			log.Errorf("Unable to generate reward addr for %s: %v",
@@ -101,10 +145,12 @@ func (s *Server) handleCreateSession(peer Peer, id *wtdb.SessionID,
 		ID: *id,
 		Policy: wtpolicy.Policy{
 			TxPolicy: wtpolicy.TxPolicy{
-				BlobType:     req.BlobType,
-				RewardBase:   req.RewardBase,
-				RewardRate:   req.RewardRate,
-				SweepFeeRate: req.SweepFeeRate,
+				BlobType:           req.BlobType,
+				RewardBase:         req.RewardBase,
+				RewardRate:         req.RewardRate,
+				SweepFeeRate:       req.SweepFeeRate,
+				MaxSweepFeeRate:    req.MaxSweepFeeRate,
+				SweepFeePercentile: req.SweepFeePercentile,
 			},
 			MaxUpdates: req.MaxUpdates,
 		},
@@ -123,6 +169,8 @@ func (s *Server) handleCreateSession(peer Peer, id *wtdb.SessionID,
 
 	log.Infof("Accepted session for %s", id)
 
+	s.cfg.Metrics.SessionCreated()
+
 	return s.replyCreateSession(
 		peer, id, wtwire.CodeOK, 0, rewardScript,
 	)
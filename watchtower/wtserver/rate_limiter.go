@@ -0,0 +1,247 @@
+package wtserver
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/watchtower/blob"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/lightningnetwork/lnd/watchtower/wtmetrics"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// clientIdleTimeout is the time after which the rate limiter forgets
+	// a client that sent no requests and isn't banned.
+	clientIdleTimeout = 10 * time.Minute
+
+	// clientPruneInterval is the minimum time between two passes over the
+	// tracked clients to forget the idle ones.
+	clientPruneInterval = time.Minute
+)
+
+var (
+	// ErrUpdateRateExceeded signals that a client sent state updates
+	// faster than allowed.
+	ErrUpdateRateExceeded = errors.New("update rate exceeded")
+
+	// ErrStorageLimitExceeded signals that a client requested a session
+	// whose encrypted blobs could take up more storage than allowed.
+	ErrStorageLimitExceeded = errors.New("storage limit exceeded")
+)
+
+// RateLimits configures the limits the server enforces on each client to
+// protect its disk and CPU from abusive clients. A client is identified by the
+// key it connects with, which is also the ID of its session. Identifying
+// clients by their network address instead would let a single client behind
+// a shared address, like the Tor daemon of an onion tower or a NAT, get all
+// other clients behind it banned. As a key owns at most one session, the
+// number of sessions per key is inherently limited to one. A zero value
+// disables the respective limit.
+type RateLimits struct {
+	// MaxUpdateRate is the number of state updates per second a client
+	// may send on average. Bursts of up to one second of updates are
+	// allowed.
+	MaxUpdateRate float64
+
+	// MaxStoragePerSession is the total size in bytes of the encrypted
+	// blobs a session may store. It is enforced when the session is
+	// created, based on the maximum number of updates the session asks
+	// for, so it holds across restarts of the server.
+	MaxStoragePerSession uint64
+
+	// MaxViolations is the number of times a client may exceed its limits
+	// before it is banned.
+	MaxViolations uint32
+
+	// BanDuration is the time for which a banned client's connections are
+	// rejected.
+	BanDuration time.Duration
+}
+
+// enabled returns whether any of the limits is enforced.
+func (l *RateLimits) enabled() bool {
+	return l.MaxUpdateRate > 0 || l.MaxStoragePerSession > 0
+}
+
+// clientUsage tracks the requests of a single client.
+type clientUsage struct {
+	// updates limits the rate of the client's state updates.
+	updates *rate.Limiter
+
+	// violations is the number of times the client exceeded its limits
+	// since it was last banned.
+	violations uint32
+
+	// bannedUntil is the time until which the client is banned.
+	bannedUntil time.Time
+
+	// lastSeen is the time of the client's last request.
+	lastSeen time.Time
+}
+
+// rateLimiter enforces the RateLimits on the clients of the server and keeps
+// the list of banned clients.
+type rateLimiter struct {
+	limits  RateLimits
+	metrics wtmetrics.Recorder
+
+	// now returns the current time, it is replaced in tests.
+	now func() time.Time
+
+	mu        sync.Mutex
+	clients   map[wtdb.SessionID]*clientUsage
+	lastPrune time.Time
+}
+
+// newRateLimiter creates a rateLimiter enforcing the given limits.
+func newRateLimiter(limits RateLimits,
+	metrics wtmetrics.Recorder) *rateLimiter {
+
+	return &rateLimiter{
+		limits:  limits,
+		metrics: metrics,
+		now:     time.Now,
+		clients: make(map[wtdb.SessionID]*clientUsage),
+	}
+}
+
+// prune forgets the clients that have been idle for longer than the idle
+// timeout and aren't banned, so that the tracked clients don't grow with every
+// key that ever connected. Clients are only pruned once per prune interval.
+//
+// NOTE: The mutex MUST be held.
+func (r *rateLimiter) prune(now time.Time) {
+	if now.Sub(r.lastPrune) < clientPruneInterval {
+		return
+	}
+	r.lastPrune = now
+
+	for client, usage := range r.clients {
+		if now.Before(usage.bannedUntil) ||
+			now.Sub(usage.lastSeen) < clientIdleTimeout {
+
+			continue
+		}
+
+		delete(r.clients, client)
+	}
+}
+
+// usage returns the usage of the given client, creating it if this is the
+// first request of the client.
+//
+// NOTE: The mutex MUST be held.
+func (r *rateLimiter) usage(client *wtdb.SessionID) *clientUsage {
+	now := r.now()
+	r.prune(now)
+
+	usage, ok := r.clients[*client]
+	if !ok {
+		usage = &clientUsage{}
+		if r.limits.MaxUpdateRate > 0 {
+			burst := int(r.limits.MaxUpdateRate)
+			if burst < 1 {
+				burst = 1
+			}
+
+			usage.updates = rate.NewLimiter(
+				rate.Limit(r.limits.MaxUpdateRate), burst,
+			)
+		}
+		r.clients[*client] = usage
+	}
+	usage.lastSeen = now
+
+	return usage
+}
+
+// isBanned returns whether the given client is currently banned, in which case
+// its connection should be rejected.
+func (r *rateLimiter) isBanned(client *wtdb.SessionID) bool {
+	if !r.limits.enabled() {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	usage, ok := r.clients[*client]
+	if !ok || !r.now().Before(usage.bannedUntil) {
+		return false
+	}
+
+	r.metrics.BannedConnRejected()
+
+	return true
+}
+
+// violation records that the client exceeded the given limit, and bans the
+// client once it exceeded its limits too often.
+//
+// NOTE: The mutex MUST be held.
+func (r *rateLimiter) violation(client *wtdb.SessionID, usage *clientUsage,
+	limit wtmetrics.Limit) {
+
+	r.metrics.LimitExceeded(limit)
+
+	if r.limits.MaxViolations == 0 {
+		return
+	}
+
+	usage.violations++
+	if usage.violations < r.limits.MaxViolations {
+		return
+	}
+
+	log.Warnf("Banning client %s for %v after exceeding its limits %d "+
+		"times", client, r.limits.BanDuration, usage.violations)
+
+	usage.violations = 0
+	usage.bannedUntil = r.now().Add(r.limits.BanDuration)
+	r.metrics.ClientBanned()
+}
+
+// allowSession returns ErrStorageLimitExceeded if a session of the given client
+// with the given maximum number of updates of the given blob type could store
+// more encrypted blobs than allowed.
+func (r *rateLimiter) allowSession(client *wtdb.SessionID, maxUpdates uint16,
+	blobType blob.Type) error {
+
+	maxStorage := r.limits.MaxStoragePerSession
+	if maxStorage == 0 {
+		return nil
+	}
+
+	storage := uint64(maxUpdates) * uint64(blob.Size(blobType))
+	if storage <= maxStorage {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.violation(client, r.usage(client), wtmetrics.LimitStorage)
+
+	return ErrStorageLimitExceeded
+}
+
+// allowUpdate returns ErrUpdateRateExceeded if a state update of the given
+// client exceeds the client's update rate.
+func (r *rateLimiter) allowUpdate(client *wtdb.SessionID) error {
+	if r.limits.MaxUpdateRate == 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	usage := r.usage(client)
+	if !usage.updates.AllowN(r.now(), 1) {
+		r.violation(client, usage, wtmetrics.LimitUpdateRate)
+		return ErrUpdateRateExceeded
+	}
+
+	return nil
+}
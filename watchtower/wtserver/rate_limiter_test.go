@@ -0,0 +1,99 @@
+package wtserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/watchtower/blob"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/lightningnetwork/lnd/watchtower/wtmetrics"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRateLimiter asserts that the limits of each client are enforced
+// separately, and that clients are banned for the ban duration once they
+// exceeded their limits too often.
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = &wtdb.SessionID{1}
+		bob   = &wtdb.SessionID{2}
+	)
+
+	blobType := blob.TypeAltruistCommit
+	blobSize := uint64(blob.Size(blobType))
+
+	now := time.Unix(1000, 0)
+	limiter := newRateLimiter(RateLimits{
+		MaxUpdateRate:        2,
+		MaxStoragePerSession: 10 * blobSize,
+		MaxViolations:        3,
+		BanDuration:          time.Hour,
+	}, wtmetrics.NoOpRecorder{})
+	limiter.now = func() time.Time {
+		return now
+	}
+
+	// A session may ask for as many updates as fit into its storage, but
+	// not more.
+	require.NoError(t, limiter.allowSession(alice, 10, blobType))
+	require.ErrorIs(
+		t, limiter.allowSession(alice, 11, blobType),
+		ErrStorageLimitExceeded,
+	)
+
+	// Alice may send a burst of two updates, after which she has to wait
+	// for the rate to allow another one. This is her second violation.
+	require.NoError(t, limiter.allowUpdate(alice))
+	require.NoError(t, limiter.allowUpdate(alice))
+	require.ErrorIs(t, limiter.allowUpdate(alice), ErrUpdateRateExceeded)
+
+	// Half a second later, another update is allowed by the rate.
+	now = now.Add(500 * time.Millisecond)
+	require.False(t, limiter.isBanned(alice))
+	require.NoError(t, limiter.allowUpdate(alice))
+
+	// Exceeding the rate once more is her third violation, so she's
+	// banned now.
+	require.ErrorIs(t, limiter.allowUpdate(alice), ErrUpdateRateExceeded)
+	require.True(t, limiter.isBanned(alice))
+
+	// Bob's limits are unaffected by Alice.
+	require.False(t, limiter.isBanned(bob))
+	require.NoError(t, limiter.allowSession(bob, 10, blobType))
+	require.NoError(t, limiter.allowUpdate(bob))
+
+	// Banned clients aren't forgotten while they're idle, so the ban
+	// holds until it expires.
+	now = now.Add(clientIdleTimeout)
+	require.NoError(t, limiter.allowUpdate(bob))
+	require.True(t, limiter.isBanned(alice))
+
+	// Once the ban expired, Alice may connect again. As she has been idle
+	// since, she's forgotten on the next pass over the clients.
+	now = now.Add(time.Hour)
+	require.False(t, limiter.isBanned(alice))
+	require.NoError(t, limiter.allowUpdate(bob))
+	require.NotContains(t, limiter.clients, *alice)
+	require.Contains(t, limiter.clients, *bob)
+}
+
+// TestRateLimiterDisabled asserts that no limits are enforced and no client is
+// banned with zero limits.
+func TestRateLimiterDisabled(t *testing.T) {
+	t.Parallel()
+
+	client := &wtdb.SessionID{1}
+
+	limiter := newRateLimiter(RateLimits{}, wtmetrics.NoOpRecorder{})
+	for i := 0; i < 10; i++ {
+		require.NoError(t, limiter.allowSession(
+			client, 1000, blob.TypeAltruistCommit,
+		))
+		require.NoError(t, limiter.allowUpdate(client))
+	}
+
+	require.False(t, limiter.isBanned(client))
+	require.Empty(t, limiter.clients)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/lightningnetwork/lnd/watchtower/wtmetrics"
 	"github.com/lightningnetwork/lnd/watchtower/wtwire"
 )
 
@@ -67,6 +68,14 @@ type Config struct {
 	// DisableReward causes the server to reject any session creation
 	// attempts that request rewards.
 	DisableReward bool
+
+	// Metrics records the sessions and state updates processed by the
+	// server. If nil, no metrics are recorded.
+	Metrics wtmetrics.Recorder
+
+	// RateLimits configures the limits enforced on each client and when
+	// abusive clients are banned.
+	RateLimits RateLimits
 }
 
 // Server houses the state required to handle watchtower peers. It's primary job
@@ -87,6 +96,8 @@ type Server struct {
 
 	localInit *wtwire.Init
 
+	limiter *rateLimiter
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -99,15 +110,22 @@ func New(cfg *Config) (*Server, error) {
 		lnwire.NewRawFeatureVector(
 			wtwire.AltruistSessionsOptional,
 			wtwire.AnchorCommitOptional,
+			wtwire.DynamicFeeOptional,
+			wtwire.CPFPAnchorOptional,
 		),
 		cfg.ChainHash,
 	)
 
+	if cfg.Metrics == nil {
+		cfg.Metrics = wtmetrics.NoOpRecorder{}
+	}
+
 	s := &Server{
 		cfg:       cfg,
 		clients:   make(map[wtdb.SessionID]Peer),
 		newPeers:  make(chan Peer),
 		localInit: localInit,
+		limiter:   newRateLimiter(cfg.RateLimits, cfg.Metrics),
 		quit:      make(chan struct{}),
 	}
 
@@ -218,6 +236,14 @@ func (s *Server) handleClient(peer Peer) {
 	// Use the connection's remote pubkey as the client's session id.
 	id := wtdb.NewSessionIDFromPubKey(peer.RemotePub())
 
+	// Reject the connection right away if the client is banned.
+	if s.limiter.isBanned(&id) {
+		log.Debugf("Rejecting connection of banned client %s@%s", id,
+			peer.RemoteAddr())
+		peer.Close()
+		return
+	}
+
 	// Register this peer in the server's client map, and defer the
 	// connection's cleanup. If the peer already exists, we will close the
 	// connection and exit immediately.
@@ -248,12 +274,16 @@ func (s *Server) handleClient(peer Peer) {
 		return
 	}
 
-	err = s.localInit.CheckRemoteInit(remoteInit, wtwire.FeatureNames)
+	negotiation, err := s.localInit.Negotiate(
+		remoteInit, wtwire.FeatureNames,
+	)
 	if err != nil {
 		log.Errorf("Cannot support client %s: %v", id, err)
 		return
 	}
 
+	log.Debugf("Negotiated %v with client %s", negotiation, id)
+
 	nextMsg, err := s.readMessage(peer)
 	if err != nil {
 		log.Errorf("Unable to read watchtower msg from %s: %v",
@@ -264,18 +294,22 @@ func (s *Server) handleClient(peer Peer) {
 	switch msg := nextMsg.(type) {
 	case *wtwire.CreateSession:
 		// Attempt to open a new session for this client.
-		err = s.handleCreateSession(peer, &id, msg)
+		start := time.Now()
+		err = s.handleCreateSession(peer, &id, msg, negotiation)
 		if err != nil {
 			log.Errorf("Unable to handle CreateSession "+
 				"from %s: %v", id, err)
 		}
+		s.cfg.Metrics.MessageProcessed(msg.MsgType(), time.Since(start))
 
 	case *wtwire.DeleteSession:
+		start := time.Now()
 		err = s.handleDeleteSession(peer, &id)
 		if err != nil {
 			log.Errorf("Unable to handle DeleteSession "+
 				"from %s: %v", id, err)
 		}
+		s.cfg.Metrics.MessageProcessed(msg.MsgType(), time.Since(start))
 
 	case *wtwire.StateUpdate:
 		err = s.handleStateUpdates(peer, &id, msg)
@@ -160,7 +160,9 @@ var createSessionTests = []createSessionTestCase{
 	{
 		name: "duplicate session create altruist anchor commit",
 		initMsg: wtwire.NewInitMessage(
-			lnwire.NewRawFeatureVector(),
+			lnwire.NewRawFeatureVector(
+				wtwire.AnchorCommitRequired,
+			),
 			testnetChainHash,
 		),
 		createMsg: &wtwire.CreateSession{
@@ -265,6 +267,102 @@ var createSessionTests = []createSessionTestCase{
 			Data: []byte{},
 		},
 	},
+	{
+		name: "duplicate session create dynamic fee",
+		initMsg: wtwire.NewInitMessage(
+			lnwire.NewRawFeatureVector(
+				wtwire.DynamicFeeRequired,
+			),
+			testnetChainHash,
+		),
+		createMsg: &wtwire.CreateSession{
+			BlobType:           blob.TypeAltruistDynamicFeeCommit,
+			MaxUpdates:         1000,
+			SweepFeeRate:       10000,
+			MaxSweepFeeRate:    40000,
+			SweepFeePercentile: 50,
+		},
+		expReply: &wtwire.CreateSessionReply{
+			Code: wtwire.CodeOK,
+			Data: []byte{},
+		},
+		expDupReply: &wtwire.CreateSessionReply{
+			Code: wtwire.CodeOK,
+			Data: []byte{},
+		},
+	},
+	{
+		name: "reject dynamic fee max below min",
+		initMsg: wtwire.NewInitMessage(
+			lnwire.NewRawFeatureVector(
+				wtwire.DynamicFeeRequired,
+			),
+			testnetChainHash,
+		),
+		createMsg: &wtwire.CreateSession{
+			BlobType:           blob.TypeAltruistDynamicFeeCommit,
+			MaxUpdates:         1000,
+			SweepFeeRate:       10000,
+			MaxSweepFeeRate:    5000,
+			SweepFeePercentile: 50,
+		},
+		expReply: &wtwire.CreateSessionReply{
+			Code: wtwire.CreateSessionCodeRejectSweepFeeRate,
+			Data: []byte{},
+		},
+	},
+	{
+		name: "reject dynamic fee without percentile",
+		initMsg: wtwire.NewInitMessage(
+			lnwire.NewRawFeatureVector(
+				wtwire.DynamicFeeRequired,
+			),
+			testnetChainHash,
+		),
+		createMsg: &wtwire.CreateSession{
+			BlobType:        blob.TypeAltruistDynamicFeeCommit,
+			MaxUpdates:      1000,
+			SweepFeeRate:    10000,
+			MaxSweepFeeRate: 40000,
+		},
+		expReply: &wtwire.CreateSessionReply{
+			Code: wtwire.CreateSessionCodeRejectSweepFeeRate,
+			Data: []byte{},
+		},
+	},
+	{
+		name: "reject blob type without negotiated feature",
+		initMsg: wtwire.NewInitMessage(
+			lnwire.NewRawFeatureVector(),
+			testnetChainHash,
+		),
+		createMsg: &wtwire.CreateSession{
+			BlobType:     blob.TypeAltruistAnchorCommit,
+			MaxUpdates:   1000,
+			SweepFeeRate: 10000,
+		},
+		expReply: &wtwire.CreateSessionReply{
+			Code: wtwire.CreateSessionCodeRejectBlobType,
+			Data: []byte{},
+		},
+	},
+	{
+		name: "legacy client without negotiated feature",
+		initMsg: &wtwire.Init{
+			ConnFeatures: lnwire.NewRawFeatureVector(),
+			ChainHash:    testnetChainHash,
+			Version:      wtwire.ProtocolVersionLegacy,
+		},
+		createMsg: &wtwire.CreateSession{
+			BlobType:     blob.TypeAltruistAnchorCommit,
+			MaxUpdates:   1000,
+			SweepFeeRate: 10000,
+		},
+		expReply: &wtwire.CreateSessionReply{
+			Code: wtwire.CodeOK,
+			Data: []byte{},
+		},
+	},
 	// TODO(conner): add policy rejection tests
 }
 
@@ -2,6 +2,7 @@ package wtserver
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/lightningnetwork/lnd/watchtower/wtdb"
 	"github.com/lightningnetwork/lnd/watchtower/wtwire"
@@ -36,10 +37,14 @@ func (s *Server) handleStateUpdates(peer Peer, id *wtdb.SessionID,
 		}
 
 		// Try to accept the state update from the client.
+		start := time.Now()
 		err := s.handleStateUpdate(peer, id, curUpdate)
 		if err != nil {
 			return err
 		}
+		s.cfg.Metrics.MessageProcessed(
+			curUpdate.MsgType(), time.Since(start),
+		)
 
 		// If the client signals that this is last StateUpdate
 		// message, we can disconnect the client.
@@ -73,6 +78,24 @@ func (s *Server) handleStateUpdate(peer Peer, id *wtdb.SessionID,
 		err         error
 	)
 
+	// Reject the update if the client exceeds its update rate.
+	err = s.limiter.allowUpdate(id)
+	if err != nil {
+		log.Debugf("Rejecting state update %d from %s@%s: %v",
+			update.SeqNum, id, peer.RemoteAddr(), err)
+
+		if s.cfg.NoAckUpdates {
+			return &connFailure{
+				ID:   *id,
+				Code: wtwire.CodeTemporaryFailure,
+			}
+		}
+
+		return s.replyStateUpdate(
+			peer, id, wtwire.CodeTemporaryFailure, 0,
+		)
+	}
+
 	sessionUpdate := wtdb.SessionStateUpdate{
 		ID:            *id,
 		Hint:          update.Hint,
@@ -87,6 +110,8 @@ func (s *Server) handleStateUpdate(peer Peer, id *wtdb.SessionID,
 		log.Debugf("State update %d accepted for %s",
 			update.SeqNum, id)
 
+		s.cfg.Metrics.UpdateStored(len(update.EncryptedBlob))
+
 		failCode = wtwire.CodeOK
 
 	// Return a permanent failure if a client tries to send an update for
@@ -34,7 +34,23 @@ type CreateSession struct {
 	// constructing the justice transaction. All sweep transactions created
 	// for this session must use this value during construction, and the
 	// signatures must implicitly commit to the resulting output values.
+	// For dynamic fee sessions, this is the lowest fee rate the tower may
+	// choose.
 	SweepFeeRate chainfee.SatPerKWeight
+
+	// MaxSweepFeeRate is the highest fee rate the tower may choose for the
+	// justice transaction of a dynamic fee session.
+	//
+	// NOTE: This value is only serialized if BlobType has
+	// blob.FlagDynamicFee.
+	MaxSweepFeeRate chainfee.SatPerKWeight
+
+	// SweepFeePercentile is the percentile of the tower's fee estimates at
+	// the time of the breach that a dynamic fee session targets.
+	//
+	// NOTE: This value is only serialized if BlobType has
+	// blob.FlagDynamicFee.
+	SweepFeePercentile uint8
 }
 
 // A compile time check to ensure CreateSession implements the wtwire.Message
@@ -46,13 +62,27 @@ var _ Message = (*CreateSession)(nil)
 //
 // This is part of the wtwire.Message interface.
 func (m *CreateSession) Decode(r io.Reader, pver uint32) error {
-	return ReadElements(r,
+	err := ReadElements(r,
 		&m.BlobType,
 		&m.MaxUpdates,
 		&m.RewardBase,
 		&m.RewardRate,
 		&m.SweepFeeRate,
 	)
+	if err != nil {
+		return err
+	}
+
+	// The dynamic fee parameters are only present for dynamic fee
+	// sessions, keeping the message backwards compatible.
+	if !m.BlobType.HasDynamicFee() {
+		return nil
+	}
+
+	return ReadElements(r,
+		&m.MaxSweepFeeRate,
+		&m.SweepFeePercentile,
+	)
 }
 
 // Encode serializes the target CreateSession into the passed io.Writer
@@ -60,13 +90,25 @@ func (m *CreateSession) Decode(r io.Reader, pver uint32) error {
 //
 // This is part of the wtwire.Message interface.
 func (m *CreateSession) Encode(w io.Writer, pver uint32) error {
-	return WriteElements(w,
+	err := WriteElements(w,
 		m.BlobType,
 		m.MaxUpdates,
 		m.RewardBase,
 		m.RewardRate,
 		m.SweepFeeRate,
 	)
+	if err != nil {
+		return err
+	}
+
+	if !m.BlobType.HasDynamicFee() {
+		return nil
+	}
+
+	return WriteElements(w,
+		m.MaxSweepFeeRate,
+		m.SweepFeePercentile,
+	)
 }
 
 // MsgType returns the integer uniquely identifying this message type on the
@@ -82,5 +124,5 @@ func (m *CreateSession) MsgType() MessageType {
 //
 // This is part of the wtwire.Message interface.
 func (m *CreateSession) MaxPayloadLength(uint32) uint32 {
-	return 2 + 2 + 4 + 4 + 8 // 20
+	return 2 + 2 + 4 + 4 + 8 + 8 + 1 // 29
 }
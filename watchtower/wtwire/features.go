@@ -9,6 +9,10 @@ var FeatureNames = map[lnwire.FeatureBit]string{
 	AltruistSessionsOptional: "altruist-sessions",
 	AnchorCommitRequired:     "anchor-commit",
 	AnchorCommitOptional:     "anchor-commit",
+	DynamicFeeRequired:       "dynamic-fee",
+	DynamicFeeOptional:       "dynamic-fee",
+	CPFPAnchorRequired:       "cpfp-anchor",
+	CPFPAnchorOptional:       "cpfp-anchor",
 }
 
 const (
@@ -30,4 +34,24 @@ const (
 	// AnchorCommitOptional specifies that the advertising tower allows the
 	// remote party to negotiate sessions for protecting anchor channels.
 	AnchorCommitOptional lnwire.FeatureBit = 3
+
+	// DynamicFeeRequired specifies that the advertising tower requires the
+	// remote party to negotiate sessions whose sweep fee rate is chosen by
+	// the tower at breach time.
+	DynamicFeeRequired lnwire.FeatureBit = 4
+
+	// DynamicFeeOptional specifies that the advertising tower allows the
+	// remote party to negotiate sessions whose sweep fee rate is chosen by
+	// the tower at breach time.
+	DynamicFeeOptional lnwire.FeatureBit = 5
+
+	// CPFPAnchorRequired specifies that the advertising tower requires the
+	// remote party to negotiate sessions whose justice transactions have
+	// an anchor output that allows the tower to bump their fee.
+	CPFPAnchorRequired lnwire.FeatureBit = 6
+
+	// CPFPAnchorOptional specifies that the advertising tower allows the
+	// remote party to negotiate sessions whose justice transactions have
+	// an anchor output that allows the tower to bump their fee.
+	CPFPAnchorOptional lnwire.FeatureBit = 7
 )
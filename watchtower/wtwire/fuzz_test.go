@@ -0,0 +1,153 @@
+package wtwire_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/lightningnetwork/lnd/watchtower/wtwire"
+	"github.com/stretchr/testify/require"
+)
+
+// numSeedMsgs is the number of randomly generated messages that are added to
+// the seed corpus of each fuzz target.
+const numSeedMsgs = 10
+
+// prefixWithMsgType takes []byte and adds a wire protocol prefix
+// to make the []byte into an actual message to be used in fuzzing.
+func prefixWithMsgType(data []byte, prefix wtwire.MessageType) []byte {
+	var prefixBytes [2]byte
+	binary.BigEndian.PutUint16(prefixBytes[:], uint16(prefix))
+	data = append(prefixBytes[:], data...)
+
+	return data
+}
+
+// randMessage creates a random message of the same type as emptyMsg.
+func randMessage(t testing.TB, emptyMsg wtwire.Message,
+	r *rand.Rand) wtwire.Message {
+
+	t.Helper()
+
+	// The Init message contains a feature vector, which can't be generated
+	// by testing/quick.
+	if emptyMsg.MsgType() == wtwire.MsgInit {
+		return wtwire.NewInitMessage(
+			randRawFeatureVector(r), randChainHash(r),
+		)
+	}
+
+	v, ok := quick.Value(reflect.TypeOf(emptyMsg).Elem(), r)
+	require.True(t, ok, "unable to generate %v", emptyMsg.MsgType())
+
+	msg := reflect.New(v.Type())
+	msg.Elem().Set(v)
+
+	return msg.Interface().(wtwire.Message)
+}
+
+// seedCorpus adds a set of randomly generated, valid messages of the same type
+// as emptyMsg to the seed corpus of the fuzz target. The message type prefix is
+// stripped, as the fuzz targets add it themselves.
+func seedCorpus(f *testing.F, emptyMsg wtwire.Message) {
+	f.Helper()
+
+	r := rand.New(rand.NewSource(int64(emptyMsg.MsgType())))
+	for i := 0; i < numSeedMsgs; i++ {
+		msg := randMessage(f, emptyMsg, r)
+
+		var b bytes.Buffer
+		if _, err := wtwire.WriteMessage(&b, msg, 0); err != nil {
+			continue
+		}
+
+		f.Add(b.Bytes()[2:])
+	}
+}
+
+// harness performs the actual fuzz testing of the appropriate wire message.
+// This function will check that the passed-in message passes wire length
+// checks, is a valid message once deserialized, and passes a sequence of
+// serialization and deserialization checks.
+func harness(t *testing.T, data []byte, emptyMsg wtwire.Message) {
+	t.Helper()
+
+	// Create a reader with the byte array.
+	r := bytes.NewReader(data)
+
+	// Make sure byte array length (excluding 2 bytes for message type) is
+	// less than max payload size for the wire message.
+	payloadLen := uint32(len(data)) - 2
+	if payloadLen > emptyMsg.MaxPayloadLength(0) {
+		// Ignore this input - max payload constraint violated.
+		return
+	}
+
+	msg, err := wtwire.ReadMessage(r, 0)
+	if err != nil {
+		return
+	}
+
+	// We will serialize the message into a new bytes buffer.
+	var b bytes.Buffer
+	_, err = wtwire.WriteMessage(&b, msg, 0)
+	require.NoError(t, err)
+
+	// Deserialize the message from the serialized bytes buffer, and then
+	// assert that the original message is equal to the newly deserialized
+	// message.
+	newMsg, err := wtwire.ReadMessage(&b, 0)
+	require.NoError(t, err)
+	require.Equal(t, msg, newMsg)
+}
+
+// fuzzMsg registers a fuzz target for the type of emptyMsg that is seeded with
+// randomly generated messages of that type and checks that any message that
+// can be decoded survives a serialization round trip.
+func fuzzMsg(f *testing.F, emptyMsg wtwire.Message) {
+	seedCorpus(f, emptyMsg)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with the message type.
+		data = prefixWithMsgType(data, emptyMsg.MsgType())
+
+		// Pass the message into our general fuzz harness for wire
+		// messages!
+		harness(t, data, emptyMsg)
+	})
+}
+
+func FuzzCreateSessionReply(f *testing.F) {
+	fuzzMsg(f, &wtwire.CreateSessionReply{})
+}
+
+func FuzzCreateSession(f *testing.F) {
+	fuzzMsg(f, &wtwire.CreateSession{})
+}
+
+func FuzzDeleteSessionReply(f *testing.F) {
+	fuzzMsg(f, &wtwire.DeleteSessionReply{})
+}
+
+func FuzzDeleteSession(f *testing.F) {
+	fuzzMsg(f, &wtwire.DeleteSession{})
+}
+
+func FuzzError(f *testing.F) {
+	fuzzMsg(f, &wtwire.Error{})
+}
+
+func FuzzInit(f *testing.F) {
+	fuzzMsg(f, &wtwire.Init{})
+}
+
+func FuzzStateUpdateReply(f *testing.F) {
+	fuzzMsg(f, &wtwire.StateUpdateReply{})
+}
+
+func FuzzStateUpdate(f *testing.F) {
+	fuzzMsg(f, &wtwire.StateUpdate{})
+}
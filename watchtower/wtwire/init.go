@@ -9,10 +9,25 @@ import (
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
+const (
+	// ProtocolVersionLegacy is the protocol version of peers that don't
+	// advertise a version in their Init message.
+	ProtocolVersionLegacy uint16 = 0
+
+	// ProtocolVersionNegotiation is the first protocol version advertised
+	// in the Init message. Peers speaking it only negotiate sessions whose
+	// features were advertised by both of them.
+	ProtocolVersionNegotiation uint16 = 1
+
+	// ProtocolVersion is the latest protocol version supported by this
+	// implementation.
+	ProtocolVersion = ProtocolVersionNegotiation
+)
+
 // Init is the first message sent over the watchtower wire protocol, and
 // specifies connection features bits and level of requiredness maintained by
 // the sending node. The Init message also sends the chain hash identifying the
-// network that the sender is on.
+// network that the sender is on, and the latest protocol version it supports.
 type Init struct {
 	// ConnFeatures are the feature bits being advertised for the duration
 	// of a single connection with a peer.
@@ -21,16 +36,22 @@ type Init struct {
 	// ChainHash is the genesis hash of the chain that the advertiser claims
 	// to be on.
 	ChainHash chainhash.Hash
+
+	// Version is the latest protocol version supported by the advertiser.
+	// It's appended to the message, so that older peers ignore it and are
+	// seen as speaking ProtocolVersionLegacy.
+	Version uint16
 }
 
 // NewInitMessage generates a new Init message from a raw connection feature
-// vector and chain hash.
+// vector and chain hash, advertising the latest protocol version.
 func NewInitMessage(connFeatures *lnwire.RawFeatureVector,
 	chainHash chainhash.Hash) *Init {
 
 	return &Init{
 		ConnFeatures: connFeatures,
 		ChainHash:    chainHash,
+		Version:      ProtocolVersion,
 	}
 }
 
@@ -42,6 +63,7 @@ func (msg *Init) Encode(w io.Writer, pver uint32) error {
 	return WriteElements(w,
 		msg.ConnFeatures,
 		msg.ChainHash,
+		msg.Version,
 	)
 }
 
@@ -50,10 +72,22 @@ func (msg *Init) Encode(w io.Writer, pver uint32) error {
 //
 // This is part of the wtwire.Message interface.
 func (msg *Init) Decode(r io.Reader, pver uint32) error {
-	return ReadElements(r,
+	err := ReadElements(r,
 		&msg.ConnFeatures,
 		&msg.ChainHash,
 	)
+	if err != nil {
+		return err
+	}
+
+	// Older peers don't append their protocol version.
+	err = ReadElement(r, &msg.Version)
+	if err == io.EOF {
+		msg.Version = ProtocolVersionLegacy
+		return nil
+	}
+
+	return err
 }
 
 // MsgType returns the integer uniquely identifying this message type on the
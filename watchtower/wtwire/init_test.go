@@ -59,10 +59,12 @@ var checkRemoteInitTests = []checkRemoteInitTest{
 		name:      "same chain, remote-unknown-required",
 		lFeatures: lnwire.NewRawFeatureVector(wtwire.AltruistSessionsOptional),
 		lHash:     testnetChainHash,
-		rFeatures: lnwire.NewRawFeatureVector(lnwire.GossipQueriesRequired),
-		rHash:     testnetChainHash,
+		rFeatures: lnwire.NewRawFeatureVector(
+			lnwire.TLVOnionPayloadRequired,
+		),
+		rHash: testnetChainHash,
 		expErr: feature.NewErrUnknownRequired(
-			[]lnwire.FeatureBit{lnwire.GossipQueriesRequired},
+			[]lnwire.FeatureBit{lnwire.TLVOnionPayloadRequired},
 		),
 	},
 }
@@ -0,0 +1,156 @@
+package wtwire
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/watchtower/blob"
+)
+
+// blobTypeFeatures maps the blob type flags that need support from both
+// parties to the optional bit of the feature advertising that support.
+var blobTypeFeatures = map[blob.Flag]lnwire.FeatureBit{
+	blob.FlagAnchorChannel: AnchorCommitOptional,
+	blob.FlagDynamicFee:    DynamicFeeOptional,
+	blob.FlagCPFPAnchor:    CPFPAnchorOptional,
+}
+
+// Negotiation is the outcome of the exchange of Init messages between a client
+// and a tower.
+type Negotiation struct {
+	// Version is the protocol version spoken by both parties, which is the
+	// lower of their advertised versions.
+	Version uint16
+
+	// Features holds the features advertised by both parties. A feature
+	// is set as required if either party requires it.
+	Features *lnwire.FeatureVector
+}
+
+// String returns a human-readable description of the negotiated version and
+// features.
+func (n *Negotiation) String() string {
+	bits := make([]lnwire.FeatureBit, 0, len(n.Features.Features()))
+	for bit := range n.Features.Features() {
+		bits = append(bits, bit)
+	}
+	sort.Slice(bits, func(i, j int) bool {
+		return bits[i] < bits[j]
+	})
+
+	features := make([]string, 0, len(bits))
+	for _, bit := range bits {
+		features = append(features, fmt.Sprintf("%s(%d)",
+			n.Features.Name(bit), bit))
+	}
+
+	return fmt.Sprintf("version=%d features=[%s]", n.Version,
+		strings.Join(features, " "))
+}
+
+// AllowsBlobType returns whether sessions of the given blob type may be
+// negotiated over the connection. Starting with ProtocolVersionNegotiation,
+// both parties must have advertised the features the blob type relies on.
+// Legacy peers don't gate blob types on features.
+func (n *Negotiation) AllowsBlobType(blobType blob.Type) bool {
+	if n.Version < ProtocolVersionNegotiation {
+		return true
+	}
+
+	for flag, feature := range blobTypeFeatures {
+		if blobType.Has(flag) && !n.Features.HasFeature(feature) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ErrUnsupportedRequired signals that a party requires features that the other
+// party doesn't advertise.
+type ErrUnsupportedRequired struct {
+	features []string
+}
+
+// NewErrUnsupportedRequired creates an ErrUnsupportedRequired for the given
+// required feature bits.
+func NewErrUnsupportedRequired(bits []lnwire.FeatureBit,
+	featureNames map[lnwire.FeatureBit]string) *ErrUnsupportedRequired {
+
+	features := make([]string, 0, len(bits))
+	for _, bit := range bits {
+		features = append(features, fmt.Sprintf("%s(%d)",
+			featureNames[bit], bit))
+	}
+
+	return &ErrUnsupportedRequired{features}
+}
+
+// Error returns a human-readable error listing the unsupported features.
+func (e *ErrUnsupportedRequired) Error() string {
+	return fmt.Sprintf("required features not supported by both "+
+		"parties: %s", strings.Join(e.features, ", "))
+}
+
+// Negotiate validates the remote party's Init message like CheckRemoteInit,
+// and determines the protocol version and the features supported by both
+// parties. Starting with ProtocolVersionNegotiation, an error is returned if
+// either party requires a feature that the other one doesn't advertise.
+func (msg *Init) Negotiate(remoteInit *Init,
+	featureNames map[lnwire.FeatureBit]string) (*Negotiation, error) {
+
+	err := msg.CheckRemoteInit(remoteInit, featureNames)
+	if err != nil {
+		return nil, err
+	}
+
+	version := msg.Version
+	if remoteInit.Version < version {
+		version = remoteInit.Version
+	}
+
+	localFeatures := lnwire.NewFeatureVector(msg.ConnFeatures, featureNames)
+	remoteFeatures := lnwire.NewFeatureVector(
+		remoteInit.ConnFeatures, featureNames,
+	)
+
+	shared := lnwire.NewRawFeatureVector()
+	var unsupported []lnwire.FeatureBit
+	for bit := range featureNames {
+		// Visit each feature once, through its optional bit.
+		if bit.IsRequired() {
+			continue
+		}
+
+		advertised := localFeatures.HasFeature(bit) &&
+			remoteFeatures.HasFeature(bit)
+		required := localFeatures.RequiresFeature(bit) ||
+			remoteFeatures.RequiresFeature(bit)
+
+		switch {
+		case advertised && required:
+			shared.Set(bit ^ 1)
+
+		case advertised:
+			shared.Set(bit)
+
+		case required:
+			unsupported = append(unsupported, bit^1)
+		}
+	}
+
+	if version >= ProtocolVersionNegotiation && len(unsupported) > 0 {
+		sort.Slice(unsupported, func(i, j int) bool {
+			return unsupported[i] < unsupported[j]
+		})
+
+		return nil, NewErrUnsupportedRequired(unsupported, featureNames)
+	}
+
+	return &Negotiation{
+		Version:  version,
+		Features: lnwire.NewFeatureVector(shared, featureNames),
+	}, nil
+}
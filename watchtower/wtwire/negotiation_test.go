@@ -0,0 +1,101 @@
+package wtwire_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/watchtower/blob"
+	"github.com/lightningnetwork/lnd/watchtower/wtwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInitLegacyVersion asserts that an Init message without a protocol
+// version, as sent by older peers, is decoded as ProtocolVersionLegacy.
+func TestInitLegacyVersion(t *testing.T) {
+	t.Parallel()
+
+	initMsg := wtwire.NewInitMessage(
+		lnwire.NewRawFeatureVector(wtwire.AltruistSessionsOptional),
+		testnetChainHash,
+	)
+
+	var b bytes.Buffer
+	_, err := wtwire.WriteMessage(&b, initMsg, 0)
+	require.NoError(t, err)
+
+	// Strip the trailing protocol version.
+	legacyMsg := b.Bytes()[:b.Len()-2]
+	msg, err := wtwire.ReadMessage(bytes.NewReader(legacyMsg), 0)
+	require.NoError(t, err)
+
+	legacyInit, ok := msg.(*wtwire.Init)
+	require.True(t, ok)
+	require.Equal(t, wtwire.ProtocolVersionLegacy, legacyInit.Version)
+	require.Equal(t, initMsg.ConnFeatures, legacyInit.ConnFeatures)
+}
+
+// TestNegotiate asserts that negotiating with the remote party's Init message
+// yields the lower protocol version and the features advertised by both
+// parties, and that required features must be supported by both parties
+// unless one of them is a legacy peer.
+func TestNegotiate(t *testing.T) {
+	t.Parallel()
+
+	towerFeatures := lnwire.NewRawFeatureVector(
+		wtwire.AltruistSessionsOptional,
+		wtwire.AnchorCommitOptional,
+	)
+	clientFeatures := lnwire.NewRawFeatureVector(
+		wtwire.AltruistSessionsRequired,
+		wtwire.AnchorCommitRequired,
+		wtwire.DynamicFeeOptional,
+	)
+
+	towerInit := wtwire.NewInitMessage(towerFeatures, testnetChainHash)
+	clientInit := wtwire.NewInitMessage(clientFeatures, testnetChainHash)
+
+	// Features advertised by both parties are shared, as required if
+	// either party requires them. Dynamic fees are only advertised by the
+	// client, so they aren't negotiated.
+	negotiation, err := clientInit.Negotiate(towerInit, wtwire.FeatureNames)
+	require.NoError(t, err)
+	require.Equal(t, wtwire.ProtocolVersion, negotiation.Version)
+	require.True(t, negotiation.Features.RawFeatureVector.Equals(
+		lnwire.NewRawFeatureVector(
+			wtwire.AltruistSessionsRequired,
+			wtwire.AnchorCommitRequired,
+		),
+	))
+
+	require.True(t, negotiation.AllowsBlobType(blob.TypeAltruistCommit))
+	require.True(
+		t, negotiation.AllowsBlobType(blob.TypeAltruistAnchorCommit),
+	)
+	require.False(t, negotiation.AllowsBlobType(
+		blob.TypeAltruistDynamicFeeCommit,
+	))
+
+	// The negotiation is symmetric.
+	towerNegotiation, err := towerInit.Negotiate(
+		clientInit, wtwire.FeatureNames,
+	)
+	require.NoError(t, err)
+	require.Equal(t, negotiation, towerNegotiation)
+
+	// If the client requires dynamic fees, which the tower doesn't
+	// advertise, the negotiation fails.
+	clientFeatures.Set(wtwire.DynamicFeeRequired)
+	_, err = clientInit.Negotiate(towerInit, wtwire.FeatureNames)
+	require.IsType(t, &wtwire.ErrUnsupportedRequired{}, err)
+
+	// Legacy towers don't advertise a protocol version, and aren't held to
+	// the features they advertise.
+	towerInit.Version = wtwire.ProtocolVersionLegacy
+	negotiation, err = clientInit.Negotiate(towerInit, wtwire.FeatureNames)
+	require.NoError(t, err)
+	require.Equal(t, wtwire.ProtocolVersionLegacy, negotiation.Version)
+	require.True(t, negotiation.AllowsBlobType(
+		blob.TypeAltruistDynamicFeeCommit,
+	))
+}
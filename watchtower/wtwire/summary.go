@@ -10,6 +10,16 @@ func MessageSummary(msg Message) string {
 		return ""
 
 	case *CreateSession:
+		if msg.BlobType.HasDynamicFee() {
+			return fmt.Sprintf("blob_type=%s, max_updates=%d "+
+				"reward_base=%d reward_rate=%d "+
+				"sweep_fee_rate=%d max_sweep_fee_rate=%d "+
+				"sweep_fee_percentile=%d", msg.BlobType,
+				msg.MaxUpdates, msg.RewardBase, msg.RewardRate,
+				msg.SweepFeeRate, msg.MaxSweepFeeRate,
+				msg.SweepFeePercentile)
+		}
+
 		return fmt.Sprintf("blob_type=%s, max_updates=%d "+
 			"reward_base=%d reward_rate=%d sweep_fee_rate=%d",
 			msg.BlobType, msg.MaxUpdates, msg.RewardBase,
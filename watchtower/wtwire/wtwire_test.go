@@ -10,7 +10,9 @@ import (
 
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/watchtower/blob"
 	"github.com/lightningnetwork/lnd/watchtower/wtwire"
 )
 
@@ -85,6 +87,26 @@ func TestWatchtowerWireProtocol(t *testing.T) {
 
 			v[0] = reflect.ValueOf(*req)
 		},
+		wtwire.MsgCreateSession: func(v []reflect.Value, r *rand.Rand) {
+			req := wtwire.CreateSession{
+				BlobType:     blob.Type(r.Int31()),
+				MaxUpdates:   uint16(r.Int31()),
+				RewardBase:   r.Uint32(),
+				RewardRate:   r.Uint32(),
+				SweepFeeRate: chainfee.SatPerKWeight(r.Uint64()),
+			}
+
+			// The dynamic fee parameters are only encoded for
+			// dynamic fee sessions.
+			if req.BlobType.HasDynamicFee() {
+				req.MaxSweepFeeRate = chainfee.SatPerKWeight(
+					r.Uint64(),
+				)
+				req.SweepFeePercentile = uint8(r.Int31())
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
 	}
 
 	// With the above types defined, we'll now generate a slice of
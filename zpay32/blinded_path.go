@@ -0,0 +1,119 @@
+package zpay32
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// BlindedPaymentPath is a blinded path to the recipient of an invoice, along
+// with the aggregate forwarding policy of all the hops of the path. The sender
+// routes the payment to the introduction node of the path, and pays the
+// aggregate fee for the hops after it.
+type BlindedPaymentPath struct {
+	// FeeBaseMsat is the aggregate base fee of the path.
+	FeeBaseMsat uint32
+
+	// FeeRate is the aggregate proportional fee of the path, in parts per
+	// million.
+	FeeRate uint32
+
+	// CltvExpiryDelta is the aggregate CLTV expiry delta of the path,
+	// including the final CLTV delta of the recipient.
+	CltvExpiryDelta uint16
+
+	// HTLCMinMsat is the minimum HTLC that the path accepts.
+	HTLCMinMsat uint64
+
+	// HTLCMaxMsat is the maximum HTLC that the path accepts.
+	HTLCMaxMsat uint64
+
+	// Features are the features of the path.
+	Features *lnwire.FeatureVector
+
+	// Path is the blinded path itself.
+	Path *lnwire.BlindedPath
+}
+
+// Encode serializes the blinded payment path into the passed io.Writer.
+func (p *BlindedPaymentPath) Encode(w io.Writer) error {
+	var b [26]byte
+	binary.BigEndian.PutUint32(b[:4], p.FeeBaseMsat)
+	binary.BigEndian.PutUint32(b[4:8], p.FeeRate)
+	binary.BigEndian.PutUint16(b[8:10], p.CltvExpiryDelta)
+	binary.BigEndian.PutUint64(b[10:18], p.HTLCMinMsat)
+	binary.BigEndian.PutUint64(b[18:26], p.HTLCMaxMsat)
+	if _, err := w.Write(b[:]); err != nil {
+		return err
+	}
+
+	features := p.Features
+	if features == nil {
+		features = lnwire.EmptyFeatureVector()
+	}
+	if err := features.Encode(w); err != nil {
+		return err
+	}
+
+	if p.Path == nil {
+		return lnwire.ErrNoBlindedHops
+	}
+
+	return p.Path.Encode(w)
+}
+
+// DecodeBlindedPaymentPath deserializes a blinded payment path from the
+// passed io.Reader.
+func DecodeBlindedPaymentPath(r io.Reader) (*BlindedPaymentPath, error) {
+	var b [26]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return nil, err
+	}
+
+	p := &BlindedPaymentPath{
+		FeeBaseMsat:     binary.BigEndian.Uint32(b[:4]),
+		FeeRate:         binary.BigEndian.Uint32(b[4:8]),
+		CltvExpiryDelta: binary.BigEndian.Uint16(b[8:10]),
+		HTLCMinMsat:     binary.BigEndian.Uint64(b[10:18]),
+		HTLCMaxMsat:     binary.BigEndian.Uint64(b[18:26]),
+		Path:            &lnwire.BlindedPath{},
+	}
+
+	rawFeatures := lnwire.NewRawFeatureVector()
+	if err := rawFeatures.Decode(r); err != nil {
+		return nil, err
+	}
+	p.Features = lnwire.NewFeatureVector(rawFeatures, lnwire.Features)
+
+	if err := p.Path.Decode(r); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// parseBlindedPaymentPath converts the data (encoded in base32) into a
+// blinded payment path.
+func parseBlindedPaymentPath(data []byte) (*BlindedPaymentPath, error) {
+	base256Data, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+
+	r := bytes.NewReader(base256Data)
+	path, err := DecodeBlindedPaymentPath(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("blinded payment path has %d trailing "+
+			"bytes", r.Len())
+	}
+
+	return path, nil
+}
@@ -255,6 +255,27 @@ func writeTaggedFields(bufferBase32 *bytes.Buffer, invoice *Invoice) error {
 		}
 	}
 
+	for _, blindedPath := range invoice.BlindedPaymentPaths {
+		var b bytes.Buffer
+		if err := blindedPath.Encode(&b); err != nil {
+			return err
+		}
+
+		blindedPathBase32, err := bech32.ConvertBits(
+			b.Bytes(), 8, 5, true,
+		)
+		if err != nil {
+			return err
+		}
+
+		err = writeTaggedField(
+			bufferBase32, fieldTypeB, blindedPathBase32,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	if invoice.Destination != nil {
 		// Convert 33 byte pubkey to 53 5-bit groups.
 		pubKeyBase32, err := bech32.ConvertBits(
@@ -0,0 +1,87 @@
+package zpay32
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/require"
+)
+
+// seedCorpus adds a set of valid testnet invoices to the seed corpus of the
+// fuzz target.
+func seedCorpus(f *testing.F) {
+	f.Helper()
+
+	options := [][]func(*Invoice){
+		{
+			Description(testCupOfCoffee),
+		},
+		{
+			Amount(testMillisat25mBTC),
+			DescriptionHash(testDescriptionHash),
+			FallbackAddr(testAddrTestnet),
+		},
+		{
+			Amount(testMillisat20mBTC),
+			Description(testPleaseConsider),
+			Expiry(testExpiry60),
+			CLTVExpiry(144),
+			RouteHint(testDoubleHop),
+			PaymentAddr(testPaymentAddr),
+		},
+	}
+
+	for _, opts := range options {
+		invoice, err := NewInvoice(
+			&chaincfg.TestNet3Params, testPaymentHash,
+			time.Unix(10, 0), opts...,
+		)
+		require.NoError(f, err)
+
+		encoded, err := invoice.Encode(testMessageSigner)
+		require.NoError(f, err)
+
+		f.Add(encoded)
+	}
+}
+
+// FuzzDecode checks that decoding arbitrary data never panics, and that any
+// invoice that can be decoded is well-formed.
+func FuzzDecode(f *testing.F) {
+	seedCorpus(f)
+
+	f.Fuzz(func(t *testing.T, data string) {
+		inv, err := Decode(data, &chaincfg.TestNet3Params)
+		if err != nil {
+			return
+		}
+
+		// Call these functions as a sanity check to make sure the
+		// invoice is well-formed.
+		_ = inv.MinFinalCLTVExpiry()
+		_ = inv.Expiry()
+	})
+}
+
+// FuzzEncode checks that any invoice that can be decoded can also be encoded
+// again.
+func FuzzEncode(f *testing.F) {
+	seedCorpus(f)
+
+	f.Fuzz(func(t *testing.T, data string) {
+		inv, err := Decode(data, &chaincfg.TestNet3Params)
+		if err != nil {
+			return
+		}
+
+		// Call these functions as a sanity check to make sure the
+		// invoice is well-formed.
+		_ = inv.MinFinalCLTVExpiry()
+		_ = inv.Expiry()
+
+		// Encode the invoice again using the static test key. This may
+		// fail for invoices that aren't valid, but it must not panic.
+		_, _ = inv.Encode(testMessageSigner)
+	})
+}
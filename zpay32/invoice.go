@@ -76,6 +76,10 @@ const (
 	// probing the recipient.
 	fieldTypeS = 16
 
+	// fieldTypeB contains a blinded payment path to the recipient, along
+	// with the aggregate forwarding policy of the path.
+	fieldTypeB = 20
+
 	// maxInvoiceLength is the maximum total length an invoice can have.
 	// This is chosen to be the maximum number of bytes that can fit into a
 	// single QR code: https://en.wikipedia.org/wiki/QR_code#Storage
@@ -190,6 +194,13 @@ type Invoice struct {
 	// Metadata is additional data that is sent along with the payment to
 	// the payee.
 	Metadata []byte
+
+	// BlindedPaymentPaths are blinded paths to the recipient, which hide
+	// the recipient's identity from the sender. If set, payments are
+	// routed to one of these paths instead of the destination.
+	//
+	// NOTE: This is optional, and can't be combined with RouteHints.
+	BlindedPaymentPaths []*BlindedPaymentPath
 }
 
 // Amount is a functional option that allows callers of NewInvoice to set the
@@ -288,6 +299,14 @@ func Metadata(metadata []byte) func(*Invoice) {
 	}
 }
 
+// WithBlindedPaymentPath is a functional option that allows callers of
+// NewInvoice to add a blinded payment path to the recipient.
+func WithBlindedPaymentPath(path *BlindedPaymentPath) func(*Invoice) {
+	return func(i *Invoice) {
+		i.BlindedPaymentPaths = append(i.BlindedPaymentPaths, path)
+	}
+}
+
 // NewInvoice creates a new Invoice object. The last parameter is a set of
 // variadic arguments for setting optional fields of the invoice.
 //
@@ -385,5 +404,12 @@ func validateInvoice(invoice *Invoice) error {
 		return fmt.Errorf("missing feature vector")
 	}
 
+	// Route hints lead to the recipient's node, so they can't be used
+	// along with blinded paths that hide it.
+	if len(invoice.RouteHints) > 0 && len(invoice.BlindedPaymentPaths) > 0 {
+		return fmt.Errorf("route hints and blinded paths can't be " +
+			"used together")
+	}
+
 	return nil
 }
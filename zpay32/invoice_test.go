@@ -899,6 +899,96 @@ func TestNewInvoice(t *testing.T) {
 	}
 }
 
+// TestBlindedPaymentPaths tests that blinded payment paths survive an encoding
+// round trip, and that they can't be combined with route hints.
+func TestBlindedPaymentPaths(t *testing.T) {
+	t.Parallel()
+
+	newPath := func(feeBase uint32) *BlindedPaymentPath {
+		return &BlindedPaymentPath{
+			FeeBaseMsat:     feeBase,
+			FeeRate:         2000,
+			CltvExpiryDelta: 144,
+			HTLCMinMsat:     1000,
+			HTLCMaxMsat:     5000000,
+			Features: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.TLVOnionPayloadOptional,
+				), lnwire.Features,
+			),
+			Path: &lnwire.BlindedPath{
+				IntroductionNode: testHopHintPubkey1,
+				BlindingPoint:    testHopHintPubkey2,
+				Hops: []*lnwire.BlindedHop{
+					{
+						BlindedNodeID: testHopHintPubkey2,
+						EncryptedData: []byte{1, 2, 3},
+					},
+					{
+						BlindedNodeID: testPubKey,
+						EncryptedData: []byte{4, 5},
+					},
+				},
+			},
+		}
+	}
+	paths := []*BlindedPaymentPath{newPath(1000), newPath(2000)}
+
+	invoice, err := NewInvoice(&chaincfg.MainNetParams,
+		testPaymentHash, time.Unix(1496314658, 0),
+		Amount(testMillisat25mBTC),
+		Description(testCupOfCoffee),
+		WithBlindedPaymentPath(paths[0]),
+		WithBlindedPaymentPath(paths[1]))
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+
+	encoded, err := invoice.Encode(testMessageSigner)
+	if err != nil {
+		t.Fatalf("unable to encode invoice: %v", err)
+	}
+
+	decoded, err := Decode(encoded, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to decode invoice: %v", err)
+	}
+
+	if len(decoded.BlindedPaymentPaths) != len(paths) {
+		t.Fatalf("expected %d blinded paths, got %d", len(paths),
+			len(decoded.BlindedPaymentPaths))
+	}
+
+	for i, path := range paths {
+		var expected, actual bytes.Buffer
+		if err := path.Encode(&expected); err != nil {
+			t.Fatalf("unable to encode path: %v", err)
+		}
+		err := decoded.BlindedPaymentPaths[i].Encode(&actual)
+		if err != nil {
+			t.Fatalf("unable to encode decoded path: %v", err)
+		}
+
+		if !bytes.Equal(expected.Bytes(), actual.Bytes()) {
+			t.Fatalf("blinded path %d mismatch: expected %x, "+
+				"got %x", i, expected.Bytes(), actual.Bytes())
+		}
+	}
+
+	// Route hints lead to the recipient, so they can't be used along
+	// with blinded paths.
+	_, err = NewInvoice(&chaincfg.MainNetParams,
+		testPaymentHash, time.Unix(1496314658, 0),
+		Amount(testMillisat25mBTC),
+		Description(testCupOfCoffee),
+		RouteHint(testSingleHop),
+		WithBlindedPaymentPath(paths[0]))
+	if err == nil {
+		t.Fatalf("expected invoice with route hints and blinded " +
+			"paths to be invalid")
+	}
+}
+
 // TestMaxInvoiceLength tests that attempting to decode an invoice greater than
 // maxInvoiceLength fails with ErrInvoiceTooLarge.
 func TestMaxInvoiceLength(t *testing.T) {